@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case in Go names, as golint expects
+var initialisms = map[string]string{
+	"id": "ID", "ids": "IDs", "url": "URL", "uuid": "UUID", "isk": "ISK", "lp": "LP",
+	"npc": "NPC", "api": "API", "http": "HTTP", "ip": "IP", "sp": "SP", "ui": "UI",
+}
+
+// camelID matches the "Id"/"Ids" words of ESI component names such as CharactersCharacterIdAssetsGet
+var camelID = regexp.MustCompile(`Id(s?)([A-Z]|$)`)
+
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true,
+	"goto": true, "if": true, "import": true, "interface": true, "map": true, "package": true,
+	"range": true, "return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+}
+
+// packageName returns the Go package name of an endpoint family, e.g. planetaryinteraction
+func packageName(tag string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(tag) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// goName turns snake_case and CamelCase spec names into exported Go names
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		if initialism, ok := initialisms[strings.ToLower(word)]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return camelID.ReplaceAllString(b.String(), "ID$1$2")
+}
+
+// paramName turns a parameter name into an unexported Go identifier
+func paramName(name string) string {
+	exported := goName(name)
+	var result string
+	if _, ok := initialisms[strings.ToLower(exported)]; ok {
+		result = strings.ToLower(exported)
+	} else {
+		result = strings.ToLower(exported[:1]) + exported[1:]
+	}
+	if goKeywords[result] {
+		result += "Param"
+	}
+	return result
+}
+
+// generator renders the client package of one endpoint family
+type generator struct {
+	spec    *Spec
+	tag     string
+	types   map[string]string
+	imports map[string]bool
+}
+
+func newGenerator(spec *Spec, tag string) *generator {
+	return &generator{
+		spec:    spec,
+		tag:     tag,
+		types:   map[string]string{},
+		imports: map[string]bool{"context": true},
+	}
+}
+
+// method is an operation rendered as a client method
+type method struct {
+	name      string
+	signature string
+	doc       string
+	body      string
+}
+
+// generate renders the package source for the given operations
+func (g *generator) generate(operations []*Operation) ([]byte, error) {
+	var methods []method
+	for _, operation := range operations {
+		m, err := g.method(operation)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", operation.OperationID, err)
+		}
+		methods = append(methods, m...)
+	}
+
+	pkg := packageName(g.tag)
+	var b bytes.Buffer
+	b.WriteString("// Code generated by esigen from the ESI OpenAPI spec. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Package %s is the generated client for the %s endpoints of ESI.\n", pkg, g.tag)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	// Standard library first, then the runtime, as in the hand-written clients
+	var std []string
+	for imp := range g.imports {
+		if !strings.HasPrefix(imp, "go-falcon/") {
+			std = append(std, imp)
+		}
+	}
+	sort.Strings(std)
+	b.WriteString("import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	b.WriteString("\n\t\"go-falcon/pkg/evegateway/esiruntime\"\n)\n\n")
+
+	fmt.Fprintf(&b, "// Client interface for %s ESI operations\ntype Client interface {\n", g.tag)
+	for _, m := range methods {
+		fmt.Fprintf(&b, "\t%s%s\n", m.name, m.signature)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// ClientImpl implements the Client interface\ntype ClientImpl struct {\n\truntime *esiruntime.Runtime\n}\n\n")
+	fmt.Fprintf(&b, "// NewClient creates a %s client on top of the shared ESI runtime\n", g.tag)
+	b.WriteString("func NewClient(runtime *esiruntime.Runtime) Client {\n\treturn &ClientImpl{runtime: runtime}\n}\n\n")
+
+	for _, m := range methods {
+		b.WriteString(m.doc)
+		fmt.Fprintf(&b, "func (c *ClientImpl) %s%s {\n%s}\n\n", m.name, m.signature, m.body)
+	}
+
+	names := make([]string, 0, len(g.types))
+	for name := range g.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString(g.types[name])
+	}
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go: %w\n%s", err, b.String())
+	}
+	return source, nil
+}
+
+// method renders an operation; GET endpoints get a plain and a WithCache variant
+func (g *generator) method(operation *Operation) ([]method, error) {
+	name := goName(operation.OperationID)
+	paginated := operation.Method == "GET" && operation.paginated()
+
+	var (
+		args      []string
+		callArgs  []string
+		pathArgs  []string
+		queryCode []string
+		hasQuery  bool
+		hasBody   bool
+	)
+	args = append(args, "ctx context.Context")
+	callArgs = append(callArgs, "ctx")
+
+	endpoint := operation.Path
+	var paramsFields []string
+	for _, p := range operation.Parameters {
+		p = g.spec.parameter(p)
+		switch p.In {
+		case "path":
+			arg := paramName(p.Name)
+			args = append(args, arg+" "+g.goType(p.Schema, name+goName(p.Name)))
+			callArgs = append(callArgs, arg)
+			pathArgs = append(pathArgs, "esiruntime.PathParam("+arg+")")
+			endpoint = strings.Replace(endpoint, "{"+p.Name+"}", "%s", 1)
+		case "query":
+			if paginated && p.Name == "page" {
+				continue
+			}
+			hasQuery = true
+			field := goName(p.Name)
+			typ := g.goType(p.Schema, name+field)
+			switch {
+			case p.Required:
+				paramsFields = append(paramsFields, fmt.Sprintf("%s%s %s", comment(p.Description), field, typ))
+				queryCode = append(queryCode, fmt.Sprintf("query.Set(%q, esiruntime.QueryParam(params.%s))", p.Name, field))
+			case nilable(typ):
+				paramsFields = append(paramsFields, fmt.Sprintf("%s%s %s", comment(p.Description), field, typ))
+				queryCode = append(queryCode, fmt.Sprintf("if len(params.%s) > 0 {\nquery.Set(%q, esiruntime.QueryParam(params.%s))\n}", field, p.Name, field))
+			default:
+				paramsFields = append(paramsFields, fmt.Sprintf("%s%s *%s", comment(p.Description), field, typ))
+				queryCode = append(queryCode, fmt.Sprintf("if params.%s != nil {\nquery.Set(%q, esiruntime.QueryParam(*params.%s))\n}", field, p.Name, field))
+			}
+		}
+	}
+
+	if hasQuery {
+		paramsType := name + "Params"
+		g.types[paramsType] = fmt.Sprintf("// %s holds the query parameters of %s\ntype %s struct {\n%s\n}\n\n",
+			paramsType, name, paramsType, strings.Join(paramsFields, "\n"))
+		args = append(args, "params "+paramsType)
+		callArgs = append(callArgs, "params")
+		g.imports["net/url"] = true
+	}
+
+	if operation.RequestBody != nil {
+		if media, ok := operation.RequestBody.Content["application/json"]; ok {
+			args = append(args, "body "+g.goType(media.Schema, name+"Body"))
+			callArgs = append(callArgs, "body")
+			hasBody = true
+		}
+	}
+
+	scopes := operation.scopes()
+	if len(scopes) > 0 {
+		args = append(args, "token esiruntime.TokenProvider")
+		callArgs = append(callArgs, "token")
+	}
+
+	// The request literal shared by every variant
+	var req strings.Builder
+	fmt.Fprintf(&req, "esiruntime.Request{\nMethod: %q,\n", operation.Method)
+	if len(pathArgs) > 0 {
+		g.imports["fmt"] = true
+		fmt.Fprintf(&req, "Endpoint: fmt.Sprintf(%q, %s),\n", endpoint, strings.Join(pathArgs, ", "))
+	} else {
+		fmt.Fprintf(&req, "Endpoint: %q,\n", endpoint)
+	}
+	if hasQuery {
+		req.WriteString("Query: query,\n")
+	}
+	if len(scopes) > 0 {
+		req.WriteString("Token: token,\n")
+	}
+	if hasBody {
+		req.WriteString("Body: body,\n")
+	}
+	if operation.CompatibilityDate != "" {
+		fmt.Fprintf(&req, "CompatibilityDate: %q,\n", operation.CompatibilityDate)
+	}
+	req.WriteString("}")
+
+	prelude := ""
+	if hasQuery {
+		prelude = "query := url.Values{}\n" + strings.Join(queryCode, "\n") + "\n"
+	}
+
+	doc := g.doc(name, operation, paginated, scopes)
+	argList := strings.Join(args, ", ")
+
+	_, schema := operation.successResponse()
+	if operation.Method != "GET" {
+		if schema == nil {
+			g.imports["encoding/json"] = true
+			return []method{{
+				name:      name,
+				signature: fmt.Sprintf("(%s) error", argList),
+				doc:       doc,
+				body:      fmt.Sprintf("%s_, err := esiruntime.Send[json.RawMessage](ctx, c.runtime, %s)\nreturn err\n", prelude, req.String()),
+			}}, nil
+		}
+		typ := g.goType(schema, name+"Response")
+		return []method{{
+			name:      name,
+			signature: fmt.Sprintf("(%s) (%s, error)", argList, typ),
+			doc:       doc,
+			body:      fmt.Sprintf("%sreturn esiruntime.Send[%s](ctx, c.runtime, %s)\n", prelude, typ, req.String()),
+		}}, nil
+	}
+
+	if schema == nil {
+		return nil, fmt.Errorf("GET endpoint without a JSON response")
+	}
+	typ := g.goType(schema, name+"Response")
+	call := fmt.Sprintf("esiruntime.Get[%s](ctx, c.runtime, %s)", typ, req.String())
+	if paginated {
+		if !strings.HasPrefix(typ, "[]") {
+			return nil, fmt.Errorf("paginated endpoint without a list response")
+		}
+		call = fmt.Sprintf("esiruntime.GetAllPages[%s](ctx, c.runtime, %s)", strings.TrimPrefix(typ, "[]"), req.String())
+	}
+
+	withCache := name + "WithCache"
+	return []method{
+		{
+			name:      name,
+			signature: fmt.Sprintf("(%s) (%s, error)", argList, typ),
+			doc:       doc,
+			body: fmt.Sprintf("result, err := c.%s(%s)\nif err != nil {\nreturn %s, err\n}\nreturn result.Data, nil\n",
+				withCache, strings.Join(callArgs, ", "), zeroValue(typ)),
+		},
+		{
+			name:      withCache,
+			signature: fmt.Sprintf("(%s) (*esiruntime.Result[%s], error)", argList, typ),
+			doc:       fmt.Sprintf("// %s is %s with cache info\n", withCache, name),
+			body:      fmt.Sprintf("%sreturn %s\n", prelude, call),
+		},
+	}, nil
+}
+
+// doc renders the doc comment of a method: the endpoint, its summary and how it is served
+func (g *generator) doc(name string, operation *Operation, paginated bool, scopes []string) string {
+	var notes []string
+	if paginated {
+		notes = append(notes, "fetches all pages")
+	}
+	if operation.CacheAge > 0 {
+		notes = append(notes, fmt.Sprintf("cached for %ds", operation.CacheAge))
+	}
+	if len(scopes) > 0 {
+		notes = append(notes, "requires "+strings.Join(scopes, ", "))
+	}
+
+	doc := fmt.Sprintf("// %s calls %s %s (%s)\n", name, operation.Method, operation.Path, strings.TrimSpace(operation.Summary))
+	if len(notes) > 0 {
+		note := strings.Join(notes, "; ")
+		doc += "// " + strings.ToUpper(note[:1]) + note[1:] + ".\n"
+	}
+	return doc
+}
+
+// goType returns the Go type of a schema, declaring struct types as needed. hint names the struct
+// of an inline object.
+func (g *generator) goType(schema *Schema, hint string) string {
+	if schema == nil {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+
+	if schema.Ref != "" {
+		name := refName(schema.Ref)
+		resolved, ok := g.spec.Components.Schemas[name]
+		if !ok {
+			g.imports["encoding/json"] = true
+			return "json.RawMessage"
+		}
+		return g.goType(resolved, goName(name))
+	}
+
+	switch schema.Type.Name {
+	case "integer":
+		if schema.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if schema.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "string":
+		if schema.Format == "date-time" {
+			g.imports["time"] = true
+			return "time.Time"
+		}
+		return "string"
+	case "array":
+		return "[]" + g.goType(schema.Items, hint+"Item")
+	}
+
+	if len(schema.Properties) > 0 {
+		g.declareStruct(hint, schema)
+		return hint
+	}
+	if values := schema.additionalProperties(); values != nil {
+		return "map[string]" + g.goType(values, hint+"Value")
+	}
+	if schema.Type.Name == "object" {
+		return "map[string]any"
+	}
+	g.imports["encoding/json"] = true
+	return "json.RawMessage"
+}
+
+// declareStruct declares the struct type of an object schema once
+func (g *generator) declareStruct(name string, schema *Schema) {
+	if _, declared := g.types[name]; declared {
+		return
+	}
+	g.types[name] = "" // Reserve the name so recursive schemas terminate
+
+	required := map[string]bool{}
+	for _, property := range schema.Required {
+		required[property] = true
+	}
+	properties := make([]string, 0, len(schema.Properties))
+	for property := range schema.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	var b strings.Builder
+	if schema.Description != "" {
+		b.WriteString(comment(name + ": " + schema.Description))
+	} else {
+		fmt.Fprintf(&b, "// %s is an ESI model\n", name)
+	}
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	for _, property := range properties {
+		propertySchema := schema.Properties[property]
+		field := goName(property)
+		typ := g.goType(propertySchema, name+field)
+		tag := property
+		if !required[property] {
+			tag += ",omitempty"
+			if !nilable(typ) {
+				typ = "*" + typ
+			}
+		}
+		fmt.Fprintf(&b, "%s%s %s `json:%q`\n", comment(propertySchema.Description), field, typ, tag)
+	}
+	b.WriteString("}\n\n")
+	g.types[name] = b.String()
+}
+
+// comment renders a one-line field comment, or nothing without a description
+func comment(description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if description == "" {
+		return ""
+	}
+	return "// " + description + "\n"
+}
+
+// nilable reports whether a Go type already has a nil value, so optional fields need no pointer
+func nilable(typ string) bool {
+	return strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "json.RawMessage" || typ == "any"
+}
+
+// zeroValue returns the zero value literal of a Go type
+func zeroValue(typ string) string {
+	switch {
+	case nilable(typ):
+		return "nil"
+	case typ == "string":
+		return `""`
+	case typ == "bool":
+		return "false"
+	case strings.HasPrefix(typ, "int"), strings.HasPrefix(typ, "float"):
+		return "0"
+	default:
+		return typ + "{}"
+	}
+}
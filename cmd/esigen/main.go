@@ -0,0 +1,114 @@
+// Command esigen generates typed ESI clients from the ESI OpenAPI spec. Each endpoint family (an
+// OpenAPI tag such as "Insurance") becomes a package under pkg/evegateway/generated that sends its
+// requests through pkg/evegateway/esiruntime, so generated and hand-written clients share the
+// cache, retry client and connection pool and can coexist while families are migrated.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+func main() {
+	var (
+		source = flag.String("source", "pkg/evegateway/openapi.json", "URL or file to read the ESI OpenAPI spec from")
+		save   = flag.String("save", "", "File to store the downloaded spec in (e.g. pkg/evegateway/openapi.json)")
+		tags   = flag.String("tags", "", "Comma-separated endpoint families (OpenAPI tags) to generate")
+		output = flag.String("output", "pkg/evegateway/generated", "Directory to write the generated packages to")
+		list   = flag.Bool("list", false, "List the endpoint families in the spec and exit")
+	)
+	flag.Parse()
+
+	data, err := readSpec(*source)
+	if err != nil {
+		log.Fatalf("❌ Failed to read ESI spec from %s: %v", *source, err)
+	}
+	if *save != "" {
+		if err := os.WriteFile(*save, data, 0o644); err != nil {
+			log.Fatalf("❌ Failed to write %s: %v", *save, err)
+		}
+		fmt.Printf("📄 ESI spec saved to %s\n", *save)
+	}
+
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		log.Fatalf("❌ Invalid ESI spec: %v", err)
+	}
+
+	families := spec.Families()
+	if *list {
+		names := make([]string, 0, len(families))
+		for name := range families {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-24s %3d operation(s) -> %s\n", name, len(families[name]), packageName(name))
+		}
+		return
+	}
+
+	if *tags == "" {
+		log.Fatal("❌ -tags is required (use -list to see the endpoint families)")
+	}
+
+	for _, tag := range strings.Split(*tags, ",") {
+		tag = strings.TrimSpace(tag)
+		operations, ok := families[tag]
+		if !ok {
+			log.Fatalf("❌ Unknown endpoint family %q (use -list to see the endpoint families)", tag)
+		}
+
+		source, err := newGenerator(&spec, tag).generate(operations)
+		if err != nil {
+			log.Fatalf("❌ Failed to generate %s: %v", tag, err)
+		}
+
+		dir := filepath.Join(*output, packageName(tag))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			log.Fatalf("❌ Failed to create %s: %v", dir, err)
+		}
+		file := filepath.Join(dir, packageName(tag)+"_gen.go")
+		if err := os.WriteFile(file, source, 0o644); err != nil {
+			log.Fatalf("❌ Failed to write %s: %v", file, err)
+		}
+		fmt.Printf("✅ Generated %s (%d operation(s))\n", file, len(operations))
+	}
+}
+
+// readSpec loads the spec from ESI or from a file
+func readSpec(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "go-falcon-esigen")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Spec is the subset of the ESI OpenAPI 3.1 document the generator reads
+type Spec struct {
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas    map[string]*Schema    `json:"schemas"`
+		Parameters map[string]*Parameter `json:"parameters"`
+	} `json:"components"`
+}
+
+// Operation is one endpoint; Path and Method are filled in by Families
+type Operation struct {
+	OperationID       string                `json:"operationId"`
+	Summary           string                `json:"summary"`
+	Description       string                `json:"description"`
+	Tags              []string              `json:"tags"`
+	Parameters        []*Parameter          `json:"parameters"`
+	RequestBody       *Body                 `json:"requestBody"`
+	Responses         map[string]*Response  `json:"responses"`
+	Security          []map[string][]string `json:"security"`
+	CacheAge          int                   `json:"x-cache-age"`
+	CompatibilityDate string                `json:"x-compatibility-date"`
+
+	Path   string `json:"-"`
+	Method string `json:"-"`
+}
+
+// Parameter is a path, query or header parameter, or a reference to a shared one
+type Parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Body is a request body
+type Body struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is one response of an operation
+type Response struct {
+	Content map[string]*MediaType `json:"content"`
+	Headers map[string]any        `json:"headers"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON schema as used by ESI
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 SchemaType         `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+}
+
+// additionalProperties returns the schema of a map's values; ESI also uses a plain false
+func (s *Schema) additionalProperties() *Schema {
+	var values Schema
+	if len(s.AdditionalProperties) == 0 || json.Unmarshal(s.AdditionalProperties, &values) != nil {
+		return nil
+	}
+	return &values
+}
+
+// SchemaType is the schema type, which OpenAPI 3.1 allows to be a list such as ["array", "null"]
+type SchemaType struct {
+	Name     string
+	Nullable bool
+}
+
+// UnmarshalJSON accepts a single type name or a list of them
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		t.Name = name
+		return nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if name == "null" {
+			t.Nullable = true
+		} else {
+			t.Name = name
+		}
+	}
+	return nil
+}
+
+// Families groups the operations by endpoint family (their first tag), ordered by path and method
+func (s *Spec) Families() map[string][]*Operation {
+	families := map[string][]*Operation{}
+	for path, methods := range s.Paths {
+		for method, operation := range methods {
+			if len(operation.Tags) == 0 {
+				continue
+			}
+			operation.Path = path
+			operation.Method = strings.ToUpper(method)
+			families[operation.Tags[0]] = append(families[operation.Tags[0]], operation)
+		}
+	}
+	for _, operations := range families {
+		sort.Slice(operations, func(i, j int) bool {
+			if operations[i].Path != operations[j].Path {
+				return operations[i].Path < operations[j].Path
+			}
+			return operations[i].Method < operations[j].Method
+		})
+	}
+	return families
+}
+
+// parameter resolves a parameter reference
+func (s *Spec) parameter(p *Parameter) *Parameter {
+	if p.Ref == "" {
+		return p
+	}
+	if resolved, ok := s.Components.Parameters[refName(p.Ref)]; ok {
+		return resolved
+	}
+	return p
+}
+
+// refName returns the component name of a reference such as #/components/schemas/CharacterID
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// scopes returns the SSO scopes an operation requires
+func (o *Operation) scopes() []string {
+	var scopes []string
+	for _, requirement := range o.Security {
+		scopes = append(scopes, requirement["OAuth2"]...)
+	}
+	return scopes
+}
+
+// successResponse returns the status code and schema of the success response; the schema is nil
+// for endpoints answering 204 No Content
+func (o *Operation) successResponse() (string, *Schema) {
+	for _, code := range []string{"200", "201", "204"} {
+		if response, ok := o.Responses[code]; ok {
+			if media, ok := response.Content["application/json"]; ok && code != "204" {
+				return code, media.Schema
+			}
+			return code, nil
+		}
+	}
+	return "", nil
+}
+
+// paginated reports whether the endpoint announces its page count in X-Pages
+func (o *Operation) paginated() bool {
+	if response, ok := o.Responses["200"]; ok {
+		_, ok := response.Headers["X-Pages"]
+		return ok
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -19,41 +20,62 @@ import (
 
 	"go-falcon/internal/alliance"
 	"go-falcon/internal/assets"
+	"go-falcon/internal/attachments"
+	attachmentsModels "go-falcon/internal/attachments/models"
 	"go-falcon/internal/auth"
 	"go-falcon/internal/character"
 	characterDto "go-falcon/internal/character/dto"
+	"go-falcon/internal/comments"
 	"go-falcon/internal/corporation"
 	corporationDto "go-falcon/internal/corporation/dto"
 	"go-falcon/internal/discord"
 	discordServices "go-falcon/internal/discord/services"
+	"go-falcon/internal/fittings"
 	"go-falcon/internal/groups"
 	groupsDto "go-falcon/internal/groups/dto"
 	groupsServices "go-falcon/internal/groups/services"
 	"go-falcon/internal/killmails"
 	"go-falcon/internal/mapservice"
 	"go-falcon/internal/market"
+	"go-falcon/internal/notifications"
 	"go-falcon/internal/scheduler"
 	"go-falcon/internal/sde_admin"
 	"go-falcon/internal/site_settings"
 	"go-falcon/internal/sitemap"
 	sitemapServices "go-falcon/internal/sitemap/services"
 	"go-falcon/internal/structures"
+	"go-falcon/internal/tags"
 	"go-falcon/internal/users"
 	usersModels "go-falcon/internal/users/models"
 	"go-falcon/internal/websocket"
 	"go-falcon/internal/zkillboard"
+	"go-falcon/pkg/apidocs"
 	"go-falcon/pkg/app"
+	"go-falcon/pkg/changestream"
 	"go-falcon/pkg/config"
+	"go-falcon/pkg/database"
+	"go-falcon/pkg/deadline"
 	evegateway "go-falcon/pkg/evegateway"
+	"go-falcon/pkg/evegateway/esiusage"
+	"go-falcon/pkg/invalidation"
 	"go-falcon/pkg/middleware"
 	"go-falcon/pkg/module"
 	"go-falcon/pkg/permissions"
+	"go-falcon/pkg/permissions/cachestats"
+	"go-falcon/pkg/residency"
+	"go-falcon/pkg/retryqueue"
+	"go-falcon/pkg/startup"
+	"go-falcon/pkg/stepup"
+	"go-falcon/pkg/storage"
 	"go-falcon/pkg/version"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humachi"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	_ "go.uber.org/automaxprocs"
 )
 
@@ -190,11 +212,11 @@ func (a *DiscordUserAdapter) GetUserByUserID(ctx context.Context, userID string)
 	}, nil
 }
 
-// customLoggerMiddleware logs requests but excludes health check endpoints
+// customLoggerMiddleware logs requests but excludes health check and metrics endpoints
 func customLoggerMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip logging for health check endpoints
-		if strings.HasSuffix(r.URL.Path, "/health") {
+		// Skip logging for health check and metrics scrapes
+		if strings.HasSuffix(r.URL.Path, "/health") || r.URL.Path == "/metrics" {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -291,6 +313,9 @@ func main() {
 		log.Fatalf("Failed to initialize application: %v", err)
 	}
 	defer appCtx.Shutdown(ctx)
+	if appCtx.MongoDB == nil {
+		log.Fatalf("MongoDB is required but could not be reached; see the connection error above and check MONGODB_URI")
+	}
 
 	// Print memory information after app initialization (more accurate)
 	var m runtime.MemStats
@@ -309,6 +334,13 @@ func main() {
 	// Print memory limits if available (cgroups v1/v2)
 	printMemoryLimits()
 
+	// Shed low-priority requests before the gateway runs out of memory or goroutines
+	var loadShedder *middleware.LoadShedder
+	if config.GetLoadSheddingEnabled() {
+		loadShedder = middleware.NewLoadShedder(middleware.LoadSheddingConfigFromEnv(containerMemoryLimit()))
+		loadShedder.Start(ctx)
+	}
+
 	// Initialize Chi router
 	r := chi.NewRouter()
 
@@ -316,32 +348,65 @@ func main() {
 	r.Use(customLoggerMiddleware) // Custom logger that excludes health checks
 	r.Use(chimiddleware.Recoverer)
 	r.Use(chimiddleware.RequestID)
-	r.Use(chimiddleware.RealIP)
-	// Apply timeout middleware but exclude WebSocket endpoints
+	r.Use(chimiddleware.RealIP) // Trusts X-Forwarded-For/X-Real-IP: the proxy in front must overwrite them, as auth throttling keys on the result
+	if loadShedder != nil {
+		r.Use(loadShedder.Middleware(config.GetAPIPrefix())) // Reject low-priority requests with 503 under resource pressure
+	}
+	// Apply timeout middleware but exclude WebSocket endpoints and streamed exports. The timeout is
+	// the request's budget: MongoDB operations and ESI retries made for it stop when it runs out.
+	requestTimeout := config.GetRequestTimeout()
+	auditExportPath := config.GetAPIPrefix() + "/groups/admin/audit/export"
 	r.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Skip timeout for WebSocket endpoints
-			if strings.HasPrefix(r.URL.Path, "/websocket/") {
+			// Skip timeout for WebSocket endpoints and the groups audit export, which streams until done
+			if strings.HasPrefix(r.URL.Path, "/websocket/") || r.URL.Path == auditExportPath {
 				next.ServeHTTP(w, r)
 				return
 			}
 			// Apply timeout for all other endpoints
-			chimiddleware.Timeout(60*time.Second)(next).ServeHTTP(w, r)
+			chimiddleware.Timeout(requestTimeout)(next).ServeHTTP(w, r)
 		})
 	})
-	r.Use(corsMiddleware) // Add CORS support for cross-subdomain requests
-
-	// Health check endpoint with version info
-	r.Get("/health", enhancedHealthHandler)
-
-	// Note: WebSocket handler registration will be done after WebSocket module initialization
+	r.Use(deadline.Middleware)                                // Count requests that ran past their deadline, per route
+	r.Use(corsMiddleware)                                     // Add CORS support for cross-subdomain requests
+	r.Use(middleware.DegradedMode(appCtx.MongoDB))            // Reject writes with 503 while MongoDB is unreachable
+	r.Use(evegateway.ModuleMiddleware(config.GetAPIPrefix())) // Attribute ESI calls to the module serving the request
+	if config.GetCacheControlEnabled() {
+		r.Use(middleware.CacheControl(middleware.NewCachePolicyFromConfig(), config.GetAPIPrefix()))
+	}
 
 	// Initialize EVE Online ESI client with Redis caching
 	evegateClient := evegateway.NewClientWithRedis(appCtx.Redis)
 
+	// Health check endpoint with version info and ESI connection pool stats
+	r.Get("/health", enhancedHealthHandler(evegateClient, appCtx.MongoDB))
+
+	// Prometheus metrics endpoint with Go runtime, process and ESI client metrics
+	if config.GetMetricsEnabled() {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+		if err := evegateClient.RegisterMetrics(registry); err != nil {
+			log.Fatalf("Failed to register ESI metrics: %v", err)
+		}
+		if err := deadline.Register(registry); err != nil {
+			log.Fatalf("Failed to register deadline metrics: %v", err)
+		}
+		if loadShedder != nil {
+			if err := loadShedder.Register(registry); err != nil {
+				log.Fatalf("Failed to register load shedding metrics: %v", err)
+			}
+		}
+		r.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	}
+
+	// Note: WebSocket handler registration will be done after WebSocket module initialization
+
 	// Initialize modules in dependency order
 	var modules []module.Module
 
+	// Record every initialization step so operators can verify a healthy boot
+	startupReport := startup.NewReport()
+
 	// 1. Initialize base modules without dependencies (corporation needs auth, will be moved later)
 
 	// 2. Initialize site settings module (no dependencies)
@@ -351,9 +416,11 @@ func main() {
 	}
 
 	// Initialize site settings first
-	if err := siteSettingsModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("site_settings", startup.PhaseInit, func() error { return siteSettingsModule.Initialize(ctx) }); err != nil {
 		log.Fatalf("Failed to initialize site settings: %v", err)
 	}
+	residency.SetSettingsSource(siteSettingsModule.GetService())        // Site settings can restrict sensitive ESI data storage
+	middleware.SetCookieSettingsSource(siteSettingsModule.GetService()) // Site settings can override the auth cookie attributes
 
 	// 3. Initialize groups module with site settings dependency
 	groupsModule, err := groups.NewModule(appCtx.MongoDB, nil, siteSettingsModule.GetService())
@@ -362,13 +429,26 @@ func main() {
 	}
 
 	// Initialize groups module
-	if err := groupsModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("groups", startup.PhaseInit, func() error { return groupsModule.Initialize(ctx) }); err != nil {
 		log.Fatalf("Failed to initialize groups module: %v", err)
 	}
+	groupsModule.GetService().SetCharacterRolesSource(evegateClient.Character)      // Membership rules on corporation roles read them from ESI
+	groupsModule.GetService().SetCorporationMemberSource(evegateClient.Corporation) // Corporation mappings read member roles and titles from ESI
+	groupsModule.GetService().SetAffiliationSource(evegateClient.Character)         // Membership revalidation reads affiliations from ESI
+	groupsModule.GetService().SetAllianceContactSource(evegateClient.Alliance)      // Standings groups import alliance contacts from ESI
 
 	// 4. Initialize auth module and set groups service dependency
 	authModule := auth.New(appCtx.MongoDB, appCtx.Redis, evegateClient)
 	authModule.GetAuthService().SetGroupsService(groupsModule.GetService())
+	authModule.GetAuthService().SetScopeSetSource(siteSettingsModule.GetService()) // Named EVE SSO scope sets come from site settings
+	evegateClient.SetTokenRefresher(authModule.GetAuthService())                   // Refresh expired or rejected SSO tokens on ESI calls
+
+	if err := startupReport.Run("auth", startup.PhaseInit, func() error { return authModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize auth module: %v", err)
+	}
+
+	// Public keys of the JWT signing keys, so other services can verify falcon JWTs
+	r.Get("/.well-known/jwks.json", authModule.JWKSHandler())
 
 	// 5. Update groups module with auth dependencies
 	if err := groupsModule.SetAuthModule(authModule); err != nil {
@@ -378,6 +458,9 @@ func main() {
 	// 6. Initialize permission manager
 	log.Printf("🔐 Initializing permission management system")
 	permissionManager := permissions.NewPermissionManager(appCtx.MongoDB.Database)
+	if appCtx.Redis != nil {
+		permissionManager.EnableDecisionCache(appCtx.Redis.Client, time.Duration(config.GetPermissionCacheTTLSeconds())*time.Second)
+	}
 
 	// Set permission manager in groups module
 	if err := groupsModule.SetPermissionManager(permissionManager); err != nil {
@@ -392,6 +475,11 @@ func main() {
 	corporationModule := corporation.NewModule(appCtx.MongoDB, appCtx.Redis, evegateClient, authModule, characterModule.GetService(), appCtx.SDEService)
 	corporationModule.SetGroupService(groupsModule.GetService())
 
+	// Initialize corporation module to create database indexes
+	if err := startupReport.Run("corporation", startup.PhaseInit, func() error { return corporationModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize corporation module: %v", err)
+	}
+
 	// Update groups service with permission manager
 	groupsModule.GetService().SetPermissionManager(permissionManager)
 
@@ -524,16 +612,24 @@ func main() {
 	killmailsModule := killmails.New(appCtx.MongoDB, appCtx.Redis, evegateClient, appCtx.SDEService)
 
 	// Initialize killmails module to create database indexes
-	if err := killmailsModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("killmails", startup.PhaseInit, func() error { return killmailsModule.Initialize(ctx) }); err != nil {
 		log.Fatalf("Failed to initialize killmails module: %v", err)
 	}
 
 	usersModule := users.New(appCtx.MongoDB, appCtx.Redis, authModule, evegateClient, appCtx.SDEService)
 	usersModule.SetGroupService(groupsModule.GetService())
+	exportStore, err := storage.NewGridFSStore(appCtx.MongoDB, usersModels.ExportsBucket)
+	if err != nil {
+		log.Fatalf("❌ Failed to create data export store: %v", err)
+	}
+	usersModule.SetExportStore(exportStore)
+	if err := startupReport.Run("users", startup.PhaseInit, func() error { return usersModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize users module: %v", err)
+	}
 
 	// Initialize market module
 	marketModule := market.New(appCtx.MongoDB, appCtx.Redis, evegateClient, appCtx.SDEService)
-	if err := marketModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("market", startup.PhaseInit, func() error { return marketModule.Initialize(ctx) }); err != nil {
 		log.Printf("❌ Failed to initialize market module: %v", err)
 	} else {
 		log.Printf("✅ Market module initialized successfully")
@@ -543,7 +639,7 @@ func main() {
 	mapModule := mapservice.NewModule(appCtx.MongoDB, appCtx.Redis, appCtx.SDEService)
 	// Set groups service dependency for map module
 	mapModule.SetGroupsService(groupsModule.GetService())
-	if err := mapModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("map", startup.PhaseInit, func() error { return mapModule.Initialize(ctx) }); err != nil {
 		log.Printf("❌ Failed to initialize map module: %v", err)
 	} else {
 		log.Printf("✅ Map module initialized successfully")
@@ -559,32 +655,79 @@ func main() {
 	discordUserAdapter := &DiscordUserAdapter{userService: usersModule.GetService()}
 
 	discordModule := discord.NewModule(appCtx.MongoDB, appCtx.Redis, discordGroupsAdapter, discordCharacterAdapter, discordCorporationAdapter, discordUserAdapter, discordPermissionMiddleware)
-
-	schedulerModule := scheduler.New(appCtx.MongoDB, appCtx.Redis, authModule, characterModule, allianceModule.GetService(), corporationModule, marketModule)
-	schedulerModule.SetGroupService(groupsModule.GetService())
-	sdeAdminModule := sde_admin.New(appCtx.MongoDB, appCtx.Redis, authModule, permissionManager, appCtx.SDEService)
+	discordModule.GetService().SetRoleSyncSettingsSource(siteSettingsModule.GetService())         // Site settings control the role push on membership change
+	groupsModule.GetService().AddMembershipListener(discordModule.GetService().MembershipChanged) // Push Discord roles when mapped memberships change
+	if err := startupReport.Run("discord", startup.PhaseInit, func() error { return discordModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize discord module: %v", err)
+	}
 
 	// Create auth middleware for new modules
 	authMiddleware := middleware.NewPermissionMiddleware(authModule.GetAuthService(), permissionManager)
+	authModule.SetPermissionMiddleware(authMiddleware)
+
+	// Initialize notifications module (real-time delivery is attached once the websocket module exists)
+	notificationsModule := notifications.New(appCtx.MongoDB, appCtx.Redis, authMiddleware)
+	if err := startupReport.Run("notifications", startup.PhaseInit, func() error { return notificationsModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize notifications module: %v", err)
+	}
+
+	// Initialize tags module (shared entity tagging for intel, recruitment and SRP workflows)
+	tagsModule := tags.New(appCtx.MongoDB, appCtx.Redis, authMiddleware)
+	if err := startupReport.Run("tags", startup.PhaseInit, func() error { return tagsModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize tags module: %v", err)
+	}
+
+	// Initialize comments module (threaded comments on objects owned by other modules; mentions notify through the notifications module)
+	commentsModule := comments.New(appCtx.MongoDB, appCtx.Redis, authMiddleware)
+	if err := startupReport.Run("comments", startup.PhaseInit, func() error { return commentsModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize comments module: %v", err)
+	}
+	commentsModule.SetNotifier(notificationsModule.GetService())
+	corporationModule.SetNotifier(notificationsModule.GetService())
+	usersModule.SetNotifier(notificationsModule.GetService())
+	groupsModule.GetService().SetNotifier(notificationsModule.GetService())
+
+	// Initialize attachments module (files attached to objects owned by other modules, stored in GridFS)
+	attachmentStore, err := storage.NewGridFSStore(appCtx.MongoDB, attachmentsModels.StorageBucket)
+	if err != nil {
+		log.Fatalf("❌ Failed to create attachment store: %v", err)
+	}
+	attachmentsModule := attachments.New(appCtx.MongoDB, appCtx.Redis, authMiddleware, attachmentStore)
+	if err := startupReport.Run("attachments", startup.PhaseInit, func() error { return attachmentsModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize attachments module: %v", err)
+	}
+
+	sdeAdminModule := sde_admin.New(appCtx.MongoDB, appCtx.Redis, authModule, permissionManager, appCtx.SDEService)
+	schedulerModule := scheduler.New(appCtx.MongoDB, appCtx.Redis, authModule, characterModule, allianceModule.GetService(), corporationModule, marketModule, sdeAdminModule, notificationsModule, usersModule)
+	schedulerModule.SetGroupService(groupsModule.GetService())
+	if err := startupReport.Run("scheduler", startup.PhaseInit, func() error { return schedulerModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize scheduler module: %v", err)
+	}
 
 	// Initialize assets module first (to get structure tracker)
 	assetsModule := assets.NewModule(appCtx.MongoDB, appCtx.Redis, evegateClient, appCtx.SDEService, nil, authMiddleware, schedulerModule.GetSchedulerService(), authModule.GetAuthService())
-	if err := assetsModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("assets", startup.PhaseInit, func() error { return assetsModule.Initialize(ctx) }); err != nil {
 		log.Printf("❌ Failed to initialize assets module: %v", err)
 	}
 
 	// Initialize structures module with assets structure tracker and auth service
 	structuresModule := structures.NewModule(appCtx.MongoDB, appCtx.Redis, evegateClient, appCtx.SDEService, authMiddleware, assetsModule.GetStructureAccessTracker(), authModule.GetAuthService())
-	if err := structuresModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("structures", startup.PhaseInit, func() error { return structuresModule.Initialize(ctx) }); err != nil {
 		log.Printf("❌ Failed to initialize structures module: %v", err)
 	}
 
 	// Update assets module with structures service
 	assetsModule = assets.NewModule(appCtx.MongoDB, appCtx.Redis, evegateClient, appCtx.SDEService, structuresModule.GetService(), authMiddleware, schedulerModule.GetSchedulerService(), authModule.GetAuthService())
-	if err := assetsModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("assets (with structures)", startup.PhaseInit, func() error { return assetsModule.Initialize(ctx) }); err != nil {
 		log.Printf("❌ Failed to initialize assets module: %v", err)
 	}
 
+	// Initialize fittings module (needs killmails service and auth middleware)
+	fittingsModule := fittings.New(appCtx.MongoDB, appCtx.Redis, evegateClient, killmailsModule.GetService(), authMiddleware, authModule.GetAuthService())
+	if err := startupReport.Run("fittings", startup.PhaseInit, func() error { return fittingsModule.Initialize(ctx) }); err != nil {
+		log.Printf("❌ Failed to initialize fittings module: %v", err)
+	}
+
 	// 8. Initialize WebSocket module
 	log.Printf("🔌 Initializing WebSocket module")
 	websocketModule, err := websocket.NewModule(appCtx.MongoDB.Database, appCtx.Redis.Client, authModule.GetAuthService())
@@ -593,11 +736,13 @@ func main() {
 	}
 
 	// Start WebSocket service
-	if err := websocketModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("websocket", startup.PhaseInit, func() error { return websocketModule.Initialize(ctx) }); err != nil {
 		log.Printf("❌ Failed to initialize WebSocket service: %v", err)
 	} else {
 		log.Printf("✅ WebSocket module initialized successfully")
 	}
+	sdeAdminModule.SetNotifier(websocketModule)
+	notificationsModule.SetNotifier(websocketModule)
 
 	// 9. Initialize zkillboard module with websocket dependency
 	log.Printf("📡 Initializing ZKillboard module")
@@ -614,10 +759,64 @@ func main() {
 	}
 
 	// Initialize zkillboard module
-	if err := zkillboardModule.Initialize(ctx); err != nil {
+	if err := startupReport.Run("zkillboard", startup.PhaseInit, func() error { return zkillboardModule.Initialize(ctx) }); err != nil {
 		log.Fatalf("Failed to initialize zkillboard module: %v", err)
 	}
 
+	// Optional MongoDB change-stream listener so replicas converge quickly after admin changes
+	var changeListener *changestream.Listener
+	if config.GetChangeStreamsEnabled() {
+		log.Printf("👀 Starting MongoDB change-stream listener")
+		changeListener = changestream.NewListener(
+			appCtx.MongoDB.Database,
+			appCtx.Redis.Client,
+			websocketModule.GetService().GetRedisHub().GetServerID(),
+			config.GetChangeStreamCollections(),
+		)
+		changeListener.AddHandler(func(ctx context.Context, event changestream.Event) {
+			if err := websocketModule.BroadcastDataChange(ctx, event.Collection, event.OperationType, event.DocumentID, event.UpdatedFields); err != nil {
+				slog.Warn("Failed to broadcast data change", "collection", event.Collection, "error", err)
+			}
+		})
+		changeListener.Start(ctx)
+	}
+
+	// Site settings and sitemap routes are cached per instance; writes publish invalidations
+	// so every replica drops its copy
+	cacheBus := invalidation.NewBus(appCtx.Redis.Client, websocketModule.GetService().GetRedisHub().GetServerID())
+	siteSettingsModule.GetService().SetCacheInvalidator(cacheBus)
+	cacheBus.Subscribe(invalidation.ScopeSiteSettings, func(ctx context.Context) {
+		siteSettingsModule.GetService().InvalidateCache()
+	})
+	sitemapModule.GetService().SetCacheInvalidator(cacheBus)
+	cacheBus.Subscribe(invalidation.ScopeSitemap, func(ctx context.Context) {
+		sitemapModule.GetService().InvalidateCache()
+	})
+	cacheBus.Start(ctx)
+
+	// Changes written to MongoDB directly (scripts, restores, another replica's listener) reach
+	// every instance through the change events channel and drop the caches they affect
+	changeSubscriber := changestream.NewSubscriber(appCtx.Redis.Client)
+	changeSubscriber.Handle(func(ctx context.Context, event changestream.Event) {
+		siteSettingsModule.GetService().InvalidateCache()
+	}, "site_settings")
+	changeSubscriber.Handle(func(ctx context.Context, event changestream.Event) {
+		sitemapModule.GetService().InvalidateCache()
+	}, "routes")
+	changeSubscriber.Handle(func(ctx context.Context, event changestream.Event) {
+		permissionManager.InvalidateDecisions(ctx)
+	}, "groups", "group_memberships", "permissions", "group_permissions")
+	changeSubscriber.Start(ctx)
+
+	// Failed scheduled ESI updates are recorded and retried with backoff instead of leaving the
+	// data stale until the next run
+	esiRetryQueue := retryqueue.NewQueue(appCtx.MongoDB)
+	esiRetryQueue.Register(retryqueue.KindCorporation, 48*time.Hour, func(ctx context.Context, id int64) error {
+		return corporationModule.RefreshCorporation(evegateway.WithModule(ctx, "retryqueue"), id)
+	})
+	corporationModule.SetUpdateRecorder(esiRetryQueue)
+	esiRetryQueue.Start(ctx)
+
 	// Register WebSocket HTTP handler on main router (must be outside Huma API for WebSocket upgrades)
 	log.Printf("🔌 Registering WebSocket HTTP handler")
 	websocketModule.RegisterHTTPHandler(r)
@@ -629,46 +828,67 @@ func main() {
 	go func() {
 		log.Printf("🔄 Starting permission registration in background...")
 
+		// Register auth permissions
+		if err := startupReport.Run("auth permissions", startup.PhaseBackground, func() error { return authModule.RegisterPermissions(ctx, permissionManager) }); err != nil {
+			log.Printf("❌ Failed to register auth permissions: %v", err)
+		} else {
+			log.Printf("   🔑 Auth permissions registered successfully")
+		}
+
 		// Register scheduler permissions
-		if err := schedulerModule.RegisterPermissions(ctx, permissionManager); err != nil {
+		if err := startupReport.Run("scheduler permissions", startup.PhaseBackground, func() error { return schedulerModule.RegisterPermissions(ctx, permissionManager) }); err != nil {
 			log.Printf("❌ Failed to register scheduler permissions: %v", err)
 		} else {
 			log.Printf("   ⏰ Scheduler permissions registered successfully")
 		}
 
 		// Register character permissions
-		if err := characterModule.RegisterPermissions(ctx, permissionManager); err != nil {
+		if err := startupReport.Run("character permissions", startup.PhaseBackground, func() error { return characterModule.RegisterPermissions(ctx, permissionManager) }); err != nil {
 			log.Printf("❌ Failed to register character permissions: %v", err)
 		} else {
 			log.Printf("   🚀 Character permissions registered successfully")
 		}
 
 		// Register sitemap permissions
-		if err := sitemapModule.RegisterPermissions(ctx, permissionManager); err != nil {
+		if err := startupReport.Run("sitemap permissions", startup.PhaseBackground, func() error { return sitemapModule.RegisterPermissions(ctx, permissionManager) }); err != nil {
 			log.Printf("❌ Failed to register sitemap permissions: %v", err)
 		} else {
 			log.Printf("   🗺️  Sitemap permissions registered successfully")
 		}
 
 		// Seed default routes for sitemap
-		if err := sitemapModule.SeedDefaultRoutes(ctx); err != nil {
+		if err := startupReport.Run("sitemap default routes", startup.PhaseBackground, func() error { return sitemapModule.SeedDefaultRoutes(ctx) }); err != nil {
 			log.Printf("❌ Failed to seed default routes: %v", err)
 		}
 
 		// Register corporation permissions
-		if err := corporationModule.RegisterPermissions(ctx, permissionManager); err != nil {
+		if err := startupReport.Run("corporation permissions", startup.PhaseBackground, func() error { return corporationModule.RegisterPermissions(ctx, permissionManager) }); err != nil {
 			log.Printf("❌ Failed to register corporation permissions: %v", err)
 		} else {
 			log.Printf("   🏢 Corporation permissions registered successfully")
 		}
 
 		// Register alliance permissions
-		if err := allianceModule.RegisterPermissions(ctx, permissionManager); err != nil {
+		if err := startupReport.Run("alliance permissions", startup.PhaseBackground, func() error { return allianceModule.RegisterPermissions(ctx, permissionManager) }); err != nil {
 			log.Printf("❌ Failed to register alliance permissions: %v", err)
 		} else {
 			log.Printf("   🌟 Alliance permissions registered successfully")
 		}
 
+		// Register notifications permissions
+		if err := startupReport.Run("notifications permissions", startup.PhaseBackground, func() error { return notificationsModule.RegisterPermissions(ctx, permissionManager) }); err != nil {
+			log.Printf("❌ Failed to register notifications permissions: %v", err)
+		} else {
+			log.Printf("   🔔 Notifications permissions registered successfully")
+		}
+
+		// Register tags permissions (including those of existing namespaces)
+		if err := startupReport.Run("tags permissions", startup.PhaseBackground, func() error { return tagsModule.RegisterPermissions(ctx, permissionManager) }); err != nil {
+			log.Printf("❌ Failed to register tags permissions: %v", err)
+		} else {
+			log.Printf("   🏷️  Tags permissions registered successfully")
+		}
+
 		log.Printf("✅ Background permission registration completed")
 	}()
 
@@ -680,7 +900,7 @@ func main() {
 		time.Sleep(3 * time.Second) // Wait for service to start
 		log.Printf("🔄 Starting system group permission initialization in background...")
 
-		if err := permissionManager.InitializeSystemGroupPermissions(ctx); err != nil {
+		if err := startupReport.Run("system group permissions", startup.PhaseBackground, func() error { return permissionManager.InitializeSystemGroupPermissions(ctx) }); err != nil {
 			log.Printf("❌ Failed to initialize system group permissions: %v", err)
 		} else {
 			log.Printf("✅ System group permissions initialized successfully")
@@ -692,7 +912,7 @@ func main() {
 	// Update site settings with auth, groups services, and permission manager
 	siteSettingsModule.SetDependenciesWithPermissions(authModule.GetAuthService(), groupsModule.GetService(), permissionManager)
 
-	modules = append(modules, authModule, usersModule, discordModule, schedulerModule, characterModule, corporationModule, allianceModule, killmailsModule, zkillboardModule, groupsModule, sitemapModule, siteSettingsModule, sdeAdminModule, websocketModule, structuresModule, assetsModule, marketModule, mapModule)
+	modules = append(modules, authModule, usersModule, discordModule, schedulerModule, characterModule, corporationModule, allianceModule, killmailsModule, zkillboardModule, groupsModule, sitemapModule, siteSettingsModule, sdeAdminModule, websocketModule, structuresModule, assetsModule, marketModule, mapModule, fittingsModule, notificationsModule, tagsModule, commentsModule, attachmentsModule)
 
 	// Initialize remaining modules
 	// Initialize character module in background to avoid index creation hang during startup
@@ -700,7 +920,7 @@ func main() {
 		time.Sleep(5 * time.Second) // Wait for main service to be fully operational
 		log.Printf("🔄 Starting character module initialization in background...")
 
-		if err := characterModule.Initialize(ctx); err != nil {
+		if err := startupReport.Run("character", startup.PhaseBackground, func() error { return characterModule.Initialize(ctx) }); err != nil {
 			log.Printf("❌ Failed to initialize character module: %v", err)
 		} else {
 			log.Printf("✅ Character module initialized successfully (indexes created)")
@@ -754,22 +974,51 @@ func main() {
 		{Name: "Auth", Description: "EVE Online SSO authentication and JWT management"},
 		{Name: "Auth / EVE", Description: "EVE Online SSO integration endpoints"},
 		{Name: "Auth / Profile", Description: "User profile management and character information"},
+		{Name: "Auth / Characters", Description: "Characters linked to the signed-in account and the active character"},
+		{Name: "Auth / Sessions", Description: "Signed-in devices of the account and revoking them"},
+		{Name: "Auth / Discord", Description: "Discord account linked to the signed-in account"},
+		{Name: "Auth / Device", Description: "Device flow sign-in for CLI tools approved in the browser"},
+		{Name: "Auth / Providers", Description: "Staff sign-in through external identity providers (OIDC, Discord)"},
+		{Name: "Auth / Staff", Description: "Non-EVE staff accounts and their permissions"},
+		{Name: "Auth / Audit", Description: "Authentication audit log of logins, logouts, session refreshes and scope grants"},
+		{Name: "Auth / Impersonation", Description: "Super admin tokens acting as another user, and the record of them"},
+		{Name: "Auth / MFA", Description: "Authenticator (TOTP) enrollment and sudo tokens for actions that need a second factor"},
 		{Name: "Users", Description: "User management and character administration"},
 		{Name: "Users / Management", Description: "Administrative user management operations"},
 		{Name: "Users / Characters", Description: "Character listing and management"},
+		{Name: "Users / Usage", Description: "Per-character API usage statistics and leaderboards"},
+		{Name: "Users / Inactivity", Description: "Inactive user policy report and exemptions"},
+		{Name: "Users / Deletion", Description: "Account deletion requests (right to erasure) and their receipts"},
+		{Name: "Users / Tags", Description: "Administrative tags on users and the users audit log"},
+		{Name: "Users / Preferences", Description: "Per-user settings shared by all frontends"},
+		{Name: "Users / Data Export", Description: "Downloadable copies of everything stored about the caller's account"},
+		{Name: "Users / Merge", Description: "Merging duplicate user accounts and reverting merges"},
 		{Name: "Character", Description: "EVE Online character profiles and information"},
 		{Name: "Discord", Description: "Discord bot integration and role synchronization management"},
 		{Name: "Discord / OAuth", Description: "Discord OAuth authentication and account linking"},
 		{Name: "Discord / Guilds", Description: "Discord guild configuration and management"},
 		{Name: "Discord / Roles", Description: "Discord role mapping and synchronization"},
+		{Name: "Discord / Sync", Description: "Manual and per-user Discord role synchronization"},
+		{Name: "Discord / Users", Description: "Linked Discord accounts"},
 		{Name: "Corporations", Description: "EVE Online corporation information and management"},
+		{Name: "Corporations / Administration", Description: "Corporation maintenance operations such as CEO token validation"},
 		{Name: "Alliances", Description: "EVE Online alliance information and management"},
 		{Name: "Groups", Description: "Group and role-based access control management"},
 		{Name: "Groups / Management", Description: "Group creation, modification, and deletion"},
 		{Name: "Groups / Memberships", Description: "Character group membership operations"},
 		{Name: "Groups / Characters", Description: "Character-centric group operations"},
+		{Name: "Groups / Users", Description: "User-centric group operations"},
+		{Name: "Groups / Current User", Description: "Groups of the authenticated user"},
+		{Name: "Groups / Permissions", Description: "Group permission assignment and management"},
+		{Name: "Groups / Standings", Description: "Standings tier groups maintained from alliance contacts"},
+		{Name: "Groups / Snapshots", Description: "Historical group membership and permission snapshots"},
+		{Name: "Groups / Join Requests", Description: "Applications to join groups and their approval"},
+		{Name: "Groups / Audit", Description: "Audit log export of group, membership and permission changes"},
+		{Name: "Groups / Rules", Description: "Automatic custom group membership by corporation, alliance or corporation role"},
+		{Name: "Groups / Corporation Mappings", Description: "Custom group membership kept aligned with in-game corporation roles and titles"},
+		{Name: "Groups / Webhooks", Description: "Signed outbound notifications of membership and permission changes"},
+		{Name: "Groups / Templates", Description: "Group sets created together for onboarded corporations"},
 		{Name: "Permissions", Description: "Permission management and checking"},
-		{Name: "Group Permissions", Description: "Group permission assignment and management"},
 		{Name: "Scheduler", Description: "Task scheduling, execution, and monitoring"},
 		{Name: "Scheduler / Status", Description: "Task scheduler status and statistics"},
 		{Name: "Scheduler / Tasks", Description: "Scheduled task management and configuration"},
@@ -777,9 +1026,31 @@ func main() {
 		{Name: "Site Settings", Description: "Application configuration and site settings management"},
 		{Name: "Site Settings / Public", Description: "Public site settings accessible without authentication"},
 		{Name: "Site Settings / Management", Description: "Administrative site settings management operations"},
-		{Name: "SDE Admin", Description: "EVE Online Static Data Export administration and Redis import management"},
+		{Name: "Site Settings / Corporations", Description: "Managed corporation configuration"},
+		{Name: "Site Settings / Alliances", Description: "Managed alliance configuration"},
+		{Name: "Sitemap / Admin", Description: "Route and navigation administration"},
+		{Name: "Sitemap / User", Description: "Navigation and routes available to the current user"},
+		{Name: "SDE / Admin", Description: "EVE Online Static Data Export administration and Redis import management"},
 		{Name: "WebSocket", Description: "Real-time WebSocket communication and connection management"},
-		{Name: "WebSocket Admin", Description: "Administrative WebSocket connection and room management"},
+		{Name: "WebSocket / Admin", Description: "Administrative WebSocket connection and room management"},
+		{Name: "Map", Description: "EVE Online map search, regions and route calculation"},
+		{Name: "Map / Signatures", Description: "Shared cosmic signature tracking"},
+		{Name: "Map / Wormholes", Description: "Wormhole connection mapping"},
+		{Name: "Market / Orders", Description: "Market orders by station, region and item type"},
+		{Name: "Market / Summary", Description: "Region market summaries and trade hub price comparison"},
+		{Name: "Market / Administration", Description: "Market data fetch control"},
+		{Name: "Market / Debug", Description: "Market data diagnostics"},
+		{Name: "Killmails", Description: "Killmail lookup and import"},
+		{Name: "Killmails / Character Stats", Description: "Ship usage statistics derived from killmails"},
+		{Name: "Structures", Description: "Player-owned structure information and access tracking"},
+		{Name: "Assets", Description: "Character and corporation assets"},
+		{Name: "Fittings", Description: "Ship fittings imported from ESI and killmails"},
+		{Name: "Notifications", Description: "Notifications sent to users, groups, corporations, alliances or everyone"},
+		{Name: "Tags / Namespaces", Description: "Permission-controlled tag namespaces"},
+		{Name: "Tags / Management", Description: "Tag definitions within a namespace"},
+		{Name: "Tags / Assignments", Description: "Tags attached to characters, corporations, killmails and fittings"},
+		{Name: "Comments", Description: "Threaded comments on SRP requests, applications, timers and other objects"},
+		{Name: "Attachments", Description: "Screenshots and fitting files attached to SRP requests, applications and other objects"},
 		{Name: "ZKillboard", Description: "ZKillboard RedisQ consumer service and killmail statistics"},
 		{Name: "Module Status", Description: "Module health status and statistics endpoints"},
 	}
@@ -826,6 +1097,28 @@ func main() {
 		})
 	}
 
+	// Resolve the caller identity once per request so services, logs and background work can attribute it
+	unifiedAPI.UseMiddleware(middleware.IdentityMiddleware(authModule.GetAuthService()))
+
+	// Throttle users whose groups carry a rate limit policy
+	if config.GetGroupRateLimitsEnabled() && appCtx.Redis != nil {
+		rateLimiter := middleware.NewRateLimiter(appCtx.Redis.Client, groupsModule.GetService())
+		unifiedAPI.UseMiddleware(rateLimiter.Middleware(unifiedAPI))
+	}
+
+	// Record sampled per-character API usage for all operations
+	unifiedAPI.UseMiddleware(usersModule.UsageMiddleware())
+
+	// Destructive admin actions require a recent SSO sign-in or second-factor confirmation per session
+	var stepUpGuard *stepup.Guard
+	if config.GetStepUpEnabled() {
+		stepUpGuard = stepup.NewGuard(appCtx.Redis.Client, time.Duration(config.GetStepUpWindowMinutes())*time.Minute)
+		// Authenticator codes confirm step-ups and gate the actions that need a second factor
+		stepUpGuard.RegisterVerifier("totp", authModule.GetAuthService().TOTPVerifier())
+		stepUpGuard.SetSecondFactor(authModule.GetAuthService(), config.GetStepUpTOTPRequired())
+		unifiedAPI.UseMiddleware(stepUpGuard.Middleware())
+	}
+
 	log.Printf("✅ Unified Huma v2 API created")
 	log.Printf("🔧 Single OpenAPI 3.1.1 specification will be available at %s/openapi.json", apiPrefix)
 	log.Printf("📚 Scalar API Documentation available at /docs")
@@ -836,6 +1129,9 @@ func main() {
 	// Register auth module routes
 	log.Printf("   🔐 Auth module: /auth/*")
 	authModule.RegisterUnifiedRoutes(unifiedAPI, "/auth")
+	if stepUpGuard != nil {
+		stepup.RegisterRoutes(unifiedAPI, "/auth/step-up", stepUpGuard)
+	}
 
 	// Register users module routes
 	log.Printf("   👥 Users module: /users/*")
@@ -873,6 +1169,26 @@ func main() {
 	log.Printf("   ⚔️  Killmails module: /killmails/*")
 	killmailsModule.RegisterUnifiedRoutes(unifiedAPI, "/killmails")
 
+	// Register fittings module routes
+	log.Printf("   🛠️  Fittings module: /fittings/*")
+	fittingsModule.RegisterUnifiedRoutes(unifiedAPI, "/fittings")
+
+	// Register notifications module routes
+	log.Printf("   🔔 Notifications module: /notifications/*")
+	notificationsModule.RegisterUnifiedRoutes(unifiedAPI, "/notifications")
+
+	// Register tags module routes
+	log.Printf("   🏷️  Tags module: /tags/*")
+	tagsModule.RegisterUnifiedRoutes(unifiedAPI, "/tags")
+
+	// Register comments module routes
+	log.Printf("   💬 Comments module: /comments/*")
+	commentsModule.RegisterUnifiedRoutes(unifiedAPI, "/comments")
+
+	// Register attachments module routes
+	log.Printf("   📎 Attachments module: /attachments/*")
+	attachmentsModule.RegisterUnifiedRoutes(unifiedAPI, "/attachments")
+
 	// Register zkillboard module routes
 	log.Printf("   📡 ZKillboard module: /zkillboard/*")
 	if err := zkillboardModule.RegisterRoutes(unifiedAPI); err != nil {
@@ -907,8 +1223,34 @@ func main() {
 	log.Printf("   🔌 WebSocket module: /websocket/*")
 	websocketModule.RegisterUnifiedRoutes(unifiedAPI)
 
+	// Register startup report endpoint
+	log.Printf("   🩺 Startup report: /admin/startup-report")
+	startup.RegisterRoutes(unifiedAPI, "/admin/startup-report", startupReport, authMiddleware)
+
+	// Register ESI usage report endpoint
+	log.Printf("   📡 ESI usage report: /admin/esi-usage")
+	esiusage.RegisterRoutes(unifiedAPI, "/admin/esi-usage", evegateClient, authMiddleware)
+
+	// Register permission decision cache statistics endpoint
+	log.Printf("   🔐 Permission cache stats: /admin/permissions/cache-stats")
+	cachestats.RegisterRoutes(unifiedAPI, "/admin/permissions/cache-stats", permissionManager, authMiddleware)
+
+	// Register stale data report endpoint
+	log.Printf("   ⏳ Stale data report: /admin/stale-data")
+	retryqueue.RegisterRoutes(unifiedAPI, "/admin/stale-data", esiRetryQueue, authMiddleware)
+
+	// Register manual cache refresh endpoint
+	log.Printf("   🧹 Cache refresh: /admin/cache/refresh")
+	invalidation.RegisterRoutes(unifiedAPI, "/admin/cache", cacheBus, authMiddleware)
+
 	log.Printf("✅ All modules registered on unified API")
 
+	// Operation IDs and tags are linted against the committed lock so renames surface before
+	// generated clients break; `make openapi` runs the same check and fails on issues
+	if err := startupReport.Run("openapi lint", startup.PhaseInit, func() error { return lintOpenAPI(unifiedAPI) }); err != nil {
+		log.Printf("⚠️  %v", err)
+	}
+
 	// Note: evegateway is now a shared package for EVE Online ESI integration
 	// Other services can import and use: evegateway.NewClient().GetServerStatus(ctx)
 	_ = evegateClient // Available for modules to use
@@ -942,10 +1284,10 @@ func main() {
 		log.Printf(" - Mode: Integrated - HUMA APIs on main server %s:%s", host, port)
 	}
 
-	// Main server
+	// Main server; ?filter=viewer on the spec path returns only the operations the caller may use
 	srv := &http.Server{
 		Addr:         host + ":" + port,
-		Handler:      r,
+		Handler:      apidocs.FilterMiddleware(unifiedAPI, apiPrefix+"/openapi.json", authMiddleware)(r),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -982,6 +1324,7 @@ func main() {
 	// Start main server
 	go func() {
 		slog.Info("Starting main Falcon API server", slog.String("addr", srv.Addr))
+		startupReport.MarkReady()
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			slog.Error("Main server failed to start", "error", err)
 			os.Exit(1)
@@ -1016,29 +1359,53 @@ func main() {
 		mod.Stop()
 	}
 
+	cacheBus.Stop()
+	changeSubscriber.Stop()
+	esiRetryQueue.Stop()
+	if changeListener != nil {
+		changeListener.Stop()
+	}
+
 	// Application context will handle database and telemetry shutdown
 	appCtx.Shutdown(shutdownCtx)
 
 	slog.Info("Falcon shutdown completed successfully")
 }
 
-func enhancedHealthHandler(w http.ResponseWriter, r *http.Request) {
-	// Health checks are excluded from logging to reduce noise
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+func enhancedHealthHandler(evegateClient *evegateway.Client, mongodb *database.MongoDB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Health checks are excluded from logging to reduce noise
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
 
-	versionInfo := version.Get()
-	response := fmt.Sprintf(`{
-		"status": "healthy",
+		esiConnections, _ := json.Marshal(evegateClient.ConnectionStats())
+		esiBreakers, _ := json.Marshal(evegateClient.CircuitBreakerStats())
+		esiCoalescing, _ := json.Marshal(evegateClient.CoalescingStats())
+		mongoStatus, _ := json.Marshal(mongodb.Status())
+
+		// Still 200 while degraded: reads keep being served and the driver reconnects on its own
+		status := "healthy"
+		if !mongodb.Available() {
+			status = "degraded"
+		}
+
+		versionInfo := version.Get()
+		response := fmt.Sprintf(`{
+		"status": "%s",
 		"architecture": "falcon",
 		"version": "%s",
 		"git_commit": "%s",
 		"build_date": "%s",
 		"go_version": "%s",
-		"platform": "%s"
-	}`, versionInfo.Version, versionInfo.GitCommit, versionInfo.BuildDate, versionInfo.GoVersion, versionInfo.Platform)
-
-	w.Write([]byte(response))
+		"platform": "%s",
+		"esi_connections": %s,
+		"esi_circuit_breakers": %s,
+		"esi_coalescing": %s,
+		"mongodb": %s
+	}`, status, versionInfo.Version, versionInfo.GitCommit, versionInfo.BuildDate, versionInfo.GoVersion, versionInfo.Platform, esiConnections, esiBreakers, esiCoalescing, mongoStatus)
+
+		w.Write([]byte(response))
+	}
 }
 
 // scalarDocsHandler returns a handler that serves the Scalar API documentation interface
@@ -1164,6 +1531,14 @@ func printMemoryLimits() {
 	log.Printf(" - Container limit: Not detected (running outside container or unsupported)")
 }
 
+// containerMemoryLimit returns the container memory limit in bytes, or 0 when none is set
+func containerMemoryLimit() int64 {
+	if limit := readCgroupV2MemoryLimit(); limit > 0 {
+		return limit
+	}
+	return readCgroupV1MemoryLimit()
+}
+
 // readCgroupV2MemoryLimit reads memory limit from cgroups v2
 func readCgroupV2MemoryLimit() int64 {
 	data, err := os.ReadFile("/sys/fs/cgroup/memory.max")
@@ -1205,3 +1580,27 @@ func readCgroupV1MemoryLimit() int64 {
 
 	return limit
 }
+
+// lintOpenAPI checks the registered operations against the operation ID lock and tag conventions.
+// Issues are logged rather than fatal so a stale lock never blocks a deploy.
+func lintOpenAPI(api huma.API) error {
+	spec, err := json.Marshal(api.OpenAPI())
+	if err != nil {
+		return fmt.Errorf("failed to encode OpenAPI spec: %w", err)
+	}
+	lock, err := apidocs.DefaultLock()
+	if err != nil {
+		return err
+	}
+	issues, err := apidocs.Lint(spec, lock)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		slog.Warn("OpenAPI lint", "issue", issue.String())
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%d OpenAPI lint issue(s); run `make openapi` for details", len(issues))
+	}
+	return nil
+}
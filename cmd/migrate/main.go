@@ -2,30 +2,81 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"go-falcon/pkg/app"
+	"go-falcon/pkg/database"
 	pkgMigrations "go-falcon/pkg/migrations"
 
 	// Import all migration files to register them
 	localMigrations "go-falcon/migrations"
 )
 
+// Exit codes for automation
+const (
+	exitOK      = 0
+	exitFailed  = 1 // The command failed
+	exitUsage   = 2 // Invalid flags
+	exitPending = 3 // status -fail-on-pending found pending migrations
+
+	exitIrreversible = 4 // down refused an irreversible migration without -force
+)
+
+// result is the JSON document printed to stdout with -json
+type result struct {
+	Command    string                      `json:"command"`
+	DryRun     bool                        `json:"dry_run,omitempty"`
+	Applied    []string                    `json:"applied,omitempty"`
+	RolledBack []string                    `json:"rolled_back,omitempty"`
+	File       string                      `json:"file,omitempty"`
+	Status     *pkgMigrations.StatusReport `json:"status,omitempty"`
+	Schemas    []database.SchemaStatus     `json:"schemas,omitempty"`
+	Error      string                      `json:"error,omitempty"`
+}
+
 func main() {
 	// Define command flags
 	var (
-		command = flag.String("command", "up", "Migration command: up, down, status, create")
-		steps   = flag.Int("steps", 0, "Number of migrations to rollback (for down command)")
-		name    = flag.String("name", "", "Migration name (for create command)")
-		dryRun  = flag.Bool("dry-run", false, "Show what would be done without executing")
+		command       = flag.String("command", "up", "Migration command: up, down, status, schema, create")
+		steps         = flag.Int("steps", 0, "Number of migrations to rollback (for down command)")
+		name          = flag.String("name", "", "Migration name (for create command)")
+		dryRun        = flag.Bool("dry-run", false, "Show what would be done without executing")
+		jsonOutput    = flag.Bool("json", false, "Print a single JSON document to stdout; progress goes to stderr")
+		failOnPending = flag.Bool("fail-on-pending", false, "Exit with code 3 from status when migrations are pending")
+		force         = flag.Bool("force", false, "Roll back irreversible migrations (for down command)")
+		appliedBy     = flag.String("applied-by", "", "Who to record as applying migrations (defaults to the OS user)")
+		warnDocuments = flag.Int64("warn-documents", pkgMigrations.DefaultLargeCollectionThreshold, "Warn before index builds and scans on collections with at least this many documents")
 	)
 
 	flag.Parse()
 
+	out := &output{json: *jsonOutput, result: result{Command: *command, DryRun: *dryRun}}
+
+	switch *command {
+	case "up", "down", "status", "schema":
+	case "create":
+		if *name == "" {
+			out.fail(exitUsage, "Migration name is required for create command")
+		}
+		file, err := createMigration(*name, out.progress())
+		if err != nil {
+			out.fail(exitFailed, "Failed to create migration: %v", err)
+		}
+		out.result.File = file
+		out.done(exitOK)
+	default:
+		out.fail(exitUsage, "Unknown command: %s", *command)
+	}
+
 	// Initialize context
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
@@ -33,69 +84,186 @@ func main() {
 	// Initialize application (just for database connection)
 	appCtx, err := app.InitializeApp("migrate")
 	if err != nil {
-		log.Fatalf("❌ Failed to initialize application: %v", err)
+		out.fail(exitFailed, "Failed to initialize application: %v", err)
+	}
+	out.shutdown = func() { appCtx.Shutdown(ctx) }
+	if appCtx.MongoDB == nil {
+		out.fail(exitFailed, "MongoDB is not reachable; check MONGODB_URI")
+	}
+
+	if *command == "schema" {
+		report, err := database.SchemaReport(ctx, appCtx.MongoDB.Database)
+		if err != nil {
+			out.fail(exitFailed, "Failed to get schema report: %v", err)
+		}
+		out.result.Schemas = report
+		if !out.json {
+			printSchemaReport(out.progress(), report)
+		}
+		out.done(exitOK)
 	}
-	defer appCtx.Shutdown(ctx)
 
 	// Create migration runner
 	runner := pkgMigrations.NewRunner(appCtx.MongoDB.Database)
+	runner.SetOutput(out.progress())
+	runner.SetAppliedBy(*appliedBy)
+	runner.SetLargeCollectionThreshold(*warnDocuments)
 
 	// Register all migrations
 	localMigrations.RegisterAll(runner)
 
+	before, err := runner.GetStatus(ctx)
+	if err != nil {
+		out.fail(exitFailed, "Failed to get migration status: %v", err)
+	}
+
 	// Execute command
 	switch *command {
 	case "up":
-		fmt.Println("🚀 Running database migrations...")
+		fmt.Fprintln(out.progress(), "🚀 Running database migrations...")
 		if *dryRun {
-			fmt.Println("⚠️  DRY RUN MODE - No changes will be made")
-			if err := runner.Status(ctx); err != nil {
-				log.Fatalf("❌ Failed to show status: %v", err)
-			}
+			fmt.Fprintln(out.progress(), "⚠️  DRY RUN MODE - No changes will be made")
+			out.result.Applied = before.PendingVersions()
 		} else {
 			if err := runner.Run(ctx); err != nil {
-				log.Fatalf("❌ Migration failed: %v", err)
+				out.fail(exitFailed, "Migration failed: %v", err)
 			}
-			fmt.Println("✅ All migrations completed successfully")
+			fmt.Fprintln(out.progress(), "✅ All migrations completed successfully")
 		}
 
 	case "down":
 		if *steps == 0 {
 			*steps = 1 // Default to rolling back 1 migration
 		}
-		fmt.Printf("🔄 Rolling back %d migration(s)...\n", *steps)
+		fmt.Fprintf(out.progress(), "🔄 Rolling back %d migration(s)...\n", *steps)
 		if *dryRun {
-			fmt.Println("⚠️  DRY RUN MODE - No changes will be made")
-			if err := runner.Status(ctx); err != nil {
-				log.Fatalf("❌ Failed to show status: %v", err)
-			}
+			fmt.Fprintln(out.progress(), "⚠️  DRY RUN MODE - No changes will be made")
 		} else {
-			if err := runner.Rollback(ctx, *steps); err != nil {
-				log.Fatalf("❌ Rollback failed: %v", err)
+			if err := runner.Rollback(ctx, *steps, *force); err != nil {
+				if errors.Is(err, pkgMigrations.ErrIrreversible) {
+					out.fail(exitIrreversible, "Rollback refused: %v", err)
+				}
+				out.fail(exitFailed, "Rollback failed: %v", err)
 			}
-			fmt.Println("✅ Rollback completed successfully")
+			fmt.Fprintln(out.progress(), "✅ Rollback completed successfully")
 		}
+	}
 
-	case "status":
+	after := before
+	if !*dryRun && *command != "status" {
+		if after, err = runner.GetStatus(ctx); err != nil {
+			out.fail(exitFailed, "Failed to get migration status: %v", err)
+		}
+		out.result.Applied, out.result.RolledBack = diffApplied(before, after)
+	}
+	out.result.Status = after
+
+	if !out.json && (*command == "status" || *dryRun) {
 		if err := runner.Status(ctx); err != nil {
-			log.Fatalf("❌ Failed to get migration status: %v", err)
+			out.fail(exitFailed, "Failed to get migration status: %v", err)
 		}
+	}
 
-	case "create":
-		if *name == "" {
-			log.Fatal("❌ Migration name is required for create command")
+	if *command == "status" && *failOnPending && after.Pending > 0 {
+		out.done(exitPending)
+	}
+	out.done(exitOK)
+}
+
+// output prints human-readable progress, or a single JSON result with -json
+type output struct {
+	json     bool
+	result   result
+	shutdown func()
+}
+
+// progress returns where progress messages go: stderr in JSON mode so stdout stays parseable
+func (o *output) progress() io.Writer {
+	if o.json {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// fail reports an error and exits with the given code
+func (o *output) fail(code int, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if !o.json {
+		log.Printf("❌ %s", message)
+		os.Exit(code)
+	}
+	o.result.Error = message
+	o.done(code)
+}
+
+// done prints the JSON result in JSON mode, releases the database connection and exits with the given code
+func (o *output) done(code int) {
+	if o.shutdown != nil {
+		o.shutdown()
+	}
+	if o.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(o.result); err != nil {
+			log.Printf("❌ Failed to encode result: %v", err)
+			code = exitFailed
 		}
-		if err := createMigration(*name); err != nil {
-			log.Fatalf("❌ Failed to create migration: %v", err)
+	}
+	os.Exit(code)
+}
+
+// diffApplied returns the versions applied and rolled back between two status reports
+func diffApplied(before, after *pkgMigrations.StatusReport) (applied, rolledBack []string) {
+	wasApplied := make(map[string]bool, len(before.Migrations))
+	for _, m := range before.Migrations {
+		wasApplied[m.Version] = m.Applied
+	}
+	for _, m := range after.Migrations {
+		switch {
+		case m.Applied && !wasApplied[m.Version]:
+			applied = append(applied, m.Version)
+		case !m.Applied && wasApplied[m.Version]:
+			rolledBack = append(rolledBack, m.Version)
 		}
+	}
+	// Rollbacks run newest first
+	for i, j := 0, len(rolledBack)-1; i < j; i, j = i+1, j-1 {
+		rolledBack[i], rolledBack[j] = rolledBack[j], rolledBack[i]
+	}
+	return applied, rolledBack
+}
 
-	default:
-		log.Fatalf("❌ Unknown command: %s", *command)
+// printSchemaReport lists the documents per schema version of each versioned collection
+func printSchemaReport(w io.Writer, report []database.SchemaStatus) {
+	fmt.Fprintln(w, "\n📊 Document Schema Versions:")
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	if len(report) == 0 {
+		fmt.Fprintln(w, "No versioned collections registered")
+		return
+	}
+
+	for _, status := range report {
+		versions := make([]int, 0, len(status.Versions))
+		for version := range status.Versions {
+			versions = append(versions, version)
+		}
+		sort.Ints(versions)
+
+		counts := make([]string, 0, len(versions))
+		for _, version := range versions {
+			counts = append(counts, fmt.Sprintf("v%d: %d", version, status.Versions[version]))
+		}
+
+		marker := "✅"
+		if status.Outdated > 0 {
+			marker = "⏳"
+		}
+		fmt.Fprintf(w, "%s %s (current v%d, %d outdated) %s\n", marker, status.Collection, status.CurrentVersion, status.Outdated, strings.Join(counts, ", "))
 	}
 }
 
-// createMigration creates a new migration file template
-func createMigration(name string) error {
+// createMigration creates a new migration file template and returns its path
+func createMigration(name string, progress io.Writer) (string, error) {
 	// Get next version number
 	version := fmt.Sprintf("%03d", getNextVersionNumber())
 	filename := fmt.Sprintf("migrations/%s_%s.go", version, name)
@@ -115,6 +283,8 @@ func init() {
 		Description: "TODO: Add description",
 		Up:          up%s,
 		Down:        down%s,
+		// TODO: Set Irreversible if down loses data and list what up touches
+		Impact:      Impact{Collections: []string{}, IndexBuilds: 0},
 	})
 }
 
@@ -133,27 +303,27 @@ func down%s(ctx context.Context, db *mongo.Database) error {
 
 	// Create migrations directory if it doesn't exist
 	if err := os.MkdirAll("migrations", 0755); err != nil {
-		return err
+		return "", err
 	}
 
 	// Check if file already exists
 	if _, err := os.Stat(filename); err == nil {
-		return fmt.Errorf("migration file %s already exists", filename)
+		return "", fmt.Errorf("migration file %s already exists", filename)
 	}
 
 	// Write file
 	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
-		return err
+		return "", err
 	}
 
-	fmt.Printf("✅ Created migration file: %s\n", filename)
-	fmt.Println("📝 Don't forget to:")
-	fmt.Println("   1. Update the Description field")
-	fmt.Println("   2. Implement the up() function")
-	fmt.Println("   3. Implement the down() function (if possible)")
-	fmt.Println("   4. Import the migration in migrations/registry.go")
+	fmt.Fprintf(progress, "✅ Created migration file: %s\n", filename)
+	fmt.Fprintln(progress, "📝 Don't forget to:")
+	fmt.Fprintln(progress, "   1. Update the Description field")
+	fmt.Fprintln(progress, "   2. Implement the up() function")
+	fmt.Fprintln(progress, "   3. Implement the down() function (if possible)")
+	fmt.Fprintln(progress, "   4. Declare Impact and, if down loses data, Irreversible")
 
-	return nil
+	return filename, nil
 }
 
 // getNextVersionNumber determines the next migration version number
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go-falcon/pkg/apidocs"
+)
+
+func main() {
+	var (
+		source     = flag.String("source", "http://localhost:8080/openapi.json", "URL or file to read the OpenAPI spec from")
+		output     = flag.String("output", "falcon-openapi.json", "File to write the exported spec to (empty to skip)")
+		lockFile   = flag.String("lock", "pkg/apidocs/operations.lock.json", "Operation ID lock file")
+		updateLock = flag.Bool("update-lock", false, "Rewrite the lock file from the spec instead of checking it")
+		checkOnly  = flag.Bool("check", false, "Validate the spec without writing the output file")
+	)
+	flag.Parse()
+
+	spec, err := readSpec(*source)
+	if err != nil {
+		log.Fatalf("❌ Failed to read OpenAPI spec from %s: %v", *source, err)
+	}
+
+	operations, _, err := apidocs.Operations(spec)
+	if err != nil {
+		log.Fatalf("❌ Invalid OpenAPI spec: %v", err)
+	}
+
+	if *updateLock {
+		data, err := apidocs.NewLock(operations).Encode()
+		if err != nil {
+			log.Fatalf("❌ Failed to encode lock: %v", err)
+		}
+		if err := os.WriteFile(*lockFile, data, 0o644); err != nil {
+			log.Fatalf("❌ Failed to write %s: %v", *lockFile, err)
+		}
+		fmt.Printf("🔒 Locked %d operation IDs in %s\n", len(operations), *lockFile)
+	}
+
+	lockData, err := os.ReadFile(*lockFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to read %s: %v", *lockFile, err)
+	}
+	lock, err := apidocs.ParseLock(lockData)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	issues, err := apidocs.Lint(spec, lock)
+	if err != nil {
+		log.Fatalf("❌ Failed to lint OpenAPI spec: %v", err)
+	}
+	if len(issues) > 0 {
+		fmt.Printf("❌ %d OpenAPI issue(s) found:\n", len(issues))
+		for _, issue := range issues {
+			fmt.Printf("   - %s\n", issue)
+		}
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %d operations validated\n", len(operations))
+
+	if *checkOnly || *output == "" {
+		return
+	}
+	if err := os.WriteFile(*output, spec, 0o644); err != nil {
+		log.Fatalf("❌ Failed to write %s: %v", *output, err)
+	}
+	fmt.Printf("📄 OpenAPI spec written to %s\n", *output)
+}
+
+// readSpec loads the spec from a running server or a previously exported file
+func readSpec(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"go-falcon/pkg/version"
+)
+
+func main() {
+	var (
+		jsonOutput = flag.Bool("json", false, "Print the build information as JSON")
+		short      = flag.Bool("short", false, "Print only the version string")
+	)
+	flag.Parse()
+
+	info := version.Get()
+
+	switch {
+	case *jsonOutput:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(info); err != nil {
+			log.Fatalf("❌ Failed to encode version info: %v", err)
+		}
+	case *short:
+		fmt.Println(version.GetVersionString())
+	default:
+		fmt.Println(version.GetBuildInfo())
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"go-falcon/internal/assets/services"
 	models "go-falcon/internal/auth/models"
 	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/residency"
 )
 
 // contextKey type for context keys
@@ -67,6 +68,59 @@ func (r *AssetRoutes) RegisterRoutes(api huma.API) {
 		}, nil
 	})
 
+	// Asset data endpoints are left out when the deployment disables asset data
+	if residency.Configured(residency.CategoryAssets) != residency.ModeDisabled {
+		r.registerAssetDataRoutes(api)
+	}
+
+	// Structure access monitoring endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "getStructureAccessStats",
+		Method:      "GET",
+		Path:        "/assets/structure-access-stats",
+		Summary:     "Get structure access statistics",
+		Description: "Returns statistics about failed structure access attempts for monitoring purposes",
+		Tags:        []string{"Assets"},
+		Security: []map[string][]string{
+			{"bearerAuth": {}},
+			{"cookieAuth": {}},
+		},
+	}, func(ctx context.Context, input *struct {
+		CharacterID int32 `query:"character_id" doc:"Optional character ID to filter stats, 0 for global stats"`
+	}) (*dto.StructureAccessStatsOutput, error) {
+		// Get authenticated user from context (authentication is handled by API gateway)
+		authData, ok := ctx.Value(AuthDataKey).(*AuthData)
+		if !ok || authData == nil || authData.User == nil {
+			return nil, huma.Error401Unauthorized("authentication required")
+		}
+
+		// Admin users can view all stats, regular users only their own
+		var queryCharID *int32
+		if input.CharacterID > 0 {
+			// Check if user has permission to view this character's stats
+			if input.CharacterID != int32(authData.User.CharacterID) {
+				// TODO: Add admin check here
+				return nil, huma.Error403Forbidden("not authorized to view this character's statistics")
+			}
+			queryCharID = &input.CharacterID
+		}
+
+		stats, err := r.service.GetStructureAccessStats(ctx, queryCharID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to get structure access stats", err)
+		}
+
+		return &dto.StructureAccessStatsOutput{
+			Body: stats,
+		}, nil
+	})
+
+	// TODO: Add remaining asset endpoints
+	// Corporation assets, tracking endpoints, etc.
+}
+
+// registerAssetDataRoutes registers the endpoints serving character asset data
+func (r *AssetRoutes) registerAssetDataRoutes(api huma.API) {
 	// Character assets endpoint - requires authentication and ownership
 	huma.Register(api, huma.Operation{
 		OperationID: "getCharacterAssets",
@@ -80,6 +134,10 @@ func (r *AssetRoutes) RegisterRoutes(api huma.API) {
 			{"cookieAuth": {}},
 		},
 	}, func(ctx context.Context, input *dto.GetCharacterAssetsRequest) (*dto.AssetListOutput, error) {
+		if err := residency.Require(ctx, residency.CategoryAssets); err != nil {
+			return nil, err
+		}
+
 		// Authenticate user
 		user, err := r.middleware.RequireAuth(ctx, input.Authorization, input.Cookie)
 		if err != nil {
@@ -180,6 +238,10 @@ func (r *AssetRoutes) RegisterRoutes(api huma.API) {
 			{"cookieAuth": {}},
 		},
 	}, func(ctx context.Context, input *dto.RefreshCharacterAssetsRequest) (*dto.RefreshAssetsOutput, error) {
+		if err := residency.Require(ctx, residency.CategoryAssets); err != nil {
+			return nil, err
+		}
+
 		// Authenticate user
 		user, err := r.middleware.RequireAuth(ctx, input.Authorization, input.Cookie)
 		if err != nil {
@@ -252,49 +314,4 @@ func (r *AssetRoutes) RegisterRoutes(api huma.API) {
 			},
 		}, nil
 	})
-
-	// Structure access monitoring endpoint
-	huma.Register(api, huma.Operation{
-		OperationID: "getStructureAccessStats",
-		Method:      "GET",
-		Path:        "/assets/structure-access-stats",
-		Summary:     "Get structure access statistics",
-		Description: "Returns statistics about failed structure access attempts for monitoring purposes",
-		Tags:        []string{"Assets"},
-		Security: []map[string][]string{
-			{"bearerAuth": {}},
-			{"cookieAuth": {}},
-		},
-	}, func(ctx context.Context, input *struct {
-		CharacterID int32 `query:"character_id" doc:"Optional character ID to filter stats, 0 for global stats"`
-	}) (*dto.StructureAccessStatsOutput, error) {
-		// Get authenticated user from context (authentication is handled by API gateway)
-		authData, ok := ctx.Value(AuthDataKey).(*AuthData)
-		if !ok || authData == nil || authData.User == nil {
-			return nil, huma.Error401Unauthorized("authentication required")
-		}
-
-		// Admin users can view all stats, regular users only their own
-		var queryCharID *int32
-		if input.CharacterID > 0 {
-			// Check if user has permission to view this character's stats
-			if input.CharacterID != int32(authData.User.CharacterID) {
-				// TODO: Add admin check here
-				return nil, huma.Error403Forbidden("not authorized to view this character's statistics")
-			}
-			queryCharID = &input.CharacterID
-		}
-
-		stats, err := r.service.GetStructureAccessStats(ctx, queryCharID)
-		if err != nil {
-			return nil, huma.Error500InternalServerError("failed to get structure access stats", err)
-		}
-
-		return &dto.StructureAccessStatsOutput{
-			Body: stats,
-		}, nil
-	})
-
-	// TODO: Add remaining asset endpoints
-	// Corporation assets, tracking endpoints, etc.
 }
@@ -13,6 +13,7 @@ import (
 	structureModels "go-falcon/internal/structures/models"
 	"go-falcon/internal/structures/services"
 	"go-falcon/pkg/evegateway"
+	"go-falcon/pkg/residency"
 	"go-falcon/pkg/sde"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -41,8 +42,17 @@ func NewAssetService(db *mongo.Database, eveGateway *evegateway.Client, sdeServi
 	}
 }
 
-// GetCharacterAssets retrieves character assets from database only
+// GetCharacterAssets retrieves character assets from the database, or live from ESI when asset
+// storage is restricted to the cache
 func (s *AssetService) GetCharacterAssets(ctx context.Context, characterID int32, token string, locationID *int64) ([]*models.Asset, int, error) {
+	if !residency.Stores(ctx, residency.CategoryAssets) {
+		esiAssets, err := s.eveGateway.Assets.GetCharacterAssets(ctx, characterID, token)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to fetch assets from ESI: %w", err)
+		}
+		return s.liveAssets(ctx, esiAssets, characterID, 0, token, locationID, nil)
+	}
+
 	// Get assets from database only - no ESI queries
 	filter := bson.M{"character_id": characterID}
 	if locationID != nil {
@@ -65,8 +75,17 @@ func (s *AssetService) GetCharacterAssets(ctx context.Context, characterID int32
 	return assets, total, nil
 }
 
-// GetCorporationAssets retrieves corporation assets from database only
+// GetCorporationAssets retrieves corporation assets from the database, or live from ESI when asset
+// storage is restricted to the cache
 func (s *AssetService) GetCorporationAssets(ctx context.Context, corporationID, characterID int32, token string, locationID *int64, division *int) ([]*models.Asset, int, error) {
+	if !residency.Stores(ctx, residency.CategoryAssets) {
+		esiAssets, err := s.eveGateway.Assets.GetCorporationAssets(ctx, corporationID, token)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to fetch assets from ESI: %w", err)
+		}
+		return s.liveAssets(ctx, esiAssets, characterID, corporationID, token, locationID, division)
+	}
+
 	// Get assets from database only - no ESI queries
 	filter := bson.M{"corporation_id": corporationID}
 	if locationID != nil {
@@ -92,6 +111,27 @@ func (s *AssetService) GetCorporationAssets(ctx context.Context, corporationID,
 	return assets, total, nil
 }
 
+// liveAssets processes assets fetched from ESI without storing them and applies the filters the
+// database queries would
+func (s *AssetService) liveAssets(ctx context.Context, esiAssets []map[string]any, characterID, corporationID int32, token string, locationID *int64, division *int) ([]*models.Asset, int, error) {
+	processed, err := s.processESIAssetsInMemory(ctx, esiAssets, characterID, corporationID, token)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to process ESI assets: %w", err)
+	}
+
+	assets := make([]*models.Asset, 0, len(processed))
+	for _, asset := range processed {
+		if locationID != nil && asset.LocationID != *locationID {
+			continue
+		}
+		if division != nil && asset.LocationFlag != fmt.Sprintf("CorpSAG%d", *division) {
+			continue
+		}
+		assets = append(assets, asset)
+	}
+	return assets, len(assets), nil
+}
+
 // isContainer checks if a type ID is a container
 func (s *AssetService) isContainer(typeID int32) bool {
 	for _, containerID := range models.ContainerTypeIDs {
@@ -118,8 +158,17 @@ func (s *AssetService) determineLocationType(locationID int64) string {
 	}
 }
 
-// RefreshCharacterAssets forces a refresh of character assets from ESI
+// RefreshCharacterAssets forces a refresh of character assets from ESI. When asset storage is
+// restricted nothing is fetched; assets stored before the restriction are removed instead.
 func (s *AssetService) RefreshCharacterAssets(ctx context.Context, characterID int32, token string) (int, int, int, error) {
+	if !residency.Stores(ctx, residency.CategoryAssets) {
+		result, err := s.db.Collection(models.AssetsCollection).DeleteMany(ctx, bson.M{"character_id": characterID})
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("failed to remove stored assets: %w", err)
+		}
+		return 0, 0, int(result.DeletedCount), nil
+	}
+
 	// Check ESI error limits before starting
 	if err := s.eveGateway.CheckErrorLimits(); err != nil {
 		return 0, 0, 0, fmt.Errorf("cannot refresh assets: %w", err)
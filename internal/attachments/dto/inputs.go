@@ -0,0 +1,41 @@
+package dto
+
+import "github.com/danielgtaylor/huma/v2"
+
+// UploadForm is the multipart form of an attachment upload
+type UploadForm struct {
+	File huma.FormFile `form:"file" required:"true" doc:"File to attach; its type is detected from its contents"`
+}
+
+// UploadAttachmentInput represents the input for attaching a file to an object
+type UploadAttachmentInput struct {
+	ObjectType    string `path:"object_type" doc:"Kind of object, as registered by its owning module" example:"srp-request"`
+	ObjectID      string `path:"object_id" doc:"ID of the object within its module"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	RawBody       huma.MultipartFormFiles[UploadForm]
+}
+
+// ObjectAttachmentsInput identifies an object whose attachments are listed
+type ObjectAttachmentsInput struct {
+	ObjectType    string `path:"object_type" doc:"Kind of object, as registered by its owning module" example:"srp-request"`
+	ObjectID      string `path:"object_id" doc:"ID of the object within its module"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// AttachmentInput identifies an attachment on an object
+type AttachmentInput struct {
+	ObjectType    string `path:"object_type" doc:"Kind of object, as registered by its owning module" example:"srp-request"`
+	ObjectID      string `path:"object_id" doc:"ID of the object within its module"`
+	AttachmentID  string `path:"attachment_id" doc:"Attachment ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// DownloadAttachmentInput represents a signed download request
+type DownloadAttachmentInput struct {
+	AttachmentID string `path:"attachment_id" doc:"Attachment ID"`
+	Expires      int64  `query:"expires" doc:"Unix time the URL expires at"`
+	Signature    string `query:"signature" doc:"URL signature"`
+}
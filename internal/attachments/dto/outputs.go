@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"go-falcon/internal/attachments/models"
+)
+
+// AttachmentResponse is an attachment with a signed URL to download it
+type AttachmentResponse struct {
+	ID                    string    `json:"id"`
+	Filename              string    `json:"filename"`
+	ContentType           string    `json:"content_type"`
+	Size                  int64     `json:"size" doc:"Size in bytes"`
+	SHA256                string    `json:"sha256"`
+	Scanned               bool      `json:"scanned" doc:"Whether the file passed a virus scan; false when scanning is not configured"`
+	UploadedByCharacterID int       `json:"uploaded_by_character_id"`
+	UploaderName          string    `json:"uploader_name"`
+	CreatedAt             time.Time `json:"created_at"`
+	DownloadURL           string    `json:"download_url" doc:"Signed URL that serves the file without further authentication until download_url_expires_at"`
+	DownloadURLExpiresAt  time.Time `json:"download_url_expires_at"`
+}
+
+// AttachmentOutput represents the output for a single attachment
+type AttachmentOutput struct {
+	Body AttachmentResponse `json:"body"`
+}
+
+// AttachmentListOutput represents the attachments of an object
+type AttachmentListOutput struct {
+	Body struct {
+		ObjectType  string               `json:"object_type"`
+		ObjectID    string               `json:"object_id"`
+		Attachments []AttachmentResponse `json:"attachments"`
+	} `json:"body"`
+}
+
+// DownloadOutput is the raw contents of an attachment
+type DownloadOutput struct {
+	ContentType         string `header:"Content-Type"`
+	ContentDisposition  string `header:"Content-Disposition"`
+	CacheControl        string `header:"Cache-Control"`
+	XContentTypeOptions string `header:"X-Content-Type-Options"`
+	Body                []byte
+}
+
+// MessageOutput represents a plain confirmation message
+type MessageOutput struct {
+	Body struct {
+		Message string `json:"message"`
+	} `json:"body"`
+}
+
+// AttachmentsStatusResponse represents the module status response
+type AttachmentsStatusResponse struct {
+	Module       string   `json:"module" description:"Module name"`
+	Status       string   `json:"status" enum:"healthy,unhealthy" description:"Module health status"`
+	Message      string   `json:"message,omitempty" description:"Optional status message or error details"`
+	ObjectTypes  []string `json:"object_types" description:"Object types registered by other modules"`
+	VirusScan    bool     `json:"virus_scan" description:"Whether uploads are virus-scanned"`
+	MaxSizeBytes int64    `json:"max_size_bytes" description:"Largest accepted upload"`
+	AllowedTypes []string `json:"allowed_types" description:"Accepted content types"`
+}
+
+// StatusOutput represents the module status output
+type StatusOutput struct {
+	Body AttachmentsStatusResponse `json:"body"`
+}
+
+// ToAttachmentResponse converts an attachment model into its response form
+func ToAttachmentResponse(attachment *models.Attachment, downloadURL string, expiresAt time.Time) AttachmentResponse {
+	return AttachmentResponse{
+		ID:                    attachment.ID.Hex(),
+		Filename:              attachment.Filename,
+		ContentType:           attachment.ContentType,
+		Size:                  attachment.Size,
+		SHA256:                attachment.SHA256,
+		Scanned:               attachment.Scanned,
+		UploadedByCharacterID: attachment.UploadedByCharacterID,
+		UploaderName:          attachment.UploaderName,
+		CreatedAt:             attachment.CreatedAt,
+		DownloadURL:           downloadURL,
+		DownloadURLExpiresAt:  expiresAt,
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AttachmentsCollection = "attachments"
+	// StorageBucket is the GridFS bucket holding attachment contents
+	StorageBucket = "attachment_files"
+)
+
+// Attachment is a file attached to an object owned by another module, such as a screenshot on
+// an SRP request or a fitting file on a recruitment application. The contents live in the
+// storage backend under StorageKey.
+type Attachment struct {
+	ID                    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ObjectType            string             `bson:"object_type" json:"object_type"`
+	ObjectID              string             `bson:"object_id" json:"object_id"`
+	Filename              string             `bson:"filename" json:"filename"`
+	ContentType           string             `bson:"content_type" json:"content_type"`
+	Size                  int64              `bson:"size" json:"size"`
+	SHA256                string             `bson:"sha256" json:"sha256"`
+	StorageKey            string             `bson:"storage_key" json:"-"`
+	Scanned               bool               `bson:"scanned" json:"scanned"`
+	UploadedByUserID      string             `bson:"uploaded_by_user_id" json:"uploaded_by_user_id"`
+	UploadedByCharacterID int                `bson:"uploaded_by_character_id" json:"uploaded_by_character_id"`
+	UploaderName          string             `bson:"uploader_name" json:"uploader_name"`
+	CreatedAt             time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package attachments
+
+import (
+	"context"
+
+	"go-falcon/internal/attachments/routes"
+	"go-falcon/internal/attachments/services"
+	"go-falcon/pkg/database"
+	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/module"
+	"go-falcon/pkg/storage"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/go-chi/chi/v5"
+)
+
+// Module represents the attachments module
+type Module struct {
+	*module.BaseModule
+	service              *services.Service
+	repository           *services.Repository
+	permissionMiddleware *middleware.PermissionMiddleware
+}
+
+// New creates a new attachments module instance storing files in the given store
+func New(mongodb *database.MongoDB, redis *database.Redis, permissionMiddleware *middleware.PermissionMiddleware, store storage.Store) *Module {
+	repository := services.NewRepository(mongodb)
+	service := services.NewService(repository, store)
+
+	return &Module{
+		BaseModule:           module.NewBaseModule("attachments", mongodb, redis),
+		service:              service,
+		repository:           repository,
+		permissionMiddleware: permissionMiddleware,
+	}
+}
+
+// SetScanner replaces the virus scanner configured through ATTACHMENTS_CLAMD_ADDRESS
+func (m *Module) SetScanner(scanner services.Scanner) {
+	m.service.SetScanner(scanner)
+}
+
+// RegisterObjectType allows files to be attached to a module's objects. The owning module
+// supplies the policy deciding who may view, upload to and moderate each object's attachments.
+func (m *Module) RegisterObjectType(objectType string, policy services.AccessPolicy) {
+	m.service.RegisterObjectType(objectType, policy)
+}
+
+// RegisterUnifiedRoutes registers all attachments routes with the unified API gateway
+func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string) {
+	routes.RegisterAttachmentsRoutes(api, basePath, m.service, m.permissionMiddleware)
+}
+
+// Routes registers routes on a Chi router (implements module.Module interface)
+func (m *Module) Routes(r chi.Router) {
+	// Attachments module uses only Huma v2 unified routes
+}
+
+// Initialize performs module initialization tasks
+func (m *Module) Initialize(ctx context.Context) error {
+	return m.repository.CreateIndexes(ctx)
+}
+
+// GetService returns the service instance for this module
+func (m *Module) GetService() *services.Service {
+	return m.service
+}
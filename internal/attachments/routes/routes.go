@@ -0,0 +1,110 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+
+	"go-falcon/internal/attachments/dto"
+	"go-falcon/internal/attachments/services"
+	"go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// uploadOverhead is the allowance for multipart framing on top of the maximum file size
+const uploadOverhead = 1 << 20
+
+// RegisterAttachmentsRoutes registers attachments routes on a shared Huma API. Routes only
+// authenticate the caller; what they may do is decided by the policy of the object's owning module.
+func RegisterAttachmentsRoutes(api huma.API, basePath string, service *services.Service, permissionMiddleware *middleware.PermissionMiddleware) {
+	// Status endpoint (public, no auth required)
+	huma.Register(api, huma.Operation{
+		OperationID: "attachments-get-status",
+		Method:      http.MethodGet,
+		Path:        basePath + "/status",
+		Summary:     "Get attachments module status",
+		Description: "Returns the health status of the attachments module, the object types files can be attached to and the upload limits",
+		Tags:        []string{"Module Status"},
+	}, func(ctx context.Context, input *struct{}) (*dto.StatusOutput, error) {
+		return &dto.StatusOutput{Body: *service.GetStatus(ctx)}, nil
+	})
+
+	// Signed download (public; the signature in the URL grants access)
+	huma.Register(api, huma.Operation{
+		OperationID: "attachments-download-attachment",
+		Method:      http.MethodGet,
+		Path:        basePath + "/download/{attachment_id}",
+		Summary:     "Download attachment",
+		Description: "Serves an attachment's contents. The URL is signed and short-lived; fresh URLs are returned whenever attachments are listed or fetched.",
+		Tags:        []string{"Attachments"},
+	}, func(ctx context.Context, input *dto.DownloadAttachmentInput) (*dto.DownloadOutput, error) {
+		return service.Download(ctx, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "attachments-list-attachments",
+		Method:      http.MethodGet,
+		Path:        basePath + "/{object_type}/{object_id}",
+		Summary:     "List attachments",
+		Description: "Lists the files attached to an object, oldest first, with signed download URLs (requires view access to the object)",
+		Tags:        []string{"Attachments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ObjectAttachmentsInput) (*dto.AttachmentListOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		return service.ListAttachments(ctx, user, input.ObjectType, input.ObjectID)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "attachments-upload-attachment",
+		Method:        http.MethodPost,
+		Path:          basePath + "/{object_type}/{object_id}",
+		Summary:       "Upload attachment",
+		Description:   "Attaches a file to an object. The file's type is detected from its contents and must be allowed; files are virus scanned when a scanner is configured.",
+		Tags:          []string{"Attachments"},
+		DefaultStatus: http.StatusCreated,
+		MaxBodyBytes:  service.MaxSize() + uploadOverhead,
+		Security:      []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UploadAttachmentInput) (*dto.AttachmentOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		form := input.RawBody.Data()
+		return service.Upload(ctx, user, input.ObjectType, input.ObjectID, form.File)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "attachments-get-attachment",
+		Method:      http.MethodGet,
+		Path:        basePath + "/{object_type}/{object_id}/{attachment_id}",
+		Summary:     "Get attachment",
+		Description: "Returns an attachment's details with a signed download URL (requires view access to the object)",
+		Tags:        []string{"Attachments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.AttachmentInput) (*dto.AttachmentOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		return service.GetAttachment(ctx, user, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "attachments-delete-attachment",
+		Method:      http.MethodDelete,
+		Path:        basePath + "/{object_type}/{object_id}/{attachment_id}",
+		Summary:     "Delete attachment",
+		Description: "Removes an attachment and its file (uploader or moderator)",
+		Tags:        []string{"Attachments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.AttachmentInput) (*dto.MessageOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		return service.DeleteAttachment(ctx, user, input)
+	})
+}
@@ -0,0 +1,15 @@
+package services
+
+import "context"
+
+// AccessPolicy decides who may see and add the attachments of one kind of object. It is
+// implemented by the module that owns the objects.
+type AccessPolicy interface {
+	// CanView reports whether the character may list and download the object's attachments
+	CanView(ctx context.Context, characterID int, objectID string) (bool, error)
+	// CanUpload reports whether the character may attach files to the object and delete
+	// their own attachments
+	CanUpload(ctx context.Context, characterID int, objectID string) (bool, error)
+	// CanModerate reports whether the character may delete anyone's attachments on the object
+	CanModerate(ctx context.Context, characterID int, objectID string) (bool, error)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+
+	"go-falcon/internal/attachments/models"
+	"go-falcon/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	db          *database.MongoDB
+	attachments *mongo.Collection
+}
+
+func NewRepository(db *database.MongoDB) *Repository {
+	return &Repository{
+		db:          db,
+		attachments: db.Database.Collection(models.AttachmentsCollection),
+	}
+}
+
+// CreateAttachment inserts an attachment record
+func (r *Repository) CreateAttachment(ctx context.Context, attachment *models.Attachment) error {
+	_, err := r.attachments.InsertOne(ctx, attachment)
+	return err
+}
+
+// GetAttachment retrieves an attachment by ID
+func (r *Repository) GetAttachment(ctx context.Context, id primitive.ObjectID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.attachments.FindOne(ctx, bson.M{"_id": id}).Decode(&attachment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// ListAttachments returns an object's attachments, oldest first
+func (r *Repository) ListAttachments(ctx context.Context, objectType, objectID string) ([]models.Attachment, error) {
+	cursor, err := r.attachments.Find(ctx,
+		bson.M{"object_type": objectType, "object_id": objectID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	attachments := []models.Attachment{}
+	if err := cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// CountAttachments returns how many attachments an object has
+func (r *Repository) CountAttachments(ctx context.Context, objectType, objectID string) (int64, error) {
+	return r.attachments.CountDocuments(ctx, bson.M{"object_type": objectType, "object_id": objectID})
+}
+
+// DeleteAttachment removes an attachment record
+func (r *Repository) DeleteAttachment(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.attachments.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// CheckHealth verifies database connectivity
+func (r *Repository) CheckHealth(ctx context.Context) error {
+	return r.db.HealthCheck(ctx)
+}
+
+// CreateIndexes creates the indexes for attachments
+func (r *Repository) CreateIndexes(ctx context.Context) error {
+	_, err := r.attachments.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "object_type", Value: 1}, {Key: "object_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "uploaded_by_character_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	return err
+}
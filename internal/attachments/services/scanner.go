@@ -0,0 +1,89 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrInfected is returned by a Scanner when the file contains malware
+var ErrInfected = errors.New("file is infected")
+
+// Scanner checks uploaded files for malware before they are stored. It returns an error
+// wrapping ErrInfected for infected files and any other error when the scan could not run.
+type Scanner interface {
+	Scan(ctx context.Context, data []byte) error
+}
+
+// clamdChunkSize is the size of the chunks streamed to clamd
+const clamdChunkSize = 64 * 1024
+
+// ClamdScanner scans files with a ClamAV daemon over its INSTREAM command
+type ClamdScanner struct {
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner creates a scanner for the clamd listening on address (host:port)
+func NewClamdScanner(address string) *ClamdScanner {
+	return &ClamdScanner{address: address, timeout: 30 * time.Second}
+}
+
+// Scan implements Scanner
+func (s *ClamdScanner) Scan(ctx context.Context, data []byte) error {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(s.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("failed to send to clamd: %w", err)
+	}
+
+	size := make([]byte, 4)
+	for reader := bytes.NewReader(data); reader.Len() > 0; {
+		chunk := make([]byte, min(clamdChunkSize, reader.Len()))
+		reader.Read(chunk)
+
+		binary.BigEndian.PutUint32(size, uint32(len(chunk)))
+		if _, err := conn.Write(append(size, chunk...)); err != nil {
+			return fmt.Errorf("failed to send to clamd: %w", err)
+		}
+	}
+	// A zero-length chunk ends the stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return fmt.Errorf("failed to send to clamd: %w", err)
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+
+	// Replies look like "stream: OK" or "stream: <signature> FOUND"
+	result := strings.TrimSpace(strings.TrimRight(string(reply), "\x00"))
+	switch {
+	case strings.HasSuffix(result, " OK"):
+		return nil
+	case strings.HasSuffix(result, " FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(result, "stream: "), " FOUND")
+		return fmt.Errorf("%w: %s", ErrInfected, signature)
+	default:
+		return fmt.Errorf("unexpected clamd reply: %s", result)
+	}
+}
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go-falcon/internal/attachments/dto"
+	"go-falcon/internal/attachments/models"
+	authModels "go-falcon/internal/auth/models"
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/storage"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxAttachmentsPerObject caps how many files one object can carry
+const maxAttachmentsPerObject = 20
+
+// downloadPath is the path segment signed downloads are served under, so it cannot be an object type
+const downloadPath = "download"
+
+// maxFilenameLength is the longest filename kept; longer names are truncated
+const maxFilenameLength = 200
+
+type Service struct {
+	repository *Repository
+	store      storage.Store
+	scanner    Scanner
+
+	maxSize       int64
+	allowedTypes  map[string]bool
+	urlTTL        time.Duration
+	signingSecret []byte
+	apiPrefix     string
+
+	policiesMu sync.RWMutex
+	policies   map[string]AccessPolicy
+}
+
+func NewService(repository *Repository, store storage.Store) *Service {
+	allowedTypes := make(map[string]bool)
+	for _, contentType := range config.GetAttachmentsAllowedTypes() {
+		allowedTypes[contentType] = true
+	}
+
+	service := &Service{
+		repository:    repository,
+		store:         store,
+		maxSize:       int64(config.GetAttachmentsMaxSizeMB()) << 20,
+		allowedTypes:  allowedTypes,
+		urlTTL:        time.Duration(config.GetAttachmentsURLTTLMinutes()) * time.Minute,
+		signingSecret: []byte(config.GetAttachmentsSigningSecret()),
+		apiPrefix:     config.GetAPIPrefix(),
+		policies:      make(map[string]AccessPolicy),
+	}
+	if address := config.GetAttachmentsClamdAddress(); address != "" {
+		service.scanner = NewClamdScanner(address)
+	}
+	return service
+}
+
+// SetScanner replaces the virus scanner uploads are checked with; nil disables scanning
+func (s *Service) SetScanner(scanner Scanner) {
+	s.scanner = scanner
+}
+
+// MaxSize returns the largest accepted upload in bytes
+func (s *Service) MaxSize() int64 {
+	return s.maxSize
+}
+
+// RegisterObjectType allows attachments on an object type, with access decided by the owning module's policy
+func (s *Service) RegisterObjectType(objectType string, policy AccessPolicy) {
+	if objectType == downloadPath {
+		slog.Error("Attachment object type is reserved", "object_type", objectType)
+		return
+	}
+
+	s.policiesMu.Lock()
+	defer s.policiesMu.Unlock()
+	s.policies[objectType] = policy
+}
+
+// ObjectTypes returns the registered object types
+func (s *Service) ObjectTypes() []string {
+	s.policiesMu.RLock()
+	defer s.policiesMu.RUnlock()
+
+	objectTypes := make([]string, 0, len(s.policies))
+	for objectType := range s.policies {
+		objectTypes = append(objectTypes, objectType)
+	}
+	sort.Strings(objectTypes)
+	return objectTypes
+}
+
+func (s *Service) policy(objectType string) (AccessPolicy, error) {
+	s.policiesMu.RLock()
+	defer s.policiesMu.RUnlock()
+
+	policy, ok := s.policies[objectType]
+	if !ok {
+		return nil, huma.Error404NotFound(fmt.Sprintf("Unknown object type %s", objectType))
+	}
+	return policy, nil
+}
+
+// authorize runs one of the policy's checks and turns a denial into a 403
+func authorize(ctx context.Context, check func(context.Context, int, string) (bool, error), user *authModels.AuthenticatedUser, objectID, action string) error {
+	allowed, err := check(ctx, user.CharacterID, objectID)
+	if err != nil {
+		return huma.Error500InternalServerError("Failed to check attachment permissions", err)
+	}
+	if !allowed {
+		return huma.Error403Forbidden(fmt.Sprintf("Not allowed to %s on this object", action))
+	}
+	return nil
+}
+
+// Upload validates, scans and stores a file and attaches it to an object
+func (s *Service) Upload(ctx context.Context, user *authModels.AuthenticatedUser, objectType, objectID string, file huma.FormFile) (*dto.AttachmentOutput, error) {
+	policy, err := s.policy(objectType)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, policy.CanUpload, user, objectID, "attach files"); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repository.CountAttachments(ctx, objectType, objectID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to count attachments", err)
+	}
+	if count >= maxAttachmentsPerObject {
+		return nil, huma.Error409Conflict(fmt.Sprintf("An object can have at most %d attachments", maxAttachmentsPerObject))
+	}
+
+	if file.Size > s.maxSize {
+		return nil, huma.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Files may be at most %d MB", s.maxSize>>20))
+	}
+	data, err := io.ReadAll(io.LimitReader(file, s.maxSize+1))
+	if err != nil {
+		return nil, huma.Error400BadRequest("Failed to read upload")
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, huma.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Files may be at most %d MB", s.maxSize>>20))
+	}
+	if len(data) == 0 {
+		return nil, huma.Error400BadRequest("File is empty")
+	}
+
+	// The declared content type is not trusted; what is served later is what the contents are
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if !s.allowedTypes[contentType] {
+		return nil, huma.Error415UnsupportedMediaType(fmt.Sprintf("Files of type %s cannot be attached", contentType))
+	}
+
+	scanned := false
+	if s.scanner != nil {
+		if err := s.scanner.Scan(ctx, data); err != nil {
+			if errors.Is(err, ErrInfected) {
+				slog.WarnContext(ctx, "Rejected infected attachment", "object_type", objectType, "object_id", objectID, "character_id", user.CharacterID, "error", err)
+				return nil, huma.Error422UnprocessableEntity("File was rejected by the virus scanner")
+			}
+			return nil, huma.Error503ServiceUnavailable("Virus scanning is unavailable, try again later", err)
+		}
+		scanned = true
+	}
+
+	sum := sha256.Sum256(data)
+	attachment := &models.Attachment{
+		ID:                    primitive.NewObjectID(),
+		ObjectType:            objectType,
+		ObjectID:              objectID,
+		Filename:              sanitizeFilename(file.Filename),
+		ContentType:           contentType,
+		Size:                  int64(len(data)),
+		SHA256:                hex.EncodeToString(sum[:]),
+		Scanned:               scanned,
+		UploadedByUserID:      user.UserID,
+		UploadedByCharacterID: user.CharacterID,
+		UploaderName:          user.CharacterName,
+		CreatedAt:             time.Now(),
+	}
+	attachment.StorageKey = fmt.Sprintf("%s/%s/%s", objectType, objectID, attachment.ID.Hex())
+
+	if err := s.store.Put(ctx, attachment.StorageKey, bytes.NewReader(data), contentType); err != nil {
+		return nil, huma.Error500InternalServerError("Failed to store attachment", err)
+	}
+	if err := s.repository.CreateAttachment(ctx, attachment); err != nil {
+		if deleteErr := s.store.Delete(ctx, attachment.StorageKey); deleteErr != nil {
+			slog.ErrorContext(ctx, "Failed to remove stored file of unsaved attachment", "storage_key", attachment.StorageKey, "error", deleteErr)
+		}
+		return nil, huma.Error500InternalServerError("Failed to save attachment", err)
+	}
+
+	slog.InfoContext(ctx, "Attachment uploaded", "attachment_id", attachment.ID.Hex(), "object_type", objectType, "object_id", objectID, "size", attachment.Size, "content_type", contentType)
+	return &dto.AttachmentOutput{Body: s.toResponse(attachment)}, nil
+}
+
+// ListAttachments returns an object's attachments with fresh download URLs
+func (s *Service) ListAttachments(ctx context.Context, user *authModels.AuthenticatedUser, objectType, objectID string) (*dto.AttachmentListOutput, error) {
+	policy, err := s.policy(objectType)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, policy.CanView, user, objectID, "view attachments"); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repository.ListAttachments(ctx, objectType, objectID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list attachments", err)
+	}
+
+	output := &dto.AttachmentListOutput{}
+	output.Body.ObjectType = objectType
+	output.Body.ObjectID = objectID
+	output.Body.Attachments = make([]dto.AttachmentResponse, len(attachments))
+	for i := range attachments {
+		output.Body.Attachments[i] = s.toResponse(&attachments[i])
+	}
+	return output, nil
+}
+
+// GetAttachment returns an attachment with a fresh download URL
+func (s *Service) GetAttachment(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.AttachmentInput) (*dto.AttachmentOutput, error) {
+	policy, err := s.policy(input.ObjectType)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, policy.CanView, user, input.ObjectID, "view attachments"); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.getAttachment(ctx, input.ObjectType, input.ObjectID, input.AttachmentID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AttachmentOutput{Body: s.toResponse(attachment)}, nil
+}
+
+// DeleteAttachment removes an attachment and its file. Uploaders may delete their own
+// attachments while they can still upload to the object; moderators may delete any.
+func (s *Service) DeleteAttachment(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.AttachmentInput) (*dto.MessageOutput, error) {
+	policy, err := s.policy(input.ObjectType)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.getAttachment(ctx, input.ObjectType, input.ObjectID, input.AttachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	if attachment.UploadedByCharacterID == user.CharacterID {
+		allowed, _ = policy.CanUpload(ctx, user.CharacterID, input.ObjectID)
+	}
+	if !allowed {
+		if err := authorize(ctx, policy.CanModerate, user, input.ObjectID, "delete this attachment"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repository.DeleteAttachment(ctx, attachment.ID); err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete attachment", err)
+	}
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		slog.ErrorContext(ctx, "Failed to remove stored file of deleted attachment", "storage_key", attachment.StorageKey, "error", err)
+	}
+
+	output := &dto.MessageOutput{}
+	output.Body.Message = "Attachment deleted"
+	return output, nil
+}
+
+// Download serves an attachment's contents to the holder of a valid signed URL
+func (s *Service) Download(ctx context.Context, input *dto.DownloadAttachmentInput) (*dto.DownloadOutput, error) {
+	if !s.validSignature(input.AttachmentID, input.Expires, input.Signature) {
+		return nil, huma.Error403Forbidden("Invalid download signature")
+	}
+	remaining := time.Until(time.Unix(input.Expires, 0))
+	if remaining <= 0 {
+		return nil, huma.Error403Forbidden("Download URL has expired")
+	}
+
+	id, err := primitive.ObjectIDFromHex(input.AttachmentID)
+	if err != nil {
+		return nil, huma.Error404NotFound("Attachment not found")
+	}
+	attachment, err := s.repository.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get attachment", err)
+	}
+	if attachment == nil {
+		return nil, huma.Error404NotFound("Attachment not found")
+	}
+
+	reader, err := s.store.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, huma.Error404NotFound("Attachment file is missing")
+		}
+		return nil, huma.Error500InternalServerError("Failed to open attachment", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to read attachment", err)
+	}
+
+	// Images are shown inline; anything else is downloaded rather than rendered by the browser
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	return &dto.DownloadOutput{
+		ContentType:         attachment.ContentType,
+		ContentDisposition:  mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}),
+		CacheControl:        fmt.Sprintf("private, max-age=%d", int(remaining.Seconds())),
+		XContentTypeOptions: "nosniff",
+		Body:                data,
+	}, nil
+}
+
+// GetStatus returns the health status of the attachments module
+func (s *Service) GetStatus(ctx context.Context) *dto.AttachmentsStatusResponse {
+	allowedTypes := make([]string, 0, len(s.allowedTypes))
+	for contentType := range s.allowedTypes {
+		allowedTypes = append(allowedTypes, contentType)
+	}
+	sort.Strings(allowedTypes)
+
+	status := &dto.AttachmentsStatusResponse{
+		Module:       "attachments",
+		Status:       "healthy",
+		ObjectTypes:  s.ObjectTypes(),
+		VirusScan:    s.scanner != nil,
+		MaxSizeBytes: s.maxSize,
+		AllowedTypes: allowedTypes,
+	}
+	if err := s.repository.CheckHealth(ctx); err != nil {
+		status.Status = "unhealthy"
+		status.Message = "Database connection failed: " + err.Error()
+	}
+	return status
+}
+
+func (s *Service) getAttachment(ctx context.Context, objectType, objectID, attachmentID string) (*models.Attachment, error) {
+	id, err := primitive.ObjectIDFromHex(attachmentID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("Invalid attachment ID %s", attachmentID))
+	}
+
+	attachment, err := s.repository.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get attachment", err)
+	}
+	if attachment == nil || attachment.ObjectType != objectType || attachment.ObjectID != objectID {
+		return nil, huma.Error404NotFound(fmt.Sprintf("Attachment %s not found", attachmentID))
+	}
+	return attachment, nil
+}
+
+// toResponse builds the response for an attachment with a freshly signed download URL
+func (s *Service) toResponse(attachment *models.Attachment) dto.AttachmentResponse {
+	expiresAt := time.Now().Add(s.urlTTL).Truncate(time.Second)
+	id := attachment.ID.Hex()
+	url := fmt.Sprintf("%s/attachments/"+downloadPath+"/%s?expires=%d&signature=%s", s.apiPrefix, id, expiresAt.Unix(), s.sign(id, expiresAt.Unix()))
+	return dto.ToAttachmentResponse(attachment, url, expiresAt)
+}
+
+func (s *Service) sign(attachmentID string, expires int64) string {
+	mac := hmac.New(sha256.New, s.signingSecret)
+	mac.Write([]byte(attachmentID + ":" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Service) validSignature(attachmentID string, expires int64, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(s.sign(attachmentID, expires)))
+}
+
+// sanitizeFilename keeps the base name of an uploaded file without control characters
+func sanitizeFilename(filename string) string {
+	filename = filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, filename)
+	filename = strings.TrimSpace(filename)
+
+	if runes := []rune(filename); len(runes) > maxFilenameLength {
+		filename = string(runes[:maxFilenameLength])
+	}
+	if filename == "" || filename == "." || filename == "/" {
+		return "attachment"
+	}
+	return filename
+}
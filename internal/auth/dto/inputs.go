@@ -1,5 +1,12 @@
 package dto
 
+import (
+	"net"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
 // =============================================================================
 // REQUEST DTOs (Legacy)
 // =============================================================================
@@ -26,7 +33,9 @@ type ProfileRefreshRequest struct {
 
 // EVELoginInput represents the input for EVE SSO login initiation (no body needed)
 type EVELoginInput struct {
+	ClientRequest
 	Cookie string `header:"Cookie" doc:"Optional session cookie for authentication"`
+	Scopes string `query:"scopes" doc:"Name of the scope set to request (see /auth/eve/scope-sets); no scopes when empty"`
 }
 
 // EVERegisterInput represents the input for EVE SSO registration initiation (no body needed)
@@ -36,6 +45,7 @@ type EVERegisterInput struct {
 
 // EVECallbackInput represents the input for EVE SSO callback
 type EVECallbackInput struct {
+	ClientRequest
 	Code   string `query:"code" validate:"required" doc:"OAuth2 authorization code from EVE Online"`
 	State  string `query:"state" validate:"required" doc:"CSRF protection state parameter"`
 	Cookie string `header:"Cookie" doc:"Optional session cookie for authentication"`
@@ -43,6 +53,7 @@ type EVECallbackInput struct {
 
 // EVETokenExchangeInput represents the input for mobile token exchange
 type EVETokenExchangeInput struct {
+	ClientRequest
 	Body EVETokenExchangeRequest `json:"body"`
 }
 
@@ -82,13 +93,28 @@ type TokenInput struct {
 	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
 }
 
-// LogoutInput represents the input for logout (no body needed)
+// LogoutInput represents the input for logout; the session of the presented token is revoked
 type LogoutInput struct {
-	// No parameters needed - logout clears cookies
+	ClientRequest
+	Authorization string `header:"Authorization" doc:"Bearer token of the session to end"`
+	Cookie        string `header:"Cookie" doc:"Session and refresh token cookies of the session to end"`
+}
+
+// SessionRefreshRequest carries the refresh token of clients that do not use cookies
+type SessionRefreshRequest struct {
+	RefreshToken string `json:"refresh_token" doc:"Refresh token from the last login or refresh"`
+}
+
+// SessionRefreshInput represents the input for refreshing a session
+type SessionRefreshInput struct {
+	ClientRequest
+	Cookie string                 `header:"Cookie" doc:"Refresh token cookie (falcon_refresh_token)"`
+	Body   *SessionRefreshRequest `json:"body,omitempty"`
 }
 
 // RefreshTokenInput represents the input for token refresh
 type RefreshTokenInput struct {
+	ClientRequest
 	Body RefreshTokenRequest `json:"body"`
 }
 
@@ -96,3 +122,215 @@ type RefreshTokenInput struct {
 type VerifyTokenInput struct {
 	Token string `query:"token" validate:"required" doc:"JWT token to verify"`
 }
+
+// ProviderLoginInput represents the input for starting a staff provider login
+type ProviderLoginInput struct {
+	Provider string `path:"provider" doc:"Identity provider (oidc or discord)"`
+}
+
+// ProviderCallbackInput represents the input for a staff provider OAuth2 callback
+type ProviderCallbackInput struct {
+	ClientRequest
+	Provider string `path:"provider" doc:"Identity provider (oidc or discord)"`
+	Code     string `query:"code" required:"true" doc:"OAuth2 authorization code from the provider"`
+	State    string `query:"state" required:"true" doc:"CSRF protection state parameter"`
+}
+
+// ListStaffAccountsInput represents the input for listing staff accounts
+type ListStaffAccountsInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+}
+
+// StaffAccountRequest represents the editable fields of a staff account
+type StaffAccountRequest struct {
+	Provider    string   `json:"provider" enum:"oidc,discord" doc:"Identity provider the account signs in through"`
+	Email       string   `json:"email" format:"email" doc:"Verified provider email the account is matched by on first login"`
+	Name        string   `json:"name" minLength:"1" maxLength:"100" doc:"Display name"`
+	Permissions []string `json:"permissions" doc:"Permission IDs the account holds (e.g. 'corporation:wallet:read')"`
+	Enabled     *bool    `json:"enabled,omitempty" doc:"Whether the account may sign in (default true)"`
+}
+
+// CreateStaffAccountInput represents the input for provisioning a staff account
+type CreateStaffAccountInput struct {
+	Authorization string              `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string              `header:"Cookie" doc:"Session cookie for authentication"`
+	Body          StaffAccountRequest `json:"body"`
+}
+
+// UpdateStaffAccountInput represents the input for updating a staff account
+type UpdateStaffAccountInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+	AccountID     string `path:"account_id" doc:"Staff account ID"`
+	Body          struct {
+		Name        *string  `json:"name,omitempty" minLength:"1" maxLength:"100" doc:"Display name"`
+		Permissions []string `json:"permissions,omitempty" doc:"Replacement list of permission IDs"`
+		Enabled     *bool    `json:"enabled,omitempty" doc:"Whether the account may sign in"`
+	} `json:"body"`
+}
+
+// DeleteStaffAccountInput represents the input for deleting a staff account
+type DeleteStaffAccountInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+	AccountID     string `path:"account_id" doc:"Staff account ID"`
+}
+
+// EVELinkInput represents the input for starting a character link flow
+type EVELinkInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+}
+
+// AuthAuditInput represents the input for querying the authentication audit log
+type AuthAuditInput struct {
+	UserID      string    `query:"user_id" doc:"Only events of this user"`
+	CharacterID int       `query:"character_id" doc:"Only events of this character"`
+	Event       string    `query:"event" enum:"login,logout,token_refresh,scope_grant,impersonation,sudo" doc:"Only events of this kind; failed callbacks are failed logins"`
+	Outcome     string    `query:"outcome" enum:"success,failure" doc:"Only events with this outcome"`
+	IPAddress   string    `query:"ip_address" doc:"Only events from this IP address"`
+	From        time.Time `query:"from" doc:"Only events at or after this time (RFC 3339)"`
+	To          time.Time `query:"to" doc:"Only events before this time (RFC 3339)"`
+	Page        int       `query:"page" minimum:"1" default:"1" doc:"Page number"`
+	Limit       int       `query:"limit" minimum:"1" maximum:"500" default:"100" doc:"Items per page"`
+}
+
+// DiscordLinkInput represents the input of the Discord account link endpoints
+type DiscordLinkInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+}
+
+// DiscordCallbackInput represents the input for the Discord account link callback
+type DiscordCallbackInput struct {
+	Code  string `query:"code" doc:"OAuth2 authorization code from Discord"`
+	State string `query:"state" validate:"required" doc:"CSRF protection state parameter"`
+	Error string `query:"error" doc:"Set by Discord when the user declined the authorization"`
+}
+
+// LinkedCharactersInput represents the input for listing the characters of the current account
+type LinkedCharactersInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+}
+
+// ClientRequest captures the client of requests that start or refresh a session
+type ClientRequest struct {
+	UserAgent string `header:"User-Agent" doc:"Client user agent, shown in the session list"`
+	IPAddress string `json:"-"`
+}
+
+// Resolve records the client IP address (RealIP has already applied X-Forwarded-For)
+func (c *ClientRequest) Resolve(ctx huma.Context) []error {
+	c.IPAddress = ctx.RemoteAddr()
+	if host, _, err := net.SplitHostPort(c.IPAddress); err == nil {
+		c.IPAddress = host
+	}
+	return nil
+}
+
+// LinkedCharacterInput represents the input for unlinking or activating one of the account's characters
+type LinkedCharacterInput struct {
+	ClientRequest
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+	CharacterID   int    `path:"character_id" minimum:"90000000" maximum:"2147483647" doc:"EVE Online character ID"`
+}
+
+// SessionsInput represents the input for listing or revoking the sessions of the current account
+type SessionsInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+}
+
+// SessionInput represents the input for revoking one session of the current account
+type SessionInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+	SessionID     string `path:"session_id" doc:"Session ID"`
+}
+
+// ImpersonateRequest names the user to act as and why
+type ImpersonateRequest struct {
+	CharacterID int    `json:"character_id" minimum:"90000000" maximum:"2147483647" doc:"A character of the user to act as"`
+	Reason      string `json:"reason" minLength:"3" maxLength:"500" doc:"Why the impersonation is needed; recorded with it"`
+}
+
+// ImpersonateInput represents the input for starting an impersonation
+type ImpersonateInput struct {
+	ClientRequest
+	Authorization string             `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string             `header:"Cookie" doc:"Session cookie for authentication"`
+	Body          ImpersonateRequest `json:"body"`
+}
+
+// ImpersonationsInput represents the input for listing impersonations
+type ImpersonationsInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+	Limit         int    `query:"limit" default:"50" minimum:"1" maximum:"500" doc:"Maximum number of impersonations to return"`
+}
+
+// DeviceCodeRequest describes the tool starting a device flow
+type DeviceCodeRequest struct {
+	ClientName string `json:"client_name,omitempty" maxLength:"64" doc:"Name of the tool (e.g. falconctl), shown to the user approving it"`
+}
+
+// DeviceCodeInput represents the input for starting a device flow
+type DeviceCodeInput struct {
+	ClientRequest
+	Body *DeviceCodeRequest `json:"body,omitempty"`
+}
+
+// DeviceTokenRequest carries the device code a tool polls with
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"device_code" minLength:"1" doc:"Device code from POST /auth/device/code"`
+}
+
+// DeviceTokenInput represents the input for polling a device flow
+type DeviceTokenInput struct {
+	ClientRequest
+	Body DeviceTokenRequest `json:"body"`
+}
+
+// DeviceAuthorizationInput represents the input for looking up a device flow by its user code
+type DeviceAuthorizationInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Session cookie for authentication"`
+	UserCode      string `query:"user_code" required:"true" doc:"User code shown by the tool; case and dashes are ignored"`
+}
+
+// DeviceDecisionRequest names the device flow the user approves or denies
+type DeviceDecisionRequest struct {
+	UserCode string `json:"user_code" minLength:"1" doc:"User code shown by the tool; case and dashes are ignored"`
+}
+
+// DeviceDecisionInput represents the input for approving or denying a device flow
+type DeviceDecisionInput struct {
+	Authorization string                `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string                `header:"Cookie" doc:"Session cookie for authentication"`
+	Body          DeviceDecisionRequest `json:"body"`
+}
+
+// TOTPCodeRequest carries a code of the caller's authenticator app
+type TOTPCodeRequest struct {
+	Code string `json:"code" pattern:"^[0-9]{6}$" doc:"Current 6-digit code of the authenticator app"`
+}
+
+// TOTPStatusInput represents the input for the authenticator enrollment status (caller from the token)
+type TOTPStatusInput struct{}
+
+// TOTPEnrollInput represents the input for starting an authenticator enrollment
+type TOTPEnrollInput struct{}
+
+// TOTPCodeInput represents the input for confirming or removing an authenticator
+type TOTPCodeInput struct {
+	Body TOTPCodeRequest `json:"body"`
+}
+
+// SudoInput represents the input for elevating the caller's session with a second factor
+type SudoInput struct {
+	ClientRequest
+	Body TOTPCodeRequest `json:"body"`
+}
@@ -8,12 +8,13 @@ import "time"
 
 // AuthStatusResponse represents authentication status
 type AuthStatusResponse struct {
-	Authenticated bool     `json:"authenticated"`
-	UserID        *string  `json:"user_id"`
-	CharacterID   *int     `json:"character_id"`
-	CharacterName *string  `json:"character_name"`
-	Characters    []string `json:"characters"`
-	Permissions   []string `json:"permissions"`
+	Authenticated bool              `json:"authenticated"`
+	UserID        *string           `json:"user_id"`
+	CharacterID   *int              `json:"character_id"`
+	CharacterName *string           `json:"character_name"`
+	Characters    []string          `json:"characters"`
+	Permissions   []string          `json:"permissions"`
+	Impersonator  *ImpersonatorInfo `json:"impersonator,omitempty" doc:"Set while an administrator acts as this user"`
 }
 
 // EVELoginResponse represents EVE SSO login initiation response
@@ -29,6 +30,16 @@ type UserInfoResponse struct {
 	CharacterName string `json:"character_name"`
 	Scopes        string `json:"scopes"`
 	ExpiresAt     string `json:"expires_at,omitempty"`
+
+	// Impersonator is set while an administrator acts as this user, so clients can show a banner
+	Impersonator *ImpersonatorInfo `json:"impersonator,omitempty"`
+}
+
+// ImpersonatorInfo identifies the administrator behind an impersonation token
+type ImpersonatorInfo struct {
+	UserID        string `json:"user_id"`
+	CharacterID   int    `json:"character_id"`
+	CharacterName string `json:"character_name"`
 }
 
 // ProfileResponse represents a user profile
@@ -65,6 +76,14 @@ type PublicProfileResponse struct {
 type TokenResponse struct {
 	Token     string    `json:"token"`
 	ExpiresAt time.Time `json:"expires_at"`
+
+	// RefreshToken is set when a new session starts or is refreshed; it can be used once, with
+	// POST /auth/session/refresh
+	RefreshToken     string     `json:"refresh_token,omitempty"`
+	RefreshExpiresAt *time.Time `json:"refresh_expires_at,omitempty"`
+
+	// SessionID is the session the token belongs to, for the audit log
+	SessionID string `json:"-"`
 }
 
 // RefreshTokenResponse represents a successful token refresh
@@ -89,6 +108,65 @@ type VerifyResponse struct {
 	ExpiresAt     time.Time `json:"expires_at,omitempty"`
 }
 
+// LinkedCharacter is one EVE character of a user account
+type LinkedCharacter struct {
+	CharacterID     int       `json:"character_id"`
+	CharacterName   string    `json:"character_name"`
+	CorporationID   int       `json:"corporation_id,omitempty"`
+	CorporationName string    `json:"corporation_name,omitempty"`
+	AllianceID      int       `json:"alliance_id,omitempty"`
+	AllianceName    string    `json:"alliance_name,omitempty"`
+	Position        int       `json:"position"`
+	Valid           bool      `json:"valid" doc:"Whether the character's EVE token still works"`
+	Active          bool      `json:"active" doc:"Whether the session acts as this character"`
+	LastLogin       time.Time `json:"last_login"`
+}
+
+// LinkedCharactersResponse lists the characters of a user account
+type LinkedCharactersResponse struct {
+	UserID            string            `json:"user_id"`
+	ActiveCharacterID int               `json:"active_character_id"`
+	Characters        []LinkedCharacter `json:"characters"`
+}
+
+// DiscordLinkResponse describes the Discord account linked to a user account
+type DiscordLinkResponse struct {
+	Linked        bool       `json:"linked"`
+	DiscordUserID string     `json:"discord_user_id,omitempty"`
+	Username      string     `json:"username,omitempty"`
+	LinkedAt      *time.Time `json:"linked_at,omitempty"`
+}
+
+// Session is a signed-in device of a user account
+type Session struct {
+	SessionID     string    `json:"session_id"`
+	CharacterID   int       `json:"character_id"`
+	CharacterName string    `json:"character_name"`
+	UserAgent     string    `json:"user_agent" doc:"User agent of the last login or refresh"`
+	IPAddress     string    `json:"ip_address" doc:"IP address of the last login or refresh"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+	ExpiresAt     time.Time `json:"expires_at" doc:"When the session ends unless it is refreshed"`
+	Current       bool      `json:"current" doc:"Whether this is the session of the request"`
+}
+
+// SessionsResponse lists the active sessions of a user account, most recently seen first
+type SessionsResponse struct {
+	CurrentSessionID string    `json:"current_session_id,omitempty"`
+	Sessions         []Session `json:"sessions"`
+}
+
+// ScopeSet is a named list of EVE SSO scopes that a login can request
+type ScopeSet struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// ScopeSetsResponse lists the available scope sets
+type ScopeSetsResponse struct {
+	Sets []ScopeSet `json:"sets"`
+}
+
 // =============================================================================
 // HUMA OUTPUT DTOs
 // =============================================================================
@@ -106,7 +184,7 @@ type EVERegisterOutput struct {
 // EVECallbackOutput represents the output for EVE SSO callback
 type EVECallbackOutput struct {
 	Status    int                    `json:"-" status:"302" doc:"HTTP status code for redirect"`
-	SetCookie string                 `header:"Set-Cookie" doc:"Authentication cookie"`
+	SetCookie []string               `header:"Set-Cookie" doc:"Authentication and refresh token cookies"`
 	Location  string                 `header:"Location" doc:"Redirect location"`
 	Body      map[string]interface{} `json:"body,omitempty"`
 }
@@ -148,7 +226,7 @@ type TokenOutput struct {
 
 // LogoutOutput represents the output for logout
 type LogoutOutput struct {
-	SetCookie string         `header:"Set-Cookie" doc:"Clear authentication cookie"`
+	SetCookie []string       `header:"Set-Cookie" doc:"Clear authentication and refresh token cookies"`
 	Body      LogoutResponse `json:"body"`
 }
 
@@ -196,3 +274,261 @@ type AuthMetrics struct {
 	AverageLoginTime string  `json:"avg_login_time" description:"Average login processing time"`
 	MemoryUsage      float64 `json:"memory_usage_mb" description:"Memory usage in MB"`
 }
+
+// ProviderResponse represents a configured staff identity provider
+type ProviderResponse struct {
+	Name        string `json:"name" doc:"Provider key used in login URLs"`
+	DisplayName string `json:"display_name" doc:"Label for the login button"`
+}
+
+// ListProvidersOutput represents the output for listing staff identity providers
+type ListProvidersOutput struct {
+	Body struct {
+		Providers []ProviderResponse `json:"providers" doc:"Configured providers; EVE SSO is always available under /auth/eve"`
+	} `json:"body"`
+}
+
+// ProviderLoginOutput represents the output for starting a staff provider login
+type ProviderLoginOutput struct {
+	Body EVELoginResponse `json:"body"`
+}
+
+// StaffAccountResponse represents a staff account
+type StaffAccountResponse struct {
+	ID          string     `json:"id" doc:"Staff account ID"`
+	UserID      string     `json:"user_id" doc:"User ID carried by the account's tokens"`
+	Provider    string     `json:"provider" doc:"Identity provider"`
+	Email       string     `json:"email" doc:"Provider email"`
+	Bound       bool       `json:"bound" doc:"Whether the account is bound to a provider user by a first login"`
+	Name        string     `json:"name" doc:"Display name"`
+	Permissions []string   `json:"permissions" doc:"Permission IDs the account holds"`
+	Enabled     bool       `json:"enabled" doc:"Whether the account may sign in"`
+	LastLogin   *time.Time `json:"last_login,omitempty" doc:"Last successful login"`
+	CreatedBy   int        `json:"created_by" doc:"Character ID of the admin who provisioned the account"`
+	CreatedAt   time.Time  `json:"created_at" doc:"Creation time"`
+	UpdatedAt   time.Time  `json:"updated_at" doc:"Last update time"`
+}
+
+// StaffAccountOutput represents the output for a single staff account
+type StaffAccountOutput struct {
+	Body StaffAccountResponse `json:"body"`
+}
+
+// ListStaffAccountsOutput represents the output for listing staff accounts
+type ListStaffAccountsOutput struct {
+	Body struct {
+		Accounts []StaffAccountResponse `json:"accounts" doc:"Staff accounts ordered by name"`
+	} `json:"body"`
+}
+
+// DeleteStaffAccountOutput represents the output for deleting a staff account
+type DeleteStaffAccountOutput struct {
+	Body struct {
+		Message string `json:"message" doc:"Result message"`
+	} `json:"body"`
+}
+
+// EVELinkOutput represents the output for starting a character link flow
+type EVELinkOutput struct {
+	Body EVELoginResponse `json:"body"`
+}
+
+// DiscordLoginOutput represents the output for starting a Discord account link flow
+type DiscordLoginOutput struct {
+	Body EVELoginResponse `json:"body"`
+}
+
+// DiscordCallbackOutput redirects the browser back to the frontend after a Discord link flow
+type DiscordCallbackOutput struct {
+	Status   int    `json:"-" status:"302" doc:"HTTP status code for redirect"`
+	Location string `header:"Location" doc:"Redirect location"`
+}
+
+// DiscordLinkOutput represents the output for reading or removing the linked Discord account
+type DiscordLinkOutput struct {
+	Body DiscordLinkResponse `json:"body"`
+}
+
+// LinkedCharactersOutput represents the output for listing or unlinking characters
+type LinkedCharactersOutput struct {
+	Body LinkedCharactersResponse `json:"body"`
+}
+
+// ActivateCharacterOutput represents the output for switching the active character
+type ActivateCharacterOutput struct {
+	SetCookie []string      `header:"Set-Cookie" doc:"Authentication cookie for the new active character, and a refresh token cookie when a session starts"`
+	Body      TokenResponse `json:"body"`
+}
+
+// SessionRefreshOutput represents the output for refreshing a session
+type SessionRefreshOutput struct {
+	SetCookie []string      `header:"Set-Cookie" doc:"Authentication and refresh token cookies of the rotated session"`
+	Body      TokenResponse `json:"body"`
+}
+
+// SessionsOutput represents the output for listing or revoking sessions
+type SessionsOutput struct {
+	Body SessionsResponse `json:"body"`
+}
+
+// ImpersonationResponse is an impersonation token and the user it acts as
+type ImpersonationResponse struct {
+	ImpersonationID     string    `json:"impersonation_id"`
+	Token               string    `json:"token" doc:"Bearer token acting as the target user; it cannot be refreshed"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	TargetUserID        string    `json:"target_user_id"`
+	TargetCharacterID   int       `json:"target_character_id"`
+	TargetCharacterName string    `json:"target_character_name"`
+}
+
+// ImpersonationRecord is one recorded impersonation
+type ImpersonationRecord struct {
+	ID                  string           `json:"id"`
+	Impersonator        ImpersonatorInfo `json:"impersonator"`
+	TargetUserID        string           `json:"target_user_id"`
+	TargetCharacterID   int              `json:"target_character_id"`
+	TargetCharacterName string           `json:"target_character_name"`
+	Reason              string           `json:"reason"`
+	IPAddress           string           `json:"ip_address"`
+	CreatedAt           time.Time        `json:"created_at"`
+	ExpiresAt           time.Time        `json:"expires_at"`
+}
+
+// ImpersonationOutput represents the output for starting an impersonation
+type ImpersonationOutput struct {
+	Body ImpersonationResponse `json:"body"`
+}
+
+// ImpersonationsOutput represents the output for listing impersonations
+type ImpersonationsOutput struct {
+	Body struct {
+		Impersonations []ImpersonationRecord `json:"impersonations" doc:"Most recent impersonations, newest first"`
+	} `json:"body"`
+}
+
+// AuthAuditEvent is one entry of the authentication audit log
+type AuthAuditEvent struct {
+	ID            string    `json:"id"`
+	Event         string    `json:"event" doc:"login, logout, token_refresh, scope_grant or impersonation"`
+	Outcome       string    `json:"outcome" doc:"success or failure"`
+	Method        string    `json:"method,omitempty" doc:"How the caller logged in: eve_sso, eve_token or a staff provider"`
+	UserID        string    `json:"user_id,omitempty"`
+	CharacterID   int       `json:"character_id,omitempty"`
+	CharacterName string    `json:"character_name,omitempty"`
+	SessionID     string    `json:"session_id,omitempty"`
+	Scopes        string    `json:"scopes,omitempty" doc:"Scopes granted by a scope_grant"`
+	Detail        string    `json:"detail,omitempty" doc:"Failure reason or event details"`
+	IPAddress     string    `json:"ip_address,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// AuthAuditResponse represents a page of the authentication audit log
+type AuthAuditResponse struct {
+	Events []AuthAuditEvent `json:"events"`
+	Total  int64            `json:"total" doc:"Events matching the filter"`
+	Page   int              `json:"page"`
+	Limit  int              `json:"limit"`
+}
+
+// AuthAuditOutput represents the output for querying the authentication audit log
+type AuthAuditOutput struct {
+	Body AuthAuditResponse `json:"body"`
+}
+
+// ScopeSetsOutput represents the output for listing scope sets
+type ScopeSetsOutput struct {
+	Body ScopeSetsResponse `json:"body"`
+}
+
+// JSONWebKey is the public half of a JWT signing key (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+// JSONWebKeySet lists the keys that verify falcon JWTs, served at /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// DeviceCodeResponse starts a device flow (RFC 8628): the tool shows the user code and
+// verification URI, then polls POST /auth/device/token with the device code
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code" doc:"Secret the tool polls with; never shown to the user"`
+	UserCode                string `json:"user_code" doc:"Code the user enters in the browser"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete" doc:"Verification URI with the user code filled in"`
+	ExpiresIn               int    `json:"expires_in" doc:"Seconds until the codes expire"`
+	Interval                int    `json:"interval" doc:"Minimum seconds between polls"`
+}
+
+// DeviceCodeOutput represents the output for starting a device flow
+type DeviceCodeOutput struct {
+	Body DeviceCodeResponse `json:"body"`
+}
+
+// DeviceAuthorizationResponse describes a device flow awaiting the user's decision
+type DeviceAuthorizationResponse struct {
+	UserCode   string    `json:"user_code"`
+	ClientName string    `json:"client_name,omitempty"`
+	UserAgent  string    `json:"user_agent" doc:"User agent of the tool that requested the code"`
+	IPAddress  string    `json:"ip_address" doc:"IP address of the tool that requested the code"`
+	Status     string    `json:"status" enum:"pending,approved,denied"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// DeviceAuthorizationOutput represents the output for looking up, approving or denying a device flow
+type DeviceAuthorizationOutput struct {
+	Body DeviceAuthorizationResponse `json:"body"`
+}
+
+// DeviceTokenOutput represents the output of an approved device flow
+type DeviceTokenOutput struct {
+	Body TokenResponse `json:"body"`
+}
+
+// TOTPEnrollmentResponse carries a new authenticator secret; it is shown once
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret" doc:"Base32 secret for manual entry in the authenticator app"`
+	OTPAuthURI string `json:"otpauth_uri" doc:"otpauth:// URI to render as a QR code"`
+	Digits     int    `json:"digits" doc:"Digits per code"`
+	Period     int    `json:"period" doc:"Seconds per code"`
+}
+
+// TOTPEnrollOutput represents the output for starting an authenticator enrollment
+type TOTPEnrollOutput struct {
+	Body TOTPEnrollmentResponse `json:"body"`
+}
+
+// TOTPStatusResponse reports the authenticator enrollment of the caller
+type TOTPStatusResponse struct {
+	Enrolled    bool       `json:"enrolled" doc:"Whether a confirmed authenticator protects the account"`
+	Pending     bool       `json:"pending" doc:"Whether an enrollment waits for its first code"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+}
+
+// TOTPStatusOutput represents the output for the authenticator enrollment status
+type TOTPStatusOutput struct {
+	Body TOTPStatusResponse `json:"body"`
+}
+
+// SudoResponse carries a token of the caller's session elevated by a second factor
+type SudoResponse struct {
+	Token     string    `json:"token" doc:"JWT of the same session with the sudo claim"`
+	ExpiresAt time.Time `json:"expires_at" doc:"When the token expires"`
+	SudoUntil time.Time `json:"sudo_until" doc:"When the elevation ends; the token stays valid without it afterwards"`
+}
+
+// SudoOutput represents the output for elevating a session
+type SudoOutput struct {
+	SetCookie []string     `header:"Set-Cookie" doc:"Authentication cookie with the elevated token"`
+	Body      SudoResponse `json:"body"`
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // UserProfile represents a user profile in the database
 type UserProfile struct {
@@ -23,16 +27,60 @@ type UserProfile struct {
 	Valid              bool              `bson:"valid" json:"valid"`
 	Position           int               `bson:"position" json:"position"` // User position/rank for character ordering
 	Metadata           map[string]string `bson:"metadata" json:"metadata,omitempty"`
+	Discord            *DiscordLink      `bson:"discord,omitempty" json:"discord,omitempty"` // Linked Discord account, stored on every character of the user
+	DeletedAt          *time.Time        `bson:"deleted_at,omitempty" json:"-"`              // Set while the character is soft-deleted by the users module
 	CreatedAt          time.Time         `bson:"created_at" json:"created_at"`
 	UpdatedAt          time.Time         `bson:"updated_at" json:"updated_at"`
 }
 
+// DiscordLink is a Discord account linked to a falcon user
+type DiscordLink struct {
+	UserID   string    `bson:"user_id" json:"user_id"`
+	Username string    `bson:"username" json:"username"`
+	LinkedAt time.Time `bson:"linked_at" json:"linked_at"`
+}
+
 // AuthenticatedUser represents an authenticated user in context
 type AuthenticatedUser struct {
 	UserID        string `json:"user_id"`
 	CharacterID   int    `json:"character_id"`
 	CharacterName string `json:"character_name"`
 	Scopes        string `json:"scopes"`
+
+	// Provider is the external identity provider of a staff account; empty for EVE characters
+	Provider string `json:"provider,omitempty"`
+
+	// IssuedAt is when the token was issued, which refreshing moves forward
+	IssuedAt time.Time `json:"-"`
+
+	// AuthTime is when the user last signed in through SSO; refreshing keeps it. Zero for tokens
+	// that were not issued by a sign-in, such as impersonation tokens.
+	AuthTime time.Time `json:"-"`
+
+	// SessionID is the refresh token chain the token belongs to; empty for tokens issued without one
+	SessionID string `json:"-"`
+
+	// TokenGeneration is the account's token generation the token was issued in; tokens of an older
+	// generation were signed out by logging out everywhere
+	TokenGeneration int64 `json:"-"`
+
+	// Impersonator is the administrator acting as this user; nil for the user's own tokens
+	Impersonator *Impersonator `json:"impersonator,omitempty"`
+
+	// SudoUntil is when the second-factor elevation of the token ends; zero for tokens without one
+	SudoUntil time.Time `json:"-"`
+}
+
+// Impersonator is the administrator behind an impersonation token
+type Impersonator struct {
+	UserID        string `bson:"user_id" json:"user_id"`
+	CharacterID   int    `bson:"character_id" json:"character_id"`
+	CharacterName string `bson:"character_name" json:"character_name"`
+}
+
+// IsStaff reports whether the user is a staff account without EVE characters
+func (u *AuthenticatedUser) IsStaff() bool {
+	return u.Provider != ""
 }
 
 // EVETokenResponse represents the response from EVE's OAuth token endpoint
@@ -74,6 +122,152 @@ type EVELoginState struct {
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
 	UserID    string    `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Provider  string    `bson:"provider,omitempty" json:"provider,omitempty"` // Staff identity provider; empty for EVE SSO
+
+	// Purpose is LoginPurposeLink when the flow adds a character to UserID's account, and
+	// LoginPurposeDiscordLink when it links a Discord account to it
+	Purpose string `bson:"purpose,omitempty" json:"purpose,omitempty"`
+	// CharacterID is the character that started a link flow; it stays the active character
+	CharacterID int `bson:"character_id,omitempty" json:"character_id,omitempty"`
+	// CodeVerifier is the PKCE secret of an EVE SSO flow, sent with the authorization code
+	CodeVerifier string `bson:"code_verifier,omitempty" json:"code_verifier,omitempty"`
+}
+
+// Login state purposes
+const (
+	// LoginPurposeLink marks an EVE SSO flow that links another character to a signed-in account
+	LoginPurposeLink = "link"
+	// LoginPurposeDiscordLink marks a Discord OAuth2 flow that links a Discord account to UserID
+	LoginPurposeDiscordLink = "discord_link"
+)
+
+// Session is a signed-in device: the refresh token chain of one login and what it was last seen from
+type Session struct {
+	SessionID     string     `bson:"session_id" json:"session_id"`
+	UserID        string     `bson:"user_id" json:"user_id"`
+	CharacterID   int        `bson:"character_id" json:"character_id"`
+	CharacterName string     `bson:"character_name" json:"character_name"`
+	UserAgent     string     `bson:"user_agent" json:"user_agent"`
+	IPAddress     string     `bson:"ip_address" json:"ip_address"`
+	CreatedAt     time.Time  `bson:"created_at" json:"created_at"`
+	LastSeenAt    time.Time  `bson:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt     time.Time  `bson:"expires_at" json:"expires_at"` // Expiry of the session's current refresh token
+	RevokedAt     *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+// SessionClient describes the client a session is used from
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Impersonation records an administrator obtaining a token that acts as another user
+type Impersonation struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Impersonator        Impersonator       `bson:"impersonator" json:"impersonator"`
+	TargetUserID        string             `bson:"target_user_id" json:"target_user_id"`
+	TargetCharacterID   int                `bson:"target_character_id" json:"target_character_id"`
+	TargetCharacterName string             `bson:"target_character_name" json:"target_character_name"`
+	Reason              string             `bson:"reason" json:"reason"`
+	IPAddress           string             `bson:"ip_address" json:"ip_address"`
+	SessionID           string             `bson:"session_id" json:"session_id"`
+	CreatedAt           time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt           time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// Device authorization states
+const (
+	DeviceAuthorizationPending  = "pending"
+	DeviceAuthorizationApproved = "approved"
+	DeviceAuthorizationDenied   = "denied"
+)
+
+// DeviceAuthorization is a device flow sign-in (RFC 8628): a headless client polls with its
+// device code while the user approves its user code in a signed-in browser
+type DeviceAuthorization struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	DeviceCodeHash string             `bson:"device_code_hash"`
+	UserCode       string             `bson:"user_code"` // Normalized: upper case, without the dash
+	ClientName     string             `bson:"client_name,omitempty"`
+	UserAgent      string             `bson:"user_agent"` // Client that requested the code
+	IPAddress      string             `bson:"ip_address"`
+	Status         string             `bson:"status"`
+	UserID         string             `bson:"user_id,omitempty"` // Set once approved or denied
+	CharacterID    int                `bson:"character_id,omitempty"`
+	CharacterName  string             `bson:"character_name,omitempty"`
+	Scopes         string             `bson:"scopes,omitempty"`
+	LastPolledAt   *time.Time         `bson:"last_polled_at,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at"`
+	ExpiresAt      time.Time          `bson:"expires_at"`
+}
+
+// Auth audit events. A failed SSO or provider callback is a failed login.
+const (
+	AuditEventLogin         = "login"
+	AuditEventLogout        = "logout"
+	AuditEventTokenRefresh  = "token_refresh"
+	AuditEventScopeGrant    = "scope_grant"
+	AuditEventImpersonation = "impersonation"
+	AuditEventSudo          = "sudo"
+)
+
+// Auth audit outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// Auth audit login methods; staff provider logins use the provider name
+const (
+	AuditMethodEVESSO     = "eve_sso"
+	AuditMethodEVEToken   = "eve_token"
+	AuditMethodDeviceCode = "device_code"
+)
+
+// AuthAuditEvent is one entry of the authentication audit log (auth_audit collection). Failed
+// events carry what is known about the caller, which may be nothing but the client.
+type AuthAuditEvent struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Event         string             `bson:"event" json:"event"`
+	Outcome       string             `bson:"outcome" json:"outcome"`
+	Method        string             `bson:"method,omitempty" json:"method,omitempty"`
+	UserID        string             `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	CharacterID   int                `bson:"character_id,omitempty" json:"character_id,omitempty"`
+	CharacterName string             `bson:"character_name,omitempty" json:"character_name,omitempty"`
+	SessionID     string             `bson:"session_id,omitempty" json:"session_id,omitempty"`
+	Scopes        string             `bson:"scopes,omitempty" json:"scopes,omitempty"` // Scopes granted by a scope_grant
+	Detail        string             `bson:"detail,omitempty" json:"detail,omitempty"` // Failure reason or event details
+	IPAddress     string             `bson:"ip_address,omitempty" json:"ip_address,omitempty"`
+	UserAgent     string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	Timestamp     time.Time          `bson:"timestamp" json:"timestamp"`
+}
+
+// AuthAuditFilter selects auth audit events; zero fields match everything
+type AuthAuditFilter struct {
+	UserID      string
+	CharacterID int
+	Event       string
+	Outcome     string
+	IPAddress   string
+	From        time.Time
+	To          time.Time
+}
+
+// RefreshToken is one link of a session's refresh token chain. Only the SHA-256 of the token is
+// stored. A refresh marks the token rotated and issues the next one in the same session.
+type RefreshToken struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	TokenHash     string             `bson:"token_hash" json:"-"`
+	SessionID     string             `bson:"session_id" json:"session_id"`
+	UserID        string             `bson:"user_id" json:"user_id"`
+	CharacterID   int                `bson:"character_id" json:"character_id"`
+	CharacterName string             `bson:"character_name" json:"character_name"`
+	Scopes        string             `bson:"scopes" json:"scopes"`
+	AuthTime      time.Time          `bson:"auth_time,omitempty" json:"auth_time"` // SSO sign-in that started the session
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt     time.Time          `bson:"expires_at" json:"expires_at"`
+	RotatedAt     *time.Time         `bson:"rotated_at,omitempty" json:"rotated_at,omitempty"`
+	RevokedAt     *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
 }
 
 // ESICharacterInfo represents character information from ESI
@@ -131,3 +325,70 @@ type BatchRefreshStats struct {
 	Results        []TokenRefreshResult `json:"results,omitempty"`
 	ProcessedAt    time.Time            `json:"processed_at"`
 }
+
+// Staff identity providers
+const (
+	ProviderOIDC    = "oidc"
+	ProviderDiscord = "discord"
+)
+
+// StaffUserIDPrefix marks the user IDs of staff accounts so they never collide with EVE users
+const StaffUserIDPrefix = "staff-"
+
+// ExternalIdentity is the identity an external provider vouched for at login
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// StaffAccount is a non-EVE account (accountant, developer) that signs in through an external
+// identity provider. It has no characters and only holds the permissions listed on it.
+type StaffAccount struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Provider    string             `bson:"provider" json:"provider"`
+	Email       string             `bson:"email" json:"email"`
+	Subject     string             `bson:"subject,omitempty" json:"subject,omitempty"` // Provider user ID, bound on first login
+	Name        string             `bson:"name" json:"name"`
+	Permissions []string           `bson:"permissions" json:"permissions"`
+	Enabled     bool               `bson:"enabled" json:"enabled"`
+	LastLogin   *time.Time         `bson:"last_login,omitempty" json:"last_login,omitempty"`
+	CreatedBy   int                `bson:"created_by" json:"created_by"` // Character ID of the admin
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// UserID returns the user ID carried by the account's tokens
+func (a *StaffAccount) UserID() string {
+	return StaffUserIDPrefix + a.ID.Hex()
+}
+
+// SigningKey is an asymmetric key that signs falcon JWTs. Keys are published in the JWKS before
+// they activate and stay there after they are superseded until every token they signed expired.
+type SigningKey struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	KeyID       string             `bson:"kid"`
+	Generation  int                `bson:"generation"` // Unique; concurrent rotations race on it
+	Algorithm   string             `bson:"algorithm"`
+	PrivateKey  string             `bson:"private_key"` // PKCS#8, sealed with a key derived from JWT_SECRET
+	PublicKey   string             `bson:"public_key"`  // PKIX PEM
+	CreatedAt   time.Time          `bson:"created_at"`
+	ActivatesAt time.Time          `bson:"activates_at"`
+	ExpiresAt   *time.Time         `bson:"expires_at,omitempty"` // Set once superseded; removed by a TTL index
+}
+
+// TOTPEnrollment is the authenticator app of a user account (auth_totp collection). The secret is
+// sealed with a key derived from JWT_SECRET. An enrollment only elevates sessions once a first code
+// confirmed it; LastUsedStep keeps every code from being used twice.
+type TOTPEnrollment struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID       string             `bson:"user_id" json:"user_id"`
+	Secret       string             `bson:"secret" json:"-"`
+	Confirmed    bool               `bson:"confirmed" json:"confirmed"`
+	LastUsedStep int64              `bson:"last_used_step" json:"-"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	ConfirmedAt  *time.Time         `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+	LastUsedAt   *time.Time         `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+}
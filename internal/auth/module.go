@@ -2,7 +2,10 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"go-falcon/internal/auth/middleware"
@@ -10,7 +13,9 @@ import (
 	"go-falcon/internal/auth/services"
 	"go-falcon/pkg/database"
 	"go-falcon/pkg/evegateway"
+	humaMiddleware "go-falcon/pkg/middleware"
 	"go-falcon/pkg/module"
+	"go-falcon/pkg/permissions"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi/v5"
@@ -22,6 +27,9 @@ type Module struct {
 	authService *services.AuthService
 	middleware  *middleware.Middleware
 	routes      *routes.Routes
+
+	// permissionMiddleware guards the staff account endpoints; set once the permission system exists
+	permissionMiddleware *humaMiddleware.PermissionMiddleware
 }
 
 // New creates a new auth module with standardized structure
@@ -29,7 +37,7 @@ func New(mongodb *database.MongoDB, redis *database.Redis, esiClient *evegateway
 	baseModule := module.NewBaseModule("auth", mongodb, redis)
 
 	// Create services
-	authService := services.NewAuthService(mongodb, esiClient)
+	authService := services.NewAuthService(mongodb, redis, esiClient)
 
 	// Create middleware with JWT validator
 	middlewareLayer := middleware.New(authService)
@@ -57,6 +65,48 @@ func (m *Module) RegisterHumaRoutes(r chi.Router) {
 // RegisterUnifiedRoutes registers routes on the shared Huma API
 func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string) {
 	routes.RegisterAuthRoutes(api, basePath, m.authService, m.middleware)
+	routes.RegisterProviderRoutes(api, basePath, m.authService)
+	if m.permissionMiddleware != nil {
+		routes.RegisterStaffRoutes(api, basePath, m.authService, m.permissionMiddleware)
+		routes.RegisterImpersonationRoutes(api, basePath, m.authService, m.permissionMiddleware)
+		routes.RegisterAuditRoutes(api, basePath, m.authService, m.permissionMiddleware)
+		routes.RegisterMFARoutes(api, basePath, m.authService, m.permissionMiddleware)
+	} else {
+		slog.Warn("Permission middleware not set, staff account, impersonation, audit and MFA endpoints are not registered")
+	}
+}
+
+// SetPermissionMiddleware sets the permission middleware used by the staff account endpoints
+func (m *Module) SetPermissionMiddleware(permissionMiddleware *humaMiddleware.PermissionMiddleware) {
+	m.permissionMiddleware = permissionMiddleware
+}
+
+// RegisterPermissions registers auth-specific permissions
+func (m *Module) RegisterPermissions(ctx context.Context, permissionManager *permissions.PermissionManager) error {
+	authPermissions := []permissions.Permission{
+		{
+			ID:          routes.AuditReadPermission,
+			Service:     "auth",
+			Resource:    "audit",
+			Action:      "read",
+			IsStatic:    false,
+			Name:        "Read Authentication Audit Log",
+			Description: "Query logins, logouts, session refreshes, scope grants and impersonations of all users",
+			Category:    "System Administration",
+			CreatedAt:   time.Now(),
+		},
+	}
+
+	return permissionManager.RegisterServicePermissions(ctx, authPermissions)
+}
+
+// Initialize loads the JWT signing keys, creating the first key when none exist, so tokens can
+// be issued and verified as soon as the server listens
+func (m *Module) Initialize(ctx context.Context) error {
+	if err := m.authService.RotateSigningKeys(ctx); err != nil {
+		return fmt.Errorf("failed to load JWT signing keys: %w", err)
+	}
+	return nil
 }
 
 // StartBackgroundTasks starts auth-specific background tasks
@@ -71,6 +121,9 @@ func (m *Module) StartBackgroundTasks(ctx context.Context) {
 
 	// Start token refresh routine (if needed)
 	go m.runTokenRefresh(ctx)
+
+	// Start JWT signing key rotation
+	go m.runSigningKeyRotation(ctx)
 }
 
 // GetAuthService returns the auth service for other modules
@@ -93,6 +146,46 @@ func (m *Module) RefreshExpiringTokens(ctx context.Context, batchSize int) (int,
 	return m.authService.RefreshExpiringTokens(ctx, batchSize)
 }
 
+// JWKSHandler serves the public JWT signing keys at /.well-known/jwks.json, outside the API
+// prefix, so other services can verify falcon JWTs
+func (m *Module) JWKSHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		set, err := m.authService.JWKS()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Failed to load JWT signing keys", "error", err)
+			http.Error(w, "signing keys unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		// New keys are published an hour before they sign, so caches may lag behind a little
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=900")
+		json.NewEncoder(w).Encode(set)
+	}
+}
+
+// runSigningKeyRotation keeps the JWT signing keys in sync with other replicas and rotates them
+// when they are due. Initialize does the first load.
+func (m *Module) runSigningKeyRotation(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Signing key rotation routine stopped due to context cancellation")
+			return
+		case <-m.StopChannel():
+			slog.Info("Signing key rotation routine stopped")
+			return
+		case <-ticker.C:
+			if err := m.authService.RotateSigningKeys(ctx); err != nil {
+				slog.Error("Failed to rotate JWT signing keys", "error", err)
+			}
+		}
+	}
+}
+
 // runStateCleanup periodically cleans up expired OAuth states
 func (m *Module) runStateCleanup(ctx context.Context) {
 	ticker := time.NewTicker(5 * time.Minute)
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"context"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+	"go-falcon/internal/auth/services"
+	"go-falcon/pkg/authz"
+	humaMiddleware "go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// AuditReadPermission allows querying the authentication audit log
+const AuditReadPermission = "auth:audit:read"
+
+// RegisterAuditRoutes registers the authentication audit log endpoints
+func RegisterAuditRoutes(api huma.API, basePath string, authService *services.AuthService, permissionMiddleware *humaMiddleware.PermissionMiddleware) {
+	huma.Register(api, authz.Protect(api, permissionMiddleware, huma.Operation{
+		OperationID: "auth-admin-list-audit-events",
+		Method:      "GET",
+		Path:        basePath + "/admin/audit",
+		Summary:     "Query authentication audit log",
+		Description: "List logins, logouts, session refreshes, scope grants and impersonations, newest first, filtered by user, character, event, outcome, IP address and time range. Failed SSO and provider callbacks are failed logins.",
+		Tags:        []string{"Auth / Audit"},
+	}, authz.Permissions(AuditReadPermission)), func(ctx context.Context, input *dto.AuthAuditInput) (*dto.AuthAuditOutput, error) {
+		filter := models.AuthAuditFilter{
+			UserID:      input.UserID,
+			CharacterID: input.CharacterID,
+			Event:       input.Event,
+			Outcome:     input.Outcome,
+			IPAddress:   input.IPAddress,
+			From:        input.From,
+			To:          input.To,
+		}
+		events, err := authService.ListAuthAuditEvents(ctx, filter, input.Page, input.Limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to query audit log", err)
+		}
+
+		return &dto.AuthAuditOutput{Body: *events}, nil
+	})
+}
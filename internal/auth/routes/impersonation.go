@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/services"
+	humaMiddleware "go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// RegisterImpersonationRoutes registers the user impersonation endpoints (super admin only)
+func RegisterImpersonationRoutes(api huma.API, basePath string, authService *services.AuthService, permissionMiddleware *humaMiddleware.PermissionMiddleware) {
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-admin-impersonate",
+		Method:      "POST",
+		Path:        basePath + "/admin/impersonate",
+		Summary:     "Impersonate user",
+		Description: "Issue a short-lived bearer token acting as the user of a character, to reproduce what they see. The token carries an act claim naming the administrator, cannot be refreshed and is recorded with the given reason (requires super admin).",
+		Tags:        []string{"Auth / Impersonation"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ImpersonateInput) (*dto.ImpersonationOutput, error) {
+		user, err := permissionMiddleware.RequireSuperAdmin(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		impersonation, err := authService.Impersonate(ctx, user, input.Body.CharacterID, input.Body.Reason, sessionClient(input.ClientRequest))
+		switch {
+		case errors.Is(err, services.ErrNestedImpersonation):
+			return nil, huma.Error403Forbidden(err.Error())
+		case errors.Is(err, services.ErrImpersonateSelf):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrImpersonationTargetNotFound):
+			return nil, huma.Error404NotFound("Character not found")
+		case err != nil:
+			return nil, huma.Error500InternalServerError("Failed to start impersonation", err)
+		}
+
+		return &dto.ImpersonationOutput{Body: *impersonation}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-admin-list-impersonations",
+		Method:      "GET",
+		Path:        basePath + "/admin/impersonations",
+		Summary:     "List impersonations",
+		Description: "List the most recent impersonations with who acted as whom and why (requires super admin)",
+		Tags:        []string{"Auth / Impersonation"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ImpersonationsInput) (*dto.ImpersonationsOutput, error) {
+		if _, err := permissionMiddleware.RequireSuperAdmin(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+
+		impersonations, err := authService.ListImpersonations(ctx, input.Limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list impersonations", err)
+		}
+
+		output := &dto.ImpersonationsOutput{}
+		output.Body.Impersonations = impersonations
+		return output, nil
+	})
+}
@@ -0,0 +1,139 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/services"
+	"go-falcon/pkg/authz"
+	humaMiddleware "go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// RegisterMFARoutes registers the authenticator (TOTP) enrollment and sudo endpoints (super admin only)
+func RegisterMFARoutes(api huma.API, basePath string, authService *services.AuthService, permissionMiddleware *humaMiddleware.PermissionMiddleware) {
+	huma.Register(api, authz.Protect(api, permissionMiddleware, huma.Operation{
+		OperationID: "auth-get-totp",
+		Method:      "GET",
+		Path:        basePath + "/mfa/totp",
+		Summary:     "Get authenticator status",
+		Description: "Reports whether an authenticator app protects the caller's account, or waits for its first code",
+		Tags:        []string{"Auth / MFA"},
+	}, authz.SuperAdmin()), func(ctx context.Context, input *dto.TOTPStatusInput) (*dto.TOTPStatusOutput, error) {
+		user := humaMiddleware.GetAuthenticatedUser(ctx)
+
+		status, err := authService.TOTPStatus(ctx, user.UserID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get authenticator status", err)
+		}
+		return &dto.TOTPStatusOutput{Body: *status}, nil
+	})
+
+	huma.Register(api, authz.Protect(api, permissionMiddleware, huma.Operation{
+		OperationID: "auth-enroll-totp",
+		Method:      "POST",
+		Path:        basePath + "/mfa/totp",
+		Summary:     "Enroll authenticator",
+		Description: "Generate an authenticator secret for the caller and return it with an otpauth:// URI for a QR code. The secret is shown once; the enrollment takes effect after confirming a first code. Starting over replaces an unconfirmed enrollment.",
+		Tags:        []string{"Auth / MFA"},
+	}, authz.SuperAdmin()), func(ctx context.Context, input *dto.TOTPEnrollInput) (*dto.TOTPEnrollOutput, error) {
+		user := humaMiddleware.GetAuthenticatedUser(ctx)
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		enrollment, err := authService.EnrollTOTP(ctx, user)
+		if err != nil {
+			return nil, totpError(err)
+		}
+		return &dto.TOTPEnrollOutput{Body: *enrollment}, nil
+	})
+
+	huma.Register(api, authz.Protect(api, permissionMiddleware, huma.Operation{
+		OperationID: "auth-confirm-totp",
+		Method:      "POST",
+		Path:        basePath + "/mfa/totp/confirm",
+		Summary:     "Confirm authenticator",
+		Description: "Confirm a pending enrollment with a code from the authenticator app. From then on permission grants, bans and SDE updates need a sudo token.",
+		Tags:        []string{"Auth / MFA"},
+	}, authz.SuperAdmin()), func(ctx context.Context, input *dto.TOTPCodeInput) (*dto.TOTPStatusOutput, error) {
+		user := humaMiddleware.GetAuthenticatedUser(ctx)
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		status, err := authService.ConfirmTOTP(ctx, user.UserID, input.Body.Code)
+		if err != nil {
+			return nil, totpError(err)
+		}
+		return &dto.TOTPStatusOutput{Body: *status}, nil
+	})
+
+	huma.Register(api, authz.Protect(api, permissionMiddleware, huma.Operation{
+		OperationID: "auth-remove-totp",
+		Method:      "DELETE",
+		Path:        basePath + "/mfa/totp",
+		Summary:     "Remove authenticator",
+		Description: "Remove the caller's authenticator. A confirmed authenticator is only removed with one of its current codes.",
+		Tags:        []string{"Auth / MFA"},
+	}, authz.SuperAdmin()), func(ctx context.Context, input *dto.TOTPCodeInput) (*dto.TOTPStatusOutput, error) {
+		user := humaMiddleware.GetAuthenticatedUser(ctx)
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		if err := authService.RemoveTOTP(ctx, user.UserID, input.Body.Code); err != nil {
+			return nil, totpError(err)
+		}
+		return &dto.TOTPStatusOutput{}, nil
+	})
+
+	huma.Register(api, authz.Protect(api, permissionMiddleware, huma.Operation{
+		OperationID: "auth-sudo",
+		Method:      "POST",
+		Path:        basePath + "/sudo",
+		Summary:     "Elevate session",
+		Description: "Exchange an authenticator code for a token of the same session carrying a sudo claim, which permission grants, bans and SDE updates require for STEP_UP_WINDOW_MINUTES. Replaces the session cookie; every attempt is audited.",
+		Tags:        []string{"Auth / MFA"},
+	}, authz.SuperAdmin()), func(ctx context.Context, input *dto.SudoInput) (*dto.SudoOutput, error) {
+		user := humaMiddleware.GetAuthenticatedUser(ctx)
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		sudo, err := authService.Sudo(ctx, user, input.Body.Code, sessionClient(input.ClientRequest))
+		if err != nil {
+			return nil, totpError(err)
+		}
+		return &dto.SudoOutput{
+			SetCookie: []string{humaMiddleware.CreateAuthCookieHeader(ctx, sudo.Token)},
+			Body:      *sudo,
+		}, nil
+	})
+}
+
+// totpError maps errors of enrolling or checking an authenticator to API errors
+func totpError(err error) error {
+	var throttled *services.ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		return huma.ErrorWithHeaders(huma.Error429TooManyRequests("Too many authenticator codes"),
+			http.Header{"Retry-After": {strconv.Itoa(retryAfter)}})
+	case errors.Is(err, services.ErrInvalidTOTPCode):
+		return huma.Error403Forbidden(err.Error())
+	case errors.Is(err, services.ErrTOTPNotEnrolled):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, services.ErrTOTPAlreadyEnrolled):
+		return huma.Error409Conflict(err.Error())
+	case errors.Is(err, services.ErrTOTPStaffAccount):
+		return huma.Error403Forbidden(err.Error())
+	default:
+		return huma.Error500InternalServerError("Failed to process authenticator", err)
+	}
+}
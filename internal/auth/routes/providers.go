@@ -0,0 +1,131 @@
+package routes
+
+import (
+	"context"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/services"
+	"go-falcon/pkg/config"
+	humaMiddleware "go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// RegisterProviderRoutes registers the staff identity provider login endpoints (public)
+func RegisterProviderRoutes(api huma.API, basePath string, authService *services.AuthService) {
+	staffService := authService.GetStaffService()
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-list-providers",
+		Method:      "GET",
+		Path:        basePath + "/providers",
+		Summary:     "List staff identity providers",
+		Description: "List the configured identity providers staff accounts sign in through; EVE SSO is always available under /auth/eve",
+		Tags:        []string{"Auth / Providers"},
+	}, func(ctx context.Context, input *struct{}) (*dto.ListProvidersOutput, error) {
+		output := &dto.ListProvidersOutput{}
+		output.Body.Providers = staffService.ListProviders()
+		return output, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-provider-login",
+		Method:      "GET",
+		Path:        basePath + "/providers/{provider}/login",
+		Summary:     "Initiate staff provider login",
+		Description: "Start the OAuth2 flow of a staff identity provider",
+		Tags:        []string{"Auth / Providers"},
+	}, func(ctx context.Context, input *dto.ProviderLoginInput) (*dto.ProviderLoginOutput, error) {
+		loginResp, err := staffService.InitiateLogin(ctx, input.Provider)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.ProviderLoginOutput{Body: *loginResp}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-provider-callback",
+		Method:      "GET",
+		Path:        basePath + "/providers/{provider}/callback",
+		Summary:     "Staff provider OAuth2 callback",
+		Description: "Handle the OAuth2 callback of a staff identity provider; only provisioned staff accounts can sign in",
+		Tags:        []string{"Auth / Providers"},
+	}, func(ctx context.Context, input *dto.ProviderCallbackInput) (*dto.EVECallbackOutput, error) {
+		jwtToken, err := staffService.HandleCallback(ctx, input.Provider, input.Code, input.State, sessionClient(input.ClientRequest))
+		if err != nil {
+			return nil, err
+		}
+
+		return &dto.EVECallbackOutput{
+			Status:    302,
+			SetCookie: []string{humaMiddleware.CreateAuthCookieHeader(ctx, jwtToken)},
+			Location:  config.GetFrontendURL(),
+		}, nil
+	})
+}
+
+// RegisterStaffRoutes registers the staff account management endpoints (super admin only)
+func RegisterStaffRoutes(api huma.API, basePath string, authService *services.AuthService, permissionMiddleware *humaMiddleware.PermissionMiddleware) {
+	staffService := authService.GetStaffService()
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-list-staff-accounts",
+		Method:      "GET",
+		Path:        basePath + "/staff",
+		Summary:     "List staff accounts",
+		Description: "List the non-EVE staff accounts and the permissions they hold (requires super admin)",
+		Tags:        []string{"Auth / Staff"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ListStaffAccountsInput) (*dto.ListStaffAccountsOutput, error) {
+		if _, err := permissionMiddleware.RequireSuperAdmin(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+		return staffService.ListStaffAccounts(ctx)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-create-staff-account",
+		Method:      "POST",
+		Path:        basePath + "/staff",
+		Summary:     "Create staff account",
+		Description: "Provision a staff account; it is claimed by the first login with a verified matching email (requires super admin)",
+		Tags:        []string{"Auth / Staff"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.CreateStaffAccountInput) (*dto.StaffAccountOutput, error) {
+		user, err := permissionMiddleware.RequireSuperAdmin(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		return staffService.CreateStaffAccount(ctx, &input.Body, user.CharacterID)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-update-staff-account",
+		Method:      "PUT",
+		Path:        basePath + "/staff/{account_id}",
+		Summary:     "Update staff account",
+		Description: "Change the name, permissions or enabled flag of a staff account; changes apply to its next request (requires super admin)",
+		Tags:        []string{"Auth / Staff"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UpdateStaffAccountInput) (*dto.StaffAccountOutput, error) {
+		if _, err := permissionMiddleware.RequireSuperAdmin(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+		return staffService.UpdateStaffAccount(ctx, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-delete-staff-account",
+		Method:      "DELETE",
+		Path:        basePath + "/staff/{account_id}",
+		Summary:     "Delete staff account",
+		Description: "Delete a staff account; its outstanding tokens lose all permissions (requires super admin)",
+		Tags:        []string{"Auth / Staff"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DeleteStaffAccountInput) (*dto.DeleteStaffAccountOutput, error) {
+		if _, err := permissionMiddleware.RequireSuperAdmin(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+		return staffService.DeleteStaffAccount(ctx, input.AccountID)
+	})
+}
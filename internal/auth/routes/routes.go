@@ -2,10 +2,18 @@ package routes
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"go-falcon/internal/auth/dto"
 	"go-falcon/internal/auth/middleware"
+	"go-falcon/internal/auth/models"
 	"go-falcon/internal/auth/services"
 	"go-falcon/pkg/config"
 	humaMiddleware "go-falcon/pkg/middleware"
@@ -57,20 +65,35 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		OperationID: "auth-eve-login",
 		Method:      "GET",
 		Path:        basePath + "/eve/login",
-		Summary:     "Initiate EVE SSO login (basic, no scopes)",
-		Description: "Start EVE Online SSO authentication flow without additional scopes",
+		Summary:     "Initiate EVE SSO login",
+		Description: "Start EVE Online SSO authentication flow, without scopes or with the scopes of a named scope set",
 		Tags:        []string{"Auth / EVE"},
 	}, func(ctx context.Context, input *dto.EVELoginInput) (*dto.EVELoginOutput, error) {
+		if err := throttle(ctx, authService, services.ThrottleEVELogin, input.IPAddress); err != nil {
+			return nil, err
+		}
+
 		// Extract user ID from cookie if present
 		userID := ""
 		if input.Cookie != "" {
 			user, err := authService.GetCurrentUserFromHeaders(ctx, "", input.Cookie)
-			if err == nil && user != nil {
+			if err == nil && user != nil && user.Impersonator == nil {
 				userID = user.UserID
 			}
 		}
 
-		// Generate login URL without scopes (basic login)
+		// Request the named scope set, or no scopes (basic login)
+		if input.Scopes != "" {
+			loginResp, err := authService.InitiateEVELoginWithScopeSet(ctx, input.Scopes, userID)
+			if errors.Is(err, services.ErrUnknownScopeSet) {
+				return nil, huma.Error400BadRequest("Unknown scope set: " + input.Scopes)
+			}
+			if err != nil {
+				return nil, huma.Error500InternalServerError("Failed to initiate login", err)
+			}
+			return &dto.EVELoginOutput{Body: *loginResp}, nil
+		}
+
 		loginResp, err := authService.InitiateEVELogin(ctx, false, userID)
 		if err != nil {
 			return nil, huma.Error500InternalServerError("Failed to initiate login", err)
@@ -79,6 +102,17 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		return &dto.EVELoginOutput{Body: *loginResp}, nil
 	})
 
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-eve-scope-sets",
+		Method:      "GET",
+		Path:        basePath + "/eve/scope-sets",
+		Summary:     "List EVE SSO scope sets",
+		Description: "List the named scope sets that /auth/eve/login?scopes=<name> can request",
+		Tags:        []string{"Auth / EVE"},
+	}, func(ctx context.Context, input *struct{}) (*dto.ScopeSetsOutput, error) {
+		return &dto.ScopeSetsOutput{Body: *authService.ListScopeSets(ctx)}, nil
+	})
+
 	huma.Register(api, huma.Operation{
 		OperationID: "auth-eve-register",
 		Method:      "GET",
@@ -91,7 +125,7 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		userID := ""
 		if input.Cookie != "" {
 			user, err := authService.GetCurrentUserFromHeaders(ctx, "", input.Cookie)
-			if err == nil && user != nil {
+			if err == nil && user != nil && user.Impersonator == nil {
 				userID = user.UserID
 			}
 		}
@@ -105,6 +139,34 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		return &dto.EVERegisterOutput{Body: *loginResp}, nil
 	})
 
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-eve-link",
+		Method:      "GET",
+		Path:        basePath + "/eve/link",
+		Summary:     "Initiate EVE SSO character linking",
+		Description: "Start an EVE SSO flow (with full scopes) that adds another character to the signed-in account. The session keeps its active character; a character that belongs to another account is rejected.",
+		Tags:        []string{"Auth / EVE"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.EVELinkInput) (*dto.EVELinkOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+		if user.IsStaff() {
+			return nil, huma.Error403Forbidden("Staff accounts cannot link EVE characters")
+		}
+
+		linkResp, err := authService.InitiateCharacterLink(ctx, user.UserID, user.CharacterID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to initiate character linking", err)
+		}
+
+		return &dto.EVELinkOutput{Body: *linkResp}, nil
+	})
+
 	huma.Register(api, huma.Operation{
 		OperationID: "auth-eve-callback",
 		Method:      "GET",
@@ -118,19 +180,25 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		if input.Cookie != "" {
 			// Try to validate the existing JWT from cookie
 			user, err := authService.GetCurrentUserFromHeaders(ctx, "", input.Cookie)
-			if err == nil && user != nil {
+			if err == nil && user != nil && user.Impersonator == nil {
 				existingUserID = user.UserID
 			}
 		}
 
 		// Handle the OAuth callback with existing user ID if available
-		jwtToken, _, err := authService.HandleEVECallbackWithUserID(ctx, input.Code, input.State, existingUserID)
+		tokenResp, _, err := authService.HandleEVECallbackWithUserID(ctx, input.Code, input.State, existingUserID, sessionClient(input.ClientRequest))
+		if errors.Is(err, services.ErrCharacterLinkedElsewhere) {
+			return nil, huma.Error409Conflict("Character is already linked to another account; unlink it there first")
+		}
+		if errors.Is(err, services.ErrCharacterDeleted) {
+			return nil, huma.Error403Forbidden("Character has been deleted")
+		}
 		if err != nil {
 			return nil, huma.Error400BadRequest("Authentication failed", err)
 		}
 
-		// Set authentication cookie using Huma header response
-		cookieHeader := humaMiddleware.CreateAuthCookieHeader(jwtToken)
+		// Set authentication and refresh token cookies using Huma header response
+		cookieHeaders := sessionCookies(ctx, tokenResp)
 
 		// Get frontend URL from configuration
 		frontendURL := config.GetFrontendURL()
@@ -139,7 +207,7 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		// Huma will handle this as a proper redirect response
 		return &dto.EVECallbackOutput{
 			Status:    302,
-			SetCookie: cookieHeader,
+			SetCookie: cookieHeaders,
 			Location:  frontendURL,
 			Body:      nil, // Empty body for redirect
 		}, nil
@@ -153,8 +221,16 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		Description: "Exchange EVE SSO access token for internal JWT (mobile apps)",
 		Tags:        []string{"Auth / EVE"},
 	}, func(ctx context.Context, input *dto.EVETokenExchangeInput) (*dto.EVETokenExchangeOutput, error) {
+		// The character is only counted once EVE SSO has verified the token, inside ExchangeEVEToken
+		if err := throttle(ctx, authService, services.ThrottleEVEToken, input.IPAddress); err != nil {
+			return nil, err
+		}
+
 		// Exchange EVE token for JWT
-		tokenResp, err := authService.ExchangeEVEToken(ctx, &input.Body)
+		tokenResp, err := authService.ExchangeEVEToken(ctx, &input.Body, sessionClient(input.ClientRequest))
+		if throttled := tooManyAttempts(err); throttled != nil {
+			return nil, throttled
+		}
 		if err != nil {
 			return nil, huma.Error401Unauthorized("Token exchange failed", err)
 		}
@@ -170,6 +246,11 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		Description: "Refresh an expired EVE access token using refresh token",
 		Tags:        []string{"Auth / EVE"},
 	}, func(ctx context.Context, input *dto.RefreshTokenInput) (*dto.RefreshTokenOutput, error) {
+		// EVE refresh tokens are opaque, so only the IP address is throttled
+		if err := throttle(ctx, authService, services.ThrottleEVERefresh, input.IPAddress); err != nil {
+			return nil, err
+		}
+
 		// Refresh the access token using the EVE service
 		tokenResp, err := authService.RefreshAccessToken(ctx, input.Body.RefreshToken)
 		if err != nil {
@@ -330,6 +411,9 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		if err != nil {
 			return nil, err // Returns proper Huma error response
 		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
 
 		// Get user profile to obtain user ID for token generation
 		profile, err := authService.GetUserProfile(ctx, user.CharacterID)
@@ -338,7 +422,7 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		}
 
 		// Generate JWT token for authenticated user
-		tokenResp, err := authService.GetBearerToken(ctx, profile.UserID, user.CharacterID, user.CharacterName, user.Scopes)
+		tokenResp, err := authService.GetBearerToken(ctx, user.SessionID, user.AuthTime, profile.UserID, user.CharacterID, user.CharacterName, user.Scopes)
 		if err != nil {
 			return nil, huma.Error500InternalServerError("Failed to generate token", err)
 		}
@@ -352,6 +436,428 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		return &dto.TokenOutput{Body: *response}, nil
 	})
 
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-session-refresh",
+		Method:      "POST",
+		Path:        basePath + "/session/refresh",
+		Summary:     "Refresh session",
+		Description: "Exchange the session refresh token (falcon_refresh_token cookie, or refresh_token in the body) for a new JWT and refresh token. Each refresh token works once; presenting a used one revokes the whole session.",
+		Tags:        []string{"Auth"},
+	}, func(ctx context.Context, input *dto.SessionRefreshInput) (*dto.SessionRefreshOutput, error) {
+		refreshToken := authMiddleware.ExtractRefreshTokenFromCookie(input.Cookie)
+		if input.Body != nil && input.Body.RefreshToken != "" {
+			refreshToken = input.Body.RefreshToken
+		}
+		if refreshToken == "" {
+			return nil, huma.Error401Unauthorized("Refresh token required")
+		}
+
+		tokenResp, err := authService.RefreshSession(ctx, refreshToken, sessionClient(input.ClientRequest))
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) {
+			return nil, huma.Error401Unauthorized(err.Error())
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to refresh session", err)
+		}
+
+		return &dto.SessionRefreshOutput{
+			SetCookie: sessionCookies(ctx, tokenResp),
+			Body:      *tokenResp,
+		}, nil
+	})
+
+	// Linked characters of the signed-in account
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-list-characters",
+		Method:      "GET",
+		Path:        basePath + "/characters",
+		Summary:     "List linked characters",
+		Description: "List the EVE characters linked to the signed-in account and which one the session acts as",
+		Tags:        []string{"Auth / Characters"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.LinkedCharactersInput) (*dto.LinkedCharactersOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		characters, err := authService.ListLinkedCharacters(ctx, user.UserID, user.CharacterID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list characters", err)
+		}
+
+		return &dto.LinkedCharactersOutput{Body: *characters}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-unlink-character",
+		Method:      "DELETE",
+		Path:        basePath + "/characters/{character_id}",
+		Summary:     "Unlink character",
+		Description: "Remove a character from the signed-in account. It becomes an account of its own and can still sign in; the active character cannot be unlinked.",
+		Tags:        []string{"Auth / Characters"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.LinkedCharacterInput) (*dto.LinkedCharactersOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		characters, err := authService.UnlinkCharacter(ctx, user.UserID, user.CharacterID, input.CharacterID)
+		if err != nil {
+			return nil, linkedCharacterError(err, "Failed to unlink character")
+		}
+
+		return &dto.LinkedCharactersOutput{Body: *characters}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-activate-character",
+		Method:      "POST",
+		Path:        basePath + "/characters/{character_id}/activate",
+		Summary:     "Switch active character",
+		Description: "Make another linked character the one the session acts as. Returns a new token and replaces the session cookie.",
+		Tags:        []string{"Auth / Characters"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.LinkedCharacterInput) (*dto.ActivateCharacterOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		tokenResp, err := authService.ActivateCharacter(ctx, user.SessionID, user.AuthTime, sessionClient(input.ClientRequest), user.UserID, input.CharacterID)
+		if err != nil {
+			return nil, linkedCharacterError(err, "Failed to switch character")
+		}
+
+		return &dto.ActivateCharacterOutput{
+			SetCookie: sessionCookies(ctx, tokenResp),
+			Body:      *tokenResp,
+		}, nil
+	})
+
+	// Sessions (signed-in devices) of the signed-in account
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-list-sessions",
+		Method:      "GET",
+		Path:        basePath + "/sessions",
+		Summary:     "List sessions",
+		Description: "List the active sessions of the signed-in account with the device, IP address and last activity of each",
+		Tags:        []string{"Auth / Sessions"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.SessionsInput) (*dto.SessionsOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions, err := authService.ListSessions(ctx, user.UserID, user.SessionID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list sessions", err)
+		}
+
+		return &dto.SessionsOutput{Body: *sessions}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-revoke-other-sessions",
+		Method:      "DELETE",
+		Path:        basePath + "/sessions",
+		Summary:     "Revoke other sessions",
+		Description: "Sign out every other device of the signed-in account; the current session stays signed in",
+		Tags:        []string{"Auth / Sessions"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.SessionsInput) (*dto.SessionsOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		if _, err := authService.RevokeOtherSessions(ctx, user.UserID, user.SessionID); err != nil {
+			return nil, huma.Error500InternalServerError("Failed to revoke sessions", err)
+		}
+
+		sessions, err := authService.ListSessions(ctx, user.UserID, user.SessionID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list sessions", err)
+		}
+
+		return &dto.SessionsOutput{Body: *sessions}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-revoke-session",
+		Method:      "DELETE",
+		Path:        basePath + "/sessions/{session_id}",
+		Summary:     "Revoke session",
+		Description: "Sign out one session of the signed-in account. Its tokens stop working immediately; revoking the current session signs this client out.",
+		Tags:        []string{"Auth / Sessions"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.SessionInput) (*dto.SessionsOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		err = authService.RevokeUserSession(ctx, user.UserID, input.SessionID)
+		if errors.Is(err, services.ErrSessionNotFound) {
+			return nil, huma.Error404NotFound("Session not found")
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to revoke session", err)
+		}
+
+		sessions, err := authService.ListSessions(ctx, user.UserID, user.SessionID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list sessions", err)
+		}
+
+		return &dto.SessionsOutput{Body: *sessions}, nil
+	})
+
+	// Device flow: headless tools sign in by having the user approve a code in the browser
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-device-code",
+		Method:      "POST",
+		Path:        basePath + "/device/code",
+		Summary:     "Start device sign-in",
+		Description: "Start an OAuth 2.0 device flow (RFC 8628) for a tool without a browser, such as falconctl. Show the user code and verification URI to the user, then poll POST /auth/device/token with the device code.",
+		Tags:        []string{"Auth / Device"},
+	}, func(ctx context.Context, input *dto.DeviceCodeInput) (*dto.DeviceCodeOutput, error) {
+		clientName := ""
+		if input.Body != nil {
+			clientName = input.Body.ClientName
+		}
+
+		code, err := authService.StartDeviceAuthorization(ctx, clientName, sessionClient(input.ClientRequest))
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to start device sign-in", err)
+		}
+
+		return &dto.DeviceCodeOutput{Body: *code}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-device-token",
+		Method:      "POST",
+		Path:        basePath + "/device/token",
+		Summary:     "Poll device sign-in",
+		Description: "Poll a device flow no more often than its interval. Until the user decides, the 400 response's detail is an RFC 8628 error code: authorization_pending, slow_down (back off by 5 seconds), access_denied, expired_token or invalid_grant. Once approved, returns a JWT and refresh token of a new session.",
+		Tags:        []string{"Auth / Device"},
+	}, func(ctx context.Context, input *dto.DeviceTokenInput) (*dto.DeviceTokenOutput, error) {
+		tokenResp, err := authService.PollDeviceAuthorization(ctx, input.Body.DeviceCode, sessionClient(input.ClientRequest))
+		switch {
+		case errors.Is(err, services.ErrDeviceAuthorizationPending), errors.Is(err, services.ErrDeviceSlowDown),
+			errors.Is(err, services.ErrDeviceAccessDenied), errors.Is(err, services.ErrDeviceCodeExpired),
+			errors.Is(err, services.ErrInvalidDeviceCode):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrCharacterDeleted):
+			return nil, huma.Error403Forbidden("Character has been deleted")
+		case err != nil:
+			return nil, huma.Error500InternalServerError("Failed to poll device sign-in", err)
+		}
+
+		return &dto.DeviceTokenOutput{Body: *tokenResp}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-device-get",
+		Method:      "GET",
+		Path:        basePath + "/device",
+		Summary:     "Get device sign-in",
+		Description: "Look up a pending device flow by the user code the tool shows, so the user can check which tool and address they are approving",
+		Tags:        []string{"Auth / Device"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DeviceAuthorizationInput) (*dto.DeviceAuthorizationOutput, error) {
+		if _, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+
+		authorization, err := authService.GetDeviceAuthorization(ctx, input.UserCode)
+		if err != nil {
+			return nil, deviceDecisionError(err)
+		}
+
+		return &dto.DeviceAuthorizationOutput{Body: *authorization}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-device-approve",
+		Method:      "POST",
+		Path:        basePath + "/device/approve",
+		Summary:     "Approve device sign-in",
+		Description: "Sign the tool in as the active character of the signed-in account. It receives a session of its own, listed and revocable with the other sessions.",
+		Tags:        []string{"Auth / Device"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DeviceDecisionInput) (*dto.DeviceAuthorizationOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		authorization, err := authService.DecideDeviceAuthorization(ctx, user, input.Body.UserCode, true)
+		if err != nil {
+			return nil, deviceDecisionError(err)
+		}
+
+		return &dto.DeviceAuthorizationOutput{Body: *authorization}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-device-deny",
+		Method:      "POST",
+		Path:        basePath + "/device/deny",
+		Summary:     "Deny device sign-in",
+		Description: "Reject a device flow; the tool's next poll fails with access_denied",
+		Tags:        []string{"Auth / Device"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DeviceDecisionInput) (*dto.DeviceAuthorizationOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		authorization, err := authService.DecideDeviceAuthorization(ctx, user, input.Body.UserCode, false)
+		if err != nil {
+			return nil, deviceDecisionError(err)
+		}
+
+		return &dto.DeviceAuthorizationOutput{Body: *authorization}, nil
+	})
+
+	// Discord account of the signed-in account
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-discord-login",
+		Method:      "GET",
+		Path:        basePath + "/discord/login",
+		Summary:     "Initiate Discord account linking",
+		Description: "Start a Discord OAuth2 flow (identify scope) that links a Discord account to the signed-in account, replacing any Discord account linked before",
+		Tags:        []string{"Auth / Discord"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DiscordLinkInput) (*dto.DiscordLoginOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+		if user.IsStaff() {
+			return nil, huma.Error403Forbidden("Staff accounts cannot link a Discord account")
+		}
+
+		loginResp, err := authService.InitiateDiscordLink(ctx, user.UserID)
+		if errors.Is(err, services.ErrDiscordNotConfigured) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to initiate Discord linking", err)
+		}
+
+		return &dto.DiscordLoginOutput{Body: *loginResp}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-discord-callback",
+		Method:      "GET",
+		Path:        basePath + "/discord/callback",
+		Summary:     "Discord account linking callback",
+		Description: "Handle the OAuth2 callback of a Discord link flow and redirect to the frontend",
+		Tags:        []string{"Auth / Discord"},
+	}, func(ctx context.Context, input *dto.DiscordCallbackInput) (*dto.DiscordCallbackOutput, error) {
+		frontendURL := config.GetFrontendURL()
+		failed := func(message string) *dto.DiscordCallbackOutput {
+			return &dto.DiscordCallbackOutput{
+				Status:   302,
+				Location: frontendURL + "/discord/error?message=" + url.QueryEscape(message),
+			}
+		}
+
+		if input.Error != "" || input.Code == "" {
+			return failed("Discord linking was cancelled."), nil
+		}
+
+		_, err := authService.CompleteDiscordLink(ctx, input.Code, input.State)
+		switch {
+		case errors.Is(err, services.ErrDiscordLinkedElsewhere):
+			return failed("This Discord account is already linked to another account."), nil
+		case errors.Is(err, services.ErrInvalidDiscordState):
+			return failed("The Discord linking request expired. Please try again."), nil
+		case err != nil:
+			slog.ErrorContext(ctx, "Discord account linking failed", "error", err)
+			return failed("Discord linking failed. Please try again."), nil
+		}
+
+		return &dto.DiscordCallbackOutput{
+			Status:   302,
+			Location: frontendURL + "/user/characters",
+		}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-discord-status",
+		Method:      "GET",
+		Path:        basePath + "/discord",
+		Summary:     "Get linked Discord account",
+		Description: "Get the Discord account linked to the signed-in account",
+		Tags:        []string{"Auth / Discord"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DiscordLinkInput) (*dto.DiscordLinkOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		link, err := authService.GetDiscordLinkStatus(ctx, user.UserID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get Discord account", err)
+		}
+
+		return &dto.DiscordLinkOutput{Body: *link}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-discord-unlink",
+		Method:      "DELETE",
+		Path:        basePath + "/discord",
+		Summary:     "Unlink Discord account",
+		Description: "Remove the Discord account linked to the signed-in account",
+		Tags:        []string{"Auth / Discord"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DiscordLinkInput) (*dto.DiscordLinkOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
+		}
+
+		if err := authService.UnlinkDiscord(ctx, user.UserID); err != nil {
+			return nil, huma.Error500InternalServerError("Failed to unlink Discord account", err)
+		}
+
+		return &dto.DiscordLinkOutput{Body: dto.DiscordLinkResponse{Linked: false}}, nil
+	})
+
 	// Public endpoints
 	huma.Register(api, huma.Operation{
 		OperationID: "auth-public-profile",
@@ -374,25 +880,135 @@ func RegisterAuthRoutes(api huma.API, basePath string, authService *services.Aut
 		Method:      "POST",
 		Path:        basePath + "/logout",
 		Summary:     "Logout user",
-		Description: "Clear authentication cookie and logout the user",
+		Description: "Revoke the session and clear the authentication and refresh token cookies",
 		Tags:        []string{"Auth"},
 	}, func(ctx context.Context, input *dto.LogoutInput) (*dto.LogoutOutput, error) {
-		// Clear authentication cookie
-		response := dto.LogoutResponse{
-			Success: true,
-			Message: "Logged out successfully",
+		return logout(ctx, authService, authMiddleware, input), nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-logout-all",
+		Method:      "POST",
+		Path:        basePath + "/logout-all",
+		Summary:     "Logout everywhere",
+		Description: "Sign the account out on every device: revoke all of its sessions and invalidate every JWT and cookie issued so far, then clear this client's cookies",
+		Tags:        []string{"Auth"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.LogoutInput) (*dto.LogoutOutput, error) {
+		user, err := authMiddleware.ValidateAuthFromHeaders(input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if err := rejectImpersonation(user); err != nil {
+			return nil, err
 		}
 
-		// Clear authentication cookie using Huma header response
-		cookieHeader := humaMiddleware.CreateClearCookieHeader()
+		revoked, err := authService.LogoutEverywhere(ctx, user, sessionClient(input.ClientRequest))
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to log out everywhere", err)
+		}
 
 		return &dto.LogoutOutput{
-			SetCookie: cookieHeader,
-			Body:      response,
+			SetCookie: []string{
+				humaMiddleware.CreateClearCookieHeader(ctx),
+				humaMiddleware.CreateClearRefreshCookieHeader(ctx),
+			},
+			Body: dto.LogoutResponse{
+				Success: true,
+				Message: fmt.Sprintf("Logged out everywhere; %d sessions revoked", revoked),
+			},
 		}, nil
 	})
 }
 
+// sessionClient returns the client a session is started or refreshed from
+func sessionClient(client dto.ClientRequest) models.SessionClient {
+	return models.SessionClient{UserAgent: client.UserAgent, IPAddress: client.IPAddress}
+}
+
+// sessionCookies returns the cookies of issued session tokens; the refresh token cookie is only
+// replaced when a refresh token was issued
+func sessionCookies(ctx context.Context, tokenResp *dto.TokenResponse) []string {
+	cookies := []string{humaMiddleware.CreateAuthCookieHeader(ctx, tokenResp.Token)}
+	if tokenResp.RefreshToken != "" {
+		cookies = append(cookies, humaMiddleware.CreateRefreshCookieHeader(ctx, tokenResp.RefreshToken))
+	}
+	return cookies
+}
+
+// logout revokes the caller's session and clears its cookies. Logging out always succeeds: a
+// failed revocation is logged, and the tokens still expire.
+func logout(ctx context.Context, authService *services.AuthService, authMiddleware *humaMiddleware.AuthMiddleware, input *dto.LogoutInput) *dto.LogoutOutput {
+	user := authMiddleware.ValidateOptionalAuthFromHeaders(input.Authorization, input.Cookie)
+	refreshToken := authMiddleware.ExtractRefreshTokenFromCookie(input.Cookie)
+	if err := authService.EndSession(ctx, user, refreshToken, sessionClient(input.ClientRequest)); err != nil {
+		slog.ErrorContext(ctx, "Failed to revoke session on logout", "error", err)
+	}
+
+	return &dto.LogoutOutput{
+		SetCookie: []string{
+			humaMiddleware.CreateClearCookieHeader(ctx),
+			humaMiddleware.CreateClearRefreshCookieHeader(ctx),
+		},
+		Body: dto.LogoutResponse{
+			Success: true,
+			Message: "Logged out successfully",
+		},
+	}
+}
+
+// rejectImpersonation keeps impersonation tokens away from endpoints that change the account or
+// its sessions, or that mint tokens outliving the impersonation
+func rejectImpersonation(user *models.AuthenticatedUser) error {
+	if user.Impersonator != nil {
+		return huma.Error403Forbidden("Not available while impersonating")
+	}
+	return nil
+}
+
+// throttle counts a sign-in attempt by IP address and answers 429 with Retry-After while the caller
+// is locked out. The IP address is the one chimiddleware.RealIP took from X-Forwarded-For or
+// X-Real-IP, which clients can set freely: the per-IP limit only holds behind a proxy that
+// overwrites those headers.
+func throttle(ctx context.Context, authService *services.AuthService, endpoint, ipAddress string) error {
+	return tooManyAttempts(authService.Throttle(ctx, endpoint, ipAddress, 0))
+}
+
+// tooManyAttempts maps a *services.ThrottledError to 429 with Retry-After; other errors give nil
+func tooManyAttempts(err error) error {
+	var throttled *services.ThrottledError
+	if !errors.As(err, &throttled) {
+		return nil
+	}
+	retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+	return huma.ErrorWithHeaders(huma.Error429TooManyRequests("Too many sign-in attempts"),
+		http.Header{"Retry-After": {strconv.Itoa(retryAfter)}})
+}
+
+// deviceDecisionError maps errors of looking up, approving or denying a device flow to API errors
+func deviceDecisionError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrUnknownUserCode):
+		return huma.Error404NotFound("Unknown or expired user code")
+	case errors.Is(err, services.ErrDeviceStaffAccount):
+		return huma.Error403Forbidden(err.Error())
+	default:
+		return huma.Error500InternalServerError("Failed to process device sign-in", err)
+	}
+}
+
+// linkedCharacterError maps linked character errors to API errors
+func linkedCharacterError(err error, message string) error {
+	switch {
+	case errors.Is(err, services.ErrCharacterNotLinked):
+		return huma.Error404NotFound("Character is not linked to this account")
+	case errors.Is(err, services.ErrActiveCharacter):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return huma.Error500InternalServerError(message, err)
+	}
+}
+
 // registerRoutes registers all Auth module routes with Huma
 // Removed registerRoutes() - all routes are now registered via RegisterAuthRoutes()
 // This prevents duplicate route registration and placeholder implementations
@@ -404,7 +1020,7 @@ func (hr *Routes) eveLogin(ctx context.Context, input *dto.EVELoginInput) (*dto.
 	userID := ""
 	if input.Cookie != "" {
 		user, err := hr.authService.GetCurrentUserFromHeaders(ctx, "", input.Cookie)
-		if err == nil && user != nil {
+		if err == nil && user != nil && user.Impersonator == nil {
 			userID = user.UserID
 		}
 	}
@@ -423,7 +1039,7 @@ func (hr *Routes) eveRegister(ctx context.Context, input *dto.EVERegisterInput)
 	userID := ""
 	if input.Cookie != "" {
 		user, err := hr.authService.GetCurrentUserFromHeaders(ctx, "", input.Cookie)
-		if err == nil && user != nil {
+		if err == nil && user != nil && user.Impersonator == nil {
 			userID = user.UserID
 		}
 	}
@@ -443,19 +1059,19 @@ func (hr *Routes) eveCallback(ctx context.Context, input *dto.EVECallbackInput)
 	if input.Cookie != "" {
 		// Try to validate the existing JWT from cookie
 		user, err := hr.authService.GetCurrentUserFromHeaders(ctx, "", input.Cookie)
-		if err == nil && user != nil {
+		if err == nil && user != nil && user.Impersonator == nil {
 			existingUserID = user.UserID
 		}
 	}
 
 	// Handle the OAuth callback with existing user ID if available
-	jwtToken, _, err := hr.authService.HandleEVECallbackWithUserID(ctx, input.Code, input.State, existingUserID)
+	tokenResp, _, err := hr.authService.HandleEVECallbackWithUserID(ctx, input.Code, input.State, existingUserID, sessionClient(input.ClientRequest))
 	if err != nil {
 		return nil, huma.Error400BadRequest("Authentication failed", err)
 	}
 
-	// Set authentication cookie using Huma header response
-	cookieHeader := humaMiddleware.CreateAuthCookieHeader(jwtToken)
+	// Set authentication and refresh token cookies using Huma header response
+	cookieHeaders := sessionCookies(ctx, tokenResp)
 
 	// Get frontend URL from configuration
 	frontendURL := config.GetFrontendURL()
@@ -464,7 +1080,7 @@ func (hr *Routes) eveCallback(ctx context.Context, input *dto.EVECallbackInput)
 	// Huma will handle this as a proper redirect response
 	return &dto.EVECallbackOutput{
 		Status:    302,
-		SetCookie: cookieHeader,
+		SetCookie: cookieHeaders,
 		Location:  frontendURL,
 		Body:      nil, // Empty body for redirect
 	}, nil
@@ -472,7 +1088,7 @@ func (hr *Routes) eveCallback(ctx context.Context, input *dto.EVECallbackInput)
 
 func (hr *Routes) eveTokenExchange(ctx context.Context, input *dto.EVETokenExchangeInput) (*dto.EVETokenExchangeOutput, error) {
 	// Exchange EVE token for JWT
-	tokenResp, err := hr.authService.ExchangeEVEToken(ctx, &input.Body)
+	tokenResp, err := hr.authService.ExchangeEVEToken(ctx, &input.Body, sessionClient(input.ClientRequest))
 	if err != nil {
 		return nil, huma.Error401Unauthorized("Token exchange failed", err)
 	}
@@ -563,7 +1179,7 @@ func (hr *Routes) token(ctx context.Context, input *dto.TokenInput) (*dto.TokenO
 	}
 
 	// Generate JWT token for authenticated user
-	tokenResp, err := hr.authService.GetBearerToken(ctx, profile.UserID, user.CharacterID, user.CharacterName, user.Scopes)
+	tokenResp, err := hr.authService.GetBearerToken(ctx, user.SessionID, user.AuthTime, profile.UserID, user.CharacterID, user.CharacterName, user.Scopes)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Failed to generate token", err)
 	}
@@ -589,19 +1205,7 @@ func (hr *Routes) publicProfile(ctx context.Context, input *dto.PublicProfileInp
 }
 
 func (hr *Routes) logout(ctx context.Context, input *dto.LogoutInput) (*dto.LogoutOutput, error) {
-	// Clear authentication cookie
-	response := dto.LogoutResponse{
-		Success: true,
-		Message: "Logged out successfully",
-	}
-
-	// Clear authentication cookie using Huma header response
-	cookieHeader := humaMiddleware.CreateClearCookieHeader()
-
-	return &dto.LogoutOutput{
-		SetCookie: cookieHeader,
-		Body:      response,
-	}, nil
+	return logout(ctx, hr.authService, hr.authMiddleware, input), nil
 }
 
 // Helper methods for cookie handling (to be implemented in future iterations)
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+)
+
+// auditWriteTimeout bounds an audit write. The write is detached from the request, so a client
+// hanging up mid-login cannot keep its attempt out of the log.
+const auditWriteTimeout = 5 * time.Second
+
+// recordAuthEvent appends an event from a client to the auth audit log. Auditing never fails the
+// operation it records; a failed write is logged instead.
+func recordAuthEvent(ctx context.Context, repository *Repository, event *models.AuthAuditEvent, client models.SessionClient) {
+	event.IPAddress = client.IPAddress
+	event.UserAgent = client.UserAgent
+	event.Timestamp = time.Now()
+
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+
+	if err := repository.InsertAuditEvent(writeCtx, event); err != nil {
+		slog.ErrorContext(ctx, "Failed to record auth audit event",
+			"event", event.Event, "outcome", event.Outcome, "character_id", event.CharacterID, "error", err)
+	}
+}
+
+// recordAuthEvent appends an event to the auth audit log
+func (s *AuthService) recordAuthEvent(ctx context.Context, event *models.AuthAuditEvent, client models.SessionClient) {
+	recordAuthEvent(ctx, s.repository, event, client)
+}
+
+// recordScopeGrant records the scopes a login granted that the character did not have before
+func (s *AuthService) recordScopeGrant(ctx context.Context, previousScopes string, profile *models.UserProfile, method string, client models.SessionClient) {
+	granted := grantedScopes(previousScopes, profile.Scopes)
+	if granted == "" {
+		return
+	}
+	s.recordAuthEvent(ctx, &models.AuthAuditEvent{
+		Event:         models.AuditEventScopeGrant,
+		Outcome:       models.AuditOutcomeSuccess,
+		Method:        method,
+		UserID:        profile.UserID,
+		CharacterID:   profile.CharacterID,
+		CharacterName: profile.CharacterName,
+		Scopes:        granted,
+	}, client)
+}
+
+// grantedScopes returns the scopes of current that previous did not have
+func grantedScopes(previous, current string) string {
+	had := make(map[string]bool)
+	for _, scope := range strings.Fields(previous) {
+		had[scope] = true
+	}
+
+	var granted []string
+	for _, scope := range strings.Fields(current) {
+		if !had[scope] {
+			granted = append(granted, scope)
+		}
+	}
+	return strings.Join(granted, " ")
+}
+
+// previousScopes returns the scopes a character held before a login, or "" for a new character
+func (s *AuthService) previousScopes(ctx context.Context, characterID int) string {
+	profile, err := s.repository.GetUserProfileByCharacterID(ctx, characterID)
+	if err != nil || profile == nil {
+		return ""
+	}
+	return profile.Scopes
+}
+
+// ListAuthAuditEvents returns a page of the auth audit log
+func (s *AuthService) ListAuthAuditEvents(ctx context.Context, filter models.AuthAuditFilter, page, limit int) (*dto.AuthAuditResponse, error) {
+	events, total, err := s.repository.ListAuditEvents(ctx, filter, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AuthAuditResponse{
+		Events: make([]dto.AuthAuditEvent, 0, len(events)),
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, dto.AuthAuditEvent{
+			ID:            event.ID.Hex(),
+			Event:         event.Event,
+			Outcome:       event.Outcome,
+			Method:        event.Method,
+			UserID:        event.UserID,
+			CharacterID:   event.CharacterID,
+			CharacterName: event.CharacterName,
+			SessionID:     event.SessionID,
+			Scopes:        event.Scopes,
+			Detail:        event.Detail,
+			IPAddress:     event.IPAddress,
+			UserAgent:     event.UserAgent,
+			Timestamp:     event.Timestamp,
+		})
+	}
+	return response, nil
+}
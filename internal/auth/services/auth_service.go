@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"crypto/cipher"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -12,6 +13,7 @@ import (
 
 	"go-falcon/internal/auth/dto"
 	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/config"
 	"go-falcon/pkg/database"
 	"go-falcon/pkg/evegateway"
 	"go-falcon/pkg/handlers"
@@ -26,7 +28,12 @@ type AuthService struct {
 	repository     *Repository
 	eveService     *EVEService
 	profileService *ProfileService
+	staffService   *StaffService
 	groupsService  GroupsService // Interface to avoid circular dependency
+	scopeSets      ScopeSetSource
+	redis          *database.Redis  // Session revocation list
+	discordLink    *discordProvider // Discord account linking; nil when the Discord application is not configured
+	totpSeal       cipher.AEAD      // Seals authenticator secrets
 }
 
 // GroupsService interface for groups module dependency
@@ -38,17 +45,50 @@ type GroupsService interface {
 }
 
 // NewAuthService creates a new auth service with all dependencies
-func NewAuthService(mongodb *database.MongoDB, esiClient *evegateway.Client) *AuthService {
-	repository := NewRepository(mongodb)
+func NewAuthService(mongodb *database.MongoDB, redis *database.Redis, esiClient *evegateway.Client) *AuthService {
+	repository := NewRepository(mongodb, redis)
 	eveService := NewEVEService(repository)
 	profileService := NewProfileService(repository, eveService, esiClient)
+	staffService := NewStaffService(repository, eveService, NewProviderRegistry())
 
-	return &AuthService{
+	service := &AuthService{
 		repository:     repository,
 		eveService:     eveService,
 		profileService: profileService,
+		staffService:   staffService,
 		groupsService:  nil, // Will be set after groups module initialization
+		redis:          redis,
+		discordLink:    newDiscordLinkProvider(),
+		totpSeal:       newSecretSeal([]byte(config.GetJWTSecret()), "go-falcon/totp-secrets:"),
 	}
+	eveService.tokenGeneration = service.tokenGeneration
+	service.registerBuiltinClaims()
+
+	return service
+}
+
+// RegisterClaimProvider registers a custom JWT claim. The claim is only issued
+// when its name is listed in JWT_CUSTOM_CLAIMS.
+func (s *AuthService) RegisterClaimProvider(name string, provider ClaimProvider) error {
+	return s.eveService.claims.Register(name, provider)
+}
+
+// registerBuiltinClaims registers the claims auth can answer from the user profile
+func (s *AuthService) registerBuiltinClaims() {
+	s.eveService.claims.Register("corporation_id", func(ctx context.Context, subject ClaimSubject) (any, error) {
+		profile, err := s.repository.GetUserProfileByCharacterID(ctx, subject.CharacterID)
+		if err != nil || profile == nil || profile.CorporationID == 0 {
+			return nil, err
+		}
+		return profile.CorporationID, nil
+	})
+	s.eveService.claims.Register("alliance_id", func(ctx context.Context, subject ClaimSubject) (any, error) {
+		profile, err := s.repository.GetUserProfileByCharacterID(ctx, subject.CharacterID)
+		if err != nil || profile == nil || profile.AllianceID == 0 {
+			return nil, err
+		}
+		return profile.AllianceID, nil
+	})
 }
 
 // SetGroupsService sets the groups service dependency (called after groups module initialization)
@@ -56,6 +96,16 @@ func (s *AuthService) SetGroupsService(groupsService GroupsService) {
 	s.groupsService = groupsService
 }
 
+// GetStaffService returns the service for staff accounts and their identity providers
+func (s *AuthService) GetStaffService() *StaffService {
+	return s.staffService
+}
+
+// GetStaffPermissions returns the permissions of a staff account (for the permission middleware)
+func (s *AuthService) GetStaffPermissions(ctx context.Context, userID string) ([]string, error) {
+	return s.staffService.GetStaffPermissions(ctx, userID)
+}
+
 // HealthCheck handles health check requests
 func (s *AuthService) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	handlers.HealthHandler("auth")(w, r)
@@ -87,7 +137,7 @@ func (s *AuthService) GetAuthStatus(ctx context.Context, r *http.Request) (*dto.
 	}
 
 	// Validate JWT and get user info
-	user, err := s.eveService.ValidateJWT(jwtToken)
+	user, err := s.ValidateJWT(jwtToken)
 	if err != nil {
 		return &dto.AuthStatusResponse{
 			Authenticated: false,
@@ -110,14 +160,19 @@ func (s *AuthService) GetAuthStatus(ctx context.Context, r *http.Request) (*dto.
 	}
 
 	// Return authenticated response with user info
-	return &dto.AuthStatusResponse{
+	response := &dto.AuthStatusResponse{
 		Authenticated: true,
 		UserID:        &user.UserID,
 		CharacterID:   &user.CharacterID,
 		CharacterName: &user.CharacterName,
 		Characters:    []string{user.CharacterName}, // For now, just include current character
 		Permissions:   permissions,
-	}, nil
+	}
+	if user.Impersonator != nil {
+		impersonator := impersonatorInfo(user.Impersonator)
+		response.Impersonator = &impersonator
+	}
+	return response, nil
 }
 
 // GetCurrentUser returns current user information
@@ -139,7 +194,7 @@ func (s *AuthService) GetCurrentUser(ctx context.Context, r *http.Request) (*dto
 	}
 
 	// Validate JWT and get user info
-	user, err := s.eveService.ValidateJWT(jwtToken)
+	user, err := s.ValidateJWT(jwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
@@ -187,7 +242,7 @@ func (s *AuthService) GetAuthStatusFromHeaders(ctx context.Context, authHeader,
 	}
 
 	// Validate JWT and get user info
-	user, err := s.eveService.ValidateJWT(jwtToken)
+	user, err := s.ValidateJWT(jwtToken)
 	if err != nil {
 		return &dto.AuthStatusResponse{
 			Authenticated: false,
@@ -248,17 +303,22 @@ func (s *AuthService) GetCurrentUserFromHeaders(ctx context.Context, authHeader,
 	}
 
 	// Validate JWT and get user info
-	user, err := s.eveService.ValidateJWT(jwtToken)
+	user, err := s.ValidateJWT(jwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
-	return &dto.UserInfoResponse{
+	userInfo := &dto.UserInfoResponse{
 		UserID:        user.UserID,
 		CharacterID:   user.CharacterID,
 		CharacterName: user.CharacterName,
 		Scopes:        user.Scopes,
-	}, nil
+	}
+	if user.Impersonator != nil {
+		impersonator := impersonatorInfo(user.Impersonator)
+		userInfo.Impersonator = &impersonator
+	}
+	return userInfo, nil
 }
 
 // InitiateEVELogin initiates EVE SSO login flow
@@ -286,12 +346,38 @@ func (s *AuthService) InitiateEVELogin(ctx context.Context, withScopes bool, use
 }
 
 // HandleEVECallback processes EVE SSO callback (legacy, without user ID from cookie)
-func (s *AuthService) HandleEVECallback(ctx context.Context, code, state string) (string, *dto.UserInfoResponse, error) {
-	return s.HandleEVECallbackWithUserID(ctx, code, state, "")
+func (s *AuthService) HandleEVECallback(ctx context.Context, code, state string, client models.SessionClient) (*dto.TokenResponse, *dto.UserInfoResponse, error) {
+	return s.HandleEVECallbackWithUserID(ctx, code, state, "", client)
 }
 
 // HandleEVECallbackWithUserID processes EVE SSO callback with optional existing user ID from cookie
-func (s *AuthService) HandleEVECallbackWithUserID(ctx context.Context, code, state, cookieUserID string) (string, *dto.UserInfoResponse, error) {
+// and starts a new session. The login is recorded in the audit log whether it succeeds or not.
+func (s *AuthService) HandleEVECallbackWithUserID(ctx context.Context, code, state, cookieUserID string, client models.SessionClient) (*dto.TokenResponse, *dto.UserInfoResponse, error) {
+	sessionTokens, userInfo, err := s.handleEVECallback(ctx, code, state, cookieUserID, client)
+	if err != nil {
+		s.recordAuthEvent(ctx, &models.AuthAuditEvent{
+			Event:   models.AuditEventLogin,
+			Outcome: models.AuditOutcomeFailure,
+			Method:  models.AuditMethodEVESSO,
+			Detail:  err.Error(),
+		}, client)
+		return nil, nil, err
+	}
+
+	s.recordAuthEvent(ctx, &models.AuthAuditEvent{
+		Event:         models.AuditEventLogin,
+		Outcome:       models.AuditOutcomeSuccess,
+		Method:        models.AuditMethodEVESSO,
+		UserID:        userInfo.UserID,
+		CharacterID:   userInfo.CharacterID,
+		CharacterName: userInfo.CharacterName,
+		SessionID:     sessionTokens.SessionID,
+	}, client)
+	return sessionTokens, userInfo, nil
+}
+
+// handleEVECallback completes an EVE SSO login or link flow
+func (s *AuthService) handleEVECallback(ctx context.Context, code, state, cookieUserID string, client models.SessionClient) (*dto.TokenResponse, *dto.UserInfoResponse, error) {
 	tracer := otel.Tracer("go-falcon/auth")
 	ctx, span := tracer.Start(ctx, "auth.service.handle_eve_callback")
 	defer span.End()
@@ -304,21 +390,34 @@ func (s *AuthService) HandleEVECallbackWithUserID(ctx context.Context, code, sta
 	)
 
 	// Handle OAuth callback
-	charInfo, tokenResp, stateUserID, err := s.eveService.HandleCallback(ctx, code, state)
+	charInfo, tokenResp, loginState, err := s.eveService.HandleCallback(ctx, code, state)
 	if err != nil {
 		span.RecordError(err)
-		return "", nil, fmt.Errorf("failed to handle callback: %w", err)
+		return nil, nil, fmt.Errorf("failed to handle callback: %w", err)
 	}
+	stateUserID := loginState.UserID
 
 	// Determine the user ID with priority:
+	// 0. User ID of a link flow, which must not take a character from another account
 	// 1. User ID from valid cookie (if user is already logged in)
 	// 2. User ID from state (if stored during login initiation)
 	// 3. User ID from existing profile for this character
 	// 4. Generate new user ID
 	userID := ""
 
-	// First priority: use user ID from valid cookie if available
-	if cookieUserID != "" {
+	if loginState.Purpose == models.LoginPurposeLink {
+		existingProfile, err := s.profileService.GetProfile(ctx, charInfo.CharacterID)
+		if err != nil {
+			span.RecordError(err)
+			return nil, nil, fmt.Errorf("failed to check existing profile: %w", err)
+		}
+		if existingProfile != nil && existingProfile.UserID != stateUserID {
+			return nil, nil, ErrCharacterLinkedElsewhere
+		}
+		userID = stateUserID
+		span.SetAttributes(attribute.String("user_id_source", "link"))
+	} else if cookieUserID != "" {
+		// First priority: use user ID from valid cookie if available
 		userID = cookieUserID
 		span.SetAttributes(attribute.String("user_id_source", "cookie"))
 	} else if stateUserID != "" {
@@ -330,7 +429,7 @@ func (s *AuthService) HandleEVECallbackWithUserID(ctx context.Context, code, sta
 		existingProfile, err := s.profileService.GetProfile(ctx, charInfo.CharacterID)
 		if err != nil {
 			span.RecordError(err)
-			return "", nil, fmt.Errorf("failed to check existing profile: %w", err)
+			return nil, nil, fmt.Errorf("failed to check existing profile: %w", err)
 		}
 
 		if existingProfile != nil {
@@ -344,11 +443,13 @@ func (s *AuthService) HandleEVECallbackWithUserID(ctx context.Context, code, sta
 	}
 
 	// Create or update user profile
+	previousScopes := s.previousScopes(ctx, charInfo.CharacterID)
 	profile, err := s.profileService.CreateOrUpdateProfile(ctx, charInfo, userID, tokenResp.AccessToken, tokenResp.RefreshToken, tokenResp.ExpiresIn)
 	if err != nil {
 		span.RecordError(err)
-		return "", nil, fmt.Errorf("failed to create/update profile: %w", err)
+		return nil, nil, fmt.Errorf("failed to create/update profile: %w", err)
 	}
+	s.recordScopeGrant(ctx, previousScopes, profile, models.AuditMethodEVESSO, client)
 
 	// Check if this should be the first super admin (only if groups service is available)
 	if s.groupsService != nil {
@@ -379,11 +480,19 @@ func (s *AuthService) HandleEVECallbackWithUserID(ctx context.Context, code, sta
 		}
 	}
 
-	// Generate JWT token
-	jwtToken, _, err := s.eveService.GenerateJWT(profile.UserID, profile.CharacterID, profile.CharacterName, profile.Scopes)
+	// A linked character joins the account; the session stays on the character that started the flow
+	if loginState.Purpose == models.LoginPurposeLink && loginState.CharacterID != 0 && loginState.CharacterID != profile.CharacterID {
+		active, err := s.profileService.GetProfile(ctx, loginState.CharacterID)
+		if err == nil && active != nil && active.UserID == profile.UserID {
+			profile = active
+		}
+	}
+
+	// Start a session with a JWT and refresh token
+	sessionTokens, err := s.startSession(ctx, client, time.Now(), profile.UserID, profile.CharacterID, profile.CharacterName, profile.Scopes)
 	if err != nil {
 		span.RecordError(err)
-		return "", nil, fmt.Errorf("failed to generate JWT: %w", err)
+		return nil, nil, fmt.Errorf("failed to start session: %w", err)
 	}
 
 	userInfo := &dto.UserInfoResponse{
@@ -393,11 +502,36 @@ func (s *AuthService) HandleEVECallbackWithUserID(ctx context.Context, code, sta
 		Scopes:        profile.Scopes,
 	}
 
-	return jwtToken, userInfo, nil
+	return sessionTokens, userInfo, nil
 }
 
-// ExchangeEVEToken exchanges EVE token for JWT (mobile apps)
-func (s *AuthService) ExchangeEVEToken(ctx context.Context, req *dto.EVETokenExchangeRequest) (*dto.TokenResponse, error) {
+// ExchangeEVEToken exchanges EVE token for JWT (mobile apps); the login is recorded in the audit log
+func (s *AuthService) ExchangeEVEToken(ctx context.Context, req *dto.EVETokenExchangeRequest, client models.SessionClient) (*dto.TokenResponse, error) {
+	tokenResp, profile, err := s.exchangeEVEToken(ctx, req, client)
+	if err != nil {
+		s.recordAuthEvent(ctx, &models.AuthAuditEvent{
+			Event:   models.AuditEventLogin,
+			Outcome: models.AuditOutcomeFailure,
+			Method:  models.AuditMethodEVEToken,
+			Detail:  err.Error(),
+		}, client)
+		return nil, err
+	}
+
+	s.recordAuthEvent(ctx, &models.AuthAuditEvent{
+		Event:         models.AuditEventLogin,
+		Outcome:       models.AuditOutcomeSuccess,
+		Method:        models.AuditMethodEVEToken,
+		UserID:        profile.UserID,
+		CharacterID:   profile.CharacterID,
+		CharacterName: profile.CharacterName,
+		SessionID:     tokenResp.SessionID,
+	}, client)
+	return tokenResp, nil
+}
+
+// exchangeEVEToken verifies a mobile app's EVE token and starts a session for its character
+func (s *AuthService) exchangeEVEToken(ctx context.Context, req *dto.EVETokenExchangeRequest, client models.SessionClient) (*dto.TokenResponse, *models.UserProfile, error) {
 	tracer := otel.Tracer("go-falcon/auth")
 	ctx, span := tracer.Start(ctx, "auth.service.exchange_eve_token")
 	defer span.End()
@@ -411,35 +545,41 @@ func (s *AuthService) ExchangeEVEToken(ctx context.Context, req *dto.EVETokenExc
 	charInfo, err := s.verifyEVEAccessToken(ctx, req.AccessToken)
 	if err != nil {
 		span.RecordError(err)
-		return nil, fmt.Errorf("failed to verify EVE token: %w", err)
+		return nil, nil, fmt.Errorf("failed to verify EVE token: %w", err)
+	}
+
+	// Count the exchange against the character only now that EVE SSO vouches for it, so forged
+	// tokens cannot lock a character out
+	if err := s.Throttle(ctx, ThrottleEVEToken, "", charInfo.CharacterID); err != nil {
+		span.RecordError(err)
+		return nil, nil, err
 	}
 
 	// Calculate ExpiresIn from ExpiresOn timestamp
 	expiresOn, err := time.Parse("2006-01-02T15:04:05Z", charInfo.ExpiresOn)
 	if err != nil {
 		span.RecordError(err)
-		return nil, fmt.Errorf("failed to parse token expiry: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse token expiry: %w", err)
 	}
 	expiresIn := int(time.Until(expiresOn).Seconds())
 
 	// Create or update user profile
+	previousScopes := s.previousScopes(ctx, charInfo.CharacterID)
 	profile, err := s.profileService.CreateOrUpdateProfile(ctx, charInfo, "", req.AccessToken, req.RefreshToken, expiresIn)
 	if err != nil {
 		span.RecordError(err)
-		return nil, fmt.Errorf("failed to create/update profile: %w", err)
+		return nil, nil, fmt.Errorf("failed to create/update profile: %w", err)
 	}
+	s.recordScopeGrant(ctx, previousScopes, profile, models.AuditMethodEVEToken, client)
 
-	// Generate JWT token
-	jwtToken, expiresAt, err := s.eveService.GenerateJWT(profile.UserID, profile.CharacterID, profile.CharacterName, profile.Scopes)
+	// Start a session with a JWT and refresh token
+	tokenResp, err := s.startSession(ctx, client, time.Now(), profile.UserID, profile.CharacterID, profile.CharacterName, profile.Scopes)
 	if err != nil {
 		span.RecordError(err)
-		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+		return nil, nil, fmt.Errorf("failed to start session: %w", err)
 	}
 
-	return &dto.TokenResponse{
-		Token:     jwtToken,
-		ExpiresAt: expiresAt,
-	}, nil
+	return tokenResp, profile, nil
 }
 
 // GetUserProfile returns full user profile
@@ -509,9 +649,10 @@ func (s *AuthService) GetPublicProfile(ctx context.Context, characterID int) (*d
 	}, nil
 }
 
-// GetBearerToken generates a bearer token for authenticated user
-func (s *AuthService) GetBearerToken(ctx context.Context, userID string, characterID int, characterName, scopes string) (*dto.TokenResponse, error) {
-	jwtToken, expiresAt, err := s.eveService.GenerateJWT(userID, characterID, characterName, scopes)
+// GetBearerToken generates a bearer token for authenticated user. The token belongs to the
+// caller's session, so revoking the session revokes it too, and keeps the session's sign-in time.
+func (s *AuthService) GetBearerToken(ctx context.Context, sessionID string, authTime time.Time, userID string, characterID int, characterName, scopes string) (*dto.TokenResponse, error) {
+	jwtToken, expiresAt, err := s.eveService.GenerateSessionJWT(ctx, sessionID, userID, characterID, characterName, scopes, authTime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -544,9 +685,18 @@ func (s *AuthService) CleanupExpiredStates(ctx context.Context) error {
 	return s.eveService.CleanupExpiredStates(ctx)
 }
 
-// ValidateJWT validates a JWT token (for middleware)
+// ValidateJWT validates a JWT token (for middleware), rejecting tokens of revoked sessions and
+// tokens issued before the account logged out everywhere
 func (s *AuthService) ValidateJWT(token string) (*models.AuthenticatedUser, error) {
-	return s.eveService.ValidateJWT(token)
+	user, err := s.eveService.ValidateJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	if s.sessionRevoked(user.SessionID) || s.generationRevoked(user) {
+		return nil, ErrSessionRevoked
+	}
+	s.touchSession(user.SessionID)
+	return user, nil
 }
 
 // verifyEVEAccessToken verifies an EVE access token and returns character info
@@ -673,7 +823,25 @@ func (s *AuthService) RefreshAccessToken(ctx context.Context, refreshToken strin
 	return s.eveService.RefreshAccessToken(ctx, refreshToken)
 }
 
+// RefreshCharacterToken implements evegateway.TokenRefresher so ESI calls refresh expired or
+// rejected character tokens transparently
+func (s *AuthService) RefreshCharacterToken(ctx context.Context, characterID int, staleToken string) (string, error) {
+	return s.profileService.RefreshCharacterToken(ctx, characterID, staleToken)
+}
+
+// CharacterToken returns an ESI token provider for a character's stored SSO token
+func (s *AuthService) CharacterToken(characterID int) evegateway.TokenProvider {
+	return s.profileService.CharacterToken(characterID)
+}
+
 // VerifyJWT verifies a JWT token and returns user information with expiration time
 func (s *AuthService) VerifyJWT(token string) (*models.AuthenticatedUser, time.Time, error) {
-	return s.eveService.VerifyJWT(token)
+	user, expiresAt, err := s.eveService.VerifyJWT(token)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if s.sessionRevoked(user.SessionID) || s.generationRevoked(user) {
+		return nil, time.Time{}, ErrSessionRevoked
+	}
+	return user, expiresAt, nil
 }
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrCharacterLinkedElsewhere is returned when a link flow signs in a character that belongs to another account
+	ErrCharacterLinkedElsewhere = errors.New("character is already linked to another account")
+	// ErrCharacterNotLinked is returned for a character that is not part of the caller's account
+	ErrCharacterNotLinked = errors.New("character is not linked to this account")
+	// ErrActiveCharacter is returned when unlinking the character the session acts as
+	ErrActiveCharacter = errors.New("the active character cannot be unlinked; switch to another character first")
+)
+
+// InitiateCharacterLink starts an EVE SSO flow that links another character to the user's
+// account; characterID remains the active character once the flow completes
+func (s *AuthService) InitiateCharacterLink(ctx context.Context, userID string, characterID int) (*dto.EVELoginResponse, error) {
+	authURL, state, err := s.eveService.GenerateLinkURL(ctx, userID, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate link URL: %w", err)
+	}
+
+	return &dto.EVELoginResponse{
+		AuthURL: authURL,
+		State:   state,
+	}, nil
+}
+
+// ListLinkedCharacters returns the characters of a user account in their display order, leaving
+// out soft-deleted ones
+func (s *AuthService) ListLinkedCharacters(ctx context.Context, userID string, activeCharacterID int) (*dto.LinkedCharactersResponse, error) {
+	profiles, err := s.repository.GetAllCharactersByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get characters: %w", err)
+	}
+	profiles = slices.DeleteFunc(profiles, func(profile *models.UserProfile) bool { return profile.DeletedAt != nil })
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Position < profiles[j].Position })
+
+	response := &dto.LinkedCharactersResponse{
+		UserID:            userID,
+		ActiveCharacterID: activeCharacterID,
+		Characters:        make([]dto.LinkedCharacter, 0, len(profiles)),
+	}
+	for _, profile := range profiles {
+		response.Characters = append(response.Characters, dto.LinkedCharacter{
+			CharacterID:     profile.CharacterID,
+			CharacterName:   profile.CharacterName,
+			CorporationID:   profile.CorporationID,
+			CorporationName: profile.CorporationName,
+			AllianceID:      profile.AllianceID,
+			AllianceName:    profile.AllianceName,
+			Position:        profile.Position,
+			Valid:           profile.Valid,
+			Active:          profile.CharacterID == activeCharacterID,
+			LastLogin:       profile.LastLogin,
+		})
+	}
+	return response, nil
+}
+
+// UnlinkCharacter removes a character from the user's account. The character keeps its profile
+// and tokens under a new account of its own, so it can still sign in by itself.
+func (s *AuthService) UnlinkCharacter(ctx context.Context, userID string, activeCharacterID, characterID int) (*dto.LinkedCharactersResponse, error) {
+	if characterID == activeCharacterID {
+		return nil, ErrActiveCharacter
+	}
+	if _, err := s.linkedProfile(ctx, userID, characterID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.ReassignCharacter(ctx, characterID, uuid.New().String(), 0); err != nil {
+		return nil, fmt.Errorf("failed to unlink character: %w", err)
+	}
+
+	return s.ListLinkedCharacters(ctx, userID, activeCharacterID)
+}
+
+// ActivateCharacter issues a session token that acts as another character of the user's account.
+// The session's refresh token follows the switch; a token without a session starts one. authTime
+// is the caller's sign-in time, which the new token keeps.
+func (s *AuthService) ActivateCharacter(ctx context.Context, sessionID string, authTime time.Time, client models.SessionClient, userID string, characterID int) (*dto.TokenResponse, error) {
+	profile, err := s.linkedProfile(ctx, userID, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sessionID == "" {
+		return s.startSession(ctx, client, authTime, userID, profile.CharacterID, profile.CharacterName, profile.Scopes)
+	}
+	if err := s.repository.SwitchRefreshSessionCharacter(ctx, sessionID, profile.CharacterID, profile.CharacterName, profile.Scopes); err != nil {
+		return nil, fmt.Errorf("failed to switch session character: %w", err)
+	}
+	return s.GetBearerToken(ctx, sessionID, authTime, userID, profile.CharacterID, profile.CharacterName, profile.Scopes)
+}
+
+// linkedProfile returns the profile of a character when it belongs to the user's account
+func (s *AuthService) linkedProfile(ctx context.Context, userID string, characterID int) (*models.UserProfile, error) {
+	profile, err := s.repository.GetUserProfileByCharacterID(ctx, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get character: %w", err)
+	}
+	if profile == nil || profile.UserID != userID || profile.DeletedAt != nil {
+		return nil, ErrCharacterNotLinked
+	}
+	return profile, nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"go-falcon/pkg/config"
+)
+
+// ClaimSubject identifies the user a JWT is being issued for
+type ClaimSubject struct {
+	UserID        string
+	CharacterID   int
+	CharacterName string
+	Scopes        string
+}
+
+// ClaimProvider returns the value of a custom claim for a subject.
+// A nil value omits the claim from the token.
+type ClaimProvider func(ctx context.Context, subject ClaimSubject) (any, error)
+
+// reservedClaims are set by GenerateJWT and cannot be overridden by providers
+var reservedClaims = map[string]bool{
+	"user_id":            true,
+	"character_id":       true,
+	"character_name":     true,
+	"scopes":             true,
+	"provider":           true,
+	"exp":                true,
+	"iat":                true,
+	"nbf":                true,
+	"iss":                true,
+	"sub":                true,
+	"aud":                true,
+	"jti":                true,
+	"ver":                true,
+	sessionIDClaim:       true,
+	actorClaim:           true,
+	tokenGenerationClaim: true,
+	sudoClaim:            true,
+	authTimeClaim:        true,
+}
+
+// ClaimRegistry holds custom claim providers and decides which ones are added to issued JWTs
+type ClaimRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]ClaimProvider
+	enabled   []string
+	maxBytes  int
+}
+
+// NewClaimRegistry creates a registry configured from JWT_CUSTOM_CLAIMS and JWT_CUSTOM_CLAIMS_MAX_BYTES
+func NewClaimRegistry() *ClaimRegistry {
+	return &ClaimRegistry{
+		providers: make(map[string]ClaimProvider),
+		enabled:   config.GetJWTCustomClaims(),
+		maxBytes:  config.GetJWTCustomClaimsMaxBytes(),
+	}
+}
+
+// Register adds a claim provider under the claim name it populates
+func (r *ClaimRegistry) Register(name string, provider ClaimProvider) error {
+	if name == "" || provider == nil {
+		return fmt.Errorf("claim name and provider are required")
+	}
+	if reservedClaims[name] {
+		return fmt.Errorf("claim %q is reserved", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("claim provider %q is already registered", name)
+	}
+	r.providers[name] = provider
+	return nil
+}
+
+// Registered returns the names of all registered providers
+func (r *ClaimRegistry) Registered() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Collect evaluates the enabled providers in configuration order. Failing providers are skipped,
+// and claims that would push the encoded total past the size budget are dropped.
+func (r *ClaimRegistry) Collect(ctx context.Context, subject ClaimSubject) map[string]any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.enabled) == 0 {
+		return nil
+	}
+
+	claims := make(map[string]any)
+	used := 0
+	for _, name := range r.enabled {
+		provider, ok := r.providers[name]
+		if !ok {
+			continue
+		}
+
+		value, err := provider(ctx, subject)
+		if err != nil {
+			slog.WarnContext(ctx, "Custom JWT claim provider failed", "claim", name, "character_id", subject.CharacterID, "error", err)
+			continue
+		}
+		if value == nil {
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			slog.WarnContext(ctx, "Custom JWT claim is not JSON encodable", "claim", name, "error", err)
+			continue
+		}
+		size := len(name) + len(encoded)
+		if r.maxBytes > 0 && used+size > r.maxBytes {
+			slog.WarnContext(ctx, "Custom JWT claim dropped, size budget exceeded",
+				"claim", name, "size", size, "used", used, "max_bytes", r.maxBytes)
+			continue
+		}
+
+		claims[name] = value
+		used += size
+	}
+
+	return claims
+}
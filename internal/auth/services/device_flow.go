@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/config"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Device flow polling errors, named after the RFC 8628 error codes tools expect
+var (
+	// ErrDeviceAuthorizationPending is returned while the user has not decided yet
+	ErrDeviceAuthorizationPending = errors.New("authorization_pending")
+	// ErrDeviceSlowDown is returned when a tool polls faster than the interval
+	ErrDeviceSlowDown = errors.New("slow_down")
+	// ErrDeviceAccessDenied is returned once when the user denied the device
+	ErrDeviceAccessDenied = errors.New("access_denied")
+	// ErrDeviceCodeExpired is returned for a device code the user did not approve in time
+	ErrDeviceCodeExpired = errors.New("expired_token")
+	// ErrInvalidDeviceCode is returned for an unknown or already redeemed device code
+	ErrInvalidDeviceCode = errors.New("invalid_grant")
+)
+
+var (
+	// ErrUnknownUserCode is returned when approving or denying a user code that is unknown,
+	// expired or already decided
+	ErrUnknownUserCode = errors.New("unknown or expired user code")
+	// ErrDeviceStaffAccount is returned when a staff account tries to approve a device
+	ErrDeviceStaffAccount = errors.New("device sign-in is only available to EVE characters")
+)
+
+const (
+	// deviceCodeLifetime is how long the user has to approve a device
+	deviceCodeLifetime = 10 * time.Minute
+	// devicePollInterval is the minimum time between polls of a device code
+	devicePollInterval = 5 * time.Second
+	// userCodeAlphabet leaves out vowels and look-alike letters (RFC 8628 section 6.1)
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	// userCodeLength is the number of characters of a user code, shown as two groups of four
+	userCodeLength = 8
+	// userCodeAttempts bounds retries when a generated user code is already in use
+	userCodeAttempts = 3
+)
+
+// StartDeviceAuthorization starts a device flow for a tool that cannot receive a browser redirect
+func (s *AuthService) StartDeviceAuthorization(ctx context.Context, clientName string, client models.SessionClient) (*dto.DeviceCodeResponse, error) {
+	deviceCode, err := newRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate device code: %w", err)
+	}
+
+	now := time.Now()
+	authorization := &models.DeviceAuthorization{
+		DeviceCodeHash: hashRefreshToken(deviceCode),
+		ClientName:     clientName,
+		UserAgent:      client.UserAgent,
+		IPAddress:      client.IPAddress,
+		Status:         models.DeviceAuthorizationPending,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(deviceCodeLifetime),
+	}
+	for attempt := 1; ; attempt++ {
+		authorization.UserCode, err = newUserCode()
+		if err != nil {
+			return nil, err
+		}
+		err = s.repository.InsertDeviceAuthorization(ctx, authorization)
+		if err == nil {
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == userCodeAttempts {
+			return nil, fmt.Errorf("failed to store device authorization: %w", err)
+		}
+	}
+
+	userCode := formatUserCode(authorization.UserCode)
+	verificationURI := config.GetFrontendURL() + "/device"
+	return &dto.DeviceCodeResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(userCode),
+		ExpiresIn:               int(deviceCodeLifetime.Seconds()),
+		Interval:                int(devicePollInterval.Seconds()),
+	}, nil
+}
+
+// GetDeviceAuthorization returns the device flow of a user code, so the user can check which
+// tool they are about to approve
+func (s *AuthService) GetDeviceAuthorization(ctx context.Context, userCode string) (*dto.DeviceAuthorizationResponse, error) {
+	authorization, err := s.repository.GetDeviceAuthorizationByUserCode(ctx, normalizeUserCode(userCode))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device authorization: %w", err)
+	}
+	if authorization == nil || time.Now().After(authorization.ExpiresAt) {
+		return nil, ErrUnknownUserCode
+	}
+	return deviceAuthorizationToDTO(authorization), nil
+}
+
+// DecideDeviceAuthorization approves or denies a pending device flow. An approved device signs in
+// as the user's active character with a session of its own.
+func (s *AuthService) DecideDeviceAuthorization(ctx context.Context, user *models.AuthenticatedUser, userCode string, approve bool) (*dto.DeviceAuthorizationResponse, error) {
+	if user.Provider != "" {
+		return nil, ErrDeviceStaffAccount
+	}
+
+	status := models.DeviceAuthorizationDenied
+	if approve {
+		status = models.DeviceAuthorizationApproved
+	}
+	decided, err := s.repository.DecideDeviceAuthorization(ctx, normalizeUserCode(userCode), status, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update device authorization: %w", err)
+	}
+	if !decided {
+		return nil, ErrUnknownUserCode
+	}
+	return s.GetDeviceAuthorization(ctx, userCode)
+}
+
+// PollDeviceAuthorization is polled by the tool with its device code. Once the user approved, the
+// first poll starts a session and returns its tokens; until then it returns one of the device
+// flow polling errors.
+func (s *AuthService) PollDeviceAuthorization(ctx context.Context, deviceCode string, client models.SessionClient) (*dto.TokenResponse, error) {
+	deviceCodeHash := hashRefreshToken(deviceCode)
+	authorization, err := s.repository.PollDeviceAuthorization(ctx, deviceCodeHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device authorization: %w", err)
+	}
+	if authorization == nil {
+		return nil, ErrInvalidDeviceCode
+	}
+	if time.Now().After(authorization.ExpiresAt) {
+		return nil, ErrDeviceCodeExpired
+	}
+
+	switch authorization.Status {
+	case models.DeviceAuthorizationDenied:
+		if _, err := s.repository.ClaimDeviceAuthorization(ctx, deviceCodeHash, models.DeviceAuthorizationDenied); err != nil {
+			return nil, fmt.Errorf("failed to remove device authorization: %w", err)
+		}
+		return nil, ErrDeviceAccessDenied
+	case models.DeviceAuthorizationApproved:
+		return s.redeemDeviceAuthorization(ctx, deviceCodeHash, client)
+	}
+
+	if authorization.LastPolledAt != nil && time.Since(*authorization.LastPolledAt) < devicePollInterval {
+		return nil, ErrDeviceSlowDown
+	}
+	return nil, ErrDeviceAuthorizationPending
+}
+
+// redeemDeviceAuthorization starts the session of an approved device flow, recorded as a login
+func (s *AuthService) redeemDeviceAuthorization(ctx context.Context, deviceCodeHash string, client models.SessionClient) (*dto.TokenResponse, error) {
+	authorization, err := s.repository.ClaimDeviceAuthorization(ctx, deviceCodeHash, models.DeviceAuthorizationApproved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeem device authorization: %w", err)
+	}
+	if authorization == nil {
+		// A concurrent poll redeemed it
+		return nil, ErrInvalidDeviceCode
+	}
+
+	// The device never signed in through SSO, so its session carries no sign-in time
+	tokenResp, err := s.startSession(ctx, client, time.Time{}, authorization.UserID, authorization.CharacterID, authorization.CharacterName, authorization.Scopes)
+
+	event := &models.AuthAuditEvent{
+		Event:         models.AuditEventLogin,
+		Outcome:       models.AuditOutcomeSuccess,
+		Method:        models.AuditMethodDeviceCode,
+		UserID:        authorization.UserID,
+		CharacterID:   authorization.CharacterID,
+		CharacterName: authorization.CharacterName,
+		Detail:        authorization.ClientName,
+	}
+	if err != nil {
+		event.Outcome = models.AuditOutcomeFailure
+		event.Detail = err.Error()
+	} else {
+		event.SessionID = tokenResp.SessionID
+	}
+	s.recordAuthEvent(ctx, event, client)
+
+	return tokenResp, err
+}
+
+// newUserCode generates a normalized user code
+func newUserCode() (string, error) {
+	var code strings.Builder
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := 0; i < userCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate user code: %w", err)
+		}
+		code.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return code.String(), nil
+}
+
+// normalizeUserCode reads a user code as typed: case, dashes and spaces are ignored
+func normalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(userCode))
+}
+
+// formatUserCode splits a normalized user code into two groups for display (BCDF-GHJK)
+func formatUserCode(userCode string) string {
+	half := len(userCode) / 2
+	return userCode[:half] + "-" + userCode[half:]
+}
+
+func deviceAuthorizationToDTO(authorization *models.DeviceAuthorization) *dto.DeviceAuthorizationResponse {
+	return &dto.DeviceAuthorizationResponse{
+		UserCode:   formatUserCode(authorization.UserCode),
+		ClientName: authorization.ClientName,
+		UserAgent:  authorization.UserAgent,
+		IPAddress:  authorization.IPAddress,
+		Status:     authorization.Status,
+		CreatedAt:  authorization.CreatedAt,
+		ExpiresAt:  authorization.ExpiresAt,
+	}
+}
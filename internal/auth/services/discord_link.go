@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+)
+
+var (
+	// ErrDiscordNotConfigured is returned when the Discord application is not configured
+	ErrDiscordNotConfigured = errors.New("Discord account linking is not configured")
+	// ErrInvalidDiscordState is returned for a Discord callback without a pending link flow
+	ErrInvalidDiscordState = errors.New("invalid or expired state")
+	// ErrDiscordLinkedElsewhere is returned when the Discord account is linked to another user
+	ErrDiscordLinkedElsewhere = errors.New("Discord account is already linked to another account")
+)
+
+// InitiateDiscordLink starts a Discord OAuth2 flow that links a Discord account to the user
+func (s *AuthService) InitiateDiscordLink(ctx context.Context, userID string) (*dto.EVELoginResponse, error) {
+	if s.discordLink == nil {
+		return nil, ErrDiscordNotConfigured
+	}
+
+	state, err := s.eveService.generateSecureState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate state: %w", err)
+	}
+	err = s.repository.StoreLoginState(ctx, &models.EVELoginState{
+		State:    state,
+		UserID:   userID,
+		Provider: models.ProviderDiscord,
+		Purpose:  models.LoginPurposeDiscordLink,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store login state: %w", err)
+	}
+
+	authURL, err := s.discordLink.AuthCodeURL(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.EVELoginResponse{
+		AuthURL: authURL,
+		State:   state,
+	}, nil
+}
+
+// CompleteDiscordLink handles the Discord callback of a link flow and stores the Discord account
+// on the user that started it. Linking again replaces the user's previous Discord account.
+func (s *AuthService) CompleteDiscordLink(ctx context.Context, code, state string) (*models.DiscordLink, error) {
+	if s.discordLink == nil {
+		return nil, ErrDiscordNotConfigured
+	}
+
+	loginState, err := s.repository.ConsumeLoginState(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate state: %w", err)
+	}
+	if loginState == nil || loginState.Purpose != models.LoginPurposeDiscordLink || loginState.UserID == "" {
+		return nil, ErrInvalidDiscordState
+	}
+
+	external, err := s.discordLink.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with Discord: %w", err)
+	}
+
+	owner, err := s.repository.GetDiscordLinkOwner(ctx, external.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check Discord account: %w", err)
+	}
+	if owner != "" && owner != loginState.UserID {
+		return nil, ErrDiscordLinkedElsewhere
+	}
+
+	link := &models.DiscordLink{
+		UserID:   external.Subject,
+		Username: external.Name,
+		LinkedAt: time.Now(),
+	}
+	if err := s.repository.SetDiscordLink(ctx, loginState.UserID, link); err != nil {
+		return nil, fmt.Errorf("failed to store Discord account: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Discord account linked", "user_id", loginState.UserID, "discord_user_id", link.UserID)
+	return link, nil
+}
+
+// UnlinkDiscord removes the Discord account of a user
+func (s *AuthService) UnlinkDiscord(ctx context.Context, userID string) error {
+	if err := s.repository.SetDiscordLink(ctx, userID, nil); err != nil {
+		return fmt.Errorf("failed to unlink Discord account: %w", err)
+	}
+	return nil
+}
+
+// GetDiscordLink returns the Discord account linked to a user, or nil
+func (s *AuthService) GetDiscordLink(ctx context.Context, userID string) (*models.DiscordLink, error) {
+	profiles, err := s.repository.GetAllCharactersByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get characters: %w", err)
+	}
+	for _, profile := range profiles {
+		if profile.Discord != nil {
+			return profile.Discord, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetDiscordLinkStatus returns the Discord account linked to a user for API responses
+func (s *AuthService) GetDiscordLinkStatus(ctx context.Context, userID string) (*dto.DiscordLinkResponse, error) {
+	link, err := s.GetDiscordLink(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return &dto.DiscordLinkResponse{Linked: false}, nil
+	}
+	return &dto.DiscordLinkResponse{
+		Linked:        true,
+		DiscordUserID: link.UserID,
+		Username:      link.Username,
+		LinkedAt:      &link.LinkedAt,
+	}, nil
+}
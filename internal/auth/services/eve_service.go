@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -19,6 +21,7 @@ import (
 	"go-falcon/pkg/config"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -46,19 +49,27 @@ type EVEService struct {
 	redirectURI  string
 	scopes       string
 	jwtSecret    []byte
+	signingKeys  *SigningKeys
 	jwksCache    *JWKSCache
 	repository   *Repository
+	claims       *ClaimRegistry
+
+	// tokenGeneration returns the current token generation of an account; set by the auth service
+	tokenGeneration func(ctx context.Context, userID string) int64
 }
 
 // NewEVEService creates a new EVE SSO service
 func NewEVEService(repository *Repository) *EVEService {
+	jwtSecret := []byte(config.GetJWTSecret())
 	return &EVEService{
 		clientID:     config.GetEVEClientID(),
 		clientSecret: config.GetEVEClientSecret(),
 		redirectURI:  config.GetEVERedirectURI(),
 		scopes:       config.GetEVEScopes(),
-		jwtSecret:    []byte(config.GetJWTSecret()),
+		jwtSecret:    jwtSecret,
+		signingKeys:  NewSigningKeys(repository, jwtSecret),
 		repository:   repository,
+		claims:       NewClaimRegistry(),
 		jwksCache: &JWKSCache{
 			keys: make(map[string]*rsa.PublicKey),
 		},
@@ -67,6 +78,35 @@ func NewEVEService(repository *Repository) *EVEService {
 
 // GenerateAuthURL generates an EVE SSO authorization URL
 func (s *EVEService) GenerateAuthURL(ctx context.Context, withScopes bool, userID string) (string, string, error) {
+	scopes := ""
+	if withScopes {
+		scopes = s.scopes
+	}
+	return s.generateAuthURL(ctx, scopes, &models.EVELoginState{UserID: userID})
+}
+
+// GenerateAuthURLWithScopes generates an EVE SSO authorization URL requesting the given
+// space-separated scopes
+func (s *EVEService) GenerateAuthURLWithScopes(ctx context.Context, scopes, userID string) (string, string, error) {
+	return s.generateAuthURL(ctx, scopes, &models.EVELoginState{UserID: userID})
+}
+
+// DefaultScopes returns the scopes of a full registration (EVE_SCOPES)
+func (s *EVEService) DefaultScopes() string {
+	return s.scopes
+}
+
+// GenerateLinkURL generates an EVE SSO authorization URL (with full scopes) whose callback links
+// the character to userID's account while characterID stays the active character
+func (s *EVEService) GenerateLinkURL(ctx context.Context, userID string, characterID int) (string, string, error) {
+	return s.generateAuthURL(ctx, s.scopes, &models.EVELoginState{
+		UserID:      userID,
+		Purpose:     models.LoginPurposeLink,
+		CharacterID: characterID,
+	})
+}
+
+func (s *EVEService) generateAuthURL(ctx context.Context, scopes string, loginState *models.EVELoginState) (string, string, error) {
 	tracer := otel.Tracer("go-falcon/auth")
 	ctx, span := tracer.Start(ctx, "auth.eve_service.generate_auth_url")
 	defer span.End()
@@ -78,11 +118,16 @@ func (s *EVEService) GenerateAuthURL(ctx context.Context, withScopes bool, userI
 		return "", "", fmt.Errorf("failed to generate state: %w", err)
 	}
 
-	// Store state in database
-	loginState := &models.EVELoginState{
-		State:  state,
-		UserID: userID,
+	// PKCE binds the authorization code to this login: only the holder of the verifier can redeem it
+	codeVerifier, err := newPKCEVerifier()
+	if err != nil {
+		span.RecordError(err)
+		return "", "", fmt.Errorf("failed to generate code verifier: %w", err)
 	}
+
+	// Store state and verifier until the callback
+	loginState.State = state
+	loginState.CodeVerifier = codeVerifier
 	if err := s.repository.StoreLoginState(ctx, loginState); err != nil {
 		span.RecordError(err)
 		return "", "", fmt.Errorf("failed to store login state: %w", err)
@@ -94,9 +139,11 @@ func (s *EVEService) GenerateAuthURL(ctx context.Context, withScopes bool, userI
 	params.Set("redirect_uri", s.redirectURI)
 	params.Set("client_id", s.clientID)
 	params.Set("state", state)
+	params.Set("code_challenge", pkceChallenge(codeVerifier))
+	params.Set("code_challenge_method", "S256")
 
-	if withScopes && s.scopes != "" {
-		params.Set("scope", s.scopes)
+	if scopes != "" {
+		params.Set("scope", scopes)
 	}
 
 	authURL := EVEAuthURL + "?" + params.Encode()
@@ -104,15 +151,16 @@ func (s *EVEService) GenerateAuthURL(ctx context.Context, withScopes bool, userI
 	span.SetAttributes(
 		attribute.String("service", "auth"),
 		attribute.String("operation", "generate_auth_url"),
-		attribute.Bool("with_scopes", withScopes),
+		attribute.Bool("with_scopes", scopes != ""),
 		attribute.String("state", state),
+		attribute.String("purpose", loginState.Purpose),
 	)
 
 	return authURL, state, nil
 }
 
-// HandleCallback processes the OAuth callback from EVE
-func (s *EVEService) HandleCallback(ctx context.Context, code, state string) (*models.EVECharacterInfo, *models.EVETokenResponse, string, error) {
+// HandleCallback processes the OAuth callback from EVE and returns the login state it belongs to
+func (s *EVEService) HandleCallback(ctx context.Context, code, state string) (*models.EVECharacterInfo, *models.EVETokenResponse, *models.EVELoginState, error) {
 	tracer := otel.Tracer("go-falcon/auth")
 	ctx, span := tracer.Start(ctx, "auth.eve_service.handle_callback")
 	defer span.End()
@@ -123,119 +171,114 @@ func (s *EVEService) HandleCallback(ctx context.Context, code, state string) (*m
 		attribute.String("state", state),
 	)
 
-	// Validate state
-	loginState, err := s.repository.GetLoginState(ctx, state)
+	// Validate and consume state; a state completes one callback
+	loginState, err := s.repository.ConsumeLoginState(ctx, state)
 	if err != nil {
 		span.RecordError(err)
-		return nil, nil, "", fmt.Errorf("failed to validate state: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to validate state: %w", err)
 	}
-	if loginState == nil {
+	if loginState == nil || loginState.Provider != "" {
 		err := errors.New("invalid or expired state")
 		span.RecordError(err)
-		return nil, nil, "", err
+		return nil, nil, nil, err
 	}
 
 	// Exchange code for tokens
-	tokenResponse, err := s.exchangeCodeForToken(ctx, code)
+	tokenResponse, err := s.exchangeCodeForToken(ctx, code, loginState.CodeVerifier)
 	if err != nil {
 		span.RecordError(err)
-		return nil, nil, "", fmt.Errorf("failed to exchange code for token: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to exchange code for token: %w", err)
 	}
 
 	// Verify token and get character info
 	charInfo, err := s.verifyAccessToken(ctx, tokenResponse.AccessToken)
 	if err != nil {
 		span.RecordError(err)
-		return nil, nil, "", fmt.Errorf("failed to verify access token: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to verify access token: %w", err)
 	}
 
-	return charInfo, tokenResponse, loginState.UserID, nil
+	return charInfo, tokenResponse, loginState, nil
 }
 
 // ValidateJWT validates a JWT token and returns user information
 func (s *EVEService) ValidateJWT(tokenString string) (*models.AuthenticatedUser, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.jwtSecret, nil
-	})
-
+	claims, err := s.parseToken(tokenString)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse JWT: %w", err)
-	}
-
-	if !token.Valid {
-		return nil, errors.New("invalid JWT token")
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, errors.New("invalid JWT claims")
+		return nil, err
 	}
-
-	// Extract user information from claims
-	userID, _ := claims["user_id"].(string)
-	characterIDFloat, _ := claims["character_id"].(float64)
-	characterID := int(characterIDFloat)
-	characterName, _ := claims["character_name"].(string)
-	scopes, _ := claims["scopes"].(string)
-
-	return &models.AuthenticatedUser{
-		UserID:        userID,
-		CharacterID:   characterID,
-		CharacterName: characterName,
-		Scopes:        scopes,
-	}, nil
+	return userFromClaims(claims)
 }
 
 // VerifyJWT validates a JWT token and returns user information with expiration time
 func (s *EVEService) VerifyJWT(tokenString string) (*models.AuthenticatedUser, time.Time, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.jwtSecret, nil
-	})
-
+	claims, err := s.parseToken(tokenString)
 	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("failed to parse JWT: %w", err)
+		return nil, time.Time{}, err
 	}
 
-	if !token.Valid {
-		return nil, time.Time{}, errors.New("invalid JWT token")
+	user, err := userFromClaims(claims)
+	if err != nil {
+		return nil, time.Time{}, err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, time.Time{}, errors.New("invalid JWT claims")
+	// Extract expiration time from claims
+	var expiresAt time.Time
+	if exp, ok := numberClaim(claims, "exp"); ok {
+		expiresAt = time.Unix(exp, 0)
 	}
 
-	// Extract user information from claims
-	userID, _ := claims["user_id"].(string)
-	characterIDFloat, _ := claims["character_id"].(float64)
-	characterID := int(characterIDFloat)
-	characterName, _ := claims["character_name"].(string)
-	scopes, _ := claims["scopes"].(string)
+	return user, expiresAt, nil
+}
 
-	// Extract expiration time from claims
-	var expiresAt time.Time
-	if exp, ok := claims["exp"].(float64); ok {
-		expiresAt = time.Unix(int64(exp), 0)
+// issuedAt reads the iat claim, returning the zero time for tokens without one
+func issuedAt(claims jwt.MapClaims) time.Time {
+	if iat, ok := numberClaim(claims, "iat"); ok {
+		return time.Unix(iat, 0)
 	}
+	return time.Time{}
+}
 
-	return &models.AuthenticatedUser{
-		UserID:        userID,
-		CharacterID:   characterID,
-		CharacterName: characterName,
-		Scopes:        scopes,
-	}, expiresAt, nil
+// GenerateJWT creates a JWT token for the authenticated user, including any enabled custom claims.
+// It carries no sign-in time, so it never satisfies step-up confirmation.
+func (s *EVEService) GenerateJWT(ctx context.Context, userID string, characterID int, characterName, scopes string) (string, time.Time, error) {
+	return s.GenerateSessionJWT(ctx, "", userID, characterID, characterName, scopes, time.Time{})
+}
+
+// GenerateSessionJWT creates a JWT token that belongs to a refresh token session. The session ID is
+// issued as the "sid" claim, which is what revoking the session matches; an empty session ID
+// issues a token that can only expire. authTime is the SSO sign-in of the session, issued as the
+// "auth_time" claim when set.
+func (s *EVEService) GenerateSessionJWT(ctx context.Context, sessionID, userID string, characterID int, characterName, scopes string, authTime time.Time) (string, time.Time, error) {
+	expiresAt := time.Now().Add(config.GetCookieDuration())
+	claims := s.userClaims(ctx, sessionID, userID, characterID, characterName, scopes, authTime, expiresAt)
+	return s.signJWT(claims, expiresAt)
 }
 
-// GenerateJWT creates a JWT token for the authenticated user
-func (s *EVEService) GenerateJWT(userID string, characterID int, characterName, scopes string) (string, time.Time, error) {
+// GenerateSudoJWT creates a JWT of the user's session carrying the sudo claim, which lets
+// second-factor protected actions run until sudoUntil
+func (s *EVEService) GenerateSudoJWT(ctx context.Context, user *models.AuthenticatedUser, sudoUntil time.Time) (string, time.Time, error) {
 	expiresAt := time.Now().Add(config.GetCookieDuration())
+	claims := s.userClaims(ctx, user.SessionID, user.UserID, user.CharacterID, user.CharacterName, user.Scopes, user.AuthTime, expiresAt)
+	claims[sudoClaim] = sudoUntil.Unix()
+	return s.signJWT(claims, expiresAt)
+}
 
+// GenerateImpersonationJWT creates a JWT token acting as a user on behalf of an administrator.
+// The administrator is named in the "act" claim so every module can tell the token apart. The
+// target never signed in, so the token carries no "auth_time".
+func (s *EVEService) GenerateImpersonationJWT(ctx context.Context, sessionID string, target *models.UserProfile, impersonator *models.Impersonator, expiresAt time.Time) (string, error) {
+	claims := s.userClaims(ctx, sessionID, target.UserID, target.CharacterID, target.CharacterName, target.Scopes, time.Time{}, expiresAt)
+	claims[actorClaim] = map[string]any{
+		"sub":            impersonator.UserID,
+		"character_id":   impersonator.CharacterID,
+		"character_name": impersonator.CharacterName,
+	}
+	token, _, err := s.signJWT(claims, expiresAt)
+	return token, err
+}
+
+// userClaims builds the claims of a user token, including any enabled custom claims
+func (s *EVEService) userClaims(ctx context.Context, sessionID, userID string, characterID int, characterName, scopes string, authTime, expiresAt time.Time) jwt.MapClaims {
 	claims := jwt.MapClaims{
 		"user_id":        userID,
 		"character_id":   characterID,
@@ -245,9 +288,42 @@ func (s *EVEService) GenerateJWT(userID string, characterID int, characterName,
 		"iat":            time.Now().Unix(),
 		"iss":            "go-falcon",
 	}
+	stampClaimsVersion(claims, userID)
+	s.stampTokenGeneration(ctx, claims, userID)
+	if !authTime.IsZero() {
+		claims[authTimeClaim] = authTime.Unix()
+	}
+	if sessionID != "" {
+		claims[sessionIDClaim] = sessionID
+		claims["jti"] = uuid.New().String()
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.jwtSecret)
+	custom := s.claims.Collect(ctx, ClaimSubject{
+		UserID:        userID,
+		CharacterID:   characterID,
+		CharacterName: characterName,
+		Scopes:        scopes,
+	})
+	for name, value := range custom {
+		claims[name] = value
+	}
+	return claims
+}
+
+// stampTokenGeneration adds the account's token generation to claims, leaving it out for accounts
+// that never logged out everywhere
+func (s *EVEService) stampTokenGeneration(ctx context.Context, claims jwt.MapClaims, userID string) {
+	if s.tokenGeneration == nil {
+		return
+	}
+	if generation := s.tokenGeneration(ctx, userID); generation > 0 {
+		claims[tokenGenerationClaim] = generation
+	}
+}
+
+// signJWT signs claims with the current signing key
+func (s *EVEService) signJWT(claims jwt.MapClaims, expiresAt time.Time) (string, time.Time, error) {
+	tokenString, err := s.sign(claims)
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to sign JWT: %w", err)
 	}
@@ -255,6 +331,45 @@ func (s *EVEService) GenerateJWT(userID string, characterID int, characterName,
 	return tokenString, expiresAt, nil
 }
 
+// GenerateStaffJWT creates a JWT token for a staff account. Staff tokens carry no character and
+// name the identity provider, which keeps them out of character-based permission checks.
+func (s *EVEService) GenerateStaffJWT(account *models.StaffAccount) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(config.GetCookieDuration())
+
+	claims := jwt.MapClaims{
+		"user_id":        account.UserID(),
+		"character_id":   0,
+		"character_name": account.Name,
+		"scopes":         "",
+		"provider":       account.Provider,
+		"exp":            expiresAt.Unix(),
+		"iat":            now.Unix(),
+		authTimeClaim:    now.Unix(),
+		"iss":            "go-falcon",
+	}
+	stampClaimsVersion(claims, account.UserID())
+	s.stampTokenGeneration(context.Background(), claims, account.UserID())
+
+	return s.signJWT(claims, expiresAt)
+}
+
+// sign signs claims with ES256 and the newest active signing key, named in the "kid" header.
+// JWT_SIGNING_ALGORITHM=HS256 signs with the JWT secret instead, as does a replica that cannot
+// load the signing keys; every replica accepts both.
+func (s *EVEService) sign(claims jwt.MapClaims) (string, error) {
+	if config.GetJWTSigningAlgorithm() != jwt.SigningMethodHS256.Alg() {
+		key, err := s.signingKeys.signer()
+		if err == nil {
+			token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+			token.Header["kid"] = key.kid
+			return token.SignedString(key.private)
+		}
+		slog.Warn("No JWT signing key available, signing with the JWT secret", "error", err)
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+}
+
 // RefreshAccessToken refreshes an EVE access token using refresh token
 func (s *EVEService) RefreshAccessToken(ctx context.Context, refreshToken string) (*models.EVETokenResponse, error) {
 	tracer := otel.Tracer("go-falcon/auth")
@@ -299,10 +414,13 @@ func (s *EVEService) RefreshAccessToken(ctx context.Context, refreshToken string
 }
 
 // exchangeCodeForToken exchanges authorization code for access token
-func (s *EVEService) exchangeCodeForToken(ctx context.Context, code string) (*models.EVETokenResponse, error) {
+func (s *EVEService) exchangeCodeForToken(ctx context.Context, code, codeVerifier string) (*models.EVETokenResponse, error) {
 	data := url.Values{}
 	data.Set("grant_type", "authorization_code")
 	data.Set("code", code)
+	if codeVerifier != "" {
+		data.Set("code_verifier", codeVerifier)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", EVETokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
@@ -370,6 +488,21 @@ func (s *EVEService) generateSecureState() (string, error) {
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
 
+// newPKCEVerifier generates a PKCE code verifier: 43 characters of the unreserved URL alphabet
+func newPKCEVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// pkceChallenge derives the S256 code challenge of a PKCE code verifier (RFC 7636)
+func pkceChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
 // CleanupExpiredStates removes expired OAuth states
 func (s *EVEService) CleanupExpiredStates(ctx context.Context) error {
 	return s.repository.CleanupExpiredStates(ctx)
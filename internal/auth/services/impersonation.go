@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/config"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrNestedImpersonation is returned when an impersonation token tries to impersonate again
+	ErrNestedImpersonation = errors.New("cannot impersonate while impersonating")
+	// ErrImpersonationTargetNotFound is returned when the character to act as has no profile
+	ErrImpersonationTargetNotFound = errors.New("character not found")
+	// ErrImpersonateSelf is returned when an administrator targets their own account
+	ErrImpersonateSelf = errors.New("cannot impersonate your own account")
+)
+
+// impersonationSessionPrefix marks the session ID of an impersonation token; logging out with the
+// token revokes it like any other session
+const impersonationSessionPrefix = "impersonation:"
+
+// Impersonate issues a short-lived token acting as the user of a character on behalf of an
+// administrator. The impersonation is recorded before the token is handed out.
+func (s *AuthService) Impersonate(ctx context.Context, admin *models.AuthenticatedUser, characterID int, reason string, client models.SessionClient) (*dto.ImpersonationResponse, error) {
+	if admin.Impersonator != nil {
+		return nil, ErrNestedImpersonation
+	}
+
+	target, err := s.repository.GetUserProfileByCharacterID(ctx, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get character: %w", err)
+	}
+	if target == nil {
+		return nil, ErrImpersonationTargetNotFound
+	}
+	if target.UserID == admin.UserID {
+		return nil, ErrImpersonateSelf
+	}
+
+	now := time.Now()
+	impersonation := &models.Impersonation{
+		ID: primitive.NewObjectID(),
+		Impersonator: models.Impersonator{
+			UserID:        admin.UserID,
+			CharacterID:   admin.CharacterID,
+			CharacterName: admin.CharacterName,
+		},
+		TargetUserID:        target.UserID,
+		TargetCharacterID:   target.CharacterID,
+		TargetCharacterName: target.CharacterName,
+		Reason:              reason,
+		IPAddress:           client.IPAddress,
+		CreatedAt:           now,
+		ExpiresAt:           now.Add(config.GetImpersonationDuration()),
+	}
+	impersonation.SessionID = impersonationSessionPrefix + impersonation.ID.Hex()
+
+	if err := s.repository.CreateImpersonation(ctx, impersonation); err != nil {
+		return nil, fmt.Errorf("failed to record impersonation: %w", err)
+	}
+
+	token, err := s.eveService.GenerateImpersonationJWT(ctx, impersonation.SessionID, target, &impersonation.Impersonator, impersonation.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate impersonation token: %w", err)
+	}
+
+	slog.WarnContext(ctx, "Administrator started impersonation",
+		"impersonation_id", impersonation.ID.Hex(),
+		"impersonator_user_id", admin.UserID,
+		"impersonator_character_id", admin.CharacterID,
+		"target_user_id", target.UserID,
+		"target_character_id", target.CharacterID,
+		"reason", reason)
+	s.recordAuthEvent(ctx, &models.AuthAuditEvent{
+		Event:         models.AuditEventImpersonation,
+		Outcome:       models.AuditOutcomeSuccess,
+		UserID:        admin.UserID,
+		CharacterID:   admin.CharacterID,
+		CharacterName: admin.CharacterName,
+		SessionID:     impersonation.SessionID,
+		Detail:        fmt.Sprintf("acting as %s (character %d, user %s): %s", target.CharacterName, target.CharacterID, target.UserID, reason),
+	}, client)
+
+	return &dto.ImpersonationResponse{
+		ImpersonationID:     impersonation.ID.Hex(),
+		Token:               token,
+		ExpiresAt:           impersonation.ExpiresAt,
+		TargetUserID:        target.UserID,
+		TargetCharacterID:   target.CharacterID,
+		TargetCharacterName: target.CharacterName,
+	}, nil
+}
+
+// ListImpersonations returns the most recent impersonations
+func (s *AuthService) ListImpersonations(ctx context.Context, limit int) ([]dto.ImpersonationRecord, error) {
+	impersonations, err := s.repository.ListImpersonations(ctx, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list impersonations: %w", err)
+	}
+
+	records := make([]dto.ImpersonationRecord, 0, len(impersonations))
+	for _, impersonation := range impersonations {
+		records = append(records, dto.ImpersonationRecord{
+			ID:                  impersonation.ID.Hex(),
+			Impersonator:        impersonatorInfo(&impersonation.Impersonator),
+			TargetUserID:        impersonation.TargetUserID,
+			TargetCharacterID:   impersonation.TargetCharacterID,
+			TargetCharacterName: impersonation.TargetCharacterName,
+			Reason:              impersonation.Reason,
+			IPAddress:           impersonation.IPAddress,
+			CreatedAt:           impersonation.CreatedAt,
+			ExpiresAt:           impersonation.ExpiresAt,
+		})
+	}
+	return records, nil
+}
+
+// impersonatorInfo converts an impersonator for API responses
+func impersonatorInfo(impersonator *models.Impersonator) dto.ImpersonatorInfo {
+	return dto.ImpersonatorInfo{
+		UserID:        impersonator.UserID,
+		CharacterID:   impersonator.CharacterID,
+		CharacterName: impersonator.CharacterName,
+	}
+}
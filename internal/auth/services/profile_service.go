@@ -167,7 +167,7 @@ func (s *ProfileService) RefreshExpiringTokens(ctx context.Context, batchSize in
 	failureCount := 0
 
 	for _, profile := range profiles {
-		if err := s.refreshSingleToken(ctx, profile.CharacterID, profile.RefreshToken); err != nil {
+		if _, err := s.refreshSingleToken(ctx, profile.CharacterID, profile.RefreshToken); err != nil {
 			slog.Error("Failed to refresh token",
 				"character_id", profile.CharacterID,
 				"character_name", profile.CharacterName,
@@ -224,14 +224,14 @@ func (s *ProfileService) enrichProfileWithESI(ctx context.Context, profile *mode
 	return nil
 }
 
-// refreshSingleToken refreshes a single user's access token
-func (s *ProfileService) refreshSingleToken(ctx context.Context, characterID int, refreshToken string) error {
+// refreshSingleToken refreshes a single user's access token and returns the new one
+func (s *ProfileService) refreshSingleToken(ctx context.Context, characterID int, refreshToken string) (string, error) {
 	// Refresh the token
 	tokenResp, err := s.eveService.RefreshAccessToken(ctx, refreshToken)
 	if err != nil {
 		// If refresh fails, mark profile as invalid
 		s.repository.InvalidateProfile(ctx, characterID)
-		return fmt.Errorf("failed to refresh token: %w", err)
+		return "", fmt.Errorf("failed to refresh token: %w", err)
 	}
 
 	// Use actual EVE token expiry from the refresh response
@@ -240,10 +240,59 @@ func (s *ProfileService) refreshSingleToken(ctx context.Context, characterID int
 	// Update tokens in database
 	err = s.repository.UpdateProfileTokens(ctx, characterID, tokenResp.AccessToken, tokenResp.RefreshToken, expiresAt)
 	if err != nil {
-		return fmt.Errorf("failed to update tokens: %w", err)
+		return "", fmt.Errorf("failed to update tokens: %w", err)
 	}
 
-	return nil
+	return tokenResp.AccessToken, nil
+}
+
+// tokenRefreshMargin refreshes stored tokens this close to expiry before handing them out
+const tokenRefreshMargin = time.Minute
+
+// RefreshCharacterToken refreshes a character's access token after ESI rejected staleToken or it
+// expired. When the stored token has already been replaced by another request or replica and is
+// still valid, that token is returned without refreshing again.
+func (s *ProfileService) RefreshCharacterToken(ctx context.Context, characterID int, staleToken string) (string, error) {
+	profile, err := s.repository.GetUserProfileByCharacterID(ctx, characterID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load profile: %w", err)
+	}
+	if profile == nil || profile.RefreshToken == "" {
+		return "", evegateway.ErrNoToken
+	}
+
+	if profile.AccessToken != staleToken && time.Until(profile.TokenExpiry) > tokenRefreshMargin {
+		return profile.AccessToken, nil
+	}
+	return s.refreshSingleToken(ctx, characterID, profile.RefreshToken)
+}
+
+// CharacterToken returns a token provider for a character's stored SSO token, refreshed when
+// it is about to expire
+func (s *ProfileService) CharacterToken(characterID int) evegateway.TokenProvider {
+	return characterToken{profiles: s, characterID: characterID}
+}
+
+// characterToken is a TokenProvider backed by the tokens stored in a character's profile
+type characterToken struct {
+	profiles    *ProfileService
+	characterID int
+}
+
+// AccessToken implements evegateway.TokenProvider
+func (t characterToken) AccessToken(ctx context.Context) (string, error) {
+	profile, err := t.profiles.repository.GetUserProfileByCharacterID(ctx, t.characterID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load profile: %w", err)
+	}
+	if profile == nil || profile.AccessToken == "" {
+		return "", evegateway.ErrNoToken
+	}
+
+	if time.Until(profile.TokenExpiry) < tokenRefreshMargin {
+		return t.profiles.RefreshCharacterToken(ctx, t.characterID, profile.AccessToken)
+	}
+	return profile.AccessToken, nil
 }
 
 // getESICharacterInfo retrieves character information from ESI
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/config"
+)
+
+// IdentityProvider is an external OAuth2 identity provider staff accounts sign in through.
+// EVE SSO is not one of them: it logs in characters and keeps its own flow under /auth/eve.
+type IdentityProvider interface {
+	// Name is the provider key used in routes and stored on staff accounts
+	Name() string
+	// DisplayName is the label shown on the login button
+	DisplayName() string
+	// AuthCodeURL returns the authorization URL the browser is sent to
+	AuthCodeURL(ctx context.Context, state string) (string, error)
+	// Exchange trades an authorization code for the identity it belongs to
+	Exchange(ctx context.Context, code string) (*models.ExternalIdentity, error)
+}
+
+// ProviderRegistry holds the configured staff identity providers
+type ProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]IdentityProvider
+}
+
+// NewProviderRegistry creates a registry with the providers enabled in the environment
+func NewProviderRegistry() *ProviderRegistry {
+	registry := &ProviderRegistry{providers: make(map[string]IdentityProvider)}
+
+	if clientID := config.GetAuthOIDCClientID(); clientID != "" {
+		registry.Register(&oidcProvider{
+			issuer:       strings.TrimSuffix(config.GetAuthOIDCIssuer(), "/"),
+			clientID:     clientID,
+			clientSecret: config.GetAuthOIDCClientSecret(),
+			redirectURI:  config.GetAuthOIDCRedirectURI(),
+			displayName:  config.GetAuthOIDCDisplayName(),
+		})
+	}
+	if clientID := config.GetAuthDiscordClientID(); clientID != "" {
+		registry.Register(&discordProvider{
+			clientID:     clientID,
+			clientSecret: config.GetAuthDiscordClientSecret(),
+			redirectURI:  config.GetAuthDiscordRedirectURI(),
+			scope:        "identify email",
+		})
+	}
+
+	return registry
+}
+
+// Register adds a provider, replacing any provider with the same name
+func (r *ProviderRegistry) Register(provider IdentityProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[provider.Name()] = provider
+}
+
+// Get returns the provider with the given name
+func (r *ProviderRegistry) Get(name string) (IdentityProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// List returns the registered providers ordered by name
+func (r *ProviderRegistry) List() []IdentityProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	providers := make([]IdentityProvider, 0, len(r.providers))
+	for _, provider := range r.providers {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name() < providers[j].Name() })
+	return providers
+}
+
+// oidcProvider signs in against any OpenID Connect issuer. Endpoints come from the issuer's
+// discovery document; the identity is read from the userinfo endpoint with the access token.
+type oidcProvider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURI  string
+	displayName  string
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+}
+
+// oidcDiscovery is the part of the discovery document the provider uses
+type oidcDiscovery struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+func (p *oidcProvider) Name() string        { return models.ProviderOIDC }
+func (p *oidcProvider) DisplayName() string { return p.displayName }
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.clientID)
+	params.Set("redirect_uri", p.redirectURI)
+	params.Set("scope", "openid email profile")
+	params.Set("state", state)
+
+	return discovery.AuthorizationEndpoint + "?" + params.Encode(), nil
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code string) (*models.ExternalIdentity, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := exchangeAuthorizationCode(ctx, discovery.TokenEndpoint, p.clientID, p.clientSecret, p.redirectURI, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var userinfo struct {
+		Subject           string `json:"sub"`
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		Name              string `json:"name"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := getJSON(ctx, discovery.UserinfoEndpoint, accessToken, &userinfo); err != nil {
+		return nil, fmt.Errorf("failed to get OIDC userinfo: %w", err)
+	}
+	if userinfo.Subject == "" {
+		return nil, fmt.Errorf("OIDC userinfo has no subject")
+	}
+
+	name := userinfo.Name
+	if name == "" {
+		name = userinfo.PreferredUsername
+	}
+
+	return &models.ExternalIdentity{
+		Provider:      models.ProviderOIDC,
+		Subject:       userinfo.Subject,
+		Email:         strings.ToLower(userinfo.Email),
+		EmailVerified: userinfo.EmailVerified,
+		Name:          name,
+	}, nil
+}
+
+// discover fetches the discovery document once; failures are retried on the next login
+func (p *oidcProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	if p.issuer == "" {
+		return nil, fmt.Errorf("AUTH_OIDC_ISSUER is not set")
+	}
+
+	var discovery oidcDiscovery
+	if err := getJSON(ctx, p.issuer+"/.well-known/openid-configuration", "", &discovery); err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC endpoints: %w", err)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("OIDC discovery document of %s lacks authorization, token or userinfo endpoint", p.issuer)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+const (
+	discordAuthURL  = "https://discord.com/oauth2/authorize"
+	discordTokenURL = "https://discord.com/api/oauth2/token"
+	discordUserURL  = "https://discord.com/api/users/@me"
+)
+
+// discordProvider signs in with a Discord account
+type discordProvider struct {
+	clientID     string
+	clientSecret string
+	redirectURI  string
+	scope        string
+}
+
+func (p *discordProvider) Name() string        { return models.ProviderDiscord }
+func (p *discordProvider) DisplayName() string { return "Discord" }
+
+func (p *discordProvider) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.clientID)
+	params.Set("redirect_uri", p.redirectURI)
+	params.Set("scope", p.scope)
+	params.Set("state", state)
+
+	return discordAuthURL + "?" + params.Encode(), nil
+}
+
+func (p *discordProvider) Exchange(ctx context.Context, code string) (*models.ExternalIdentity, error) {
+	accessToken, err := exchangeAuthorizationCode(ctx, discordTokenURL, p.clientID, p.clientSecret, p.redirectURI, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		ID         string `json:"id"`
+		Username   string `json:"username"`
+		GlobalName string `json:"global_name"`
+		Email      string `json:"email"`
+		Verified   bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, discordUserURL, accessToken, &user); err != nil {
+		return nil, fmt.Errorf("failed to get Discord user: %w", err)
+	}
+
+	name := user.GlobalName
+	if name == "" {
+		name = user.Username
+	}
+
+	return &models.ExternalIdentity{
+		Provider:      models.ProviderDiscord,
+		Subject:       user.ID,
+		Email:         strings.ToLower(user.Email),
+		EmailVerified: user.Verified,
+		Name:          name,
+	}, nil
+}
+
+// newDiscordLinkProvider returns the Discord client of the account linking flow, or nil when the
+// Discord application is not configured
+func newDiscordLinkProvider() *discordProvider {
+	clientID := config.GetDiscordClientID()
+	if clientID == "" {
+		return nil
+	}
+	return &discordProvider{
+		clientID:     clientID,
+		clientSecret: config.GetDiscordClientSecret(),
+		redirectURI:  config.GetAuthDiscordLinkRedirectURI(),
+		scope:        "identify",
+	}
+}
+
+// exchangeAuthorizationCode runs the OAuth2 authorization code grant and returns the access token
+func exchangeAuthorizationCode(ctx context.Context, tokenURL, clientID, clientSecret, redirectURI, code string) (string, error) {
+	data := url.Values{}
+	data.Set("grant_type", "authorization_code")
+	data.Set("code", code)
+	data.Set("redirect_uri", redirectURI)
+	data.Set("client_id", clientID)
+	data.Set("client_secret", clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("token exchange failed: %s - %s", resp.Status, string(body))
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("token exchange returned no access token")
+	}
+
+	return tokenResp.AccessToken, nil
+}
+
+// getJSON fetches a JSON document, authenticating with the bearer token when one is given
+func getJSON(ctx context.Context, endpoint, bearerToken string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s - %s", resp.Status, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
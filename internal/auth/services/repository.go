@@ -2,12 +2,18 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"go-falcon/internal/auth/models"
 	"go-falcon/pkg/database"
 
+	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.opentelemetry.io/otel"
@@ -17,12 +23,14 @@ import (
 // Repository handles database operations for auth module
 type Repository struct {
 	mongodb *database.MongoDB
+	redis   *database.Redis // Holds OAuth login states; nil keeps them in MongoDB
 }
 
 // NewRepository creates a new auth repository
-func NewRepository(mongodb *database.MongoDB) *Repository {
+func NewRepository(mongodb *database.MongoDB, redis *database.Redis) *Repository {
 	return &Repository{
 		mongodb: mongodb,
+		redis:   redis,
 	}
 }
 
@@ -86,11 +94,19 @@ func (r *Repository) CreateOrUpdateUserProfile(ctx context.Context, profile *mod
 		"updated_at":           profile.UpdatedAt,
 	}
 
+	setOnInsert := bson.M{"created_at": now}
+	if isNewCharacter {
+		// A character joining an account shares the account's Discord link
+		var linked models.UserProfile
+		linkFilter := bson.M{"user_id": profile.UserID, "discord": bson.M{"$exists": true}}
+		if err := collection.FindOne(ctx, linkFilter).Decode(&linked); err == nil {
+			setOnInsert["discord"] = linked.Discord
+		}
+	}
+
 	update := bson.M{
-		"$set": updateFields,
-		"$setOnInsert": bson.M{
-			"created_at": now,
-		},
+		"$set":         updateFields,
+		"$setOnInsert": setOnInsert,
 	}
 
 	opts := options.Update().SetUpsert(true)
@@ -329,30 +345,127 @@ func (r *Repository) InvalidateProfile(ctx context.Context, characterID int) err
 	return nil
 }
 
-// StoreLoginState stores OAuth login state
-func (r *Repository) StoreLoginState(ctx context.Context, state *models.EVELoginState) error {
-	collection := r.mongodb.Collection("auth_states")
+// ReassignCharacter moves a character to another user account at the given position
+func (r *Repository) ReassignCharacter(ctx context.Context, characterID int, userID string, position int) error {
+	tracer := otel.Tracer("go-falcon/auth")
+	ctx, span := tracer.Start(ctx, "auth.repository.reassign_character")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("service", "auth"),
+		attribute.String("operation", "reassign_character"),
+		attribute.Int("character_id", characterID),
+	)
+
+	collection := r.mongodb.Collection("user_profiles")
+
+	// The Discord link belongs to the account the character leaves
+	filter := bson.M{"character_id": characterID}
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":    userID,
+			"position":   position,
+			"updated_at": time.Now(),
+		},
+		"$unset": bson.M{"discord": ""},
+	}
+
+	if _, err := collection.UpdateOne(ctx, filter, update); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetDiscordLinkOwner returns the user a Discord account is linked to, or "" when it is not linked
+func (r *Repository) GetDiscordLinkOwner(ctx context.Context, discordUserID string) (string, error) {
+	collection := r.mongodb.Collection("user_profiles")
+
+	var profile models.UserProfile
+	opts := options.FindOne().SetProjection(bson.M{"user_id": 1})
+	err := collection.FindOne(ctx, bson.M{"discord.user_id": discordUserID}, opts).Decode(&profile)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return profile.UserID, nil
+}
+
+// SetDiscordLink stores the Discord account of a user on all of its characters; nil removes it
+func (r *Repository) SetDiscordLink(ctx context.Context, userID string, link *models.DiscordLink) error {
+	collection := r.mongodb.Collection("user_profiles")
+
+	update := bson.M{"$unset": bson.M{"discord": ""}}
+	if link != nil {
+		update = bson.M{"$set": bson.M{"discord": link, "updated_at": time.Now()}}
+	}
+	_, err := collection.UpdateMany(ctx, bson.M{"user_id": userID}, update)
+	return err
+}
 
+const (
+	// loginStateKeyPrefix prefixes the Redis keys of OAuth login states
+	loginStateKeyPrefix = "auth:login_state:"
+	// loginStateTTL is how long a sign-in may take from the redirect to the callback
+	loginStateTTL = 15 * time.Minute
+)
+
+// StoreLoginState stores an OAuth login state for loginStateTTL. States are kept in Redis so the
+// callback succeeds on any gateway instance and across restarts; MongoDB holds them when Redis is
+// not configured or fails.
+func (r *Repository) StoreLoginState(ctx context.Context, state *models.EVELoginState) error {
 	state.CreatedAt = time.Now()
-	state.ExpiresAt = state.CreatedAt.Add(15 * time.Minute) // States expire in 15 minutes
+	state.ExpiresAt = state.CreatedAt.Add(loginStateTTL)
 
+	if r.redis != nil {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		err = r.redis.Client.Set(ctx, loginStateKeyPrefix+state.State, data, loginStateTTL).Err()
+		if err == nil {
+			return nil
+		}
+		slog.WarnContext(ctx, "Failed to store login state in Redis, storing it in MongoDB", "error", err)
+	}
+
+	collection := r.mongodb.Collection("auth_states")
 	_, err := collection.InsertOne(ctx, state)
 	return err
 }
 
-// GetLoginState retrieves and validates OAuth login state
-func (r *Repository) GetLoginState(ctx context.Context, state string) (*models.EVELoginState, error) {
+// ConsumeLoginState returns an OAuth login state and removes it in the same step, so a state
+// completes at most one callback. It returns nil when the state is unknown, expired or used.
+func (r *Repository) ConsumeLoginState(ctx context.Context, state string) (*models.EVELoginState, error) {
+	if r.redis != nil {
+		data, err := r.redis.Client.GetDel(ctx, loginStateKeyPrefix+state).Bytes()
+		switch {
+		case err == nil:
+			var loginState models.EVELoginState
+			if err := json.Unmarshal(data, &loginState); err != nil {
+				return nil, fmt.Errorf("malformed login state: %w", err)
+			}
+			return &loginState, nil
+		case errors.Is(err, redis.Nil):
+			// The state may have been stored in MongoDB during a Redis outage
+		default:
+			slog.WarnContext(ctx, "Failed to read login state from Redis, trying MongoDB", "error", err)
+		}
+	}
+
 	collection := r.mongodb.Collection("auth_states")
 
 	var loginState models.EVELoginState
-	err := collection.FindOne(ctx, bson.M{
+	err := collection.FindOneAndDelete(ctx, bson.M{
 		"state":      state,
 		"expires_at": bson.M{"$gt": time.Now()},
 	}).Decode(&loginState)
-
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, nil // State not found or expired
+			return nil, nil // State not found, expired or already used
 		}
 		return nil, err
 	}
@@ -360,7 +473,7 @@ func (r *Repository) GetLoginState(ctx context.Context, state string) (*models.E
 	return &loginState, nil
 }
 
-// CleanupExpiredStates removes expired OAuth states
+// CleanupExpiredStates removes expired OAuth states stored in MongoDB; Redis expires its own
 func (r *Repository) CleanupExpiredStates(ctx context.Context) error {
 	collection := r.mongodb.Collection("auth_states")
 
@@ -369,8 +482,542 @@ func (r *Repository) CleanupExpiredStates(ctx context.Context) error {
 	return err
 }
 
+// refreshTokenLive matches refresh tokens that have been neither rotated nor revoked
+func refreshTokenLive() bson.M {
+	return bson.M{
+		"rotated_at": bson.M{"$exists": false},
+		"revoked_at": bson.M{"$exists": false},
+	}
+}
+
+// StoreRefreshToken stores a new link of a session's refresh token chain
+func (r *Repository) StoreRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	collection := r.mongodb.Collection("auth_refresh_tokens")
+
+	_, err := collection.InsertOne(ctx, token)
+	return err
+}
+
+// GetRefreshToken returns a refresh token by hash in any state, or nil when it does not exist
+func (r *Repository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	collection := r.mongodb.Collection("auth_refresh_tokens")
+
+	var token models.RefreshToken
+	err := collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken marks a live, unexpired refresh token rotated and returns it. It returns nil
+// when the token is unknown, expired, or was already rotated or revoked, so of two concurrent
+// refreshes with the same token only one succeeds.
+func (r *Repository) RotateRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	collection := r.mongodb.Collection("auth_refresh_tokens")
+
+	filter := refreshTokenLive()
+	filter["token_hash"] = tokenHash
+	filter["expires_at"] = bson.M{"$gt": time.Now()}
+
+	var token models.RefreshToken
+	err := collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"rotated_at": time.Now()}}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeRefreshSession revokes a session and every refresh token of it
+func (r *Repository) RevokeRefreshSession(ctx context.Context, sessionID string) error {
+	filter := bson.M{"session_id": sessionID, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	if _, err := r.mongodb.Collection("auth_refresh_tokens").UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+	_, err := r.mongodb.Collection("auth_sessions").UpdateOne(ctx, filter, update)
+	return err
+}
+
+// SwitchRefreshSessionCharacter makes the live refresh token of a session issue tokens for another character
+func (r *Repository) SwitchRefreshSessionCharacter(ctx context.Context, sessionID string, characterID int, characterName, scopes string) error {
+	collection := r.mongodb.Collection("auth_refresh_tokens")
+
+	filter := refreshTokenLive()
+	filter["session_id"] = sessionID
+	update := bson.M{
+		"$set": bson.M{
+			"character_id":   characterID,
+			"character_name": characterName,
+			"scopes":         scopes,
+		},
+	}
+	if _, err := collection.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+
+	_, err := r.mongodb.Collection("auth_sessions").UpdateOne(ctx,
+		bson.M{"session_id": sessionID},
+		bson.M{"$set": bson.M{"character_id": characterID, "character_name": characterName}},
+	)
+	return err
+}
+
+// SaveSession records a login or refresh of a session, creating the session on its first login
+func (r *Repository) SaveSession(ctx context.Context, session *models.Session) error {
+	collection := r.mongodb.Collection("auth_sessions")
+
+	update := bson.M{
+		"$set": bson.M{
+			"character_id":   session.CharacterID,
+			"character_name": session.CharacterName,
+			"user_agent":     session.UserAgent,
+			"ip_address":     session.IPAddress,
+			"last_seen_at":   session.LastSeenAt,
+			"expires_at":     session.ExpiresAt,
+		},
+		"$setOnInsert": bson.M{
+			"user_id":    session.UserID,
+			"created_at": session.CreatedAt,
+		},
+	}
+	_, err := collection.UpdateOne(ctx, bson.M{"session_id": session.SessionID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// TouchSession moves the last seen time of a session forward
+func (r *Repository) TouchSession(ctx context.Context, sessionID string, seenAt time.Time) error {
+	collection := r.mongodb.Collection("auth_sessions")
+
+	_, err := collection.UpdateOne(ctx, bson.M{"session_id": sessionID}, bson.M{"$max": bson.M{"last_seen_at": seenAt}})
+	return err
+}
+
+// GetSession returns a session by ID, or nil when it does not exist
+func (r *Repository) GetSession(ctx context.Context, sessionID string) (*models.Session, error) {
+	collection := r.mongodb.Collection("auth_sessions")
+
+	var session models.Session
+	err := collection.FindOne(ctx, bson.M{"session_id": sessionID}).Decode(&session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveSessions returns the unrevoked, unexpired sessions of a user, most recently seen first
+func (r *Repository) ListActiveSessions(ctx context.Context, userID string) ([]models.Session, error) {
+	collection := r.mongodb.Collection("auth_sessions")
+
+	filter := bson.M{
+		"user_id":    userID,
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []models.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// CreateImpersonation records an impersonation
+func (r *Repository) CreateImpersonation(ctx context.Context, impersonation *models.Impersonation) error {
+	collection := r.mongodb.Collection("auth_impersonations")
+
+	_, err := collection.InsertOne(ctx, impersonation)
+	return err
+}
+
+// ListImpersonations returns the most recent impersonations, newest first
+func (r *Repository) ListImpersonations(ctx context.Context, limit int64) ([]models.Impersonation, error) {
+	collection := r.mongodb.Collection("auth_impersonations")
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	impersonations := []models.Impersonation{}
+	if err := cursor.All(ctx, &impersonations); err != nil {
+		return nil, err
+	}
+	return impersonations, nil
+}
+
+// InsertAuditEvent appends an event to the auth audit log
+func (r *Repository) InsertAuditEvent(ctx context.Context, event *models.AuthAuditEvent) error {
+	collection := r.mongodb.Collection("auth_audit")
+
+	_, err := collection.InsertOne(ctx, event)
+	return err
+}
+
+// AnonymizeAuditEvents strips the character and client details from the audit events of a user
+// account and returns how many were changed
+func (r *Repository) AnonymizeAuditEvents(ctx context.Context, userID string) (int64, error) {
+	collection := r.mongodb.Collection("auth_audit")
+
+	result, err := collection.UpdateMany(ctx, bson.M{"user_id": userID}, bson.M{
+		"$unset": bson.M{
+			"character_id":   "",
+			"character_name": "",
+			"scopes":         "",
+			"detail":         "",
+			"ip_address":     "",
+			"user_agent":     "",
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ListAuditEvents returns a page of auth audit events matching the filter, newest first, and how
+// many match in total
+func (r *Repository) ListAuditEvents(ctx context.Context, filter models.AuthAuditFilter, page, limit int) ([]models.AuthAuditEvent, int64, error) {
+	collection := r.mongodb.Collection("auth_audit")
+
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	if filter.CharacterID != 0 {
+		query["character_id"] = filter.CharacterID
+	}
+	if filter.Event != "" {
+		query["event"] = filter.Event
+	}
+	if filter.Outcome != "" {
+		query["outcome"] = filter.Outcome
+	}
+	if filter.IPAddress != "" {
+		query["ip_address"] = filter.IPAddress
+	}
+	timestamp := bson.M{}
+	if !filter.From.IsZero() {
+		timestamp["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timestamp["$lt"] = filter.To
+	}
+	if len(timestamp) > 0 {
+		query["timestamp"] = timestamp
+	}
+
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.AuthAuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// InsertDeviceAuthorization stores a new device authorization. It fails with a duplicate key
+// error when its user code is already in use.
+func (r *Repository) InsertDeviceAuthorization(ctx context.Context, authorization *models.DeviceAuthorization) error {
+	collection := r.mongodb.Collection("auth_device_codes")
+
+	result, err := collection.InsertOne(ctx, authorization)
+	if err != nil {
+		return err
+	}
+	authorization.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetDeviceAuthorizationByUserCode returns a device authorization by its normalized user code, or
+// nil when it does not exist
+func (r *Repository) GetDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (*models.DeviceAuthorization, error) {
+	collection := r.mongodb.Collection("auth_device_codes")
+
+	var authorization models.DeviceAuthorization
+	err := collection.FindOne(ctx, bson.M{"user_code": userCode}).Decode(&authorization)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &authorization, nil
+}
+
+// DecideDeviceAuthorization approves or denies a pending, unexpired device authorization on
+// behalf of a user. It reports false when there was no such authorization.
+func (r *Repository) DecideDeviceAuthorization(ctx context.Context, userCode, status string, user *models.AuthenticatedUser) (bool, error) {
+	collection := r.mongodb.Collection("auth_device_codes")
+
+	filter := bson.M{
+		"user_code":  userCode,
+		"status":     models.DeviceAuthorizationPending,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":         status,
+		"user_id":        user.UserID,
+		"character_id":   user.CharacterID,
+		"character_name": user.CharacterName,
+		"scopes":         user.Scopes,
+	}}
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// PollDeviceAuthorization records a poll of a device code and returns the authorization as it was
+// before, so the previous poll time can be checked; nil when the device code is unknown
+func (r *Repository) PollDeviceAuthorization(ctx context.Context, deviceCodeHash string) (*models.DeviceAuthorization, error) {
+	collection := r.mongodb.Collection("auth_device_codes")
+
+	var authorization models.DeviceAuthorization
+	update := bson.M{"$set": bson.M{"last_polled_at": time.Now()}}
+	err := collection.FindOneAndUpdate(ctx, bson.M{"device_code_hash": deviceCodeHash}, update).Decode(&authorization)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &authorization, nil
+}
+
+// ClaimDeviceAuthorization removes a device authorization in the given status and returns it, so
+// of two concurrent polls only one receives tokens; nil when there was no such authorization
+func (r *Repository) ClaimDeviceAuthorization(ctx context.Context, deviceCodeHash, status string) (*models.DeviceAuthorization, error) {
+	collection := r.mongodb.Collection("auth_device_codes")
+
+	var authorization models.DeviceAuthorization
+	err := collection.FindOneAndDelete(ctx, bson.M{"device_code_hash": deviceCodeHash, "status": status}).Decode(&authorization)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &authorization, nil
+}
+
+// GetTOTPEnrollment returns the authenticator enrollment of a user account, or nil
+func (r *Repository) GetTOTPEnrollment(ctx context.Context, userID string) (*models.TOTPEnrollment, error) {
+	collection := r.mongodb.Collection("auth_totp")
+
+	var enrollment models.TOTPEnrollment
+	err := collection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&enrollment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// ReplacePendingTOTPEnrollment stores a new unconfirmed enrollment in place of an unconfirmed one.
+// A confirmed enrollment is never replaced: the upsert then fails with a duplicate key error.
+func (r *Repository) ReplacePendingTOTPEnrollment(ctx context.Context, enrollment *models.TOTPEnrollment) error {
+	collection := r.mongodb.Collection("auth_totp")
+
+	_, err := collection.ReplaceOne(ctx,
+		bson.M{"user_id": enrollment.UserID, "confirmed": false},
+		enrollment,
+		options.Replace().SetUpsert(true))
+	return err
+}
+
+// UseTOTPStep records the time step of an accepted code, confirming the enrollment. It reports
+// false when the step, or a later one, was already used.
+func (r *Repository) UseTOTPStep(ctx context.Context, userID string, step int64, now time.Time) (bool, error) {
+	collection := r.mongodb.Collection("auth_totp")
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"user_id": userID, "last_used_step": bson.M{"$lt": step}},
+		[]bson.M{{"$set": bson.M{
+			"last_used_step": step,
+			"last_used_at":   now,
+			"confirmed_at":   bson.M{"$ifNull": bson.A{"$confirmed_at", now}},
+			"confirmed":      true,
+		}}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// DeleteTOTPEnrollment removes the authenticator enrollment of a user account
+func (r *Repository) DeleteTOTPEnrollment(ctx context.Context, userID string) error {
+	collection := r.mongodb.Collection("auth_totp")
+
+	_, err := collection.DeleteOne(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+// ListSigningKeys returns the JWT signing keys that have not expired, oldest generation first
+func (r *Repository) ListSigningKeys(ctx context.Context) ([]models.SigningKey, error) {
+	collection := r.mongodb.Collection("auth_signing_keys")
+
+	filter := bson.M{"$or": []bson.M{
+		{"expires_at": bson.M{"$exists": false}},
+		{"expires_at": bson.M{"$gt": time.Now()}},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "generation", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := []models.SigningKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// InsertSigningKey stores a new signing key. It fails with a duplicate key error when another
+// replica already stored a key of the same generation.
+func (r *Repository) InsertSigningKey(ctx context.Context, key *models.SigningKey) error {
+	collection := r.mongodb.Collection("auth_signing_keys")
+
+	result, err := collection.InsertOne(ctx, key)
+	if err != nil {
+		return err
+	}
+	key.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// ExpireSigningKeys sets when superseded signing keys (generations below generation) stop
+// verifying; keys that already have an expiry keep it
+func (r *Repository) ExpireSigningKeys(ctx context.Context, generation int, expiresAt time.Time) error {
+	collection := r.mongodb.Collection("auth_signing_keys")
+
+	filter := bson.M{
+		"generation": bson.M{"$lt": generation},
+		"expires_at": bson.M{"$exists": false},
+	}
+	_, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"expires_at": expiresAt}})
+	return err
+}
+
 // CheckHealth verifies database connectivity
 func (r *Repository) CheckHealth(ctx context.Context) error {
 	// Perform a simple ping to check database connectivity
 	return r.mongodb.Client.Ping(ctx, nil)
 }
+
+// CreateStaffAccount stores a new staff account
+func (r *Repository) CreateStaffAccount(ctx context.Context, account *models.StaffAccount) error {
+	collection := r.mongodb.Collection("staff_accounts")
+
+	now := time.Now()
+	account.CreatedAt = now
+	account.UpdatedAt = now
+
+	result, err := collection.InsertOne(ctx, account)
+	if err != nil {
+		return err
+	}
+	account.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetStaffAccount retrieves a staff account by ID
+func (r *Repository) GetStaffAccount(ctx context.Context, id primitive.ObjectID) (*models.StaffAccount, error) {
+	return r.findStaffAccount(ctx, bson.M{"_id": id})
+}
+
+// GetStaffAccountBySubject retrieves the staff account bound to a provider user
+func (r *Repository) GetStaffAccountBySubject(ctx context.Context, provider, subject string) (*models.StaffAccount, error) {
+	return r.findStaffAccount(ctx, bson.M{"provider": provider, "subject": subject})
+}
+
+// GetStaffAccountByEmail retrieves the staff account provisioned for a provider email
+func (r *Repository) GetStaffAccountByEmail(ctx context.Context, provider, email string) (*models.StaffAccount, error) {
+	return r.findStaffAccount(ctx, bson.M{"provider": provider, "email": email})
+}
+
+// ListStaffAccounts returns all staff accounts ordered by name
+func (r *Repository) ListStaffAccounts(ctx context.Context) ([]models.StaffAccount, error) {
+	collection := r.mongodb.Collection("staff_accounts")
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var accounts []models.StaffAccount
+	if err := cursor.All(ctx, &accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// UpdateStaffAccount applies a $set update to a staff account
+func (r *Repository) UpdateStaffAccount(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	collection := r.mongodb.Collection("staff_accounts")
+
+	update["updated_at"] = time.Now()
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	return err
+}
+
+// DeleteStaffAccount removes a staff account and reports whether it existed
+func (r *Repository) DeleteStaffAccount(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	collection := r.mongodb.Collection("staff_accounts")
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// findStaffAccount returns the staff account matching the filter, or nil
+func (r *Repository) findStaffAccount(ctx context.Context, filter bson.M) (*models.StaffAccount, error) {
+	collection := r.mongodb.Collection("staff_accounts")
+
+	var account models.StaffAccount
+	if err := collection.FindOne(ctx, filter).Decode(&account); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"go-falcon/internal/auth/dto"
+)
+
+// Built-in scope sets; site settings can override them
+const (
+	// ScopeSetBasic requests no scopes (plain login)
+	ScopeSetBasic = "basic"
+	// ScopeSetFull requests EVE_SCOPES (registration)
+	ScopeSetFull = "full"
+)
+
+// ErrUnknownScopeSet is returned for a scope set that is not configured
+var ErrUnknownScopeSet = errors.New("unknown scope set")
+
+// ScopeSetSource reads the named EVE SSO scope sets configured in site settings
+type ScopeSetSource interface {
+	EVEScopeSets(ctx context.Context) (map[string]string, error)
+}
+
+// SetScopeSetSource sets where named scope sets are read from
+func (s *AuthService) SetScopeSetSource(source ScopeSetSource) {
+	s.scopeSets = source
+}
+
+// ScopeSets returns the available scope sets by name: the built-in basic and full sets and the
+// sets configured in site settings, which take precedence
+func (s *AuthService) ScopeSets(ctx context.Context) map[string]string {
+	sets := map[string]string{
+		ScopeSetBasic: "",
+		ScopeSetFull:  s.eveService.DefaultScopes(),
+	}
+	if s.scopeSets == nil {
+		return sets
+	}
+
+	configured, err := s.scopeSets.EVEScopeSets(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read EVE scope sets from site settings", "error", err)
+		return sets
+	}
+	for name, scopes := range configured {
+		sets[name] = scopes
+	}
+	return sets
+}
+
+// ListScopeSets returns the available scope sets ordered by name
+func (s *AuthService) ListScopeSets(ctx context.Context) *dto.ScopeSetsResponse {
+	response := &dto.ScopeSetsResponse{Sets: []dto.ScopeSet{}}
+	for name, scopes := range s.ScopeSets(ctx) {
+		response.Sets = append(response.Sets, dto.ScopeSet{Name: name, Scopes: strings.Fields(scopes)})
+	}
+	sort.Slice(response.Sets, func(i, j int) bool { return response.Sets[i].Name < response.Sets[j].Name })
+	return response
+}
+
+// InitiateEVELoginWithScopeSet initiates EVE SSO login requesting the scopes of a named set
+func (s *AuthService) InitiateEVELoginWithScopeSet(ctx context.Context, setName, userID string) (*dto.EVELoginResponse, error) {
+	scopes, ok := s.ScopeSets(ctx)[setName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownScopeSet, setName)
+	}
+
+	authURL, state, err := s.eveService.GenerateAuthURLWithScopes(ctx, scopes, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate auth URL: %w", err)
+	}
+
+	return &dto.EVELoginResponse{
+		AuthURL: authURL,
+		State:   state,
+	}, nil
+}
@@ -0,0 +1,466 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/config"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrInvalidRefreshToken is returned for a refresh token that is unknown or expired
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again;
+	// the whole session has been revoked
+	ErrRefreshTokenReused = errors.New("refresh token was already used; the session has been revoked")
+	// ErrSessionRevoked is returned when validating a token of a revoked session
+	ErrSessionRevoked = errors.New("session has been revoked")
+	// ErrSessionNotFound is returned for a session that does not belong to the caller's account
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrCharacterDeleted is returned when starting a session for a soft-deleted character
+	ErrCharacterDeleted = errors.New("character has been deleted")
+)
+
+const (
+	// revokedSessionKeyPrefix prefixes the Redis keys of the session revocation list
+	revokedSessionKeyPrefix = "auth:revoked_session:"
+	// tokenGenerationKeyPrefix prefixes the Redis keys of the per-account token generation counters
+	tokenGenerationKeyPrefix = "auth:token_generation:"
+	// seenSessionKeyPrefix prefixes the Redis keys that throttle last seen updates
+	seenSessionKeyPrefix = "auth:seen_session:"
+)
+
+// revocationCheckTimeout bounds the Redis lookup made for every authenticated request
+const revocationCheckTimeout = 500 * time.Millisecond
+
+// sessionSeenInterval is how stale a session's last seen time may get while it is in use
+const sessionSeenInterval = 5 * time.Minute
+
+// startSession starts a refresh token session and issues its first tokens. Soft-deleted characters
+// cannot start one, whichever way they signed in. authTime is the SSO sign-in that started the
+// session, zero when it was not started by one; every token of the session carries it.
+func (s *AuthService) startSession(ctx context.Context, client models.SessionClient, authTime time.Time, userID string, characterID int, characterName, scopes string) (*dto.TokenResponse, error) {
+	profile, err := s.repository.GetUserProfileByCharacterID(ctx, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get character: %w", err)
+	}
+	if profile != nil && profile.DeletedAt != nil {
+		return nil, ErrCharacterDeleted
+	}
+	return s.issueSessionTokens(ctx, uuid.New().String(), client, authTime, userID, characterID, characterName, scopes)
+}
+
+// issueSessionTokens issues a JWT of the session and the next refresh token of its chain, and
+// records the client the session was last used from
+func (s *AuthService) issueSessionTokens(ctx context.Context, sessionID string, client models.SessionClient, authTime time.Time, userID string, characterID int, characterName, scopes string) (*dto.TokenResponse, error) {
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	refreshExpiresAt := now.Add(config.GetRefreshTokenDuration())
+	err = s.repository.StoreRefreshToken(ctx, &models.RefreshToken{
+		TokenHash:     hashRefreshToken(refreshToken),
+		SessionID:     sessionID,
+		UserID:        userID,
+		CharacterID:   characterID,
+		CharacterName: characterName,
+		Scopes:        scopes,
+		AuthTime:      authTime,
+		CreatedAt:     now,
+		ExpiresAt:     refreshExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	err = s.repository.SaveSession(ctx, &models.Session{
+		SessionID:     sessionID,
+		UserID:        userID,
+		CharacterID:   characterID,
+		CharacterName: characterName,
+		UserAgent:     client.UserAgent,
+		IPAddress:     client.IPAddress,
+		CreatedAt:     now,
+		LastSeenAt:    now,
+		ExpiresAt:     refreshExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+
+	jwtToken, expiresAt, err := s.eveService.GenerateSessionJWT(ctx, sessionID, userID, characterID, characterName, scopes, authTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+	}
+
+	return &dto.TokenResponse{
+		Token:            jwtToken,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: &refreshExpiresAt,
+		SessionID:        sessionID,
+	}, nil
+}
+
+// RefreshSession rotates a refresh token: it is marked used and a new JWT and refresh token of
+// the same session are issued. Presenting a token that was already rotated means it leaked (or a
+// client replayed it), so the whole session is revoked. Every attempt is recorded in the audit log.
+func (s *AuthService) RefreshSession(ctx context.Context, refreshToken string, client models.SessionClient) (*dto.TokenResponse, error) {
+	tokenResp, token, err := s.refreshSession(ctx, refreshToken, client)
+
+	event := &models.AuthAuditEvent{
+		Event:   models.AuditEventTokenRefresh,
+		Outcome: models.AuditOutcomeSuccess,
+	}
+	if token != nil {
+		event.UserID = token.UserID
+		event.CharacterID = token.CharacterID
+		event.CharacterName = token.CharacterName
+		event.SessionID = token.SessionID
+	}
+	if err != nil {
+		event.Outcome = models.AuditOutcomeFailure
+		event.Detail = err.Error()
+	}
+	s.recordAuthEvent(ctx, event, client)
+
+	return tokenResp, err
+}
+
+// refreshSession rotates a refresh token and returns the new tokens along with the presented
+// token, when it is known
+func (s *AuthService) refreshSession(ctx context.Context, refreshToken string, client models.SessionClient) (*dto.TokenResponse, *models.RefreshToken, error) {
+	if refreshToken == "" {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	tokenHash := hashRefreshToken(refreshToken)
+
+	current, err := s.repository.RotateRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if current == nil {
+		return s.rejectRefreshToken(ctx, tokenHash)
+	}
+
+	// Issue for the character as it is now, so scope changes apply and unlinked or deleted characters drop out
+	profile, err := s.repository.GetUserProfileByCharacterID(ctx, current.CharacterID)
+	if err != nil {
+		return nil, current, fmt.Errorf("failed to get character: %w", err)
+	}
+	if profile == nil || profile.UserID != current.UserID || profile.DeletedAt != nil {
+		if err := s.RevokeSession(ctx, current.SessionID); err != nil {
+			slog.ErrorContext(ctx, "Failed to revoke session of an unlinked character", "session_id", current.SessionID, "error", err)
+		}
+		return nil, current, ErrInvalidRefreshToken
+	}
+
+	// The rotated tokens keep the session's sign-in time; refreshing is not signing in again
+	tokenResp, err := s.issueSessionTokens(ctx, current.SessionID, client, current.AuthTime, profile.UserID, profile.CharacterID, profile.CharacterName, profile.Scopes)
+	return tokenResp, current, err
+}
+
+// rejectRefreshToken returns why a refresh token cannot be rotated, revoking its session when
+// the token was rotated before
+func (s *AuthService) rejectRefreshToken(ctx context.Context, tokenHash string) (*dto.TokenResponse, *models.RefreshToken, error) {
+	token, err := s.repository.GetRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if token == nil || (token.RotatedAt == nil && token.RevokedAt == nil) {
+		return nil, token, ErrInvalidRefreshToken
+	}
+
+	if token.RevokedAt == nil {
+		slog.WarnContext(ctx, "Refresh token reused, revoking session",
+			"session_id", token.SessionID, "user_id", token.UserID, "character_id", token.CharacterID)
+	}
+	if err := s.RevokeSession(ctx, token.SessionID); err != nil {
+		return nil, token, fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil, token, ErrRefreshTokenReused
+}
+
+// RevokeSession revokes a session: its refresh tokens stop working and its JWTs are put on the
+// revocation list until the longest-lived of them has expired. Impersonation sessions can be
+// configured to outlive session JWTs, so the entry lasts for the longer of the two.
+func (s *AuthService) RevokeSession(ctx context.Context, sessionID string) error {
+	if err := s.repository.RevokeRefreshSession(ctx, sessionID); err != nil {
+		return err
+	}
+	if s.redis == nil {
+		return nil
+	}
+	ttl := max(config.GetCookieDuration(), config.GetImpersonationDuration()) + tokenLeeway
+	return s.redis.Set(ctx, revokedSessionKeyPrefix+sessionID, "1", ttl)
+}
+
+// EndSession revokes the session of a logout and records it in the audit log. The session comes
+// from the caller's JWT (user may be nil) or, once that has expired, from the refresh token; tokens
+// without a session have nothing to revoke.
+func (s *AuthService) EndSession(ctx context.Context, user *models.AuthenticatedUser, refreshToken string, client models.SessionClient) error {
+	event := &models.AuthAuditEvent{
+		Event:   models.AuditEventLogout,
+		Outcome: models.AuditOutcomeSuccess,
+	}
+	if user != nil {
+		event.UserID = user.UserID
+		event.CharacterID = user.CharacterID
+		event.CharacterName = user.CharacterName
+		event.SessionID = user.SessionID
+		if user.Impersonator != nil {
+			event.Detail = fmt.Sprintf("impersonation by user %s (character %d)", user.Impersonator.UserID, user.Impersonator.CharacterID)
+		}
+	}
+	if event.SessionID == "" && refreshToken != "" {
+		token, err := s.repository.GetRefreshToken(ctx, hashRefreshToken(refreshToken))
+		if err != nil {
+			return fmt.Errorf("failed to get refresh token: %w", err)
+		}
+		if token != nil {
+			event.UserID = token.UserID
+			event.CharacterID = token.CharacterID
+			event.CharacterName = token.CharacterName
+			event.SessionID = token.SessionID
+		}
+	}
+	if event.UserID == "" && event.SessionID == "" {
+		return nil
+	}
+
+	if event.SessionID != "" {
+		if err := s.RevokeSession(ctx, event.SessionID); err != nil {
+			event.Outcome = models.AuditOutcomeFailure
+			event.Detail = err.Error()
+			s.recordAuthEvent(ctx, event, client)
+			return err
+		}
+	}
+	s.recordAuthEvent(ctx, event, client)
+	return nil
+}
+
+// LogoutEverywhere signs an account out on every device: all of its sessions are revoked and its
+// token generation is bumped, so every JWT and cookie issued before, including ones without a
+// session, stops working at once. The counter never expires; a counter that lapsed would let a
+// later bump land on a generation still carried by live tokens.
+func (s *AuthService) LogoutEverywhere(ctx context.Context, user *models.AuthenticatedUser, client models.SessionClient) (int, error) {
+	if s.redis == nil {
+		return 0, errors.New("signing out everywhere requires Redis")
+	}
+	event := &models.AuthAuditEvent{
+		Event:         models.AuditEventLogout,
+		Outcome:       models.AuditOutcomeSuccess,
+		UserID:        user.UserID,
+		CharacterID:   user.CharacterID,
+		CharacterName: user.CharacterName,
+		SessionID:     user.SessionID,
+	}
+
+	// Sessions first: a refresh racing the bump then issues a token of the old generation
+	revoked, err := s.RevokeOtherSessions(ctx, user.UserID, "")
+	if err == nil {
+		err = s.redis.Client.Incr(ctx, tokenGenerationKeyPrefix+user.UserID).Err()
+	}
+	if err != nil {
+		event.Outcome = models.AuditOutcomeFailure
+		event.Detail = err.Error()
+		s.recordAuthEvent(ctx, event, client)
+		return revoked, err
+	}
+
+	event.Detail = fmt.Sprintf("logged out everywhere; %d sessions revoked", revoked)
+	s.recordAuthEvent(ctx, event, client)
+	slog.InfoContext(ctx, "Logged out everywhere", "user_id", user.UserID, "sessions_revoked", revoked)
+	return revoked, nil
+}
+
+// tokenGeneration returns the current token generation of an account, 0 when it never logged out
+// everywhere or Redis cannot answer
+func (s *AuthService) tokenGeneration(ctx context.Context, userID string) int64 {
+	if s.redis == nil || userID == "" {
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, revocationCheckTimeout)
+	defer cancel()
+
+	generation, err := s.redis.Client.Get(ctx, tokenGenerationKeyPrefix+userID).Int64()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Warn("Failed to read token generation", "user_id", userID, "error", err)
+		}
+		return 0
+	}
+	return generation
+}
+
+// generationRevoked reports whether a token was issued before its account last logged out
+// everywhere. Like the session revocation list it fails open when Redis is unreachable.
+func (s *AuthService) generationRevoked(user *models.AuthenticatedUser) bool {
+	return user.TokenGeneration < s.tokenGeneration(context.Background(), user.UserID)
+}
+
+// sessionRevoked reports whether a session is on the revocation list. A Redis outage lets tokens
+// through: they are still bounded by their expiry, and failing closed would sign everyone out.
+func (s *AuthService) sessionRevoked(sessionID string) bool {
+	if s.redis == nil || sessionID == "" {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), revocationCheckTimeout)
+	defer cancel()
+
+	count, err := s.redis.Exists(ctx, revokedSessionKeyPrefix+sessionID)
+	if err != nil {
+		slog.Warn("Failed to check session revocation list", "session_id", sessionID, "error", err)
+		return false
+	}
+	return count > 0
+}
+
+// touchSession updates the last seen time of a session in use, at most once per sessionSeenInterval
+// across replicas. It runs detached from the request and never delays it.
+func (s *AuthService) touchSession(sessionID string) {
+	if s.redis == nil || sessionID == "" {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		due, err := s.redis.Client.SetNX(ctx, seenSessionKeyPrefix+sessionID, "1", sessionSeenInterval).Result()
+		if err != nil || !due {
+			return
+		}
+		if err := s.repository.TouchSession(ctx, sessionID, time.Now()); err != nil {
+			slog.Debug("Failed to update session last seen time", "session_id", sessionID, "error", err)
+		}
+	}()
+}
+
+// ListSessions returns the active sessions of a user account
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentSessionID string) (*dto.SessionsResponse, error) {
+	sessions, err := s.repository.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	response := &dto.SessionsResponse{
+		CurrentSessionID: currentSessionID,
+		Sessions:         make([]dto.Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, dto.Session{
+			SessionID:     session.SessionID,
+			CharacterID:   session.CharacterID,
+			CharacterName: session.CharacterName,
+			UserAgent:     session.UserAgent,
+			IPAddress:     session.IPAddress,
+			CreatedAt:     session.CreatedAt,
+			LastSeenAt:    session.LastSeenAt,
+			ExpiresAt:     session.ExpiresAt,
+			Current:       session.SessionID == currentSessionID,
+		})
+	}
+	return response, nil
+}
+
+// RevokeUserSession revokes one session of a user account
+func (s *AuthService) RevokeUserSession(ctx context.Context, userID, sessionID string) error {
+	session, err := s.repository.GetSession(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil || session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	return s.RevokeSession(ctx, sessionID)
+}
+
+// RevokeOtherSessions revokes every active session of a user account except the current one and
+// returns how many were revoked
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) (int, error) {
+	sessions, err := s.repository.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.SessionID == currentSessionID {
+			continue
+		}
+		if err := s.RevokeSession(ctx, session.SessionID); err != nil {
+			return revoked, fmt.Errorf("failed to revoke session %s: %w", session.SessionID, err)
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// RevokeCharacterSessions revokes every session of a user's account signed in as one character,
+// e.g. when the character is deleted
+func (s *AuthService) RevokeCharacterSessions(ctx context.Context, userID string, characterID int) (int, error) {
+	sessions, err := s.repository.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.CharacterID != characterID {
+			continue
+		}
+		if err := s.RevokeSession(ctx, session.SessionID); err != nil {
+			return revoked, fmt.Errorf("failed to revoke session %s: %w", session.SessionID, err)
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// EraseUser removes what the auth module keeps about a user account that is being erased: every
+// session is revoked and its audit events lose their character and client details. It returns how
+// many sessions were revoked and audit events anonymized.
+func (s *AuthService) EraseUser(ctx context.Context, userID string) (int, int64, error) {
+	revoked, err := s.RevokeOtherSessions(ctx, userID, "")
+	if err != nil {
+		return revoked, 0, err
+	}
+	anonymized, err := s.repository.AnonymizeAuditEvents(ctx, userID)
+	if err != nil {
+		return revoked, 0, fmt.Errorf("failed to anonymize audit events: %w", err)
+	}
+	return revoked, anonymized, nil
+}
+
+// newRefreshToken returns a random opaque refresh token
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken returns the stored form of a refresh token
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// signingKeyPublishLead is how long a new key is published in the JWKS before it signs, so
+	// services that cache the JWKS know it by the time they see its first token
+	signingKeyPublishLead = time.Hour
+	// signingKeyMissReload limits how often an unknown key ID triggers a reload from MongoDB
+	signingKeyMissReload = 10 * time.Second
+	// signingKeyLoadTimeout bounds loads triggered while signing or verifying a token
+	signingKeyLoadTimeout = 5 * time.Second
+)
+
+// ErrUnknownSigningKey is returned for a token signed by a key that is not (or no longer) published
+var ErrUnknownSigningKey = errors.New("unknown JWT signing key")
+
+// signingKey is a parsed signing key
+type signingKey struct {
+	kid         string
+	generation  int
+	private     *ecdsa.PrivateKey
+	createdAt   time.Time
+	activatesAt time.Time
+}
+
+// SigningKeys holds the ES256 keys that sign falcon JWTs. The keys live in MongoDB, shared by all
+// replicas; each replica keeps them in memory and reloads them when it rotates or meets an
+// unknown key ID. Private keys are sealed with a key derived from JWT_SECRET.
+type SigningKeys struct {
+	repository *Repository
+	seal       cipher.AEAD
+
+	loadMu   sync.Mutex // Serializes loads and rotations
+	mu       sync.RWMutex
+	keys     []*signingKey // Oldest generation first
+	loadedAt time.Time
+}
+
+// NewSigningKeys creates the signing key ring; keys are loaded on first use
+func NewSigningKeys(repository *Repository, secret []byte) *SigningKeys {
+	return &SigningKeys{
+		repository: repository,
+		seal:       newSecretSeal(secret, "go-falcon/jwt-signing-keys:"),
+	}
+}
+
+// newSecretSeal derives an AES-GCM cipher for secrets stored in MongoDB from JWT_SECRET; the label
+// gives every kind of secret a key of its own
+func newSecretSeal(secret []byte, label string) cipher.AEAD {
+	sealKey := sha256.Sum256(append([]byte(label), secret...))
+	// A 32-byte key always makes a valid AES-256 cipher
+	block, _ := aes.NewCipher(sealKey[:])
+	seal, _ := cipher.NewGCM(block)
+	return seal
+}
+
+// Rotate reloads the keys and stores a new one when the newest is older than
+// JWT_KEY_ROTATION_INTERVAL. The new key signs after signingKeyPublishLead; the keys it supersedes
+// keep verifying until the tokens they signed expired.
+func (k *SigningKeys) Rotate(ctx context.Context) error {
+	k.loadMu.Lock()
+	defer k.loadMu.Unlock()
+
+	if err := k.load(ctx); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	k.mu.RLock()
+	var newest *signingKey
+	if len(k.keys) > 0 {
+		newest = k.keys[len(k.keys)-1]
+	}
+	k.mu.RUnlock()
+	if newest != nil && now.Before(newest.createdAt.Add(config.GetJWTKeyRotationInterval())) {
+		return nil
+	}
+
+	key := &models.SigningKey{
+		Generation:  1,
+		Algorithm:   jwt.SigningMethodES256.Alg(),
+		CreatedAt:   now,
+		ActivatesAt: now,
+	}
+	if newest != nil {
+		key.Generation = newest.generation + 1
+		key.ActivatesAt = now.Add(signingKeyPublishLead)
+	}
+	if err := k.generate(key); err != nil {
+		return err
+	}
+
+	if err := k.repository.InsertSigningKey(ctx, key); err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to store signing key: %w", err)
+		}
+		// Another replica rotated first
+		return k.load(ctx)
+	}
+	if err := k.repository.ExpireSigningKeys(ctx, key.Generation, key.ActivatesAt.Add(maxTokenLifetime())); err != nil {
+		return fmt.Errorf("failed to expire superseded signing keys: %w", err)
+	}
+	slog.Info("Rotated JWT signing key", "kid", key.KeyID, "generation", key.Generation, "activates_at", key.ActivatesAt)
+
+	return k.load(ctx)
+}
+
+// maxTokenLifetime is how long a token may be accepted after it was signed
+func maxTokenLifetime() time.Duration {
+	return max(config.GetCookieDuration(), config.GetImpersonationDuration()) + tokenLeeway
+}
+
+// generate creates the key pair of a new signing key
+func (k *SigningKeys) generate(key *models.SigningKey) error {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode signing key: %w", err)
+	}
+
+	nonce := make([]byte, k.seal.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to seal signing key: %w", err)
+	}
+
+	jwk, err := publicJWK(&private.PublicKey, "")
+	if err != nil {
+		return err
+	}
+	key.KeyID = jwkThumbprint(jwk)
+	key.PrivateKey = base64.StdEncoding.EncodeToString(k.seal.Seal(nonce, nonce, privateDER, []byte(key.KeyID)))
+	key.PublicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	return nil
+}
+
+// load replaces the in-memory keys with the unexpired keys in MongoDB; callers hold loadMu
+func (k *SigningKeys) load(ctx context.Context) error {
+	stored, err := k.repository.ListSigningKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	keys := make([]*signingKey, 0, len(stored))
+	for _, key := range stored {
+		private, err := k.open(&key)
+		if err != nil {
+			// Typically JWT_SECRET differs from the replica that created the key
+			slog.Error("Skipping JWT signing key that cannot be opened", "kid", key.KeyID, "error", err)
+			continue
+		}
+		keys = append(keys, &signingKey{
+			kid:         key.KeyID,
+			generation:  key.Generation,
+			private:     private,
+			createdAt:   key.CreatedAt,
+			activatesAt: key.ActivatesAt,
+		})
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.loadedAt = time.Now()
+	k.mu.Unlock()
+	return nil
+}
+
+// open unseals the private key of a stored signing key
+func (k *SigningKeys) open(key *models.SigningKey) (*ecdsa.PrivateKey, error) {
+	sealed, err := base64.StdEncoding.DecodeString(key.PrivateKey)
+	if err != nil || len(sealed) < k.seal.NonceSize() {
+		return nil, errors.New("malformed private key")
+	}
+	nonce, ciphertext := sealed[:k.seal.NonceSize()], sealed[k.seal.NonceSize():]
+	der, err := k.seal.Open(nil, nonce, ciphertext, []byte(key.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal private key: %w", err)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	private, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an ECDSA key")
+	}
+	return private, nil
+}
+
+// ensureLoaded loads the keys once when they are needed before the first rotation ran
+func (k *SigningKeys) ensureLoaded() error {
+	k.mu.RLock()
+	loaded := !k.loadedAt.IsZero()
+	k.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), signingKeyLoadTimeout)
+	defer cancel()
+	return k.Rotate(ctx)
+}
+
+// signer returns the newest key that has activated
+func (k *SigningKeys) signer() (*signingKey, error) {
+	if err := k.ensureLoaded(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if !k.keys[i].activatesAt.After(now) {
+			return k.keys[i], nil
+		}
+	}
+	return nil, errors.New("no active JWT signing key")
+}
+
+// verifier returns the public key of a key ID, reloading once when another replica may have
+// created it since the last load
+func (k *SigningKeys) verifier(kid string) (*ecdsa.PublicKey, error) {
+	if err := k.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	key, reload := k.lookup(kid)
+	if key != nil {
+		return key, nil
+	}
+	if !reload {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSigningKey, kid)
+	}
+
+	k.loadMu.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), signingKeyLoadTimeout)
+	err := k.load(ctx)
+	cancel()
+	k.loadMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if key, _ := k.lookup(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownSigningKey, kid)
+}
+
+// lookup returns the public key of a key ID, or whether a reload is due when it is unknown
+func (k *SigningKeys) lookup(kid string) (*ecdsa.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, key := range k.keys {
+		if key.kid == kid {
+			return &key.private.PublicKey, false
+		}
+	}
+	return nil, time.Since(k.loadedAt) >= signingKeyMissReload
+}
+
+// JWKS returns the public keys of all published signing keys
+func (k *SigningKeys) JWKS() (*dto.JSONWebKeySet, error) {
+	if err := k.ensureLoaded(); err != nil {
+		return nil, err
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	set := &dto.JSONWebKeySet{Keys: make([]dto.JSONWebKey, 0, len(k.keys))}
+	for _, key := range k.keys {
+		jwk, err := publicJWK(&key.private.PublicKey, key.kid)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, *jwk)
+	}
+	return set, nil
+}
+
+// publicJWK encodes a P-256 public key as a JWK
+func publicJWK(public *ecdsa.PublicKey, kid string) (*dto.JSONWebKey, error) {
+	ecdhKey, err := public.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+	// Uncompressed point: 0x04 || X || Y, each coordinate padded to the curve size
+	point := ecdhKey.Bytes()
+	size := (len(point) - 1) / 2
+
+	return &dto.JSONWebKey{
+		KeyType:   "EC",
+		Curve:     "P-256",
+		X:         base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+		Y:         base64.RawURLEncoding.EncodeToString(point[1+size:]),
+		KeyID:     kid,
+		Use:       "sig",
+		Algorithm: jwt.SigningMethodES256.Alg(),
+	}, nil
+}
+
+// jwkThumbprint derives a key ID from the required members of a JWK (RFC 7638)
+func jwkThumbprint(jwk *dto.JSONWebKey) string {
+	// json.Marshal sorts map keys, which is the member order the thumbprint requires
+	members, _ := json.Marshal(map[string]string{
+		"crv": jwk.Curve,
+		"kty": jwk.KeyType,
+		"x":   jwk.X,
+		"y":   jwk.Y,
+	})
+	sum := sha256.Sum256(members)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RotateSigningKeys reloads the JWT signing keys and rotates them when they are due
+func (s *AuthService) RotateSigningKeys(ctx context.Context) error {
+	return s.eveService.signingKeys.Rotate(ctx)
+}
+
+// JWKS returns the public keys that verify falcon JWTs
+func (s *AuthService) JWKS() (*dto.JSONWebKeySet, error) {
+	return s.eveService.signingKeys.JWKS()
+}
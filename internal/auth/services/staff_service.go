@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StaffService handles staff accounts: non-EVE users who sign in through an external identity
+// provider. Only accounts an admin provisioned can sign in, matched by verified email on the first
+// login and by the provider's user ID afterwards.
+type StaffService struct {
+	repository *Repository
+	eveService *EVEService
+	providers  *ProviderRegistry
+}
+
+// NewStaffService creates a new staff service
+func NewStaffService(repository *Repository, eveService *EVEService, providers *ProviderRegistry) *StaffService {
+	return &StaffService{
+		repository: repository,
+		eveService: eveService,
+		providers:  providers,
+	}
+}
+
+// Providers returns the provider registry so further providers can be registered
+func (s *StaffService) Providers() *ProviderRegistry {
+	return s.providers
+}
+
+// ListProviders returns the configured staff identity providers
+func (s *StaffService) ListProviders() []dto.ProviderResponse {
+	providers := s.providers.List()
+
+	response := make([]dto.ProviderResponse, 0, len(providers))
+	for _, provider := range providers {
+		response = append(response, dto.ProviderResponse{
+			Name:        provider.Name(),
+			DisplayName: provider.DisplayName(),
+		})
+	}
+	return response
+}
+
+// InitiateLogin stores a login state for the provider and returns its authorization URL
+func (s *StaffService) InitiateLogin(ctx context.Context, providerName string) (*dto.EVELoginResponse, error) {
+	provider, ok := s.providers.Get(providerName)
+	if !ok {
+		return nil, huma.Error404NotFound(fmt.Sprintf("Identity provider %s is not configured", providerName))
+	}
+
+	state, err := s.eveService.generateSecureState()
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to generate state", err)
+	}
+	if err := s.repository.StoreLoginState(ctx, &models.EVELoginState{State: state, Provider: providerName}); err != nil {
+		return nil, huma.Error500InternalServerError("Failed to store login state", err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state)
+	if err != nil {
+		return nil, huma.Error502BadGateway("Identity provider unavailable", err)
+	}
+
+	return &dto.EVELoginResponse{AuthURL: authURL, State: state}, nil
+}
+
+// HandleCallback completes a provider login and returns a JWT for the matching staff account. The
+// login is recorded in the audit log whether it succeeds or not.
+func (s *StaffService) HandleCallback(ctx context.Context, providerName, code, state string, client models.SessionClient) (string, error) {
+	token, account, err := s.handleCallback(ctx, providerName, code, state)
+	if err != nil {
+		recordAuthEvent(ctx, s.repository, &models.AuthAuditEvent{
+			Event:   models.AuditEventLogin,
+			Outcome: models.AuditOutcomeFailure,
+			Method:  providerName,
+			Detail:  err.Error(),
+		}, client)
+		return "", err
+	}
+
+	recordAuthEvent(ctx, s.repository, &models.AuthAuditEvent{
+		Event:         models.AuditEventLogin,
+		Outcome:       models.AuditOutcomeSuccess,
+		Method:        providerName,
+		UserID:        account.UserID(),
+		CharacterName: account.Name,
+	}, client)
+	return token, nil
+}
+
+// handleCallback completes a provider login for the staff account it resolves to
+func (s *StaffService) handleCallback(ctx context.Context, providerName, code, state string) (string, *models.StaffAccount, error) {
+	provider, ok := s.providers.Get(providerName)
+	if !ok {
+		return "", nil, huma.Error404NotFound(fmt.Sprintf("Identity provider %s is not configured", providerName))
+	}
+
+	loginState, err := s.repository.ConsumeLoginState(ctx, state)
+	if err != nil {
+		return "", nil, huma.Error500InternalServerError("Failed to validate state", err)
+	}
+	if loginState == nil || loginState.Provider != providerName || loginState.Purpose != "" {
+		return "", nil, huma.Error400BadRequest("Invalid or expired state")
+	}
+
+	external, err := provider.Exchange(ctx, code)
+	if err != nil {
+		return "", nil, huma.Error400BadRequest("Authentication failed", err)
+	}
+
+	account, err := s.resolveAccount(ctx, external)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	if err := s.repository.UpdateStaffAccount(ctx, account.ID, bson.M{"last_login": now}); err != nil {
+		slog.Warn("Failed to record staff login", "account_id", account.ID.Hex(), "error", err)
+	}
+
+	token, _, err := s.eveService.GenerateStaffJWT(account)
+	if err != nil {
+		return "", nil, huma.Error500InternalServerError("Failed to generate token", err)
+	}
+
+	slog.Info("Staff account signed in", "account_id", account.ID.Hex(), "provider", providerName)
+	return token, account, nil
+}
+
+// resolveAccount finds the enabled staff account for an external identity, binding the provider
+// user ID to an account provisioned by email on its first login
+func (s *StaffService) resolveAccount(ctx context.Context, external *models.ExternalIdentity) (*models.StaffAccount, error) {
+	account, err := s.repository.GetStaffAccountBySubject(ctx, external.Provider, external.Subject)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to look up staff account", err)
+	}
+
+	if account == nil {
+		// Only a verified email may claim a provisioned account
+		if external.Email == "" || !external.EmailVerified {
+			return nil, huma.Error403Forbidden("No staff account for this identity")
+		}
+		account, err = s.repository.GetStaffAccountByEmail(ctx, external.Provider, external.Email)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to look up staff account", err)
+		}
+		if account == nil || account.Subject != "" {
+			return nil, huma.Error403Forbidden("No staff account for this identity")
+		}
+
+		if err := s.repository.UpdateStaffAccount(ctx, account.ID, bson.M{"subject": external.Subject}); err != nil {
+			return nil, huma.Error500InternalServerError("Failed to bind staff account", err)
+		}
+		account.Subject = external.Subject
+		slog.Info("Bound staff account to provider identity", "account_id", account.ID.Hex(), "provider", external.Provider)
+	}
+
+	if !account.Enabled {
+		return nil, huma.Error403Forbidden("Staff account is disabled")
+	}
+	return account, nil
+}
+
+// GetStaffPermissions returns the permissions of an enabled staff account by the user ID its
+// tokens carry. Disabled and deleted accounts hold no permissions.
+func (s *StaffService) GetStaffPermissions(ctx context.Context, userID string) ([]string, error) {
+	id, err := primitive.ObjectIDFromHex(strings.TrimPrefix(userID, models.StaffUserIDPrefix))
+	if err != nil {
+		return nil, nil
+	}
+
+	account, err := s.repository.GetStaffAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil || !account.Enabled {
+		return nil, nil
+	}
+	return account.Permissions, nil
+}
+
+// ListStaffAccounts returns all staff accounts
+func (s *StaffService) ListStaffAccounts(ctx context.Context) (*dto.ListStaffAccountsOutput, error) {
+	accounts, err := s.repository.ListStaffAccounts(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list staff accounts", err)
+	}
+
+	output := &dto.ListStaffAccountsOutput{}
+	output.Body.Accounts = make([]dto.StaffAccountResponse, 0, len(accounts))
+	for i := range accounts {
+		output.Body.Accounts = append(output.Body.Accounts, staffAccountToResponse(&accounts[i]))
+	}
+	return output, nil
+}
+
+// CreateStaffAccount provisions a staff account
+func (s *StaffService) CreateStaffAccount(ctx context.Context, req *dto.StaffAccountRequest, createdBy int) (*dto.StaffAccountOutput, error) {
+	account := &models.StaffAccount{
+		Provider:    req.Provider,
+		Email:       strings.ToLower(strings.TrimSpace(req.Email)),
+		Name:        req.Name,
+		Permissions: normalizePermissions(req.Permissions),
+		Enabled:     req.Enabled == nil || *req.Enabled,
+		CreatedBy:   createdBy,
+	}
+
+	if err := s.repository.CreateStaffAccount(ctx, account); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, huma.Error409Conflict(fmt.Sprintf("A %s staff account for %s already exists", account.Provider, account.Email))
+		}
+		return nil, huma.Error500InternalServerError("Failed to create staff account", err)
+	}
+
+	slog.Info("Created staff account", "account_id", account.ID.Hex(), "provider", account.Provider, "created_by", createdBy)
+	return &dto.StaffAccountOutput{Body: staffAccountToResponse(account)}, nil
+}
+
+// UpdateStaffAccount changes the name, permissions or enabled flag of a staff account
+func (s *StaffService) UpdateStaffAccount(ctx context.Context, input *dto.UpdateStaffAccountInput) (*dto.StaffAccountOutput, error) {
+	id, err := primitive.ObjectIDFromHex(input.AccountID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid staff account ID")
+	}
+
+	update := bson.M{}
+	if input.Body.Name != nil {
+		update["name"] = *input.Body.Name
+	}
+	if input.Body.Permissions != nil {
+		update["permissions"] = normalizePermissions(input.Body.Permissions)
+	}
+	if input.Body.Enabled != nil {
+		update["enabled"] = *input.Body.Enabled
+	}
+
+	if len(update) > 0 {
+		if err := s.repository.UpdateStaffAccount(ctx, id, update); err != nil {
+			return nil, huma.Error500InternalServerError("Failed to update staff account", err)
+		}
+	}
+
+	account, err := s.repository.GetStaffAccount(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get staff account", err)
+	}
+	if account == nil {
+		return nil, huma.Error404NotFound("Staff account not found")
+	}
+
+	return &dto.StaffAccountOutput{Body: staffAccountToResponse(account)}, nil
+}
+
+// DeleteStaffAccount removes a staff account; its outstanding tokens lose all permissions
+func (s *StaffService) DeleteStaffAccount(ctx context.Context, accountID string) (*dto.DeleteStaffAccountOutput, error) {
+	id, err := primitive.ObjectIDFromHex(accountID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid staff account ID")
+	}
+
+	deleted, err := s.repository.DeleteStaffAccount(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete staff account", err)
+	}
+	if !deleted {
+		return nil, huma.Error404NotFound("Staff account not found")
+	}
+
+	output := &dto.DeleteStaffAccountOutput{}
+	output.Body.Message = "Staff account deleted successfully"
+	return output, nil
+}
+
+// normalizePermissions trims and de-duplicates permission IDs
+func normalizePermissions(permissions []string) []string {
+	seen := make(map[string]bool, len(permissions))
+	normalized := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.TrimSpace(permission)
+		if permission == "" || seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		normalized = append(normalized, permission)
+	}
+	return normalized
+}
+
+// staffAccountToResponse converts a staff account to its API shape
+func staffAccountToResponse(account *models.StaffAccount) dto.StaffAccountResponse {
+	return dto.StaffAccountResponse{
+		ID:          account.ID.Hex(),
+		UserID:      account.UserID(),
+		Provider:    account.Provider,
+		Email:       account.Email,
+		Bound:       account.Subject != "",
+		Name:        account.Name,
+		Permissions: account.Permissions,
+		Enabled:     account.Enabled,
+		LastLogin:   account.LastLogin,
+		CreatedBy:   account.CreatedBy,
+		CreatedAt:   account.CreatedAt,
+		UpdatedAt:   account.UpdatedAt,
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims layouts of issued JWTs. During a rolling deployment old and new replicas validate each
+// other's tokens, so every build reads all layouts from ClaimsVersionLegacy up to its own and reads
+// newer layouts through their fields it knows. Layout changes are therefore additive: a field is
+// only dropped once no supported layout needs it.
+const (
+	// ClaimsVersionLegacy tokens predate versioning and carry no "ver" claim
+	ClaimsVersionLegacy = 1
+	// ClaimsVersion adds "ver" and the standard "sub" claim (the user ID, mirrored in user_id)
+	ClaimsVersion = 2
+)
+
+// claimsVersionClaim names the claim holding the layout version
+const claimsVersionClaim = "ver"
+
+// sessionIDClaim names the claim holding the refresh token session of a token. It is optional in
+// every layout: replicas that do not read it accept the token until it expires.
+const sessionIDClaim = "sid"
+
+// actorClaim names the claim (RFC 8693 "act") identifying the administrator behind an
+// impersonation token. Like "sid" it is optional in every layout.
+const actorClaim = "act"
+
+// tokenGenerationClaim names the claim holding the account's token generation when the token was
+// issued (see LogoutEverywhere). It is only issued once the account has logged out everywhere.
+const tokenGenerationClaim = "gen"
+
+// sudoClaim names the claim holding when the second-factor elevation of a token ends (Unix time).
+// Optional in every layout; replicas that do not read it treat the token as not elevated.
+const sudoClaim = "sudo"
+
+// authTimeClaim names the claim holding when the user last signed in through SSO (Unix time, as
+// OIDC "auth_time"). Refreshing and reissuing a token carry it over unchanged, so it is what step-up
+// confirmation trusts; tokens without it count as not recently signed in.
+const authTimeClaim = "auth_time"
+
+// tokenLeeway absorbs clock skew between replicas when checking exp and nbf
+const tokenLeeway = 30 * time.Second
+
+// issuedClaimsVersion returns the layout to issue. JWT_CLAIMS_VERSION pins it to the previous
+// layout while replicas that cannot read the new one are still serving.
+func issuedClaimsVersion() int {
+	version := config.GetJWTClaimsVersion()
+	if version < ClaimsVersionLegacy || version > ClaimsVersion {
+		return ClaimsVersion
+	}
+	return version
+}
+
+// stampClaimsVersion adds the version-specific claims of the issued layout
+func stampClaimsVersion(claims jwt.MapClaims, userID string) {
+	if issuedClaimsVersion() < 2 {
+		return
+	}
+	claims[claimsVersionClaim] = ClaimsVersion
+	claims["sub"] = userID
+}
+
+// parseToken verifies a JWT signature and expiry and returns its claims. ES256 tokens are
+// verified with the signing key named by "kid", HS256 tokens with the JWT secret.
+func (s *EVEService) parseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodECDSA:
+			kid, _ := token.Header["kid"].(string)
+			return s.signingKeys.verifier(kid)
+		case *jwt.SigningMethodHMAC:
+			return s.jwtSecret, nil
+		}
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}, jwt.WithLeeway(tokenLeeway), jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg(), jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT: %w", err)
+	}
+
+	if !token.Valid {
+		return nil, errors.New("invalid JWT token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid JWT claims")
+	}
+	return claims, nil
+}
+
+// userFromClaims reads the authenticated user from any supported claims layout
+func userFromClaims(claims jwt.MapClaims) (*models.AuthenticatedUser, error) {
+	userID := stringClaim(claims, "user_id")
+	if userID == "" {
+		userID = stringClaim(claims, "sub")
+	}
+	if userID == "" {
+		return nil, errors.New("JWT has no user")
+	}
+
+	if version := claimsVersion(claims); version > ClaimsVersion {
+		slog.Debug("Reading JWT from a newer claims layout", "version", version, "supported", ClaimsVersion)
+	}
+
+	characterID, _ := numberClaim(claims, "character_id")
+	generation, _ := numberClaim(claims, tokenGenerationClaim)
+
+	return &models.AuthenticatedUser{
+		UserID:          userID,
+		CharacterID:     int(characterID),
+		CharacterName:   stringClaim(claims, "character_name"),
+		Scopes:          scopesClaim(claims),
+		Provider:        stringClaim(claims, "provider"),
+		IssuedAt:        issuedAt(claims),
+		AuthTime:        authTime(claims),
+		SessionID:       stringClaim(claims, sessionIDClaim),
+		TokenGeneration: generation,
+		Impersonator:    impersonatorClaim(claims),
+		SudoUntil:       sudoUntil(claims),
+	}, nil
+}
+
+// authTime reads when the user last signed in through SSO, or the zero time for tokens without it
+func authTime(claims jwt.MapClaims) time.Time {
+	if at, ok := numberClaim(claims, authTimeClaim); ok {
+		return time.Unix(at, 0)
+	}
+	return time.Time{}
+}
+
+// sudoUntil reads when the elevation of a token ends, or the zero time for tokens without one
+func sudoUntil(claims jwt.MapClaims) time.Time {
+	if until, ok := numberClaim(claims, sudoClaim); ok {
+		return time.Unix(until, 0)
+	}
+	return time.Time{}
+}
+
+// impersonatorClaim reads the administrator of an impersonation token, or nil for other tokens
+func impersonatorClaim(claims jwt.MapClaims) *models.Impersonator {
+	act, ok := claims[actorClaim].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	impersonator := &models.Impersonator{
+		UserID:        stringClaim(act, "sub"),
+		CharacterName: stringClaim(act, "character_name"),
+	}
+	if characterID, ok := numberClaim(act, "character_id"); ok {
+		impersonator.CharacterID = int(characterID)
+	}
+	if impersonator.UserID == "" {
+		return nil
+	}
+	return impersonator
+}
+
+// claimsVersion returns the layout version of a token's claims
+func claimsVersion(claims jwt.MapClaims) int {
+	if version, ok := numberClaim(claims, claimsVersionClaim); ok && version >= ClaimsVersionLegacy {
+		return int(version)
+	}
+	return ClaimsVersionLegacy
+}
+
+// stringClaim reads a string claim, returning "" when it is missing or not a string
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// numberClaim reads a numeric claim whether it was encoded as a JSON number or a string
+func numberClaim(claims jwt.MapClaims, name string) (int64, bool) {
+	switch v := claims[name].(type) {
+	case float64:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// scopesClaim reads the space-separated scopes, also accepting a list of scopes
+func scopesClaim(claims jwt.MapClaims) string {
+	switch v := claims["scopes"].(type) {
+	case string:
+		return v
+	case []interface{}:
+		scopes := make([]string, 0, len(v))
+		for _, scope := range v {
+			if s, ok := scope.(string); ok && s != "" {
+				scopes = append(scopes, s)
+			}
+		}
+		return strings.Join(scopes, " ")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go-falcon/internal/auth/dto"
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/stepup"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	// ErrTOTPAlreadyEnrolled is returned when enrolling an account that has a confirmed authenticator
+	ErrTOTPAlreadyEnrolled = errors.New("an authenticator is already enrolled; remove it first")
+	// ErrTOTPNotEnrolled is returned when the account has no authenticator to confirm or use
+	ErrTOTPNotEnrolled = errors.New("no authenticator enrolled")
+	// ErrInvalidTOTPCode is returned for a wrong, expired or already used code
+	ErrInvalidTOTPCode = errors.New("invalid authenticator code")
+	// ErrTOTPStaffAccount is returned when a staff account tries to enroll an authenticator
+	ErrTOTPStaffAccount = errors.New("authenticators are only available to EVE characters")
+)
+
+const (
+	// totpPeriod is how long one code is current (RFC 6238 default)
+	totpPeriod = 30
+	// totpDigits is the length of a code
+	totpDigits = 6
+	// totpSkew is how many periods a code may be off, absorbing clock drift of the phone
+	totpSkew = 1
+	// totpSecretSize is the secret length in bytes (160 bits, as RFC 4226 recommends)
+	totpSecretSize = 20
+	// totpIssuer is shown in the authenticator app
+	totpIssuer = "Go Falcon"
+	// totpAttemptsPerMinute bounds code guesses per account; going over locks it out like a sign-in
+	totpAttemptsPerMinute = 5
+	// ThrottleTOTP names the authenticator code checks in the throttle keys
+	ThrottleTOTP = "totp"
+)
+
+// TOTPStatus returns the authenticator enrollment of a user account
+func (s *AuthService) TOTPStatus(ctx context.Context, userID string) (*dto.TOTPStatusResponse, error) {
+	enrollment, err := s.repository.GetTOTPEnrollment(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authenticator enrollment: %w", err)
+	}
+	if enrollment == nil {
+		return &dto.TOTPStatusResponse{}, nil
+	}
+	return &dto.TOTPStatusResponse{
+		Enrolled:    enrollment.Confirmed,
+		Pending:     !enrollment.Confirmed,
+		ConfirmedAt: enrollment.ConfirmedAt,
+		LastUsedAt:  enrollment.LastUsedAt,
+	}, nil
+}
+
+// EnrollTOTP starts an authenticator enrollment and returns its secret, replacing an enrollment
+// that was never confirmed. The authenticator protects the account once ConfirmTOTP accepted a code.
+func (s *AuthService) EnrollTOTP(ctx context.Context, user *models.AuthenticatedUser) (*dto.TOTPEnrollmentResponse, error) {
+	if user.Provider != "" {
+		return nil, ErrTOTPStaffAccount
+	}
+
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate authenticator secret: %w", err)
+	}
+	sealed, err := s.sealTOTPSecret(user.UserID, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repository.ReplacePendingTOTPEnrollment(ctx, &models.TOTPEnrollment{
+		UserID:    user.UserID,
+		Secret:    sealed,
+		CreatedAt: time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrTOTPAlreadyEnrolled
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store authenticator enrollment: %w", err)
+	}
+
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret)
+	query := url.Values{}
+	query.Set("secret", encoded)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(totpIssuer + ":" + user.CharacterName)
+
+	return &dto.TOTPEnrollmentResponse{
+		Secret:     encoded,
+		OTPAuthURI: "otpauth://totp/" + label + "?" + query.Encode(),
+		Digits:     totpDigits,
+		Period:     totpPeriod,
+	}, nil
+}
+
+// ConfirmTOTP confirms a pending enrollment with a first code from the authenticator
+func (s *AuthService) ConfirmTOTP(ctx context.Context, userID, code string) (*dto.TOTPStatusResponse, error) {
+	if err := s.checkTOTP(ctx, userID, code, false); err != nil {
+		return nil, err
+	}
+	return s.TOTPStatus(ctx, userID)
+}
+
+// RemoveTOTP removes the authenticator of an account; a confirmed one needs a current code
+func (s *AuthService) RemoveTOTP(ctx context.Context, userID, code string) error {
+	enrollment, err := s.repository.GetTOTPEnrollment(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get authenticator enrollment: %w", err)
+	}
+	if enrollment == nil {
+		return ErrTOTPNotEnrolled
+	}
+	if enrollment.Confirmed {
+		if err := s.checkTOTP(ctx, userID, code, true); err != nil {
+			return err
+		}
+	}
+	if err := s.repository.DeleteTOTPEnrollment(ctx, userID); err != nil {
+		return fmt.Errorf("failed to remove authenticator: %w", err)
+	}
+	return nil
+}
+
+// Sudo elevates the caller's session with an authenticator code: it returns a token of the same
+// session whose sudo claim lets second-factor protected actions run for STEP_UP_WINDOW_MINUTES.
+// Every attempt is recorded in the audit log.
+func (s *AuthService) Sudo(ctx context.Context, user *models.AuthenticatedUser, code string, client models.SessionClient) (*dto.SudoResponse, error) {
+	event := &models.AuthAuditEvent{
+		Event:         models.AuditEventSudo,
+		Outcome:       models.AuditOutcomeSuccess,
+		Method:        ThrottleTOTP,
+		UserID:        user.UserID,
+		CharacterID:   user.CharacterID,
+		CharacterName: user.CharacterName,
+		SessionID:     user.SessionID,
+	}
+
+	response, err := s.sudo(ctx, user, code)
+	if err != nil {
+		event.Outcome = models.AuditOutcomeFailure
+		event.Detail = err.Error()
+	}
+	s.recordAuthEvent(ctx, event, client)
+	return response, err
+}
+
+func (s *AuthService) sudo(ctx context.Context, user *models.AuthenticatedUser, code string) (*dto.SudoResponse, error) {
+	if err := s.checkTOTP(ctx, user.UserID, code, true); err != nil {
+		return nil, err
+	}
+
+	sudoUntil := time.Now().Add(time.Duration(config.GetStepUpWindowMinutes()) * time.Minute)
+	token, expiresAt, err := s.eveService.GenerateSudoJWT(ctx, user, sudoUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+	}
+	return &dto.SudoResponse{Token: token, ExpiresAt: expiresAt, SudoUntil: sudoUntil}, nil
+}
+
+// HasSecondFactor reports whether a user account has a confirmed authenticator
+func (s *AuthService) HasSecondFactor(ctx context.Context, userID string) (bool, error) {
+	enrollment, err := s.repository.GetTOTPEnrollment(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get authenticator enrollment: %w", err)
+	}
+	return enrollment != nil && enrollment.Confirmed, nil
+}
+
+// TOTPVerifier lets step-up confirmations (POST /auth/step-up) use the authenticator
+func (s *AuthService) TOTPVerifier() stepup.Verifier {
+	return totpVerifier{s}
+}
+
+type totpVerifier struct {
+	s *AuthService
+}
+
+func (v totpVerifier) Verify(ctx context.Context, userID, code string) (bool, error) {
+	err := v.s.checkTOTP(ctx, userID, code, true)
+	var throttled *ThrottledError
+	if errors.As(err, &throttled) {
+		return false, &stepup.ThrottledError{RetryAfter: throttled.RetryAfter}
+	}
+	if errors.Is(err, ErrInvalidTOTPCode) || errors.Is(err, ErrTOTPNotEnrolled) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// checkTOTP accepts a code of a confirmed (or, when confirming, a pending) enrollment and uses up
+// its time step, so the same code cannot be used twice
+func (s *AuthService) checkTOTP(ctx context.Context, userID, code string, confirmed bool) error {
+	enrollment, err := s.repository.GetTOTPEnrollment(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get authenticator enrollment: %w", err)
+	}
+	if enrollment == nil || enrollment.Confirmed != confirmed {
+		return ErrTOTPNotEnrolled
+	}
+
+	if s.redis != nil {
+		ctx, cancel := context.WithTimeout(ctx, throttleCheckTimeout)
+		retryAfter, err := s.countAttempt(ctx, throttleKeyPrefix+ThrottleTOTP+":user:"+userID, totpAttemptsPerMinute)
+		cancel()
+		if err == nil && retryAfter > 0 {
+			return &ThrottledError{RetryAfter: retryAfter}
+		}
+	}
+
+	secret, err := s.openTOTPSecret(enrollment)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	step, ok := matchTOTP(secret, code, now)
+	if !ok || step <= enrollment.LastUsedStep {
+		return ErrInvalidTOTPCode
+	}
+
+	used, err := s.repository.UseTOTPStep(ctx, userID, step, now)
+	if err != nil {
+		return fmt.Errorf("failed to record authenticator code: %w", err)
+	}
+	if !used {
+		// A concurrent request used the code first
+		return ErrInvalidTOTPCode
+	}
+	return nil
+}
+
+// matchTOTP returns the time step a code belongs to, trying the current step and totpSkew steps
+// either side of it
+func matchTOTP(secret []byte, code string, now time.Time) (int64, bool) {
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode computes the code of a time step (RFC 6238 with HMAC-SHA1, RFC 4226 truncation)
+func totpCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1_000_000)
+}
+
+// sealTOTPSecret encrypts a secret for storage, bound to its user account
+func (s *AuthService) sealTOTPSecret(userID string, secret []byte) (string, error) {
+	nonce := make([]byte, s.totpSeal.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to seal authenticator secret: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(s.totpSeal.Seal(nonce, nonce, secret, []byte(userID))), nil
+}
+
+// openTOTPSecret decrypts the secret of an enrollment
+func (s *AuthService) openTOTPSecret(enrollment *models.TOTPEnrollment) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(enrollment.Secret)
+	if err != nil || len(sealed) < s.totpSeal.NonceSize() {
+		return nil, errors.New("malformed authenticator secret")
+	}
+	nonce, ciphertext := sealed[:s.totpSeal.NonceSize()], sealed[s.totpSeal.NonceSize():]
+	secret, err := s.totpSeal.Open(nil, nonce, ciphertext, []byte(enrollment.UserID))
+	if err != nil {
+		// Typically JWT_SECRET changed since the enrollment
+		return nil, fmt.Errorf("failed to unseal authenticator secret: %w", err)
+	}
+	return secret, nil
+}
@@ -8,6 +8,7 @@ import (
 	"go-falcon/internal/character/dto"
 	"go-falcon/internal/character/services"
 	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/residency"
 
 	"github.com/danielgtaylor/huma/v2"
 )
@@ -660,58 +661,65 @@ func RegisterCharacterRoutes(api huma.API, basePath string, service *services.Se
 		return result, nil
 	})
 
-	// Get character wallet balance endpoint (authenticated, requires ESI token)
-	huma.Register(api, huma.Operation{
-		OperationID: "character-get-wallet",
-		Method:      "GET",
-		Path:        basePath + "/{character_id}/wallet",
-		Summary:     "Get character wallet balance",
-		Description: "Get character's current wallet balance in ISK. Requires authentication and esi-wallet.read_character_wallet.v1 scope for the character.",
-		Tags:        []string{"Character"},
-	}, func(ctx context.Context, input *dto.GetCharacterWalletInput) (*dto.CharacterWalletOutput, error) {
-		// Require authentication
-		var user *models.AuthenticatedUser
-		if characterAdapter != nil {
-			authUser, err := characterAdapter.RequireCharacterAccess(ctx, input.Authorization, input.Cookie)
-			if err != nil {
+	// Get character wallet balance endpoint (authenticated, requires ESI token); left out when the
+	// deployment disables wallet data
+	if residency.Configured(residency.CategoryWallets) != residency.ModeDisabled {
+		huma.Register(api, huma.Operation{
+			OperationID: "character-get-wallet",
+			Method:      "GET",
+			Path:        basePath + "/{character_id}/wallet",
+			Summary:     "Get character wallet balance",
+			Description: "Get character's current wallet balance in ISK. Requires authentication and esi-wallet.read_character_wallet.v1 scope for the character.",
+			Tags:        []string{"Character"},
+		}, func(ctx context.Context, input *dto.GetCharacterWalletInput) (*dto.CharacterWalletOutput, error) {
+			if err := residency.Require(ctx, residency.CategoryWallets); err != nil {
 				return nil, err
 			}
-			user = authUser
-		}
 
-		// Check if the user is requesting their own wallet or if they have permission
-		if user == nil || user.CharacterID != input.CharacterID {
-			return nil, huma.Error403Forbidden("You can only view your own character wallet")
-		}
+			// Require authentication
+			var user *models.AuthenticatedUser
+			if characterAdapter != nil {
+				authUser, err := characterAdapter.RequireCharacterAccess(ctx, input.Authorization, input.Cookie)
+				if err != nil {
+					return nil, err
+				}
+				user = authUser
+			}
 
-		// Get the user profile to retrieve the ESI access token
-		if authRepository == nil {
-			return nil, huma.Error500InternalServerError("Authentication service not available")
-		}
+			// Check if the user is requesting their own wallet or if they have permission
+			if user == nil || user.CharacterID != input.CharacterID {
+				return nil, huma.Error403Forbidden("You can only view your own character wallet")
+			}
 
-		profile, err := authRepository.GetUserProfileByCharacterID(ctx, input.CharacterID)
-		if err != nil {
-			return nil, huma.Error500InternalServerError("Failed to retrieve user profile", err)
-		}
-		if profile == nil {
-			return nil, huma.Error404NotFound("User profile not found")
-		}
+			// Get the user profile to retrieve the ESI access token
+			if authRepository == nil {
+				return nil, huma.Error500InternalServerError("Authentication service not available")
+			}
 
-		// Check if access token is expired
-		if time.Now().After(profile.TokenExpiry) {
-			return nil, huma.Error401Unauthorized("EVE access token expired, please re-authenticate")
-		}
+			profile, err := authRepository.GetUserProfileByCharacterID(ctx, input.CharacterID)
+			if err != nil {
+				return nil, huma.Error500InternalServerError("Failed to retrieve user profile", err)
+			}
+			if profile == nil {
+				return nil, huma.Error404NotFound("User profile not found")
+			}
 
-		// Use the ESI access token from the profile
-		token := profile.AccessToken
-		if token == "" {
-			return nil, huma.Error401Unauthorized("No EVE access token available")
-		}
+			// Check if access token is expired
+			if time.Now().After(profile.TokenExpiry) {
+				return nil, huma.Error401Unauthorized("EVE access token expired, please re-authenticate")
+			}
 
-		result, err := service.GetCharacterWallet(ctx, input.CharacterID, token)
-		if err != nil {
-			return nil, huma.Error500InternalServerError("Failed to get character wallet", err)
-		}
-		return result, nil
-	})
+			// Use the ESI access token from the profile
+			token := profile.AccessToken
+			if token == "" {
+				return nil, huma.Error401Unauthorized("No EVE access token available")
+			}
+
+			result, err := service.GetCharacterWallet(ctx, input.CharacterID, token)
+			if err != nil {
+				return nil, huma.Error500InternalServerError("Failed to get character wallet", err)
+			}
+			return result, nil
+		})
+	}
 }
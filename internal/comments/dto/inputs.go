@@ -0,0 +1,46 @@
+package dto
+
+// ListCommentsInput represents the input for listing the comments on an object
+type ListCommentsInput struct {
+	ObjectType    string `path:"object_type" doc:"Kind of object, as registered by its owning module" example:"srp-request"`
+	ObjectID      string `path:"object_id" doc:"ID of the object within its module"`
+	Page          int    `query:"page" minimum:"1" default:"1" doc:"Page number"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"500" default:"100" doc:"Items per page"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// CreateCommentInput represents the input for commenting on an object or replying to a comment
+type CreateCommentInput struct {
+	ObjectType    string `path:"object_type" doc:"Kind of object, as registered by its owning module" example:"srp-request"`
+	ObjectID      string `path:"object_id" doc:"ID of the object within its module"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Body          struct {
+		Body     string `json:"body" minLength:"1" maxLength:"10000" doc:"Comment text"`
+		ParentID string `json:"parent_id,omitempty" doc:"Comment being replied to; omit for a top-level comment"`
+		Mentions []int  `json:"mentions,omitempty" maxItems:"20" doc:"Character IDs mentioned in the comment; those who can view the object are notified"`
+	}
+}
+
+// UpdateCommentInput represents the input for editing a comment
+type UpdateCommentInput struct {
+	ObjectType    string `path:"object_type" doc:"Kind of object, as registered by its owning module" example:"srp-request"`
+	ObjectID      string `path:"object_id" doc:"ID of the object within its module"`
+	CommentID     string `path:"comment_id" doc:"Comment ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Body          struct {
+		Body     string `json:"body" minLength:"1" maxLength:"10000" doc:"New comment text; the previous text is kept in the comment's history"`
+		Mentions []int  `json:"mentions,omitempty" maxItems:"20" doc:"Character IDs mentioned in the new text; only newly mentioned characters are notified"`
+	}
+}
+
+// CommentInput identifies a comment on an object
+type CommentInput struct {
+	ObjectType    string `path:"object_type" doc:"Kind of object, as registered by its owning module" example:"srp-request"`
+	ObjectID      string `path:"object_id" doc:"ID of the object within its module"`
+	CommentID     string `path:"comment_id" doc:"Comment ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
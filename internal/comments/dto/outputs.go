@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"time"
+
+	"go-falcon/internal/comments/models"
+)
+
+// CommentResponse is a comment as returned by the API. Deleted comments keep their place in the
+// thread but their text is withheld.
+type CommentResponse struct {
+	ID                string     `json:"id"`
+	ParentID          string     `json:"parent_id,omitempty" doc:"Comment this one replies to"`
+	ThreadID          string     `json:"thread_id" doc:"Top-level comment of the thread"`
+	Body              string     `json:"body"`
+	Mentions          []int      `json:"mentions,omitempty" doc:"Mentioned character IDs"`
+	AuthorCharacterID int        `json:"author_character_id"`
+	AuthorName        string     `json:"author_name"`
+	EditCount         int        `json:"edit_count"`
+	EditedAt          *time.Time `json:"edited_at,omitempty"`
+	Deleted           bool       `json:"deleted"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// CommentOutput represents the output for a single comment
+type CommentOutput struct {
+	Body CommentResponse `json:"body"`
+}
+
+// CommentListOutput represents a paginated list of an object's comments
+type CommentListOutput struct {
+	Body struct {
+		ObjectType string            `json:"object_type"`
+		ObjectID   string            `json:"object_id"`
+		Comments   []CommentResponse `json:"comments" doc:"Comments oldest first; build threads from parent_id"`
+		Total      int64             `json:"total"`
+		Page       int               `json:"page"`
+		Limit      int               `json:"limit"`
+	} `json:"body"`
+}
+
+// RevisionResponse is a previous version of a comment
+type RevisionResponse struct {
+	Body       string    `json:"body"`
+	Mentions   []int     `json:"mentions,omitempty"`
+	ReplacedBy int       `json:"replaced_by" doc:"Character ID whose edit or deletion replaced this text"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// CommentHistoryOutput represents a comment with its edit history
+type CommentHistoryOutput struct {
+	Body struct {
+		Comment   CommentResponse    `json:"comment"`
+		Revisions []RevisionResponse `json:"revisions" doc:"Previous versions, oldest first"`
+	} `json:"body"`
+}
+
+// MessageOutput represents a plain confirmation message
+type MessageOutput struct {
+	Body struct {
+		Message string `json:"message"`
+	} `json:"body"`
+}
+
+// CommentsStatusResponse represents the module status response
+type CommentsStatusResponse struct {
+	Module      string   `json:"module" description:"Module name"`
+	Status      string   `json:"status" enum:"healthy,unhealthy" description:"Module health status"`
+	Message     string   `json:"message,omitempty" description:"Optional status message or error details"`
+	ObjectTypes []string `json:"object_types" description:"Object types registered by other modules"`
+}
+
+// StatusOutput represents the module status output
+type StatusOutput struct {
+	Body CommentsStatusResponse `json:"body"`
+}
+
+// ToCommentResponse converts a comment model into its response form
+func ToCommentResponse(comment *models.Comment) CommentResponse {
+	response := CommentResponse{
+		ID:                comment.ID.Hex(),
+		ThreadID:          comment.ThreadID.Hex(),
+		Body:              comment.Body,
+		Mentions:          comment.Mentions,
+		AuthorCharacterID: comment.AuthorCharacterID,
+		AuthorName:        comment.AuthorName,
+		EditCount:         comment.EditCount,
+		EditedAt:          comment.EditedAt,
+		Deleted:           comment.Deleted,
+		CreatedAt:         comment.CreatedAt,
+	}
+	if comment.ParentID != nil {
+		response.ParentID = comment.ParentID.Hex()
+	}
+	if comment.Deleted {
+		response.Body = ""
+		response.Mentions = nil
+	}
+	return response
+}
+
+// ToRevisionResponse converts a revision model into its response form
+func ToRevisionResponse(revision *models.Revision) RevisionResponse {
+	return RevisionResponse{
+		Body:       revision.Body,
+		Mentions:   revision.Mentions,
+		ReplacedBy: revision.ReplacedBy,
+		ReplacedAt: revision.ReplacedAt,
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CommentsCollection  = "comments"
+	RevisionsCollection = "comment_revisions"
+)
+
+// Comment is a comment on an object owned by another module, such as an SRP request or a
+// recruitment application. Replies point at their parent; ThreadID is the top-level comment the
+// reply belongs to (the comment itself for top-level comments).
+type Comment struct {
+	ID                primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ObjectType        string              `bson:"object_type" json:"object_type"`
+	ObjectID          string              `bson:"object_id" json:"object_id"`
+	ParentID          *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	ThreadID          primitive.ObjectID  `bson:"thread_id" json:"thread_id"`
+	Body              string              `bson:"body" json:"body"`
+	Mentions          []int               `bson:"mentions,omitempty" json:"mentions,omitempty"`
+	AuthorUserID      string              `bson:"author_user_id" json:"author_user_id"`
+	AuthorCharacterID int                 `bson:"author_character_id" json:"author_character_id"`
+	AuthorName        string              `bson:"author_name" json:"author_name"`
+	EditCount         int                 `bson:"edit_count" json:"edit_count"`
+	EditedAt          *time.Time          `bson:"edited_at,omitempty" json:"edited_at,omitempty"`
+	Deleted           bool                `bson:"deleted" json:"deleted"`
+	DeletedBy         int                 `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+	DeletedAt         *time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	CreatedAt         time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// Revision is a previous version of a comment's body, stored when the comment is edited or deleted
+type Revision struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CommentID primitive.ObjectID `bson:"comment_id" json:"comment_id"`
+	Body      string             `bson:"body" json:"body"`
+	Mentions  []int              `bson:"mentions,omitempty" json:"mentions,omitempty"`
+	// ReplacedBy is the character whose edit or deletion replaced this body
+	ReplacedBy int       `bson:"replaced_by" json:"replaced_by"`
+	ReplacedAt time.Time `bson:"replaced_at" json:"replaced_at"`
+}
@@ -0,0 +1,66 @@
+package comments
+
+import (
+	"context"
+
+	"go-falcon/internal/comments/routes"
+	"go-falcon/internal/comments/services"
+	"go-falcon/pkg/database"
+	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/module"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/go-chi/chi/v5"
+)
+
+// Module represents the comments module
+type Module struct {
+	*module.BaseModule
+	service              *services.Service
+	repository           *services.Repository
+	permissionMiddleware *middleware.PermissionMiddleware
+}
+
+// New creates a new comments module instance
+func New(mongodb *database.MongoDB, redis *database.Redis, permissionMiddleware *middleware.PermissionMiddleware) *Module {
+	repository := services.NewRepository(mongodb)
+	service := services.NewService(repository)
+
+	return &Module{
+		BaseModule:           module.NewBaseModule("comments", mongodb, redis),
+		service:              service,
+		repository:           repository,
+		permissionMiddleware: permissionMiddleware,
+	}
+}
+
+// SetNotifier sets where mention notifications are sent (the notifications service)
+func (m *Module) SetNotifier(notifier services.Notifier) {
+	m.service.SetNotifier(notifier)
+}
+
+// RegisterObjectType makes a module's objects commentable. The owning module supplies the
+// policy deciding who may view, comment on and moderate each object.
+func (m *Module) RegisterObjectType(objectType string, policy services.AccessPolicy) {
+	m.service.RegisterObjectType(objectType, policy)
+}
+
+// RegisterUnifiedRoutes registers all comments routes with the unified API gateway
+func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string) {
+	routes.RegisterCommentsRoutes(api, basePath, m.service, m.permissionMiddleware)
+}
+
+// Routes registers routes on a Chi router (implements module.Module interface)
+func (m *Module) Routes(r chi.Router) {
+	// Comments module uses only Huma v2 unified routes
+}
+
+// Initialize performs module initialization tasks
+func (m *Module) Initialize(ctx context.Context) error {
+	return m.repository.CreateIndexes(ctx)
+}
+
+// GetService returns the service instance for this module
+func (m *Module) GetService() *services.Service {
+	return m.service
+}
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+
+	"go-falcon/internal/comments/dto"
+	"go-falcon/internal/comments/services"
+	"go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// RegisterCommentsRoutes registers comments routes on a shared Huma API. Routes only
+// authenticate the caller; what they may do is decided by the policy of the object's owning module.
+func RegisterCommentsRoutes(api huma.API, basePath string, service *services.Service, permissionMiddleware *middleware.PermissionMiddleware) {
+	// Status endpoint (public, no auth required)
+	huma.Register(api, huma.Operation{
+		OperationID: "comments-get-status",
+		Method:      http.MethodGet,
+		Path:        basePath + "/status",
+		Summary:     "Get comments module status",
+		Description: "Returns the health status of the comments module and the object types that can be commented on",
+		Tags:        []string{"Module Status"},
+	}, func(ctx context.Context, input *struct{}) (*dto.StatusOutput, error) {
+		return &dto.StatusOutput{Body: *service.GetStatus(ctx)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "comments-list-comments",
+		Method:      http.MethodGet,
+		Path:        basePath + "/{object_type}/{object_id}",
+		Summary:     "List comments",
+		Description: "Lists the comments on an object, oldest first, including replies (requires view access to the object)",
+		Tags:        []string{"Comments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ListCommentsInput) (*dto.CommentListOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		return service.ListComments(ctx, user, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "comments-create-comment",
+		Method:        http.MethodPost,
+		Path:          basePath + "/{object_type}/{object_id}",
+		Summary:       "Create comment",
+		Description:   "Comments on an object or replies to one of its comments; mentioned characters that can view the object are notified",
+		Tags:          []string{"Comments"},
+		DefaultStatus: http.StatusCreated,
+		Security:      []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.CreateCommentInput) (*dto.CommentOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		return service.CreateComment(ctx, user, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "comments-update-comment",
+		Method:      http.MethodPut,
+		Path:        basePath + "/{object_type}/{object_id}/{comment_id}",
+		Summary:     "Update comment",
+		Description: "Edits a comment, keeping the previous text in its history (author or moderator)",
+		Tags:        []string{"Comments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UpdateCommentInput) (*dto.CommentOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		return service.UpdateComment(ctx, user, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "comments-delete-comment",
+		Method:      http.MethodDelete,
+		Path:        basePath + "/{object_type}/{object_id}/{comment_id}",
+		Summary:     "Delete comment",
+		Description: "Removes a comment's text while keeping its replies in place (author or moderator)",
+		Tags:        []string{"Comments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.CommentInput) (*dto.MessageOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		return service.DeleteComment(ctx, user, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "comments-get-comment-history",
+		Method:      http.MethodGet,
+		Path:        basePath + "/{object_type}/{object_id}/{comment_id}/history",
+		Summary:     "Get comment history",
+		Description: "Returns a comment with its previous versions; the history of deleted comments is limited to moderators",
+		Tags:        []string{"Comments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.CommentInput) (*dto.CommentHistoryOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		return service.GetCommentHistory(ctx, user, input)
+	})
+}
@@ -0,0 +1,54 @@
+package services
+
+import "context"
+
+// AccessPolicy decides who may read and write the comments on one kind of object. It is
+// implemented by the module that owns the objects, which knows e.g. that applicants may comment
+// on their own recruitment application but not on anyone else's.
+type AccessPolicy interface {
+	// CanView reports whether the character may read the object's comments. It also decides
+	// which mentioned characters are notified.
+	CanView(ctx context.Context, characterID int, objectID string) (bool, error)
+	// CanComment reports whether the character may comment on the object and edit or delete
+	// their own comments
+	CanComment(ctx context.Context, characterID int, objectID string) (bool, error)
+	// CanModerate reports whether the character may edit or delete anyone's comments on the
+	// object and read the history of deleted comments
+	CanModerate(ctx context.Context, characterID int, objectID string) (bool, error)
+}
+
+// PermissionChecker checks a character's permissions (implemented by the permission manager)
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, characterID int64, permissionID string) (bool, error)
+}
+
+// PermissionPolicy is an AccessPolicy for objects whose comments are guarded by fixed
+// permissions rather than per-object rules. An empty permission denies the action.
+type PermissionPolicy struct {
+	Checker  PermissionChecker
+	View     string
+	Comment  string
+	Moderate string
+}
+
+// CanView implements AccessPolicy
+func (p *PermissionPolicy) CanView(ctx context.Context, characterID int, objectID string) (bool, error) {
+	return p.has(ctx, characterID, p.View)
+}
+
+// CanComment implements AccessPolicy
+func (p *PermissionPolicy) CanComment(ctx context.Context, characterID int, objectID string) (bool, error) {
+	return p.has(ctx, characterID, p.Comment)
+}
+
+// CanModerate implements AccessPolicy
+func (p *PermissionPolicy) CanModerate(ctx context.Context, characterID int, objectID string) (bool, error) {
+	return p.has(ctx, characterID, p.Moderate)
+}
+
+func (p *PermissionPolicy) has(ctx context.Context, characterID int, permissionID string) (bool, error) {
+	if permissionID == "" {
+		return false, nil
+	}
+	return p.Checker.HasPermission(ctx, int64(characterID), permissionID)
+}
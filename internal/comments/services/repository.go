@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go-falcon/internal/comments/models"
+	"go-falcon/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// userProfilesCollection is owned by the auth module; comments only reads it to find the users
+// behind mentioned characters
+const userProfilesCollection = "user_profiles"
+
+type Repository struct {
+	db        *database.MongoDB
+	comments  *mongo.Collection
+	revisions *mongo.Collection
+}
+
+func NewRepository(db *database.MongoDB) *Repository {
+	return &Repository{
+		db:        db,
+		comments:  db.Database.Collection(models.CommentsCollection),
+		revisions: db.Database.Collection(models.RevisionsCollection),
+	}
+}
+
+// CreateComment inserts a comment. Top-level comments become their own thread.
+func (r *Repository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	comment.ID = primitive.NewObjectID()
+	if comment.ParentID == nil {
+		comment.ThreadID = comment.ID
+	}
+	_, err := r.comments.InsertOne(ctx, comment)
+	return err
+}
+
+// GetComment retrieves a comment on an object
+func (r *Repository) GetComment(ctx context.Context, objectType, objectID string, commentID primitive.ObjectID) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.comments.FindOne(ctx, bson.M{
+		"_id":         commentID,
+		"object_type": objectType,
+		"object_id":   objectID,
+	}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListComments returns a page of an object's comments, oldest first, and the total count
+func (r *Repository) ListComments(ctx context.Context, objectType, objectID string, page, limit int) ([]models.Comment, int64, error) {
+	filter := bson.M{"object_type": objectType, "object_id": objectID}
+
+	total, err := r.comments.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.comments.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []models.Comment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// UpdateComment keeps the comment's current body as a revision and then applies the update
+func (r *Repository) UpdateComment(ctx context.Context, comment *models.Comment, replacedBy int, update bson.M) (*models.Comment, error) {
+	revision := &models.Revision{
+		ID:         primitive.NewObjectID(),
+		CommentID:  comment.ID,
+		Body:       comment.Body,
+		Mentions:   comment.Mentions,
+		ReplacedBy: replacedBy,
+		ReplacedAt: time.Now(),
+	}
+	if _, err := r.revisions.InsertOne(ctx, revision); err != nil {
+		return nil, err
+	}
+
+	var updated models.Comment
+	err := r.comments.FindOneAndUpdate(ctx,
+		bson.M{"_id": comment.ID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ListRevisions returns the previous bodies of a comment, oldest first
+func (r *Repository) ListRevisions(ctx context.Context, commentID primitive.ObjectID) ([]models.Revision, error) {
+	cursor, err := r.revisions.Find(ctx,
+		bson.M{"comment_id": commentID},
+		options.Find().SetSort(bson.D{{Key: "replaced_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []models.Revision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetUserIDsByCharacterIDs maps characters to the users that own them; unknown characters are omitted
+func (r *Repository) GetUserIDsByCharacterIDs(ctx context.Context, characterIDs []int) (map[int]string, error) {
+	cursor, err := r.db.Database.Collection(userProfilesCollection).Find(ctx,
+		bson.M{"character_id": bson.M{"$in": characterIDs}},
+		options.Find().SetProjection(bson.M{"character_id": 1, "user_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	userIDs := make(map[int]string, len(characterIDs))
+	for cursor.Next(ctx) {
+		var profile struct {
+			CharacterID int    `bson:"character_id"`
+			UserID      string `bson:"user_id"`
+		}
+		if err := cursor.Decode(&profile); err != nil {
+			return nil, err
+		}
+		if profile.UserID != "" {
+			userIDs[profile.CharacterID] = profile.UserID
+		}
+	}
+	return userIDs, cursor.Err()
+}
+
+// CheckHealth verifies database connectivity
+func (r *Repository) CheckHealth(ctx context.Context) error {
+	return r.db.HealthCheck(ctx)
+}
+
+// CreateIndexes creates the indexes for comments and their revisions
+func (r *Repository) CreateIndexes(ctx context.Context) error {
+	if _, err := r.comments.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "object_type", Value: 1}, {Key: "object_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "author_character_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}); err != nil {
+		return err
+	}
+
+	_, err := r.revisions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "comment_id", Value: 1}, {Key: "replaced_at", Value: 1}},
+	})
+	return err
+}
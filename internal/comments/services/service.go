@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	authModels "go-falcon/internal/auth/models"
+	"go-falcon/internal/comments/dto"
+	"go-falcon/internal/comments/models"
+	notificationsDto "go-falcon/internal/notifications/dto"
+	notificationModels "go-falcon/internal/notifications/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mentionSnippetLength is how much of a comment is quoted in a mention notification
+const mentionSnippetLength = 200
+
+// Notifier sends notifications on behalf of a user (implemented by the notifications service)
+type Notifier interface {
+	Send(ctx context.Context, sender *authModels.AuthenticatedUser, input *notificationsDto.SendNotificationInput) (*notificationModels.Operation, error)
+}
+
+type Service struct {
+	repository *Repository
+	notifier   Notifier
+
+	policiesMu sync.RWMutex
+	policies   map[string]AccessPolicy
+}
+
+func NewService(repository *Repository) *Service {
+	return &Service{
+		repository: repository,
+		policies:   make(map[string]AccessPolicy),
+	}
+}
+
+// SetNotifier sets where mention notifications are sent
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// RegisterObjectType makes an object type commentable, with access decided by the owning module's policy
+func (s *Service) RegisterObjectType(objectType string, policy AccessPolicy) {
+	s.policiesMu.Lock()
+	defer s.policiesMu.Unlock()
+	s.policies[objectType] = policy
+}
+
+// ObjectTypes returns the registered object types
+func (s *Service) ObjectTypes() []string {
+	s.policiesMu.RLock()
+	defer s.policiesMu.RUnlock()
+
+	objectTypes := make([]string, 0, len(s.policies))
+	for objectType := range s.policies {
+		objectTypes = append(objectTypes, objectType)
+	}
+	sort.Strings(objectTypes)
+	return objectTypes
+}
+
+func (s *Service) policy(objectType string) (AccessPolicy, error) {
+	s.policiesMu.RLock()
+	defer s.policiesMu.RUnlock()
+
+	policy, ok := s.policies[objectType]
+	if !ok {
+		return nil, huma.Error404NotFound(fmt.Sprintf("Unknown object type %s", objectType))
+	}
+	return policy, nil
+}
+
+// authorize runs one of the policy's checks and turns a denial into a 403
+func authorize(ctx context.Context, check func(context.Context, int, string) (bool, error), user *authModels.AuthenticatedUser, objectID, action string) error {
+	allowed, err := check(ctx, user.CharacterID, objectID)
+	if err != nil {
+		return huma.Error500InternalServerError("Failed to check comment permissions", err)
+	}
+	if !allowed {
+		return huma.Error403Forbidden(fmt.Sprintf("Not allowed to %s on this object", action))
+	}
+	return nil
+}
+
+// ListComments returns a page of an object's comments
+func (s *Service) ListComments(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.ListCommentsInput) (*dto.CommentListOutput, error) {
+	policy, err := s.policy(input.ObjectType)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, policy.CanView, user, input.ObjectID, "view comments"); err != nil {
+		return nil, err
+	}
+
+	comments, total, err := s.repository.ListComments(ctx, input.ObjectType, input.ObjectID, input.Page, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list comments", err)
+	}
+
+	output := &dto.CommentListOutput{}
+	output.Body.ObjectType = input.ObjectType
+	output.Body.ObjectID = input.ObjectID
+	output.Body.Comments = make([]dto.CommentResponse, len(comments))
+	for i := range comments {
+		output.Body.Comments[i] = dto.ToCommentResponse(&comments[i])
+	}
+	output.Body.Total = total
+	output.Body.Page = input.Page
+	output.Body.Limit = input.Limit
+	return output, nil
+}
+
+// CreateComment posts a comment or a reply and notifies the mentioned characters
+func (s *Service) CreateComment(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.CreateCommentInput) (*dto.CommentOutput, error) {
+	policy, err := s.policy(input.ObjectType)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, policy.CanComment, user, input.ObjectID, "comment"); err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		ObjectType:        input.ObjectType,
+		ObjectID:          input.ObjectID,
+		Body:              input.Body.Body,
+		Mentions:          uniqueMentions(input.Body.Mentions),
+		AuthorUserID:      user.UserID,
+		AuthorCharacterID: user.CharacterID,
+		AuthorName:        user.CharacterName,
+		CreatedAt:         time.Now(),
+	}
+
+	if input.Body.ParentID != "" {
+		parent, err := s.getComment(ctx, input.ObjectType, input.ObjectID, input.Body.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		comment.ParentID = &parent.ID
+		comment.ThreadID = parent.ThreadID
+	}
+
+	if err := s.repository.CreateComment(ctx, comment); err != nil {
+		return nil, huma.Error500InternalServerError("Failed to create comment", err)
+	}
+
+	s.notifyMentions(ctx, user, policy, comment, comment.Mentions)
+	return &dto.CommentOutput{Body: dto.ToCommentResponse(comment)}, nil
+}
+
+// UpdateComment edits a comment, keeping the previous text in its history. Authors may edit
+// their own comments while they can still comment on the object; moderators may edit any.
+func (s *Service) UpdateComment(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.UpdateCommentInput) (*dto.CommentOutput, error) {
+	policy, err := s.policy(input.ObjectType)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.getComment(ctx, input.ObjectType, input.ObjectID, input.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Deleted {
+		return nil, huma.Error409Conflict("Deleted comments cannot be edited")
+	}
+	if err := s.authorizeChange(ctx, policy, user, comment, "edit this comment"); err != nil {
+		return nil, err
+	}
+
+	mentions := uniqueMentions(input.Body.Mentions)
+	now := time.Now()
+	updated, err := s.repository.UpdateComment(ctx, comment, user.CharacterID, bson.M{
+		"$set": bson.M{"body": input.Body.Body, "mentions": mentions, "edited_at": now},
+		"$inc": bson.M{"edit_count": 1},
+	})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to update comment", err)
+	}
+
+	previous := make(map[int]bool, len(comment.Mentions))
+	for _, characterID := range comment.Mentions {
+		previous[characterID] = true
+	}
+	var added []int
+	for _, characterID := range mentions {
+		if !previous[characterID] {
+			added = append(added, characterID)
+		}
+	}
+	s.notifyMentions(ctx, user, policy, updated, added)
+
+	return &dto.CommentOutput{Body: dto.ToCommentResponse(updated)}, nil
+}
+
+// DeleteComment removes a comment's text while keeping its place in the thread so replies stay
+// attached. The text is kept in the comment's history, visible to moderators only.
+func (s *Service) DeleteComment(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.CommentInput) (*dto.MessageOutput, error) {
+	policy, err := s.policy(input.ObjectType)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.getComment(ctx, input.ObjectType, input.ObjectID, input.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Deleted {
+		return nil, huma.Error404NotFound(fmt.Sprintf("Comment %s not found", input.CommentID))
+	}
+	if err := s.authorizeChange(ctx, policy, user, comment, "delete this comment"); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := s.repository.UpdateComment(ctx, comment, user.CharacterID, bson.M{
+		"$set":   bson.M{"body": "", "deleted": true, "deleted_by": user.CharacterID, "deleted_at": now},
+		"$unset": bson.M{"mentions": ""},
+	}); err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete comment", err)
+	}
+
+	output := &dto.MessageOutput{}
+	output.Body.Message = "Comment deleted"
+	return output, nil
+}
+
+// GetCommentHistory returns a comment with its previous versions. The history of a deleted
+// comment holds its removed text and is only shown to moderators.
+func (s *Service) GetCommentHistory(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.CommentInput) (*dto.CommentHistoryOutput, error) {
+	policy, err := s.policy(input.ObjectType)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, policy.CanView, user, input.ObjectID, "view comments"); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.getComment(ctx, input.ObjectType, input.ObjectID, input.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Deleted {
+		if err := authorize(ctx, policy.CanModerate, user, input.ObjectID, "view deleted comments"); err != nil {
+			return nil, err
+		}
+	}
+
+	revisions, err := s.repository.ListRevisions(ctx, comment.ID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get comment history", err)
+	}
+
+	output := &dto.CommentHistoryOutput{}
+	output.Body.Comment = dto.ToCommentResponse(comment)
+	output.Body.Revisions = make([]dto.RevisionResponse, len(revisions))
+	for i := range revisions {
+		output.Body.Revisions[i] = dto.ToRevisionResponse(&revisions[i])
+	}
+	return output, nil
+}
+
+// GetStatus returns the health status of the comments module
+func (s *Service) GetStatus(ctx context.Context) *dto.CommentsStatusResponse {
+	status := &dto.CommentsStatusResponse{
+		Module:      "comments",
+		Status:      "healthy",
+		ObjectTypes: s.ObjectTypes(),
+	}
+	if err := s.repository.CheckHealth(ctx); err != nil {
+		status.Status = "unhealthy"
+		status.Message = "Database connection failed: " + err.Error()
+	}
+	return status
+}
+
+func (s *Service) getComment(ctx context.Context, objectType, objectID, commentID string) (*models.Comment, error) {
+	id, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("Invalid comment ID %s", commentID))
+	}
+
+	comment, err := s.repository.GetComment(ctx, objectType, objectID, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get comment", err)
+	}
+	if comment == nil {
+		return nil, huma.Error404NotFound(fmt.Sprintf("Comment %s not found", commentID))
+	}
+	return comment, nil
+}
+
+// authorizeChange allows authors who may still comment on the object, and moderators
+func (s *Service) authorizeChange(ctx context.Context, policy AccessPolicy, user *authModels.AuthenticatedUser, comment *models.Comment, action string) error {
+	if comment.AuthorCharacterID == user.CharacterID {
+		if allowed, err := policy.CanComment(ctx, user.CharacterID, comment.ObjectID); err == nil && allowed {
+			return nil
+		}
+	}
+	return authorize(ctx, policy.CanModerate, user, comment.ObjectID, action)
+}
+
+// notifyMentions notifies the users behind the mentioned characters that can view the object.
+// Failures are logged; a comment is never rejected because a notification could not be sent.
+func (s *Service) notifyMentions(ctx context.Context, author *authModels.AuthenticatedUser, policy AccessPolicy, comment *models.Comment, mentions []int) {
+	if s.notifier == nil || len(mentions) == 0 {
+		return
+	}
+
+	var viewers []int
+	for _, characterID := range mentions {
+		if characterID == author.CharacterID {
+			continue
+		}
+		allowed, err := policy.CanView(ctx, characterID, comment.ObjectID)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to check mentioned character's access", "character_id", characterID, "object_type", comment.ObjectType, "error", err)
+			continue
+		}
+		if allowed {
+			viewers = append(viewers, characterID)
+		}
+	}
+	if len(viewers) == 0 {
+		return
+	}
+
+	userIDs, err := s.repository.GetUserIDsByCharacterIDs(ctx, viewers)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to resolve mentioned characters", "comment_id", comment.ID.Hex(), "error", err)
+		return
+	}
+
+	seen := make(map[string]bool, len(userIDs))
+	recipients := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID != author.UserID && !seen[userID] {
+			seen[userID] = true
+			recipients = append(recipients, userID)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	snippet := []rune(comment.Body)
+	if len(snippet) > mentionSnippetLength {
+		snippet = append(snippet[:mentionSnippetLength], '…')
+	}
+
+	input := &notificationsDto.SendNotificationInput{}
+	input.Body.Title = fmt.Sprintf("%s mentioned you", author.CharacterName)
+	input.Body.Message = fmt.Sprintf("%s mentioned you in a comment on %s %s:\n\n%s", author.CharacterName, comment.ObjectType, comment.ObjectID, string(snippet))
+	input.Body.Level = "info"
+	input.Body.Audience = []notificationsDto.AudienceSelectorInput{{Type: string(notificationModels.AudienceUsers), UserIDs: recipients}}
+
+	if _, err := s.notifier.Send(ctx, author, input); err != nil {
+		slog.ErrorContext(ctx, "Failed to send mention notification", "comment_id", comment.ID.Hex(), "error", err)
+	}
+}
+
+// uniqueMentions drops duplicate and invalid character IDs, keeping the original order
+func uniqueMentions(characterIDs []int) []int {
+	seen := make(map[int]bool, len(characterIDs))
+	var unique []int
+	for _, characterID := range characterIDs {
+		if characterID > 0 && !seen[characterID] {
+			seen[characterID] = true
+			unique = append(unique, characterID)
+		}
+	}
+	return unique
+}
@@ -52,3 +52,111 @@ type GetCorporationMembersInput struct {
 	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
 	Cookie        string `header:"Cookie" description:"Authentication cookie"`
 }
+
+// ImportContainerLogsInput represents the input for importing corporation container audit logs
+type ImportContainerLogsInput struct {
+	CorporationID int    `path:"corporation_id" minimum:"1" description:"Corporation ID to import container logs for" example:"98701142"`
+	DirectorID    int    `query:"director_id" minimum:"1" description:"Director character of your account whose token is used to read the logs" example:"661916654"`
+	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Authentication cookie"`
+}
+
+// GetContainerLogsInput represents the input for querying stored corporation container audit logs
+type GetContainerLogsInput struct {
+	CorporationID int    `path:"corporation_id" minimum:"1" description:"Corporation ID to query container logs for" example:"98701142"`
+	Division      int    `query:"division" minimum:"0" maximum:"7" description:"Hangar division (1-7) to restrict results to" example:"3"`
+	LocationFlag  string `query:"location_flag" description:"Raw location flag to filter by (overrides division)" example:"CorpSAG3"`
+	CharacterID   int    `query:"character_id" minimum:"0" description:"Only entries performed by this character" example:"661916654"`
+	ContainerID   int64  `query:"container_id" minimum:"0" description:"Only entries for this container"`
+	TypeID        int    `query:"type_id" minimum:"0" description:"Only entries involving this item type" example:"34"`
+	Action        string `query:"action" enum:"add,assemble,configure,enter_password,lock,move,repackage,set_name,set_password,unlock" description:"Only entries with this action"`
+	From          string `query:"from" description:"Start of the time range (RFC3339)" example:"2025-01-01T00:00:00Z"`
+	To            string `query:"to" description:"End of the time range (RFC3339)" example:"2025-01-31T23:59:59Z"`
+	Page          int    `query:"page" minimum:"1" default:"1" description:"Page number"`
+	PageSize      int    `query:"page_size" minimum:"1" maximum:"500" default:"100" description:"Entries per page"`
+	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Authentication cookie"`
+}
+
+// CorporationWalletsInput identifies a corporation's wallets
+type CorporationWalletsInput struct {
+	CorporationID int    `path:"corporation_id" minimum:"1" description:"Corporation ID" example:"98701142"`
+	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Authentication cookie"`
+}
+
+// GetWalletJournalInput represents the input for reading a wallet division's journal
+type GetWalletJournalInput struct {
+	CorporationID int    `path:"corporation_id" minimum:"1" description:"Corporation ID" example:"98701142"`
+	Division      int    `path:"division" minimum:"1" maximum:"7" description:"Wallet division (1 is the master wallet)" example:"1"`
+	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Authentication cookie"`
+}
+
+// WalletDivisionRequest configures one wallet division
+type WalletDivisionRequest struct {
+	Division       int    `json:"division" minimum:"1" maximum:"7" description:"Wallet division (1 is the master wallet)"`
+	Name           string `json:"name" minLength:"1" maxLength:"50" description:"Friendly name shown instead of the division number" example:"SRP Fund"`
+	ViewPermission string `json:"view_permission,omitempty" maxLength:"100" description:"Permission required to read the division (defaults to corporation:wallets:view)" example:"srp:requests:manage"`
+}
+
+// UpdateWalletSettingsInput represents the input for configuring a corporation's wallet divisions
+type UpdateWalletSettingsInput struct {
+	CorporationID int    `path:"corporation_id" minimum:"1" description:"Corporation ID" example:"98701142"`
+	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Authentication cookie"`
+	Body          struct {
+		TokenCharacterID int                     `json:"token_character_id" minimum:"1" description:"Character whose token reads the wallets (needs esi-wallet.read_corporation_wallets.v1 and the Accountant or Junior Accountant role)" example:"661916654"`
+		Divisions        []WalletDivisionRequest `json:"divisions" maxItems:"7" description:"Division settings; divisions left out keep their default name and permission"`
+	}
+}
+
+// BudgetAlertRequest is the body of a budget alert rule
+type BudgetAlertRequest struct {
+	Name      string  `json:"name" minLength:"1" maxLength:"100" description:"Rule name shown in alerts" example:"SRP fund running low"`
+	Type      string  `json:"type" enum:"balance_below,large_transaction" description:"Alert when the balance drops below the threshold, or when a single journal entry moves at least the threshold"`
+	Division  int     `json:"division" minimum:"1" maximum:"7" description:"Wallet division the rule watches"`
+	Threshold float64 `json:"threshold" exclusiveMinimum:"0" description:"Threshold in ISK" example:"5000000000"`
+	GroupID   string  `json:"group_id" minLength:"1" description:"Group whose members are notified (e.g. finance officers)"`
+	Enabled   *bool   `json:"enabled,omitempty" description:"Whether the rule is evaluated (default true)"`
+}
+
+// CreateBudgetAlertInput represents the input for creating a budget alert rule
+type CreateBudgetAlertInput struct {
+	CorporationID int    `path:"corporation_id" minimum:"1" description:"Corporation ID" example:"98701142"`
+	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Authentication cookie"`
+	Body          BudgetAlertRequest
+}
+
+// UpdateBudgetAlertInput represents the input for replacing a budget alert rule
+type UpdateBudgetAlertInput struct {
+	CorporationID int    `path:"corporation_id" minimum:"1" description:"Corporation ID" example:"98701142"`
+	AlertID       string `path:"alert_id" description:"Budget alert rule ID"`
+	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Authentication cookie"`
+	Body          BudgetAlertRequest
+}
+
+// BudgetAlertInput identifies a budget alert rule
+type BudgetAlertInput struct {
+	CorporationID int    `path:"corporation_id" minimum:"1" description:"Corporation ID" example:"98701142"`
+	AlertID       string `path:"alert_id" description:"Budget alert rule ID"`
+	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Authentication cookie"`
+}
+
+// GetAssetMovementsInput represents the input for querying detected hangar asset movements
+type GetAssetMovementsInput struct {
+	CorporationID int    `path:"corporation_id" minimum:"1" description:"Corporation ID to query asset movements for" example:"98701142"`
+	Division      int    `query:"division" minimum:"0" maximum:"7" description:"Hangar division (1-7) items left or entered" example:"3"`
+	LocationFlag  string `query:"location_flag" description:"Raw location flag to filter by (overrides division)" example:"CorpSAG3"`
+	TypeID        int    `query:"type_id" minimum:"0" description:"Only movements of this item type" example:"34"`
+	Kind          string `query:"kind" enum:"added,removed,moved,changed" description:"Only movements of this kind"`
+	From          string `query:"from" description:"Start of the time range (RFC3339)" example:"2025-01-01T00:00:00Z"`
+	To            string `query:"to" description:"End of the time range (RFC3339)" example:"2025-01-31T23:59:59Z"`
+	Page          int    `query:"page" minimum:"1" default:"1" description:"Page number"`
+	PageSize      int    `query:"page_size" minimum:"1" maximum:"500" default:"100" description:"Entries per page"`
+	Authorization string `header:"Authorization" description:"JWT Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Authentication cookie"`
+}
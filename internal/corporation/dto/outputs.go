@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"go-falcon/internal/corporation/models"
+)
 
 // CharacterInfo represents basic character information
 type CharacterInfo struct {
@@ -176,3 +180,155 @@ type CorporationMembersResult struct {
 type CorporationMembersOutput struct {
 	Body CorporationMembersResult `json:"body"`
 }
+
+// ContainerLogImportResult represents the outcome of a container log import
+type ContainerLogImportResult struct {
+	CorporationID int `json:"corporation_id" description:"Corporation ID"`
+	Fetched       int `json:"fetched" description:"Number of log entries returned by ESI"`
+	Inserted      int `json:"inserted" description:"Number of log entries not previously stored"`
+
+	AssetsImported bool `json:"assets_imported" description:"Whether hangar assets were read; needs esi-assets.read_corporation_assets.v1 on the director token"`
+	AssetMovements int  `json:"asset_movements" description:"Number of hangar asset movements detected since the previous import"`
+}
+
+// ContainerLogImportOutput represents the container log import response (Huma wrapper)
+type ContainerLogImportOutput struct {
+	Body ContainerLogImportResult `json:"body"`
+}
+
+// ContainerLogEntry represents a single container audit log entry
+type ContainerLogEntry struct {
+	LoggedAt         time.Time `json:"logged_at" description:"When the action happened"`
+	ContainerID      int64     `json:"container_id" description:"Item ID of the container"`
+	ContainerTypeID  int       `json:"container_type_id" description:"Type ID of the container"`
+	CharacterID      int       `json:"character_id" description:"Character who performed the action"`
+	CharacterName    string    `json:"character_name,omitempty" description:"Name of the character who performed the action"`
+	LocationID       int64     `json:"location_id" description:"Station or structure the container is in"`
+	LocationFlag     string    `json:"location_flag" description:"Location flag of the container, e.g. CorpSAG3"`
+	Division         int       `json:"division,omitempty" description:"Hangar division derived from the location flag"`
+	Action           string    `json:"action" description:"Action performed on the container"`
+	PasswordType     string    `json:"password_type,omitempty" description:"Password type for password actions"`
+	TypeID           int       `json:"type_id,omitempty" description:"Type ID of the item moved"`
+	TypeName         string    `json:"type_name,omitempty" description:"Name of the item moved"`
+	Quantity         int       `json:"quantity,omitempty" description:"Quantity of the item moved"`
+	OldConfigBitmask int       `json:"old_config_bitmask,omitempty" description:"Container configuration before a configure action"`
+	NewConfigBitmask int       `json:"new_config_bitmask,omitempty" description:"Container configuration after a configure action"`
+}
+
+// ContainerLogsResult represents a page of container audit log entries
+type ContainerLogsResult struct {
+	CorporationID int                 `json:"corporation_id" description:"Corporation ID"`
+	Entries       []ContainerLogEntry `json:"entries" description:"Container log entries, newest first"`
+	Total         int64               `json:"total" description:"Total entries matching the filter"`
+	Page          int                 `json:"page" description:"Current page"`
+	PageSize      int                 `json:"page_size" description:"Entries per page"`
+}
+
+// ContainerLogsOutput represents the container logs response (Huma wrapper)
+type ContainerLogsOutput struct {
+	Body ContainerLogsResult `json:"body"`
+}
+
+// AssetMovementEntry represents a single detected hangar asset movement
+type AssetMovementEntry struct {
+	Kind             string    `json:"kind" description:"added, removed, moved (to another division or location) or changed (quantity)"`
+	ItemID           int64     `json:"item_id" description:"Item ID of the stack"`
+	TypeID           int       `json:"type_id" description:"Type ID of the stack"`
+	TypeName         string    `json:"type_name,omitempty" description:"Name of the item type"`
+	FromLocationID   int64     `json:"from_location_id,omitempty" description:"Station or structure the stack was in"`
+	FromLocationFlag string    `json:"from_location_flag,omitempty" description:"Location flag the stack was in"`
+	FromDivision     int       `json:"from_division,omitempty" description:"Hangar division the stack was in"`
+	ToLocationID     int64     `json:"to_location_id,omitempty" description:"Station or structure the stack is in"`
+	ToLocationFlag   string    `json:"to_location_flag,omitempty" description:"Location flag the stack is in"`
+	ToDivision       int       `json:"to_division,omitempty" description:"Hangar division the stack is in"`
+	QuantityChange   int       `json:"quantity_change" description:"Change of the stack's quantity; negative when items were taken"`
+	PreviousImportAt time.Time `json:"previous_import_at" description:"Import before which the movement had not happened"`
+	DetectedAt       time.Time `json:"detected_at" description:"Import that detected the movement"`
+}
+
+// AssetMovementsResult represents a page of detected asset movements
+type AssetMovementsResult struct {
+	CorporationID int                  `json:"corporation_id" description:"Corporation ID"`
+	Entries       []AssetMovementEntry `json:"entries" description:"Asset movements, newest first"`
+	Total         int64                `json:"total" description:"Total movements matching the filter"`
+	Page          int                  `json:"page" description:"Current page"`
+	PageSize      int                  `json:"page_size" description:"Entries per page"`
+}
+
+// AssetMovementsOutput represents the asset movements response (Huma wrapper)
+type AssetMovementsOutput struct {
+	Body AssetMovementsResult `json:"body"`
+}
+
+// WalletDivisionBalance represents the balance of one wallet division
+type WalletDivisionBalance struct {
+	Division int     `json:"division" description:"Wallet division (1 is the master wallet)"`
+	Name     string  `json:"name" description:"Friendly name of the division"`
+	Balance  float64 `json:"balance" description:"Balance in ISK"`
+}
+
+// CorporationWalletsResult represents the wallet divisions the caller may read
+type CorporationWalletsResult struct {
+	CorporationID int                     `json:"corporation_id" description:"Corporation ID"`
+	Divisions     []WalletDivisionBalance `json:"divisions" description:"Divisions the caller has access to"`
+	Cached        bool                    `json:"cached" description:"Whether the balances were served from cache"`
+	ExpiresAt     *time.Time              `json:"expires_at,omitempty" description:"When ESI refreshes the balances"`
+}
+
+// CorporationWalletsOutput represents the corporation wallets response (Huma wrapper)
+type CorporationWalletsOutput struct {
+	Body CorporationWalletsResult `json:"body"`
+}
+
+// WalletJournalEntry represents a single wallet journal entry
+type WalletJournalEntry struct {
+	ID            int64     `json:"id" description:"Journal reference ID"`
+	Date          time.Time `json:"date" description:"When the entry was made"`
+	RefType       string    `json:"ref_type" description:"Kind of transaction, e.g. player_donation"`
+	Description   string    `json:"description" description:"ESI description of the entry"`
+	Amount        float64   `json:"amount" description:"Amount in ISK; negative for withdrawals"`
+	Balance       float64   `json:"balance" description:"Division balance after the entry"`
+	FirstPartyID  int32     `json:"first_party_id,omitempty" description:"First party of the transaction"`
+	SecondPartyID int32     `json:"second_party_id,omitempty" description:"Second party of the transaction"`
+	Reason        string    `json:"reason,omitempty" description:"Reason given for the transaction"`
+}
+
+// WalletJournalResult represents a wallet division's journal
+type WalletJournalResult struct {
+	CorporationID int                  `json:"corporation_id" description:"Corporation ID"`
+	Division      int                  `json:"division" description:"Wallet division"`
+	Name          string               `json:"name" description:"Friendly name of the division"`
+	Entries       []WalletJournalEntry `json:"entries" description:"Journal entries, newest first"`
+	Cached        bool                 `json:"cached" description:"Whether the journal was served from cache"`
+	ExpiresAt     *time.Time           `json:"expires_at,omitempty" description:"When ESI refreshes the journal"`
+}
+
+// WalletJournalOutput represents the wallet journal response (Huma wrapper)
+type WalletJournalOutput struct {
+	Body WalletJournalResult `json:"body"`
+}
+
+// WalletSettingsOutput represents the wallet settings response (Huma wrapper)
+type WalletSettingsOutput struct {
+	Body models.WalletSettings `json:"body"`
+}
+
+// BudgetAlertOutput represents a budget alert rule response (Huma wrapper)
+type BudgetAlertOutput struct {
+	Body models.BudgetAlertRule `json:"body"`
+}
+
+// BudgetAlertListOutput represents the budget alert rules of a corporation (Huma wrapper)
+type BudgetAlertListOutput struct {
+	Body struct {
+		CorporationID int                       `json:"corporation_id" description:"Corporation ID"`
+		Alerts        []*models.BudgetAlertRule `json:"alerts" description:"Budget alert rules, oldest first"`
+	} `json:"body"`
+}
+
+// BudgetAlertDeleteOutput represents the response of deleting a budget alert rule (Huma wrapper)
+type BudgetAlertDeleteOutput struct {
+	Body struct {
+		Message string `json:"message" description:"Result message"`
+	} `json:"body"`
+}
@@ -64,9 +64,139 @@ type Structure struct {
 	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
 }
 
+// ContainerLog represents a secure container audit log entry imported from ESI
+type ContainerLog struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CorporationID    int                `bson:"corporation_id" json:"corporation_id"`
+	LoggedAt         time.Time          `bson:"logged_at" json:"logged_at"`
+	ContainerID      int64              `bson:"container_id" json:"container_id"`
+	ContainerTypeID  int                `bson:"container_type_id" json:"container_type_id"`
+	CharacterID      int                `bson:"character_id" json:"character_id"`
+	LocationID       int64              `bson:"location_id" json:"location_id"`
+	LocationFlag     string             `bson:"location_flag" json:"location_flag"`
+	Action           string             `bson:"action" json:"action"`
+	PasswordType     string             `bson:"password_type,omitempty" json:"password_type,omitempty"`
+	TypeID           int                `bson:"type_id,omitempty" json:"type_id,omitempty"`
+	Quantity         int                `bson:"quantity,omitempty" json:"quantity,omitempty"`
+	OldConfigBitmask int                `bson:"old_config_bitmask,omitempty" json:"old_config_bitmask,omitempty"`
+	NewConfigBitmask int                `bson:"new_config_bitmask,omitempty" json:"new_config_bitmask,omitempty"`
+
+	// Metadata
+	ImportedAt time.Time `bson:"imported_at" json:"imported_at"`
+}
+
+// HangarSnapshot is the content of a corporation's hangar divisions at the last import, which the
+// next import is compared with to detect asset movements
+type HangarSnapshot struct {
+	CorporationID int          `bson:"corporation_id" json:"corporation_id"`
+	Items         []HangarItem `bson:"items" json:"items"`
+	TakenAt       time.Time    `bson:"taken_at" json:"taken_at"`
+}
+
+// HangarItem is an item stack lying directly in a hangar division
+type HangarItem struct {
+	ItemID       int64  `bson:"item_id" json:"item_id"`
+	TypeID       int    `bson:"type_id" json:"type_id"`
+	LocationID   int64  `bson:"location_id" json:"location_id"`
+	LocationFlag string `bson:"location_flag" json:"location_flag"`
+	Quantity     int    `bson:"quantity" json:"quantity"`
+}
+
+// Asset movement kinds
+const (
+	AssetMovementAdded   = "added"   // A stack appeared in a hangar division
+	AssetMovementRemoved = "removed" // A stack left the hangar divisions
+	AssetMovementMoved   = "moved"   // A stack moved to another division or location
+	AssetMovementChanged = "changed" // A stack's quantity changed in place
+)
+
+// AssetMovement is a change of a corporation's hangar contents between two imports. ESI does not
+// say who made it; the container logs and member tracking of the same period narrow it down.
+type AssetMovement struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CorporationID    int                `bson:"corporation_id" json:"corporation_id"`
+	Kind             string             `bson:"kind" json:"kind"`
+	ItemID           int64              `bson:"item_id" json:"item_id"`
+	TypeID           int                `bson:"type_id" json:"type_id"`
+	FromLocationID   int64              `bson:"from_location_id,omitempty" json:"from_location_id,omitempty"`
+	FromLocationFlag string             `bson:"from_location_flag,omitempty" json:"from_location_flag,omitempty"`
+	ToLocationID     int64              `bson:"to_location_id,omitempty" json:"to_location_id,omitempty"`
+	ToLocationFlag   string             `bson:"to_location_flag,omitempty" json:"to_location_flag,omitempty"`
+	QuantityChange   int                `bson:"quantity_change" json:"quantity_change"`
+
+	// The movement happened between these two imports
+	PreviousImportAt time.Time `bson:"previous_import_at" json:"previous_import_at"`
+	DetectedAt       time.Time `bson:"detected_at" json:"detected_at"`
+}
+
+// WalletSettings configures how a corporation's wallet divisions are shown and who may read them
+type WalletSettings struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CorporationID    int                `bson:"corporation_id" json:"corporation_id"`
+	TokenCharacterID int                `bson:"token_character_id" json:"token_character_id"`
+	Divisions        []WalletDivision   `bson:"divisions" json:"divisions"`
+
+	// Metadata
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	UpdatedBy string    `bson:"updated_by" json:"updated_by"`
+}
+
+// WalletDivision is the configuration of one wallet division
+type WalletDivision struct {
+	Division       int    `bson:"division" json:"division"`
+	Name           string `bson:"name" json:"name"`
+	ViewPermission string `bson:"view_permission" json:"view_permission"`
+}
+
+// Budget alert rule types
+const (
+	BudgetAlertBalanceBelow     = "balance_below"
+	BudgetAlertLargeTransaction = "large_transaction"
+)
+
+// BudgetAlertRule notifies a group when a wallet division's balance drops below a threshold or a
+// single journal entry moves more than a threshold
+type BudgetAlertRule struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CorporationID int                `bson:"corporation_id" json:"corporation_id"`
+	Name          string             `bson:"name" json:"name"`
+	Type          string             `bson:"type" json:"type"`
+	Division      int                `bson:"division" json:"division"`
+	Threshold     float64            `bson:"threshold" json:"threshold"`
+	GroupID       string             `bson:"group_id" json:"group_id"`
+	Enabled       bool               `bson:"enabled" json:"enabled"`
+
+	// Evaluation state: whether a balance alert is currently raised (it is raised again only after
+	// the balance recovered) and the newest journal entry already checked for large transactions
+	Triggered       bool       `bson:"triggered" json:"triggered"`
+	LastJournalID   int64      `bson:"last_journal_id,omitempty" json:"-"`
+	LastTriggeredAt *time.Time `bson:"last_triggered_at,omitempty" json:"last_triggered_at,omitempty"`
+
+	// Metadata; alerts are sent on behalf of the rule's creator
+	CreatedByUserID      string    `bson:"created_by_user_id" json:"created_by_user_id"`
+	CreatedByCharacterID int       `bson:"created_by_character_id" json:"created_by_character_id"`
+	CreatedByName        string    `bson:"created_by_name" json:"created_by_name"`
+	CreatedAt            time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt            time.Time `bson:"updated_at" json:"updated_at"`
+}
+
 // Constants for collection names
 const (
 	CorporationCollection             = "corporations"
 	TrackCorporationMembersCollection = "track_corporation_members"
 	StructuresCollection              = "structures"
+	ContainerLogsCollection           = "corporation_container_logs"
+	HangarSnapshotsCollection         = "corporation_hangar_snapshots"
+	AssetMovementsCollection          = "corporation_asset_movements"
+	WalletSettingsCollection          = "corporation_wallet_settings"
+	BudgetAlertsCollection            = "corporation_budget_alerts"
 )
+
+// ContainerLogsScope is the ESI scope required to read corporation container logs
+const ContainerLogsScope = "esi-corporations.read_container_logs.v1"
+
+// AssetsScope is the ESI scope required to read corporation assets, from which asset movements are detected
+const AssetsScope = "esi-assets.read_corporation_assets.v1"
+
+// WalletScope is the ESI scope required to read corporation wallet balances and journals
+const WalletScope = "esi-wallet.read_corporation_wallets.v1"
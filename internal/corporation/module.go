@@ -60,6 +60,11 @@ func NewModule(mongodb *database.MongoDB, redis *database.Redis, eveClient *eveg
 	return m
 }
 
+// Initialize creates database indexes for the corporation module
+func (m *Module) Initialize(ctx context.Context) error {
+	return m.service.CreateIndexes(ctx)
+}
+
 // SetGroupService sets the groups service dependency
 func (m *Module) SetGroupService(groupService *groupsServices.Service) {
 	m.groupService = groupService
@@ -80,6 +85,10 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string) {
 				permissionManager = m.groupService.GetPermissionManager()
 			}
 
+			if permissionManager != nil {
+				m.service.SetPermissionChecker(permissionManager)
+			}
+
 			// Create centralized permission middleware
 			permissionMiddleware := middleware.NewPermissionMiddleware(authService, permissionManager)
 			// Create corporation adapter
@@ -128,6 +137,26 @@ func (m *Module) ValidateCEOTokens(ctx context.Context) error {
 	return m.service.ValidateCEOTokens(ctx)
 }
 
+// SetUpdateRecorder sets where scheduled corporation update outcomes are recorded (the ESI retry queue)
+func (m *Module) SetUpdateRecorder(recorder services.UpdateRecorder) {
+	m.service.SetUpdateRecorder(recorder)
+}
+
+// RefreshCorporation fetches one corporation from ESI and stores it
+func (m *Module) RefreshCorporation(ctx context.Context, corporationID int64) error {
+	return m.service.RefreshCorporation(ctx, corporationID)
+}
+
+// SetNotifier sets where budget alerts are sent (the notifications service)
+func (m *Module) SetNotifier(notifier services.Notifier) {
+	m.service.SetNotifier(notifier)
+}
+
+// EvaluateBudgetAlerts implements the scheduler's CorporationModule interface for budget alerts
+func (m *Module) EvaluateBudgetAlerts(ctx context.Context) (int, error) {
+	return m.service.EvaluateBudgetAlerts(ctx)
+}
+
 // RegisterPermissions registers corporation-specific permissions
 func (m *Module) RegisterPermissions(ctx context.Context, permissionManager *permissions.PermissionManager) error {
 	corporationPermissions := []permissions.Permission{
@@ -175,6 +204,39 @@ func (m *Module) RegisterPermissions(ctx context.Context, permissionManager *per
 			Category:    "Corporation Management",
 			CreatedAt:   time.Now(),
 		},
+		{
+			ID:          "corporation:containerlogs:view",
+			Service:     "corporation",
+			Resource:    "containerlogs",
+			Action:      "view",
+			IsStatic:    false,
+			Name:        "View Corporation Container Logs",
+			Description: "Import and query corporation container audit logs showing who moved items in which hangar division. Intended for directors",
+			Category:    "Corporation Management",
+			CreatedAt:   time.Now(),
+		},
+		{
+			ID:          "corporation:wallets:view",
+			Service:     "corporation",
+			Resource:    "wallets",
+			Action:      "view",
+			IsStatic:    false,
+			Name:        "View Corporation Wallets",
+			Description: "View corporation wallet balances and journals. Divisions configured with their own permission additionally require that permission",
+			Category:    "Corporation Management",
+			CreatedAt:   time.Now(),
+		},
+		{
+			ID:          "corporation:wallets:manage",
+			Service:     "corporation",
+			Resource:    "wallets",
+			Action:      "manage",
+			IsStatic:    false,
+			Name:        "Manage Corporation Wallets",
+			Description: "Configure wallet division names and permissions and the budget alerts sent to finance officers",
+			Category:    "Corporation Management",
+			CreatedAt:   time.Now(),
+		},
 	}
 
 	return permissionManager.RegisterServicePermissions(ctx, corporationPermissions)
@@ -2,11 +2,15 @@ package routes
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	authModels "go-falcon/internal/auth/models"
 	"go-falcon/internal/corporation/dto"
 	"go-falcon/internal/corporation/services"
+	"go-falcon/pkg/apidocs"
 	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/residency"
 
 	"github.com/danielgtaylor/huma/v2"
 )
@@ -73,6 +77,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string, corporatio
 		Summary:     "Track Corporation Members",
 		Description: "Retrieves member tracking information for a corporation. Requires authentication and 'corporation:membertracking:view' permission. Updates the tracking data in the database.",
 		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:membertracking:view"),
 	}, func(ctx context.Context, input *dto.GetCorporationMemberTrackingInput) (*dto.CorporationMemberTrackingOutput, error) {
 		// Require specific member tracking permission
 		if corporationAdapter != nil {
@@ -86,6 +91,77 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string, corporatio
 		return m.service.GetMemberTracking(ctx, input.CorporationID, input.CEOID)
 	})
 
+	// Container log import endpoint (authenticated, requires container log permission and a director token)
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-import-container-logs",
+		Method:      "POST",
+		Path:        basePath + "/{corporation_id}/container-logs/import",
+		Summary:     "Import Corporation Container Logs",
+		Description: "Imports secure container audit logs from ESI using the token of one of your own director characters (requires esi-corporations.read_container_logs.v1 and the in-game Director role). When the token also has esi-assets.read_corporation_assets.v1, hangar asset movements since the previous import are recorded. ESI only keeps recent entries, so imports should run regularly. Requires 'corporation:containerlogs:view' permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:containerlogs:view"),
+	}, func(ctx context.Context, input *dto.ImportContainerLogsInput) (*dto.ContainerLogImportOutput, error) {
+		user, err := requireContainerLogUser(ctx, corporationAdapter, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.ImportContainerLogs(ctx, user, input.CorporationID, input.DirectorID)
+		if err != nil {
+			if errors.Is(err, services.ErrCorporationAccessDenied) {
+				return nil, huma.Error403Forbidden(err.Error())
+			}
+			return nil, containerLogError(err, "Failed to import container logs")
+		}
+		return result, nil
+	})
+
+	// Container log query endpoint (authenticated, requires container log permission)
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-get-container-logs",
+		Method:      "GET",
+		Path:        basePath + "/{corporation_id}/container-logs",
+		Summary:     "Get Corporation Container Logs",
+		Description: "Queries imported container audit logs to see who took what from which hangar division. Filter by division, character, container, item type, action and time range. Only corporations one of your characters belongs to. Requires 'corporation:containerlogs:view' permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:containerlogs:view"),
+	}, func(ctx context.Context, input *dto.GetContainerLogsInput) (*dto.ContainerLogsOutput, error) {
+		user, err := requireContainerLogUser(ctx, corporationAdapter, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.GetContainerLogs(ctx, user, input)
+		if err != nil {
+			return nil, containerLogError(err, "Failed to get container logs")
+		}
+		return result, nil
+	})
+
+	// Asset movement query endpoint (authenticated, requires container log permission)
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-get-asset-movements",
+		Method:      "GET",
+		Path:        basePath + "/{corporation_id}/asset-movements",
+		Summary:     "Get Corporation Asset Movements",
+		Description: "Queries hangar asset movements detected between container log imports: stacks added to, removed from or moved between hangar divisions, or whose quantity changed. Filter by division, item type, kind and time range. Only corporations one of your characters belongs to. Requires 'corporation:containerlogs:view' permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:containerlogs:view"),
+	}, func(ctx context.Context, input *dto.GetAssetMovementsInput) (*dto.AssetMovementsOutput, error) {
+		user, err := requireContainerLogUser(ctx, corporationAdapter, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.GetAssetMovements(ctx, user, input)
+		if err != nil {
+			return nil, containerLogError(err, "Failed to get asset movements")
+		}
+		return result, nil
+	})
+
+	m.registerWalletRoutes(api, basePath, corporationAdapter)
+
 	// Status endpoint (public, no auth required)
 	huma.Register(api, huma.Operation{
 		OperationID: "corporation-get-status",
@@ -106,7 +182,8 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string, corporatio
 		Path:        basePath + "/validate-ceo-tokens",
 		Summary:     "Validate CEO Tokens",
 		Description: "Validates all CEO tokens and returns detailed results about invalid or missing tokens. This endpoint requires super_admin privileges and may take a while to complete for large datasets.",
-		Tags:        []string{"Corporations", "Administration"},
+		Tags:        []string{"Corporations / Administration"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, func(ctx context.Context, input *dto.ValidateCEOTokensInput) (*dto.ValidateCEOTokensOutput, error) {
 		// Require super_admin privileges
 		if corporationAdapter != nil {
@@ -166,6 +243,245 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string, corporatio
 	})
 }
 
+// registerWalletRoutes registers the wallet division and budget alert routes
+func (m *Module) registerWalletRoutes(api huma.API, basePath string, corporationAdapter *middleware.CorporationAdapter) {
+	// Wallet data endpoints are left out when the deployment disables wallet data
+	if residency.Configured(residency.CategoryWallets) != residency.ModeDisabled {
+		m.registerWalletDataRoutes(api, basePath, corporationAdapter)
+	}
+
+	// Wallet settings endpoints (authenticated, requires wallet management permission)
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-get-wallet-settings",
+		Method:      "GET",
+		Path:        basePath + "/{corporation_id}/wallets/settings",
+		Summary:     "Get Corporation Wallet Settings",
+		Description: "Returns the friendly names and view permissions of all seven wallet divisions and the character whose token reads them. Requires 'corporation:wallets:manage' permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:wallets:manage"),
+	}, func(ctx context.Context, input *dto.CorporationWalletsInput) (*dto.WalletSettingsOutput, error) {
+		if _, err := requireWalletUser(ctx, corporationAdapter, input.Authorization, input.Cookie, true); err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.GetWalletSettings(ctx, input.CorporationID)
+		if err != nil {
+			return nil, walletError(err, "Failed to get wallet settings")
+		}
+		return result, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-update-wallet-settings",
+		Method:      "PUT",
+		Path:        basePath + "/{corporation_id}/wallets/settings",
+		Summary:     "Update Corporation Wallet Settings",
+		Description: "Sets the character whose token reads the wallets and the friendly name and view permission of each division. Divisions left out revert to their default name and 'corporation:wallets:view'. Requires 'corporation:wallets:manage' permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:wallets:manage"),
+	}, func(ctx context.Context, input *dto.UpdateWalletSettingsInput) (*dto.WalletSettingsOutput, error) {
+		user, err := requireWalletUser(ctx, corporationAdapter, input.Authorization, input.Cookie, true)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.UpdateWalletSettings(ctx, user, input)
+		if err != nil {
+			return nil, walletError(err, "Failed to update wallet settings")
+		}
+		return result, nil
+	})
+
+	// Budget alert endpoints (authenticated, requires wallet management permission)
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-list-budget-alerts",
+		Method:      "GET",
+		Path:        basePath + "/{corporation_id}/wallets/alerts",
+		Summary:     "List Corporation Budget Alerts",
+		Description: "Lists the corporation's budget alert rules. Requires 'corporation:wallets:manage' permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:wallets:manage"),
+	}, func(ctx context.Context, input *dto.CorporationWalletsInput) (*dto.BudgetAlertListOutput, error) {
+		if _, err := requireWalletUser(ctx, corporationAdapter, input.Authorization, input.Cookie, true); err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.ListBudgetAlerts(ctx, input.CorporationID)
+		if err != nil {
+			return nil, walletError(err, "Failed to list budget alerts")
+		}
+		return result, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "corporation-create-budget-alert",
+		Method:        "POST",
+		Path:          basePath + "/{corporation_id}/wallets/alerts",
+		Summary:       "Create Corporation Budget Alert",
+		Description:   "Creates a rule that notifies a group (e.g. finance officers) when a wallet division's balance drops below a threshold or a single journal entry moves at least the threshold. Alerts are sent on behalf of the rule's creator. Requires 'corporation:wallets:manage' permission.",
+		Tags:          []string{"Corporations"},
+		DefaultStatus: 201,
+		Extensions:    apidocs.RequiresPermission("corporation:wallets:manage"),
+	}, func(ctx context.Context, input *dto.CreateBudgetAlertInput) (*dto.BudgetAlertOutput, error) {
+		user, err := requireWalletUser(ctx, corporationAdapter, input.Authorization, input.Cookie, true)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.CreateBudgetAlert(ctx, user, input.CorporationID, &input.Body)
+		if err != nil {
+			return nil, walletError(err, "Failed to create budget alert")
+		}
+		return result, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-update-budget-alert",
+		Method:      "PUT",
+		Path:        basePath + "/{corporation_id}/wallets/alerts/{alert_id}",
+		Summary:     "Update Corporation Budget Alert",
+		Description: "Replaces a budget alert rule's settings. A raised balance alert is cleared and evaluated again. Requires 'corporation:wallets:manage' permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:wallets:manage"),
+	}, func(ctx context.Context, input *dto.UpdateBudgetAlertInput) (*dto.BudgetAlertOutput, error) {
+		if _, err := requireWalletUser(ctx, corporationAdapter, input.Authorization, input.Cookie, true); err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.UpdateBudgetAlert(ctx, input.CorporationID, input.AlertID, &input.Body)
+		if err != nil {
+			return nil, walletError(err, "Failed to update budget alert")
+		}
+		return result, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-delete-budget-alert",
+		Method:      "DELETE",
+		Path:        basePath + "/{corporation_id}/wallets/alerts/{alert_id}",
+		Summary:     "Delete Corporation Budget Alert",
+		Description: "Deletes a budget alert rule. Requires 'corporation:wallets:manage' permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:wallets:manage"),
+	}, func(ctx context.Context, input *dto.BudgetAlertInput) (*dto.BudgetAlertDeleteOutput, error) {
+		if _, err := requireWalletUser(ctx, corporationAdapter, input.Authorization, input.Cookie, true); err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.DeleteBudgetAlert(ctx, input.CorporationID, input.AlertID)
+		if err != nil {
+			return nil, walletError(err, "Failed to delete budget alert")
+		}
+		return result, nil
+	})
+}
+
+// registerWalletDataRoutes registers the wallet balance and journal routes
+func (m *Module) registerWalletDataRoutes(api huma.API, basePath string, corporationAdapter *middleware.CorporationAdapter) {
+	// Wallet balances endpoint (authenticated, filtered by per-division permissions)
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-get-wallets",
+		Method:      "GET",
+		Path:        basePath + "/{corporation_id}/wallets",
+		Summary:     "Get Corporation Wallets",
+		Description: "Returns the balances of the corporation's wallet divisions under their configured names. Only divisions whose view permission the caller holds are included. Requires 'corporation:wallets:view' permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:wallets:view"),
+	}, func(ctx context.Context, input *dto.CorporationWalletsInput) (*dto.CorporationWalletsOutput, error) {
+		if err := residency.Require(ctx, residency.CategoryWallets); err != nil {
+			return nil, err
+		}
+
+		user, err := requireWalletUser(ctx, corporationAdapter, input.Authorization, input.Cookie, false)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.GetWallets(ctx, user, input.CorporationID)
+		if err != nil {
+			return nil, walletError(err, "Failed to get corporation wallets")
+		}
+		return result, nil
+	})
+
+	// Wallet journal endpoint (authenticated, requires the division's view permission)
+	huma.Register(api, huma.Operation{
+		OperationID: "corporation-get-wallet-journal",
+		Method:      "GET",
+		Path:        basePath + "/{corporation_id}/wallets/{division}/journal",
+		Summary:     "Get Corporation Wallet Journal",
+		Description: "Returns the journal of one wallet division, newest first. Requires 'corporation:wallets:view' permission and the division's configured view permission.",
+		Tags:        []string{"Corporations"},
+		Extensions:  apidocs.RequiresPermission("corporation:wallets:view"),
+	}, func(ctx context.Context, input *dto.GetWalletJournalInput) (*dto.WalletJournalOutput, error) {
+		if err := residency.Require(ctx, residency.CategoryWallets); err != nil {
+			return nil, err
+		}
+
+		user, err := requireWalletUser(ctx, corporationAdapter, input.Authorization, input.Cookie, false)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := m.service.GetWalletJournal(ctx, user, input.CorporationID, input.Division)
+		if err != nil {
+			return nil, walletError(err, "Failed to get wallet journal")
+		}
+		return result, nil
+	})
+}
+
+// requireContainerLogUser authenticates a container log or asset movement request. Like wallet
+// routes, these need the caller to tell which corporations and characters are theirs.
+func requireContainerLogUser(ctx context.Context, corporationAdapter *middleware.CorporationAdapter, authHeader, cookieHeader string) (*authModels.AuthenticatedUser, error) {
+	if corporationAdapter == nil {
+		return nil, huma.Error503ServiceUnavailable("Permission system not available")
+	}
+	return corporationAdapter.RequireContainerLogAccess(ctx, authHeader, cookieHeader)
+}
+
+// containerLogError maps container log and asset movement query errors to HTTP errors
+func containerLogError(err error, message string) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidTimeRange):
+		return huma.Error400BadRequest("Invalid time range", err)
+	case errors.Is(err, services.ErrCorporationAccessDenied):
+		return huma.Error403Forbidden("You are not a member of this corporation")
+	case errors.Is(err, services.ErrInvalidDirector):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, services.ErrContainerLogsUnavailable):
+		return huma.Error502BadGateway("Failed to get container logs from ESI", err)
+	}
+	return huma.Error500InternalServerError(message, err)
+}
+
+// requireWalletUser authenticates a wallet request. Unlike the other corporation routes, wallet
+// routes are never served without the permission system, as they expose finances.
+func requireWalletUser(ctx context.Context, corporationAdapter *middleware.CorporationAdapter, authHeader, cookieHeader string, manage bool) (*authModels.AuthenticatedUser, error) {
+	if corporationAdapter == nil {
+		return nil, huma.Error503ServiceUnavailable("Permission system not available")
+	}
+	if manage {
+		return corporationAdapter.RequireWalletManagement(ctx, authHeader, cookieHeader)
+	}
+	return corporationAdapter.RequireWalletAccess(ctx, authHeader, cookieHeader)
+}
+
+// walletError maps wallet service errors to HTTP errors
+func walletError(err error, message string) error {
+	switch {
+	case errors.Is(err, services.ErrWalletAccessDenied):
+		return huma.Error403Forbidden("You do not have access to this wallet division")
+	case errors.Is(err, services.ErrBudgetAlertNotFound):
+		return huma.Error404NotFound("Budget alert not found")
+	case errors.Is(err, services.ErrInvalidWalletSettings):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, services.ErrWalletTokenUnavailable):
+		return huma.Error409Conflict(err.Error())
+	}
+	return huma.Error500InternalServerError(message, err)
+}
+
 // getCorporationInfo handles the corporation information request
 func (m *Module) getCorporationInfo(ctx context.Context, corporationID int) (*dto.CorporationInfoOutput, error) {
 	if corporationID <= 0 {
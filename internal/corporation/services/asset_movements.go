@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	authModels "go-falcon/internal/auth/models"
+	"go-falcon/internal/corporation/dto"
+	"go-falcon/internal/corporation/models"
+)
+
+// importAssetMovements reads the corporation's assets from ESI, records how the hangar divisions
+// changed since the previous import and stores the new contents. Returns the number of movements;
+// the first import only stores the contents.
+func (s *Service) importAssetMovements(ctx context.Context, corporationID int, token string) (int, error) {
+	assets, err := s.eveClient.Assets.GetCorporationAssets(ctx, int32(corporationID), token)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get corporation assets: %w", err)
+	}
+
+	now := time.Now().UTC()
+	current := &models.HangarSnapshot{CorporationID: corporationID, TakenAt: now}
+	for _, asset := range assets {
+		flag, _ := asset["location_flag"].(string)
+		if !strings.HasPrefix(flag, hangarFlagPrefix) {
+			// Only stacks lying in a division; container contents are covered by the container logs
+			continue
+		}
+		current.Items = append(current.Items, models.HangarItem{
+			ItemID:       assetNumber(asset["item_id"]),
+			TypeID:       int(assetNumber(asset["type_id"])),
+			LocationID:   assetNumber(asset["location_id"]),
+			LocationFlag: flag,
+			Quantity:     int(assetNumber(asset["quantity"])),
+		})
+	}
+
+	previous, err := s.repository.GetHangarSnapshot(ctx, corporationID)
+	if err != nil {
+		return 0, err
+	}
+
+	var movements []*models.AssetMovement
+	if previous != nil {
+		movements = diffHangars(previous, current)
+		if err := s.repository.InsertAssetMovements(ctx, movements); err != nil {
+			return 0, err
+		}
+	}
+	if err := s.repository.SaveHangarSnapshot(ctx, current); err != nil {
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "Asset movements imported", "corporation_id", corporationID, "items", len(current.Items), "movements", len(movements))
+	return len(movements), nil
+}
+
+// diffHangars returns the movements that turn the previous hangar contents into the current ones
+func diffHangars(previous, current *models.HangarSnapshot) []*models.AssetMovement {
+	before := make(map[int64]models.HangarItem, len(previous.Items))
+	for _, item := range previous.Items {
+		before[item.ItemID] = item
+	}
+
+	var movements []*models.AssetMovement
+	movement := func(kind string, item models.HangarItem, quantityChange int) *models.AssetMovement {
+		return &models.AssetMovement{
+			CorporationID:    current.CorporationID,
+			Kind:             kind,
+			ItemID:           item.ItemID,
+			TypeID:           item.TypeID,
+			QuantityChange:   quantityChange,
+			PreviousImportAt: previous.TakenAt,
+			DetectedAt:       current.TakenAt,
+		}
+	}
+
+	for _, item := range current.Items {
+		old, existed := before[item.ItemID]
+		delete(before, item.ItemID)
+
+		switch {
+		case !existed:
+			added := movement(models.AssetMovementAdded, item, item.Quantity)
+			added.ToLocationID, added.ToLocationFlag = item.LocationID, item.LocationFlag
+			movements = append(movements, added)
+		case old.LocationID != item.LocationID || old.LocationFlag != item.LocationFlag:
+			moved := movement(models.AssetMovementMoved, item, item.Quantity-old.Quantity)
+			moved.FromLocationID, moved.FromLocationFlag = old.LocationID, old.LocationFlag
+			moved.ToLocationID, moved.ToLocationFlag = item.LocationID, item.LocationFlag
+			movements = append(movements, moved)
+		case old.Quantity != item.Quantity:
+			changed := movement(models.AssetMovementChanged, item, item.Quantity-old.Quantity)
+			changed.FromLocationID, changed.FromLocationFlag = old.LocationID, old.LocationFlag
+			changed.ToLocationID, changed.ToLocationFlag = item.LocationID, item.LocationFlag
+			movements = append(movements, changed)
+		}
+	}
+
+	for _, item := range previous.Items {
+		if _, gone := before[item.ItemID]; !gone {
+			continue
+		}
+		removed := movement(models.AssetMovementRemoved, item, -item.Quantity)
+		removed.FromLocationID, removed.FromLocationFlag = item.LocationID, item.LocationFlag
+		movements = append(movements, removed)
+	}
+
+	return movements
+}
+
+// assetNumber reads a numeric field of an ESI asset
+func assetNumber(value any) int64 {
+	switch number := value.(type) {
+	case int64:
+		return number
+	case int32:
+		return int64(number)
+	case int:
+		return int64(number)
+	case float64:
+		return int64(number)
+	}
+	return 0
+}
+
+// GetAssetMovements queries recorded hangar asset movements of one of the caller's corporations
+func (s *Service) GetAssetMovements(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.GetAssetMovementsInput) (*dto.AssetMovementsOutput, error) {
+	if err := s.requireCorporationMember(ctx, user, input.CorporationID); err != nil {
+		return nil, err
+	}
+
+	filter := AssetMovementFilter{
+		CorporationID: input.CorporationID,
+		LocationFlag:  input.LocationFlag,
+		TypeID:        input.TypeID,
+		Kind:          input.Kind,
+		Page:          input.Page,
+		PageSize:      input.PageSize,
+	}
+	if filter.LocationFlag == "" && input.Division > 0 {
+		filter.LocationFlag = fmt.Sprintf("%s%d", hangarFlagPrefix, input.Division)
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = 100
+	}
+
+	var err error
+	if filter.From, err = parseOptionalTime(input.From); err != nil {
+		return nil, fmt.Errorf("%w: from: %v", ErrInvalidTimeRange, err)
+	}
+	if filter.To, err = parseOptionalTime(input.To); err != nil {
+		return nil, fmt.Errorf("%w: to: %v", ErrInvalidTimeRange, err)
+	}
+
+	movements, total, err := s.repository.GetAssetMovements(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	typeNames := make(map[int]string)
+	entries := make([]dto.AssetMovementEntry, len(movements))
+	for i, movement := range movements {
+		entries[i] = dto.AssetMovementEntry{
+			Kind:             movement.Kind,
+			ItemID:           movement.ItemID,
+			TypeID:           movement.TypeID,
+			TypeName:         s.lookupTypeName(movement.TypeID, typeNames),
+			FromLocationID:   movement.FromLocationID,
+			FromLocationFlag: movement.FromLocationFlag,
+			FromDivision:     hangarDivision(movement.FromLocationFlag),
+			ToLocationID:     movement.ToLocationID,
+			ToLocationFlag:   movement.ToLocationFlag,
+			ToDivision:       hangarDivision(movement.ToLocationFlag),
+			QuantityChange:   movement.QuantityChange,
+			PreviousImportAt: movement.PreviousImportAt,
+			DetectedAt:       movement.DetectedAt,
+		}
+	}
+
+	return &dto.AssetMovementsOutput{
+		Body: dto.AssetMovementsResult{
+			CorporationID: input.CorporationID,
+			Entries:       entries,
+			Total:         total,
+			Page:          filter.Page,
+			PageSize:      filter.PageSize,
+		},
+	}, nil
+}
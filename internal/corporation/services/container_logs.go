@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	authModels "go-falcon/internal/auth/models"
+	"go-falcon/internal/corporation/dto"
+	"go-falcon/internal/corporation/models"
+	evegatewayTypes "go-falcon/pkg/evegateway/corporation"
+)
+
+// hangarFlagPrefix is the location flag prefix for corporation hangar divisions (CorpSAG1..CorpSAG7)
+const hangarFlagPrefix = "CorpSAG"
+
+var (
+	// ErrInvalidTimeRange is returned when a container log query has an unparseable time bound
+	ErrInvalidTimeRange = errors.New("invalid time range")
+	// ErrCorporationAccessDenied is returned when the caller has no character in the corporation, or
+	// names a director character of another account
+	ErrCorporationAccessDenied = errors.New("not a member of this corporation")
+	// ErrInvalidDirector is returned when the named director cannot import the corporation's
+	// container logs: another corporation, no token, a missing scope or no Director role
+	ErrInvalidDirector = errors.New("director cannot import container logs")
+	// ErrContainerLogsUnavailable is returned when ESI fails to deliver the container logs
+	ErrContainerLogsUnavailable = errors.New("container logs unavailable from ESI")
+)
+
+// ImportContainerLogs pulls the latest container audit logs from ESI using a director's token and
+// stores new entries. The director must be one of the caller's own characters. When the token can
+// also read corporation assets, hangar asset movements since the previous import are recorded too.
+func (s *Service) ImportContainerLogs(ctx context.Context, user *authModels.AuthenticatedUser, corporationID int, directorID int) (*dto.ContainerLogImportOutput, error) {
+	slog.InfoContext(ctx, "Importing container logs", "corporation_id", corporationID, "director_id", directorID, "user_id", user.UserID)
+
+	profile, err := s.authService.GetUserProfileByCharacterID(ctx, directorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get director profile: %w", err)
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("%w: no profile for character %d", ErrInvalidDirector, directorID)
+	}
+	if profile.UserID != user.UserID {
+		return nil, fmt.Errorf("%w: character %d does not belong to your account", ErrCorporationAccessDenied, directorID)
+	}
+	if profile.CorporationID != corporationID {
+		return nil, fmt.Errorf("%w: character %d is not a member of corporation %d", ErrInvalidDirector, directorID, corporationID)
+	}
+	if profile.AccessToken == "" {
+		return nil, fmt.Errorf("%w: no valid access token", ErrInvalidDirector)
+	}
+	if !slices.Contains(strings.Fields(profile.Scopes), models.ContainerLogsScope) {
+		return nil, fmt.Errorf("%w: token is missing scope %s", ErrInvalidDirector, models.ContainerLogsScope)
+	}
+
+	esiLogs, err := s.eveClient.Corporation.GetCorporationContainerLogs(ctx, corporationID, profile.AccessToken)
+	if err != nil {
+		if errors.Is(err, evegatewayTypes.ErrForbidden) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDirector, err)
+		}
+		slog.ErrorContext(ctx, "Failed to get container logs from ESI", "corporation_id", corporationID, "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrContainerLogsUnavailable, err)
+	}
+
+	now := time.Now().UTC()
+	logs := make([]*models.ContainerLog, len(esiLogs))
+	for i, entry := range esiLogs {
+		logs[i] = &models.ContainerLog{
+			CorporationID:    corporationID,
+			LoggedAt:         entry.LoggedAt,
+			ContainerID:      entry.ContainerID,
+			ContainerTypeID:  entry.ContainerTypeID,
+			CharacterID:      entry.CharacterID,
+			LocationID:       entry.LocationID,
+			LocationFlag:     entry.LocationFlag,
+			Action:           entry.Action,
+			PasswordType:     entry.PasswordType,
+			TypeID:           entry.TypeID,
+			Quantity:         entry.Quantity,
+			OldConfigBitmask: entry.OldConfigBitmask,
+			NewConfigBitmask: entry.NewConfigBitmask,
+			ImportedAt:       now,
+		}
+	}
+
+	inserted, err := s.repository.UpsertContainerLogs(ctx, logs)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Container logs imported", "corporation_id", corporationID, "fetched", len(logs), "inserted", inserted)
+
+	result := dto.ContainerLogImportResult{
+		CorporationID: corporationID,
+		Fetched:       len(logs),
+		Inserted:      inserted,
+	}
+
+	// Asset movements are optional: a director token without the assets scope only imports logs
+	if slices.Contains(strings.Fields(profile.Scopes), models.AssetsScope) {
+		movements, err := s.importAssetMovements(ctx, corporationID, profile.AccessToken)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to import asset movements", "corporation_id", corporationID, "error", err)
+		} else {
+			result.AssetsImported = true
+			result.AssetMovements = movements
+		}
+	}
+
+	return &dto.ContainerLogImportOutput{Body: result}, nil
+}
+
+// requireCorporationMember returns ErrCorporationAccessDenied unless one of the caller's characters
+// is in the corporation, so container logs and asset movements stay with their own corporation
+func (s *Service) requireCorporationMember(ctx context.Context, user *authModels.AuthenticatedUser, corporationID int) error {
+	if s.authService == nil {
+		return fmt.Errorf("auth service not available")
+	}
+	characters, err := s.authService.GetAllCharactersByUserID(ctx, user.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get characters: %w", err)
+	}
+	for _, character := range characters {
+		if character.CorporationID == corporationID && character.DeletedAt == nil {
+			return nil
+		}
+	}
+	return ErrCorporationAccessDenied
+}
+
+// GetContainerLogs queries stored container audit logs of one of the caller's corporations
+func (s *Service) GetContainerLogs(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.GetContainerLogsInput) (*dto.ContainerLogsOutput, error) {
+	if err := s.requireCorporationMember(ctx, user, input.CorporationID); err != nil {
+		return nil, err
+	}
+
+	filter := ContainerLogFilter{
+		CorporationID: input.CorporationID,
+		LocationFlag:  input.LocationFlag,
+		CharacterID:   input.CharacterID,
+		ContainerID:   input.ContainerID,
+		TypeID:        input.TypeID,
+		Action:        input.Action,
+		Page:          input.Page,
+		PageSize:      input.PageSize,
+	}
+	if filter.LocationFlag == "" && input.Division > 0 {
+		filter.LocationFlag = fmt.Sprintf("%s%d", hangarFlagPrefix, input.Division)
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = 100
+	}
+
+	var err error
+	if filter.From, err = parseOptionalTime(input.From); err != nil {
+		return nil, fmt.Errorf("%w: from: %v", ErrInvalidTimeRange, err)
+	}
+	if filter.To, err = parseOptionalTime(input.To); err != nil {
+		return nil, fmt.Errorf("%w: to: %v", ErrInvalidTimeRange, err)
+	}
+
+	logs, total, err := s.repository.GetContainerLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	characterNames := make(map[int]string)
+	typeNames := make(map[int]string)
+	entries := make([]dto.ContainerLogEntry, len(logs))
+	for i, entry := range logs {
+		entries[i] = dto.ContainerLogEntry{
+			LoggedAt:         entry.LoggedAt,
+			ContainerID:      entry.ContainerID,
+			ContainerTypeID:  entry.ContainerTypeID,
+			CharacterID:      entry.CharacterID,
+			CharacterName:    s.lookupCharacterName(ctx, entry.CharacterID, characterNames),
+			LocationID:       entry.LocationID,
+			LocationFlag:     entry.LocationFlag,
+			Division:         hangarDivision(entry.LocationFlag),
+			Action:           entry.Action,
+			PasswordType:     entry.PasswordType,
+			TypeID:           entry.TypeID,
+			TypeName:         s.lookupTypeName(entry.TypeID, typeNames),
+			Quantity:         entry.Quantity,
+			OldConfigBitmask: entry.OldConfigBitmask,
+			NewConfigBitmask: entry.NewConfigBitmask,
+		}
+	}
+
+	return &dto.ContainerLogsOutput{
+		Body: dto.ContainerLogsResult{
+			CorporationID: input.CorporationID,
+			Entries:       entries,
+			Total:         total,
+			Page:          filter.Page,
+			PageSize:      filter.PageSize,
+		},
+	}, nil
+}
+
+// lookupCharacterName resolves a character name once per request
+func (s *Service) lookupCharacterName(ctx context.Context, characterID int, cache map[int]string) string {
+	if characterID == 0 || s.characterService == nil {
+		return ""
+	}
+	if name, ok := cache[characterID]; ok {
+		return name
+	}
+
+	name := ""
+	if profile, err := s.characterService.GetCharacterProfile(ctx, characterID); err == nil && profile != nil {
+		name = profile.Body.Name
+	}
+	cache[characterID] = name
+	return name
+}
+
+// lookupTypeName resolves an item type name from the SDE once per request
+func (s *Service) lookupTypeName(typeID int, cache map[int]string) string {
+	if typeID == 0 || s.sdeService == nil {
+		return ""
+	}
+	if name, ok := cache[typeID]; ok {
+		return name
+	}
+
+	name := ""
+	if typeInfo, err := s.sdeService.GetType(strconv.Itoa(typeID)); err == nil && typeInfo != nil {
+		name = typeInfo.Name["en"]
+	}
+	cache[typeID] = name
+	return name
+}
+
+// hangarDivision returns the hangar division number for a CorpSAG location flag, or 0
+func hangarDivision(locationFlag string) int {
+	if !strings.HasPrefix(locationFlag, hangarFlagPrefix) {
+		return 0
+	}
+	division, err := strconv.Atoi(strings.TrimPrefix(locationFlag, hangarFlagPrefix))
+	if err != nil {
+		return 0
+	}
+	return division
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -10,6 +10,7 @@ import (
 	"go-falcon/pkg/database"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -20,6 +21,11 @@ type Repository struct {
 	collection               *mongo.Collection
 	memberTrackingCollection *mongo.Collection
 	structuresCollection     *mongo.Collection
+	containerLogsCollection  *mongo.Collection
+	snapshotsCollection      *mongo.Collection
+	movementsCollection      *mongo.Collection
+	walletSettingsCollection *mongo.Collection
+	budgetAlertsCollection   *mongo.Collection
 }
 
 // NewRepository creates a new corporation repository
@@ -29,6 +35,11 @@ func NewRepository(mongodb *database.MongoDB) *Repository {
 		collection:               mongodb.Database.Collection(models.CorporationCollection),
 		memberTrackingCollection: mongodb.Database.Collection(models.TrackCorporationMembersCollection),
 		structuresCollection:     mongodb.Database.Collection(models.StructuresCollection),
+		containerLogsCollection:  mongodb.Database.Collection(models.ContainerLogsCollection),
+		snapshotsCollection:      mongodb.Database.Collection(models.HangarSnapshotsCollection),
+		movementsCollection:      mongodb.Database.Collection(models.AssetMovementsCollection),
+		walletSettingsCollection: mongodb.Database.Collection(models.WalletSettingsCollection),
+		budgetAlertsCollection:   mongodb.Database.Collection(models.BudgetAlertsCollection),
 	}
 }
 
@@ -351,6 +362,371 @@ func (r *Repository) UpdateStructure(ctx context.Context, structure *models.Stru
 	return err
 }
 
+// ContainerLogFilter narrows a container log query
+type ContainerLogFilter struct {
+	CorporationID int
+	LocationFlag  string
+	CharacterID   int
+	ContainerID   int64
+	TypeID        int
+	Action        string
+	From          *time.Time
+	To            *time.Time
+	Page          int
+	PageSize      int
+}
+
+// UpsertContainerLogs stores container log entries, skipping ones that were already imported.
+// Returns the number of newly inserted entries.
+func (r *Repository) UpsertContainerLogs(ctx context.Context, logs []*models.ContainerLog) (int, error) {
+	if len(logs) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, len(logs))
+	for i, entry := range logs {
+		// ESI has no stable ID for log entries, so the identifying fields form the key
+		filter := bson.M{
+			"corporation_id": entry.CorporationID,
+			"container_id":   entry.ContainerID,
+			"logged_at":      entry.LoggedAt,
+			"character_id":   entry.CharacterID,
+			"action":         entry.Action,
+			"type_id":        entry.TypeID,
+			"quantity":       entry.Quantity,
+		}
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$setOnInsert": entry}).
+			SetUpsert(true)
+	}
+
+	result, err := r.containerLogsCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("failed to store container logs: %w", err)
+	}
+
+	return int(result.UpsertedCount), nil
+}
+
+// GetContainerLogs returns container log entries matching the filter, newest first, with the total match count
+func (r *Repository) GetContainerLogs(ctx context.Context, filter ContainerLogFilter) ([]*models.ContainerLog, int64, error) {
+	query := bson.M{"corporation_id": filter.CorporationID}
+	if filter.LocationFlag != "" {
+		query["location_flag"] = filter.LocationFlag
+	}
+	if filter.CharacterID != 0 {
+		query["character_id"] = filter.CharacterID
+	}
+	if filter.ContainerID != 0 {
+		query["container_id"] = filter.ContainerID
+	}
+	if filter.TypeID != 0 {
+		query["type_id"] = filter.TypeID
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.From != nil || filter.To != nil {
+		timeRange := bson.M{}
+		if filter.From != nil {
+			timeRange["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			timeRange["$lte"] = *filter.To
+		}
+		query["logged_at"] = timeRange
+	}
+
+	total, err := r.containerLogsCollection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count container logs: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "logged_at", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.PageSize)).
+		SetLimit(int64(filter.PageSize))
+
+	cursor, err := r.containerLogsCollection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query container logs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var logs []*models.ContainerLog
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode container logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// GetWalletSettings returns a corporation's wallet settings, or nil if none are stored
+func (r *Repository) GetWalletSettings(ctx context.Context, corporationID int) (*models.WalletSettings, error) {
+	var settings models.WalletSettings
+	err := r.walletSettingsCollection.FindOne(ctx, bson.M{"corporation_id": corporationID}).Decode(&settings)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get wallet settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveWalletSettings creates or replaces a corporation's wallet settings
+func (r *Repository) SaveWalletSettings(ctx context.Context, settings *models.WalletSettings) error {
+	_, err := r.walletSettingsCollection.UpdateOne(ctx,
+		bson.M{"corporation_id": settings.CorporationID},
+		bson.M{"$set": bson.M{
+			"token_character_id": settings.TokenCharacterID,
+			"divisions":          settings.Divisions,
+			"updated_at":         settings.UpdatedAt,
+			"updated_by":         settings.UpdatedBy,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save wallet settings: %w", err)
+	}
+	return nil
+}
+
+// CreateBudgetAlert inserts a budget alert rule
+func (r *Repository) CreateBudgetAlert(ctx context.Context, rule *models.BudgetAlertRule) error {
+	rule.ID = primitive.NewObjectID()
+	if _, err := r.budgetAlertsCollection.InsertOne(ctx, rule); err != nil {
+		return fmt.Errorf("failed to create budget alert: %w", err)
+	}
+	return nil
+}
+
+// GetBudgetAlert returns a corporation's budget alert rule, or nil if it does not exist
+func (r *Repository) GetBudgetAlert(ctx context.Context, corporationID int, ruleID primitive.ObjectID) (*models.BudgetAlertRule, error) {
+	var rule models.BudgetAlertRule
+	err := r.budgetAlertsCollection.FindOne(ctx, bson.M{"_id": ruleID, "corporation_id": corporationID}).Decode(&rule)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get budget alert: %w", err)
+	}
+	return &rule, nil
+}
+
+// ListBudgetAlerts returns a corporation's budget alert rules, oldest first
+func (r *Repository) ListBudgetAlerts(ctx context.Context, corporationID int) ([]*models.BudgetAlertRule, error) {
+	return r.findBudgetAlerts(ctx, bson.M{"corporation_id": corporationID})
+}
+
+// ListEnabledBudgetAlerts returns the enabled budget alert rules of all corporations
+func (r *Repository) ListEnabledBudgetAlerts(ctx context.Context) ([]*models.BudgetAlertRule, error) {
+	return r.findBudgetAlerts(ctx, bson.M{"enabled": true})
+}
+
+func (r *Repository) findBudgetAlerts(ctx context.Context, filter bson.M) ([]*models.BudgetAlertRule, error) {
+	cursor, err := r.budgetAlertsCollection.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "corporation_id", Value: 1}, {Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query budget alerts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	rules := []*models.BudgetAlertRule{}
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode budget alerts: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateBudgetAlert applies a $set update to a budget alert rule
+func (r *Repository) UpdateBudgetAlert(ctx context.Context, ruleID primitive.ObjectID, update bson.M) error {
+	if _, err := r.budgetAlertsCollection.UpdateOne(ctx, bson.M{"_id": ruleID}, bson.M{"$set": update}); err != nil {
+		return fmt.Errorf("failed to update budget alert: %w", err)
+	}
+	return nil
+}
+
+// DeleteBudgetAlert removes a corporation's budget alert rule, reporting whether it existed
+func (r *Repository) DeleteBudgetAlert(ctx context.Context, corporationID int, ruleID primitive.ObjectID) (bool, error) {
+	result, err := r.budgetAlertsCollection.DeleteOne(ctx, bson.M{"_id": ruleID, "corporation_id": corporationID})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete budget alert: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// CreateIndexes creates indexes for the corporation collections
+// GetHangarSnapshot returns the hangar contents stored by the last asset import, or nil before the first one
+func (r *Repository) GetHangarSnapshot(ctx context.Context, corporationID int) (*models.HangarSnapshot, error) {
+	var snapshot models.HangarSnapshot
+	err := r.snapshotsCollection.FindOne(ctx, bson.M{"corporation_id": corporationID}).Decode(&snapshot)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hangar snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// SaveHangarSnapshot replaces the stored hangar contents of a corporation
+func (r *Repository) SaveHangarSnapshot(ctx context.Context, snapshot *models.HangarSnapshot) error {
+	_, err := r.snapshotsCollection.ReplaceOne(ctx, bson.M{"corporation_id": snapshot.CorporationID}, snapshot, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save hangar snapshot: %w", err)
+	}
+	return nil
+}
+
+// InsertAssetMovements stores detected asset movements
+func (r *Repository) InsertAssetMovements(ctx context.Context, movements []*models.AssetMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(movements))
+	for i, movement := range movements {
+		documents[i] = movement
+	}
+	if _, err := r.movementsCollection.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("failed to store asset movements: %w", err)
+	}
+	return nil
+}
+
+// AssetMovementFilter narrows an asset movement query
+type AssetMovementFilter struct {
+	CorporationID int
+	LocationFlag  string
+	TypeID        int
+	Kind          string
+	From          *time.Time
+	To            *time.Time
+	Page          int
+	PageSize      int
+}
+
+// GetAssetMovements returns asset movements matching the filter, newest first, with the total match count
+func (r *Repository) GetAssetMovements(ctx context.Context, filter AssetMovementFilter) ([]*models.AssetMovement, int64, error) {
+	query := bson.M{"corporation_id": filter.CorporationID}
+	if filter.LocationFlag != "" {
+		// A movement concerns a division when an item left or entered it
+		query["$or"] = bson.A{
+			bson.M{"from_location_flag": filter.LocationFlag},
+			bson.M{"to_location_flag": filter.LocationFlag},
+		}
+	}
+	if filter.TypeID != 0 {
+		query["type_id"] = filter.TypeID
+	}
+	if filter.Kind != "" {
+		query["kind"] = filter.Kind
+	}
+	if filter.From != nil || filter.To != nil {
+		timeRange := bson.M{}
+		if filter.From != nil {
+			timeRange["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			timeRange["$lte"] = *filter.To
+		}
+		query["detected_at"] = timeRange
+	}
+
+	total, err := r.movementsCollection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count asset movements: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "detected_at", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.PageSize)).
+		SetLimit(int64(filter.PageSize))
+
+	cursor, err := r.movementsCollection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query asset movements: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var movements []*models.AssetMovement
+	if err := cursor.All(ctx, &movements); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode asset movements: %w", err)
+	}
+
+	return movements, total, nil
+}
+
+func (r *Repository) CreateIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "corporation_id", Value: 1},
+				{Key: "container_id", Value: 1},
+				{Key: "logged_at", Value: 1},
+				{Key: "character_id", Value: 1},
+				{Key: "action", Value: 1},
+				{Key: "type_id", Value: 1},
+				{Key: "quantity", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "corporation_id", Value: 1},
+				{Key: "logged_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "corporation_id", Value: 1},
+				{Key: "location_flag", Value: 1},
+				{Key: "logged_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "corporation_id", Value: 1},
+				{Key: "character_id", Value: 1},
+				{Key: "logged_at", Value: -1},
+			},
+		},
+	}
+
+	if _, err := r.containerLogsCollection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return err
+	}
+
+	if _, err := r.snapshotsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "corporation_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	if _, err := r.movementsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "corporation_id", Value: 1}, {Key: "detected_at", Value: -1}}},
+		{Keys: bson.D{{Key: "corporation_id", Value: 1}, {Key: "type_id", Value: 1}, {Key: "detected_at", Value: -1}}},
+	}); err != nil {
+		return err
+	}
+
+	if _, err := r.walletSettingsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "corporation_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	_, err := r.budgetAlertsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "corporation_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "enabled", Value: 1}, {Key: "corporation_id", Value: 1}}},
+	})
+	return err
+}
+
 // CheckHealth verifies database connectivity
 func (r *Repository) CheckHealth(ctx context.Context) error {
 	// Perform a simple ping to check database connectivity
@@ -12,6 +12,7 @@ import (
 	"go-falcon/internal/corporation/models"
 	"go-falcon/pkg/evegateway"
 	evegatewayTypes "go-falcon/pkg/evegateway/corporation"
+	"go-falcon/pkg/retryqueue"
 	"go-falcon/pkg/sde"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -24,11 +25,22 @@ type Service struct {
 	characterService *characterServices.Service
 	sdeService       sde.SDEService
 	authService      AuthService
+
+	permissionChecker WalletPermissionChecker
+	notifier          Notifier
+	updateRecorder    UpdateRecorder
 }
 
 // AuthService interface for auth operations we need
 type AuthService interface {
 	GetUserProfileByCharacterID(ctx context.Context, characterID int) (*authModels.UserProfile, error)
+	GetAllCharactersByUserID(ctx context.Context, userID string) ([]*authModels.UserProfile, error)
+}
+
+// UpdateRecorder records the outcome of scheduled ESI updates so failed ones are retried
+type UpdateRecorder interface {
+	RecordSuccess(ctx context.Context, kind string, entityID int64)
+	RecordFailure(ctx context.Context, kind string, entityID int64, err error)
 }
 
 // NewService creates a new corporation service
@@ -42,6 +54,16 @@ func NewService(repository *Repository, eveClient *evegateway.Client, characterS
 	}
 }
 
+// SetUpdateRecorder sets where scheduled update outcomes are recorded (the ESI retry queue)
+func (s *Service) SetUpdateRecorder(recorder UpdateRecorder) {
+	s.updateRecorder = recorder
+}
+
+// CreateIndexes creates database indexes for corporation collections
+func (s *Service) CreateIndexes(ctx context.Context) error {
+	return s.repository.CreateIndexes(ctx)
+}
+
 // GetCorporationInfo retrieves corporation information, first checking the database,
 // then falling back to EVE ESI if not found or data is stale
 func (s *Service) GetCorporationInfo(ctx context.Context, corporationID int) (*dto.CorporationInfoOutput, error) {
@@ -314,30 +336,15 @@ func (s *Service) UpdateAllCorporations(ctx context.Context, concurrentWorkers i
 				// Create a new context for each update to avoid context cancellation issues
 				updateCtx := context.Background()
 
-				// Get corporation info from EVE ESI
-				esiData, err := s.eveClient.GetCorporationInfo(updateCtx, corporationID)
+				corporation, err := s.refreshCorporation(updateCtx, corporationID)
 				if err != nil {
-					slog.WarnContext(updateCtx, "Failed to get corporation from ESI",
+					slog.WarnContext(updateCtx, "Failed to update corporation",
 						"worker_id", workerID,
 						"corporation_id", corporationID,
 						"error", err)
-					results <- updateResult{
-						corporationID: corporationID,
-						success:       false,
-						err:           err,
+					if s.updateRecorder != nil {
+						s.updateRecorder.RecordFailure(updateCtx, retryqueue.KindCorporation, int64(corporationID), err)
 					}
-					continue
-				}
-
-				// Convert ESI data to our model
-				corporation := s.convertESIDataToModel(esiData, corporationID)
-
-				// Update in database
-				if err := s.repository.UpdateCorporation(updateCtx, corporation); err != nil {
-					slog.WarnContext(updateCtx, "Failed to update corporation in database",
-						"worker_id", workerID,
-						"corporation_id", corporationID,
-						"error", err)
 					results <- updateResult{
 						corporationID: corporationID,
 						success:       false,
@@ -345,6 +352,9 @@ func (s *Service) UpdateAllCorporations(ctx context.Context, concurrentWorkers i
 					}
 					continue
 				}
+				if s.updateRecorder != nil {
+					s.updateRecorder.RecordSuccess(updateCtx, retryqueue.KindCorporation, int64(corporationID))
+				}
 
 				slog.DebugContext(updateCtx, "Successfully updated corporation",
 					"worker_id", workerID,
@@ -404,6 +414,26 @@ func (s *Service) UpdateAllCorporations(ctx context.Context, concurrentWorkers i
 	return nil
 }
 
+// RefreshCorporation fetches a corporation from ESI and stores it; the retry queue uses it to
+// retry failed scheduled updates
+func (s *Service) RefreshCorporation(ctx context.Context, corporationID int64) error {
+	_, err := s.refreshCorporation(ctx, int(corporationID))
+	return err
+}
+
+func (s *Service) refreshCorporation(ctx context.Context, corporationID int) (*models.Corporation, error) {
+	esiData, err := s.eveClient.GetCorporationInfo(ctx, corporationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get corporation from ESI: %w", err)
+	}
+
+	corporation := s.convertESIDataToModel(esiData, corporationID)
+	if err := s.repository.UpdateCorporation(ctx, corporation); err != nil {
+		return nil, fmt.Errorf("failed to update corporation in database: %w", err)
+	}
+	return corporation, nil
+}
+
 // ValidateCEOTokens checks if all CEO characters have valid tokens
 // TODO: This will be enhanced with a notification system for invalid tokens
 func (s *Service) ValidateCEOTokens(ctx context.Context) error {
@@ -0,0 +1,600 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	authModels "go-falcon/internal/auth/models"
+	"go-falcon/internal/corporation/dto"
+	"go-falcon/internal/corporation/models"
+	notificationsDto "go-falcon/internal/notifications/dto"
+	notificationModels "go-falcon/internal/notifications/models"
+	"go-falcon/pkg/evegateway/wallet"
+	"go-falcon/pkg/permissions"
+	"go-falcon/pkg/residency"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Wallet permissions: view grants the wallet endpoints and every division left on the default
+// permission; manage grants division settings and budget alert rules
+const (
+	WalletViewPermission   = "corporation:wallets:view"
+	WalletManagePermission = "corporation:wallets:manage"
+)
+
+// maxAlertedEntries is how many large journal entries are listed in one alert
+const maxAlertedEntries = 5
+
+var (
+	// ErrWalletAccessDenied is returned when the caller may not read a wallet division
+	ErrWalletAccessDenied = errors.New("wallet division access denied")
+	// ErrWalletTokenUnavailable is returned when no usable token is configured to read a corporation's wallets
+	ErrWalletTokenUnavailable = errors.New("wallet token unavailable")
+	// ErrInvalidWalletSettings is returned for wallet settings or budget alert rules that cannot be applied
+	ErrInvalidWalletSettings = errors.New("invalid wallet settings")
+	// ErrBudgetAlertNotFound is returned when a budget alert rule does not exist
+	ErrBudgetAlertNotFound = errors.New("budget alert not found")
+)
+
+// WalletPermissionChecker checks and looks up permissions (implemented by the permission manager)
+type WalletPermissionChecker interface {
+	HasPermission(ctx context.Context, characterID int64, permissionID string) (bool, error)
+	GetPermission(permissionID string) (permissions.Permission, bool)
+}
+
+// Notifier sends notifications on behalf of a user (implemented by the notifications service)
+type Notifier interface {
+	Send(ctx context.Context, sender *authModels.AuthenticatedUser, input *notificationsDto.SendNotificationInput) (*notificationModels.Operation, error)
+}
+
+// SetPermissionChecker sets the checker used for per-division wallet permissions
+func (s *Service) SetPermissionChecker(checker WalletPermissionChecker) {
+	s.permissionChecker = checker
+}
+
+// SetNotifier sets where budget alerts are sent
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// GetWalletSettings returns a corporation's wallet settings with every division filled in
+func (s *Service) GetWalletSettings(ctx context.Context, corporationID int) (*dto.WalletSettingsOutput, error) {
+	settings, err := s.walletSettings(ctx, corporationID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.WalletSettingsOutput{Body: *settings}, nil
+}
+
+// UpdateWalletSettings sets the token character and the names and permissions of wallet divisions
+func (s *Service) UpdateWalletSettings(ctx context.Context, user *authModels.AuthenticatedUser, input *dto.UpdateWalletSettingsInput) (*dto.WalletSettingsOutput, error) {
+	if _, err := s.walletTokenForCharacter(ctx, input.CorporationID, input.Body.TokenCharacterID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWalletSettings, err)
+	}
+
+	configured := make(map[int]models.WalletDivision)
+	for _, division := range input.Body.Divisions {
+		if _, duplicate := configured[division.Division]; duplicate {
+			return nil, fmt.Errorf("%w: division %d is configured twice", ErrInvalidWalletSettings, division.Division)
+		}
+		permissionID := division.ViewPermission
+		if permissionID == "" {
+			permissionID = WalletViewPermission
+		}
+		if s.permissionChecker != nil {
+			if _, exists := s.permissionChecker.GetPermission(permissionID); !exists {
+				return nil, fmt.Errorf("%w: unknown permission %s", ErrInvalidWalletSettings, permissionID)
+			}
+		}
+		configured[division.Division] = models.WalletDivision{
+			Division:       division.Division,
+			Name:           strings.TrimSpace(division.Name),
+			ViewPermission: permissionID,
+		}
+	}
+
+	settings := &models.WalletSettings{
+		CorporationID:    input.CorporationID,
+		TokenCharacterID: input.Body.TokenCharacterID,
+		Divisions:        resolveWalletDivisions(configured),
+		UpdatedAt:        time.Now().UTC(),
+		UpdatedBy:        user.UserID,
+	}
+	if err := s.repository.SaveWalletSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Corporation wallet settings updated", "corporation_id", input.CorporationID, "updated_by", user.UserID)
+	return s.GetWalletSettings(ctx, input.CorporationID)
+}
+
+// GetWallets returns the balances of the wallet divisions the user may read
+func (s *Service) GetWallets(ctx context.Context, user *authModels.AuthenticatedUser, corporationID int) (*dto.CorporationWalletsOutput, error) {
+	settings, err := s.walletSettings(ctx, corporationID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[int]models.WalletDivision)
+	for _, division := range settings.Divisions {
+		if s.canViewDivision(ctx, user, division) {
+			visible[division.Division] = division
+		}
+	}
+	if len(visible) == 0 {
+		return nil, ErrWalletAccessDenied
+	}
+
+	token, err := s.walletTokenForCharacter(ctx, corporationID, settings.TokenCharacterID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.eveClient.Corporation.GetCorporationWalletsWithCache(ctx, corporationID, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get corporation wallets: %w", err)
+	}
+
+	balances := []dto.WalletDivisionBalance{}
+	for _, esiWallet := range result.Data {
+		division, ok := visible[esiWallet.Division]
+		if !ok {
+			continue
+		}
+		balances = append(balances, dto.WalletDivisionBalance{
+			Division: esiWallet.Division,
+			Name:     division.Name,
+			Balance:  esiWallet.Balance,
+		})
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Division < balances[j].Division })
+
+	return &dto.CorporationWalletsOutput{
+		Body: dto.CorporationWalletsResult{
+			CorporationID: corporationID,
+			Divisions:     balances,
+			Cached:        result.Cache.Cached,
+			ExpiresAt:     result.Cache.ExpiresAt,
+		},
+	}, nil
+}
+
+// GetWalletJournal returns a wallet division's journal if the user may read the division
+func (s *Service) GetWalletJournal(ctx context.Context, user *authModels.AuthenticatedUser, corporationID, divisionNumber int) (*dto.WalletJournalOutput, error) {
+	settings, err := s.walletSettings(ctx, corporationID)
+	if err != nil {
+		return nil, err
+	}
+	division := settings.Divisions[divisionNumber-1]
+	if !s.canViewDivision(ctx, user, division) {
+		return nil, ErrWalletAccessDenied
+	}
+
+	token, err := s.walletTokenForCharacter(ctx, corporationID, settings.TokenCharacterID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.eveClient.Wallet.GetCorporationWalletJournalWithCache(ctx, corporationID, divisionNumber, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet journal: %w", err)
+	}
+
+	entries := make([]dto.WalletJournalEntry, len(result.Data))
+	for i, entry := range sortedJournal(result.Data) {
+		entries[i] = dto.WalletJournalEntry{
+			ID:            entry.ID,
+			Date:          entry.Date,
+			RefType:       entry.RefType,
+			Description:   entry.Description,
+			Amount:        valueOf(entry.Amount),
+			Balance:       valueOf(entry.Balance),
+			FirstPartyID:  valueOf(entry.FirstPartyID),
+			SecondPartyID: valueOf(entry.SecondPartyID),
+			Reason:        entry.Reason,
+		}
+	}
+
+	return &dto.WalletJournalOutput{
+		Body: dto.WalletJournalResult{
+			CorporationID: corporationID,
+			Division:      divisionNumber,
+			Name:          division.Name,
+			Entries:       entries,
+			Cached:        result.Cache.Cached,
+			ExpiresAt:     result.Cache.ExpiresAt,
+		},
+	}, nil
+}
+
+// ListBudgetAlerts returns a corporation's budget alert rules
+func (s *Service) ListBudgetAlerts(ctx context.Context, corporationID int) (*dto.BudgetAlertListOutput, error) {
+	rules, err := s.repository.ListBudgetAlerts(ctx, corporationID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &dto.BudgetAlertListOutput{}
+	output.Body.CorporationID = corporationID
+	output.Body.Alerts = rules
+	return output, nil
+}
+
+// CreateBudgetAlert adds a budget alert rule; its alerts are sent on behalf of the creating user
+func (s *Service) CreateBudgetAlert(ctx context.Context, user *authModels.AuthenticatedUser, corporationID int, request *dto.BudgetAlertRequest) (*dto.BudgetAlertOutput, error) {
+	now := time.Now().UTC()
+	rule := &models.BudgetAlertRule{
+		CorporationID:        corporationID,
+		Name:                 strings.TrimSpace(request.Name),
+		Type:                 request.Type,
+		Division:             request.Division,
+		Threshold:            request.Threshold,
+		GroupID:              request.GroupID,
+		Enabled:              request.Enabled == nil || *request.Enabled,
+		CreatedByUserID:      user.UserID,
+		CreatedByCharacterID: user.CharacterID,
+		CreatedByName:        user.CharacterName,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if err := s.repository.CreateBudgetAlert(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Budget alert created", "corporation_id", corporationID, "alert_id", rule.ID.Hex(), "type", rule.Type, "division", rule.Division)
+	return &dto.BudgetAlertOutput{Body: *rule}, nil
+}
+
+// UpdateBudgetAlert replaces a budget alert rule's settings. A raised balance alert is cleared so
+// the new threshold is evaluated from scratch.
+func (s *Service) UpdateBudgetAlert(ctx context.Context, corporationID int, alertID string, request *dto.BudgetAlertRequest) (*dto.BudgetAlertOutput, error) {
+	ruleID, err := primitive.ObjectIDFromHex(alertID)
+	if err != nil {
+		return nil, ErrBudgetAlertNotFound
+	}
+	rule, err := s.repository.GetBudgetAlert(ctx, corporationID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, ErrBudgetAlertNotFound
+	}
+
+	rule.Name = strings.TrimSpace(request.Name)
+	rule.Type = request.Type
+	rule.Division = request.Division
+	rule.Threshold = request.Threshold
+	rule.GroupID = request.GroupID
+	rule.Enabled = request.Enabled == nil || *request.Enabled
+	rule.Triggered = false
+	rule.UpdatedAt = time.Now().UTC()
+
+	if err := s.repository.UpdateBudgetAlert(ctx, rule.ID, bson.M{
+		"name":       rule.Name,
+		"type":       rule.Type,
+		"division":   rule.Division,
+		"threshold":  rule.Threshold,
+		"group_id":   rule.GroupID,
+		"enabled":    rule.Enabled,
+		"triggered":  rule.Triggered,
+		"updated_at": rule.UpdatedAt,
+	}); err != nil {
+		return nil, err
+	}
+	return &dto.BudgetAlertOutput{Body: *rule}, nil
+}
+
+// DeleteBudgetAlert removes a budget alert rule
+func (s *Service) DeleteBudgetAlert(ctx context.Context, corporationID int, alertID string) (*dto.BudgetAlertDeleteOutput, error) {
+	ruleID, err := primitive.ObjectIDFromHex(alertID)
+	if err != nil {
+		return nil, ErrBudgetAlertNotFound
+	}
+	deleted, err := s.repository.DeleteBudgetAlert(ctx, corporationID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, ErrBudgetAlertNotFound
+	}
+
+	output := &dto.BudgetAlertDeleteOutput{}
+	output.Body.Message = "Budget alert deleted"
+	return output, nil
+}
+
+// EvaluateBudgetAlerts checks every enabled budget alert rule against the current wallets and
+// notifies the rule's group when it fires. Returns the number of alerts sent.
+func (s *Service) EvaluateBudgetAlerts(ctx context.Context) (int, error) {
+	if s.notifier == nil {
+		return 0, fmt.Errorf("notifier not configured")
+	}
+	// Alerts read wallet journals, which the deployment may not fetch
+	if !residency.Enabled(ctx, residency.CategoryWallets) {
+		return 0, nil
+	}
+
+	rules, err := s.repository.ListEnabledBudgetAlerts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	byCorporation := make(map[int][]*models.BudgetAlertRule)
+	for _, rule := range rules {
+		byCorporation[rule.CorporationID] = append(byCorporation[rule.CorporationID], rule)
+	}
+
+	sent := 0
+	var failed []string
+	for corporationID, corporationRules := range byCorporation {
+		count, err := s.evaluateCorporationAlerts(ctx, corporationID, corporationRules)
+		sent += count
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to evaluate budget alerts", "corporation_id", corporationID, "error", err)
+			failed = append(failed, strconv.Itoa(corporationID))
+		}
+	}
+
+	if len(failed) > 0 {
+		return sent, fmt.Errorf("budget alerts could not be evaluated for corporations %s", strings.Join(failed, ", "))
+	}
+	return sent, nil
+}
+
+func (s *Service) evaluateCorporationAlerts(ctx context.Context, corporationID int, rules []*models.BudgetAlertRule) (int, error) {
+	settings, err := s.walletSettings(ctx, corporationID)
+	if err != nil {
+		return 0, err
+	}
+	token, err := s.walletTokenForCharacter(ctx, corporationID, settings.TokenCharacterID)
+	if err != nil {
+		return 0, err
+	}
+
+	corporationName := strconv.Itoa(corporationID)
+	if corporation, err := s.repository.GetCorporationByID(ctx, corporationID); err == nil && corporation != nil {
+		corporationName = corporation.Name
+	}
+
+	var balances map[int]float64
+	journals := make(map[int][]wallet.JournalEntry)
+	sent := 0
+
+	for _, rule := range rules {
+		division := settings.Divisions[rule.Division-1]
+
+		switch rule.Type {
+		case models.BudgetAlertBalanceBelow:
+			if balances == nil {
+				wallets, err := s.eveClient.Corporation.GetCorporationWallets(ctx, corporationID, token)
+				if err != nil {
+					return sent, fmt.Errorf("failed to get corporation wallets: %w", err)
+				}
+				balances = make(map[int]float64, len(wallets))
+				for _, esiWallet := range wallets {
+					balances[esiWallet.Division] = esiWallet.Balance
+				}
+			}
+
+			balance, ok := balances[rule.Division]
+			if !ok {
+				continue
+			}
+			below := balance < rule.Threshold
+			if below == rule.Triggered {
+				continue
+			}
+
+			update := bson.M{"triggered": below}
+			if below {
+				message := fmt.Sprintf("The %s wallet (division %d) of %s is down to %s ISK, below the %s ISK threshold.",
+					division.Name, rule.Division, corporationName, formatISK(balance), formatISK(rule.Threshold))
+				if err := s.sendBudgetAlert(ctx, rule, message); err != nil {
+					return sent, err
+				}
+				sent++
+				update["last_triggered_at"] = time.Now().UTC()
+			}
+			if err := s.repository.UpdateBudgetAlert(ctx, rule.ID, update); err != nil {
+				return sent, err
+			}
+
+		case models.BudgetAlertLargeTransaction:
+			journal, ok := journals[rule.Division]
+			if !ok {
+				entries, err := s.eveClient.Wallet.GetCorporationWalletJournal(ctx, corporationID, rule.Division, token)
+				if err != nil {
+					return sent, fmt.Errorf("failed to get wallet journal: %w", err)
+				}
+				journal = sortedJournal(entries)
+				journals[rule.Division] = journal
+			}
+
+			// Entries made before the rule existed are never alerted on
+			var large []wallet.JournalEntry
+			newestID := rule.LastJournalID
+			for _, entry := range journal {
+				if entry.ID <= rule.LastJournalID || entry.Date.Before(rule.CreatedAt) {
+					continue
+				}
+				if entry.ID > newestID {
+					newestID = entry.ID
+				}
+				if math.Abs(valueOf(entry.Amount)) >= rule.Threshold {
+					large = append(large, entry)
+				}
+			}
+			if newestID == rule.LastJournalID {
+				continue
+			}
+
+			update := bson.M{"last_journal_id": newestID}
+			if len(large) > 0 {
+				if err := s.sendBudgetAlert(ctx, rule, largeTransactionMessage(division, corporationName, rule, large)); err != nil {
+					return sent, err
+				}
+				sent++
+				update["last_triggered_at"] = time.Now().UTC()
+			}
+			if err := s.repository.UpdateBudgetAlert(ctx, rule.ID, update); err != nil {
+				return sent, err
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *Service) sendBudgetAlert(ctx context.Context, rule *models.BudgetAlertRule, message string) error {
+	input := &notificationsDto.SendNotificationInput{}
+	input.Body.Title = "Budget alert: " + rule.Name
+	input.Body.Message = message
+	input.Body.Level = "warning"
+	input.Body.Audience = []notificationsDto.AudienceSelectorInput{{Type: "group", GroupID: rule.GroupID}}
+
+	sender := &authModels.AuthenticatedUser{
+		UserID:        rule.CreatedByUserID,
+		CharacterID:   rule.CreatedByCharacterID,
+		CharacterName: rule.CreatedByName,
+	}
+	if _, err := s.notifier.Send(ctx, sender, input); err != nil {
+		return fmt.Errorf("failed to send budget alert %s: %w", rule.ID.Hex(), err)
+	}
+
+	slog.InfoContext(ctx, "Budget alert sent", "corporation_id", rule.CorporationID, "alert_id", rule.ID.Hex(), "type", rule.Type)
+	return nil
+}
+
+func largeTransactionMessage(division models.WalletDivision, corporationName string, rule *models.BudgetAlertRule, entries []wallet.JournalEntry) string {
+	var message strings.Builder
+	fmt.Fprintf(&message, "%d journal entries of at least %s ISK in the %s wallet (division %d) of %s:",
+		len(entries), formatISK(rule.Threshold), division.Name, rule.Division, corporationName)
+	for i, entry := range entries {
+		if i == maxAlertedEntries {
+			fmt.Fprintf(&message, "\n... and %d more", len(entries)-maxAlertedEntries)
+			break
+		}
+		fmt.Fprintf(&message, "\n%s %s ISK (%s)", entry.Date.UTC().Format("2006-01-02 15:04"), formatISK(valueOf(entry.Amount)), entry.RefType)
+		if entry.Reason != "" {
+			fmt.Fprintf(&message, ": %s", entry.Reason)
+		}
+	}
+	return message.String()
+}
+
+// walletSettings returns the stored settings with all seven divisions filled in, or the defaults
+func (s *Service) walletSettings(ctx context.Context, corporationID int) (*models.WalletSettings, error) {
+	settings, err := s.repository.GetWalletSettings(ctx, corporationID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &models.WalletSettings{CorporationID: corporationID}
+	}
+
+	configured := make(map[int]models.WalletDivision, len(settings.Divisions))
+	for _, division := range settings.Divisions {
+		configured[division.Division] = division
+	}
+	settings.Divisions = resolveWalletDivisions(configured)
+	return settings, nil
+}
+
+// resolveWalletDivisions returns all seven divisions, using the defaults for unconfigured ones
+func resolveWalletDivisions(configured map[int]models.WalletDivision) []models.WalletDivision {
+	divisions := make([]models.WalletDivision, 0, wallet.MaxDivision)
+	for number := wallet.MinDivision; number <= wallet.MaxDivision; number++ {
+		division, ok := configured[number]
+		if !ok {
+			division = models.WalletDivision{Division: number}
+		}
+		if division.Name == "" {
+			division.Name = fmt.Sprintf("Division %d", number)
+			if number == wallet.MinDivision {
+				division.Name = "Master Wallet"
+			}
+		}
+		if division.ViewPermission == "" {
+			division.ViewPermission = WalletViewPermission
+		}
+		divisions = append(divisions, division)
+	}
+	return divisions
+}
+
+func (s *Service) canViewDivision(ctx context.Context, user *authModels.AuthenticatedUser, division models.WalletDivision) bool {
+	if s.permissionChecker == nil {
+		// The route has already required the default permission
+		return division.ViewPermission == WalletViewPermission
+	}
+	allowed, err := s.permissionChecker.HasPermission(ctx, int64(user.CharacterID), division.ViewPermission)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to check wallet division permission", "character_id", user.CharacterID, "permission", division.ViewPermission, "error", err)
+		return false
+	}
+	return allowed
+}
+
+// walletTokenForCharacter returns the access token of the character configured to read a
+// corporation's wallets
+func (s *Service) walletTokenForCharacter(ctx context.Context, corporationID, characterID int) (string, error) {
+	if characterID == 0 {
+		return "", fmt.Errorf("%w: no token character configured for corporation %d", ErrWalletTokenUnavailable, corporationID)
+	}
+	if s.authService == nil {
+		return "", fmt.Errorf("%w: auth service not available", ErrWalletTokenUnavailable)
+	}
+
+	profile, err := s.authService.GetUserProfileByCharacterID(ctx, characterID)
+	if err != nil || profile == nil {
+		return "", fmt.Errorf("%w: no profile for character %d", ErrWalletTokenUnavailable, characterID)
+	}
+	if profile.CorporationID != corporationID {
+		return "", fmt.Errorf("%w: character %d is not a member of corporation %d", ErrWalletTokenUnavailable, characterID, corporationID)
+	}
+	if profile.AccessToken == "" {
+		return "", fmt.Errorf("%w: character %d does not have a valid access token", ErrWalletTokenUnavailable, characterID)
+	}
+	if !strings.Contains(profile.Scopes, models.WalletScope) {
+		return "", fmt.Errorf("%w: token of character %d is missing scope %s", ErrWalletTokenUnavailable, characterID, models.WalletScope)
+	}
+	return profile.AccessToken, nil
+}
+
+// sortedJournal returns journal entries newest first
+func sortedJournal(entries []wallet.JournalEntry) []wallet.JournalEntry {
+	sorted := append([]wallet.JournalEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID > sorted[j].ID })
+	return sorted
+}
+
+func valueOf[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
+
+// formatISK formats an amount with thousands separators and no decimals, e.g. -1,250,000
+func formatISK(amount float64) string {
+	digits := strconv.FormatFloat(math.Abs(math.Round(amount)), 'f', 0, 64)
+	var formatted strings.Builder
+	if amount <= -0.5 {
+		formatted.WriteByte('-')
+	}
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			formatted.WriteByte(',')
+		}
+		formatted.WriteRune(digit)
+	}
+	return formatted.String()
+}
@@ -172,6 +172,13 @@ type GetSyncStatusInput struct {
 	Limit         int    `query:"limit" minimum:"1" maximum:"50" default:"10" doc:"Number of recent sync records to return"`
 }
 
+// RoleSyncInput represents a drift check or reconcile of Discord roles against group memberships
+type RoleSyncInput struct {
+	Authorization string `header:"Authorization" example:"Bearer your-jwt-token" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" example:"falcon_auth_token=your-token" doc:"Authentication cookie"`
+	GuildID       string `query:"guild_id" example:"123456789012345678" doc:"Optional guild ID (empty for all enabled guilds)"`
+}
+
 // User Management Inputs
 
 // GetDiscordUserInput represents a request to get Discord user info
@@ -203,6 +203,31 @@ type ManualSyncResponse struct {
 	EstimatedDuration string `json:"estimated_duration,omitempty" example:"2-5 minutes" doc:"Estimated completion time"`
 }
 
+// RoleSyncReportOutput represents the response of a drift check or reconcile
+type RoleSyncReportOutput struct {
+	Body RoleSyncReportResponse
+}
+
+// RoleSyncReportResponse lists the linked Discord accounts whose roles differ from their group memberships
+type RoleSyncReportResponse struct {
+	DryRun         bool             `json:"dry_run" example:"true" doc:"Whether roles were left unchanged (drift check)"`
+	UsersChecked   int64            `json:"users_checked" example:"120" doc:"Linked Discord accounts checked"`
+	UsersWithDrift int              `json:"users_with_drift" example:"3" doc:"Account and guild pairs whose managed roles differ from the group memberships"`
+	UsersFailed    int64            `json:"users_failed" example:"0" doc:"Accounts that could not be checked"`
+	Changes        []RoleSyncChange `json:"changes" doc:"Role differences per account and guild"`
+	Errors         []string         `json:"errors" doc:"Guild and account errors"`
+	ProcessingTime int64            `json:"processing_time_ms" example:"5400" doc:"Duration in milliseconds"`
+}
+
+// RoleSyncChange is the managed roles an account is missing or holds without the mapped group
+type RoleSyncChange struct {
+	UserID       string   `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000" doc:"Go Falcon user UUID"`
+	DiscordID    string   `json:"discord_id" example:"123456789012345678" doc:"Discord user ID"`
+	GuildID      string   `json:"guild_id" example:"123456789012345678" doc:"Discord guild ID"`
+	RolesAdded   []string `json:"roles_added" doc:"Role IDs added, or missing in a drift check"`
+	RolesRemoved []string `json:"roles_removed" doc:"Role IDs removed, or held without the mapped group in a drift check"`
+}
+
 // Discord Guild Roles Output
 
 // DiscordGuildRolesOutput represents the response for fetching Discord guild roles
@@ -39,7 +39,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/auth/login",
 		Summary:     "Get Discord OAuth authorization URL",
 		Description: "Generate Discord OAuth authorization URL for user authentication or account linking",
-		Tags:        []string{"Discord Authentication"},
+		Tags:        []string{"Discord / OAuth"},
 	}, r.getDiscordAuthURL)
 
 	huma.Register(api, huma.Operation{
@@ -48,7 +48,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/auth/callback",
 		Summary:     "Handle Discord OAuth callback",
 		Description: "Process Discord OAuth callback and complete authentication or account linking",
-		Tags:        []string{"Discord Authentication"},
+		Tags:        []string{"Discord / OAuth"},
 	}, r.discordCallback)
 
 	huma.Register(api, huma.Operation{
@@ -57,7 +57,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/auth/link",
 		Summary:     "Link Discord account to existing user",
 		Description: "Link a Discord account to an existing Go Falcon user using OAuth tokens",
-		Tags:        []string{"Discord Authentication"},
+		Tags:        []string{"Discord / OAuth"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.linkDiscordAccount)
 
@@ -67,7 +67,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/auth/unlink/{discord_id}",
 		Summary:     "Unlink Discord account",
 		Description: "Unlink a Discord account from the current Go Falcon user",
-		Tags:        []string{"Discord Authentication"},
+		Tags:        []string{"Discord / OAuth"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.unlinkDiscordAccount)
 
@@ -77,7 +77,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/auth/status",
 		Summary:     "Get Discord authentication status",
 		Description: "Check if user has Discord accounts linked and get status information",
-		Tags:        []string{"Discord Authentication"},
+		Tags:        []string{"Discord / OAuth"},
 	}, r.getDiscordAuthStatus)
 
 	// User management routes
@@ -87,7 +87,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/users/{user_id}",
 		Summary:     "Get Discord user information",
 		Description: "Get Discord account information for a specific Go Falcon user",
-		Tags:        []string{"Discord Users"},
+		Tags:        []string{"Discord / Users"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.getDiscordUser)
 
@@ -97,7 +97,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/users",
 		Summary:     "List Discord users",
 		Description: "List all Discord users with filtering and pagination",
-		Tags:        []string{"Discord Users"},
+		Tags:        []string{"Discord / Users"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.listDiscordUsers)
 
@@ -108,7 +108,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/guilds",
 		Summary:     "Create Discord guild configuration",
 		Description: "Add a new Discord guild configuration with bot token for role management",
-		Tags:        []string{"Discord Guilds"},
+		Tags:        []string{"Discord / Guilds"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.createGuildConfig)
 
@@ -118,7 +118,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/guilds/{guild_id}",
 		Summary:     "Get Discord guild configuration",
 		Description: "Get configuration details for a specific Discord guild",
-		Tags:        []string{"Discord Guilds"},
+		Tags:        []string{"Discord / Guilds"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.getGuildConfig)
 
@@ -128,7 +128,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/guilds/{guild_id}",
 		Summary:     "Update Discord guild configuration",
 		Description: "Update configuration for an existing Discord guild",
-		Tags:        []string{"Discord Guilds"},
+		Tags:        []string{"Discord / Guilds"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.updateGuildConfig)
 
@@ -138,7 +138,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/guilds/{guild_id}",
 		Summary:     "Delete Discord guild configuration",
 		Description: "Remove a Discord guild configuration and all associated role mappings",
-		Tags:        []string{"Discord Guilds"},
+		Tags:        []string{"Discord / Guilds"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.deleteGuildConfig)
 
@@ -148,7 +148,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/guilds",
 		Summary:     "List Discord guild configurations",
 		Description: "List all Discord guild configurations with filtering and pagination",
-		Tags:        []string{"Discord Guilds"},
+		Tags:        []string{"Discord / Guilds"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.listGuildConfigs)
 
@@ -158,7 +158,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/guilds/{guild_id}/roles",
 		Summary:     "Get Discord guild roles",
 		Description: "Fetch all roles from a Discord guild using the Discord API",
-		Tags:        []string{"Discord Guilds"},
+		Tags:        []string{"Discord / Guilds"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.getGuildRoles)
 
@@ -169,7 +169,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/sync/manual",
 		Summary:     "Trigger manual role synchronization",
 		Description: "Manually trigger Discord role synchronization for all guilds or specific targets",
-		Tags:        []string{"Discord Sync"},
+		Tags:        []string{"Discord / Sync"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.triggerManualSync)
 
@@ -179,7 +179,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/sync/user/{user_id}",
 		Summary:     "Synchronize specific user roles",
 		Description: "Synchronize Discord roles for a specific Go Falcon user",
-		Tags:        []string{"Discord Sync"},
+		Tags:        []string{"Discord / Sync"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.syncUser)
 
@@ -189,10 +189,30 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/sync/status",
 		Summary:     "Get synchronization status",
 		Description: "Get current and recent Discord role synchronization status",
-		Tags:        []string{"Discord Sync"},
+		Tags:        []string{"Discord / Sync"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.getSyncStatus)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "getDiscordRoleDrift",
+		Method:      http.MethodGet,
+		Path:        "/discord/sync/drift",
+		Summary:     "Detect Discord role drift",
+		Description: "List linked accounts whose mapped Discord roles differ from their group memberships, without changing any role",
+		Tags:        []string{"Discord / Sync"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, r.getRoleDrift)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "reconcileDiscordRoles",
+		Method:      http.MethodPost,
+		Path:        "/discord/sync/reconcile",
+		Summary:     "Reconcile Discord roles",
+		Description: "Add and remove mapped Discord roles so they match group memberships, and report the changes made",
+		Tags:        []string{"Discord / Sync"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, r.reconcileRoles)
+
 	// Role mapping routes
 	huma.Register(api, huma.Operation{
 		OperationID: "createRoleMapping",
@@ -200,7 +220,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/guilds/{guild_id}/role-mappings",
 		Summary:     "Create Discord role mapping",
 		Description: "Create a new mapping between a Go Falcon group and Discord role",
-		Tags:        []string{"Discord Role Mappings"},
+		Tags:        []string{"Discord / Roles"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.createRoleMapping)
 
@@ -210,7 +230,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/guilds/{guild_id}/role-mappings",
 		Summary:     "List Discord role mappings",
 		Description: "List role mappings for a specific Discord guild with filtering",
-		Tags:        []string{"Discord Role Mappings"},
+		Tags:        []string{"Discord / Roles"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.listRoleMappings)
 
@@ -220,7 +240,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/role-mappings/{mapping_id}",
 		Summary:     "Get Discord role mapping",
 		Description: "Get details for a specific Discord role mapping",
-		Tags:        []string{"Discord Role Mappings"},
+		Tags:        []string{"Discord / Roles"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.getRoleMapping)
 
@@ -230,7 +250,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/role-mappings/{mapping_id}",
 		Summary:     "Update Discord role mapping",
 		Description: "Update an existing Discord role mapping",
-		Tags:        []string{"Discord Role Mappings"},
+		Tags:        []string{"Discord / Roles"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.updateRoleMapping)
 
@@ -240,7 +260,7 @@ func (r *Routes) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/discord/role-mappings/{mapping_id}",
 		Summary:     "Delete Discord role mapping",
 		Description: "Delete a Discord role mapping",
-		Tags:        []string{"Discord Role Mappings"},
+		Tags:        []string{"Discord / Roles"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, r.deleteRoleMapping)
 
@@ -470,6 +490,24 @@ func (r *Routes) getSyncStatus(ctx context.Context, input *dto.GetSyncStatusInpu
 	return r.service.GetSyncStatus(ctx, input)
 }
 
+func (r *Routes) getRoleDrift(ctx context.Context, input *dto.RoleSyncInput) (*dto.RoleSyncReportOutput, error) {
+	_, err := r.discordAdapter.RequireAuth(ctx, input.Authorization, input.Cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.service.GetRoleDrift(ctx, input)
+}
+
+func (r *Routes) reconcileRoles(ctx context.Context, input *dto.RoleSyncInput) (*dto.RoleSyncReportOutput, error) {
+	_, err := r.discordAdapter.RequireAuth(ctx, input.Authorization, input.Cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.service.ReconcileRoles(ctx, input)
+}
+
 // Role mapping handlers
 
 func (r *Routes) createRoleMapping(ctx context.Context, input *dto.CreateRoleMappingInput) (*dto.DiscordRoleMappingOutput, error) {
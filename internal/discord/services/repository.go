@@ -160,6 +160,22 @@ func (r *Repository) GetDiscordUsersByUserID(ctx context.Context, userID string)
 	return users, nil
 }
 
+// GetUserIDByCharacterID returns the Go Falcon user owning a character, empty if none does
+func (r *Repository) GetUserIDByCharacterID(ctx context.Context, characterID int64) (string, error) {
+	var profile struct {
+		UserID string `bson:"user_id"`
+	}
+	err := r.db.Collection("user_profiles").FindOne(ctx, bson.M{"character_id": characterID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1})).Decode(&profile)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get user of character: %w", err)
+	}
+	return profile.UserID, nil
+}
+
 // UpdateDiscordUser updates a Discord user record
 func (r *Repository) UpdateDiscordUser(ctx context.Context, id primitive.ObjectID, update bson.M) error {
 	update["updated_at"] = time.Now()
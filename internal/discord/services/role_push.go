@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/discord/dto"
+	"go-falcon/pkg/config"
+)
+
+const (
+	defaultRolePushDelay = 10 * time.Second
+	maxRolePushDelay     = 5 * time.Minute
+	rolePushTimeout      = 2 * time.Minute
+)
+
+// RoleSyncSettingsSource reads the Discord role sync options an administrator set in site settings
+type RoleSyncSettingsSource interface {
+	DiscordRoleSyncSettings(ctx context.Context) (map[string]interface{}, error)
+}
+
+// SetRoleSyncSettingsSource lets site settings control the role push on membership change
+func (s *Service) SetRoleSyncSettingsSource(source RoleSyncSettingsSource) {
+	s.roleSyncSettings = source
+}
+
+// rolePushOptions returns whether membership changes are pushed and after which delay. Pushing is
+// on with the default delay unless the discord_role_sync setting says otherwise.
+func (s *Service) rolePushOptions(ctx context.Context) (bool, time.Duration) {
+	enabled, delay := true, defaultRolePushDelay
+	if s.roleSyncSettings == nil {
+		return enabled, delay
+	}
+
+	fields, err := s.roleSyncSettings.DiscordRoleSyncSettings(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read Discord role sync settings", "error", err)
+		return enabled, delay
+	}
+	if push, ok := fields["push_on_change"].(bool); ok {
+		enabled = push
+	}
+	if value, ok := fields["push_delay"].(string); ok {
+		if parsed, err := config.ParseDuration(value); err == nil && parsed >= 0 {
+			delay = min(parsed, maxRolePushDelay)
+		}
+	}
+	return enabled, delay
+}
+
+// MembershipChanged queues a role push for the user owning a character whose membership of a
+// mapped group changed; it is registered as a groups membership listener. Changes within the push
+// delay are batched into one sync of the user.
+func (s *Service) MembershipChanged(ctx context.Context, groupID primitive.ObjectID, characterID int64, added bool) {
+	go s.queueRolePush(context.WithoutCancel(ctx), groupID, characterID)
+}
+
+func (s *Service) queueRolePush(ctx context.Context, groupID primitive.ObjectID, characterID int64) {
+	enabled, delay := s.rolePushOptions(ctx)
+	if !enabled {
+		return
+	}
+
+	mappings, err := s.repo.GetRoleMappingsByGroupIDs(ctx, []primitive.ObjectID{groupID})
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to get role mappings for membership change", "group_id", groupID.Hex(), "error", err)
+		return
+	}
+	if len(mappings) == 0 {
+		return
+	}
+
+	userID, err := s.repo.GetUserIDByCharacterID(ctx, characterID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to resolve user for membership change", "character_id", characterID, "error", err)
+		return
+	}
+	if userID == "" {
+		return
+	}
+	discordUsers, err := s.repo.GetDiscordUsersByUserID(ctx, userID)
+	if err != nil || len(discordUsers) == 0 {
+		return
+	}
+
+	s.pushMu.Lock()
+	if s.pendingPushes[userID] {
+		s.pushMu.Unlock()
+		return
+	}
+	s.pendingPushes[userID] = true
+	s.pushMu.Unlock()
+
+	time.AfterFunc(delay, func() { s.pushUserRoles(ctx, userID) })
+}
+
+// pushUserRoles syncs a user's roles in every enabled guild. The user is dequeued first so that
+// changes made during the sync queue another push.
+func (s *Service) pushUserRoles(ctx context.Context, userID string) {
+	s.pushMu.Lock()
+	delete(s.pendingPushes, userID)
+	s.pushMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, rolePushTimeout)
+	defer cancel()
+
+	stats, err := s.syncService.SyncUser(ctx, userID, nil, false)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to push Discord roles after membership change", "user_id", userID, "error", err)
+		return
+	}
+	if len(stats.Errors) > 0 {
+		slog.WarnContext(ctx, "Discord role push completed with errors", "user_id", userID, "errors", stats.Errors)
+	}
+}
+
+// GetRoleDrift reports the linked accounts whose managed Discord roles differ from their group
+// memberships, without changing any role
+func (s *Service) GetRoleDrift(ctx context.Context, input *dto.RoleSyncInput) (*dto.RoleSyncReportOutput, error) {
+	return s.roleSyncReport(ctx, input.GuildID, true)
+}
+
+// ReconcileRoles brings the managed Discord roles of every linked account in line with their group
+// memberships and reports what changed
+func (s *Service) ReconcileRoles(ctx context.Context, input *dto.RoleSyncInput) (*dto.RoleSyncReportOutput, error) {
+	return s.roleSyncReport(ctx, input.GuildID, false)
+}
+
+func (s *Service) roleSyncReport(ctx context.Context, guildID string, dryRun bool) (*dto.RoleSyncReportOutput, error) {
+	var stats *SyncStats
+	var err error
+	if guildID != "" {
+		stats, err = s.syncService.SyncGuild(ctx, guildID, dryRun)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Failed to sync guild: " + err.Error())
+		}
+	} else {
+		stats, err = s.syncService.SyncAllGuilds(ctx, dryRun)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to sync guilds", err)
+		}
+	}
+
+	report := dto.RoleSyncReportResponse{
+		DryRun:         dryRun,
+		UsersChecked:   stats.ProcessedUsers,
+		UsersFailed:    stats.FailedUsers,
+		Changes:        []dto.RoleSyncChange{},
+		Errors:         stats.Errors,
+		ProcessingTime: stats.ProcessingTime,
+	}
+	for _, result := range stats.Results {
+		if len(result.RolesAdded) == 0 && len(result.RolesRemoved) == 0 {
+			continue
+		}
+		report.Changes = append(report.Changes, dto.RoleSyncChange{
+			UserID:       result.UserID,
+			DiscordID:    result.DiscordID,
+			GuildID:      result.GuildID,
+			RolesAdded:   result.RolesAdded,
+			RolesRemoved: result.RolesRemoved,
+		})
+	}
+	report.UsersWithDrift = len(report.Changes)
+
+	return &dto.RoleSyncReportOutput{Body: report}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -26,6 +27,9 @@ type Service struct {
 	characterService   CharacterServiceInterface
 	corporationService CorporationServiceInterface
 	userService        UserServiceInterface
+	roleSyncSettings   RoleSyncSettingsSource
+	pushMu             sync.Mutex
+	pendingPushes      map[string]bool // Users with a queued role push
 }
 
 // NewService creates a new Discord service
@@ -44,6 +48,7 @@ func NewService(db *database.MongoDB, groupsService GroupsServiceInterface, char
 		characterService:   characterService,
 		corporationService: corporationService,
 		userService:        userService,
+		pendingPushes:      make(map[string]bool),
 	}
 }
 
@@ -0,0 +1,41 @@
+package dto
+
+// ListFittingsInput represents the input for listing the caller's fittings
+type ListFittingsInput struct {
+	Page          int    `query:"page" minimum:"1" default:"1" doc:"Page number"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Items per page"`
+	ShipTypeID    int32  `query:"ship_type_id" doc:"Filter by ship type ID (0 means no filter)"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// GetFittingInput represents the input for retrieving a single fitting
+type GetFittingInput struct {
+	FittingID     string `path:"fitting_id" doc:"Fitting ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// DeleteFittingInput represents the input for deleting a fitting
+type DeleteFittingInput struct {
+	FittingID     string `path:"fitting_id" doc:"Fitting ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// ImportESIFittingsInput represents the input for importing in-game fittings from ESI
+type ImportESIFittingsInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// ImportKillmailFittingInput represents the input for saving a killmail victim fit
+type ImportKillmailFittingInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Body          struct {
+		KillmailID int64  `json:"killmail_id" minimum:"1" doc:"Killmail ID"`
+		Hash       string `json:"hash" minLength:"1" doc:"Killmail hash"`
+		Name       string `json:"name,omitempty" maxLength:"50" doc:"Optional fitting name (defaults to the killmail reference)"`
+	}
+}
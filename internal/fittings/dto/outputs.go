@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"time"
+
+	"go-falcon/internal/fittings/models"
+)
+
+// FittingResponse represents a stored fitting
+type FittingResponse struct {
+	ID           string               `json:"id"`
+	CharacterID  int                  `json:"character_id"`
+	Name         string               `json:"name"`
+	Description  string               `json:"description,omitempty"`
+	ShipTypeID   int32                `json:"ship_type_id"`
+	Items        []models.FittingItem `json:"items"`
+	Source       string               `json:"source" enum:"esi,killmail"`
+	ESIFittingID *int64               `json:"esi_fitting_id,omitempty"`
+	KillmailID   *int64               `json:"killmail_id,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+// FittingOutput represents the output for a single fitting
+type FittingOutput struct {
+	Body FittingResponse `json:"body"`
+}
+
+// FittingListResponse represents a paginated list of fittings
+type FittingListResponse struct {
+	Fittings []FittingResponse `json:"fittings"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	Limit    int               `json:"limit"`
+}
+
+// FittingListOutput represents the output for listing fittings
+type FittingListOutput struct {
+	Body FittingListResponse `json:"body"`
+}
+
+// ImportESIFittingsResponse summarises an ESI fitting import
+type ImportESIFittingsResponse struct {
+	Imported int `json:"imported" doc:"Fittings created"`
+	Updated  int `json:"updated" doc:"Existing fittings refreshed from ESI"`
+	Total    int `json:"total" doc:"Fittings returned by ESI"`
+}
+
+// ImportESIFittingsOutput represents the output for an ESI fitting import
+type ImportESIFittingsOutput struct {
+	Body ImportESIFittingsResponse `json:"body"`
+}
+
+// DeleteFittingResponse represents the result of deleting a fitting
+type DeleteFittingResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// DeleteFittingOutput represents the output for deleting a fitting
+type DeleteFittingOutput struct {
+	Body DeleteFittingResponse `json:"body"`
+}
+
+// FittingsStatusResponse represents the module status response
+type FittingsStatusResponse struct {
+	Module  string `json:"module" description:"Module name"`
+	Status  string `json:"status" enum:"healthy,unhealthy" description:"Module health status"`
+	Message string `json:"message,omitempty" description:"Optional status message or error details"`
+}
+
+// StatusOutput represents the module status output
+type StatusOutput struct {
+	Body FittingsStatusResponse `json:"body"`
+}
+
+// ToFittingResponse converts a fitting model into its response form
+func ToFittingResponse(fitting *models.Fitting) FittingResponse {
+	items := fitting.Items
+	if items == nil {
+		items = []models.FittingItem{}
+	}
+	return FittingResponse{
+		ID:           fitting.ID.Hex(),
+		CharacterID:  fitting.CharacterID,
+		Name:         fitting.Name,
+		Description:  fitting.Description,
+		ShipTypeID:   fitting.ShipTypeID,
+		Items:        items,
+		Source:       string(fitting.Source),
+		ESIFittingID: fitting.ESIFittingID,
+		KillmailID:   fitting.KillmailID,
+		CreatedAt:    fitting.CreatedAt,
+		UpdatedAt:    fitting.UpdatedAt,
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FittingsCollection = "fittings"
+)
+
+// FittingSource identifies where a stored fitting came from
+type FittingSource string
+
+const (
+	FittingSourceESI      FittingSource = "esi"
+	FittingSourceKillmail FittingSource = "killmail"
+)
+
+// Fitting represents a ship fitting stored for a character
+type Fitting struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CharacterID  int                `bson:"character_id" json:"character_id"`
+	Name         string             `bson:"name" json:"name"`
+	Description  string             `bson:"description,omitempty" json:"description,omitempty"`
+	ShipTypeID   int32              `bson:"ship_type_id" json:"ship_type_id"`
+	Items        []FittingItem      `bson:"items" json:"items"`
+	Source       FittingSource      `bson:"source" json:"source"`
+	ESIFittingID *int64             `bson:"esi_fitting_id,omitempty" json:"esi_fitting_id,omitempty"`
+	KillmailID   *int64             `bson:"killmail_id,omitempty" json:"killmail_id,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// FittingItem represents a module, charge, drone or cargo item in a fitting.
+// Flag uses the ESI fitting flag names (HiSlot0, MedSlot3, DroneBay, Cargo, ...).
+type FittingItem struct {
+	TypeID   int32  `bson:"type_id" json:"type_id"`
+	Flag     string `bson:"flag" json:"flag"`
+	Quantity int32  `bson:"quantity" json:"quantity"`
+}
@@ -0,0 +1,58 @@
+package fittings
+
+import (
+	"context"
+
+	"go-falcon/internal/fittings/routes"
+	"go-falcon/internal/fittings/services"
+	"go-falcon/pkg/database"
+	"go-falcon/pkg/evegateway"
+	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/module"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/go-chi/chi/v5"
+)
+
+// Module represents the fittings module
+type Module struct {
+	*module.BaseModule
+	service              *services.Service
+	repository           *services.Repository
+	permissionMiddleware *middleware.PermissionMiddleware
+	authService          routes.AuthService
+}
+
+// New creates a new fittings module instance
+func New(mongodb *database.MongoDB, redis *database.Redis, eveGateway *evegateway.Client, killmailService services.KillmailService, permissionMiddleware *middleware.PermissionMiddleware, authService routes.AuthService) *Module {
+	repository := services.NewRepository(mongodb)
+	service := services.NewService(repository, eveGateway, killmailService)
+
+	return &Module{
+		BaseModule:           module.NewBaseModule("fittings", mongodb, redis),
+		service:              service,
+		repository:           repository,
+		permissionMiddleware: permissionMiddleware,
+		authService:          authService,
+	}
+}
+
+// RegisterUnifiedRoutes registers all fittings routes with the unified API gateway
+func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string) {
+	routes.RegisterFittingsRoutes(api, basePath, m.service, m.permissionMiddleware, m.authService)
+}
+
+// Routes registers routes on a Chi router (implements module.Module interface)
+func (m *Module) Routes(r chi.Router) {
+	// Fittings module uses only Huma v2 unified routes
+}
+
+// Initialize performs module initialization tasks
+func (m *Module) Initialize(ctx context.Context) error {
+	return m.repository.CreateIndexes(ctx)
+}
+
+// GetService returns the service instance for this module
+func (m *Module) GetService() *services.Service {
+	return m.service
+}
@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/internal/fittings/dto"
+	"go-falcon/internal/fittings/services"
+	"go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// FittingsReadScope is the ESI scope required to import in-game fittings
+const FittingsReadScope = "esi-fittings.read_fittings.v1"
+
+// AuthService interface for auth operations we need
+type AuthService interface {
+	GetUserProfileByCharacterID(ctx context.Context, characterID int) (*models.UserProfile, error)
+}
+
+// RegisterFittingsRoutes registers fittings routes on a shared Huma API
+func RegisterFittingsRoutes(api huma.API, basePath string, service *services.Service, permissionMiddleware *middleware.PermissionMiddleware, authService AuthService) {
+	// Status endpoint (public, no auth required)
+	huma.Register(api, huma.Operation{
+		OperationID: "fittings-get-status",
+		Method:      http.MethodGet,
+		Path:        basePath + "/status",
+		Summary:     "Get fittings module status",
+		Description: "Returns the health status of the fittings module",
+		Tags:        []string{"Module Status"},
+	}, func(ctx context.Context, input *struct{}) (*dto.StatusOutput, error) {
+		return &dto.StatusOutput{Body: *service.GetStatus(ctx)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "fittings-list",
+		Method:      http.MethodGet,
+		Path:        basePath,
+		Summary:     "List my fittings",
+		Description: "Lists fittings stored for the authenticated character",
+		Tags:        []string{"Fittings"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ListFittingsInput) (*dto.FittingListOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := service.ListFittings(ctx, user.CharacterID, input)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list fittings", err)
+		}
+		return &dto.FittingListOutput{Body: *response}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "fittings-get",
+		Method:      http.MethodGet,
+		Path:        basePath + "/{fitting_id}",
+		Summary:     "Get fitting",
+		Description: "Returns a single fitting owned by the authenticated character",
+		Tags:        []string{"Fittings"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.GetFittingInput) (*dto.FittingOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		fitting, err := service.GetFitting(ctx, user.CharacterID, input.FittingID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get fitting", err)
+		}
+		if fitting == nil {
+			return nil, huma.Error404NotFound("Fitting not found")
+		}
+		return &dto.FittingOutput{Body: dto.ToFittingResponse(fitting)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "fittings-delete",
+		Method:      http.MethodDelete,
+		Path:        basePath + "/{fitting_id}",
+		Summary:     "Delete fitting",
+		Description: "Deletes a fitting owned by the authenticated character",
+		Tags:        []string{"Fittings"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DeleteFittingInput) (*dto.DeleteFittingOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		deleted, err := service.DeleteFitting(ctx, user.CharacterID, input.FittingID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to delete fitting", err)
+		}
+		if !deleted {
+			return nil, huma.Error404NotFound("Fitting not found")
+		}
+		return &dto.DeleteFittingOutput{Body: dto.DeleteFittingResponse{Success: true, Message: "Fitting deleted successfully"}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "fittings-import-esi",
+		Method:      http.MethodPost,
+		Path:        basePath + "/import/esi",
+		Summary:     "Import in-game fittings",
+		Description: "Imports the authenticated character's saved in-game fittings from ESI. Requires the esi-fittings.read_fittings.v1 scope. Re-importing refreshes previously imported fittings.",
+		Tags:        []string{"Fittings"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ImportESIFittingsInput) (*dto.ImportESIFittingsOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		profile, err := authService.GetUserProfileByCharacterID(ctx, user.CharacterID)
+		if err != nil || profile == nil {
+			return nil, huma.Error500InternalServerError("Failed to retrieve user profile", err)
+		}
+		if !slices.Contains(strings.Fields(profile.Scopes), FittingsReadScope) {
+			return nil, huma.Error403Forbidden("Missing required EVE scope: " + FittingsReadScope)
+		}
+		if profile.AccessToken == "" || time.Now().After(profile.TokenExpiry) {
+			return nil, huma.Error401Unauthorized("EVE access token expired, please re-authenticate")
+		}
+
+		response, err := service.ImportFromESI(ctx, user.CharacterID, profile.AccessToken)
+		if err != nil {
+			return nil, huma.Error502BadGateway("Failed to import fittings from ESI", err)
+		}
+		return &dto.ImportESIFittingsOutput{Body: *response}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "fittings-import-killmail",
+		Method:        http.MethodPost,
+		Path:          basePath + "/import/killmail",
+		Summary:       "Save killmail victim fit",
+		Description:   "Converts the victim's fit of a killmail into a stored fitting for the authenticated character",
+		Tags:          []string{"Fittings"},
+		Security:      []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+		DefaultStatus: http.StatusCreated,
+	}, func(ctx context.Context, input *dto.ImportKillmailFittingInput) (*dto.FittingOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		fitting, err := service.ImportFromKillmail(ctx, user.CharacterID, input.Body.KillmailID, input.Body.Hash, input.Body.Name)
+		if errors.Is(err, services.ErrKillmailUnavailable) {
+			return nil, huma.Error502BadGateway("Failed to load killmail", err)
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to save killmail fit", err)
+		}
+		return &dto.FittingOutput{Body: dto.ToFittingResponse(fitting)}, nil
+	})
+}
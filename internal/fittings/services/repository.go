@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go-falcon/internal/fittings/models"
+	"go-falcon/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewRepository(db *database.MongoDB) *Repository {
+	return &Repository{
+		db:         db,
+		collection: db.Database.Collection(models.FittingsCollection),
+	}
+}
+
+// GetByID retrieves a fitting by its ID
+func (r *Repository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Fitting, error) {
+	var fitting models.Fitting
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&fitting)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &fitting, nil
+}
+
+// ListByCharacter returns a page of fittings owned by a character, newest first
+func (r *Repository) ListByCharacter(ctx context.Context, characterID int, shipTypeID int32, page, limit int) ([]models.Fitting, int64, error) {
+	filter := bson.M{"character_id": characterID}
+	if shipTypeID > 0 {
+		filter["ship_type_id"] = shipTypeID
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var fittings []models.Fitting
+	if err := cursor.All(ctx, &fittings); err != nil {
+		return nil, 0, err
+	}
+	return fittings, total, nil
+}
+
+// UpsertESIFitting inserts or refreshes a fitting imported from ESI.
+// Returns true when a new document was created.
+func (r *Repository) UpsertESIFitting(ctx context.Context, fitting *models.Fitting) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"character_id":   fitting.CharacterID,
+		"source":         models.FittingSourceESI,
+		"esi_fitting_id": fitting.ESIFittingID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"name":         fitting.Name,
+			"description":  fitting.Description,
+			"ship_type_id": fitting.ShipTypeID,
+			"items":        fitting.Items,
+			"updated_at":   now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": now,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// Create inserts a new fitting
+func (r *Repository) Create(ctx context.Context, fitting *models.Fitting) error {
+	now := time.Now()
+	fitting.ID = primitive.NewObjectID()
+	fitting.CreatedAt = now
+	fitting.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, fitting)
+	return err
+}
+
+// Delete removes a fitting owned by a character
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID, characterID int) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "character_id": characterID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// CheckHealth verifies database connectivity
+func (r *Repository) CheckHealth(ctx context.Context) error {
+	return r.db.HealthCheck(ctx)
+}
+
+// CreateIndexes creates necessary indexes for the fittings collection
+func (r *Repository) CreateIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "character_id", Value: 1},
+				{Key: "updated_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "character_id", Value: 1},
+				{Key: "ship_type_id", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "character_id", Value: 1},
+				{Key: "source", Value: 1},
+				{Key: "esi_fitting_id", Value: 1},
+			},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"source": models.FittingSourceESI}),
+		},
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"go-falcon/internal/fittings/dto"
+	"go-falcon/internal/fittings/models"
+	killmailModels "go-falcon/internal/killmails/models"
+	"go-falcon/pkg/evegateway"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrKillmailUnavailable is returned when the killmail of a fit import can be read neither from
+// the database nor from ESI
+var ErrKillmailUnavailable = errors.New("killmail unavailable")
+
+// KillmailService is the subset of the killmails service needed to convert victim fits
+type KillmailService interface {
+	GetKillmail(ctx context.Context, killmailID int64, hash string) (*killmailModels.Killmail, error)
+}
+
+type Service struct {
+	repository      *Repository
+	eveGateway      *evegateway.Client
+	killmailService KillmailService
+}
+
+func NewService(repository *Repository, eveGateway *evegateway.Client, killmailService KillmailService) *Service {
+	return &Service{
+		repository:      repository,
+		eveGateway:      eveGateway,
+		killmailService: killmailService,
+	}
+}
+
+// ListFittings returns a page of the character's stored fittings
+func (s *Service) ListFittings(ctx context.Context, characterID int, input *dto.ListFittingsInput) (*dto.FittingListResponse, error) {
+	fittings, total, err := s.repository.ListByCharacter(ctx, characterID, input.ShipTypeID, input.Page, input.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fittings: %w", err)
+	}
+
+	response := &dto.FittingListResponse{
+		Fittings: make([]dto.FittingResponse, len(fittings)),
+		Total:    total,
+		Page:     input.Page,
+		Limit:    input.Limit,
+	}
+	for i := range fittings {
+		response.Fittings[i] = dto.ToFittingResponse(&fittings[i])
+	}
+	return response, nil
+}
+
+// GetFitting returns a fitting if it belongs to the character
+func (s *Service) GetFitting(ctx context.Context, characterID int, fittingID string) (*models.Fitting, error) {
+	id, err := primitive.ObjectIDFromHex(fittingID)
+	if err != nil {
+		return nil, nil
+	}
+
+	fitting, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fitting: %w", err)
+	}
+	if fitting == nil || fitting.CharacterID != characterID {
+		return nil, nil
+	}
+	return fitting, nil
+}
+
+// DeleteFitting removes a fitting owned by the character
+func (s *Service) DeleteFitting(ctx context.Context, characterID int, fittingID string) (bool, error) {
+	id, err := primitive.ObjectIDFromHex(fittingID)
+	if err != nil {
+		return false, nil
+	}
+	return s.repository.Delete(ctx, id, characterID)
+}
+
+// ImportFromESI imports the character's in-game fittings, refreshing ones imported earlier
+func (s *Service) ImportFromESI(ctx context.Context, characterID int, token string) (*dto.ImportESIFittingsResponse, error) {
+	esiFittings, err := s.eveGateway.Fittings.GetCharacterFittings(ctx, characterID, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fittings from ESI: %w", err)
+	}
+
+	response := &dto.ImportESIFittingsResponse{Total: len(esiFittings)}
+	for _, esiFitting := range esiFittings {
+		fittingID := esiFitting.FittingID
+		fitting := &models.Fitting{
+			CharacterID:  characterID,
+			Name:         esiFitting.Name,
+			Description:  esiFitting.Description,
+			ShipTypeID:   esiFitting.ShipTypeID,
+			Items:        make([]models.FittingItem, len(esiFitting.Items)),
+			Source:       models.FittingSourceESI,
+			ESIFittingID: &fittingID,
+		}
+		for i, item := range esiFitting.Items {
+			fitting.Items[i] = models.FittingItem{TypeID: item.TypeID, Flag: item.Flag, Quantity: item.Quantity}
+		}
+
+		created, err := s.repository.UpsertESIFitting(ctx, fitting)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store fitting %d: %w", fittingID, err)
+		}
+		if created {
+			response.Imported++
+		} else {
+			response.Updated++
+		}
+	}
+
+	slog.InfoContext(ctx, "Imported fittings from ESI",
+		"character_id", characterID,
+		"imported", response.Imported,
+		"updated", response.Updated)
+
+	return response, nil
+}
+
+// ImportFromKillmail stores the victim's fit of a killmail as a fitting for the character
+func (s *Service) ImportFromKillmail(ctx context.Context, characterID int, killmailID int64, hash, name string) (*models.Fitting, error) {
+	killmail, err := s.killmailService.GetKillmail(ctx, killmailID, hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKillmailUnavailable, err)
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("Killmail %d", killmailID)
+	}
+
+	id := killmailID
+	fitting := &models.Fitting{
+		CharacterID: characterID,
+		Name:        name,
+		ShipTypeID:  int32(killmail.Victim.ShipTypeID),
+		Items:       convertKillmailItems(killmail.Victim.Items),
+		Source:      models.FittingSourceKillmail,
+		KillmailID:  &id,
+	}
+
+	if err := s.repository.Create(ctx, fitting); err != nil {
+		return nil, fmt.Errorf("failed to store fitting: %w", err)
+	}
+	return fitting, nil
+}
+
+// GetStatus returns the health status of the fittings module
+func (s *Service) GetStatus(ctx context.Context) *dto.FittingsStatusResponse {
+	if err := s.repository.CheckHealth(ctx); err != nil {
+		return &dto.FittingsStatusResponse{
+			Module:  "fittings",
+			Status:  "unhealthy",
+			Message: "Database connection failed: " + err.Error(),
+		}
+	}
+	return &dto.FittingsStatusResponse{Module: "fittings", Status: "healthy"}
+}
+
+// convertKillmailItems turns killmail victim items into fitting items.
+// Destroyed and dropped quantities are merged per slot; container contents and
+// items in bays a fitting cannot hold are skipped.
+func convertKillmailItems(items []killmailModels.Item) []models.FittingItem {
+	type key struct {
+		typeID int32
+		flag   string
+	}
+	quantities := make(map[key]int32)
+	var order []key
+
+	for _, item := range items {
+		flag, ok := fittingFlagName(item.Flag)
+		if !ok {
+			continue
+		}
+
+		quantity := int64(0)
+		if item.QuantityDestroyed != nil {
+			quantity += *item.QuantityDestroyed
+		}
+		if item.QuantityDropped != nil {
+			quantity += *item.QuantityDropped
+		}
+		if quantity == 0 {
+			quantity = 1
+		}
+
+		k := key{typeID: int32(item.ItemTypeID), flag: flag}
+		if _, seen := quantities[k]; !seen {
+			order = append(order, k)
+		}
+		quantities[k] += int32(quantity)
+	}
+
+	result := make([]models.FittingItem, 0, len(order))
+	for _, k := range order {
+		result = append(result, models.FittingItem{TypeID: k.typeID, Flag: k.flag, Quantity: quantities[k]})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Flag < result[j].Flag })
+	return result
+}
+
+// fittingFlagName maps an inventory flag ID to the ESI fitting flag name
+func fittingFlagName(flag int64) (string, bool) {
+	switch {
+	case flag >= 11 && flag <= 18:
+		return fmt.Sprintf("LoSlot%d", flag-11), true
+	case flag >= 19 && flag <= 26:
+		return fmt.Sprintf("MedSlot%d", flag-19), true
+	case flag >= 27 && flag <= 34:
+		return fmt.Sprintf("HiSlot%d", flag-27), true
+	case flag >= 92 && flag <= 99:
+		return fmt.Sprintf("RigSlot%d", flag-92), true
+	case flag >= 125 && flag <= 132:
+		return fmt.Sprintf("SubSystemSlot%d", flag-125), true
+	case flag >= 164 && flag <= 171:
+		return fmt.Sprintf("ServiceSlot%d", flag-164), true
+	case flag == 5:
+		return "Cargo", true
+	case flag == 87:
+		return "DroneBay", true
+	case flag == 158:
+		return "FighterBay", true
+	}
+	return "", false
+}
@@ -1,5 +1,9 @@
 package dto
 
+import (
+	"time"
+)
+
 // CreateGroupInput represents the input for creating a new group
 type CreateGroupInput struct {
 	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
@@ -8,6 +12,7 @@ type CreateGroupInput struct {
 		Name        string `json:"name" minLength:"3" maxLength:"100" required:"true" description:"Group name"`
 		Description string `json:"description" maxLength:"500" description:"Group description"`
 		Type        string `json:"type" enum:"custom" required:"true" description:"Group type (only 'custom' allowed for manual creation)"`
+		Joinable    bool   `json:"joinable,omitempty" description:"Whether users may apply to join the group"`
 	} `json:"body"`
 }
 
@@ -20,6 +25,8 @@ type UpdateGroupInput struct {
 		Name        *string `json:"name" minLength:"3" maxLength:"100" description:"Group name"`
 		Description *string `json:"description" maxLength:"500" description:"Group description"`
 		IsActive    *bool   `json:"is_active" description:"Whether the group is active"`
+		ParentID    *string `json:"parent_id" description:"Parent group whose permissions the members inherit; empty string to detach"`
+		Joinable    *bool   `json:"joinable" description:"Whether users may apply to join the group (custom groups only)"`
 	} `json:"body"`
 }
 
@@ -34,7 +41,7 @@ type GetGroupInput struct {
 type ListGroupsInput struct {
 	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
 	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
-	Type          string `query:"type" enum:"system,corporation,alliance,custom" description:"Filter by group type"`
+	Type          string `query:"type" enum:"system,corporation,alliance,custom,standings" description:"Filter by group type"`
 	Page          int    `query:"page" minimum:"1" default:"1" description:"Page number"`
 	Limit         int    `query:"limit" minimum:"1" maximum:"100" default:"20" description:"Items per page"`
 }
@@ -45,7 +52,8 @@ type AddMemberInput struct {
 	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
 	GroupID       string `path:"group_id" required:"true" description:"Group ID"`
 	Body          struct {
-		CharacterID int64 `json:"character_id" required:"true" description:"Character ID to add to the group"`
+		CharacterID int64      `json:"character_id" required:"true" description:"Character ID to add to the group"`
+		ExpiresAt   *time.Time `json:"expires_at,omitempty" description:"Remove the membership automatically at this time (RFC 3339); the member and whoever added them are notified beforehand"`
 	} `json:"body"`
 }
 
@@ -79,7 +87,7 @@ type GetCharacterGroupsInput struct {
 	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
 	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
 	CharacterID   string `path:"character_id" required:"true" description:"Character ID"`
-	Type          string `query:"type" enum:"system,corporation,alliance,custom" description:"Filter by group type"`
+	Type          string `query:"type" enum:"system,corporation,alliance,custom,standings" description:"Filter by group type"`
 }
 
 // DeleteGroupInput represents the input for deleting a group
@@ -93,7 +101,7 @@ type DeleteGroupInput struct {
 type GetMyGroupsInput struct {
 	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
 	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
-	Type          string `query:"type" enum:"system,corporation,alliance,custom" description:"Filter by group type"`
+	Type          string `query:"type" enum:"system,corporation,alliance,custom,standings" description:"Filter by group type"`
 }
 
 // GetUserGroupsInput represents the input for getting groups by user_id
@@ -101,7 +109,7 @@ type GetUserGroupsInput struct {
 	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
 	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
 	UserID        string `path:"user_id" required:"true" description:"User ID"`
-	Type          string `query:"type" enum:"system,corporation,alliance,custom" description:"Filter by group type"`
+	Type          string `query:"type" enum:"system,corporation,alliance,custom,standings" description:"Filter by group type"`
 }
 
 // ListPermissionsInput represents the input for listing all permissions
@@ -126,10 +134,18 @@ type GrantPermissionToGroupInput struct {
 	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
 	GroupID       string `path:"group_id" required:"true" description:"Group ID"`
 	Body          struct {
-		PermissionID string `json:"permission_id" required:"true" minLength:"3" description:"Permission ID to grant"`
+		PermissionID string                `json:"permission_id" required:"true" minLength:"3" description:"Permission ID to grant"`
+		Conditions   []PermissionCondition `json:"conditions,omitempty" maxItems:"10" description:"Conditions that must all hold at check time for the grant to apply; granting again replaces them"`
 	} `json:"body"`
 }
 
+// PermissionCondition restricts a grant by an attribute of the character or the time of the check
+type PermissionCondition struct {
+	Attribute string    `json:"attribute" enum:"corporation_id,alliance_id,security_status,hour_utc,weekday_utc" required:"true" description:"Character attribute, or hour (0-23) or weekday (0 is Sunday) in UTC"`
+	Operator  string    `json:"operator" enum:"eq,ne,gt,gte,lt,lte,in,not_in" required:"true" description:"Comparison; in and not_in take a list of values, the others one value"`
+	Values    []float64 `json:"values" minItems:"1" maxItems:"100" required:"true" description:"Values to compare with, e.g. [98000001] or [18, 19, 20, 21]"`
+}
+
 // RevokePermissionFromGroupInput represents the input for revoking a permission from a group
 type RevokePermissionFromGroupInput struct {
 	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
@@ -164,3 +180,398 @@ type UpdateGroupPermissionStatusInput struct {
 		IsActive bool `json:"is_active" required:"true" description:"Set permission active/inactive status"`
 	} `json:"body"`
 }
+
+// ImportStandingsContactsInput represents the input for importing an alliance contact list from ESI
+type ImportStandingsContactsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	AllianceID    int64  `path:"alliance_id" required:"true" description:"Alliance whose contact list is imported"`
+}
+
+// DeleteStandingsContactsInput represents the input for removing an alliance contact list
+type DeleteStandingsContactsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	AllianceID    int64  `path:"alliance_id" required:"true" description:"Alliance the contact list belongs to"`
+}
+
+// GetStandingsStatusInput represents the input for getting standings group status
+type GetStandingsStatusInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+}
+
+// ReconcileStandingsInput represents the input for reconciling standings groups
+type ReconcileStandingsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	DryRun        bool   `query:"dry_run" default:"false" description:"Only report drift without changing memberships"`
+}
+
+// ListMembershipRulesInput represents the input for listing membership rules
+type ListMembershipRulesInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	GroupID       string `query:"group_id" description:"Only list the rules of one group"`
+}
+
+// MembershipRuleBody holds the conditions of a membership rule
+type MembershipRuleBody struct {
+	GroupID        string   `json:"group_id" required:"true" description:"Custom group the matching characters join"`
+	Name           string   `json:"name" minLength:"3" maxLength:"100" required:"true" description:"Rule name"`
+	CorporationIDs []int64  `json:"corporation_ids,omitempty" maxItems:"100" description:"Match characters in any of these corporations"`
+	AllianceIDs    []int64  `json:"alliance_ids,omitempty" maxItems:"100" description:"Match characters in any of these alliances"`
+	Roles          []string `json:"roles,omitempty" maxItems:"50" description:"Match characters holding any of these corporation roles as named by ESI (e.g. 'Director'); needs the esi-characters.read_corporation_roles.v1 scope"`
+	IsActive       bool     `json:"is_active" default:"true" description:"Whether the rule is evaluated"`
+}
+
+// CreateMembershipRuleInput represents the input for creating a membership rule
+type CreateMembershipRuleInput struct {
+	Authorization string             `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string             `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Body          MembershipRuleBody `json:"body"`
+}
+
+// UpdateMembershipRuleInput represents the input for replacing a membership rule
+type UpdateMembershipRuleInput struct {
+	Authorization string             `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string             `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	RuleID        string             `path:"rule_id" required:"true" description:"Membership rule ID"`
+	Body          MembershipRuleBody `json:"body"`
+}
+
+// DeleteMembershipRuleInput represents the input for deleting a membership rule
+type DeleteMembershipRuleInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	RuleID        string `path:"rule_id" required:"true" description:"Membership rule ID"`
+}
+
+// ReconcileMembershipRulesInput represents the input for evaluating the membership rules
+type ReconcileMembershipRulesInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	DryRun        bool   `query:"dry_run" default:"false" description:"Only report drift without changing memberships"`
+}
+
+// ListCorporationMappingsInput represents the input for listing corporation mappings
+type ListCorporationMappingsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	CorporationID int64  `query:"corporation_id" description:"Only list the mappings of one corporation"`
+	GroupID       string `query:"group_id" description:"Only list the mappings of one group"`
+}
+
+// CorporationMappingBody maps an in-game role or title of a corporation to a group
+type CorporationMappingBody struct {
+	CorporationID int64  `json:"corporation_id" required:"true" description:"Corporation whose members are mapped"`
+	Kind          string `json:"kind" enum:"role,title" required:"true" description:"Whether members are matched by corporation role or title"`
+	Role          string `json:"role,omitempty" maxLength:"100" description:"Corporation role as named by ESI (e.g. 'Director'), for role mappings"`
+	TitleID       int    `json:"title_id,omitempty" minimum:"0" description:"Corporation title ID, for title mappings"`
+	GroupID       string `json:"group_id" required:"true" description:"Custom group the members holding the role or title join"`
+	IsActive      bool   `json:"is_active" default:"true" description:"Whether the mapping is synced"`
+}
+
+// CreateCorporationMappingInput represents the input for creating a corporation mapping
+type CreateCorporationMappingInput struct {
+	Authorization string                 `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string                 `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Body          CorporationMappingBody `json:"body"`
+}
+
+// UpdateCorporationMappingInput represents the input for enabling or disabling a corporation mapping
+type UpdateCorporationMappingInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	MappingID     string `path:"mapping_id" required:"true" description:"Corporation mapping ID"`
+	Body          struct {
+		IsActive bool `json:"is_active" description:"Whether the mapping is synced"`
+	} `json:"body"`
+}
+
+// DeleteCorporationMappingInput represents the input for deleting a corporation mapping
+type DeleteCorporationMappingInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	MappingID     string `path:"mapping_id" required:"true" description:"Corporation mapping ID"`
+}
+
+// ReconcileCorporationMappingsInput represents the input for syncing the corporation mappings
+type ReconcileCorporationMappingsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	DryRun        bool   `query:"dry_run" default:"false" description:"Only report drift without changing memberships"`
+}
+
+// RevalidateMembershipsInput represents the input for revalidating corporation and alliance memberships
+type RevalidateMembershipsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Action        string `query:"action" enum:"flag,remove" default:"flag" description:"Flag mismatched members or remove them"`
+	DryRun        bool   `query:"dry_run" default:"false" description:"Only report mismatches without changing memberships"`
+}
+
+// ListWebhooksInput represents the input for listing group webhooks
+type ListWebhooksInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+}
+
+// WebhookBody configures a group webhook
+type WebhookBody struct {
+	Name     string   `json:"name" minLength:"3" maxLength:"100" required:"true" description:"Webhook name"`
+	URL      string   `json:"url" maxLength:"2048" required:"true" description:"HTTP(S) endpoint the events are POSTed to"`
+	Secret   string   `json:"secret,omitempty" maxLength:"256" description:"HMAC-SHA256 signing secret of at least 16 characters; generated on creation and kept on update when empty"`
+	Events   []string `json:"events,omitempty" maxItems:"10" enum:"member.added,member.removed,permission.granted,permission.revoked" description:"Events to deliver, all if empty"`
+	GroupIDs []string `json:"group_ids,omitempty" maxItems:"100" description:"Groups to deliver events of, all if empty"`
+	IsActive bool     `json:"is_active" default:"true" description:"Whether events are delivered"`
+}
+
+// CreateWebhookInput represents the input for creating a group webhook
+type CreateWebhookInput struct {
+	Authorization string      `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string      `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Body          WebhookBody `json:"body"`
+}
+
+// UpdateWebhookInput represents the input for replacing a group webhook
+type UpdateWebhookInput struct {
+	Authorization string      `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string      `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	WebhookID     string      `path:"webhook_id" required:"true" description:"Webhook ID"`
+	Body          WebhookBody `json:"body"`
+}
+
+// WebhookIDInput represents the input for deleting or testing a group webhook
+type WebhookIDInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	WebhookID     string `path:"webhook_id" required:"true" description:"Webhook ID"`
+}
+
+// ListGroupTemplatesInput represents the input for listing group templates
+type ListGroupTemplatesInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+}
+
+// TemplateGroup describes one group of a group template
+type TemplateGroup struct {
+	Key            string   `json:"key" minLength:"1" maxLength:"50" required:"true" description:"Identifier of the group within the template"`
+	ParentKey      string   `json:"parent_key,omitempty" maxLength:"50" description:"Key of an earlier group of the template to inherit permissions from"`
+	Name           string   `json:"name" minLength:"3" maxLength:"100" required:"true" description:"Group name; {ticker}, {name} and {corporation_id} are replaced with the corporation's"`
+	Description    string   `json:"description,omitempty" maxLength:"500" description:"Group description; accepts the same placeholders as the name"`
+	Joinable       bool     `json:"joinable" description:"Whether users may apply to join the group"`
+	Permissions    []string `json:"permissions,omitempty" maxItems:"100" description:"Permission IDs granted to the group"`
+	AutoMembership bool     `json:"auto_membership" description:"Add a membership rule that keeps the corporation's members in the group"`
+	MemberRoles    []string `json:"member_roles,omitempty" maxItems:"50" description:"Narrow the membership rule to members holding any of these corporation roles as named by ESI"`
+}
+
+// GroupTemplateBody configures a group template
+type GroupTemplateBody struct {
+	Name        string          `json:"name" minLength:"3" maxLength:"100" required:"true" description:"Template name"`
+	Description string          `json:"description,omitempty" maxLength:"500" description:"Template description"`
+	Groups      []TemplateGroup `json:"groups" minItems:"1" maxItems:"50" required:"true" description:"Groups created by the template, parents first"`
+}
+
+// CreateGroupTemplateInput represents the input for creating a group template
+type CreateGroupTemplateInput struct {
+	Authorization string            `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string            `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Body          GroupTemplateBody `json:"body"`
+}
+
+// UpdateGroupTemplateInput represents the input for replacing a group template
+type UpdateGroupTemplateInput struct {
+	Authorization string            `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string            `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	TemplateID    string            `path:"template_id" required:"true" description:"Group template ID"`
+	Body          GroupTemplateBody `json:"body"`
+}
+
+// DeleteGroupTemplateInput represents the input for deleting a group template
+type DeleteGroupTemplateInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	TemplateID    string `path:"template_id" required:"true" description:"Group template ID"`
+}
+
+// InstantiateGroupTemplateInput represents the input for creating the groups of a template for a corporation
+type InstantiateGroupTemplateInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	TemplateID    string `path:"template_id" required:"true" description:"Group template ID"`
+	Body          struct {
+		CorporationID int64 `json:"corporation_id" required:"true" description:"Enabled managed corporation the groups are created for"`
+		DryRun        bool  `json:"dry_run" default:"false" description:"Only resolve the group names without creating anything"`
+	} `json:"body"`
+}
+
+// ListSnapshotsInput represents the input for listing group snapshots
+type ListSnapshotsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Page          int    `query:"page" minimum:"1" default:"1" description:"Page number"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"100" default:"20" description:"Items per page"`
+}
+
+// CreateSnapshotInput represents the input for taking a manual group snapshot
+type CreateSnapshotInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+}
+
+// GetSnapshotAccessInput represents the input for querying who had access at a point in time
+type GetSnapshotAccessInput struct {
+	Authorization string    `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string    `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Permission    string    `query:"permission" description:"Permission ID to check (e.g. 'groups:management:full'); exactly one of permission or group_id is required"`
+	GroupID       string    `query:"group_id" description:"Group ID whose members to list; exactly one of permission or group_id is required"`
+	At            time.Time `query:"at" required:"true" description:"Point in time (RFC 3339); the latest snapshot taken at or before it is used"`
+}
+
+// DiffSnapshotsInput represents the input for diffing the snapshots at two points in time
+type DiffSnapshotsInput struct {
+	Authorization string    `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string    `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	From          time.Time `query:"from" required:"true" description:"Start of the range (RFC 3339)"`
+	To            time.Time `query:"to" required:"true" description:"End of the range (RFC 3339)"`
+	GroupID       string    `query:"group_id" description:"Limit the diff to one group"`
+}
+
+// ListJoinableGroupsInput represents the input for listing the groups users may apply to
+type ListJoinableGroupsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+}
+
+// CreateJoinRequestInput represents the input for applying to a group
+type CreateJoinRequestInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	GroupID       string `path:"group_id" required:"true" description:"Group ID"`
+	Body          struct {
+		CharacterID int64  `json:"character_id,omitempty" description:"Character of the caller's account that joins; defaults to the signed-in character"`
+		Message     string `json:"message,omitempty" maxLength:"1000" description:"Why the character should join"`
+	} `json:"body"`
+}
+
+// ListMyJoinRequestsInput represents the input for listing the caller's join requests
+type ListMyJoinRequestsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+}
+
+// ListJoinRequestsInput represents the input for listing join requests to review
+type ListJoinRequestsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	GroupID       string `query:"group_id" description:"Only list the requests of one group"`
+	Status        string `query:"status" enum:"pending,approved,rejected" default:"pending" description:"Filter by status"`
+	Page          int    `query:"page" minimum:"1" default:"1" description:"Page number"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"100" default:"20" description:"Items per page"`
+}
+
+// DecideJoinRequestInput represents the input for approving or rejecting a join request
+type DecideJoinRequestInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	RequestID     string `path:"request_id" required:"true" description:"Join request ID"`
+	Body          struct {
+		Reason string `json:"reason,omitempty" maxLength:"1000" description:"Reason for the decision, shown to the applicant; required when rejecting"`
+	} `json:"body"`
+}
+
+// PermissionChangeInput is one hypothetical change of a permission simulation
+type PermissionChangeInput struct {
+	Type         string `json:"type" enum:"grant,revoke,add_membership,remove_membership" required:"true" description:"Kind of change"`
+	GroupID      string `json:"group_id" required:"true" description:"Group the change applies to"`
+	PermissionID string `json:"permission_id,omitempty" description:"Permission granted or revoked (grant and revoke only)"`
+}
+
+// SimulatePermissionsInput represents the input for a permission what-if simulation
+type SimulatePermissionsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Body          struct {
+		CharacterID int64                   `json:"character_id" required:"true" description:"Character whose effective permissions are evaluated"`
+		Changes     []PermissionChangeInput `json:"changes" minItems:"1" maxItems:"50" required:"true" description:"Changes applied in order; membership changes apply to the character"`
+	} `json:"body"`
+}
+
+// ExplainPermissionInput represents the input for tracing a permission decision
+type ExplainPermissionInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	CharacterID   int64  `query:"character_id" required:"true" description:"Character whose access is explained"`
+	PermissionID  string `query:"permission" required:"true" description:"Permission ID to explain, e.g. groups:management:full"`
+}
+
+// ListPermissionDenialsInput represents the input for listing permission denials
+type ListPermissionDenialsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	PermissionID  string `query:"permission_id" description:"Only list denials of this permission"`
+}
+
+// CreatePermissionDenialInput represents the input for denying a permission
+type CreatePermissionDenialInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Body          struct {
+		PermissionID string `json:"permission_id" required:"true" description:"Permission to deny"`
+		SubjectType  string `json:"subject_type" enum:"character,corporation,alliance" required:"true" description:"Whether a single character or every character of a corporation or alliance is denied"`
+		SubjectID    int64  `json:"subject_id" required:"true" description:"Character, corporation or alliance ID"`
+		Reason       string `json:"reason,omitempty" maxLength:"500" description:"Why the permission is denied"`
+	} `json:"body"`
+}
+
+// DeletePermissionDenialInput represents the input for lifting a permission denial
+type DeletePermissionDenialInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	DenialID      string `path:"denial_id" required:"true" description:"Denial ID"`
+}
+
+// ExportAuditInput represents the input for exporting the groups audit log
+type ExportAuditInput struct {
+	Authorization  string    `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie         string    `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	Format         string    `query:"format" enum:"csv,jsonl" default:"jsonl" description:"Export format"`
+	From           time.Time `query:"from" description:"Only events at or after this time (RFC 3339)"`
+	To             time.Time `query:"to" description:"Only events before this time (RFC 3339)"`
+	ActorID        int64     `query:"actor_id" description:"Only events performed by this character"`
+	Service        string    `query:"service" description:"Only events involving permissions of this service; \"groups\" also covers group and membership changes"`
+	IncludeArchive bool      `query:"include_archive" default:"true" description:"Also export archived events, which come first"`
+}
+
+// SetGroupRateLimitInput represents the input for attaching a rate limit policy to a group
+type SetGroupRateLimitInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	GroupID       string `path:"group_id" required:"true" description:"Group ID"`
+	Body          struct {
+		RequestsPerMinute int `json:"requests_per_minute" minimum:"1" maximum:"100000" required:"true" description:"API requests per minute allowed to each user with a character in the group"`
+	} `json:"body"`
+}
+
+// RemoveGroupRateLimitInput represents the input for removing the rate limit policy of a group
+type RemoveGroupRateLimitInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	GroupID       string `path:"group_id" required:"true" description:"Group ID"`
+}
+
+// GroupManagerInput represents the input for adding or removing a group manager
+type GroupManagerInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+	GroupID       string `path:"group_id" required:"true" description:"Group ID"`
+	CharacterID   int64  `path:"character_id" required:"true" description:"Manager character ID"`
+}
+
+// ListManagedGroupsInput represents the input for listing the groups the caller manages
+type ListManagedGroupsInput struct {
+	Authorization string `header:"Authorization" description:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" description:"Cookie header containing falcon_auth_token"`
+}
@@ -17,6 +17,10 @@ type GroupResponse struct {
 	Type        string    `json:"type" description:"Group type"`
 	SystemName  *string   `json:"system_name,omitempty" description:"System group identifier"`
 	EVEEntityID *int64    `json:"eve_entity_id,omitempty" description:"EVE Corporation/Alliance ID"`
+	ParentID    *string   `json:"parent_id,omitempty" description:"Parent group whose permissions members inherit"`
+	Joinable    bool      `json:"joinable" description:"Whether users may apply to join the group"`
+	Managers    []int64   `json:"managers,omitempty" description:"Characters who may add and remove members of this group"`
+	RateLimit   int       `json:"rate_limit,omitempty" description:"API requests per minute allowed to each user in the group"`
 	IsActive    bool      `json:"is_active" description:"Whether the group is active"`
 	MemberCount *int64    `json:"member_count,omitempty" description:"Number of active members"`
 	CreatedBy   *int64    `json:"created_by,omitempty" description:"Character ID who created this group"`
@@ -31,14 +35,18 @@ type GroupMembershipOutput struct {
 
 // GroupMembershipResponse represents the actual membership data
 type GroupMembershipResponse struct {
-	ID            string    `json:"id" description:"Membership ID"`
-	GroupID       string    `json:"group_id" description:"Group ID"`
-	CharacterID   int64     `json:"character_id" description:"Character ID"`
-	CharacterName string    `json:"character_name" description:"Character name"`
-	IsActive      bool      `json:"is_active" description:"Whether the membership is active"`
-	AddedBy       *int64    `json:"added_by,omitempty" description:"Character ID who added this membership"`
-	AddedAt       time.Time `json:"added_at" description:"When the membership was added"`
-	UpdatedAt     time.Time `json:"updated_at" description:"Last update timestamp"`
+	ID            string     `json:"id" description:"Membership ID"`
+	GroupID       string     `json:"group_id" description:"Group ID"`
+	CharacterID   int64      `json:"character_id" description:"Character ID"`
+	CharacterName string     `json:"character_name" description:"Character name"`
+	IsActive      bool       `json:"is_active" description:"Whether the membership is active"`
+	AddedBy       *int64     `json:"added_by,omitempty" description:"Character ID who added this membership"`
+	Source        string     `json:"source,omitempty" description:"'rule' when a membership rule added the character"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" description:"When the membership expires"`
+	FlaggedAt     *time.Time `json:"flagged_at,omitempty" description:"When revalidation found the character no longer satisfies the group"`
+	FlagReason    string     `json:"flag_reason,omitempty" description:"Why the membership is flagged"`
+	AddedAt       time.Time  `json:"added_at" description:"When the membership was added"`
+	UpdatedAt     time.Time  `json:"updated_at" description:"Last update timestamp"`
 }
 
 // ListGroupsOutput represents the response for listing groups
@@ -179,15 +187,16 @@ type GroupPermissionOutput struct {
 
 // GroupPermissionResponse represents the actual group permission data
 type GroupPermissionResponse struct {
-	ID           string             `json:"id" description:"Assignment ID"`
-	GroupID      string             `json:"group_id" description:"Group ID"`
-	GroupName    string             `json:"group_name" description:"Group name"`
-	PermissionID string             `json:"permission_id" description:"Permission ID"`
-	Permission   PermissionResponse `json:"permission" description:"Permission details"`
-	GrantedBy    *int64             `json:"granted_by,omitempty" description:"Character ID who granted the permission"`
-	GrantedAt    time.Time          `json:"granted_at" description:"When permission was granted"`
-	IsActive     bool               `json:"is_active" description:"Whether the assignment is active"`
-	UpdatedAt    time.Time          `json:"updated_at" description:"Last update timestamp"`
+	ID           string                `json:"id" description:"Assignment ID"`
+	GroupID      string                `json:"group_id" description:"Group ID"`
+	GroupName    string                `json:"group_name" description:"Group name"`
+	PermissionID string                `json:"permission_id" description:"Permission ID"`
+	Permission   PermissionResponse    `json:"permission" description:"Permission details"`
+	GrantedBy    *int64                `json:"granted_by,omitempty" description:"Character ID who granted the permission"`
+	GrantedAt    time.Time             `json:"granted_at" description:"When permission was granted"`
+	IsActive     bool                  `json:"is_active" description:"Whether the assignment is active"`
+	UpdatedAt    time.Time             `json:"updated_at" description:"Last update timestamp"`
+	Conditions   []PermissionCondition `json:"conditions,omitempty" description:"Conditions that must all hold for the grant to apply"`
 }
 
 // ListGroupPermissionsOutput represents the response for listing group permissions
@@ -210,10 +219,12 @@ type PermissionCheckOutput struct {
 
 // PermissionCheckResponse represents the actual permission check data
 type PermissionCheckResponse struct {
-	CharacterID  int64  `json:"character_id" description:"Character ID that was checked"`
-	PermissionID string `json:"permission_id" description:"Permission ID that was checked"`
-	Granted      bool   `json:"granted" description:"Whether permission is granted"`
-	GrantedVia   string `json:"granted_via,omitempty" description:"Which group granted the permission"`
+	CharacterID     int64    `json:"character_id" description:"Character ID that was checked"`
+	PermissionID    string   `json:"permission_id" description:"Permission ID that was checked"`
+	Granted         bool     `json:"granted" description:"Whether permission is granted"`
+	GrantedVia      string   `json:"granted_via,omitempty" description:"Which group granted the permission"`
+	DeniedBy        string   `json:"denied_by,omitempty" description:"Denial that withholds the permission despite any grant, e.g. \"corporation 98000001\""`
+	UnmetConditions []string `json:"unmet_conditions,omitempty" description:"Conditions of matching grants that did not hold, e.g. \"Night Shift: hour_utc in [0, 1, 2]\""`
 }
 
 // MessageOutput represents a simple message response
@@ -225,3 +236,477 @@ type MessageOutput struct {
 type MessageResponse struct {
 	Message string `json:"message" description:"Response message"`
 }
+
+// StandingsContactListResponse summarizes an imported alliance contact list
+type StandingsContactListResponse struct {
+	AllianceID int64     `json:"alliance_id" description:"Alliance the contact list belongs to"`
+	Contacts   int       `json:"contacts" description:"Number of contacts in the list"`
+	ImportedBy int64     `json:"imported_by" description:"Character ID that imported the list"`
+	ImportedAt time.Time `json:"imported_at" description:"When the list was imported"`
+}
+
+// StandingsContactListOutput represents the response for importing a contact list
+type StandingsContactListOutput struct {
+	Body StandingsContactListResponse `json:"body"`
+}
+
+// StandingsTierResponse describes a standings tier group
+type StandingsTierResponse struct {
+	GroupID     string `json:"group_id,omitempty" description:"Group ID, empty until the first reconciliation"`
+	GroupName   string `json:"group_name" description:"Group name"`
+	Description string `json:"description" description:"Which standings the tier covers"`
+	MemberCount int64  `json:"member_count" description:"Current number of members"`
+}
+
+// StandingsTierDriftResponse describes drift of one tier group
+type StandingsTierDriftResponse struct {
+	GroupName  string  `json:"group_name" description:"Group name"`
+	Expected   int     `json:"expected" description:"Characters that should be in the group"`
+	Missing    []int64 `json:"missing" description:"Characters with the standing that were not in the group"`
+	Unexpected []int64 `json:"unexpected" description:"Group members that no longer have the standing"`
+}
+
+// StandingsSyncReportResponse represents a standings reconciliation report
+type StandingsSyncReportResponse struct {
+	ID                  string                       `json:"id" description:"Report ID"`
+	DryRun              bool                         `json:"dry_run" description:"Whether memberships were left unchanged"`
+	TriggeredBy         *int64                       `json:"triggered_by,omitempty" description:"Character ID that started the run, empty for scheduled runs"`
+	ContactLists        int                          `json:"contact_lists" description:"Number of imported contact lists used"`
+	CharactersEvaluated int                          `json:"characters_evaluated" description:"Number of registered characters evaluated"`
+	Tiers               []StandingsTierDriftResponse `json:"tiers" description:"Drift per tier group"`
+	Added               int                          `json:"added" description:"Memberships added"`
+	Removed             int                          `json:"removed" description:"Memberships removed"`
+	StartedAt           time.Time                    `json:"started_at" description:"When the run started"`
+	CompletedAt         time.Time                    `json:"completed_at" description:"When the run completed"`
+}
+
+// StandingsSyncReportOutput represents the response for a standings reconciliation
+type StandingsSyncReportOutput struct {
+	Body StandingsSyncReportResponse `json:"body"`
+}
+
+// StandingsStatusResponse represents the state of the standings groups
+type StandingsStatusResponse struct {
+	ContactLists []StandingsContactListResponse `json:"contact_lists" description:"Imported alliance contact lists"`
+	Tiers        []StandingsTierResponse        `json:"tiers" description:"Standings tier groups"`
+	LastReport   *StandingsSyncReportResponse   `json:"last_report,omitempty" description:"Most recent reconciliation report"`
+}
+
+// StandingsStatusOutput represents the response for getting standings group status
+type StandingsStatusOutput struct {
+	Body StandingsStatusResponse `json:"body"`
+}
+
+// MembershipRuleResponse represents a membership rule
+type MembershipRuleResponse struct {
+	ID             string    `json:"id" description:"Rule ID"`
+	GroupID        string    `json:"group_id" description:"Group the matching characters join"`
+	GroupName      string    `json:"group_name" description:"Group name"`
+	Name           string    `json:"name" description:"Rule name"`
+	CorporationIDs []int64   `json:"corporation_ids" description:"Corporations that match, any if empty"`
+	AllianceIDs    []int64   `json:"alliance_ids" description:"Alliances that match, any if empty"`
+	Roles          []string  `json:"roles" description:"Corporation roles that match, any if empty"`
+	IsActive       bool      `json:"is_active" description:"Whether the rule is evaluated"`
+	CreatedBy      int64     `json:"created_by" description:"Character ID that created the rule"`
+	CreatedAt      time.Time `json:"created_at" description:"When the rule was created"`
+	UpdatedAt      time.Time `json:"updated_at" description:"Last update timestamp"`
+}
+
+// MembershipRuleOutput represents the response for creating or updating a membership rule
+type MembershipRuleOutput struct {
+	Body MembershipRuleResponse `json:"body"`
+}
+
+// ListMembershipRulesOutput represents the response for listing membership rules
+type ListMembershipRulesOutput struct {
+	Body struct {
+		Rules []MembershipRuleResponse `json:"rules" description:"Membership rules"`
+	} `json:"body"`
+}
+
+// MembershipRuleDriftResponse describes how far one group is from its membership rules or corporation mappings
+type MembershipRuleDriftResponse struct {
+	GroupID    string  `json:"group_id" description:"Group ID"`
+	GroupName  string  `json:"group_name" description:"Group name"`
+	Expected   int     `json:"expected" description:"Characters matching a rule or mapping of the group"`
+	Missing    []int64 `json:"missing" description:"Matching characters that were not in the group"`
+	Unexpected []int64 `json:"unexpected" description:"Members added by a rule or mapping that no longer match"`
+}
+
+// MembershipRulesReportResponse represents one evaluation of the membership rules
+type MembershipRulesReportResponse struct {
+	DryRun              bool                          `json:"dry_run" description:"Whether memberships were left unchanged"`
+	Rules               int                           `json:"rules" description:"Number of active rules evaluated"`
+	CharactersEvaluated int                           `json:"characters_evaluated" description:"Number of registered characters evaluated"`
+	RoleLookupsFailed   int                           `json:"role_lookups_failed" description:"Characters whose ESI roles could not be read; their rule memberships are kept"`
+	Groups              []MembershipRuleDriftResponse `json:"groups" description:"Drift per group"`
+	Added               int                           `json:"added" description:"Memberships added"`
+	Removed             int                           `json:"removed" description:"Memberships removed"`
+	StartedAt           time.Time                     `json:"started_at" description:"When the run started"`
+	CompletedAt         time.Time                     `json:"completed_at" description:"When the run completed"`
+}
+
+// MembershipRulesReportOutput represents the response for evaluating the membership rules
+type MembershipRulesReportOutput struct {
+	Body MembershipRulesReportResponse `json:"body"`
+}
+
+// CorporationMappingResponse represents a corporation mapping
+type CorporationMappingResponse struct {
+	ID            string    `json:"id" description:"Mapping ID"`
+	CorporationID int64     `json:"corporation_id" description:"Corporation whose members are mapped"`
+	Kind          string    `json:"kind" description:"role or title"`
+	Role          string    `json:"role,omitempty" description:"Corporation role of role mappings"`
+	TitleID       int       `json:"title_id,omitempty" description:"Title ID of title mappings"`
+	GroupID       string    `json:"group_id" description:"Group the matching members join"`
+	GroupName     string    `json:"group_name" description:"Group name"`
+	IsActive      bool      `json:"is_active" description:"Whether the mapping is synced"`
+	CreatedBy     int64     `json:"created_by" description:"Character ID that created the mapping"`
+	CreatedAt     time.Time `json:"created_at" description:"When the mapping was created"`
+	UpdatedAt     time.Time `json:"updated_at" description:"Last update timestamp"`
+}
+
+// CorporationMappingOutput represents the response for creating or updating a corporation mapping
+type CorporationMappingOutput struct {
+	Body CorporationMappingResponse `json:"body"`
+}
+
+// ListCorporationMappingsOutput represents the response for listing corporation mappings
+type ListCorporationMappingsOutput struct {
+	Body struct {
+		Mappings []CorporationMappingResponse `json:"mappings" description:"Corporation mappings"`
+	} `json:"body"`
+}
+
+// CorporationMappingsReportResponse represents one sync of the corporation mappings
+type CorporationMappingsReportResponse struct {
+	DryRun             bool                          `json:"dry_run" description:"Whether memberships were left unchanged"`
+	Mappings           int                           `json:"mappings" description:"Number of active mappings synced"`
+	Corporations       int                           `json:"corporations" description:"Number of corporations read from ESI"`
+	CorporationsFailed []int64                       `json:"corporations_failed" description:"Corporations whose roles or titles could not be read; their mapped members are kept"`
+	Groups             []MembershipRuleDriftResponse `json:"groups" description:"Drift per group"`
+	Added              int                           `json:"added" description:"Memberships added"`
+	Removed            int                           `json:"removed" description:"Memberships removed"`
+	StartedAt          time.Time                     `json:"started_at" description:"When the run started"`
+	CompletedAt        time.Time                     `json:"completed_at" description:"When the run completed"`
+}
+
+// RevalidationMismatch is a member of a corporation or alliance group whose character is no longer
+// in the group's entity
+type RevalidationMismatch struct {
+	GroupID       string `json:"group_id" description:"Group ID"`
+	GroupName     string `json:"group_name" description:"Group name"`
+	CharacterID   int64  `json:"character_id" description:"Member character ID"`
+	CorporationID int64  `json:"corporation_id" description:"Current corporation of the character"`
+	AllianceID    int64  `json:"alliance_id,omitempty" description:"Current alliance of the character"`
+}
+
+// MembershipRevalidationReportResponse represents one revalidation of the corporation and alliance
+// group memberships against ESI
+type MembershipRevalidationReportResponse struct {
+	DryRun               bool                   `json:"dry_run" description:"Whether memberships were left unchanged"`
+	Action               string                 `json:"action" description:"What happens to mismatched members: flag or remove"`
+	Groups               int                    `json:"groups" description:"Corporation and alliance groups checked"`
+	CharactersChecked    int                    `json:"characters_checked" description:"Member characters whose affiliation was read"`
+	CharactersUnresolved int                    `json:"characters_unresolved" description:"Member characters ESI returned no affiliation for; their memberships are kept"`
+	Mismatches           []RevalidationMismatch `json:"mismatches" description:"Members no longer in the group's corporation or alliance"`
+	Flagged              int                    `json:"flagged" description:"Memberships newly flagged"`
+	Removed              int                    `json:"removed" description:"Memberships removed"`
+	Cleared              int                    `json:"cleared" description:"Flags cleared from members that satisfy their group again"`
+	StartedAt            time.Time              `json:"started_at" description:"When the run started"`
+	CompletedAt          time.Time              `json:"completed_at" description:"When the run completed"`
+}
+
+// MembershipRevalidationReportOutput represents the response for revalidating memberships
+type MembershipRevalidationReportOutput struct {
+	Body MembershipRevalidationReportResponse `json:"body"`
+}
+
+// CorporationMappingsReportOutput represents the response for syncing the corporation mappings
+type CorporationMappingsReportOutput struct {
+	Body CorporationMappingsReportResponse `json:"body"`
+}
+
+// WebhookResponse represents a group webhook; the secret is only returned on creation
+type WebhookResponse struct {
+	ID                  string     `json:"id" description:"Webhook ID"`
+	Name                string     `json:"name" description:"Webhook name"`
+	URL                 string     `json:"url" description:"Endpoint the events are POSTed to"`
+	Secret              string     `json:"secret,omitempty" description:"Signing secret, only returned when the webhook is created"`
+	Events              []string   `json:"events" description:"Events delivered, all if empty"`
+	GroupIDs            []string   `json:"group_ids" description:"Groups whose events are delivered, all if empty"`
+	IsActive            bool       `json:"is_active" description:"Whether events are delivered"`
+	LastDeliveryAt      *time.Time `json:"last_delivery_at,omitempty" description:"When the latest delivery was attempted"`
+	LastStatusCode      int        `json:"last_status_code,omitempty" description:"HTTP status of the latest delivery"`
+	LastError           string     `json:"last_error,omitempty" description:"Error of the latest delivery, if it failed"`
+	ConsecutiveFailures int        `json:"consecutive_failures" description:"Failed deliveries since the last successful one"`
+	CreatedBy           int64      `json:"created_by" description:"Character ID that created the webhook"`
+	CreatedAt           time.Time  `json:"created_at" description:"When the webhook was created"`
+	UpdatedAt           time.Time  `json:"updated_at" description:"Last update timestamp"`
+}
+
+// WebhookOutput represents the response for creating, updating or testing a group webhook
+type WebhookOutput struct {
+	Body WebhookResponse `json:"body"`
+}
+
+// ListWebhooksOutput represents the response for listing group webhooks
+type ListWebhooksOutput struct {
+	Body struct {
+		Webhooks []WebhookResponse `json:"webhooks" description:"Group webhooks"`
+	} `json:"body"`
+}
+
+// GroupTemplateResponse represents a group template
+type GroupTemplateResponse struct {
+	ID          string          `json:"id" description:"Group template ID"`
+	Name        string          `json:"name" description:"Template name"`
+	Description string          `json:"description" description:"Template description"`
+	Groups      []TemplateGroup `json:"groups" description:"Groups created by the template, parents first"`
+	CreatedBy   int64           `json:"created_by" description:"Character ID that created the template"`
+	CreatedAt   time.Time       `json:"created_at" description:"When the template was created"`
+	UpdatedAt   time.Time       `json:"updated_at" description:"Last update timestamp"`
+}
+
+// GroupTemplateOutput represents the response for creating or updating a group template
+type GroupTemplateOutput struct {
+	Body GroupTemplateResponse `json:"body"`
+}
+
+// ListGroupTemplatesOutput represents the response for listing group templates
+type ListGroupTemplatesOutput struct {
+	Body struct {
+		Templates []GroupTemplateResponse `json:"templates" description:"Group templates"`
+	} `json:"body"`
+}
+
+// TemplateInstanceGroup is a group created, or planned in a dry run, from a template
+type TemplateInstanceGroup struct {
+	Key         string   `json:"key" description:"Key of the group within the template"`
+	Name        string   `json:"name" description:"Resolved group name"`
+	GroupID     string   `json:"group_id,omitempty" description:"ID of the created group"`
+	Permissions []string `json:"permissions" description:"Permissions granted, or to grant in a dry run"`
+	RuleID      string   `json:"rule_id,omitempty" description:"ID of the membership rule created for the corporation's members"`
+}
+
+// TemplateInstanceResponse reports the groups created from a template for a corporation
+type TemplateInstanceResponse struct {
+	TemplateID    string                  `json:"template_id" description:"Group template ID"`
+	TemplateName  string                  `json:"template_name" description:"Template name"`
+	CorporationID int64                   `json:"corporation_id" description:"Corporation the groups were created for"`
+	DryRun        bool                    `json:"dry_run" description:"Whether nothing was created"`
+	Groups        []TemplateInstanceGroup `json:"groups" description:"Groups in template order"`
+	Errors        []string                `json:"errors" description:"Groups, grants and rules that could not be created"`
+}
+
+// TemplateInstanceOutput represents the response for instantiating a group template
+type TemplateInstanceOutput struct {
+	Body TemplateInstanceResponse `json:"body"`
+}
+
+// SnapshotResponse represents a group snapshot summary
+type SnapshotResponse struct {
+	ID          string    `json:"id" description:"Snapshot ID"`
+	TakenAt     time.Time `json:"taken_at" description:"When the snapshot was taken"`
+	Trigger     string    `json:"trigger" description:"What took the snapshot (scheduled or manual)"`
+	TakenBy     *int64    `json:"taken_by,omitempty" description:"Character ID that took a manual snapshot"`
+	Groups      int       `json:"groups" description:"Number of groups captured"`
+	Memberships int       `json:"memberships" description:"Number of active memberships captured"`
+	Grants      int       `json:"grants" description:"Number of active permission grants captured"`
+}
+
+// SnapshotOutput represents the response for taking a snapshot
+type SnapshotOutput struct {
+	Body SnapshotResponse `json:"body"`
+}
+
+// ListSnapshotsOutput represents the response for listing snapshots
+type ListSnapshotsOutput struct {
+	Body ListSnapshotsResponse `json:"body"`
+}
+
+// ListSnapshotsResponse represents the actual response data for listing snapshots
+type ListSnapshotsResponse struct {
+	Snapshots []SnapshotResponse `json:"snapshots" description:"Snapshots, newest first"`
+	Total     int64              `json:"total" description:"Total number of snapshots"`
+	Page      int                `json:"page" description:"Current page number"`
+	Limit     int                `json:"limit" description:"Items per page"`
+}
+
+// SnapshotAccessEntry represents a character that had access in a snapshot
+type SnapshotAccessEntry struct {
+	CharacterID   int64    `json:"character_id" description:"Character ID"`
+	CharacterName string   `json:"character_name,omitempty" description:"Character name"`
+	Via           []string `json:"via" description:"Names of the groups that gave the access"`
+}
+
+// SnapshotAccessOutput represents the response for a point-in-time access query
+type SnapshotAccessOutput struct {
+	Body SnapshotAccessResponse `json:"body"`
+}
+
+// SnapshotAccessResponse represents who had access to a permission or group at a point in time
+type SnapshotAccessResponse struct {
+	Permission string                `json:"permission,omitempty" description:"Permission ID that was checked"`
+	GroupID    string                `json:"group_id,omitempty" description:"Group ID that was checked"`
+	At         time.Time             `json:"at" description:"Requested point in time"`
+	Snapshot   SnapshotResponse      `json:"snapshot" description:"Snapshot the answer is based on"`
+	Characters []SnapshotAccessEntry `json:"characters" description:"Characters that had access"`
+}
+
+// SnapshotGroupDiff represents the changes to one group between two snapshots
+type SnapshotGroupDiff struct {
+	GroupID            string   `json:"group_id" description:"Group ID"`
+	Name               string   `json:"name" description:"Group name"`
+	Change             string   `json:"change" description:"created, deleted or modified"`
+	MembersAdded       []int64  `json:"members_added" description:"Character IDs that joined the group"`
+	MembersRemoved     []int64  `json:"members_removed" description:"Character IDs that left the group"`
+	PermissionsGranted []string `json:"permissions_granted" description:"Permission IDs granted to the group"`
+	PermissionsRevoked []string `json:"permissions_revoked" description:"Permission IDs revoked from the group"`
+}
+
+// SnapshotDiffOutput represents the response for diffing two snapshots
+type SnapshotDiffOutput struct {
+	Body SnapshotDiffResponse `json:"body"`
+}
+
+// SnapshotDiffResponse represents the changes between the snapshots at two points in time
+type SnapshotDiffResponse struct {
+	From   SnapshotResponse    `json:"from" description:"Snapshot at or before the start of the range"`
+	To     SnapshotResponse    `json:"to" description:"Snapshot at or before the end of the range"`
+	Groups []SnapshotGroupDiff `json:"groups" description:"Groups that changed, by name"`
+}
+
+// ListJoinableGroupsOutput represents the response for listing the groups users may apply to
+type ListJoinableGroupsOutput struct {
+	Body struct {
+		Groups []GroupResponse `json:"groups" description:"Active groups accepting join requests"`
+	} `json:"body"`
+}
+
+// ListManagedGroupsOutput represents the response for listing the groups the caller manages
+type ListManagedGroupsOutput struct {
+	Body struct {
+		Groups []GroupResponse `json:"groups" description:"Groups any character of the caller's account manages"`
+	} `json:"body"`
+}
+
+// JoinRequestEventResponse represents one entry of a join request's audit trail
+type JoinRequestEventResponse struct {
+	Action  string    `json:"action" description:"submitted, approved or rejected"`
+	ActorID int64     `json:"actor_id" description:"Character that acted"`
+	Reason  string    `json:"reason,omitempty" description:"Message or reason given"`
+	At      time.Time `json:"at" description:"When it happened"`
+}
+
+// JoinRequestResponse represents a join request
+type JoinRequestResponse struct {
+	ID             string                     `json:"id" description:"Join request ID"`
+	GroupID        string                     `json:"group_id" description:"Group ID"`
+	GroupName      string                     `json:"group_name" description:"Group name"`
+	CharacterID    int64                      `json:"character_id" description:"Applying character"`
+	CharacterName  string                     `json:"character_name" description:"Applying character name"`
+	Message        string                     `json:"message,omitempty" description:"Applicant's message"`
+	Status         string                     `json:"status" description:"pending, approved or rejected"`
+	DecidedBy      *int64                     `json:"decided_by,omitempty" description:"Character who decided"`
+	DecisionReason string                     `json:"decision_reason,omitempty" description:"Reason given with the decision"`
+	DecidedAt      *time.Time                 `json:"decided_at,omitempty" description:"When the request was decided"`
+	History        []JoinRequestEventResponse `json:"history" description:"Audit trail of the request, oldest first"`
+	CreatedAt      time.Time                  `json:"created_at" description:"When the request was submitted"`
+}
+
+// JoinRequestOutput represents the response for submitting or deciding a join request
+type JoinRequestOutput struct {
+	Body JoinRequestResponse `json:"body"`
+}
+
+// ListJoinRequestsOutput represents the response for listing join requests
+type ListJoinRequestsOutput struct {
+	Body struct {
+		Requests []JoinRequestResponse `json:"requests" description:"Join requests, newest first"`
+		Total    int64                 `json:"total" description:"Total number of matching requests"`
+	} `json:"body"`
+}
+
+// SimulatedAccessResponse represents a character's effective permissions in one state
+type SimulatedAccessResponse struct {
+	Groups      []string `json:"groups" description:"Names of the groups the character is a member of"`
+	AdminGroup  string   `json:"admin_group,omitempty" description:"Admin group through which the account bypasses permission checks"`
+	Permissions []string `json:"permissions" description:"Effective permissions, including inherited ones and the admin bypass"`
+}
+
+// PermissionSimulationResponse compares a character's effective permissions before and after
+// hypothetical changes
+type PermissionSimulationResponse struct {
+	CharacterID int64                   `json:"character_id" description:"Character evaluated"`
+	Before      SimulatedAccessResponse `json:"before" description:"Effective access today"`
+	After       SimulatedAccessResponse `json:"after" description:"Effective access with the changes applied"`
+	Gained      []string                `json:"gained" description:"Permissions the changes would give"`
+	Lost        []string                `json:"lost" description:"Permissions the changes would take away"`
+}
+
+// PermissionSimulationOutput represents the response for a permission what-if simulation
+type PermissionSimulationOutput struct {
+	Body PermissionSimulationResponse `json:"body"`
+}
+
+// PermissionAssignmentTrace represents one grant of a permission reaching a character through a group
+type PermissionAssignmentTrace struct {
+	GroupID               string                `json:"group_id" description:"Group the character is a member of"`
+	GroupName             string                `json:"group_name" description:"Name of that group"`
+	GroupType             string                `json:"group_type" description:"system, corporation, alliance or custom"`
+	EVEEntityID           *int64                `json:"eve_entity_id,omitempty" description:"Corporation or alliance the group stands for"`
+	MembershipSource      string                `json:"membership_source,omitempty" description:"What added the membership automatically; empty when added by hand"`
+	MembershipAddedBy     *int64                `json:"membership_added_by,omitempty" description:"Character who added the membership"`
+	MembershipAddedByName string                `json:"membership_added_by_name,omitempty" description:"Name of the character who added the membership"`
+	MembershipAddedAt     time.Time             `json:"membership_added_at" description:"When the character joined the group"`
+	GrantGroupID          string                `json:"grant_group_id" description:"Group the permission is granted to"`
+	GrantGroupName        string                `json:"grant_group_name" description:"Name of that group"`
+	Inherited             bool                  `json:"inherited" description:"Whether the grant is on an ancestor of the member's group"`
+	GrantedBy             *int64                `json:"granted_by,omitempty" description:"Character who granted the permission"`
+	GrantedByName         string                `json:"granted_by_name,omitempty" description:"Name of the character who granted the permission"`
+	GrantedAt             time.Time             `json:"granted_at" description:"When the permission was granted"`
+	Conditions            []PermissionCondition `json:"conditions,omitempty" description:"Conditions the grant is limited by"`
+	UnmetConditions       []string              `json:"unmet_conditions,omitempty" description:"Conditions that do not hold now"`
+	Applied               bool                  `json:"applied" description:"Whether this assignment is the one that grants the permission"`
+}
+
+// PermissionExplanationResponse represents the decision trace of a permission check
+type PermissionExplanationResponse struct {
+	CharacterID   int64                       `json:"character_id" description:"Character that was checked"`
+	CharacterName string                      `json:"character_name,omitempty" description:"Name of the character"`
+	PermissionID  string                      `json:"permission_id" description:"Permission that was checked"`
+	Granted       bool                        `json:"granted" description:"Whether the permission is granted"`
+	Decision      string                      `json:"decision" enum:"admin_bypass,denied,granted,conditions_unmet,no_assignment" description:"Step of the check that decided"`
+	AdminGroup    string                      `json:"admin_group,omitempty" description:"Admin group that bypasses the check"`
+	Denials       []PermissionDenialResponse  `json:"denials" description:"Denials of the permission reaching the character directly or through its corporation or alliance"`
+	Assignments   []PermissionAssignmentTrace `json:"assignments" description:"Active grants reaching the character, direct ones first"`
+}
+
+// PermissionExplanationOutput represents the response for explaining a permission
+type PermissionExplanationOutput struct {
+	Body PermissionExplanationResponse `json:"body"`
+}
+
+// PermissionDenialResponse represents a permission withheld from a character, corporation or alliance
+type PermissionDenialResponse struct {
+	ID           string    `json:"id" description:"Denial ID"`
+	PermissionID string    `json:"permission_id" description:"Denied permission"`
+	SubjectType  string    `json:"subject_type" description:"character, corporation or alliance"`
+	SubjectID    int64     `json:"subject_id" description:"Character, corporation or alliance ID"`
+	Reason       string    `json:"reason,omitempty" description:"Why the permission is denied"`
+	CreatedBy    int64     `json:"created_by" description:"Character who created the denial"`
+	CreatedAt    time.Time `json:"created_at" description:"When the denial was created"`
+}
+
+// PermissionDenialOutput represents the response for a single permission denial
+type PermissionDenialOutput struct {
+	Body PermissionDenialResponse `json:"body"`
+}
+
+// ListPermissionDenialsOutput represents the response for listing permission denials
+type ListPermissionDenialsOutput struct {
+	Body struct {
+		Denials []PermissionDenialResponse `json:"denials" description:"Denials, newest first"`
+	} `json:"body"`
+}
@@ -73,6 +73,10 @@ func (m *CharacterContextMiddleware) ResolveCharacterContext(ctx context.Context
 	if err != nil {
 		return nil, fmt.Errorf("invalid JWT token: %w", err)
 	}
+	// Staff accounts have no character to resolve groups for
+	if user.IsStaff() {
+		return nil, fmt.Errorf("staff accounts have no character context")
+	}
 
 	// Create character context with basic information
 	charContext := &CharacterContext{
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/pkg/identity"
 )
 
 // GroupType represents the type of group
@@ -14,6 +16,7 @@ const (
 	GroupTypeCorporation GroupType = "corporation" // EVE Corporation groups
 	GroupTypeAlliance    GroupType = "alliance"    // EVE Alliance groups
 	GroupTypeCustom      GroupType = "custom"      // User-created custom groups
+	GroupTypeStandings   GroupType = "standings"   // Alliance contact standings tiers, managed by reconciliation
 )
 
 // Group represents a group in the system
@@ -29,6 +32,20 @@ type Group struct {
 	EVEEntityTicker *string `bson:"eve_entity_ticker,omitempty" json:"eve_entity_ticker"` // Corporation/Alliance ticker
 	EVEEntityName   *string `bson:"eve_entity_name,omitempty" json:"eve_entity_name"`     // Corporation/Alliance full name
 
+	// Members inherit the permissions granted to the parent group and its ancestors
+	ParentID *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id"`
+
+	// Users may apply to join; managers approve or reject the join requests (custom groups only)
+	Joinable bool `bson:"joinable,omitempty" json:"joinable"`
+
+	// Characters that may add and remove members of this group without the global membership
+	// permission; any character of their account acts as manager (custom groups only)
+	Managers []int64 `bson:"managers,omitempty" json:"managers"`
+
+	// API requests per minute allowed to each user with a character in the group; 0 sets no policy.
+	// A user in several limited groups gets the highest of their limits.
+	RateLimit int `bson:"rate_limit,omitempty" json:"rate_limit,omitempty"`
+
 	IsActive  bool      `bson:"is_active" json:"is_active"`
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
@@ -40,11 +57,26 @@ type GroupMembership struct {
 	GroupID     primitive.ObjectID `bson:"group_id" json:"group_id"`
 	CharacterID int64              `bson:"character_id" json:"character_id"`
 	IsActive    bool               `bson:"is_active" json:"is_active"`
-	AddedBy     *int64             `bson:"added_by,omitempty" json:"added_by"` // Character ID who added this membership
+	AddedBy     *int64             `bson:"added_by,omitempty" json:"added_by"`               // Character ID who added this membership
+	Source      string             `bson:"source,omitempty" json:"source,omitempty"`         // Set for memberships managed by membership rules or corporation mappings
+	ExpiresAt   *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // Removed by the expiry task after this time
+	NotifiedAt  *time.Time         `bson:"expiry_notified_at,omitempty" json:"-"`            // When the expiry notice went out
+	FlaggedAt   *time.Time         `bson:"flagged_at,omitempty" json:"flagged_at,omitempty"` // Set by revalidation while the character no longer satisfies the group
+	FlagReason  string             `bson:"flag_reason,omitempty" json:"flag_reason,omitempty"`
 	AddedAt     time.Time          `bson:"added_at" json:"added_at"`
 	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
+// Membership sources mark memberships that are managed automatically; only these are removed again
+// when a character stops matching. Adding such a member by hand makes the membership manual.
+const (
+	MembershipSourceRule               = "rule"
+	MembershipSourceCorporationMapping = "corporation_mapping"
+)
+
+// CorporationRolesScope is the EVE SSO scope membership rules need to read corporation roles
+const CorporationRolesScope = "esi-characters.read_corporation_roles.v1"
+
 // SystemGroups contains the predefined system group names
 var SystemGroups = map[string]string{
 	"super_admin":   "Super Administrator",
@@ -52,8 +84,341 @@ var SystemGroups = map[string]string{
 	"guest":         "Guest Users",
 }
 
+// StandingsTier maps a range of alliance contact standings to a managed group
+type StandingsTier struct {
+	GroupName   string
+	Description string
+	Matches     func(standing float64) bool
+}
+
+// StandingsTiers are the groups kept in sync with imported alliance contacts
+var StandingsTiers = []StandingsTier{
+	{
+		GroupName:   "standings_plus10",
+		Description: "Characters with excellent (+10) alliance standing",
+		Matches:     func(standing float64) bool { return standing >= 10 },
+	},
+	{
+		GroupName:   "standings_plus5",
+		Description: "Characters with good (+5) alliance standing",
+		Matches:     func(standing float64) bool { return standing >= 5 && standing < 10 },
+	},
+	{
+		GroupName:   "standings_minus10",
+		Description: "Characters with terrible (-10) alliance standing",
+		Matches:     func(standing float64) bool { return standing <= -10 },
+	},
+}
+
+// AllianceContactsScope is the EVE SSO scope standings imports read an alliance's contacts with
+const AllianceContactsScope = "esi-alliances.read_contacts.v1"
+
+// StandingsContact is a single entry of an alliance contact list
+type StandingsContact struct {
+	ContactID   int64   `bson:"contact_id" json:"contact_id"`
+	ContactType string  `bson:"contact_type" json:"contact_type"` // character, corporation, alliance or faction
+	Standing    float64 `bson:"standing" json:"standing"`
+}
+
+// StandingsContactList is the imported contact list of one alliance
+type StandingsContactList struct {
+	AllianceID int64              `bson:"_id" json:"alliance_id"`
+	Contacts   []StandingsContact `bson:"contacts" json:"contacts"`
+	ImportedBy int64              `bson:"imported_by" json:"imported_by"`
+	ImportedAt time.Time          `bson:"imported_at" json:"imported_at"`
+}
+
+// StandingsTierDrift describes how far a tier group is from the imported standings
+type StandingsTierDrift struct {
+	GroupName  string  `bson:"group_name" json:"group_name"`
+	Expected   int     `bson:"expected" json:"expected"`
+	Missing    []int64 `bson:"missing" json:"missing"`       // Characters with the standing but not in the group
+	Unexpected []int64 `bson:"unexpected" json:"unexpected"` // Group members without the standing
+}
+
+// StandingsSyncReport records one reconciliation run of the standings groups
+type StandingsSyncReport struct {
+	ID                  primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	DryRun              bool                 `bson:"dry_run" json:"dry_run"`
+	TriggeredBy         *int64               `bson:"triggered_by,omitempty" json:"triggered_by"` // Character ID, nil for scheduled runs
+	ContactLists        int                  `bson:"contact_lists" json:"contact_lists"`
+	CharactersEvaluated int                  `bson:"characters_evaluated" json:"characters_evaluated"`
+	Tiers               []StandingsTierDrift `bson:"tiers" json:"tiers"`
+	Added               int                  `bson:"added" json:"added"`
+	Removed             int                  `bson:"removed" json:"removed"`
+	StartedAt           time.Time            `bson:"started_at" json:"started_at"`
+	CompletedAt         time.Time            `bson:"completed_at" json:"completed_at"`
+}
+
+// Snapshot records a point-in-time copy of all group memberships and permission grants
+type Snapshot struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TakenAt     time.Time          `bson:"taken_at" json:"taken_at"`
+	Trigger     string             `bson:"trigger" json:"trigger"`             // "scheduled" or "manual"
+	TakenBy     *int64             `bson:"taken_by,omitempty" json:"taken_by"` // Character ID for manual snapshots
+	Groups      int                `bson:"groups" json:"groups"`               // Number of groups captured
+	Memberships int                `bson:"memberships" json:"memberships"`     // Number of active memberships captured
+	Grants      int                `bson:"grants" json:"grants"`               // Number of active permission grants captured
+}
+
+// SnapshotGroup is the state of one group within a snapshot
+type SnapshotGroup struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	SnapshotID  primitive.ObjectID `bson:"snapshot_id" json:"snapshot_id"`
+	TakenAt     time.Time          `bson:"taken_at" json:"taken_at"`
+	GroupID     primitive.ObjectID `bson:"group_id" json:"group_id"`
+	Name        string             `bson:"name" json:"name"`
+	Type        GroupType          `bson:"type" json:"type"`
+	SystemName  *string            `bson:"system_name,omitempty" json:"system_name"`
+	IsActive    bool               `bson:"is_active" json:"is_active"`
+	Members     []int64            `bson:"members" json:"members"`         // Active member character IDs, sorted
+	Permissions []string           `bson:"permissions" json:"permissions"` // Active permission IDs, sorted
+}
+
+// Snapshot triggers
+const (
+	SnapshotTriggerScheduled = "scheduled"
+	SnapshotTriggerManual    = "manual"
+)
+
+// AdminGroupNames are groups whose members pass every permission check without explicit grants
+var AdminGroupNames = []string{"Super Administrator", "Administrator"}
+
+// MembershipRule adds characters to a custom group while they match all of its non-empty
+// conditions: one of the corporations, one of the alliances and one of the corporation roles
+// (read from ESI with the esi-characters.read_corporation_roles.v1 scope)
+type MembershipRule struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GroupID        primitive.ObjectID `bson:"group_id" json:"group_id"`
+	Name           string             `bson:"name" json:"name"`
+	CorporationIDs []int64            `bson:"corporation_ids,omitempty" json:"corporation_ids"`
+	AllianceIDs    []int64            `bson:"alliance_ids,omitempty" json:"alliance_ids"`
+	Roles          []string           `bson:"roles,omitempty" json:"roles"`
+	IsActive       bool               `bson:"is_active" json:"is_active"`
+	CreatedBy      int64              `bson:"created_by" json:"created_by"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// MatchesAffiliation reports whether the corporation and alliance conditions hold
+func (r *MembershipRule) MatchesAffiliation(corporationID, allianceID int64) bool {
+	if len(r.CorporationIDs) > 0 && !containsID(r.CorporationIDs, corporationID) {
+		return false
+	}
+	if len(r.AllianceIDs) > 0 && (allianceID == 0 || !containsID(r.AllianceIDs, allianceID)) {
+		return false
+	}
+	return true
+}
+
+// MatchesRoles reports whether the role condition holds for the roles a character has
+func (r *MembershipRule) MatchesRoles(roles map[string]bool) bool {
+	if len(r.Roles) == 0 {
+		return true
+	}
+	for _, role := range r.Roles {
+		if roles[role] {
+			return true
+		}
+	}
+	return false
+}
+
+// Kinds of corporation mappings
+const (
+	CorporationMappingRole  = "role"
+	CorporationMappingTitle = "title"
+)
+
+// EVE SSO scopes corporation mappings read member roles and titles with; the titles also need the
+// Director role in game
+const (
+	CorporationMembershipScope = "esi-corporations.read_corporation_membership.v1"
+	CorporationTitlesScope     = "esi-corporations.read_titles.v1"
+)
+
+// CorporationMapping keeps the registered members of a corporation that hold an in-game role or
+// title in a custom group. Unlike membership rules, which read the roles of each character with its
+// own token, mappings read the member lists of the whole corporation with a director's token.
+type CorporationMapping struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CorporationID int64              `bson:"corporation_id" json:"corporation_id"`
+	Kind          string             `bson:"kind" json:"kind"`                             // role or title
+	Role          string             `bson:"role,omitempty" json:"role,omitempty"`         // ESI role name for role mappings
+	TitleID       int                `bson:"title_id,omitempty" json:"title_id,omitempty"` // Title ID for title mappings
+	GroupID       primitive.ObjectID `bson:"group_id" json:"group_id"`
+	IsActive      bool               `bson:"is_active" json:"is_active"`
+	CreatedBy     int64              `bson:"created_by" json:"created_by"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Events delivered to group webhooks
+const (
+	WebhookMemberAdded       = "member.added"
+	WebhookMemberRemoved     = "member.removed"
+	WebhookPermissionGranted = "permission.granted"
+	WebhookPermissionRevoked = "permission.revoked"
+)
+
+// GroupWebhook is an outbound endpoint notified of membership and permission changes. Deliveries
+// are signed with HMAC-SHA256 over the timestamp and body using Secret.
+type GroupWebhook struct {
+	ID                  primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name                string               `bson:"name" json:"name"`
+	URL                 string               `bson:"url" json:"url"`
+	Secret              string               `bson:"secret" json:"-"`
+	Events              []string             `bson:"events,omitempty" json:"events"`       // All events if empty
+	GroupIDs            []primitive.ObjectID `bson:"group_ids,omitempty" json:"group_ids"` // All groups if empty
+	IsActive            bool                 `bson:"is_active" json:"is_active"`
+	LastDeliveryAt      *time.Time           `bson:"last_delivery_at,omitempty" json:"last_delivery_at,omitempty"`
+	LastStatusCode      int                  `bson:"last_status_code,omitempty" json:"last_status_code,omitempty"`
+	LastError           string               `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	ConsecutiveFailures int                  `bson:"consecutive_failures" json:"consecutive_failures"`
+	CreatedBy           int64                `bson:"created_by" json:"created_by"`
+	CreatedAt           time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt           time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+// Subscribes reports whether the webhook wants an event about a group
+func (w *GroupWebhook) Subscribes(event string, groupID primitive.ObjectID) bool {
+	if len(w.Events) > 0 {
+		found := false
+		for _, subscribed := range w.Events {
+			if subscribed == event {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(w.GroupIDs) == 0 {
+		return true
+	}
+	for _, id := range w.GroupIDs {
+		if id == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinRequest is a user's application for one of their characters to join a joinable group
+type JoinRequest struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GroupID        primitive.ObjectID `bson:"group_id" json:"group_id"`
+	CharacterID    int64              `bson:"character_id" json:"character_id"`
+	UserID         string             `bson:"user_id" json:"user_id"`
+	Message        string             `bson:"message,omitempty" json:"message"`
+	Status         string             `bson:"status" json:"status"`
+	DecidedBy      *int64             `bson:"decided_by,omitempty" json:"decided_by"`
+	DecisionReason string             `bson:"decision_reason,omitempty" json:"decision_reason"`
+	DecidedAt      *time.Time         `bson:"decided_at,omitempty" json:"decided_at"`
+	History        []JoinRequestEvent `bson:"history" json:"history"` // Audit trail, oldest first
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// JoinRequestEvent records who submitted or decided a join request, and why
+type JoinRequestEvent struct {
+	Action  string    `bson:"action" json:"action"`     // submitted, approved or rejected
+	ActorID int64     `bson:"actor_id" json:"actor_id"` // Character that acted
+	Reason  string    `bson:"reason,omitempty" json:"reason"`
+	At      time.Time `bson:"at" json:"at"`
+}
+
+// GroupTemplate is a set of custom groups created together for a newly onboarded corporation.
+// Group names and descriptions may use the placeholders {ticker}, {name} and {corporation_id}.
+type GroupTemplate struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description"`
+	Groups      []TemplateGroup    `bson:"groups" json:"groups"`
+	CreatedBy   int64              `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// TemplateGroup is one group of a template. A group with AutoMembership gets a membership rule
+// matching the corporation's members, narrowed to MemberRoles when set.
+type TemplateGroup struct {
+	Key            string   `bson:"key" json:"key"`                                   // Unique within the template
+	ParentKey      string   `bson:"parent_key,omitempty" json:"parent_key,omitempty"` // Key of an earlier group of the template
+	Name           string   `bson:"name" json:"name"`
+	Description    string   `bson:"description,omitempty" json:"description,omitempty"`
+	Joinable       bool     `bson:"joinable,omitempty" json:"joinable"`
+	Permissions    []string `bson:"permissions,omitempty" json:"permissions"`
+	AutoMembership bool     `bson:"auto_membership,omitempty" json:"auto_membership"`
+	MemberRoles    []string `bson:"member_roles,omitempty" json:"member_roles"`
+}
+
+// Join request statuses; the event actions use the same names plus JoinRequestSubmitted
+const (
+	JoinRequestPending   = "pending"
+	JoinRequestApproved  = "approved"
+	JoinRequestRejected  = "rejected"
+	JoinRequestSubmitted = "submitted"
+)
+
+// AuditEvent is one entry of the groups audit log: a change to a group, its members, its
+// permission grants or a permission denial. Old events move to the archive collection.
+type AuditEvent struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Action       string              `bson:"action" json:"action"`
+	Service      string              `bson:"service" json:"service"` // Service of the permission involved; "groups" otherwise
+	GroupID      *primitive.ObjectID `bson:"group_id,omitempty" json:"group_id,omitempty"`
+	GroupName    string              `bson:"group_name,omitempty" json:"group_name,omitempty"`
+	CharacterID  int64               `bson:"character_id,omitempty" json:"character_id,omitempty"` // Member or denied character
+	PermissionID string              `bson:"permission_id,omitempty" json:"permission_id,omitempty"`
+	Detail       string              `bson:"detail,omitempty" json:"detail,omitempty"`
+
+	identity.Attribution `bson:",inline"`
+	Timestamp            time.Time `bson:"timestamp" json:"timestamp"`
+}
+
+// Groups audit log actions
+const (
+	AuditGroupCreated           = "group_created"
+	AuditGroupUpdated           = "group_updated"
+	AuditGroupDeleted           = "group_deleted"
+	AuditMemberAdded            = "member_added"
+	AuditMemberRemoved          = "member_removed"
+	AuditPermissionGranted      = "permission_granted"
+	AuditPermissionRevoked      = "permission_revoked"
+	AuditPermissionStatus       = "permission_status_changed"
+	AuditPermissionDenied       = "permission_denied"
+	AuditPermissionDenialLifted = "permission_denial_removed"
+	AuditManagerAdded           = "manager_added"
+	AuditManagerRemoved         = "manager_removed"
+)
+
+// AuditServiceGroups is the service of audit events that involve no permission
+const AuditServiceGroups = "groups"
+
 // Collection names
 const (
-	GroupsCollection      = "groups"
-	MembershipsCollection = "group_memberships"
+	GroupsCollection              = "groups"
+	MembershipsCollection         = "group_memberships"
+	StandingsContactsCollection   = "standings_contacts"
+	StandingsReportsCollection    = "standings_sync_reports"
+	SnapshotsCollection           = "group_snapshots"
+	SnapshotGroupsCollection      = "group_snapshot_groups"
+	MembershipRulesCollection     = "group_membership_rules"
+	JoinRequestsCollection        = "group_join_requests"
+	CorporationMappingsCollection = "group_corporation_mappings"
+	WebhooksCollection            = "group_webhooks"
+	TemplatesCollection           = "group_templates"
+	AuditCollection               = "group_audit"
+	AuditArchiveCollection        = "group_audit_archive"
 )
@@ -143,10 +143,28 @@ func (m *Module) SetAuthModule(authModule AuthModule) error {
 	// Recreate routes with the new middleware
 	m.routes = routes.NewModule(m.service, m.middleware)
 
+	// Offer group membership claims for issued JWTs (enabled via JWT_CUSTOM_CLAIMS)
+	m.registerClaimProviders(authService)
+
 	slog.Info("Groups module updated with auth dependencies")
 	return nil
 }
 
+// registerClaimProviders registers the groups and perm_version JWT claims with the auth service
+func (m *Module) registerClaimProviders(authService *authServices.AuthService) {
+	if err := authService.RegisterClaimProvider("groups", func(ctx context.Context, subject authServices.ClaimSubject) (any, error) {
+		return m.service.GroupSlugsClaim(ctx, int64(subject.CharacterID))
+	}); err != nil {
+		slog.Warn("Failed to register groups JWT claim", "error", err)
+	}
+
+	if err := authService.RegisterClaimProvider("perm_version", func(ctx context.Context, subject authServices.ClaimSubject) (any, error) {
+		return m.service.PermissionVersionClaim(ctx, int64(subject.CharacterID))
+	}); err != nil {
+		slog.Warn("Failed to register perm_version JWT claim", "error", err)
+	}
+}
+
 // SetPermissionManager updates the groups module with permission manager
 func (m *Module) SetPermissionManager(permissionManager *permissions.PermissionManager) error {
 	if m.middleware == nil {
@@ -11,6 +11,7 @@ import (
 	"go-falcon/internal/groups/dto"
 	"go-falcon/internal/groups/middleware"
 	"go-falcon/internal/groups/services"
+	"go-falcon/pkg/apidocs"
 )
 
 // Module contains the dependencies for group routes
@@ -84,6 +85,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "List groups",
 		Description: "List groups with optional filtering (requires authentication)",
 		Tags:        []string{"Groups / Management"},
+		Extensions:  apidocs.RequiresPermission("groups:view:all"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.ListGroupsInput) (*dto.ListGroupsOutput, error) {
 		// Validate authentication and check permissions
@@ -102,6 +104,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Get a specific group",
 		Description: "Retrieve details of a specific group (requires authentication)",
 		Tags:        []string{"Groups / Management"},
+		Extensions:  apidocs.RequiresPermission("groups:view:all"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.getGroup)
 
@@ -131,7 +134,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Method:      "POST",
 		Path:        "/groups/{group_id}/members",
 		Summary:     "Add a member to a group",
-		Description: "Add a character to a group (requires admin access)",
+		Description: "Add a character to a group (requires groups:memberships:manage, or being a manager of the group)",
 		Tags:        []string{"Groups / Memberships"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.addMember)
@@ -141,11 +144,66 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Method:      "DELETE",
 		Path:        "/groups/{group_id}/members/{character_id}",
 		Summary:     "Remove a member from a group",
-		Description: "Remove a character from a group (requires admin access)",
+		Description: "Remove a character from a group (requires groups:memberships:manage, or being a manager of the group)",
 		Tags:        []string{"Groups / Memberships"},
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.removeMember)
 
+	// Group managers
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-list-managed",
+		Method:      "GET",
+		Path:        "/groups/managed",
+		Summary:     "List managed groups",
+		Description: "List the groups any character of the authenticated user's account manages",
+		Tags:        []string{"Groups / Memberships"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listManagedGroups)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-add-manager",
+		Method:      "PUT",
+		Path:        "/groups/{group_id}/managers/{character_id}",
+		Summary:     "Add group manager",
+		Description: "Let a character add and remove members of a custom group without the global membership permission (requires groups:management:full)",
+		Tags:        []string{"Groups / Memberships"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.addGroupManager)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-remove-manager",
+		Method:      "DELETE",
+		Path:        "/groups/{group_id}/managers/{character_id}",
+		Summary:     "Remove group manager",
+		Description: "Stop a character from managing the members of a group (requires groups:management:full)",
+		Tags:        []string{"Groups / Memberships"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.removeGroupManager)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-set-rate-limit",
+		Method:      "PUT",
+		Path:        "/groups/{group_id}/rate-limit",
+		Summary:     "Set group rate limit",
+		Description: "Limit every user with a character in the group to a number of API requests per minute; a user in several limited groups gets the highest limit (requires groups:management:full)",
+		Tags:        []string{"Groups / Management"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.setGroupRateLimit)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-remove-rate-limit",
+		Method:      "DELETE",
+		Path:        "/groups/{group_id}/rate-limit",
+		Summary:     "Remove group rate limit",
+		Description: "Remove the rate limit policy of a group (requires groups:management:full)",
+		Tags:        []string{"Groups / Management"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.removeGroupRateLimit)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "groups-list-members",
 		Method:      "GET",
@@ -153,6 +211,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "List group members",
 		Description: "List all members of a group (requires authentication)",
 		Tags:        []string{"Groups / Memberships"},
+		Extensions:  apidocs.RequiresPermission("groups:view:all"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.listMembers)
 
@@ -163,6 +222,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Check group membership",
 		Description: "Check if a character is a member of a group (requires authentication)",
 		Tags:        []string{"Groups / Memberships"},
+		Extensions:  apidocs.RequiresPermission("groups:view:all"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.checkMembership)
 
@@ -185,6 +245,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Get my groups",
 		Description: "Get all groups the current authenticated user belongs to",
 		Tags:        []string{"Groups / Current User"},
+		Extensions:  apidocs.RequiresPermission("groups:view:all"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.getMyGroups)
 
@@ -196,9 +257,411 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Get user groups",
 		Description: "Get all groups that any character belonging to a user_id belongs to (requires authentication)",
 		Tags:        []string{"Groups / Users"},
+		Extensions:  apidocs.RequiresPermission("groups:view:all"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.getUserGroups)
 
+	// Standings group endpoints
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-get-standings-status",
+		Method:      "GET",
+		Path:        "/groups/standings",
+		Summary:     "Get standings groups status",
+		Description: "List imported alliance contact lists, the standings tier groups and the latest drift report (requires groups:management:full)",
+		Tags:        []string{"Groups / Standings"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.getStandingsStatus)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-import-standings-contacts",
+		Method:      "PUT",
+		Path:        "/groups/standings/contacts/{alliance_id}",
+		Summary:     "Import alliance contacts",
+		Description: "Read the contact list of an alliance from ESI, with the token of one of its characters that granted esi-alliances.read_contacts.v1, and replace the stored one used to build the standings tier groups (requires groups:management:full)",
+		Tags:        []string{"Groups / Standings"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.importStandingsContacts)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-delete-standings-contacts",
+		Method:      "DELETE",
+		Path:        "/groups/standings/contacts/{alliance_id}",
+		Summary:     "Delete alliance contacts",
+		Description: "Remove the contact list of an alliance; its standings are dropped on the next reconciliation (requires groups:management:full)",
+		Tags:        []string{"Groups / Standings"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.deleteStandingsContacts)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-reconcile-standings",
+		Method:      "POST",
+		Path:        "/groups/standings/reconcile",
+		Summary:     "Reconcile standings groups",
+		Description: "Sync the standings tier group memberships with the imported contact lists and return the drift report; use dry_run to only report (requires groups:management:full)",
+		Tags:        []string{"Groups / Standings"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.reconcileStandings)
+
+	// Membership rule endpoints
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-list-membership-rules",
+		Method:      "GET",
+		Path:        "/groups/rules",
+		Summary:     "List membership rules",
+		Description: "List the rules that add characters to custom groups by corporation, alliance or corporation role (requires groups:management:full)",
+		Tags:        []string{"Groups / Rules"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listMembershipRules)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-create-membership-rule",
+		Method:      "POST",
+		Path:        "/groups/rules",
+		Summary:     "Create membership rule",
+		Description: "Add a rule such as 'all members of corporation X join group Y'; it applies on sign-in and on the next scheduled evaluation (requires groups:management:full)",
+		Tags:        []string{"Groups / Rules"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.createMembershipRule)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-update-membership-rule",
+		Method:      "PUT",
+		Path:        "/groups/rules/{rule_id}",
+		Summary:     "Update membership rule",
+		Description: "Replace the conditions of a membership rule (requires groups:management:full)",
+		Tags:        []string{"Groups / Rules"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.updateMembershipRule)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-delete-membership-rule",
+		Method:      "DELETE",
+		Path:        "/groups/rules/{rule_id}",
+		Summary:     "Delete membership rule",
+		Description: "Remove a membership rule; the members it added leave on the next evaluation (requires groups:management:full)",
+		Tags:        []string{"Groups / Rules"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.deleteMembershipRule)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-reconcile-membership-rules",
+		Method:      "POST",
+		Path:        "/groups/rules/reconcile",
+		Summary:     "Evaluate membership rules",
+		Description: "Evaluate the membership rules for every registered character and return the drift per group; use dry_run to only report (requires groups:management:full)",
+		Tags:        []string{"Groups / Rules"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.reconcileMembershipRules)
+
+	// Corporation mapping endpoints
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-list-corporation-mappings",
+		Method:      "GET",
+		Path:        "/groups/corporation-mappings",
+		Summary:     "List corporation mappings",
+		Description: "List the mappings that keep corporation members holding an in-game role or title in a custom group (requires groups:management:full)",
+		Tags:        []string{"Groups / Corporation Mappings"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listCorporationMappings)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-create-corporation-mapping",
+		Method:      "POST",
+		Path:        "/groups/corporation-mappings",
+		Summary:     "Create corporation mapping",
+		Description: "Map a corporation role or title to a custom group; members holding it join on the next sync, read with the token of a director who granted esi-corporations.read_corporation_membership.v1 or esi-corporations.read_titles.v1 (requires groups:management:full)",
+		Tags:        []string{"Groups / Corporation Mappings"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.createCorporationMapping)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-update-corporation-mapping",
+		Method:      "PUT",
+		Path:        "/groups/corporation-mappings/{mapping_id}",
+		Summary:     "Update corporation mapping",
+		Description: "Enable or disable a corporation mapping (requires groups:management:full)",
+		Tags:        []string{"Groups / Corporation Mappings"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.updateCorporationMapping)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-delete-corporation-mapping",
+		Method:      "DELETE",
+		Path:        "/groups/corporation-mappings/{mapping_id}",
+		Summary:     "Delete corporation mapping",
+		Description: "Remove a corporation mapping; the members it added leave on the next sync (requires groups:management:full)",
+		Tags:        []string{"Groups / Corporation Mappings"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.deleteCorporationMapping)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-reconcile-corporation-mappings",
+		Method:      "POST",
+		Path:        "/groups/corporation-mappings/reconcile",
+		Summary:     "Sync corporation mappings",
+		Description: "Read the member roles and titles of every mapped corporation from ESI and return the drift per group; use dry_run to only report (requires groups:management:full)",
+		Tags:        []string{"Groups / Corporation Mappings"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.reconcileCorporationMappings)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-revalidate-memberships",
+		Method:      "POST",
+		Path:        "/groups/memberships/revalidate",
+		Summary:     "Revalidate corporation and alliance memberships",
+		Description: "Read the affiliation of every corporation and alliance group member from ESI and flag or remove the members no longer in the group's entity; use dry_run to only report (requires groups:management:full)",
+		Tags:        []string{"Groups / Memberships"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.revalidateMemberships)
+
+	// Group template endpoints
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-list-templates",
+		Method:      "GET",
+		Path:        "/groups/templates",
+		Summary:     "List group templates",
+		Description: "List the templates of groups created together for an onboarded corporation (requires groups:management:full)",
+		Tags:        []string{"Groups / Templates"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listGroupTemplates)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-create-template",
+		Method:      "POST",
+		Path:        "/groups/templates",
+		Summary:     "Create group template",
+		Description: "Define a set of custom groups with their parents, settings, default permissions and auto-membership rules; names may use {ticker}, {name} and {corporation_id} (requires groups:management:full)",
+		Tags:        []string{"Groups / Templates"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.createGroupTemplate)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-update-template",
+		Method:      "PUT",
+		Path:        "/groups/templates/{template_id}",
+		Summary:     "Update group template",
+		Description: "Replace a group template; groups already created from it are not changed (requires groups:management:full)",
+		Tags:        []string{"Groups / Templates"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.updateGroupTemplate)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-delete-template",
+		Method:      "DELETE",
+		Path:        "/groups/templates/{template_id}",
+		Summary:     "Delete group template",
+		Description: "Remove a group template; groups created from it are kept (requires groups:management:full)",
+		Tags:        []string{"Groups / Templates"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.deleteGroupTemplate)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-instantiate-template",
+		Method:      "POST",
+		Path:        "/groups/templates/{template_id}/instantiate",
+		Summary:     "Instantiate group template",
+		Description: "Create the groups of a template for an enabled managed corporation, with their grants and membership rules; use dry_run to preview the names. Needs a second factor when the template grants permissions (requires groups:management:full)",
+		Tags:        []string{"Groups / Templates"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.instantiateGroupTemplate)
+
+	// Webhook endpoints
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-list-webhooks",
+		Method:      "GET",
+		Path:        "/groups/webhooks",
+		Summary:     "List group webhooks",
+		Description: "List the outbound webhooks notified of membership and permission changes (requires groups:management:full)",
+		Tags:        []string{"Groups / Webhooks"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listWebhooks)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-create-webhook",
+		Method:      "POST",
+		Path:        "/groups/webhooks",
+		Summary:     "Create group webhook",
+		Description: "Add an endpoint that receives HMAC-signed member.added, member.removed, permission.granted and permission.revoked events; the signing secret is only returned here (requires groups:management:full)",
+		Tags:        []string{"Groups / Webhooks"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.createWebhook)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-update-webhook",
+		Method:      "PUT",
+		Path:        "/groups/webhooks/{webhook_id}",
+		Summary:     "Update group webhook",
+		Description: "Replace the configuration of a webhook; an empty secret keeps the current one (requires groups:management:full)",
+		Tags:        []string{"Groups / Webhooks"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.updateWebhook)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-delete-webhook",
+		Method:      "DELETE",
+		Path:        "/groups/webhooks/{webhook_id}",
+		Summary:     "Delete group webhook",
+		Description: "Remove a webhook (requires groups:management:full)",
+		Tags:        []string{"Groups / Webhooks"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.deleteWebhook)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-test-webhook",
+		Method:      "POST",
+		Path:        "/groups/webhooks/{webhook_id}/test",
+		Summary:     "Test group webhook",
+		Description: "Deliver a webhook.test event now and return the webhook with the delivery outcome (requires groups:management:full)",
+		Tags:        []string{"Groups / Webhooks"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.testWebhook)
+
+	// Snapshot endpoints
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-list-snapshots",
+		Method:      "GET",
+		Path:        "/groups/snapshots",
+		Summary:     "List group snapshots",
+		Description: "List the stored snapshots of group memberships and permission grants, newest first (requires groups:audit:read)",
+		Tags:        []string{"Groups / Snapshots"},
+		Extensions:  apidocs.RequiresPermission("groups:audit:read"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listSnapshots)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-create-snapshot",
+		Method:      "POST",
+		Path:        "/groups/snapshots",
+		Summary:     "Take group snapshot",
+		Description: "Record the current group memberships and permission grants, e.g. before a risky change (requires groups:audit:read)",
+		Tags:        []string{"Groups / Snapshots"},
+		Extensions:  apidocs.RequiresPermission("groups:audit:read"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.createSnapshot)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-get-snapshot-access",
+		Method:      "GET",
+		Path:        "/groups/snapshots/access",
+		Summary:     "Get historical access",
+		Description: "List the characters that held a permission or belonged to a group at a point in time, from the latest snapshot at or before it (requires groups:audit:read)",
+		Tags:        []string{"Groups / Snapshots"},
+		Extensions:  apidocs.RequiresPermission("groups:audit:read"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.getSnapshotAccess)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-diff-snapshots",
+		Method:      "GET",
+		Path:        "/groups/snapshots/diff",
+		Summary:     "Diff group snapshots",
+		Description: "List membership and permission changes between the snapshots in effect at two points in time (requires groups:audit:read)",
+		Tags:        []string{"Groups / Snapshots"},
+		Extensions:  apidocs.RequiresPermission("groups:audit:read"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.diffSnapshots)
+
+	// Join request endpoints
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-list-joinable",
+		Method:      "GET",
+		Path:        "/groups/joinable",
+		Summary:     "List joinable groups",
+		Description: "List the active groups users may apply to join (requires authentication)",
+		Tags:        []string{"Groups / Join Requests"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listJoinableGroups)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-create-join-request",
+		Method:      "POST",
+		Path:        "/groups/{group_id}/join-requests",
+		Summary:     "Apply to join a group",
+		Description: "Apply for a character of the caller's account to join a joinable group; a manager approves or rejects the request (requires authentication)",
+		Tags:        []string{"Groups / Join Requests"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.createJoinRequest)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-list-my-join-requests",
+		Method:      "GET",
+		Path:        "/groups/join-requests/me",
+		Summary:     "List my join requests",
+		Description: "List the join requests of the caller's characters with their status and decision reasons (requires authentication)",
+		Tags:        []string{"Groups / Join Requests"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listMyJoinRequests)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-list-join-requests",
+		Method:      "GET",
+		Path:        "/groups/join-requests",
+		Summary:     "List join requests",
+		Description: "List join requests to review, pending ones by default, with their audit trail (requires membership management access)",
+		Tags:        []string{"Groups / Join Requests"},
+		Extensions:  apidocs.RequiresPermission("groups:memberships:manage"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listJoinRequests)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-approve-join-request",
+		Method:      "POST",
+		Path:        "/groups/join-requests/{request_id}/approve",
+		Summary:     "Approve join request",
+		Description: "Approve a pending join request and add the character to the group (requires membership management access)",
+		Tags:        []string{"Groups / Join Requests"},
+		Extensions:  apidocs.RequiresPermission("groups:memberships:manage"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.approveJoinRequest)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-reject-join-request",
+		Method:      "POST",
+		Path:        "/groups/join-requests/{request_id}/reject",
+		Summary:     "Reject join request",
+		Description: "Reject a pending join request with a reason shown to the applicant (requires membership management access)",
+		Tags:        []string{"Groups / Join Requests"},
+		Extensions:  apidocs.RequiresPermission("groups:memberships:manage"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.rejectJoinRequest)
+
+	// Export the groups audit log
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-export-audit",
+		Method:      "GET",
+		Path:        "/groups/admin/audit/export",
+		Summary:     "Export groups audit log",
+		Description: "Stream group, membership, permission grant and denial changes as CSV or JSON Lines, oldest first, filtered by time range, acting character and service. Archived events are included unless include_archive=false (requires groups:audit:read).",
+		Tags:        []string{"Groups / Audit"},
+		Extensions:  apidocs.RequiresPermission("groups:audit:read"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.exportAudit)
+
 	// Permission Management Endpoints
 
 	// List all permissions
@@ -223,14 +686,75 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.getPermission)
 
+	// Evaluate hypothetical permission changes
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-simulate-permissions",
+		Method:      "POST",
+		Path:        "/groups/admin/permissions/simulate",
+		Summary:     "Simulate permission changes",
+		Description: "Show a character's effective permissions today and with hypothetical grants, revocations or membership changes applied, and which permissions it would gain or lose. Nothing is persisted (requires groups:management:full).",
+		Tags:        []string{"Permissions"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.simulatePermissions)
+
+	// Explain a permission decision
+	huma.Register(api, huma.Operation{
+		OperationID: "groups-explain-permission",
+		Method:      "GET",
+		Path:        "/groups/permissions/explain",
+		Summary:     "Explain permission",
+		Description: "Trace why a character has or lacks a permission: the admin bypass, denials and every assignment through its groups with the group, corporation or alliance it comes through and who granted it when (requires groups:management:full)",
+		Tags:        []string{"Permissions"},
+		Extensions:  apidocs.RequiresPermission("groups:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.explainPermission)
+
+	// List permission denials
+	huma.Register(api, huma.Operation{
+		OperationID: "permissions-list-denials",
+		Method:      "GET",
+		Path:        "/groups/admin/permissions/denials",
+		Summary:     "List permission denials",
+		Description: "List the permissions withheld from characters, corporations or alliances (requires groups:permissions:manage)",
+		Tags:        []string{"Permissions"},
+		Extensions:  apidocs.RequiresPermission("groups:permissions:manage"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.listPermissionDenials)
+
+	// Deny a permission
+	huma.Register(api, huma.Operation{
+		OperationID: "permissions-create-denial",
+		Method:      "POST",
+		Path:        "/groups/admin/permissions/denials",
+		Summary:     "Deny permission",
+		Description: "Withhold a permission from a character or from every character of a corporation or alliance. A denial overrides any grant; only the admin groups bypass it (requires groups:permissions:manage).",
+		Tags:        []string{"Permissions"},
+		Extensions:  apidocs.RequiresPermission("groups:permissions:manage"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.createPermissionDenial)
+
+	// Lift a permission denial
+	huma.Register(api, huma.Operation{
+		OperationID: "permissions-delete-denial",
+		Method:      "DELETE",
+		Path:        "/groups/admin/permissions/denials/{denial_id}",
+		Summary:     "Remove permission denial",
+		Description: "Lift a denial so the permission follows group grants again (requires groups:permissions:manage and a second factor when enabled)",
+		Tags:        []string{"Permissions"},
+		Extensions:  apidocs.RequiresPermission("groups:permissions:manage"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, m.deletePermissionDenial)
+
 	// Grant permission to group
 	huma.Register(api, huma.Operation{
 		OperationID: "groups-grant-permission",
 		Method:      "POST",
 		Path:        "/groups/{group_id}/permissions",
 		Summary:     "Grant permission to group",
-		Description: "Grant a specific permission to a group (requires groups:permissions:manage)",
-		Tags:        []string{"Group Permissions"},
+		Description: "Grant a specific permission to a group, optionally only while conditions on the character or the time of day hold (requires groups:permissions:manage)",
+		Tags:        []string{"Groups / Permissions"},
+		Extensions:  apidocs.RequiresPermission("groups:permissions:manage"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.grantPermissionToGroup)
 
@@ -241,7 +765,8 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/groups/{group_id}/permissions/{permission_id}",
 		Summary:     "Revoke permission from group",
 		Description: "Revoke a specific permission from a group (requires groups:permissions:manage)",
-		Tags:        []string{"Group Permissions"},
+		Tags:        []string{"Groups / Permissions"},
+		Extensions:  apidocs.RequiresPermission("groups:permissions:manage"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.revokePermissionFromGroup)
 
@@ -252,7 +777,8 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/groups/{group_id}/permissions/{permission_id}",
 		Summary:     "Update group permission status",
 		Description: "Update the active/inactive status of a permission assigned to a group (requires groups:permissions:manage)",
-		Tags:        []string{"Group Permissions"},
+		Tags:        []string{"Groups / Permissions"},
+		Extensions:  apidocs.RequiresPermission("groups:permissions:manage"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.updateGroupPermissionStatus)
 
@@ -263,7 +789,8 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Path:        "/groups/{group_id}/permissions",
 		Summary:     "List group permissions",
 		Description: "Get all permissions assigned to a specific group (requires authentication)",
-		Tags:        []string{"Group Permissions"},
+		Tags:        []string{"Groups / Permissions"},
+		Extensions:  apidocs.RequiresPermission("groups:view:all"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, m.listGroupPermissions)
 
@@ -340,23 +867,23 @@ func (m *Module) deleteGroup(ctx context.Context, input *dto.DeleteGroupInput) (
 }
 
 func (m *Module) addMember(ctx context.Context, input *dto.AddMemberInput) (*dto.GroupMembershipOutput, error) {
-	// Validate authentication and admin access
-	user, err := m.middleware.RequireGroupMembershipAccess(ctx, input.Authorization, input.Cookie)
+	// Validate authentication; the service checks membership permission or group management
+	user, err := m.requireAuth(ctx, input.Authorization, input.Cookie)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.service.AddMember(ctx, input, int64(user.CharacterID))
+	return m.service.AddMember(ctx, input, user)
 }
 
 func (m *Module) removeMember(ctx context.Context, input *dto.RemoveMemberInput) (*dto.SuccessOutput, error) {
-	// Validate authentication and admin access
-	_, err := m.middleware.RequireGroupMembershipAccess(ctx, input.Authorization, input.Cookie)
+	// Validate authentication; the service checks membership permission or group management
+	user, err := m.requireAuth(ctx, input.Authorization, input.Cookie)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.service.RemoveMember(ctx, input)
+	return m.service.RemoveMember(ctx, input, user)
 }
 
 func (m *Module) listMembers(ctx context.Context, input *dto.ListMembersInput) (*dto.ListMembersOutput, error) {
@@ -486,3 +1013,483 @@ func (m *Module) checkPermission(ctx context.Context, input *dto.CheckPermission
 
 	return m.service.CheckPermission(ctx, input, int64(user.CharacterID))
 }
+
+func (m *Module) getStandingsStatus(ctx context.Context, input *dto.GetStandingsStatusInput) (*dto.StandingsStatusOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.GetStandingsStatus(ctx)
+}
+
+func (m *Module) importStandingsContacts(ctx context.Context, input *dto.ImportStandingsContactsInput) (*dto.StandingsContactListOutput, error) {
+	// Validate authentication and group management access
+	user, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ImportStandingsContacts(ctx, input, int64(user.CharacterID))
+}
+
+func (m *Module) deleteStandingsContacts(ctx context.Context, input *dto.DeleteStandingsContactsInput) (*dto.SuccessOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.DeleteStandingsContacts(ctx, input)
+}
+
+func (m *Module) reconcileStandings(ctx context.Context, input *dto.ReconcileStandingsInput) (*dto.StandingsSyncReportOutput, error) {
+	// Validate authentication and group management access
+	user, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	triggeredBy := int64(user.CharacterID)
+	report, err := m.service.ReconcileStandingsGroups(ctx, input.DryRun, &triggeredBy)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to reconcile standings groups", err)
+	}
+
+	return &dto.StandingsSyncReportOutput{Body: services.StandingsReportToResponse(report)}, nil
+}
+
+func (m *Module) listMembershipRules(ctx context.Context, input *dto.ListMembershipRulesInput) (*dto.ListMembershipRulesOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ListMembershipRules(ctx, input)
+}
+
+func (m *Module) createMembershipRule(ctx context.Context, input *dto.CreateMembershipRuleInput) (*dto.MembershipRuleOutput, error) {
+	// Validate authentication and group management access
+	user, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.CreateMembershipRule(ctx, input, int64(user.CharacterID))
+}
+
+func (m *Module) updateMembershipRule(ctx context.Context, input *dto.UpdateMembershipRuleInput) (*dto.MembershipRuleOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.UpdateMembershipRule(ctx, input)
+}
+
+func (m *Module) deleteMembershipRule(ctx context.Context, input *dto.DeleteMembershipRuleInput) (*dto.SuccessOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.DeleteMembershipRule(ctx, input)
+}
+
+func (m *Module) reconcileMembershipRules(ctx context.Context, input *dto.ReconcileMembershipRulesInput) (*dto.MembershipRulesReportOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := m.service.ReconcileMembershipRules(ctx, input.DryRun)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to evaluate membership rules", err)
+	}
+
+	return &dto.MembershipRulesReportOutput{Body: *report}, nil
+}
+
+func (m *Module) listCorporationMappings(ctx context.Context, input *dto.ListCorporationMappingsInput) (*dto.ListCorporationMappingsOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ListCorporationMappings(ctx, input)
+}
+
+func (m *Module) createCorporationMapping(ctx context.Context, input *dto.CreateCorporationMappingInput) (*dto.CorporationMappingOutput, error) {
+	// Validate authentication and group management access
+	user, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.CreateCorporationMapping(ctx, input, int64(user.CharacterID))
+}
+
+func (m *Module) updateCorporationMapping(ctx context.Context, input *dto.UpdateCorporationMappingInput) (*dto.CorporationMappingOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.UpdateCorporationMapping(ctx, input)
+}
+
+func (m *Module) deleteCorporationMapping(ctx context.Context, input *dto.DeleteCorporationMappingInput) (*dto.SuccessOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.DeleteCorporationMapping(ctx, input)
+}
+
+func (m *Module) reconcileCorporationMappings(ctx context.Context, input *dto.ReconcileCorporationMappingsInput) (*dto.CorporationMappingsReportOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := m.service.ReconcileCorporationMappings(ctx, input.DryRun)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to sync corporation mappings", err)
+	}
+
+	return &dto.CorporationMappingsReportOutput{Body: *report}, nil
+}
+
+func (m *Module) revalidateMemberships(ctx context.Context, input *dto.RevalidateMembershipsInput) (*dto.MembershipRevalidationReportOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := m.service.RevalidateMemberships(ctx, input.Action, input.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MembershipRevalidationReportOutput{Body: *report}, nil
+}
+
+func (m *Module) listGroupTemplates(ctx context.Context, input *dto.ListGroupTemplatesInput) (*dto.ListGroupTemplatesOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ListGroupTemplates(ctx)
+}
+
+func (m *Module) createGroupTemplate(ctx context.Context, input *dto.CreateGroupTemplateInput) (*dto.GroupTemplateOutput, error) {
+	// Validate authentication and group management access
+	user, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.CreateGroupTemplate(ctx, input, int64(user.CharacterID))
+}
+
+func (m *Module) updateGroupTemplate(ctx context.Context, input *dto.UpdateGroupTemplateInput) (*dto.GroupTemplateOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.UpdateGroupTemplate(ctx, input)
+}
+
+func (m *Module) deleteGroupTemplate(ctx context.Context, input *dto.DeleteGroupTemplateInput) (*dto.SuccessOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.DeleteGroupTemplate(ctx, input)
+}
+
+func (m *Module) instantiateGroupTemplate(ctx context.Context, input *dto.InstantiateGroupTemplateInput) (*dto.TemplateInstanceOutput, error) {
+	// Validate authentication and group management access
+	user, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.InstantiateGroupTemplate(ctx, input, int64(user.CharacterID))
+}
+
+func (m *Module) listWebhooks(ctx context.Context, input *dto.ListWebhooksInput) (*dto.ListWebhooksOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ListWebhooks(ctx)
+}
+
+func (m *Module) createWebhook(ctx context.Context, input *dto.CreateWebhookInput) (*dto.WebhookOutput, error) {
+	// Validate authentication and group management access
+	user, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.CreateWebhook(ctx, input, int64(user.CharacterID))
+}
+
+func (m *Module) updateWebhook(ctx context.Context, input *dto.UpdateWebhookInput) (*dto.WebhookOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.UpdateWebhook(ctx, input)
+}
+
+func (m *Module) deleteWebhook(ctx context.Context, input *dto.WebhookIDInput) (*dto.SuccessOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.DeleteWebhook(ctx, input)
+}
+
+func (m *Module) testWebhook(ctx context.Context, input *dto.WebhookIDInput) (*dto.WebhookOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.TestWebhook(ctx, input)
+}
+
+func (m *Module) listSnapshots(ctx context.Context, input *dto.ListSnapshotsInput) (*dto.ListSnapshotsOutput, error) {
+	// Validate authentication and audit access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:audit:read")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ListSnapshots(ctx, input)
+}
+
+func (m *Module) createSnapshot(ctx context.Context, input *dto.CreateSnapshotInput) (*dto.SnapshotOutput, error) {
+	// Validate authentication and audit access
+	user, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:audit:read")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.CreateSnapshot(ctx, int64(user.CharacterID))
+}
+
+func (m *Module) getSnapshotAccess(ctx context.Context, input *dto.GetSnapshotAccessInput) (*dto.SnapshotAccessOutput, error) {
+	// Validate authentication and audit access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:audit:read")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.GetSnapshotAccess(ctx, input)
+}
+
+func (m *Module) diffSnapshots(ctx context.Context, input *dto.DiffSnapshotsInput) (*dto.SnapshotDiffOutput, error) {
+	// Validate authentication and audit access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:audit:read")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.DiffSnapshots(ctx, input)
+}
+
+func (m *Module) listJoinableGroups(ctx context.Context, input *dto.ListJoinableGroupsInput) (*dto.ListJoinableGroupsOutput, error) {
+	// Validate authentication
+	if _, err := m.requireAuth(ctx, input.Authorization, input.Cookie); err != nil {
+		return nil, err
+	}
+
+	return m.service.ListJoinableGroups(ctx)
+}
+
+func (m *Module) createJoinRequest(ctx context.Context, input *dto.CreateJoinRequestInput) (*dto.JoinRequestOutput, error) {
+	// Validate authentication
+	user, err := m.requireAuth(ctx, input.Authorization, input.Cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.CreateJoinRequest(ctx, input, user)
+}
+
+func (m *Module) listMyJoinRequests(ctx context.Context, input *dto.ListMyJoinRequestsInput) (*dto.ListJoinRequestsOutput, error) {
+	// Validate authentication
+	user, err := m.requireAuth(ctx, input.Authorization, input.Cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ListMyJoinRequests(ctx, user.UserID)
+}
+
+func (m *Module) listJoinRequests(ctx context.Context, input *dto.ListJoinRequestsInput) (*dto.ListJoinRequestsOutput, error) {
+	// Validate authentication and membership management access
+	_, err := m.middleware.RequireGroupMembershipAccess(ctx, input.Authorization, input.Cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ListJoinRequests(ctx, input)
+}
+
+func (m *Module) approveJoinRequest(ctx context.Context, input *dto.DecideJoinRequestInput) (*dto.JoinRequestOutput, error) {
+	// Validate authentication and membership management access
+	user, err := m.middleware.RequireGroupMembershipAccess(ctx, input.Authorization, input.Cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ApproveJoinRequest(ctx, input, int64(user.CharacterID))
+}
+
+func (m *Module) rejectJoinRequest(ctx context.Context, input *dto.DecideJoinRequestInput) (*dto.JoinRequestOutput, error) {
+	// Validate authentication and membership management access
+	user, err := m.middleware.RequireGroupMembershipAccess(ctx, input.Authorization, input.Cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.RejectJoinRequest(ctx, input, int64(user.CharacterID))
+}
+
+func (m *Module) simulatePermissions(ctx context.Context, input *dto.SimulatePermissionsInput) (*dto.PermissionSimulationOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.SimulatePermissions(ctx, input)
+}
+
+func (m *Module) explainPermission(ctx context.Context, input *dto.ExplainPermissionInput) (*dto.PermissionExplanationOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ExplainPermission(ctx, input)
+}
+
+func (m *Module) listPermissionDenials(ctx context.Context, input *dto.ListPermissionDenialsInput) (*dto.ListPermissionDenialsOutput, error) {
+	// Validate authentication and permission management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:permissions:manage")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ListPermissionDenials(ctx, input)
+}
+
+func (m *Module) createPermissionDenial(ctx context.Context, input *dto.CreatePermissionDenialInput) (*dto.PermissionDenialOutput, error) {
+	// Validate authentication and permission management access
+	user, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:permissions:manage")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.CreatePermissionDenial(ctx, input, int64(user.CharacterID))
+}
+
+func (m *Module) deletePermissionDenial(ctx context.Context, input *dto.DeletePermissionDenialInput) (*dto.MessageOutput, error) {
+	// Validate authentication and permission management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:permissions:manage")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.DeletePermissionDenial(ctx, input)
+}
+
+func (m *Module) exportAudit(ctx context.Context, input *dto.ExportAuditInput) (*huma.StreamResponse, error) {
+	// Validate authentication and audit access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:audit:read")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ExportAuditEvents(ctx, input)
+}
+
+func (m *Module) listManagedGroups(ctx context.Context, input *dto.ListManagedGroupsInput) (*dto.ListManagedGroupsOutput, error) {
+	// Validate authentication
+	user, err := m.requireAuth(ctx, input.Authorization, input.Cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.ListManagedGroups(ctx, user.UserID)
+}
+
+func (m *Module) addGroupManager(ctx context.Context, input *dto.GroupManagerInput) (*dto.GroupOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.AddGroupManager(ctx, input)
+}
+
+func (m *Module) removeGroupManager(ctx context.Context, input *dto.GroupManagerInput) (*dto.GroupOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.RemoveGroupManager(ctx, input)
+}
+
+func (m *Module) setGroupRateLimit(ctx context.Context, input *dto.SetGroupRateLimitInput) (*dto.GroupOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.SetGroupRateLimit(ctx, input)
+}
+
+func (m *Module) removeGroupRateLimit(ctx context.Context, input *dto.RemoveGroupRateLimitInput) (*dto.GroupOutput, error) {
+	// Validate authentication and group management access
+	_, err := m.middleware.RequirePermission(ctx, input.Authorization, input.Cookie, "groups:management:full")
+	if err != nil {
+		return nil, err
+	}
+
+	return m.service.RemoveGroupRateLimit(ctx, input)
+}
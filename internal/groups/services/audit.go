@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/identity"
+)
+
+const (
+	// auditWriteTimeout bounds an audit write, which is detached from the request so a client
+	// hanging up cannot keep a completed change out of the log
+	auditWriteTimeout = 5 * time.Second
+	// auditArchiveBatchSize is how many events are moved to the archive at a time
+	auditArchiveBatchSize = 1000
+	// auditExportFlushEvery is how many exported events are written between flushes
+	auditExportFlushEvery = 500
+	// auditExportWriteWindow is how long the export may take to write the events between two
+	// flushes; each flush pushes the server's write deadline out by it
+	auditExportWriteWindow = time.Minute
+)
+
+// auditCSVHeader lists the columns of CSV audit exports
+var auditCSVHeader = []string{
+	"timestamp", "action", "service", "group_id", "group_name", "character_id", "permission_id", "detail",
+	"actor_user_id", "actor_character_id", "actor_character_name", "impersonator_character_id", "auth_method", "process",
+}
+
+// recordAudit appends an event to the groups audit log, attributed to the identity of ctx.
+// Auditing never fails the change it records; a failed write is logged instead.
+func (s *Service) recordAudit(ctx context.Context, event *models.AuditEvent) {
+	if attribution := identity.AttributionFromContext(ctx); attribution != nil {
+		event.Attribution = *attribution
+	}
+	if event.Service == "" {
+		event.Service = models.AuditServiceGroups
+	}
+	event.Timestamp = time.Now()
+
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+
+	if err := s.repo.InsertAuditEvent(writeCtx, event); err != nil {
+		slog.ErrorContext(ctx, "Failed to record groups audit event",
+			"action", event.Action, "group_name", event.GroupName, "permission_id", event.PermissionID, "error", err)
+	}
+}
+
+// recordGroupAudit records an event about a group
+func (s *Service) recordGroupAudit(ctx context.Context, action string, group *models.Group, event models.AuditEvent) {
+	event.Action = action
+	if group != nil {
+		groupID := group.ID
+		event.GroupID = &groupID
+		event.GroupName = group.Name
+	}
+	if event.PermissionID != "" && s.permissionManager != nil {
+		if permission, exists := s.permissionManager.GetPermission(event.PermissionID); exists {
+			event.Service = permission.Service
+		}
+	}
+	s.recordAudit(ctx, &event)
+}
+
+// ArchiveAuditEvents moves audit events past GROUP_AUDIT_RETENTION_DAYS to the archive collection
+// and deletes archived events past GROUP_AUDIT_ARCHIVE_RETENTION_DAYS (used by the scheduler)
+func (s *Service) ArchiveAuditEvents(ctx context.Context) (archived, purged int64, err error) {
+	now := time.Now()
+
+	if retentionDays := config.GetGroupAuditRetentionDays(); retentionDays > 0 {
+		archived, err = s.repo.ArchiveAuditEventsBefore(ctx, now.AddDate(0, 0, -retentionDays), auditArchiveBatchSize)
+		if err != nil {
+			return archived, 0, err
+		}
+	}
+	if retentionDays := config.GetGroupAuditArchiveRetentionDays(); retentionDays > 0 {
+		purged, err = s.repo.DeleteArchivedAuditEventsBefore(ctx, now.AddDate(0, 0, -retentionDays))
+		if err != nil {
+			return archived, 0, err
+		}
+	}
+
+	if archived > 0 || purged > 0 {
+		slog.Info("Archived groups audit events", "archived", archived, "purged", purged)
+	}
+	return archived, purged, nil
+}
+
+// ExportAuditEvents streams the audit events matching the filters as CSV or JSON Lines, archived
+// events first, oldest first. Events are read from a cursor as they are written, so exports of any
+// size use constant memory. The route is exempt from the request timeout, and the write deadline
+// moves forward with every flush, so an export runs until it is complete or the client hangs up.
+func (s *Service) ExportAuditEvents(ctx context.Context, input *dto.ExportAuditInput) (*huma.StreamResponse, error) {
+	if !input.From.IsZero() && !input.To.IsZero() && !input.From.Before(input.To) {
+		return nil, huma.Error400BadRequest("from must be before to")
+	}
+
+	filter := bson.M{}
+	if input.ActorID != 0 {
+		filter["actor_character_id"] = input.ActorID
+	}
+	if input.Service != "" {
+		filter["service"] = input.Service
+	}
+	timestamp := bson.M{}
+	if !input.From.IsZero() {
+		timestamp["$gte"] = input.From
+	}
+	if !input.To.IsZero() {
+		timestamp["$lt"] = input.To
+	}
+	if len(timestamp) > 0 {
+		filter["timestamp"] = timestamp
+	}
+
+	sources := []bool{false}
+	if input.IncludeArchive {
+		sources = []bool{true, false}
+	}
+
+	return &huma.StreamResponse{
+		Body: func(hctx huma.Context) {
+			contentType, extension := "application/x-ndjson", "jsonl"
+			if input.Format == "csv" {
+				contentType, extension = "text/csv; charset=utf-8", "csv"
+			}
+			hctx.SetHeader("Content-Type", contentType)
+			hctx.SetHeader("Content-Disposition",
+				fmt.Sprintf(`attachment; filename="groups-audit-%s.%s"`, time.Now().UTC().Format("20060102-150405"), extension))
+
+			exported, err := s.writeAuditExport(hctx.Context(), hctx.BodyWriter(), input.Format, filter, sources)
+			if err != nil {
+				// Headers are gone by now; the truncated file is all the client gets
+				slog.Error("Groups audit export failed", "exported", exported, "error", err)
+				return
+			}
+			slog.Info("Exported groups audit events", "format", input.Format, "events", exported)
+		},
+	}, nil
+}
+
+// writeAuditExport writes the events of each source in turn and returns how many were written
+func (s *Service) writeAuditExport(ctx context.Context, w io.Writer, format string, filter bson.M, sources []bool) (int, error) {
+	flusher, _ := w.(http.Flusher)
+	var controller *http.ResponseController
+	if rw, ok := w.(http.ResponseWriter); ok {
+		controller = http.NewResponseController(rw)
+	}
+	extendDeadline := func() {
+		if controller != nil {
+			// Unsupported by some writers; the server's WriteTimeout then still applies
+			_ = controller.SetWriteDeadline(time.Now().Add(auditExportWriteWindow))
+		}
+	}
+	extendDeadline()
+
+	csvWriter := csv.NewWriter(w)
+	encoder := json.NewEncoder(w)
+
+	if format == "csv" {
+		if err := csvWriter.Write(auditCSVHeader); err != nil {
+			return 0, err
+		}
+	}
+
+	exported := 0
+	for _, archive := range sources {
+		cursor, err := s.repo.FindAuditEvents(ctx, filter, archive)
+		if err != nil {
+			return exported, err
+		}
+
+		for cursor.Next(ctx) {
+			var event models.AuditEvent
+			if err := cursor.Decode(&event); err != nil {
+				cursor.Close(ctx)
+				return exported, fmt.Errorf("failed to decode audit event: %w", err)
+			}
+
+			if format == "csv" {
+				err = csvWriter.Write(auditEventCSVRecord(&event))
+			} else {
+				err = encoder.Encode(&event)
+			}
+			if err != nil {
+				cursor.Close(ctx)
+				return exported, err
+			}
+
+			exported++
+			if exported%auditExportFlushEvery == 0 {
+				csvWriter.Flush()
+				if flusher != nil {
+					flusher.Flush()
+				}
+				extendDeadline()
+			}
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return exported, fmt.Errorf("failed to read audit events: %w", err)
+		}
+	}
+
+	csvWriter.Flush()
+	return exported, csvWriter.Error()
+}
+
+func auditEventCSVRecord(event *models.AuditEvent) []string {
+	return []string{
+		event.Timestamp.UTC().Format(time.RFC3339),
+		event.Action,
+		event.Service,
+		hexOrEmpty(event.GroupID),
+		event.GroupName,
+		formatNonZero(event.CharacterID),
+		event.PermissionID,
+		event.Detail,
+		event.ActorUserID,
+		formatNonZero(int64(event.ActorCharacterID)),
+		event.ActorCharacterName,
+		formatNonZero(int64(event.ImpersonatorCharacterID)),
+		string(event.AuthMethod),
+		event.Process,
+	}
+}
+
+func hexOrEmpty(id *primitive.ObjectID) string {
+	if id == nil {
+		return ""
+	}
+	return id.Hex()
+}
+
+func formatNonZero(value int64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatInt(value, 10)
+}
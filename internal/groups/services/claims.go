@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/pkg/permissions"
+)
+
+// GroupSlugsClaim returns the identifiers of the character's active groups for use as a JWT claim.
+// System groups use their system name; other groups use their name (e.g. corp_TICKER).
+func (s *Service) GroupSlugsClaim(ctx context.Context, characterID int64) ([]string, error) {
+	groups, err := s.repo.GetCharacterGroups(ctx, characterID, bson.M{"is_active": true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get character groups: %w", err)
+	}
+
+	slugs := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if group.SystemName != nil && *group.SystemName != "" {
+			slugs = append(slugs, *group.SystemName)
+		} else {
+			slugs = append(slugs, group.Name)
+		}
+	}
+	sort.Strings(slugs)
+
+	return slugs, nil
+}
+
+// PermissionVersionClaim returns a short hash of the character's active group permission grants.
+// It changes whenever the character's effective grants or denials change, so downstream services can
+// detect stale tokens without calling back into falcon.
+func (s *Service) PermissionVersionClaim(ctx context.Context, characterID int64) (string, error) {
+	groups, err := s.repo.GetCharacterGroups(ctx, characterID, bson.M{"is_active": true})
+	if err != nil {
+		return "", fmt.Errorf("failed to get character groups: %w", err)
+	}
+
+	groupIDs := make([]primitive.ObjectID, len(groups))
+	for i, group := range groups {
+		groupIDs[i] = group.ID
+	}
+	// Grants inherited from parent groups count as the character's own
+	if s.permissionManager != nil {
+		if groupIDs, err = s.permissionManager.EffectiveGroupIDs(ctx, groupIDs); err != nil {
+			return "", err
+		}
+	}
+
+	var grants []string
+	if len(groupIDs) > 0 {
+		cursor, err := s.repo.db.Collection("group_permissions").Find(ctx, bson.M{
+			"group_id":  bson.M{"$in": groupIDs},
+			"is_active": true,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to query group permissions: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		var groupPermissions []permissions.GroupPermission
+		if err := cursor.All(ctx, &groupPermissions); err != nil {
+			return "", fmt.Errorf("failed to decode group permissions: %w", err)
+		}
+		for _, gp := range groupPermissions {
+			grant := gp.GroupID.Hex() + ":" + gp.PermissionID
+			// Changing the conditions of a grant changes who it applies to
+			for _, condition := range gp.Conditions {
+				grant += "|" + condition.String()
+			}
+			grants = append(grants, grant)
+		}
+	}
+	for _, group := range groups {
+		// Membership in an admin system group implies permissions not stored as grants
+		if group.SystemName != nil {
+			grants = append(grants, "system:"+*group.SystemName)
+		}
+	}
+	// Denials take permissions away whatever the grants say
+	if s.permissionManager != nil {
+		denials, err := s.permissionManager.DenialsForCharacter(ctx, characterID)
+		if err != nil {
+			return "", err
+		}
+		for _, denial := range denials {
+			grants = append(grants, "deny:"+denial.PermissionID)
+		}
+	}
+	sort.Strings(grants)
+
+	sum := sha256.Sum256([]byte(strings.Join(grants, "\n")))
+	return hex.EncodeToString(sum[:8]), nil
+}
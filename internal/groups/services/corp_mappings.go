@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+	"go-falcon/pkg/evegateway/corporation"
+)
+
+// CorporationMemberSource reads the roles and titles of every member of a corporation from ESI
+type CorporationMemberSource interface {
+	GetCorporationMemberRoles(ctx context.Context, corporationID int, token string) ([]corporation.CorporationMemberRoles, error)
+	GetCorporationMemberTitles(ctx context.Context, corporationID int, token string) ([]corporation.CorporationMemberTitles, error)
+}
+
+// SetCorporationMemberSource sets where corporation mappings read member roles and titles from.
+// Without one, mappings never add members and keep the members they added.
+func (s *Service) SetCorporationMemberSource(source CorporationMemberSource) {
+	s.corporationSource = source
+}
+
+// ListCorporationMappings returns the corporation mappings, optionally of one corporation or group
+func (s *Service) ListCorporationMappings(ctx context.Context, input *dto.ListCorporationMappingsInput) (*dto.ListCorporationMappingsOutput, error) {
+	filter := bson.M{}
+	if input.CorporationID != 0 {
+		filter["corporation_id"] = input.CorporationID
+	}
+	if input.GroupID != "" {
+		groupID, err := primitive.ObjectIDFromHex(input.GroupID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid group ID")
+		}
+		filter["group_id"] = groupID
+	}
+
+	mappings, err := s.repo.ListCorporationMappings(ctx, filter)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list corporation mappings", err)
+	}
+
+	output := &dto.ListCorporationMappingsOutput{}
+	output.Body.Mappings = make([]dto.CorporationMappingResponse, 0, len(mappings))
+	groupNames := make(map[primitive.ObjectID]string)
+	for i := range mappings {
+		name, seen := groupNames[mappings[i].GroupID]
+		if !seen {
+			if group, err := s.repo.GetGroupByID(ctx, mappings[i].GroupID); err == nil && group != nil {
+				name = group.Name
+			}
+			groupNames[mappings[i].GroupID] = name
+		}
+		output.Body.Mappings = append(output.Body.Mappings, corporationMappingToResponse(&mappings[i], name))
+	}
+	return output, nil
+}
+
+// CreateCorporationMapping maps a corporation role or title to a custom group; members holding it
+// join on the next sync
+func (s *Service) CreateCorporationMapping(ctx context.Context, input *dto.CreateCorporationMappingInput, createdBy int64) (*dto.CorporationMappingOutput, error) {
+	body := &input.Body
+	switch body.Kind {
+	case models.CorporationMappingRole:
+		if body.Role == "" || body.TitleID != 0 {
+			return nil, huma.Error400BadRequest("A role mapping needs a role and no title_id")
+		}
+	case models.CorporationMappingTitle:
+		if body.TitleID <= 0 || body.Role != "" {
+			return nil, huma.Error400BadRequest("A title mapping needs a title_id and no role")
+		}
+	default:
+		return nil, huma.Error400BadRequest("kind must be role or title")
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(body.GroupID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid group ID")
+	}
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get group", err)
+	}
+	if group == nil {
+		return nil, huma.Error404NotFound("Group not found")
+	}
+	// Corporation, alliance, standings and system groups have their own membership management
+	if group.Type != models.GroupTypeCustom {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("Corporation mappings can only target custom groups, not %s groups", group.Type))
+	}
+
+	now := time.Now()
+	mapping := &models.CorporationMapping{
+		CorporationID: body.CorporationID,
+		Kind:          body.Kind,
+		Role:          body.Role,
+		TitleID:       body.TitleID,
+		GroupID:       groupID,
+		IsActive:      body.IsActive,
+		CreatedBy:     createdBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	created, err := s.repo.CreateCorporationMapping(ctx, mapping)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to create corporation mapping", err)
+	}
+	if !created {
+		return nil, huma.Error409Conflict("This role or title is already mapped to the group")
+	}
+
+	slog.Info("Created corporation mapping",
+		"mapping_id", mapping.ID.Hex(), "corporation_id", mapping.CorporationID, "kind", mapping.Kind, "group_name", group.Name, "created_by", createdBy)
+	return &dto.CorporationMappingOutput{Body: corporationMappingToResponse(mapping, group.Name)}, nil
+}
+
+// UpdateCorporationMapping enables or disables a mapping; the members of a disabled mapping leave
+// on the next sync
+func (s *Service) UpdateCorporationMapping(ctx context.Context, input *dto.UpdateCorporationMappingInput) (*dto.CorporationMappingOutput, error) {
+	id, err := primitive.ObjectIDFromHex(input.MappingID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid corporation mapping ID")
+	}
+
+	found, err := s.repo.SetCorporationMappingActive(ctx, id, input.Body.IsActive)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to update corporation mapping", err)
+	}
+	if !found {
+		return nil, huma.Error404NotFound("Corporation mapping not found")
+	}
+
+	mappings, err := s.repo.ListCorporationMappings(ctx, bson.M{"_id": id})
+	if err != nil || len(mappings) == 0 {
+		return nil, huma.Error500InternalServerError("Failed to reload corporation mapping", err)
+	}
+	groupName := ""
+	if group, err := s.repo.GetGroupByID(ctx, mappings[0].GroupID); err == nil && group != nil {
+		groupName = group.Name
+	}
+	return &dto.CorporationMappingOutput{Body: corporationMappingToResponse(&mappings[0], groupName)}, nil
+}
+
+// DeleteCorporationMapping removes a mapping; the members it added leave on the next sync unless
+// another mapping of the group matches them
+func (s *Service) DeleteCorporationMapping(ctx context.Context, input *dto.DeleteCorporationMappingInput) (*dto.SuccessOutput, error) {
+	id, err := primitive.ObjectIDFromHex(input.MappingID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid corporation mapping ID")
+	}
+
+	deleted, err := s.repo.DeleteCorporationMapping(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete corporation mapping", err)
+	}
+	if !deleted {
+		return nil, huma.Error404NotFound("Corporation mapping not found")
+	}
+
+	return &dto.SuccessOutput{
+		Body: dto.SuccessResponse{
+			Message: "Corporation mapping deleted successfully",
+		},
+	}, nil
+}
+
+// ReconcileCorporationMappings reads the member roles and titles of every mapped corporation and,
+// unless dryRun is set, adds the registered members holding a mapped role or title to its group and
+// removes the mapping-added members that no longer hold one. Members added by hand are never
+// removed. When a corporation cannot be read with any of its directors' tokens, the mapped
+// memberships of its characters are kept until a later run succeeds.
+func (s *Service) ReconcileCorporationMappings(ctx context.Context, dryRun bool) (*dto.CorporationMappingsReportResponse, error) {
+	report := &dto.CorporationMappingsReportResponse{
+		DryRun:             dryRun,
+		CorporationsFailed: []int64{},
+		Groups:             []dto.MembershipRuleDriftResponse{},
+		StartedAt:          time.Now(),
+	}
+
+	mappings, err := s.repo.ListCorporationMappings(ctx, bson.M{"is_active": true})
+	if err != nil {
+		return nil, err
+	}
+	affiliations, err := s.repo.GetCharacterAffiliations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Mappings = len(mappings)
+
+	byCorporation := make(map[int64][]models.CorporationMapping)
+	expected := make(map[primitive.ObjectID]map[int64]bool)
+	for _, mapping := range mappings {
+		byCorporation[mapping.CorporationID] = append(byCorporation[mapping.CorporationID], mapping)
+		expected[mapping.GroupID] = make(map[int64]bool)
+	}
+	report.Corporations = len(byCorporation)
+
+	// Only registered characters are added, and only those still in the corporation
+	registered := make(map[int64]int64, len(affiliations))
+	for _, affiliation := range affiliations {
+		registered[affiliation.CharacterID] = affiliation.CorporationID
+	}
+
+	failed := make(map[int64]bool)
+	for corporationID, corporationMappings := range byCorporation {
+		holders, err := s.readCorporationMembers(ctx, corporationID, corporationMappings)
+		if err != nil {
+			slog.Warn("Failed to read corporation members for corporation mappings", "corporation_id", corporationID, "error", err)
+			failed[corporationID] = true
+			report.CorporationsFailed = append(report.CorporationsFailed, corporationID)
+			continue
+		}
+		for _, mapping := range corporationMappings {
+			for _, characterID := range holders[corporationMappingKey(&mapping)] {
+				if registered[characterID] == corporationID {
+					expected[mapping.GroupID][characterID] = true
+				}
+			}
+		}
+	}
+	sort.Slice(report.CorporationsFailed, func(i, j int) bool { return report.CorporationsFailed[i] < report.CorporationsFailed[j] })
+
+	// Characters of unreadable corporations keep what they hold
+	undetermined := make(map[int64]bool)
+	for characterID, corporationID := range registered {
+		if failed[corporationID] {
+			undetermined[characterID] = true
+		}
+	}
+
+	// Groups whose last mapping was deleted or disabled still hold mapped members to remove
+	groupIDs, err := s.repo.GetSourcedMembershipGroupIDs(ctx, models.MembershipSourceCorporationMapping, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, groupID := range groupIDs {
+		if _, ok := expected[groupID]; !ok {
+			expected[groupID] = map[int64]bool{}
+		}
+	}
+
+	for groupID, characters := range expected {
+		drift, added, removed, err := s.reconcileSourcedGroup(ctx, models.MembershipSourceCorporationMapping, groupID, characters, undetermined, dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile group %s: %w", groupID.Hex(), err)
+		}
+		if drift == nil {
+			continue
+		}
+		report.Groups = append(report.Groups, *drift)
+		report.Added += added
+		report.Removed += removed
+	}
+	sort.Slice(report.Groups, func(i, j int) bool { return report.Groups[i].GroupName < report.Groups[j].GroupName })
+
+	report.CompletedAt = time.Now()
+	slog.Info("Reconciled corporation mappings",
+		"dry_run", dryRun,
+		"mappings", report.Mappings,
+		"corporations", report.Corporations,
+		"corporations_failed", len(report.CorporationsFailed),
+		"added", report.Added,
+		"removed", report.Removed)
+
+	return report, nil
+}
+
+// SyncCorporationMappings runs a reconciliation for the scheduler
+func (s *Service) SyncCorporationMappings(ctx context.Context) (added, removed int, err error) {
+	report, err := s.ReconcileCorporationMappings(ctx, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	return report.Added, report.Removed, nil
+}
+
+// readCorporationMembers returns the members holding each mapped role or title of a corporation,
+// keyed by corporationMappingKey. Roles and titles are only read when a mapping needs them, trying
+// the token of each character that granted the scope until one is accepted.
+func (s *Service) readCorporationMembers(ctx context.Context, corporationID int64, mappings []models.CorporationMapping) (map[string][]int64, error) {
+	if s.corporationSource == nil {
+		return nil, fmt.Errorf("no ESI corporation source configured")
+	}
+
+	needRoles, needTitles := false, false
+	for _, mapping := range mappings {
+		needRoles = needRoles || mapping.Kind == models.CorporationMappingRole
+		needTitles = needTitles || mapping.Kind == models.CorporationMappingTitle
+	}
+
+	holders := make(map[string][]int64)
+	if needRoles {
+		err := s.withCorporationToken(ctx, corporationID, models.CorporationMembershipScope, func(token string) error {
+			members, err := s.corporationSource.GetCorporationMemberRoles(ctx, int(corporationID), token)
+			if err != nil {
+				return err
+			}
+			for _, member := range members {
+				for _, role := range member.Roles {
+					key := models.CorporationMappingRole + ":" + role
+					holders[key] = append(holders[key], int64(member.CharacterID))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read member roles: %w", err)
+		}
+	}
+	if needTitles {
+		err := s.withCorporationToken(ctx, corporationID, models.CorporationTitlesScope, func(token string) error {
+			members, err := s.corporationSource.GetCorporationMemberTitles(ctx, int(corporationID), token)
+			if err != nil {
+				return err
+			}
+			for _, member := range members {
+				for _, titleID := range member.Titles {
+					key := fmt.Sprintf("%s:%d", models.CorporationMappingTitle, titleID)
+					holders[key] = append(holders[key], int64(member.CharacterID))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read member titles: %w", err)
+		}
+	}
+	return holders, nil
+}
+
+// withCorporationToken calls read with the tokens of the corporation's characters holding a scope
+// until one succeeds; ESI refuses the tokens of characters without the Director role
+func (s *Service) withCorporationToken(ctx context.Context, corporationID int64, scope string, read func(token string) error) error {
+	tokens, err := s.repo.GetCorporationTokens(ctx, corporationID, scope)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("no character of the corporation granted %s", scope)
+	}
+
+	for _, token := range tokens {
+		if err = read(token); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// corporationMappingKey identifies the role or title a mapping matches
+func corporationMappingKey(mapping *models.CorporationMapping) string {
+	if mapping.Kind == models.CorporationMappingTitle {
+		return fmt.Sprintf("%s:%d", models.CorporationMappingTitle, mapping.TitleID)
+	}
+	return models.CorporationMappingRole + ":" + mapping.Role
+}
+
+// corporationMappingToResponse converts a stored mapping to its API shape
+func corporationMappingToResponse(mapping *models.CorporationMapping, groupName string) dto.CorporationMappingResponse {
+	return dto.CorporationMappingResponse{
+		ID:            mapping.ID.Hex(),
+		CorporationID: mapping.CorporationID,
+		Kind:          mapping.Kind,
+		Role:          mapping.Role,
+		TitleID:       mapping.TitleID,
+		GroupID:       mapping.GroupID.Hex(),
+		GroupName:     groupName,
+		IsActive:      mapping.IsActive,
+		CreatedBy:     mapping.CreatedBy,
+		CreatedAt:     mapping.CreatedAt,
+		UpdatedAt:     mapping.UpdatedAt,
+	}
+}
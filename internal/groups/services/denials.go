@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+	"go-falcon/pkg/permissions"
+	"go-falcon/pkg/stepup"
+)
+
+// ListPermissionDenials returns the permission denials, optionally of one permission
+func (s *Service) ListPermissionDenials(ctx context.Context, input *dto.ListPermissionDenialsInput) (*dto.ListPermissionDenialsOutput, error) {
+	if s.permissionManager == nil {
+		return nil, huma.Error500InternalServerError("permission manager not available")
+	}
+
+	denials, err := s.permissionManager.ListDenials(ctx, input.PermissionID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list permission denials", err)
+	}
+
+	output := &dto.ListPermissionDenialsOutput{}
+	output.Body.Denials = make([]dto.PermissionDenialResponse, 0, len(denials))
+	for i := range denials {
+		output.Body.Denials = append(output.Body.Denials, denialToResponse(&denials[i]))
+	}
+	return output, nil
+}
+
+// CreatePermissionDenial withholds a permission from a character, corporation or alliance,
+// overriding any grant its groups give
+func (s *Service) CreatePermissionDenial(ctx context.Context, input *dto.CreatePermissionDenialInput, createdBy int64) (*dto.PermissionDenialOutput, error) {
+	if s.permissionManager == nil {
+		return nil, huma.Error500InternalServerError("permission manager not available")
+	}
+	if _, exists := s.permissionManager.GetPermission(input.Body.PermissionID); !exists {
+		return nil, huma.Error404NotFound("Permission not found: " + input.Body.PermissionID)
+	}
+
+	denial := &permissions.PermissionDenial{
+		PermissionID: input.Body.PermissionID,
+		SubjectType:  input.Body.SubjectType,
+		SubjectID:    input.Body.SubjectID,
+		Reason:       input.Body.Reason,
+		CreatedBy:    createdBy,
+	}
+	if err := s.permissionManager.CreateDenial(ctx, denial); err != nil {
+		if errors.Is(err, permissions.ErrDenialExists) {
+			return nil, huma.Error409Conflict("Permission is already denied to this subject")
+		}
+		return nil, huma.Error500InternalServerError("Failed to create permission denial", err)
+	}
+	s.recordGroupAudit(ctx, models.AuditPermissionDenied, nil, denialAuditEvent(denial))
+
+	return &dto.PermissionDenialOutput{Body: denialToResponse(denial)}, nil
+}
+
+// DeletePermissionDenial lifts a denial. That can hand the permission back to everyone it
+// covered, so it takes a second factor like a grant does.
+func (s *Service) DeletePermissionDenial(ctx context.Context, input *dto.DeletePermissionDenialInput) (*dto.MessageOutput, error) {
+	if s.permissionManager == nil {
+		return nil, huma.Error500InternalServerError("permission manager not available")
+	}
+	id, err := primitive.ObjectIDFromHex(input.DenialID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid denial ID")
+	}
+
+	if err := stepup.RequireSecondFactor(ctx, "permissions-delete-denial"); err != nil {
+		return nil, err
+	}
+
+	denial, err := s.permissionManager.DeleteDenial(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete permission denial", err)
+	}
+	if denial == nil {
+		return nil, huma.Error404NotFound("Permission denial not found")
+	}
+	s.recordGroupAudit(ctx, models.AuditPermissionDenialLifted, nil, denialAuditEvent(denial))
+
+	return &dto.MessageOutput{
+		Body: dto.MessageResponse{Message: "Permission denial removed"},
+	}, nil
+}
+
+// denialAuditEvent describes a denial for the audit log
+func denialAuditEvent(denial *permissions.PermissionDenial) models.AuditEvent {
+	event := models.AuditEvent{
+		PermissionID: denial.PermissionID,
+		Detail:       fmt.Sprintf("%s %d", denial.SubjectType, denial.SubjectID),
+	}
+	if denial.Reason != "" {
+		event.Detail += ": " + denial.Reason
+	}
+	if denial.SubjectType == permissions.DenySubjectCharacter {
+		event.CharacterID = denial.SubjectID
+	}
+	return event
+}
+
+func denialToResponse(denial *permissions.PermissionDenial) dto.PermissionDenialResponse {
+	return dto.PermissionDenialResponse{
+		ID:           denial.ID.Hex(),
+		PermissionID: denial.PermissionID,
+		SubjectType:  denial.SubjectType,
+		SubjectID:    denial.SubjectID,
+		Reason:       denial.Reason,
+		CreatedBy:    denial.CreatedBy,
+		CreatedAt:    denial.CreatedAt,
+	}
+}
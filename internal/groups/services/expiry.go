@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	authModels "go-falcon/internal/auth/models"
+	"go-falcon/internal/groups/models"
+	notificationsDto "go-falcon/internal/notifications/dto"
+	notificationModels "go-falcon/internal/notifications/models"
+	"go-falcon/pkg/config"
+)
+
+// Notifier sends notifications to users; implemented by the notifications service
+type Notifier interface {
+	Send(ctx context.Context, sender *authModels.AuthenticatedUser, input *notificationsDto.SendNotificationInput) (*notificationModels.Operation, error)
+}
+
+// membershipSender is shown as the sender of membership expiry notices
+var membershipSender = &authModels.AuthenticatedUser{UserID: "system", CharacterName: "Group Memberships"}
+
+// SetNotifier sets where membership expiry notices are sent
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// ExpireGroupMemberships notifies the members whose time-limited membership expires within
+// GROUP_MEMBERSHIP_EXPIRY_NOTICE_HOURS, together with whoever added them, and removes the
+// memberships that have expired (used by the scheduler). A failed notice is retried on the next
+// run; removal does not wait for it.
+func (s *Service) ExpireGroupMemberships(ctx context.Context) (notified, removed int, err error) {
+	now := time.Now()
+
+	if s.notifier != nil {
+		notice := time.Duration(config.GetGroupMembershipExpiryNoticeHours()) * time.Hour
+		due, err := s.repo.GetMembershipsExpiringBefore(ctx, now.Add(notice), true)
+		if err != nil {
+			return 0, 0, err
+		}
+		for i := range due {
+			if !due[i].ExpiresAt.After(now) {
+				continue
+			}
+			if err := s.sendExpiryNotice(ctx, &due[i]); err != nil {
+				slog.Warn("Failed to send membership expiry notice",
+					"group_id", due[i].GroupID.Hex(), "character_id", due[i].CharacterID, "error", err)
+				continue
+			}
+			if err := s.repo.MarkMembershipExpiryNotified(ctx, due[i].ID, now); err != nil {
+				return notified, 0, err
+			}
+			notified++
+		}
+	}
+
+	expired, err := s.repo.GetMembershipsExpiringBefore(ctx, now, false)
+	if err != nil {
+		return notified, 0, err
+	}
+	for _, membership := range expired {
+		if err := s.repo.RemoveMembership(ctx, membership.GroupID, membership.CharacterID); err != nil {
+			slog.Error("Failed to remove expired membership",
+				"group_id", membership.GroupID.Hex(), "character_id", membership.CharacterID, "error", err)
+			continue
+		}
+		group, _ := s.repo.GetGroupByID(ctx, membership.GroupID)
+		s.recordGroupAudit(ctx, models.AuditMemberRemoved, group, models.AuditEvent{
+			GroupID:     &membership.GroupID,
+			CharacterID: membership.CharacterID,
+			Detail:      "membership expired",
+		})
+		slog.Info("Removed expired group membership",
+			"group_id", membership.GroupID.Hex(),
+			"character_id", membership.CharacterID,
+			"expired_at", membership.ExpiresAt)
+		removed++
+	}
+
+	return notified, removed, nil
+}
+
+// sendExpiryNotice tells the member's user and the user who added the membership that it ends soon
+func (s *Service) sendExpiryNotice(ctx context.Context, membership *models.GroupMembership) error {
+	group, err := s.repo.GetGroupByID(ctx, membership.GroupID)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		return nil
+	}
+
+	characterIDs := []int64{membership.CharacterID}
+	if membership.AddedBy != nil {
+		characterIDs = append(characterIDs, *membership.AddedBy)
+	}
+	userIDs, err := s.repo.GetUserIDsByCharacterIDs(ctx, characterIDs)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	names, _ := s.repo.GetCharacterNames(ctx, []int64{membership.CharacterID})
+	member := names[membership.CharacterID]
+	if member == "" {
+		member = fmt.Sprintf("Character %d", membership.CharacterID)
+	}
+
+	input := &notificationsDto.SendNotificationInput{}
+	input.Body.Title = fmt.Sprintf("Membership of %s is expiring", group.Name)
+	input.Body.Message = fmt.Sprintf("%s leaves the group %s on %s UTC. Ask a group manager to extend the membership if it is still needed.",
+		member, group.Name, membership.ExpiresAt.UTC().Format("2006-01-02 15:04"))
+	input.Body.Level = "warning"
+	input.Body.Audience = []notificationsDto.AudienceSelectorInput{{Type: "users", UserIDs: userIDs}}
+
+	if _, err := s.notifier.Send(ctx, membershipSender, input); err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"go-falcon/internal/groups/dto"
+)
+
+// ExplainPermission returns the full decision trace of a permission for a character: the admin
+// bypass, the denials reaching it and every assignment through its groups, with who added the
+// membership and who granted the permission
+func (s *Service) ExplainPermission(ctx context.Context, input *dto.ExplainPermissionInput) (*dto.PermissionExplanationOutput, error) {
+	if s.permissionManager == nil {
+		return nil, huma.Error500InternalServerError("permission manager not available")
+	}
+	if _, exists := s.permissionManager.GetPermission(input.PermissionID); !exists {
+		return nil, huma.Error404NotFound("Permission not found: " + input.PermissionID)
+	}
+
+	explanation, err := s.permissionManager.ExplainPermission(ctx, input.CharacterID, input.PermissionID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to explain permission", err)
+	}
+
+	characterIDs := []int64{input.CharacterID}
+	for _, assignment := range explanation.Assignments {
+		if assignment.MembershipAddedBy != nil {
+			characterIDs = append(characterIDs, *assignment.MembershipAddedBy)
+		}
+		if assignment.GrantedBy != nil {
+			characterIDs = append(characterIDs, *assignment.GrantedBy)
+		}
+	}
+	names, _ := s.repo.GetCharacterNames(ctx, characterIDs)
+	nameOf := func(characterID *int64) string {
+		if characterID == nil {
+			return ""
+		}
+		return names[*characterID]
+	}
+
+	response := dto.PermissionExplanationResponse{
+		CharacterID:   explanation.CharacterID,
+		CharacterName: names[explanation.CharacterID],
+		PermissionID:  explanation.PermissionID,
+		Granted:       explanation.Granted,
+		Decision:      explanation.Decision,
+		AdminGroup:    explanation.AdminGroup,
+		Denials:       make([]dto.PermissionDenialResponse, 0, len(explanation.Denials)),
+		Assignments:   make([]dto.PermissionAssignmentTrace, 0, len(explanation.Assignments)),
+	}
+	for i := range explanation.Denials {
+		response.Denials = append(response.Denials, denialToResponse(&explanation.Denials[i]))
+	}
+	for _, assignment := range explanation.Assignments {
+		var unmet []string
+		for _, condition := range assignment.UnmetConditions {
+			unmet = append(unmet, condition.String())
+		}
+		response.Assignments = append(response.Assignments, dto.PermissionAssignmentTrace{
+			GroupID:               assignment.GroupID.Hex(),
+			GroupName:             assignment.GroupName,
+			GroupType:             assignment.GroupType,
+			EVEEntityID:           assignment.EVEEntityID,
+			MembershipSource:      assignment.MembershipSource,
+			MembershipAddedBy:     assignment.MembershipAddedBy,
+			MembershipAddedByName: nameOf(assignment.MembershipAddedBy),
+			MembershipAddedAt:     assignment.MembershipAddedAt,
+			GrantGroupID:          assignment.GrantGroupID.Hex(),
+			GrantGroupName:        assignment.GrantGroupName,
+			Inherited:             assignment.Depth > 0,
+			GrantedBy:             assignment.GrantedBy,
+			GrantedByName:         nameOf(assignment.GrantedBy),
+			GrantedAt:             assignment.GrantedAt,
+			Conditions:            conditionsToDTO(assignment.Conditions),
+			UnmetConditions:       unmet,
+			Applied:               assignment.Applied,
+		})
+	}
+
+	return &dto.PermissionExplanationOutput{Body: response}, nil
+}
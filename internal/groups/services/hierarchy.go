@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/models"
+	"go-falcon/pkg/permissions"
+)
+
+// validateGroupParent checks that a group can be placed under a parent: the parent exists, is not
+// an admin group (those bypass permission checks rather than grant permissions), the group is not
+// one of the parent's ancestors and the hierarchy stays within permissions.MaxGroupDepth levels
+func (s *Service) validateGroupParent(ctx context.Context, group *models.Group, parentID primitive.ObjectID) error {
+	if parentID == group.ID {
+		return fmt.Errorf("a group cannot be its own parent")
+	}
+
+	parent, err := s.repo.GetGroupByID(ctx, parentID)
+	if err != nil {
+		return fmt.Errorf("failed to get parent group: %w", err)
+	}
+	if parent == nil {
+		return fmt.Errorf("parent group not found")
+	}
+	if slices.Contains(models.AdminGroupNames, parent.Name) {
+		return fmt.Errorf("%s cannot be a parent group", parent.Name)
+	}
+
+	// Walk up from the parent; meeting the group again means the new link closes a cycle
+	depth := 1
+	for ancestor := parent; ancestor.ParentID != nil; depth++ {
+		if *ancestor.ParentID == group.ID {
+			return fmt.Errorf("group %s is already an ancestor of %s", group.Name, parent.Name)
+		}
+		if depth > permissions.MaxGroupDepth {
+			return fmt.Errorf("group hierarchy is nested deeper than %d levels", permissions.MaxGroupDepth)
+		}
+		next, err := s.repo.GetGroupByID(ctx, *ancestor.ParentID)
+		if err != nil {
+			return fmt.Errorf("failed to get ancestor group: %w", err)
+		}
+		if next == nil {
+			break
+		}
+		ancestor = next
+	}
+
+	below, err := s.subtreeHeight(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	if depth+below > permissions.MaxGroupDepth {
+		return fmt.Errorf("group hierarchy would be nested deeper than %d levels", permissions.MaxGroupDepth)
+	}
+	return nil
+}
+
+// subtreeHeight returns how many levels of child groups hang below a group
+func (s *Service) subtreeHeight(ctx context.Context, groupID primitive.ObjectID) (int, error) {
+	height := 0
+	frontier := []primitive.ObjectID{groupID}
+	for len(frontier) > 0 && height <= permissions.MaxGroupDepth {
+		children, err := s.repo.GetGroupsByFilter(ctx, bson.M{"parent_id": bson.M{"$in": frontier}})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get child groups: %w", err)
+		}
+		if len(children) == 0 {
+			break
+		}
+		height++
+		frontier = frontier[:0]
+		for _, child := range children {
+			frontier = append(frontier, child.ID)
+		}
+	}
+	return height, nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	authModels "go-falcon/internal/auth/models"
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+)
+
+// ListJoinableGroups returns the active groups users may apply to
+func (s *Service) ListJoinableGroups(ctx context.Context) (*dto.ListJoinableGroupsOutput, error) {
+	groups, err := s.repo.GetGroupsByFilter(ctx, bson.M{
+		"joinable":  true,
+		"is_active": true,
+		"type":      models.GroupTypeCustom,
+	})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list joinable groups", err)
+	}
+
+	output := &dto.ListJoinableGroupsOutput{}
+	output.Body.Groups = make([]dto.GroupResponse, 0, len(groups))
+	for i := range groups {
+		output.Body.Groups = append(output.Body.Groups, *s.modelToGroupResponse(&groups[i], nil))
+	}
+	return output, nil
+}
+
+// CreateJoinRequest applies for a character of the caller's account to join a joinable group
+func (s *Service) CreateJoinRequest(ctx context.Context, input *dto.CreateJoinRequestInput, user *authModels.AuthenticatedUser) (*dto.JoinRequestOutput, error) {
+	groupID, err := primitive.ObjectIDFromHex(input.GroupID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid group ID")
+	}
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get group", err)
+	}
+	if group == nil {
+		return nil, huma.Error404NotFound("Group not found")
+	}
+	if !group.Joinable || !group.IsActive {
+		return nil, huma.Error403Forbidden("This group does not accept join requests")
+	}
+
+	characterID := int64(user.CharacterID)
+	if input.Body.CharacterID != 0 && input.Body.CharacterID != characterID {
+		owned, err := s.repo.GetCharacterIDsByUserID(ctx, user.UserID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get account characters", err)
+		}
+		if !containsCharacter(owned, input.Body.CharacterID) {
+			return nil, huma.Error403Forbidden("Character does not belong to your account")
+		}
+		characterID = input.Body.CharacterID
+	}
+
+	membership, err := s.repo.GetMembership(ctx, groupID, characterID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to check existing membership", err)
+	}
+	if membership != nil && membership.IsActive {
+		return nil, huma.Error409Conflict("Character is already a member of this group")
+	}
+
+	now := time.Now()
+	request := &models.JoinRequest{
+		GroupID:     groupID,
+		CharacterID: characterID,
+		UserID:      user.UserID,
+		Message:     input.Body.Message,
+		Status:      models.JoinRequestPending,
+		History: []models.JoinRequestEvent{{
+			Action:  models.JoinRequestSubmitted,
+			ActorID: int64(user.CharacterID),
+			Reason:  input.Body.Message,
+			At:      now,
+		}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateJoinRequest(ctx, request); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, huma.Error409Conflict("Character already has a pending request for this group")
+		}
+		return nil, huma.Error500InternalServerError("Failed to create join request", err)
+	}
+
+	slog.Info("Join request submitted", "request_id", request.ID.Hex(), "group_name", group.Name, "character_id", characterID)
+	return &dto.JoinRequestOutput{Body: s.joinRequestToResponse(ctx, request, group.Name)}, nil
+}
+
+// ListMyJoinRequests returns the join requests of every character of the caller's account
+func (s *Service) ListMyJoinRequests(ctx context.Context, userID string) (*dto.ListJoinRequestsOutput, error) {
+	requests, total, err := s.repo.ListJoinRequests(ctx, bson.M{"user_id": userID}, 1, 100)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list join requests", err)
+	}
+	return s.joinRequestsToOutput(ctx, requests, total), nil
+}
+
+// ListJoinRequests returns the join requests managers review, pending ones by default
+func (s *Service) ListJoinRequests(ctx context.Context, input *dto.ListJoinRequestsInput) (*dto.ListJoinRequestsOutput, error) {
+	filter := bson.M{"status": input.Status}
+	if input.GroupID != "" {
+		groupID, err := primitive.ObjectIDFromHex(input.GroupID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid group ID")
+		}
+		filter["group_id"] = groupID
+	}
+
+	requests, total, err := s.repo.ListJoinRequests(ctx, filter, input.Page, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list join requests", err)
+	}
+	return s.joinRequestsToOutput(ctx, requests, total), nil
+}
+
+// ApproveJoinRequest approves a pending join request and adds the character to the group
+func (s *Service) ApproveJoinRequest(ctx context.Context, input *dto.DecideJoinRequestInput, decidedBy int64) (*dto.JoinRequestOutput, error) {
+	request, group, err := s.decideJoinRequest(ctx, input, models.JoinRequestApproved, decidedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	membership := &models.GroupMembership{
+		GroupID:     request.GroupID,
+		CharacterID: request.CharacterID,
+		IsActive:    true,
+		AddedBy:     &decidedBy,
+	}
+	if err := s.repo.AddMembership(ctx, membership); err != nil {
+		slog.Error("Approved join request but failed to add member",
+			"request_id", request.ID.Hex(), "character_id", request.CharacterID, "error", err)
+		return nil, huma.Error500InternalServerError("Join request approved but adding the member failed; add them manually", err)
+	}
+	s.recordGroupAudit(ctx, models.AuditMemberAdded, group, models.AuditEvent{
+		CharacterID: request.CharacterID,
+		Detail:      "join request " + request.ID.Hex(),
+	})
+
+	return &dto.JoinRequestOutput{Body: s.joinRequestToResponse(ctx, request, group.Name)}, nil
+}
+
+// RejectJoinRequest rejects a pending join request; the applicant sees the reason
+func (s *Service) RejectJoinRequest(ctx context.Context, input *dto.DecideJoinRequestInput, decidedBy int64) (*dto.JoinRequestOutput, error) {
+	if input.Body.Reason == "" {
+		return nil, huma.Error400BadRequest("A reason is required to reject a join request")
+	}
+
+	request, group, err := s.decideJoinRequest(ctx, input, models.JoinRequestRejected, decidedBy)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.JoinRequestOutput{Body: s.joinRequestToResponse(ctx, request, group.Name)}, nil
+}
+
+// decideJoinRequest records the decision on a pending join request. Only one decision is ever
+// recorded: concurrent approvals and rejections of the same request conflict.
+func (s *Service) decideJoinRequest(ctx context.Context, input *dto.DecideJoinRequestInput, status string, decidedBy int64) (*models.JoinRequest, *models.Group, error) {
+	id, err := primitive.ObjectIDFromHex(input.RequestID)
+	if err != nil {
+		return nil, nil, huma.Error400BadRequest("Invalid join request ID")
+	}
+	existing, err := s.repo.GetJoinRequest(ctx, id)
+	if err != nil {
+		return nil, nil, huma.Error500InternalServerError("Failed to get join request", err)
+	}
+	if existing == nil {
+		return nil, nil, huma.Error404NotFound("Join request not found")
+	}
+	group, err := s.repo.GetGroupByID(ctx, existing.GroupID)
+	if err != nil {
+		return nil, nil, huma.Error500InternalServerError("Failed to get group", err)
+	}
+	if group == nil {
+		return nil, nil, huma.Error404NotFound("Group not found")
+	}
+
+	request, err := s.repo.DecideJoinRequest(ctx, id, status, decidedBy, input.Body.Reason)
+	if err != nil {
+		return nil, nil, huma.Error500InternalServerError("Failed to decide join request", err)
+	}
+	if request == nil {
+		return nil, nil, huma.Error409Conflict("Join request has already been decided")
+	}
+
+	slog.Info("Join request decided",
+		"request_id", request.ID.Hex(),
+		"group_name", group.Name,
+		"character_id", request.CharacterID,
+		"status", status,
+		"decided_by", decidedBy)
+	return request, group, nil
+}
+
+// joinRequestsToOutput builds a list response, looking up group and character names once
+func (s *Service) joinRequestsToOutput(ctx context.Context, requests []models.JoinRequest, total int64) *dto.ListJoinRequestsOutput {
+	output := &dto.ListJoinRequestsOutput{}
+	output.Body.Total = total
+	output.Body.Requests = make([]dto.JoinRequestResponse, 0, len(requests))
+
+	groupNames := make(map[primitive.ObjectID]string)
+	for i := range requests {
+		name, seen := groupNames[requests[i].GroupID]
+		if !seen {
+			if group, err := s.repo.GetGroupByID(ctx, requests[i].GroupID); err == nil && group != nil {
+				name = group.Name
+			}
+			groupNames[requests[i].GroupID] = name
+		}
+		output.Body.Requests = append(output.Body.Requests, s.joinRequestToResponse(ctx, &requests[i], name))
+	}
+	return output
+}
+
+func (s *Service) joinRequestToResponse(ctx context.Context, request *models.JoinRequest, groupName string) dto.JoinRequestResponse {
+	names, _ := s.repo.GetCharacterNames(ctx, []int64{request.CharacterID})
+
+	response := dto.JoinRequestResponse{
+		ID:             request.ID.Hex(),
+		GroupID:        request.GroupID.Hex(),
+		GroupName:      groupName,
+		CharacterID:    request.CharacterID,
+		CharacterName:  names[request.CharacterID],
+		Message:        request.Message,
+		Status:         request.Status,
+		DecidedBy:      request.DecidedBy,
+		DecisionReason: request.DecisionReason,
+		DecidedAt:      request.DecidedAt,
+		History:        make([]dto.JoinRequestEventResponse, 0, len(request.History)),
+		CreatedAt:      request.CreatedAt,
+	}
+	for _, event := range request.History {
+		response.History = append(response.History, dto.JoinRequestEventResponse{
+			Action:  event.Action,
+			ActorID: event.ActorID,
+			Reason:  event.Reason,
+			At:      event.At,
+		})
+	}
+	return response
+}
+
+func containsCharacter(characterIDs []int64, characterID int64) bool {
+	for _, id := range characterIDs {
+		if id == characterID {
+			return true
+		}
+	}
+	return false
+}
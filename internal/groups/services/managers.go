@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	authModels "go-falcon/internal/auth/models"
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+)
+
+// membershipPermissions let their holders change the members of any group
+var membershipPermissions = []string{"groups:memberships:manage", "groups:management:full"}
+
+// authorizeMembershipChange lets the actor add or remove members of a group when they hold a
+// global membership permission or any character of their account manages the group. Enforced
+// here rather than only at the route so every caller of AddMember and RemoveMember is scoped.
+func (s *Service) authorizeMembershipChange(ctx context.Context, group *models.Group, actor *authModels.AuthenticatedUser) error {
+	if actor == nil {
+		return huma.Error401Unauthorized("Authentication required")
+	}
+
+	if s.permissionManager != nil {
+		for _, permissionID := range membershipPermissions {
+			granted, err := s.permissionManager.HasPermission(ctx, int64(actor.CharacterID), permissionID)
+			if err != nil {
+				return huma.Error500InternalServerError("Failed to check permissions", err)
+			}
+			if granted {
+				return nil
+			}
+		}
+	}
+
+	if group.Type == models.GroupTypeCustom && len(group.Managers) > 0 {
+		if containsCharacter(group.Managers, int64(actor.CharacterID)) {
+			return nil
+		}
+		characterIDs, err := s.repo.GetCharacterIDsByUserID(ctx, actor.UserID)
+		if err != nil {
+			return huma.Error500InternalServerError("Failed to get account characters", err)
+		}
+		for _, characterID := range characterIDs {
+			if containsCharacter(group.Managers, characterID) {
+				return nil
+			}
+		}
+	}
+
+	return huma.Error403Forbidden("Changing the members of this group requires groups:memberships:manage or being one of its managers")
+}
+
+// ListManagedGroups returns the groups any character of the user's account manages
+func (s *Service) ListManagedGroups(ctx context.Context, userID string) (*dto.ListManagedGroupsOutput, error) {
+	characterIDs, err := s.repo.GetCharacterIDsByUserID(ctx, userID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get account characters", err)
+	}
+
+	output := &dto.ListManagedGroupsOutput{}
+	output.Body.Groups = []dto.GroupResponse{}
+	if len(characterIDs) == 0 {
+		return output, nil
+	}
+
+	groups, err := s.repo.GetGroupsByFilter(ctx, bson.M{
+		"managers": bson.M{"$in": characterIDs},
+		"type":     models.GroupTypeCustom,
+	})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list managed groups", err)
+	}
+	for i := range groups {
+		memberCount, err := s.repo.GetGroupMemberCount(ctx, groups[i].ID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to count group members", err)
+		}
+		output.Body.Groups = append(output.Body.Groups, *s.modelToGroupResponse(&groups[i], &memberCount))
+	}
+	return output, nil
+}
+
+// AddGroupManager lets a character manage the members of a custom group
+func (s *Service) AddGroupManager(ctx context.Context, input *dto.GroupManagerInput) (*dto.GroupOutput, error) {
+	group, err := s.managedGroup(ctx, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only characters that have signed in belong to an account whose user can act on the group
+	userIDs, err := s.repo.GetUserIDsByCharacterIDs(ctx, []int64{input.CharacterID})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to look up character", err)
+	}
+	if len(userIDs) == 0 {
+		return nil, huma.Error404NotFound("Character not found")
+	}
+
+	added, err := s.repo.AddGroupManager(ctx, group.ID, input.CharacterID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to add group manager", err)
+	}
+	if added {
+		s.recordGroupAudit(ctx, models.AuditManagerAdded, group, models.AuditEvent{CharacterID: input.CharacterID})
+		slog.Info("Added group manager", "group_name", group.Name, "character_id", input.CharacterID)
+	}
+
+	return s.reloadGroupOutput(ctx, group.ID)
+}
+
+// RemoveGroupManager stops a character from managing the members of a group
+func (s *Service) RemoveGroupManager(ctx context.Context, input *dto.GroupManagerInput) (*dto.GroupOutput, error) {
+	group, err := s.managedGroup(ctx, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.repo.RemoveGroupManager(ctx, group.ID, input.CharacterID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to remove group manager", err)
+	}
+	if !removed {
+		return nil, huma.Error404NotFound("Character is not a manager of this group")
+	}
+	s.recordGroupAudit(ctx, models.AuditManagerRemoved, group, models.AuditEvent{CharacterID: input.CharacterID})
+	slog.Info("Removed group manager", "group_name", group.Name, "character_id", input.CharacterID)
+
+	return s.reloadGroupOutput(ctx, group.ID)
+}
+
+// managedGroup returns a group that can have managers
+func (s *Service) managedGroup(ctx context.Context, groupIDHex string) (*models.Group, error) {
+	groupID, err := primitive.ObjectIDFromHex(groupIDHex)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid group ID")
+	}
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get group", err)
+	}
+	if group == nil {
+		return nil, huma.Error404NotFound("Group not found")
+	}
+	if group.Type != models.GroupTypeCustom {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("Only custom groups have managers; %s groups are maintained automatically", group.Type))
+	}
+	return group, nil
+}
+
+func (s *Service) reloadGroupOutput(ctx context.Context, groupID primitive.ObjectID) (*dto.GroupOutput, error) {
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil || group == nil {
+		return nil, huma.Error500InternalServerError("Failed to reload group", err)
+	}
+	memberCount, err := s.repo.GetGroupMemberCount(ctx, groupID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to count group members", err)
+	}
+	return s.modelToOutput(group, &memberCount), nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+)
+
+// SetGroupRateLimit attaches a requests-per-minute policy to a group, enforced by the gateway for
+// every user with a character in it
+func (s *Service) SetGroupRateLimit(ctx context.Context, input *dto.SetGroupRateLimitInput) (*dto.GroupOutput, error) {
+	group, err := s.rateLimitedGroup(ctx, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateGroup(ctx, group.ID, bson.M{"rate_limit": input.Body.RequestsPerMinute}); err != nil {
+		return nil, huma.Error500InternalServerError("Failed to set group rate limit", err)
+	}
+	s.recordGroupAudit(ctx, models.AuditGroupUpdated, group, models.AuditEvent{
+		Detail: fmt.Sprintf("rate limit set to %d requests per minute", input.Body.RequestsPerMinute),
+	})
+	slog.Info("Set group rate limit", "group_name", group.Name, "requests_per_minute", input.Body.RequestsPerMinute)
+
+	return s.reloadGroupOutput(ctx, group.ID)
+}
+
+// RemoveGroupRateLimit removes the rate limit policy of a group
+func (s *Service) RemoveGroupRateLimit(ctx context.Context, input *dto.RemoveGroupRateLimitInput) (*dto.GroupOutput, error) {
+	group, err := s.rateLimitedGroup(ctx, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.repo.RemoveGroupRateLimit(ctx, group.ID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to remove group rate limit", err)
+	}
+	if !removed {
+		return nil, huma.Error404NotFound("Group has no rate limit")
+	}
+	s.recordGroupAudit(ctx, models.AuditGroupUpdated, group, models.AuditEvent{Detail: "rate limit removed"})
+	slog.Info("Removed group rate limit", "group_name", group.Name)
+
+	return s.reloadGroupOutput(ctx, group.ID)
+}
+
+// UserRateLimit returns the API requests per minute a user may make: the highest limit of the
+// groups its characters are in, or 0 when none of them has a policy. Used by the gateway's rate
+// limit middleware.
+func (s *Service) UserRateLimit(ctx context.Context, userID string) (int, error) {
+	characterIDs, err := s.repo.GetCharacterIDsByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.GetRateLimitForCharacters(ctx, characterIDs)
+}
+
+func (s *Service) rateLimitedGroup(ctx context.Context, groupIDHex string) (*models.Group, error) {
+	groupID, err := primitive.ObjectIDFromHex(groupIDHex)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid group ID")
+	}
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get group", err)
+	}
+	if group == nil {
+		return nil, huma.Error404NotFound("Group not found")
+	}
+	return group, nil
+}
@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,6 +14,7 @@ import (
 
 	"go-falcon/internal/groups/models"
 	"go-falcon/pkg/database"
+	"go-falcon/pkg/permissions"
 )
 
 // Repository handles database operations for groups
@@ -20,6 +23,23 @@ type Repository struct {
 	groupsCollection      *mongo.Collection
 	membershipsCollection *mongo.Collection
 	charactersCollection  *mongo.Collection
+	standingsCollection   *mongo.Collection
+	standingsReports      *mongo.Collection
+	snapshotsCollection   *mongo.Collection
+	snapshotGroups        *mongo.Collection
+	rulesCollection       *mongo.Collection
+	corporationMappings   *mongo.Collection
+	webhooks              *mongo.Collection
+	templates             *mongo.Collection
+	joinRequests          *mongo.Collection
+	auditCollection       *mongo.Collection
+	auditArchive          *mongo.Collection
+
+	// Drops cached permission decisions when memberships or groups change
+	permissionManager *permissions.PermissionManager
+
+	// Told about every membership a character gains or loses, for webhooks
+	membershipObserver func(ctx context.Context, groupID primitive.ObjectID, characterID int64, added bool)
 }
 
 // NewRepository creates a new repository instance
@@ -29,6 +49,17 @@ func NewRepository(db *database.MongoDB) *Repository {
 		groupsCollection:      db.Database.Collection(models.GroupsCollection),
 		membershipsCollection: db.Database.Collection(models.MembershipsCollection),
 		charactersCollection:  db.Database.Collection("characters"),
+		standingsCollection:   db.Database.Collection(models.StandingsContactsCollection),
+		standingsReports:      db.Database.Collection(models.StandingsReportsCollection),
+		snapshotsCollection:   db.Database.Collection(models.SnapshotsCollection),
+		snapshotGroups:        db.Database.Collection(models.SnapshotGroupsCollection),
+		rulesCollection:       db.Database.Collection(models.MembershipRulesCollection),
+		corporationMappings:   db.Database.Collection(models.CorporationMappingsCollection),
+		webhooks:              db.Database.Collection(models.WebhooksCollection),
+		templates:             db.Database.Collection(models.TemplatesCollection),
+		joinRequests:          db.Database.Collection(models.JoinRequestsCollection),
+		auditCollection:       db.Database.Collection(models.AuditCollection),
+		auditArchive:          db.Database.Collection(models.AuditArchiveCollection),
 	}
 }
 
@@ -54,6 +85,14 @@ func (r *Repository) CreateIndexes(ctx context.Context) error {
 		{
 			Keys: bson.D{{Key: "is_active", Value: 1}},
 		},
+		{
+			Keys:    bson.D{{Key: "parent_id", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "managers", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	}
 
 	// Membership collection indexes
@@ -83,6 +122,95 @@ func (r *Repository) CreateIndexes(ctx context.Context) error {
 		return fmt.Errorf("failed to create membership indexes: %w", err)
 	}
 
+	// Keep standings reconciliation reports for 30 days
+	reportIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "started_at", Value: -1}},
+		Options: options.Index().SetExpireAfterSeconds(int32((30 * 24 * time.Hour).Seconds())),
+	}
+	if _, err := r.standingsReports.Indexes().CreateOne(ctx, reportIndex); err != nil {
+		return fmt.Errorf("failed to create standings report indexes: %w", err)
+	}
+
+	// Snapshots are looked up by date, their groups by snapshot and permission
+	if _, err := r.snapshotsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "taken_at", Value: -1}},
+	}); err != nil {
+		return fmt.Errorf("failed to create snapshot indexes: %w", err)
+	}
+	snapshotGroupIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "snapshot_id", Value: 1}, {Key: "group_id", Value: 1}}},
+		{Keys: bson.D{{Key: "snapshot_id", Value: 1}, {Key: "permissions", Value: 1}}},
+		{Keys: bson.D{{Key: "taken_at", Value: 1}}},
+	}
+	if _, err := r.snapshotGroups.Indexes().CreateMany(ctx, snapshotGroupIndexes); err != nil {
+		return fmt.Errorf("failed to create snapshot group indexes: %w", err)
+	}
+
+	// Membership rules are listed per group; rule-added memberships are found by source
+	if _, err := r.rulesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "group_id", Value: 1}},
+	}); err != nil {
+		return fmt.Errorf("failed to create membership rule indexes: %w", err)
+	}
+	// A role or title maps to a group once; mappings are read per corporation
+	if _, err := r.corporationMappings.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "corporation_id", Value: 1},
+			{Key: "kind", Value: 1},
+			{Key: "role", Value: 1},
+			{Key: "title_id", Value: 1},
+			{Key: "group_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return fmt.Errorf("failed to create corporation mapping indexes: %w", err)
+	}
+	if _, err := r.templates.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return fmt.Errorf("failed to create group template indexes: %w", err)
+	}
+	if _, err := r.membershipsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}); err != nil {
+		return fmt.Errorf("failed to create membership expiry index: %w", err)
+	}
+	if _, err := r.membershipsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "source", Value: 1}, {Key: "group_id", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}); err != nil {
+		return fmt.Errorf("failed to create membership source index: %w", err)
+	}
+
+	// A character has at most one pending join request per group
+	joinRequestIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "group_id", Value: 1}, {Key: "character_id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": models.JoinRequestPending}),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}
+	if _, err := r.joinRequests.Indexes().CreateMany(ctx, joinRequestIndexes); err != nil {
+		return fmt.Errorf("failed to create join request indexes: %w", err)
+	}
+
+	// Audit events are exported by time range, optionally narrowed to an actor or a service, in
+	// both the live and the archive collection
+	auditIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "timestamp", Value: 1}}},
+		{Keys: bson.D{{Key: "actor_character_id", Value: 1}, {Key: "timestamp", Value: 1}}},
+		{Keys: bson.D{{Key: "service", Value: 1}, {Key: "timestamp", Value: 1}}},
+	}
+	for _, collection := range []*mongo.Collection{r.auditCollection, r.auditArchive} {
+		if _, err := collection.Indexes().CreateMany(ctx, auditIndexes); err != nil {
+			return fmt.Errorf("failed to create audit indexes on %s: %w", collection.Name(), err)
+		}
+	}
+
 	return nil
 }
 
@@ -197,9 +325,86 @@ func (r *Repository) UpdateGroup(ctx context.Context, id primitive.ObjectID, upd
 		return fmt.Errorf("group not found")
 	}
 
+	r.invalidateDecisions(ctx)
 	return nil
 }
 
+// RemoveGroupRateLimit removes the rate limit policy of a group and reports whether it had one
+func (r *Repository) RemoveGroupRateLimit(ctx context.Context, groupID primitive.ObjectID) (bool, error) {
+	result, err := r.groupsCollection.UpdateOne(ctx, bson.M{"_id": groupID, "rate_limit": bson.M{"$gt": 0}}, bson.M{
+		"$unset": bson.M{"rate_limit": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to remove group rate limit: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// GetRateLimitForCharacters returns the highest rate limit of the active groups the characters are
+// active members of, or 0 when none of them has a policy
+func (r *Repository) GetRateLimitForCharacters(ctx context.Context, characterIDs []int64) (int, error) {
+	if len(characterIDs) == 0 {
+		return 0, nil
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"character_id": bson.M{"$in": characterIDs}, "is_active": true}},
+		{"$lookup": bson.M{
+			"from":         models.GroupsCollection,
+			"localField":   "group_id",
+			"foreignField": "_id",
+			"as":           "group",
+		}},
+		{"$unwind": "$group"},
+		{"$match": bson.M{"group.is_active": true, "group.rate_limit": bson.M{"$gt": 0}}},
+		{"$group": bson.M{"_id": nil, "rate_limit": bson.M{"$max": "$group.rate_limit"}}},
+	}
+
+	cursor, err := r.membershipsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rate limit: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var result struct {
+		RateLimit int `bson:"rate_limit"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return 0, fmt.Errorf("failed to decode rate limit: %w", err)
+		}
+	}
+	return result.RateLimit, cursor.Err()
+}
+
+// AddGroupManager makes a character a manager of a group and reports whether it was not one already
+func (r *Repository) AddGroupManager(ctx context.Context, groupID primitive.ObjectID, characterID int64) (bool, error) {
+	result, err := r.groupsCollection.UpdateOne(ctx, bson.M{"_id": groupID}, bson.M{
+		"$addToSet": bson.M{"managers": characterID},
+		"$set":      bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to add group manager: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return false, fmt.Errorf("group not found")
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// RemoveGroupManager removes a manager from a group and reports whether it was one
+func (r *Repository) RemoveGroupManager(ctx context.Context, groupID primitive.ObjectID, characterID int64) (bool, error) {
+	result, err := r.groupsCollection.UpdateOne(ctx, bson.M{"_id": groupID, "managers": characterID}, bson.M{
+		"$pull": bson.M{"managers": characterID},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to remove group manager: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
 // DeleteGroup deletes a group
 func (r *Repository) DeleteGroup(ctx context.Context, id primitive.ObjectID) error {
 	// First, delete all memberships for this group
@@ -207,6 +412,19 @@ func (r *Repository) DeleteGroup(ctx context.Context, id primitive.ObjectID) err
 	if err != nil {
 		return fmt.Errorf("failed to delete group memberships: %w", err)
 	}
+	if _, err := r.joinRequests.DeleteMany(ctx, bson.M{"group_id": id}); err != nil {
+		return fmt.Errorf("failed to delete group join requests: %w", err)
+	}
+	if _, err := r.rulesCollection.DeleteMany(ctx, bson.M{"group_id": id}); err != nil {
+		return fmt.Errorf("failed to delete group membership rules: %w", err)
+	}
+	if _, err := r.corporationMappings.DeleteMany(ctx, bson.M{"group_id": id}); err != nil {
+		return fmt.Errorf("failed to delete group corporation mappings: %w", err)
+	}
+	// Child groups stop inheriting from the deleted group
+	if _, err := r.groupsCollection.UpdateMany(ctx, bson.M{"parent_id": id}, bson.M{"$set": bson.M{"parent_id": nil}}); err != nil {
+		return fmt.Errorf("failed to detach child groups: %w", err)
+	}
 
 	// Then delete the group
 	result, err := r.groupsCollection.DeleteOne(ctx, bson.M{"_id": id})
@@ -218,6 +436,7 @@ func (r *Repository) DeleteGroup(ctx context.Context, id primitive.ObjectID) err
 		return fmt.Errorf("group not found")
 	}
 
+	r.invalidateDecisions(ctx)
 	return nil
 }
 
@@ -241,6 +460,25 @@ func (r *Repository) AddMembership(ctx context.Context, membership *models.Group
 			"added_at": now,
 		},
 	}
+	// Re-adding a member replaces the source and expiry of the previous membership
+	unset := bson.M{"expiry_notified_at": ""}
+	if membership.Source != "" {
+		update["$set"].(bson.M)["source"] = membership.Source
+	} else {
+		unset["source"] = ""
+	}
+	if membership.ExpiresAt != nil {
+		update["$set"].(bson.M)["expires_at"] = *membership.ExpiresAt
+	} else {
+		unset["expires_at"] = ""
+	}
+	update["$unset"] = unset
+
+	// Memberships are re-asserted on every sign-in; only a real change drops cached decisions
+	previous, err := r.GetMembership(ctx, membership.GroupID, membership.CharacterID)
+	if err != nil {
+		return err
+	}
 
 	opts := options.Update().SetUpsert(true)
 	result, err := r.membershipsCollection.UpdateOne(ctx, filter, update, opts)
@@ -251,6 +489,13 @@ func (r *Repository) AddMembership(ctx context.Context, membership *models.Group
 	if result.UpsertedID != nil {
 		membership.ID = result.UpsertedID.(primitive.ObjectID)
 	}
+	if previous == nil || previous.IsActive != membership.IsActive {
+		r.invalidateDecisions(ctx)
+	}
+	wasActive := previous != nil && previous.IsActive
+	if wasActive != membership.IsActive {
+		r.observeMembership(ctx, membership.GroupID, membership.CharacterID, membership.IsActive)
+	}
 
 	return nil
 }
@@ -269,9 +514,25 @@ func (r *Repository) RemoveMembership(ctx context.Context, groupID primitive.Obj
 		return fmt.Errorf("membership not found")
 	}
 
+	r.invalidateDecisions(ctx)
+	r.observeMembership(ctx, groupID, characterID, false)
 	return nil
 }
 
+// invalidateDecisions drops the cached permission decisions after a change that can alter them
+func (r *Repository) invalidateDecisions(ctx context.Context) {
+	if r.permissionManager != nil {
+		r.permissionManager.InvalidateDecisions(ctx)
+	}
+}
+
+// observeMembership reports a membership gained or lost to the observer, if any
+func (r *Repository) observeMembership(ctx context.Context, groupID primitive.ObjectID, characterID int64, added bool) {
+	if r.membershipObserver != nil {
+		r.membershipObserver(ctx, groupID, characterID, added)
+	}
+}
+
 // GetMembership retrieves a specific membership
 func (r *Repository) GetMembership(ctx context.Context, groupID primitive.ObjectID, characterID int64) (*models.GroupMembership, error) {
 	var membership models.GroupMembership
@@ -447,6 +708,30 @@ func (r *Repository) GetActiveGroupMemberships(ctx context.Context, groupID prim
 	return memberships, nil
 }
 
+// FlagMembership marks a membership whose character no longer satisfies its group
+func (r *Repository) FlagMembership(ctx context.Context, id primitive.ObjectID, reason string) error {
+	_, err := r.membershipsCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"flagged_at":  time.Now(),
+		"flag_reason": reason,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to flag membership: %w", err)
+	}
+	return nil
+}
+
+// ClearMembershipFlag removes the revalidation flag of a membership
+func (r *Repository) ClearMembershipFlag(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.membershipsCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$unset": bson.M{
+		"flagged_at":  "",
+		"flag_reason": "",
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to clear membership flag: %w", err)
+	}
+	return nil
+}
+
 // GetGroupsByFilter returns all groups matching the provided filter
 func (r *Repository) GetGroupsByFilter(ctx context.Context, filter bson.M) ([]models.Group, error) {
 	cursor, err := r.groupsCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
@@ -494,3 +779,881 @@ func (r *Repository) GetCharacterIDsByUserID(ctx context.Context, userID string)
 
 	return characterIDs, nil
 }
+
+// CharacterAffiliation is the corporation and alliance of a registered character
+type CharacterAffiliation struct {
+	CharacterID   int64 `bson:"character_id"`
+	CorporationID int64 `bson:"corporation_id"`
+	AllianceID    int64 `bson:"alliance_id"`
+}
+
+// GetCharacterAffiliations returns the affiliation of every registered character
+func (r *Repository) GetCharacterAffiliations(ctx context.Context) ([]CharacterAffiliation, error) {
+	userProfilesCollection := r.db.Database.Collection("user_profiles")
+
+	projection := bson.M{"character_id": 1, "corporation_id": 1, "alliance_id": 1}
+	cursor, err := userProfilesCollection.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user profiles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var affiliations []CharacterAffiliation
+	if err := cursor.All(ctx, &affiliations); err != nil {
+		return nil, fmt.Errorf("failed to decode user profiles: %w", err)
+	}
+
+	return affiliations, nil
+}
+
+// UpsertStandingsContacts replaces the imported contact list of an alliance
+func (r *Repository) UpsertStandingsContacts(ctx context.Context, list *models.StandingsContactList) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.standingsCollection.ReplaceOne(ctx, bson.M{"_id": list.AllianceID}, list, opts); err != nil {
+		return fmt.Errorf("failed to save standings contacts: %w", err)
+	}
+	return nil
+}
+
+// DeleteStandingsContacts removes the imported contact list of an alliance
+func (r *Repository) DeleteStandingsContacts(ctx context.Context, allianceID int64) (bool, error) {
+	result, err := r.standingsCollection.DeleteOne(ctx, bson.M{"_id": allianceID})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete standings contacts: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// GetStandingsContactLists returns every imported alliance contact list
+func (r *Repository) GetStandingsContactLists(ctx context.Context) ([]models.StandingsContactList, error) {
+	cursor, err := r.standingsCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find standings contacts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var lists []models.StandingsContactList
+	if err := cursor.All(ctx, &lists); err != nil {
+		return nil, fmt.Errorf("failed to decode standings contacts: %w", err)
+	}
+
+	return lists, nil
+}
+
+// CreateStandingsReport stores the result of a standings reconciliation run
+func (r *Repository) CreateStandingsReport(ctx context.Context, report *models.StandingsSyncReport) error {
+	result, err := r.standingsReports.InsertOne(ctx, report)
+	if err != nil {
+		return fmt.Errorf("failed to save standings report: %w", err)
+	}
+
+	report.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetLatestStandingsReport returns the most recent standings reconciliation report
+func (r *Repository) GetLatestStandingsReport(ctx context.Context) (*models.StandingsSyncReport, error) {
+	var report models.StandingsSyncReport
+	opts := options.FindOne().SetSort(bson.D{{Key: "started_at", Value: -1}})
+	if err := r.standingsReports.FindOne(ctx, bson.M{}, opts).Decode(&report); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get standings report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// GetAllActiveMemberships returns the active member character IDs of every group
+func (r *Repository) GetAllActiveMemberships(ctx context.Context) (map[primitive.ObjectID][]int64, error) {
+	projection := bson.M{"group_id": 1, "character_id": 1}
+	cursor, err := r.membershipsCollection.Find(ctx, bson.M{"is_active": true}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find memberships: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	members := make(map[primitive.ObjectID][]int64)
+	for cursor.Next(ctx) {
+		var membership models.GroupMembership
+		if err := cursor.Decode(&membership); err != nil {
+			return nil, fmt.Errorf("failed to decode membership: %w", err)
+		}
+		members[membership.GroupID] = append(members[membership.GroupID], membership.CharacterID)
+	}
+
+	return members, cursor.Err()
+}
+
+// GetActiveMembershipGroupIDs returns the groups each of the characters is an active member of
+func (r *Repository) GetActiveMembershipGroupIDs(ctx context.Context, characterIDs []int64) (map[int64][]primitive.ObjectID, error) {
+	filter := bson.M{"character_id": bson.M{"$in": characterIDs}, "is_active": true}
+	projection := bson.M{"group_id": 1, "character_id": 1}
+	cursor, err := r.membershipsCollection.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find memberships: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	memberships := make(map[int64][]primitive.ObjectID)
+	for cursor.Next(ctx) {
+		var membership struct {
+			GroupID     primitive.ObjectID `bson:"group_id"`
+			CharacterID int64              `bson:"character_id"`
+		}
+		if err := cursor.Decode(&membership); err != nil {
+			return nil, fmt.Errorf("failed to decode membership: %w", err)
+		}
+		memberships[membership.CharacterID] = append(memberships[membership.CharacterID], membership.GroupID)
+	}
+
+	return memberships, cursor.Err()
+}
+
+// GetAllActiveGroupPermissions returns the active permission IDs granted to every group
+func (r *Repository) GetAllActiveGroupPermissions(ctx context.Context) (map[primitive.ObjectID][]string, error) {
+	projection := bson.M{"group_id": 1, "permission_id": 1}
+	cursor, err := r.db.Database.Collection("group_permissions").Find(ctx, bson.M{"is_active": true}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find group permissions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	grants := make(map[primitive.ObjectID][]string)
+	for cursor.Next(ctx) {
+		var grant struct {
+			GroupID      primitive.ObjectID `bson:"group_id"`
+			PermissionID string             `bson:"permission_id"`
+		}
+		if err := cursor.Decode(&grant); err != nil {
+			return nil, fmt.Errorf("failed to decode group permission: %w", err)
+		}
+		grants[grant.GroupID] = append(grants[grant.GroupID], grant.PermissionID)
+	}
+
+	return grants, cursor.Err()
+}
+
+// GetConditionalGroupPermissions returns the active grants that carry conditions
+func (r *Repository) GetConditionalGroupPermissions(ctx context.Context) ([]permissions.GroupPermission, error) {
+	cursor, err := r.db.Database.Collection("group_permissions").Find(ctx, bson.M{
+		"is_active":  true,
+		"conditions": bson.M{"$exists": true, "$ne": bson.A{}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find conditional group permissions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var grants []permissions.GroupPermission
+	if err := cursor.All(ctx, &grants); err != nil {
+		return nil, fmt.Errorf("failed to decode group permissions: %w", err)
+	}
+	return grants, nil
+}
+
+// CreateSnapshot stores a snapshot together with the state of its groups
+func (r *Repository) CreateSnapshot(ctx context.Context, snapshot *models.Snapshot, groups []models.SnapshotGroup) error {
+	result, err := r.snapshotsCollection.InsertOne(ctx, snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	snapshot.ID = result.InsertedID.(primitive.ObjectID)
+
+	if len(groups) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(groups))
+	for i := range groups {
+		groups[i].SnapshotID = snapshot.ID
+		docs[i] = groups[i]
+	}
+	if _, err := r.snapshotGroups.InsertMany(ctx, docs); err != nil {
+		// Don't leave a snapshot behind that claims groups it doesn't have
+		r.snapshotsCollection.DeleteOne(ctx, bson.M{"_id": snapshot.ID})
+		return fmt.Errorf("failed to store snapshot groups: %w", err)
+	}
+
+	return nil
+}
+
+// ListSnapshots returns snapshots newest first
+func (r *Repository) ListSnapshots(ctx context.Context, page, limit int) ([]models.Snapshot, int64, error) {
+	total, err := r.snapshotsCollection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count snapshots: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "taken_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.snapshotsCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []models.Snapshot
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode snapshots: %w", err)
+	}
+
+	return snapshots, total, nil
+}
+
+// GetSnapshotAt returns the latest snapshot taken at or before the given time
+func (r *Repository) GetSnapshotAt(ctx context.Context, at time.Time) (*models.Snapshot, error) {
+	var snapshot models.Snapshot
+	opts := options.FindOne().SetSort(bson.D{{Key: "taken_at", Value: -1}})
+	if err := r.snapshotsCollection.FindOne(ctx, bson.M{"taken_at": bson.M{"$lte": at}}, opts).Decode(&snapshot); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// GetSnapshotGroups returns the groups of a snapshot matching the filter
+func (r *Repository) GetSnapshotGroups(ctx context.Context, snapshotID primitive.ObjectID, filter bson.M) ([]models.SnapshotGroup, error) {
+	query := bson.M{"snapshot_id": snapshotID}
+	for key, value := range filter {
+		query[key] = value
+	}
+
+	cursor, err := r.snapshotGroups.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snapshot groups: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []models.SnapshotGroup
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// DeleteSnapshotsBefore removes snapshots taken before the cutoff and returns how many were removed
+func (r *Repository) DeleteSnapshotsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	filter := bson.M{"taken_at": bson.M{"$lt": cutoff}}
+
+	if _, err := r.snapshotGroups.DeleteMany(ctx, filter); err != nil {
+		return 0, fmt.Errorf("failed to delete snapshot groups: %w", err)
+	}
+	result, err := r.snapshotsCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete snapshots: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// ListMembershipRules returns the membership rules matching a filter, oldest first
+func (r *Repository) ListMembershipRules(ctx context.Context, filter bson.M) ([]models.MembershipRule, error) {
+	cursor, err := r.rulesCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list membership rules: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	rules := []models.MembershipRule{}
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode membership rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetMembershipRule returns a membership rule, or nil when it does not exist
+func (r *Repository) GetMembershipRule(ctx context.Context, id primitive.ObjectID) (*models.MembershipRule, error) {
+	var rule models.MembershipRule
+	err := r.rulesCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&rule)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// CreateMembershipRule stores a new membership rule
+func (r *Repository) CreateMembershipRule(ctx context.Context, rule *models.MembershipRule) error {
+	result, err := r.rulesCollection.InsertOne(ctx, rule)
+	if err != nil {
+		return fmt.Errorf("failed to create membership rule: %w", err)
+	}
+	rule.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// ReplaceMembershipRule overwrites a membership rule; false when it does not exist
+func (r *Repository) ReplaceMembershipRule(ctx context.Context, rule *models.MembershipRule) (bool, error) {
+	result, err := r.rulesCollection.ReplaceOne(ctx, bson.M{"_id": rule.ID}, rule)
+	if err != nil {
+		return false, fmt.Errorf("failed to update membership rule: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// DeleteMembershipRule removes a membership rule; false when it does not exist
+func (r *Repository) DeleteMembershipRule(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.rulesCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete membership rule: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// ListCorporationMappings returns the corporation mappings matching a filter, oldest first
+func (r *Repository) ListCorporationMappings(ctx context.Context, filter bson.M) ([]models.CorporationMapping, error) {
+	cursor, err := r.corporationMappings.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corporation mappings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	mappings := []models.CorporationMapping{}
+	if err := cursor.All(ctx, &mappings); err != nil {
+		return nil, fmt.Errorf("failed to decode corporation mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+// CreateCorporationMapping stores a new corporation mapping; false when the same mapping exists
+func (r *Repository) CreateCorporationMapping(ctx context.Context, mapping *models.CorporationMapping) (bool, error) {
+	result, err := r.corporationMappings.InsertOne(ctx, mapping)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create corporation mapping: %w", err)
+	}
+	mapping.ID = result.InsertedID.(primitive.ObjectID)
+	return true, nil
+}
+
+// SetCorporationMappingActive enables or disables a corporation mapping; false when it does not exist
+func (r *Repository) SetCorporationMappingActive(ctx context.Context, id primitive.ObjectID, active bool) (bool, error) {
+	result, err := r.corporationMappings.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"is_active":  active,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		return false, fmt.Errorf("failed to update corporation mapping: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// DeleteCorporationMapping removes a corporation mapping; false when it does not exist
+func (r *Repository) DeleteCorporationMapping(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.corporationMappings.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete corporation mapping: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// ListWebhooks returns the webhooks matching a filter, oldest first
+func (r *Repository) ListWebhooks(ctx context.Context, filter bson.M) ([]models.GroupWebhook, error) {
+	cursor, err := r.webhooks.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []models.GroupWebhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns a webhook, or nil when it does not exist
+func (r *Repository) GetWebhook(ctx context.Context, id primitive.ObjectID) (*models.GroupWebhook, error) {
+	var webhook models.GroupWebhook
+	err := r.webhooks.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+// CreateWebhook stores a new webhook
+func (r *Repository) CreateWebhook(ctx context.Context, webhook *models.GroupWebhook) error {
+	result, err := r.webhooks.InsertOne(ctx, webhook)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	webhook.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// ReplaceWebhook overwrites a webhook; false when it does not exist
+func (r *Repository) ReplaceWebhook(ctx context.Context, webhook *models.GroupWebhook) (bool, error) {
+	result, err := r.webhooks.ReplaceOne(ctx, bson.M{"_id": webhook.ID}, webhook)
+	if err != nil {
+		return false, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// DeleteWebhook removes a webhook; false when it does not exist
+func (r *Repository) DeleteWebhook(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// RecordWebhookDelivery stores the outcome of the latest delivery to a webhook
+func (r *Repository) RecordWebhookDelivery(ctx context.Context, id primitive.ObjectID, statusCode int, deliveryErr error) error {
+	set := bson.M{"last_delivery_at": time.Now(), "last_status_code": statusCode}
+	update := bson.M{"$set": set}
+	if deliveryErr != nil {
+		set["last_error"] = deliveryErr.Error()
+		update["$inc"] = bson.M{"consecutive_failures": 1}
+	} else {
+		set["consecutive_failures"] = 0
+		update["$unset"] = bson.M{"last_error": ""}
+	}
+
+	if _, err := r.webhooks.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListTemplates returns the group templates by name
+func (r *Repository) ListTemplates(ctx context.Context) ([]models.GroupTemplate, error) {
+	cursor, err := r.templates.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group templates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	templates := []models.GroupTemplate{}
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode group templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetTemplate returns a group template, or nil when it does not exist
+func (r *Repository) GetTemplate(ctx context.Context, id primitive.ObjectID) (*models.GroupTemplate, error) {
+	var template models.GroupTemplate
+	err := r.templates.FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group template: %w", err)
+	}
+	return &template, nil
+}
+
+// CreateTemplate stores a new group template; false when the name is taken
+func (r *Repository) CreateTemplate(ctx context.Context, template *models.GroupTemplate) (bool, error) {
+	result, err := r.templates.InsertOne(ctx, template)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create group template: %w", err)
+	}
+	template.ID = result.InsertedID.(primitive.ObjectID)
+	return true, nil
+}
+
+// ReplaceTemplate overwrites a group template; found is false when it does not exist and taken
+// when another template has its name
+func (r *Repository) ReplaceTemplate(ctx context.Context, template *models.GroupTemplate) (found bool, taken bool, err error) {
+	result, err := r.templates.ReplaceOne(ctx, bson.M{"_id": template.ID}, template)
+	if mongo.IsDuplicateKeyError(err) {
+		return true, true, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to update group template: %w", err)
+	}
+	return result.MatchedCount > 0, false, nil
+}
+
+// DeleteTemplate removes a group template; false when it does not exist. Groups created from it
+// are kept.
+func (r *Repository) DeleteTemplate(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.templates.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete group template: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// GetCorporationTokens returns the access tokens of the valid characters of a corporation that
+// granted a scope
+func (r *Repository) GetCorporationTokens(ctx context.Context, corporationID int64, scope string) ([]string, error) {
+	return r.getScopedTokens(ctx, "corporation_id", corporationID, scope)
+}
+
+// GetAllianceTokens returns the access tokens of the valid characters of an alliance that granted
+// a scope
+func (r *Repository) GetAllianceTokens(ctx context.Context, allianceID int64, scope string) ([]string, error) {
+	return r.getScopedTokens(ctx, "alliance_id", allianceID, scope)
+}
+
+// getScopedTokens returns the access tokens of the valid characters whose profile field (their
+// corporation or alliance) is id and that granted a scope
+func (r *Repository) getScopedTokens(ctx context.Context, field string, id int64, scope string) ([]string, error) {
+	filter := bson.M{
+		field:          id,
+		"valid":        true,
+		"access_token": bson.M{"$ne": ""},
+		"scopes":       bson.M{"$regex": regexp.QuoteMeta(scope)},
+	}
+
+	projection := bson.M{"access_token": 1}
+	cursor, err := r.db.Database.Collection("user_profiles").Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user profiles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tokens []string
+	for cursor.Next(ctx) {
+		var profile struct {
+			AccessToken string `bson:"access_token"`
+		}
+		if err := cursor.Decode(&profile); err != nil {
+			continue
+		}
+		tokens = append(tokens, profile.AccessToken)
+	}
+	return tokens, cursor.Err()
+}
+
+// AddSourcedMembership adds a character to a group on behalf of a membership rule or corporation
+// mapping. Existing memberships, including deactivated ones, are left alone; false when nothing was
+// added.
+func (r *Repository) AddSourcedMembership(ctx context.Context, groupID primitive.ObjectID, characterID int64, source string) (bool, error) {
+	now := time.Now()
+	filter := bson.M{"group_id": groupID, "character_id": characterID}
+	update := bson.M{"$setOnInsert": bson.M{
+		"is_active":  true,
+		"source":     source,
+		"added_at":   now,
+		"updated_at": now,
+	}}
+
+	result, err := r.membershipsCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, fmt.Errorf("failed to add %s membership: %w", source, err)
+	}
+	if result.UpsertedCount == 0 {
+		return false, nil
+	}
+	r.invalidateDecisions(ctx)
+	r.observeMembership(ctx, groupID, characterID, true)
+	return true, nil
+}
+
+// RemoveSourcedMembership removes a membership added on behalf of a source; false when the
+// character has no such membership
+func (r *Repository) RemoveSourcedMembership(ctx context.Context, groupID primitive.ObjectID, characterID int64, source string) (bool, error) {
+	result, err := r.membershipsCollection.DeleteOne(ctx, bson.M{
+		"group_id":     groupID,
+		"character_id": characterID,
+		"source":       source,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to remove %s membership: %w", source, err)
+	}
+	if result.DeletedCount == 0 {
+		return false, nil
+	}
+	r.invalidateDecisions(ctx)
+	r.observeMembership(ctx, groupID, characterID, false)
+	return true, nil
+}
+
+// GetSourcedMembershipGroupIDs returns the groups that hold memberships added on behalf of a
+// source, optionally only those of one character (characterID 0 for all)
+func (r *Repository) GetSourcedMembershipGroupIDs(ctx context.Context, source string, characterID int64) ([]primitive.ObjectID, error) {
+	filter := bson.M{"source": source}
+	if characterID != 0 {
+		filter["character_id"] = characterID
+	}
+
+	values, err := r.membershipsCollection.Distinct(ctx, "group_id", filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s membership groups: %w", source, err)
+	}
+
+	groupIDs := make([]primitive.ObjectID, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(primitive.ObjectID); ok {
+			groupIDs = append(groupIDs, id)
+		}
+	}
+	return groupIDs, nil
+}
+
+// GetCorporationRolesTokens returns the access token of every valid character that granted the
+// corporation roles scope, optionally limited to some characters
+func (r *Repository) GetCorporationRolesTokens(ctx context.Context, characterIDs []int64) (map[int64]string, error) {
+	filter := bson.M{
+		"valid":        true,
+		"access_token": bson.M{"$ne": ""},
+		"scopes":       bson.M{"$regex": regexp.QuoteMeta(models.CorporationRolesScope)},
+	}
+	if characterIDs != nil {
+		filter["character_id"] = bson.M{"$in": characterIDs}
+	}
+
+	projection := bson.M{"character_id": 1, "access_token": 1}
+	cursor, err := r.db.Database.Collection("user_profiles").Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user profiles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tokens := make(map[int64]string)
+	for cursor.Next(ctx) {
+		var profile struct {
+			CharacterID int64  `bson:"character_id"`
+			AccessToken string `bson:"access_token"`
+		}
+		if err := cursor.Decode(&profile); err != nil {
+			continue
+		}
+		tokens[profile.CharacterID] = profile.AccessToken
+	}
+	return tokens, cursor.Err()
+}
+
+// GetMembershipsExpiringBefore returns the active memberships expiring before a time; with
+// unnotified set only those whose expiry notice has not been sent yet
+func (r *Repository) GetMembershipsExpiringBefore(ctx context.Context, before time.Time, unnotified bool) ([]models.GroupMembership, error) {
+	filter := bson.M{
+		"is_active":  true,
+		"expires_at": bson.M{"$lte": before},
+	}
+	if unnotified {
+		filter["expiry_notified_at"] = bson.M{"$exists": false}
+	}
+
+	cursor, err := r.membershipsCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expiring memberships: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var memberships []models.GroupMembership
+	if err := cursor.All(ctx, &memberships); err != nil {
+		return nil, fmt.Errorf("failed to decode expiring memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+// MarkMembershipExpiryNotified records that the expiry notice of a membership went out
+func (r *Repository) MarkMembershipExpiryNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	if _, err := r.membershipsCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"expiry_notified_at": at}}); err != nil {
+		return fmt.Errorf("failed to mark membership expiry notified: %w", err)
+	}
+	return nil
+}
+
+// GetUserIDsByCharacterIDs returns the distinct users owning the given characters
+func (r *Repository) GetUserIDsByCharacterIDs(ctx context.Context, characterIDs []int64) ([]string, error) {
+	values, err := r.db.Database.Collection("user_profiles").Distinct(ctx, "user_id", bson.M{"character_id": bson.M{"$in": characterIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user profiles: %w", err)
+	}
+
+	userIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if userID, ok := value.(string); ok && userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+// CreateJoinRequest stores a new join request. A second pending request of the same character for
+// the same group fails with a duplicate key error.
+func (r *Repository) CreateJoinRequest(ctx context.Context, request *models.JoinRequest) error {
+	result, err := r.joinRequests.InsertOne(ctx, request)
+	if err != nil {
+		return err
+	}
+	request.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetJoinRequest returns a join request, or nil when it does not exist
+func (r *Repository) GetJoinRequest(ctx context.Context, id primitive.ObjectID) (*models.JoinRequest, error) {
+	var request models.JoinRequest
+	err := r.joinRequests.FindOne(ctx, bson.M{"_id": id}).Decode(&request)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join request: %w", err)
+	}
+	return &request, nil
+}
+
+// ListJoinRequests returns a page of the join requests matching a filter, newest first
+func (r *Repository) ListJoinRequests(ctx context.Context, filter bson.M, page, limit int) ([]models.JoinRequest, int64, error) {
+	total, err := r.joinRequests.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count join requests: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.joinRequests.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list join requests: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	requests := []models.JoinRequest{}
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode join requests: %w", err)
+	}
+	return requests, total, nil
+}
+
+// DecideJoinRequest approves or rejects a pending join request and appends the decision to its
+// history. Returns the decided request, or nil when it is no longer pending.
+func (r *Repository) DecideJoinRequest(ctx context.Context, id primitive.ObjectID, status string, decidedBy int64, reason string) (*models.JoinRequest, error) {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"status":          status,
+			"decided_by":      decidedBy,
+			"decision_reason": reason,
+			"decided_at":      now,
+			"updated_at":      now,
+		},
+		"$push": bson.M{"history": models.JoinRequestEvent{
+			Action:  status,
+			ActorID: decidedBy,
+			Reason:  reason,
+			At:      now,
+		}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var request models.JoinRequest
+	err := r.joinRequests.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": models.JoinRequestPending}, update, opts).Decode(&request)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decide join request: %w", err)
+	}
+	return &request, nil
+}
+
+// InsertAuditEvent appends an event to the groups audit log
+func (r *Repository) InsertAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	if _, err := r.auditCollection.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+	return nil
+}
+
+// FindAuditEvents returns a cursor over the audit events matching a filter, oldest first, from the
+// live collection or the archive. The caller closes the cursor.
+func (r *Repository) FindAuditEvents(ctx context.Context, filter bson.M, archive bool) (*mongo.Cursor, error) {
+	collection := r.auditCollection
+	if archive {
+		collection = r.auditArchive
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	return cursor, nil
+}
+
+// ArchiveAuditEventsBefore moves audit events older than the cutoff to the archive collection, in
+// batches, and returns how many were moved. Events are copied before they are deleted and keep
+// their IDs, so a run that fails half-way is completed by the next one.
+func (r *Repository) ArchiveAuditEventsBefore(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	filter := bson.M{"timestamp": bson.M{"$lt": cutoff}}
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}).SetLimit(int64(batchSize))
+
+	var moved int64
+	for {
+		cursor, err := r.auditCollection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return moved, fmt.Errorf("failed to read audit events to archive: %w", err)
+		}
+		var events []bson.M
+		if err := cursor.All(ctx, &events); err != nil {
+			return moved, fmt.Errorf("failed to decode audit events to archive: %w", err)
+		}
+		if len(events) == 0 {
+			return moved, nil
+		}
+
+		documents := make([]interface{}, len(events))
+		ids := make([]interface{}, len(events))
+		for i, event := range events {
+			documents[i] = event
+			ids[i] = event["_id"]
+		}
+		_, err = r.auditArchive.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+		if err != nil && !isOnlyDuplicateKeyErrors(err) {
+			return moved, fmt.Errorf("failed to archive audit events: %w", err)
+		}
+
+		result, err := r.auditCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return moved, fmt.Errorf("failed to delete archived audit events: %w", err)
+		}
+		moved += result.DeletedCount
+
+		if len(events) < batchSize {
+			return moved, nil
+		}
+	}
+}
+
+// DeleteArchivedAuditEventsBefore removes archived audit events older than the cutoff and returns
+// how many were removed
+func (r *Repository) DeleteArchivedAuditEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.auditArchive.DeleteMany(ctx, bson.M{"timestamp": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete archived audit events: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// isOnlyDuplicateKeyErrors reports whether every write error of a bulk insert is a duplicate key,
+// i.e. the documents were already there
+func isOnlyDuplicateKeyErrors(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
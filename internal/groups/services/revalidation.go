@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+)
+
+// AffiliationSource reads the current corporation and alliance of characters from ESI
+type AffiliationSource interface {
+	GetCharactersAffiliation(ctx context.Context, characterIDs []int) ([]map[string]interface{}, error)
+}
+
+// SetAffiliationSource sets where membership revalidation reads affiliations from. Without one,
+// revalidation fails and memberships are left alone.
+func (s *Service) SetAffiliationSource(source AffiliationSource) {
+	s.affiliationSource = source
+}
+
+// What revalidation does with members no longer in their group's corporation or alliance
+const (
+	RevalidationFlag   = "flag"
+	RevalidationRemove = "remove"
+)
+
+// affiliationBatchSize is the most characters ESI resolves in one affiliation request
+const affiliationBatchSize = 1000
+
+// affiliation is a character's corporation and alliance as reported by ESI
+type affiliation struct {
+	corporationID int64
+	allianceID    int64
+}
+
+// RevalidateMemberships reads the affiliation of every member of a corporation or alliance group
+// from ESI and flags or, with action remove, removes the members no longer in the group's entity.
+// Flags are cleared from members that are back in it. Characters ESI returns nothing for are left
+// alone, so a failed batch never removes anyone.
+func (s *Service) RevalidateMemberships(ctx context.Context, action string, dryRun bool) (*dto.MembershipRevalidationReportResponse, error) {
+	if action == "" {
+		action = RevalidationFlag
+	}
+	if action != RevalidationFlag && action != RevalidationRemove {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("Unknown revalidation action: %s", action))
+	}
+	if s.affiliationSource == nil {
+		return nil, huma.Error503ServiceUnavailable("No ESI affiliation source configured")
+	}
+
+	report := &dto.MembershipRevalidationReportResponse{
+		DryRun:     dryRun,
+		Action:     action,
+		Mismatches: []dto.RevalidationMismatch{},
+		StartedAt:  time.Now(),
+	}
+
+	groups, err := s.repo.GetGroupsByFilter(ctx, bson.M{
+		"type":          bson.M{"$in": []string{string(models.GroupTypeCorporation), string(models.GroupTypeAlliance)}},
+		"eve_entity_id": bson.M{"$exists": true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity groups: %w", err)
+	}
+
+	members := make(map[int]bool)
+	memberships := make(map[int][]models.GroupMembership, len(groups))
+	for i, group := range groups {
+		if group.EVEEntityID == nil {
+			continue
+		}
+		active, err := s.repo.GetActiveGroupMemberships(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		memberships[i] = active
+		for _, membership := range active {
+			members[int(membership.CharacterID)] = true
+		}
+		report.Groups++
+	}
+
+	affiliations := s.readAffiliations(ctx, members)
+	report.CharactersChecked = len(members)
+	report.CharactersUnresolved = len(members) - len(affiliations)
+
+	for i, group := range groups {
+		for _, membership := range memberships[i] {
+			current, ok := affiliations[membership.CharacterID]
+			if !ok {
+				continue
+			}
+
+			var satisfied bool
+			var reason string
+			if group.Type == models.GroupTypeCorporation {
+				satisfied = current.corporationID == *group.EVEEntityID
+				reason = fmt.Sprintf("no longer in corporation %d", *group.EVEEntityID)
+			} else {
+				satisfied = current.allianceID == *group.EVEEntityID
+				reason = fmt.Sprintf("no longer in alliance %d", *group.EVEEntityID)
+			}
+
+			if satisfied {
+				if membership.FlaggedAt != nil {
+					if !dryRun {
+						if err := s.repo.ClearMembershipFlag(ctx, membership.ID); err != nil {
+							return nil, err
+						}
+					}
+					report.Cleared++
+				}
+				continue
+			}
+
+			report.Mismatches = append(report.Mismatches, dto.RevalidationMismatch{
+				GroupID:       group.ID.Hex(),
+				GroupName:     group.Name,
+				CharacterID:   membership.CharacterID,
+				CorporationID: current.corporationID,
+				AllianceID:    current.allianceID,
+			})
+
+			switch {
+			case action == RevalidationRemove:
+				if !dryRun {
+					if err := s.repo.RemoveMembership(ctx, group.ID, membership.CharacterID); err != nil {
+						slog.Error("Failed to remove revalidated membership",
+							"group_id", group.ID.Hex(), "character_id", membership.CharacterID, "error", err)
+						continue
+					}
+					s.recordGroupAudit(ctx, models.AuditMemberRemoved, &group, models.AuditEvent{
+						GroupID:     &group.ID,
+						CharacterID: membership.CharacterID,
+						Detail:      "revalidation: " + reason,
+					})
+				}
+				report.Removed++
+			case membership.FlaggedAt == nil:
+				if !dryRun {
+					if err := s.repo.FlagMembership(ctx, membership.ID, reason); err != nil {
+						return nil, err
+					}
+				}
+				report.Flagged++
+			}
+		}
+	}
+
+	report.CompletedAt = time.Now()
+	slog.Info("Revalidated group memberships",
+		"dry_run", dryRun,
+		"action", action,
+		"groups", report.Groups,
+		"characters", report.CharactersChecked,
+		"unresolved", report.CharactersUnresolved,
+		"mismatches", len(report.Mismatches),
+		"flagged", report.Flagged,
+		"removed", report.Removed,
+		"cleared", report.Cleared)
+
+	return report, nil
+}
+
+// RunMembershipRevalidation runs a revalidation for the scheduler
+func (s *Service) RunMembershipRevalidation(ctx context.Context, action string) (flagged, removed int, err error) {
+	report, err := s.RevalidateMemberships(ctx, action, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	return report.Flagged, report.Removed, nil
+}
+
+// readAffiliations resolves the characters in batches ESI accepts. A failed batch is logged and
+// its characters are missing from the result.
+func (s *Service) readAffiliations(ctx context.Context, characters map[int]bool) map[int64]affiliation {
+	ids := make([]int, 0, len(characters))
+	for id := range characters {
+		ids = append(ids, id)
+	}
+
+	result := make(map[int64]affiliation, len(ids))
+	for start := 0; start < len(ids); start += affiliationBatchSize {
+		end := min(start+affiliationBatchSize, len(ids))
+		batch, err := s.affiliationSource.GetCharactersAffiliation(ctx, ids[start:end])
+		if err != nil {
+			slog.Warn("Failed to read character affiliations", "characters", end-start, "error", err)
+			continue
+		}
+		for _, entry := range batch {
+			characterID := affiliationID(entry["character_id"])
+			if characterID == 0 {
+				continue
+			}
+			result[characterID] = affiliation{
+				corporationID: affiliationID(entry["corporation_id"]),
+				allianceID:    affiliationID(entry["alliance_id"]),
+			}
+		}
+	}
+	return result
+}
+
+// affiliationID reads an ID of an affiliation entry, which may have been decoded as int or float64
+func affiliationID(value interface{}) int64 {
+	switch id := value.(type) {
+	case int:
+		return int64(id)
+	case int32:
+		return int64(id)
+	case int64:
+		return id
+	case float64:
+		return int64(id)
+	}
+	return 0
+}
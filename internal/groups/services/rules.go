@@ -0,0 +1,494 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+	"go-falcon/pkg/evegateway/character"
+)
+
+// CharacterRolesSource reads the corporation roles of a character from ESI
+type CharacterRolesSource interface {
+	GetCharacterRoles(ctx context.Context, characterID int, token string) (*character.RolesResponse, error)
+}
+
+// SetCharacterRolesSource sets where membership rules read corporation roles from. Without one,
+// rules on roles never add members and keep the members they added.
+func (s *Service) SetCharacterRolesSource(source CharacterRolesSource) {
+	s.rolesSource = source
+}
+
+// ListMembershipRules returns the membership rules, optionally of one group
+func (s *Service) ListMembershipRules(ctx context.Context, input *dto.ListMembershipRulesInput) (*dto.ListMembershipRulesOutput, error) {
+	filter := bson.M{}
+	if input.GroupID != "" {
+		groupID, err := primitive.ObjectIDFromHex(input.GroupID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid group ID")
+		}
+		filter["group_id"] = groupID
+	}
+
+	rules, err := s.repo.ListMembershipRules(ctx, filter)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list membership rules", err)
+	}
+
+	output := &dto.ListMembershipRulesOutput{}
+	output.Body.Rules = make([]dto.MembershipRuleResponse, 0, len(rules))
+	groupNames := make(map[primitive.ObjectID]string)
+	for i := range rules {
+		name, seen := groupNames[rules[i].GroupID]
+		if !seen {
+			if group, err := s.repo.GetGroupByID(ctx, rules[i].GroupID); err == nil && group != nil {
+				name = group.Name
+			}
+			groupNames[rules[i].GroupID] = name
+		}
+		output.Body.Rules = append(output.Body.Rules, membershipRuleToResponse(&rules[i], name))
+	}
+	return output, nil
+}
+
+// CreateMembershipRule adds a rule; matching characters join its group on the next evaluation
+func (s *Service) CreateMembershipRule(ctx context.Context, input *dto.CreateMembershipRuleInput, createdBy int64) (*dto.MembershipRuleOutput, error) {
+	rule, group, err := s.membershipRuleFromBody(ctx, &input.Body)
+	if err != nil {
+		return nil, err
+	}
+	rule.CreatedBy = createdBy
+	rule.CreatedAt = rule.UpdatedAt
+
+	if err := s.repo.CreateMembershipRule(ctx, rule); err != nil {
+		return nil, huma.Error500InternalServerError("Failed to create membership rule", err)
+	}
+
+	slog.Info("Created membership rule", "rule_id", rule.ID.Hex(), "group_name", group.Name, "created_by", createdBy)
+	return &dto.MembershipRuleOutput{Body: membershipRuleToResponse(rule, group.Name)}, nil
+}
+
+// UpdateMembershipRule replaces the conditions of a rule. Characters that stop matching leave the
+// group on the next evaluation.
+func (s *Service) UpdateMembershipRule(ctx context.Context, input *dto.UpdateMembershipRuleInput) (*dto.MembershipRuleOutput, error) {
+	id, err := primitive.ObjectIDFromHex(input.RuleID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid membership rule ID")
+	}
+	existing, err := s.repo.GetMembershipRule(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get membership rule", err)
+	}
+	if existing == nil {
+		return nil, huma.Error404NotFound("Membership rule not found")
+	}
+
+	rule, group, err := s.membershipRuleFromBody(ctx, &input.Body)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = existing.ID
+	rule.CreatedBy = existing.CreatedBy
+	rule.CreatedAt = existing.CreatedAt
+
+	found, err := s.repo.ReplaceMembershipRule(ctx, rule)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to update membership rule", err)
+	}
+	if !found {
+		return nil, huma.Error404NotFound("Membership rule not found")
+	}
+
+	return &dto.MembershipRuleOutput{Body: membershipRuleToResponse(rule, group.Name)}, nil
+}
+
+// DeleteMembershipRule removes a rule; the members it added leave on the next evaluation unless
+// another rule of the group matches them
+func (s *Service) DeleteMembershipRule(ctx context.Context, input *dto.DeleteMembershipRuleInput) (*dto.SuccessOutput, error) {
+	id, err := primitive.ObjectIDFromHex(input.RuleID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid membership rule ID")
+	}
+
+	deleted, err := s.repo.DeleteMembershipRule(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete membership rule", err)
+	}
+	if !deleted {
+		return nil, huma.Error404NotFound("Membership rule not found")
+	}
+
+	return &dto.SuccessOutput{
+		Body: dto.SuccessResponse{
+			Message: "Membership rule deleted successfully",
+		},
+	}, nil
+}
+
+// membershipRuleFromBody validates a rule body against its target group
+func (s *Service) membershipRuleFromBody(ctx context.Context, body *dto.MembershipRuleBody) (*models.MembershipRule, *models.Group, error) {
+	if len(body.CorporationIDs) == 0 && len(body.AllianceIDs) == 0 && len(body.Roles) == 0 {
+		return nil, nil, huma.Error400BadRequest("A membership rule needs at least one corporation, alliance or role")
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(body.GroupID)
+	if err != nil {
+		return nil, nil, huma.Error400BadRequest("Invalid group ID")
+	}
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, nil, huma.Error500InternalServerError("Failed to get group", err)
+	}
+	if group == nil {
+		return nil, nil, huma.Error404NotFound("Group not found")
+	}
+	// Corporation, alliance, standings and system groups have their own membership management
+	if group.Type != models.GroupTypeCustom {
+		return nil, nil, huma.Error400BadRequest(fmt.Sprintf("Membership rules can only target custom groups, not %s groups", group.Type))
+	}
+
+	return &models.MembershipRule{
+		GroupID:        groupID,
+		Name:           body.Name,
+		CorporationIDs: body.CorporationIDs,
+		AllianceIDs:    body.AllianceIDs,
+		Roles:          body.Roles,
+		IsActive:       body.IsActive,
+		UpdatedAt:      time.Now(),
+	}, group, nil
+}
+
+// ReconcileMembershipRules evaluates the active rules against every registered character and, unless
+// dryRun is set, adds the matching characters to the rule groups and removes the rule-added members
+// that no longer match. Members added by hand are never removed. When the ESI roles of a character
+// cannot be read, the memberships it holds through role rules are kept until a later run succeeds.
+func (s *Service) ReconcileMembershipRules(ctx context.Context, dryRun bool) (*dto.MembershipRulesReportResponse, error) {
+	report := &dto.MembershipRulesReportResponse{
+		DryRun:    dryRun,
+		Groups:    []dto.MembershipRuleDriftResponse{},
+		StartedAt: time.Now(),
+	}
+
+	rules, err := s.repo.ListMembershipRules(ctx, bson.M{"is_active": true})
+	if err != nil {
+		return nil, err
+	}
+	affiliations, err := s.repo.GetCharacterAffiliations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Rules = len(rules)
+	report.CharactersEvaluated = len(affiliations)
+
+	roles, err := s.newRoleLookup(ctx, rules, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := make(map[primitive.ObjectID]map[int64]bool)
+	undetermined := make(map[primitive.ObjectID]map[int64]bool)
+	for _, rule := range rules {
+		expected[rule.GroupID] = make(map[int64]bool)
+		undetermined[rule.GroupID] = make(map[int64]bool)
+	}
+	for _, affiliation := range affiliations {
+		for i := range rules {
+			matched, known := roles.matches(ctx, &rules[i], affiliation)
+			if !known {
+				undetermined[rules[i].GroupID][affiliation.CharacterID] = true
+			} else if matched {
+				expected[rules[i].GroupID][affiliation.CharacterID] = true
+			}
+		}
+	}
+	report.RoleLookupsFailed = roles.failed
+
+	// Groups whose last rule was deleted or disabled still hold rule-added members to remove
+	groupIDs, err := s.repo.GetSourcedMembershipGroupIDs(ctx, models.MembershipSourceRule, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, groupID := range groupIDs {
+		if _, ok := expected[groupID]; !ok {
+			expected[groupID] = map[int64]bool{}
+		}
+	}
+
+	for groupID, characters := range expected {
+		drift, added, removed, err := s.reconcileSourcedGroup(ctx, models.MembershipSourceRule, groupID, characters, undetermined[groupID], dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile group %s: %w", groupID.Hex(), err)
+		}
+		if drift == nil {
+			continue
+		}
+		report.Groups = append(report.Groups, *drift)
+		report.Added += added
+		report.Removed += removed
+	}
+	sort.Slice(report.Groups, func(i, j int) bool { return report.Groups[i].GroupName < report.Groups[j].GroupName })
+
+	report.CompletedAt = time.Now()
+	slog.Info("Reconciled membership rules",
+		"dry_run", dryRun,
+		"rules", report.Rules,
+		"characters", report.CharactersEvaluated,
+		"role_lookups_failed", report.RoleLookupsFailed,
+		"added", report.Added,
+		"removed", report.Removed)
+
+	return report, nil
+}
+
+// SyncMembershipRules runs a reconciliation for the scheduler
+func (s *Service) SyncMembershipRules(ctx context.Context) (added, removed int, err error) {
+	report, err := s.ReconcileMembershipRules(ctx, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	return report.Added, report.Removed, nil
+}
+
+// reconcileSourcedGroup diffs one group against the characters its rules or mappings match and
+// applies the diff; only memberships of the source are removed. A nil drift means the group no
+// longer exists.
+func (s *Service) reconcileSourcedGroup(ctx context.Context, source string, groupID primitive.ObjectID, expected, undetermined map[int64]bool, dryRun bool) (*dto.MembershipRuleDriftResponse, int, int, error) {
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil || group == nil {
+		return nil, 0, 0, err
+	}
+
+	memberships, err := s.repo.GetActiveGroupMemberships(ctx, groupID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	drift := &dto.MembershipRuleDriftResponse{
+		GroupID:    groupID.Hex(),
+		GroupName:  group.Name,
+		Expected:   len(expected),
+		Missing:    []int64{},
+		Unexpected: []int64{},
+	}
+
+	current := make(map[int64]bool, len(memberships))
+	for _, membership := range memberships {
+		current[membership.CharacterID] = true
+		if membership.Source == source && !expected[membership.CharacterID] && !undetermined[membership.CharacterID] {
+			drift.Unexpected = append(drift.Unexpected, membership.CharacterID)
+		}
+	}
+	for characterID := range expected {
+		if !current[characterID] {
+			drift.Missing = append(drift.Missing, characterID)
+		}
+	}
+	sort.Slice(drift.Missing, func(i, j int) bool { return drift.Missing[i] < drift.Missing[j] })
+	sort.Slice(drift.Unexpected, func(i, j int) bool { return drift.Unexpected[i] < drift.Unexpected[j] })
+
+	if dryRun {
+		return drift, 0, 0, nil
+	}
+
+	added, removed := 0, 0
+	for _, characterID := range drift.Missing {
+		ok, err := s.repo.AddSourcedMembership(ctx, groupID, characterID, source)
+		if err != nil {
+			slog.Error("Failed to add character to group",
+				"source", source, "character_id", characterID, "group_name", group.Name, "error", err)
+			continue
+		}
+		if ok {
+			added++
+		}
+	}
+	for _, characterID := range drift.Unexpected {
+		ok, err := s.repo.RemoveSourcedMembership(ctx, groupID, characterID, source)
+		if err != nil {
+			slog.Error("Failed to remove character from group",
+				"source", source, "character_id", characterID, "group_name", group.Name, "error", err)
+			continue
+		}
+		if ok {
+			removed++
+		}
+	}
+
+	return drift, added, removed, nil
+}
+
+// applyMembershipRules evaluates the active rules for one character when they sign in or their
+// profile is refreshed
+func (s *Service) applyMembershipRules(ctx context.Context, characterID int64, corporationID, allianceID *int64) error {
+	rules, err := s.repo.ListMembershipRules(ctx, bson.M{"is_active": true})
+	if err != nil {
+		return err
+	}
+	held, err := s.repo.GetSourcedMembershipGroupIDs(ctx, models.MembershipSourceRule, characterID)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 && len(held) == 0 {
+		return nil
+	}
+
+	affiliation := CharacterAffiliation{CharacterID: characterID}
+	if corporationID != nil {
+		affiliation.CorporationID = *corporationID
+	}
+	if allianceID != nil {
+		affiliation.AllianceID = *allianceID
+	}
+
+	roles, err := s.newRoleLookup(ctx, rules, []int64{characterID})
+	if err != nil {
+		return err
+	}
+
+	// true: join, false: leave; groups with an undetermined role rule and no match are left as they are
+	decisions := make(map[primitive.ObjectID]bool)
+	for _, groupID := range held {
+		decisions[groupID] = false
+	}
+	undetermined := make(map[primitive.ObjectID]bool)
+	for i := range rules {
+		matched, known := roles.matches(ctx, &rules[i], affiliation)
+		switch {
+		case matched:
+			decisions[rules[i].GroupID] = true
+		case !known:
+			undetermined[rules[i].GroupID] = true
+		default:
+			if _, ok := decisions[rules[i].GroupID]; !ok {
+				decisions[rules[i].GroupID] = false
+			}
+		}
+	}
+
+	for groupID, join := range decisions {
+		if join {
+			if added, err := s.repo.AddSourcedMembership(ctx, groupID, characterID, models.MembershipSourceRule); err != nil {
+				slog.Error("Failed to add character to rule group", "character_id", characterID, "group_id", groupID.Hex(), "error", err)
+			} else if added {
+				slog.Info("Membership rule added character to group", "character_id", characterID, "group_id", groupID.Hex())
+			}
+			continue
+		}
+		if undetermined[groupID] {
+			continue
+		}
+		if removed, err := s.repo.RemoveSourcedMembership(ctx, groupID, characterID, models.MembershipSourceRule); err != nil {
+			slog.Error("Failed to remove character from rule group", "character_id", characterID, "group_id", groupID.Hex(), "error", err)
+		} else if removed {
+			slog.Info("Membership rule removed character from group", "character_id", characterID, "group_id", groupID.Hex())
+		}
+	}
+
+	return nil
+}
+
+// roleLookup reads corporation roles from ESI at most once per character and evaluation
+type roleLookup struct {
+	source CharacterRolesSource
+	tokens map[int64]string
+	roles  map[int64]map[string]bool // nil value: the lookup failed
+	failed int
+}
+
+// newRoleLookup loads the tokens role rules need, if any of the rules is on roles
+func (s *Service) newRoleLookup(ctx context.Context, rules []models.MembershipRule, characterIDs []int64) (*roleLookup, error) {
+	lookup := &roleLookup{source: s.rolesSource, roles: make(map[int64]map[string]bool)}
+	for _, rule := range rules {
+		if len(rule.Roles) == 0 {
+			continue
+		}
+		tokens, err := s.repo.GetCorporationRolesTokens(ctx, characterIDs)
+		if err != nil {
+			return nil, err
+		}
+		lookup.tokens = tokens
+		break
+	}
+	return lookup, nil
+}
+
+// matches evaluates a rule for a character; known is false when the rule depends on roles that
+// could not be read
+func (l *roleLookup) matches(ctx context.Context, rule *models.MembershipRule, affiliation CharacterAffiliation) (matched, known bool) {
+	if !rule.MatchesAffiliation(affiliation.CorporationID, affiliation.AllianceID) {
+		return false, true
+	}
+	if len(rule.Roles) == 0 {
+		return true, true
+	}
+
+	roles, seen := l.roles[affiliation.CharacterID]
+	if !seen {
+		roles = l.fetch(ctx, affiliation.CharacterID)
+		l.roles[affiliation.CharacterID] = roles
+	}
+	if roles == nil {
+		return false, false
+	}
+	return rule.MatchesRoles(roles), true
+}
+
+// fetch reads the roles of a character; characters that did not grant the roles scope hold none
+func (l *roleLookup) fetch(ctx context.Context, characterID int64) map[string]bool {
+	token, ok := l.tokens[characterID]
+	if !ok {
+		return map[string]bool{}
+	}
+	if l.source == nil {
+		l.failed++
+		return nil
+	}
+
+	response, err := l.source.GetCharacterRoles(ctx, int(characterID), token)
+	if err != nil {
+		slog.Warn("Failed to read corporation roles for membership rules", "character_id", characterID, "error", err)
+		l.failed++
+		return nil
+	}
+
+	roles := make(map[string]bool, len(response.Roles))
+	for _, role := range response.Roles {
+		roles[role] = true
+	}
+	return roles
+}
+
+// membershipRuleToResponse converts a stored rule to its API shape
+func membershipRuleToResponse(rule *models.MembershipRule, groupName string) dto.MembershipRuleResponse {
+	response := dto.MembershipRuleResponse{
+		ID:             rule.ID.Hex(),
+		GroupID:        rule.GroupID.Hex(),
+		GroupName:      groupName,
+		Name:           rule.Name,
+		CorporationIDs: rule.CorporationIDs,
+		AllianceIDs:    rule.AllianceIDs,
+		Roles:          rule.Roles,
+		IsActive:       rule.IsActive,
+		CreatedBy:      rule.CreatedBy,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}
+	if response.CorporationIDs == nil {
+		response.CorporationIDs = []int64{}
+	}
+	if response.AllianceIDs == nil {
+		response.AllianceIDs = []int64{}
+	}
+	if response.Roles == nil {
+		response.Roles = []string{}
+	}
+	return response
+}
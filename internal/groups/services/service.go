@@ -13,11 +13,13 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	authModels "go-falcon/internal/auth/models"
 	"go-falcon/internal/groups/dto"
 	"go-falcon/internal/groups/models"
 	siteSettingsModels "go-falcon/internal/site_settings/models"
 	"go-falcon/pkg/database"
 	"go-falcon/pkg/permissions"
+	"go-falcon/pkg/stepup"
 )
 
 // Service handles business logic for groups
@@ -25,8 +27,19 @@ type Service struct {
 	repo                *Repository
 	siteSettingsService SiteSettingsServiceInterface
 	permissionManager   *permissions.PermissionManager
+	rolesSource         CharacterRolesSource    // ESI corporation roles for membership rules
+	corporationSource   CorporationMemberSource // ESI member roles and titles for corporation mappings
+	notifier            Notifier                // Membership expiry notices
+	affiliationSource   AffiliationSource       // ESI bulk affiliations for membership revalidation
+	contactsSource      AllianceContactSource   // ESI alliance contacts for standings groups
+	webhookSlots        chan struct{}           // Bounds concurrent webhook deliveries
+	membershipListeners []MembershipListener
 }
 
+// MembershipListener is told when a character gains or loses a group membership, whatever the
+// cause. It runs on the path of the change and must hand slow work off.
+type MembershipListener func(ctx context.Context, groupID primitive.ObjectID, characterID int64, added bool)
+
 // Interface to access site settings without circular dependency
 type SiteSettingsServiceInterface interface {
 	GetEnabledCorporations(ctx context.Context) ([]siteSettingsModels.ManagedCorporation, error)
@@ -35,16 +48,33 @@ type SiteSettingsServiceInterface interface {
 
 // NewService creates a new service instance
 func NewService(db *database.MongoDB, siteSettingsService SiteSettingsServiceInterface) *Service {
-	return &Service{
+	service := &Service{
 		repo:                NewRepository(db),
 		siteSettingsService: siteSettingsService,
 		permissionManager:   nil, // Will be set later
+		webhookSlots:        make(chan struct{}, webhookConcurrency),
+	}
+	service.repo.membershipObserver = service.membershipChanged
+	return service
+}
+
+// AddMembershipListener registers a listener for membership changes; listeners are added at startup
+func (s *Service) AddMembershipListener(listener MembershipListener) {
+	s.membershipListeners = append(s.membershipListeners, listener)
+}
+
+// membershipChanged is the repository's membership observer
+func (s *Service) membershipChanged(ctx context.Context, groupID primitive.ObjectID, characterID int64, added bool) {
+	s.emitMembershipWebhook(ctx, groupID, characterID, added)
+	for _, listener := range s.membershipListeners {
+		listener(ctx, groupID, characterID, added)
 	}
 }
 
 // SetPermissionManager sets the permission manager for the service
 func (s *Service) SetPermissionManager(permissionManager *permissions.PermissionManager) {
 	s.permissionManager = permissionManager
+	s.repo.permissionManager = permissionManager
 }
 
 // GetPermissionManager returns the permission manager
@@ -115,12 +145,14 @@ func (s *Service) CreateGroup(ctx context.Context, input *dto.CreateGroupInput,
 		Name:        input.Body.Name,
 		Description: input.Body.Description,
 		Type:        models.GroupType(input.Body.Type),
+		Joinable:    input.Body.Joinable,
 		IsActive:    true,
 	}
 
 	if err := s.repo.CreateGroup(ctx, group); err != nil {
 		return nil, fmt.Errorf("failed to create group: %w", err)
 	}
+	s.recordGroupAudit(ctx, models.AuditGroupCreated, group, models.AuditEvent{})
 
 	return s.modelToOutput(group, nil), nil
 }
@@ -230,6 +262,26 @@ func (s *Service) UpdateGroup(ctx context.Context, input *dto.UpdateGroupInput)
 	if input.Body.IsActive != nil {
 		update["is_active"] = *input.Body.IsActive
 	}
+	if input.Body.ParentID != nil {
+		if *input.Body.ParentID == "" {
+			update["parent_id"] = nil
+		} else {
+			parentID, err := primitive.ObjectIDFromHex(*input.Body.ParentID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parent group ID: %w", err)
+			}
+			if err := s.validateGroupParent(ctx, group, parentID); err != nil {
+				return nil, err
+			}
+			update["parent_id"] = parentID
+		}
+	}
+	if input.Body.Joinable != nil {
+		if *input.Body.Joinable && group.Type != models.GroupTypeCustom {
+			return nil, fmt.Errorf("only custom groups can accept join requests")
+		}
+		update["joinable"] = *input.Body.Joinable
+	}
 
 	if len(update) == 0 {
 		return nil, fmt.Errorf("no fields to update")
@@ -244,6 +296,7 @@ func (s *Service) UpdateGroup(ctx context.Context, input *dto.UpdateGroupInput)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get updated group: %w", err)
 	}
+	s.recordGroupAudit(ctx, models.AuditGroupUpdated, updatedGroup, models.AuditEvent{Detail: "changed " + changedFields(update)})
 
 	// Get member count
 	memberCount, err := s.repo.GetGroupMemberCount(ctx, updatedGroup.ID)
@@ -275,9 +328,23 @@ func (s *Service) DeleteGroup(ctx context.Context, input *dto.DeleteGroupInput)
 		return nil, fmt.Errorf("system groups cannot be deleted")
 	}
 
+	// Deleting a group that still has members needs a recent sign-in
+	memberCount, err := s.repo.GetGroupMemberCount(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count group members: %w", err)
+	}
+	if memberCount > 0 {
+		if err := stepup.Require(ctx, "groups-delete"); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.repo.DeleteGroup(ctx, id); err != nil {
 		return nil, fmt.Errorf("failed to delete group: %w", err)
 	}
+	s.recordGroupAudit(ctx, models.AuditGroupDeleted, group, models.AuditEvent{
+		Detail: fmt.Sprintf("%d members", memberCount),
+	})
 
 	return &dto.SuccessOutput{
 		Body: dto.SuccessResponse{
@@ -286,8 +353,9 @@ func (s *Service) DeleteGroup(ctx context.Context, input *dto.DeleteGroupInput)
 	}, nil
 }
 
-// AddMember adds a character to a group
-func (s *Service) AddMember(ctx context.Context, input *dto.AddMemberInput, addedBy int64) (*dto.GroupMembershipOutput, error) {
+// AddMember adds a character to a group; the actor needs the global membership permission or must
+// manage the group
+func (s *Service) AddMember(ctx context.Context, input *dto.AddMemberInput, actor *authModels.AuthenticatedUser) (*dto.GroupMembershipOutput, error) {
 	groupID, err := primitive.ObjectIDFromHex(input.GroupID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid group ID: %w", err)
@@ -302,32 +370,55 @@ func (s *Service) AddMember(ctx context.Context, input *dto.AddMemberInput, adde
 		return nil, fmt.Errorf("group not found")
 	}
 
+	if err := s.authorizeMembershipChange(ctx, group, actor); err != nil {
+		return nil, err
+	}
+
+	// Standings groups follow the imported contact lists; manual changes would be reverted
+	if group.Type == models.GroupTypeStandings {
+		return nil, fmt.Errorf("standings group memberships are managed by reconciliation")
+	}
+
 	// Check if membership already exists
 	existing, err := s.repo.GetMembership(ctx, groupID, input.Body.CharacterID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing membership: %w", err)
 	}
-	if existing != nil && existing.IsActive {
+	// A member added by a rule or corporation mapping is pinned: the membership becomes manual and
+	// stays when the rule or mapping stops matching
+	if existing != nil && existing.IsActive && existing.Source == "" {
 		return nil, fmt.Errorf("character is already a member of this group")
 	}
 
+	if input.Body.ExpiresAt != nil && !input.Body.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expires_at must be in the future")
+	}
+
 	// Create membership
+	addedBy := int64(actor.CharacterID)
 	membership := &models.GroupMembership{
 		GroupID:     groupID,
 		CharacterID: input.Body.CharacterID,
 		IsActive:    true,
 		AddedBy:     &addedBy,
+		ExpiresAt:   input.Body.ExpiresAt,
 	}
 
 	if err := s.repo.AddMembership(ctx, membership); err != nil {
 		return nil, fmt.Errorf("failed to add membership: %w", err)
 	}
+	auditEvent := models.AuditEvent{CharacterID: membership.CharacterID}
+	if membership.ExpiresAt != nil {
+		auditEvent.Detail = "expires " + membership.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	s.recordGroupAudit(ctx, models.AuditMemberAdded, group, auditEvent)
 
 	return s.membershipModelToOutput(membership), nil
 }
 
-// RemoveMember removes a character from a group
-func (s *Service) RemoveMember(ctx context.Context, input *dto.RemoveMemberInput) (*dto.SuccessOutput, error) {
+// RemoveMember removes a character from a group; the actor needs the global membership permission
+// or must manage the group
+func (s *Service) RemoveMember(ctx context.Context, input *dto.RemoveMemberInput, actor *authModels.AuthenticatedUser) (*dto.SuccessOutput, error) {
 	groupID, err := primitive.ObjectIDFromHex(input.GroupID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid group ID: %w", err)
@@ -346,10 +437,17 @@ func (s *Service) RemoveMember(ctx context.Context, input *dto.RemoveMemberInput
 	if group == nil {
 		return nil, fmt.Errorf("group not found")
 	}
+	if err := s.authorizeMembershipChange(ctx, group, actor); err != nil {
+		return nil, err
+	}
+	if group.Type == models.GroupTypeStandings {
+		return nil, fmt.Errorf("standings group memberships are managed by reconciliation")
+	}
 
 	if err := s.repo.RemoveMembership(ctx, groupID, characterID); err != nil {
 		return nil, fmt.Errorf("failed to remove membership: %w", err)
 	}
+	s.recordGroupAudit(ctx, models.AuditMemberRemoved, group, models.AuditEvent{CharacterID: characterID})
 
 	return &dto.SuccessOutput{
 		Body: dto.SuccessResponse{
@@ -670,6 +768,10 @@ func (s *Service) modelToOutput(group *models.Group, memberCount *int64) *dto.Gr
 			Type:        string(group.Type),
 			SystemName:  group.SystemName,
 			EVEEntityID: group.EVEEntityID,
+			ParentID:    parentIDHex(group.ParentID),
+			Joinable:    group.Joinable,
+			Managers:    group.Managers,
+			RateLimit:   group.RateLimit,
 			IsActive:    group.IsActive,
 			MemberCount: memberCount,
 			CreatedAt:   group.CreatedAt,
@@ -678,6 +780,15 @@ func (s *Service) modelToOutput(group *models.Group, memberCount *int64) *dto.Gr
 	}
 }
 
+// parentIDHex formats the parent of a group for API responses
+func parentIDHex(parentID *primitive.ObjectID) *string {
+	if parentID == nil {
+		return nil
+	}
+	hex := parentID.Hex()
+	return &hex
+}
+
 func (s *Service) modelToGroupResponse(group *models.Group, memberCount *int64) *dto.GroupResponse {
 	return &dto.GroupResponse{
 		ID:          group.ID.Hex(),
@@ -686,6 +797,10 @@ func (s *Service) modelToGroupResponse(group *models.Group, memberCount *int64)
 		Type:        string(group.Type),
 		SystemName:  group.SystemName,
 		EVEEntityID: group.EVEEntityID,
+		ParentID:    parentIDHex(group.ParentID),
+		Joinable:    group.Joinable,
+		Managers:    group.Managers,
+		RateLimit:   group.RateLimit,
 		IsActive:    group.IsActive,
 		MemberCount: memberCount,
 		CreatedAt:   group.CreatedAt,
@@ -702,6 +817,10 @@ func (s *Service) membershipModelToOutput(membership *models.GroupMembership) *d
 			CharacterName: "", // Will be populated by the caller if needed
 			IsActive:      membership.IsActive,
 			AddedBy:       membership.AddedBy,
+			Source:        membership.Source,
+			ExpiresAt:     membership.ExpiresAt,
+			FlaggedAt:     membership.FlaggedAt,
+			FlagReason:    membership.FlagReason,
 			AddedAt:       membership.AddedAt,
 			UpdatedAt:     membership.UpdatedAt,
 		},
@@ -716,6 +835,10 @@ func (s *Service) membershipModelToGroupMembershipResponse(membership *models.Gr
 		CharacterName: "", // Will be populated by the caller
 		IsActive:      membership.IsActive,
 		AddedBy:       membership.AddedBy,
+		Source:        membership.Source,
+		ExpiresAt:     membership.ExpiresAt,
+		FlaggedAt:     membership.FlaggedAt,
+		FlagReason:    membership.FlagReason,
 		AddedAt:       membership.AddedAt,
 		UpdatedAt:     membership.UpdatedAt,
 	}
@@ -738,19 +861,10 @@ func (s *Service) ValidateCharacterMemberships(ctx context.Context, characterID
 	return nil
 }
 
-// CleanupInvalidMemberships removes characters from corp/alliance groups if they're no longer members
-func (s *Service) CleanupInvalidMemberships(ctx context.Context) error {
-	// This would be called by a scheduler task to periodically clean up group memberships
-	slog.Debug("[Groups] Cleaning up invalid memberships",
-		"note", "ESI cleanup not yet implemented - Phase 2")
-
-	// TODO: Implement batch cleanup:
-	// 1. Get all corp/alliance groups
-	// 2. For each group, validate all members via ESI
-	// 3. Remove invalid memberships
-	// 4. Log cleanup results
-
-	return nil
+// InvalidatePermissionDecisions drops the cached permission decisions, e.g. after characters moved
+// to another account: the admin bypass covers every character of an account
+func (s *Service) InvalidatePermissionDecisions(ctx context.Context) {
+	s.repo.invalidateDecisions(ctx)
 }
 
 // EnsureFirstUserSuperAdmin checks if this is the first user and adds them to super_admin group
@@ -807,6 +921,7 @@ func (s *Service) GetStatus(ctx context.Context) *dto.GroupsStatusResponse {
 }
 
 // AutoJoinCharacterToEnabledGroups automatically joins character to corporation/alliance groups if enabled
+// and applies the membership rules of custom groups
 func (s *Service) AutoJoinCharacterToEnabledGroups(ctx context.Context, characterID int64, corporationID, allianceID *int64, scopes string) error {
 	slog.Debug("Auto-joining character to enabled groups",
 		"character_id", characterID,
@@ -883,6 +998,11 @@ func (s *Service) AutoJoinCharacterToEnabledGroups(ctx context.Context, characte
 		}
 	}
 
+	// Custom groups with membership rules follow the character's affiliation and roles
+	if err := s.applyMembershipRules(ctx, characterID, corporationID, allianceID); err != nil {
+		slog.Error("Failed to apply membership rules", "character_id", characterID, "error", err)
+	}
+
 	return nil
 }
 
@@ -1363,10 +1483,19 @@ func (s *Service) GrantPermissionToGroup(ctx context.Context, input *dto.GrantPe
 		return nil, huma.Error404NotFound("group not found", err)
 	}
 
+	// Granting permissions is the usual way to escalate privileges; require a second factor
+	if err := stepup.RequireSecondFactor(ctx, "groups-grant-permission"); err != nil {
+		return nil, err
+	}
+
 	// Grant permission
-	err = s.permissionManager.GrantPermissionToGroup(ctx, groupID, input.Body.PermissionID, grantedBy)
+	conditions := conditionsFromDTO(input.Body.Conditions)
+	err = s.permissionManager.GrantConditionalPermissionToGroup(ctx, groupID, input.Body.PermissionID, grantedBy, conditions)
 	if err != nil {
 		// Check for specific error cases
+		if strings.Contains(err.Error(), "condition") {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
 		if strings.Contains(err.Error(), "not found") {
 			return nil, huma.Error404NotFound("permission not found")
 		}
@@ -1378,6 +1507,11 @@ func (s *Service) GrantPermissionToGroup(ctx context.Context, input *dto.GrantPe
 		}
 		return nil, huma.Error500InternalServerError("failed to grant permission", err)
 	}
+	s.recordGroupAudit(ctx, models.AuditPermissionGranted, group, models.AuditEvent{
+		PermissionID: input.Body.PermissionID,
+		Detail:       describeConditions(conditions),
+	})
+	s.emitWebhook(ctx, models.WebhookPermissionGranted, groupID, 0, input.Body.PermissionID)
 
 	// Get permission details for response
 	perm, exists := s.permissionManager.GetPermission(input.Body.PermissionID)
@@ -1401,10 +1535,11 @@ func (s *Service) GrantPermissionToGroup(ctx context.Context, input *dto.GrantPe
 				Category:    perm.Category,
 				CreatedAt:   perm.CreatedAt,
 			},
-			GrantedBy: &grantedBy,
-			GrantedAt: time.Now(),
-			IsActive:  true,
-			UpdatedAt: time.Now(),
+			GrantedBy:  &grantedBy,
+			GrantedAt:  time.Now(),
+			IsActive:   true,
+			UpdatedAt:  time.Now(),
+			Conditions: input.Body.Conditions,
 		},
 	}, nil
 }
@@ -1433,6 +1568,12 @@ func (s *Service) RevokePermissionFromGroup(ctx context.Context, input *dto.Revo
 		}
 		return nil, huma.Error500InternalServerError("failed to delete permission", err)
 	}
+	group, _ := s.repo.GetGroupByID(ctx, groupID)
+	s.recordGroupAudit(ctx, models.AuditPermissionRevoked, group, models.AuditEvent{
+		GroupID:      &groupID,
+		PermissionID: input.PermissionID,
+	})
+	s.emitWebhook(ctx, models.WebhookPermissionRevoked, groupID, 0, input.PermissionID)
 
 	return &dto.MessageOutput{
 		Body: dto.MessageResponse{
@@ -1471,6 +1612,10 @@ func (s *Service) UpdateGroupPermissionStatus(ctx context.Context, input *dto.Up
 		}
 		return nil, huma.Error500InternalServerError("failed to update permission status", err)
 	}
+	s.recordGroupAudit(ctx, models.AuditPermissionStatus, group, models.AuditEvent{
+		PermissionID: input.PermissionID,
+		Detail:       fmt.Sprintf("is_active=%t", input.Body.IsActive),
+	})
 
 	// Get permission details for response
 	perm, exists := s.permissionManager.GetPermission(input.PermissionID)
@@ -1573,10 +1718,11 @@ func (s *Service) ListGroupPermissions(ctx context.Context, input *dto.ListGroup
 				Category:    perm.Category,
 				CreatedAt:   perm.CreatedAt,
 			},
-			GrantedBy: gp.GrantedBy,
-			GrantedAt: gp.GrantedAt,
-			IsActive:  gp.IsActive,
-			UpdatedAt: gp.UpdatedAt,
+			GrantedBy:  gp.GrantedBy,
+			GrantedAt:  gp.GrantedAt,
+			IsActive:   gp.IsActive,
+			UpdatedAt:  gp.UpdatedAt,
+			Conditions: conditionsToDTO(gp.Conditions),
 		})
 	}
 
@@ -1614,10 +1760,68 @@ func (s *Service) CheckPermission(ctx context.Context, input *dto.CheckPermissio
 
 	return &dto.PermissionCheckOutput{
 		Body: dto.PermissionCheckResponse{
-			CharacterID:  permCheck.CharacterID,
-			PermissionID: permCheck.PermissionID,
-			Granted:      permCheck.Granted,
-			GrantedVia:   permCheck.GrantedVia,
+			CharacterID:     permCheck.CharacterID,
+			PermissionID:    permCheck.PermissionID,
+			Granted:         permCheck.Granted,
+			GrantedVia:      permCheck.GrantedVia,
+			DeniedBy:        permCheck.DeniedBy,
+			UnmetConditions: permCheck.UnmetConditions,
 		},
 	}, nil
 }
+
+// conditionsFromDTO converts grant conditions of a request
+func conditionsFromDTO(conditions []dto.PermissionCondition) []permissions.PermissionCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	converted := make([]permissions.PermissionCondition, len(conditions))
+	for i, condition := range conditions {
+		converted[i] = permissions.PermissionCondition{
+			Attribute: condition.Attribute,
+			Operator:  condition.Operator,
+			Values:    condition.Values,
+		}
+	}
+	return converted
+}
+
+// conditionsToDTO converts stored grant conditions for a response
+func conditionsToDTO(conditions []permissions.PermissionCondition) []dto.PermissionCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	converted := make([]dto.PermissionCondition, len(conditions))
+	for i, condition := range conditions {
+		converted[i] = dto.PermissionCondition{
+			Attribute: condition.Attribute,
+			Operator:  condition.Operator,
+			Values:    condition.Values,
+		}
+	}
+	return converted
+}
+
+// describeConditions renders grant conditions for the audit log, e.g. "when security_status gt 0"
+func describeConditions(conditions []permissions.PermissionCondition) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	parts := make([]string, len(conditions))
+	for i, condition := range conditions {
+		parts[i] = condition.String()
+	}
+	return "when " + strings.Join(parts, " and ")
+}
+
+// changedFields lists the fields of an update, for the audit log
+func changedFields(update bson.M) string {
+	fields := make([]string, 0, len(update))
+	for field := range update {
+		if field != "updated_at" {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ", ")
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+	"go-falcon/pkg/permissions"
+)
+
+// accessState is the data that decides a character's permissions: the memberships of every
+// character of its account (admin membership of any of them bypasses checks), all grants and the
+// permissions denied to the character. Conditional grants whose conditions do not hold for the
+// character right now are left out. Changes never touch denials.
+type accessState struct {
+	memberships map[int64]map[primitive.ObjectID]bool
+	grants      map[primitive.ObjectID]map[string]bool
+	denied      map[string]bool
+}
+
+// SimulatePermissions evaluates a character's effective permissions today and with hypothetical
+// grants, revocations and membership changes applied. Nothing is written.
+func (s *Service) SimulatePermissions(ctx context.Context, input *dto.SimulatePermissionsInput) (*dto.PermissionSimulationOutput, error) {
+	if s.permissionManager == nil {
+		return nil, huma.Error500InternalServerError("permission manager not available")
+	}
+	characterID := input.Body.CharacterID
+
+	groupList, err := s.repo.GetGroupsByFilter(ctx, bson.M{})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to load groups", err)
+	}
+	groups := make(map[primitive.ObjectID]*models.Group, len(groupList))
+	for i := range groupList {
+		groups[groupList[i].ID] = &groupList[i]
+	}
+
+	// The admin bypass applies when any character of the account is an admin
+	accountCharacters := []int64{characterID}
+	userIDs, err := s.repo.GetUserIDsByCharacterIDs(ctx, []int64{characterID})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get character account", err)
+	}
+	if len(userIDs) > 0 {
+		if accountCharacters, err = s.repo.GetCharacterIDsByUserID(ctx, userIDs[0]); err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get account characters", err)
+		}
+	}
+
+	memberships, err := s.repo.GetActiveMembershipGroupIDs(ctx, append(accountCharacters, characterID))
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get memberships", err)
+	}
+	grants, err := s.repo.GetAllActiveGroupPermissions(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get group permissions", err)
+	}
+	denials, err := s.permissionManager.DenialsForCharacter(ctx, characterID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get permission denials", err)
+	}
+	conditional, err := s.repo.GetConditionalGroupPermissions(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get group permissions", err)
+	}
+
+	before := &accessState{
+		memberships: make(map[int64]map[primitive.ObjectID]bool),
+		grants:      make(map[primitive.ObjectID]map[string]bool),
+		denied:      make(map[string]bool, len(denials)),
+	}
+	for _, denial := range denials {
+		before.denied[denial.PermissionID] = true
+	}
+	for id, groupIDs := range memberships {
+		before.memberships[id] = make(map[primitive.ObjectID]bool, len(groupIDs))
+		for _, groupID := range groupIDs {
+			before.memberships[id][groupID] = true
+		}
+	}
+	for groupID, permissionIDs := range grants {
+		before.grants[groupID] = make(map[string]bool, len(permissionIDs))
+		for _, permissionID := range permissionIDs {
+			before.grants[groupID][permissionID] = true
+		}
+	}
+	for _, grant := range conditional {
+		holds, err := s.permissionManager.ConditionsHold(ctx, characterID, grant.Conditions)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to evaluate grant conditions", err)
+		}
+		if !holds {
+			delete(before.grants[grant.GroupID], grant.PermissionID)
+		}
+	}
+
+	after := before.clone()
+	for _, change := range input.Body.Changes {
+		if err := s.applyPermissionChange(after, groups, characterID, change); err != nil {
+			return nil, err
+		}
+	}
+
+	beforeAccess := s.evaluateAccess(before, groups, characterID)
+	afterAccess := s.evaluateAccess(after, groups, characterID)
+
+	return &dto.PermissionSimulationOutput{
+		Body: dto.PermissionSimulationResponse{
+			CharacterID: characterID,
+			Before:      beforeAccess,
+			After:       afterAccess,
+			Gained:      missingFrom(afterAccess.Permissions, beforeAccess.Permissions),
+			Lost:        missingFrom(beforeAccess.Permissions, afterAccess.Permissions),
+		},
+	}, nil
+}
+
+// applyPermissionChange applies one hypothetical change to a state
+func (s *Service) applyPermissionChange(state *accessState, groups map[primitive.ObjectID]*models.Group, characterID int64, change dto.PermissionChangeInput) error {
+	groupID, err := primitive.ObjectIDFromHex(change.GroupID)
+	if err != nil {
+		return huma.Error400BadRequest("Invalid group ID: " + change.GroupID)
+	}
+	if groups[groupID] == nil {
+		return huma.Error404NotFound("Group not found: " + change.GroupID)
+	}
+
+	switch change.Type {
+	case "grant", "revoke":
+		if _, exists := s.permissionManager.GetPermission(change.PermissionID); !exists {
+			return huma.Error400BadRequest("Permission not found: " + change.PermissionID)
+		}
+		if change.Type == "revoke" {
+			delete(state.grants[groupID], change.PermissionID)
+			return nil
+		}
+		if state.grants[groupID] == nil {
+			state.grants[groupID] = make(map[string]bool)
+		}
+		state.grants[groupID][change.PermissionID] = true
+	case "add_membership":
+		if state.memberships[characterID] == nil {
+			state.memberships[characterID] = make(map[primitive.ObjectID]bool)
+		}
+		state.memberships[characterID][groupID] = true
+	case "remove_membership":
+		delete(state.memberships[characterID], groupID)
+	}
+	return nil
+}
+
+// evaluateAccess resolves the effective permissions of a character the way HasPermission does:
+// grants of its groups and of their active ancestors less denied permissions, or everything for
+// admin accounts
+func (s *Service) evaluateAccess(state *accessState, groups map[primitive.ObjectID]*models.Group, characterID int64) dto.SimulatedAccessResponse {
+	access := dto.SimulatedAccessResponse{Groups: []string{}, Permissions: []string{}}
+
+	for id, groupIDs := range state.memberships {
+		for groupID := range groupIDs {
+			group := groups[groupID]
+			if group == nil || !group.IsActive || !isAdminGroupName(group.Name) {
+				continue
+			}
+			if access.AdminGroup == "" || id == characterID {
+				access.AdminGroup = group.Name
+			}
+		}
+	}
+
+	effective := make(map[string]bool)
+	for groupID := range state.memberships[characterID] {
+		group := groups[groupID]
+		if group == nil {
+			continue
+		}
+		access.Groups = append(access.Groups, group.Name)
+		for _, lineageID := range groupLineage(groups, groupID) {
+			for permissionID := range state.grants[lineageID] {
+				effective[permissionID] = true
+			}
+		}
+	}
+	if access.AdminGroup != "" {
+		for permissionID := range s.permissionManager.GetAllPermissions() {
+			effective[permissionID] = true
+		}
+	} else {
+		for permissionID := range state.denied {
+			delete(effective, permissionID)
+		}
+	}
+
+	for permissionID := range effective {
+		access.Permissions = append(access.Permissions, permissionID)
+	}
+	sort.Strings(access.Groups)
+	sort.Strings(access.Permissions)
+	return access
+}
+
+// groupLineage returns a group and the active ancestors it inherits permissions from, following
+// the same rules as the permission checks
+func groupLineage(groups map[primitive.ObjectID]*models.Group, groupID primitive.ObjectID) []primitive.ObjectID {
+	current := groups[groupID]
+	if current == nil {
+		return nil
+	}
+
+	lineage := []primitive.ObjectID{groupID}
+	for depth := 1; depth <= permissions.MaxGroupDepth && current.ParentID != nil; depth++ {
+		parent := groups[*current.ParentID]
+		if parent == nil {
+			break
+		}
+		if parent.IsActive {
+			lineage = append(lineage, parent.ID)
+		}
+		current = parent
+	}
+	return lineage
+}
+
+func (a *accessState) clone() *accessState {
+	clone := &accessState{
+		memberships: make(map[int64]map[primitive.ObjectID]bool, len(a.memberships)),
+		grants:      make(map[primitive.ObjectID]map[string]bool, len(a.grants)),
+		denied:      a.denied,
+	}
+	for id, groupIDs := range a.memberships {
+		clone.memberships[id] = make(map[primitive.ObjectID]bool, len(groupIDs))
+		for groupID := range groupIDs {
+			clone.memberships[id][groupID] = true
+		}
+	}
+	for groupID, permissionIDs := range a.grants {
+		clone.grants[groupID] = make(map[string]bool, len(permissionIDs))
+		for permissionID := range permissionIDs {
+			clone.grants[groupID][permissionID] = true
+		}
+	}
+	return clone
+}
+
+func isAdminGroupName(name string) bool {
+	for _, adminName := range models.AdminGroupNames {
+		if name == adminName {
+			return true
+		}
+	}
+	return false
+}
+
+// missingFrom returns the sorted values of a that are not in b
+func missingFrom(a, b []string) []string {
+	present := make(map[string]bool, len(b))
+	for _, value := range b {
+		present[value] = true
+	}
+	missing := []string{}
+	for _, value := range a {
+		if !present[value] {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+	"go-falcon/pkg/config"
+)
+
+// TakeSnapshot records the current memberships and permission grants of every group, then prunes
+// snapshots older than the configured retention
+func (s *Service) TakeSnapshot(ctx context.Context, trigger string, takenBy *int64) (*models.Snapshot, error) {
+	groups, err := s.repo.GetGroupsByFilter(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.GetAllActiveMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := s.repo.GetAllActiveGroupPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.Snapshot{
+		TakenAt: time.Now(),
+		Trigger: trigger,
+		TakenBy: takenBy,
+		Groups:  len(groups),
+	}
+
+	snapshotGroups := make([]models.SnapshotGroup, 0, len(groups))
+	for _, group := range groups {
+		groupMembers := append([]int64{}, members[group.ID]...)
+		groupGrants := append([]string{}, grants[group.ID]...)
+		sort.Slice(groupMembers, func(i, j int) bool { return groupMembers[i] < groupMembers[j] })
+		sort.Strings(groupGrants)
+
+		snapshot.Memberships += len(groupMembers)
+		snapshot.Grants += len(groupGrants)
+
+		snapshotGroups = append(snapshotGroups, models.SnapshotGroup{
+			TakenAt:     snapshot.TakenAt,
+			GroupID:     group.ID,
+			Name:        group.Name,
+			Type:        group.Type,
+			SystemName:  group.SystemName,
+			IsActive:    group.IsActive,
+			Members:     groupMembers,
+			Permissions: groupGrants,
+		})
+	}
+
+	if err := s.repo.CreateSnapshot(ctx, snapshot, snapshotGroups); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Took group snapshot",
+		"trigger", trigger,
+		"groups", snapshot.Groups,
+		"memberships", snapshot.Memberships,
+		"grants", snapshot.Grants)
+
+	retentionDays := config.GetGroupSnapshotRetentionDays()
+	if retentionDays > 0 {
+		cutoff := snapshot.TakenAt.AddDate(0, 0, -retentionDays)
+		if pruned, err := s.repo.DeleteSnapshotsBefore(ctx, cutoff); err != nil {
+			slog.Warn("Failed to prune old group snapshots", "error", err)
+		} else if pruned > 0 {
+			slog.Info("Pruned old group snapshots", "count", pruned, "retention_days", retentionDays)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// TakeGroupSnapshot takes a scheduled snapshot for the scheduler
+func (s *Service) TakeGroupSnapshot(ctx context.Context) (memberships, grants int, err error) {
+	snapshot, err := s.TakeSnapshot(ctx, models.SnapshotTriggerScheduled, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	return snapshot.Memberships, snapshot.Grants, nil
+}
+
+// CreateSnapshot takes a manual snapshot on behalf of a character
+func (s *Service) CreateSnapshot(ctx context.Context, takenBy int64) (*dto.SnapshotOutput, error) {
+	snapshot, err := s.TakeSnapshot(ctx, models.SnapshotTriggerManual, &takenBy)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to take group snapshot", err)
+	}
+	return &dto.SnapshotOutput{Body: snapshotToResponse(snapshot)}, nil
+}
+
+// ListSnapshots returns the stored snapshots, newest first
+func (s *Service) ListSnapshots(ctx context.Context, input *dto.ListSnapshotsInput) (*dto.ListSnapshotsOutput, error) {
+	snapshots, total, err := s.repo.ListSnapshots(ctx, input.Page, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list group snapshots", err)
+	}
+
+	response := dto.ListSnapshotsResponse{
+		Snapshots: make([]dto.SnapshotResponse, 0, len(snapshots)),
+		Total:     total,
+		Page:      input.Page,
+		Limit:     input.Limit,
+	}
+	for i := range snapshots {
+		response.Snapshots = append(response.Snapshots, snapshotToResponse(&snapshots[i]))
+	}
+
+	return &dto.ListSnapshotsOutput{Body: response}, nil
+}
+
+// GetSnapshotAccess answers who held a permission, or belonged to a group, at a point in time.
+// The answer comes from the latest snapshot taken at or before that time.
+//
+// Members of the admin groups pass every permission check, so a permission query also lists them.
+// At runtime the bypass extends to every character of an admin's user; snapshots don't record
+// character ownership, so only the characters that were themselves admin group members are listed.
+func (s *Service) GetSnapshotAccess(ctx context.Context, input *dto.GetSnapshotAccessInput) (*dto.SnapshotAccessOutput, error) {
+	if (input.Permission == "") == (input.GroupID == "") {
+		return nil, huma.Error400BadRequest("Exactly one of permission or group_id is required")
+	}
+
+	snapshot, err := s.snapshotAt(ctx, input.At)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter bson.M
+	if input.GroupID != "" {
+		groupID, err := primitive.ObjectIDFromHex(input.GroupID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid group ID format")
+		}
+		filter = bson.M{"group_id": groupID}
+	} else {
+		filter = bson.M{"$or": []bson.M{
+			{"permissions": input.Permission},
+			{"name": bson.M{"$in": models.AdminGroupNames}},
+		}}
+	}
+
+	groups, err := s.repo.GetSnapshotGroups(ctx, snapshot.ID, filter)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get snapshot groups", err)
+	}
+
+	via := make(map[int64][]string)
+	for _, group := range groups {
+		// Inactive groups grant nothing, but their members are still answered for a group query
+		if !group.IsActive && input.GroupID == "" {
+			continue
+		}
+		for _, characterID := range group.Members {
+			via[characterID] = append(via[characterID], group.Name)
+		}
+	}
+
+	characterIDs := make([]int64, 0, len(via))
+	for characterID := range via {
+		characterIDs = append(characterIDs, characterID)
+	}
+	sort.Slice(characterIDs, func(i, j int) bool { return characterIDs[i] < characterIDs[j] })
+
+	names := map[int64]string{}
+	if len(characterIDs) > 0 {
+		if names, err = s.repo.GetCharacterNames(ctx, characterIDs); err != nil {
+			slog.Warn("Failed to resolve character names for snapshot access", "error", err)
+			names = map[int64]string{}
+		}
+	}
+
+	response := dto.SnapshotAccessResponse{
+		Permission: input.Permission,
+		GroupID:    input.GroupID,
+		At:         input.At,
+		Snapshot:   snapshotToResponse(snapshot),
+		Characters: make([]dto.SnapshotAccessEntry, 0, len(characterIDs)),
+	}
+	for _, characterID := range characterIDs {
+		response.Characters = append(response.Characters, dto.SnapshotAccessEntry{
+			CharacterID:   characterID,
+			CharacterName: names[characterID],
+			Via:           via[characterID],
+		})
+	}
+
+	return &dto.SnapshotAccessOutput{Body: response}, nil
+}
+
+// DiffSnapshots lists the membership and permission changes between the snapshots at two points in time
+func (s *Service) DiffSnapshots(ctx context.Context, input *dto.DiffSnapshotsInput) (*dto.SnapshotDiffOutput, error) {
+	if !input.From.Before(input.To) {
+		return nil, huma.Error400BadRequest("from must be before to")
+	}
+
+	filter := bson.M{}
+	if input.GroupID != "" {
+		groupID, err := primitive.ObjectIDFromHex(input.GroupID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid group ID format")
+		}
+		filter["group_id"] = groupID
+	}
+
+	from, err := s.snapshotAt(ctx, input.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.snapshotAt(ctx, input.To)
+	if err != nil {
+		return nil, err
+	}
+
+	fromGroups, err := s.repo.GetSnapshotGroups(ctx, from.ID, filter)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get snapshot groups", err)
+	}
+	toGroups, err := s.repo.GetSnapshotGroups(ctx, to.ID, filter)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get snapshot groups", err)
+	}
+
+	before := make(map[primitive.ObjectID]models.SnapshotGroup, len(fromGroups))
+	for _, group := range fromGroups {
+		before[group.GroupID] = group
+	}
+
+	diffs := make([]dto.SnapshotGroupDiff, 0)
+	for _, group := range toGroups {
+		previous, existed := before[group.GroupID]
+		delete(before, group.GroupID)
+
+		diff := diffSnapshotGroup(previous, group)
+		switch {
+		case !existed:
+			diff.Change = "created"
+		case len(diff.MembersAdded)+len(diff.MembersRemoved)+len(diff.PermissionsGranted)+len(diff.PermissionsRevoked) == 0:
+			continue
+		default:
+			diff.Change = "modified"
+		}
+		diffs = append(diffs, diff)
+	}
+	for _, group := range before {
+		diff := diffSnapshotGroup(group, models.SnapshotGroup{GroupID: group.GroupID, Name: group.Name})
+		diff.Change = "deleted"
+		diffs = append(diffs, diff)
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+
+	return &dto.SnapshotDiffOutput{
+		Body: dto.SnapshotDiffResponse{
+			From:   snapshotToResponse(from),
+			To:     snapshotToResponse(to),
+			Groups: diffs,
+		},
+	}, nil
+}
+
+// snapshotAt returns the snapshot in effect at the given time or a 404 when none was taken yet
+func (s *Service) snapshotAt(ctx context.Context, at time.Time) (*models.Snapshot, error) {
+	snapshot, err := s.repo.GetSnapshotAt(ctx, at)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get group snapshot", err)
+	}
+	if snapshot == nil {
+		return nil, huma.Error404NotFound(fmt.Sprintf("No group snapshot taken at or before %s", at.Format(time.RFC3339)))
+	}
+	return snapshot, nil
+}
+
+// diffSnapshotGroup compares two states of the same group
+func diffSnapshotGroup(from, to models.SnapshotGroup) dto.SnapshotGroupDiff {
+	diff := dto.SnapshotGroupDiff{
+		GroupID:            to.GroupID.Hex(),
+		Name:               to.Name,
+		MembersAdded:       []int64{},
+		MembersRemoved:     []int64{},
+		PermissionsGranted: []string{},
+		PermissionsRevoked: []string{},
+	}
+
+	fromMembers := make(map[int64]bool, len(from.Members))
+	for _, characterID := range from.Members {
+		fromMembers[characterID] = true
+	}
+	for _, characterID := range to.Members {
+		if !fromMembers[characterID] {
+			diff.MembersAdded = append(diff.MembersAdded, characterID)
+		}
+		delete(fromMembers, characterID)
+	}
+	for _, characterID := range from.Members {
+		if fromMembers[characterID] {
+			diff.MembersRemoved = append(diff.MembersRemoved, characterID)
+		}
+	}
+
+	fromPermissions := make(map[string]bool, len(from.Permissions))
+	for _, permissionID := range from.Permissions {
+		fromPermissions[permissionID] = true
+	}
+	for _, permissionID := range to.Permissions {
+		if !fromPermissions[permissionID] {
+			diff.PermissionsGranted = append(diff.PermissionsGranted, permissionID)
+		}
+		delete(fromPermissions, permissionID)
+	}
+	for _, permissionID := range from.Permissions {
+		if fromPermissions[permissionID] {
+			diff.PermissionsRevoked = append(diff.PermissionsRevoked, permissionID)
+		}
+	}
+
+	return diff
+}
+
+// snapshotToResponse converts a stored snapshot to its API shape
+func snapshotToResponse(snapshot *models.Snapshot) dto.SnapshotResponse {
+	return dto.SnapshotResponse{
+		ID:          snapshot.ID.Hex(),
+		TakenAt:     snapshot.TakenAt,
+		Trigger:     snapshot.Trigger,
+		TakenBy:     snapshot.TakenBy,
+		Groups:      snapshot.Groups,
+		Memberships: snapshot.Memberships,
+		Grants:      snapshot.Grants,
+	}
+}
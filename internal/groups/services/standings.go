@@ -0,0 +1,414 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+)
+
+// AllianceContactSource reads the contact list of an alliance from ESI
+type AllianceContactSource interface {
+	GetAllianceContacts(ctx context.Context, allianceID int64, token string) ([]map[string]any, error)
+}
+
+// SetAllianceContactSource sets where standings imports read alliance contacts from. Without one,
+// contact lists cannot be imported and the stored ones are used as they are.
+func (s *Service) SetAllianceContactSource(source AllianceContactSource) {
+	s.contactsSource = source
+}
+
+// ImportStandingsContacts reads the contact list of an alliance from ESI and replaces the stored
+// one. The tier groups pick up the new standings on the next reconciliation.
+func (s *Service) ImportStandingsContacts(ctx context.Context, input *dto.ImportStandingsContactsInput, importedBy int64) (*dto.StandingsContactListOutput, error) {
+	if s.contactsSource == nil {
+		return nil, huma.Error503ServiceUnavailable("Alliance contacts are not available")
+	}
+
+	list, err := s.importAllianceContacts(ctx, input.AllianceID, importedBy)
+	if errors.Is(err, errNoAllianceToken) {
+		return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("No character of alliance %d granted %s", input.AllianceID, models.AllianceContactsScope))
+	}
+	if err != nil {
+		return nil, huma.Error502BadGateway("Failed to read alliance contacts from ESI", err)
+	}
+
+	return &dto.StandingsContactListOutput{Body: contactListToResponse(list)}, nil
+}
+
+// errNoAllianceToken is returned when no character of an alliance granted AllianceContactsScope
+var errNoAllianceToken = errors.New("no character of the alliance granted " + models.AllianceContactsScope)
+
+// importAllianceContacts reads an alliance's contacts with the token of each of its characters
+// that granted the scope until ESI accepts one, and stores them
+func (s *Service) importAllianceContacts(ctx context.Context, allianceID, importedBy int64) (*models.StandingsContactList, error) {
+	tokens, err := s.repo.GetAllianceTokens(ctx, allianceID, models.AllianceContactsScope)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errNoAllianceToken
+	}
+
+	var contacts []map[string]any
+	for _, token := range tokens {
+		if contacts, err = s.contactsSource.GetAllianceContacts(ctx, allianceID, token); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.StandingsContactList{
+		AllianceID: allianceID,
+		Contacts:   make([]models.StandingsContact, 0, len(contacts)),
+		ImportedBy: importedBy,
+		ImportedAt: time.Now(),
+	}
+	for _, contact := range contacts {
+		contactID, _ := contact["contact_id"].(int64)
+		contactType, _ := contact["contact_type"].(string)
+		standing, _ := contact["standing"].(float64)
+		if contactID == 0 || contactType == "" {
+			continue
+		}
+		list.Contacts = append(list.Contacts, models.StandingsContact{
+			ContactID:   contactID,
+			ContactType: contactType,
+			Standing:    standing,
+		})
+	}
+
+	if err := s.repo.UpsertStandingsContacts(ctx, list); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Imported alliance standings contacts",
+		"alliance_id", list.AllianceID, "contacts", len(list.Contacts), "imported_by", importedBy)
+	return list, nil
+}
+
+// refreshStandingsContacts re-reads every imported contact list from ESI. A list that cannot be
+// read keeps its last import.
+func (s *Service) refreshStandingsContacts(ctx context.Context) {
+	if s.contactsSource == nil {
+		return
+	}
+	lists, err := s.repo.GetStandingsContactLists(ctx)
+	if err != nil {
+		slog.Warn("Failed to list standings contacts to refresh", "error", err)
+		return
+	}
+	for _, list := range lists {
+		if _, err := s.importAllianceContacts(ctx, list.AllianceID, 0); err != nil {
+			slog.Warn("Failed to refresh alliance standings contacts, keeping the last import",
+				"alliance_id", list.AllianceID, "error", err)
+		}
+	}
+}
+
+// DeleteStandingsContacts removes an alliance contact list so it no longer feeds the tier groups
+func (s *Service) DeleteStandingsContacts(ctx context.Context, input *dto.DeleteStandingsContactsInput) (*dto.SuccessOutput, error) {
+	deleted, err := s.repo.DeleteStandingsContacts(ctx, input.AllianceID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete standings contacts", err)
+	}
+	if !deleted {
+		return nil, huma.Error404NotFound(fmt.Sprintf("No contacts imported for alliance %d", input.AllianceID))
+	}
+
+	return &dto.SuccessOutput{
+		Body: dto.SuccessResponse{
+			Message: "Standings contacts deleted successfully",
+		},
+	}, nil
+}
+
+// GetStandingsStatus returns the imported contact lists, the tier groups and the latest drift report
+func (s *Service) GetStandingsStatus(ctx context.Context) (*dto.StandingsStatusOutput, error) {
+	lists, err := s.repo.GetStandingsContactLists(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get standings contacts", err)
+	}
+
+	response := dto.StandingsStatusResponse{
+		ContactLists: make([]dto.StandingsContactListResponse, 0, len(lists)),
+		Tiers:        make([]dto.StandingsTierResponse, 0, len(models.StandingsTiers)),
+	}
+	for i := range lists {
+		response.ContactLists = append(response.ContactLists, contactListToResponse(&lists[i]))
+	}
+
+	for _, tier := range models.StandingsTiers {
+		tierResponse := dto.StandingsTierResponse{
+			GroupName:   tier.GroupName,
+			Description: tier.Description,
+		}
+
+		group, err := s.repo.GetGroupByName(ctx, tier.GroupName)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get standings group", err)
+		}
+		if group != nil {
+			tierResponse.GroupID = group.ID.Hex()
+			if tierResponse.MemberCount, err = s.repo.GetGroupMemberCount(ctx, group.ID); err != nil {
+				return nil, huma.Error500InternalServerError("Failed to count standings group members", err)
+			}
+		}
+
+		response.Tiers = append(response.Tiers, tierResponse)
+	}
+
+	report, err := s.repo.GetLatestStandingsReport(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get standings report", err)
+	}
+	if report != nil {
+		reportResponse := StandingsReportToResponse(report)
+		response.LastReport = &reportResponse
+	}
+
+	return &dto.StandingsStatusOutput{Body: response}, nil
+}
+
+// ReconcileStandingsGroups brings the tier group memberships in line with the imported contact
+// lists and records the drift it found. With dryRun set only the report is written.
+//
+// A character's standing is taken from the most specific contact on a list (character, then
+// corporation, then alliance), as in the EVE client. When several alliance lists rate the same
+// character, the lowest standing wins so a single red keeps a character out of the blue tiers.
+func (s *Service) ReconcileStandingsGroups(ctx context.Context, dryRun bool, triggeredBy *int64) (*models.StandingsSyncReport, error) {
+	report := &models.StandingsSyncReport{
+		DryRun:      dryRun,
+		TriggeredBy: triggeredBy,
+		Tiers:       make([]models.StandingsTierDrift, 0, len(models.StandingsTiers)),
+		StartedAt:   time.Now(),
+	}
+
+	lists, err := s.repo.GetStandingsContactLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	affiliations, err := s.repo.GetCharacterAffiliations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.ContactLists = len(lists)
+	report.CharactersEvaluated = len(affiliations)
+
+	standings := resolveStandings(lists, affiliations)
+
+	for _, tier := range models.StandingsTiers {
+		drift, added, removed, err := s.reconcileStandingsTier(ctx, tier, standings, dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile %s: %w", tier.GroupName, err)
+		}
+		report.Tiers = append(report.Tiers, *drift)
+		report.Added += added
+		report.Removed += removed
+	}
+
+	report.CompletedAt = time.Now()
+	if err := s.repo.CreateStandingsReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Reconciled standings groups",
+		"dry_run", dryRun,
+		"contact_lists", report.ContactLists,
+		"characters", report.CharactersEvaluated,
+		"added", report.Added,
+		"removed", report.Removed)
+
+	return report, nil
+}
+
+// SyncStandingsGroups refreshes the imported contact lists from ESI and runs a reconciliation for
+// the scheduler
+func (s *Service) SyncStandingsGroups(ctx context.Context) (added, removed int, err error) {
+	s.refreshStandingsContacts(ctx)
+	report, err := s.ReconcileStandingsGroups(ctx, false, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	return report.Added, report.Removed, nil
+}
+
+// reconcileStandingsTier diffs one tier group against the resolved standings and applies the diff
+func (s *Service) reconcileStandingsTier(ctx context.Context, tier models.StandingsTier, standings map[int64]float64, dryRun bool) (*models.StandingsTierDrift, int, int, error) {
+	expected := make(map[int64]bool)
+	for characterID, standing := range standings {
+		if tier.Matches(standing) {
+			expected[characterID] = true
+		}
+	}
+
+	drift := &models.StandingsTierDrift{
+		GroupName:  tier.GroupName,
+		Expected:   len(expected),
+		Missing:    []int64{},
+		Unexpected: []int64{},
+	}
+
+	group, err := s.repo.GetGroupByName(ctx, tier.GroupName)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if group != nil && group.Type != models.GroupTypeStandings {
+		return nil, 0, 0, fmt.Errorf("group name %s is taken by a %s group", tier.GroupName, group.Type)
+	}
+	if group == nil && !dryRun {
+		if group, err = s.createStandingsGroup(ctx, tier); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	current := make(map[int64]bool)
+	if group != nil {
+		memberships, err := s.repo.GetActiveGroupMemberships(ctx, group.ID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		for _, membership := range memberships {
+			current[membership.CharacterID] = true
+		}
+	}
+
+	for characterID := range expected {
+		if !current[characterID] {
+			drift.Missing = append(drift.Missing, characterID)
+		}
+	}
+	for characterID := range current {
+		if !expected[characterID] {
+			drift.Unexpected = append(drift.Unexpected, characterID)
+		}
+	}
+	sort.Slice(drift.Missing, func(i, j int) bool { return drift.Missing[i] < drift.Missing[j] })
+	sort.Slice(drift.Unexpected, func(i, j int) bool { return drift.Unexpected[i] < drift.Unexpected[j] })
+
+	if dryRun {
+		return drift, 0, 0, nil
+	}
+
+	added, removed := 0, 0
+	for _, characterID := range drift.Missing {
+		if err := s.addMemberToGroup(ctx, group.ID, characterID); err != nil {
+			slog.Error("Failed to add character to standings group",
+				"character_id", characterID, "group_name", tier.GroupName, "error", err)
+			continue
+		}
+		added++
+	}
+	for _, characterID := range drift.Unexpected {
+		if err := s.repo.RemoveMembership(ctx, group.ID, characterID); err != nil {
+			slog.Error("Failed to remove character from standings group",
+				"character_id", characterID, "group_name", tier.GroupName, "error", err)
+			continue
+		}
+		removed++
+	}
+
+	return drift, added, removed, nil
+}
+
+// createStandingsGroup creates the managed group for a standings tier
+func (s *Service) createStandingsGroup(ctx context.Context, tier models.StandingsTier) (*models.Group, error) {
+	group := &models.Group{
+		Name:        tier.GroupName,
+		Description: tier.Description,
+		Type:        models.GroupTypeStandings,
+		IsActive:    true,
+	}
+	if err := s.repo.CreateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Created standings group", "group_name", group.Name)
+	return group, nil
+}
+
+// resolveStandings returns the effective standing of every character that any contact list rates
+func resolveStandings(lists []models.StandingsContactList, affiliations []CharacterAffiliation) map[int64]float64 {
+	standings := make(map[int64]float64)
+
+	for _, list := range lists {
+		byType := map[string]map[int64]float64{
+			"character":   {},
+			"corporation": {},
+			"alliance":    {},
+		}
+		for _, contact := range list.Contacts {
+			if contacts, ok := byType[contact.ContactType]; ok {
+				contacts[contact.ContactID] = contact.Standing
+			}
+		}
+
+		for _, affiliation := range affiliations {
+			// Characters of the alliance itself are not its contacts
+			if affiliation.AllianceID != 0 && affiliation.AllianceID == list.AllianceID {
+				continue
+			}
+
+			standing, ok := byType["character"][affiliation.CharacterID]
+			if !ok {
+				standing, ok = byType["corporation"][affiliation.CorporationID]
+			}
+			if !ok && affiliation.AllianceID != 0 {
+				standing, ok = byType["alliance"][affiliation.AllianceID]
+			}
+			if !ok {
+				continue
+			}
+
+			if existing, seen := standings[affiliation.CharacterID]; !seen || standing < existing {
+				standings[affiliation.CharacterID] = standing
+			}
+		}
+	}
+
+	return standings
+}
+
+// contactListToResponse summarizes a stored contact list
+func contactListToResponse(list *models.StandingsContactList) dto.StandingsContactListResponse {
+	return dto.StandingsContactListResponse{
+		AllianceID: list.AllianceID,
+		Contacts:   len(list.Contacts),
+		ImportedBy: list.ImportedBy,
+		ImportedAt: list.ImportedAt,
+	}
+}
+
+// StandingsReportToResponse converts a stored reconciliation report to its API shape
+func StandingsReportToResponse(report *models.StandingsSyncReport) dto.StandingsSyncReportResponse {
+	response := dto.StandingsSyncReportResponse{
+		ID:                  report.ID.Hex(),
+		DryRun:              report.DryRun,
+		TriggeredBy:         report.TriggeredBy,
+		ContactLists:        report.ContactLists,
+		CharactersEvaluated: report.CharactersEvaluated,
+		Tiers:               make([]dto.StandingsTierDriftResponse, 0, len(report.Tiers)),
+		Added:               report.Added,
+		Removed:             report.Removed,
+		StartedAt:           report.StartedAt,
+		CompletedAt:         report.CompletedAt,
+	}
+	for _, tier := range report.Tiers {
+		response.Tiers = append(response.Tiers, dto.StandingsTierDriftResponse{
+			GroupName:  tier.GroupName,
+			Expected:   tier.Expected,
+			Missing:    tier.Missing,
+			Unexpected: tier.Unexpected,
+		})
+	}
+	return response
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+	"go-falcon/pkg/stepup"
+)
+
+// ListGroupTemplates returns the group templates
+func (s *Service) ListGroupTemplates(ctx context.Context) (*dto.ListGroupTemplatesOutput, error) {
+	templates, err := s.repo.ListTemplates(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list group templates", err)
+	}
+
+	output := &dto.ListGroupTemplatesOutput{}
+	output.Body.Templates = make([]dto.GroupTemplateResponse, 0, len(templates))
+	for i := range templates {
+		output.Body.Templates = append(output.Body.Templates, groupTemplateToResponse(&templates[i]))
+	}
+	return output, nil
+}
+
+// CreateGroupTemplate adds a group template
+func (s *Service) CreateGroupTemplate(ctx context.Context, input *dto.CreateGroupTemplateInput, createdBy int64) (*dto.GroupTemplateOutput, error) {
+	template, err := s.groupTemplateFromBody(&input.Body)
+	if err != nil {
+		return nil, err
+	}
+	template.CreatedBy = createdBy
+	template.CreatedAt = template.UpdatedAt
+
+	created, err := s.repo.CreateTemplate(ctx, template)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to create group template", err)
+	}
+	if !created {
+		return nil, huma.Error409Conflict(fmt.Sprintf("A group template named '%s' already exists", template.Name))
+	}
+
+	slog.Info("Created group template", "template_id", template.ID.Hex(), "name", template.Name, "created_by", createdBy)
+	return &dto.GroupTemplateOutput{Body: groupTemplateToResponse(template)}, nil
+}
+
+// UpdateGroupTemplate replaces a group template; groups created from it are not changed
+func (s *Service) UpdateGroupTemplate(ctx context.Context, input *dto.UpdateGroupTemplateInput) (*dto.GroupTemplateOutput, error) {
+	existing, err := s.getGroupTemplate(ctx, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := s.groupTemplateFromBody(&input.Body)
+	if err != nil {
+		return nil, err
+	}
+	template.ID = existing.ID
+	template.CreatedBy = existing.CreatedBy
+	template.CreatedAt = existing.CreatedAt
+
+	found, taken, err := s.repo.ReplaceTemplate(ctx, template)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to update group template", err)
+	}
+	if taken {
+		return nil, huma.Error409Conflict(fmt.Sprintf("A group template named '%s' already exists", template.Name))
+	}
+	if !found {
+		return nil, huma.Error404NotFound("Group template not found")
+	}
+
+	return &dto.GroupTemplateOutput{Body: groupTemplateToResponse(template)}, nil
+}
+
+// DeleteGroupTemplate removes a group template; groups created from it are kept
+func (s *Service) DeleteGroupTemplate(ctx context.Context, input *dto.DeleteGroupTemplateInput) (*dto.SuccessOutput, error) {
+	id, err := primitive.ObjectIDFromHex(input.TemplateID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid group template ID")
+	}
+
+	deleted, err := s.repo.DeleteTemplate(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete group template", err)
+	}
+	if !deleted {
+		return nil, huma.Error404NotFound("Group template not found")
+	}
+
+	return &dto.SuccessOutput{
+		Body: dto.SuccessResponse{
+			Message: "Group template deleted successfully",
+		},
+	}, nil
+}
+
+// InstantiateGroupTemplate creates the groups of a template for an enabled managed corporation:
+// each group with its permission grants and, for auto-membership groups, a membership rule on the
+// corporation. Nothing is created when a resolved group name is taken. A group that fails to
+// create is reported and its children are skipped; the other groups are still created.
+func (s *Service) InstantiateGroupTemplate(ctx context.Context, input *dto.InstantiateGroupTemplateInput, createdBy int64) (*dto.TemplateInstanceOutput, error) {
+	template, err := s.getGroupTemplate(ctx, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	corporationID := input.Body.CorporationID
+	replacer, err := s.templateReplacer(ctx, corporationID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := dto.TemplateInstanceResponse{
+		TemplateID:    template.ID.Hex(),
+		TemplateName:  template.Name,
+		CorporationID: corporationID,
+		DryRun:        input.Body.DryRun,
+		Groups:        make([]dto.TemplateInstanceGroup, 0, len(template.Groups)),
+		Errors:        []string{},
+	}
+
+	var taken []string
+	names := make(map[string]bool, len(template.Groups))
+	grantsPermissions := false
+	for _, group := range template.Groups {
+		name := replacer.Replace(group.Name)
+		if names[name] {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("Template groups resolve to the same name: %s", name))
+		}
+		names[name] = true
+		existing, err := s.repo.GetGroupByName(ctx, name)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to check existing group", err)
+		}
+		if existing != nil {
+			taken = append(taken, name)
+		}
+		if len(group.Permissions) > 0 {
+			grantsPermissions = true
+		}
+		report.Groups = append(report.Groups, dto.TemplateInstanceGroup{Key: group.Key, Name: name, Permissions: []string{}})
+	}
+	if len(taken) > 0 {
+		return nil, huma.Error409Conflict("Groups already exist: " + strings.Join(taken, ", "))
+	}
+	if report.DryRun {
+		for i, group := range template.Groups {
+			report.Groups[i].Permissions = append(report.Groups[i].Permissions, group.Permissions...)
+		}
+		return &dto.TemplateInstanceOutput{Body: report}, nil
+	}
+
+	// The grants escalate privileges like any other grant; require a second factor
+	if grantsPermissions {
+		if s.permissionManager == nil {
+			return nil, huma.Error500InternalServerError("permission manager not available")
+		}
+		if err := stepup.RequireSecondFactor(ctx, "groups-instantiate-template"); err != nil {
+			return nil, err
+		}
+	}
+
+	detail := fmt.Sprintf("template %s for corporation %d", template.Name, corporationID)
+	created := make(map[string]primitive.ObjectID, len(template.Groups))
+	for i, spec := range template.Groups {
+		instance := &report.Groups[i]
+
+		group := &models.Group{
+			Name:        instance.Name,
+			Description: replacer.Replace(spec.Description),
+			Type:        models.GroupTypeCustom,
+			Joinable:    spec.Joinable,
+			IsActive:    true,
+		}
+		if spec.ParentKey != "" {
+			parentID, ok := created[spec.ParentKey]
+			if !ok {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: skipped, parent %s was not created", instance.Name, spec.ParentKey))
+				continue
+			}
+			group.ParentID = &parentID
+		}
+		if err := s.repo.CreateGroup(ctx, group); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", instance.Name, err))
+			continue
+		}
+		created[spec.Key] = group.ID
+		instance.GroupID = group.ID.Hex()
+		s.recordGroupAudit(ctx, models.AuditGroupCreated, group, models.AuditEvent{Detail: detail})
+
+		for _, permissionID := range spec.Permissions {
+			if err := s.permissionManager.GrantPermissionToGroup(ctx, group.ID, permissionID, createdBy); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: grant %s: %v", instance.Name, permissionID, err))
+				continue
+			}
+			instance.Permissions = append(instance.Permissions, permissionID)
+			s.recordGroupAudit(ctx, models.AuditPermissionGranted, group, models.AuditEvent{PermissionID: permissionID, Detail: detail})
+			s.emitWebhook(ctx, models.WebhookPermissionGranted, group.ID, 0, permissionID)
+		}
+
+		if spec.AutoMembership {
+			now := time.Now()
+			rule := &models.MembershipRule{
+				GroupID:        group.ID,
+				Name:           fmt.Sprintf("%s members", instance.Name),
+				CorporationIDs: []int64{corporationID},
+				Roles:          spec.MemberRoles,
+				IsActive:       true,
+				CreatedBy:      createdBy,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if err := s.repo.CreateMembershipRule(ctx, rule); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: membership rule: %v", instance.Name, err))
+				continue
+			}
+			instance.RuleID = rule.ID.Hex()
+		}
+	}
+
+	slog.Info("Instantiated group template",
+		"template_id", template.ID.Hex(), "corporation_id", corporationID, "groups", len(created), "errors", len(report.Errors), "created_by", createdBy)
+	return &dto.TemplateInstanceOutput{Body: report}, nil
+}
+
+// templateReplacer resolves the placeholders of template names for an enabled managed corporation
+func (s *Service) templateReplacer(ctx context.Context, corporationID int64) (*strings.Replacer, error) {
+	if s.siteSettingsService == nil {
+		return nil, huma.Error500InternalServerError("site settings not available")
+	}
+	corporations, err := s.siteSettingsService.GetEnabledCorporations(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get managed corporations", err)
+	}
+	for _, corporation := range corporations {
+		if corporation.CorporationID == corporationID {
+			return strings.NewReplacer(
+				"{ticker}", corporation.Ticker,
+				"{name}", corporation.Name,
+				"{corporation_id}", strconv.FormatInt(corporationID, 10),
+			), nil
+		}
+	}
+	return nil, huma.Error400BadRequest(fmt.Sprintf("Corporation %d is not an enabled managed corporation; add it in site settings first", corporationID))
+}
+
+func (s *Service) getGroupTemplate(ctx context.Context, templateID string) (*models.GroupTemplate, error) {
+	id, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid group template ID")
+	}
+	template, err := s.repo.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get group template", err)
+	}
+	if template == nil {
+		return nil, huma.Error404NotFound("Group template not found")
+	}
+	return template, nil
+}
+
+// groupTemplateFromBody validates a template: unique keys, parents defined before their children,
+// known permissions and member roles only on auto-membership groups
+func (s *Service) groupTemplateFromBody(body *dto.GroupTemplateBody) (*models.GroupTemplate, error) {
+	template := &models.GroupTemplate{
+		Name:        body.Name,
+		Description: body.Description,
+		Groups:      make([]models.TemplateGroup, 0, len(body.Groups)),
+		UpdatedAt:   time.Now(),
+	}
+
+	keys := make(map[string]bool, len(body.Groups))
+	for _, group := range body.Groups {
+		if keys[group.Key] {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("Duplicate template group key: %s", group.Key))
+		}
+		if group.ParentKey != "" && !keys[group.ParentKey] {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("Parent %s of template group %s must be an earlier group of the template", group.ParentKey, group.Key))
+		}
+		keys[group.Key] = true
+
+		if len(group.MemberRoles) > 0 && !group.AutoMembership {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("Template group %s sets member_roles without auto_membership", group.Key))
+		}
+		if s.permissionManager != nil {
+			for _, permissionID := range group.Permissions {
+				if _, exists := s.permissionManager.GetPermission(permissionID); !exists {
+					return nil, huma.Error400BadRequest(fmt.Sprintf("Unknown permission in template group %s: %s", group.Key, permissionID))
+				}
+			}
+		}
+
+		template.Groups = append(template.Groups, models.TemplateGroup{
+			Key:            group.Key,
+			ParentKey:      group.ParentKey,
+			Name:           group.Name,
+			Description:    group.Description,
+			Joinable:       group.Joinable,
+			Permissions:    group.Permissions,
+			AutoMembership: group.AutoMembership,
+			MemberRoles:    group.MemberRoles,
+		})
+	}
+	return template, nil
+}
+
+func groupTemplateToResponse(template *models.GroupTemplate) dto.GroupTemplateResponse {
+	groups := make([]dto.TemplateGroup, 0, len(template.Groups))
+	for _, group := range template.Groups {
+		groups = append(groups, dto.TemplateGroup{
+			Key:            group.Key,
+			ParentKey:      group.ParentKey,
+			Name:           group.Name,
+			Description:    group.Description,
+			Joinable:       group.Joinable,
+			Permissions:    group.Permissions,
+			AutoMembership: group.AutoMembership,
+			MemberRoles:    group.MemberRoles,
+		})
+	}
+	return dto.GroupTemplateResponse{
+		ID:          template.ID.Hex(),
+		Name:        template.Name,
+		Description: template.Description,
+		Groups:      groups,
+		CreatedBy:   template.CreatedBy,
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+}
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go-falcon/internal/groups/dto"
+	"go-falcon/internal/groups/models"
+	"go-falcon/pkg/identity"
+)
+
+const (
+	// webhookTimeout bounds one delivery, including reading the response status
+	webhookTimeout = 10 * time.Second
+	// webhookConcurrency is how many deliveries run at once; a reconciliation changing many
+	// memberships queues behind it instead of flooding the endpoints
+	webhookConcurrency = 8
+	// webhookMinSecretLength keeps signatures from being guessable
+	webhookMinSecretLength = 16
+	// webhookTestEvent is delivered by the test endpoint
+	webhookTestEvent = "webhook.test"
+)
+
+// webhookClient delivers webhooks. Its dialer refuses internal addresses, so an endpoint cannot
+// reach services on the host or its network through a host name resolving there or a redirect.
+var webhookClient = &http.Client{
+	Timeout: webhookTimeout,
+	Transport: &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: webhookTimeout, Control: refuseInternalAddress}).DialContext,
+		TLSHandshakeTimeout: webhookTimeout,
+	},
+}
+
+// errWebhookInternalAddress is returned when a webhook endpoint is a loopback, private or
+// link-local address
+var errWebhookInternalAddress = errors.New("webhook endpoint resolves to a loopback, private or link-local address")
+
+// WebhookPayload is the JSON body POSTed to group webhooks
+type WebhookPayload struct {
+	ID           string                `json:"id"`
+	Event        string                `json:"event"`
+	Timestamp    time.Time             `json:"timestamp"`
+	Group        *WebhookGroup         `json:"group,omitempty"`
+	CharacterID  int64                 `json:"character_id,omitempty"`
+	PermissionID string                `json:"permission_id,omitempty"`
+	Actor        *identity.Attribution `json:"actor,omitempty"`
+}
+
+// WebhookGroup identifies the group of a webhook event
+type WebhookGroup struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ListWebhooks returns every group webhook
+func (s *Service) ListWebhooks(ctx context.Context) (*dto.ListWebhooksOutput, error) {
+	webhooks, err := s.repo.ListWebhooks(ctx, bson.M{})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list webhooks", err)
+	}
+
+	output := &dto.ListWebhooksOutput{}
+	output.Body.Webhooks = make([]dto.WebhookResponse, 0, len(webhooks))
+	for i := range webhooks {
+		output.Body.Webhooks = append(output.Body.Webhooks, webhookToResponse(&webhooks[i]))
+	}
+	return output, nil
+}
+
+// CreateWebhook adds a webhook; without a secret one is generated and returned once
+func (s *Service) CreateWebhook(ctx context.Context, input *dto.CreateWebhookInput, createdBy int64) (*dto.WebhookOutput, error) {
+	webhook, err := webhookFromBody(&input.Body)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		if webhook.Secret, err = generateWebhookSecret(); err != nil {
+			return nil, huma.Error500InternalServerError("Failed to generate webhook secret", err)
+		}
+	}
+	webhook.CreatedBy = createdBy
+	webhook.CreatedAt = webhook.UpdatedAt
+
+	if err := s.repo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, huma.Error500InternalServerError("Failed to create webhook", err)
+	}
+
+	slog.Info("Created group webhook", "webhook_id", webhook.ID.Hex(), "name", webhook.Name, "created_by", createdBy)
+	response := webhookToResponse(webhook)
+	response.Secret = webhook.Secret
+	return &dto.WebhookOutput{Body: response}, nil
+}
+
+// UpdateWebhook replaces the configuration of a webhook, keeping its secret when none is given
+func (s *Service) UpdateWebhook(ctx context.Context, input *dto.UpdateWebhookInput) (*dto.WebhookOutput, error) {
+	existing, err := s.getWebhook(ctx, input.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := webhookFromBody(&input.Body)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = existing.Secret
+	}
+	webhook.ID = existing.ID
+	webhook.CreatedBy = existing.CreatedBy
+	webhook.CreatedAt = existing.CreatedAt
+	webhook.LastDeliveryAt = existing.LastDeliveryAt
+	webhook.LastStatusCode = existing.LastStatusCode
+	webhook.LastError = existing.LastError
+	webhook.ConsecutiveFailures = existing.ConsecutiveFailures
+
+	found, err := s.repo.ReplaceWebhook(ctx, webhook)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to update webhook", err)
+	}
+	if !found {
+		return nil, huma.Error404NotFound("Webhook not found")
+	}
+
+	return &dto.WebhookOutput{Body: webhookToResponse(webhook)}, nil
+}
+
+// DeleteWebhook removes a webhook
+func (s *Service) DeleteWebhook(ctx context.Context, input *dto.WebhookIDInput) (*dto.SuccessOutput, error) {
+	id, err := primitive.ObjectIDFromHex(input.WebhookID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid webhook ID")
+	}
+
+	deleted, err := s.repo.DeleteWebhook(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete webhook", err)
+	}
+	if !deleted {
+		return nil, huma.Error404NotFound("Webhook not found")
+	}
+
+	return &dto.SuccessOutput{
+		Body: dto.SuccessResponse{
+			Message: "Webhook deleted successfully",
+		},
+	}, nil
+}
+
+// TestWebhook delivers a webhook.test event right away, even to an inactive webhook, and returns
+// the webhook with the outcome recorded
+func (s *Service) TestWebhook(ctx context.Context, input *dto.WebhookIDInput) (*dto.WebhookOutput, error) {
+	webhook, err := s.getWebhook(ctx, input.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &WebhookPayload{
+		ID:        uuid.New().String(),
+		Event:     webhookTestEvent,
+		Timestamp: time.Now().UTC(),
+		Actor:     identity.AttributionFromContext(ctx),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to encode webhook payload", err)
+	}
+	s.deliverWebhook(ctx, webhook, payload, body)
+
+	webhook, err = s.getWebhook(ctx, input.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.WebhookOutput{Body: webhookToResponse(webhook)}, nil
+}
+
+// emitMembershipWebhook reports a membership change to the subscribed webhooks
+func (s *Service) emitMembershipWebhook(ctx context.Context, groupID primitive.ObjectID, characterID int64, added bool) {
+	event := models.WebhookMemberRemoved
+	if added {
+		event = models.WebhookMemberAdded
+	}
+	s.emitWebhook(ctx, event, groupID, characterID, "")
+}
+
+// emitWebhook delivers an event to the subscribed webhooks in the background. Deliveries never
+// fail or delay the change they report; their outcome is recorded on the webhook.
+func (s *Service) emitWebhook(ctx context.Context, event string, groupID primitive.ObjectID, characterID int64, permissionID string) {
+	payload := &WebhookPayload{
+		ID:           uuid.New().String(),
+		Event:        event,
+		Timestamp:    time.Now().UTC(),
+		CharacterID:  characterID,
+		PermissionID: permissionID,
+		Actor:        identity.AttributionFromContext(ctx),
+	}
+
+	go s.dispatchWebhook(context.WithoutCancel(ctx), payload, groupID)
+}
+
+func (s *Service) dispatchWebhook(ctx context.Context, payload *WebhookPayload, groupID primitive.ObjectID) {
+	webhooks, err := s.repo.ListWebhooks(ctx, bson.M{"is_active": true})
+	if err != nil {
+		slog.Error("Failed to load group webhooks", "event", payload.Event, "error", err)
+		return
+	}
+	var subscribed []*models.GroupWebhook
+	for i := range webhooks {
+		if webhooks[i].Subscribes(payload.Event, groupID) {
+			subscribed = append(subscribed, &webhooks[i])
+		}
+	}
+	if len(subscribed) == 0 {
+		return
+	}
+
+	payload.Group = &WebhookGroup{ID: groupID.Hex()}
+	if group, err := s.repo.GetGroupByID(ctx, groupID); err == nil && group != nil {
+		payload.Group.Name = group.Name
+		payload.Group.Type = string(group.Type)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("Failed to encode webhook payload", "event", payload.Event, "error", err)
+		return
+	}
+
+	for _, webhook := range subscribed {
+		s.webhookSlots <- struct{}{}
+		s.deliverWebhook(ctx, webhook, payload, body)
+		<-s.webhookSlots
+	}
+}
+
+// deliverWebhook POSTs one signed payload and records the outcome. The signature header is
+// "sha256=" followed by the hex HMAC-SHA256 of "<X-Falcon-Timestamp>.<body>" keyed with the secret,
+// so receivers can reject replays of old deliveries.
+func (s *Service) deliverWebhook(ctx context.Context, webhook *models.GroupWebhook, payload *WebhookPayload, body []byte) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	statusCode, err := func() (int, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+		if err != nil {
+			return 0, err
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("User-Agent", "go-falcon-webhooks")
+		request.Header.Set("X-Falcon-Event", payload.Event)
+		request.Header.Set("X-Falcon-Delivery", payload.ID)
+		request.Header.Set("X-Falcon-Timestamp", timestamp)
+		request.Header.Set("X-Falcon-Signature", "sha256="+signWebhook(webhook.Secret, timestamp, body))
+
+		response, err := webhookClient.Do(request)
+		if err != nil {
+			return 0, err
+		}
+		defer response.Body.Close()
+		io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			return response.StatusCode, fmt.Errorf("endpoint returned %s", response.Status)
+		}
+		return response.StatusCode, nil
+	}()
+
+	if err != nil {
+		slog.Warn("Group webhook delivery failed",
+			"webhook_id", webhook.ID.Hex(), "event", payload.Event, "delivery_id", payload.ID, "error", err)
+	}
+	if recordErr := s.repo.RecordWebhookDelivery(ctx, webhook.ID, statusCode, err); recordErr != nil {
+		slog.Error("Failed to record webhook delivery", "webhook_id", webhook.ID.Hex(), "error", recordErr)
+	}
+}
+
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func (s *Service) getWebhook(ctx context.Context, webhookIDHex string) (*models.GroupWebhook, error) {
+	id, err := primitive.ObjectIDFromHex(webhookIDHex)
+	if err != nil {
+		return nil, huma.Error400BadRequest("Invalid webhook ID")
+	}
+	webhook, err := s.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get webhook", err)
+	}
+	if webhook == nil {
+		return nil, huma.Error404NotFound("Webhook not found")
+	}
+	return webhook, nil
+}
+
+// webhookFromBody validates a webhook body
+func webhookFromBody(body *dto.WebhookBody) (*models.GroupWebhook, error) {
+	endpoint, err := url.Parse(body.URL)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return nil, huma.Error400BadRequest("url must be an absolute http or https URL")
+	}
+	if internalHost(endpoint.Hostname()) {
+		return nil, huma.Error400BadRequest("url must not point to a loopback, private or link-local address")
+	}
+	if body.Secret != "" && len(body.Secret) < webhookMinSecretLength {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("secret must be at least %d characters", webhookMinSecretLength))
+	}
+
+	groupIDs := make([]primitive.ObjectID, 0, len(body.GroupIDs))
+	for _, hexID := range body.GroupIDs {
+		groupID, err := primitive.ObjectIDFromHex(hexID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid group ID: " + hexID)
+		}
+		groupIDs = append(groupIDs, groupID)
+	}
+
+	return &models.GroupWebhook{
+		Name:      body.Name,
+		URL:       body.URL,
+		Secret:    body.Secret,
+		Events:    body.Events,
+		GroupIDs:  groupIDs,
+		IsActive:  body.IsActive,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// internalHost reports whether a URL host is localhost or an internal IP address. Host names are
+// checked again when delivering, against every address they resolve to.
+func internalHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip, err := netip.ParseAddr(host)
+	return err == nil && internalAddress(ip)
+}
+
+// refuseInternalAddress is the dialer hook of webhookClient, run for each resolved address
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if internalAddress(ip) {
+		return errWebhookInternalAddress
+	}
+	return nil
+}
+
+// internalAddress reports whether an IP address is not publicly routable: loopback, private,
+// link-local, unspecified or multicast
+func internalAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast()
+}
+
+// webhookToResponse converts a stored webhook to its API shape, without the secret
+func webhookToResponse(webhook *models.GroupWebhook) dto.WebhookResponse {
+	response := dto.WebhookResponse{
+		ID:                  webhook.ID.Hex(),
+		Name:                webhook.Name,
+		URL:                 webhook.URL,
+		Events:              webhook.Events,
+		GroupIDs:            make([]string, 0, len(webhook.GroupIDs)),
+		IsActive:            webhook.IsActive,
+		LastDeliveryAt:      webhook.LastDeliveryAt,
+		LastStatusCode:      webhook.LastStatusCode,
+		LastError:           webhook.LastError,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		CreatedBy:           webhook.CreatedBy,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+	for _, groupID := range webhook.GroupIDs {
+		response.GroupIDs = append(response.GroupIDs, groupID.Hex())
+	}
+	if response.Events == nil {
+		response.Events = []string{}
+	}
+	return response
+}
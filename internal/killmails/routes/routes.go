@@ -64,7 +64,7 @@ func RegisterKillmailRoutes(api huma.API, basePath string, service *services.Ser
 		Path:          basePath + "/character/{character_id}/stats",
 		Summary:       "Get character killmail statistics",
 		Description:   "Returns killmail statistics for a character, including last ships used in tracked categories.",
-		Tags:          []string{"Character Stats"},
+		Tags:          []string{"Killmails / Character Stats"},
 		DefaultStatus: http.StatusOK,
 	}, func(ctx context.Context, input *dto.GetCharacterStatsInput) (*dto.CharacterStatsOutput, error) {
 		stats, err := service.GetCharacterStats(ctx, input.CharacterID)
@@ -86,7 +86,7 @@ func RegisterKillmailRoutes(api huma.API, basePath string, service *services.Ser
 		Path:          basePath + "/character/{character_id}/last-ship/{category}",
 		Summary:       "Get character's last ship in a category",
 		Description:   "Returns the last ship used by a character in a specific category (interdictor, forcerecon, strategic, hic, monitor, blackops, marauders, fax, dread, carrier, super, titan, lancer).",
-		Tags:          []string{"Character Stats"},
+		Tags:          []string{"Killmails / Character Stats"},
 		DefaultStatus: http.StatusOK,
 	}, func(ctx context.Context, input *dto.GetCharacterLastShipByCategoryInput) (*dto.LastShipByCategoryOutput, error) {
 		ship, err := service.GetCharacterLastShipByCategory(ctx, input.CharacterID, input.Category)
@@ -108,7 +108,7 @@ func RegisterKillmailRoutes(api huma.API, basePath string, service *services.Ser
 		Path:          basePath + "/characters/by-category/{category}",
 		Summary:       "Get characters by ship category",
 		Description:   "Returns characters who have used ships in a specific category.",
-		Tags:          []string{"Character Stats"},
+		Tags:          []string{"Killmails / Character Stats"},
 		DefaultStatus: http.StatusOK,
 	}, func(ctx context.Context, input *dto.GetCharactersByShipCategoryInput) (*dto.CharactersByShipCategoryOutput, error) {
 		characters, err := service.GetCharactersByShipCategory(ctx, input.Category, input.Limit)
@@ -132,7 +132,7 @@ func RegisterKillmailRoutes(api huma.API, basePath string, service *services.Ser
 		Path:          basePath + "/characters/by-ship-type/{ship_type_id}",
 		Summary:       "Get characters by ship type",
 		Description:   "Returns characters who last used a specific ship type ID.",
-		Tags:          []string{"Character Stats"},
+		Tags:          []string{"Killmails / Character Stats"},
 		DefaultStatus: http.StatusOK,
 	}, func(ctx context.Context, input *dto.GetCharactersByShipTypeInput) (*dto.CharactersByShipTypeOutput, error) {
 		characters, err := service.GetCharactersByShipType(ctx, input.ShipTypeID, input.Limit)
@@ -156,7 +156,7 @@ func RegisterKillmailRoutes(api huma.API, basePath string, service *services.Ser
 		Path:          basePath + "/characters/recent-activity",
 		Summary:       "Get recent character activity",
 		Description:   "Returns characters with recent killmail activity in tracked ship categories.",
-		Tags:          []string{"Character Stats"},
+		Tags:          []string{"Killmails / Character Stats"},
 		DefaultStatus: http.StatusOK,
 	}, func(ctx context.Context, input *dto.GetRecentCharacterActivityInput) (*dto.RecentCharacterActivityOutput, error) {
 		since := time.Now().Add(-time.Duration(input.Hours) * time.Hour)
@@ -181,7 +181,7 @@ func RegisterKillmailRoutes(api huma.API, basePath string, service *services.Ser
 		Path:          basePath + "/categories",
 		Summary:       "Get tracked ship categories",
 		Description:   "Returns the list of ship categories that are being tracked for character statistics.",
-		Tags:          []string{"Character Stats"},
+		Tags:          []string{"Killmails / Character Stats"},
 		DefaultStatus: http.StatusOK,
 	}, func(ctx context.Context, input *struct{}) (*dto.TrackedCategoriesOutput, error) {
 		categories, err := service.GetTrackedCategories(ctx)
@@ -204,7 +204,7 @@ func RegisterKillmailRoutes(api huma.API, basePath string, service *services.Ser
 		Path:          basePath + "/categories/stats",
 		Summary:       "Get category statistics",
 		Description:   "Returns statistics about how many characters have used ships in each tracked category.",
-		Tags:          []string{"Character Stats"},
+		Tags:          []string{"Killmails / Character Stats"},
 		DefaultStatus: http.StatusOK,
 	}, func(ctx context.Context, input *struct{}) (*dto.CategoryStatsOutput, error) {
 		stats, err := service.GetCategoryStats(ctx)
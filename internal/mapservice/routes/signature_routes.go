@@ -7,6 +7,7 @@ import (
 	"github.com/danielgtaylor/huma/v2"
 	"go-falcon/internal/mapservice/dto"
 	"go-falcon/internal/mapservice/services"
+	"go-falcon/pkg/apidocs"
 	"go-falcon/pkg/middleware"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -21,6 +22,7 @@ func RegisterSignatureRoutes(api huma.API, basePath string, service *services.Ma
 		Summary:     "Create signature",
 		Description: "Create a new map signature",
 		Tags:        []string{"Map / Signatures"},
+		Extensions:  apidocs.RequiresPermission("map:signatures:manage", "map:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.CreateSignatureInputWithAuth) (*dto.SignatureResponseOutput, error) {
 		// Validate authentication and signature management access
@@ -154,6 +156,7 @@ func RegisterSignatureRoutes(api huma.API, basePath string, service *services.Ma
 		Summary:     "Update signature",
 		Description: "Update an existing signature",
 		Tags:        []string{"Map / Signatures"},
+		Extensions:  apidocs.RequiresPermission("map:signatures:manage", "map:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.UpdateSignatureInputWithAuth) (*dto.SignatureResponseOutput, error) {
 		// Validate authentication and signature management access
@@ -195,6 +198,7 @@ func RegisterSignatureRoutes(api huma.API, basePath string, service *services.Ma
 		Summary:     "Delete signature",
 		Description: "Delete a signature",
 		Tags:        []string{"Map / Signatures"},
+		Extensions:  apidocs.RequiresPermission("map:signatures:manage", "map:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.DeleteSignatureInputWithAuth) (*dto.DeleteSignatureResponseOutput, error) {
 		// Validate authentication and signature management access
@@ -239,6 +243,7 @@ func RegisterSignatureRoutes(api huma.API, basePath string, service *services.Ma
 		Summary:     "Batch signature operations",
 		Description: "Create, update, or delete multiple signatures in one operation",
 		Tags:        []string{"Map / Signatures"},
+		Extensions:  apidocs.RequiresPermission("map:signatures:manage", "map:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.BatchSignatureInputWithAuth) (*dto.BatchSignatureOutput, error) {
 		// Validate authentication and signature management access
@@ -8,6 +8,7 @@ import (
 	"go-falcon/internal/mapservice/dto"
 	"go-falcon/internal/mapservice/models"
 	"go-falcon/internal/mapservice/services"
+	"go-falcon/pkg/apidocs"
 	"go-falcon/pkg/middleware"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -22,6 +23,7 @@ func RegisterWormholeRoutes(api huma.API, basePath string, service *services.Map
 		Summary:     "Create wormhole",
 		Description: "Create a new wormhole connection",
 		Tags:        []string{"Map / Wormholes"},
+		Extensions:  apidocs.RequiresPermission("map:wormholes:manage", "map:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.CreateWormholeInputWithAuth) (*dto.WormholeResponseOutput, error) {
 		// Validate authentication and wormhole management access
@@ -180,6 +182,7 @@ func RegisterWormholeRoutes(api huma.API, basePath string, service *services.Map
 		Summary:     "Update wormhole",
 		Description: "Update an existing wormhole connection",
 		Tags:        []string{"Map / Wormholes"},
+		Extensions:  apidocs.RequiresPermission("map:wormholes:manage", "map:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.UpdateWormholeInputWithAuth) (*dto.WormholeResponseOutput, error) {
 		// Validate authentication and wormhole management access
@@ -229,6 +232,7 @@ func RegisterWormholeRoutes(api huma.API, basePath string, service *services.Map
 		Summary:     "Delete wormhole",
 		Description: "Delete a wormhole connection",
 		Tags:        []string{"Map / Wormholes"},
+		Extensions:  apidocs.RequiresPermission("map:wormholes:manage", "map:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.DeleteWormholeInputWithAuth) (*dto.DeleteWormholeResponseOutput, error) {
 		// Validate authentication and wormhole management access
@@ -273,6 +277,7 @@ func RegisterWormholeRoutes(api huma.API, basePath string, service *services.Map
 		Summary:     "Batch wormhole operations",
 		Description: "Create, update, or delete multiple wormholes in one operation",
 		Tags:        []string{"Map / Wormholes"},
+		Extensions:  apidocs.RequiresPermission("map:wormholes:manage", "map:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.BatchWormholeInputWithAuth) (*dto.BatchWormholeOutput, error) {
 		// Validate authentication and wormhole management access
@@ -54,6 +54,11 @@ type SearchOrdersInput struct {
 	SortOrder  string  `query:"sort_order" enum:"asc,desc" default:"asc" doc:"Sort order"`
 }
 
+// CompareHubsInput represents the input for comparing a type's prices across market hubs
+type CompareHubsInput struct {
+	TypeID int `path:"type_id" validate:"required" minimum:"1" maximum:"2147483647" doc:"Item type ID"`
+}
+
 // TriggerFetchInput represents the input for manually triggering a market data fetch
 type TriggerFetchInput struct {
 	RegionID int  `query:"region_id,omitempty" minimum:"10000001" maximum:"11000033" doc:"Specific region to fetch (optional, fetches all if not specified)"`
@@ -153,6 +153,40 @@ type TriggerFetchOutput struct {
 	} `json:"body"`
 }
 
+// HubPrice represents a type's prices and volume at a single market hub
+type HubPrice struct {
+	Hub           string    `json:"hub" doc:"Trade hub name"`
+	StationID     int64     `json:"station_id" doc:"Hub station ID"`
+	StationName   string    `json:"station_name,omitempty" doc:"Hub station name"`
+	RegionID      int       `json:"region_id" doc:"Region ID"`
+	SystemID      int       `json:"system_id" doc:"Solar system ID"`
+	LowestSell    *float64  `json:"lowest_sell,omitempty" doc:"Lowest sell order price"`
+	HighestBuy    *float64  `json:"highest_buy,omitempty" doc:"Highest buy order price"`
+	Spread        *float64  `json:"spread,omitempty" doc:"Lowest sell minus highest buy"`
+	SpreadPercent *float64  `json:"spread_percent,omitempty" doc:"Spread as a percentage of the lowest sell price"`
+	SellVolume    int64     `json:"sell_volume" doc:"Units remaining on sell orders"`
+	BuyVolume     int64     `json:"buy_volume" doc:"Units remaining on buy orders"`
+	SellOrders    int       `json:"sell_orders" doc:"Number of sell orders"`
+	BuyOrders     int       `json:"buy_orders" doc:"Number of buy orders"`
+	LastUpdated   time.Time `json:"last_updated,omitempty" doc:"When the newest order at this hub was fetched"`
+}
+
+// HubComparison represents a type's prices across all configured market hubs
+type HubComparison struct {
+	TypeID       int        `json:"type_id" doc:"Item type ID"`
+	TypeName     string     `json:"type_name,omitempty" doc:"Item type name"`
+	Hubs         []HubPrice `json:"hubs" doc:"Prices per hub, in configured order"`
+	CheapestSell string     `json:"cheapest_sell_hub,omitempty" doc:"Hub with the lowest sell price (best place to buy)"`
+	BestBuy      string     `json:"best_buy_hub,omitempty" doc:"Hub with the highest buy price (best place to sell)"`
+	HaulMargin   *float64   `json:"haul_margin,omitempty" doc:"Best buy price minus cheapest sell price across hubs, per unit"`
+	GeneratedAt  time.Time  `json:"generated_at" doc:"When this comparison was computed"`
+}
+
+// HubComparisonOutput represents the response for hub price comparison
+type HubComparisonOutput struct {
+	Body HubComparison `json:"body"`
+}
+
 // Helper function to convert model to DTO
 func MarketOrderFromModel(order *models.MarketOrder) MarketOrder {
 	return MarketOrder{
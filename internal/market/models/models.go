@@ -150,3 +150,30 @@ type FetchStatistics struct {
 	AverageFetchTime    int64          `json:"average_fetch_time_ms"`
 	PaginationBreakdown map[string]int `json:"pagination_breakdown"`
 }
+
+// MarketHub is a trade hub station included in multi-region price comparisons
+type MarketHub struct {
+	Name      string `json:"name"`
+	StationID int64  `json:"station_id"`
+	RegionID  int    `json:"region_id"`
+	SystemID  int    `json:"system_id"`
+}
+
+// DefaultMarketHubs are the four empire trade hubs compared when MARKET_HUB_STATIONS is not set
+var DefaultMarketHubs = []MarketHub{
+	{Name: "Jita", StationID: 60003760, RegionID: 10000002, SystemID: 30000142},
+	{Name: "Amarr", StationID: 60008494, RegionID: 10000043, SystemID: 30002187},
+	{Name: "Dodixie", StationID: 60011866, RegionID: 10000032, SystemID: 30002659},
+	{Name: "Rens", StationID: 60004588, RegionID: 10000030, SystemID: 30002510},
+}
+
+// HubOrderStats aggregates one side (buy or sell) of a type's orders at a single station
+type HubOrderStats struct {
+	LocationID  int64     `bson:"location_id"`
+	IsBuyOrder  bool      `bson:"is_buy_order"`
+	MinPrice    float64   `bson:"min_price"`
+	MaxPrice    float64   `bson:"max_price"`
+	Volume      int64     `bson:"volume"`
+	OrderCount  int       `bson:"order_count"`
+	LastUpdated time.Time `bson:"last_updated"`
+}
@@ -33,7 +33,7 @@ func New(mongodb *database.MongoDB, redis *database.Redis, eveGateway *evegatewa
 	repository := services.NewRepository(mongodb)
 
 	// Create main service
-	service := services.NewService(repository, eveGateway, sdeService)
+	service := services.NewService(repository, eveGateway, sdeService, redis)
 
 	// Create fetch service
 	fetchService := services.NewFetchService(repository, eveGateway, sdeService)
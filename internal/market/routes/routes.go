@@ -20,7 +20,7 @@ func RegisterMarketRoutes(api huma.API, basePath string, service *services.Servi
 		Path:        basePath + "/orders/station/{location_id}",
 		Summary:     "Get market orders for station/structure",
 		Description: "Retrieve market orders for a specific station or structure. Data is sourced from database with hourly ESI updates.",
-		Tags:        []string{"Market Orders"},
+		Tags:        []string{"Market / Orders"},
 		Errors:      []int{400, 404, 500},
 	}, func(ctx context.Context, input *dto.GetStationOrdersInput) (*dto.MarketOrdersOutput, error) {
 		page := input.Page
@@ -49,7 +49,7 @@ func RegisterMarketRoutes(api huma.API, basePath string, service *services.Servi
 		Path:        basePath + "/orders/region/{region_id}",
 		Summary:     "Get market orders for region",
 		Description: "Retrieve market orders for a specific region. Data is sourced from database with hourly ESI updates.",
-		Tags:        []string{"Market Orders"},
+		Tags:        []string{"Market / Orders"},
 		Errors:      []int{400, 404, 500},
 	}, func(ctx context.Context, input *dto.GetRegionOrdersInput) (*dto.MarketOrdersOutput, error) {
 		page := input.Page
@@ -78,7 +78,7 @@ func RegisterMarketRoutes(api huma.API, basePath string, service *services.Servi
 		Path:        basePath + "/orders/item/{type_id}",
 		Summary:     "Get market orders for item type",
 		Description: "Retrieve market orders for a specific item type. Data is sourced from database with hourly ESI updates.",
-		Tags:        []string{"Market Orders"},
+		Tags:        []string{"Market / Orders"},
 		Errors:      []int{400, 404, 500},
 	}, func(ctx context.Context, input *dto.GetItemOrdersInput) (*dto.MarketOrdersOutput, error) {
 		page := input.Page
@@ -107,7 +107,7 @@ func RegisterMarketRoutes(api huma.API, basePath string, service *services.Servi
 		Path:        basePath + "/orders/search",
 		Summary:     "Search market orders",
 		Description: "Advanced search for market orders with multiple filter options. Supports filtering by type, region, system, location, price, and volume ranges.",
-		Tags:        []string{"Market Orders"},
+		Tags:        []string{"Market / Orders"},
 		Errors:      []int{400, 500},
 	}, func(ctx context.Context, input *dto.SearchOrdersInput) (*dto.MarketOrdersOutput, error) {
 		return service.SearchOrders(ctx, input)
@@ -122,12 +122,25 @@ func RegisterMarketRoutes(api huma.API, basePath string, service *services.Servi
 		Path:        basePath + "/summary/region/{region_id}",
 		Summary:     "Get region market summary",
 		Description: "Retrieve aggregate market statistics for a specific region including order counts, unique items, and trading activity.",
-		Tags:        []string{"Market Summary"},
+		Tags:        []string{"Market / Summary"},
 		Errors:      []int{400, 404, 500},
 	}, func(ctx context.Context, input *dto.GetRegionSummaryInput) (*dto.RegionSummaryOutput, error) {
 		return service.GetRegionSummary(ctx, input.RegionID)
 	})
 
+	// Compare prices across trade hubs
+	huma.Register(api, huma.Operation{
+		OperationID: "market-compare-hubs",
+		Method:      "GET",
+		Path:        basePath + "/compare/{type_id}",
+		Summary:     "Compare item prices across trade hubs",
+		Description: "Compare lowest sell, highest buy, spread and volume for an item type at each configured trade hub (Jita, Amarr, Dodixie and Rens by default), with the best hubs to buy and sell. Uses imported orders; results are cached briefly.",
+		Tags:        []string{"Market / Summary"},
+		Errors:      []int{400, 500},
+	}, func(ctx context.Context, input *dto.CompareHubsInput) (*dto.HubComparisonOutput, error) {
+		return service.CompareHubs(ctx, input.TypeID)
+	})
+
 	// Module Status and Administration
 
 	// Get market module status
@@ -150,7 +163,7 @@ func RegisterMarketRoutes(api huma.API, basePath string, service *services.Servi
 		Path:        basePath + "/fetch/trigger",
 		Summary:     "Trigger market data fetch",
 		Description: "Manually trigger a market data fetch operation. Can fetch all regions or a specific region. Use 'force' parameter to bypass cache timing.",
-		Tags:        []string{"Market Administration"},
+		Tags:        []string{"Market / Administration"},
 		Errors:      []int{400, 500},
 	}, func(ctx context.Context, input *dto.TriggerFetchInput) (*dto.TriggerFetchOutput, error) {
 		force := input.Force
@@ -171,7 +184,7 @@ func RegisterMarketRoutes(api huma.API, basePath string, service *services.Servi
 		Path:        basePath + "/debug/collections",
 		Summary:     "Debug: Check collection document counts",
 		Description: "Temporary debug endpoint to check document counts in market collections.",
-		Tags:        []string{"Market Debug"},
+		Tags:        []string{"Market / Debug"},
 	}, func(ctx context.Context, input *struct{}) (*struct {
 		Body struct {
 			MarketOrders     int64 `json:"market_orders"`
@@ -209,7 +222,7 @@ func RegisterMarketRoutes(api huma.API, basePath string, service *services.Servi
 		Path:        basePath + "/debug/sample-temp",
 		Summary:     "Debug: Show sample documents from temp collection",
 		Description: "Show first 5 documents from market_orders_temp for debugging.",
-		Tags:        []string{"Market Debug"},
+		Tags:        []string{"Market / Debug"},
 	}, func(ctx context.Context, input *struct{}) (*struct {
 		Body struct {
 			SampleOrders []interface{} `json:"sample_orders"`
@@ -183,6 +183,47 @@ func (r *Repository) GetRegionSummary(ctx context.Context, regionID int) (*model
 	return summary, nil
 }
 
+// GetHubOrderStats aggregates a type's buy and sell orders at each of the given stations
+func (r *Repository) GetHubOrderStats(ctx context.Context, typeID int, locationIDs []int64) ([]models.HubOrderStats, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"type_id":     typeID,
+			"location_id": bson.M{"$in": locationIDs},
+		}},
+		{"$group": bson.M{
+			"_id":          bson.M{"location_id": "$location_id", "is_buy_order": "$is_buy_order"},
+			"min_price":    bson.M{"$min": "$price"},
+			"max_price":    bson.M{"$max": "$price"},
+			"volume":       bson.M{"$sum": bson.M{"$toLong": "$volume_remain"}},
+			"order_count":  bson.M{"$sum": 1},
+			"last_updated": bson.M{"$max": "$fetched_at"},
+		}},
+		{"$project": bson.M{
+			"_id":          0,
+			"location_id":  "$_id.location_id",
+			"is_buy_order": "$_id.is_buy_order",
+			"min_price":    1,
+			"max_price":    1,
+			"volume":       1,
+			"order_count":  1,
+			"last_updated": 1,
+		}},
+	}
+
+	cursor, err := r.ordersCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate hub order stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stats []models.HubOrderStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode hub order stats: %w", err)
+	}
+
+	return stats, nil
+}
+
 // BulkUpsertOrders performs bulk upsert of market orders
 func (r *Repository) BulkUpsertOrders(ctx context.Context, collectionName string, orders []models.MarketOrder) error {
 	if len(orders) == 0 {
@@ -2,12 +2,17 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strconv"
+	"strings"
 	"time"
 
 	"go-falcon/internal/market/dto"
 	"go-falcon/internal/market/models"
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/database"
 	"go-falcon/pkg/evegateway"
 	"go-falcon/pkg/sde"
 
@@ -21,10 +26,11 @@ type Service struct {
 	fetchService *FetchService
 	eveGateway   *evegateway.Client
 	sdeService   sde.SDEService
+	redis        *database.Redis
 }
 
 // NewService creates a new service instance
-func NewService(repository *Repository, eveGateway *evegateway.Client, sdeService sde.SDEService) *Service {
+func NewService(repository *Repository, eveGateway *evegateway.Client, sdeService sde.SDEService, redis *database.Redis) *Service {
 	fetchService := NewFetchService(repository, eveGateway, sdeService)
 
 	return &Service{
@@ -32,6 +38,7 @@ func NewService(repository *Repository, eveGateway *evegateway.Client, sdeServic
 		fetchService: fetchService,
 		eveGateway:   eveGateway,
 		sdeService:   sdeService,
+		redis:        redis,
 	}
 }
 
@@ -313,6 +320,175 @@ func (s *Service) GetRegionSummary(ctx context.Context, regionID int) (*dto.Regi
 	return result, nil
 }
 
+// CompareHubs compares a type's prices across the configured market hubs, caching the result in Redis
+func (s *Service) CompareHubs(ctx context.Context, typeID int) (*dto.HubComparisonOutput, error) {
+	hubs := s.marketHubs()
+	cacheKey := hubComparisonCacheKey(typeID, hubs)
+
+	if s.redis != nil {
+		if cached, err := s.redis.Get(ctx, cacheKey); err == nil {
+			var comparison dto.HubComparison
+			if err := json.Unmarshal([]byte(cached), &comparison); err == nil {
+				return &dto.HubComparisonOutput{Body: comparison}, nil
+			}
+		}
+	}
+
+	locationIDs := make([]int64, len(hubs))
+	for i, hub := range hubs {
+		locationIDs[i] = hub.StationID
+	}
+
+	stats, err := s.repository.GetHubOrderStats(ctx, typeID, locationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare hub prices: %w", err)
+	}
+
+	comparison := buildHubComparison(typeID, hubs, stats)
+	if typeInfo, err := s.sdeService.GetType(strconv.Itoa(typeID)); err == nil && typeInfo != nil {
+		comparison.TypeName = typeInfo.Name["en"]
+	}
+
+	if s.redis != nil {
+		if data, err := json.Marshal(comparison); err == nil {
+			ttl := time.Duration(config.GetMarketCompareCacheSeconds()) * time.Second
+			if err := s.redis.Set(ctx, cacheKey, string(data), ttl); err != nil {
+				slog.WarnContext(ctx, "Failed to cache hub comparison", "type_id", typeID, "error", err)
+			}
+		}
+	}
+
+	return &dto.HubComparisonOutput{Body: *comparison}, nil
+}
+
+// marketHubs returns the hubs to compare: MARKET_HUB_STATIONS resolved through the SDE, or the default empire hubs
+func (s *Service) marketHubs() []models.MarketHub {
+	stationIDs := config.GetMarketHubStationIDs()
+	if len(stationIDs) == 0 {
+		return models.DefaultMarketHubs
+	}
+
+	known := make(map[int64]models.MarketHub, len(models.DefaultMarketHubs))
+	for _, hub := range models.DefaultMarketHubs {
+		known[hub.StationID] = hub
+	}
+
+	hubs := make([]models.MarketHub, 0, len(stationIDs))
+	for _, stationID := range stationIDs {
+		if hub, ok := known[int64(stationID)]; ok {
+			hubs = append(hubs, hub)
+			continue
+		}
+
+		station, err := s.sdeService.GetStaStation(stationID)
+		if err != nil || station == nil {
+			slog.Warn("Ignoring unknown market hub station", "station_id", stationID)
+			continue
+		}
+		hubs = append(hubs, models.MarketHub{
+			Name:      station.StationName,
+			StationID: int64(station.StationID),
+			RegionID:  station.RegionID,
+			SystemID:  station.SolarSystemID,
+		})
+	}
+
+	if len(hubs) == 0 {
+		return models.DefaultMarketHubs
+	}
+	return hubs
+}
+
+// hubComparisonCacheKey includes the hub stations so changing MARKET_HUB_STATIONS never serves a stale layout
+func hubComparisonCacheKey(typeID int, hubs []models.MarketHub) string {
+	stations := make([]string, len(hubs))
+	for i, hub := range hubs {
+		stations[i] = strconv.FormatInt(hub.StationID, 10)
+	}
+	return fmt.Sprintf("market:compare:%d:%s", typeID, strings.Join(stations, ","))
+}
+
+// buildHubComparison turns per-station order aggregates into per-hub prices, spreads and the best cross-hub trade
+func buildHubComparison(typeID int, hubs []models.MarketHub, stats []models.HubOrderStats) *dto.HubComparison {
+	type sides struct{ buy, sell *models.HubOrderStats }
+	byStation := make(map[int64]*sides, len(hubs))
+	for i := range stats {
+		stat := &stats[i]
+		entry := byStation[stat.LocationID]
+		if entry == nil {
+			entry = &sides{}
+			byStation[stat.LocationID] = entry
+		}
+		if stat.IsBuyOrder {
+			entry.buy = stat
+		} else {
+			entry.sell = stat
+		}
+	}
+
+	comparison := &dto.HubComparison{
+		TypeID:      typeID,
+		Hubs:        make([]dto.HubPrice, 0, len(hubs)),
+		GeneratedAt: time.Now(),
+	}
+
+	var cheapestSell, bestBuy *float64
+	for _, hub := range hubs {
+		price := dto.HubPrice{
+			Hub:       hub.Name,
+			StationID: hub.StationID,
+			RegionID:  hub.RegionID,
+			SystemID:  hub.SystemID,
+		}
+
+		if entry := byStation[hub.StationID]; entry != nil {
+			if entry.sell != nil {
+				lowest := entry.sell.MinPrice
+				price.LowestSell = &lowest
+				price.SellVolume = entry.sell.Volume
+				price.SellOrders = entry.sell.OrderCount
+				price.LastUpdated = entry.sell.LastUpdated
+			}
+			if entry.buy != nil {
+				highest := entry.buy.MaxPrice
+				price.HighestBuy = &highest
+				price.BuyVolume = entry.buy.Volume
+				price.BuyOrders = entry.buy.OrderCount
+				if entry.buy.LastUpdated.After(price.LastUpdated) {
+					price.LastUpdated = entry.buy.LastUpdated
+				}
+			}
+		}
+
+		if price.LowestSell != nil && price.HighestBuy != nil {
+			spread := *price.LowestSell - *price.HighestBuy
+			price.Spread = &spread
+			if *price.LowestSell > 0 {
+				percent := spread / *price.LowestSell * 100
+				price.SpreadPercent = &percent
+			}
+		}
+
+		if price.LowestSell != nil && (cheapestSell == nil || *price.LowestSell < *cheapestSell) {
+			cheapestSell = price.LowestSell
+			comparison.CheapestSell = hub.Name
+		}
+		if price.HighestBuy != nil && (bestBuy == nil || *price.HighestBuy > *bestBuy) {
+			bestBuy = price.HighestBuy
+			comparison.BestBuy = hub.Name
+		}
+
+		comparison.Hubs = append(comparison.Hubs, price)
+	}
+
+	if cheapestSell != nil && bestBuy != nil {
+		margin := *bestBuy - *cheapestSell
+		comparison.HaulMargin = &margin
+	}
+
+	return comparison
+}
+
 // GetMarketStatus retrieves the overall status of the market module
 func (s *Service) GetMarketStatus(ctx context.Context) (*dto.MarketStatusOutput, error) {
 	// Get fetch statuses for all regions
@@ -0,0 +1,66 @@
+package dto
+
+// AudienceSelectorInput selects recipients by user, group, corporation, alliance or everyone
+type AudienceSelectorInput struct {
+	Type          string   `json:"type" enum:"users,group,corporation,alliance,everyone" doc:"How recipients are selected"`
+	UserIDs       []string `json:"user_ids,omitempty" maxItems:"1000" doc:"User IDs (type users)"`
+	GroupID       string   `json:"group_id,omitempty" doc:"Group ID; all active members receive the notification (type group)"`
+	CorporationID int      `json:"corporation_id,omitempty" doc:"EVE corporation ID; all users with a character in it (type corporation)"`
+	AllianceID    int      `json:"alliance_id,omitempty" doc:"EVE alliance ID; all users with a character in it (type alliance)"`
+}
+
+// SendNotificationInput represents the input for sending a notification to one or more audiences
+type SendNotificationInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Body          struct {
+		Title           string                  `json:"title" minLength:"1" maxLength:"200" doc:"Notification title"`
+		Message         string                  `json:"message" minLength:"1" maxLength:"4000" doc:"Notification body"`
+		Level           string                  `json:"level,omitempty" enum:"info,warning,critical" default:"info" doc:"Notification severity"`
+		Audience        []AudienceSelectorInput `json:"audience" minItems:"1" maxItems:"20" doc:"Audience selectors; recipients matched by several selectors are notified once"`
+		AckRequired     bool                    `json:"ack_required,omitempty" doc:"Recipients must explicitly acknowledge the notification (CTA pings, policy changes)"`
+		ReminderMinutes int                     `json:"reminder_minutes,omitempty" minimum:"0" maximum:"10080" doc:"Minutes between reminders to recipients who have not acknowledged (defaults to NOTIFICATIONS_ACK_REMINDER_MINUTES; ack_required only)"`
+	}
+}
+
+// GetOperationInput represents the input for polling a send operation
+type GetOperationInput struct {
+	OperationID   string `path:"operation_id" doc:"Send operation ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// ListDeliveriesInput represents the input for listing a notification's per-recipient delivery status
+type ListDeliveriesInput struct {
+	NotificationID string `path:"notification_id" doc:"Notification ID"`
+	Status         string `query:"status" enum:"pending,delivered,failed,all" default:"all" doc:"Filter by delivery status"`
+	Page           int    `query:"page" minimum:"1" default:"1" doc:"Page number"`
+	Limit          int    `query:"limit" minimum:"1" maximum:"500" default:"100" doc:"Items per page"`
+	Authorization  string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie         string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// ListAcknowledgementsInput represents the input for viewing acknowledgement status of a notification
+type ListAcknowledgementsInput struct {
+	NotificationID string `path:"notification_id" doc:"Notification ID"`
+	Acknowledged   string `query:"acknowledged" enum:"true,false,all" default:"all" doc:"Filter recipients by acknowledgement"`
+	Page           int    `query:"page" minimum:"1" default:"1" doc:"Page number"`
+	Limit          int    `query:"limit" minimum:"1" maximum:"500" default:"100" doc:"Items per page"`
+	Authorization  string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie         string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// NotificationReceiptInput represents the input for marking a received notification as read or acknowledged
+type NotificationReceiptInput struct {
+	NotificationID string `path:"notification_id" doc:"Notification ID"`
+	Authorization  string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie         string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// ListMyNotificationsInput represents the input for listing the caller's notifications
+type ListMyNotificationsInput struct {
+	Page          int    `query:"page" minimum:"1" default:"1" doc:"Page number"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Items per page"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
@@ -0,0 +1,163 @@
+package dto
+
+import (
+	"time"
+
+	"go-falcon/internal/notifications/models"
+)
+
+// OperationResponse reports the progress of an asynchronous send operation
+type OperationResponse struct {
+	ID             string     `json:"id" doc:"Operation ID"`
+	NotificationID string     `json:"notification_id" doc:"Notification being delivered"`
+	Status         string     `json:"status" enum:"queued,running,completed,failed" doc:"Operation status"`
+	Total          int        `json:"total" doc:"Distinct recipients resolved from the audience (0 until expansion finishes)"`
+	Processed      int        `json:"processed" doc:"Recipients attempted so far"`
+	Delivered      int        `json:"delivered" doc:"Recipients delivered successfully"`
+	Failed         int        `json:"failed" doc:"Recipients whose delivery failed"`
+	Progress       float64    `json:"progress" doc:"Percentage of recipients processed"`
+	Error          string     `json:"error,omitempty" doc:"Error that stopped the operation"`
+	CreatedAt      time.Time  `json:"created_at"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// OperationOutput represents the output for a send operation
+type OperationOutput struct {
+	Body OperationResponse `json:"body"`
+}
+
+// DeliveryResponse is the delivery status of a notification for one recipient
+type DeliveryResponse struct {
+	UserID         string     `json:"user_id"`
+	Status         string     `json:"status" enum:"pending,delivered,failed"`
+	Error          string     `json:"error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	RemindersSent  int        `json:"reminders_sent"`
+	LastRemindedAt *time.Time `json:"last_reminded_at,omitempty"`
+}
+
+// DeliveryListResponse represents a paginated list of delivery records
+type DeliveryListResponse struct {
+	NotificationID string             `json:"notification_id"`
+	Deliveries     []DeliveryResponse `json:"deliveries"`
+	Total          int64              `json:"total"`
+	Page           int                `json:"page"`
+	Limit          int                `json:"limit"`
+}
+
+// DeliveryListOutput represents the output for listing delivery records
+type DeliveryListOutput struct {
+	Body DeliveryListResponse `json:"body"`
+}
+
+// AcknowledgementListResponse summarises who has read and acknowledged a notification
+type AcknowledgementListResponse struct {
+	NotificationID string             `json:"notification_id"`
+	AckRequired    bool               `json:"ack_required"`
+	Recipients     int64              `json:"recipients" doc:"Total recipients"`
+	Read           int64              `json:"read" doc:"Recipients who opened the notification"`
+	Acknowledged   int64              `json:"acknowledged" doc:"Recipients who acknowledged the notification"`
+	Outstanding    int64              `json:"outstanding" doc:"Recipients who have not acknowledged yet"`
+	Deliveries     []DeliveryResponse `json:"deliveries"`
+	Total          int64              `json:"total" doc:"Recipients matching the filter"`
+	Page           int                `json:"page"`
+	Limit          int                `json:"limit"`
+}
+
+// AcknowledgementListOutput represents the output for viewing acknowledgement status
+type AcknowledgementListOutput struct {
+	Body AcknowledgementListResponse `json:"body"`
+}
+
+// ReceiptResponse is the caller's read and acknowledgement state for a notification
+type ReceiptResponse struct {
+	NotificationID string     `json:"notification_id"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// ReceiptOutput represents the output for marking a notification as read or acknowledged
+type ReceiptOutput struct {
+	Body ReceiptResponse `json:"body"`
+}
+
+// NotificationResponse is a notification as seen by a recipient
+type NotificationResponse struct {
+	ID             string     `json:"id"`
+	Title          string     `json:"title"`
+	Message        string     `json:"message"`
+	Level          string     `json:"level" enum:"info,warning,critical"`
+	SenderName     string     `json:"sender_name"`
+	AckRequired    bool       `json:"ack_required"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// NotificationListResponse represents a paginated list of the caller's notifications
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Total         int64                  `json:"total"`
+	Page          int                    `json:"page"`
+	Limit         int                    `json:"limit"`
+}
+
+// NotificationListOutput represents the output for listing the caller's notifications
+type NotificationListOutput struct {
+	Body NotificationListResponse `json:"body"`
+}
+
+// NotificationsStatusResponse represents the module status response
+type NotificationsStatusResponse struct {
+	Module  string `json:"module" description:"Module name"`
+	Status  string `json:"status" enum:"healthy,unhealthy" description:"Module health status"`
+	Message string `json:"message,omitempty" description:"Optional status message or error details"`
+}
+
+// StatusOutput represents the module status output
+type StatusOutput struct {
+	Body NotificationsStatusResponse `json:"body"`
+}
+
+// ToOperationResponse converts an operation model into its response form
+func ToOperationResponse(operation *models.Operation) OperationResponse {
+	response := OperationResponse{
+		ID:             operation.ID.Hex(),
+		NotificationID: operation.NotificationID.Hex(),
+		Status:         string(operation.Status),
+		Total:          operation.Total,
+		Processed:      operation.Processed,
+		Delivered:      operation.Delivered,
+		Failed:         operation.Failed,
+		Error:          operation.Error,
+		CreatedAt:      operation.CreatedAt,
+		StartedAt:      operation.StartedAt,
+		CompletedAt:    operation.CompletedAt,
+		UpdatedAt:      operation.UpdatedAt,
+	}
+	if operation.Total > 0 {
+		response.Progress = float64(operation.Processed) / float64(operation.Total) * 100
+	} else if operation.Status == models.OperationCompleted {
+		response.Progress = 100
+	}
+	return response
+}
+
+// ToDeliveryResponse converts a delivery model into its response form
+func ToDeliveryResponse(delivery *models.Delivery) DeliveryResponse {
+	return DeliveryResponse{
+		UserID:         delivery.UserID,
+		Status:         string(delivery.Status),
+		Error:          delivery.Error,
+		DeliveredAt:    delivery.DeliveredAt,
+		ReadAt:         delivery.ReadAt,
+		AcknowledgedAt: delivery.AcknowledgedAt,
+		RemindersSent:  delivery.RemindersSent,
+		LastRemindedAt: delivery.LastRemindedAt,
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	NotificationsCollection = "notifications"
+	DeliveriesCollection    = "notification_deliveries"
+	OperationsCollection    = "notification_operations"
+)
+
+// AudienceType identifies how a selector resolves to recipients
+type AudienceType string
+
+const (
+	AudienceUsers       AudienceType = "users"
+	AudienceGroup       AudienceType = "group"
+	AudienceCorporation AudienceType = "corporation"
+	AudienceAlliance    AudienceType = "alliance"
+	AudienceEveryone    AudienceType = "everyone"
+)
+
+// AudienceSelector selects a set of recipients. Only the field matching Type is used.
+type AudienceSelector struct {
+	Type          AudienceType `bson:"type" json:"type"`
+	UserIDs       []string     `bson:"user_ids,omitempty" json:"user_ids,omitempty"`
+	GroupID       string       `bson:"group_id,omitempty" json:"group_id,omitempty"`
+	CorporationID int          `bson:"corporation_id,omitempty" json:"corporation_id,omitempty"`
+	AllianceID    int          `bson:"alliance_id,omitempty" json:"alliance_id,omitempty"`
+}
+
+// Notification is a message sent to one or more audiences
+type Notification struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Title             string             `bson:"title" json:"title"`
+	Message           string             `bson:"message" json:"message"`
+	Level             string             `bson:"level" json:"level"`
+	Audience          []AudienceSelector `bson:"audience" json:"audience"`
+	AckRequired       bool               `bson:"ack_required" json:"ack_required"`
+	ReminderMinutes   int                `bson:"reminder_minutes,omitempty" json:"reminder_minutes,omitempty"`
+	SenderUserID      string             `bson:"sender_user_id" json:"sender_user_id"`
+	SenderCharacterID int                `bson:"sender_character_id" json:"sender_character_id"`
+	SenderName        string             `bson:"sender_name" json:"sender_name"`
+	OperationID       primitive.ObjectID `bson:"operation_id" json:"operation_id"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// DeliveryStatus is the per-recipient delivery state
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery records a notification's delivery to a single user.
+// For acknowledgement-required notifications RemindAt holds the next reminder time
+// and is cleared once the user acknowledges or the reminder limit is reached.
+type Delivery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	NotificationID primitive.ObjectID `bson:"notification_id" json:"notification_id"`
+	UserID         string             `bson:"user_id" json:"user_id"`
+	Status         DeliveryStatus     `bson:"status" json:"status"`
+	Error          string             `bson:"error,omitempty" json:"error,omitempty"`
+	DeliveredAt    *time.Time         `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	ReadAt         *time.Time         `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	AckRequired    bool               `bson:"ack_required" json:"ack_required"`
+	AcknowledgedAt *time.Time         `bson:"acknowledged_at,omitempty" json:"acknowledged_at,omitempty"`
+	RemindAt       *time.Time         `bson:"remind_at,omitempty" json:"remind_at,omitempty"`
+	RemindersSent  int                `bson:"reminders_sent" json:"reminders_sent"`
+	LastRemindedAt *time.Time         `bson:"last_reminded_at,omitempty" json:"last_reminded_at,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// OperationStatus is the state of an asynchronous send operation
+type OperationStatus string
+
+const (
+	OperationQueued    OperationStatus = "queued"
+	OperationRunning   OperationStatus = "running"
+	OperationCompleted OperationStatus = "completed"
+	OperationFailed    OperationStatus = "failed"
+)
+
+// Operation tracks the progress of expanding and delivering a notification
+type Operation struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	NotificationID primitive.ObjectID `bson:"notification_id" json:"notification_id"`
+	Status         OperationStatus    `bson:"status" json:"status"`
+	Total          int                `bson:"total" json:"total"`
+	Processed      int                `bson:"processed" json:"processed"`
+	Delivered      int                `bson:"delivered" json:"delivered"`
+	Failed         int                `bson:"failed" json:"failed"`
+	Error          string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedBy      string             `bson:"created_by" json:"created_by"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt      *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	CompletedAt    *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,86 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"go-falcon/internal/notifications/routes"
+	"go-falcon/internal/notifications/services"
+	"go-falcon/pkg/database"
+	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/module"
+	"go-falcon/pkg/permissions"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/go-chi/chi/v5"
+)
+
+// Module represents the notifications module
+type Module struct {
+	*module.BaseModule
+	service              *services.Service
+	repository           *services.Repository
+	permissionMiddleware *middleware.PermissionMiddleware
+}
+
+// New creates a new notifications module instance
+func New(mongodb *database.MongoDB, redis *database.Redis, permissionMiddleware *middleware.PermissionMiddleware) *Module {
+	repository := services.NewRepository(mongodb)
+	service := services.NewService(repository)
+
+	return &Module{
+		BaseModule:           module.NewBaseModule("notifications", mongodb, redis),
+		service:              service,
+		repository:           repository,
+		permissionMiddleware: permissionMiddleware,
+	}
+}
+
+// SetNotifier sets the real-time channel used to deliver notifications (the websocket module)
+func (m *Module) SetNotifier(notifier services.UserNotifier) {
+	m.service.SetNotifier(notifier)
+}
+
+// RegisterUnifiedRoutes registers all notifications routes with the unified API gateway
+func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string) {
+	routes.RegisterNotificationsRoutes(api, basePath, m.service, m.permissionMiddleware)
+}
+
+// Routes registers routes on a Chi router (implements module.Module interface)
+func (m *Module) Routes(r chi.Router) {
+	// Notifications module uses only Huma v2 unified routes
+}
+
+// Initialize performs module initialization tasks
+func (m *Module) Initialize(ctx context.Context) error {
+	return m.repository.CreateIndexes(ctx)
+}
+
+// RegisterPermissions registers notifications-specific permissions
+func (m *Module) RegisterPermissions(ctx context.Context, permissionManager *permissions.PermissionManager) error {
+	notificationPermissions := []permissions.Permission{
+		{
+			ID:          routes.SendPermission,
+			Service:     "notifications",
+			Resource:    "bulk",
+			Action:      "send",
+			IsStatic:    false,
+			Name:        "Send Notifications",
+			Description: "Send notifications to groups, corporations, alliances or all users and view their delivery status",
+			Category:    "User Management",
+			CreatedAt:   time.Now(),
+		},
+	}
+
+	return permissionManager.RegisterServicePermissions(ctx, notificationPermissions)
+}
+
+// SendAckReminders re-sends due acknowledgement reminders (called by the scheduler)
+func (m *Module) SendAckReminders(ctx context.Context) (int, error) {
+	return m.service.SendAckReminders(ctx)
+}
+
+// GetService returns the service instance for this module
+func (m *Module) GetService() *services.Service {
+	return m.service
+}
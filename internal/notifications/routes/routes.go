@@ -0,0 +1,202 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go-falcon/internal/notifications/dto"
+	"go-falcon/internal/notifications/services"
+	"go-falcon/pkg/apidocs"
+	"go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// SendPermission allows sending notifications to audiences and inspecting their delivery
+const SendPermission = "notifications:bulk:send"
+
+// RegisterNotificationsRoutes registers notifications routes on a shared Huma API
+func RegisterNotificationsRoutes(api huma.API, basePath string, service *services.Service, permissionMiddleware *middleware.PermissionMiddleware) {
+	// Status endpoint (public, no auth required)
+	huma.Register(api, huma.Operation{
+		OperationID: "notifications-get-status",
+		Method:      http.MethodGet,
+		Path:        basePath + "/status",
+		Summary:     "Get notifications module status",
+		Description: "Returns the health status of the notifications module",
+		Tags:        []string{"Module Status"},
+	}, func(ctx context.Context, input *struct{}) (*dto.StatusOutput, error) {
+		return &dto.StatusOutput{Body: *service.GetStatus(ctx)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "notifications-send",
+		Method:        http.MethodPost,
+		Path:          basePath,
+		Summary:       "Send notification",
+		Description:   "Sends a notification to one or more audiences (users, group, corporation, alliance or everyone). Recipients are expanded and delivered in the background; poll the returned operation for progress.",
+		Tags:          []string{"Notifications"},
+		DefaultStatus: http.StatusAccepted,
+		Extensions:    apidocs.RequiresPermission(SendPermission),
+		Security:      []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.SendNotificationInput) (*dto.OperationOutput, error) {
+		user, err := permissionMiddleware.RequirePermission(ctx, input.Authorization, input.Cookie, SendPermission)
+		if err != nil {
+			return nil, err
+		}
+
+		operation, err := service.Send(ctx, user, input)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAudience) {
+				return nil, huma.Error400BadRequest(err.Error())
+			}
+			return nil, huma.Error500InternalServerError("Failed to send notification", err)
+		}
+		return &dto.OperationOutput{Body: dto.ToOperationResponse(operation)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "notifications-get-operation",
+		Method:      http.MethodGet,
+		Path:        basePath + "/operations/{operation_id}",
+		Summary:     "Get send operation",
+		Description: "Returns the progress of a notification send operation",
+		Tags:        []string{"Notifications"},
+		Extensions:  apidocs.RequiresPermission(SendPermission),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.GetOperationInput) (*dto.OperationOutput, error) {
+		if _, err := permissionMiddleware.RequirePermission(ctx, input.Authorization, input.Cookie, SendPermission); err != nil {
+			return nil, err
+		}
+
+		operation, err := service.GetOperation(ctx, input.OperationID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get operation", err)
+		}
+		if operation == nil {
+			return nil, huma.Error404NotFound("Operation not found")
+		}
+		return &dto.OperationOutput{Body: dto.ToOperationResponse(operation)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "notifications-list-deliveries",
+		Method:      http.MethodGet,
+		Path:        basePath + "/{notification_id}/deliveries",
+		Summary:     "List delivery status",
+		Description: "Lists the per-recipient delivery status of a notification",
+		Tags:        []string{"Notifications"},
+		Extensions:  apidocs.RequiresPermission(SendPermission),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ListDeliveriesInput) (*dto.DeliveryListOutput, error) {
+		if _, err := permissionMiddleware.RequirePermission(ctx, input.Authorization, input.Cookie, SendPermission); err != nil {
+			return nil, err
+		}
+
+		response, err := service.ListDeliveries(ctx, input)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list deliveries", err)
+		}
+		if response == nil {
+			return nil, huma.Error404NotFound("Notification not found")
+		}
+		return &dto.DeliveryListOutput{Body: *response}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "notifications-list-acknowledgements",
+		Method:      http.MethodGet,
+		Path:        basePath + "/{notification_id}/acknowledgements",
+		Summary:     "Get acknowledgement status",
+		Description: "Returns how many recipients have read and acknowledged a notification, with a page of recipients filtered by acknowledgement",
+		Tags:        []string{"Notifications"},
+		Extensions:  apidocs.RequiresPermission(SendPermission),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ListAcknowledgementsInput) (*dto.AcknowledgementListOutput, error) {
+		if _, err := permissionMiddleware.RequirePermission(ctx, input.Authorization, input.Cookie, SendPermission); err != nil {
+			return nil, err
+		}
+
+		response, err := service.ListAcknowledgements(ctx, input)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get acknowledgement status", err)
+		}
+		if response == nil {
+			return nil, huma.Error404NotFound("Notification not found")
+		}
+		return &dto.AcknowledgementListOutput{Body: *response}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "notifications-mark-read",
+		Method:      http.MethodPost,
+		Path:        basePath + "/{notification_id}/read",
+		Summary:     "Mark notification read",
+		Description: "Records that the authenticated user has read a notification addressed to them",
+		Tags:        []string{"Notifications"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.NotificationReceiptInput) (*dto.ReceiptOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		receipt, err := service.MarkRead(ctx, user.UserID, input.NotificationID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to mark notification read", err)
+		}
+		if receipt == nil {
+			return nil, huma.Error404NotFound("Notification not found")
+		}
+		return &dto.ReceiptOutput{Body: *receipt}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "notifications-acknowledge",
+		Method:      http.MethodPost,
+		Path:        basePath + "/{notification_id}/ack",
+		Summary:     "Acknowledge notification",
+		Description: "Acknowledges an acknowledgement-required notification addressed to the authenticated user and stops further reminders",
+		Tags:        []string{"Notifications"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.NotificationReceiptInput) (*dto.ReceiptOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		receipt, err := service.Acknowledge(ctx, user.UserID, input.NotificationID)
+		if err != nil {
+			if errors.Is(err, services.ErrAckNotRequired) {
+				return nil, huma.Error400BadRequest(err.Error())
+			}
+			return nil, huma.Error500InternalServerError("Failed to acknowledge notification", err)
+		}
+		if receipt == nil {
+			return nil, huma.Error404NotFound("Notification not found")
+		}
+		return &dto.ReceiptOutput{Body: *receipt}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "notifications-list-mine",
+		Method:      http.MethodGet,
+		Path:        basePath + "/me",
+		Summary:     "List my notifications",
+		Description: "Lists notifications addressed to the authenticated user, newest first",
+		Tags:        []string{"Notifications"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ListMyNotificationsInput) (*dto.NotificationListOutput, error) {
+		user, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := service.ListMyNotifications(ctx, user.UserID, input)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list notifications", err)
+		}
+		return &dto.NotificationListOutput{Body: *response}, nil
+	})
+}
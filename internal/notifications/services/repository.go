@@ -0,0 +1,426 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	groupsModels "go-falcon/internal/groups/models"
+	"go-falcon/internal/notifications/models"
+	"go-falcon/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// userProfilesCollection is owned by the auth module; notifications only reads it to resolve audiences
+const userProfilesCollection = "user_profiles"
+
+type Repository struct {
+	db            *database.MongoDB
+	notifications *mongo.Collection
+	deliveries    *mongo.Collection
+	operations    *mongo.Collection
+}
+
+func NewRepository(db *database.MongoDB) *Repository {
+	return &Repository{
+		db:            db,
+		notifications: db.Database.Collection(models.NotificationsCollection),
+		deliveries:    db.Database.Collection(models.DeliveriesCollection),
+		operations:    db.Database.Collection(models.OperationsCollection),
+	}
+}
+
+// CreateNotification inserts a notification together with its queued operation
+func (r *Repository) CreateNotification(ctx context.Context, notification *models.Notification, operation *models.Operation) error {
+	notification.ID = primitive.NewObjectID()
+	operation.ID = primitive.NewObjectID()
+	notification.OperationID = operation.ID
+	operation.NotificationID = notification.ID
+
+	if _, err := r.notifications.InsertOne(ctx, notification); err != nil {
+		return err
+	}
+	_, err := r.operations.InsertOne(ctx, operation)
+	return err
+}
+
+// GetNotification retrieves a notification by its ID
+func (r *Repository) GetNotification(ctx context.Context, id primitive.ObjectID) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.notifications.FindOne(ctx, bson.M{"_id": id}).Decode(&notification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// GetNotificationsByIDs retrieves notifications keyed by ID
+func (r *Repository) GetNotificationsByIDs(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]models.Notification, error) {
+	cursor, err := r.notifications.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []models.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	result := make(map[primitive.ObjectID]models.Notification, len(notifications))
+	for _, notification := range notifications {
+		result[notification.ID] = notification
+	}
+	return result, nil
+}
+
+// GetOperation retrieves a send operation by its ID
+func (r *Repository) GetOperation(ctx context.Context, id primitive.ObjectID) (*models.Operation, error) {
+	var operation models.Operation
+	err := r.operations.FindOne(ctx, bson.M{"_id": id}).Decode(&operation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &operation, nil
+}
+
+// UpdateOperation sets fields on a send operation
+func (r *Repository) UpdateOperation(ctx context.Context, id primitive.ObjectID, fields bson.M) error {
+	fields["updated_at"] = time.Now()
+	_, err := r.operations.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+	return err
+}
+
+// IncrementOperation adds batch results to the operation's progress counters
+func (r *Repository) IncrementOperation(ctx context.Context, id primitive.ObjectID, delivered, failed int) error {
+	_, err := r.operations.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$inc": bson.M{"processed": delivered + failed, "delivered": delivered, "failed": failed},
+		"$set": bson.M{"updated_at": time.Now()},
+	})
+	return err
+}
+
+// InsertPendingDeliveries creates pending delivery records for a batch of recipients.
+// Recipients that already have a record are skipped.
+func (r *Repository) InsertPendingDeliveries(ctx context.Context, notification *models.Notification, userIDs []string) error {
+	now := time.Now()
+	var remindAt *time.Time
+	if notification.AckRequired && notification.ReminderMinutes > 0 {
+		next := now.Add(time.Duration(notification.ReminderMinutes) * time.Minute)
+		remindAt = &next
+	}
+
+	docs := make([]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		docs[i] = models.Delivery{
+			NotificationID: notification.ID,
+			UserID:         userID,
+			Status:         models.DeliveryPending,
+			AckRequired:    notification.AckRequired,
+			RemindAt:       remindAt,
+			CreatedAt:      now,
+		}
+	}
+
+	_, err := r.deliveries.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+// MarkDelivered marks a batch of recipients as delivered
+func (r *Repository) MarkDelivered(ctx context.Context, notificationID primitive.ObjectID, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	_, err := r.deliveries.UpdateMany(ctx,
+		bson.M{"notification_id": notificationID, "user_id": bson.M{"$in": userIDs}},
+		bson.M{"$set": bson.M{"status": models.DeliveryDelivered, "delivered_at": now}},
+	)
+	return err
+}
+
+// MarkFailed records a failed delivery for a single recipient
+func (r *Repository) MarkFailed(ctx context.Context, notificationID primitive.ObjectID, userID, reason string) error {
+	_, err := r.deliveries.UpdateOne(ctx,
+		bson.M{"notification_id": notificationID, "user_id": userID},
+		bson.M{"$set": bson.M{"status": models.DeliveryFailed, "error": reason}},
+	)
+	return err
+}
+
+// ListDeliveries returns a page of delivery records for a notification
+func (r *Repository) ListDeliveries(ctx context.Context, notificationID primitive.ObjectID, status string, page, limit int) ([]models.Delivery, int64, error) {
+	filter := bson.M{"notification_id": notificationID}
+	if status != "" && status != "all" {
+		filter["status"] = status
+	}
+	return r.findDeliveries(ctx, filter, bson.D{{Key: "user_id", Value: 1}}, page, limit)
+}
+
+// ListAcknowledgements returns a page of delivery records filtered by acknowledgement ("true", "false" or "all")
+func (r *Repository) ListAcknowledgements(ctx context.Context, notificationID primitive.ObjectID, acknowledged string, page, limit int) ([]models.Delivery, int64, error) {
+	filter := bson.M{"notification_id": notificationID}
+	switch acknowledged {
+	case "true":
+		filter["acknowledged_at"] = bson.M{"$exists": true}
+	case "false":
+		filter["acknowledged_at"] = bson.M{"$exists": false}
+	}
+	return r.findDeliveries(ctx, filter, bson.D{{Key: "user_id", Value: 1}}, page, limit)
+}
+
+// CountReceipts returns how many recipients a notification has and how many have read and acknowledged it
+func (r *Repository) CountReceipts(ctx context.Context, notificationID primitive.ObjectID) (recipients, read, acknowledged int64, err error) {
+	filter := bson.M{"notification_id": notificationID}
+	if recipients, err = r.deliveries.CountDocuments(ctx, filter); err != nil {
+		return
+	}
+	if read, err = r.deliveries.CountDocuments(ctx, bson.M{"notification_id": notificationID, "read_at": bson.M{"$exists": true}}); err != nil {
+		return
+	}
+	acknowledged, err = r.deliveries.CountDocuments(ctx, bson.M{"notification_id": notificationID, "acknowledged_at": bson.M{"$exists": true}})
+	return
+}
+
+// GetDelivery retrieves a user's delivery record for a notification
+func (r *Repository) GetDelivery(ctx context.Context, notificationID primitive.ObjectID, userID string) (*models.Delivery, error) {
+	var delivery models.Delivery
+	err := r.deliveries.FindOne(ctx, bson.M{"notification_id": notificationID, "user_id": userID}).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// MarkRead records when the user first read the notification
+func (r *Repository) MarkRead(ctx context.Context, notificationID primitive.ObjectID, userID string, at time.Time) error {
+	_, err := r.deliveries.UpdateOne(ctx,
+		bson.M{"notification_id": notificationID, "user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": at}},
+	)
+	return err
+}
+
+// MarkAcknowledged records the user's acknowledgement and cancels further reminders
+func (r *Repository) MarkAcknowledged(ctx context.Context, notificationID primitive.ObjectID, userID string, at time.Time) error {
+	_, err := r.deliveries.UpdateOne(ctx,
+		bson.M{"notification_id": notificationID, "user_id": userID, "acknowledged_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"acknowledged_at": at}, "$unset": bson.M{"remind_at": ""}},
+	)
+	return err
+}
+
+// ListDueReminders returns unacknowledged deliveries whose next reminder is due, oldest first
+func (r *Repository) ListDueReminders(ctx context.Context, now time.Time, limit int) ([]models.Delivery, error) {
+	filter := bson.M{
+		"remind_at":       bson.M{"$lte": now},
+		"acknowledged_at": bson.M{"$exists": false},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "remind_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []models.Delivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordReminder counts a sent reminder and schedules the next one, or stops reminders when next is nil
+func (r *Repository) RecordReminder(ctx context.Context, id primitive.ObjectID, at time.Time, next *time.Time) error {
+	set := bson.M{"last_reminded_at": at}
+	update := bson.M{
+		"$inc": bson.M{"reminders_sent": 1},
+		"$set": set,
+	}
+	if next != nil {
+		set["remind_at"] = *next
+	} else {
+		update["$unset"] = bson.M{"remind_at": ""}
+	}
+
+	_, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// ListUserDeliveries returns a page of a user's received notifications, newest first
+func (r *Repository) ListUserDeliveries(ctx context.Context, userID string, page, limit int) ([]models.Delivery, int64, error) {
+	filter := bson.M{"user_id": userID}
+	return r.findDeliveries(ctx, filter, bson.D{{Key: "created_at", Value: -1}}, page, limit)
+}
+
+func (r *Repository) findDeliveries(ctx context.Context, filter bson.M, sort bson.D, page, limit int) ([]models.Delivery, int64, error) {
+	total, err := r.deliveries.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []models.Delivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// StreamAudienceUserIDs calls fn with the user ID of every profile matched by the selector.
+// Profiles are read through a cursor with the given batch size so large audiences are never loaded at once.
+// The same user ID may be passed more than once when a user has several matching characters.
+func (r *Repository) StreamAudienceUserIDs(ctx context.Context, selector models.AudienceSelector, batchSize int, fn func(userID string)) error {
+	switch selector.Type {
+	case models.AudienceUsers:
+		for _, userID := range selector.UserIDs {
+			fn(userID)
+		}
+		return nil
+	case models.AudienceGroup:
+		return r.streamGroupUserIDs(ctx, selector.GroupID, batchSize, fn)
+	case models.AudienceCorporation:
+		return r.streamProfileUserIDs(ctx, bson.M{"corporation_id": selector.CorporationID}, batchSize, fn)
+	case models.AudienceAlliance:
+		return r.streamProfileUserIDs(ctx, bson.M{"alliance_id": selector.AllianceID}, batchSize, fn)
+	case models.AudienceEveryone:
+		return r.streamProfileUserIDs(ctx, bson.M{}, batchSize, fn)
+	}
+	return nil
+}
+
+func (r *Repository) streamProfileUserIDs(ctx context.Context, filter bson.M, batchSize int, fn func(userID string)) error {
+	opts := options.Find().
+		SetProjection(bson.M{"user_id": 1}).
+		SetBatchSize(int32(batchSize))
+
+	cursor, err := r.db.Database.Collection(userProfilesCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var profile struct {
+			UserID string `bson:"user_id"`
+		}
+		if err := cursor.Decode(&profile); err != nil {
+			return err
+		}
+		if profile.UserID != "" {
+			fn(profile.UserID)
+		}
+	}
+	return cursor.Err()
+}
+
+// streamGroupUserIDs resolves active group members to users, one batch of characters at a time
+func (r *Repository) streamGroupUserIDs(ctx context.Context, groupID string, batchSize int, fn func(userID string)) error {
+	objectID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().
+		SetProjection(bson.M{"character_id": 1}).
+		SetBatchSize(int32(batchSize))
+
+	cursor, err := r.db.Database.Collection(groupsModels.MembershipsCollection).
+		Find(ctx, bson.M{"group_id": objectID, "is_active": true}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	characterIDs := make([]int64, 0, batchSize)
+	flush := func() error {
+		if len(characterIDs) == 0 {
+			return nil
+		}
+		err := r.streamProfileUserIDs(ctx, bson.M{"character_id": bson.M{"$in": characterIDs}}, batchSize, fn)
+		characterIDs = characterIDs[:0]
+		return err
+	}
+
+	for cursor.Next(ctx) {
+		var membership struct {
+			CharacterID int64 `bson:"character_id"`
+		}
+		if err := cursor.Decode(&membership); err != nil {
+			return err
+		}
+		characterIDs = append(characterIDs, membership.CharacterID)
+		if len(characterIDs) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// CheckHealth verifies database connectivity
+func (r *Repository) CheckHealth(ctx context.Context) error {
+	return r.db.HealthCheck(ctx)
+}
+
+// CreateIndexes creates the indexes used by notification queries
+func (r *Repository) CreateIndexes(ctx context.Context) error {
+	if _, err := r.notifications.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "sender_user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}); err != nil {
+		return err
+	}
+
+	if _, err := r.deliveries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "notification_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "notification_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "remind_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	}); err != nil {
+		return err
+	}
+
+	_, err := r.operations.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	return err
+}
@@ -0,0 +1,494 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/internal/notifications/dto"
+	notificationModels "go-falcon/internal/notifications/models"
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/identity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidAudience is returned when an audience selector is missing the field its type requires
+var ErrInvalidAudience = errors.New("invalid audience selector")
+
+// ErrAckNotRequired is returned when acknowledging a notification that does not ask for acknowledgement
+var ErrAckNotRequired = errors.New("notification does not require acknowledgement")
+
+// UserNotifier pushes a notification to a user's connected clients
+type UserNotifier interface {
+	NotifyUser(ctx context.Context, userID, message string, data map[string]interface{}) error
+}
+
+type Service struct {
+	repository *Repository
+	notifier   UserNotifier
+	batchSize  int
+}
+
+func NewService(repository *Repository) *Service {
+	return &Service{
+		repository: repository,
+		batchSize:  config.GetNotificationBatchSize(),
+	}
+}
+
+// SetNotifier sets the real-time channel used to deliver notifications
+func (s *Service) SetNotifier(notifier UserNotifier) {
+	s.notifier = notifier
+}
+
+// Send stores a notification and starts delivering it in the background.
+// The returned operation can be polled for progress.
+func (s *Service) Send(ctx context.Context, sender *models.AuthenticatedUser, input *dto.SendNotificationInput) (*notificationModels.Operation, error) {
+	audience, err := toAudience(input.Body.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	level := input.Body.Level
+	if level == "" {
+		level = "info"
+	}
+
+	reminderMinutes := 0
+	if input.Body.AckRequired {
+		reminderMinutes = input.Body.ReminderMinutes
+		if reminderMinutes == 0 {
+			reminderMinutes = config.GetNotificationAckReminderMinutes()
+		}
+	}
+
+	now := time.Now()
+	notification := &notificationModels.Notification{
+		Title:             input.Body.Title,
+		Message:           input.Body.Message,
+		Level:             level,
+		Audience:          audience,
+		AckRequired:       input.Body.AckRequired,
+		ReminderMinutes:   reminderMinutes,
+		SenderUserID:      sender.UserID,
+		SenderCharacterID: sender.CharacterID,
+		SenderName:        sender.CharacterName,
+		CreatedAt:         now,
+	}
+	operation := &notificationModels.Operation{
+		Status:    notificationModels.OperationQueued,
+		CreatedBy: sender.UserID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repository.CreateNotification(ctx, notification, operation); err != nil {
+		return nil, fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	// Delivery outlives the request, so it must not inherit the request's cancellation;
+	// it keeps the sender's identity so delivery logs stay attributed
+	go s.deliver(identity.Detach(ctx), notification, operation.ID)
+
+	return operation, nil
+}
+
+// deliver expands the audience and delivers the notification batch by batch, updating the operation as it goes
+func (s *Service) deliver(ctx context.Context, notification *notificationModels.Notification, operationID primitive.ObjectID) {
+	startedAt := time.Now()
+	if err := s.repository.UpdateOperation(ctx, operationID, bson.M{"status": notificationModels.OperationRunning, "started_at": startedAt}); err != nil {
+		slog.ErrorContext(ctx, "Failed to start notification operation", "operation_id", operationID.Hex(), "error", err)
+		return
+	}
+
+	recipients, err := s.expandAudience(ctx, notification.Audience)
+	if err != nil {
+		s.failOperation(ctx, operationID, fmt.Errorf("failed to resolve audience: %w", err))
+		return
+	}
+
+	if err := s.repository.UpdateOperation(ctx, operationID, bson.M{"total": len(recipients)}); err != nil {
+		slog.WarnContext(ctx, "Failed to record notification recipient count", "operation_id", operationID.Hex(), "error", err)
+	}
+
+	for start := 0; start < len(recipients); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(recipients) {
+			end = len(recipients)
+		}
+
+		if err := s.deliverBatch(ctx, notification, operationID, recipients[start:end]); err != nil {
+			s.failOperation(ctx, operationID, err)
+			return
+		}
+	}
+
+	completedAt := time.Now()
+	if err := s.repository.UpdateOperation(ctx, operationID, bson.M{"status": notificationModels.OperationCompleted, "completed_at": completedAt}); err != nil {
+		slog.ErrorContext(ctx, "Failed to complete notification operation", "operation_id", operationID.Hex(), "error", err)
+	}
+
+	slog.InfoContext(ctx, "Notification delivered",
+		"notification_id", notification.ID.Hex(),
+		"recipients", len(recipients),
+		"duration", completedAt.Sub(startedAt).String())
+}
+
+// deliverBatch records pending deliveries for a batch, pushes each one and stores the per-recipient outcome
+func (s *Service) deliverBatch(ctx context.Context, notification *notificationModels.Notification, operationID primitive.ObjectID, userIDs []string) error {
+	if err := s.repository.InsertPendingDeliveries(ctx, notification, userIDs); err != nil {
+		return fmt.Errorf("failed to record deliveries: %w", err)
+	}
+
+	data := pushData(notification)
+
+	delivered := make([]string, 0, len(userIDs))
+	failed := 0
+	for _, userID := range userIDs {
+		err := errors.New("real-time delivery is not configured")
+		if s.notifier != nil {
+			err = s.notifier.NotifyUser(ctx, userID, notification.Message, data)
+		}
+		if err != nil {
+			failed++
+			if markErr := s.repository.MarkFailed(ctx, notification.ID, userID, err.Error()); markErr != nil {
+				slog.WarnContext(ctx, "Failed to record failed notification delivery", "user_id", userID, "error", markErr)
+			}
+			continue
+		}
+		delivered = append(delivered, userID)
+	}
+
+	if err := s.repository.MarkDelivered(ctx, notification.ID, delivered); err != nil {
+		return fmt.Errorf("failed to record delivered notifications: %w", err)
+	}
+	return s.repository.IncrementOperation(ctx, operationID, len(delivered), failed)
+}
+
+// pushData is the payload sent with a notification over WebSocket
+func pushData(notification *notificationModels.Notification) map[string]interface{} {
+	return map[string]interface{}{
+		"notification_id": notification.ID.Hex(),
+		"title":           notification.Title,
+		"level":           notification.Level,
+		"sender_name":     notification.SenderName,
+		"ack_required":    notification.AckRequired,
+	}
+}
+
+// expandAudience resolves every selector to a de-duplicated list of user IDs
+func (s *Service) expandAudience(ctx context.Context, audience []notificationModels.AudienceSelector) ([]string, error) {
+	seen := make(map[string]struct{})
+	var recipients []string
+	add := func(userID string) {
+		if _, ok := seen[userID]; ok {
+			return
+		}
+		seen[userID] = struct{}{}
+		recipients = append(recipients, userID)
+	}
+
+	for _, selector := range audience {
+		if err := s.repository.StreamAudienceUserIDs(ctx, selector, s.batchSize, add); err != nil {
+			return nil, fmt.Errorf("%s selector: %w", selector.Type, err)
+		}
+	}
+	return recipients, nil
+}
+
+func (s *Service) failOperation(ctx context.Context, operationID primitive.ObjectID, cause error) {
+	slog.ErrorContext(ctx, "Notification operation failed", "operation_id", operationID.Hex(), "error", cause)
+
+	completedAt := time.Now()
+	if err := s.repository.UpdateOperation(ctx, operationID, bson.M{
+		"status":       notificationModels.OperationFailed,
+		"error":        cause.Error(),
+		"completed_at": completedAt,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to record notification operation failure", "operation_id", operationID.Hex(), "error", err)
+	}
+}
+
+// GetOperation returns a send operation by ID, or nil if it does not exist
+func (s *Service) GetOperation(ctx context.Context, operationID string) (*notificationModels.Operation, error) {
+	id, err := primitive.ObjectIDFromHex(operationID)
+	if err != nil {
+		return nil, nil
+	}
+	return s.repository.GetOperation(ctx, id)
+}
+
+// ListDeliveries returns a page of per-recipient delivery records, or nil if the notification does not exist
+func (s *Service) ListDeliveries(ctx context.Context, input *dto.ListDeliveriesInput) (*dto.DeliveryListResponse, error) {
+	id, err := primitive.ObjectIDFromHex(input.NotificationID)
+	if err != nil {
+		return nil, nil
+	}
+
+	notification, err := s.repository.GetNotification(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	if notification == nil {
+		return nil, nil
+	}
+
+	deliveries, total, err := s.repository.ListDeliveries(ctx, id, input.Status, input.Page, input.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	response := &dto.DeliveryListResponse{
+		NotificationID: input.NotificationID,
+		Deliveries:     make([]dto.DeliveryResponse, len(deliveries)),
+		Total:          total,
+		Page:           input.Page,
+		Limit:          input.Limit,
+	}
+	for i := range deliveries {
+		response.Deliveries[i] = dto.ToDeliveryResponse(&deliveries[i])
+	}
+	return response, nil
+}
+
+// ListMyNotifications returns a page of notifications addressed to the user, newest first
+func (s *Service) ListMyNotifications(ctx context.Context, userID string, input *dto.ListMyNotificationsInput) (*dto.NotificationListResponse, error) {
+	deliveries, total, err := s.repository.ListUserDeliveries(ctx, userID, input.Page, input.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	ids := make([]primitive.ObjectID, len(deliveries))
+	for i, delivery := range deliveries {
+		ids[i] = delivery.NotificationID
+	}
+	notifications, err := s.repository.GetNotificationsByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notifications: %w", err)
+	}
+
+	response := &dto.NotificationListResponse{
+		Notifications: make([]dto.NotificationResponse, 0, len(deliveries)),
+		Total:         total,
+		Page:          input.Page,
+		Limit:         input.Limit,
+	}
+	for _, delivery := range deliveries {
+		notification, ok := notifications[delivery.NotificationID]
+		if !ok {
+			continue
+		}
+		response.Notifications = append(response.Notifications, dto.NotificationResponse{
+			ID:             notification.ID.Hex(),
+			Title:          notification.Title,
+			Message:        notification.Message,
+			Level:          notification.Level,
+			SenderName:     notification.SenderName,
+			AckRequired:    notification.AckRequired,
+			CreatedAt:      notification.CreatedAt,
+			DeliveredAt:    delivery.DeliveredAt,
+			ReadAt:         delivery.ReadAt,
+			AcknowledgedAt: delivery.AcknowledgedAt,
+		})
+	}
+	return response, nil
+}
+
+// ListAcknowledgements returns acknowledgement counts and a page of recipients, or nil if the notification does not exist
+func (s *Service) ListAcknowledgements(ctx context.Context, input *dto.ListAcknowledgementsInput) (*dto.AcknowledgementListResponse, error) {
+	id, err := primitive.ObjectIDFromHex(input.NotificationID)
+	if err != nil {
+		return nil, nil
+	}
+
+	notification, err := s.repository.GetNotification(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	if notification == nil {
+		return nil, nil
+	}
+
+	recipients, read, acknowledged, err := s.repository.CountReceipts(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count receipts: %w", err)
+	}
+
+	deliveries, total, err := s.repository.ListAcknowledgements(ctx, id, input.Acknowledged, input.Page, input.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list acknowledgements: %w", err)
+	}
+
+	response := &dto.AcknowledgementListResponse{
+		NotificationID: input.NotificationID,
+		AckRequired:    notification.AckRequired,
+		Recipients:     recipients,
+		Read:           read,
+		Acknowledged:   acknowledged,
+		Outstanding:    recipients - acknowledged,
+		Deliveries:     make([]dto.DeliveryResponse, len(deliveries)),
+		Total:          total,
+		Page:           input.Page,
+		Limit:          input.Limit,
+	}
+	for i := range deliveries {
+		response.Deliveries[i] = dto.ToDeliveryResponse(&deliveries[i])
+	}
+	return response, nil
+}
+
+// MarkRead records that the user opened a notification. Returns nil if the user is not a recipient.
+func (s *Service) MarkRead(ctx context.Context, userID, notificationID string) (*dto.ReceiptResponse, error) {
+	return s.updateReceipt(ctx, userID, notificationID, false)
+}
+
+// Acknowledge records the user's acknowledgement of a notification and stops reminders.
+// Returns nil if the user is not a recipient, or ErrAckNotRequired for notifications without acknowledgement.
+func (s *Service) Acknowledge(ctx context.Context, userID, notificationID string) (*dto.ReceiptResponse, error) {
+	return s.updateReceipt(ctx, userID, notificationID, true)
+}
+
+func (s *Service) updateReceipt(ctx context.Context, userID, notificationID string, acknowledge bool) (*dto.ReceiptResponse, error) {
+	id, err := primitive.ObjectIDFromHex(notificationID)
+	if err != nil {
+		return nil, nil
+	}
+
+	delivery, err := s.repository.GetDelivery(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+	if delivery == nil {
+		return nil, nil
+	}
+	if acknowledge && !delivery.AckRequired {
+		return nil, ErrAckNotRequired
+	}
+
+	now := time.Now()
+	if delivery.ReadAt == nil {
+		if err := s.repository.MarkRead(ctx, id, userID, now); err != nil {
+			return nil, fmt.Errorf("failed to mark notification read: %w", err)
+		}
+		delivery.ReadAt = &now
+	}
+	if acknowledge && delivery.AcknowledgedAt == nil {
+		if err := s.repository.MarkAcknowledged(ctx, id, userID, now); err != nil {
+			return nil, fmt.Errorf("failed to acknowledge notification: %w", err)
+		}
+		delivery.AcknowledgedAt = &now
+	}
+
+	return &dto.ReceiptResponse{
+		NotificationID: notificationID,
+		ReadAt:         delivery.ReadAt,
+		AcknowledgedAt: delivery.AcknowledgedAt,
+	}, nil
+}
+
+// SendAckReminders re-sends acknowledgement-required notifications whose reminder is due.
+// Each recipient gets at most NOTIFICATIONS_ACK_MAX_REMINDERS reminders.
+func (s *Service) SendAckReminders(ctx context.Context) (int, error) {
+	now := time.Now()
+	maxReminders := config.GetNotificationAckMaxReminders()
+	sent := 0
+
+	for {
+		deliveries, err := s.repository.ListDueReminders(ctx, now, s.batchSize)
+		if err != nil {
+			return sent, fmt.Errorf("failed to list due reminders: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return sent, nil
+		}
+
+		ids := make([]primitive.ObjectID, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.NotificationID)
+		}
+		notifications, err := s.repository.GetNotificationsByIDs(ctx, ids)
+		if err != nil {
+			return sent, fmt.Errorf("failed to load notifications: %w", err)
+		}
+
+		for _, delivery := range deliveries {
+			notification, ok := notifications[delivery.NotificationID]
+
+			// Every due record is rescheduled or closed, even if the push fails, so the loop always advances
+			var next *time.Time
+			if ok && notification.ReminderMinutes > 0 && delivery.RemindersSent+1 < maxReminders {
+				at := now.Add(time.Duration(notification.ReminderMinutes) * time.Minute)
+				next = &at
+			}
+
+			if ok && s.notifier != nil {
+				data := pushData(&notification)
+				data["reminder"] = delivery.RemindersSent + 1
+				if err := s.notifier.NotifyUser(ctx, delivery.UserID, notification.Message, data); err != nil {
+					slog.WarnContext(ctx, "Failed to send acknowledgement reminder",
+						"notification_id", notification.ID.Hex(), "user_id", delivery.UserID, "error", err)
+				} else {
+					sent++
+				}
+			}
+
+			if err := s.repository.RecordReminder(ctx, delivery.ID, now, next); err != nil {
+				return sent, fmt.Errorf("failed to record reminder: %w", err)
+			}
+		}
+	}
+}
+
+// GetStatus returns the health status of the notifications module
+func (s *Service) GetStatus(ctx context.Context) *dto.NotificationsStatusResponse {
+	if err := s.repository.CheckHealth(ctx); err != nil {
+		return &dto.NotificationsStatusResponse{
+			Module:  "notifications",
+			Status:  "unhealthy",
+			Message: "Database connection failed: " + err.Error(),
+		}
+	}
+	return &dto.NotificationsStatusResponse{Module: "notifications", Status: "healthy"}
+}
+
+// toAudience validates the audience selectors and converts them to their stored form
+func toAudience(inputs []dto.AudienceSelectorInput) ([]notificationModels.AudienceSelector, error) {
+	audience := make([]notificationModels.AudienceSelector, len(inputs))
+	for i, input := range inputs {
+		selector := notificationModels.AudienceSelector{Type: notificationModels.AudienceType(input.Type)}
+
+		switch selector.Type {
+		case notificationModels.AudienceUsers:
+			if len(input.UserIDs) == 0 {
+				return nil, fmt.Errorf("%w: users selector requires user_ids", ErrInvalidAudience)
+			}
+			selector.UserIDs = input.UserIDs
+		case notificationModels.AudienceGroup:
+			if _, err := primitive.ObjectIDFromHex(input.GroupID); err != nil {
+				return nil, fmt.Errorf("%w: group selector requires a valid group_id", ErrInvalidAudience)
+			}
+			selector.GroupID = input.GroupID
+		case notificationModels.AudienceCorporation:
+			if input.CorporationID <= 0 {
+				return nil, fmt.Errorf("%w: corporation selector requires corporation_id", ErrInvalidAudience)
+			}
+			selector.CorporationID = input.CorporationID
+		case notificationModels.AudienceAlliance:
+			if input.AllianceID <= 0 {
+				return nil, fmt.Errorf("%w: alliance selector requires alliance_id", ErrInvalidAudience)
+			}
+			selector.AllianceID = input.AllianceID
+		case notificationModels.AudienceEveryone:
+		default:
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidAudience, input.Type)
+		}
+
+		audience[i] = selector
+	}
+	return audience, nil
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"go-falcon/internal/scheduler/models"
+	"go-falcon/pkg/identity"
 )
 
 // TaskResponse represents a task in API responses
@@ -57,6 +58,7 @@ type ExecutionResponse struct {
 	Metadata    map[string]interface{} `json:"metadata"`
 	WorkerID    string                 `json:"worker_id"`
 	RetryCount  int                    `json:"retry_count"`
+	TriggeredBy *identity.Attribution  `json:"triggered_by,omitempty" doc:"Who started the execution; the scheduler itself for cron runs"`
 }
 
 // ExecutionListResponse represents a paginated list of task executions
@@ -79,6 +81,26 @@ type SchedulerStatsResponse struct {
 	NextScheduledRun *time.Time `json:"next_scheduled_run,omitempty"`
 	WorkerCount      int        `json:"worker_count"`
 	QueueSize        int        `json:"queue_size"`
+	// Per-task analytics over the last 7 days, lowest 24h success rate first
+	Tasks []TaskStatsResponse `json:"tasks"`
+	// When the per-task analytics were computed; they are cached briefly
+	TasksComputedAt time.Time `json:"tasks_computed_at"`
+}
+
+// TaskStatsResponse represents the execution analytics of one task
+type TaskStatsResponse struct {
+	TaskID         string     `json:"task_id"`
+	TaskName       string     `json:"task_name,omitempty"`
+	Executions24h  int64      `json:"executions_24h"`
+	SuccessRate24h *float64   `json:"success_rate_24h,omitempty" doc:"Share of executions that completed (0-1); absent without runs in the window"`
+	Executions7d   int64      `json:"executions_7d"`
+	SuccessRate7d  *float64   `json:"success_rate_7d,omitempty" doc:"Share of executions that completed (0-1); absent without runs in the window"`
+	DurationP50    string     `json:"duration_p50" doc:"Median duration of completed executions"`
+	DurationP90    string     `json:"duration_p90"`
+	DurationP99    string     `json:"duration_p99"`
+	Failing        bool       `json:"failing" doc:"Whether the latest execution failed"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastFailedAt   *time.Time `json:"last_failed_at,omitempty"`
 }
 
 // SchedulerStatusResponse represents scheduler status
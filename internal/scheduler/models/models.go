@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"go-falcon/pkg/identity"
 )
 
 // TaskType defines the type of task to execute
@@ -86,6 +88,7 @@ type TaskExecution struct {
 	Metadata    map[string]interface{} `json:"metadata" bson:"metadata"`
 	WorkerID    string                 `json:"worker_id" bson:"worker_id"`
 	RetryCount  int                    `json:"retry_count" bson:"retry_count"`
+	TriggeredBy *identity.Attribution  `json:"triggered_by,omitempty" bson:"triggered_by,omitempty"`
 }
 
 // HTTPTaskConfig defines configuration for HTTP tasks
@@ -136,6 +139,20 @@ type SchedulerStats struct {
 	QueueSize        int        `json:"queue_size"`
 }
 
+// TaskExecutionStats is the aggregated execution history of one task
+type TaskExecutionStats struct {
+	TaskID          string          `bson:"_id"`
+	TaskName        string          `bson:"task_name"`
+	Durations       []time.Duration `bson:"durations"` // Completed executions in the stats window
+	Executions24h   int64           `bson:"executions_24h"`
+	Completed24h    int64           `bson:"completed_24h"`
+	Executions7d    int64           `bson:"executions_7d"`
+	Completed7d     int64           `bson:"completed_7d"`
+	LastRunStatus   TaskStatus      `bson:"last_run_status"`
+	LastError       string          `bson:"last_error"`
+	LastFailureTime *time.Time      `bson:"last_failure_at"`
+}
+
 // EngineStats represents engine statistics
 type EngineStats struct {
 	WorkerCount int  `json:"worker_count"`
@@ -11,6 +11,8 @@ import (
 	groupsServices "go-falcon/internal/groups/services"
 	"go-falcon/internal/scheduler/routes"
 	"go-falcon/internal/scheduler/services"
+	sdeAdminDto "go-falcon/internal/sde_admin/dto"
+	usersDto "go-falcon/internal/users/dto"
 	"go-falcon/pkg/database"
 	"go-falcon/pkg/middleware"
 	"go-falcon/pkg/module"
@@ -28,12 +30,15 @@ type Module struct {
 	routes           *routes.Routes
 
 	// Dependencies
-	authModule        *auth.Module
-	characterModule   CharacterModule
-	allianceModule    AllianceModule
-	corporationModule CorporationModule
-	marketModule      MarketModule
-	groupService      *groupsServices.Service
+	authModule          *auth.Module
+	characterModule     CharacterModule
+	allianceModule      AllianceModule
+	corporationModule   CorporationModule
+	marketModule        MarketModule
+	sdeModule           SDEModule
+	notificationsModule NotificationsModule
+	usersModule         UsersModule
+	groupService        *groupsServices.Service
 }
 
 // AuthModule interface defines the methods needed from the auth module
@@ -55,6 +60,7 @@ type AllianceModule interface {
 type CorporationModule interface {
 	UpdateAllCorporations(ctx context.Context, concurrentWorkers int) error
 	ValidateCEOTokens(ctx context.Context) error
+	EvaluateBudgetAlerts(ctx context.Context) (int, error)
 }
 
 // MarketModule interface defines the methods needed from the market module
@@ -63,27 +69,48 @@ type MarketModule interface {
 	GetMarketStatus(ctx context.Context) (string, error) // Returns pagination mode detection status
 }
 
+// SDEModule interface defines the methods needed from the SDE admin module
+type SDEModule interface {
+	RunAutoUpdate(ctx context.Context) (*sdeAdminDto.AutoUpdateResult, error)
+}
+
+// NotificationsModule interface defines the methods needed from the notifications module
+type NotificationsModule interface {
+	SendAckReminders(ctx context.Context) (int, error)
+}
+
+// UsersModule interface defines the methods needed from the users module
+type UsersModule interface {
+	EnforceInactivityPolicy(ctx context.Context) (*usersDto.InactivityRunResult, error)
+	ProcessAccountDeletions(ctx context.Context) (*usersDto.AccountDeletionRunResult, error)
+	ProcessDataExports(ctx context.Context) (*usersDto.DataExportRunResult, error)
+	PurgeDeletedUsers(ctx context.Context) (*usersDto.SoftDeletePurgeRunResult, error)
+}
+
 // New creates a new scheduler module with standardized structure
-func New(mongodb *database.MongoDB, redis *database.Redis, authModule *auth.Module, characterModule CharacterModule, allianceModule AllianceModule, corporationModule CorporationModule, marketModule MarketModule) *Module {
+func New(mongodb *database.MongoDB, redis *database.Redis, authModule *auth.Module, characterModule CharacterModule, allianceModule AllianceModule, corporationModule CorporationModule, marketModule MarketModule, sdeModule SDEModule, notificationsModule NotificationsModule, usersModule UsersModule) *Module {
 	baseModule := module.NewBaseModule("scheduler", mongodb, redis)
 
 	// Create services (note: groups module will be set later via SetGroupService)
-	schedulerService := services.NewSchedulerService(mongodb, redis, authModule, characterModule, allianceModule, corporationModule, nil, marketModule)
+	schedulerService := services.NewSchedulerService(mongodb, redis, authModule, characterModule, allianceModule, corporationModule, nil, marketModule, sdeModule, notificationsModule, usersModule)
 
 	// Note: SchedulerAdapter will be created in SetGroupService when PermissionManager becomes available
 	var schedulerAdapter *middleware.SchedulerAdapter
 
 	return &Module{
-		BaseModule:        baseModule,
-		schedulerService:  schedulerService,
-		schedulerAdapter:  schedulerAdapter,
-		routes:            nil, // Will be created when needed
-		authModule:        authModule,
-		characterModule:   characterModule,
-		allianceModule:    allianceModule,
-		corporationModule: corporationModule,
-		marketModule:      marketModule,
-		groupService:      nil, // Will be set after groups module initialization
+		BaseModule:          baseModule,
+		schedulerService:    schedulerService,
+		schedulerAdapter:    schedulerAdapter,
+		routes:              nil, // Will be created when needed
+		authModule:          authModule,
+		characterModule:     characterModule,
+		allianceModule:      allianceModule,
+		corporationModule:   corporationModule,
+		marketModule:        marketModule,
+		sdeModule:           sdeModule,
+		notificationsModule: notificationsModule,
+		usersModule:         usersModule,
+		groupService:        nil, // Will be set after groups module initialization
 	}
 }
 
@@ -96,7 +123,7 @@ func (m *Module) SetGroupService(groupService *groupsServices.Service) {
 		m.schedulerService = services.NewSchedulerService(
 			m.BaseModule.MongoDB(), m.BaseModule.Redis(),
 			m.authModule, m.characterModule, m.allianceModule, m.corporationModule,
-			groupService, m.marketModule,
+			groupService, m.marketModule, m.sdeModule, m.notificationsModule, m.usersModule,
 		)
 		slog.Info("Scheduler service recreated with groups module dependency")
 	}
@@ -137,6 +164,11 @@ func (m *Module) RegisterHumaRoutes(r chi.Router) {
 	}
 }
 
+// Initialize creates the hardcoded system tasks that don't exist yet
+func (m *Module) Initialize(ctx context.Context) error {
+	return m.schedulerService.InitializeSystemTasks(ctx)
+}
+
 // StartBackgroundTasks starts scheduler-specific background tasks
 func (m *Module) StartBackgroundTasks(ctx context.Context) {
 	slog.Info("Starting scheduler background tasks", "module", m.Name())
@@ -144,9 +176,6 @@ func (m *Module) StartBackgroundTasks(ctx context.Context) {
 	// Start base module background tasks
 	go m.BaseModule.StartBackgroundTasks(ctx)
 
-	// Initialize hardcoded system tasks
-	go m.initializeSystemTasks(ctx)
-
 	// Start the scheduler engine
 	go m.startEngine(ctx)
 
@@ -252,13 +281,6 @@ func (m *Module) RegisterPermissions(ctx context.Context, permissionManager *per
 	return permissionManager.RegisterServicePermissions(ctx, schedulerPermissions)
 }
 
-// initializeSystemTasks creates hardcoded system tasks if they don't exist
-func (m *Module) initializeSystemTasks(ctx context.Context) {
-	if err := m.schedulerService.InitializeSystemTasks(ctx); err != nil {
-		slog.Error("Failed to initialize system tasks", "error", err)
-	}
-}
-
 // startEngine starts the scheduler engine
 func (m *Module) startEngine(ctx context.Context) {
 	// Wait a moment for database connections to be ready
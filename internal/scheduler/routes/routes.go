@@ -5,6 +5,7 @@ import (
 
 	"go-falcon/internal/scheduler/dto"
 	"go-falcon/internal/scheduler/services"
+	"go-falcon/pkg/apidocs"
 	"go-falcon/pkg/middleware"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -33,10 +34,6 @@ func NewRoutes(service *services.SchedulerService, schedulerAdapter *middleware.
 		api:              api,
 	}
 
-	// Note: registerRoutes() call removed for security - it registered endpoints without authentication
-	// All secure routes are now registered via RegisterSchedulerRoutes() called by RegisterUnifiedRoutes()
-	// hr.registerRoutes() // REMOVED: This registered unprotected endpoints
-
 	return hr
 }
 
@@ -73,8 +70,9 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Method:      "GET",
 		Path:        basePath + "/stats",
 		Summary:     "Get scheduler statistics",
-		Description: "Get comprehensive scheduler statistics including task counts and execution metrics",
+		Description: "Get comprehensive scheduler statistics including task counts, execution metrics and per-task duration percentiles, success rates and last errors over the last 7 days (per-task analytics are cached for a minute)",
 		Tags:        []string{"Scheduler / Status"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.SchedulerStatsInput) (*dto.SchedulerStatsOutput, error) {
 		// Validate authentication and task management permission
@@ -101,6 +99,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "List scheduled tasks",
 		Description: "List all scheduled tasks with filtering and pagination support",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskListInput) (*dto.TaskListOutput, error) {
 		// Validate authentication and task management permission
@@ -143,6 +142,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Create new task",
 		Description: "Create a new scheduled task with cron-like scheduling",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskCreateInput) (*dto.TaskCreateOutput, error) {
 		// Validate authentication and task management permission
@@ -168,6 +168,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Get task details",
 		Description: "Get detailed information about a specific scheduled task",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskGetInput) (*dto.TaskGetOutput, error) {
 		// Validate authentication and task management permission
@@ -196,6 +197,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Update task",
 		Description: "Update an existing scheduled task configuration",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskUpdateInput) (*dto.TaskUpdateOutput, error) {
 		// Validate authentication and task management permission
@@ -227,6 +229,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Delete task",
 		Description: "Delete a scheduled task (system tasks cannot be deleted)",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskDeleteInput) (*dto.TaskDeleteOutput, error) {
 		// Validate authentication and task management permission
@@ -264,6 +267,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Execute task immediately",
 		Description: "Manually trigger immediate execution of a scheduled task",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskExecuteInput) (*dto.TaskExecuteOutput, error) {
 		// Validate authentication and task management permission
@@ -315,6 +319,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Pause task",
 		Description: "Pause execution of a scheduled task",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskPauseInput) (*dto.TaskPauseOutput, error) {
 		// Validate authentication and task management permission
@@ -346,6 +351,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Resume task",
 		Description: "Resume execution of a paused scheduled task",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskResumeInput) (*dto.TaskResumeOutput, error) {
 		// Validate authentication and task management permission
@@ -377,6 +383,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Stop running task",
 		Description: "Stop a currently running scheduled task execution",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskStopInput) (*dto.TaskStopOutput, error) {
 		// Validate authentication and task management permission
@@ -408,6 +415,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Get task execution history",
 		Description: "Get execution history for a specific scheduled task",
 		Tags:        []string{"Scheduler / Tasks"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.TaskExecutionHistoryInput) (*dto.TaskExecutionHistoryOutput, error) {
 		// Validate authentication and task management permission
@@ -446,6 +454,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "List all executions",
 		Description: "List all task executions across all tasks with filtering and pagination support",
 		Tags:        []string{"Scheduler / Executions"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.ExecutionListInput) (*dto.ExecutionListOutput, error) {
 		// Validate authentication and task management permission
@@ -471,6 +480,7 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 		Summary:     "Get execution details",
 		Description: "Get detailed information about a specific task execution",
 		Tags:        []string{"Scheduler / Executions"},
+		Extensions:  apidocs.RequiresPermission(middleware.SchedulerTaskManagementPermissions...),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.ExecutionGetInput) (*dto.ExecutionGetOutput, error) {
 		// Validate authentication and task management permission
@@ -520,38 +530,6 @@ func RegisterSchedulerRoutes(api huma.API, basePath string, service *services.Sc
 	})
 }
 
-// registerRoutes registers all Scheduler module routes with Huma
-func (hr *Routes) registerRoutes() {
-	// Public endpoints (no authentication required)
-	huma.Get(hr.api, "/status", hr.getModuleStatus)
-	huma.Get(hr.api, "/scheduler-status", hr.getStatus)
-	huma.Get(hr.api, "/stats", hr.getStats)
-
-	// Task management endpoints (require authentication and permissions)
-	huma.Get(hr.api, "/tasks", hr.listTasks)
-	huma.Post(hr.api, "/tasks", hr.createTask)
-	huma.Get(hr.api, "/tasks/{task_id}", hr.getTask)
-	huma.Put(hr.api, "/tasks/{task_id}", hr.updateTask)
-	huma.Delete(hr.api, "/tasks/{task_id}", hr.deleteTask)
-
-	// Task control endpoints
-	huma.Post(hr.api, "/tasks/{task_id}/execute", hr.executeTask)
-	huma.Post(hr.api, "/tasks/{task_id}/enable", hr.enableTask)
-	huma.Post(hr.api, "/tasks/{task_id}/disable", hr.disableTask)
-	huma.Post(hr.api, "/tasks/{task_id}/pause", hr.pauseTask)
-	huma.Post(hr.api, "/tasks/{task_id}/resume", hr.resumeTask)
-	huma.Post(hr.api, "/tasks/{task_id}/stop", hr.stopTask)
-
-	// Execution endpoints
-	huma.Get(hr.api, "/tasks/{task_id}/history", hr.getTaskHistory)
-	huma.Get(hr.api, "/executions", hr.listExecutions)
-	huma.Get(hr.api, "/executions/{execution_id}", hr.getExecution)
-
-	// Bulk operations
-	huma.Post(hr.api, "/tasks/bulk", hr.bulkTaskOperation)
-	huma.Post(hr.api, "/tasks/import", hr.importTasks)
-}
-
 // Public endpoint handlers
 
 func (hr *Routes) getModuleStatus(ctx context.Context, input *struct{}) (*dto.StatusOutput, error) {
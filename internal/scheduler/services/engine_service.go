@@ -9,6 +9,7 @@ import (
 
 	"go-falcon/internal/scheduler/models"
 	"go-falcon/pkg/database"
+	"go-falcon/pkg/identity"
 
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
@@ -49,12 +50,15 @@ type EngineService struct {
 	stopChan chan struct{}
 
 	// Module dependencies
-	authModule        AuthModule
-	characterModule   CharacterModule
-	allianceModule    AllianceModule
-	corporationModule CorporationModule
-	groupsModule      GroupsModule
-	marketModule      MarketModule
+	authModule          AuthModule
+	characterModule     CharacterModule
+	allianceModule      AllianceModule
+	corporationModule   CorporationModule
+	groupsModule        GroupsModule
+	marketModule        MarketModule
+	sdeModule           SDEModule
+	notificationsModule NotificationsModule
+	usersModule         UsersModule
 }
 
 // AuthModule interface defines the methods needed from the auth module
@@ -65,6 +69,13 @@ type AuthModule interface {
 // GroupsModule interface defines the methods needed from the groups module
 type GroupsModule interface {
 	ValidateGroupMembershipsAgainstEntityStatus(ctx context.Context) error
+	SyncStandingsGroups(ctx context.Context) (added, removed int, err error)
+	SyncMembershipRules(ctx context.Context) (added, removed int, err error)
+	SyncCorporationMappings(ctx context.Context) (added, removed int, err error)
+	RunMembershipRevalidation(ctx context.Context, action string) (flagged, removed int, err error)
+	ExpireGroupMemberships(ctx context.Context) (notified, removed int, err error)
+	ArchiveAuditEvents(ctx context.Context) (archived, purged int64, err error)
+	TakeGroupSnapshot(ctx context.Context) (memberships, grants int, err error)
 }
 
 // MarketModule interface defines the methods needed from the market module
@@ -79,22 +90,25 @@ type TaskExecutor interface {
 }
 
 // NewEngineService creates a new scheduler engine
-func NewEngineService(repository *Repository, redis *database.Redis, authModule AuthModule, characterModule CharacterModule, allianceModule AllianceModule, corporationModule CorporationModule, groupsModule GroupsModule, marketModule MarketModule) *EngineService {
+func NewEngineService(repository *Repository, redis *database.Redis, authModule AuthModule, characterModule CharacterModule, allianceModule AllianceModule, corporationModule CorporationModule, groupsModule GroupsModule, marketModule MarketModule, sdeModule SDEModule, notificationsModule NotificationsModule, usersModule UsersModule) *EngineService {
 	engine := &EngineService{
-		repository:        repository,
-		redis:             redis,
-		workers:           10, // Default worker count
-		taskQueue:         make(chan *models.TaskExecution, 1000),
-		activeTasks:       make(map[string]*models.Task),
-		runningExecutions: make(map[string]*ExecutionContext),
-		executors:         make(map[models.TaskType]TaskExecutor),
-		stopChan:          make(chan struct{}),
-		authModule:        authModule,
-		characterModule:   characterModule,
-		allianceModule:    allianceModule,
-		corporationModule: corporationModule,
-		groupsModule:      groupsModule,
-		marketModule:      marketModule,
+		repository:          repository,
+		redis:               redis,
+		workers:             10, // Default worker count
+		taskQueue:           make(chan *models.TaskExecution, 1000),
+		activeTasks:         make(map[string]*models.Task),
+		runningExecutions:   make(map[string]*ExecutionContext),
+		executors:           make(map[models.TaskType]TaskExecutor),
+		stopChan:            make(chan struct{}),
+		authModule:          authModule,
+		characterModule:     characterModule,
+		allianceModule:      allianceModule,
+		corporationModule:   corporationModule,
+		groupsModule:        groupsModule,
+		marketModule:        marketModule,
+		sdeModule:           sdeModule,
+		notificationsModule: notificationsModule,
+		usersModule:         usersModule,
 	}
 
 	// Initialize cron scheduler
@@ -192,19 +206,20 @@ func (e *EngineService) ExecuteTaskNow(ctx context.Context, taskID string) (*mod
 		return nil, fmt.Errorf("task is disabled")
 	}
 
-	// Create execution record
+	// Create execution record, remembering who asked for it
 	execution := &models.TaskExecution{
-		ID:        uuid.New().String(),
-		TaskID:    taskID,
-		Status:    models.TaskStatusPending,
-		StartedAt: time.Now(),
-		Metadata:  make(map[string]interface{}),
+		ID:          uuid.New().String(),
+		TaskID:      taskID,
+		Status:      models.TaskStatusPending,
+		StartedAt:   time.Now(),
+		Metadata:    make(map[string]interface{}),
+		TriggeredBy: identity.AttributionFromContext(ctx),
 	}
 
 	// Queue for execution
 	select {
 	case e.taskQueue <- execution:
-		slog.Info("Task queued for immediate execution", slog.String("task_id", taskID))
+		slog.InfoContext(ctx, "Task queued for immediate execution", slog.String("task_id", taskID))
 		return execution, nil
 	default:
 		return nil, fmt.Errorf("task queue is full")
@@ -340,12 +355,14 @@ func (e *EngineService) scheduleTask(task *models.Task) error {
 // executeScheduledTask executes a task from the cron scheduler
 func (e *EngineService) executeScheduledTask(taskID string) {
 	// Create execution record
+	attribution := identity.System("scheduler").Attribution()
 	execution := &models.TaskExecution{
-		ID:        uuid.New().String(),
-		TaskID:    taskID,
-		Status:    models.TaskStatusPending,
-		StartedAt: time.Now(),
-		Metadata:  make(map[string]interface{}),
+		ID:          uuid.New().String(),
+		TaskID:      taskID,
+		Status:      models.TaskStatusPending,
+		StartedAt:   time.Now(),
+		Metadata:    make(map[string]interface{}),
+		TriggeredBy: &attribution,
 	}
 
 	// Queue for execution
@@ -393,8 +410,12 @@ func (e *EngineService) processExecution(parentCtx context.Context, execution *m
 		slog.String("task_id", execution.TaskID),
 		slog.String("worker_id", workerID))
 
-	// Create cancellable context for this execution
-	executionCtx, cancel := context.WithCancel(parentCtx)
+	// Create cancellable context for this execution, acting as whoever triggered it
+	id := identity.FromAttribution(execution.TriggeredBy)
+	if id == nil {
+		id = identity.System("scheduler")
+	}
+	executionCtx, cancel := context.WithCancel(identity.With(parentCtx, id))
 	defer cancel()
 
 	// Track this execution for cancellation
@@ -558,7 +579,7 @@ func (e *EngineService) finishExecution(ctx context.Context, execution *models.T
 // registerBuiltinExecutors registers the built-in task executors
 func (e *EngineService) registerBuiltinExecutors() {
 	e.executors[models.TaskTypeHTTP] = NewHTTPExecutor()
-	e.executors[models.TaskTypeSystem] = NewSystemExecutor(e.authModule, e.characterModule, e.allianceModule, e.corporationModule, e.groupsModule, e.marketModule)
+	e.executors[models.TaskTypeSystem] = NewSystemExecutor(e.authModule, e.characterModule, e.allianceModule, e.corporationModule, e.groupsModule, e.marketModule, e.sdeModule, e.notificationsModule, e.usersModule)
 	e.executors[models.TaskTypeFunction] = NewFunctionExecutor()
 }
 
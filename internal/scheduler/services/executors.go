@@ -11,6 +11,9 @@ import (
 
 	"go-falcon/internal/alliance/dto"
 	"go-falcon/internal/scheduler/models"
+	sdeAdminDto "go-falcon/internal/sde_admin/dto"
+	usersDto "go-falcon/internal/users/dto"
+	"go-falcon/pkg/evegateway"
 )
 
 // HTTPExecutor executes HTTP tasks
@@ -179,27 +182,52 @@ type AllianceModule interface {
 type CorporationModule interface {
 	UpdateAllCorporations(ctx context.Context, concurrentWorkers int) error
 	ValidateCEOTokens(ctx context.Context) error
+	EvaluateBudgetAlerts(ctx context.Context) (int, error)
+}
+
+// SDEModule interface for SDE maintenance operations
+type SDEModule interface {
+	RunAutoUpdate(ctx context.Context) (*sdeAdminDto.AutoUpdateResult, error)
+}
+
+// NotificationsModule interface for notification maintenance operations
+type NotificationsModule interface {
+	SendAckReminders(ctx context.Context) (int, error)
+}
+
+// UsersModule interface for user maintenance operations
+type UsersModule interface {
+	EnforceInactivityPolicy(ctx context.Context) (*usersDto.InactivityRunResult, error)
+	ProcessAccountDeletions(ctx context.Context) (*usersDto.AccountDeletionRunResult, error)
+	ProcessDataExports(ctx context.Context) (*usersDto.DataExportRunResult, error)
+	PurgeDeletedUsers(ctx context.Context) (*usersDto.SoftDeletePurgeRunResult, error)
 }
 
 // SystemExecutor executes system tasks
 type SystemExecutor struct {
-	authModule        AuthModule
-	characterModule   CharacterModule
-	allianceModule    AllianceModule
-	corporationModule CorporationModule
-	groupsModule      GroupsModule
-	marketModule      MarketModule
+	authModule          AuthModule
+	characterModule     CharacterModule
+	allianceModule      AllianceModule
+	corporationModule   CorporationModule
+	groupsModule        GroupsModule
+	marketModule        MarketModule
+	sdeModule           SDEModule
+	notificationsModule NotificationsModule
+	usersModule         UsersModule
 }
 
 // NewSystemExecutor creates a new system executor
-func NewSystemExecutor(authModule AuthModule, characterModule CharacterModule, allianceModule AllianceModule, corporationModule CorporationModule, groupsModule GroupsModule, marketModule MarketModule) *SystemExecutor {
+func NewSystemExecutor(authModule AuthModule, characterModule CharacterModule, allianceModule AllianceModule, corporationModule CorporationModule, groupsModule GroupsModule, marketModule MarketModule, sdeModule SDEModule, notificationsModule NotificationsModule, usersModule UsersModule) *SystemExecutor {
 	return &SystemExecutor{
-		authModule:        authModule,
-		characterModule:   characterModule,
-		allianceModule:    allianceModule,
-		corporationModule: corporationModule,
-		groupsModule:      groupsModule,
-		marketModule:      marketModule,
+		authModule:          authModule,
+		characterModule:     characterModule,
+		allianceModule:      allianceModule,
+		corporationModule:   corporationModule,
+		groupsModule:        groupsModule,
+		marketModule:        marketModule,
+		sdeModule:           sdeModule,
+		notificationsModule: notificationsModule,
+		usersModule:         usersModule,
 	}
 }
 
@@ -211,6 +239,8 @@ func (e *SystemExecutor) Execute(ctx context.Context, task *models.Task) (*model
 		return nil, fmt.Errorf("invalid system config: %w", err)
 	}
 
+	// Attribute the task's ESI calls to it in the ESI usage report
+	ctx = evegateway.WithModule(ctx, "scheduler:"+config.TaskName)
 	start := time.Now()
 
 	switch config.TaskName {
@@ -228,12 +258,40 @@ func (e *SystemExecutor) Execute(ctx context.Context, task *models.Task) (*model
 		return e.executeCorporationUpdate(ctx, config, start)
 	case "ceo_token_validation":
 		return e.executeCEOTokenValidation(ctx, config, start)
+	case "corporation_budget_alerts":
+		return e.executeBudgetAlerts(ctx, start)
 	case "groups_sync":
 		return e.executeGroupsSync(ctx, config, start)
+	case "standings_groups_sync":
+		return e.executeStandingsGroupsSync(ctx, start)
+	case "membership_rules_sync":
+		return e.executeMembershipRulesSync(ctx, start)
+	case "corporation_mapping_sync":
+		return e.executeCorporationMappingSync(ctx, start)
+	case "group_membership_revalidation":
+		return e.executeGroupMembershipRevalidation(ctx, config, start)
+	case "group_membership_expiry":
+		return e.executeGroupMembershipExpiry(ctx, start)
+	case "group_audit_archival":
+		return e.executeGroupAuditArchival(ctx, start)
+	case "group_snapshot":
+		return e.executeGroupSnapshot(ctx, start)
 	case "market_data_fetch":
 		return e.executeMarketDataFetch(ctx, config, start)
 	case "pagination_migration_monitor":
 		return e.executePaginationMigrationMonitor(ctx, config, start)
+	case "sde_auto_update":
+		return e.executeSDEAutoUpdate(ctx, start)
+	case "notification_ack_reminders":
+		return e.executeNotificationAckReminders(ctx, start)
+	case "user_inactivity_policy":
+		return e.executeUserInactivityPolicy(ctx, start)
+	case "user_account_erasure":
+		return e.executeUserAccountErasure(ctx, start)
+	case "user_data_exports":
+		return e.executeUserDataExports(ctx, start)
+	case "user_soft_delete_purge":
+		return e.executeUserSoftDeletePurge(ctx, start)
 	default:
 		return &models.TaskResult{
 			Success:  false,
@@ -478,6 +536,77 @@ func (e *SystemExecutor) executeCEOTokenValidation(ctx context.Context, config *
 	}, nil
 }
 
+// executeUserInactivityPolicy flags, notifies, disables and purges inactive users
+func (e *SystemExecutor) executeUserInactivityPolicy(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.usersModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Users module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	result, err := e.usersModule.EnforceInactivityPolicy(ctx)
+	if result == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Inactivity policy failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	taskResult := &models.TaskResult{
+		Success: err == nil,
+		Output: fmt.Sprintf("Flagged %d, notified %d, disabled %d, purged %d, cleared %d inactive users (enforced: %t)",
+			result.Flagged, result.Notified, result.Disabled, result.Purged, result.Cleared, result.Enforced),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "user_inactivity_policy",
+			"enforced":  result.Enforced,
+			"flagged":   result.Flagged,
+			"notified":  result.Notified,
+			"disabled":  result.Disabled,
+			"purged":    result.Purged,
+			"cleared":   result.Cleared,
+			"failed":    result.Failed,
+		},
+	}
+	if err != nil {
+		taskResult.Error = err.Error()
+	}
+	return taskResult, nil
+}
+
+// executeBudgetAlerts evaluates corporation wallet budget alert rules
+func (e *SystemExecutor) executeBudgetAlerts(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.corporationModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Corporation module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	sent, err := e.corporationModule.EvaluateBudgetAlerts(ctx)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Budget alert evaluation failed after sending %d alerts: %v", sent, err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   fmt.Sprintf("Sent %d budget alerts", sent),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type":   "corporation_budget_alerts",
+			"alerts_sent": sent,
+		},
+	}, nil
+}
+
 // executeGroupsSync executes the groups synchronization system task
 func (e *SystemExecutor) executeGroupsSync(ctx context.Context, config *models.SystemTaskConfig, start time.Time) (*models.TaskResult, error) {
 	if e.groupsModule == nil {
@@ -508,6 +637,226 @@ func (e *SystemExecutor) executeGroupsSync(ctx context.Context, config *models.S
 	}, nil
 }
 
+// executeStandingsGroupsSync reconciles the standings tier groups with the imported alliance contacts
+func (e *SystemExecutor) executeStandingsGroupsSync(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.groupsModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Groups module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	added, removed, err := e.groupsModule.SyncStandingsGroups(ctx)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Standings groups sync failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   fmt.Sprintf("Standings groups reconciled: %d added, %d removed", added, removed),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "standings_groups_sync",
+			"added":     added,
+			"removed":   removed,
+		},
+	}, nil
+}
+
+// executeMembershipRulesSync evaluates the membership rules of custom groups for every character
+func (e *SystemExecutor) executeMembershipRulesSync(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.groupsModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Groups module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	added, removed, err := e.groupsModule.SyncMembershipRules(ctx)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Membership rules sync failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   fmt.Sprintf("Membership rules evaluated: %d added, %d removed", added, removed),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "membership_rules_sync",
+			"added":     added,
+			"removed":   removed,
+		},
+	}, nil
+}
+
+// executeCorporationMappingSync keeps group memberships aligned with in-game corporation roles and titles
+func (e *SystemExecutor) executeCorporationMappingSync(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.groupsModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Groups module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	added, removed, err := e.groupsModule.SyncCorporationMappings(ctx)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Corporation mapping sync failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   fmt.Sprintf("Corporation mappings synced: %d added, %d removed", added, removed),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "corporation_mapping_sync",
+			"added":     added,
+			"removed":   removed,
+		},
+	}, nil
+}
+
+// executeGroupMembershipRevalidation checks corporation and alliance group members against their
+// current ESI affiliation
+func (e *SystemExecutor) executeGroupMembershipRevalidation(ctx context.Context, config *models.SystemTaskConfig, start time.Time) (*models.TaskResult, error) {
+	if e.groupsModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Groups module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	action, _ := config.Parameters["action"].(string)
+	flagged, removed, err := e.groupsModule.RunMembershipRevalidation(ctx, action)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Group membership revalidation failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   fmt.Sprintf("Group memberships revalidated: %d flagged, %d removed", flagged, removed),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "group_membership_revalidation",
+			"action":    action,
+			"flagged":   flagged,
+			"removed":   removed,
+		},
+	}, nil
+}
+
+// executeGroupMembershipExpiry sends expiry notices and removes expired time-limited memberships
+func (e *SystemExecutor) executeGroupMembershipExpiry(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.groupsModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Groups module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	notified, removed, err := e.groupsModule.ExpireGroupMemberships(ctx)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Group membership expiry failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   fmt.Sprintf("Group memberships: %d expiry notices sent, %d expired removed", notified, removed),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "group_membership_expiry",
+			"notified":  notified,
+			"removed":   removed,
+		},
+	}, nil
+}
+
+// executeGroupAuditArchival moves old groups audit events to the archive and purges expired ones
+func (e *SystemExecutor) executeGroupAuditArchival(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.groupsModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Groups module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	archived, purged, err := e.groupsModule.ArchiveAuditEvents(ctx)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Groups audit archival failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   fmt.Sprintf("Groups audit: %d events archived, %d archived events purged", archived, purged),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "group_audit_archival",
+			"archived":  archived,
+			"purged":    purged,
+		},
+	}, nil
+}
+
+// executeGroupSnapshot records the group memberships and permission grants for historical access queries
+func (e *SystemExecutor) executeGroupSnapshot(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.groupsModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Groups module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	memberships, grants, err := e.groupsModule.TakeGroupSnapshot(ctx)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Group snapshot failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   fmt.Sprintf("Group snapshot taken: %d memberships, %d permission grants", memberships, grants),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type":   "group_snapshot",
+			"memberships": memberships,
+			"grants":      grants,
+		},
+	}, nil
+}
+
 // executeMarketDataFetch executes the market data fetch system task
 func (e *SystemExecutor) executeMarketDataFetch(ctx context.Context, config *models.SystemTaskConfig, start time.Time) (*models.TaskResult, error) {
 	if e.marketModule == nil {
@@ -595,6 +944,85 @@ func (e *SystemExecutor) executePaginationMigrationMonitor(ctx context.Context,
 	}, nil
 }
 
+// executeSDEAutoUpdate executes the SDE auto-update system task
+func (e *SystemExecutor) executeSDEAutoUpdate(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.sdeModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "SDE admin module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	result, err := e.sdeModule.RunAutoUpdate(ctx)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("SDE auto-update failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	metadata := map[string]interface{}{
+		"task_type":   "sde_auto_update",
+		"outcome":     result.Outcome,
+		"old_version": result.OldVersion,
+		"new_version": result.NewVersion,
+	}
+
+	// A rolled back import leaves the previous SDE in place but still needs attention
+	if result.Outcome == sdeAdminDto.AutoUpdateRolledBack || result.Outcome == sdeAdminDto.AutoUpdateFailed {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("SDE auto-update %s: %s", result.Outcome, result.Reason),
+			Duration: models.Duration(time.Since(start)),
+			Metadata: metadata,
+		}, nil
+	}
+
+	output := fmt.Sprintf("SDE auto-update %s", result.Outcome)
+	if result.Reason != "" {
+		output = fmt.Sprintf("%s: %s", output, result.Reason)
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   output,
+		Duration: models.Duration(time.Since(start)),
+		Metadata: metadata,
+	}, nil
+}
+
+// executeNotificationAckReminders executes the acknowledgement reminder system task
+func (e *SystemExecutor) executeNotificationAckReminders(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.notificationsModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Notifications module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	sent, err := e.notificationsModule.SendAckReminders(ctx)
+	if err != nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    fmt.Sprintf("Acknowledgement reminders failed: %v", err),
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	return &models.TaskResult{
+		Success:  true,
+		Output:   fmt.Sprintf("Sent %d acknowledgement reminders", sent),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type":      "notification_ack_reminders",
+			"reminders_sent": sent,
+		},
+	}, nil
+}
+
 // parseSystemConfig parses system task configuration
 func (e *SystemExecutor) parseSystemConfig(config map[string]interface{}) (*models.SystemTaskConfig, error) {
 	systemConfig := &models.SystemTaskConfig{}
@@ -680,3 +1108,85 @@ func (e *FunctionExecutor) parseFunctionConfig(config map[string]interface{}) (*
 
 	return functionConfig, nil
 }
+
+// executeUserAccountErasure erases the user accounts whose deletion is due
+func (e *SystemExecutor) executeUserAccountErasure(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.usersModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Users module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	result, err := e.usersModule.ProcessAccountDeletions(ctx)
+	taskResult := &models.TaskResult{
+		Success:  err == nil,
+		Output:   fmt.Sprintf("Erased %d accounts, %d failed", result.Erased, result.Failed),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "user_account_erasure",
+			"erased":    result.Erased,
+			"failed":    result.Failed,
+		},
+	}
+	if err != nil {
+		taskResult.Error = err.Error()
+	}
+	return taskResult, nil
+}
+
+// executeUserDataExports builds requested user data exports and deletes expired ones
+func (e *SystemExecutor) executeUserDataExports(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.usersModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Users module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	result, err := e.usersModule.ProcessDataExports(ctx)
+	taskResult := &models.TaskResult{
+		Success:  err == nil,
+		Output:   fmt.Sprintf("Built %d data exports, %d failed, %d expired", result.Exported, result.Failed, result.Expired),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "user_data_exports",
+			"exported":  result.Exported,
+			"failed":    result.Failed,
+			"expired":   result.Expired,
+		},
+	}
+	if err != nil {
+		taskResult.Error = err.Error()
+	}
+	return taskResult, nil
+}
+
+// executeUserSoftDeletePurge permanently deletes user characters soft-deleted longer ago than the retention period
+func (e *SystemExecutor) executeUserSoftDeletePurge(ctx context.Context, start time.Time) (*models.TaskResult, error) {
+	if e.usersModule == nil {
+		return &models.TaskResult{
+			Success:  false,
+			Error:    "Users module not available",
+			Duration: models.Duration(time.Since(start)),
+		}, nil
+	}
+
+	result, err := e.usersModule.PurgeDeletedUsers(ctx)
+	taskResult := &models.TaskResult{
+		Success:  err == nil,
+		Output:   fmt.Sprintf("Purged %d soft-deleted characters, %d failed", result.Purged, result.Failed),
+		Duration: models.Duration(time.Since(start)),
+		Metadata: map[string]interface{}{
+			"task_type": "user_soft_delete_purge",
+			"purged":    result.Purged,
+			"failed":    result.Failed,
+		},
+	}
+	if err != nil {
+		taskResult.Error = err.Error()
+	}
+	return taskResult, nil
+}
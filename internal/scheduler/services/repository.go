@@ -458,3 +458,64 @@ func (r *Repository) CheckHealth(ctx context.Context) error {
 	// Perform a simple ping to check database connectivity
 	return r.mongodb.Client.Ping(ctx, nil)
 }
+
+// GetTaskExecutionStats aggregates finished executions since the given time per task: completed
+// durations for percentiles, run and success counts for the last 24 hours and the whole window,
+// and the status of the latest run and the latest failure
+func (r *Repository) GetTaskExecutionStats(ctx context.Context, since time.Time) ([]models.TaskExecutionStats, error) {
+	dayAgo := time.Now().Add(-24 * time.Hour)
+	isCompleted := bson.M{"$eq": bson.A{"$status", models.TaskStatusCompleted}}
+	isFailed := bson.M{"$eq": bson.A{"$status", models.TaskStatusFailed}}
+	inLastDay := bson.M{"$gte": bson.A{"$started_at", dayAgo}}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"started_at": bson.M{"$gte": since},
+			"status":     bson.M{"$in": bson.A{models.TaskStatusCompleted, models.TaskStatusFailed}},
+		}},
+		{"$group": bson.M{
+			"_id":            "$task_id",
+			"durations":      bson.M{"$push": bson.M{"$cond": bson.A{isCompleted, "$duration", "$$REMOVE"}}},
+			"executions_7d":  bson.M{"$sum": 1},
+			"completed_7d":   bson.M{"$sum": bson.M{"$cond": bson.A{isCompleted, 1, 0}}},
+			"executions_24h": bson.M{"$sum": bson.M{"$cond": bson.A{inLastDay, 1, 0}}},
+			"completed_24h": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{inLastDay, isCompleted}}, 1, 0,
+			}}},
+			// Documents compare field by field, so the latest started_at wins; null sorts below documents
+			"last_run": bson.M{"$max": bson.M{"started_at": "$started_at", "status": "$status"}},
+			"last_failure": bson.M{"$max": bson.M{"$cond": bson.A{
+				isFailed, bson.M{"started_at": "$started_at", "error": "$error"}, nil,
+			}}},
+		}},
+		{"$lookup": bson.M{
+			"from":         "scheduler_tasks",
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "task",
+		}},
+		{"$project": bson.M{
+			"task_name":       bson.M{"$arrayElemAt": bson.A{"$task.name", 0}},
+			"durations":       1,
+			"executions_7d":   1,
+			"completed_7d":    1,
+			"executions_24h":  1,
+			"completed_24h":   1,
+			"last_run_status": "$last_run.status",
+			"last_error":      "$last_failure.error",
+			"last_failure_at": "$last_failure.started_at",
+		}},
+	}
+
+	cursor, err := r.executions.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var stats []models.TaskExecutionStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"go-falcon/internal/scheduler/dto"
 	"go-falcon/internal/scheduler/models"
 	"go-falcon/pkg/database"
+	"go-falcon/pkg/identity"
 
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
@@ -20,30 +23,36 @@ import (
 
 // SchedulerService is the main service that orchestrates scheduler operations
 type SchedulerService struct {
-	repository        *Repository
-	engineService     *EngineService
-	authModule        AuthModule
-	characterModule   CharacterModule
-	allianceModule    AllianceModule
-	corporationModule CorporationModule
-	groupsModule      GroupsModule
-	marketModule      MarketModule
+	repository          *Repository
+	engineService       *EngineService
+	authModule          AuthModule
+	characterModule     CharacterModule
+	allianceModule      AllianceModule
+	corporationModule   CorporationModule
+	groupsModule        GroupsModule
+	marketModule        MarketModule
+	sdeModule           SDEModule
+	notificationsModule NotificationsModule
+	redis               *database.Redis
 }
 
 // NewSchedulerService creates a new scheduler service with all dependencies
-func NewSchedulerService(mongodb *database.MongoDB, redis *database.Redis, authModule AuthModule, characterModule CharacterModule, allianceModule AllianceModule, corporationModule CorporationModule, groupsModule GroupsModule, marketModule MarketModule) *SchedulerService {
+func NewSchedulerService(mongodb *database.MongoDB, redis *database.Redis, authModule AuthModule, characterModule CharacterModule, allianceModule AllianceModule, corporationModule CorporationModule, groupsModule GroupsModule, marketModule MarketModule, sdeModule SDEModule, notificationsModule NotificationsModule, usersModule UsersModule) *SchedulerService {
 	repository := NewRepository(mongodb)
-	engineService := NewEngineService(repository, redis, authModule, characterModule, allianceModule, corporationModule, groupsModule, marketModule)
+	engineService := NewEngineService(repository, redis, authModule, characterModule, allianceModule, corporationModule, groupsModule, marketModule, sdeModule, notificationsModule, usersModule)
 
 	return &SchedulerService{
-		repository:        repository,
-		engineService:     engineService,
-		authModule:        authModule,
-		characterModule:   characterModule,
-		allianceModule:    allianceModule,
-		corporationModule: corporationModule,
-		groupsModule:      groupsModule,
-		marketModule:      marketModule,
+		repository:          repository,
+		engineService:       engineService,
+		authModule:          authModule,
+		characterModule:     characterModule,
+		allianceModule:      allianceModule,
+		corporationModule:   corporationModule,
+		groupsModule:        groupsModule,
+		marketModule:        marketModule,
+		sdeModule:           sdeModule,
+		notificationsModule: notificationsModule,
+		redis:               redis,
 	}
 }
 
@@ -70,7 +79,7 @@ func (s *SchedulerService) CreateTask(ctx context.Context, req *dto.TaskCreateRe
 		Config:      req.Config,
 		CreatedAt:   now,
 		UpdatedAt:   now,
-		CreatedBy:   "api", // TODO: Get from authenticated user
+		CreatedBy:   actorLabel(ctx),
 	}
 
 	// Set metadata
@@ -154,7 +163,7 @@ func (s *SchedulerService) UpdateTask(ctx context.Context, taskID string, req *d
 	}
 
 	task.UpdatedAt = time.Now()
-	task.UpdatedBy = "api" // TODO: Get from authenticated user
+	task.UpdatedBy = actorLabel(ctx)
 
 	if err := s.repository.UpdateTask(ctx, task); err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
@@ -387,6 +396,11 @@ func (s *SchedulerService) GetStats(ctx context.Context) (*dto.SchedulerStatsRes
 	stats.WorkerCount = engineStats.WorkerCount
 	stats.QueueSize = engineStats.QueueSize
 
+	taskStats, err := s.getTaskStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task execution stats: %w", err)
+	}
+
 	return &dto.SchedulerStatsResponse{
 		TotalTasks:       stats.TotalTasks,
 		EnabledTasks:     stats.EnabledTasks,
@@ -397,9 +411,115 @@ func (s *SchedulerService) GetStats(ctx context.Context) (*dto.SchedulerStatsRes
 		NextScheduledRun: stats.NextScheduledRun,
 		WorkerCount:      stats.WorkerCount,
 		QueueSize:        stats.QueueSize,
+		Tasks:            taskStats.Tasks,
+		TasksComputedAt:  taskStats.ComputedAt,
 	}, nil
 }
 
+const (
+	taskStatsCacheKey = "scheduler:stats:tasks"
+	taskStatsCacheTTL = time.Minute
+	taskStatsWindow   = 7 * 24 * time.Hour
+)
+
+// cachedTaskStats is the per-task analytics as cached in Redis
+type cachedTaskStats struct {
+	Tasks      []dto.TaskStatsResponse `json:"tasks"`
+	ComputedAt time.Time               `json:"computed_at"`
+}
+
+// getTaskStats returns per-task latency percentiles and success rates from the execution
+// history, cached in Redis because the aggregation scans a week of executions
+func (s *SchedulerService) getTaskStats(ctx context.Context) (*cachedTaskStats, error) {
+	if s.redis != nil {
+		if cached, err := s.redis.Get(ctx, taskStatsCacheKey); err == nil {
+			var result cachedTaskStats
+			if err := json.Unmarshal([]byte(cached), &result); err == nil {
+				return &result, nil
+			}
+		}
+	}
+
+	executionStats, err := s.repository.GetTaskExecutionStats(ctx, time.Now().Add(-taskStatsWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &cachedTaskStats{
+		Tasks:      make([]dto.TaskStatsResponse, 0, len(executionStats)),
+		ComputedAt: time.Now(),
+	}
+	for i := range executionStats {
+		result.Tasks = append(result.Tasks, taskStatsToResponse(&executionStats[i]))
+	}
+	// Degrading tasks first: lowest 24h success rate, tasks without recent runs last
+	sort.SliceStable(result.Tasks, func(i, j int) bool {
+		a, b := result.Tasks[i], result.Tasks[j]
+		if (a.SuccessRate24h == nil) != (b.SuccessRate24h == nil) {
+			return b.SuccessRate24h == nil
+		}
+		if a.SuccessRate24h != nil && *a.SuccessRate24h != *b.SuccessRate24h {
+			return *a.SuccessRate24h < *b.SuccessRate24h
+		}
+		return a.TaskID < b.TaskID
+	})
+
+	if s.redis != nil {
+		if data, err := json.Marshal(result); err == nil {
+			if err := s.redis.Set(ctx, taskStatsCacheKey, string(data), taskStatsCacheTTL); err != nil {
+				slog.WarnContext(ctx, "Failed to cache scheduler task stats", "error", err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// taskStatsToResponse converts aggregated execution history to its API shape
+func taskStatsToResponse(stats *models.TaskExecutionStats) dto.TaskStatsResponse {
+	durations := stats.Durations
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	response := dto.TaskStatsResponse{
+		TaskID:         stats.TaskID,
+		TaskName:       stats.TaskName,
+		Executions24h:  stats.Executions24h,
+		SuccessRate24h: successRate(stats.Completed24h, stats.Executions24h),
+		Executions7d:   stats.Executions7d,
+		SuccessRate7d:  successRate(stats.Completed7d, stats.Executions7d),
+		DurationP50:    percentile(durations, 50).String(),
+		DurationP90:    percentile(durations, 90).String(),
+		DurationP99:    percentile(durations, 99).String(),
+		Failing:        stats.LastRunStatus == models.TaskStatusFailed,
+		LastFailedAt:   stats.LastFailureTime,
+	}
+	if response.Failing {
+		response.LastError = stats.LastError
+	}
+	return response
+}
+
+// successRate returns completed/total, or nil without executions
+func successRate(completed, total int64) *float64 {
+	if total == 0 {
+		return nil
+	}
+	rate := float64(completed) / float64(total)
+	return &rate
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
 // GetStatus returns scheduler status (legacy)
 func (s *SchedulerService) GetStatus() *dto.SchedulerStatusResponse {
 	return &dto.SchedulerStatusResponse{
@@ -670,6 +790,7 @@ func (s *SchedulerService) executionToDTO(execution *models.TaskExecution) dto.E
 		Metadata:    execution.Metadata,
 		WorkerID:    execution.WorkerID,
 		RetryCount:  execution.RetryCount,
+		TriggeredBy: execution.TriggeredBy,
 	}
 }
 
@@ -700,3 +821,11 @@ func parseCommaSeparated(value string) []string {
 	}
 	return result
 }
+
+// actorLabel names the caller for created_by/updated_by fields, falling back to "api" when no identity is attached
+func actorLabel(ctx context.Context) string {
+	if id := identity.FromContext(ctx); id != nil {
+		return id.Label()
+	}
+	return "api"
+}
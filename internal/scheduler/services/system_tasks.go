@@ -212,6 +212,190 @@ func GetSystemTasks() []*models.Task {
 			UpdatedAt: now,
 			CreatedBy: "system",
 		},
+		{
+			ID:          "system-standings-groups-sync",
+			Name:        "Standings Groups Sync",
+			Description: "Re-imports alliance contacts from ESI, reconciles the standings tier groups and records a drift report",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 30 * * * *", // Every hour at minute 30
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityNormal,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "standings_groups_sync",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    2,
+				RetryInterval: models.Duration(10 * time.Minute),
+				Timeout:       models.Duration(15 * time.Minute),
+				Tags:          []string{"system", "groups", "standings"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-membership-rules-sync",
+			Name:        "Membership Rules Sync",
+			Description: "Evaluates the membership rules of custom groups against the affiliation and corporation roles of every character",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 45 * * * *", // Every hour at minute 45
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityNormal,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "membership_rules_sync",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    2,
+				RetryInterval: models.Duration(10 * time.Minute),
+				Timeout:       models.Duration(30 * time.Minute),
+				Tags:          []string{"system", "groups", "rules"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-corporation-mapping-sync",
+			Name:        "Corporation Mapping Sync",
+			Description: "Reads the member roles and titles of mapped corporations from ESI and keeps the mapped custom groups aligned with them",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 50 * * * *", // Every hour at minute 50
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityNormal,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "corporation_mapping_sync",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    2,
+				RetryInterval: models.Duration(10 * time.Minute),
+				Timeout:       models.Duration(30 * time.Minute),
+				Tags:          []string{"system", "groups", "corporation"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-group-membership-revalidation",
+			Name:        "Group Membership Revalidation",
+			Description: "Checks corporation and alliance group members against their current ESI affiliation and flags or removes those who left",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 20 */6 * * *", // Every 6 hours at minute 20
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityNormal,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name": "group_membership_revalidation",
+				"parameters": map[string]interface{}{
+					"action": "flag", // flag or remove
+				},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    2,
+				RetryInterval: models.Duration(15 * time.Minute),
+				Timeout:       models.Duration(30 * time.Minute),
+				Tags:          []string{"system", "groups", "esi"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-group-membership-expiry",
+			Name:        "Group Membership Expiry",
+			Description: "Notifies members before their time-limited group membership expires and removes expired memberships",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 */10 * * * *", // Every 10 minutes
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityNormal,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "group_membership_expiry",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    1,
+				RetryInterval: models.Duration(5 * time.Minute),
+				Timeout:       models.Duration(5 * time.Minute),
+				Tags:          []string{"system", "groups", "expiry"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-group-audit-archival",
+			Name:        "Groups Audit Archival",
+			Description: "Moves groups audit events past their retention to the archive collection and purges expired archived events",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 30 3 * * *", // Daily at 03:30
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityLow,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "group_audit_archival",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    2,
+				RetryInterval: models.Duration(30 * time.Minute),
+				Timeout:       models.Duration(30 * time.Minute),
+				Tags:          []string{"system", "groups", "audit"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-group-snapshot",
+			Name:        "Group Snapshot",
+			Description: "Records group memberships and permission grants for historical access queries and prunes expired snapshots",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 5 0 * * *", // Daily at 00:05
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityNormal,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "group_snapshot",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    3,
+				RetryInterval: models.Duration(15 * time.Minute),
+				Timeout:       models.Duration(15 * time.Minute),
+				Tags:          []string{"system", "groups", "snapshots"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
 		{
 			ID:          "system-alliance-bulk-import",
 			Name:        "Alliance Bulk Import",
@@ -418,6 +602,188 @@ func GetSystemTasks() []*models.Task {
 			UpdatedAt: now,
 			CreatedBy: "system",
 		},
+		{
+			ID:          "system-sde-auto-update",
+			Name:        "SDE Auto-Update",
+			Description: "Checks for new SDE versions and imports them during the maintenance window with validation and rollback",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 15 * * * *", // Every hour at :15
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityLow,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "sde_auto_update",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    0, // A failed import is rolled back; the next hourly run retries
+				RetryInterval: models.Duration(30 * time.Minute),
+				Timeout:       models.Duration(45 * time.Minute),
+				Tags:          []string{"system", "sde", "maintenance"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-corporation-budget-alerts",
+			Name:        "Corporation Budget Alerts",
+			Description: "Checks corporation wallet divisions against budget alert rules and notifies finance officers",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 */15 * * * *", // Every 15 minutes
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityNormal,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "corporation_budget_alerts",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    1,
+				RetryInterval: models.Duration(5 * time.Minute),
+				Timeout:       models.Duration(10 * time.Minute),
+				Tags:          []string{"system", "corporation", "wallet", "notifications"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-user-inactivity-policy",
+			Name:        "Inactive User Policy",
+			Description: "Flags users without login or in-game activity, notifies them, then disables and purges them per the retention settings",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 30 5 * * *", // Daily at 5:30 AM
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityLow,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "user_inactivity_policy",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    1,
+				RetryInterval: models.Duration(30 * time.Minute),
+				Timeout:       models.Duration(30 * time.Minute),
+				Tags:          []string{"system", "users", "maintenance", "retention"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-user-account-erasure",
+			Name:        "User Account Erasure",
+			Description: "Erases user accounts whose deletion request passed its grace period and records a deletion receipt",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 */15 * * * *", // Every 15 minutes
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityNormal,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "user_account_erasure",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    1,
+				RetryInterval: models.Duration(5 * time.Minute),
+				Timeout:       models.Duration(10 * time.Minute),
+				Tags:          []string{"system", "users", "gdpr"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-user-data-exports",
+			Name:        "User Data Exports",
+			Description: "Builds data exports that were not finished right after the request and deletes expired export archives",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 */5 * * * *", // Every 5 minutes
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityLow,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "user_data_exports",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    1,
+				RetryInterval: models.Duration(5 * time.Minute),
+				Timeout:       models.Duration(10 * time.Minute),
+				Tags:          []string{"system", "users", "gdpr"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-user-soft-delete-purge",
+			Name:        "User Soft Delete Purge",
+			Description: "Permanently deletes user characters that were soft-deleted longer ago than the retention period",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 30 4 * * *", // Daily at 4:30 AM
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityLow,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "user_soft_delete_purge",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    2,
+				RetryInterval: models.Duration(30 * time.Minute),
+				Timeout:       models.Duration(30 * time.Minute),
+				Tags:          []string{"system", "users", "cleanup"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
+		{
+			ID:          "system-notification-ack-reminders",
+			Name:        "Notification Acknowledgement Reminders",
+			Description: "Re-sends acknowledgement-required notifications to recipients who have not acknowledged them",
+			Type:        models.TaskTypeSystem,
+			Schedule:    "0 */5 * * * *", // Every 5 minutes
+			Status:      models.TaskStatusPending,
+			Priority:    models.TaskPriorityNormal,
+			Enabled:     true,
+			Config: map[string]interface{}{
+				"task_name":  "notification_ack_reminders",
+				"parameters": map[string]interface{}{},
+			},
+			Metadata: models.TaskMetadata{
+				MaxRetries:    1,
+				RetryInterval: models.Duration(1 * time.Minute),
+				Timeout:       models.Duration(4 * time.Minute),
+				Tags:          []string{"system", "notifications"},
+				IsSystem:      true,
+				Source:        "system",
+				Version:       1,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: "system",
+		},
 	}
 }
 
@@ -500,4 +866,32 @@ var SystemTaskDefinitions = map[string]models.SystemTaskDefinition{
 		Purpose:     "Detects when EVE ESI transitions to token-based pagination and adjusts market fetching strategy accordingly",
 		Priority:    "Low",
 	},
+	"system-sde-auto-update": {
+		Name:        "SDE Auto-Update",
+		Description: "Checks CCP for a new SDE version and imports it inside the configured maintenance window",
+		Schedule:    "Every hour at :15",
+		Purpose:     "Keeps static data current without manual imports, rolling back if the new data fails validation",
+		Priority:    "Low",
+	},
+	"system-corporation-budget-alerts": {
+		Name:        "Corporation Budget Alerts",
+		Description: "Checks corporation wallet divisions against budget alert rules and notifies finance officers",
+		Schedule:    "Every 15 minutes",
+		Purpose:     "Warns finance officers of low division balances and unusually large wallet movements",
+		Priority:    "Normal",
+	},
+	"system-user-inactivity-policy": {
+		Name:        "Inactive User Policy",
+		Description: "Flags users without login or in-game activity, notifies them, then disables and purges them per the retention settings",
+		Schedule:    "Daily at 5:30 AM",
+		Purpose:     "Removes abandoned accounts and their EVE tokens while warning users first",
+		Priority:    "Low",
+	},
+	"system-notification-ack-reminders": {
+		Name:        "Notification Acknowledgement Reminders",
+		Description: "Reminds recipients of acknowledgement-required notifications they have not acknowledged yet",
+		Schedule:    "Every 5 minutes",
+		Purpose:     "Makes sure CTA pings and policy changes are seen, up to the configured number of reminders",
+		Priority:    "Normal",
+	},
 }
@@ -218,3 +218,23 @@ type UpdateLogEntry struct {
 	Duration  string `json:"duration,omitempty" doc:"Duration of this step"`
 	Success   bool   `json:"success" doc:"Whether this step succeeded"`
 }
+
+// Auto-update outcomes
+const (
+	AutoUpdateUpToDate   = "up_to_date"
+	AutoUpdateSkipped    = "skipped"
+	AutoUpdateUpdated    = "updated"
+	AutoUpdateRolledBack = "rolled_back"
+	AutoUpdateFailed     = "failed"
+)
+
+// AutoUpdateResult describes the outcome of a scheduled SDE auto-update run
+type AutoUpdateResult struct {
+	Outcome     string         `json:"outcome" doc:"Run outcome: up_to_date, skipped, updated, rolled_back or failed"`
+	Reason      string         `json:"reason,omitempty" doc:"Why the run was skipped, rolled back or failed"`
+	OldVersion  string         `json:"old_version,omitempty" doc:"SDE version before the run"`
+	NewVersion  string         `json:"new_version,omitempty" doc:"SDE version offered by CCP"`
+	CountsAfter map[string]int `json:"counts_after,omitempty" doc:"Entity counts per data type after the import"`
+	StartedAt   string         `json:"started_at" doc:"When the run started"`
+	Duration    string         `json:"duration" doc:"How long the run took"`
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 
 	"go-falcon/internal/auth"
+	"go-falcon/internal/sde_admin/dto"
 	"go-falcon/internal/sde_admin/routes"
 	"go-falcon/internal/sde_admin/services"
 	"go-falcon/pkg/database"
@@ -30,7 +31,7 @@ type Module struct {
 
 // New creates a new SDE admin module instance
 func New(mongodb *database.MongoDB, redis *database.Redis, authModule *auth.Module, permissionManager *permissions.PermissionManager, sdeService sde.SDEService) *Module {
-	service := services.NewService(sdeService)
+	service := services.NewService(sdeService, redis)
 
 	return &Module{
 		BaseModule:        module.NewBaseModule("sde_admin", mongodb, redis),
@@ -76,6 +77,16 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string) {
 	log.Printf("SDE admin module unified routes registered at %s", basePath)
 }
 
+// SetNotifier sets the notifier used to report automatic SDE update outcomes to administrators
+func (m *Module) SetNotifier(notifier services.AdminNotifier) {
+	m.service.SetNotifier(notifier)
+}
+
+// RunAutoUpdate checks for a new SDE version and imports it if the preconditions hold (used by the scheduler)
+func (m *Module) RunAutoUpdate(ctx context.Context) (*dto.AutoUpdateResult, error) {
+	return m.service.AutoUpdate(ctx)
+}
+
 // StartBackgroundTasks starts any background processes for the module
 func (m *Module) StartBackgroundTasks(ctx context.Context) {
 	slog.Info("Starting SDE admin background tasks")
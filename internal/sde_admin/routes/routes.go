@@ -8,7 +8,9 @@ import (
 
 	"go-falcon/internal/sde_admin/dto"
 	"go-falcon/internal/sde_admin/services"
+	"go-falcon/pkg/apidocs"
 	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/stepup"
 
 	"github.com/danielgtaylor/huma/v2"
 )
@@ -54,7 +56,8 @@ func RegisterSDEAdminRoutes(api huma.API, basePath string, service *services.Ser
 		Path:        fmt.Sprintf("%s/memory", basePath),
 		Summary:     "Get SDE Memory Status",
 		Description: "Returns detailed status of SDE data currently loaded in memory",
-		Tags:        []string{"SDE Admin"},
+		Tags:        []string{"SDE / Admin"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, func(ctx context.Context, input *struct {
 		dto.AuthInput
 	}) (*dto.MemoryStatusOutput, error) {
@@ -78,7 +81,8 @@ func RegisterSDEAdminRoutes(api huma.API, basePath string, service *services.Ser
 		Path:        fmt.Sprintf("%s/stats", basePath),
 		Summary:     "Get SDE Statistics",
 		Description: "Returns detailed statistics about SDE data loaded in memory",
-		Tags:        []string{"SDE Admin"},
+		Tags:        []string{"SDE / Admin"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, func(ctx context.Context, input *struct {
 		dto.AuthInput
 	}) (*dto.SDEStatsOutput, error) {
@@ -102,7 +106,8 @@ func RegisterSDEAdminRoutes(api huma.API, basePath string, service *services.Ser
 		Path:        fmt.Sprintf("%s/reload", basePath),
 		Summary:     "Reload SDE Data",
 		Description: "Reload SDE data from files into memory. Can reload all data types or specific ones.",
-		Tags:        []string{"SDE Admin"},
+		Tags:        []string{"SDE / Admin"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, func(ctx context.Context, input *struct {
 		dto.AuthInput
 		Body dto.ReloadSDERequest `json:"body"`
@@ -127,7 +132,8 @@ func RegisterSDEAdminRoutes(api huma.API, basePath string, service *services.Ser
 		Path:        fmt.Sprintf("%s/verify", basePath),
 		Summary:     "Verify SDE Data Integrity",
 		Description: "Verify the integrity and completeness of loaded SDE data",
-		Tags:        []string{"SDE Admin"},
+		Tags:        []string{"SDE / Admin"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, func(ctx context.Context, input *struct {
 		dto.AuthInput
 	}) (*dto.VerificationOutput, error) {
@@ -151,7 +157,8 @@ func RegisterSDEAdminRoutes(api huma.API, basePath string, service *services.Ser
 		Path:        fmt.Sprintf("%s/system", basePath),
 		Summary:     "Get System Information",
 		Description: "Get system information relevant to SDE data management including memory usage",
-		Tags:        []string{"SDE Admin"},
+		Tags:        []string{"SDE / Admin"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, func(ctx context.Context, input *struct {
 		dto.AuthInput
 	}) (*dto.SystemInfoOutput, error) {
@@ -175,7 +182,8 @@ func RegisterSDEAdminRoutes(api huma.API, basePath string, service *services.Ser
 		Path:        fmt.Sprintf("%s/check-updates", basePath),
 		Summary:     "Check for SDE Updates",
 		Description: "Check configured sources for available SDE updates",
-		Tags:        []string{"SDE Admin"},
+		Tags:        []string{"SDE / Admin"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, func(ctx context.Context, input *struct {
 		dto.AuthInput
 		Body dto.CheckUpdatesRequest `json:"body"`
@@ -200,7 +208,8 @@ func RegisterSDEAdminRoutes(api huma.API, basePath string, service *services.Ser
 		Path:        fmt.Sprintf("%s/update", basePath),
 		Summary:     "Update SDE Data",
 		Description: "Download and install SDE updates from configured sources",
-		Tags:        []string{"SDE Admin"},
+		Tags:        []string{"SDE / Admin"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, func(ctx context.Context, input *struct {
 		dto.AuthInput
 		Body dto.UpdateSDERequest `json:"body"`
@@ -211,6 +220,12 @@ func RegisterSDEAdminRoutes(api huma.API, basePath string, service *services.Ser
 			return nil, err
 		}
 
+		// Replacing the SDE affects every module; require a second factor, or a recent sign-in
+		// from admins without one
+		if err := stepup.RequireSecondFactor(ctx, "updateSDE"); err != nil {
+			return nil, err
+		}
+
 		response, err := service.UpdateSDE(ctx, &input.Body)
 		if err != nil {
 			return nil, err
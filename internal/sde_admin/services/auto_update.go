@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-falcon/internal/sde_admin/dto"
+	"go-falcon/pkg/config"
+)
+
+// autoUpdateLockKey prevents two instances from importing at the same time
+const autoUpdateLockKey = "sde:auto_update:lock"
+
+// AdminNotifier delivers auto-update outcomes to administrators
+type AdminNotifier interface {
+	NotifySystemGroup(ctx context.Context, systemName, message string, data map[string]interface{}) error
+}
+
+// SetNotifier sets the notifier used to report auto-update outcomes
+func (s *Service) SetNotifier(notifier AdminNotifier) {
+	s.notifier = notifier
+}
+
+// AutoUpdate checks for a new SDE version and imports it when the preconditions hold.
+// The current data directory is backed up first and restored if the import or its validation fails.
+func (s *Service) AutoUpdate(ctx context.Context) (*dto.AutoUpdateResult, error) {
+	startTime := time.Now()
+	result := &dto.AutoUpdateResult{StartedAt: startTime.Format(time.RFC3339)}
+	defer func() {
+		result.Duration = time.Since(startTime).String()
+	}()
+
+	check, err := s.CheckForUpdates(ctx, &dto.CheckUpdatesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for SDE updates: %w", err)
+	}
+	result.OldVersion = check.CurrentVersion
+	result.NewVersion = check.LatestVersion
+
+	if !check.UpdatesAvailable {
+		result.Outcome = dto.AutoUpdateUpToDate
+		return result, nil
+	}
+
+	if reason := s.autoUpdatePrecondition(ctx, startTime); reason != "" {
+		slog.InfoContext(ctx, "SDE auto-update postponed", "reason", reason, "latest_version", check.LatestVersion)
+		result.Outcome = dto.AutoUpdateSkipped
+		result.Reason = reason
+		return result, nil
+	}
+
+	if s.redis != nil {
+		acquired, err := s.redis.Client.SetNX(ctx, autoUpdateLockKey, startTime.Unix(), 2*time.Hour).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire SDE auto-update lock: %w", err)
+		}
+		if !acquired {
+			result.Outcome = dto.AutoUpdateSkipped
+			result.Reason = "another instance is importing the SDE"
+			return result, nil
+		}
+		defer s.redis.Client.Del(context.Background(), autoUpdateLockKey)
+	}
+
+	s.runAutoUpdate(ctx, result)
+	s.notifyAutoUpdate(ctx, result)
+
+	return result, nil
+}
+
+// runAutoUpdate performs backup, import, validation and rollback, recording the outcome in result
+func (s *Service) runAutoUpdate(ctx context.Context, result *dto.AutoUpdateResult) {
+	dataDir := s.updateService.dataDir
+	backupDir := filepath.Join(dataDir, "..", "sde-backup")
+
+	countsBefore := s.entityCounts()
+
+	if err := os.RemoveAll(backupDir); err != nil {
+		result.Outcome = dto.AutoUpdateFailed
+		result.Reason = fmt.Sprintf("failed to clear previous backup: %v", err)
+		return
+	}
+	if err := copyDir(dataDir, backupDir); err != nil {
+		result.Outcome = dto.AutoUpdateFailed
+		result.Reason = fmt.Sprintf("failed to back up SDE data: %v", err)
+		return
+	}
+
+	slog.InfoContext(ctx, "Starting automatic SDE import", "old_version", result.OldVersion, "new_version", result.NewVersion)
+
+	failure := ""
+	update, err := s.UpdateSDE(ctx, &dto.UpdateSDERequest{})
+	switch {
+	case err != nil:
+		failure = fmt.Sprintf("import failed: %v", err)
+	case !update.Success:
+		failure = fmt.Sprintf("import failed: %s", update.Message)
+	default:
+		if reload, err := s.ReloadSDE(ctx, &dto.ReloadSDERequest{}); err != nil || !reload.Success {
+			failure = "reloading imported data failed"
+			if reload != nil && reload.Error != "" {
+				failure = fmt.Sprintf("%s: %s", failure, reload.Error)
+			}
+		} else {
+			result.CountsAfter = s.entityCounts()
+			failure = validateEntityCounts(countsBefore, result.CountsAfter, config.GetSDEAutoUpdateMinCountPercent())
+		}
+	}
+
+	if failure == "" {
+		result.Outcome = dto.AutoUpdateUpdated
+		slog.InfoContext(ctx, "Automatic SDE import completed", "new_version", result.NewVersion)
+		return
+	}
+
+	slog.ErrorContext(ctx, "Automatic SDE import failed, rolling back", "reason", failure)
+	result.Reason = failure
+
+	if err := restoreDir(backupDir, dataDir); err != nil {
+		s.SetStatus(StatusError)
+		result.Outcome = dto.AutoUpdateFailed
+		result.Reason = fmt.Sprintf("%s; rollback failed: %v", failure, err)
+		return
+	}
+	if reload, err := s.ReloadSDE(ctx, &dto.ReloadSDERequest{}); err != nil || !reload.Success {
+		result.Outcome = dto.AutoUpdateFailed
+		result.Reason = fmt.Sprintf("%s; reloading restored data failed", failure)
+		return
+	}
+
+	result.Outcome = dto.AutoUpdateRolledBack
+}
+
+// autoUpdatePrecondition returns why an import may not run right now, or "" if it may
+func (s *Service) autoUpdatePrecondition(ctx context.Context, now time.Time) string {
+	if !config.GetSDEAutoUpdateEnabled() {
+		return "automatic import is disabled (SDE_AUTO_UPDATE_ENABLED)"
+	}
+
+	startHour, endHour := config.GetSDEAutoUpdateWindow()
+	if !inHourWindow(now.UTC().Hour(), startHour, endHour) {
+		return fmt.Sprintf("outside the maintenance window (%02d:00-%02d:00 UTC)", startHour, endHour)
+	}
+
+	if status := s.GetStatus(); status != StatusLoaded {
+		return fmt.Sprintf("another SDE operation is in progress (status %s)", status)
+	}
+
+	if s.redis != nil {
+		usedPercent, err := s.redisMemoryPercent(ctx)
+		if err != nil {
+			return fmt.Sprintf("could not read Redis memory usage: %v", err)
+		}
+		if limit := config.GetSDEAutoUpdateMaxRedisMemoryPercent(); usedPercent > float64(limit) {
+			return fmt.Sprintf("Redis memory usage %.1f%% exceeds %d%%", usedPercent, limit)
+		}
+	}
+
+	return ""
+}
+
+// redisMemoryPercent returns used_memory as a percentage of maxmemory, or 0 when Redis has no limit
+func (s *Service) redisMemoryPercent(ctx context.Context) (float64, error) {
+	info, err := s.redis.Client.Info(ctx, "memory").Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var used, max float64
+	for _, line := range strings.Split(info, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "used_memory":
+			used, _ = strconv.ParseFloat(value, 64)
+		case "maxmemory":
+			max, _ = strconv.ParseFloat(value, 64)
+		}
+	}
+
+	if max == 0 {
+		return 0, nil
+	}
+	return used / max * 100, nil
+}
+
+// entityCounts returns the item count of every loaded data type
+func (s *Service) entityCounts() map[string]int {
+	counts := make(map[string]int)
+	for name, status := range s.sdeService.GetLoadStatus() {
+		if status.Loaded {
+			counts[name] = status.Count
+		}
+	}
+	return counts
+}
+
+// notifyAutoUpdate tells super administrators about imports, rollbacks and failures
+func (s *Service) notifyAutoUpdate(ctx context.Context, result *dto.AutoUpdateResult) {
+	if s.notifier == nil {
+		return
+	}
+
+	var message string
+	switch result.Outcome {
+	case dto.AutoUpdateUpdated:
+		message = fmt.Sprintf("SDE automatically updated to version %s", result.NewVersion)
+	case dto.AutoUpdateRolledBack:
+		message = fmt.Sprintf("Automatic SDE update to %s was rolled back: %s", result.NewVersion, result.Reason)
+	default:
+		message = fmt.Sprintf("Automatic SDE update to %s failed: %s", result.NewVersion, result.Reason)
+	}
+
+	data := map[string]interface{}{
+		"outcome":     result.Outcome,
+		"old_version": result.OldVersion,
+		"new_version": result.NewVersion,
+	}
+	if err := s.notifier.NotifySystemGroup(ctx, "super_admin", message, data); err != nil {
+		slog.WarnContext(ctx, "Failed to notify administrators about SDE auto-update", "error", err)
+	}
+}
+
+// validateEntityCounts spot-checks that no data type disappeared or shrank below minPercent of its previous count
+func validateEntityCounts(before, after map[string]int, minPercent int) string {
+	var problems []string
+	for name, previous := range before {
+		if previous == 0 {
+			continue
+		}
+		current, ok := after[name]
+		if !ok || current == 0 {
+			problems = append(problems, fmt.Sprintf("%s is empty", name))
+			continue
+		}
+		if current*100 < previous*minPercent {
+			problems = append(problems, fmt.Sprintf("%s dropped from %d to %d", name, previous, current))
+		}
+	}
+
+	if len(problems) == 0 {
+		return ""
+	}
+	return "validation failed: " + strings.Join(problems, ", ")
+}
+
+// inHourWindow reports whether hour lies in [start, end), wrapping past midnight when start > end
+func inHourWindow(hour, start, end int) bool {
+	if start == end {
+		return true
+	}
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// restoreDir replaces dst with a copy of src
+func restoreDir(src, dst string) error {
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	return copyDir(src, dst)
+}
+
+// copyDir recursively copies the contents of src into dst
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
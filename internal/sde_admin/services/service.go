@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"go-falcon/internal/sde_admin/dto"
+	"go-falcon/pkg/database"
 	"go-falcon/pkg/sde"
 )
 
@@ -26,18 +27,21 @@ const (
 type Service struct {
 	sdeService    sde.SDEService
 	updateService *UpdateService
+	redis         *database.Redis
+	notifier      AdminNotifier
 	status        string
 	statusMutex   sync.RWMutex
 }
 
 // NewService creates a new SDE admin service
-func NewService(sdeService sde.SDEService) *Service {
+func NewService(sdeService sde.SDEService, redis *database.Redis) *Service {
 	// Get data directory from SDE service (assuming it has a method to get this)
 	dataDir := "data/sde" // Default fallback
 
 	service := &Service{
 		sdeService:    sdeService,
 		updateService: NewUpdateService(dataDir),
+		redis:         redis,
 		status:        StatusLoaded, // Default to loaded status
 	}
 
@@ -92,8 +92,62 @@ var DefaultSiteSettings = []SiteSetting{
 		IsPublic:    true,
 		IsActive:    true,
 	},
+	{
+		Key: DataResidencyKey,
+		Value: map[string]interface{}{
+			"wallets": "full",
+			"assets":  "full",
+		},
+		Type:        SettingTypeObject,
+		Category:    "security",
+		Description: "Storage of sensitive ESI data by category: full, cache (never persisted) or disabled. Can only restrict the DATA_RESIDENCY_* configuration",
+		IsPublic:    false,
+		IsActive:    true,
+	},
+	{
+		Key:         EVEScopeSetsKey,
+		Value:       map[string]interface{}{},
+		Type:        SettingTypeObject,
+		Category:    "auth",
+		Description: "Named EVE SSO scope sets requested with /auth/eve/login?scopes=<name>, e.g. {\"director\": \"esi-corporations.read_structures.v1 ...\"}. basic (no scopes) and full (EVE_SCOPES) are built in and can be overridden",
+		IsPublic:    false,
+		IsActive:    true,
+	},
+	{
+		Key:         AuthCookieKey,
+		Value:       map[string]interface{}{},
+		Type:        SettingTypeObject,
+		Category:    "auth",
+		Description: "Auth cookie attributes overriding the COOKIE_* configuration: domain (\"host-only\" for none), same_site (lax, strict or none), secure (boolean) and max_age (e.g. \"12h\", at most COOKIE_DURATION). Missing fields keep the configured value",
+		IsPublic:    false,
+		IsActive:    true,
+	},
+	{
+		Key: DiscordRoleSyncKey,
+		Value: map[string]interface{}{
+			"push_on_change": true,
+			"push_delay":     "10s",
+		},
+		Type:        SettingTypeObject,
+		Category:    "system",
+		Description: "Discord role sync: push_on_change (boolean) updates a user's Discord roles when one of their mapped group memberships changes, after push_delay (e.g. \"10s\", at most 5m) to batch bursts. The periodic sync runs regardless",
+		IsPublic:    false,
+		IsActive:    true,
+	},
 }
 
+// DataResidencyKey is the setting holding the per-category data residency modes
+const DataResidencyKey = "data_residency"
+
+// EVEScopeSetsKey is the setting holding the named EVE SSO scope sets
+const EVEScopeSetsKey = "eve_scope_sets"
+
+// AuthCookieKey is the setting holding the auth cookie attributes
+const AuthCookieKey = "auth_cookie"
+
+// DiscordRoleSyncKey is the setting controlling how membership changes reach Discord roles
+const DiscordRoleSyncKey = "discord_role_sync"
+
 // SettingCategories contains valid categories for organization
 var SettingCategories = []string{
 	"general",
@@ -8,6 +8,7 @@ import (
 	"go-falcon/internal/site_settings/dto"
 	"go-falcon/internal/site_settings/models"
 	"go-falcon/internal/site_settings/services"
+	"go-falcon/pkg/apidocs"
 	"go-falcon/pkg/middleware"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -57,6 +58,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Create site setting",
 		Description: "Create a new site configuration setting (super admin only)",
 		Tags:        []string{"Site Settings / Management"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.createSettingHandler)
 
 	huma.Register(api, huma.Operation{
@@ -66,6 +68,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "List site settings",
 		Description: "List all site settings with filtering and pagination (super admin only)",
 		Tags:        []string{"Site Settings / Management"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.listSettingsHandler)
 
 	huma.Register(api, huma.Operation{
@@ -75,6 +78,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Get site setting",
 		Description: "Get a specific site setting by key (super admin only)",
 		Tags:        []string{"Site Settings / Management"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.getSettingHandler)
 
 	huma.Register(api, huma.Operation{
@@ -84,6 +88,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Update site setting",
 		Description: "Update an existing site setting (super admin only)",
 		Tags:        []string{"Site Settings / Management"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.updateSettingHandler)
 
 	huma.Register(api, huma.Operation{
@@ -93,6 +98,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Delete site setting",
 		Description: "Delete a site setting (super admin only)",
 		Tags:        []string{"Site Settings / Management"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.deleteSettingHandler)
 
 	// Corporation Management endpoints (super admin only)
@@ -103,6 +109,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Add managed corporation",
 		Description: "Add a new managed corporation with enable/disable status (super admin only)",
 		Tags:        []string{"Site Settings / Corporations"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.addCorporationHandler)
 
 	huma.Register(api, huma.Operation{
@@ -112,6 +119,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "List managed corporations",
 		Description: "List all managed corporations with optional filtering by enabled status (super admin only)",
 		Tags:        []string{"Site Settings / Corporations"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.listCorporationsHandler)
 
 	huma.Register(api, huma.Operation{
@@ -121,6 +129,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Get managed corporation",
 		Description: "Get a specific managed corporation by ID (super admin only)",
 		Tags:        []string{"Site Settings / Corporations"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.getCorporationHandler)
 
 	huma.Register(api, huma.Operation{
@@ -130,6 +139,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Update corporation status",
 		Description: "Enable or disable a managed corporation (super admin only)",
 		Tags:        []string{"Site Settings / Corporations"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.updateCorporationStatusHandler)
 
 	huma.Register(api, huma.Operation{
@@ -139,6 +149,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Remove managed corporation",
 		Description: "Remove a managed corporation completely (super admin only)",
 		Tags:        []string{"Site Settings / Corporations"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.removeCorporationHandler)
 
 	huma.Register(api, huma.Operation{
@@ -148,6 +159,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Bulk update corporations",
 		Description: "Bulk update or add multiple managed corporations (super admin only)",
 		Tags:        []string{"Site Settings / Corporations"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.bulkUpdateCorporationsHandler)
 
 	huma.Register(api, huma.Operation{
@@ -157,6 +169,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Reorder corporations",
 		Description: "Reorder managed corporations by specifying new positions (super admin only)",
 		Tags:        []string{"Site Settings / Corporations"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.reorderCorporationsHandler)
 
 	// Alliance Management endpoints (super admin only)
@@ -167,6 +180,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Add managed alliance",
 		Description: "Add a new managed alliance with enable/disable status (super admin only)",
 		Tags:        []string{"Site Settings / Alliances"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.addAllianceHandler)
 
 	huma.Register(api, huma.Operation{
@@ -176,6 +190,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "List managed alliances",
 		Description: "List all managed alliances with optional filtering by enabled status (super admin only)",
 		Tags:        []string{"Site Settings / Alliances"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.listAlliancesHandler)
 
 	huma.Register(api, huma.Operation{
@@ -185,6 +200,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Get managed alliance",
 		Description: "Get a specific managed alliance by ID (super admin only)",
 		Tags:        []string{"Site Settings / Alliances"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.getAllianceHandler)
 
 	huma.Register(api, huma.Operation{
@@ -194,6 +210,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Update alliance status",
 		Description: "Enable or disable a managed alliance (super admin only)",
 		Tags:        []string{"Site Settings / Alliances"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.updateAllianceStatusHandler)
 
 	huma.Register(api, huma.Operation{
@@ -203,6 +220,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Remove managed alliance",
 		Description: "Remove a managed alliance completely (super admin only)",
 		Tags:        []string{"Site Settings / Alliances"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.removeAllianceHandler)
 
 	huma.Register(api, huma.Operation{
@@ -212,6 +230,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Bulk update alliances",
 		Description: "Bulk update or add multiple managed alliances (super admin only)",
 		Tags:        []string{"Site Settings / Alliances"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.bulkUpdateAlliancesHandler)
 
 	huma.Register(api, huma.Operation{
@@ -221,6 +240,7 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API) {
 		Summary:     "Reorder alliances",
 		Description: "Reorder managed alliances by specifying new positions (super admin only)",
 		Tags:        []string{"Site Settings / Alliances"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
 	}, m.reorderAlliancesHandler)
 }
 
@@ -6,10 +6,14 @@ import (
 	"log/slog"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"go-falcon/internal/site_settings/dto"
 	"go-falcon/internal/site_settings/models"
+	"go-falcon/pkg/invalidation"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -19,10 +23,21 @@ type GroupsServiceInterface interface {
 	HandleEntityStatusChange(ctx context.Context, entityType string, entityID int64, enabled bool) error
 }
 
+// CacheInvalidator tells the other instances that the settings changed
+type CacheInvalidator interface {
+	Publish(ctx context.Context, scope string)
+}
+
 // Service handles business logic for site settings
 type Service struct {
 	repo          *Repository
 	groupsService GroupsServiceInterface
+	invalidator   CacheInvalidator
+
+	// Settings read on hot paths (managed corporations and alliances, single keys) are kept
+	// until a write on any instance invalidates them
+	cacheMu sync.RWMutex
+	cache   map[string]*models.SiteSetting
 }
 
 // NewService creates a new service instance
@@ -31,6 +46,7 @@ func NewService(db *mongo.Database) *Service {
 	return &Service{
 		repo:          repo,
 		groupsService: nil, // Will be set later via SetGroupsService
+		cache:         make(map[string]*models.SiteSetting),
 	}
 }
 
@@ -39,6 +55,139 @@ func (s *Service) SetGroupsService(groupsService GroupsServiceInterface) {
 	s.groupsService = groupsService
 }
 
+// SetCacheInvalidator sets where setting changes are announced to the other instances
+func (s *Service) SetCacheInvalidator(invalidator CacheInvalidator) {
+	s.invalidator = invalidator
+}
+
+// InvalidateCache drops the cached settings of this instance
+func (s *Service) InvalidateCache() {
+	s.cacheMu.Lock()
+	s.cache = make(map[string]*models.SiteSetting)
+	s.cacheMu.Unlock()
+}
+
+// settingsChanged drops the local cache and announces the change to the other instances
+func (s *Service) settingsChanged(ctx context.Context) {
+	s.InvalidateCache()
+	if s.invalidator != nil {
+		s.invalidator.Publish(ctx, invalidation.ScopeSiteSettings)
+	}
+}
+
+// getCachedSetting returns a setting from the cache, loading it on a miss. Callers must not
+// modify the returned setting.
+func (s *Service) getCachedSetting(ctx context.Context, key string) (*models.SiteSetting, error) {
+	s.cacheMu.RLock()
+	setting, ok := s.cache[key]
+	s.cacheMu.RUnlock()
+	if ok {
+		return setting, nil
+	}
+
+	setting, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMu.Lock()
+	s.cache[key] = setting
+	s.cacheMu.Unlock()
+	return setting, nil
+}
+
+// DataResidencyModes returns the storage mode set per sensitive ESI data category; it implements
+// residency.SettingsSource
+func (s *Service) DataResidencyModes(ctx context.Context) (map[string]string, error) {
+	fields, err := s.activeObjectSetting(ctx, models.DataResidencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	modes := map[string]string{}
+	for category, mode := range fields {
+		if name, ok := mode.(string); ok {
+			modes[category] = name
+		}
+	}
+	return modes, nil
+}
+
+// EVEScopeSets returns the named EVE SSO scope sets as space-separated scope lists. A set may be
+// stored as a string or as an array of scopes.
+func (s *Service) EVEScopeSets(ctx context.Context) (map[string]string, error) {
+	fields, err := s.activeObjectSetting(ctx, models.EVEScopeSetsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := map[string]string{}
+	for name, value := range fields {
+		switch scopes := value.(type) {
+		case string:
+			sets[name] = strings.Join(strings.Fields(scopes), " ")
+		case []interface{}:
+			sets[name] = joinScopes(scopes)
+		case bson.A:
+			sets[name] = joinScopes(scopes)
+		}
+	}
+	return sets, nil
+}
+
+// AuthCookieSettings returns the auth cookie attributes set in site settings; it implements
+// middleware.CookieSettingsSource
+func (s *Service) AuthCookieSettings(ctx context.Context) (map[string]interface{}, error) {
+	return s.activeObjectSetting(ctx, models.AuthCookieKey)
+}
+
+// DiscordRoleSyncSettings returns the Discord role sync options set in site settings; it implements
+// the Discord module's RoleSyncSettingsSource
+func (s *Service) DiscordRoleSyncSettings(ctx context.Context) (map[string]interface{}, error) {
+	return s.activeObjectSetting(ctx, models.DiscordRoleSyncKey)
+}
+
+func joinScopes(values []interface{}) string {
+	scopes := make([]string, 0, len(values))
+	for _, value := range values {
+		if scope, ok := value.(string); ok && scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return strings.Join(scopes, " ")
+}
+
+// activeObjectSetting returns the fields of an object setting; a missing or inactive setting has none
+func (s *Service) activeObjectSetting(ctx context.Context, key string) (map[string]interface{}, error) {
+	setting, err := s.getCachedSetting(ctx, key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments || err.Error() == fmt.Sprintf("setting with key '%s' not found", key) {
+			return map[string]interface{}{}, nil
+		}
+		return nil, err
+	}
+	if !setting.IsActive {
+		return map[string]interface{}{}, nil
+	}
+
+	fields := map[string]interface{}{}
+	switch value := setting.Value.(type) {
+	case map[string]interface{}:
+		for name, field := range value {
+			fields[name] = field
+		}
+	case bson.M:
+		for name, field := range value {
+			fields[name] = field
+		}
+	case bson.D:
+		for _, element := range value {
+			fields[element.Key] = element.Value
+		}
+	}
+	return fields, nil
+}
+
 // InitializeModule initializes the site settings module
 func (s *Service) InitializeModule(ctx context.Context) error {
 	// Create database indexes
@@ -60,6 +209,7 @@ func (s *Service) CreateSetting(ctx context.Context, input *dto.CreateSiteSettin
 	if err := s.validateValueType(input.Body.Value, input.Body.Type); err != nil {
 		return nil, fmt.Errorf("invalid value for type '%s': %w", input.Body.Type, err)
 	}
+	defer s.settingsChanged(ctx)
 
 	setting := &models.SiteSetting{
 		Key:         input.Body.Key,
@@ -81,7 +231,7 @@ func (s *Service) CreateSetting(ctx context.Context, input *dto.CreateSiteSettin
 
 // GetSetting retrieves a setting by key
 func (s *Service) GetSetting(ctx context.Context, key string) (*models.SiteSetting, error) {
-	return s.repo.GetByKey(ctx, key)
+	return s.getCachedSetting(ctx, key)
 }
 
 // UpdateSetting updates an existing site setting
@@ -132,11 +282,13 @@ func (s *Service) UpdateSetting(ctx context.Context, key string, input *dto.Upda
 		return nil, fmt.Errorf("no valid updates provided")
 	}
 
+	defer s.settingsChanged(ctx)
 	return s.repo.Update(ctx, key, updates, updatedBy)
 }
 
 // DeleteSetting deletes a site setting
 func (s *Service) DeleteSetting(ctx context.Context, key string) error {
+	defer s.settingsChanged(ctx)
 	return s.repo.Delete(ctx, key)
 }
 
@@ -543,7 +695,7 @@ func (s *Service) IsCorporationEnabled(ctx context.Context, corporationID int64)
 
 // getManagedCorporationsData retrieves the managed corporations from the setting
 func (s *Service) getManagedCorporationsData(ctx context.Context) ([]dto.ManagedCorporation, error) {
-	setting, err := s.repo.GetByKey(ctx, "managed_corporations")
+	setting, err := s.getCachedSetting(ctx, "managed_corporations")
 	if err != nil {
 		// If setting doesn't exist, return empty array
 		if err == mongo.ErrNoDocuments {
@@ -590,6 +742,8 @@ func (s *Service) getManagedCorporationsData(ctx context.Context) ([]dto.Managed
 
 // updateManagedCorporationsSetting updates the managed_corporations setting
 func (s *Service) updateManagedCorporationsSetting(ctx context.Context, corporations []dto.ManagedCorporation, updatedBy int64) error {
+	defer s.settingsChanged(ctx)
+
 	// Convert DTOs to models
 	modelCorps := make([]models.ManagedCorporation, len(corporations))
 	for i, corp := range corporations {
@@ -1041,7 +1195,7 @@ func (s *Service) IsAllianceEnabled(ctx context.Context, allianceID int64) (bool
 
 // getManagedAlliancesData retrieves the managed alliances from the setting
 func (s *Service) getManagedAlliancesData(ctx context.Context) ([]dto.ManagedAlliance, error) {
-	setting, err := s.repo.GetByKey(ctx, "managed_alliances")
+	setting, err := s.getCachedSetting(ctx, "managed_alliances")
 	if err != nil {
 		// If setting doesn't exist, return empty array
 		if err == mongo.ErrNoDocuments {
@@ -1088,6 +1242,8 @@ func (s *Service) getManagedAlliancesData(ctx context.Context) ([]dto.ManagedAll
 
 // updateManagedAlliancesSetting updates the managed_alliances setting
 func (s *Service) updateManagedAlliancesSetting(ctx context.Context, alliances []dto.ManagedAlliance, updatedBy int64) error {
+	defer s.settingsChanged(ctx)
+
 	// Convert DTOs to models
 	modelAlliances := make([]models.ManagedAlliance, len(alliances))
 	for i, alliance := range alliances {
@@ -1190,7 +1346,7 @@ func (s *Service) sortAlliancesByPosition(alliances []dto.ManagedAlliance) {
 
 // GetEnabledCorporations returns only the enabled corporations for groups module integration
 func (s *Service) GetEnabledCorporations(ctx context.Context) ([]models.ManagedCorporation, error) {
-	setting, err := s.repo.GetByKey(ctx, "managed_corporations")
+	setting, err := s.getCachedSetting(ctx, "managed_corporations")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get managed corporations: %w", err)
 	}
@@ -1224,7 +1380,7 @@ func (s *Service) GetEnabledCorporations(ctx context.Context) ([]models.ManagedC
 
 // GetEnabledAlliances returns only the enabled alliances for groups module integration
 func (s *Service) GetEnabledAlliances(ctx context.Context) ([]models.ManagedAlliance, error) {
-	setting, err := s.repo.GetByKey(ctx, "managed_alliances")
+	setting, err := s.getCachedSetting(ctx, "managed_alliances")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get managed alliances: %w", err)
 	}
@@ -6,6 +6,7 @@ import (
 	"go-falcon/internal/sitemap/dto"
 	"go-falcon/internal/sitemap/models"
 	"go-falcon/internal/sitemap/services"
+	"go-falcon/pkg/apidocs"
 	"go-falcon/pkg/middleware"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -140,6 +141,7 @@ func (r *Routes) registerAdminRoutes(api huma.API, basePath string) {
 		Summary:     "List all routes",
 		Description: "Returns list of all routes with filtering options. Requires sitemap:routes:view permission.",
 		Tags:        []string{"Sitemap / Admin"},
+		Extensions:  apidocs.RequiresPermission("sitemap:routes:view"),
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 			{"cookieAuth": {}},
@@ -171,6 +173,7 @@ func (r *Routes) registerAdminRoutes(api huma.API, basePath string) {
 		Summary:     "Get single route",
 		Description: "Returns details of a specific route. Requires sitemap:routes:view permission.",
 		Tags:        []string{"Sitemap / Admin"},
+		Extensions:  apidocs.RequiresPermission("sitemap:routes:view"),
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 			{"cookieAuth": {}},
@@ -190,14 +193,15 @@ func (r *Routes) registerAdminRoutes(api huma.API, basePath string) {
 		return &dto.RouteOutput{Body: *route}, nil
 	})
 
-	// Create new route (admin) - requires sitemap:admin:manage permission
+	// Create new route (admin) - requires sitemap:admin:full permission
 	huma.Register(api, huma.Operation{
 		OperationID: "create-route",
 		Method:      "POST",
 		Path:        adminBasePath,
 		Summary:     "Create new route",
-		Description: "Creates a new route configuration. Requires sitemap:admin:manage permission.",
+		Description: "Creates a new route configuration. Requires sitemap:admin:full permission.",
 		Tags:        []string{"Sitemap / Admin"},
+		Extensions:  apidocs.RequiresPermission("sitemap:admin:full"),
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 			{"cookieAuth": {}},
@@ -222,28 +226,30 @@ func (r *Routes) registerAdminRoutes(api huma.API, basePath string) {
 		return &dto.CreateRouteOutput{Body: response}, nil
 	})
 
-	// Update route (admin) - requires sitemap:admin:manage permission
+	// Update route (admin) - requires sitemap:admin:full permission
 	huma.Register(api, huma.Operation{
 		OperationID: "update-route",
 		Method:      "PUT",
 		Path:        adminBasePath + "/{id}",
 		Summary:     "Update route",
-		Description: "Updates an existing route configuration. Requires sitemap:admin:manage permission.",
+		Description: "Updates an existing route configuration. Requires sitemap:admin:full permission.",
 		Tags:        []string{"Sitemap / Admin"},
+		Extensions:  apidocs.RequiresPermission("sitemap:admin:full"),
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 			{"cookieAuth": {}},
 		},
 	}, r.updateRouteHandler)
 
-	// Delete route (admin) - requires sitemap:admin:manage permission
+	// Delete route (admin) - requires sitemap:admin:full permission
 	huma.Register(api, huma.Operation{
 		OperationID: "delete-route",
 		Method:      "DELETE",
 		Path:        adminBasePath + "/{id}",
 		Summary:     "Delete route",
-		Description: "Deletes a route and all its children. Requires sitemap:admin:manage permission.",
+		Description: "Deletes a route and all its children. Requires sitemap:admin:full permission.",
 		Tags:        []string{"Sitemap / Admin"},
+		Extensions:  apidocs.RequiresPermission("sitemap:admin:full"),
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 			{"cookieAuth": {}},
@@ -276,6 +282,7 @@ func (r *Routes) registerAdminRoutes(api huma.API, basePath string) {
 		Summary:     "Bulk update navigation order",
 		Description: "Updates navigation order for multiple routes. Requires sitemap:navigation:customize permission.",
 		Tags:        []string{"Sitemap / Admin"},
+		Extensions:  apidocs.RequiresPermission("sitemap:navigation:customize"),
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 			{"cookieAuth": {}},
@@ -319,6 +326,7 @@ func (r *Routes) registerAdminRoutes(api huma.API, basePath string) {
 		Summary:     "Get sitemap statistics",
 		Description: "Returns comprehensive sitemap usage statistics. Requires sitemap:routes:view permission.",
 		Tags:        []string{"Sitemap / Admin"},
+		Extensions:  apidocs.RequiresPermission("sitemap:routes:view"),
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 			{"cookieAuth": {}},
@@ -473,6 +481,7 @@ func (r *Routes) registerFolderRoutes(api huma.API, basePath string) {
 		Summary:     "Get folder statistics",
 		Description: "Returns folder usage statistics and metrics",
 		Tags:        []string{"Sitemap / Admin"},
+		Extensions:  apidocs.RequiresPermission("sitemap:admin:full"),
 		Security: []map[string][]string{
 			{"BearerAuth": {}},
 		},
@@ -3,10 +3,12 @@ package services
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"go-falcon/internal/sitemap/dto"
 	"go-falcon/internal/sitemap/models"
+	"go-falcon/pkg/invalidation"
 	"go-falcon/pkg/permissions"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -169,6 +171,18 @@ type Service struct {
 	groupService        GroupServiceInterface
 	corporationService  CorporationServiceInterface
 	siteSettingsService SiteSettingsServiceInterface
+	invalidator         CacheInvalidator
+
+	// Stored routes are read on every navigation request; they are kept until a route
+	// change on any instance invalidates them
+	routesMu      sync.RWMutex
+	allRoutes     []models.Route
+	enabledRoutes []models.Route
+}
+
+// CacheInvalidator tells the other instances that the routes changed
+type CacheInvalidator interface {
+	Publish(ctx context.Context, scope string)
 }
 
 // NewService creates a new sitemap service
@@ -183,15 +197,67 @@ func NewService(db *mongo.Database, permissionManager *permissions.PermissionMan
 	}
 }
 
-// GetAllEnabledRoutes returns all enabled routes regardless of type (for testing/debugging)
-func (s *Service) GetAllEnabledRoutes(ctx context.Context, includeDisabled, includeHidden bool) (*models.SitemapResponse, error) {
-	// Build filter based on parameters
-	filter := bson.M{}
-	if !includeDisabled {
-		filter["is_enabled"] = true
+// SetCacheInvalidator sets where route changes are announced to the other instances
+func (s *Service) SetCacheInvalidator(invalidator CacheInvalidator) {
+	s.invalidator = invalidator
+}
+
+// InvalidateCache drops the cached routes of this instance
+func (s *Service) InvalidateCache() {
+	s.routesMu.Lock()
+	s.allRoutes = nil
+	s.enabledRoutes = nil
+	s.routesMu.Unlock()
+}
+
+// routesChanged drops the local route cache and announces the change to the other instances.
+// Mutators defer it so partially applied bulk changes are picked up too.
+func (s *Service) routesChanged(ctx context.Context) {
+	s.InvalidateCache()
+	if s.invalidator != nil {
+		s.invalidator.Publish(ctx, invalidation.ScopeSitemap)
 	}
+}
 
-	routes, err := s.repository.GetRoutes(ctx, filter)
+// loadRoutes returns the stored routes ordered by nav_order, from the cache when possible.
+// The result is a copy callers may sort and append to.
+func (s *Service) loadRoutes(ctx context.Context, includeDisabled bool) ([]models.Route, error) {
+	s.routesMu.RLock()
+	cached := s.enabledRoutes
+	if includeDisabled {
+		cached = s.allRoutes
+	}
+	s.routesMu.RUnlock()
+
+	if cached == nil {
+		filter := bson.M{}
+		if !includeDisabled {
+			filter["is_enabled"] = true
+		}
+		routes, err := s.repository.GetRoutes(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if routes == nil {
+			routes = []models.Route{}
+		}
+
+		s.routesMu.Lock()
+		if includeDisabled {
+			s.allRoutes = routes
+		} else {
+			s.enabledRoutes = routes
+		}
+		s.routesMu.Unlock()
+		cached = routes
+	}
+
+	return append([]models.Route(nil), cached...), nil
+}
+
+// GetAllEnabledRoutes returns all enabled routes regardless of type (for testing/debugging)
+func (s *Service) GetAllEnabledRoutes(ctx context.Context, includeDisabled, includeHidden bool) (*models.SitemapResponse, error) {
+	routes, err := s.loadRoutes(ctx, includeDisabled)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all routes: %w", err)
 	}
@@ -421,13 +487,7 @@ func (s *Service) convertNavItemsToGroups(items []models.NavItem) []models.Navig
 
 // GetUserRoutesWithFolders returns user-specific sitemap with folder support
 func (s *Service) GetUserRoutesWithFolders(ctx context.Context, input *dto.GetUserRoutesInput) (*models.SitemapResponse, error) {
-	// Build filter based on parameters
-	filter := bson.M{}
-	if !input.IncludeDisabled {
-		filter["is_enabled"] = true
-	}
-
-	routes, err := s.repository.GetRoutes(ctx, filter)
+	routes, err := s.loadRoutes(ctx, input.IncludeDisabled)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get routes: %w", err)
 	}
@@ -461,13 +521,7 @@ func (s *Service) GetUserRoutesWithFolders(ctx context.Context, input *dto.GetUs
 
 // GetUserRoutesWithAuth returns user-specific sitemap with permission and group filtering
 func (s *Service) GetUserRoutesWithAuth(ctx context.Context, input *dto.GetUserRoutesInput, userID string, characterID int64) (*models.SitemapResponse, error) {
-	// Build filter based on parameters
-	filter := bson.M{}
-	if !input.IncludeDisabled {
-		filter["is_enabled"] = true
-	}
-
-	routes, err := s.repository.GetRoutes(ctx, filter)
+	routes, err := s.loadRoutes(ctx, input.IncludeDisabled)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get routes: %w", err)
 	}
@@ -841,6 +895,8 @@ func (s *Service) checkRouteAccess(route models.Route, userPermissions, userGrou
 
 // CreateRoute creates a new route
 func (s *Service) CreateRoute(ctx context.Context, input *dto.CreateRouteInput) (*models.Route, error) {
+	defer s.routesChanged(ctx)
+
 	// Check if route ID already exists
 	existing, _ := s.repository.GetRouteByRouteID(ctx, input.Body.RouteID)
 	if existing != nil {
@@ -933,6 +989,8 @@ func (s *Service) CreateRoute(ctx context.Context, input *dto.CreateRouteInput)
 
 // UpdateRoute updates an existing route
 func (s *Service) UpdateRoute(ctx context.Context, routeID string, body *dto.UpdateRouteBody) (*models.Route, error) {
+	defer s.routesChanged(ctx)
+
 	// Get existing route (handles both ObjectID and route_id)
 	route, err := s.GetRouteByID(ctx, routeID)
 	if err != nil {
@@ -1061,6 +1119,8 @@ func (s *Service) UpdateRoute(ctx context.Context, routeID string, body *dto.Upd
 
 // DeleteRoute deletes a route and its children
 func (s *Service) DeleteRoute(ctx context.Context, routeID string) (int, error) {
+	defer s.routesChanged(ctx)
+
 	// Get route to check if it exists (handles both ObjectID and route_id)
 	route, err := s.GetRouteByID(ctx, routeID)
 	if err != nil {
@@ -1149,6 +1209,8 @@ func (s *Service) GetRouteByID(ctx context.Context, id string) (*models.Route, e
 
 // BulkUpdateOrder updates navigation order for multiple routes
 func (s *Service) BulkUpdateOrder(ctx context.Context, updates []dto.OrderUpdate) (int, int, []string) {
+	defer s.routesChanged(ctx)
+
 	updated := 0
 	failed := 0
 	var errors []string
@@ -1216,6 +1278,8 @@ func (s *Service) GetStatus(ctx context.Context) *dto.StatusResponse {
 
 // CreateFolder creates a new folder
 func (s *Service) CreateFolder(ctx context.Context, input *dto.CreateFolderInput) (*models.Route, error) {
+	defer s.routesChanged(ctx)
+
 	// Check if folder ID already exists
 	existing, _ := s.repository.GetRouteByRouteID(ctx, input.RouteID)
 	if existing != nil {
@@ -1298,6 +1362,8 @@ func (s *Service) CreateFolder(ctx context.Context, input *dto.CreateFolderInput
 
 // UpdateFolder updates an existing folder
 func (s *Service) UpdateFolder(ctx context.Context, folderID string, input *dto.UpdateFolderInput) (*models.Route, error) {
+	defer s.routesChanged(ctx)
+
 	// Get existing folder
 	folder, err := s.GetRouteByID(ctx, folderID)
 	if err != nil {
@@ -1393,6 +1459,8 @@ func (s *Service) UpdateFolder(ctx context.Context, folderID string, input *dto.
 
 // MoveToFolder moves a route or folder to a different parent folder
 func (s *Service) MoveToFolder(ctx context.Context, itemID string, input *dto.MoveFolderInput) (*dto.MoveFolderResponse, error) {
+	defer s.routesChanged(ctx)
+
 	// Get the item being moved
 	item, err := s.repository.GetRouteByRouteID(ctx, itemID)
 	if err != nil {
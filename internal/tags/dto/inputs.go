@@ -0,0 +1,99 @@
+package dto
+
+// ListNamespacesInput represents the input for listing the namespaces visible to the caller
+type ListNamespacesInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// CreateNamespaceInput represents the input for creating a tag namespace
+type CreateNamespaceInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Body          struct {
+		Name        string `json:"name" pattern:"^[a-z0-9][a-z0-9-]{1,31}$" doc:"Namespace name; becomes part of its permission IDs (tags:<name>:view, tags:<name>:manage)" example:"intel"`
+		Description string `json:"description,omitempty" maxLength:"500" doc:"What the namespace's tags are used for"`
+	}
+}
+
+// NamespaceInput identifies a namespace
+type NamespaceInput struct {
+	Namespace     string `path:"namespace" doc:"Namespace name"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// CreateTagInput represents the input for creating a tag in a namespace
+type CreateTagInput struct {
+	Namespace     string `path:"namespace" doc:"Namespace name"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Body          struct {
+		Name        string `json:"name" pattern:"^[a-z0-9][a-z0-9-]{0,63}$" doc:"Tag name, unique within the namespace and immutable" example:"spy-suspect"`
+		Description string `json:"description,omitempty" maxLength:"500" doc:"What the tag means"`
+		Color       string `json:"color,omitempty" pattern:"^#[0-9a-fA-F]{6}$" doc:"Display color" example:"#d9534f"`
+	}
+}
+
+// TagInput identifies a tag within a namespace
+type TagInput struct {
+	Namespace     string `path:"namespace" doc:"Namespace name"`
+	Tag           string `path:"tag" doc:"Tag name"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// UpdateTagInput represents the input for changing a tag's description or color
+type UpdateTagInput struct {
+	Namespace     string `path:"namespace" doc:"Namespace name"`
+	Tag           string `path:"tag" doc:"Tag name"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Body          struct {
+		Description *string `json:"description,omitempty" maxLength:"500" doc:"What the tag means"`
+		Color       *string `json:"color,omitempty" pattern:"^(#[0-9a-fA-F]{6})?$" doc:"Display color; empty clears it"`
+	}
+}
+
+// AttachTagInput represents the input for attaching a tag to an entity
+type AttachTagInput struct {
+	Namespace     string `path:"namespace" doc:"Namespace name"`
+	Tag           string `path:"tag" doc:"Tag name"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Body          struct {
+		EntityType string `json:"entity_type" enum:"character,corporation,killmail,fitting" doc:"Kind of entity"`
+		EntityID   string `json:"entity_id" doc:"EVE ID for characters, corporations and killmails; fitting ID for fittings" example:"2112625428"`
+		Note       string `json:"note,omitempty" maxLength:"1000" doc:"Why the tag was attached; replaces the note when already attached"`
+	}
+}
+
+// DetachTagInput represents the input for removing a tag from an entity
+type DetachTagInput struct {
+	Namespace     string `path:"namespace" doc:"Namespace name"`
+	Tag           string `path:"tag" doc:"Tag name"`
+	EntityType    string `path:"entity_type" enum:"character,corporation,killmail,fitting" doc:"Kind of entity"`
+	EntityID      string `path:"entity_id" doc:"Entity ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// FindTaggedEntitiesInput represents the input for listing entities by tag
+type FindTaggedEntitiesInput struct {
+	Namespace     string   `path:"namespace" doc:"Namespace name"`
+	Tags          []string `query:"tags" minItems:"1" maxItems:"20" doc:"Tags to filter by (comma-separated)" example:"spy-suspect"`
+	Match         string   `query:"match" enum:"any,all" default:"any" doc:"Return entities carrying any or all of the tags"`
+	EntityType    string   `query:"entity_type" enum:"character,corporation,killmail,fitting" doc:"Only entities of this kind"`
+	Page          int      `query:"page" minimum:"1" default:"1" doc:"Page number"`
+	Limit         int      `query:"limit" minimum:"1" maximum:"500" default:"100" doc:"Items per page"`
+	Authorization string   `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string   `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// EntityTagsInput represents the input for listing the tags attached to an entity
+type EntityTagsInput struct {
+	EntityType    string `path:"entity_type" enum:"character,corporation,killmail,fitting" doc:"Kind of entity"`
+	EntityID      string `path:"entity_id" doc:"Entity ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
@@ -0,0 +1,147 @@
+package dto
+
+import (
+	"time"
+
+	"go-falcon/internal/tags/models"
+)
+
+// NamespaceResponse is a tag namespace with the permissions guarding it
+type NamespaceResponse struct {
+	Name             string    `json:"name"`
+	Description      string    `json:"description,omitempty"`
+	ViewPermission   string    `json:"view_permission" doc:"Permission to see the namespace's tags and tagged entities"`
+	ManagePermission string    `json:"manage_permission" doc:"Permission to create tags and attach or detach them"`
+	CreatedBy        int       `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// NamespaceOutput represents the output for a single namespace
+type NamespaceOutput struct {
+	Body NamespaceResponse `json:"body"`
+}
+
+// NamespaceListOutput represents the output for listing namespaces
+type NamespaceListOutput struct {
+	Body struct {
+		Namespaces []NamespaceResponse `json:"namespaces"`
+	} `json:"body"`
+}
+
+// TagResponse is a tag with the number of entities carrying it
+type TagResponse struct {
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Color       string    `json:"color,omitempty"`
+	EntityCount int64     `json:"entity_count" doc:"Entities the tag is attached to"`
+	CreatedBy   int       `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TagOutput represents the output for a single tag
+type TagOutput struct {
+	Body TagResponse `json:"body"`
+}
+
+// TagListOutput represents the output for listing the tags of a namespace
+type TagListOutput struct {
+	Body struct {
+		Namespace string        `json:"namespace"`
+		Tags      []TagResponse `json:"tags"`
+	} `json:"body"`
+}
+
+// AttachResponse reports the result of attaching a tag
+type AttachResponse struct {
+	Created bool   `json:"created" doc:"False when the tag was already attached and only its note was updated"`
+	Message string `json:"message"`
+}
+
+// AttachOutput represents the output for attaching a tag
+type AttachOutput struct {
+	Body AttachResponse `json:"body"`
+}
+
+// MessageOutput represents a plain confirmation message
+type MessageOutput struct {
+	Body struct {
+		Message string `json:"message"`
+	} `json:"body"`
+}
+
+// TaggedEntityResponse is an entity with the requested tags it carries
+type TaggedEntityResponse struct {
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Tags       []string  `json:"tags" doc:"Requested tags attached to the entity"`
+	LastTagged time.Time `json:"last_tagged" doc:"When the most recent of these tags was attached"`
+}
+
+// TaggedEntityListOutput represents a paginated list of tagged entities
+type TaggedEntityListOutput struct {
+	Body struct {
+		Namespace string                 `json:"namespace"`
+		Entities  []TaggedEntityResponse `json:"entities"`
+		Total     int64                  `json:"total"`
+		Page      int                    `json:"page"`
+		Limit     int                    `json:"limit"`
+	} `json:"body"`
+}
+
+// EntityTagResponse is a tag attached to an entity
+type EntityTagResponse struct {
+	Namespace  string    `json:"namespace"`
+	Tag        string    `json:"tag"`
+	Note       string    `json:"note,omitempty"`
+	AssignedBy int       `json:"assigned_by" doc:"Character ID that attached the tag"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// EntityTagsOutput represents the tags attached to an entity
+type EntityTagsOutput struct {
+	Body struct {
+		EntityType string              `json:"entity_type"`
+		EntityID   string              `json:"entity_id"`
+		Tags       []EntityTagResponse `json:"tags" doc:"Tags from namespaces the caller can view"`
+	} `json:"body"`
+}
+
+// TagsStatusResponse represents the module status response
+type TagsStatusResponse struct {
+	Module  string `json:"module" description:"Module name"`
+	Status  string `json:"status" enum:"healthy,unhealthy" description:"Module health status"`
+	Message string `json:"message,omitempty" description:"Optional status message or error details"`
+}
+
+// StatusOutput represents the module status output
+type StatusOutput struct {
+	Body TagsStatusResponse `json:"body"`
+}
+
+// ToNamespaceResponse converts a namespace model into its response form
+func ToNamespaceResponse(namespace *models.Namespace) NamespaceResponse {
+	return NamespaceResponse{
+		Name:             namespace.Name,
+		Description:      namespace.Description,
+		ViewPermission:   models.ViewPermission(namespace.Name),
+		ManagePermission: models.ManagePermission(namespace.Name),
+		CreatedBy:        namespace.CreatedBy,
+		CreatedAt:        namespace.CreatedAt,
+	}
+}
+
+// ToTagResponse converts a tag model into its response form
+func ToTagResponse(tag *models.Tag, entityCount int64) TagResponse {
+	return TagResponse{
+		Namespace:   tag.Namespace,
+		Name:        tag.Name,
+		Description: tag.Description,
+		Color:       tag.Color,
+		EntityCount: entityCount,
+		CreatedBy:   tag.CreatedBy,
+		CreatedAt:   tag.CreatedAt,
+		UpdatedAt:   tag.UpdatedAt,
+	}
+}
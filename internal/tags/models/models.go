@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	NamespacesCollection  = "tag_namespaces"
+	TagsCollection        = "tags"
+	AssignmentsCollection = "tag_assignments"
+)
+
+// AdminPermission allows creating and deleting namespaces and managing tags in every namespace
+const AdminPermission = "tags:namespaces:manage"
+
+// EntityType identifies the kind of entity a tag is attached to
+type EntityType string
+
+const (
+	EntityCharacter   EntityType = "character"
+	EntityCorporation EntityType = "corporation"
+	EntityKillmail    EntityType = "killmail"
+	EntityFitting     EntityType = "fitting"
+)
+
+// Namespace groups tags owned by one workflow (intel, recruitment, SRP, ...). Each namespace has
+// its own view and manage permissions, registered when the namespace is created.
+type Namespace struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	CreatedBy   int                `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ViewPermission returns the permission to see the tags of a namespace and what they are attached to
+func ViewPermission(namespace string) string {
+	return "tags:" + namespace + ":view"
+}
+
+// ManagePermission returns the permission to create tags in a namespace and attach or detach them
+func ManagePermission(namespace string) string {
+	return "tags:" + namespace + ":manage"
+}
+
+// Tag is a label within a namespace. Names are immutable because assignments carry them.
+type Tag struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Namespace   string             `bson:"namespace" json:"namespace"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Color       string             `bson:"color,omitempty" json:"color,omitempty"`
+	CreatedBy   int                `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Assignment attaches a tag to an entity. EntityID is the decimal EVE ID for characters,
+// corporations and killmails and the hex ObjectID for fittings.
+type Assignment struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TagID      primitive.ObjectID `bson:"tag_id" json:"tag_id"`
+	Namespace  string             `bson:"namespace" json:"namespace"`
+	Tag        string             `bson:"tag" json:"tag"`
+	EntityType EntityType         `bson:"entity_type" json:"entity_type"`
+	EntityID   string             `bson:"entity_id" json:"entity_id"`
+	Note       string             `bson:"note,omitempty" json:"note,omitempty"`
+	AssignedBy int                `bson:"assigned_by" json:"assigned_by"`
+	AssignedAt time.Time          `bson:"assigned_at" json:"assigned_at"`
+}
+
+// TaggedEntity is an entity together with the matching tags attached to it
+type TaggedEntity struct {
+	EntityType EntityType `bson:"entity_type"`
+	EntityID   string     `bson:"entity_id"`
+	Tags       []string   `bson:"tags"`
+	LastTagged time.Time  `bson:"last_tagged"`
+}
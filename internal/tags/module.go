@@ -0,0 +1,83 @@
+package tags
+
+import (
+	"context"
+	"time"
+
+	"go-falcon/internal/tags/models"
+	"go-falcon/internal/tags/routes"
+	"go-falcon/internal/tags/services"
+	"go-falcon/pkg/database"
+	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/module"
+	"go-falcon/pkg/permissions"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/go-chi/chi/v5"
+)
+
+// Module represents the tags module
+type Module struct {
+	*module.BaseModule
+	service              *services.Service
+	repository           *services.Repository
+	permissionMiddleware *middleware.PermissionMiddleware
+}
+
+// New creates a new tags module instance
+func New(mongodb *database.MongoDB, redis *database.Redis, permissionMiddleware *middleware.PermissionMiddleware) *Module {
+	repository := services.NewRepository(mongodb)
+	service := services.NewService(repository)
+
+	return &Module{
+		BaseModule:           module.NewBaseModule("tags", mongodb, redis),
+		service:              service,
+		repository:           repository,
+		permissionMiddleware: permissionMiddleware,
+	}
+}
+
+// RegisterUnifiedRoutes registers all tags routes with the unified API gateway
+func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string) {
+	routes.RegisterTagsRoutes(api, basePath, m.service, m.permissionMiddleware)
+}
+
+// Routes registers routes on a Chi router (implements module.Module interface)
+func (m *Module) Routes(r chi.Router) {
+	// Tags module uses only Huma v2 unified routes
+}
+
+// Initialize performs module initialization tasks
+func (m *Module) Initialize(ctx context.Context) error {
+	return m.repository.CreateIndexes(ctx)
+}
+
+// RegisterPermissions registers the namespace administration permission and the view and
+// manage permissions of every existing namespace. Namespaces created later register their own.
+func (m *Module) RegisterPermissions(ctx context.Context, permissionManager *permissions.PermissionManager) error {
+	m.service.SetPermissionManager(permissionManager)
+
+	tagPermissions := []permissions.Permission{
+		{
+			ID:          models.AdminPermission,
+			Service:     "tags",
+			Resource:    "namespaces",
+			Action:      "manage",
+			IsStatic:    false,
+			Name:        "Manage Tag Namespaces",
+			Description: "Create and delete tag namespaces and manage the tags of every namespace",
+			Category:    "Tags",
+			CreatedAt:   time.Now(),
+		},
+	}
+	if err := permissionManager.RegisterServicePermissions(ctx, tagPermissions); err != nil {
+		return err
+	}
+
+	return m.service.RegisterNamespacePermissions(ctx)
+}
+
+// GetService returns the service instance for this module
+func (m *Module) GetService() *services.Service {
+	return m.service
+}
@@ -0,0 +1,228 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+
+	"go-falcon/internal/tags/dto"
+	"go-falcon/internal/tags/models"
+	"go-falcon/internal/tags/services"
+	"go-falcon/pkg/apidocs"
+	"go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// viewPermissions are the permissions any of which allows reading a namespace
+func viewPermissions(namespace string) []string {
+	return []string{models.ViewPermission(namespace), models.ManagePermission(namespace), models.AdminPermission}
+}
+
+// managePermissions are the permissions any of which allows changing a namespace's tags
+func managePermissions(namespace string) []string {
+	return []string{models.ManagePermission(namespace), models.AdminPermission}
+}
+
+// RegisterTagsRoutes registers tags routes on a shared Huma API
+func RegisterTagsRoutes(api huma.API, basePath string, service *services.Service, permissionMiddleware *middleware.PermissionMiddleware) {
+	// Status endpoint (public, no auth required)
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-get-status",
+		Method:      http.MethodGet,
+		Path:        basePath + "/status",
+		Summary:     "Get tags module status",
+		Description: "Returns the health status of the tags module",
+		Tags:        []string{"Module Status"},
+	}, func(ctx context.Context, input *struct{}) (*dto.StatusOutput, error) {
+		return &dto.StatusOutput{Body: *service.GetStatus(ctx)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-list-namespaces",
+		Method:      http.MethodGet,
+		Path:        basePath + "/namespaces",
+		Summary:     "List tag namespaces",
+		Description: "Lists the tag namespaces the caller can view, with the permissions guarding each",
+		Tags:        []string{"Tags / Namespaces"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ListNamespacesInput) (*dto.NamespaceListOutput, error) {
+		if _, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+
+		namespaces, err := service.ListNamespaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		output := &dto.NamespaceListOutput{}
+		output.Body.Namespaces = []dto.NamespaceResponse{}
+		for i := range namespaces {
+			if _, err := permissionMiddleware.RequireAnyPermission(ctx, input.Authorization, input.Cookie, viewPermissions(namespaces[i].Name)); err != nil {
+				continue
+			}
+			output.Body.Namespaces = append(output.Body.Namespaces, dto.ToNamespaceResponse(&namespaces[i]))
+		}
+		return output, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "tags-create-namespace",
+		Method:        http.MethodPost,
+		Path:          basePath + "/namespaces",
+		Summary:       "Create tag namespace",
+		Description:   "Creates a tag namespace and registers its view and manage permissions, which can then be granted to groups",
+		Tags:          []string{"Tags / Namespaces"},
+		DefaultStatus: http.StatusCreated,
+		Extensions:    apidocs.RequiresPermission(models.AdminPermission),
+		Security:      []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.CreateNamespaceInput) (*dto.NamespaceOutput, error) {
+		user, err := permissionMiddleware.RequirePermission(ctx, input.Authorization, input.Cookie, models.AdminPermission)
+		if err != nil {
+			return nil, err
+		}
+		return service.CreateNamespace(ctx, input, user.CharacterID)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-delete-namespace",
+		Method:      http.MethodDelete,
+		Path:        basePath + "/namespaces/{namespace}",
+		Summary:     "Delete tag namespace",
+		Description: "Deletes a namespace together with all its tags and their assignments",
+		Tags:        []string{"Tags / Namespaces"},
+		Extensions:  apidocs.RequiresPermission(models.AdminPermission),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.NamespaceInput) (*dto.MessageOutput, error) {
+		if _, err := permissionMiddleware.RequirePermission(ctx, input.Authorization, input.Cookie, models.AdminPermission); err != nil {
+			return nil, err
+		}
+		return service.DeleteNamespace(ctx, input.Namespace)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-list-tags",
+		Method:      http.MethodGet,
+		Path:        basePath + "/namespaces/{namespace}/tags",
+		Summary:     "List tags",
+		Description: "Lists the tags of a namespace with the number of entities carrying each (requires the namespace's view or manage permission)",
+		Tags:        []string{"Tags / Management"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.NamespaceInput) (*dto.TagListOutput, error) {
+		if _, err := permissionMiddleware.RequireAnyPermission(ctx, input.Authorization, input.Cookie, viewPermissions(input.Namespace)); err != nil {
+			return nil, err
+		}
+		return service.ListTags(ctx, input.Namespace)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "tags-create-tag",
+		Method:        http.MethodPost,
+		Path:          basePath + "/namespaces/{namespace}/tags",
+		Summary:       "Create tag",
+		Description:   "Creates a tag in a namespace (requires the namespace's manage permission)",
+		Tags:          []string{"Tags / Management"},
+		DefaultStatus: http.StatusCreated,
+		Security:      []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.CreateTagInput) (*dto.TagOutput, error) {
+		user, err := permissionMiddleware.RequireAnyPermission(ctx, input.Authorization, input.Cookie, managePermissions(input.Namespace))
+		if err != nil {
+			return nil, err
+		}
+		return service.CreateTag(ctx, input, user.CharacterID)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-update-tag",
+		Method:      http.MethodPut,
+		Path:        basePath + "/namespaces/{namespace}/tags/{tag}",
+		Summary:     "Update tag",
+		Description: "Changes the description or color of a tag; tag names cannot be changed (requires the namespace's manage permission)",
+		Tags:        []string{"Tags / Management"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UpdateTagInput) (*dto.TagOutput, error) {
+		if _, err := permissionMiddleware.RequireAnyPermission(ctx, input.Authorization, input.Cookie, managePermissions(input.Namespace)); err != nil {
+			return nil, err
+		}
+		return service.UpdateTag(ctx, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-delete-tag",
+		Method:      http.MethodDelete,
+		Path:        basePath + "/namespaces/{namespace}/tags/{tag}",
+		Summary:     "Delete tag",
+		Description: "Deletes a tag and detaches it from every entity (requires the namespace's manage permission)",
+		Tags:        []string{"Tags / Management"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.TagInput) (*dto.MessageOutput, error) {
+		if _, err := permissionMiddleware.RequireAnyPermission(ctx, input.Authorization, input.Cookie, managePermissions(input.Namespace)); err != nil {
+			return nil, err
+		}
+		return service.DeleteTag(ctx, input.Namespace, input.Tag)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-attach-tag",
+		Method:      http.MethodPost,
+		Path:        basePath + "/namespaces/{namespace}/tags/{tag}/entities",
+		Summary:     "Attach tag",
+		Description: "Attaches a tag to a character, corporation, killmail or fitting; attaching it again replaces the note (requires the namespace's manage permission)",
+		Tags:        []string{"Tags / Assignments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.AttachTagInput) (*dto.AttachOutput, error) {
+		user, err := permissionMiddleware.RequireAnyPermission(ctx, input.Authorization, input.Cookie, managePermissions(input.Namespace))
+		if err != nil {
+			return nil, err
+		}
+		return service.Attach(ctx, input, user.CharacterID)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-detach-tag",
+		Method:      http.MethodDelete,
+		Path:        basePath + "/namespaces/{namespace}/tags/{tag}/entities/{entity_type}/{entity_id}",
+		Summary:     "Detach tag",
+		Description: "Removes a tag from an entity (requires the namespace's manage permission)",
+		Tags:        []string{"Tags / Assignments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DetachTagInput) (*dto.MessageOutput, error) {
+		if _, err := permissionMiddleware.RequireAnyPermission(ctx, input.Authorization, input.Cookie, managePermissions(input.Namespace)); err != nil {
+			return nil, err
+		}
+		return service.Detach(ctx, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-find-tagged-entities",
+		Method:      http.MethodGet,
+		Path:        basePath + "/namespaces/{namespace}/entities",
+		Summary:     "Find tagged entities",
+		Description: "Lists entities carrying any or all of the given tags, optionally of one kind, most recently tagged first; e.g. ?tags=spy-suspect&entity_type=character (requires the namespace's view or manage permission)",
+		Tags:        []string{"Tags / Assignments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.FindTaggedEntitiesInput) (*dto.TaggedEntityListOutput, error) {
+		if _, err := permissionMiddleware.RequireAnyPermission(ctx, input.Authorization, input.Cookie, viewPermissions(input.Namespace)); err != nil {
+			return nil, err
+		}
+		return service.FindTaggedEntities(ctx, input)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tags-get-entity-tags",
+		Method:      http.MethodGet,
+		Path:        basePath + "/entities/{entity_type}/{entity_id}",
+		Summary:     "Get entity tags",
+		Description: "Lists the tags attached to an entity, limited to namespaces the caller can view",
+		Tags:        []string{"Tags / Assignments"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.EntityTagsInput) (*dto.EntityTagsOutput, error) {
+		if _, err := permissionMiddleware.RequireAuth(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+		return service.GetEntityTags(ctx, input, func(namespace string) bool {
+			_, err := permissionMiddleware.RequireAnyPermission(ctx, input.Authorization, input.Cookie, viewPermissions(namespace))
+			return err == nil
+		})
+	})
+}
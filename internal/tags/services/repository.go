@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go-falcon/internal/tags/models"
+	"go-falcon/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	db          *database.MongoDB
+	namespaces  *mongo.Collection
+	tags        *mongo.Collection
+	assignments *mongo.Collection
+}
+
+func NewRepository(db *database.MongoDB) *Repository {
+	return &Repository{
+		db:          db,
+		namespaces:  db.Database.Collection(models.NamespacesCollection),
+		tags:        db.Database.Collection(models.TagsCollection),
+		assignments: db.Database.Collection(models.AssignmentsCollection),
+	}
+}
+
+// CreateNamespace inserts a namespace; a duplicate name returns a duplicate key error
+func (r *Repository) CreateNamespace(ctx context.Context, namespace *models.Namespace) error {
+	namespace.ID = primitive.NewObjectID()
+	_, err := r.namespaces.InsertOne(ctx, namespace)
+	return err
+}
+
+// GetNamespace retrieves a namespace by name
+func (r *Repository) GetNamespace(ctx context.Context, name string) (*models.Namespace, error) {
+	var namespace models.Namespace
+	err := r.namespaces.FindOne(ctx, bson.M{"name": name}).Decode(&namespace)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &namespace, nil
+}
+
+// ListNamespaces returns all namespaces ordered by name
+func (r *Repository) ListNamespaces(ctx context.Context) ([]models.Namespace, error) {
+	cursor, err := r.namespaces.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	namespaces := []models.Namespace{}
+	if err := cursor.All(ctx, &namespaces); err != nil {
+		return nil, err
+	}
+	return namespaces, nil
+}
+
+// DeleteNamespace removes a namespace with its tags and assignments
+func (r *Repository) DeleteNamespace(ctx context.Context, name string) (bool, error) {
+	result, err := r.namespaces.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return false, err
+	}
+	if result.DeletedCount == 0 {
+		return false, nil
+	}
+
+	if _, err := r.assignments.DeleteMany(ctx, bson.M{"namespace": name}); err != nil {
+		return true, err
+	}
+	_, err = r.tags.DeleteMany(ctx, bson.M{"namespace": name})
+	return true, err
+}
+
+// CreateTag inserts a tag; a duplicate name in the namespace returns a duplicate key error
+func (r *Repository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	tag.ID = primitive.NewObjectID()
+	_, err := r.tags.InsertOne(ctx, tag)
+	return err
+}
+
+// GetTag retrieves a tag by namespace and name
+func (r *Repository) GetTag(ctx context.Context, namespace, name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.tags.FindOne(ctx, bson.M{"namespace": namespace, "name": name}).Decode(&tag)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// ListTags returns the tags of a namespace ordered by name
+func (r *Repository) ListTags(ctx context.Context, namespace string) ([]models.Tag, error) {
+	cursor, err := r.tags.Find(ctx, bson.M{"namespace": namespace}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tags := []models.Tag{}
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// CountAssignmentsByTag returns how many entities carry each tag of a namespace
+func (r *Repository) CountAssignmentsByTag(ctx context.Context, namespace string) (map[string]int64, error) {
+	cursor, err := r.assignments.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"namespace": namespace}}},
+		{{Key: "$group", Value: bson.M{"_id": "$tag", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Tag   string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Tag] = row.Count
+	}
+	return counts, nil
+}
+
+// UpdateTag applies field updates to a tag and returns the updated tag
+func (r *Repository) UpdateTag(ctx context.Context, namespace, name string, update bson.M) (*models.Tag, error) {
+	update["updated_at"] = time.Now()
+
+	var tag models.Tag
+	err := r.tags.FindOneAndUpdate(ctx,
+		bson.M{"namespace": namespace, "name": name},
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&tag)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// DeleteTag removes a tag and detaches it from every entity, returning how many were detached
+func (r *Repository) DeleteTag(ctx context.Context, tag *models.Tag) (int64, error) {
+	if _, err := r.tags.DeleteOne(ctx, bson.M{"_id": tag.ID}); err != nil {
+		return 0, err
+	}
+	result, err := r.assignments.DeleteMany(ctx, bson.M{"tag_id": tag.ID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// Attach attaches a tag to an entity, updating the note when it is already attached.
+// It reports whether the assignment is new.
+func (r *Repository) Attach(ctx context.Context, assignment *models.Assignment) (bool, error) {
+	filter := bson.M{
+		"tag_id":      assignment.TagID,
+		"entity_type": assignment.EntityType,
+		"entity_id":   assignment.EntityID,
+	}
+	update := bson.M{
+		"$set": bson.M{"note": assignment.Note},
+		"$setOnInsert": bson.M{
+			"namespace":   assignment.Namespace,
+			"tag":         assignment.Tag,
+			"assigned_by": assignment.AssignedBy,
+			"assigned_at": assignment.AssignedAt,
+		},
+	}
+
+	result, err := r.assignments.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// Detach removes a tag from an entity and reports whether it was attached
+func (r *Repository) Detach(ctx context.Context, tagID primitive.ObjectID, entityType models.EntityType, entityID string) (bool, error) {
+	result, err := r.assignments.DeleteOne(ctx, bson.M{
+		"tag_id":      tagID,
+		"entity_type": entityType,
+		"entity_id":   entityID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// FindTaggedEntities returns a page of entities in a namespace carrying any (or all, with
+// matchAll) of the given tags, most recently tagged first, and the total number of matches
+func (r *Repository) FindTaggedEntities(ctx context.Context, namespace string, tags []string, entityType models.EntityType, matchAll bool, page, limit int) ([]models.TaggedEntity, int64, error) {
+	match := bson.M{"namespace": namespace, "tag": bson.M{"$in": tags}}
+	if entityType != "" {
+		match["entity_type"] = entityType
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"entity_type": "$entity_type", "entity_id": "$entity_id"},
+			"tags":        bson.M{"$addToSet": "$tag"},
+			"last_tagged": bson.M{"$max": "$assigned_at"},
+		}}},
+	}
+	if matchAll {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"tags": bson.M{"$size": len(tags)}}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"entity_type": "$_id.entity_type",
+			"entity_id":   "$_id.entity_id",
+			"tags":        1,
+			"last_tagged": 1,
+		}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"entities": bson.A{
+				bson.M{"$sort": bson.D{{Key: "last_tagged", Value: -1}, {Key: "entity_id", Value: 1}}},
+				bson.M{"$skip": (page - 1) * limit},
+				bson.M{"$limit": limit},
+			},
+			"total": bson.A{bson.M{"$count": "count"}},
+		}}},
+	)
+
+	cursor, err := r.assignments.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Entities []models.TaggedEntity `bson:"entities"`
+		Total    []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+	if len(results) == 0 || len(results[0].Total) == 0 {
+		return []models.TaggedEntity{}, 0, nil
+	}
+	return results[0].Entities, results[0].Total[0].Count, nil
+}
+
+// GetEntityAssignments returns every tag attached to an entity, ordered by namespace and tag
+func (r *Repository) GetEntityAssignments(ctx context.Context, entityType models.EntityType, entityID string) ([]models.Assignment, error) {
+	cursor, err := r.assignments.Find(ctx,
+		bson.M{"entity_type": entityType, "entity_id": entityID},
+		options.Find().SetSort(bson.D{{Key: "namespace", Value: 1}, {Key: "tag", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	assignments := []models.Assignment{}
+	if err := cursor.All(ctx, &assignments); err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// CheckHealth verifies database connectivity
+func (r *Repository) CheckHealth(ctx context.Context) error {
+	return r.db.HealthCheck(ctx)
+}
+
+// CreateIndexes creates the indexes for namespaces, tags and assignments
+func (r *Repository) CreateIndexes(ctx context.Context) error {
+	if _, err := r.namespaces.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	if _, err := r.tags.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "namespace", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	_, err := r.assignments.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tag_id", Value: 1}, {Key: "entity_type", Value: 1}, {Key: "entity_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "namespace", Value: 1}, {Key: "tag", Value: 1}, {Key: "entity_type", Value: 1}}},
+		{Keys: bson.D{{Key: "entity_type", Value: 1}, {Key: "entity_id", Value: 1}}},
+	})
+	return err
+}
@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-falcon/internal/tags/dto"
+	"go-falcon/internal/tags/models"
+	"go-falcon/pkg/permissions"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxTaggedEntityIDs caps TaggedEntityIDs; workflows needing more should page through the API
+const maxTaggedEntityIDs = 10000
+
+// reservedNamespaces cannot be created because their permission IDs are taken by the module
+var reservedNamespaces = map[string]bool{"namespaces": true}
+
+type Service struct {
+	repository        *Repository
+	permissionManager *permissions.PermissionManager
+}
+
+func NewService(repository *Repository) *Service {
+	return &Service{repository: repository}
+}
+
+// SetPermissionManager sets where namespace permissions are registered
+func (s *Service) SetPermissionManager(permissionManager *permissions.PermissionManager) {
+	s.permissionManager = permissionManager
+}
+
+// ListNamespaces returns all namespaces
+func (s *Service) ListNamespaces(ctx context.Context) ([]models.Namespace, error) {
+	namespaces, err := s.repository.ListNamespaces(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list namespaces", err)
+	}
+	return namespaces, nil
+}
+
+// CreateNamespace creates a namespace and registers its view and manage permissions
+func (s *Service) CreateNamespace(ctx context.Context, input *dto.CreateNamespaceInput, createdBy int) (*dto.NamespaceOutput, error) {
+	if reservedNamespaces[input.Body.Name] {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("Namespace name %s is reserved", input.Body.Name))
+	}
+
+	now := time.Now()
+	namespace := &models.Namespace{
+		Name:        input.Body.Name,
+		Description: input.Body.Description,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repository.CreateNamespace(ctx, namespace); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, huma.Error409Conflict(fmt.Sprintf("Namespace %s already exists", namespace.Name))
+		}
+		return nil, huma.Error500InternalServerError("Failed to create namespace", err)
+	}
+
+	if err := s.registerPermissions(ctx, []models.Namespace{*namespace}); err != nil {
+		return nil, huma.Error500InternalServerError("Namespace created but its permissions could not be registered", err)
+	}
+
+	slog.Info("Created tag namespace", "namespace", namespace.Name, "created_by", createdBy)
+	return &dto.NamespaceOutput{Body: dto.ToNamespaceResponse(namespace)}, nil
+}
+
+// DeleteNamespace removes a namespace with all its tags and assignments. Its permissions stay
+// registered so existing group grants are not silently lost if the namespace is recreated.
+func (s *Service) DeleteNamespace(ctx context.Context, name string) (*dto.MessageOutput, error) {
+	deleted, err := s.repository.DeleteNamespace(ctx, name)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete namespace", err)
+	}
+	if !deleted {
+		return nil, huma.Error404NotFound("Namespace not found")
+	}
+
+	slog.Info("Deleted tag namespace", "namespace", name)
+	output := &dto.MessageOutput{}
+	output.Body.Message = fmt.Sprintf("Namespace %s deleted with its tags", name)
+	return output, nil
+}
+
+// RegisterNamespacePermissions registers the permissions of every stored namespace
+func (s *Service) RegisterNamespacePermissions(ctx context.Context) error {
+	namespaces, err := s.repository.ListNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	return s.registerPermissions(ctx, namespaces)
+}
+
+func (s *Service) registerPermissions(ctx context.Context, namespaces []models.Namespace) error {
+	if s.permissionManager == nil || len(namespaces) == 0 {
+		return nil
+	}
+
+	namespacePermissions := make([]permissions.Permission, 0, len(namespaces)*2)
+	for _, namespace := range namespaces {
+		namespacePermissions = append(namespacePermissions,
+			permissions.Permission{
+				ID:          models.ViewPermission(namespace.Name),
+				Service:     "tags",
+				Resource:    namespace.Name,
+				Action:      "view",
+				Name:        fmt.Sprintf("View %s Tags", namespace.Name),
+				Description: fmt.Sprintf("See the tags of the %s namespace and which entities carry them", namespace.Name),
+				Category:    "Tags",
+			},
+			permissions.Permission{
+				ID:          models.ManagePermission(namespace.Name),
+				Service:     "tags",
+				Resource:    namespace.Name,
+				Action:      "manage",
+				Name:        fmt.Sprintf("Manage %s Tags", namespace.Name),
+				Description: fmt.Sprintf("Create and edit tags of the %s namespace and attach them to entities", namespace.Name),
+				Category:    "Tags",
+			},
+		)
+	}
+	return s.permissionManager.RegisterServicePermissions(ctx, namespacePermissions)
+}
+
+// ListTags returns the tags of a namespace with their entity counts
+func (s *Service) ListTags(ctx context.Context, namespace string) (*dto.TagListOutput, error) {
+	if _, err := s.requireNamespace(ctx, namespace); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.repository.ListTags(ctx, namespace)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list tags", err)
+	}
+	counts, err := s.repository.CountAssignmentsByTag(ctx, namespace)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to count tagged entities", err)
+	}
+
+	output := &dto.TagListOutput{}
+	output.Body.Namespace = namespace
+	output.Body.Tags = make([]dto.TagResponse, 0, len(tags))
+	for i := range tags {
+		output.Body.Tags = append(output.Body.Tags, dto.ToTagResponse(&tags[i], counts[tags[i].Name]))
+	}
+	return output, nil
+}
+
+// CreateTag creates a tag in a namespace
+func (s *Service) CreateTag(ctx context.Context, input *dto.CreateTagInput, createdBy int) (*dto.TagOutput, error) {
+	if _, err := s.requireNamespace(ctx, input.Namespace); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tag := &models.Tag{
+		Namespace:   input.Namespace,
+		Name:        input.Body.Name,
+		Description: input.Body.Description,
+		Color:       input.Body.Color,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repository.CreateTag(ctx, tag); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, huma.Error409Conflict(fmt.Sprintf("Tag %s already exists in %s", tag.Name, tag.Namespace))
+		}
+		return nil, huma.Error500InternalServerError("Failed to create tag", err)
+	}
+
+	return &dto.TagOutput{Body: dto.ToTagResponse(tag, 0)}, nil
+}
+
+// UpdateTag changes the description or color of a tag
+func (s *Service) UpdateTag(ctx context.Context, input *dto.UpdateTagInput) (*dto.TagOutput, error) {
+	update := bson.M{}
+	if input.Body.Description != nil {
+		update["description"] = *input.Body.Description
+	}
+	if input.Body.Color != nil {
+		update["color"] = *input.Body.Color
+	}
+
+	tag, err := s.repository.UpdateTag(ctx, input.Namespace, input.Tag, update)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to update tag", err)
+	}
+	if tag == nil {
+		return nil, huma.Error404NotFound("Tag not found")
+	}
+
+	counts, err := s.repository.CountAssignmentsByTag(ctx, input.Namespace)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to count tagged entities", err)
+	}
+	return &dto.TagOutput{Body: dto.ToTagResponse(tag, counts[tag.Name])}, nil
+}
+
+// DeleteTag removes a tag and detaches it from every entity
+func (s *Service) DeleteTag(ctx context.Context, namespace, name string) (*dto.MessageOutput, error) {
+	tag, err := s.requireTag(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	detached, err := s.repository.DeleteTag(ctx, tag)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete tag", err)
+	}
+
+	output := &dto.MessageOutput{}
+	output.Body.Message = fmt.Sprintf("Tag %s deleted and detached from %d entities", name, detached)
+	return output, nil
+}
+
+// Attach attaches a tag to an entity
+func (s *Service) Attach(ctx context.Context, input *dto.AttachTagInput, assignedBy int) (*dto.AttachOutput, error) {
+	tag, err := s.requireTag(ctx, input.Namespace, input.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	entityType := models.EntityType(input.Body.EntityType)
+	entityID, err := normalizeEntityID(entityType, input.Body.EntityID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
+	created, err := s.repository.Attach(ctx, &models.Assignment{
+		TagID:      tag.ID,
+		Namespace:  tag.Namespace,
+		Tag:        tag.Name,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Note:       input.Body.Note,
+		AssignedBy: assignedBy,
+		AssignedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to attach tag", err)
+	}
+
+	message := fmt.Sprintf("Tagged %s %s as %s", entityType, entityID, tag.Name)
+	if !created {
+		message = fmt.Sprintf("%s %s was already tagged %s; note updated", entityType, entityID, tag.Name)
+	}
+	return &dto.AttachOutput{Body: dto.AttachResponse{Created: created, Message: message}}, nil
+}
+
+// Detach removes a tag from an entity
+func (s *Service) Detach(ctx context.Context, input *dto.DetachTagInput) (*dto.MessageOutput, error) {
+	tag, err := s.requireTag(ctx, input.Namespace, input.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	entityType := models.EntityType(input.EntityType)
+	entityID, err := normalizeEntityID(entityType, input.EntityID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
+	detached, err := s.repository.Detach(ctx, tag.ID, entityType, entityID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to detach tag", err)
+	}
+	if !detached {
+		return nil, huma.Error404NotFound(fmt.Sprintf("%s %s is not tagged %s", entityType, entityID, tag.Name))
+	}
+
+	output := &dto.MessageOutput{}
+	output.Body.Message = fmt.Sprintf("Removed tag %s from %s %s", tag.Name, entityType, entityID)
+	return output, nil
+}
+
+// FindTaggedEntities lists the entities of a namespace carrying any or all of the given tags
+func (s *Service) FindTaggedEntities(ctx context.Context, input *dto.FindTaggedEntitiesInput) (*dto.TaggedEntityListOutput, error) {
+	if _, err := s.requireNamespace(ctx, input.Namespace); err != nil {
+		return nil, err
+	}
+
+	entities, total, err := s.repository.FindTaggedEntities(ctx, input.Namespace, normalizeTags(input.Tags),
+		models.EntityType(input.EntityType), input.Match == "all", input.Page, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to find tagged entities", err)
+	}
+
+	output := &dto.TaggedEntityListOutput{}
+	output.Body.Namespace = input.Namespace
+	output.Body.Total = total
+	output.Body.Page = input.Page
+	output.Body.Limit = input.Limit
+	output.Body.Entities = make([]dto.TaggedEntityResponse, 0, len(entities))
+	for _, entity := range entities {
+		output.Body.Entities = append(output.Body.Entities, dto.TaggedEntityResponse{
+			EntityType: string(entity.EntityType),
+			EntityID:   entity.EntityID,
+			Tags:       entity.Tags,
+			LastTagged: entity.LastTagged,
+		})
+	}
+	return output, nil
+}
+
+// GetEntityTags returns the tags attached to an entity in the namespaces canView allows
+func (s *Service) GetEntityTags(ctx context.Context, input *dto.EntityTagsInput, canView func(namespace string) bool) (*dto.EntityTagsOutput, error) {
+	entityType := models.EntityType(input.EntityType)
+	entityID, err := normalizeEntityID(entityType, input.EntityID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
+	assignments, err := s.repository.GetEntityAssignments(ctx, entityType, entityID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get entity tags", err)
+	}
+
+	output := &dto.EntityTagsOutput{}
+	output.Body.EntityType = string(entityType)
+	output.Body.EntityID = entityID
+	output.Body.Tags = []dto.EntityTagResponse{}
+
+	visible := make(map[string]bool)
+	for _, assignment := range assignments {
+		allowed, checked := visible[assignment.Namespace]
+		if !checked {
+			allowed = canView(assignment.Namespace)
+			visible[assignment.Namespace] = allowed
+		}
+		if !allowed {
+			continue
+		}
+		output.Body.Tags = append(output.Body.Tags, dto.EntityTagResponse{
+			Namespace:  assignment.Namespace,
+			Tag:        assignment.Tag,
+			Note:       assignment.Note,
+			AssignedBy: assignment.AssignedBy,
+			AssignedAt: assignment.AssignedAt,
+		})
+	}
+	return output, nil
+}
+
+// TaggedEntityIDs returns the IDs of entities of a type carrying any (or all, with matchAll) of
+// the given tags. Other modules use it to filter their own listings by tag.
+func (s *Service) TaggedEntityIDs(ctx context.Context, namespace string, tags []string, entityType models.EntityType, matchAll bool) ([]string, error) {
+	entities, _, err := s.repository.FindTaggedEntities(ctx, namespace, normalizeTags(tags), entityType, matchAll, 1, maxTaggedEntityIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(entities))
+	for i, entity := range entities {
+		ids[i] = entity.EntityID
+	}
+	return ids, nil
+}
+
+// GetStatus returns the module status
+func (s *Service) GetStatus(ctx context.Context) *dto.TagsStatusResponse {
+	if err := s.repository.CheckHealth(ctx); err != nil {
+		return &dto.TagsStatusResponse{
+			Module:  "tags",
+			Status:  "unhealthy",
+			Message: "Database connection failed: " + err.Error(),
+		}
+	}
+	return &dto.TagsStatusResponse{Module: "tags", Status: "healthy"}
+}
+
+func (s *Service) requireNamespace(ctx context.Context, name string) (*models.Namespace, error) {
+	namespace, err := s.repository.GetNamespace(ctx, name)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get namespace", err)
+	}
+	if namespace == nil {
+		return nil, huma.Error404NotFound("Namespace not found")
+	}
+	return namespace, nil
+}
+
+func (s *Service) requireTag(ctx context.Context, namespace, name string) (*models.Tag, error) {
+	tag, err := s.repository.GetTag(ctx, namespace, name)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to get tag", err)
+	}
+	if tag == nil {
+		return nil, huma.Error404NotFound("Tag not found")
+	}
+	return tag, nil
+}
+
+// normalizeEntityID validates an entity ID and returns its canonical form
+func normalizeEntityID(entityType models.EntityType, entityID string) (string, error) {
+	switch entityType {
+	case models.EntityCharacter, models.EntityCorporation, models.EntityKillmail:
+		id, err := strconv.ParseInt(strings.TrimSpace(entityID), 10, 64)
+		if err != nil || id <= 0 {
+			return "", fmt.Errorf("%s ID must be a positive integer", entityType)
+		}
+		return strconv.FormatInt(id, 10), nil
+	case models.EntityFitting:
+		id, err := primitive.ObjectIDFromHex(strings.TrimSpace(entityID))
+		if err != nil {
+			return "", fmt.Errorf("fitting ID must be a fitting's hex ID")
+		}
+		return id.Hex(), nil
+	}
+	return "", fmt.Errorf("unsupported entity type %q", entityType)
+}
+
+// normalizeTags lowercases, trims and de-duplicates tag names so "all" matching counts each once
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -50,6 +52,13 @@ type UserDeleteInput struct {
 	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
 }
 
+// UserRestoreInput represents the input for restoring a soft-deleted user character
+type UserRestoreInput struct {
+	CharacterID   int    `path:"character_id" minimum:"90000000" maximum:"2147483647" doc:"EVE Online character ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
 // UserListInput represents the input for listing users with pagination and filtering
 type UserListInput struct {
 	Page          int    `query:"page" minimum:"1" default:"1" doc:"Page number"`
@@ -57,6 +66,8 @@ type UserListInput struct {
 	Query         string `query:"query" doc:"Search by character name or ID"`
 	Banned        string `query:"banned" doc:"Filter by banned status (true/false)"`
 	Position      int    `query:"position" doc:"Filter by position value (0 means no filter)"`
+	Tag           string `query:"tag" doc:"Filter by administrative tag"`
+	Deleted       string `query:"deleted" doc:"List soft-deleted users instead of active ones (true), or both (all)"`
 	SortBy        string `query:"sort_by" enum:"character_name,created_at,last_login,position" default:"created_at" doc:"Sort field"`
 	SortOrder     string `query:"sort_order" enum:"asc,desc" default:"desc" doc:"Sort order"`
 	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
@@ -87,3 +98,212 @@ type UserReorderCharactersInput struct {
 	Authorization string                       `header:"Authorization" doc:"Bearer token for authentication"`
 	Cookie        string                       `header:"Cookie" doc:"Authentication cookie"`
 }
+
+// UsageInput represents the input for the current user's API usage report
+type UsageInput struct {
+	Hours         int    `query:"hours" minimum:"1" maximum:"168" default:"24" doc:"Number of hourly buckets to report"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// UsageLeaderboardInput represents the input for the API usage leaderboard
+type UsageLeaderboardInput struct {
+	Hours         int    `query:"hours" minimum:"1" maximum:"168" default:"24" doc:"Time window in hours"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of consumers to return"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// InactivityReportInput represents the input for the inactive user report
+type InactivityReportInput struct {
+	Stage         string `query:"stage" enum:"flagged,notified,disabled" doc:"Only report users in this stage"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// InactivityExemptionsInput represents the input for listing inactivity exemptions
+type InactivityExemptionsInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// InactivityExemptionRequest represents the request body for exempting a user from the inactivity policy
+type InactivityExemptionRequest struct {
+	Reason    string     `json:"reason" minLength:"1" maxLength:"500" doc:"Why the user is exempt, e.g. extended leave"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" doc:"When the exemption ends; omit for a permanent exemption"`
+}
+
+// CreateInactivityExemptionInput represents the input for exempting a user
+type CreateInactivityExemptionInput struct {
+	UserID        string                     `path:"user_id" doc:"User UUID"`
+	Body          InactivityExemptionRequest `json:"body"`
+	Authorization string                     `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string                     `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// DeleteInactivityExemptionInput represents the input for removing a user's exemption
+type DeleteInactivityExemptionInput struct {
+	UserID        string `path:"user_id" doc:"User UUID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// MyAccountDeletionInput represents the input for requesting, viewing or cancelling the deletion of the caller's account
+type MyAccountDeletionInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// UserTagInput represents the input for adding or removing a tag of a user's character
+type UserTagInput struct {
+	CharacterID   int    `path:"character_id" minimum:"1" doc:"Character ID"`
+	Tag           string `path:"tag" minLength:"1" maxLength:"64" doc:"Tag, e.g. fc or alt-of:123; stored lowercase"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// UserTagListInput represents the input for listing the tags in use
+type UserTagListInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// UserAuditListInput represents the input for listing the users audit log
+type UserAuditListInput struct {
+	CharacterID   int    `query:"character_id" doc:"Only list events about this character"`
+	Action        string `query:"action" enum:"tag_added,tag_removed,user_deleted,user_restored,user_purged" doc:"Only list events of this action"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"500" default:"100" doc:"Maximum number of events to return, newest first"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// MyPreferencesInput represents the input for reading the caller's preferences
+type MyPreferencesInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// UpdatePreferencesRequest represents the request body for updating the caller's preferences
+type UpdatePreferencesRequest struct {
+	Preferences map[string]interface{} `json:"preferences" doc:"Preferences to set; null removes a key and keys left out are unchanged. Known keys: timezone (IANA zone), locale (language tag), theme (light, dark, system), notification_min_level (info, warning, critical), notification_sound and notification_desktop (booleans). Other keys take a string, number or boolean."`
+}
+
+// UpdateMyPreferencesInput represents the input for updating the caller's preferences
+type UpdateMyPreferencesInput struct {
+	Body          UpdatePreferencesRequest `json:"body"`
+	Authorization string                   `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string                   `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// MyDataExportInput represents the input for requesting, viewing or downloading the caller's data export
+type MyDataExportInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// AccountDeletionRequest represents the request body for scheduling the deletion of a user's account
+type AccountDeletionRequest struct {
+	Reason    string `json:"reason" minLength:"1" maxLength:"500" doc:"Why the account is deleted, kept with the deletion receipt"`
+	Immediate bool   `json:"immediate,omitempty" doc:"Skip the grace period; the account is erased on the next run of the erasure task"`
+}
+
+// ScheduleAccountDeletionInput represents the input for scheduling the deletion of a user's account
+type ScheduleAccountDeletionInput struct {
+	UserID        string                 `path:"user_id" doc:"User UUID"`
+	Body          AccountDeletionRequest `json:"body"`
+	Authorization string                 `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string                 `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// CancelAccountDeletionInput represents the input for cancelling the pending deletion of a user's account
+type CancelAccountDeletionInput struct {
+	UserID        string `path:"user_id" doc:"User UUID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// MergeAccountsRequest represents the request body for merging two user accounts
+type MergeAccountsRequest struct {
+	SourceUserID string `json:"source_user_id" minLength:"1" doc:"Account merged away; it keeps nothing"`
+	TargetUserID string `json:"target_user_id" minLength:"1" doc:"Surviving account"`
+	Reason       string `json:"reason" minLength:"1" maxLength:"500" doc:"Why the accounts are merged, kept with the merge record"`
+}
+
+// MergeAccountsInput represents the input for merging two user accounts
+type MergeAccountsInput struct {
+	Body          MergeAccountsRequest `json:"body"`
+	Authorization string               `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string               `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// RevertAccountMergeInput represents the input for reverting an account merge
+type RevertAccountMergeInput struct {
+	MergeID       string `path:"merge_id" doc:"Account merge ID"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// AccountMergeListInput represents the input for listing account merges
+type AccountMergeListInput struct {
+	UserID        string `query:"user_id" doc:"Only list merges from or into this user"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"500" default:"100" doc:"Maximum number of merges to return, newest first"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// AccountDeletionListInput represents the input for listing account deletions
+type AccountDeletionListInput struct {
+	Status        string `query:"status" enum:"pending,running,completed,cancelled" doc:"Only list deletions in this status"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"500" default:"100" doc:"Maximum number of deletions to return, newest first"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// Bulk user operations
+const (
+	BulkEnable  = "enable"  // Restore soft-deleted characters
+	BulkDisable = "disable" // Soft-delete characters
+	BulkBan     = "ban"
+	BulkUnban   = "unban"
+	BulkTag     = "tag"
+	BulkUntag   = "untag"
+)
+
+// UserFilter selects characters like the filters of the user list
+type UserFilter struct {
+	Query    string `json:"query,omitempty" doc:"Character name or ID, as in the user list search"`
+	Banned   string `json:"banned,omitempty" enum:"true,false" doc:"Banned status"`
+	Position int    `json:"position,omitempty" doc:"Position value (0 means no filter)"`
+	Tag      string `json:"tag,omitempty" doc:"Administrative tag"`
+	Deleted  string `json:"deleted,omitempty" enum:"true,false,all" doc:"Soft-deleted characters only (true), or both (all); active characters by default"`
+}
+
+// IsEmpty reports whether the filter has no criterion, selecting every character
+func (f *UserFilter) IsEmpty() bool {
+	return f.Query == "" && f.Banned == "" && f.Position == 0 && f.Tag == "" && f.Deleted != "true"
+}
+
+// BulkUserRequest represents the request body of a bulk user operation
+type BulkUserRequest struct {
+	Operation    string      `json:"operation" enum:"enable,disable,ban,unban,tag,untag" doc:"enable restores soft-deleted characters, disable soft-deletes them"`
+	Tag          string      `json:"tag,omitempty" maxLength:"64" doc:"Tag to add or remove; required for tag and untag"`
+	CharacterIDs []int       `json:"character_ids,omitempty" maxItems:"1000" doc:"Characters to change; give either these or a filter"`
+	Filter       *UserFilter `json:"filter,omitempty" doc:"Selects the characters to change instead of character_ids"`
+	DryRun       bool        `json:"dry_run,omitempty" doc:"Only report what would change"`
+}
+
+// BulkUserInput represents the input for a bulk user operation
+type BulkUserInput struct {
+	Body          BulkUserRequest `json:"body"`
+	Authorization string          `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string          `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// UserSearchInput represents the input for searching users
+type UserSearchInput struct {
+	Query         string `query:"q" minLength:"2" maxLength:"100" required:"true" doc:"Character name or name prefix, character ID, corporation, alliance, tag or words from the notes"`
+	Deleted       string `query:"deleted" enum:"true,false,all" doc:"Search soft-deleted characters instead of active ones (true), or both (all)"`
+	Limit         int    `query:"limit" minimum:"1" maximum:"100" default:"25" doc:"Maximum number of characters to return, best match first"`
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
@@ -2,6 +2,8 @@ package dto
 
 import (
 	"time"
+
+	"go-falcon/internal/users/models"
 )
 
 // EnrichedCorporationInfo represents corporation information for enriched character responses
@@ -32,17 +34,20 @@ type EnrichedAllianceInfo struct {
 
 // UserResponse represents a user in API responses
 type UserResponse struct {
-	CharacterID   int       `json:"character_id"`
-	UserID        string    `json:"user_id"`
-	Banned        bool      `json:"banned"`
-	Scopes        string    `json:"scopes"`
-	Position      int       `json:"position"`
-	Notes         string    `json:"notes"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	LastLogin     time.Time `json:"last_login"`
-	CharacterName string    `json:"character_name"`
-	Valid         bool      `json:"valid"`
+	CharacterID   int        `json:"character_id"`
+	UserID        string     `json:"user_id"`
+	Banned        bool       `json:"banned"`
+	Scopes        string     `json:"scopes"`
+	Position      int        `json:"position"`
+	Notes         string     `json:"notes"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	LastLogin     time.Time  `json:"last_login"`
+	CharacterName string     `json:"character_name"`
+	Valid         bool       `json:"valid"`
+	Tags          []string   `json:"tags" doc:"Administrative tags"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" doc:"When the character was soft-deleted; it is purged after the retention period unless restored"`
+	DeletedBy     string     `json:"deleted_by,omitempty" doc:"Who soft-deleted the character"`
 }
 
 // CharacterSummaryResponse represents basic character information for listing
@@ -170,6 +175,12 @@ type UserDeleteResponse struct {
 	Message string `json:"message"`
 }
 
+// SoftDeletePurgeRunResult summarizes one run of the soft delete purge task
+type SoftDeletePurgeRunResult struct {
+	Purged int `json:"purged" doc:"Soft-deleted characters removed for good"`
+	Failed int `json:"failed" doc:"Characters that could not be purged; retried on the next run"`
+}
+
 // UsersStatusResponse represents the actual status response data
 type UsersStatusResponse struct {
 	Module  string `json:"module" description:"Module name"`
@@ -188,3 +199,244 @@ type UserReorderCharactersResponse struct {
 type UserReorderCharactersOutput struct {
 	Body UserReorderCharactersResponse `json:"body"`
 }
+
+// UsageBucket represents the estimated API call count for one hour
+type UsageBucket struct {
+	Start time.Time `json:"start" doc:"Start of the hourly bucket (UTC)"`
+	Count int64     `json:"count" doc:"Estimated number of API calls"`
+}
+
+// UsageResponse represents a character's API usage over a time window
+type UsageResponse struct {
+	CharacterID   int           `json:"character_id" doc:"Character ID the usage belongs to"`
+	Enabled       bool          `json:"enabled" doc:"Whether usage tracking is active; without Redis every count is zero"`
+	Hours         int           `json:"hours" doc:"Number of hourly buckets reported"`
+	SamplePercent int           `json:"sample_percent" doc:"Percentage of requests sampled; counts are extrapolated estimates"`
+	Total         int64         `json:"total" doc:"Estimated total API calls in the window"`
+	Buckets       []UsageBucket `json:"buckets" doc:"Hourly buckets, oldest first"`
+}
+
+// UsageOutput represents the output for the current user's API usage
+type UsageOutput struct {
+	Body UsageResponse `json:"body"`
+}
+
+// UsageLeaderboardEntry represents one API consumer in the leaderboard
+type UsageLeaderboardEntry struct {
+	Rank          int           `json:"rank"`
+	CharacterID   int           `json:"character_id"`
+	CharacterName string        `json:"character_name,omitempty"`
+	Count         int64         `json:"count" doc:"Estimated number of API calls in the window"`
+	Buckets       []UsageBucket `json:"buckets" doc:"Hourly buckets, oldest first"`
+}
+
+// UsageLeaderboardResponse represents the heaviest API consumers over a time window
+type UsageLeaderboardResponse struct {
+	Enabled       bool                    `json:"enabled" doc:"Whether usage tracking is active; without Redis the leaderboard is empty"`
+	Hours         int                     `json:"hours"`
+	SamplePercent int                     `json:"sample_percent"`
+	Entries       []UsageLeaderboardEntry `json:"entries"`
+}
+
+// UsageLeaderboardOutput represents the output for the API usage leaderboard
+type UsageLeaderboardOutput struct {
+	Body UsageLeaderboardResponse `json:"body"`
+}
+
+// InactivityPolicy describes the configured inactivity policy
+type InactivityPolicy struct {
+	Enforced       bool `json:"enforced" doc:"Whether inactive users are notified, disabled and purged; otherwise they are only flagged"`
+	InactiveMonths int  `json:"inactive_months" doc:"Months without SSO or in-game login after which a user is inactive"`
+	NoticeDays     int  `json:"notice_days" doc:"Days between the notice and disabling the account"`
+	PurgeDays      int  `json:"purge_days" doc:"Days between disabling and purging the account (0 never purges)"`
+}
+
+// InactiveUserEntry represents a tracked inactive user and what the policy will do next
+type InactiveUserEntry struct {
+	models.InactiveUser
+	NextAction   string     `json:"next_action" enum:"notify,disable,purge,none" doc:"Next step of the policy for this user"`
+	NextActionAt *time.Time `json:"next_action_at,omitempty" doc:"Earliest time of the next step; it happens on the first policy run after it"`
+}
+
+// InactivityReport lists inactive users, soonest next action first
+type InactivityReport struct {
+	Policy   InactivityPolicy    `json:"policy"`
+	Flagged  int                 `json:"flagged"`
+	Notified int                 `json:"notified"`
+	Disabled int                 `json:"disabled"`
+	Users    []InactiveUserEntry `json:"users"`
+}
+
+// InactivityReportOutput represents the output for the inactive user report
+type InactivityReportOutput struct {
+	Body InactivityReport `json:"body"`
+}
+
+// InactivityRunResult summarizes one run of the inactivity policy
+type InactivityRunResult struct {
+	Enforced bool `json:"enforced"`
+	Flagged  int  `json:"flagged"`
+	Notified int  `json:"notified"`
+	Disabled int  `json:"disabled"`
+	Purged   int  `json:"purged"`
+	Cleared  int  `json:"cleared" doc:"Users no longer tracked because they became active again or were exempted"`
+	Failed   int  `json:"failed"`
+}
+
+// InactivityExemptionListOutput represents the output for listing inactivity exemptions
+type InactivityExemptionListOutput struct {
+	Body struct {
+		Exemptions []*models.InactivityExemption `json:"exemptions"`
+		Count      int                           `json:"count"`
+	} `json:"body"`
+}
+
+// InactivityExemptionOutput represents the output for exempting a user
+type InactivityExemptionOutput struct {
+	Body models.InactivityExemption `json:"body"`
+}
+
+// InactivityExemptionDeleteOutput represents the output for removing an exemption
+type InactivityExemptionDeleteOutput struct {
+	Body struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+	} `json:"body"`
+}
+
+// AccountDeletionOutput represents the output for an account deletion
+type AccountDeletionOutput struct {
+	Body models.AccountDeletion `json:"body"`
+}
+
+// AccountDeletionListOutput represents the output for listing account deletions
+type AccountDeletionListOutput struct {
+	Body struct {
+		Deletions []*models.AccountDeletion `json:"deletions"`
+		Count     int                       `json:"count"`
+	} `json:"body"`
+}
+
+// UserTagCount is a tag in use and how many characters carry it
+type UserTagCount struct {
+	Tag        string `json:"tag" bson:"tag"`
+	Characters int    `json:"characters" bson:"characters"`
+}
+
+// UserTagListOutput represents the output for listing the tags in use
+type UserTagListOutput struct {
+	Body struct {
+		Tags []UserTagCount `json:"tags"`
+	} `json:"body"`
+}
+
+// UserAuditListOutput represents the output for listing the users audit log
+type UserAuditListOutput struct {
+	Body struct {
+		Events []*models.AuditEvent `json:"events"`
+		Count  int                  `json:"count"`
+	} `json:"body"`
+}
+
+// PreferencesOutput represents the output for a user's preferences
+type PreferencesOutput struct {
+	Body models.UserPreferences `json:"body"`
+}
+
+// DataExportOutput represents the output for a data export
+type DataExportOutput struct {
+	Body models.DataExport `json:"body"`
+}
+
+// DataExportDownloadOutput represents the archive of a data export
+type DataExportDownloadOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	CacheControl       string `header:"Cache-Control"`
+	Body               []byte
+}
+
+// DataExportRunResult summarizes one run of the data export task
+type DataExportRunResult struct {
+	Exported int `json:"exported"`
+	Failed   int `json:"failed" doc:"Exports that failed; retried on the next run up to three attempts"`
+	Expired  int `json:"expired" doc:"Expired exports whose archive was deleted"`
+}
+
+// AccountMergeOutput represents the output for an account merge
+type AccountMergeOutput struct {
+	Body models.AccountMerge `json:"body"`
+}
+
+// AccountMergeListOutput represents the output for listing account merges
+type AccountMergeListOutput struct {
+	Body struct {
+		Merges []*models.AccountMerge `json:"merges"`
+		Count  int                    `json:"count"`
+	} `json:"body"`
+}
+
+// AccountDeletionRunResult summarizes one run of the account erasure task
+type AccountDeletionRunResult struct {
+	Erased int `json:"erased"`
+	Failed int `json:"failed" doc:"Erasures that failed and are retried on the next run"`
+}
+
+// Outcomes of one character in a bulk user operation
+const (
+	BulkChanged     = "changed"
+	BulkWouldChange = "would_change" // Dry run only
+	BulkUnchanged   = "unchanged"    // Already in the requested state
+	BulkFailed      = "failed"
+)
+
+// BulkUserItemResult is the outcome of a bulk user operation for one character
+type BulkUserItemResult struct {
+	CharacterID   int    `json:"character_id"`
+	CharacterName string `json:"character_name,omitempty"`
+	Status        string `json:"status" enum:"changed,would_change,unchanged,failed"`
+	Error         string `json:"error,omitempty" doc:"Why the character could not be changed"`
+}
+
+// BulkUserResponse summarizes a bulk user operation
+type BulkUserResponse struct {
+	Operation string               `json:"operation"`
+	Tag       string               `json:"tag,omitempty"`
+	DryRun    bool                 `json:"dry_run"`
+	Matched   int                  `json:"matched" doc:"Characters the operation was applied to"`
+	Changed   int                  `json:"changed" doc:"Characters changed, or that would change in a dry run"`
+	Unchanged int                  `json:"unchanged"`
+	Failed    int                  `json:"failed"`
+	Results   []BulkUserItemResult `json:"results"`
+}
+
+// BulkUserOutput represents the output of a bulk user operation
+type BulkUserOutput struct {
+	Body BulkUserResponse `json:"body"`
+}
+
+// UserSearchResult is a character found by the user search
+type UserSearchResult struct {
+	CharacterID     int        `json:"character_id"`
+	CharacterName   string     `json:"character_name"`
+	UserID          string     `json:"user_id"`
+	CorporationID   int        `json:"corporation_id,omitempty"`
+	CorporationName string     `json:"corporation_name,omitempty"`
+	AllianceID      int        `json:"alliance_id,omitempty"`
+	AllianceName    string     `json:"alliance_name,omitempty"`
+	Tags            []string   `json:"tags"`
+	Banned          bool       `json:"banned"`
+	LastLogin       time.Time  `json:"last_login"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	Matched         []string   `json:"matched" doc:"Fields containing the query or one of its words: character_id, character_name, corporation_name, alliance_name, tags, notes"`
+	Score           float64    `json:"score" doc:"Relevance; results are ordered by it"`
+}
+
+// UserSearchOutput represents the output for searching users
+type UserSearchOutput struct {
+	Body struct {
+		Query   string             `json:"query"`
+		Results []UserSearchResult `json:"results"`
+		Count   int                `json:"count"`
+	} `json:"body"`
+}
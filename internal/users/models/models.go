@@ -2,6 +2,10 @@ package models
 
 import (
 	"time"
+
+	"go-falcon/pkg/identity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // User represents a user with character information and state control
@@ -20,6 +24,10 @@ type User struct {
 	LastLogin     time.Time `json:"last_login" bson:"last_login"`         // Last login timestamp
 	CharacterName string    `json:"character_name" bson:"character_name"` // EVE character name
 	Valid         bool      `json:"valid" bson:"valid"`                   // Character profile validity status
+	Tags          []string  `json:"tags,omitempty" bson:"tags,omitempty"` // Administrative tags, e.g. "fc" or "alt-of:123"
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"` // Soft deletion; purged after the retention period
+	DeletedBy string     `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"` // Who soft-deleted the character
 }
 
 // CharacterSummary represents basic character information for listing
@@ -33,6 +41,23 @@ type CharacterSummary struct {
 	Valid         bool       `json:"valid" bson:"valid"`
 }
 
+// UserSearchHit is a character found by the user search, with the profile fields it is searched on
+type UserSearchHit struct {
+	CharacterID     int        `bson:"character_id"`
+	CharacterName   string     `bson:"character_name"`
+	UserID          string     `bson:"user_id"`
+	CorporationID   int        `bson:"corporation_id"`
+	CorporationName string     `bson:"corporation_name"`
+	AllianceID      int        `bson:"alliance_id"`
+	AllianceName    string     `bson:"alliance_name"`
+	Notes           string     `bson:"notes"`
+	Tags            []string   `bson:"tags"`
+	Banned          bool       `bson:"banned"`
+	LastLogin       time.Time  `bson:"last_login"`
+	DeletedAt       *time.Time `bson:"deleted_at"`
+	Score           float64    `bson:"score"` // Text search relevance, 0 for name matches
+}
+
 // CollectionName returns the MongoDB collection name for users
 func (User) CollectionName() string {
 	return "user_profiles"
@@ -42,3 +67,220 @@ func (User) CollectionName() string {
 func (CharacterSummary) CollectionName() string {
 	return "user_profiles"
 }
+
+// Inactivity stages a user passes through before being purged
+const (
+	// InactivityFlagged users are inactive but the policy is not enforced, so nothing has been done yet
+	InactivityFlagged = "flagged"
+	// InactivityNotified users have been warned that their account will be disabled
+	InactivityNotified = "notified"
+	// InactivityDisabled users have had their EVE tokens revoked and will be purged
+	InactivityDisabled = "disabled"
+)
+
+// InactiveUser tracks a user the inactivity policy is acting on. The record is removed as soon as
+// the user shows activity again, is exempted or has been purged.
+type InactiveUser struct {
+	UserID         string     `json:"user_id" bson:"user_id"`
+	CharacterIDs   []int      `json:"character_ids" bson:"character_ids"`
+	CharacterNames []string   `json:"character_names" bson:"character_names"`
+	Stage          string     `json:"stage" bson:"stage"`
+	LastLogin      time.Time  `json:"last_login" bson:"last_login"`                                   // Latest SSO login over all characters
+	LastESIActive  *time.Time `json:"last_esi_activity,omitempty" bson:"last_esi_activity,omitempty"` // Latest in-game login reported by ESI
+	FlaggedAt      time.Time  `json:"flagged_at" bson:"flagged_at"`
+	NotifiedAt     *time.Time `json:"notified_at,omitempty" bson:"notified_at,omitempty"`
+	DisableAt      *time.Time `json:"disable_at,omitempty" bson:"disable_at,omitempty"`
+	DisabledAt     *time.Time `json:"disabled_at,omitempty" bson:"disabled_at,omitempty"`
+	PurgeAt        *time.Time `json:"purge_at,omitempty" bson:"purge_at,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// CollectionName returns the MongoDB collection name for inactive users
+func (InactiveUser) CollectionName() string {
+	return "user_inactivity"
+}
+
+// InactivityExemption keeps a user out of the inactivity policy, e.g. for long-term leave or
+// service accounts
+type InactivityExemption struct {
+	UserID    string     `json:"user_id" bson:"user_id"`
+	Reason    string     `json:"reason" bson:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	CreatedBy string     `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// CollectionName returns the MongoDB collection name for inactivity exemptions
+func (InactivityExemption) CollectionName() string {
+	return "user_inactivity_exemptions"
+}
+
+// Account deletion statuses
+const (
+	// DeletionPending deletions wait out the grace period; the user can still cancel them
+	DeletionPending = "pending"
+	// DeletionRunning deletions are being carried out by the erasure task
+	DeletionRunning = "running"
+	// DeletionCompleted deletions have erased the account and carry a receipt
+	DeletionCompleted = "completed"
+	// DeletionCancelled deletions were cancelled before they ran
+	DeletionCancelled = "cancelled"
+)
+
+// AccountDeletion is a request to erase a user account (GDPR right to erasure). It is carried out
+// by the user_account_erasure task once ScheduledFor has passed. The character IDs are dropped
+// once the erasure completed; the receipt only keeps counts.
+type AccountDeletion struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID       string             `json:"user_id" bson:"user_id"`
+	CharacterIDs []int              `json:"character_ids,omitempty" bson:"character_ids,omitempty"`
+	Status       string             `json:"status" bson:"status"`
+	RequestedBy  string             `json:"requested_by" bson:"requested_by"`         // Identity label of the user or administrator
+	Reason       string             `json:"reason,omitempty" bson:"reason,omitempty"` // Given by an administrator
+	ScheduledFor time.Time          `json:"scheduled_for" bson:"scheduled_for"`
+	Attempts     int                `json:"attempts" bson:"attempts"`
+	LastError    string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	CancelledBy  string             `json:"cancelled_by,omitempty" bson:"cancelled_by,omitempty"`
+	CancelledAt  *time.Time         `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+	Receipt      *DeletionReceipt   `json:"receipt,omitempty" bson:"receipt,omitempty"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// DeletionReceipt records what an erasure removed
+type DeletionReceipt struct {
+	ReceiptID             string    `json:"receipt_id" bson:"receipt_id"`
+	Characters            int       `json:"characters" bson:"characters"`
+	SessionsRevoked       int       `json:"sessions_revoked" bson:"sessions_revoked"`
+	ProfilesErased        int64     `json:"profiles_erased" bson:"profiles_erased"`
+	NotificationsPurged   int64     `json:"notifications_purged" bson:"notifications_purged"`
+	AuditEventsAnonymized int64     `json:"audit_events_anonymized" bson:"audit_events_anonymized"`
+	CompletedAt           time.Time `json:"completed_at" bson:"completed_at"`
+}
+
+// CollectionName returns the MongoDB collection name for account deletions
+func (AccountDeletion) CollectionName() string {
+	return "user_deletions"
+}
+
+// Account merge statuses
+const (
+	// MergeCompleted merges moved everything of the source account to the target account
+	MergeCompleted = "merged"
+	// MergeReverted merges were undone; what they moved belongs to the source account again
+	MergeReverted = "reverted"
+)
+
+// AccountMerge records the merge of a user account into another, e.g. when someone registered
+// twice. It lists exactly what was moved, so a revert moves back only that.
+type AccountMerge struct {
+	ID                primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	SourceUserID      string               `json:"source_user_id" bson:"source_user_id"` // Account merged away
+	TargetUserID      string               `json:"target_user_id" bson:"target_user_id"` // Surviving account
+	Status            string               `json:"status" bson:"status"`
+	CharacterIDs      []int                `json:"character_ids" bson:"character_ids"`
+	DeliveryIDs       []primitive.ObjectID `json:"delivery_ids,omitempty" bson:"delivery_ids,omitempty"`             // Notification deliveries
+	JoinRequestIDs    []primitive.ObjectID `json:"join_request_ids,omitempty" bson:"join_request_ids,omitempty"`     // Group join requests
+	DiscordUserIDs    []primitive.ObjectID `json:"discord_user_ids,omitempty" bson:"discord_user_ids,omitempty"`     // Linked Discord accounts
+	CreatedTaskIDs    []string             `json:"created_task_ids,omitempty" bson:"created_task_ids,omitempty"`     // Scheduler tasks created by the source
+	UpdatedTaskIDs    []string             `json:"updated_task_ids,omitempty" bson:"updated_task_ids,omitempty"`     // Scheduler tasks last updated by the source
+	SkippedDeliveries int                  `json:"skipped_deliveries,omitempty" bson:"skipped_deliveries,omitempty"` // Notifications both accounts received; the source's copy stays
+	Reason            string               `json:"reason" bson:"reason"`
+	MergedBy          string               `json:"merged_by" bson:"merged_by"` // Identity label of the administrator
+	RevertedBy        string               `json:"reverted_by,omitempty" bson:"reverted_by,omitempty"`
+	RevertedAt        *time.Time           `json:"reverted_at,omitempty" bson:"reverted_at,omitempty"`
+	CreatedAt         time.Time            `json:"created_at" bson:"created_at"`
+}
+
+// CollectionName returns the MongoDB collection name for account merges
+func (AccountMerge) CollectionName() string {
+	return "user_merges"
+}
+
+// Data export statuses
+const (
+	// ExportPending exports wait for the export task to build their archive
+	ExportPending = "pending"
+	// ExportRunning exports are being built
+	ExportRunning = "running"
+	// ExportCompleted exports can be downloaded until they expire
+	ExportCompleted = "completed"
+	// ExportFailed exports gave up after repeated failures; the user can request a new one
+	ExportFailed = "failed"
+)
+
+// ExportsBucket is the GridFS bucket the archives of data exports are stored in
+const ExportsBucket = "user_exports"
+
+// DataExport is a user's request for a copy of everything stored about their account (GDPR right
+// of access). The archive is built asynchronously and deleted, along with the request, once it
+// expires.
+type DataExport struct {
+	ID          primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID      string              `json:"user_id" bson:"user_id"`
+	Status      string              `json:"status" bson:"status"`
+	RequestedBy string              `json:"requested_by" bson:"requested_by"` // Identity label of the user
+	Attempts    int                 `json:"attempts" bson:"attempts"`
+	LastError   string              `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	StorageKey  string              `json:"-" bson:"storage_key,omitempty"`
+	SizeBytes   int64               `json:"size_bytes,omitempty" bson:"size_bytes,omitempty"`
+	Contents    *DataExportContents `json:"contents,omitempty" bson:"contents,omitempty"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	ExpiresAt   *time.Time          `json:"expires_at,omitempty" bson:"expires_at,omitempty"` // The archive is deleted after this time
+	CreatedAt   time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// DataExportContents counts the records in an export archive
+type DataExportContents struct {
+	Characters       int `json:"characters" bson:"characters"`
+	GroupMemberships int `json:"group_memberships" bson:"group_memberships"`
+	Notifications    int `json:"notifications" bson:"notifications"`
+	DiscordLinks     int `json:"discord_links" bson:"discord_links"`
+	AuditEntries     int `json:"audit_entries" bson:"audit_entries"`
+}
+
+// CollectionName returns the MongoDB collection name for data exports
+func (DataExport) CollectionName() string {
+	return "user_data_exports"
+}
+
+// UserPreferences holds the settings a user's frontends share, as key/value pairs. Known keys are
+// validated; other keys hold any string, number or boolean.
+type UserPreferences struct {
+	UserID      string                 `json:"user_id" bson:"user_id"`
+	Preferences map[string]interface{} `json:"preferences" bson:"preferences"`
+	UpdatedAt   *time.Time             `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// CollectionName returns the MongoDB collection name for user preferences
+func (UserPreferences) CollectionName() string {
+	return "user_preferences"
+}
+
+// Users audit log actions
+const (
+	AuditTagAdded     = "tag_added"
+	AuditTagRemoved   = "tag_removed"
+	AuditUserDeleted  = "user_deleted"
+	AuditUserRestored = "user_restored"
+	AuditUserPurged   = "user_purged"
+)
+
+// AuditEvent is one entry of the users audit log: an administrative change to a user's character
+type AuditEvent struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Action        string             `json:"action" bson:"action"`
+	CharacterID   int                `json:"character_id" bson:"character_id"`
+	CharacterName string             `json:"character_name,omitempty" bson:"character_name,omitempty"`
+	UserID        string             `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Tag           string             `json:"tag,omitempty" bson:"tag,omitempty"`
+
+	identity.Attribution `bson:",inline"`
+	Timestamp            time.Time `json:"timestamp" bson:"timestamp"`
+}
+
+// CollectionName returns the MongoDB collection name for the users audit log
+func (AuditEvent) CollectionName() string {
+	return "user_audit"
+}
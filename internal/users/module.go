@@ -7,14 +7,17 @@ import (
 
 	"go-falcon/internal/auth"
 	"go-falcon/internal/groups/services"
+	usersDto "go-falcon/internal/users/dto"
 	"go-falcon/internal/users/routes"
 	usersServices "go-falcon/internal/users/services"
 	"go-falcon/pkg/database"
 	"go-falcon/pkg/evegateway"
+	"go-falcon/pkg/identity"
 	"go-falcon/pkg/middleware"
 	"go-falcon/pkg/module"
 	"go-falcon/pkg/permissions"
 	"go-falcon/pkg/sde"
+	"go-falcon/pkg/storage"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi/v5"
@@ -33,6 +36,10 @@ type Module struct {
 // New creates a new users module instance
 func New(mongodb *database.MongoDB, redis *database.Redis, authModule *auth.Module, eveGateway *evegateway.Client, sdeService sde.SDEService) *Module {
 	service := usersServices.NewService(mongodb, redis, eveGateway, sdeService)
+	if authModule != nil {
+		service.SetAccountEraser(authModule.GetAuthService())
+		service.SetSessionRevoker(authModule.GetAuthService())
+	}
 
 	return &Module{
 		BaseModule:   module.NewBaseModule("users", mongodb, redis),
@@ -49,6 +56,41 @@ func (m *Module) SetGroupService(groupService *services.Service) {
 	m.service.SetGroupService(groupService)
 }
 
+// Initialize creates the indexes of the users module's own collections
+func (m *Module) Initialize(ctx context.Context) error {
+	return m.service.CreateIndexes(ctx)
+}
+
+// SetNotifier sets where inactivity notices are sent
+func (m *Module) SetNotifier(notifier usersServices.Notifier) {
+	m.service.SetNotifier(notifier)
+}
+
+// EnforceInactivityPolicy runs the inactive user policy (used by the scheduler)
+func (m *Module) EnforceInactivityPolicy(ctx context.Context) (*usersDto.InactivityRunResult, error) {
+	return m.service.EnforceInactivityPolicy(ctx)
+}
+
+// ProcessAccountDeletions erases the accounts whose deletion is due (used by the scheduler)
+func (m *Module) ProcessAccountDeletions(ctx context.Context) (*usersDto.AccountDeletionRunResult, error) {
+	return m.service.ProcessAccountDeletions(ctx)
+}
+
+// SetExportStore sets where the archives of data exports are kept
+func (m *Module) SetExportStore(store storage.Store) {
+	m.service.SetExportStore(store)
+}
+
+// ProcessDataExports builds waiting data exports and deletes expired ones (used by the scheduler)
+func (m *Module) ProcessDataExports(ctx context.Context) (*usersDto.DataExportRunResult, error) {
+	return m.service.ProcessDataExports(ctx)
+}
+
+// PurgeDeletedUsers removes characters whose soft deletion outlived the retention period (used by the scheduler)
+func (m *Module) PurgeDeletedUsers(ctx context.Context) (*usersDto.SoftDeletePurgeRunResult, error) {
+	return m.service.PurgeDeletedUsers(ctx)
+}
+
 // GetService returns the users service instance
 func (m *Module) GetService() *usersServices.Service {
 	return m.service
@@ -95,6 +137,23 @@ func (m *Module) RegisterUnifiedRoutes(api huma.API, basePath string) {
 	log.Printf("Users module unified routes registered at %s", basePath)
 }
 
+// UsageMiddleware returns a Huma middleware that records sampled API calls per character.
+// The sampling decision comes first; only sampled calls read the identity resolved by
+// middleware.IdentityMiddleware, which must run first. Anonymous requests and impersonated calls
+// are not counted.
+func (m *Module) UsageMiddleware() func(ctx huma.Context, next func(huma.Context)) {
+	tracker := m.service.GetUsageTracker()
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if tracker.Sample() {
+			if id := identity.FromContext(ctx.Context()); id != nil && !id.IsSystem() && id.Impersonator == nil {
+				tracker.Record(ctx.Context(), id.Actor.CharacterID, id.Actor.CharacterName)
+			}
+		}
+		next(ctx)
+	}
+}
+
 // StartBackgroundTasks starts any background processes for the module
 func (m *Module) StartBackgroundTasks(ctx context.Context) {
 	slog.Info("Starting users-specific background tasks")
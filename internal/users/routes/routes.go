@@ -2,15 +2,21 @@ package routes
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"go-falcon/internal/users/dto"
 	"go-falcon/internal/users/services"
+	"go-falcon/pkg/apidocs"
+	"go-falcon/pkg/identity"
 	"go-falcon/pkg/middleware"
+	"go-falcon/pkg/stepup"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humachi"
 	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Routes handles Huma-based HTTP routing for the Users module
@@ -32,10 +38,6 @@ func NewRoutes(service *services.Service, router chi.Router) *Routes {
 		api:     api,
 	}
 
-	// Note: registerRoutes() call removed for security - it exposed stats endpoint without authentication
-	// All secure routes are now registered via RegisterUsersRoutes() called by RegisterUnifiedRoutes()
-	// hr.registerRoutes() // REMOVED: This exposed unprotected endpoints
-
 	return hr
 }
 
@@ -54,6 +56,513 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 		return &dto.StatusOutput{Body: *status}, nil
 	})
 
+	// API usage reporting
+	huma.Register(api, huma.Operation{
+		OperationID: "users-get-my-usage",
+		Method:      "GET",
+		Path:        basePath + "/me/usage",
+		Summary:     "Get my API usage",
+		Description: "Returns sampled, hourly API call counts for the authenticated character",
+		Tags:        []string{"Users / Usage"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UsageInput) (*dto.UsageOutput, error) {
+		user, err := usersAdapter.RequireAuthenticated(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := service.GetUsageTracker().GetCharacterUsage(ctx, user.CharacterID, input.Hours)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get API usage", err)
+		}
+		return &dto.UsageOutput{Body: *response}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-get-usage-leaderboard",
+		Method:      "GET",
+		Path:        basePath + "/usage/leaderboard",
+		Summary:     "Get API usage leaderboard",
+		Description: "Lists the heaviest API consumers over a time window based on sampled call counts",
+		Tags:        []string{"Users / Usage"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UsageLeaderboardInput) (*dto.UsageLeaderboardOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := service.GetUsageTracker().GetLeaderboard(ctx, input.Hours, input.Limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get API usage leaderboard", err)
+		}
+		return &dto.UsageLeaderboardOutput{Body: *response}, nil
+	})
+
+	// Inactive user policy
+	huma.Register(api, huma.Operation{
+		OperationID: "users-get-inactivity-report",
+		Method:      "GET",
+		Path:        basePath + "/inactivity/report",
+		Summary:     "Get inactive user report",
+		Description: "Lists users flagged by the inactivity policy with the date they will next be notified, disabled or purged, soonest first",
+		Tags:        []string{"Users / Inactivity"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.InactivityReportInput) (*dto.InactivityReportOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := service.GetInactivityReport(ctx, input.Stage)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get inactivity report", err)
+		}
+		return &dto.InactivityReportOutput{Body: *report}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-list-inactivity-exemptions",
+		Method:      "GET",
+		Path:        basePath + "/inactivity/exemptions",
+		Summary:     "List inactivity exemptions",
+		Description: "Lists users exempted from the inactivity policy, including expired exemptions",
+		Tags:        []string{"Users / Inactivity"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.InactivityExemptionsInput) (*dto.InactivityExemptionListOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		exemptions, err := service.ListInactivityExemptions(ctx)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list inactivity exemptions", err)
+		}
+		output := &dto.InactivityExemptionListOutput{}
+		output.Body.Exemptions = exemptions
+		output.Body.Count = len(exemptions)
+		return output, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-create-inactivity-exemption",
+		Method:      "PUT",
+		Path:        basePath + "/inactivity/exemptions/{user_id}",
+		Summary:     "Exempt user from inactivity policy",
+		Description: "Exempts a user from the inactivity policy, replacing any existing exemption, and stops steps already taken against them. A disabled user must log in again to restore their tokens.",
+		Tags:        []string{"Users / Inactivity"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.CreateInactivityExemptionInput) (*dto.InactivityExemptionOutput, error) {
+		user, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		createdBy := fmt.Sprintf("character:%d", user.CharacterID)
+		if id := identity.FromContext(ctx); id != nil {
+			createdBy = id.Label()
+		}
+
+		exemption, err := service.ExemptFromInactivity(ctx, input.UserID, input.Body, createdBy)
+		if err != nil {
+			if errors.Is(err, services.ErrUserNotFound) {
+				return nil, huma.Error404NotFound("User not found")
+			}
+			return nil, huma.Error500InternalServerError("Failed to exempt user", err)
+		}
+		return &dto.InactivityExemptionOutput{Body: *exemption}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-delete-inactivity-exemption",
+		Method:      "DELETE",
+		Path:        basePath + "/inactivity/exemptions/{user_id}",
+		Summary:     "Remove inactivity exemption",
+		Description: "Puts a user back under the inactivity policy",
+		Tags:        []string{"Users / Inactivity"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.DeleteInactivityExemptionInput) (*dto.InactivityExemptionDeleteOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := service.RemoveInactivityExemption(ctx, input.UserID); err != nil {
+			if errors.Is(err, services.ErrInactivityExemptionNotFound) {
+				return nil, huma.Error404NotFound("Inactivity exemption not found")
+			}
+			return nil, huma.Error500InternalServerError("Failed to remove inactivity exemption", err)
+		}
+		output := &dto.InactivityExemptionDeleteOutput{}
+		output.Body.Success = true
+		output.Body.Message = "Inactivity exemption removed"
+		return output, nil
+	})
+
+	// Account deletion (right to erasure)
+	huma.Register(api, huma.Operation{
+		OperationID:   "users-request-my-deletion",
+		Method:        "DELETE",
+		Path:          basePath + "/me",
+		Summary:       "Delete my account",
+		Description:   "Schedules the erasure of the caller's account and all its characters after a grace period, during which it can be cancelled. The erasure revokes all sessions and EVE tokens, anonymizes audit events, removes group memberships and notifications, deletes the profiles and leaves a deletion receipt.",
+		Tags:          []string{"Users / Deletion"},
+		DefaultStatus: http.StatusAccepted,
+		Security:      []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.MyAccountDeletionInput) (*dto.AccountDeletionOutput, error) {
+		user, err := usersAdapter.RequireAuthenticated(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if user.Impersonator != nil {
+			return nil, huma.Error403Forbidden("Not available while impersonating")
+		}
+
+		deletion, err := service.RequestAccountDeletion(ctx, user.UserID, requester(ctx, user.CharacterID), "", false)
+		if err != nil {
+			return nil, accountDeletionError(err)
+		}
+		return &dto.AccountDeletionOutput{Body: *deletion}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-get-my-deletion",
+		Method:      "GET",
+		Path:        basePath + "/me/deletion",
+		Summary:     "Get my account deletion",
+		Description: "Returns the pending or running deletion of the caller's account",
+		Tags:        []string{"Users / Deletion"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.MyAccountDeletionInput) (*dto.AccountDeletionOutput, error) {
+		user, err := usersAdapter.RequireAuthenticated(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		deletion, err := service.GetAccountDeletion(ctx, user.UserID)
+		if err != nil {
+			return nil, accountDeletionError(err)
+		}
+		return &dto.AccountDeletionOutput{Body: *deletion}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-cancel-my-deletion",
+		Method:      "DELETE",
+		Path:        basePath + "/me/deletion",
+		Summary:     "Cancel my account deletion",
+		Description: "Cancels the pending deletion of the caller's account during its grace period",
+		Tags:        []string{"Users / Deletion"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.MyAccountDeletionInput) (*dto.AccountDeletionOutput, error) {
+		user, err := usersAdapter.RequireAuthenticated(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if user.Impersonator != nil {
+			return nil, huma.Error403Forbidden("Not available while impersonating")
+		}
+
+		deletion, err := service.CancelAccountDeletion(ctx, user.UserID, requester(ctx, user.CharacterID))
+		if err != nil {
+			return nil, accountDeletionError(err)
+		}
+		return &dto.AccountDeletionOutput{Body: *deletion}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-list-deletions",
+		Method:      "GET",
+		Path:        basePath + "/deletions",
+		Summary:     "List account deletions",
+		Description: "Lists account deletions newest first, including the receipts of completed erasures",
+		Tags:        []string{"Users / Deletion"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.AccountDeletionListInput) (*dto.AccountDeletionListOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		deletions, err := service.ListAccountDeletions(ctx, input.Status, input.Limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list account deletions", err)
+		}
+		output := &dto.AccountDeletionListOutput{}
+		output.Body.Deletions = deletions
+		output.Body.Count = len(deletions)
+		return output, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "users-schedule-user-deletion",
+		Method:        "POST",
+		Path:          basePath + "/{user_id}/deletion",
+		Summary:       "Schedule account deletion",
+		Description:   "Schedules the erasure of a user's account on their behalf. With immediate set the grace period is skipped, also for a deletion the user already requested.",
+		Tags:          []string{"Users / Deletion"},
+		DefaultStatus: http.StatusAccepted,
+		Extensions:    apidocs.RequiresPermission("users:management:full"),
+		Security:      []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.ScheduleAccountDeletionInput) (*dto.AccountDeletionOutput, error) {
+		user, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := stepup.Require(ctx, "users-schedule-user-deletion"); err != nil {
+			return nil, err
+		}
+
+		deletion, err := service.RequestAccountDeletion(ctx, input.UserID, requester(ctx, user.CharacterID), input.Body.Reason, input.Body.Immediate)
+		if err != nil {
+			return nil, accountDeletionError(err)
+		}
+		return &dto.AccountDeletionOutput{Body: *deletion}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-cancel-user-deletion",
+		Method:      "DELETE",
+		Path:        basePath + "/{user_id}/deletion",
+		Summary:     "Cancel account deletion",
+		Description: "Cancels the pending deletion of a user's account, whoever requested it",
+		Tags:        []string{"Users / Deletion"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.CancelAccountDeletionInput) (*dto.AccountDeletionOutput, error) {
+		user, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		deletion, err := service.CancelAccountDeletion(ctx, input.UserID, requester(ctx, user.CharacterID))
+		if err != nil {
+			return nil, accountDeletionError(err)
+		}
+		return &dto.AccountDeletionOutput{Body: *deletion}, nil
+	})
+
+	// Preferences shared by the user's frontends
+	huma.Register(api, huma.Operation{
+		OperationID: "users-get-my-preferences",
+		Method:      "GET",
+		Path:        basePath + "/me/preferences",
+		Summary:     "Get my preferences",
+		Description: "Returns the caller's preferences; empty when none were saved",
+		Tags:        []string{"Users / Preferences"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.MyPreferencesInput) (*dto.PreferencesOutput, error) {
+		user, err := usersAdapter.RequireAuthenticated(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		preferences, err := service.GetPreferences(ctx, user.UserID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get preferences", err)
+		}
+		return &dto.PreferencesOutput{Body: *preferences}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-update-my-preferences",
+		Method:      "PUT",
+		Path:        basePath + "/me/preferences",
+		Summary:     "Update my preferences",
+		Description: "Sets the given preferences of the caller and removes those set to null; preferences left out are unchanged. Known keys are validated against their schema, and nothing is saved when any preference is invalid.",
+		Tags:        []string{"Users / Preferences"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UpdateMyPreferencesInput) (*dto.PreferencesOutput, error) {
+		user, err := usersAdapter.RequireAuthenticated(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		preferences, err := service.UpdatePreferences(ctx, user.UserID, input.Body.Preferences)
+		if err != nil {
+			var invalid *services.InvalidPreferencesError
+			if errors.As(err, &invalid) {
+				details := make([]error, 0, len(invalid.Problems))
+				for key, problem := range invalid.Problems {
+					details = append(details, &huma.ErrorDetail{
+						Location: "body.preferences." + key,
+						Message:  problem,
+						Value:    input.Body.Preferences[key],
+					})
+				}
+				return nil, huma.Error422UnprocessableEntity("Invalid preferences", details...)
+			}
+			return nil, huma.Error500InternalServerError("Failed to update preferences", err)
+		}
+		return &dto.PreferencesOutput{Body: *preferences}, nil
+	})
+
+	// Data export (right of access)
+	huma.Register(api, huma.Operation{
+		OperationID:   "users-request-my-export",
+		Method:        "POST",
+		Path:          basePath + "/me/export",
+		Summary:       "Export my data",
+		Description:   "Requests a ZIP archive of everything stored about the caller's account: character profiles, group memberships, notifications, Discord links and audit entries. The archive is built in the background; poll the export until it is completed, then download it. A request while an export is still being built returns that export.",
+		Tags:          []string{"Users / Data Export"},
+		DefaultStatus: http.StatusAccepted,
+		Security:      []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.MyDataExportInput) (*dto.DataExportOutput, error) {
+		user, err := usersAdapter.RequireAuthenticated(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if user.Impersonator != nil {
+			return nil, huma.Error403Forbidden("Not available while impersonating")
+		}
+
+		export, err := service.RequestDataExport(ctx, user.UserID, requester(ctx, user.CharacterID))
+		if err != nil {
+			return nil, dataExportError(err)
+		}
+		return &dto.DataExportOutput{Body: *export}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-get-my-export",
+		Method:      "GET",
+		Path:        basePath + "/me/export",
+		Summary:     "Get my data export",
+		Description: "Returns the caller's most recent data export with its status, and once completed its contents and expiry",
+		Tags:        []string{"Users / Data Export"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.MyDataExportInput) (*dto.DataExportOutput, error) {
+		user, err := usersAdapter.RequireAuthenticated(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		export, err := service.GetDataExport(ctx, user.UserID)
+		if err != nil {
+			return nil, dataExportError(err)
+		}
+		return &dto.DataExportOutput{Body: *export}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-download-my-export",
+		Method:      "GET",
+		Path:        basePath + "/me/export/download",
+		Summary:     "Download my data export",
+		Description: "Downloads the ZIP archive of the caller's completed data export",
+		Tags:        []string{"Users / Data Export"},
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.MyDataExportInput) (*dto.DataExportDownloadOutput, error) {
+		user, err := usersAdapter.RequireAuthenticated(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+		if user.Impersonator != nil {
+			return nil, huma.Error403Forbidden("Not available while impersonating")
+		}
+
+		export, data, err := service.DownloadDataExport(ctx, user.UserID)
+		if err != nil {
+			return nil, dataExportError(err)
+		}
+		return &dto.DataExportDownloadOutput{
+			ContentType:        "application/zip",
+			ContentDisposition: fmt.Sprintf(`attachment; filename="falcon-export-%s.zip"`, export.CompletedAt.Format("20060102-150405")),
+			CacheControl:       "private, no-store",
+			Body:               data,
+		}, nil
+	})
+
+	// Account merges
+	huma.Register(api, huma.Operation{
+		OperationID: "users-merge-accounts",
+		Method:      "POST",
+		Path:        basePath + "/merges",
+		Summary:     "Merge user accounts",
+		Description: "Merges a duplicate account into the surviving one in a single transaction: characters (and with them their group memberships), notification deliveries, group join requests, Discord links and scheduler task ownership move to the target account, and the sessions of the source account end. The returned merge record can be reverted.",
+		Tags:        []string{"Users / Merge"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.MergeAccountsInput) (*dto.AccountMergeOutput, error) {
+		user, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := stepup.Require(ctx, "users-merge-accounts"); err != nil {
+			return nil, err
+		}
+
+		merge, err := service.MergeAccounts(ctx, input.Body.SourceUserID, input.Body.TargetUserID, requester(ctx, user.CharacterID), input.Body.Reason)
+		if err != nil {
+			return nil, accountMergeError(err)
+		}
+		return &dto.AccountMergeOutput{Body: *merge}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-list-merges",
+		Method:      "GET",
+		Path:        basePath + "/merges",
+		Summary:     "List account merges",
+		Description: "Lists account merges newest first, optionally only those involving one user",
+		Tags:        []string{"Users / Merge"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.AccountMergeListInput) (*dto.AccountMergeListOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		merges, err := service.ListAccountMerges(ctx, input.UserID, input.Limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list account merges", err)
+		}
+		output := &dto.AccountMergeListOutput{}
+		output.Body.Merges = merges
+		output.Body.Count = len(merges)
+		return output, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-revert-merge",
+		Method:      "POST",
+		Path:        basePath + "/merges/{merge_id}/revert",
+		Summary:     "Revert account merge",
+		Description: "Moves everything a merge moved back to the source account. Characters and records that changed hands again since the merge are left where they are.",
+		Tags:        []string{"Users / Merge"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.RevertAccountMergeInput) (*dto.AccountMergeOutput, error) {
+		user, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := stepup.Require(ctx, "users-revert-merge"); err != nil {
+			return nil, err
+		}
+
+		mergeID, err := primitive.ObjectIDFromHex(input.MergeID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid merge ID")
+		}
+		merge, err := service.RevertAccountMerge(ctx, mergeID, requester(ctx, user.CharacterID))
+		if err != nil {
+			return nil, accountMergeError(err)
+		}
+		return &dto.AccountMergeOutput{Body: *merge}, nil
+	})
+
 	// Administrative endpoints require authentication and permissions
 
 	huma.Register(api, huma.Operation{
@@ -63,6 +572,7 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 		Summary:     "List users",
 		Description: "List and search users with pagination and filtering",
 		Tags:        []string{"Users / Management"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.UserListInput) (*dto.UserListOutput, error) {
 		// Validate authentication and user management access
@@ -78,6 +588,35 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 		return &dto.UserListOutput{Body: *response}, nil
 	})
 
+	huma.Register(api, huma.Operation{
+		OperationID: "users-search-users",
+		Method:      "GET",
+		Path:        basePath + "/search",
+		Summary:     "Search users",
+		Description: "Full-text search for the admin user browser. Matches the character ID, character names by prefix or word prefixes (\"jo do\" finds John Doe), and whole words in character, corporation and alliance names, tags and notes. Results are ordered by relevance and list the fields that matched.",
+		Tags:        []string{"Users / Management"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UserSearchInput) (*dto.UserSearchOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		results, err := service.SearchUsers(ctx, input.Query, input.Deleted, input.Limit)
+		if errors.Is(err, services.ErrEmptySearch) {
+			return nil, huma.Error400BadRequest("Search query is empty")
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to search users", err)
+		}
+		output := &dto.UserSearchOutput{}
+		output.Body.Query = input.Query
+		output.Body.Results = results
+		output.Body.Count = len(results)
+		return output, nil
+	})
+
 	huma.Register(api, huma.Operation{
 		OperationID: "users-get-user",
 		Method:      "GET",
@@ -85,6 +624,7 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 		Summary:     "Get user details",
 		Description: "Get detailed information about a specific user by character ID",
 		Tags:        []string{"Users / Management"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.UserGetInput) (*dto.UserGetOutput, error) {
 		// Validate authentication and user management access
@@ -112,6 +652,7 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 		Summary:     "Update user",
 		Description: "Update user status and settings",
 		Tags:        []string{"Users / Management"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.UserUpdateInput) (*dto.UserUpdateOutput, error) {
 		// Validate authentication and user management access
@@ -120,6 +661,13 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 			return nil, err
 		}
 
+		// Banning or unbanning needs a second factor
+		if input.Body.Banned != nil {
+			if err := stepup.RequireSecondFactor(ctx, "users-update-user"); err != nil {
+				return nil, err
+			}
+		}
+
 		user, err := service.UpdateUser(ctx, input.CharacterID, input.Body)
 		if err != nil {
 			return nil, huma.Error500InternalServerError("Failed to update user", err)
@@ -132,6 +680,134 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 		return &dto.UserUpdateOutput{Body: *userResponse}, nil
 	})
 
+	huma.Register(api, huma.Operation{
+		OperationID: "users-bulk-update",
+		Method:      "POST",
+		Path:        basePath + "/bulk",
+		Summary:     "Bulk update users",
+		Description: "Applies one operation (enable, disable, ban, unban, tag, untag) to up to 1000 characters, given by ID or selected with the user list filters. disable soft-deletes characters and enable restores them. Each character is reported as changed, unchanged (already in the requested state) or failed with the reason; a dry run reports would_change instead and changes nothing. Changes need step-up confirmation, bans a second factor.",
+		Tags:        []string{"Users / Management"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.BulkUserInput) (*dto.BulkUserOutput, error) {
+		user, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		if !input.Body.DryRun {
+			// Banning or unbanning needs a second factor, as for a single user
+			if input.Body.Operation == dto.BulkBan || input.Body.Operation == dto.BulkUnban {
+				err = stepup.RequireSecondFactor(ctx, "users-bulk-update")
+			} else {
+				err = stepup.Require(ctx, "users-bulk-update")
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		response, err := service.BulkUpdateUsers(ctx, input.Body, requester(ctx, user.CharacterID))
+		if err != nil {
+			return nil, bulkUserError(err)
+		}
+		return &dto.BulkUserOutput{Body: *response}, nil
+	})
+
+	// Administrative tags
+	huma.Register(api, huma.Operation{
+		OperationID: "users-add-user-tag",
+		Method:      "PUT",
+		Path:        basePath + "/mgt/{character_id}/tags/{tag}",
+		Summary:     "Tag user",
+		Description: "Attaches an administrative tag such as fc, suspicious or alt-of:123 to a character. Tags are stored lowercase; adding a tag twice changes nothing. The change is recorded in the users audit log.",
+		Tags:        []string{"Users / Tags"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UserTagInput) (*dto.UserGetOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		user, err := service.AddUserTag(ctx, input.CharacterID, input.Tag)
+		if err != nil {
+			return nil, userTagError(err)
+		}
+		return &dto.UserGetOutput{Body: *service.UserToResponse(user)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-remove-user-tag",
+		Method:      "DELETE",
+		Path:        basePath + "/mgt/{character_id}/tags/{tag}",
+		Summary:     "Untag user",
+		Description: "Removes an administrative tag from a character. The change is recorded in the users audit log.",
+		Tags:        []string{"Users / Tags"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UserTagInput) (*dto.UserGetOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		user, err := service.RemoveUserTag(ctx, input.CharacterID, input.Tag)
+		if err != nil {
+			return nil, userTagError(err)
+		}
+		return &dto.UserGetOutput{Body: *service.UserToResponse(user)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-list-tags",
+		Method:      "GET",
+		Path:        basePath + "/tags",
+		Summary:     "List user tags",
+		Description: "Lists every administrative tag in use with how many characters carry it, most used first",
+		Tags:        []string{"Users / Tags"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UserTagListInput) (*dto.UserTagListOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		tags, err := service.ListUserTags(ctx)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list user tags", err)
+		}
+		output := &dto.UserTagListOutput{}
+		output.Body.Tags = tags
+		return output, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-list-audit",
+		Method:      "GET",
+		Path:        basePath + "/audit",
+		Summary:     "List users audit log",
+		Description: "Lists administrative changes to users, such as tags added and removed, newest first",
+		Tags:        []string{"Users / Tags"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UserAuditListInput) (*dto.UserAuditListOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		events, err := service.ListAuditEvents(ctx, input.CharacterID, input.Action, input.Limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to list users audit events", err)
+		}
+		output := &dto.UserAuditListOutput{}
+		output.Body.Events = events
+		output.Body.Count = len(events)
+		return output, nil
+	})
+
 	// User character management with enriched profile data
 	huma.Register(api, huma.Operation{
 		OperationID: "users-get-user-characters",
@@ -169,26 +845,24 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 		Method:      "DELETE",
 		Path:        basePath + "/mgt/{character_id}",
 		Summary:     "Delete user character",
-		Description: "Delete a user character. Super administrators cannot be deleted.",
+		Description: "Soft-deletes a user character like DELETE /users/{character_id}, which also returns the deleted character. Super administrators cannot be deleted.",
 		Tags:        []string{"Users / Management"},
+		Deprecated:  true,
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
 		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
 	}, func(ctx context.Context, input *dto.UserDeleteInput) (*dto.UserDeleteOutput, error) {
 		// Validate authentication and user management access
-		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		user, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
 		if err != nil {
 			return nil, err
 		}
 
-		err = service.DeleteUser(ctx, input.CharacterID)
-		if err != nil {
-			// Check for specific error types
-			if err.Error() == "cannot delete super admin character" {
-				return nil, huma.Error403Forbidden("Cannot delete super administrator character")
-			}
-			if err.Error() == "user not found" || err.Error() == fmt.Sprintf("user not found for character ID %d", input.CharacterID) {
-				return nil, huma.Error404NotFound("User not found")
-			}
-			return nil, huma.Error500InternalServerError("Failed to delete user", err)
+		if err := stepup.Require(ctx, "users-delete-user-character"); err != nil {
+			return nil, err
+		}
+
+		if _, err := service.SoftDeleteUser(ctx, input.CharacterID, requester(ctx, user.CharacterID)); err != nil {
+			return nil, softDeleteError(err)
 		}
 
 		return &dto.UserDeleteOutput{
@@ -199,6 +873,58 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 		}, nil
 	})
 
+	huma.Register(api, huma.Operation{
+		OperationID: "users-soft-delete-user",
+		Method:      "DELETE",
+		Path:        basePath + "/{character_id}",
+		Summary:     "Soft-delete user character",
+		Description: "Marks a user character deleted: it is hidden from user and character lists, its sessions end and it can no longer log in. Its profile and group memberships are kept, so it can be restored until the purge task removes it after the retention period (USER_SOFT_DELETE_RETENTION_DAYS). Super administrators cannot be deleted.",
+		Tags:        []string{"Users / Management"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UserDeleteInput) (*dto.UserGetOutput, error) {
+		user, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := stepup.Require(ctx, "users-soft-delete-user"); err != nil {
+			return nil, err
+		}
+
+		deleted, err := service.SoftDeleteUser(ctx, input.CharacterID, requester(ctx, user.CharacterID))
+		if err != nil {
+			return nil, softDeleteError(err)
+		}
+		return &dto.UserGetOutput{Body: *service.UserToResponse(deleted)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "users-restore-user",
+		Method:      "POST",
+		Path:        basePath + "/{character_id}/restore",
+		Summary:     "Restore soft-deleted user character",
+		Description: "Reverses a soft deletion that has not been purged yet. The character can log in again; sessions ended by the deletion stay ended.",
+		Tags:        []string{"Users / Management"},
+		Extensions:  apidocs.RequiresPermission("users:management:full"),
+		Security:    []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}, func(ctx context.Context, input *dto.UserRestoreInput) (*dto.UserGetOutput, error) {
+		_, err := usersAdapter.RequireUserManagement(ctx, input.Authorization, input.Cookie)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := stepup.Require(ctx, "users-restore-user"); err != nil {
+			return nil, err
+		}
+
+		restored, err := service.RestoreUser(ctx, input.CharacterID)
+		if err != nil {
+			return nil, softDeleteError(err)
+		}
+		return &dto.UserGetOutput{Body: *service.UserToResponse(restored)}, nil
+	})
+
 	// Character reordering
 	huma.Register(api, huma.Operation{
 		OperationID: "users-reorder-user-characters",
@@ -224,21 +950,98 @@ func RegisterUsersRoutes(api huma.API, basePath string, service *services.Servic
 	})
 }
 
-// registerRoutes registers all Users module routes with Huma
-func (hr *Routes) registerRoutes() {
-	// Status endpoint (public, no auth required)
-	huma.Get(hr.api, "/status", hr.getStatus)
+// requester labels who made a request, as recorded on account deletions
+func requester(ctx context.Context, characterID int) string {
+	if id := identity.FromContext(ctx); id != nil {
+		return id.Label()
+	}
+	return fmt.Sprintf("character:%d", characterID)
+}
 
-	// Public endpoints
+// accountDeletionError maps account deletion errors to API errors
+func accountDeletionError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		return huma.Error404NotFound("User not found")
+	case errors.Is(err, services.ErrDeletionNotFound):
+		return huma.Error404NotFound("No pending account deletion")
+	case errors.Is(err, services.ErrDeletionSuperAdmin):
+		return huma.Error403Forbidden("Super administrator accounts cannot be deleted")
+	case errors.Is(err, services.ErrDeletionAlreadyRequested):
+		return huma.Error409Conflict("Account deletion already requested")
+	}
+	return huma.Error500InternalServerError("Failed to process account deletion", err)
+}
 
-	// Administrative endpoints (require authentication and permissions)
-	huma.Get(hr.api, "", hr.listUsers)
-	huma.Get(hr.api, "/mgt/{character_id}", hr.getUser)
-	huma.Put(hr.api, "/mgt/{character_id}", hr.updateUser)
-	huma.Delete(hr.api, "/mgt/{character_id}", hr.deleteUser)
+// userTagError maps user tag errors to API errors
+func userTagError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidTag):
+		return huma.Error400BadRequest("Tags are up to 64 lowercase letters, digits, and . _ : - characters")
+	case errors.Is(err, services.ErrUserNotFound):
+		return huma.Error404NotFound("User not found")
+	case errors.Is(err, services.ErrTooManyTags):
+		return huma.Error409Conflict("Character already carries the maximum number of tags")
+	}
+	return huma.Error500InternalServerError("Failed to update user tags", err)
+}
+
+// bulkUserError maps bulk user operation errors to API errors
+func bulkUserError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrBulkNoTargets), errors.Is(err, services.ErrBulkTagRequired):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, services.ErrInvalidTag):
+		return huma.Error400BadRequest("Tags are up to 64 lowercase letters, digits, and . _ : - characters")
+	case errors.Is(err, services.ErrBulkTooManyTargets):
+		return huma.Error400BadRequest("Filter selects more than 1000 characters; narrow it or list character IDs")
+	}
+	return huma.Error500InternalServerError("Failed to apply bulk user operation", err)
+}
 
-	// User character management
-	huma.Get(hr.api, "/{user_id}/characters", hr.getUserCharacters)
+// softDeleteError maps soft deletion and restore errors to API errors
+func softDeleteError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		return huma.Error404NotFound("User not found")
+	case errors.Is(err, services.ErrCannotDeleteSuperAdmin):
+		return huma.Error403Forbidden("Cannot delete super administrator character")
+	case errors.Is(err, services.ErrUserAlreadyDeleted):
+		return huma.Error409Conflict("User character is already deleted")
+	case errors.Is(err, services.ErrUserNotDeleted):
+		return huma.Error409Conflict("User character is not deleted")
+	}
+	return huma.Error500InternalServerError("Failed to update user deletion", err)
+}
+
+// dataExportError maps data export errors to API errors
+func dataExportError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrExportNotFound):
+		return huma.Error404NotFound("No data export")
+	case errors.Is(err, services.ErrExportNotReady):
+		return huma.Error409Conflict("Data export is not completed")
+	case errors.Is(err, services.ErrExportUnavailable):
+		return huma.Error503ServiceUnavailable("Data exports are not available")
+	}
+	return huma.Error500InternalServerError("Failed to process data export", err)
+}
+
+// accountMergeError maps account merge errors to API errors
+func accountMergeError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrMergeSameAccount):
+		return huma.Error400BadRequest("Cannot merge an account into itself")
+	case errors.Is(err, services.ErrUserNotFound):
+		return huma.Error404NotFound("User not found")
+	case errors.Is(err, services.ErrMergeNotFound):
+		return huma.Error404NotFound("Account merge not found")
+	case errors.Is(err, services.ErrMergeDeletionPending):
+		return huma.Error409Conflict("An account deletion is pending for one of the accounts")
+	case errors.Is(err, services.ErrMergeAlreadyReverted):
+		return huma.Error409Conflict("Account merge already reverted")
+	}
+	return huma.Error500InternalServerError("Failed to process account merge", err)
 }
 
 // Public endpoint handlers
@@ -305,10 +1108,10 @@ func (hr *Routes) deleteUser(ctx context.Context, input *dto.UserDeleteInput) (*
 	err := hr.service.DeleteUser(ctx, input.CharacterID)
 	if err != nil {
 		// Check for specific error types
-		if err.Error() == "cannot delete super admin character" {
+		if errors.Is(err, services.ErrCannotDeleteSuperAdmin) {
 			return nil, huma.Error403Forbidden("Cannot delete super administrator character")
 		}
-		if err.Error() == "user not found" || err.Error() == fmt.Sprintf("user not found for character ID %d", input.CharacterID) {
+		if errors.Is(err, services.ErrUserNotFound) {
 			return nil, huma.Error404NotFound("User not found")
 		}
 		return nil, huma.Error500InternalServerError("Failed to delete user", err)
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"go-falcon/internal/users/dto"
+	"go-falcon/internal/users/models"
+)
+
+var (
+	// ErrBulkNoTargets is returned for a bulk operation without character IDs or filter criteria
+	ErrBulkNoTargets = errors.New("give either character_ids or a filter with at least one criterion")
+	// ErrBulkTagRequired is returned for a tag or untag operation without a tag
+	ErrBulkTagRequired = errors.New("tag is required for tag and untag")
+	// ErrBulkTooManyTargets is returned when a filter selects more than maxBulkTargets characters
+	ErrBulkTooManyTargets = errors.New("filter selects too many characters")
+)
+
+// maxBulkTargets bounds how many characters one bulk operation changes
+const maxBulkTargets = 1000
+
+// BulkUpdateUsers applies one operation to a list of characters or to the characters matching a
+// filter, reporting the outcome for each. Characters already in the requested state are left
+// alone; one that cannot be changed fails without stopping the others. A dry run reports what
+// would change without changing anything.
+func (s *Service) BulkUpdateUsers(ctx context.Context, req dto.BulkUserRequest, requestedBy string) (*dto.BulkUserResponse, error) {
+	tag := ""
+	if req.Operation == dto.BulkTag || req.Operation == dto.BulkUntag {
+		if req.Tag == "" {
+			return nil, ErrBulkTagRequired
+		}
+		var err error
+		if tag, err = NormalizeTag(req.Tag); err != nil {
+			return nil, err
+		}
+	}
+
+	targets, err := s.bulkTargets(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.BulkUserResponse{
+		Operation: req.Operation,
+		Tag:       tag,
+		DryRun:    req.DryRun,
+		Matched:   len(targets),
+		Results:   make([]dto.BulkUserItemResult, 0, len(targets)),
+	}
+	superAdmins := make(map[string]bool)
+	for _, target := range targets {
+		result := dto.BulkUserItemResult{CharacterID: target.characterID}
+		if target.user == nil {
+			result.Status = dto.BulkFailed
+			result.Error = "user not found"
+			response.Failed++
+			response.Results = append(response.Results, result)
+			continue
+		}
+		result.CharacterName = target.user.CharacterName
+
+		change, err := s.bulkWouldChange(ctx, req.Operation, tag, target.user, superAdmins)
+		if err == nil && change && !req.DryRun {
+			err = s.bulkApply(ctx, req.Operation, tag, target.user, requestedBy)
+		}
+		switch {
+		case err != nil:
+			result.Status = dto.BulkFailed
+			result.Error = bulkItemError(ctx, target.characterID, err)
+			response.Failed++
+		case !change:
+			result.Status = dto.BulkUnchanged
+			response.Unchanged++
+		case req.DryRun:
+			result.Status = dto.BulkWouldChange
+			response.Changed++
+		default:
+			result.Status = dto.BulkChanged
+			response.Changed++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	if !req.DryRun {
+		slog.InfoContext(ctx, "Bulk updated users",
+			"operation", req.Operation,
+			"tag", tag,
+			"matched", response.Matched,
+			"changed", response.Changed,
+			"failed", response.Failed,
+			"requested_by", requestedBy)
+	}
+	return response, nil
+}
+
+// bulkTarget is a character a bulk operation applies to; user is nil when it is not registered
+type bulkTarget struct {
+	characterID int
+	user        *models.User
+}
+
+// bulkTargets resolves the characters of a bulk operation: the listed ones in request order, or
+// those matching the filter by character ID
+func (s *Service) bulkTargets(ctx context.Context, req dto.BulkUserRequest) ([]bulkTarget, error) {
+	if len(req.CharacterIDs) > 0 {
+		if req.Filter != nil {
+			return nil, ErrBulkNoTargets
+		}
+		characterIDs := slices.Clone(req.CharacterIDs)
+		slices.Sort(characterIDs)
+		characterIDs = slices.Compact(characterIDs)
+
+		users, err := s.repository.FindUsersByCharacterIDs(ctx, characterIDs)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[int]*models.User, len(users))
+		for _, user := range users {
+			byID[user.CharacterID] = user
+		}
+
+		targets := make([]bulkTarget, 0, len(characterIDs))
+		seen := make(map[int]bool, len(characterIDs))
+		for _, characterID := range req.CharacterIDs {
+			if !seen[characterID] {
+				seen[characterID] = true
+				targets = append(targets, bulkTarget{characterID: characterID, user: byID[characterID]})
+			}
+		}
+		return targets, nil
+	}
+
+	if req.Filter == nil || req.Filter.IsEmpty() {
+		return nil, ErrBulkNoTargets
+	}
+	users, err := s.repository.FindUsersMatching(ctx, *req.Filter, maxBulkTargets+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) > maxBulkTargets {
+		return nil, ErrBulkTooManyTargets
+	}
+	targets := make([]bulkTarget, 0, len(users))
+	for _, user := range users {
+		targets = append(targets, bulkTarget{characterID: user.CharacterID, user: user})
+	}
+	return targets, nil
+}
+
+// bulkWouldChange reports whether an operation changes a character, or why it cannot. Super
+// administrator lookups are cached in superAdmins by user ID for the run.
+func (s *Service) bulkWouldChange(ctx context.Context, operation, tag string, user *models.User, superAdmins map[string]bool) (bool, error) {
+	switch operation {
+	case dto.BulkBan:
+		return !user.Banned, nil
+	case dto.BulkUnban:
+		return user.Banned, nil
+	case dto.BulkEnable:
+		return user.DeletedAt != nil, nil
+	case dto.BulkDisable:
+		if user.DeletedAt != nil {
+			return false, nil
+		}
+		if s.groupService == nil {
+			return true, nil
+		}
+		isSuperAdmin, cached := superAdmins[user.UserID]
+		if !cached {
+			var err error
+			if isSuperAdmin, err = s.groupService.IsUserInGroup(ctx, user.UserID, "Super Administrator"); err != nil {
+				return false, err
+			}
+			superAdmins[user.UserID] = isSuperAdmin
+		}
+		if isSuperAdmin {
+			return false, ErrCannotDeleteSuperAdmin
+		}
+		return true, nil
+	case dto.BulkTag:
+		if slices.Contains(user.Tags, tag) {
+			return false, nil
+		}
+		if len(user.Tags) >= maxTagsPerCharacter {
+			return false, ErrTooManyTags
+		}
+		return true, nil
+	case dto.BulkUntag:
+		return slices.Contains(user.Tags, tag), nil
+	}
+	return false, nil
+}
+
+// bulkApply changes one character through the same service calls as the single-user endpoints,
+// so soft deletions and tag changes are audited as usual
+func (s *Service) bulkApply(ctx context.Context, operation, tag string, user *models.User, requestedBy string) error {
+	var err error
+	switch operation {
+	case dto.BulkBan, dto.BulkUnban:
+		banned := operation == dto.BulkBan
+		_, err = s.repository.UpdateUser(ctx, user.CharacterID, dto.UserUpdateRequest{Banned: &banned})
+	case dto.BulkEnable:
+		_, err = s.RestoreUser(ctx, user.CharacterID)
+	case dto.BulkDisable:
+		_, err = s.SoftDeleteUser(ctx, user.CharacterID, requestedBy)
+	case dto.BulkTag:
+		_, err = s.AddUserTag(ctx, user.CharacterID, tag)
+	case dto.BulkUntag:
+		_, err = s.RemoveUserTag(ctx, user.CharacterID, tag)
+	}
+	return err
+}
+
+// bulkItemError returns the reason reported for a character that could not be changed. Expected
+// refusals are passed on; anything else is logged and reported generically.
+func bulkItemError(ctx context.Context, characterID int, err error) string {
+	for _, known := range []error{ErrCannotDeleteSuperAdmin, ErrTooManyTags, ErrUserAlreadyDeleted, ErrUserNotDeleted, ErrUserNotFound} {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	slog.WarnContext(ctx, "Failed to apply bulk user operation", "character_id", characterID, "error", err)
+	return "internal error"
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-falcon/internal/users/dto"
+	"go-falcon/internal/users/models"
+	"go-falcon/pkg/config"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrDeletionSuperAdmin is returned when deleting the account of a super administrator
+	ErrDeletionSuperAdmin = errors.New("super administrator accounts cannot be deleted")
+	// ErrDeletionAlreadyRequested is returned when the account already has a deletion pending
+	ErrDeletionAlreadyRequested = errors.New("account deletion already requested")
+	// ErrDeletionNotFound is returned when the account has no pending deletion
+	ErrDeletionNotFound = errors.New("no pending account deletion")
+)
+
+const (
+	// deletionsPerRun bounds how many accounts one run of the erasure task erases
+	deletionsPerRun = 50
+	// deletionStaleAfter is when a running erasure is considered abandoned and claimed again
+	deletionStaleAfter = 30 * time.Minute
+)
+
+// AccountEraser removes what the auth module keeps about an account; implemented by the auth service
+type AccountEraser interface {
+	EraseUser(ctx context.Context, userID string) (int, int64, error)
+}
+
+// SetAccountEraser sets how sessions and audit events of erased accounts are removed
+func (s *Service) SetAccountEraser(eraser AccountEraser) {
+	s.accountEraser = eraser
+}
+
+// RequestAccountDeletion schedules the erasure of a user account after the grace period, or on the
+// next run of the erasure task when immediate. An immediate request also brings forward a deletion
+// that is already pending.
+func (s *Service) RequestAccountDeletion(ctx context.Context, userID, requestedBy, reason string, immediate bool) (*models.AccountDeletion, error) {
+	characters, err := s.repository.ListCharacters(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(characters) == 0 {
+		return nil, ErrUserNotFound
+	}
+	if s.groupService != nil {
+		isSuperAdmin, err := s.groupService.IsUserInGroup(ctx, userID, "Super Administrator")
+		if err != nil {
+			return nil, fmt.Errorf("failed to check super admin status: %w", err)
+		}
+		if isSuperAdmin {
+			return nil, ErrDeletionSuperAdmin
+		}
+	}
+
+	now := time.Now().UTC()
+	scheduledFor := now.AddDate(0, 0, config.GetUserDeletionGraceDays())
+	if immediate {
+		scheduledFor = now
+	}
+
+	existing, err := s.repository.GetOpenAccountDeletion(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if !immediate || existing.Status != models.DeletionPending {
+			return nil, ErrDeletionAlreadyRequested
+		}
+		// An administrator overrides the grace period of the user's own request
+		if _, err := s.repository.CancelAccountDeletion(ctx, userID, requestedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	deletion := &models.AccountDeletion{
+		UserID:       userID,
+		Status:       models.DeletionPending,
+		RequestedBy:  requestedBy,
+		Reason:       reason,
+		ScheduledFor: scheduledFor,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	for _, character := range characters {
+		deletion.CharacterIDs = append(deletion.CharacterIDs, character.CharacterID)
+	}
+	if err := s.repository.InsertAccountDeletion(ctx, deletion); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Account deletion requested",
+		"user_id", userID,
+		"requested_by", requestedBy,
+		"scheduled_for", scheduledFor)
+	return deletion, nil
+}
+
+// GetAccountDeletion returns the pending or running deletion of a user account
+func (s *Service) GetAccountDeletion(ctx context.Context, userID string) (*models.AccountDeletion, error) {
+	deletion, err := s.repository.GetOpenAccountDeletion(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if deletion == nil {
+		return nil, ErrDeletionNotFound
+	}
+	return deletion, nil
+}
+
+// CancelAccountDeletion cancels the pending deletion of a user account; once the erasure has
+// started it can no longer be cancelled
+func (s *Service) CancelAccountDeletion(ctx context.Context, userID, cancelledBy string) (*models.AccountDeletion, error) {
+	deletion, err := s.repository.CancelAccountDeletion(ctx, userID, cancelledBy)
+	if err != nil {
+		return nil, err
+	}
+	if deletion == nil {
+		return nil, ErrDeletionNotFound
+	}
+
+	slog.InfoContext(ctx, "Account deletion cancelled", "user_id", userID, "cancelled_by", cancelledBy)
+	return deletion, nil
+}
+
+// ListAccountDeletions returns the most recent account deletions, with the receipts of completed ones
+func (s *Service) ListAccountDeletions(ctx context.Context, status string, limit int) ([]*models.AccountDeletion, error) {
+	return s.repository.ListAccountDeletions(ctx, status, limit)
+}
+
+// ProcessAccountDeletions erases the accounts whose deletion is due (used by the scheduler). A
+// failed erasure is retried on the next run; every step can safely run again.
+func (s *Service) ProcessAccountDeletions(ctx context.Context) (*dto.AccountDeletionRunResult, error) {
+	result := &dto.AccountDeletionRunResult{}
+
+	for i := 0; i < deletionsPerRun; i++ {
+		deletion, err := s.repository.ClaimDueAccountDeletion(ctx, time.Now().UTC(), deletionStaleAfter)
+		if err != nil {
+			return result, err
+		}
+		if deletion == nil {
+			break
+		}
+
+		receipt, err := s.eraseAccount(ctx, deletion)
+		if err == nil {
+			err = s.repository.CompleteAccountDeletion(ctx, deletion.ID, receipt)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to erase account", "user_id", deletion.UserID, "attempt", deletion.Attempts, "error", err)
+			if retryErr := s.repository.RetryAccountDeletion(ctx, deletion.ID, err.Error()); retryErr != nil {
+				slog.WarnContext(ctx, "Failed to reschedule account deletion", "user_id", deletion.UserID, "error", retryErr)
+			}
+			result.Failed++
+			continue
+		}
+
+		slog.InfoContext(ctx, "Erased account",
+			"user_id", deletion.UserID,
+			"receipt_id", receipt.ReceiptID,
+			"characters", receipt.Characters)
+		result.Erased++
+	}
+
+	if result.Failed > 0 {
+		return result, fmt.Errorf("account erasure failed for %d users", result.Failed)
+	}
+	return result, nil
+}
+
+// eraseAccount revokes the sessions and EVE tokens of an account, anonymizes its audit events,
+// removes its characters from their groups, purges its notifications, preferences and data exports
+// and finally deletes its profiles
+func (s *Service) eraseAccount(ctx context.Context, deletion *models.AccountDeletion) (*models.DeletionReceipt, error) {
+	if s.accountEraser == nil {
+		return nil, fmt.Errorf("account eraser not configured")
+	}
+
+	// Characters linked since the request are erased as well, and so are soft-deleted ones
+	characterIDs := deletion.CharacterIDs
+	characters, err := s.repository.ListCharacters(ctx, deletion.UserID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.repository.ListDeletedCharacterIDs(ctx, deletion.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, character := range characters {
+		current = append(current, character.CharacterID)
+	}
+	known := make(map[int]bool, len(characterIDs))
+	for _, characterID := range characterIDs {
+		known[characterID] = true
+	}
+	for _, characterID := range current {
+		if !known[characterID] {
+			known[characterID] = true
+			characterIDs = append(characterIDs, characterID)
+		}
+	}
+
+	receipt := &models.DeletionReceipt{
+		ReceiptID:  uuid.New().String(),
+		Characters: len(characterIDs),
+	}
+
+	if err := s.repository.RevokeUserTokens(ctx, deletion.UserID); err != nil {
+		return nil, err
+	}
+	receipt.SessionsRevoked, receipt.AuditEventsAnonymized, err = s.accountEraser.EraseUser(ctx, deletion.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase sessions: %w", err)
+	}
+
+	if s.groupService != nil {
+		for _, characterID := range characterIDs {
+			if err := s.groupService.RemoveCharacterFromAllGroups(ctx, int64(characterID)); err != nil {
+				return nil, fmt.Errorf("failed to remove character %d from groups: %w", characterID, err)
+			}
+		}
+	}
+
+	if receipt.NotificationsPurged, err = s.repository.DeleteNotificationDeliveries(ctx, deletion.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.repository.DeleteInactiveUser(ctx, deletion.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repository.DeleteInactivityExemption(ctx, deletion.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.deleteDataExports(ctx, deletion.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.repository.DeleteUserPreferences(ctx, deletion.UserID); err != nil {
+		return nil, err
+	}
+
+	if receipt.ProfilesErased, err = s.repository.DeleteUserProfiles(ctx, deletion.UserID); err != nil {
+		return nil, err
+	}
+
+	receipt.CompletedAt = time.Now().UTC()
+	return receipt, nil
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"go-falcon/internal/users/dto"
+	"go-falcon/internal/users/models"
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/identity"
+	"go-falcon/pkg/storage"
+)
+
+var (
+	// ErrExportNotFound is returned when the user never requested a data export
+	ErrExportNotFound = errors.New("no data export")
+	// ErrExportNotReady is returned when downloading an export that has not been built
+	ErrExportNotReady = errors.New("data export not ready")
+	// ErrExportUnavailable is returned when no store for export archives is configured
+	ErrExportUnavailable = errors.New("data exports are not available")
+)
+
+const (
+	// exportsPerRun bounds how many archives one run of the export task builds
+	exportsPerRun = 20
+	// exportStaleAfter is when a running export is considered abandoned and claimed again
+	exportStaleAfter = 30 * time.Minute
+	// exportMaxAttempts is how often building an export is tried before it is marked failed
+	exportMaxAttempts = 3
+)
+
+// SetExportStore sets where the archives of data exports are kept
+func (s *Service) SetExportStore(store storage.Store) {
+	s.exportStore = store
+}
+
+// RequestDataExport requests a copy of everything stored about a user account. The archive is
+// built in the background right away; the export task picks up what a stopped replica left. A
+// request while an export is pending or running returns that export.
+func (s *Service) RequestDataExport(ctx context.Context, userID, requestedBy string) (*models.DataExport, error) {
+	if s.exportStore == nil {
+		return nil, ErrExportUnavailable
+	}
+
+	existing, err := s.repository.GetOpenDataExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	now := time.Now().UTC()
+	export := &models.DataExport{
+		UserID:      userID,
+		Status:      models.ExportPending,
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repository.InsertDataExport(ctx, export); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Data export requested", "user_id", userID, "requested_by", requestedBy)
+
+	go func(ctx context.Context) {
+		if _, err := s.ProcessDataExports(ctx); err != nil {
+			slog.WarnContext(ctx, "Failed to build data exports", "error", err)
+		}
+	}(identity.Detach(ctx))
+	return export, nil
+}
+
+// GetDataExport returns the most recent data export of a user
+func (s *Service) GetDataExport(ctx context.Context, userID string) (*models.DataExport, error) {
+	export, err := s.repository.GetLatestDataExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil {
+		return nil, ErrExportNotFound
+	}
+	return export, nil
+}
+
+// DownloadDataExport returns the most recent data export of a user with its archive
+func (s *Service) DownloadDataExport(ctx context.Context, userID string) (*models.DataExport, []byte, error) {
+	if s.exportStore == nil {
+		return nil, nil, ErrExportUnavailable
+	}
+	export, err := s.GetDataExport(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export.Status != models.ExportCompleted {
+		return nil, nil, ErrExportNotReady
+	}
+
+	reader, err := s.exportStore.Open(ctx, export.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, ErrExportNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to open data export: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read data export: %w", err)
+	}
+	return export, data, nil
+}
+
+// ProcessDataExports deletes expired export archives and builds the archives of waiting exports
+// (used by the scheduler). A failed export is retried on the next run, up to exportMaxAttempts.
+func (s *Service) ProcessDataExports(ctx context.Context) (*dto.DataExportRunResult, error) {
+	result := &dto.DataExportRunResult{}
+	if s.exportStore == nil {
+		return result, ErrExportUnavailable
+	}
+
+	expired, err := s.repository.ListExpiredDataExports(ctx, time.Now().UTC())
+	if err != nil {
+		return result, err
+	}
+	for _, export := range expired {
+		if err := s.exportStore.Delete(ctx, export.StorageKey); err != nil {
+			slog.WarnContext(ctx, "Failed to delete data export archive", "export_id", export.ID.Hex(), "error", err)
+			continue
+		}
+		if err := s.repository.DeleteDataExport(ctx, export.ID); err != nil {
+			return result, err
+		}
+		result.Expired++
+	}
+
+	for i := 0; i < exportsPerRun; i++ {
+		export, err := s.repository.ClaimDataExport(ctx, time.Now().UTC(), exportStaleAfter)
+		if err != nil {
+			return result, err
+		}
+		if export == nil {
+			break
+		}
+
+		if err := s.buildDataExport(ctx, export); err != nil {
+			retry := export.Attempts < exportMaxAttempts
+			slog.WarnContext(ctx, "Failed to build data export",
+				"user_id", export.UserID, "attempt", export.Attempts, "retry", retry, "error", err)
+			if failErr := s.repository.FailDataExport(ctx, export.ID, err.Error(), retry); failErr != nil {
+				slog.WarnContext(ctx, "Failed to record data export failure", "user_id", export.UserID, "error", failErr)
+			}
+			result.Failed++
+			continue
+		}
+
+		slog.InfoContext(ctx, "Built data export",
+			"user_id", export.UserID,
+			"export_id", export.ID.Hex(),
+			"size_bytes", export.SizeBytes)
+		result.Exported++
+	}
+
+	if result.Failed > 0 {
+		return result, fmt.Errorf("data export failed for %d users", result.Failed)
+	}
+	return result, nil
+}
+
+// buildDataExport writes the archive of an export to the store and marks the export completed.
+// The archive is streamed into the store as it is written.
+func (s *Service) buildDataExport(ctx context.Context, export *models.DataExport) error {
+	key := fmt.Sprintf("%s/%s.zip", export.UserID, export.ID.Hex())
+	// A stopped replica may have left part of the archive behind
+	if err := s.exportStore.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to clear data export archive: %w", err)
+	}
+
+	reader, writer := io.Pipe()
+	written := make(chan error, 1)
+	var contents *models.DataExportContents
+	go func() {
+		var err error
+		contents, err = s.writeDataExport(ctx, writer, export)
+		writer.CloseWithError(err)
+		written <- err
+	}()
+
+	counter := &countingReader{reader: reader}
+	putErr := s.exportStore.Put(ctx, key, counter, "application/zip")
+	reader.Close()
+	writeErr := <-written
+	if putErr != nil {
+		return fmt.Errorf("failed to store data export archive: %w", putErr)
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.AddDate(0, 0, config.GetUserDataExportRetentionDays())
+	export.Status = models.ExportCompleted
+	export.StorageKey = key
+	export.SizeBytes = counter.read
+	export.Contents = contents
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	export.UpdatedAt = now
+	return s.repository.CompleteDataExport(ctx, export)
+}
+
+// deleteDataExports deletes every data export of a user with its archive
+func (s *Service) deleteDataExports(ctx context.Context, userID string) error {
+	exports, err := s.repository.ListDataExports(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if export.StorageKey != "" && s.exportStore != nil {
+			if err := s.exportStore.Delete(ctx, export.StorageKey); err != nil {
+				return fmt.Errorf("failed to delete data export archive: %w", err)
+			}
+		}
+		if err := s.repository.DeleteDataExport(ctx, export.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDataExport writes the ZIP archive of an export: one JSON file per kind of record and an
+// export.json manifest counting them
+func (s *Service) writeDataExport(ctx context.Context, w io.Writer, export *models.DataExport) (*models.DataExportContents, error) {
+	archive := zip.NewWriter(w)
+	contents := &models.DataExportContents{}
+
+	profiles, err := s.repository.ListUserProfiles(ctx, export.UserID)
+	if err != nil {
+		return nil, err
+	}
+	characterIDs := make([]int, 0, len(profiles))
+	for _, profile := range profiles {
+		characterIDs = append(characterIDs, profile.CharacterID)
+	}
+	contents.Characters = len(profiles)
+	if err := writeExportFile(archive, "characters.json", profiles); err != nil {
+		return nil, err
+	}
+
+	preferences, err := s.GetPreferences(ctx, export.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeExportFile(archive, "preferences.json", preferences.Preferences); err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.repository.listExportedMemberships(ctx, characterIDs)
+	if err != nil {
+		return nil, err
+	}
+	contents.GroupMemberships = len(memberships)
+	if err := writeExportFile(archive, "group_memberships.json", memberships); err != nil {
+		return nil, err
+	}
+
+	notifications, err := s.repository.listExportedNotifications(ctx, export.UserID)
+	if err != nil {
+		return nil, err
+	}
+	contents.Notifications = len(notifications)
+	if err := writeExportFile(archive, "notifications.json", notifications); err != nil {
+		return nil, err
+	}
+
+	discordLinks, err := s.repository.listExportedDiscordLinks(ctx, export.UserID)
+	if err != nil {
+		return nil, err
+	}
+	contents.DiscordLinks = len(discordLinks)
+	if err := writeExportFile(archive, "discord.json", discordLinks); err != nil {
+		return nil, err
+	}
+
+	authEvents, err := s.repository.listAuthAuditEvents(ctx, export.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeExportFile(archive, "audit/auth.json", authEvents); err != nil {
+		return nil, err
+	}
+	groupEvents, err := s.repository.listGroupAuditEvents(ctx, export.UserID, characterIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeExportFile(archive, "audit/groups.json", groupEvents); err != nil {
+		return nil, err
+	}
+	contents.AuditEntries = len(authEvents) + len(groupEvents)
+
+	manifest := map[string]any{
+		"export_id":    export.ID.Hex(),
+		"user_id":      export.UserID,
+		"generated_at": time.Now().UTC(),
+		"contents":     contents,
+	}
+	if err := writeExportFile(archive, "export.json", manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish data export archive: %w", err)
+	}
+	return contents, nil
+}
+
+// writeExportFile adds a file with the indented JSON of v to the archive
+func writeExportFile(archive *zip.Writer, name string, v any) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to data export: %w", name, err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s to data export: %w", name, err)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
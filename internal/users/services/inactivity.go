@@ -0,0 +1,416 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	authModels "go-falcon/internal/auth/models"
+	notificationsDto "go-falcon/internal/notifications/dto"
+	notificationModels "go-falcon/internal/notifications/models"
+	"go-falcon/internal/users/dto"
+	"go-falcon/internal/users/models"
+	"go-falcon/pkg/config"
+)
+
+// onlineScope lets the policy read a character's in-game last login from ESI
+const onlineScope = "esi-location.read_online.v1"
+
+var (
+	// ErrInactivityExemptionNotFound is returned when removing an exemption that does not exist
+	ErrInactivityExemptionNotFound = errors.New("inactivity exemption not found")
+)
+
+// Notifier sends notifications to users; implemented by the notifications service
+type Notifier interface {
+	Send(ctx context.Context, sender *authModels.AuthenticatedUser, input *notificationsDto.SendNotificationInput) (*notificationModels.Operation, error)
+}
+
+// inactivitySender is shown as the sender of inactivity notices
+var inactivitySender = &authModels.AuthenticatedUser{UserID: "system", CharacterName: "Account Maintenance"}
+
+// SetNotifier sets where inactivity notices are sent
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+func loadInactivityPolicy() dto.InactivityPolicy {
+	return dto.InactivityPolicy{
+		Enforced:       config.GetUserInactivityEnforce(),
+		InactiveMonths: config.GetUserInactivityMonths(),
+		NoticeDays:     config.GetUserInactivityNoticeDays(),
+		PurgeDays:      config.GetUserInactivityPurgeDays(),
+	}
+}
+
+// EnforceInactivityPolicy flags users without an SSO or in-game login for the configured number of
+// months and, when the policy is enforced, moves each of them one step further: notify, disable
+// after the notice period, purge after the retention period. Exempted users and super
+// administrators are never touched, and users who became active again are no longer tracked.
+func (s *Service) EnforceInactivityPolicy(ctx context.Context) (*dto.InactivityRunResult, error) {
+	policy := loadInactivityPolicy()
+	if policy.InactiveMonths <= 0 {
+		return nil, fmt.Errorf("USER_INACTIVITY_MONTHS must be positive, got %d", policy.InactiveMonths)
+	}
+
+	now := time.Now().UTC()
+	cutoff := now.AddDate(0, -policy.InactiveMonths, 0)
+
+	exempt, err := s.exemptUserIDs(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	tracked, err := s.repository.ListInactiveUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string]*models.InactiveUser, len(tracked))
+	for _, record := range tracked {
+		records[record.UserID] = record
+	}
+
+	candidates, err := s.repository.ListUsersLastLoginBefore(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.InactivityRunResult{Enforced: policy.Enforced}
+	stillInactive := make(map[string]bool, len(candidates))
+
+	for _, candidate := range candidates {
+		if exempt[candidate.UserID] || s.isSuperAdmin(ctx, candidate.UserID) {
+			continue
+		}
+
+		lastInGame := s.lastInGameLogin(ctx, candidate.Characters, now)
+		if lastInGame != nil && !lastInGame.Before(cutoff) {
+			continue
+		}
+		stillInactive[candidate.UserID] = true
+
+		record, ok := records[candidate.UserID]
+		if !ok {
+			record = &models.InactiveUser{
+				UserID:    candidate.UserID,
+				Stage:     models.InactivityFlagged,
+				FlaggedAt: now,
+			}
+			result.Flagged++
+		}
+		record.CharacterIDs = record.CharacterIDs[:0]
+		record.CharacterNames = record.CharacterNames[:0]
+		for _, character := range candidate.Characters {
+			record.CharacterIDs = append(record.CharacterIDs, character.CharacterID)
+			record.CharacterNames = append(record.CharacterNames, character.CharacterName)
+		}
+		record.LastLogin = candidate.LastLogin
+		if lastInGame != nil {
+			record.LastESIActive = lastInGame
+		}
+		record.UpdatedAt = now
+
+		purged := false
+		if policy.Enforced {
+			purged, err = s.advanceInactiveUser(ctx, record, policy, now, result)
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to apply inactivity policy", "user_id", record.UserID, "stage", record.Stage, "error", err)
+				result.Failed++
+			}
+		}
+		if purged {
+			continue
+		}
+		if err := s.repository.SaveInactiveUser(ctx, record); err != nil {
+			slog.WarnContext(ctx, "Failed to save inactivity record", "user_id", record.UserID, "error", err)
+			result.Failed++
+		}
+	}
+
+	// Users who logged in again, were exempted or no longer exist
+	for userID := range records {
+		if stillInactive[userID] {
+			continue
+		}
+		if err := s.repository.DeleteInactiveUser(ctx, userID); err != nil {
+			slog.WarnContext(ctx, "Failed to clear inactivity record", "user_id", userID, "error", err)
+			result.Failed++
+			continue
+		}
+		result.Cleared++
+	}
+
+	slog.InfoContext(ctx, "Inactivity policy applied",
+		"enforced", result.Enforced,
+		"flagged", result.Flagged,
+		"notified", result.Notified,
+		"disabled", result.Disabled,
+		"purged", result.Purged,
+		"cleared", result.Cleared,
+		"failed", result.Failed)
+
+	if result.Failed > 0 {
+		return result, fmt.Errorf("inactivity policy failed for %d users", result.Failed)
+	}
+	return result, nil
+}
+
+// advanceInactiveUser moves a user at most one step through the policy, reporting whether the
+// user was purged
+func (s *Service) advanceInactiveUser(ctx context.Context, record *models.InactiveUser, policy dto.InactivityPolicy, now time.Time, result *dto.InactivityRunResult) (bool, error) {
+	switch record.Stage {
+	case models.InactivityFlagged:
+		// Nobody is disabled without having been told first
+		if s.notifier == nil {
+			return false, fmt.Errorf("notifier not configured")
+		}
+		disableAt := now.AddDate(0, 0, policy.NoticeDays)
+		message := fmt.Sprintf("None of your characters has logged in for %d months. Log in before %s to keep your account; otherwise it will be disabled.",
+			policy.InactiveMonths, disableAt.Format("2006-01-02"))
+		if err := s.sendInactivityNotice(ctx, record.UserID, "Inactive account", message); err != nil {
+			return false, err
+		}
+		record.Stage = models.InactivityNotified
+		record.NotifiedAt = &now
+		record.DisableAt = &disableAt
+		result.Notified++
+
+	case models.InactivityNotified:
+		if record.DisableAt != nil && now.Before(*record.DisableAt) {
+			return false, nil
+		}
+		if err := s.repository.RevokeUserTokens(ctx, record.UserID); err != nil {
+			return false, err
+		}
+		record.Stage = models.InactivityDisabled
+		record.DisabledAt = &now
+		record.PurgeAt = nil
+		message := "Your account has been disabled after a long period of inactivity. Log in again to re-enable it."
+		if policy.PurgeDays > 0 {
+			purgeAt := now.AddDate(0, 0, policy.PurgeDays)
+			record.PurgeAt = &purgeAt
+			message = fmt.Sprintf("Your account has been disabled after a long period of inactivity and will be deleted on %s. Log in again before then to re-enable it.",
+				purgeAt.Format("2006-01-02"))
+		}
+		result.Disabled++
+		// Informational only; the account is disabled either way
+		if s.notifier != nil {
+			if err := s.sendInactivityNotice(ctx, record.UserID, "Account disabled", message); err != nil {
+				slog.WarnContext(ctx, "Failed to send account disabled notice", "user_id", record.UserID, "error", err)
+			}
+		}
+
+	case models.InactivityDisabled:
+		if policy.PurgeDays <= 0 {
+			return false, nil
+		}
+		// Retention was switched on after the user was disabled
+		if record.PurgeAt == nil && record.DisabledAt != nil {
+			purgeAt := record.DisabledAt.AddDate(0, 0, policy.PurgeDays)
+			record.PurgeAt = &purgeAt
+		}
+		if record.PurgeAt == nil || now.Before(*record.PurgeAt) {
+			return false, nil
+		}
+		if err := s.purgeInactiveUser(ctx, record); err != nil {
+			return false, err
+		}
+		result.Purged++
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// purgeInactiveUser deletes every character of the user and then the inactivity record itself
+func (s *Service) purgeInactiveUser(ctx context.Context, record *models.InactiveUser) error {
+	for _, characterID := range record.CharacterIDs {
+		if err := s.DeleteUser(ctx, characterID); err != nil && !errors.Is(err, ErrUserNotFound) {
+			return fmt.Errorf("failed to purge character %d: %w", characterID, err)
+		}
+	}
+	if err := s.repository.DeleteInactiveUser(ctx, record.UserID); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Purged inactive user",
+		"user_id", record.UserID,
+		"character_ids", record.CharacterIDs,
+		"last_login", record.LastLogin)
+	return nil
+}
+
+func (s *Service) sendInactivityNotice(ctx context.Context, userID, title, message string) error {
+	input := &notificationsDto.SendNotificationInput{}
+	input.Body.Title = title
+	input.Body.Message = message
+	input.Body.Level = "warning"
+	input.Body.Audience = []notificationsDto.AudienceSelectorInput{{Type: "users", UserIDs: []string{userID}}}
+
+	if _, err := s.notifier.Send(ctx, inactivitySender, input); err != nil {
+		return fmt.Errorf("failed to send inactivity notice: %w", err)
+	}
+	return nil
+}
+
+// lastInGameLogin returns the latest in-game login ESI reports for any of the characters. Only
+// characters with the online scope and a current token are asked; failures count as no activity.
+func (s *Service) lastInGameLogin(ctx context.Context, characters []ActivityCharacter, now time.Time) *time.Time {
+	if s.eveGateway == nil {
+		return nil
+	}
+
+	var latest *time.Time
+	for _, character := range characters {
+		if character.AccessToken == "" || !character.TokenExpiry.After(now) || !strings.Contains(character.Scopes, onlineScope) {
+			continue
+		}
+
+		online, err := s.eveGateway.Character.GetCharacterOnline(ctx, character.CharacterID, character.AccessToken)
+		if err != nil {
+			slog.DebugContext(ctx, "Failed to get character online status", "character_id", character.CharacterID, "error", err)
+			continue
+		}
+		value, _ := online["last_login"].(string)
+		lastLogin, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			continue
+		}
+		if latest == nil || lastLogin.After(*latest) {
+			latest = &lastLogin
+		}
+	}
+	return latest
+}
+
+func (s *Service) isSuperAdmin(ctx context.Context, userID string) bool {
+	if s.groupService == nil {
+		return false
+	}
+	isSuperAdmin, err := s.groupService.IsUserInGroup(ctx, userID, "Super Administrator")
+	if err != nil {
+		// Err on the side of keeping the account
+		slog.WarnContext(ctx, "Failed to check super admin status for inactivity policy", "user_id", userID, "error", err)
+		return true
+	}
+	return isSuperAdmin
+}
+
+func (s *Service) exemptUserIDs(ctx context.Context, now time.Time) (map[string]bool, error) {
+	exemptions, err := s.repository.ListInactivityExemptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exempt := make(map[string]bool, len(exemptions))
+	for _, exemption := range exemptions {
+		if exemption.ExpiresAt == nil || exemption.ExpiresAt.After(now) {
+			exempt[exemption.UserID] = true
+		}
+	}
+	return exempt, nil
+}
+
+// GetInactivityReport lists the users the policy is tracking with their next step, soonest first
+func (s *Service) GetInactivityReport(ctx context.Context, stage string) (*dto.InactivityReport, error) {
+	records, err := s.repository.ListInactiveUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.InactivityReport{
+		Policy: loadInactivityPolicy(),
+		Users:  []dto.InactiveUserEntry{},
+	}
+	for _, record := range records {
+		switch record.Stage {
+		case models.InactivityFlagged:
+			report.Flagged++
+		case models.InactivityNotified:
+			report.Notified++
+		case models.InactivityDisabled:
+			report.Disabled++
+		}
+		if stage != "" && record.Stage != stage {
+			continue
+		}
+
+		entry := dto.InactiveUserEntry{InactiveUser: *record, NextAction: "none"}
+		switch record.Stage {
+		case models.InactivityFlagged:
+			if report.Policy.Enforced {
+				entry.NextAction = "notify"
+			}
+		case models.InactivityNotified:
+			entry.NextAction = "disable"
+			entry.NextActionAt = record.DisableAt
+		case models.InactivityDisabled:
+			if report.Policy.PurgeDays > 0 {
+				entry.NextAction = "purge"
+				entry.NextActionAt = record.PurgeAt
+				if entry.NextActionAt == nil && record.DisabledAt != nil {
+					purgeAt := record.DisabledAt.AddDate(0, 0, report.Policy.PurgeDays)
+					entry.NextActionAt = &purgeAt
+				}
+			}
+		}
+		report.Users = append(report.Users, entry)
+	}
+
+	// Scheduled steps first, soonest first; users without a scheduled step last
+	sort.SliceStable(report.Users, func(i, j int) bool {
+		a, b := report.Users[i].NextActionAt, report.Users[j].NextActionAt
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+
+	return report, nil
+}
+
+// ListInactivityExemptions returns all inactivity exemptions, newest first
+func (s *Service) ListInactivityExemptions(ctx context.Context) ([]*models.InactivityExemption, error) {
+	return s.repository.ListInactivityExemptions(ctx)
+}
+
+// ExemptFromInactivity keeps a user out of the inactivity policy and stops any steps already taken
+// against them. A disabled user still has to log in again to restore their tokens.
+func (s *Service) ExemptFromInactivity(ctx context.Context, userID string, request dto.InactivityExemptionRequest, createdBy string) (*models.InactivityExemption, error) {
+	characters, err := s.repository.ListCharacters(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(characters) == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	exemption := &models.InactivityExemption{
+		UserID:    userID,
+		Reason:    request.Reason,
+		ExpiresAt: request.ExpiresAt,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repository.SaveInactivityExemption(ctx, exemption); err != nil {
+		return nil, err
+	}
+	if err := s.repository.DeleteInactiveUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return exemption, nil
+}
+
+// RemoveInactivityExemption puts a user back under the inactivity policy
+func (s *Service) RemoveInactivityExemption(ctx context.Context, userID string) error {
+	deleted, err := s.repository.DeleteInactivityExemption(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrInactivityExemptionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go-falcon/internal/users/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrMergeSameAccount is returned when an account would be merged into itself
+	ErrMergeSameAccount = errors.New("cannot merge an account into itself")
+	// ErrMergeDeletionPending is returned when either account has a deletion pending or running
+	ErrMergeDeletionPending = errors.New("account deletion pending")
+	// ErrMergeNotFound is returned when reverting a merge that does not exist
+	ErrMergeNotFound = errors.New("account merge not found")
+	// ErrMergeAlreadyReverted is returned when reverting a merge twice
+	ErrMergeAlreadyReverted = errors.New("account merge already reverted")
+)
+
+// SessionRevoker signs an account or one of its characters out everywhere; implemented by the auth service
+type SessionRevoker interface {
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) (int, error)
+	RevokeCharacterSessions(ctx context.Context, userID string, characterID int) (int, error)
+}
+
+// SetSessionRevoker sets how the sessions of merged accounts and deleted characters are ended
+func (s *Service) SetSessionRevoker(revoker SessionRevoker) {
+	s.sessionRevoker = revoker
+}
+
+// MergeAccounts merges the source account into the target account, e.g. when someone registered
+// twice. Characters, notification deliveries, group join requests, Discord links and scheduler task
+// ownership move in one transaction; group memberships belong to characters and move with them.
+// The sessions of the source account are ended afterwards, as it no longer has characters.
+func (s *Service) MergeAccounts(ctx context.Context, sourceUserID, targetUserID, mergedBy, reason string) (*models.AccountMerge, error) {
+	if sourceUserID == targetUserID {
+		return nil, ErrMergeSameAccount
+	}
+	for _, userID := range []string{sourceUserID, targetUserID} {
+		characters, err := s.repository.ListCharacters(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(characters) == 0 {
+			return nil, ErrUserNotFound
+		}
+		deletion, err := s.repository.GetOpenAccountDeletion(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if deletion != nil {
+			return nil, ErrMergeDeletionPending
+		}
+	}
+
+	merge := &models.AccountMerge{
+		SourceUserID: sourceUserID,
+		TargetUserID: targetUserID,
+		Status:       models.MergeCompleted,
+		Reason:       reason,
+		MergedBy:     mergedBy,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := s.repository.MergeAccounts(ctx, merge); err != nil {
+		return nil, err
+	}
+	if s.groupService != nil {
+		s.groupService.InvalidatePermissionDecisions(ctx)
+	}
+
+	revoked := 0
+	if s.sessionRevoker != nil {
+		var err error
+		if revoked, err = s.sessionRevoker.RevokeOtherSessions(ctx, sourceUserID, ""); err != nil {
+			slog.WarnContext(ctx, "Failed to end sessions of merged account", "user_id", sourceUserID, "error", err)
+		}
+	}
+
+	slog.InfoContext(ctx, "Merged user accounts",
+		"merge_id", merge.ID.Hex(),
+		"source_user_id", sourceUserID,
+		"target_user_id", targetUserID,
+		"characters", len(merge.CharacterIDs),
+		"deliveries", len(merge.DeliveryIDs),
+		"sessions_revoked", revoked,
+		"merged_by", mergedBy)
+	return merge, nil
+}
+
+// RevertAccountMerge moves what a merge moved back to the source account. Characters or records
+// that changed hands again since then stay where they are.
+func (s *Service) RevertAccountMerge(ctx context.Context, id primitive.ObjectID, revertedBy string) (*models.AccountMerge, error) {
+	merge, err := s.repository.RevertAccountMerge(ctx, id, revertedBy)
+	if err != nil {
+		return nil, err
+	}
+	if merge == nil {
+		existing, err := s.repository.GetAccountMerge(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, ErrMergeNotFound
+		}
+		return nil, ErrMergeAlreadyReverted
+	}
+	if s.groupService != nil {
+		s.groupService.InvalidatePermissionDecisions(ctx)
+	}
+
+	slog.InfoContext(ctx, "Reverted user account merge",
+		"merge_id", merge.ID.Hex(),
+		"source_user_id", merge.SourceUserID,
+		"target_user_id", merge.TargetUserID,
+		"reverted_by", revertedBy)
+	return merge, nil
+}
+
+// ListAccountMerges returns the most recent account merges, optionally involving one user
+func (s *Service) ListAccountMerges(ctx context.Context, userID string, limit int) ([]*models.AccountMerge, error) {
+	return s.repository.ListAccountMerges(ctx, userID, limit)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	// Validating timezones must not depend on the zoneinfo of the host or container image
+	_ "time/tzdata"
+
+	"go-falcon/internal/users/models"
+)
+
+const (
+	// maxPreferences bounds how many keys a user can store
+	maxPreferences = 100
+	// maxPreferenceLength bounds the length of a string preference
+	maxPreferenceLength = 1024
+)
+
+var (
+	// preferenceKeyPattern keeps keys usable as MongoDB field names
+	preferenceKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+	// localePattern matches BCP 47 language tags such as "en", "de-DE" or "zh-Hant-TW"
+	localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+)
+
+// preferenceValidators validate the known preference keys; any other key takes a string, number
+// or boolean
+var preferenceValidators = map[string]func(value interface{}) error{
+	"timezone": func(value interface{}) error {
+		name, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be a string")
+		}
+		if _, err := time.LoadLocation(name); err != nil || name == "" || name == "Local" {
+			return fmt.Errorf("must be an IANA time zone such as Europe/Berlin")
+		}
+		return nil
+	},
+	"locale": func(value interface{}) error {
+		tag, ok := value.(string)
+		if !ok || !localePattern.MatchString(tag) {
+			return fmt.Errorf("must be a language tag such as en or de-DE")
+		}
+		return nil
+	},
+	"theme":                  oneOf("light", "dark", "system"),
+	"notification_min_level": oneOf("info", "warning", "critical"),
+	"notification_sound":     isBool,
+	"notification_desktop":   isBool,
+}
+
+// InvalidPreferencesError lists why preferences were rejected, by key
+type InvalidPreferencesError struct {
+	Problems map[string]string
+}
+
+func (e *InvalidPreferencesError) Error() string {
+	keys := make([]string, 0, len(e.Problems))
+	for key := range e.Problems {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	problems := make([]string, 0, len(keys))
+	for _, key := range keys {
+		problems = append(problems, key+" "+e.Problems[key])
+	}
+	return "invalid preferences: " + strings.Join(problems, "; ")
+}
+
+// GetPreferences returns the preferences of a user; a user who never saved any has none
+func (s *Service) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	preferences, err := s.repository.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if preferences == nil {
+		preferences = &models.UserPreferences{UserID: userID}
+	}
+	if preferences.Preferences == nil {
+		preferences.Preferences = map[string]interface{}{}
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences sets the given preferences of a user and removes those set to nil; the others
+// are left unchanged. Nothing is saved when any of them is invalid.
+func (s *Service) UpdatePreferences(ctx context.Context, userID string, changes map[string]interface{}) (*models.UserPreferences, error) {
+	set := make(map[string]interface{}, len(changes))
+	var unset []string
+	problems := make(map[string]string)
+	for key, value := range changes {
+		if !preferenceKeyPattern.MatchString(key) {
+			problems[key] = "is not a valid key (lowercase letters, digits and underscores, at most 64)"
+			continue
+		}
+		if value == nil {
+			unset = append(unset, key)
+			continue
+		}
+		if err := validatePreference(key, value); err != nil {
+			problems[key] = err.Error()
+			continue
+		}
+		set[key] = value
+	}
+	if len(problems) > 0 {
+		return nil, &InvalidPreferencesError{Problems: problems}
+	}
+
+	current, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	count := len(current.Preferences)
+	for key := range set {
+		if _, exists := current.Preferences[key]; !exists {
+			count++
+		}
+	}
+	for _, key := range unset {
+		if _, exists := current.Preferences[key]; exists {
+			count--
+		}
+	}
+	if count > maxPreferences {
+		return nil, &InvalidPreferencesError{Problems: map[string]string{
+			"preferences": fmt.Sprintf("would exceed %d keys", maxPreferences),
+		}}
+	}
+
+	preferences, err := s.repository.UpdateUserPreferences(ctx, userID, set, unset)
+	if err != nil {
+		return nil, err
+	}
+	if preferences.Preferences == nil {
+		preferences.Preferences = map[string]interface{}{}
+	}
+
+	slog.DebugContext(ctx, "Updated user preferences", "user_id", userID, "set", len(set), "removed", len(unset))
+	return preferences, nil
+}
+
+// validatePreference checks a value against the schema of its key
+func validatePreference(key string, value interface{}) error {
+	if validate, known := preferenceValidators[key]; known {
+		return validate(value)
+	}
+	switch v := value.(type) {
+	case bool, float64, int, int64:
+		return nil
+	case string:
+		if len(v) > maxPreferenceLength {
+			return fmt.Errorf("must be at most %d characters", maxPreferenceLength)
+		}
+		return nil
+	}
+	return fmt.Errorf("must be a string, number or boolean")
+}
+
+// oneOf validates that a preference is one of the allowed strings
+func oneOf(allowed ...string) func(value interface{}) error {
+	return func(value interface{}) error {
+		if s, ok := value.(string); ok {
+			for _, option := range allowed {
+				if s == option {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
+
+// isBool validates that a preference is a boolean
+func isBool(value interface{}) error {
+	if _, ok := value.(bool); !ok {
+		return fmt.Errorf("must be a boolean")
+	}
+	return nil
+}
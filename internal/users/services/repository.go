@@ -2,14 +2,22 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
+	authModels "go-falcon/internal/auth/models"
+	discordModels "go-falcon/internal/discord/models"
+	groupModels "go-falcon/internal/groups/models"
+	notificationModels "go-falcon/internal/notifications/models"
 	"go-falcon/internal/users/dto"
 	"go-falcon/internal/users/models"
 	"go-falcon/pkg/database"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -36,7 +44,7 @@ func (r *Repository) GetUser(ctx context.Context, characterID int) (*models.User
 	err := collection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("user not found for character ID %d", characterID)
+			return nil, fmt.Errorf("%w: character ID %d", ErrUserNotFound, characterID)
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -91,18 +99,18 @@ func (r *Repository) UpdateUser(ctx context.Context, characterID int, req dto.Us
 	}
 
 	if result.MatchedCount == 0 {
-		return nil, fmt.Errorf("user not found for character ID %d", characterID)
+		return nil, fmt.Errorf("%w: character ID %d", ErrUserNotFound, characterID)
 	}
 
 	// Return updated user
 	return r.GetUser(ctx, characterID)
 }
 
-// ListCharacters retrieves character summaries for a specific user ID
+// ListCharacters retrieves character summaries for a specific user ID, leaving out soft-deleted characters
 func (r *Repository) ListCharacters(ctx context.Context, userID string) ([]dto.CharacterSummaryResponse, error) {
 	collection := r.mongodb.Collection(models.CharacterSummary{}.CollectionName())
 
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"user_id": userID, "deleted_at": bson.M{"$exists": false}}
 
 	// Project only needed fields for character summary
 	projection := bson.M{
@@ -154,31 +162,13 @@ func (r *Repository) ListCharacters(ctx context.Context, userID string) ([]dto.C
 func (r *Repository) ListUsers(ctx context.Context, input dto.UserListInput) (*dto.UserListResponse, error) {
 	collection := r.mongodb.Collection(models.User{}.CollectionName())
 
-	// Build filter
-	filter := bson.M{}
-
-	if input.Query != "" {
-		// Search by character name or character ID
-		query := input.Query
-		filter["$or"] = []bson.M{
-			{"character_name": bson.M{"$regex": query, "$options": "i"}},
-		}
-
-		// If query is numeric, also search by character_id
-		if characterID := parseInt(query); characterID > 0 {
-			filter["$or"] = append(filter["$or"].([]bson.M), bson.M{"character_id": characterID})
-		}
-	}
-
-	if input.Banned == "true" {
-		filter["banned"] = true
-	} else if input.Banned == "false" {
-		filter["banned"] = false
-	}
-
-	if input.Position > 0 {
-		filter["position"] = input.Position
-	}
+	filter := userFilter(dto.UserFilter{
+		Query:    input.Query,
+		Banned:   input.Banned,
+		Position: input.Position,
+		Tag:      input.Tag,
+		Deleted:  input.Deleted,
+	})
 
 	// Build sort options
 	sortField := "created_at"
@@ -234,6 +224,9 @@ func (r *Repository) ListUsers(ctx context.Context, input dto.UserListInput) (*d
 			LastLogin:     user.LastLogin,
 			CharacterName: user.CharacterName,
 			Valid:         user.Valid,
+			Tags:          tagsOf(&user),
+			DeletedAt:     user.DeletedAt,
+			DeletedBy:     user.DeletedBy,
 		})
 	}
 
@@ -253,6 +246,131 @@ func (r *Repository) ListUsers(ctx context.Context, input dto.UserListInput) (*d
 	}, nil
 }
 
+// userFilter builds the query of the user list filters
+func userFilter(input dto.UserFilter) bson.M {
+	filter := bson.M{}
+
+	if input.Query != "" {
+		// Search by character name or character ID
+		query := input.Query
+		filter["$or"] = []bson.M{
+			{"character_name": bson.M{"$regex": query, "$options": "i"}},
+		}
+
+		// If query is numeric, also search by character_id
+		if characterID := parseInt(query); characterID > 0 {
+			filter["$or"] = append(filter["$or"].([]bson.M), bson.M{"character_id": characterID})
+		}
+	}
+
+	if input.Banned == "true" {
+		filter["banned"] = true
+	} else if input.Banned == "false" {
+		filter["banned"] = false
+	}
+
+	if input.Position > 0 {
+		filter["position"] = input.Position
+	}
+
+	if input.Tag != "" {
+		filter["tags"] = input.Tag
+	}
+
+	// Soft-deleted characters are hidden unless asked for
+	switch input.Deleted {
+	case "true":
+		filter["deleted_at"] = bson.M{"$exists": true}
+	case "all":
+	default:
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+	return filter
+}
+
+// FindUsersMatching returns up to limit characters matching the user list filters, by character ID
+func (r *Repository) FindUsersMatching(ctx context.Context, input dto.UserFilter, limit int) ([]*models.User, error) {
+	return r.findUsers(ctx, userFilter(input), limit)
+}
+
+// FindUsersByCharacterIDs returns the registered characters among the given ones, by character ID
+func (r *Repository) FindUsersByCharacterIDs(ctx context.Context, characterIDs []int) ([]*models.User, error) {
+	return r.findUsers(ctx, bson.M{"character_id": bson.M{"$in": characterIDs}}, len(characterIDs))
+}
+
+func (r *Repository) findUsers(ctx context.Context, filter bson.M, limit int) ([]*models.User, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "character_id", Value: 1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	users := []*models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	return users, nil
+}
+
+// SearchUsersByName finds characters by ID or whose name has a word starting with each word of the
+// query, such as "jo do" for "John Doe", sorted by name
+func (r *Repository) SearchUsersByName(ctx context.Context, query, deleted string, limit int) ([]*models.UserSearchHit, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	words := bson.A{}
+	for _, word := range strings.Fields(query) {
+		words = append(words, bson.M{"character_name": bson.M{"$regex": `(^|\s)` + regexp.QuoteMeta(word), "$options": "i"}})
+	}
+	matches := []bson.M{{"$and": words}}
+	if characterID := parseInt(query); characterID > 0 {
+		matches = append(matches, bson.M{"character_id": characterID})
+	}
+	filter := userFilter(dto.UserFilter{Deleted: deleted})
+	filter["$or"] = matches
+
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "character_name", Value: 1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users by name: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	hits := []*models.UserSearchHit{}
+	if err := cursor.All(ctx, &hits); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	return hits, nil
+}
+
+// SearchUsersText finds characters through the user_search_text index over names, corporation,
+// alliance, tags and notes, most relevant first
+func (r *Repository) SearchUsersText(ctx context.Context, query, deleted string, limit int) ([]*models.UserSearchHit, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	filter := userFilter(dto.UserFilter{Deleted: deleted})
+	filter["$text"] = bson.M{"$search": query}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}, "access_token": 0, "refresh_token": 0}).
+		SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	hits := []*models.UserSearchHit{}
+	if err := cursor.All(ctx, &hits); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	return hits, nil
+}
+
 // parseInt safely converts string to int, returns 0 if invalid
 func parseInt(s string) int {
 	// Simple numeric check for character ID search
@@ -280,7 +398,7 @@ func (r *Repository) DeleteUser(ctx context.Context, characterID int) error {
 	}
 
 	if result.DeletedCount == 0 {
-		return fmt.Errorf("user not found for character ID %d", characterID)
+		return fmt.Errorf("%w: character ID %d", ErrUserNotFound, characterID)
 	}
 
 	return nil
@@ -347,6 +465,1160 @@ func (r *Repository) RecalculatePositions(ctx context.Context, userID string) er
 	return nil
 }
 
+// ActivityCharacter is the part of a character profile the inactivity policy looks at
+type ActivityCharacter struct {
+	CharacterID   int       `bson:"character_id"`
+	CharacterName string    `bson:"character_name"`
+	Scopes        string    `bson:"scopes"`
+	AccessToken   string    `bson:"access_token"`
+	TokenExpiry   time.Time `bson:"token_expiry"`
+}
+
+// UserActivity is a user's latest SSO login over all of their characters
+type UserActivity struct {
+	UserID     string              `bson:"_id"`
+	LastLogin  time.Time           `bson:"last_login"`
+	Characters []ActivityCharacter `bson:"characters"`
+}
+
+// ListUsersLastLoginBefore returns the users none of whose characters has logged in since before
+func (r *Repository) ListUsersLastLoginBefore(ctx context.Context, before time.Time) ([]UserActivity, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": bson.M{"$nin": bson.A{"", nil}}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$user_id",
+			"last_login": bson.M{"$max": "$last_login"},
+			"characters": bson.M{"$push": bson.M{
+				"character_id":   "$character_id",
+				"character_name": "$character_name",
+				"scopes":         "$scopes",
+				"access_token":   "$access_token",
+				"token_expiry":   "$token_expiry",
+			}},
+		}}},
+		{{Key: "$match", Value: bson.M{"last_login": bson.M{"$lt": before}}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate user activity: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []UserActivity
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode user activity: %w", err)
+	}
+	return users, nil
+}
+
+// RevokeUserTokens clears the EVE tokens of all characters of a user and marks them invalid, which
+// stops token refreshes and every ESI call made on their behalf until they log in again
+func (r *Repository) RevokeUserTokens(ctx context.Context, userID string) error {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	_, err := collection.UpdateMany(ctx, bson.M{"user_id": userID}, bson.M{
+		"$set": bson.M{
+			"access_token":  "",
+			"refresh_token": "",
+			"valid":         false,
+			"updated_at":    time.Now(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke tokens of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// ListInactiveUsers returns every user the inactivity policy is tracking
+func (r *Repository) ListInactiveUsers(ctx context.Context) ([]*models.InactiveUser, error) {
+	collection := r.mongodb.Collection(models.InactiveUser{}.CollectionName())
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "last_login", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find inactive users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []*models.InactiveUser
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode inactive users: %w", err)
+	}
+	return users, nil
+}
+
+// SaveInactiveUser creates or replaces the inactivity record of a user
+func (r *Repository) SaveInactiveUser(ctx context.Context, user *models.InactiveUser) error {
+	collection := r.mongodb.Collection(models.InactiveUser{}.CollectionName())
+
+	_, err := collection.ReplaceOne(ctx, bson.M{"user_id": user.UserID}, user, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save inactivity record of user %s: %w", user.UserID, err)
+	}
+	return nil
+}
+
+// DeleteInactiveUser removes the inactivity record of a user
+func (r *Repository) DeleteInactiveUser(ctx context.Context, userID string) error {
+	collection := r.mongodb.Collection(models.InactiveUser{}.CollectionName())
+
+	if _, err := collection.DeleteOne(ctx, bson.M{"user_id": userID}); err != nil {
+		return fmt.Errorf("failed to delete inactivity record of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// ListInactivityExemptions returns all exemptions, including expired ones
+func (r *Repository) ListInactivityExemptions(ctx context.Context) ([]*models.InactivityExemption, error) {
+	collection := r.mongodb.Collection(models.InactivityExemption{}.CollectionName())
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find inactivity exemptions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var exemptions []*models.InactivityExemption
+	if err := cursor.All(ctx, &exemptions); err != nil {
+		return nil, fmt.Errorf("failed to decode inactivity exemptions: %w", err)
+	}
+	return exemptions, nil
+}
+
+// SaveInactivityExemption creates or replaces the exemption of a user
+func (r *Repository) SaveInactivityExemption(ctx context.Context, exemption *models.InactivityExemption) error {
+	collection := r.mongodb.Collection(models.InactivityExemption{}.CollectionName())
+
+	_, err := collection.ReplaceOne(ctx, bson.M{"user_id": exemption.UserID}, exemption, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save inactivity exemption: %w", err)
+	}
+	return nil
+}
+
+// DeleteInactivityExemption removes the exemption of a user, reporting whether one existed
+func (r *Repository) DeleteInactivityExemption(ctx context.Context, userID string) (bool, error) {
+	collection := r.mongodb.Collection(models.InactivityExemption{}.CollectionName())
+
+	result, err := collection.DeleteOne(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete inactivity exemption: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// InsertAccountDeletion stores a new account deletion
+func (r *Repository) InsertAccountDeletion(ctx context.Context, deletion *models.AccountDeletion) error {
+	collection := r.mongodb.Collection(models.AccountDeletion{}.CollectionName())
+
+	result, err := collection.InsertOne(ctx, deletion)
+	if err != nil {
+		return fmt.Errorf("failed to store account deletion: %w", err)
+	}
+	deletion.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetOpenAccountDeletion returns the pending or running deletion of a user, or nil
+func (r *Repository) GetOpenAccountDeletion(ctx context.Context, userID string) (*models.AccountDeletion, error) {
+	collection := r.mongodb.Collection(models.AccountDeletion{}.CollectionName())
+
+	var deletion models.AccountDeletion
+	err := collection.FindOne(ctx, bson.M{
+		"user_id": userID,
+		"status":  bson.M{"$in": bson.A{models.DeletionPending, models.DeletionRunning}},
+	}).Decode(&deletion)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account deletion of user %s: %w", userID, err)
+	}
+	return &deletion, nil
+}
+
+// CancelAccountDeletion cancels the pending deletion of a user and returns it, or nil when there
+// is none; a running deletion can no longer be cancelled
+func (r *Repository) CancelAccountDeletion(ctx context.Context, userID, cancelledBy string) (*models.AccountDeletion, error) {
+	collection := r.mongodb.Collection(models.AccountDeletion{}.CollectionName())
+
+	now := time.Now().UTC()
+	var deletion models.AccountDeletion
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"user_id": userID, "status": models.DeletionPending},
+		bson.M{"$set": bson.M{
+			"status":       models.DeletionCancelled,
+			"cancelled_by": cancelledBy,
+			"cancelled_at": now,
+			"updated_at":   now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&deletion)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel account deletion of user %s: %w", userID, err)
+	}
+	return &deletion, nil
+}
+
+// ClaimDueAccountDeletion marks the oldest due deletion running and returns it, or nil when none
+// is due. Deletions left running by a replica that stopped are claimed again after staleAfter.
+func (r *Repository) ClaimDueAccountDeletion(ctx context.Context, now time.Time, staleAfter time.Duration) (*models.AccountDeletion, error) {
+	collection := r.mongodb.Collection(models.AccountDeletion{}.CollectionName())
+
+	var deletion models.AccountDeletion
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"status": models.DeletionPending, "scheduled_for": bson.M{"$lte": now}},
+			bson.M{"status": models.DeletionRunning, "updated_at": bson.M{"$lt": now.Add(-staleAfter)}},
+		}},
+		bson.M{
+			"$set": bson.M{"status": models.DeletionRunning, "updated_at": now},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "scheduled_for", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&deletion)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim account deletion: %w", err)
+	}
+	return &deletion, nil
+}
+
+// CompleteAccountDeletion records the receipt of a finished erasure and drops its character IDs
+func (r *Repository) CompleteAccountDeletion(ctx context.Context, id primitive.ObjectID, receipt *models.DeletionReceipt) error {
+	collection := r.mongodb.Collection(models.AccountDeletion{}.CollectionName())
+
+	_, err := collection.UpdateByID(ctx, id, bson.M{
+		"$set": bson.M{
+			"status":     models.DeletionCompleted,
+			"receipt":    receipt,
+			"updated_at": receipt.CompletedAt,
+		},
+		"$unset": bson.M{"character_ids": "", "last_error": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete account deletion: %w", err)
+	}
+	return nil
+}
+
+// RetryAccountDeletion puts a failed erasure back to pending for the next run
+func (r *Repository) RetryAccountDeletion(ctx context.Context, id primitive.ObjectID, lastError string) error {
+	collection := r.mongodb.Collection(models.AccountDeletion{}.CollectionName())
+
+	_, err := collection.UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"status":     models.DeletionPending,
+		"last_error": lastError,
+		"updated_at": time.Now().UTC(),
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to reschedule account deletion: %w", err)
+	}
+	return nil
+}
+
+// ListAccountDeletions returns the most recent account deletions, optionally in one status
+func (r *Repository) ListAccountDeletions(ctx context.Context, status string, limit int) ([]*models.AccountDeletion, error) {
+	collection := r.mongodb.Collection(models.AccountDeletion{}.CollectionName())
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account deletions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	deletions := []*models.AccountDeletion{}
+	if err := cursor.All(ctx, &deletions); err != nil {
+		return nil, fmt.Errorf("failed to decode account deletions: %w", err)
+	}
+	return deletions, nil
+}
+
+// DeleteUserProfiles deletes the profiles of all characters of a user, with their EVE tokens
+func (r *Repository) DeleteUserProfiles(ctx context.Context, userID string) (int64, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	result, err := collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete profiles of user %s: %w", userID, err)
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteNotificationDeliveries deletes every notification delivered to a user
+func (r *Repository) DeleteNotificationDeliveries(ctx context.Context, userID string) (int64, error) {
+	collection := r.mongodb.Collection(notificationModels.DeliveriesCollection)
+
+	result, err := collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete notifications of user %s: %w", userID, err)
+	}
+	return result.DeletedCount, nil
+}
+
+// MergeAccounts moves the characters, notification deliveries, group join requests, Discord links
+// and scheduler task ownership of merge.SourceUserID to merge.TargetUserID and stores the merge with
+// what was moved, all in one transaction. Deliveries of notifications the target also received stay
+// with the source, as a user holds one delivery per notification.
+func (r *Repository) MergeAccounts(ctx context.Context, merge *models.AccountMerge) error {
+	session, err := r.mongodb.Client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		source, target := merge.SourceUserID, merge.TargetUserID
+		profiles := r.mongodb.Collection(models.User{}.CollectionName())
+		deliveries := r.mongodb.Collection(notificationModels.DeliveriesCollection)
+
+		characters, err := profiles.Distinct(sc, "character_id", bson.M{"user_id": source})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find characters: %w", err)
+		}
+		merge.CharacterIDs = make([]int, 0, len(characters))
+		for _, characterID := range characters {
+			switch id := characterID.(type) {
+			case int32:
+				merge.CharacterIDs = append(merge.CharacterIDs, int(id))
+			case int64:
+				merge.CharacterIDs = append(merge.CharacterIDs, int(id))
+			}
+		}
+		if _, err := profiles.UpdateMany(sc, bson.M{"user_id": source}, bson.M{
+			"$set": bson.M{"user_id": target, "updated_at": time.Now()},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to move characters: %w", err)
+		}
+
+		received, err := deliveries.Distinct(sc, "notification_id", bson.M{"user_id": target})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find notifications: %w", err)
+		}
+		if merge.DeliveryIDs, err = moveObjectIDs(sc, deliveries,
+			bson.M{"user_id": source, "notification_id": bson.M{"$nin": received}}, "user_id", target); err != nil {
+			return nil, err
+		}
+		skipped, err := deliveries.CountDocuments(sc, bson.M{"user_id": source})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count notification deliveries: %w", err)
+		}
+		merge.SkippedDeliveries = int(skipped)
+
+		if merge.JoinRequestIDs, err = moveObjectIDs(sc, r.mongodb.Collection(groupModels.JoinRequestsCollection),
+			bson.M{"user_id": source}, "user_id", target); err != nil {
+			return nil, err
+		}
+		if merge.DiscordUserIDs, err = moveObjectIDs(sc, r.mongodb.Collection(discordModels.DiscordUsersCollection),
+			bson.M{"user_id": source}, "user_id", target); err != nil {
+			return nil, err
+		}
+
+		tasks := r.mongodb.Collection(schedulerTasksCollection)
+		if merge.CreatedTaskIDs, err = moveTaskOwnership(sc, tasks, "created_by", source, target); err != nil {
+			return nil, err
+		}
+		if merge.UpdatedTaskIDs, err = moveTaskOwnership(sc, tasks, "updated_by", source, target); err != nil {
+			return nil, err
+		}
+
+		result, err := r.mongodb.Collection(models.AccountMerge{}.CollectionName()).InsertOne(sc, merge)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store account merge: %w", err)
+		}
+		merge.ID = result.InsertedID.(primitive.ObjectID)
+		return nil, nil
+	})
+	return err
+}
+
+// RevertAccountMerge moves what a merge moved back to its source account and marks it reverted,
+// in one transaction. Only records still on the target account move back. It returns nil when the
+// merge does not exist or is already reverted.
+func (r *Repository) RevertAccountMerge(ctx context.Context, id primitive.ObjectID, revertedBy string) (*models.AccountMerge, error) {
+	session, err := r.mongodb.Client.StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	var merge models.AccountMerge
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		now := time.Now()
+		err := r.mongodb.Collection(models.AccountMerge{}.CollectionName()).FindOneAndUpdate(sc,
+			bson.M{"_id": id, "status": models.MergeCompleted},
+			bson.M{"$set": bson.M{"status": models.MergeReverted, "reverted_by": revertedBy, "reverted_at": now}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&merge)
+		if err != nil {
+			return nil, err
+		}
+		source, target := merge.SourceUserID, merge.TargetUserID
+
+		if _, err := r.mongodb.Collection(models.User{}.CollectionName()).UpdateMany(sc,
+			bson.M{"user_id": target, "character_id": bson.M{"$in": merge.CharacterIDs}},
+			bson.M{"$set": bson.M{"user_id": source, "updated_at": now}}); err != nil {
+			return nil, fmt.Errorf("failed to move characters back: %w", err)
+		}
+		for collection, ids := range map[string][]primitive.ObjectID{
+			notificationModels.DeliveriesCollection: merge.DeliveryIDs,
+			groupModels.JoinRequestsCollection:      merge.JoinRequestIDs,
+			discordModels.DiscordUsersCollection:    merge.DiscordUserIDs,
+		} {
+			if len(ids) == 0 {
+				continue
+			}
+			if _, err := r.mongodb.Collection(collection).UpdateMany(sc,
+				bson.M{"_id": bson.M{"$in": ids}, "user_id": target},
+				bson.M{"$set": bson.M{"user_id": source}}); err != nil {
+				return nil, fmt.Errorf("failed to move %s back: %w", collection, err)
+			}
+		}
+
+		tasks := r.mongodb.Collection(schedulerTasksCollection)
+		for field, ids := range map[string][]string{"created_by": merge.CreatedTaskIDs, "updated_by": merge.UpdatedTaskIDs} {
+			if len(ids) == 0 {
+				continue
+			}
+			if _, err := tasks.UpdateMany(sc,
+				bson.M{"_id": bson.M{"$in": ids}, field: "staff:" + target},
+				bson.M{"$set": bson.M{field: "staff:" + source}}); err != nil {
+				return nil, fmt.Errorf("failed to move scheduler task ownership back: %w", err)
+			}
+		}
+		return nil, nil
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revert account merge: %w", err)
+	}
+	return &merge, nil
+}
+
+// GetAccountMerge returns a merge by ID, or nil
+func (r *Repository) GetAccountMerge(ctx context.Context, id primitive.ObjectID) (*models.AccountMerge, error) {
+	var merge models.AccountMerge
+	err := r.mongodb.Collection(models.AccountMerge{}.CollectionName()).FindOne(ctx, bson.M{"_id": id}).Decode(&merge)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account merge: %w", err)
+	}
+	return &merge, nil
+}
+
+// ListAccountMerges returns the most recent account merges, optionally involving one user
+func (r *Repository) ListAccountMerges(ctx context.Context, userID string, limit int) ([]*models.AccountMerge, error) {
+	filter := bson.M{}
+	if userID != "" {
+		filter["$or"] = bson.A{bson.M{"source_user_id": userID}, bson.M{"target_user_id": userID}}
+	}
+	cursor, err := r.mongodb.Collection(models.AccountMerge{}.CollectionName()).Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account merges: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	merges := []*models.AccountMerge{}
+	if err := cursor.All(ctx, &merges); err != nil {
+		return nil, fmt.Errorf("failed to decode account merges: %w", err)
+	}
+	return merges, nil
+}
+
+// schedulerTasksCollection holds the scheduler tasks, whose created_by and updated_by name staff
+// accounts as "staff:<user_id>"
+const schedulerTasksCollection = "scheduler_tasks"
+
+// moveObjectIDs sets field to value on the documents matching filter and returns their IDs
+func moveObjectIDs(ctx context.Context, collection *mongo.Collection, filter bson.M, field, value string) ([]primitive.ObjectID, error) {
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", collection.Name(), err)
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", collection.Name(), err)
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	if _, err := collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{field: value}}); err != nil {
+		return nil, fmt.Errorf("failed to move %s: %w", collection.Name(), err)
+	}
+	return ids, nil
+}
+
+// moveTaskOwnership relabels the scheduler tasks whose field names the staff account from as the
+// staff account to and returns their IDs
+func moveTaskOwnership(ctx context.Context, tasks *mongo.Collection, field, from, to string) ([]string, error) {
+	values, err := tasks.Distinct(ctx, "_id", bson.M{field: "staff:" + from})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find scheduler tasks: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	if _, err := tasks.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{field: "staff:" + to}}); err != nil {
+		return nil, fmt.Errorf("failed to move scheduler task ownership: %w", err)
+	}
+	return ids, nil
+}
+
+// findUser returns the profile of a character, or nil when it is not registered
+func (r *Repository) findUser(ctx context.Context, characterID int) (*models.User, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	var user models.User
+	err := collection.FindOne(ctx, bson.M{"character_id": characterID}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &user, nil
+}
+
+// AddUserTag adds a tag to a character and reports whether it was missing
+func (r *Repository) AddUserTag(ctx context.Context, characterID int, tag string) (bool, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"character_id": characterID, "tags": bson.M{"$ne": tag}},
+		bson.M{"$addToSet": bson.M{"tags": tag}, "$set": bson.M{"updated_at": time.Now()}})
+	if err != nil {
+		return false, fmt.Errorf("failed to add tag to character %d: %w", characterID, err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// RemoveUserTag removes a tag from a character and reports whether it was there
+func (r *Repository) RemoveUserTag(ctx context.Context, characterID int, tag string) (bool, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"character_id": characterID, "tags": tag},
+		bson.M{"$pull": bson.M{"tags": tag}, "$set": bson.M{"updated_at": time.Now()}})
+	if err != nil {
+		return false, fmt.Errorf("failed to remove tag from character %d: %w", characterID, err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ListUserTags returns every tag in use with how many characters carry it, most used first
+func (r *Repository) ListUserTags(ctx context.Context) ([]dto.UserTagCount, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	cursor, err := collection.Aggregate(ctx, []bson.M{
+		{"$match": bson.M{"tags.0": bson.M{"$exists": true}}},
+		{"$unwind": "$tags"},
+		{"$group": bson.M{"_id": "$tags", "characters": bson.M{"$sum": 1}}},
+		{"$sort": bson.D{{Key: "characters", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$project": bson.M{"_id": 0, "tag": "$_id", "characters": 1}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate user tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tags := []dto.UserTagCount{}
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, fmt.Errorf("failed to decode user tags: %w", err)
+	}
+	return tags, nil
+}
+
+// InsertAuditEvent appends an event to the users audit log
+func (r *Repository) InsertAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	collection := r.mongodb.Collection(models.AuditEvent{}.CollectionName())
+
+	if _, err := collection.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("failed to store users audit event: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns the most recent users audit events, optionally about one character or
+// in one action
+func (r *Repository) ListAuditEvents(ctx context.Context, characterID int, action string, limit int) ([]*models.AuditEvent, error) {
+	collection := r.mongodb.Collection(models.AuditEvent{}.CollectionName())
+
+	filter := bson.M{}
+	if characterID != 0 {
+		filter["character_id"] = characterID
+	}
+	if action != "" {
+		filter["action"] = action
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users audit events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	events := []*models.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode users audit events: %w", err)
+	}
+	return events, nil
+}
+
+// SoftDeleteUser marks a character deleted and reports whether it was not deleted already
+func (r *Repository) SoftDeleteUser(ctx context.Context, characterID int, deletedBy string) (bool, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	now := time.Now()
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"character_id": characterID, "deleted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"deleted_at": now, "deleted_by": deletedBy, "updated_at": now}})
+	if err != nil {
+		return false, fmt.Errorf("failed to soft delete character %d: %w", characterID, err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// RestoreUser clears the soft deletion of a character and reports whether it was deleted
+func (r *Repository) RestoreUser(ctx context.Context, characterID int) (bool, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"character_id": characterID, "deleted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}, "$set": bson.M{"updated_at": time.Now()}})
+	if err != nil {
+		return false, fmt.Errorf("failed to restore character %d: %w", characterID, err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ListUsersDeletedBefore returns the characters soft-deleted before a time, oldest first
+func (r *Repository) ListUsersDeletedBefore(ctx context.Context, before time.Time) ([]*models.User, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	cursor, err := collection.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}},
+		options.Find().SetSort(bson.D{{Key: "deleted_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find soft-deleted users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	users := []*models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode soft-deleted users: %w", err)
+	}
+	return users, nil
+}
+
+// ListDeletedCharacterIDs returns the soft-deleted characters of a user, which ListCharacters leaves out
+func (r *Repository) ListDeletedCharacterIDs(ctx context.Context, userID string) ([]int, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	values, err := collection.Distinct(ctx, "character_id", bson.M{"user_id": userID, "deleted_at": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find soft-deleted characters: %w", err)
+	}
+	characterIDs := make([]int, 0, len(values))
+	for _, value := range values {
+		switch id := value.(type) {
+		case int32:
+			characterIDs = append(characterIDs, int(id))
+		case int64:
+			characterIDs = append(characterIDs, int(id))
+		}
+	}
+	return characterIDs, nil
+}
+
+// GetUserPreferences returns the preferences of a user, or nil when none were saved
+func (r *Repository) GetUserPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	collection := r.mongodb.Collection(models.UserPreferences{}.CollectionName())
+
+	var preferences models.UserPreferences
+	err := collection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&preferences)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences of user %s: %w", userID, err)
+	}
+	return &preferences, nil
+}
+
+// UpdateUserPreferences sets and removes preferences of a user, leaving the others unchanged, and
+// returns the result
+func (r *Repository) UpdateUserPreferences(ctx context.Context, userID string, set map[string]interface{}, unset []string) (*models.UserPreferences, error) {
+	collection := r.mongodb.Collection(models.UserPreferences{}.CollectionName())
+
+	now := time.Now().UTC()
+	setFields := bson.M{"updated_at": now}
+	for key, value := range set {
+		setFields["preferences."+key] = value
+	}
+	update := bson.M{"$set": setFields}
+	if len(unset) > 0 {
+		unsetFields := bson.M{}
+		for _, key := range unset {
+			unsetFields["preferences."+key] = ""
+		}
+		update["$unset"] = unsetFields
+	}
+
+	var preferences models.UserPreferences
+	err := collection.FindOneAndUpdate(ctx, bson.M{"user_id": userID}, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&preferences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update preferences of user %s: %w", userID, err)
+	}
+	return &preferences, nil
+}
+
+// DeleteUserPreferences deletes the preferences of a user
+func (r *Repository) DeleteUserPreferences(ctx context.Context, userID string) error {
+	collection := r.mongodb.Collection(models.UserPreferences{}.CollectionName())
+
+	if _, err := collection.DeleteOne(ctx, bson.M{"user_id": userID}); err != nil {
+		return fmt.Errorf("failed to delete preferences of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// InsertDataExport stores a new data export
+func (r *Repository) InsertDataExport(ctx context.Context, export *models.DataExport) error {
+	collection := r.mongodb.Collection(models.DataExport{}.CollectionName())
+
+	result, err := collection.InsertOne(ctx, export)
+	if err != nil {
+		return fmt.Errorf("failed to store data export: %w", err)
+	}
+	export.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetOpenDataExport returns the pending or running data export of a user, or nil
+func (r *Repository) GetOpenDataExport(ctx context.Context, userID string) (*models.DataExport, error) {
+	return r.findDataExport(ctx, bson.M{
+		"user_id": userID,
+		"status":  bson.M{"$in": bson.A{models.ExportPending, models.ExportRunning}},
+	})
+}
+
+// GetLatestDataExport returns the most recent data export of a user, or nil
+func (r *Repository) GetLatestDataExport(ctx context.Context, userID string) (*models.DataExport, error) {
+	return r.findDataExport(ctx, bson.M{"user_id": userID})
+}
+
+func (r *Repository) findDataExport(ctx context.Context, filter bson.M) (*models.DataExport, error) {
+	collection := r.mongodb.Collection(models.DataExport{}.CollectionName())
+
+	var export models.DataExport
+	err := collection.FindOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})).Decode(&export)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+	return &export, nil
+}
+
+// ClaimDataExport marks the oldest pending data export running and returns it, or nil when none
+// is waiting. Exports left running by a replica that stopped are claimed again after staleAfter.
+func (r *Repository) ClaimDataExport(ctx context.Context, now time.Time, staleAfter time.Duration) (*models.DataExport, error) {
+	collection := r.mongodb.Collection(models.DataExport{}.CollectionName())
+
+	var export models.DataExport
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"status": models.ExportPending},
+			bson.M{"status": models.ExportRunning, "updated_at": bson.M{"$lt": now.Add(-staleAfter)}},
+		}},
+		bson.M{
+			"$set": bson.M{"status": models.ExportRunning, "updated_at": now},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "created_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&export)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim data export: %w", err)
+	}
+	return &export, nil
+}
+
+// CompleteDataExport records the archive of a finished data export
+func (r *Repository) CompleteDataExport(ctx context.Context, export *models.DataExport) error {
+	collection := r.mongodb.Collection(models.DataExport{}.CollectionName())
+
+	_, err := collection.UpdateByID(ctx, export.ID, bson.M{
+		"$set": bson.M{
+			"status":       models.ExportCompleted,
+			"storage_key":  export.StorageKey,
+			"size_bytes":   export.SizeBytes,
+			"contents":     export.Contents,
+			"completed_at": export.CompletedAt,
+			"expires_at":   export.ExpiresAt,
+			"updated_at":   export.UpdatedAt,
+		},
+		"$unset": bson.M{"last_error": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete data export: %w", err)
+	}
+	return nil
+}
+
+// FailDataExport records why building an export failed and puts it back to pending for the next
+// run, or marks it failed for good
+func (r *Repository) FailDataExport(ctx context.Context, id primitive.ObjectID, lastError string, retry bool) error {
+	collection := r.mongodb.Collection(models.DataExport{}.CollectionName())
+
+	status := models.ExportFailed
+	if retry {
+		status = models.ExportPending
+	}
+	_, err := collection.UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"status":     status,
+		"last_error": lastError,
+		"updated_at": time.Now().UTC(),
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to record data export failure: %w", err)
+	}
+	return nil
+}
+
+// ListExpiredDataExports returns the data exports whose archive expired before now
+func (r *Repository) ListExpiredDataExports(ctx context.Context, now time.Time) ([]*models.DataExport, error) {
+	return r.listDataExports(ctx, bson.M{"expires_at": bson.M{"$lt": now}})
+}
+
+// ListDataExports returns all data exports of a user
+func (r *Repository) ListDataExports(ctx context.Context, userID string) ([]*models.DataExport, error) {
+	return r.listDataExports(ctx, bson.M{"user_id": userID})
+}
+
+func (r *Repository) listDataExports(ctx context.Context, filter bson.M) ([]*models.DataExport, error) {
+	collection := r.mongodb.Collection(models.DataExport{}.CollectionName())
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find data exports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	exports := []*models.DataExport{}
+	if err := cursor.All(ctx, &exports); err != nil {
+		return nil, fmt.Errorf("failed to decode data exports: %w", err)
+	}
+	return exports, nil
+}
+
+// DeleteDataExport deletes a data export
+func (r *Repository) DeleteDataExport(ctx context.Context, id primitive.ObjectID) error {
+	collection := r.mongodb.Collection(models.DataExport{}.CollectionName())
+
+	if _, err := collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("failed to delete data export: %w", err)
+	}
+	return nil
+}
+
+// exportedMembership is a group membership in a data export, with the name of its group
+type exportedMembership struct {
+	GroupName string `json:"group_name"`
+	groupModels.GroupMembership
+}
+
+// exportedNotification is a notification delivery in a data export, with the notification
+type exportedNotification struct {
+	notificationModels.Delivery
+	Notification *notificationModels.Notification `json:"notification,omitempty"`
+}
+
+// exportedDiscordLink is a linked Discord account in a data export, without its OAuth tokens
+type exportedDiscordLink struct {
+	DiscordID  string    `json:"discord_id" bson:"discord_id"`
+	Username   string    `json:"username" bson:"username"`
+	GlobalName *string   `json:"global_name,omitempty" bson:"global_name"`
+	IsActive   bool      `json:"is_active" bson:"is_active"`
+	LinkedAt   time.Time `json:"linked_at" bson:"linked_at"`
+}
+
+// ListUserProfiles returns the profiles of all characters of a user
+func (r *Repository) ListUserProfiles(ctx context.Context, userID string) ([]models.User, error) {
+	collection := r.mongodb.Collection(models.User{}.CollectionName())
+
+	cursor, err := collection.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "position", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user profiles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	profiles := []models.User{}
+	if err := cursor.All(ctx, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to decode user profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// listExportedMemberships returns the group memberships of the characters, active or not
+func (r *Repository) listExportedMemberships(ctx context.Context, characterIDs []int) ([]exportedMembership, error) {
+	cursor, err := r.mongodb.Collection(groupModels.MembershipsCollection).Find(ctx,
+		bson.M{"character_id": bson.M{"$in": characterIDs}},
+		options.Find().SetSort(bson.D{{Key: "added_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find group memberships: %w", err)
+	}
+	var memberships []groupModels.GroupMembership
+	if err := cursor.All(ctx, &memberships); err != nil {
+		return nil, fmt.Errorf("failed to decode group memberships: %w", err)
+	}
+
+	groupIDs := make([]primitive.ObjectID, 0, len(memberships))
+	for _, membership := range memberships {
+		groupIDs = append(groupIDs, membership.GroupID)
+	}
+	names := make(map[primitive.ObjectID]string)
+	if len(groupIDs) > 0 {
+		cursor, err := r.mongodb.Collection(groupModels.GroupsCollection).Find(ctx,
+			bson.M{"_id": bson.M{"$in": groupIDs}},
+			options.Find().SetProjection(bson.M{"name": 1}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find groups: %w", err)
+		}
+		var groups []groupModels.Group
+		if err := cursor.All(ctx, &groups); err != nil {
+			return nil, fmt.Errorf("failed to decode groups: %w", err)
+		}
+		for _, group := range groups {
+			names[group.ID] = group.Name
+		}
+	}
+
+	exported := make([]exportedMembership, 0, len(memberships))
+	for _, membership := range memberships {
+		exported = append(exported, exportedMembership{GroupName: names[membership.GroupID], GroupMembership: membership})
+	}
+	return exported, nil
+}
+
+// listExportedNotifications returns the notification deliveries of a user with their notifications
+func (r *Repository) listExportedNotifications(ctx context.Context, userID string) ([]exportedNotification, error) {
+	cursor, err := r.mongodb.Collection(notificationModels.DeliveriesCollection).Find(ctx,
+		bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification deliveries: %w", err)
+	}
+	var deliveries []notificationModels.Delivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode notification deliveries: %w", err)
+	}
+
+	notificationIDs := make([]primitive.ObjectID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		notificationIDs = append(notificationIDs, delivery.NotificationID)
+	}
+	notifications := make(map[primitive.ObjectID]*notificationModels.Notification)
+	if len(notificationIDs) > 0 {
+		cursor, err := r.mongodb.Collection(notificationModels.NotificationsCollection).Find(ctx,
+			bson.M{"_id": bson.M{"$in": notificationIDs}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find notifications: %w", err)
+		}
+		var found []*notificationModels.Notification
+		if err := cursor.All(ctx, &found); err != nil {
+			return nil, fmt.Errorf("failed to decode notifications: %w", err)
+		}
+		for _, notification := range found {
+			notifications[notification.ID] = notification
+		}
+	}
+
+	exported := make([]exportedNotification, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		exported = append(exported, exportedNotification{Delivery: delivery, Notification: notifications[delivery.NotificationID]})
+	}
+	return exported, nil
+}
+
+// listExportedDiscordLinks returns the Discord accounts linked to a user
+func (r *Repository) listExportedDiscordLinks(ctx context.Context, userID string) ([]exportedDiscordLink, error) {
+	cursor, err := r.mongodb.Collection(discordModels.DiscordUsersCollection).Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Discord links: %w", err)
+	}
+	links := []exportedDiscordLink{}
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, fmt.Errorf("failed to decode Discord links: %w", err)
+	}
+	return links, nil
+}
+
+// listAuthAuditEvents returns the authentication audit events of a user, oldest first
+func (r *Repository) listAuthAuditEvents(ctx context.Context, userID string) ([]authModels.AuthAuditEvent, error) {
+	cursor, err := r.mongodb.Collection(authAuditCollection).Find(ctx,
+		bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find auth audit events: %w", err)
+	}
+	events := []authModels.AuthAuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode auth audit events: %w", err)
+	}
+	return events, nil
+}
+
+// listGroupAuditEvents returns the groups audit events, archived ones included, that a user made
+// or that concern one of their characters, oldest first
+func (r *Repository) listGroupAuditEvents(ctx context.Context, userID string, characterIDs []int) ([]groupModels.AuditEvent, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"actor_user_id": userID},
+		bson.M{"character_id": bson.M{"$in": characterIDs}},
+	}}
+
+	events := []groupModels.AuditEvent{}
+	for _, collection := range []string{groupModels.AuditArchiveCollection, groupModels.AuditCollection} {
+		cursor, err := r.mongodb.Collection(collection).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find groups audit events: %w", err)
+		}
+		var found []groupModels.AuditEvent
+		if err := cursor.All(ctx, &found); err != nil {
+			return nil, fmt.Errorf("failed to decode groups audit events: %w", err)
+		}
+		events = append(events, found...)
+	}
+	return events, nil
+}
+
+// authAuditCollection is the authentication audit log kept by the auth module
+const authAuditCollection = "auth_audit"
+
+// CreateIndexes creates the indexes of the inactivity policy, preferences, account deletion,
+// account merge, data export and audit collections and of user tags, soft deletion and search
+func (r *Repository) CreateIndexes(ctx context.Context) error {
+	unique := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	if _, err := r.mongodb.Collection(models.InactiveUser{}.CollectionName()).Indexes().CreateOne(ctx, unique); err != nil {
+		return fmt.Errorf("failed to create inactive user index: %w", err)
+	}
+	if _, err := r.mongodb.Collection(models.InactivityExemption{}.CollectionName()).Indexes().CreateOne(ctx, unique); err != nil {
+		return fmt.Errorf("failed to create inactivity exemption index: %w", err)
+	}
+	if _, err := r.mongodb.Collection(models.UserPreferences{}.CollectionName()).Indexes().CreateOne(ctx, unique); err != nil {
+		return fmt.Errorf("failed to create user preferences index: %w", err)
+	}
+
+	deletions := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "scheduled_for", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	}
+	if _, err := r.mongodb.Collection(models.AccountDeletion{}.CollectionName()).Indexes().CreateMany(ctx, deletions); err != nil {
+		return fmt.Errorf("failed to create account deletion indexes: %w", err)
+	}
+
+	merges := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "source_user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "target_user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	}
+	if _, err := r.mongodb.Collection(models.AccountMerge{}.CollectionName()).Indexes().CreateMany(ctx, merges); err != nil {
+		return fmt.Errorf("failed to create account merge indexes: %w", err)
+	}
+
+	audit := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "character_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+	}
+	if _, err := r.mongodb.Collection(models.AuditEvent{}.CollectionName()).Indexes().CreateMany(ctx, audit); err != nil {
+		return fmt.Errorf("failed to create users audit indexes: %w", err)
+	}
+	profiles := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tags", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{
+			// A collection has at most one text index; this one backs the user search
+			Keys: bson.D{
+				{Key: "character_name", Value: "text"},
+				{Key: "corporation_name", Value: "text"},
+				{Key: "alliance_name", Value: "text"},
+				{Key: "tags", Value: "text"},
+				{Key: "notes", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("user_search_text").
+				SetDefaultLanguage("none").
+				SetWeights(bson.D{
+					{Key: "character_name", Value: 10},
+					{Key: "corporation_name", Value: 4},
+					{Key: "alliance_name", Value: 4},
+					{Key: "tags", Value: 4},
+					{Key: "notes", Value: 1},
+				}),
+		},
+	}
+	if _, err := r.mongodb.Collection(models.User{}.CollectionName()).Indexes().CreateMany(ctx, profiles); err != nil {
+		return fmt.Errorf("failed to create user profile indexes: %w", err)
+	}
+
+	exports := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+	}
+	if _, err := r.mongodb.Collection(models.DataExport{}.CollectionName()).Indexes().CreateMany(ctx, exports); err != nil {
+		return fmt.Errorf("failed to create data export indexes: %w", err)
+	}
+	return nil
+}
+
 // CheckHealth verifies database connectivity
 func (r *Repository) CheckHealth(ctx context.Context) error {
 	// Perform a simple ping to check database connectivity
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go-falcon/internal/users/dto"
+	"go-falcon/internal/users/models"
+)
+
+// ErrEmptySearch is returned for a search query without words
+var ErrEmptySearch = errors.New("search query is empty")
+
+// Relevance added to the text score of a character for how its name or ID matches the query, so
+// direct hits rank above characters only found through their corporation, tags or notes
+const (
+	searchRankID         = 100 // The query is the character ID
+	searchRankExactName  = 50  // The query is the character name
+	searchRankNamePrefix = 30  // The character name starts with the query
+	searchRankNameWords  = 20  // Every query word starts a word of the character name
+)
+
+// SearchUsers finds characters for the admin user browser: by ID, by name prefix or word prefixes
+// ("jo do" finds "John Doe"), and through the text index over character, corporation and alliance
+// names, tags and notes. Results are ordered by relevance, best first.
+func (s *Service) SearchUsers(ctx context.Context, query, deleted string, limit int) ([]dto.UserSearchResult, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	if query == "" {
+		return nil, ErrEmptySearch
+	}
+
+	byName, err := s.repository.SearchUsersByName(ctx, query, deleted, limit)
+	if err != nil {
+		return nil, err
+	}
+	byText, err := s.repository.SearchUsersText(ctx, query, deleted, limit)
+	if err != nil {
+		// The text index is created at startup; until it exists name matches still answer
+		slog.WarnContext(ctx, "User text search failed", "error", err)
+	}
+
+	hits := make(map[int]*models.UserSearchHit, len(byName)+len(byText))
+	for _, hit := range append(byName, byText...) {
+		if existing, ok := hits[hit.CharacterID]; ok {
+			existing.Score = max(existing.Score, hit.Score)
+			continue
+		}
+		hits[hit.CharacterID] = hit
+	}
+
+	words := strings.Fields(strings.ToLower(query))
+	results := make([]dto.UserSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if hit.Tags == nil {
+			hit.Tags = []string{}
+		}
+		results = append(results, dto.UserSearchResult{
+			CharacterID:     hit.CharacterID,
+			CharacterName:   hit.CharacterName,
+			UserID:          hit.UserID,
+			CorporationID:   hit.CorporationID,
+			CorporationName: hit.CorporationName,
+			AllianceID:      hit.AllianceID,
+			AllianceName:    hit.AllianceName,
+			Tags:            hit.Tags,
+			Banned:          hit.Banned,
+			LastLogin:       hit.LastLogin,
+			DeletedAt:       hit.DeletedAt,
+			Matched:         searchMatchedFields(query, words, hit),
+			Score:           hit.Score + searchNameRank(query, words, hit),
+		})
+	}
+	slices.SortFunc(results, func(a, b dto.UserSearchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(strings.ToLower(a.CharacterName), strings.ToLower(b.CharacterName))
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchNameRank returns the relevance of a character's ID and name for the query
+func searchNameRank(query string, words []string, hit *models.UserSearchHit) float64 {
+	name := strings.ToLower(hit.CharacterName)
+	switch {
+	case strconv.Itoa(hit.CharacterID) == query:
+		return searchRankID
+	case name == strings.ToLower(query):
+		return searchRankExactName
+	case strings.HasPrefix(name, strings.ToLower(query)):
+		return searchRankNamePrefix
+	case wordsStartWords(words, strings.Fields(name)):
+		return searchRankNameWords
+	}
+	return 0
+}
+
+// wordsStartWords reports whether each query word starts one of the words
+func wordsStartWords(query, words []string) bool {
+	for _, prefix := range query {
+		if !slices.ContainsFunc(words, func(word string) bool { return strings.HasPrefix(word, prefix) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// searchMatchedFields returns the fields of a character that contain the query or one of its words
+func searchMatchedFields(query string, words []string, hit *models.UserSearchHit) []string {
+	contains := func(value string) bool {
+		value = strings.ToLower(value)
+		return slices.ContainsFunc(words, func(word string) bool { return strings.Contains(value, word) })
+	}
+
+	matched := []string{}
+	if strconv.Itoa(hit.CharacterID) == query {
+		matched = append(matched, "character_id")
+	}
+	for _, field := range []struct{ name, value string }{
+		{"character_name", hit.CharacterName},
+		{"corporation_name", hit.CorporationName},
+		{"alliance_name", hit.AllianceName},
+	} {
+		if contains(field.value) {
+			matched = append(matched, field.name)
+		}
+	}
+	if slices.ContainsFunc(hit.Tags, contains) {
+		matched = append(matched, "tags")
+	}
+	if contains(hit.Notes) {
+		matched = append(matched, "notes")
+	}
+	return matched
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	allianceServices "go-falcon/internal/alliance/services"
@@ -13,8 +14,12 @@ import (
 	"go-falcon/pkg/database"
 	"go-falcon/pkg/evegateway"
 	"go-falcon/pkg/sde"
+	"go-falcon/pkg/storage"
 )
 
+// ErrUserNotFound is returned when a character or account is not registered
+var ErrUserNotFound = errors.New("user not found")
+
 // Service provides business logic for user operations
 type Service struct {
 	repository         *Repository
@@ -22,6 +27,12 @@ type Service struct {
 	characterService   *characterServices.Service
 	corporationService *corporationServices.Service
 	allianceService    *allianceServices.Service
+	usageTracker       *UsageTracker
+	eveGateway         *evegateway.Client
+	notifier           Notifier
+	accountEraser      AccountEraser
+	sessionRevoker     SessionRevoker
+	exportStore        storage.Store
 }
 
 // NewService creates a new service instance
@@ -43,9 +54,21 @@ func NewService(mongodb *database.MongoDB, redis *database.Redis, eveGateway *ev
 		characterService:   characterSvc,
 		corporationService: corporationSvc,
 		allianceService:    allianceSvc,
+		usageTracker:       NewUsageTracker(redis.Client),
+		eveGateway:         eveGateway,
 	}
 }
 
+// CreateIndexes creates the indexes used by the users service
+func (s *Service) CreateIndexes(ctx context.Context) error {
+	return s.repository.CreateIndexes(ctx)
+}
+
+// GetUsageTracker returns the API usage tracker
+func (s *Service) GetUsageTracker() *UsageTracker {
+	return s.usageTracker
+}
+
 // SetGroupService sets the groups service dependency
 func (s *Service) SetGroupService(groupService *services.Service) {
 	s.groupService = groupService
@@ -199,6 +222,9 @@ func (s *Service) UserToResponse(user *models.User) *dto.UserResponse {
 		LastLogin:     user.LastLogin,
 		CharacterName: user.CharacterName,
 		Valid:         user.Valid,
+		Tags:          tagsOf(user),
+		DeletedAt:     user.DeletedAt,
+		DeletedBy:     user.DeletedBy,
 	}
 }
 
@@ -245,15 +271,12 @@ func (s *Service) ReorderUserCharacters(ctx context.Context, userID string, req
 func (s *Service) DeleteUser(ctx context.Context, characterID int) error {
 	// Check if user exists before deletion
 	user, err := s.repository.GetUser(ctx, characterID)
+	if errors.Is(err, ErrUserNotFound) || (err == nil && user == nil) {
+		return ErrUserNotFound
+	}
 	if err != nil {
-		if err.Error() == fmt.Sprintf("user not found for character ID %d", characterID) {
-			return fmt.Errorf("user not found")
-		}
 		return fmt.Errorf("failed to get user: %w", err)
 	}
-	if user == nil {
-		return fmt.Errorf("user not found")
-	}
 
 	// Check if groups service is available for super admin validation
 	if s.groupService != nil {
@@ -264,7 +287,7 @@ func (s *Service) DeleteUser(ctx context.Context, characterID int) error {
 		}
 
 		if isSuperAdmin {
-			return fmt.Errorf("cannot delete super administrator character")
+			return ErrCannotDeleteSuperAdmin
 		}
 	}
 
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-falcon/internal/users/dto"
+	"go-falcon/internal/users/models"
+	"go-falcon/pkg/config"
+)
+
+var (
+	// ErrCannotDeleteSuperAdmin is returned when deleting a character of a super administrator
+	ErrCannotDeleteSuperAdmin = errors.New("cannot delete super administrator character")
+	// ErrUserAlreadyDeleted is returned when deleting a character that is already soft-deleted
+	ErrUserAlreadyDeleted = errors.New("user already deleted")
+	// ErrUserNotDeleted is returned when restoring a character that is not soft-deleted
+	ErrUserNotDeleted = errors.New("user is not deleted")
+)
+
+// SoftDeleteUser marks a character deleted: it drops out of user and character lists, its sessions
+// are ended and it can no longer log in. Its profile, tokens and group memberships are kept so
+// RestoreUser can bring it back unchanged until the retention task purges it.
+func (s *Service) SoftDeleteUser(ctx context.Context, characterID int, deletedBy string) (*models.User, error) {
+	user, err := s.repository.findUser(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.DeletedAt != nil {
+		return nil, ErrUserAlreadyDeleted
+	}
+	if s.groupService != nil {
+		isSuperAdmin, err := s.groupService.IsUserInGroup(ctx, user.UserID, "Super Administrator")
+		if err != nil {
+			return nil, fmt.Errorf("failed to check super admin status: %w", err)
+		}
+		if isSuperAdmin {
+			return nil, ErrCannotDeleteSuperAdmin
+		}
+	}
+
+	deleted, err := s.repository.SoftDeleteUser(ctx, characterID, deletedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, ErrUserAlreadyDeleted
+	}
+
+	revoked := 0
+	if s.sessionRevoker != nil {
+		if revoked, err = s.sessionRevoker.RevokeCharacterSessions(ctx, user.UserID, characterID); err != nil {
+			slog.WarnContext(ctx, "Failed to end sessions of deleted character", "character_id", characterID, "error", err)
+		}
+	}
+	s.recordAudit(ctx, &models.AuditEvent{Action: models.AuditUserDeleted}, user)
+
+	slog.InfoContext(ctx, "Soft-deleted user character",
+		"character_id", characterID,
+		"user_id", user.UserID,
+		"sessions_revoked", revoked,
+		"deleted_by", deletedBy)
+	return s.repository.findUser(ctx, characterID)
+}
+
+// RestoreUser reverses a soft deletion that has not been purged yet. Sessions ended by the
+// deletion stay ended; the character logs in again.
+func (s *Service) RestoreUser(ctx context.Context, characterID int) (*models.User, error) {
+	user, err := s.repository.findUser(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	restored, err := s.repository.RestoreUser(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		return nil, ErrUserNotDeleted
+	}
+	s.recordAudit(ctx, &models.AuditEvent{Action: models.AuditUserRestored}, user)
+
+	slog.InfoContext(ctx, "Restored user character", "character_id", characterID, "user_id", user.UserID)
+	return s.repository.findUser(ctx, characterID)
+}
+
+// PurgeDeletedUsers permanently deletes the characters soft-deleted longer ago than the retention
+// period, removing them from their groups first (used by the scheduler)
+func (s *Service) PurgeDeletedUsers(ctx context.Context) (*dto.SoftDeletePurgeRunResult, error) {
+	result := &dto.SoftDeletePurgeRunResult{}
+	cutoff := time.Now().AddDate(0, 0, -config.GetUserSoftDeleteRetentionDays())
+
+	users, err := s.repository.ListUsersDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return result, err
+	}
+
+	for _, user := range users {
+		if err := s.DeleteUser(ctx, user.CharacterID); err != nil && !errors.Is(err, ErrUserNotFound) {
+			slog.WarnContext(ctx, "Failed to purge soft-deleted character", "character_id", user.CharacterID, "error", err)
+			result.Failed++
+			continue
+		}
+		s.recordAudit(ctx, &models.AuditEvent{Action: models.AuditUserPurged}, user)
+		result.Purged++
+	}
+
+	if result.Purged > 0 || result.Failed > 0 {
+		slog.InfoContext(ctx, "Purged soft-deleted users", "purged", result.Purged, "failed", result.Failed)
+	}
+	return result, nil
+}
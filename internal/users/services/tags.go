@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-falcon/internal/users/dto"
+	"go-falcon/internal/users/models"
+	"go-falcon/pkg/identity"
+)
+
+var (
+	// ErrInvalidTag is returned for tags outside tagPattern
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrTooManyTags is returned when a character already carries maxTagsPerCharacter tags
+	ErrTooManyTags = errors.New("too many tags")
+)
+
+const (
+	// maxTagsPerCharacter bounds how many tags one character carries
+	maxTagsPerCharacter = 50
+	// auditWriteTimeout bounds an audit write, which is detached from the request so a client
+	// hanging up cannot keep a completed change out of the log
+	auditWriteTimeout = 5 * time.Second
+)
+
+// tagPattern allows short lowercase tags with a qualifier, such as "fc" or "alt-of:123"
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:-]{0,63}$`)
+
+// NormalizeTag lowercases and trims a tag and checks it against tagPattern
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if !tagPattern.MatchString(tag) {
+		return "", ErrInvalidTag
+	}
+	return tag, nil
+}
+
+// AddUserTag tags a character and records the change in the users audit log. Adding a tag the
+// character already carries changes nothing and is not audited.
+func (s *Service) AddUserTag(ctx context.Context, characterID int, tag string) (*models.User, error) {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repository.findUser(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if len(user.Tags) >= maxTagsPerCharacter {
+		return nil, ErrTooManyTags
+	}
+
+	added, err := s.repository.AddUserTag(ctx, characterID, tag)
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		s.recordAudit(ctx, &models.AuditEvent{Action: models.AuditTagAdded, Tag: tag}, user)
+		slog.InfoContext(ctx, "Tagged user", "character_id", characterID, "tag", tag)
+	}
+	return s.repository.findUser(ctx, characterID)
+}
+
+// RemoveUserTag removes a tag from a character and records the change in the users audit log
+func (s *Service) RemoveUserTag(ctx context.Context, characterID int, tag string) (*models.User, error) {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repository.findUser(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	removed, err := s.repository.RemoveUserTag(ctx, characterID, tag)
+	if err != nil {
+		return nil, err
+	}
+	if removed {
+		s.recordAudit(ctx, &models.AuditEvent{Action: models.AuditTagRemoved, Tag: tag}, user)
+		slog.InfoContext(ctx, "Untagged user", "character_id", characterID, "tag", tag)
+	}
+	return s.repository.findUser(ctx, characterID)
+}
+
+// ListUserTags returns every tag in use with how many characters carry it
+func (s *Service) ListUserTags(ctx context.Context) ([]dto.UserTagCount, error) {
+	return s.repository.ListUserTags(ctx)
+}
+
+// ListAuditEvents returns the most recent users audit events
+func (s *Service) ListAuditEvents(ctx context.Context, characterID int, action string, limit int) ([]*models.AuditEvent, error) {
+	return s.repository.ListAuditEvents(ctx, characterID, action, limit)
+}
+
+// recordAudit appends an event about a character to the users audit log, attributed to the
+// identity of ctx. Auditing never fails the change it records; a failed write is logged instead.
+func (s *Service) recordAudit(ctx context.Context, event *models.AuditEvent, user *models.User) {
+	if attribution := identity.AttributionFromContext(ctx); attribution != nil {
+		event.Attribution = *attribution
+	}
+	event.CharacterID = user.CharacterID
+	event.CharacterName = user.CharacterName
+	event.UserID = user.UserID
+	event.Timestamp = time.Now()
+
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+
+	if err := s.repository.InsertAuditEvent(writeCtx, event); err != nil {
+		slog.ErrorContext(ctx, "Failed to record users audit event",
+			"action", event.Action, "character_id", event.CharacterID, "error", err)
+	}
+}
+
+// tagsOf returns the tags of a user, never nil
+func tagsOf(user *models.User) []string {
+	if user.Tags == nil {
+		return []string{}
+	}
+	return user.Tags
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"go-falcon/internal/users/dto"
+	"go-falcon/pkg/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	usageBucketKeyPrefix = "users:usage:hour:"
+	usageNamesKey        = "users:usage:names"
+	usageBucketFormat    = "2006010215"
+
+	// UsageMaxHours is the longest window usage can be reported over
+	UsageMaxHours = 7 * 24
+)
+
+// UsageTracker records sampled per-character API call counts in hourly Redis buckets
+type UsageTracker struct {
+	redis         *redis.Client
+	enabled       bool
+	samplePercent int
+}
+
+// NewUsageTracker creates a usage tracker configured from the environment
+func NewUsageTracker(redisClient *redis.Client) *UsageTracker {
+	percent := config.GetIntEnv("USAGE_TRACKING_SAMPLE_PERCENT", 10)
+	if percent < 1 {
+		percent = 1
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	return &UsageTracker{
+		redis:         redisClient,
+		enabled:       redisClient != nil && config.GetBoolEnv("USAGE_TRACKING_ENABLED", true),
+		samplePercent: percent,
+	}
+}
+
+// Enabled reports whether usage tracking is active
+func (t *UsageTracker) Enabled() bool {
+	return t.enabled
+}
+
+// SamplePercent returns the percentage of requests that are recorded
+func (t *UsageTracker) SamplePercent() int {
+	return t.samplePercent
+}
+
+// Sample decides whether the current call is recorded. Callers sample before doing any work to
+// identify the caller, so unsampled calls cost nothing.
+func (t *UsageTracker) Sample() bool {
+	if !t.enabled {
+		return false
+	}
+	return t.samplePercent >= 100 || rand.Intn(100) < t.samplePercent
+}
+
+// Record counts a sampled API call for a character.
+// Sampled calls are weighted so the stored counts estimate the real totals.
+func (t *UsageTracker) Record(ctx context.Context, characterID int, characterName string) {
+	if !t.enabled || characterID == 0 {
+		return
+	}
+
+	weight := 100 / float64(t.samplePercent)
+	key := usageBucketKey(time.Now())
+	member := strconv.Itoa(characterID)
+
+	pipe := t.redis.Pipeline()
+	pipe.ZIncrBy(ctx, key, weight, member)
+	pipe.Expire(ctx, key, (UsageMaxHours+1)*time.Hour)
+	if characterName != "" {
+		pipe.HSet(ctx, usageNamesKey, member, characterName)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Debug("Failed to record API usage", "character_id", characterID, "error", err)
+	}
+}
+
+// GetCharacterUsage returns hourly usage buckets for a character over the last given hours
+func (t *UsageTracker) GetCharacterUsage(ctx context.Context, characterID int, hours int) (*dto.UsageResponse, error) {
+	hours = clampUsageHours(hours)
+	starts := usageBucketStarts(time.Now(), hours)
+
+	response := &dto.UsageResponse{
+		CharacterID:   characterID,
+		Enabled:       t.enabled,
+		Hours:         hours,
+		SamplePercent: t.samplePercent,
+	}
+	if t.redis == nil {
+		response.Buckets, _ = collectUsageBuckets(starts, nil)
+		return response, nil
+	}
+
+	pipe := t.redis.Pipeline()
+	cmds := queueUsageBuckets(ctx, pipe, starts, strconv.Itoa(characterID))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read usage buckets: %w", err)
+	}
+	response.Buckets, response.Total = collectUsageBuckets(starts, cmds)
+
+	return response, nil
+}
+
+// GetLeaderboard returns the heaviest API consumers over the last given hours
+func (t *UsageTracker) GetLeaderboard(ctx context.Context, hours int, limit int) (*dto.UsageLeaderboardResponse, error) {
+	hours = clampUsageHours(hours)
+	starts := usageBucketStarts(time.Now(), hours)
+
+	response := &dto.UsageLeaderboardResponse{
+		Enabled:       t.enabled,
+		Hours:         hours,
+		SamplePercent: t.samplePercent,
+		Entries:       []dto.UsageLeaderboardEntry{},
+	}
+	if t.redis == nil {
+		return response, nil
+	}
+
+	keys := make([]string, len(starts))
+	for i, start := range starts {
+		keys[i] = usageBucketKey(start)
+	}
+
+	totals, err := t.redis.ZUnionWithScores(ctx, redis.ZStore{Keys: keys}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate usage buckets: %w", err)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Score > totals[j].Score })
+	if limit > 0 && len(totals) > limit {
+		totals = totals[:limit]
+	}
+
+	members := make([]string, len(totals))
+	for i, z := range totals {
+		members[i] = fmt.Sprint(z.Member)
+	}
+
+	if len(members) == 0 {
+		return response, nil
+	}
+
+	// Read the names and each consumer's hourly series in one round trip
+	pipe := t.redis.Pipeline()
+	namesCmd := pipe.HMGet(ctx, usageNamesKey, members...)
+	bucketCmds := make([][]*redis.FloatCmd, len(members))
+	for i, member := range members {
+		bucketCmds[i] = queueUsageBuckets(ctx, pipe, starts, member)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read usage buckets: %w", err)
+	}
+	names := namesCmd.Val()
+
+	for i, z := range totals {
+		characterID, _ := strconv.Atoi(members[i])
+		entry := dto.UsageLeaderboardEntry{
+			Rank:        i + 1,
+			CharacterID: characterID,
+			Count:       int64(z.Score),
+		}
+		entry.Buckets, _ = collectUsageBuckets(starts, bucketCmds[i])
+		if i < len(names) {
+			if name, ok := names[i].(string); ok {
+				entry.CharacterName = name
+			}
+		}
+		response.Entries = append(response.Entries, entry)
+	}
+
+	return response, nil
+}
+
+// queueUsageBuckets queues reading a character's count in each bucket
+func queueUsageBuckets(ctx context.Context, pipe redis.Pipeliner, starts []time.Time, member string) []*redis.FloatCmd {
+	cmds := make([]*redis.FloatCmd, len(starts))
+	for i, start := range starts {
+		cmds[i] = pipe.ZScore(ctx, usageBucketKey(start), member)
+	}
+	return cmds
+}
+
+// collectUsageBuckets turns queued bucket reads into buckets and their total; without reads every
+// bucket is empty
+func collectUsageBuckets(starts []time.Time, cmds []*redis.FloatCmd) ([]dto.UsageBucket, int64) {
+	buckets := make([]dto.UsageBucket, len(starts))
+	var total int64
+	for i, start := range starts {
+		var count int64
+		if cmds != nil {
+			count = int64(cmds[i].Val())
+		}
+		buckets[i] = dto.UsageBucket{Start: start, Count: count}
+		total += count
+	}
+	return buckets, total
+}
+
+func usageBucketKey(t time.Time) string {
+	return usageBucketKeyPrefix + t.UTC().Format(usageBucketFormat)
+}
+
+// usageBucketStarts returns the start of each hourly bucket, oldest first, ending with the current hour
+func usageBucketStarts(now time.Time, hours int) []time.Time {
+	current := now.UTC().Truncate(time.Hour)
+	starts := make([]time.Time, hours)
+	for i := 0; i < hours; i++ {
+		starts[i] = current.Add(-time.Duration(hours-1-i) * time.Hour)
+	}
+	return starts
+}
+
+func clampUsageHours(hours int) int {
+	if hours < 1 {
+		return 1
+	}
+	if hours > UsageMaxHours {
+		return UsageMaxHours
+	}
+	return hours
+}
@@ -32,6 +32,8 @@ const (
 	MessageTypeBackendStatus         MessageType = "backend_status"
 	MessageTypeCriticalAlert         MessageType = "critical_alert"
 	MessageTypeServiceRecovery       MessageType = "service_recovery"
+	MessageTypeDataChange            MessageType = "data_change"
+	MessageTypeReplayComplete        MessageType = "replay_complete"
 )
 
 // Connection represents a WebSocket connection
@@ -44,6 +46,7 @@ type Connection struct {
 	Rooms         []string        `json:"rooms"`          // List of room IDs the connection is in
 	CreatedAt     time.Time       `json:"created_at"`
 	LastPing      time.Time       `json:"last_ping"`
+	ResumeFrom    string          `json:"-"` // History ID the client last received; missed messages are replayed
 	mu            sync.RWMutex    // Protects concurrent access
 }
 
@@ -67,6 +70,8 @@ type Message struct {
 	To        string                 `json:"to,omitempty"`   // Target connection ID (for direct messages)
 	Data      map[string]interface{} `json:"data,omitempty"` // Message payload
 	Timestamp time.Time              `json:"timestamp,omitempty"`
+	Seq       string                 `json:"seq,omitempty"`      // Room history ID, sent back as last_event_id on reconnect
+	Replayed  bool                   `json:"replayed,omitempty"` // Set on messages re-sent from history after a reconnect
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for Message
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	groupsModels "go-falcon/internal/groups/models"
 	"go-falcon/internal/websocket/middleware"
 	"go-falcon/internal/websocket/models"
 	"go-falcon/internal/websocket/routes"
@@ -19,6 +20,7 @@ import (
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -177,14 +179,88 @@ func (m *Module) SendSystemMessage(ctx context.Context, message string, data map
 		},
 	}
 
-	// Send to local connections first
+	// Publish to Redis for other instances first; this records the history entry and sets its Seq
+	publishErr := redisHub.PublishSystemMessage(ctx, systemMessage)
+
+	// Send to local connections even if the Redis publish failed
 	if err := m.service.SendMessage(systemMessage); err != nil {
 		slog.Error("Failed to send system message to local connections", "error", err)
-		// Continue to Redis broadcast even if local send fails
 	}
 
-	// Publish to Redis for other instances
-	return redisHub.PublishSystemMessage(ctx, systemMessage)
+	return publishErr
+}
+
+// NotifyUser pushes a notification to every connection of a user
+func (m *Module) NotifyUser(ctx context.Context, userID, message string, data map[string]interface{}) error {
+	notification := &models.Message{
+		Type:      models.MessageTypeNotification,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"message": message,
+			"data":    data,
+		},
+	}
+
+	return m.service.GetRedisHub().PublishToUser(ctx, userID, notification)
+}
+
+// NotifySystemGroup sends a notification to every connected member of a system group (e.g. "super_admin")
+func (m *Module) NotifySystemGroup(ctx context.Context, systemName, message string, data map[string]interface{}) error {
+	var group groupsModels.Group
+	err := m.MongoDB().Database.Collection(groupsModels.GroupsCollection).
+		FindOne(ctx, bson.M{"system_name": systemName, "is_active": true}).Decode(&group)
+	if err != nil {
+		return fmt.Errorf("failed to find system group %s: %w", systemName, err)
+	}
+
+	notification := &models.Message{
+		Type:      models.MessageTypeNotification,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"message": message,
+			"data":    data,
+		},
+	}
+
+	return m.service.GetRedisHub().PublishToRoom(ctx, fmt.Sprintf("group:%s", group.ID.Hex()), notification)
+}
+
+// BroadcastDataChange notifies local connections that a watched collection changed.
+// Every replica runs its own change-stream listener, so delivery stays local instead of going through Redis.
+// Changes of a group document go to that group's room; every other change (memberships, permissions,
+// site settings, routes) only to the super admins' room, as its metadata is not for every client.
+func (m *Module) BroadcastDataChange(ctx context.Context, collection, operationType, documentID string, updatedFields []string) error {
+	message := &models.Message{
+		Type:      models.MessageTypeDataChange,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"collection":     collection,
+			"operation_type": operationType,
+			"document_id":    documentID,
+			"updated_fields": updatedFields,
+		},
+	}
+
+	roomID := ""
+	if collection == "groups" && documentID != "" {
+		roomID = fmt.Sprintf("group:%s", documentID)
+	} else {
+		var group groupsModels.Group
+		err := m.MongoDB().Database.Collection(groupsModels.GroupsCollection).
+			FindOne(ctx, bson.M{"system_name": "super_admin", "is_active": true}).Decode(&group)
+		if err != nil {
+			return fmt.Errorf("failed to find super_admin group: %w", err)
+		}
+		roomID = fmt.Sprintf("group:%s", group.ID.Hex())
+	}
+
+	// Without connected members there is nobody to tell; never fall back to everyone
+	if _, exists := m.service.GetRoomManager().GetRoom(roomID); !exists {
+		return nil
+	}
+	message.Room = roomID
+
+	return m.service.SendMessage(message)
 }
 
 // Interface compliance check
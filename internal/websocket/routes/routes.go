@@ -44,7 +44,7 @@ func (wr *WebSocketRoutes) RegisterRoutes(api huma.API) {
 		Path:        "/websocket/connections",
 		Summary:     "List active WebSocket connections",
 		Description: "Retrieve list of active WebSocket connections (admin only)",
-		Tags:        []string{"WebSocket Admin"},
+		Tags:        []string{"WebSocket / Admin"},
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 		},
@@ -56,7 +56,7 @@ func (wr *WebSocketRoutes) RegisterRoutes(api huma.API) {
 		Path:        "/websocket/connections/{connection_id}",
 		Summary:     "Get WebSocket connection details",
 		Description: "Retrieve details of a specific WebSocket connection (admin only)",
-		Tags:        []string{"WebSocket Admin"},
+		Tags:        []string{"WebSocket / Admin"},
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 		},
@@ -68,7 +68,7 @@ func (wr *WebSocketRoutes) RegisterRoutes(api huma.API) {
 		Path:        "/websocket/rooms",
 		Summary:     "List WebSocket rooms",
 		Description: "Retrieve list of WebSocket rooms (admin only)",
-		Tags:        []string{"WebSocket Admin"},
+		Tags:        []string{"WebSocket / Admin"},
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 		},
@@ -80,7 +80,7 @@ func (wr *WebSocketRoutes) RegisterRoutes(api huma.API) {
 		Path:        "/websocket/rooms/{room_id}",
 		Summary:     "Get WebSocket room details",
 		Description: "Retrieve details of a specific WebSocket room (admin only)",
-		Tags:        []string{"WebSocket Admin"},
+		Tags:        []string{"WebSocket / Admin"},
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 		},
@@ -92,7 +92,7 @@ func (wr *WebSocketRoutes) RegisterRoutes(api huma.API) {
 		Path:        "/websocket/broadcast",
 		Summary:     "Broadcast message to all connections",
 		Description: "Broadcast a message to all WebSocket connections (admin only)",
-		Tags:        []string{"WebSocket Admin"},
+		Tags:        []string{"WebSocket / Admin"},
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 		},
@@ -104,7 +104,7 @@ func (wr *WebSocketRoutes) RegisterRoutes(api huma.API) {
 		Path:        "/websocket/connections/{connection_id}/message",
 		Summary:     "Send direct message to connection",
 		Description: "Send a direct message to a specific WebSocket connection (admin only)",
-		Tags:        []string{"WebSocket Admin"},
+		Tags:        []string{"WebSocket / Admin"},
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 		},
@@ -116,7 +116,7 @@ func (wr *WebSocketRoutes) RegisterRoutes(api huma.API) {
 		Path:        "/websocket/users/{user_id}/message",
 		Summary:     "Send message to user connections",
 		Description: "Send a message to all connections of a specific user (admin only)",
-		Tags:        []string{"WebSocket Admin"},
+		Tags:        []string{"WebSocket / Admin"},
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 		},
@@ -128,7 +128,7 @@ func (wr *WebSocketRoutes) RegisterRoutes(api huma.API) {
 		Path:        "/websocket/rooms/{room_id}/message",
 		Summary:     "Send message to room",
 		Description: "Send a message to all members of a specific room (admin only)",
-		Tags:        []string{"WebSocket Admin"},
+		Tags:        []string{"WebSocket / Admin"},
 		Security: []map[string][]string{
 			{"bearerAuth": {}},
 		},
@@ -174,6 +174,7 @@ func (wr *WebSocketRoutes) HandleWebSocketUpgrade(w http.ResponseWriter, r *http
 		Rooms:         []string{},
 		CreatedAt:     time.Now(),
 		LastPing:      time.Now(),
+		ResumeFrom:    r.URL.Query().Get("last_event_id"),
 	}
 
 	// Add connection to service
@@ -349,12 +350,12 @@ func (wr *WebSocketRoutes) handleBroadcast(ctx context.Context, input *dto.Broad
 	connections := connectionMgr.GetAllConnections()
 	recipientsCount := len(connections)
 
-	wr.service.SendMessage(message)
-
-	// Also publish via Redis for other instances
+	// Publish via Redis for other instances first; this records the history entry and sets message.Seq
 	redisHub := wr.service.GetRedisHub()
 	redisHub.BroadcastToAllInstances(ctx, message)
 
+	wr.service.SendMessage(message)
+
 	return &dto.BroadcastOutput{
 		Body: struct {
 			Success         bool      `json:"success" doc:"Whether the broadcast was successful"`
@@ -444,6 +445,10 @@ func (wr *WebSocketRoutes) handleUserMessage(ctx context.Context, input *dto.Use
 		Timestamp: time.Now(),
 	}
 
+	// Publish via Redis for other instances first; this records the history entry and sets message.Seq
+	redisHub := wr.service.GetRedisHub()
+	redisHub.PublishToUser(ctx, input.UserID, message)
+
 	// Send to all user connections
 	recipientsCount := 0
 	for _, conn := range connections {
@@ -452,10 +457,6 @@ func (wr *WebSocketRoutes) handleUserMessage(ctx context.Context, input *dto.Use
 		}
 	}
 
-	// Also publish via Redis for other instances
-	redisHub := wr.service.GetRedisHub()
-	redisHub.PublishToUser(ctx, input.UserID, message)
-
 	return &dto.UserMessageOutput{
 		Body: struct {
 			Success         bool      `json:"success" doc:"Whether the message was sent successfully"`
@@ -502,15 +503,15 @@ func (wr *WebSocketRoutes) handleRoomMessage(ctx context.Context, input *dto.Roo
 		Timestamp: time.Now(),
 	}
 
+	// Publish via Redis for other instances first; this records the history entry and sets message.Seq
+	redisHub := wr.service.GetRedisHub()
+	redisHub.PublishToRoom(ctx, input.RoomID, message)
+
 	// Send to room
 	if err := roomMgr.BroadcastToRoom(input.RoomID, message); err != nil {
 		return nil, huma.Error500InternalServerError("Failed to send room message", err)
 	}
 
-	// Also publish via Redis for other instances
-	redisHub := wr.service.GetRedisHub()
-	redisHub.PublishToRoom(ctx, input.RoomID, message)
-
 	return &dto.RoomMessageOutput{
 		Body: struct {
 			Success         bool      `json:"success" doc:"Whether the message was sent successfully"`
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-falcon/internal/websocket/models"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Redis key prefix for per-room message history streams
+	historyKeyPrefix = "websocket:history:"
+
+	// BroadcastHistoryRoom is the history stream for messages sent to every connection
+	BroadcastHistoryRoom = "broadcast"
+)
+
+// MessageHistory keeps a short, capped history of room messages in Redis streams so that
+// reconnecting clients can be sent what they missed. Stream entry IDs ("<ms>-<seq>") are
+// used as message sequence IDs; because they are time-based they can be compared across rooms.
+type MessageHistory struct {
+	client      *redis.Client
+	maxLen      int64
+	ttl         time.Duration
+	replayLimit int64
+}
+
+// NewMessageHistory creates a message history store. A maxLen of 0 disables history.
+func NewMessageHistory(client *redis.Client, maxLen int, ttl time.Duration, replayLimit int) *MessageHistory {
+	return &MessageHistory{
+		client:      client,
+		maxLen:      int64(maxLen),
+		ttl:         ttl,
+		replayLimit: int64(replayLimit),
+	}
+}
+
+// Enabled reports whether messages are being recorded
+func (h *MessageHistory) Enabled() bool {
+	return h != nil && h.client != nil && h.maxLen > 0
+}
+
+// Record appends a message to a room's history and sets message.Seq to its history ID
+func (h *MessageHistory) Record(ctx context.Context, roomID string, message *models.Message) error {
+	if !h.Enabled() {
+		return nil
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	key := historyKeyPrefix + roomID
+	pipe := h.client.TxPipeline()
+	add := pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: h.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{"message": data},
+	})
+	pipe.Expire(ctx, key, h.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record message history: %w", err)
+	}
+
+	message.Seq = add.Val()
+	return nil
+}
+
+// Since returns the messages recorded in rooms after lastID, oldest first. truncated is true
+// when messages may be missing: lastID is older than the history TTL or the replay limit was hit.
+func (h *MessageHistory) Since(ctx context.Context, rooms []string, lastID string) (messages []models.Message, truncated bool, err error) {
+	if !h.Enabled() {
+		return nil, true, nil
+	}
+
+	lastMillis, _, err := parseStreamID(lastID)
+	if err != nil {
+		return nil, false, err
+	}
+	truncated = time.Since(time.UnixMilli(lastMillis)) > h.ttl
+
+	for _, roomID := range rooms {
+		entries, err := h.client.XRangeN(ctx, historyKeyPrefix+roomID, "("+lastID, "+", h.replayLimit).Result()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read history for room %s: %w", roomID, err)
+		}
+
+		for _, entry := range entries {
+			raw, ok := entry.Values["message"].(string)
+			if !ok {
+				continue
+			}
+
+			var message models.Message
+			if err := json.Unmarshal([]byte(raw), &message); err != nil {
+				slog.Warn("Skipping unreadable history entry", "room_id", roomID, "id", entry.ID, "error", err)
+				continue
+			}
+			message.Seq = entry.ID
+			message.Replayed = true
+			messages = append(messages, message)
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return compareStreamIDs(messages[i].Seq, messages[j].Seq) < 0
+	})
+
+	if int64(len(messages)) > h.replayLimit {
+		// Keep the newest messages; the client refetches anything older
+		messages = messages[int64(len(messages))-h.replayLimit:]
+		truncated = true
+	}
+
+	return messages, truncated, nil
+}
+
+// parseStreamID splits a Redis stream ID into its millisecond and sequence parts
+func parseStreamID(id string) (int64, int64, error) {
+	millisPart, seqPart, found := strings.Cut(id, "-")
+	millis, err := strconv.ParseInt(millisPart, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid message ID %q", id)
+	}
+
+	var seq int64
+	if found {
+		if seq, err = strconv.ParseInt(seqPart, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid message ID %q", id)
+		}
+	}
+	return millis, seq, nil
+}
+
+// compareStreamIDs orders two stream IDs, returning -1, 0 or 1
+func compareStreamIDs(a, b string) int {
+	aMillis, aSeq, _ := parseStreamID(a)
+	bMillis, bSeq, _ := parseStreamID(b)
+
+	switch {
+	case aMillis != bMillis:
+		if aMillis < bMillis {
+			return -1
+		}
+		return 1
+	case aSeq < bSeq:
+		return -1
+	case aSeq > bSeq:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -19,6 +19,7 @@ type RedisHub struct {
 	serverID      string
 	connectionMgr *ConnectionManager // Reference to connection manager (set later)
 	roomMgr       *RoomManager       // Reference to room manager (set later)
+	history       *MessageHistory    // Room history for replay on reconnect
 	pubsub        *redis.PubSub
 	channels      []string
 }
@@ -38,10 +39,11 @@ const (
 )
 
 // NewRedisHub creates a new Redis hub
-func NewRedisHub(redisClient *redis.Client) *RedisHub {
+func NewRedisHub(redisClient *redis.Client, history *MessageHistory) *RedisHub {
 	return &RedisHub{
 		client:   redisClient,
 		serverID: uuid.New().String(),
+		history:  history,
 		channels: []string{
 			WebSocketChannel,
 			WebSocketRoomChannel,
@@ -188,9 +190,18 @@ func (rh *RedisHub) PublishMessage(ctx context.Context, channel string, message
 	return nil
 }
 
+// recordHistory stores the message in a room's history before it is published, so every
+// instance delivers it with the same Seq. History is best effort and never blocks delivery.
+func (rh *RedisHub) recordHistory(ctx context.Context, roomID string, message *models.Message) {
+	if err := rh.history.Record(ctx, roomID, message); err != nil {
+		slog.Warn("Failed to record WebSocket message history", "room_id", roomID, "error", err)
+	}
+}
+
 // PublishToRoom publishes a message to a room across all instances
 func (rh *RedisHub) PublishToRoom(ctx context.Context, roomID string, message *models.Message) error {
 	message.Room = roomID
+	rh.recordHistory(ctx, roomID, message)
 	return rh.PublishMessage(ctx, WebSocketRoomChannel, message)
 }
 
@@ -200,12 +211,14 @@ func (rh *RedisHub) PublishToUser(ctx context.Context, userID string, message *m
 		message.Data = make(map[string]interface{})
 	}
 	message.Data["user_id"] = userID
+	rh.recordHistory(ctx, fmt.Sprintf("user:%s", userID), message)
 	return rh.PublishMessage(ctx, WebSocketUserChannel, message)
 }
 
 // PublishSystemMessage publishes a system message across all instances
 func (rh *RedisHub) PublishSystemMessage(ctx context.Context, message *models.Message) error {
 	message.Type = models.MessageTypeSystemNotification
+	rh.recordHistory(ctx, BroadcastHistoryRoom, message)
 	return rh.PublishMessage(ctx, WebSocketSystemChannel, message)
 }
 
@@ -244,6 +257,9 @@ func (rh *RedisHub) BroadcastGroupMembershipChange(ctx context.Context, userID s
 
 // BroadcastToAllInstances broadcasts a message to all server instances
 func (rh *RedisHub) BroadcastToAllInstances(ctx context.Context, message *models.Message) error {
+	if message.To == "" {
+		rh.recordHistory(ctx, BroadcastHistoryRoom, message)
+	}
 	return rh.PublishMessage(ctx, WebSocketChannel, message)
 }
 
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"go-falcon/internal/websocket/models"
+	"go-falcon/pkg/config"
 	"log/slog"
 
 	"github.com/redis/go-redis/v9"
@@ -17,6 +18,7 @@ type WebSocketService struct {
 	roomMgr        *RoomManager
 	redisHub       *RedisHub
 	integrationSvc *IntegrationService
+	history        *MessageHistory
 	cleanupTicker  *time.Ticker
 	ctx            context.Context
 	cancelFunc     context.CancelFunc
@@ -26,7 +28,13 @@ type WebSocketService struct {
 func NewWebSocketService(db *mongo.Database, redisClient *redis.Client) *WebSocketService {
 	// Create services
 	roomMgr := NewRoomManager()
-	redisHub := NewRedisHub(redisClient)
+	history := NewMessageHistory(
+		redisClient,
+		config.GetWebSocketHistoryMaxLen(),
+		time.Duration(config.GetWebSocketHistoryTTLMinutes())*time.Minute,
+		config.GetWebSocketReplayLimit(),
+	)
+	redisHub := NewRedisHub(redisClient, history)
 	connectionMgr := NewConnectionManager(roomMgr, redisHub)
 	integrationSvc := NewIntegrationService(db, roomMgr, redisHub)
 
@@ -42,6 +50,7 @@ func NewWebSocketService(db *mongo.Database, redisClient *redis.Client) *WebSock
 		roomMgr:        roomMgr,
 		redisHub:       redisHub,
 		integrationSvc: integrationSvc,
+		history:        history,
 		ctx:            ctx,
 		cancelFunc:     cancel,
 	}
@@ -125,6 +134,8 @@ func (ws *WebSocketService) GetIntegrationService() *IntegrationService {
 
 // CreateConnection creates a new WebSocket connection with automatic room assignment
 func (ws *WebSocketService) CreateConnection(conn *models.Connection) error {
+	resumeFrom := conn.ResumeFrom
+
 	// Add connection to manager first (fast operation)
 	actualConn, err := ws.connectionMgr.AddConnection(conn.Conn, conn.UserID, conn.CharacterID, conn.CharacterName)
 	if err != nil {
@@ -146,11 +157,50 @@ func (ws *WebSocketService) CreateConnection(conn *models.Connection) error {
 		} else {
 			slog.Info("Successfully assigned user to rooms", "connection_id", conn.ID, "user_id", conn.UserID)
 		}
+
+		// Replay only once rooms are known, so group history is included
+		if resumeFrom != "" {
+			ws.ReplayMissedMessages(ctx, conn.ID, resumeFrom)
+		}
 	}()
 
 	return nil
 }
 
+// ReplayMissedMessages sends a reconnecting connection the history of its rooms recorded after
+// lastID, followed by a replay_complete message. Live messages may interleave with the replay,
+// so clients de-duplicate by seq. When replay_complete reports truncated, the client should
+// refetch current state because older messages are no longer available.
+func (ws *WebSocketService) ReplayMissedMessages(ctx context.Context, connectionID, lastID string) {
+	rooms := append(ws.roomMgr.GetConnectionRooms(connectionID), BroadcastHistoryRoom)
+
+	complete := &models.Message{
+		Type:      models.MessageTypeReplayComplete,
+		Data:      map[string]interface{}{"from": lastID},
+		Timestamp: time.Now(),
+	}
+
+	messages, truncated, err := ws.history.Since(ctx, rooms, lastID)
+	if err != nil {
+		slog.Warn("Failed to replay WebSocket history", "error", err, "connection_id", connectionID, "last_event_id", lastID)
+		complete.Data["error"] = err.Error()
+		truncated = true
+	}
+
+	for i := range messages {
+		if err := ws.connectionMgr.SendToConnection(connectionID, &messages[i]); err != nil {
+			slog.Debug("Stopped replay, connection unavailable", "error", err, "connection_id", connectionID)
+			return
+		}
+	}
+
+	complete.Data["replayed"] = len(messages)
+	complete.Data["truncated"] = truncated
+	ws.connectionMgr.SendToConnection(connectionID, complete)
+
+	slog.Info("Replayed missed WebSocket messages", "connection_id", connectionID, "count", len(messages), "truncated", truncated)
+}
+
 // SendMessage sends a message through the WebSocket system
 func (ws *WebSocketService) SendMessage(message *models.Message) error {
 	if message.Room != "" {
@@ -226,5 +276,6 @@ func (ws *WebSocketService) GetServiceInfo() map[string]interface{} {
 		"total_rooms":        stats.TotalRooms,
 		"messages_processed": stats.MessagesProcessed,
 		"redis_channels":     []string{WebSocketChannel, WebSocketRoomChannel, WebSocketUserChannel, WebSocketSystemChannel},
+		"history_enabled":    ws.history.Enabled(),
 	}
 }
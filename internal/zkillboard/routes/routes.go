@@ -42,7 +42,7 @@ func (r *Routes) RegisterRoutes(api huma.API) {
 		Path:        "/zkillboard/status",
 		Summary:     "Get ZKillboard service status",
 		Description: "Returns the current status of the ZKillboard RedisQ consumer service",
-		Tags:        []string{"Module Status", "ZKillboard"},
+		Tags:        []string{"Module Status"},
 		Security:    []map[string][]string{}, // Public endpoint
 	}, r.GetStatus)
 
@@ -14,6 +14,7 @@ import (
 
 	"go-falcon/internal/zkillboard/dto"
 	"go-falcon/internal/zkillboard/models"
+	"go-falcon/pkg/config"
 )
 
 // Helper functions for environment variables
@@ -26,15 +27,6 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
-func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if parsed, err := time.ParseDuration(value); err == nil {
-			return parsed
-		}
-	}
-	return defaultValue
-}
-
 // ServiceState represents the state of the consumer service
 type ServiceState int
 
@@ -124,7 +116,7 @@ func NewRedisQConsumer(processor *KillmailProcessor, repository *Repository) *Re
 	ttwMin := getEnvAsInt("ZKB_TTW_MIN", 1)
 	ttwMax := getEnvAsInt("ZKB_TTW_MAX", 10)
 	nullThreshold := getEnvAsInt("ZKB_NULL_THRESHOLD", 5)
-	httpTimeout := getEnvAsDuration("ZKB_HTTP_TIMEOUT", 30*time.Second)
+	httpTimeout := config.GetDurationEnv("ZKB_HTTP_TIMEOUT", 30*time.Second)
 
 	// Create HTTP client with timeout and redirect handling
 	httpClient := &http.Client{
@@ -14,6 +14,7 @@ func init() {
 		Description: "Create indexes for groups and group_memberships collections",
 		Up:          up001,
 		Down:        down001,
+		Impact:      Impact{Collections: []string{"groups", "group_memberships"}, IndexBuilds: 9},
 	})
 }
 
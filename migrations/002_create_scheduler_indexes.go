@@ -14,6 +14,7 @@ func init() {
 		Description: "Create indexes for scheduler_tasks and scheduler_executions collections",
 		Up:          up002,
 		Down:        down002,
+		Impact:      Impact{Collections: []string{"scheduler_tasks", "scheduler_executions"}, IndexBuilds: 10},
 	})
 }
 
@@ -11,10 +11,12 @@ import (
 
 func init() {
 	Register(Migration{
-		Version:     "003_seed_system_groups",
-		Description: "Create initial system groups (super_admin, authenticated, guest)",
-		Up:          up003,
-		Down:        down003,
+		Version:      "003_seed_system_groups",
+		Description:  "Create initial system groups (super_admin, authenticated, guest)",
+		Up:           up003,
+		Down:         down003,
+		Irreversible: true,
+		Impact:       Impact{Collections: []string{"groups"}},
 	})
 }
 
@@ -16,6 +16,7 @@ func init() {
 		Description: "Create indexes for characters collection",
 		Up:          up004,
 		Down:        down004,
+		Impact:      Impact{Collections: []string{"characters"}, IndexBuilds: 6},
 	})
 }
 
@@ -14,6 +14,7 @@ func init() {
 		Description: "Create indexes for users collection",
 		Up:          up005,
 		Down:        down005,
+		Impact:      Impact{Collections: []string{"users"}, IndexBuilds: 8},
 	})
 }
 
@@ -15,6 +15,7 @@ func init() {
 		Description: "Create indexes for user_profiles collection (auth system)",
 		Up:          up006,
 		Down:        down006,
+		Impact:      Impact{Collections: []string{"user_profiles"}, IndexBuilds: 11},
 	})
 }
 
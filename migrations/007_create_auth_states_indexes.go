@@ -15,6 +15,7 @@ func init() {
 		Description: "Create indexes for auth_states collection (EVE SSO states)",
 		Up:          up007,
 		Down:        down007,
+		Impact:      Impact{Collections: []string{"auth_states"}, IndexBuilds: 5},
 	})
 }
 
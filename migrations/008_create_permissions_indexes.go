@@ -15,6 +15,7 @@ func init() {
 		Description: "Create indexes for permissions collection (permission system)",
 		Up:          up008,
 		Down:        down008,
+		Impact:      Impact{Collections: []string{"permissions"}, IndexBuilds: 9},
 	})
 }
 
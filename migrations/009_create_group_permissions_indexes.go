@@ -15,6 +15,7 @@ func init() {
 		Description: "Create indexes for group_permissions collection (group-permission assignments)",
 		Up:          up009,
 		Down:        down009,
+		Impact:      Impact{Collections: []string{"group_permissions"}, IndexBuilds: 9},
 	})
 }
 
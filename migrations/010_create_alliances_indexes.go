@@ -15,6 +15,7 @@ func init() {
 		Description: "Create indexes for alliances collection (EVE alliance data)",
 		Up:          up010,
 		Down:        down010,
+		Impact:      Impact{Collections: []string{"alliances"}, IndexBuilds: 10},
 	})
 }
 
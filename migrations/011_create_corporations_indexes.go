@@ -15,6 +15,7 @@ func init() {
 		Description: "Create indexes for corporations collection (EVE corporation data)",
 		Up:          up011,
 		Down:        down011,
+		Impact:      Impact{Collections: []string{"corporations"}, IndexBuilds: 13},
 	})
 }
 
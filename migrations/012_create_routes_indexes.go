@@ -15,6 +15,7 @@ func init() {
 		Description: "Create indexes for routes collection (dynamic routing system)",
 		Up:          up012,
 		Down:        down012,
+		Impact:      Impact{Collections: []string{"routes"}, IndexBuilds: 14},
 	})
 }
 
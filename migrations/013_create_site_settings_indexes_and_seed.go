@@ -11,10 +11,12 @@ import (
 
 func init() {
 	Register(Migration{
-		Version:     "013_create_site_settings_indexes_and_seed",
-		Description: "Create indexes and seed data for site_settings collection",
-		Up:          up013,
-		Down:        down013,
+		Version:      "013_create_site_settings_indexes_and_seed",
+		Description:  "Create indexes and seed data for site_settings collection",
+		Up:           up013,
+		Down:         down013,
+		Irreversible: true,
+		Impact:       Impact{Collections: []string{"site_settings"}, IndexBuilds: 8},
 	})
 }
 
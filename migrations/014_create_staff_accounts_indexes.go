@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	Register(Migration{
+		Version:     "014_create_staff_accounts_indexes",
+		Description: "Create indexes for staff_accounts collection (non-EVE staff logins)",
+		Up:          up014,
+		Down:        down014,
+		Impact:      Impact{Collections: []string{"staff_accounts"}, IndexBuilds: 2},
+	})
+}
+
+func up014(ctx context.Context, db *mongo.Database) error {
+	staffAccountsCollection := db.Collection("staff_accounts")
+
+	indexes := []mongo.IndexModel{
+		// One account per provider email
+		{
+			Keys:    bson.D{{Key: "provider", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		// Subjects are bound on first login, so only index accounts that have one
+		{
+			Keys: bson.D{{Key: "provider", Value: 1}, {Key: "subject", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"subject": bson.M{"$exists": true}}),
+		},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(30 * time.Second)
+	_, err := staffAccountsCollection.Indexes().CreateMany(ctx, indexes, opts)
+	if err != nil && !isIndexExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func down014(ctx context.Context, db *mongo.Database) error {
+	staffAccountsCollection := db.Collection("staff_accounts")
+	if _, err := staffAccountsCollection.Indexes().DropAll(ctx); err != nil {
+		return err
+	}
+	return nil
+}
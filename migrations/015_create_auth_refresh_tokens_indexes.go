@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	Register(Migration{
+		Version:     "015_create_auth_refresh_tokens_indexes",
+		Description: "Create indexes for auth_refresh_tokens collection (session refresh token chains)",
+		Up:          up015,
+		Down:        down015,
+		Impact:      Impact{Collections: []string{"auth_refresh_tokens"}, IndexBuilds: 3},
+	})
+}
+
+func up015(ctx context.Context, db *mongo.Database) error {
+	refreshTokensCollection := db.Collection("auth_refresh_tokens")
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		// Revoking a session updates its whole chain
+		{
+			Keys: bson.D{{Key: "session_id", Value: 1}},
+		},
+		// Expired tokens are no longer needed for reuse detection either
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(30 * time.Second)
+	_, err := refreshTokensCollection.Indexes().CreateMany(ctx, indexes, opts)
+	if err != nil && !isIndexExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func down015(ctx context.Context, db *mongo.Database) error {
+	refreshTokensCollection := db.Collection("auth_refresh_tokens")
+	if _, err := refreshTokensCollection.Indexes().DropAll(ctx); err != nil {
+		return err
+	}
+	return nil
+}
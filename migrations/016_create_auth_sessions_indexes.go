@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	Register(Migration{
+		Version:     "016_create_auth_sessions_indexes",
+		Description: "Create indexes for auth_sessions collection (signed-in devices)",
+		Up:          up016,
+		Down:        down016,
+		Impact:      Impact{Collections: []string{"auth_sessions"}, IndexBuilds: 3},
+	})
+}
+
+func up016(ctx context.Context, db *mongo.Database) error {
+	sessionsCollection := db.Collection("auth_sessions")
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "session_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		// Session list of an account, most recently seen first
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_seen_at", Value: -1}},
+		},
+		// A session ends with its last refresh token
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(30 * time.Second)
+	_, err := sessionsCollection.Indexes().CreateMany(ctx, indexes, opts)
+	if err != nil && !isIndexExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func down016(ctx context.Context, db *mongo.Database) error {
+	sessionsCollection := db.Collection("auth_sessions")
+	if _, err := sessionsCollection.Indexes().DropAll(ctx); err != nil {
+		return err
+	}
+	return nil
+}
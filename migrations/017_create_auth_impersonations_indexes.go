@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	Register(Migration{
+		Version:     "017_create_auth_impersonations_indexes",
+		Description: "Create indexes for auth_impersonations collection (admin impersonation record)",
+		Up:          up017,
+		Down:        down017,
+		Impact:      Impact{Collections: []string{"auth_impersonations"}, IndexBuilds: 2},
+	})
+}
+
+func up017(ctx context.Context, db *mongo.Database) error {
+	impersonationsCollection := db.Collection("auth_impersonations")
+
+	// Records are kept; there is no TTL index
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+		// Who acted as a given user
+		{
+			Keys: bson.D{{Key: "target_user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(30 * time.Second)
+	_, err := impersonationsCollection.Indexes().CreateMany(ctx, indexes, opts)
+	if err != nil && !isIndexExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func down017(ctx context.Context, db *mongo.Database) error {
+	impersonationsCollection := db.Collection("auth_impersonations")
+	if _, err := impersonationsCollection.Indexes().DropAll(ctx); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	Register(Migration{
+		Version:     "018_create_user_profiles_discord_index",
+		Description: "Create index for Discord account links on user_profiles",
+		Up:          up018,
+		Down:        down018,
+		Impact:      Impact{Collections: []string{"user_profiles"}, IndexBuilds: 1},
+	})
+}
+
+func up018(ctx context.Context, db *mongo.Database) error {
+	profilesCollection := db.Collection("user_profiles")
+
+	// Which account a Discord user is linked to; only linked profiles are indexed
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "discord.user_id", Value: 1}},
+		Options: options.Index().SetName("discord_user_id").SetSparse(true),
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(30 * time.Second)
+	_, err := profilesCollection.Indexes().CreateOne(ctx, index, opts)
+	if err != nil && !isIndexExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func down018(ctx context.Context, db *mongo.Database) error {
+	profilesCollection := db.Collection("user_profiles")
+	if _, err := profilesCollection.Indexes().DropOne(ctx, "discord_user_id"); err != nil {
+		return err
+	}
+	return nil
+}
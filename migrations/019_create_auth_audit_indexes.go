@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	Register(Migration{
+		Version:     "019_create_auth_audit_indexes",
+		Description: "Create indexes for auth_audit collection (authentication audit log)",
+		Up:          up019,
+		Down:        down019,
+		Impact:      Impact{Collections: []string{"auth_audit"}, IndexBuilds: 5},
+	})
+}
+
+func up019(ctx context.Context, db *mongo.Database) error {
+	auditCollection := db.Collection("auth_audit")
+
+	// The audit log is queried newest first, by who or where the events came from
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "timestamp", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "character_id", Value: 1}, {Key: "timestamp", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "ip_address", Value: 1}, {Key: "timestamp", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "event", Value: 1}, {Key: "outcome", Value: 1}, {Key: "timestamp", Value: -1}},
+		},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(30 * time.Second)
+	_, err := auditCollection.Indexes().CreateMany(ctx, indexes, opts)
+	if err != nil && !isIndexExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func down019(ctx context.Context, db *mongo.Database) error {
+	auditCollection := db.Collection("auth_audit")
+	if _, err := auditCollection.Indexes().DropAll(ctx); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	Register(Migration{
+		Version:     "020_create_auth_signing_keys_indexes",
+		Description: "Create indexes for auth_signing_keys collection (JWT signing keys)",
+		Up:          up020,
+		Down:        down020,
+		Impact:      Impact{Collections: []string{"auth_signing_keys"}, IndexBuilds: 2},
+	})
+}
+
+func up020(ctx context.Context, db *mongo.Database) error {
+	keysCollection := db.Collection("auth_signing_keys")
+
+	indexes := []mongo.IndexModel{
+		{
+			// Replicas rotating at the same time race on the next generation; only one key wins
+			Keys:    bson.D{{Key: "generation", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Superseded keys are removed once every token they signed expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(30 * time.Second)
+	_, err := keysCollection.Indexes().CreateMany(ctx, indexes, opts)
+	if err != nil && !isIndexExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func down020(ctx context.Context, db *mongo.Database) error {
+	keysCollection := db.Collection("auth_signing_keys")
+	if _, err := keysCollection.Indexes().DropAll(ctx); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	Register(Migration{
+		Version:     "021_create_auth_device_codes_indexes",
+		Description: "Create indexes for auth_device_codes collection (device flow sign-in)",
+		Up:          up021,
+		Down:        down021,
+		Impact:      Impact{Collections: []string{"auth_device_codes"}, IndexBuilds: 3},
+	})
+}
+
+func up021(ctx context.Context, db *mongo.Database) error {
+	deviceCodesCollection := db.Collection("auth_device_codes")
+
+	indexes := []mongo.IndexModel{
+		{
+			// Tools poll with the device code
+			Keys:    bson.D{{Key: "device_code_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Users approve by user code; a generated code that is in use is regenerated
+			Keys:    bson.D{{Key: "user_code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Device codes nobody redeemed are removed once expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(30 * time.Second)
+	_, err := deviceCodesCollection.Indexes().CreateMany(ctx, indexes, opts)
+	if err != nil && !isIndexExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func down021(ctx context.Context, db *mongo.Database) error {
+	deviceCodesCollection := db.Collection("auth_device_codes")
+	if _, err := deviceCodesCollection.Indexes().DropAll(ctx); err != nil {
+		return err
+	}
+	return nil
+}
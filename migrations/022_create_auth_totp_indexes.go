@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	Register(Migration{
+		Version:     "022_create_auth_totp_indexes",
+		Description: "Create indexes for auth_totp collection (authenticator enrollments)",
+		Up:          up022,
+		Down:        down022,
+		Impact:      Impact{Collections: []string{"auth_totp"}, IndexBuilds: 1},
+	})
+}
+
+func up022(ctx context.Context, db *mongo.Database) error {
+	totpCollection := db.Collection("auth_totp")
+
+	indexes := []mongo.IndexModel{
+		{
+			// One authenticator per account; replacing a pending enrollment must not add a second
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(30 * time.Second)
+	_, err := totpCollection.Indexes().CreateMany(ctx, indexes, opts)
+	if err != nil && !isIndexExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func down022(ctx context.Context, db *mongo.Database) error {
+	totpCollection := db.Collection("auth_totp")
+	if _, err := totpCollection.Indexes().DropAll(ctx); err != nil {
+		return err
+	}
+	return nil
+}
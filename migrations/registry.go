@@ -14,6 +14,9 @@ func Register(migration Migration) {
 		Description: migration.Description,
 		Up:          migration.Up,
 		Down:        migration.Down,
+
+		Irreversible: migration.Irreversible,
+		Impact:       migration.Impact,
 	})
 }
 
@@ -23,8 +26,15 @@ type Migration struct {
 	Description string
 	Up          migrations.MigrationFunc
 	Down        migrations.MigrationFunc
+	// Irreversible marks a Down that loses data written after the migration ran
+	Irreversible bool
+	// Impact lists the collections touched and indexes built, for warnings on large data
+	Impact Impact
 }
 
+// Impact is the cost estimate of a migration, see migrations.Impact
+type Impact = migrations.Impact
+
 // RegisterAll registers all migrations with the runner
 func RegisterAll(runner *migrations.Runner) {
 	for _, m := range registeredMigrations {
@@ -0,0 +1,102 @@
+package apidocs
+
+import (
+	"encoding/json"
+)
+
+// httpMethods are the OpenAPI path item keys that hold operations
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Viewer describes who a filtered spec is rendered for
+type Viewer struct {
+	Authenticated bool
+	HasPermission func(permissionID string) bool
+}
+
+// FilterSpec removes the operations a viewer cannot call from an encoded OpenAPI document.
+// Operations with a security requirement are hidden from anonymous viewers, and operations
+// annotated with PermissionExtension are hidden unless the viewer holds one of the listed permissions
+// (all of them under PermissionModeAll).
+// Tags left without operations are dropped as well.
+func FilterSpec(spec []byte, viewer Viewer) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+
+	usedTags := make(map[string]bool)
+	paths, _ := doc["paths"].(map[string]any)
+	for path, item := range paths {
+		pathItem, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		remaining := 0
+		for _, method := range httpMethods {
+			op, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			if !viewer.canCall(op) {
+				delete(pathItem, method)
+				continue
+			}
+			remaining++
+			if tags, ok := op["tags"].([]any); ok {
+				for _, tag := range tags {
+					if name, ok := tag.(string); ok {
+						usedTags[name] = true
+					}
+				}
+			}
+		}
+
+		if remaining == 0 {
+			delete(paths, path)
+		}
+	}
+
+	if tags, ok := doc["tags"].([]any); ok {
+		kept := make([]any, 0, len(tags))
+		for _, tag := range tags {
+			if t, ok := tag.(map[string]any); ok {
+				if name, _ := t["name"].(string); !usedTags[name] {
+					continue
+				}
+			}
+			kept = append(kept, tag)
+		}
+		doc["tags"] = kept
+	}
+
+	return json.Marshal(doc)
+}
+
+// canCall reports whether the viewer may call an encoded operation
+func (v Viewer) canCall(op map[string]any) bool {
+	if security, ok := op["security"].([]any); ok && len(security) > 0 && !v.Authenticated {
+		return false
+	}
+
+	required, ok := op[PermissionExtension].([]any)
+	if !ok || len(required) == 0 {
+		return true
+	}
+	if !v.Authenticated || v.HasPermission == nil {
+		return false
+	}
+
+	all := op[PermissionModeExtension] == PermissionModeAll
+	for _, permission := range required {
+		id, _ := permission.(string)
+		held := id != "" && v.HasPermission(id)
+		if held && !all {
+			return true
+		}
+		if !held && all {
+			return false
+		}
+	}
+	return all
+}
@@ -0,0 +1,84 @@
+package apidocs
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// FilterQueryParam and FilterViewerValue select the per-viewer spec: GET /openapi.json?filter=viewer
+const (
+	FilterQueryParam  = "filter"
+	FilterViewerValue = "viewer"
+)
+
+// Authenticator resolves the caller of a spec request and checks their permissions
+type Authenticator interface {
+	RequireAuth(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error)
+	GetPermissionChecker() middleware.PermissionChecker
+}
+
+// FilterMiddleware serves a copy of the spec tailored to the caller when specPath is requested with
+// ?filter=viewer. All other requests, including the unfiltered spec, pass through unchanged.
+func FilterMiddleware(api huma.API, specPath string, auth Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != specPath || r.URL.Query().Get(FilterQueryParam) != FilterViewerValue {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			spec, err := json.Marshal(api.OpenAPI())
+			if err != nil {
+				http.Error(w, "failed to encode OpenAPI specification", http.StatusInternalServerError)
+				return
+			}
+
+			filtered, err := FilterSpec(spec, resolveViewer(r, auth))
+			if err != nil {
+				slog.ErrorContext(r.Context(), "Failed to filter OpenAPI specification", "error", err)
+				http.Error(w, "failed to filter OpenAPI specification", http.StatusInternalServerError)
+				return
+			}
+
+			// The body depends on who is asking, so it must never be shared between callers
+			w.Header().Set("Content-Type", "application/openapi+json")
+			w.Header().Set("Cache-Control", "private, no-store")
+			w.Header().Set("Vary", "Authorization, Cookie")
+			w.Write(filtered)
+		})
+	}
+}
+
+// resolveViewer authenticates the request; failed authentication yields an anonymous viewer
+func resolveViewer(r *http.Request, auth Authenticator) Viewer {
+	ctx := r.Context()
+	user, err := auth.RequireAuth(ctx, r.Header.Get("Authorization"), r.Header.Get("Cookie"))
+	if err != nil || user == nil {
+		return Viewer{}
+	}
+
+	viewer := Viewer{Authenticated: true}
+	checker := auth.GetPermissionChecker()
+	if checker == nil {
+		return viewer
+	}
+
+	// Many operations share a permission, so each one is checked at most once per request
+	checked := make(map[string]bool)
+	viewer.HasPermission = func(permissionID string) bool {
+		if allowed, ok := checked[permissionID]; ok {
+			return allowed
+		}
+		allowed, err := checker.HasPermission(ctx, int64(user.CharacterID), permissionID)
+		checked[permissionID] = err == nil && allowed
+		return checked[permissionID]
+	}
+	return viewer
+}
@@ -0,0 +1,239 @@
+package apidocs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// lockedOperations is the committed list of operation IDs that generated clients depend on
+//
+//go:embed operations.lock.json
+var lockedOperations []byte
+
+var (
+	// operationIDPattern is the kebab-case form new operation IDs must use, e.g. "corporation-get-info"
+	operationIDPattern = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+	// tagPattern allows a Title Case area with an optional sub-area, e.g. "Groups / Memberships"
+	tagPattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*( [A-Z][A-Za-z0-9]*)*( / [A-Z][A-Za-z0-9]*( [A-Z][A-Za-z0-9]*)*)?$`)
+)
+
+// Operation is a single operation found in an OpenAPI document
+type Operation struct {
+	ID     string
+	Method string
+	Path   string
+	Tags   []string
+}
+
+// OperationLock lists the operation IDs that must not disappear from the spec
+type OperationLock struct {
+	Operations []string `json:"operations"`
+}
+
+// Issue is a problem found while linting an OpenAPI document
+type Issue struct {
+	OperationID string
+	Method      string
+	Path        string
+	Message     string
+}
+
+func (i Issue) String() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s %s (%s): %s", i.Method, i.Path, i.OperationID, i.Message)
+}
+
+// DefaultLock returns the operation lock embedded in the binary
+func DefaultLock() (OperationLock, error) {
+	return ParseLock(lockedOperations)
+}
+
+// ParseLock decodes an operation lock file
+func ParseLock(data []byte) (OperationLock, error) {
+	var lock OperationLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return OperationLock{}, fmt.Errorf("invalid operation lock: %w", err)
+	}
+	return lock, nil
+}
+
+// NewLock builds a lock from the operations currently in a spec
+func NewLock(operations []Operation) OperationLock {
+	ids := make([]string, 0, len(operations))
+	for _, op := range operations {
+		if op.ID != "" {
+			ids = append(ids, op.ID)
+		}
+	}
+	sort.Strings(ids)
+	return OperationLock{Operations: ids}
+}
+
+// Encode renders the lock in the committed file format
+func (l OperationLock) Encode() ([]byte, error) {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Operations lists the operations in an encoded OpenAPI document, sorted by path and method,
+// along with the tag names declared at the top level of the document
+func Operations(spec []byte) ([]Operation, []string, error) {
+	var doc struct {
+		Tags []struct {
+			Name string `json:"name"`
+		} `json:"tags"`
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	var operations []Operation
+	for path, item := range doc.Paths {
+		for _, method := range httpMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op struct {
+				OperationID string   `json:"operationId"`
+				Tags        []string `json:"tags"`
+			}
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, nil, fmt.Errorf("invalid operation %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			operations = append(operations, Operation{
+				ID:     op.OperationID,
+				Method: strings.ToUpper(method),
+				Path:   path,
+				Tags:   op.Tags,
+			})
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Path != operations[j].Path {
+			return operations[i].Path < operations[j].Path
+		}
+		return operations[i].Method < operations[j].Method
+	})
+
+	declared := make([]string, 0, len(doc.Tags))
+	for _, tag := range doc.Tags {
+		declared = append(declared, tag.Name)
+	}
+	return operations, declared, nil
+}
+
+// Lint checks an encoded OpenAPI document against the operation ID and tag conventions:
+//   - every operation has a unique, explicitly declared operation ID
+//   - new operation IDs are kebab-case; IDs already in the lock are kept as they are
+//   - every operation ID in the lock is still present, so renames are caught before clients break
+//   - every operation has exactly one tag, declared at the top level of the document
+//   - tags are Title Case and use "Area / Sub-area" rather than "Area Sub-area"
+func Lint(spec []byte, lock OperationLock) ([]Issue, error) {
+	operations, declaredTags, err := Operations(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make(map[string]bool, len(lock.Operations))
+	for _, id := range lock.Operations {
+		locked[id] = true
+	}
+	declared := make(map[string]bool, len(declaredTags))
+	for _, tag := range declaredTags {
+		declared[tag] = true
+	}
+	areas := tagAreas(operations)
+
+	var issues []Issue
+	report := func(op Operation, format string, args ...any) {
+		issues = append(issues, Issue{
+			OperationID: op.ID,
+			Method:      op.Method,
+			Path:        op.Path,
+			Message:     fmt.Sprintf(format, args...),
+		})
+	}
+
+	seen := make(map[string]Operation)
+	for _, op := range operations {
+		switch {
+		case op.ID == "":
+			report(op, "missing operation ID")
+		case seen[op.ID].ID != "":
+			first := seen[op.ID]
+			report(op, "operation ID already used by %s %s", first.Method, first.Path)
+		default:
+			seen[op.ID] = op
+			if !locked[op.ID] {
+				if !operationIDPattern.MatchString(op.ID) {
+					report(op, "operation ID must be kebab-case, e.g. \"module-get-thing\"")
+				}
+				report(op, "operation ID is not in the lock; run `make openapi-lock` if it is new")
+			}
+		}
+
+		if len(op.Tags) != 1 {
+			report(op, "expected exactly one tag, got %d", len(op.Tags))
+		}
+		for _, tag := range op.Tags {
+			if !tagPattern.MatchString(tag) {
+				report(op, "tag %q must be Title Case, optionally \"Area / Sub-area\"", tag)
+			} else if area := misplacedArea(tag, areas); area != "" {
+				report(op, "tag %q should be written %q", tag, area+" / "+strings.TrimPrefix(tag, area+" "))
+			}
+			if !declared[tag] {
+				report(op, "tag %q is not declared in the API tags", tag)
+			}
+		}
+	}
+
+	for _, id := range lock.Operations {
+		if _, ok := seen[id]; !ok {
+			issues = append(issues, Issue{
+				OperationID: id,
+				Message:     fmt.Sprintf("locked operation ID %q was removed or renamed; generated clients calling it will break", id),
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+// tagAreas collects the area part of every tag, e.g. "Groups" for "Groups / Memberships"
+func tagAreas(operations []Operation) map[string]bool {
+	areas := make(map[string]bool)
+	for _, op := range operations {
+		for _, tag := range op.Tags {
+			area, _, _ := strings.Cut(tag, " / ")
+			areas[area] = true
+		}
+	}
+	return areas
+}
+
+// misplacedArea returns the area a tag starts with when it should have been written as a sub-area,
+// e.g. "Market" for "Market Orders" when "Market / ..." tags exist
+func misplacedArea(tag string, areas map[string]bool) string {
+	if strings.Contains(tag, " / ") {
+		return ""
+	}
+	words := strings.Fields(tag)
+	for n := len(words) - 1; n > 0; n-- {
+		if prefix := strings.Join(words[:n], " "); areas[prefix] {
+			return prefix
+		}
+	}
+	return ""
+}
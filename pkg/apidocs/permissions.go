@@ -0,0 +1,25 @@
+package apidocs
+
+// PermissionExtension is the OpenAPI operation extension listing the permissions that grant access
+// to an operation. Holding any one of them is enough, mirroring RequireAnyPermission.
+const PermissionExtension = "x-falcon-permission"
+
+// PermissionModeExtension marks operations that require every permission listed in
+// PermissionExtension rather than any one of them
+const PermissionModeExtension = "x-falcon-permission-mode"
+
+// PermissionModeAll is the PermissionModeExtension value requiring all listed permissions
+const PermissionModeAll = "all"
+
+// SuperAdminPermission is the permission RequireSuperAdmin checks; only administrators hold it
+const SuperAdminPermission = "system:admin:full"
+
+// RequiresPermission returns operation extensions declaring the permissions an operation requires
+func RequiresPermission(permissionIDs ...string) map[string]any {
+	return map[string]any{PermissionExtension: permissionIDs}
+}
+
+// RequiresAllPermissions returns operation extensions declaring permissions that are all required
+func RequiresAllPermissions(permissionIDs ...string) map[string]any {
+	return map[string]any{PermissionExtension: permissionIDs, PermissionModeExtension: PermissionModeAll}
+}
@@ -0,0 +1,180 @@
+// Package authz lets Huma operations declare the falcon permissions and ESI scopes they require.
+// Protect enforces the requirements before the handler runs and documents them in the OpenAPI spec.
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/apidocs"
+	"go-falcon/pkg/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Requirement declares what a caller needs to call an operation; see Protect
+type Requirement func(*accessRule)
+
+// accessRule collects the requirements of one operation
+type accessRule struct {
+	permissions []string
+	mode        middleware.PermissionMode
+	scopes      []string
+	superAdmin  bool
+	allowStaff  bool
+}
+
+// Permissions requires every listed falcon permission
+func Permissions(permissionIDs ...string) Requirement {
+	return func(rule *accessRule) {
+		rule.permissions = append(rule.permissions, permissionIDs...)
+		rule.mode = middleware.PermissionModeAND
+	}
+}
+
+// AnyPermission requires at least one of the listed falcon permissions
+func AnyPermission(permissionIDs ...string) Requirement {
+	return func(rule *accessRule) {
+		rule.permissions = append(rule.permissions, permissionIDs...)
+		rule.mode = middleware.PermissionModeOR
+	}
+}
+
+// Scopes requires ESI scopes granted to the caller's active character, as carried by their token
+func Scopes(scopes ...string) Requirement {
+	return func(rule *accessRule) {
+		rule.scopes = append(rule.scopes, scopes...)
+	}
+}
+
+// SuperAdmin requires a super admin, like RequireSuperAdmin
+func SuperAdmin() Requirement {
+	return func(rule *accessRule) {
+		rule.superAdmin = true
+	}
+}
+
+// AllowStaff admits staff accounts to an operation that only requires a signed-in caller. Staff
+// are refused there by default; operations with permissions check staff against their own list.
+func AllowStaff() Requirement {
+	return func(rule *accessRule) {
+		rule.allowStaff = true
+	}
+}
+
+// Protect returns the operation with its requirements enforced by an operation middleware and
+// documented in the OpenAPI spec: the security requirements list the permissions and scopes as
+// roles, and the permission extension lets the viewer-filtered spec hide the operation. The
+// handler runs with the caller in its context (middleware.GetAuthenticatedUser), so its input
+// needs no Authorization or Cookie fields. Without requirements the operation only requires a
+// signed-in caller.
+//
+//	huma.Register(api, authz.Protect(api, permissionMiddleware, huma.Operation{...},
+//		authz.Permissions("auth:audit:read")), handler)
+func Protect(api huma.API, pm *middleware.PermissionMiddleware, op huma.Operation, requirements ...Requirement) huma.Operation {
+	rule := &accessRule{mode: middleware.PermissionModeAND}
+	for _, requirement := range requirements {
+		requirement(rule)
+	}
+
+	op.Security = rule.security()
+	if extensions := rule.extensions(); extensions != nil {
+		if op.Extensions == nil {
+			op.Extensions = map[string]any{}
+		}
+		for name, value := range extensions {
+			op.Extensions[name] = value
+		}
+	}
+
+	op.Middlewares = append(op.Middlewares, func(ctx huma.Context, next func(huma.Context)) {
+		user, err := check(ctx.Context(), pm, ctx.Header("Authorization"), ctx.Header("Cookie"), rule)
+		if err != nil {
+			var statusErr huma.StatusError
+			if errors.As(err, &statusErr) {
+				huma.WriteErr(api, ctx, statusErr.GetStatus(), statusErr.Error())
+			} else {
+				huma.WriteErr(api, ctx, http.StatusInternalServerError, "Permission check failed", err)
+			}
+			return
+		}
+		next(huma.WithContext(ctx, context.WithValue(ctx.Context(), middleware.AuthContextKeyUser, user)))
+	})
+	return op
+}
+
+// check authenticates the caller and enforces the rule
+func check(ctx context.Context, pm *middleware.PermissionMiddleware, authHeader, cookieHeader string, rule *accessRule) (*models.AuthenticatedUser, error) {
+	var user *models.AuthenticatedUser
+	var err error
+	switch {
+	case rule.superAdmin:
+		user, err = pm.RequireSuperAdmin(ctx, authHeader, cookieHeader)
+	case len(rule.permissions) == 0 && rule.allowStaff:
+		user, err = pm.RequireAuthAllowStaff(ctx, authHeader, cookieHeader)
+	case len(rule.permissions) == 0:
+		user, err = pm.RequireAuth(ctx, authHeader, cookieHeader)
+	case len(rule.permissions) == 1:
+		user, err = pm.RequirePermission(ctx, authHeader, cookieHeader, rule.permissions[0])
+	case rule.mode == middleware.PermissionModeOR:
+		user, err = pm.RequireAnyPermission(ctx, authHeader, cookieHeader, rule.permissions)
+	default:
+		user, err = pm.RequireAllPermissions(ctx, authHeader, cookieHeader, rule.permissions)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	granted := strings.Fields(user.Scopes)
+	for _, scope := range rule.scopes {
+		if !slices.Contains(granted, scope) {
+			return nil, huma.Error403Forbidden(fmt.Sprintf("ESI scopes required: %s", strings.Join(rule.scopes, " ")))
+		}
+	}
+	return user, nil
+}
+
+// security returns the OpenAPI security requirements of the rule. Roles within a requirement are
+// all needed, so holding any one permission becomes one requirement per permission.
+func (rule *accessRule) security() []map[string][]string {
+	var alternatives [][]string
+	switch {
+	case rule.superAdmin:
+		alternatives = [][]string{{apidocs.SuperAdminPermission}}
+	case rule.mode == middleware.PermissionModeOR && len(rule.permissions) > 0:
+		for _, permissionID := range rule.permissions {
+			alternatives = append(alternatives, []string{permissionID})
+		}
+	default:
+		alternatives = [][]string{rule.permissions}
+	}
+
+	security := make([]map[string][]string, 0, 2*len(alternatives))
+	for _, permissionIDs := range alternatives {
+		roles := append(append([]string{}, permissionIDs...), rule.scopes...)
+		security = append(security,
+			map[string][]string{"bearerAuth": roles},
+			map[string][]string{"cookieAuth": roles},
+		)
+	}
+	return security
+}
+
+// extensions returns the permission extensions of the rule, or nil when it has no permissions
+func (rule *accessRule) extensions() map[string]any {
+	switch {
+	case rule.superAdmin:
+		return apidocs.RequiresPermission(apidocs.SuperAdminPermission)
+	case len(rule.permissions) == 0:
+		return nil
+	case rule.mode == middleware.PermissionModeAND && len(rule.permissions) > 1:
+		return apidocs.RequiresAllPermissions(rule.permissions...)
+	default:
+		return apidocs.RequiresPermission(rule.permissions...)
+	}
+}
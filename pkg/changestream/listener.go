@@ -0,0 +1,235 @@
+package changestream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RedisChannel is the pub/sub channel change events are published on
+const RedisChannel = "changestream:events"
+
+// DefaultCollections are the collections watched when none are configured
+var DefaultCollections = []string{
+	"groups",
+	"group_memberships",
+	"permissions",
+	"group_permissions",
+	"site_settings",
+	"routes",
+}
+
+// Event describes a single change observed on a watched collection
+type Event struct {
+	Collection    string    `json:"collection"`
+	OperationType string    `json:"operation_type"`
+	DocumentID    string    `json:"document_id,omitempty"`
+	UpdatedFields []string  `json:"updated_fields,omitempty"`
+	ServerID      string    `json:"server_id,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// Handler is notified of every change event observed by the listener
+type Handler func(ctx context.Context, event Event)
+
+// Listener watches MongoDB change streams and fans events out to Redis and local handlers
+type Listener struct {
+	db          *mongo.Database
+	redis       *redis.Client
+	serverID    string
+	collections []string
+
+	mu       sync.RWMutex
+	handlers []Handler
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewListener creates a change-stream listener for the given collections
+func NewListener(db *mongo.Database, redisClient *redis.Client, serverID string, collections []string) *Listener {
+	if len(collections) == 0 {
+		collections = DefaultCollections
+	}
+	return &Listener{
+		db:          db,
+		redis:       redisClient,
+		serverID:    serverID,
+		collections: collections,
+	}
+}
+
+// AddHandler registers a handler invoked for each change event
+func (l *Listener) AddHandler(handler Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers = append(l.handlers, handler)
+}
+
+// Collections returns the collections being watched
+func (l *Listener) Collections() []string {
+	return l.collections
+}
+
+// Start begins watching every configured collection in its own goroutine
+func (l *Listener) Start(ctx context.Context) {
+	ctx, l.cancel = context.WithCancel(ctx)
+
+	for _, name := range l.collections {
+		l.wg.Add(1)
+		go func(collection string) {
+			defer l.wg.Done()
+			l.watch(ctx, collection)
+		}(name)
+	}
+
+	slog.Info("Change stream listener started", "collections", l.collections, "server_id", l.serverID)
+}
+
+// Stop cancels all watchers and waits for them to exit
+func (l *Listener) Stop() {
+	if l.cancel != nil {
+		l.cancel()
+	}
+	l.wg.Wait()
+}
+
+// watch keeps a change stream open on a collection, reconnecting with backoff on failure
+func (l *Listener) watch(ctx context.Context, collection string) {
+	var resumeToken bson.Raw
+	backoff := time.Second
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		opts := options.ChangeStream()
+		if resumeToken != nil {
+			opts.SetResumeAfter(resumeToken)
+		}
+
+		stream, err := l.db.Collection(collection).Watch(ctx, mongo.Pipeline{}, opts)
+		if err != nil {
+			slog.Warn("Failed to open change stream", "collection", collection, "error", err, "retry_in", backoff)
+			if !sleepContext(ctx, backoff) {
+				return
+			}
+			backoff = nextBackoff(backoff)
+			continue
+		}
+
+		received := false
+		for stream.Next(ctx) {
+			received = true
+			resumeToken = stream.ResumeToken()
+			l.dispatch(ctx, collection, stream.Current)
+		}
+
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			slog.Warn("Change stream interrupted", "collection", collection, "error", err, "retry_in", backoff)
+			// A stale resume token can make every subsequent Watch fail, so start fresh
+			resumeToken = nil
+		}
+		stream.Close(context.Background())
+
+		// A stream that delivered events was healthy; one that ends right away (dropped
+		// collection, failing cursor) must not be reopened in a tight loop
+		if received {
+			backoff = time.Second
+		}
+		if !sleepContext(ctx, backoff) {
+			return
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// dispatch decodes a raw change document and delivers it to Redis and local handlers
+func (l *Listener) dispatch(ctx context.Context, collection string, raw bson.Raw) {
+	var change struct {
+		OperationType string `bson:"operationType"`
+		DocumentKey   struct {
+			ID interface{} `bson:"_id"`
+		} `bson:"documentKey"`
+		UpdateDescription struct {
+			UpdatedFields bson.M `bson:"updatedFields"`
+		} `bson:"updateDescription"`
+	}
+	if err := bson.Unmarshal(raw, &change); err != nil {
+		slog.Error("Failed to decode change event", "collection", collection, "error", err)
+		return
+	}
+
+	event := Event{
+		Collection:    collection,
+		OperationType: change.OperationType,
+		ServerID:      l.serverID,
+		Timestamp:     time.Now(),
+	}
+	if change.DocumentKey.ID != nil {
+		event.DocumentID = formatID(change.DocumentKey.ID)
+	}
+	for field := range change.UpdateDescription.UpdatedFields {
+		event.UpdatedFields = append(event.UpdatedFields, field)
+	}
+
+	if err := l.publish(ctx, event); err != nil {
+		slog.Error("Failed to publish change event", "collection", collection, "error", err)
+	}
+
+	l.mu.RLock()
+	handlers := append([]Handler(nil), l.handlers...)
+	l.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
+
+// publish sends the event to the shared Redis channel for other consumers
+func (l *Listener) publish(ctx context.Context, event Event) error {
+	if l.redis == nil {
+		return nil
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal change event: %w", err)
+	}
+	return l.redis.Publish(ctx, RedisChannel, data).Err()
+}
+
+func formatID(id interface{}) string {
+	if oid, ok := id.(interface{ Hex() string }); ok {
+		return oid.Hex()
+	}
+	if stringer, ok := id.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%v", id)
+}
+
+func nextBackoff(current time.Duration) time.Duration {
+	next := current * 2
+	if next > time.Minute {
+		return time.Minute
+	}
+	return next
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
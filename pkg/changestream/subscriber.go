@@ -0,0 +1,102 @@
+package changestream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Subscriber consumes the change events every listener publishes on RedisChannel, so instances
+// react to changes whether or not they watch MongoDB themselves. Each listening instance publishes
+// the same change, so handlers must be idempotent (cache invalidations are).
+type Subscriber struct {
+	redis *redis.Client
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+
+	pubsub *redis.PubSub
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSubscriber creates a change event subscriber; without a Redis client it receives nothing
+func NewSubscriber(redisClient *redis.Client) *Subscriber {
+	return &Subscriber{
+		redis:    redisClient,
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Handle registers a handler run for every change event of the given collections
+func (s *Subscriber) Handle(handler Handler, collections ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, collection := range collections {
+		s.handlers[collection] = append(s.handlers[collection], handler)
+	}
+}
+
+// Collections returns the collections that have handlers, ordered by name
+func (s *Subscriber) Collections() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	collections := make([]string, 0, len(s.handlers))
+	for collection := range s.handlers {
+		collections = append(collections, collection)
+	}
+	sort.Strings(collections)
+	return collections
+}
+
+// Start subscribes to the change event channel
+func (s *Subscriber) Start(ctx context.Context) {
+	if s.redis == nil {
+		return
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.pubsub = s.redis.Subscribe(ctx, RedisChannel)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.listen(ctx)
+	}()
+
+	slog.Info("Change event subscriber started", "collections", s.Collections())
+}
+
+// Stop unsubscribes and waits for the subscriber to exit
+func (s *Subscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.pubsub != nil {
+		s.pubsub.Close()
+	}
+	s.wg.Wait()
+}
+
+func (s *Subscriber) listen(ctx context.Context) {
+	for msg := range s.pubsub.Channel() {
+		var event Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			slog.Warn("Failed to decode change event", "error", err)
+			continue
+		}
+
+		s.mu.RLock()
+		handlers := append([]Handler(nil), s.handlers[event.Collection]...)
+		s.mu.RUnlock()
+
+		for _, handler := range handlers {
+			handler(ctx, event)
+		}
+	}
+}
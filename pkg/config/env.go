@@ -64,6 +64,26 @@ func GetIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// GetDurationEnv returns the duration value of an environment variable ("30s", "5m", "7d") or a
+// default value if not set or invalid
+func GetDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := parseDurationWithDays(value)
+	if err != nil {
+		slog.Warn("Invalid duration, using default", "key", key, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// ParseDuration parses a duration like time.ParseDuration, also accepting days ("7d", "1d12h")
+func ParseDuration(s string) (time.Duration, error) {
+	return parseDurationWithDays(s)
+}
+
 // MustGetEnv returns the value of an environment variable or panics if not set
 func MustGetEnv(key string) string {
 	if value := os.Getenv(key); value != "" {
@@ -105,18 +125,139 @@ func GetEVEScopes() string {
 	return GetEnv("EVE_SCOPES", "publicData")
 }
 
+// Staff identity providers. Non-EVE staff accounts sign in through these; each provider is
+// enabled by setting its client ID.
+func GetAuthOIDCIssuer() string {
+	return GetEnv("AUTH_OIDC_ISSUER", "")
+}
+
+func GetAuthOIDCClientID() string {
+	return GetEnv("AUTH_OIDC_CLIENT_ID", "")
+}
+
+func GetAuthOIDCClientSecret() string {
+	return GetEnv("AUTH_OIDC_CLIENT_SECRET", "")
+}
+
+func GetAuthOIDCRedirectURI() string {
+	return GetEnv("AUTH_OIDC_REDIRECT_URI", "http://localhost:8080/auth/providers/oidc/callback")
+}
+
+// GetAuthOIDCDisplayName returns the login button label for the OIDC provider
+func GetAuthOIDCDisplayName() string {
+	return GetEnv("AUTH_OIDC_DISPLAY_NAME", "Single Sign-On")
+}
+
+func GetAuthDiscordClientID() string {
+	return GetEnv("AUTH_DISCORD_CLIENT_ID", "")
+}
+
+func GetAuthDiscordClientSecret() string {
+	return GetEnv("AUTH_DISCORD_CLIENT_SECRET", "")
+}
+
+func GetAuthDiscordRedirectURI() string {
+	return GetEnv("AUTH_DISCORD_REDIRECT_URI", "http://localhost:8080/auth/providers/discord/callback")
+}
+
+// GetDiscordClientID returns the client ID of the Discord linking application (not the staff login one)
+func GetDiscordClientID() string {
+	return GetEnv("DISCORD_CLIENT_ID", "")
+}
+
+func GetDiscordClientSecret() string {
+	return GetEnv("DISCORD_CLIENT_SECRET", "")
+}
+
+// GetAuthDiscordLinkRedirectURI returns the callback of /auth/discord/login, which links a Discord
+// account to the signed-in user through the DISCORD_CLIENT_ID application
+func GetAuthDiscordLinkRedirectURI() string {
+	return GetEnv("AUTH_DISCORD_LINK_REDIRECT_URI", "http://localhost:8080/auth/discord/callback")
+}
+
 func GetJWTSecret() string {
 	return MustGetEnv("JWT_SECRET")
 }
 
+// GetJWTClaimsVersion returns the claims layout version of issued JWTs (0 for the newest). Pin it
+// to the previous version while a rolling deployment still has replicas that cannot read the new one.
+func GetJWTClaimsVersion() int {
+	return GetIntEnv("JWT_CLAIMS_VERSION", 0)
+}
+
+// GetJWTSigningAlgorithm returns the algorithm of issued JWTs: ES256 signs with the rotating keys
+// published at /.well-known/jwks.json, HS256 with JWT_SECRET. Keep HS256 while a rolling
+// deployment still has replicas that cannot verify ES256 tokens.
+func GetJWTSigningAlgorithm() string {
+	return strings.ToUpper(GetEnv("JWT_SIGNING_ALGORITHM", "ES256"))
+}
+
+// GetJWTKeyRotationInterval returns how long a JWT signing key signs before it is replaced
+func GetJWTKeyRotationInterval() time.Duration {
+	durationStr := GetEnv("JWT_KEY_ROTATION_INTERVAL", "30d")
+	duration, err := parseDurationWithDays(durationStr)
+	if err != nil || duration < 24*time.Hour {
+		slog.Warn("⚠️ Invalid JWT_KEY_ROTATION_INTERVAL (minimum 1d), using default",
+			slog.String("value", durationStr),
+			slog.String("default", "30d"))
+		return 30 * 24 * time.Hour
+	}
+	return duration
+}
+
+// GetJWTCustomClaims returns the claim providers whose claims are added to issued JWTs
+func GetJWTCustomClaims() []string {
+	return GetEnvStringSlice("JWT_CUSTOM_CLAIMS")
+}
+
+// GetJWTCustomClaimsMaxBytes returns the size budget for custom claims in a JWT (JSON-encoded)
+func GetJWTCustomClaimsMaxBytes() int {
+	return GetIntEnv("JWT_CUSTOM_CLAIMS_MAX_BYTES", 1024)
+}
+
 // GetFrontendURL returns the frontend URL for redirects
 func GetFrontendURL() string {
 	return GetEnv("FRONTEND_URL", "https://go.eveonline.it")
 }
 
-// GetCookieDomain returns the cookie domain for auth cookies
+// GetCookieDomain returns the cookie domain for auth cookies; empty for host-only cookies
 func GetCookieDomain() string {
-	return GetEnv("COOKIE_DOMAIN", ".eveonline.it")
+	return CookieDomain(GetEnv("COOKIE_DOMAIN", ".eveonline.it"))
+}
+
+// CookieDomain maps a configured cookie domain to the Domain attribute: "host-only" sets cookies
+// without one, so they are only sent to the host that set them (e.g. localhost in development)
+func CookieDomain(domain string) string {
+	if strings.EqualFold(domain, "host-only") {
+		return ""
+	}
+	return domain
+}
+
+// GetCookieSameSite returns the SameSite attribute of the auth cookie: Lax, Strict or None
+func GetCookieSameSite() string {
+	return GetEnv("COOKIE_SAMESITE", "Lax")
+}
+
+// GetCookieSecure returns whether auth cookies are only sent over HTTPS
+func GetCookieSecure() bool {
+	return GetBoolEnv("COOKIE_SECURE", true)
+}
+
+// GetStepUpEnabled returns whether destructive admin actions require a recent re-authentication
+func GetStepUpEnabled() bool {
+	return GetBoolEnv("STEP_UP_ENABLED", true)
+}
+
+// GetStepUpWindowMinutes returns how long a re-authentication or step-up confirmation stays valid
+func GetStepUpWindowMinutes() int {
+	return GetIntEnv("STEP_UP_WINDOW_MINUTES", 5)
+}
+
+// GetStepUpTOTPRequired returns whether actions that need a second factor are refused to callers
+// without an enrolled authenticator, instead of falling back to a recent sign-in
+func GetStepUpTOTPRequired() bool {
+	return GetBoolEnv("STEP_UP_TOTP_REQUIRED", false)
 }
 
 // GetCookieDuration returns the cookie duration for auth cookies
@@ -135,11 +276,82 @@ func GetCookieDuration() time.Duration {
 	return duration
 }
 
+// GetRefreshTokenDuration returns how long a session refresh token stays valid; every refresh
+// issues a new token with a full lifetime, so this is how long a session may sit idle
+func GetRefreshTokenDuration() time.Duration {
+	durationStr := GetEnv("REFRESH_TOKEN_DURATION", "30d")
+	duration, err := parseDurationWithDays(durationStr)
+	if err != nil {
+		slog.Warn("⚠️ Failed to parse REFRESH_TOKEN_DURATION, using default",
+			slog.String("value", durationStr),
+			slog.String("error", err.Error()),
+			slog.String("default", "30d"))
+		return 30 * 24 * time.Hour
+	}
+	return duration
+}
+
+// GetImpersonationDuration returns how long an admin impersonation token stays valid
+func GetImpersonationDuration() time.Duration {
+	durationStr := GetEnv("IMPERSONATION_DURATION", "30m")
+	duration, err := parseDurationWithDays(durationStr)
+	if err != nil || duration <= 0 {
+		slog.Warn("⚠️ Failed to parse IMPERSONATION_DURATION, using default",
+			slog.String("value", durationStr),
+			slog.String("default", "30m"))
+		return 30 * time.Minute
+	}
+	return duration
+}
+
+// GetAuthThrottleIPLimit returns how many attempts per minute an IP address may make against a
+// throttled sign-in endpoint before it is locked out (0 disables the limit)
+func GetAuthThrottleIPLimit() int {
+	return GetIntEnv("AUTH_THROTTLE_IP_LIMIT", 20)
+}
+
+// GetAuthThrottleCharacterLimit returns how many attempts per minute may name the same character
+// before it is locked out of a throttled sign-in endpoint (0 disables the limit)
+func GetAuthThrottleCharacterLimit() int {
+	return GetIntEnv("AUTH_THROTTLE_CHARACTER_LIMIT", 10)
+}
+
+// GetAuthLockoutMax returns the longest lockout; each repeated lockout within a day doubles the
+// previous one, starting at one minute
+func GetAuthLockoutMax() time.Duration {
+	durationStr := GetEnv("AUTH_LOCKOUT_MAX", "1h")
+	duration, err := parseDurationWithDays(durationStr)
+	if err != nil || duration < time.Minute {
+		slog.Warn("⚠️ Invalid AUTH_LOCKOUT_MAX (minimum 1m), using default",
+			slog.String("value", durationStr),
+			slog.String("default", "1h"))
+		return time.Hour
+	}
+	return duration
+}
+
 // GetEnvInt is an alias for GetIntEnv for backward compatibility
 func GetEnvInt(key string, defaultValue int) int {
 	return GetIntEnv(key, defaultValue)
 }
 
+// GetEnvStringSlice returns the non-empty, trimmed entries of a comma-separated environment variable
+func GetEnvStringSlice(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
 // GetEnvIntSlice returns a slice of integers from a comma-separated environment variable
 func GetEnvIntSlice(key string) []int {
 	value := os.Getenv(key)
@@ -231,6 +443,155 @@ func GetSDEChecksumsURL() string {
 	return GetEnv("SDE_CHECKSUMS_URL", "https://eve-static-data-export.s3-eu-west-1.amazonaws.com/tranquility/checksum")
 }
 
+// GetSDEAutoUpdateEnabled returns whether the scheduled SDE auto-update may import new versions
+func GetSDEAutoUpdateEnabled() bool {
+	return GetBoolEnv("SDE_AUTO_UPDATE_ENABLED", false)
+}
+
+// GetSDEAutoUpdateWindow returns the UTC hour range [start, end) in which automatic SDE imports may run
+func GetSDEAutoUpdateWindow() (int, int) {
+	return GetIntEnv("SDE_AUTO_UPDATE_START_HOUR", 2), GetIntEnv("SDE_AUTO_UPDATE_END_HOUR", 6)
+}
+
+// GetSDEAutoUpdateMaxRedisMemoryPercent returns the Redis memory usage above which automatic imports are postponed
+func GetSDEAutoUpdateMaxRedisMemoryPercent() int {
+	return GetIntEnv("SDE_AUTO_UPDATE_MAX_REDIS_MEMORY_PERCENT", 80)
+}
+
+// GetSDEAutoUpdateMinCountPercent returns the minimum share of the previous entity count each data type must keep after an import
+func GetSDEAutoUpdateMinCountPercent() int {
+	return GetIntEnv("SDE_AUTO_UPDATE_MIN_COUNT_PERCENT", 90)
+}
+
+// GetNotificationBatchSize returns how many recipients a bulk notification delivers per batch
+// (at least 1)
+func GetNotificationBatchSize() int {
+	size := GetIntEnv("NOTIFICATIONS_BATCH_SIZE", 500)
+	if size < 1 {
+		slog.Warn("Invalid NOTIFICATIONS_BATCH_SIZE, using 1", "value", size)
+		return 1
+	}
+	return size
+}
+
+// GetNotificationAckReminderMinutes returns the default delay before an unacknowledged notification is re-sent
+func GetNotificationAckReminderMinutes() int {
+	return GetIntEnv("NOTIFICATIONS_ACK_REMINDER_MINUTES", 60)
+}
+
+// GetNotificationAckMaxReminders returns how many reminders a recipient gets before reminders stop
+func GetNotificationAckMaxReminders() int {
+	return GetIntEnv("NOTIFICATIONS_ACK_MAX_REMINDERS", 3)
+}
+
+// GetAttachmentsMaxSizeMB returns the largest accepted attachment upload in megabytes
+func GetAttachmentsMaxSizeMB() int {
+	return GetIntEnv("ATTACHMENTS_MAX_SIZE_MB", 10)
+}
+
+// GetAttachmentsAllowedTypes returns the content types attachments may have, as detected from
+// their contents rather than taken from the upload
+func GetAttachmentsAllowedTypes() []string {
+	if types := GetEnvStringSlice("ATTACHMENTS_ALLOWED_TYPES"); len(types) > 0 {
+		return types
+	}
+	return []string{"image/png", "image/jpeg", "image/gif", "image/webp", "text/plain", "text/xml"}
+}
+
+// GetAttachmentsURLTTLMinutes returns how long signed attachment download URLs stay valid
+func GetAttachmentsURLTTLMinutes() int {
+	return GetIntEnv("ATTACHMENTS_URL_TTL_MINUTES", 15)
+}
+
+// GetAttachmentsSigningSecret returns the key signing attachment download URLs (defaults to JWT_SECRET)
+func GetAttachmentsSigningSecret() string {
+	if secret := GetEnv("ATTACHMENTS_SIGNING_SECRET", ""); secret != "" {
+		return secret
+	}
+	return GetJWTSecret()
+}
+
+// GetAttachmentsClamdAddress returns the clamd address uploads are scanned with (host:port);
+// empty disables virus scanning
+func GetAttachmentsClamdAddress() string {
+	return GetEnv("ATTACHMENTS_CLAMD_ADDRESS", "")
+}
+
+// GetGroupSnapshotRetentionDays returns how long group membership and permission snapshots are kept
+func GetGroupSnapshotRetentionDays() int {
+	return GetIntEnv("GROUP_SNAPSHOT_RETENTION_DAYS", 365)
+}
+
+// GetGroupMembershipExpiryNoticeHours returns how long before a time-limited group membership
+// expires the member and whoever added them are notified
+func GetGroupMembershipExpiryNoticeHours() int {
+	return GetIntEnv("GROUP_MEMBERSHIP_EXPIRY_NOTICE_HOURS", 24)
+}
+
+// GetGroupAuditRetentionDays returns how long groups audit events stay in the live collection
+// before they are moved to the archive; 0 never archives them
+func GetGroupAuditRetentionDays() int {
+	return GetIntEnv("GROUP_AUDIT_RETENTION_DAYS", 90)
+}
+
+// GetGroupAuditArchiveRetentionDays returns how long archived groups audit events are kept;
+// 0 keeps them forever
+func GetGroupAuditArchiveRetentionDays() int {
+	return GetIntEnv("GROUP_AUDIT_ARCHIVE_RETENTION_DAYS", 0)
+}
+
+// GetPermissionCacheTTLSeconds returns how long permission decisions are cached in Redis;
+// 0 disables the cache
+func GetPermissionCacheTTLSeconds() int {
+	return GetIntEnv("PERMISSION_CACHE_TTL_SECONDS", 300)
+}
+
+// GetUserInactivityEnforce returns whether the inactivity policy notifies, disables and purges users;
+// when false inactive users are only flagged for the report
+func GetUserInactivityEnforce() bool {
+	return GetBoolEnv("USER_INACTIVITY_ENFORCE", false)
+}
+
+// GetUserInactivityMonths returns after how many months without login or in-game activity a user is inactive
+func GetUserInactivityMonths() int {
+	return GetIntEnv("USER_INACTIVITY_MONTHS", 6)
+}
+
+// GetUserInactivityNoticeDays returns how long a notified user has to log in before being disabled
+func GetUserInactivityNoticeDays() int {
+	return GetIntEnv("USER_INACTIVITY_NOTICE_DAYS", 14)
+}
+
+// GetUserInactivityPurgeDays returns how long a disabled user is kept before being purged (0 never purges)
+func GetUserInactivityPurgeDays() int {
+	return GetIntEnv("USER_INACTIVITY_PURGE_DAYS", 90)
+}
+
+// GetUserDeletionGraceDays returns how long a user can cancel their account deletion before it runs
+func GetUserDeletionGraceDays() int {
+	return GetIntEnv("USER_DELETION_GRACE_DAYS", 14)
+}
+
+// GetUserDataExportRetentionDays returns how long a finished data export can be downloaded before it is deleted
+func GetUserDataExportRetentionDays() int {
+	return GetIntEnv("USER_DATA_EXPORT_RETENTION_DAYS", 7)
+}
+
+// GetUserSoftDeleteRetentionDays returns how long a soft-deleted user character can be restored before it is purged
+func GetUserSoftDeleteRetentionDays() int {
+	return GetIntEnv("USER_SOFT_DELETE_RETENTION_DAYS", 30)
+}
+
+// GetMarketHubStationIDs returns the stations compared by the market hub comparison endpoint (empty means the default empire hubs)
+func GetMarketHubStationIDs() []int {
+	return GetEnvIntSlice("MARKET_HUB_STATIONS")
+}
+
+// GetMarketCompareCacheSeconds returns how long a hub price comparison is cached in Redis
+func GetMarketCompareCacheSeconds() int {
+	return GetIntEnv("MARKET_COMPARE_CACHE_SECONDS", 300)
+}
+
 // GetWebSocketURL returns the WebSocket URL from environment
 func GetWebSocketURL() string {
 	return GetEnv("WEBSOCKET_URL", "wss://localhost:3000/websocket/connect")
@@ -258,8 +619,123 @@ func GetWebSocketAllowedOrigins() []string {
 	return result
 }
 
+// GetWebSocketHistoryMaxLen returns how many messages are kept per room for replay (0 disables history)
+func GetWebSocketHistoryMaxLen() int {
+	return GetIntEnv("WEBSOCKET_HISTORY_MAX_LEN", 200)
+}
+
+// GetWebSocketHistoryTTLMinutes returns how long room history is kept after the last message
+func GetWebSocketHistoryTTLMinutes() int {
+	return GetIntEnv("WEBSOCKET_HISTORY_TTL_MINUTES", 10)
+}
+
+// GetWebSocketReplayLimit returns the maximum number of messages replayed to a reconnecting client
+func GetWebSocketReplayLimit() int {
+	return GetIntEnv("WEBSOCKET_REPLAY_LIMIT", 500)
+}
+
+// GetChangeStreamsEnabled returns whether the MongoDB change-stream listener is enabled.
+// Change streams require MongoDB to run as a replica set.
+func GetChangeStreamsEnabled() bool {
+	return GetBoolEnv("CHANGE_STREAMS_ENABLED", false)
+}
+
+// GetChangeStreamCollections returns the collections watched by the change-stream listener
+// Empty means the listener's built-in defaults are used
+func GetChangeStreamCollections() []string {
+	return GetEnvStringSlice("CHANGE_STREAM_COLLECTIONS")
+}
+
+// GetCacheControlEnabled returns whether the Cache-Control policy middleware sets caching headers
+func GetCacheControlEnabled() bool {
+	return GetBoolEnv("CACHE_CONTROL_ENABLED", true)
+}
+
+// GetCacheControlRules returns extra "prefix=class" cache rules that override the built-in ones
+func GetCacheControlRules() []string {
+	return GetEnvStringSlice("CACHE_CONTROL_RULES")
+}
+
+// GetCacheStaticMaxAge returns the browser max-age (seconds) of static SDE responses
+func GetCacheStaticMaxAge() int {
+	return GetIntEnv("CACHE_STATIC_MAX_AGE", 3600)
+}
+
+// GetCacheStaticCDNMaxAge returns the CDN max-age (seconds) of static SDE responses
+func GetCacheStaticCDNMaxAge() int {
+	return GetIntEnv("CACHE_STATIC_CDN_MAX_AGE", 86400)
+}
+
+// GetCacheShortMaxAge returns the browser max-age (seconds) of short-lived public responses
+func GetCacheShortMaxAge() int {
+	return GetIntEnv("CACHE_SHORT_MAX_AGE", 30)
+}
+
+// GetCacheShortCDNMaxAge returns the CDN max-age (seconds) of short-lived public responses
+func GetCacheShortCDNMaxAge() int {
+	return GetIntEnv("CACHE_SHORT_CDN_MAX_AGE", 60)
+}
+
+// GetMetricsEnabled returns whether Prometheus metrics are served on /metrics
+func GetMetricsEnabled() bool {
+	return GetBoolEnv("METRICS_ENABLED", true)
+}
+
+// GetDataResidencyMode returns the storage mode of a sensitive ESI data category
+// (DATA_RESIDENCY_WALLETS, DATA_RESIDENCY_ASSETS): full, cache or disabled
+func GetDataResidencyMode(category string) string {
+	return strings.ToLower(GetEnv("DATA_RESIDENCY_"+strings.ToUpper(category), "full"))
+}
+
 // OpenAPIServer represents an OpenAPI server configuration
 type OpenAPIServer struct {
 	URL         string
 	Description string
 }
+
+// GetLoadSheddingEnabled returns whether low-priority requests are rejected under resource pressure
+func GetLoadSheddingEnabled() bool {
+	return GetBoolEnv("LOAD_SHEDDING_ENABLED", true)
+}
+
+// GetLoadSheddingMaxInFlight returns the number of concurrent requests treated as full load
+func GetLoadSheddingMaxInFlight() int {
+	return GetIntEnv("LOAD_SHEDDING_MAX_IN_FLIGHT", 1000)
+}
+
+// GetLoadSheddingMaxGoroutines returns the goroutine count treated as full load
+func GetLoadSheddingMaxGoroutines() int {
+	return GetIntEnv("LOAD_SHEDDING_MAX_GOROUTINES", 50000)
+}
+
+// GetLoadSheddingMemoryPercent returns the share of the container memory limit treated as full load
+func GetLoadSheddingMemoryPercent() int {
+	return GetIntEnv("LOAD_SHEDDING_MEMORY_PERCENT", 90)
+}
+
+// GetLoadSheddingLowPriorityPercent returns the load (percent of full) at which low-priority requests are shed
+func GetLoadSheddingLowPriorityPercent() int {
+	return GetIntEnv("LOAD_SHEDDING_LOW_PRIORITY_PERCENT", 75)
+}
+
+// GetLoadSheddingRules returns extra "prefix=priority" rules that override the built-in ones
+func GetLoadSheddingRules() []string {
+	return GetEnvStringSlice("LOAD_SHEDDING_RULES")
+}
+
+// GetGroupRateLimitsEnabled returns whether the rate limit policies of groups are enforced
+func GetGroupRateLimitsEnabled() bool {
+	return GetBoolEnv("GROUP_RATE_LIMITS_ENABLED", true)
+}
+
+// GetRequestTimeout returns the time budget of an HTTP request (WebSockets excluded); MongoDB and
+// ESI calls made for the request run within what is left of it
+func GetRequestTimeout() time.Duration {
+	value := GetEnv("REQUEST_TIMEOUT", "60s")
+	timeout, err := parseDurationWithDays(value)
+	if err != nil || timeout <= 0 {
+		slog.Warn("Invalid REQUEST_TIMEOUT, using default", "value", value, "default", "60s")
+		return 60 * time.Second
+	}
+	return timeout
+}
@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SchemaVersionField is the document field holding its schema version. Documents written before a
+// collection adopted versioning have no such field and count as version 0.
+const SchemaVersionField = "schema_version"
+
+// UpgradeFunc rewrites a document from one schema version to the next. It works on the raw
+// document so it can read fields the current model no longer has.
+type UpgradeFunc func(doc bson.M) error
+
+// Schema describes the versions of the documents stored in a collection. Upgrades is keyed by the
+// version an upgrade starts from: Upgrades[1] turns a version 1 document into version 2.
+type Schema struct {
+	Collection string
+	Version    int
+	Upgrades   map[int]UpgradeFunc
+}
+
+var (
+	schemasMu sync.RWMutex
+	schemas   = map[string]*Schema{}
+)
+
+// RegisterSchema adds a collection schema to the registry and returns it. Models register their
+// schema once, typically from a package-level variable, and repositories decode through it.
+// It panics when the collection is already registered or an upgrade step is missing.
+func RegisterSchema(schema Schema) *Schema {
+	for version := 0; version < schema.Version; version++ {
+		if schema.Upgrades[version] == nil {
+			panic(fmt.Sprintf("schema %s: missing upgrade from version %d", schema.Collection, version))
+		}
+	}
+
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+	if _, exists := schemas[schema.Collection]; exists {
+		panic(fmt.Sprintf("schema %s registered twice", schema.Collection))
+	}
+	s := schema
+	schemas[schema.Collection] = &s
+	return &s
+}
+
+// RegisteredSchemas returns the registered schemas ordered by collection
+func RegisteredSchemas() []*Schema {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+
+	list := make([]*Schema, 0, len(schemas))
+	for _, schema := range schemas {
+		list = append(list, schema)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Collection < list[j].Collection })
+	return list
+}
+
+// documentVersion reads the schema version of a document, 0 when it has none
+func documentVersion(doc bson.M) int {
+	switch v := doc[SchemaVersionField].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+// Upgrade brings a document to the current version in place and reports whether it changed.
+// Documents from a newer version (written by a newer deployment during a rollout) are left alone.
+func (s *Schema) Upgrade(doc bson.M) (bool, error) {
+	version := documentVersion(doc)
+	if version >= s.Version {
+		return false, nil
+	}
+	for ; version < s.Version; version++ {
+		if err := s.Upgrades[version](doc); err != nil {
+			return false, fmt.Errorf("upgrade %s document %v from version %d: %w", s.Collection, doc["_id"], version, err)
+		}
+	}
+	doc[SchemaVersionField] = s.Version
+	return true, nil
+}
+
+// Decode upgrades a raw document lazily and unmarshals it into v. Documents already at the current
+// version are unmarshalled directly. The upgrade is not written back; the next save of the model
+// stores the current version, and UpgradeCollection rewrites the rest.
+func (s *Schema) Decode(raw bson.Raw, v interface{}) error {
+	if version, ok := raw.Lookup(SchemaVersionField).AsInt64OK(); ok && int(version) >= s.Version {
+		return bson.Unmarshal(raw, v)
+	}
+
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	if _, err := s.Upgrade(doc); err != nil {
+		return err
+	}
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, v)
+}
+
+// outdatedFilter matches documents below the current version, including unversioned ones
+func (s *Schema) outdatedFilter() bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{SchemaVersionField: bson.M{"$lt": s.Version}},
+		bson.M{SchemaVersionField: bson.M{"$exists": false}},
+	}}
+}
+
+// UpgradeCollection rewrites every outdated document of the collection, for use from a migration.
+// Each document is replaced only if its version is still the one read, so a concurrent save by the
+// application wins over the upgrade. It returns the number of documents upgraded.
+func (s *Schema) UpgradeCollection(ctx context.Context, db *mongo.Database) (int64, error) {
+	collection := db.Collection(s.Collection)
+	cursor, err := collection.Find(ctx, s.outdatedFilter(), options.Find().SetBatchSize(500))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var upgraded int64
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return upgraded, err
+		}
+
+		filter := bson.M{"_id": doc["_id"]}
+		if _, versioned := doc[SchemaVersionField]; versioned {
+			filter[SchemaVersionField] = doc[SchemaVersionField]
+		} else {
+			filter[SchemaVersionField] = bson.M{"$exists": false}
+		}
+
+		if _, err := s.Upgrade(doc); err != nil {
+			return upgraded, err
+		}
+		result, err := collection.ReplaceOne(ctx, filter, doc)
+		if err != nil {
+			return upgraded, fmt.Errorf("replace %s document %v: %w", s.Collection, doc["_id"], err)
+		}
+		upgraded += result.ModifiedCount
+	}
+	return upgraded, cursor.Err()
+}
+
+// SchemaStatus is the number of documents per schema version in a collection
+type SchemaStatus struct {
+	Collection     string        `json:"collection"`
+	CurrentVersion int           `json:"current_version"`
+	Versions       map[int]int64 `json:"versions"`
+	Outdated       int64         `json:"outdated"`
+}
+
+// SchemaReport counts the documents at each version for every registered collection, so it is
+// visible when the documents still at an old version are few enough to drop an upgrade step
+func SchemaReport(ctx context.Context, db *mongo.Database) ([]SchemaStatus, error) {
+	var report []SchemaStatus
+	for _, schema := range RegisteredSchemas() {
+		pipeline := mongo.Pipeline{
+			{{Key: "$group", Value: bson.M{
+				"_id":   bson.M{"$ifNull": bson.A{"$" + SchemaVersionField, 0}},
+				"count": bson.M{"$sum": 1},
+			}}},
+		}
+		cursor, err := db.Collection(schema.Collection).Aggregate(ctx, pipeline)
+		if err != nil {
+			return nil, fmt.Errorf("schema report for %s: %w", schema.Collection, err)
+		}
+
+		var groups []struct {
+			Version int   `bson:"_id"`
+			Count   int64 `bson:"count"`
+		}
+		if err := cursor.All(ctx, &groups); err != nil {
+			return nil, fmt.Errorf("schema report for %s: %w", schema.Collection, err)
+		}
+
+		status := SchemaStatus{
+			Collection:     schema.Collection,
+			CurrentVersion: schema.Version,
+			Versions:       make(map[int]int64, len(groups)),
+		}
+		for _, group := range groups {
+			status.Versions[group.Version] = group.Count
+			if group.Version < schema.Version {
+				status.Outdated += group.Count
+			}
+		}
+		report = append(report, status)
+	}
+	return report, nil
+}
@@ -0,0 +1,82 @@
+// Package deadline tracks the time budget of a request as it flows through the handler, MongoDB
+// and ESI layers, and counts the requests that ran out of it.
+package deadline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Layers a deadline can be exceeded in
+const (
+	LayerHandler = "handler"
+	LayerMongo   = "mongo"
+	LayerESI     = "esi"
+)
+
+// BackgroundRoute labels work that does not belong to an HTTP request (scheduler tasks, workers)
+const BackgroundRoute = "background"
+
+var exceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "request_deadline_exceeded_total",
+	Help: "Operations that ran out of their request's time budget, by route template and layer (handler, mongo, esi).",
+}, []string{"route", "layer"})
+
+// Register adds the deadline collectors to a Prometheus registry
+func Register(registerer prometheus.Registerer) error {
+	return registerer.Register(exceeded)
+}
+
+// Remaining returns the time left before the context's deadline; ok is false without a deadline
+func Remaining(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// Allows reports whether work expected to take d still fits in the context's budget
+func Allows(ctx context.Context, d time.Duration) bool {
+	remaining, ok := Remaining(ctx)
+	return !ok || remaining > d
+}
+
+// Observe counts err against the route of ctx when it is a deadline error
+func Observe(ctx context.Context, layer string, err error) {
+	if err != nil && errors.Is(err, context.DeadlineExceeded) {
+		ObserveExceeded(ctx, layer)
+	}
+}
+
+// ObserveExceeded counts a deadline exceeded in a layer against the route of ctx
+func ObserveExceeded(ctx context.Context, layer string) {
+	exceeded.WithLabelValues(route(ctx), layer).Inc()
+}
+
+// route returns the route template of the request ctx belongs to
+func route(ctx context.Context) string {
+	if rctx := chi.RouteContext(ctx); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+		return "unmatched"
+	}
+	return BackgroundRoute
+}
+
+// Middleware counts the requests whose handler was still running when their deadline passed. It
+// must run inside the middleware that sets the request timeout.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		if errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+			ObserveExceeded(r.Context(), LayerHandler)
+		}
+	})
+}
@@ -1,16 +1,17 @@
 package assets
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"strconv"
 	"time"
 
 	"go-falcon/pkg/config"
+	"go-falcon/pkg/evegateway/paging"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -36,6 +37,10 @@ type Client interface {
 	GetCharacterAssetsWithCache(ctx context.Context, characterID int32, token string) (*AssetsResult, error)
 	GetCorporationAssets(ctx context.Context, corporationID int32, token string) ([]AssetResponse, error)
 	GetCorporationAssetsWithCache(ctx context.Context, corporationID int32, token string) (*AssetsResult, error)
+	GetCharacterAssetNames(ctx context.Context, characterID int32, itemIDs []int64, token string) ([]AssetName, error)
+	GetCharacterAssetLocations(ctx context.Context, characterID int32, itemIDs []int64, token string) ([]AssetLocation, error)
+	GetCorporationAssetNames(ctx context.Context, corporationID int32, itemIDs []int64, token string) ([]AssetName, error)
+	GetCorporationAssetLocations(ctx context.Context, corporationID int32, itemIDs []int64, token string) ([]AssetLocation, error)
 }
 
 // AssetResponse represents an EVE Online asset from ESI
@@ -49,6 +54,28 @@ type AssetResponse struct {
 	IsBlueprintCopy *bool  `json:"is_blueprint_copy,omitempty"`
 }
 
+// AssetName is the player-given name of a singleton container, ship or structure
+type AssetName struct {
+	ItemID int64  `json:"item_id"`
+	Name   string `json:"name"`
+}
+
+// AssetPosition is an item's position in space, in meters
+type AssetPosition struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// AssetLocation is the position of an item in space
+type AssetLocation struct {
+	ItemID   int64         `json:"item_id"`
+	Position AssetPosition `json:"position"`
+}
+
+// maxItemIDsPerRequest is ESI's limit on item IDs per names/locations request
+const maxItemIDsPerRequest = 1000
+
 // CacheManager interface for caching operations
 type CacheManager interface {
 	Get(key string) ([]byte, bool, error)
@@ -88,7 +115,7 @@ func NewAssetsClient(httpClient *http.Client, baseURL, userAgent string, cacheMa
 func (c *ClientImpl) GetCharacterAssets(ctx context.Context, characterID int32, token string) ([]AssetResponse, error) {
 	var span trace.Span
 	endpoint := fmt.Sprintf("/characters/%d/assets/", characterID)
-	cacheKey := fmt.Sprintf("%s%s?token=%s", c.baseURL, endpoint, token)
+	cacheKey := fmt.Sprintf("%s%s", c.baseURL, endpoint)
 
 	// Only create spans if telemetry is enabled
 	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
@@ -122,7 +149,7 @@ func (c *ClientImpl) GetCharacterAssets(ctx context.Context, characterID int32,
 	}
 
 	// Fetch all pages from ESI
-	allAssets, err := c.fetchCharacterAssetsPages(ctx, characterID, token)
+	allAssets, headers, err := c.fetchCharacterAssetsPages(ctx, characterID, token)
 	if err != nil {
 		if span != nil {
 			span.RecordError(err)
@@ -139,6 +166,11 @@ func (c *ClientImpl) GetCharacterAssets(ctx context.Context, characterID int32,
 		span.SetStatus(codes.Ok, "successfully retrieved character assets")
 	}
 
+	// Cache the combined pages so a cache hit returns the complete list
+	if data, err := json.Marshal(allAssets); err == nil {
+		c.cacheManager.Set(cacheKey, data, headers)
+	}
+
 	slog.InfoContext(ctx, "Successfully retrieved character assets", "character_id", characterID, "count", len(allAssets))
 	return allAssets, nil
 }
@@ -147,7 +179,7 @@ func (c *ClientImpl) GetCharacterAssets(ctx context.Context, characterID int32,
 func (c *ClientImpl) GetCorporationAssets(ctx context.Context, corporationID int32, token string) ([]AssetResponse, error) {
 	var span trace.Span
 	endpoint := fmt.Sprintf("/corporations/%d/assets/", corporationID)
-	cacheKey := fmt.Sprintf("%s%s?token=%s", c.baseURL, endpoint, token)
+	cacheKey := fmt.Sprintf("%s%s", c.baseURL, endpoint)
 
 	// Only create spans if telemetry is enabled
 	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
@@ -181,7 +213,7 @@ func (c *ClientImpl) GetCorporationAssets(ctx context.Context, corporationID int
 	}
 
 	// Fetch all pages from ESI
-	allAssets, err := c.fetchCorporationAssetsPages(ctx, corporationID, token)
+	allAssets, headers, err := c.fetchCorporationAssetsPages(ctx, corporationID, token)
 	if err != nil {
 		if span != nil {
 			span.RecordError(err)
@@ -198,6 +230,11 @@ func (c *ClientImpl) GetCorporationAssets(ctx context.Context, corporationID int
 		span.SetStatus(codes.Ok, "successfully retrieved corporation assets")
 	}
 
+	// Cache the combined pages so a cache hit returns the complete list
+	if data, err := json.Marshal(allAssets); err == nil {
+		c.cacheManager.Set(cacheKey, data, headers)
+	}
+
 	slog.InfoContext(ctx, "Successfully retrieved corporation assets", "corporation_id", corporationID, "count", len(allAssets))
 	return allAssets, nil
 }
@@ -205,7 +242,7 @@ func (c *ClientImpl) GetCorporationAssets(ctx context.Context, corporationID int
 // GetCharacterAssetsWithCache retrieves character assets from ESI with cache information
 func (c *ClientImpl) GetCharacterAssetsWithCache(ctx context.Context, characterID int32, token string) (*AssetsResult, error) {
 	endpoint := fmt.Sprintf("/characters/%d/assets/", characterID)
-	cacheKey := fmt.Sprintf("%s%s?token=%s", c.baseURL, endpoint, token)
+	cacheKey := fmt.Sprintf("%s%s", c.baseURL, endpoint)
 
 	// Check cache first and get expiry info
 	cachedData, found, expiresAt, err := c.cacheManager.GetWithExpiry(cacheKey)
@@ -246,7 +283,7 @@ func (c *ClientImpl) GetCharacterAssetsWithCache(ctx context.Context, characterI
 // GetCorporationAssetsWithCache retrieves corporation assets from ESI with cache information
 func (c *ClientImpl) GetCorporationAssetsWithCache(ctx context.Context, corporationID int32, token string) (*AssetsResult, error) {
 	endpoint := fmt.Sprintf("/corporations/%d/assets/", corporationID)
-	cacheKey := fmt.Sprintf("%s%s?token=%s", c.baseURL, endpoint, token)
+	cacheKey := fmt.Sprintf("%s%s", c.baseURL, endpoint)
 
 	// Check cache first and get expiry info
 	cachedData, found, expiresAt, err := c.cacheManager.GetWithExpiry(cacheKey)
@@ -284,41 +321,27 @@ func (c *ClientImpl) GetCorporationAssetsWithCache(ctx context.Context, corporat
 	}, nil
 }
 
-// fetchCharacterAssetsPages handles pagination for character assets
-func (c *ClientImpl) fetchCharacterAssetsPages(ctx context.Context, characterID int32, token string) ([]AssetResponse, error) {
-	var allAssets []AssetResponse
-	page := 1
-
-	for {
-		assets, totalPages, err := c.fetchCharacterAssetsPage(ctx, characterID, token, page)
-		if err != nil {
-			return nil, err
-		}
-
-		allAssets = append(allAssets, assets...)
-
-		// Check if we have more pages
-		if page >= totalPages || len(assets) == 0 {
-			break
-		}
-		page++
-	}
-
-	return allAssets, nil
+// fetchCharacterAssetsPages fetches all pages of character assets, returning the first page's headers for caching
+func (c *ClientImpl) fetchCharacterAssetsPages(ctx context.Context, characterID int32, token string) ([]AssetResponse, http.Header, error) {
+	url := fmt.Sprintf("%s/characters/%d/assets/", c.baseURL, characterID)
+	return paging.FetchAllPages[AssetResponse](ctx, func(ctx context.Context, page int) ([]byte, http.Header, error) {
+		return c.fetchAssetsPage(ctx, paging.PageURL(url, page), token)
+	}, paging.DefaultConcurrency)
 }
 
-// fetchCharacterAssetsPage fetches a single page of character assets
-func (c *ClientImpl) fetchCharacterAssetsPage(ctx context.Context, characterID int32, token string, page int) ([]AssetResponse, int, error) {
-	endpoint := fmt.Sprintf("/characters/%d/assets/", characterID)
-	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
-
-	if page > 1 {
-		url += fmt.Sprintf("?page=%d", page)
-	}
+// fetchCorporationAssetsPages fetches all pages of corporation assets, returning the first page's headers for caching
+func (c *ClientImpl) fetchCorporationAssetsPages(ctx context.Context, corporationID int32, token string) ([]AssetResponse, http.Header, error) {
+	url := fmt.Sprintf("%s/corporations/%d/assets/", c.baseURL, corporationID)
+	return paging.FetchAllPages[AssetResponse](ctx, func(ctx context.Context, page int) ([]byte, http.Header, error) {
+		return c.fetchAssetsPage(ctx, paging.PageURL(url, page), token)
+	}, paging.DefaultConcurrency)
+}
 
+// fetchAssetsPage fetches a single page of character or corporation assets
+func (c *ClientImpl) fetchAssetsPage(ctx context.Context, url, token string) ([]byte, http.Header, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set required headers
@@ -329,125 +352,157 @@ func (c *ClientImpl) fetchCharacterAssetsPage(ctx context.Context, characterID i
 	// Use retry mechanism
 	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
 	if err != nil {
-		slog.ErrorContext(ctx, "Failed to call ESI character assets endpoint", "error", err)
-		return nil, 0, fmt.Errorf("failed to call ESI: %w", err)
+		slog.ErrorContext(ctx, "Failed to call ESI assets endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		slog.ErrorContext(ctx, "ESI character assets endpoint returned error", "status_code", resp.StatusCode)
-		return nil, 0, fmt.Errorf("ESI returned status %d", resp.StatusCode)
-	}
-
-	// Get total pages from headers
-	totalPages := 1
-	if pagesHeader := resp.Header.Get("X-Pages"); pagesHeader != "" {
-		if pages, err := strconv.Atoi(pagesHeader); err == nil {
-			totalPages = pages
-		}
+		slog.ErrorContext(ctx, "ESI assets endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		slog.ErrorContext(ctx, "Failed to read character assets response", "error", err)
-		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+		slog.ErrorContext(ctx, "Failed to read assets response", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	var assets []AssetResponse
-	if err := json.Unmarshal(body, &assets); err != nil {
-		slog.ErrorContext(ctx, "Failed to parse character assets response", "error", err)
-		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
-	}
+	return body, resp.Header, nil
+}
 
-	// Cache the page if it's the first page
-	if page == 1 {
-		cacheKey := fmt.Sprintf("%s%s?token=%s", c.baseURL, endpoint, token)
-		c.cacheManager.Set(cacheKey, body, resp.Header)
+// GetCharacterAssetNames resolves the names of a character's items (containers, ships) by item ID
+func (c *ClientImpl) GetCharacterAssetNames(ctx context.Context, characterID int32, itemIDs []int64, token string) ([]AssetName, error) {
+	var names []AssetName
+	endpoint := fmt.Sprintf("/characters/%d/assets/names/", characterID)
+	if err := c.postItemIDs(ctx, endpoint, itemIDs, token, func(body []byte) error {
+		var batch []AssetName
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return err
+		}
+		names = append(names, batch...)
+		return nil
+	}); err != nil {
+		return nil, err
 	}
-
-	return assets, totalPages, nil
+	return names, nil
 }
 
-// fetchCorporationAssetsPages handles pagination for corporation assets
-func (c *ClientImpl) fetchCorporationAssetsPages(ctx context.Context, corporationID int32, token string) ([]AssetResponse, error) {
-	var allAssets []AssetResponse
-	page := 1
-
-	for {
-		assets, totalPages, err := c.fetchCorporationAssetsPage(ctx, corporationID, token, page)
-		if err != nil {
-			return nil, err
+// GetCharacterAssetLocations resolves the positions in space of a character's items by item ID
+func (c *ClientImpl) GetCharacterAssetLocations(ctx context.Context, characterID int32, itemIDs []int64, token string) ([]AssetLocation, error) {
+	var locations []AssetLocation
+	endpoint := fmt.Sprintf("/characters/%d/assets/locations/", characterID)
+	if err := c.postItemIDs(ctx, endpoint, itemIDs, token, func(body []byte) error {
+		var batch []AssetLocation
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return err
 		}
+		locations = append(locations, batch...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
 
-		allAssets = append(allAssets, assets...)
-
-		// Check if we have more pages
-		if page >= totalPages || len(assets) == 0 {
-			break
+// GetCorporationAssetNames resolves the names of a corporation's items by item ID
+func (c *ClientImpl) GetCorporationAssetNames(ctx context.Context, corporationID int32, itemIDs []int64, token string) ([]AssetName, error) {
+	var names []AssetName
+	endpoint := fmt.Sprintf("/corporations/%d/assets/names/", corporationID)
+	if err := c.postItemIDs(ctx, endpoint, itemIDs, token, func(body []byte) error {
+		var batch []AssetName
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return err
 		}
-		page++
+		names = append(names, batch...)
+		return nil
+	}); err != nil {
+		return nil, err
 	}
+	return names, nil
+}
 
-	return allAssets, nil
+// GetCorporationAssetLocations resolves the positions in space of a corporation's items by item ID
+func (c *ClientImpl) GetCorporationAssetLocations(ctx context.Context, corporationID int32, itemIDs []int64, token string) ([]AssetLocation, error) {
+	var locations []AssetLocation
+	endpoint := fmt.Sprintf("/corporations/%d/assets/locations/", corporationID)
+	if err := c.postItemIDs(ctx, endpoint, itemIDs, token, func(body []byte) error {
+		var batch []AssetLocation
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return err
+		}
+		locations = append(locations, batch...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return locations, nil
 }
 
-// fetchCorporationAssetsPage fetches a single page of corporation assets
-func (c *ClientImpl) fetchCorporationAssetsPage(ctx context.Context, corporationID int32, token string, page int) ([]AssetResponse, int, error) {
-	endpoint := fmt.Sprintf("/corporations/%d/assets/", corporationID)
-	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
+// postItemIDs posts item IDs to a names/locations endpoint in batches of maxItemIDsPerRequest,
+// handing each response body to decode
+func (c *ClientImpl) postItemIDs(ctx context.Context, endpoint string, itemIDs []int64, token string, decode func(body []byte) error) error {
+	var span trace.Span
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegate")
+		ctx, span = tracer.Start(ctx, "evegate.PostAssetItemIDs")
+		defer span.End()
 
-	if page > 1 {
-		url += fmt.Sprintf("?page=%d", page)
+		span.SetAttributes(
+			attribute.String("esi.endpoint", endpoint),
+			attribute.Int("assets.item_ids", len(itemIDs)),
+		)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to create request: %w", err)
-	}
+	for start := 0; start < len(itemIDs); start += maxItemIDsPerRequest {
+		end := start + maxItemIDsPerRequest
+		if end > len(itemIDs) {
+			end = len(itemIDs)
+		}
 
-	// Set required headers
-	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+		requestBody, err := json.Marshal(itemIDs[start:end])
+		if err != nil {
+			return fmt.Errorf("failed to marshal item IDs: %w", err)
+		}
 
-	// Use retry mechanism
-	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
-	if err != nil {
-		slog.ErrorContext(ctx, "Failed to call ESI corporation assets endpoint", "error", err)
-		return nil, 0, fmt.Errorf("failed to call ESI: %w", err)
-	}
-	defer resp.Body.Close()
+		req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, bytes.NewReader(requestBody))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		slog.ErrorContext(ctx, "ESI corporation assets endpoint returned error", "status_code", resp.StatusCode)
-		return nil, 0, fmt.Errorf("ESI returned status %d", resp.StatusCode)
-	}
+		req.Header.Set("User-Agent", c.userAgent)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
 
-	// Get total pages from headers
-	totalPages := 1
-	if pagesHeader := resp.Header.Get("X-Pages"); pagesHeader != "" {
-		if pages, err := strconv.Atoi(pagesHeader); err == nil {
-			totalPages = pages
+		resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+		if err != nil {
+			if span != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "failed to call ESI")
+			}
+			slog.ErrorContext(ctx, "Failed to call ESI assets endpoint", "endpoint", endpoint, "error", err)
+			return fmt.Errorf("failed to call ESI: %w", err)
 		}
-	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		slog.ErrorContext(ctx, "Failed to read corporation assets response", "error", err)
-		return nil, 0, fmt.Errorf("failed to read response: %w", err)
-	}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
 
-	var assets []AssetResponse
-	if err := json.Unmarshal(body, &assets); err != nil {
-		slog.ErrorContext(ctx, "Failed to parse corporation assets response", "error", err)
-		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
-	}
+		if resp.StatusCode != http.StatusOK {
+			if span != nil {
+				span.SetStatus(codes.Error, "ESI returned error status")
+			}
+			slog.ErrorContext(ctx, "ESI assets endpoint returned error", "endpoint", endpoint, "status_code", resp.StatusCode)
+			return fmt.Errorf("ESI returned status %d", resp.StatusCode)
+		}
 
-	// Cache the page if it's the first page
-	if page == 1 {
-		cacheKey := fmt.Sprintf("%s%s?token=%s", c.baseURL, endpoint, token)
-		c.cacheManager.Set(cacheKey, body, resp.Header)
+		if err := decode(body); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
 	}
 
-	return assets, totalPages, nil
+	return nil
 }
@@ -0,0 +1,272 @@
+package evegateway
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-falcon/pkg/config"
+)
+
+// ErrESIUnavailable is returned instead of calling ESI while the circuit breaker of the
+// requested endpoint family is open. Callers can test for it with errors.Is.
+var ErrESIUnavailable = errors.New("ESI is unavailable")
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// CircuitBreakerConfig controls when ESI endpoint families are cut off
+type CircuitBreakerConfig struct {
+	// FailureThreshold is how many consecutive 5xx responses or timeouts open the breaker; 0 disables it
+	FailureThreshold int
+	// OpenDuration is how long an open breaker rejects requests before letting a probe through
+	OpenDuration time.Duration
+}
+
+// LoadCircuitBreakerConfig reads the ESI circuit breaker settings from the environment
+func LoadCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: config.GetIntEnv("ESI_CIRCUIT_BREAKER_THRESHOLD", 5),
+		OpenDuration:     config.GetDurationEnv("ESI_CIRCUIT_BREAKER_OPEN_DURATION", 30*time.Second),
+	}
+}
+
+// CircuitBreakerStats reports the state of one endpoint family's breaker
+type CircuitBreakerStats struct {
+	Family              string     `json:"family"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	Trips               int64      `json:"trips"`
+}
+
+// circuitBreaker tracks one endpoint family. While open it rejects requests; once OpenDuration has
+// passed it lets a single probe through (half-open) and closes again if the probe succeeds.
+type circuitBreaker struct {
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+	trips    int64
+}
+
+// CircuitBreakers keeps a circuit breaker per ESI endpoint family (characters, corporations,
+// markets, ...), so an outage of one part of ESI does not cut off the rest
+type CircuitBreakers struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// NewCircuitBreakers creates the breakers for the given config
+func NewCircuitBreakers(cfg CircuitBreakerConfig) *CircuitBreakers {
+	return &CircuitBreakers{
+		config:   cfg,
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// endpointFamily returns the first path segment after the optional ESI version, e.g.
+// "characters" for /latest/characters/123/assets/
+func endpointFamily(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(segments) > 1 && isVersionSegment(segments[0]) {
+		segments = segments[1:]
+	}
+	if segments[0] == "" {
+		return "root"
+	}
+	return segments[0]
+}
+
+func isVersionSegment(segment string) bool {
+	switch segment {
+	case "latest", "legacy", "dev":
+		return true
+	}
+	return len(segment) > 1 && segment[0] == 'v' && strings.Trim(segment[1:], "0123456789") == ""
+}
+
+// Allow reports whether a request to the family may be sent. In the half-open state only one
+// probe is allowed at a time.
+func (b *CircuitBreakers) Allow(family string) bool {
+	if b == nil || b.config.FailureThreshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker, ok := b.breakers[family]
+	if !ok {
+		return true
+	}
+
+	switch breaker.state {
+	case BreakerOpen:
+		if time.Since(breaker.openedAt) < b.config.OpenDuration {
+			return false
+		}
+		breaker.state = BreakerHalfOpen
+		breaker.probing = true
+		slog.Info("ESI circuit breaker half-open, probing", "family", family)
+		return true
+	case BreakerHalfOpen:
+		if breaker.probing {
+			return false
+		}
+		breaker.probing = true
+		return true
+	}
+	return true
+}
+
+// RetryAfter returns how long requests to the family will still be rejected
+func (b *CircuitBreakers) RetryAfter(family string) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker, ok := b.breakers[family]
+	if !ok || breaker.state == BreakerClosed {
+		return 0
+	}
+	if remaining := b.config.OpenDuration - time.Since(breaker.openedAt); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// RecordSuccess closes the family's breaker
+func (b *CircuitBreakers) RecordSuccess(family string) {
+	if b == nil || b.config.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker, ok := b.breakers[family]
+	if !ok {
+		return
+	}
+	if breaker.state != BreakerClosed {
+		slog.Info("ESI circuit breaker closed", "family", family)
+	}
+	breaker.state = BreakerClosed
+	breaker.failures = 0
+	breaker.probing = false
+}
+
+// RecordFailure counts a 5xx response or timeout, opening the breaker once the threshold is
+// reached. A failed probe reopens it straight away.
+func (b *CircuitBreakers) RecordFailure(family string) {
+	if b == nil || b.config.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker, ok := b.breakers[family]
+	if !ok {
+		breaker = &circuitBreaker{state: BreakerClosed}
+		b.breakers[family] = breaker
+	}
+
+	breaker.failures++
+	if breaker.state == BreakerHalfOpen || (breaker.state == BreakerClosed && breaker.failures >= b.config.FailureThreshold) {
+		breaker.state = BreakerOpen
+		breaker.openedAt = time.Now()
+		breaker.probing = false
+		breaker.trips++
+		slog.Warn("ESI circuit breaker opened",
+			"family", family,
+			"consecutive_failures", breaker.failures,
+			"open_duration", b.config.OpenDuration.String())
+	}
+}
+
+// Release gives up a half-open probe whose outcome is unknown because the caller went away, so
+// the next request probes instead
+func (b *CircuitBreakers) Release(family string) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if breaker, ok := b.breakers[family]; ok && breaker.state == BreakerHalfOpen {
+		breaker.probing = false
+	}
+}
+
+// Stats returns the state of every family that has seen failures, sorted by family
+func (b *CircuitBreakers) Stats() []CircuitBreakerStats {
+	stats := []CircuitBreakerStats{}
+	if b == nil {
+		return stats
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for family, breaker := range b.breakers {
+		entry := CircuitBreakerStats{
+			Family:              family,
+			State:               breaker.state,
+			ConsecutiveFailures: breaker.failures,
+			Trips:               breaker.trips,
+		}
+		if breaker.state != BreakerClosed {
+			openedAt := breaker.openedAt
+			entry.OpenedAt = &openedAt
+		}
+		stats = append(stats, entry)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Family < stats[j].Family })
+	return stats
+}
+
+// staleResponse answers a conditional request while the breaker is open with a synthetic 304, so
+// the calling sub-client serves the copy it already has cached. Its expiry is pushed back until the
+// breaker is due to probe again, and the Warning header marks the data as stale.
+func staleResponse(req *http.Request, retryAfter time.Duration) *http.Response {
+	if req.Method != http.MethodGet || (req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == "") {
+		return nil
+	}
+
+	maxAge := int(retryAfter.Seconds())
+	if maxAge < 1 {
+		maxAge = 1
+	}
+
+	header := make(http.Header)
+	header.Set("Cache-Control", fmt.Sprintf("max-age=%d", maxAge))
+	header.Set("Warning", `110 - "Response is Stale"`)
+
+	return &http.Response{
+		Status:     "304 Not Modified",
+		StatusCode: http.StatusNotModified,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       http.NoBody,
+		Request:    req,
+	}
+}
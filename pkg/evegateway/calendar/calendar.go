@@ -0,0 +1,367 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// EventsResult contains calendar event summaries and cache information
+type EventsResult struct {
+	Data  []EventSummary `json:"data"`
+	Cache CacheInfo      `json:"cache"`
+}
+
+// Client interface for calendar-related ESI operations
+type Client interface {
+	GetCalendarEvents(ctx context.Context, characterID int, fromEventID int32, token string) ([]EventSummary, error)
+	GetCalendarEventsWithCache(ctx context.Context, characterID int, fromEventID int32, token string) (*EventsResult, error)
+	GetCalendarEvent(ctx context.Context, characterID int, eventID int32, token string) (*Event, error)
+	RespondToCalendarEvent(ctx context.Context, characterID int, eventID int32, response string, token string) error
+	GetCalendarEventAttendees(ctx context.Context, characterID int, eventID int32, token string) ([]Attendee, error)
+}
+
+// Event responses. ResponseNotResponded is only reported by ESI; it cannot be sent.
+const (
+	ResponseAccepted     = "accepted"
+	ResponseDeclined     = "declined"
+	ResponseTentative    = "tentative"
+	ResponseNotResponded = "not_responded"
+)
+
+// Event owner types used in Event.OwnerType
+const (
+	OwnerEVEServer   = "eve_server"
+	OwnerCorporation = "corporation"
+	OwnerFaction     = "faction"
+	OwnerCharacter   = "character"
+	OwnerAlliance    = "alliance"
+)
+
+// EventSummary is a calendar entry as listed for a character
+type EventSummary struct {
+	EventID       int32     `json:"event_id"`
+	EventDate     time.Time `json:"event_date"`
+	Title         string    `json:"title"`
+	Importance    int32     `json:"importance"`
+	EventResponse string    `json:"event_response"`
+}
+
+// Event is a calendar event with its description. Duration is in minutes and Text is EVE client markup.
+type Event struct {
+	EventID    int32     `json:"event_id"`
+	Date       time.Time `json:"date"`
+	Duration   int32     `json:"duration"`
+	Title      string    `json:"title"`
+	Text       string    `json:"text"`
+	Importance int32     `json:"importance"`
+	OwnerID    int32     `json:"owner_id"`
+	OwnerName  string    `json:"owner_name"`
+	OwnerType  string    `json:"owner_type"`
+	Response   string    `json:"response"`
+}
+
+// Attendee is a character's response to an event
+type Attendee struct {
+	CharacterID   int32  `json:"character_id"`
+	EventResponse string `json:"event_response"`
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewCalendarClient creates a new calendar client
+func NewCalendarClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetCalendarEvents retrieves the next 50 events starting now, or after fromEventID when set
+// (requires esi-calendar.read_calendar_events.v1)
+func (c *ClientImpl) GetCalendarEvents(ctx context.Context, characterID int, fromEventID int32, token string) ([]EventSummary, error) {
+	var events []EventSummary
+	_, err := c.get(ctx, eventsEndpoint(characterID, fromEventID), token, &events)
+	return events, err
+}
+
+// GetCalendarEventsWithCache retrieves calendar event summaries with cache info
+func (c *ClientImpl) GetCalendarEventsWithCache(ctx context.Context, characterID int, fromEventID int32, token string) (*EventsResult, error) {
+	endpoint := eventsEndpoint(characterID, fromEventID)
+
+	var events []EventSummary
+	cached, err := c.get(ctx, endpoint, token, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventsResult{
+		Data:  events,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)},
+	}, nil
+}
+
+// GetCalendarEvent retrieves the details of an event (requires esi-calendar.read_calendar_events.v1)
+func (c *ClientImpl) GetCalendarEvent(ctx context.Context, characterID int, eventID int32, token string) (*Event, error) {
+	var event Event
+	if _, err := c.get(ctx, fmt.Sprintf("/characters/%d/calendar/%d/", characterID, eventID), token, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// RespondToCalendarEvent accepts, declines or tentatively accepts an event
+// (requires esi-calendar.respond_calendar_events.v1)
+func (c *ClientImpl) RespondToCalendarEvent(ctx context.Context, characterID int, eventID int32, response string, token string) error {
+	switch response {
+	case ResponseAccepted, ResponseDeclined, ResponseTentative:
+	default:
+		return fmt.Errorf("invalid event response %q", response)
+	}
+
+	payload, err := json.Marshal(map[string]string{"response": response})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	return c.put(ctx, fmt.Sprintf("/characters/%d/calendar/%d/", characterID, eventID), token, payload)
+}
+
+// GetCalendarEventAttendees retrieves the responses to an event; only available for events the
+// character may see attendees of (requires esi-calendar.read_calendar_events.v1)
+func (c *ClientImpl) GetCalendarEventAttendees(ctx context.Context, characterID int, eventID int32, token string) ([]Attendee, error) {
+	var attendees []Attendee
+	_, err := c.get(ctx, fmt.Sprintf("/characters/%d/calendar/%d/attendees/", characterID, eventID), token, &attendees)
+	return attendees, err
+}
+
+// get fetches an endpoint into v, serving it from cache while fresh and revalidating it with its
+// ETag once expired
+func (c *ClientImpl) get(ctx context.Context, endpoint, token string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, token, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, token, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs an authenticated GET. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, token, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/calendar")
+		ctx, span = tracer.Start(ctx, "calendar.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI calendar endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI calendar endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// put sends a JSON body once through the plain HTTP client; the retry client cannot replay a
+// request body. ESI answers 204 on success.
+func (c *ClientImpl) put(ctx context.Context, endpoint, token string, payload []byte) error {
+	requestURL := c.baseURL + endpoint
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/calendar")
+		ctx, span = tracer.Start(ctx, "calendar.put")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", requestURL))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, requestURL, bytes.NewReader(payload))
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI calendar endpoint", "url", requestURL, "error", err)
+		return fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		body, _ := io.ReadAll(resp.Body)
+		message := strings.TrimSpace(string(body))
+		slog.ErrorContext(ctx, "ESI calendar endpoint returned error", "url", requestURL, "status_code", resp.StatusCode, "error", message)
+		return fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, message)
+	}
+
+	if span != nil {
+		span.SetStatus(codes.Ok, "request completed")
+	}
+	return nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
+
+// eventsEndpoint adds from_event to the calendar endpoint; the cursor is part of the cache key
+func eventsEndpoint(characterID int, fromEventID int32) string {
+	endpoint := fmt.Sprintf("/characters/%d/calendar/", characterID)
+	if fromEventID > 0 {
+		endpoint += fmt.Sprintf("?from_event=%d", fromEventID)
+	}
+	return endpoint
+}
@@ -65,6 +65,7 @@ type Client interface {
 	GetCharacterShipWithCache(ctx context.Context, characterID int, token string) (*ShipResult, error)
 	GetCharacterWallet(ctx context.Context, characterID int, token string) (*WalletResponse, error)
 	GetCharacterWalletWithCache(ctx context.Context, characterID int, token string) (*WalletResult, error)
+	GetCharacterRoles(ctx context.Context, characterID int, token string) (*RolesResponse, error)
 }
 
 // CharacterInfoResponse represents character public information
@@ -1607,6 +1608,14 @@ type WalletResponse struct {
 	Balance float64 `json:"balance"`
 }
 
+// RolesResponse represents the corporation roles a character holds
+type RolesResponse struct {
+	Roles        []string `json:"roles"`
+	RolesAtBase  []string `json:"roles_at_base,omitempty"`
+	RolesAtHQ    []string `json:"roles_at_hq,omitempty"`
+	RolesAtOther []string `json:"roles_at_other,omitempty"`
+}
+
 // ShipResult represents the result of a ship request with cache info
 type ShipResult struct {
 	Data  *ShipResponse
@@ -2400,3 +2409,146 @@ func (c *CharacterClient) GetCharacterWalletWithCache(ctx context.Context, chara
 		Cache: CacheInfo{Cached: cached, ExpiresAt: cacheExpiry},
 	}, nil
 }
+
+// GetCharacterRoles retrieves the corporation roles of a character from ESI
+// (requires the esi-characters.read_corporation_roles.v1 scope)
+func (c *CharacterClient) GetCharacterRoles(ctx context.Context, characterID int, token string) (*RolesResponse, error) {
+	var span trace.Span
+	endpoint := fmt.Sprintf("/characters/%d/roles/", characterID)
+	cacheKey := fmt.Sprintf("%s%s:%s", c.baseURL, endpoint, token)
+
+	// Only create spans if telemetry is enabled
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegate/character")
+		ctx, span = tracer.Start(ctx, "character.GetCharacterRoles")
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("esi.endpoint", "character.roles"),
+			attribute.Int("esi.character_id", characterID),
+			attribute.String("esi.base_url", c.baseURL),
+			attribute.String("cache.key", cacheKey),
+			attribute.Bool("auth.required", true),
+		)
+	}
+
+	slog.InfoContext(ctx, "Requesting character roles from ESI", "character_id", characterID)
+
+	// Check cache first
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		var roles RolesResponse
+		if err := json.Unmarshal(cachedData, &roles); err == nil {
+			if span != nil {
+				span.SetAttributes(attribute.Bool("cache.hit", true))
+				span.SetStatus(codes.Ok, "cache hit")
+			}
+			slog.InfoContext(ctx, "Using cached character roles", "character_id", characterID)
+			return &roles, nil
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+endpoint, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		slog.ErrorContext(ctx, "Failed to create character roles request", "error", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	// Add conditional headers if we have cached data
+	c.cacheManager.SetConditionalHeaders(req, cacheKey)
+
+	if span != nil {
+		span.SetAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.String()),
+		)
+	}
+
+	// Use retry mechanism
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI character roles endpoint", "error", err)
+		return nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(
+			attribute.Int("http.status_code", resp.StatusCode),
+			attribute.String("http.status_text", resp.Status),
+		)
+	}
+
+	// Handle 304 Not Modified
+	if resp.StatusCode == http.StatusNotModified {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if span != nil {
+				span.SetAttributes(attribute.Bool("cache.hit", true))
+				span.SetStatus(codes.Ok, "cache hit - not modified")
+			}
+			slog.InfoContext(ctx, "Character roles not modified, using cached data")
+
+			// Refresh the expiry since ESI confirmed data is still valid
+			c.cacheManager.RefreshExpiry(cacheKey, resp.Header)
+
+			var roles RolesResponse
+			if err := json.Unmarshal(cachedData, &roles); err != nil {
+				return nil, fmt.Errorf("failed to parse cached response: %w", err)
+			}
+			return &roles, nil
+		}
+		slog.WarnContext(ctx, "Received 304 Not Modified but no cached data available", "character_id", characterID)
+		return nil, fmt.Errorf("ESI returned 304 Not Modified but no cached data is available for character %d roles", characterID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI character roles endpoint returned error", "status_code", resp.StatusCode)
+		return nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		slog.ErrorContext(ctx, "Failed to read character roles response", "error", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Update cache
+	c.cacheManager.Set(cacheKey, body, resp.Header)
+
+	var roles RolesResponse
+	if err := json.Unmarshal(body, &roles); err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to parse response")
+		}
+		slog.ErrorContext(ctx, "Failed to parse character roles response", "error", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("roles.count", len(roles.Roles)))
+		span.SetStatus(codes.Ok, "success")
+	}
+
+	slog.InfoContext(ctx, "Successfully fetched character roles from ESI", "character_id", characterID, "roles", len(roles.Roles))
+	return &roles, nil
+}
@@ -17,12 +17,32 @@ import (
 	"go-falcon/pkg/database"
 	"go-falcon/pkg/evegateway/alliance"
 	"go-falcon/pkg/evegateway/assets"
+	"go-falcon/pkg/evegateway/calendar"
 	"go-falcon/pkg/evegateway/character"
+	"go-falcon/pkg/evegateway/contracts"
 	"go-falcon/pkg/evegateway/corporation"
+	"go-falcon/pkg/evegateway/dogma"
+	"go-falcon/pkg/evegateway/esiruntime"
+	"go-falcon/pkg/evegateway/factionwarfare"
+	"go-falcon/pkg/evegateway/fittings"
+	"go-falcon/pkg/evegateway/fleets"
+	"go-falcon/pkg/evegateway/generated/contacts"
+	"go-falcon/pkg/evegateway/generated/insurance"
+	"go-falcon/pkg/evegateway/generated/planetaryinteraction"
+	"go-falcon/pkg/evegateway/incursions"
+	"go-falcon/pkg/evegateway/industry"
 	"go-falcon/pkg/evegateway/killmails"
+	"go-falcon/pkg/evegateway/loyalty"
+	"go-falcon/pkg/evegateway/mail"
 	"go-falcon/pkg/evegateway/market"
+	"go-falcon/pkg/evegateway/routes"
+	"go-falcon/pkg/evegateway/sovereignty"
 	"go-falcon/pkg/evegateway/structures"
+	"go-falcon/pkg/evegateway/universe"
+	"go-falcon/pkg/evegateway/wallet"
+	"go-falcon/pkg/evegateway/wars"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -39,17 +59,41 @@ type Client struct {
 	retryClient  RetryClient
 	errorLimits  *ESIErrorLimits
 	limitsMutex  sync.RWMutex
+	connections  *connectionTracker
+	breakers     *CircuitBreakers
+	coalescing   *CoalescingRetryClient
+	metrics      *Metrics
+	tokenRefresh *tokenRefreshTransport
 
 	// Category clients
-	Status      StatusClient
-	Character   CharacterClient
-	Universe    UniverseClient
-	Alliance    AllianceClient
-	Corporation CorporationClient
-	Killmails   KillmailClient
-	Market      MarketClient
-	Assets      AssetsClient
-	Structures  StructuresClient
+	Status         StatusClient
+	Character      CharacterClient
+	Universe       UniverseClient
+	Alliance       AllianceClient
+	Corporation    CorporationClient
+	Killmails      KillmailClient
+	Market         MarketClient
+	Assets         AssetsClient
+	Structures     StructuresClient
+	Fittings       FittingsClient
+	Wallet         WalletClient
+	Contracts      ContractsClient
+	Industry       IndustryClient
+	Mail           MailClient
+	Fleets         FleetsClient
+	Calendar       CalendarClient
+	Loyalty        LoyaltyClient
+	Sovereignty    SovereigntyClient
+	Wars           WarsClient
+	FactionWarfare FactionWarfareClient
+	Incursions     IncursionsClient
+	Dogma          DogmaClient
+	Routes         RoutesClient
+
+	// Generated clients (cmd/esigen)
+	Contacts             contacts.Client
+	Insurance            insurance.Client
+	PlanetaryInteraction planetaryinteraction.Client
 }
 
 // ESIStatusResponse represents the EVE Online server status
@@ -80,12 +124,15 @@ type CharacterClient interface {
 	GetCharacterOnline(ctx context.Context, characterID int, token string) (map[string]any, error)
 	GetCharacterShip(ctx context.Context, characterID int, token string) (map[string]any, error)
 	GetCharacterWallet(ctx context.Context, characterID int, token string) (map[string]any, error)
+	GetCharacterRoles(ctx context.Context, characterID int, token string) (*character.RolesResponse, error)
 }
 
 // UniverseClient interface for universe operations
 type UniverseClient interface {
 	GetSystemInfo(ctx context.Context, systemID int) (map[string]any, error)
 	GetStationInfo(ctx context.Context, stationID int) (map[string]any, error)
+	GetNames(ctx context.Context, ids []int) ([]universe.Name, error)
+	GetIDs(ctx context.Context, names []string) ([]universe.Name, error)
 }
 
 // AllianceClient interface for alliance operations
@@ -115,6 +162,7 @@ type CorporationClient interface {
 	GetCorporationMemberTrackingWithCache(ctx context.Context, corporationID int, token string) (*corporation.CorporationMemberTrackingResult, error)
 	GetCorporationMemberRoles(ctx context.Context, corporationID int, token string) ([]corporation.CorporationMemberRoles, error)
 	GetCorporationMemberRolesWithCache(ctx context.Context, corporationID int, token string) (*corporation.CorporationRolesResult, error)
+	GetCorporationMemberTitles(ctx context.Context, corporationID int, token string) ([]corporation.CorporationMemberTitles, error)
 
 	// Corporation Structures and Assets (requires authentication)
 	GetCorporationStructures(ctx context.Context, corporationID int, token string) ([]corporation.CorporationStructure, error)
@@ -127,6 +175,9 @@ type CorporationClient interface {
 	// Corporation Finances (requires authentication)
 	GetCorporationWallets(ctx context.Context, corporationID int, token string) ([]corporation.CorporationWallet, error)
 	GetCorporationWalletsWithCache(ctx context.Context, corporationID int, token string) (*corporation.CorporationWalletResult, error)
+
+	// Corporation Audit (requires Director role)
+	GetCorporationContainerLogs(ctx context.Context, corporationID int, token string) ([]corporation.CorporationContainerLog, error)
 }
 
 // KillmailClient interface for killmail operations
@@ -140,6 +191,10 @@ type KillmailClient interface {
 type AssetsClient interface {
 	GetCharacterAssets(ctx context.Context, characterID int32, token string) ([]map[string]any, error)
 	GetCorporationAssets(ctx context.Context, corporationID int32, token string) ([]map[string]any, error)
+	GetCharacterAssetNames(ctx context.Context, characterID int32, itemIDs []int64, token string) ([]assets.AssetName, error)
+	GetCharacterAssetLocations(ctx context.Context, characterID int32, itemIDs []int64, token string) ([]assets.AssetLocation, error)
+	GetCorporationAssetNames(ctx context.Context, corporationID int32, itemIDs []int64, token string) ([]assets.AssetName, error)
+	GetCorporationAssetLocations(ctx context.Context, corporationID int32, itemIDs []int64, token string) ([]assets.AssetLocation, error)
 }
 
 // MarketClient interface for market operations
@@ -149,6 +204,8 @@ type MarketClient interface {
 	GetMarketStats(ctx context.Context, regionID int) ([]map[string]any, error)
 	GetMarketTypes(ctx context.Context, regionID int) ([]int, error)
 	GetStructureOrders(ctx context.Context, structureID int64, token string, page int) ([]map[string]any, error)
+	GetMarketOrdersForType(ctx context.Context, regionID int, typeID int, orderType string) ([]market.MarketOrderResponse, error)
+	GetMarketPrices(ctx context.Context) ([]market.MarketPriceResponse, error)
 }
 
 // StructuresClient interface for structures operations
@@ -156,6 +213,154 @@ type StructuresClient interface {
 	GetStructure(ctx context.Context, structureID int64, token string) (map[string]any, error)
 }
 
+// FittingsClient interface for fittings operations
+type FittingsClient interface {
+	GetCharacterFittings(ctx context.Context, characterID int, token string) ([]fittings.FittingResponse, error)
+	GetCharacterFittingsWithCache(ctx context.Context, characterID int, token string) (*fittings.FittingsResult, error)
+}
+
+// ContractsClient interface for contract operations
+type ContractsClient interface {
+	GetCharacterContracts(ctx context.Context, characterID int, token string) ([]contracts.Contract, error)
+	GetCharacterContractsWithCache(ctx context.Context, characterID int, token string) (*contracts.ContractsResult, error)
+	GetCharacterContractItems(ctx context.Context, characterID int, contractID int32, token string) ([]contracts.ContractItem, error)
+	GetCharacterContractBids(ctx context.Context, characterID int, contractID int32, token string) ([]contracts.ContractBid, error)
+	GetCorporationContracts(ctx context.Context, corporationID int, token string) ([]contracts.Contract, error)
+	GetCorporationContractsWithCache(ctx context.Context, corporationID int, token string) (*contracts.ContractsResult, error)
+	GetCorporationContractItems(ctx context.Context, corporationID int, contractID int32, token string) ([]contracts.ContractItem, error)
+	GetCorporationContractBids(ctx context.Context, corporationID int, contractID int32, token string) ([]contracts.ContractBid, error)
+	GetPublicContracts(ctx context.Context, regionID int) ([]contracts.Contract, error)
+	GetPublicContractsWithCache(ctx context.Context, regionID int) (*contracts.ContractsResult, error)
+	GetPublicContractItems(ctx context.Context, contractID int32) ([]contracts.ContractItem, error)
+	GetPublicContractBids(ctx context.Context, contractID int32) ([]contracts.ContractBid, error)
+}
+
+// IndustryClient interface for industry operations
+type IndustryClient interface {
+	GetCharacterIndustryJobs(ctx context.Context, characterID int, includeCompleted bool, token string) ([]industry.Job, error)
+	GetCharacterIndustryJobsWithCache(ctx context.Context, characterID int, includeCompleted bool, token string) (*industry.JobsResult, error)
+	GetCorporationIndustryJobs(ctx context.Context, corporationID int, includeCompleted bool, token string) ([]industry.Job, error)
+	GetCorporationIndustryJobsWithCache(ctx context.Context, corporationID int, includeCompleted bool, token string) (*industry.JobsResult, error)
+	GetIndustryFacilities(ctx context.Context) ([]industry.Facility, error)
+	GetIndustrySystems(ctx context.Context) ([]industry.SystemCostIndices, error)
+	GetIndustrySystemsWithCache(ctx context.Context) (*industry.SystemsResult, error)
+}
+
+// MailClient interface for mail operations
+type MailClient interface {
+	GetMailHeaders(ctx context.Context, characterID int, labels []int32, lastMailID int32, token string) ([]mail.Header, error)
+	GetMailHeadersWithCache(ctx context.Context, characterID int, labels []int32, lastMailID int32, token string) (*mail.HeadersResult, error)
+	GetMail(ctx context.Context, characterID int, mailID int32, token string) (*mail.Mail, error)
+	SendMail(ctx context.Context, characterID int, newMail mail.NewMail, token string) (int32, error)
+	UpdateMail(ctx context.Context, characterID int, mailID int32, update mail.MailUpdate, token string) error
+	DeleteMail(ctx context.Context, characterID int, mailID int32, token string) error
+	GetMailLabels(ctx context.Context, characterID int, token string) (*mail.Labels, error)
+	CreateMailLabel(ctx context.Context, characterID int, label mail.NewLabel, token string) (int32, error)
+	DeleteMailLabel(ctx context.Context, characterID int, labelID int32, token string) error
+	GetMailingLists(ctx context.Context, characterID int, token string) ([]mail.MailingList, error)
+}
+
+// FleetsClient interface for fleet operations
+type FleetsClient interface {
+	GetCharacterFleet(ctx context.Context, characterID int, token string) (*fleets.CharacterFleet, error)
+	GetFleet(ctx context.Context, fleetID int64, token string) (*fleets.Fleet, error)
+	UpdateFleet(ctx context.Context, fleetID int64, update fleets.FleetUpdate, token string) error
+	GetFleetMembers(ctx context.Context, fleetID int64, token string) ([]fleets.Member, error)
+	GetFleetMembersWithCache(ctx context.Context, fleetID int64, token string) (*fleets.MembersResult, error)
+	InviteFleetMember(ctx context.Context, fleetID int64, invitation fleets.Invitation, token string) error
+	KickFleetMember(ctx context.Context, fleetID int64, memberID int, token string) error
+	MoveFleetMember(ctx context.Context, fleetID int64, memberID int, movement fleets.Movement, token string) error
+	GetFleetWings(ctx context.Context, fleetID int64, token string) ([]fleets.Wing, error)
+	CreateFleetWing(ctx context.Context, fleetID int64, token string) (int64, error)
+	RenameFleetWing(ctx context.Context, fleetID, wingID int64, name, token string) error
+	DeleteFleetWing(ctx context.Context, fleetID, wingID int64, token string) error
+	CreateFleetSquad(ctx context.Context, fleetID, wingID int64, token string) (int64, error)
+	RenameFleetSquad(ctx context.Context, fleetID, squadID int64, name, token string) error
+	DeleteFleetSquad(ctx context.Context, fleetID, squadID int64, token string) error
+}
+
+// CalendarClient interface for calendar operations
+type CalendarClient interface {
+	GetCalendarEvents(ctx context.Context, characterID int, fromEventID int32, token string) ([]calendar.EventSummary, error)
+	GetCalendarEventsWithCache(ctx context.Context, characterID int, fromEventID int32, token string) (*calendar.EventsResult, error)
+	GetCalendarEvent(ctx context.Context, characterID int, eventID int32, token string) (*calendar.Event, error)
+	RespondToCalendarEvent(ctx context.Context, characterID int, eventID int32, response string, token string) error
+	GetCalendarEventAttendees(ctx context.Context, characterID int, eventID int32, token string) ([]calendar.Attendee, error)
+}
+
+// LoyaltyClient interface for loyalty point operations
+type LoyaltyClient interface {
+	GetCharacterLoyaltyPoints(ctx context.Context, characterID int, token string) ([]loyalty.LoyaltyPoints, error)
+	GetLoyaltyStoreOffers(ctx context.Context, corporationID int) ([]loyalty.Offer, error)
+	GetLoyaltyStoreOffersWithCache(ctx context.Context, corporationID int) (*loyalty.OffersResult, error)
+}
+
+// SovereigntyClient interface for sovereignty operations
+type SovereigntyClient interface {
+	GetSovereigntyMap(ctx context.Context) ([]sovereignty.SystemSovereignty, error)
+	GetSovereigntyMapWithCache(ctx context.Context) (*sovereignty.MapResult, error)
+	GetSovereigntyStructures(ctx context.Context) ([]sovereignty.Structure, error)
+	GetSovereigntyStructuresWithCache(ctx context.Context) (*sovereignty.StructuresResult, error)
+	GetSovereigntyCampaigns(ctx context.Context) ([]sovereignty.Campaign, error)
+	GetSovereigntyCampaignsWithCache(ctx context.Context) (*sovereignty.CampaignsResult, error)
+}
+
+// WarsClient interface for war operations
+type WarsClient interface {
+	GetWars(ctx context.Context, maxWarID int32) ([]int32, error)
+	GetWarsWithCache(ctx context.Context, maxWarID int32) (*wars.WarIDsResult, error)
+	GetWar(ctx context.Context, warID int) (*wars.War, error)
+	GetWarWithCache(ctx context.Context, warID int) (*wars.WarResult, error)
+	GetWarKillmails(ctx context.Context, warID int) ([]wars.KillmailRef, error)
+	GetWarKillmailsWithCache(ctx context.Context, warID int) (*wars.KillmailsResult, error)
+}
+
+// FactionWarfareClient interface for faction warfare operations
+type FactionWarfareClient interface {
+	GetFWSystems(ctx context.Context) ([]factionwarfare.System, error)
+	GetFWSystemsWithCache(ctx context.Context) (*factionwarfare.SystemsResult, error)
+	GetFWWars(ctx context.Context) ([]factionwarfare.War, error)
+	GetFWStats(ctx context.Context) ([]factionwarfare.FactionStats, error)
+	GetFWStatsWithCache(ctx context.Context) (*factionwarfare.StatsResult, error)
+	GetCharacterFWStats(ctx context.Context, characterID int, token string) (*factionwarfare.CharacterStats, error)
+	GetCorporationFWStats(ctx context.Context, corporationID int, token string) (*factionwarfare.CorporationStats, error)
+}
+
+// IncursionsClient interface for incursion operations
+type IncursionsClient interface {
+	GetIncursions(ctx context.Context) ([]incursions.Incursion, error)
+	GetIncursionsWithCache(ctx context.Context) (*incursions.IncursionsResult, error)
+}
+
+// DogmaClient interface for dogma operations
+type DogmaClient interface {
+	GetDogmaAttributes(ctx context.Context) ([]int32, error)
+	GetDogmaAttribute(ctx context.Context, attributeID int32) (*dogma.Attribute, error)
+	GetDogmaEffects(ctx context.Context) ([]int32, error)
+	GetDogmaEffect(ctx context.Context, effectID int32) (*dogma.Effect, error)
+	GetDynamicItem(ctx context.Context, typeID int32, itemID int64) (*dogma.DynamicItem, error)
+	GetDynamicItemWithCache(ctx context.Context, typeID int32, itemID int64) (*dogma.DynamicItemResult, error)
+}
+
+// RoutesClient interface for route planning operations
+type RoutesClient interface {
+	GetRoute(ctx context.Context, origin, destination int32, flag string, options *routes.RouteOptions) ([]int32, error)
+	GetRouteWithCache(ctx context.Context, origin, destination int32, flag string, options *routes.RouteOptions) (*routes.RouteResult, error)
+}
+
+// WalletClient interface for wallet operations
+type WalletClient interface {
+	GetCharacterWalletBalance(ctx context.Context, characterID int, token string) (float64, error)
+	GetCharacterWalletJournal(ctx context.Context, characterID int, token string) ([]wallet.JournalEntry, error)
+	GetCharacterWalletJournalWithCache(ctx context.Context, characterID int, token string) (*wallet.JournalResult, error)
+	GetCharacterWalletTransactions(ctx context.Context, characterID int, fromID int64, token string) ([]wallet.Transaction, error)
+	GetCharacterWalletTransactionsWithCache(ctx context.Context, characterID int, fromID int64, token string) (*wallet.TransactionsResult, error)
+	GetCorporationWalletJournal(ctx context.Context, corporationID, division int, token string) ([]wallet.JournalEntry, error)
+	GetCorporationWalletJournalWithCache(ctx context.Context, corporationID, division int, token string) (*wallet.JournalResult, error)
+	GetCorporationWalletTransactions(ctx context.Context, corporationID, division int, fromID int64, token string) ([]wallet.Transaction, error)
+	GetCorporationWalletTransactionsWithCache(ctx context.Context, corporationID, division int, fromID int64, token string) (*wallet.TransactionsResult, error)
+}
+
 // GetErrorLimits returns the current ESI error limits
 func (c *Client) GetErrorLimits() ESIErrorLimits {
 	c.limitsMutex.RLock()
@@ -177,133 +382,199 @@ func (c *Client) CheckErrorLimits() error {
 	return nil
 }
 
+// esiBaseURL is the address of the live ESI API
+const esiBaseURL = "https://esi.evetech.net"
+
 // NewClient creates a new EVE Online ESI client with in-memory caching
 func NewClient() *Client {
-	var transport http.RoundTripper = http.DefaultTransport
+	return newClient(esiBaseURL, NewDefaultCacheManager())
+}
 
-	// Only add OpenTelemetry instrumentation if telemetry is enabled
-	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
-		transport = otelhttp.NewTransport(http.DefaultTransport,
-			otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
-				return fmt.Sprintf("HTTP %s %s", r.Method, r.URL.Host)
-			}),
-		)
-	}
+// NewClientWithRedis creates a new EVE Online ESI client with Redis caching
+func NewClientWithRedis(redisClient *database.Redis) *Client {
+	return newClient(esiBaseURL, NewRedisCacheManager(redisClient))
+}
 
-	// ESI-compliant User-Agent header with contact information
-	userAgent := config.GetEnv("ESI_USER_AGENT", "go-falcon/1.0.0 contact@example.com")
+// NewClientWithBaseURL creates a client with in-memory caching that talks to another ESI host,
+// such as the esitest mock server
+func NewClientWithBaseURL(baseURL string) *Client {
+	return newClient(strings.TrimSuffix(baseURL, "/"), NewDefaultCacheManager())
+}
 
-	httpClient := &http.Client{
-		Timeout:   30 * time.Second,
-		Transport: transport,
+// newClient wires all category clients onto a single HTTP client and transport
+// so every sub-client shares one connection pool
+func newClient(baseURL string, cacheManager CacheManager) *Client {
+	metrics := NewMetrics()
+	if _, redisBacked := cacheManager.(*RedisCacheManager); redisBacked {
+		// Keep hot entries in process so they skip the Redis round-trip
+		if memoryConfig := LoadMemoryCacheConfig(); memoryConfig.MaxEntries > 0 {
+			cacheManager = NewTieredCacheManager(cacheManager, memoryConfig, metrics)
+		}
 	}
+	cacheManager = &instrumentedCacheManager{CacheManager: cacheManager, metrics: metrics}
 
-	cacheManager := NewDefaultCacheManager()
-	errorLimits := &ESIErrorLimits{}
-	limitsMutex := &sync.RWMutex{}
-	retryClient := NewDefaultRetryClient(httpClient, errorLimits, limitsMutex)
-
-	// Create category clients using the shared infrastructure
-	statusClient := &statusClientImpl{cacheManager, retryClient, httpClient, "https://esi.evetech.net", userAgent}
-	characterClientDirect := character.NewCharacterClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
-	characterClient := &characterClientImpl{client: characterClientDirect}
-	universeClient := &universeClientImpl{cacheManager, retryClient, httpClient, "https://esi.evetech.net", userAgent}
-	allianceClientDirect := alliance.NewAllianceClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
-	allianceClient := &allianceClientImpl{client: allianceClientDirect}
-	corporationClientDirect := corporation.NewCorporationClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
-	corporationClient := &corporationClientImpl{client: corporationClientDirect}
-	killmailClientDirect := killmails.NewKillmailClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
-	killmailClient := &killmailClientImpl{client: killmailClientDirect}
-	marketClientDirect := market.NewMarketClient(httpClient, cacheManager)
-	marketClient := &marketClientImpl{client: marketClientDirect}
-	assetsClientDirect := assets.NewAssetsClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
-	assetsClient := &assetsClientImpl{client: assetsClientDirect}
-	structuresClientDirect := structures.NewStructuresClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
-	structuresClient := &structuresClientImpl{client: structuresClientDirect}
-
-	return &Client{
-		httpClient:   httpClient,
-		baseURL:      "https://esi.evetech.net",
-		userAgent:    userAgent,
-		cacheManager: cacheManager,
-		retryClient:  retryClient,
-		errorLimits:  errorLimits,
-		limitsMutex:  sync.RWMutex{},
-		Status:       statusClient,
-		Character:    characterClient,
-		Universe:     universeClient,
-		Alliance:     allianceClient,
-		Corporation:  corporationClient,
-		Killmails:    killmailClient,
-		Market:       marketClient,
-		Assets:       assetsClient,
-		Structures:   structuresClient,
-	}
-}
+	transportConfig := LoadTransportConfig()
+	tracker := newConnectionTracker(NewTransport(transportConfig))
 
-// NewClientWithRedis creates a new EVE Online ESI client with Redis caching
-func NewClientWithRedis(redisClient *database.Redis) *Client {
-	var transport http.RoundTripper = http.DefaultTransport
+	var transport http.RoundTripper = tracker
 
 	// Only add OpenTelemetry instrumentation if telemetry is enabled
 	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
-		transport = otelhttp.NewTransport(http.DefaultTransport,
+		transport = otelhttp.NewTransport(tracker,
 			otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
 				return fmt.Sprintf("HTTP %s %s", r.Method, r.URL.Host)
 			}),
 		)
 	}
 
+	// Refresh expired or rejected EVE SSO tokens once the auth module provides a refresher
+	tokenRefresh := &tokenRefreshTransport{base: transport}
+	transport = tokenRefresh
+
 	// ESI-compliant User-Agent header with contact information
 	userAgent := config.GetEnv("ESI_USER_AGENT", "go-falcon/1.0.0 contact@example.com")
 
 	httpClient := &http.Client{
-		Timeout:   30 * time.Second,
+		Timeout:   transportConfig.RequestTimeout,
 		Transport: transport,
 	}
 
-	// Use Redis cache manager instead of default in-memory cache
-	cacheManager := NewRedisCacheManager(redisClient)
 	errorLimits := &ESIErrorLimits{}
 	limitsMutex := &sync.RWMutex{}
-	retryClient := NewDefaultRetryClient(httpClient, errorLimits, limitsMutex)
+	breakers := NewCircuitBreakers(LoadCircuitBreakerConfig())
+	defaultRetryClient := NewDefaultRetryClient(httpClient, errorLimits, limitsMutex, breakers, metrics)
+	defaultRetryClient.callBudget = transportConfig.CallBudget
+	retryClient := NewCoalescingRetryClient(defaultRetryClient)
 
 	// Create category clients using the shared infrastructure
-	statusClient := &statusClientImpl{cacheManager, retryClient, httpClient, "https://esi.evetech.net", userAgent}
-	characterClientDirect := character.NewCharacterClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
+	statusClient := &statusClientImpl{cacheManager, retryClient, httpClient, baseURL, userAgent}
+	characterClientDirect := character.NewCharacterClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
 	characterClient := &characterClientImpl{client: characterClientDirect}
-	universeClient := &universeClientImpl{cacheManager, retryClient, httpClient, "https://esi.evetech.net", userAgent}
-	allianceClientDirect := alliance.NewAllianceClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
+	universeClient := &universeClientImpl{cacheManager, retryClient, httpClient, baseURL, userAgent, universe.NewUniverseClient(httpClient, baseURL, userAgent, cacheManager, retryClient)}
+	allianceClientDirect := alliance.NewAllianceClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
 	allianceClient := &allianceClientImpl{client: allianceClientDirect}
-	corporationClientDirect := corporation.NewCorporationClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
+	corporationClientDirect := corporation.NewCorporationClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
 	corporationClient := &corporationClientImpl{client: corporationClientDirect}
-	killmailClientDirect := killmails.NewKillmailClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
+	killmailClientDirect := killmails.NewKillmailClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
 	killmailClient := &killmailClientImpl{client: killmailClientDirect}
-	marketClientDirect := market.NewMarketClient(httpClient, cacheManager)
+	marketClientDirect := market.NewMarketClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
 	marketClient := &marketClientImpl{client: marketClientDirect}
-	assetsClientDirect := assets.NewAssetsClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
+	assetsClientDirect := assets.NewAssetsClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
 	assetsClient := &assetsClientImpl{client: assetsClientDirect}
-	structuresClientDirect := structures.NewStructuresClient(httpClient, "https://esi.evetech.net", userAgent, cacheManager, retryClient)
+	structuresClientDirect := structures.NewStructuresClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
 	structuresClient := &structuresClientImpl{client: structuresClientDirect}
+	fittingsClientDirect := fittings.NewFittingsClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	fittingsClient := &fittingsClientImpl{client: fittingsClientDirect}
+	walletClient := wallet.NewWalletClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	contractsClient := contracts.NewContractsClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	industryClient := industry.NewIndustryClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	mailClient := mail.NewMailClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	fleetsClient := fleets.NewFleetsClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	calendarClient := calendar.NewCalendarClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	loyaltyClient := loyalty.NewLoyaltyClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	sovereigntyClient := sovereignty.NewSovereigntyClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	warsClient := wars.NewWarsClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	factionWarfareClient := factionwarfare.NewFactionWarfareClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	incursionsClient := incursions.NewIncursionsClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	dogmaClient := dogma.NewDogmaClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+	routesClient := routes.NewRoutesClient(httpClient, baseURL, userAgent, cacheManager, retryClient)
+
+	// Generated clients share one runtime on the same infrastructure
+	runtime := esiruntime.New(httpClient, baseURL, userAgent, cacheManager, retryClient)
 
 	return &Client{
-		httpClient:   httpClient,
-		baseURL:      "https://esi.evetech.net",
-		userAgent:    userAgent,
-		cacheManager: cacheManager,
-		retryClient:  retryClient,
-		errorLimits:  errorLimits,
-		limitsMutex:  sync.RWMutex{},
-		Status:       statusClient,
-		Character:    characterClient,
-		Universe:     universeClient,
-		Alliance:     allianceClient,
-		Corporation:  corporationClient,
-		Killmails:    killmailClient,
-		Market:       marketClient,
-		Assets:       assetsClient,
-		Structures:   structuresClient,
-	}
+		httpClient:     httpClient,
+		baseURL:        baseURL,
+		userAgent:      userAgent,
+		cacheManager:   cacheManager,
+		retryClient:    retryClient,
+		errorLimits:    errorLimits,
+		limitsMutex:    sync.RWMutex{},
+		connections:    tracker,
+		breakers:       breakers,
+		coalescing:     retryClient,
+		metrics:        metrics,
+		tokenRefresh:   tokenRefresh,
+		Status:         statusClient,
+		Character:      characterClient,
+		Universe:       universeClient,
+		Alliance:       allianceClient,
+		Corporation:    corporationClient,
+		Killmails:      killmailClient,
+		Market:         marketClient,
+		Assets:         assetsClient,
+		Structures:     structuresClient,
+		Fittings:       fittingsClient,
+		Wallet:         walletClient,
+		Contracts:      contractsClient,
+		Industry:       industryClient,
+		Mail:           mailClient,
+		Fleets:         fleetsClient,
+		Calendar:       calendarClient,
+		Loyalty:        loyaltyClient,
+		Sovereignty:    sovereigntyClient,
+		Wars:           warsClient,
+		FactionWarfare: factionWarfareClient,
+		Incursions:     incursionsClient,
+		Dogma:          dogmaClient,
+		Routes:         routesClient,
+
+		Contacts:             contacts.NewClient(runtime),
+		Insurance:            insurance.NewClient(runtime),
+		PlanetaryInteraction: planetaryinteraction.NewClient(runtime),
+	}
+}
+
+// ConnectionStats returns connection reuse counters for the shared ESI transport
+func (c *Client) ConnectionStats() ConnectionStats {
+	if c.connections == nil {
+		return ConnectionStats{}
+	}
+	return c.connections.Stats()
+}
+
+// CircuitBreakerStats returns the state of the ESI circuit breakers of endpoint families that
+// have seen failures
+func (c *Client) CircuitBreakerStats() []CircuitBreakerStats {
+	return c.breakers.Stats()
+}
+
+// CoalescingStats returns how many GET requests were sent to ESI and how many callers shared one
+// already in flight
+func (c *Client) CoalescingStats() CoalescingStats {
+	if c.coalescing == nil {
+		return CoalescingStats{}
+	}
+	return c.coalescing.Stats()
+}
+
+// RegisterMetrics adds the ESI client's Prometheus metrics to a registry: requests, latency,
+// retries and cache lookups per endpoint, the error limit, and the coalescing counters
+func (c *Client) RegisterMetrics(registerer prometheus.Registerer) error {
+	if err := c.metrics.Register(registerer); err != nil {
+		return err
+	}
+	return registerer.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Name: "esi_coalesced_requests_total",
+		Help: "ESI GET requests served by an identical request already in flight.",
+	}, func() float64 {
+		return float64(c.CoalescingStats().Coalesced)
+	}))
+}
+
+// SetTokenRefresher enables transparent token refresh for authenticated calls: expired access
+// tokens are refreshed before sending and requests answered 401 are retried once with a new token
+func (c *Client) SetTokenRefresher(refresher TokenRefresher) {
+	c.tokenRefresh.setRefresher(refresher)
+}
+
+// UsageReport returns the ESI requests made since startup by originating module and endpoint, and
+// the cache hit rate per endpoint
+func (c *Client) UsageReport() UsageReport {
+	if c.metrics == nil {
+		return UsageReport{Modules: []ModuleUsage{}, Cache: []CacheUsage{}}
+	}
+	return c.metrics.usage.report()
 }
 
 // HTTPClient returns the underlying HTTP client for advanced usage
@@ -613,6 +884,7 @@ type universeClientImpl struct {
 	httpClient   *http.Client
 	baseURL      string
 	userAgent    string
+	client       universe.Client
 }
 
 type assetsClientImpl struct {
@@ -623,6 +895,10 @@ type structuresClientImpl struct {
 	client structures.Client
 }
 
+type fittingsClientImpl struct {
+	client fittings.Client
+}
+
 // StatusClient implementation
 func (s *statusClientImpl) GetServerStatus(ctx context.Context) (*ESIStatusResponse, error) {
 	// Delegate to the existing GetServerStatus method logic - for backward compatibility
@@ -975,6 +1251,10 @@ func (c *characterClientImpl) GetCharacterWallet(ctx context.Context, characterI
 	return result, nil
 }
 
+func (c *characterClientImpl) GetCharacterRoles(ctx context.Context, characterID int, token string) (*character.RolesResponse, error) {
+	return c.client.GetCharacterRoles(ctx, characterID, token)
+}
+
 // UniverseClient implementation
 func (u *universeClientImpl) GetSystemInfo(ctx context.Context, systemID int) (map[string]any, error) {
 	slog.InfoContext(ctx, "System info request delegated to universe package", "system_id", systemID)
@@ -986,6 +1266,16 @@ func (u *universeClientImpl) GetStationInfo(ctx context.Context, stationID int)
 	return map[string]any{"station_id": stationID, "name": "use universe package"}, nil
 }
 
+// GetNames resolves IDs to names in bulk
+func (u *universeClientImpl) GetNames(ctx context.Context, ids []int) ([]universe.Name, error) {
+	return u.client.GetNames(ctx, ids)
+}
+
+// GetIDs resolves names to IDs in bulk
+func (u *universeClientImpl) GetIDs(ctx context.Context, names []string) ([]universe.Name, error) {
+	return u.client.GetIDs(ctx, names)
+}
+
 // Alliance client adapter
 type allianceClientImpl struct {
 	client alliance.Client
@@ -1119,6 +1409,10 @@ func (c *corporationClientImpl) GetCorporationMemberRolesWithCache(ctx context.C
 	return c.client.GetCorporationMemberRolesWithCache(ctx, corporationID, token)
 }
 
+func (c *corporationClientImpl) GetCorporationMemberTitles(ctx context.Context, corporationID int, token string) ([]corporation.CorporationMemberTitles, error) {
+	return c.client.GetCorporationMemberTitles(ctx, corporationID, token)
+}
+
 func (c *corporationClientImpl) GetCorporationStructures(ctx context.Context, corporationID int, token string) ([]corporation.CorporationStructure, error) {
 	return c.client.GetCorporationStructures(ctx, corporationID, token)
 }
@@ -1143,6 +1437,10 @@ func (c *corporationClientImpl) GetCorporationWalletsWithCache(ctx context.Conte
 	return c.client.GetCorporationWalletsWithCache(ctx, corporationID, token)
 }
 
+func (c *corporationClientImpl) GetCorporationContainerLogs(ctx context.Context, corporationID int, token string) ([]corporation.CorporationContainerLog, error) {
+	return c.client.GetCorporationContainerLogs(ctx, corporationID, token)
+}
+
 // Killmail client adapter
 // Market client adapter
 type marketClientImpl struct {
@@ -1258,6 +1556,14 @@ func (m *marketClientImpl) GetStructureOrders(ctx context.Context, structureID i
 	return result, nil
 }
 
+func (m *marketClientImpl) GetMarketOrdersForType(ctx context.Context, regionID int, typeID int, orderType string) ([]market.MarketOrderResponse, error) {
+	return m.client.GetMarketOrdersForType(ctx, regionID, typeID, orderType)
+}
+
+func (m *marketClientImpl) GetMarketPrices(ctx context.Context) ([]market.MarketPriceResponse, error) {
+	return m.client.GetMarketPrices(ctx)
+}
+
 type killmailClientImpl struct {
 	client killmails.Client
 }
@@ -1368,6 +1674,22 @@ func (a *assetsClientImpl) GetCorporationAssets(ctx context.Context, corporation
 	return result, nil
 }
 
+func (a *assetsClientImpl) GetCharacterAssetNames(ctx context.Context, characterID int32, itemIDs []int64, token string) ([]assets.AssetName, error) {
+	return a.client.GetCharacterAssetNames(ctx, characterID, itemIDs, token)
+}
+
+func (a *assetsClientImpl) GetCharacterAssetLocations(ctx context.Context, characterID int32, itemIDs []int64, token string) ([]assets.AssetLocation, error) {
+	return a.client.GetCharacterAssetLocations(ctx, characterID, itemIDs, token)
+}
+
+func (a *assetsClientImpl) GetCorporationAssetNames(ctx context.Context, corporationID int32, itemIDs []int64, token string) ([]assets.AssetName, error) {
+	return a.client.GetCorporationAssetNames(ctx, corporationID, itemIDs, token)
+}
+
+func (a *assetsClientImpl) GetCorporationAssetLocations(ctx context.Context, corporationID int32, itemIDs []int64, token string) ([]assets.AssetLocation, error) {
+	return a.client.GetCorporationAssetLocations(ctx, corporationID, itemIDs, token)
+}
+
 // Structures client adapter
 func (s *structuresClientImpl) GetStructure(ctx context.Context, structureID int64, token string) (map[string]any, error) {
 	structure, err := s.client.GetStructure(ctx, structureID, token)
@@ -1500,3 +1822,12 @@ func convertItemsToMap(items []killmails.Item) []map[string]any {
 	}
 	return result
 }
+
+// Fittings client adapter
+func (c *fittingsClientImpl) GetCharacterFittings(ctx context.Context, characterID int, token string) ([]fittings.FittingResponse, error) {
+	return c.client.GetCharacterFittings(ctx, characterID, token)
+}
+
+func (c *fittingsClientImpl) GetCharacterFittingsWithCache(ctx context.Context, characterID int, token string) (*fittings.FittingsResult, error) {
+	return c.client.GetCharacterFittingsWithCache(ctx, characterID, token)
+}
@@ -0,0 +1,158 @@
+package evegateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"go-falcon/pkg/config"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// CoalescingStats reports how many GET requests were sent upstream and how many callers were
+// served by a request another caller already had in flight
+type CoalescingStats struct {
+	Enabled   bool  `json:"enabled"`
+	Upstream  int64 `json:"upstream"`
+	Coalesced int64 `json:"coalesced"`
+}
+
+// sharedResponse is an upstream response buffered so that every waiting caller gets its own copy
+type sharedResponse struct {
+	status     string
+	statusCode int
+	proto      string
+	protoMajor int
+	protoMinor int
+	header     http.Header
+	body       []byte
+}
+
+// CoalescingRetryClient collapses concurrent identical GET requests into a single upstream request.
+// Sub-clients only reach the retry client on a cache miss, so when several goroutines miss the same
+// resource at once (e.g. login and a profile refresh for the same character) ESI is asked once and
+// every caller receives the same response.
+type CoalescingRetryClient struct {
+	next    RetryClient
+	enabled bool
+	group   singleflight.Group
+
+	upstream  atomic.Int64
+	coalesced atomic.Int64
+}
+
+// NewCoalescingRetryClient wraps a retry client with request coalescing, unless disabled with
+// ESI_REQUEST_COALESCING=false
+func NewCoalescingRetryClient(next RetryClient) *CoalescingRetryClient {
+	return &CoalescingRetryClient{
+		next:    next,
+		enabled: config.GetBoolEnv("ESI_REQUEST_COALESCING", true),
+	}
+}
+
+// DoWithRetry sends the request, sharing the response with identical GET requests already in flight
+func (c *CoalescingRetryClient) DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error) {
+	if !c.enabled || req.Method != http.MethodGet {
+		return c.next.DoWithRetry(ctx, req, maxRetries)
+	}
+
+	// The first caller's cancellation must not fail the others, so the shared request only ends
+	// early once it has finished or every caller has given up waiting
+	key := coalescingKey(req)
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		c.upstream.Add(1)
+		return c.fetch(context.WithoutCancel(ctx), req, maxRetries)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if res.Shared {
+			c.coalesced.Add(1)
+			slog.DebugContext(ctx, "Coalesced ESI request", "url", req.URL.String())
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*sharedResponse).response(req), nil
+	}
+}
+
+// fetch performs the upstream request and buffers the body so it can be handed to several callers
+func (c *CoalescingRetryClient) fetch(ctx context.Context, req *http.Request, maxRetries int) (*sharedResponse, error) {
+	resp, err := c.next.DoWithRetry(ctx, req, maxRetries)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ESI response: %w", err)
+	}
+
+	return &sharedResponse{
+		status:     resp.Status,
+		statusCode: resp.StatusCode,
+		proto:      resp.Proto,
+		protoMajor: resp.ProtoMajor,
+		protoMinor: resp.ProtoMinor,
+		header:     resp.Header,
+		body:       body,
+	}, nil
+}
+
+// response returns a copy of the shared response that the caller may read and modify
+func (s *sharedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        s.status,
+		StatusCode:    s.statusCode,
+		Proto:         s.proto,
+		ProtoMajor:    s.protoMajor,
+		ProtoMinor:    s.protoMinor,
+		Header:        s.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(s.body)),
+		ContentLength: int64(len(s.body)),
+		Request:       req,
+	}
+}
+
+// coalescingKey identifies requests that may share a response: the URL (which is also the cache
+// key of the sub-clients), the caller's token, so authenticated data is never handed to another
+// token, and the conditional headers, so nobody receives a 304 for a copy they do not have
+func coalescingKey(req *http.Request) string {
+	var b strings.Builder
+	b.WriteString(req.URL.String())
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		sum := sha256.Sum256([]byte(auth))
+		b.WriteString("|auth=")
+		b.WriteString(hex.EncodeToString(sum[:8]))
+	}
+	if etag := req.Header.Get("If-None-Match"); etag != "" {
+		b.WriteString("|etag=")
+		b.WriteString(etag)
+	}
+	if since := req.Header.Get("If-Modified-Since"); since != "" {
+		b.WriteString("|since=")
+		b.WriteString(since)
+	}
+	return b.String()
+}
+
+// Stats returns the coalescing counters
+func (c *CoalescingRetryClient) Stats() CoalescingStats {
+	return CoalescingStats{
+		Enabled:   c.enabled,
+		Upstream:  c.upstream.Load(),
+		Coalesced: c.coalesced.Load(),
+	}
+}
@@ -0,0 +1,376 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/evegateway/paging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ContractsResult contains contracts and cache information
+type ContractsResult struct {
+	Data  []Contract `json:"data"`
+	Cache CacheInfo  `json:"cache"`
+}
+
+// Client interface for contract-related ESI operations
+type Client interface {
+	GetCharacterContracts(ctx context.Context, characterID int, token string) ([]Contract, error)
+	GetCharacterContractsWithCache(ctx context.Context, characterID int, token string) (*ContractsResult, error)
+	GetCharacterContractItems(ctx context.Context, characterID int, contractID int32, token string) ([]ContractItem, error)
+	GetCharacterContractBids(ctx context.Context, characterID int, contractID int32, token string) ([]ContractBid, error)
+	GetCorporationContracts(ctx context.Context, corporationID int, token string) ([]Contract, error)
+	GetCorporationContractsWithCache(ctx context.Context, corporationID int, token string) (*ContractsResult, error)
+	GetCorporationContractItems(ctx context.Context, corporationID int, contractID int32, token string) ([]ContractItem, error)
+	GetCorporationContractBids(ctx context.Context, corporationID int, contractID int32, token string) ([]ContractBid, error)
+	GetPublicContracts(ctx context.Context, regionID int) ([]Contract, error)
+	GetPublicContractsWithCache(ctx context.Context, regionID int) (*ContractsResult, error)
+	GetPublicContractItems(ctx context.Context, contractID int32) ([]ContractItem, error)
+	GetPublicContractBids(ctx context.Context, contractID int32) ([]ContractBid, error)
+}
+
+// Contract represents a contract from ESI. Public contracts omit the assignee, acceptor,
+// availability and status fields, which are only returned for character and corporation contracts.
+type Contract struct {
+	ContractID          int32      `json:"contract_id"`
+	Type                string     `json:"type"`
+	Status              string     `json:"status,omitempty"`
+	Availability        string     `json:"availability,omitempty"`
+	Title               string     `json:"title,omitempty"`
+	IssuerID            int32      `json:"issuer_id"`
+	IssuerCorporationID int32      `json:"issuer_corporation_id"`
+	AssigneeID          int32      `json:"assignee_id,omitempty"`
+	AcceptorID          int32      `json:"acceptor_id,omitempty"`
+	ForCorporation      bool       `json:"for_corporation"`
+	StartLocationID     *int64     `json:"start_location_id,omitempty"`
+	EndLocationID       *int64     `json:"end_location_id,omitempty"`
+	DateIssued          time.Time  `json:"date_issued"`
+	DateExpired         time.Time  `json:"date_expired"`
+	DateAccepted        *time.Time `json:"date_accepted,omitempty"`
+	DateCompleted       *time.Time `json:"date_completed,omitempty"`
+	DaysToComplete      *int32     `json:"days_to_complete,omitempty"`
+	Price               *float64   `json:"price,omitempty"`
+	Reward              *float64   `json:"reward,omitempty"`
+	Collateral          *float64   `json:"collateral,omitempty"`
+	Buyout              *float64   `json:"buyout,omitempty"`
+	Volume              *float64   `json:"volume,omitempty"`
+}
+
+// ContractItem represents an item in a contract. IsIncluded is false for items the issuer asks for
+// in exchange. Public contract items carry ItemID and blueprint details instead of RecordID.
+type ContractItem struct {
+	RecordID           int64  `json:"record_id"`
+	ItemID             *int64 `json:"item_id,omitempty"`
+	TypeID             int32  `json:"type_id"`
+	Quantity           int32  `json:"quantity"`
+	RawQuantity        *int32 `json:"raw_quantity,omitempty"`
+	IsIncluded         bool   `json:"is_included"`
+	IsSingleton        bool   `json:"is_singleton"`
+	IsBlueprintCopy    *bool  `json:"is_blueprint_copy,omitempty"`
+	MaterialEfficiency *int32 `json:"material_efficiency,omitempty"`
+	TimeEfficiency     *int32 `json:"time_efficiency,omitempty"`
+	Runs               *int32 `json:"runs,omitempty"`
+}
+
+// ContractBid represents a bid on an auction contract. BidderID is not returned for public contracts.
+type ContractBid struct {
+	BidID    int32     `json:"bid_id"`
+	BidderID int32     `json:"bidder_id,omitempty"`
+	Amount   float64   `json:"amount"`
+	DateBid  time.Time `json:"date_bid"`
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewContractsClient creates a new contracts client
+func NewContractsClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetCharacterContracts retrieves all pages of contracts a character issued, accepted or was
+// assigned in the last 30 days (requires esi-contracts.read_character_contracts.v1)
+func (c *ClientImpl) GetCharacterContracts(ctx context.Context, characterID int, token string) ([]Contract, error) {
+	var contracts []Contract
+	_, err := c.getList(ctx, fmt.Sprintf("/characters/%d/contracts/", characterID), token, &contracts)
+	return contracts, err
+}
+
+// GetCharacterContractsWithCache retrieves a character's contracts with cache info
+func (c *ClientImpl) GetCharacterContractsWithCache(ctx context.Context, characterID int, token string) (*ContractsResult, error) {
+	return c.getContractsWithCache(ctx, fmt.Sprintf("/characters/%d/contracts/", characterID), token)
+}
+
+// GetCharacterContractItems retrieves the items of a character contract (requires esi-contracts.read_character_contracts.v1)
+func (c *ClientImpl) GetCharacterContractItems(ctx context.Context, characterID int, contractID int32, token string) ([]ContractItem, error) {
+	var items []ContractItem
+	_, err := c.getList(ctx, fmt.Sprintf("/characters/%d/contracts/%d/items/", characterID, contractID), token, &items)
+	return items, err
+}
+
+// GetCharacterContractBids retrieves the bids on a character auction contract (requires esi-contracts.read_character_contracts.v1)
+func (c *ClientImpl) GetCharacterContractBids(ctx context.Context, characterID int, contractID int32, token string) ([]ContractBid, error) {
+	var bids []ContractBid
+	_, err := c.getList(ctx, fmt.Sprintf("/characters/%d/contracts/%d/bids/", characterID, contractID), token, &bids)
+	return bids, err
+}
+
+// GetCorporationContracts retrieves all pages of a corporation's contracts
+// (requires esi-contracts.read_corporation_contracts.v1)
+func (c *ClientImpl) GetCorporationContracts(ctx context.Context, corporationID int, token string) ([]Contract, error) {
+	var contracts []Contract
+	_, err := c.getList(ctx, fmt.Sprintf("/corporations/%d/contracts/", corporationID), token, &contracts)
+	return contracts, err
+}
+
+// GetCorporationContractsWithCache retrieves a corporation's contracts with cache info
+func (c *ClientImpl) GetCorporationContractsWithCache(ctx context.Context, corporationID int, token string) (*ContractsResult, error) {
+	return c.getContractsWithCache(ctx, fmt.Sprintf("/corporations/%d/contracts/", corporationID), token)
+}
+
+// GetCorporationContractItems retrieves the items of a corporation contract (requires esi-contracts.read_corporation_contracts.v1)
+func (c *ClientImpl) GetCorporationContractItems(ctx context.Context, corporationID int, contractID int32, token string) ([]ContractItem, error) {
+	var items []ContractItem
+	_, err := c.getList(ctx, fmt.Sprintf("/corporations/%d/contracts/%d/items/", corporationID, contractID), token, &items)
+	return items, err
+}
+
+// GetCorporationContractBids retrieves all pages of bids on a corporation auction contract
+// (requires esi-contracts.read_corporation_contracts.v1)
+func (c *ClientImpl) GetCorporationContractBids(ctx context.Context, corporationID int, contractID int32, token string) ([]ContractBid, error) {
+	var bids []ContractBid
+	_, err := c.getList(ctx, fmt.Sprintf("/corporations/%d/contracts/%d/bids/", corporationID, contractID), token, &bids)
+	return bids, err
+}
+
+// GetPublicContracts retrieves all pages of outstanding public contracts in a region
+func (c *ClientImpl) GetPublicContracts(ctx context.Context, regionID int) ([]Contract, error) {
+	var contracts []Contract
+	_, err := c.getList(ctx, fmt.Sprintf("/contracts/public/%d/", regionID), "", &contracts)
+	return contracts, err
+}
+
+// GetPublicContractsWithCache retrieves a region's public contracts with cache info
+func (c *ClientImpl) GetPublicContractsWithCache(ctx context.Context, regionID int) (*ContractsResult, error) {
+	return c.getContractsWithCache(ctx, fmt.Sprintf("/contracts/public/%d/", regionID), "")
+}
+
+// GetPublicContractItems retrieves all pages of items in a public contract. Contracts that have
+// expired or been accepted return no items.
+func (c *ClientImpl) GetPublicContractItems(ctx context.Context, contractID int32) ([]ContractItem, error) {
+	var items []ContractItem
+	_, err := c.getList(ctx, fmt.Sprintf("/contracts/public/items/%d/", contractID), "", &items)
+	return items, err
+}
+
+// GetPublicContractBids retrieves all pages of bids on a public auction contract
+func (c *ClientImpl) GetPublicContractBids(ctx context.Context, contractID int32) ([]ContractBid, error) {
+	var bids []ContractBid
+	_, err := c.getList(ctx, fmt.Sprintf("/contracts/public/bids/%d/", contractID), "", &bids)
+	return bids, err
+}
+
+// getContractsWithCache wraps getList for contract lists, adding the cache expiry
+func (c *ClientImpl) getContractsWithCache(ctx context.Context, endpoint, token string) (*ContractsResult, error) {
+	var contracts []Contract
+	cached, err := c.getList(ctx, endpoint, token, &contracts)
+	if err != nil {
+		return nil, err
+	}
+
+	var cacheExpiry *time.Time
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(c.baseURL + endpoint); err == nil && found {
+		cacheExpiry = expiry
+	}
+
+	return &ContractsResult{
+		Data:  contracts,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: cacheExpiry},
+	}, nil
+}
+
+// getList fetches a list endpoint into v, following X-Pages. The complete list is cached under
+// the endpoint with the first page's headers. Single-page responses are revalidated with their
+// ETag once expired; multi-page lists are refetched, since one page's ETag says nothing about the others.
+// An empty token makes an unauthenticated request.
+func (c *ClientImpl) getList(ctx context.Context, endpoint, token string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, token, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	totalPages := paging.TotalPages(headers)
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found && totalPages <= 1 {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch the first page unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, token, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if totalPages > 1 {
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			return false, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		rest, err := paging.FetchRemainingPages[json.RawMessage](ctx, totalPages, func(ctx context.Context, page int) ([]byte, http.Header, error) {
+			return c.fetch(ctx, paging.PageURL(cacheKey, page), token, "")
+		}, paging.DefaultConcurrency)
+		if err != nil {
+			return false, err
+		}
+		items = append(items, rest...)
+
+		if body, err = json.Marshal(items); err != nil {
+			return false, fmt.Errorf("failed to combine pages: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs a GET, authenticated when token is set. When conditionalKey is set, the request
+// carries the ETag cached under that key and a 304 response is reported as a nil body with the
+// response headers. 204 No Content (e.g. items of an expired public contract) is an empty list.
+func (c *ClientImpl) fetch(ctx context.Context, url, token, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/contracts")
+		ctx, span = tracer.Start(ctx, "contracts.fetch")
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("esi.url", url),
+			attribute.Bool("esi.authenticated", token != ""),
+		)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI contracts endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && conditionalKey != "":
+		return nil, resp.Header, nil
+	case resp.StatusCode == http.StatusNoContent:
+		return []byte("[]"), resp.Header, nil
+	case resp.StatusCode != http.StatusOK:
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI contracts endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
@@ -3,10 +3,12 @@ package corporation
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"go-falcon/pkg/config"
@@ -86,6 +88,7 @@ type Client interface {
 	GetCorporationMemberTrackingWithCache(ctx context.Context, corporationID int, token string) (*CorporationMemberTrackingResult, error)
 	GetCorporationMemberRoles(ctx context.Context, corporationID int, token string) ([]CorporationMemberRoles, error)
 	GetCorporationMemberRolesWithCache(ctx context.Context, corporationID int, token string) (*CorporationRolesResult, error)
+	GetCorporationMemberTitles(ctx context.Context, corporationID int, token string) ([]CorporationMemberTitles, error)
 
 	// Corporation Structures and Assets (requires authentication)
 	GetCorporationStructures(ctx context.Context, corporationID int, token string) ([]CorporationStructure, error)
@@ -98,6 +101,9 @@ type Client interface {
 	// Corporation Finances (requires authentication)
 	GetCorporationWallets(ctx context.Context, corporationID int, token string) ([]CorporationWallet, error)
 	GetCorporationWalletsWithCache(ctx context.Context, corporationID int, token string) (*CorporationWalletResult, error)
+
+	// Corporation Audit (requires Director role)
+	GetCorporationContainerLogs(ctx context.Context, corporationID int, token string) ([]CorporationContainerLog, error)
 }
 
 // CorporationInfoResponse represents corporation public information
@@ -199,6 +205,32 @@ type CorporationMemberRoles struct {
 	RolesAtOther          []string `json:"roles_at_other,omitempty"`
 }
 
+// CorporationMemberTitles represents the titles a member holds
+type CorporationMemberTitles struct {
+	CharacterID int   `json:"character_id"`
+	Titles      []int `json:"titles"`
+}
+
+// ErrForbidden is returned when ESI refuses the token, e.g. because the character lacks the
+// corporation role an endpoint requires
+var ErrForbidden = errors.New("ESI refused the token")
+
+// CorporationContainerLog represents a single audit log entry for a secure container
+type CorporationContainerLog struct {
+	LoggedAt         time.Time `json:"logged_at"`
+	ContainerID      int64     `json:"container_id"`
+	ContainerTypeID  int       `json:"container_type_id"`
+	CharacterID      int       `json:"character_id"`
+	LocationID       int64     `json:"location_id"`
+	LocationFlag     string    `json:"location_flag"`
+	Action           string    `json:"action"`
+	PasswordType     string    `json:"password_type,omitempty"`
+	TypeID           int       `json:"type_id,omitempty"`
+	Quantity         int       `json:"quantity,omitempty"`
+	OldConfigBitmask int       `json:"old_config_bitmask,omitempty"`
+	NewConfigBitmask int       `json:"new_config_bitmask,omitempty"`
+}
+
 // CacheManager interface for caching operations
 type CacheManager interface {
 	Get(key string) ([]byte, bool, error)
@@ -962,6 +994,25 @@ func (c *CorporationClient) GetCorporationMemberRolesWithCache(ctx context.Conte
 	}, nil
 }
 
+// GetCorporationMemberTitles retrieves the titles of every corporation member from ESI (requires
+// authentication and the Director role)
+func (c *CorporationClient) GetCorporationMemberTitles(ctx context.Context, corporationID int, token string) ([]CorporationMemberTitles, error) {
+	endpoint := fmt.Sprintf("/corporations/%d/members/titles/", corporationID)
+	cacheKey := fmt.Sprintf("%s%s", c.baseURL, endpoint)
+
+	body, err := c.makeAuthenticatedRequest(ctx, endpoint, token, cacheKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var titles []CorporationMemberTitles
+	if err := json.Unmarshal(body, &titles); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return titles, nil
+}
+
 // GetCorporationStructures retrieves corporation structures from ESI (requires authentication)
 func (c *CorporationClient) GetCorporationStructures(ctx context.Context, corporationID int, token string) ([]CorporationStructure, error) {
 	endpoint := fmt.Sprintf("/corporations/%d/structures/", corporationID)
@@ -1111,3 +1162,74 @@ func (c *CorporationClient) GetCorporationWalletsWithCache(ctx context.Context,
 		Cache: CacheInfo{Cached: cached, ExpiresAt: cacheExpiry},
 	}, nil
 }
+
+// GetCorporationContainerLogs retrieves all pages of secure container audit logs from ESI
+// (requires esi-corporations.read_container_logs.v1 and the Director role)
+func (c *CorporationClient) GetCorporationContainerLogs(ctx context.Context, corporationID int, token string) ([]CorporationContainerLog, error) {
+	var allLogs []CorporationContainerLog
+	page := 1
+
+	for {
+		logs, totalPages, err := c.fetchContainerLogsPage(ctx, corporationID, token, page)
+		if err != nil {
+			return nil, err
+		}
+
+		allLogs = append(allLogs, logs...)
+
+		if page >= totalPages || len(logs) == 0 {
+			break
+		}
+		page++
+	}
+
+	return allLogs, nil
+}
+
+// fetchContainerLogsPage fetches a single page of container audit logs
+func (c *CorporationClient) fetchContainerLogsPage(ctx context.Context, corporationID int, token string, page int) ([]CorporationContainerLog, int, error) {
+	url := fmt.Sprintf("%s/corporations/%d/containers/logs/?page=%d", c.baseURL, corporationID, page)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to call ESI container logs endpoint", "error", err)
+		return nil, 0, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusForbidden {
+		return nil, 0, fmt.Errorf("%w: the character needs the Director role", ErrForbidden)
+	}
+	if resp.StatusCode != http.StatusOK {
+		slog.ErrorContext(ctx, "ESI container logs endpoint returned error", "status_code", resp.StatusCode)
+		return nil, 0, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	totalPages := 1
+	if pagesHeader := resp.Header.Get("X-Pages"); pagesHeader != "" {
+		if pages, err := strconv.Atoi(pagesHeader); err == nil {
+			totalPages = pages
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var logs []CorporationContainerLog
+	if err := json.Unmarshal(body, &logs); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return logs, totalPages, nil
+}
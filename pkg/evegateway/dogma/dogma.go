@@ -0,0 +1,322 @@
+package dogma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// DynamicItemResult contains a mutated item and cache information
+type DynamicItemResult struct {
+	Data  *DynamicItem `json:"data"`
+	Cache CacheInfo    `json:"cache"`
+}
+
+// Client interface for dogma ESI operations
+type Client interface {
+	GetDogmaAttributes(ctx context.Context) ([]int32, error)
+	GetDogmaAttribute(ctx context.Context, attributeID int32) (*Attribute, error)
+	GetDogmaEffects(ctx context.Context) ([]int32, error)
+	GetDogmaEffect(ctx context.Context, effectID int32) (*Effect, error)
+	GetDynamicItem(ctx context.Context, typeID int32, itemID int64) (*DynamicItem, error)
+	GetDynamicItemWithCache(ctx context.Context, typeID int32, itemID int64) (*DynamicItemResult, error)
+}
+
+// Attribute is a dogma attribute definition
+type Attribute struct {
+	AttributeID  int32    `json:"attribute_id"`
+	Name         string   `json:"name,omitempty"`
+	DisplayName  string   `json:"display_name,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	DefaultValue *float64 `json:"default_value,omitempty"`
+	UnitID       *int32   `json:"unit_id,omitempty"`
+	IconID       *int32   `json:"icon_id,omitempty"`
+	HighIsGood   *bool    `json:"high_is_good,omitempty"`
+	Stackable    *bool    `json:"stackable,omitempty"`
+	Published    *bool    `json:"published,omitempty"`
+}
+
+// Effect is a dogma effect definition and the attribute modifiers it applies
+type Effect struct {
+	EffectID                 int32      `json:"effect_id"`
+	Name                     string     `json:"name,omitempty"`
+	DisplayName              string     `json:"display_name,omitempty"`
+	Description              string     `json:"description,omitempty"`
+	EffectCategory           *int32     `json:"effect_category,omitempty"`
+	IconID                   *int32     `json:"icon_id,omitempty"`
+	Published                *bool      `json:"published,omitempty"`
+	IsOffensive              *bool      `json:"is_offensive,omitempty"`
+	IsAssistance             *bool      `json:"is_assistance,omitempty"`
+	IsWarpSafe               *bool      `json:"is_warp_safe,omitempty"`
+	DisallowAutoRepeat       *bool      `json:"disallow_auto_repeat,omitempty"`
+	RangeChance              *bool      `json:"range_chance,omitempty"`
+	ElectronicChance         *bool      `json:"electronic_chance,omitempty"`
+	PreExpression            *int32     `json:"pre_expression,omitempty"`
+	PostExpression           *int32     `json:"post_expression,omitempty"`
+	DurationAttributeID      *int32     `json:"duration_attribute_id,omitempty"`
+	DischargeAttributeID     *int32     `json:"discharge_attribute_id,omitempty"`
+	RangeAttributeID         *int32     `json:"range_attribute_id,omitempty"`
+	FalloffAttributeID       *int32     `json:"falloff_attribute_id,omitempty"`
+	TrackingSpeedAttributeID *int32     `json:"tracking_speed_attribute_id,omitempty"`
+	Modifiers                []Modifier `json:"modifiers,omitempty"`
+}
+
+// Modifier is one attribute change an effect applies
+type Modifier struct {
+	Func                 string `json:"func"`
+	Domain               string `json:"domain,omitempty"`
+	ModifiedAttributeID  *int32 `json:"modified_attribute_id,omitempty"`
+	ModifyingAttributeID *int32 `json:"modifying_attribute_id,omitempty"`
+	EffectID             *int32 `json:"effect_id,omitempty"`
+	Operator             *int32 `json:"operator,omitempty"`
+}
+
+// DynamicItem is a mutated (abyssal) item: its rolled attribute values, the mutaplasmid that
+// produced it and the type it was made from
+type DynamicItem struct {
+	CreatedBy       int32            `json:"created_by"`
+	MutatorTypeID   int32            `json:"mutator_type_id"`
+	SourceTypeID    int32            `json:"source_type_id"`
+	DogmaAttributes []AttributeValue `json:"dogma_attributes"`
+	DogmaEffects    []EffectState    `json:"dogma_effects"`
+}
+
+// AttributeValue is the value of an attribute on an item
+type AttributeValue struct {
+	AttributeID int32   `json:"attribute_id"`
+	Value       float64 `json:"value"`
+}
+
+// EffectState is an effect on an item
+type EffectState struct {
+	EffectID  int32 `json:"effect_id"`
+	IsDefault bool  `json:"is_default"`
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewDogmaClient creates a new dogma client
+func NewDogmaClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetDogmaAttributes retrieves the IDs of all dogma attributes
+func (c *ClientImpl) GetDogmaAttributes(ctx context.Context) ([]int32, error) {
+	var ids []int32
+	_, err := c.get(ctx, "/dogma/attributes/", &ids)
+	return ids, err
+}
+
+// GetDogmaAttribute retrieves a dogma attribute definition
+func (c *ClientImpl) GetDogmaAttribute(ctx context.Context, attributeID int32) (*Attribute, error) {
+	var attribute Attribute
+	if _, err := c.get(ctx, fmt.Sprintf("/dogma/attributes/%d/", attributeID), &attribute); err != nil {
+		return nil, err
+	}
+	return &attribute, nil
+}
+
+// GetDogmaEffects retrieves the IDs of all dogma effects
+func (c *ClientImpl) GetDogmaEffects(ctx context.Context) ([]int32, error) {
+	var ids []int32
+	_, err := c.get(ctx, "/dogma/effects/", &ids)
+	return ids, err
+}
+
+// GetDogmaEffect retrieves a dogma effect definition
+func (c *ClientImpl) GetDogmaEffect(ctx context.Context, effectID int32) (*Effect, error) {
+	var effect Effect
+	if _, err := c.get(ctx, fmt.Sprintf("/dogma/effects/%d/", effectID), &effect); err != nil {
+		return nil, err
+	}
+	return &effect, nil
+}
+
+// GetDynamicItem retrieves the rolled stats of a mutated item by its type and item ID
+func (c *ClientImpl) GetDynamicItem(ctx context.Context, typeID int32, itemID int64) (*DynamicItem, error) {
+	result, err := c.GetDynamicItemWithCache(ctx, typeID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetDynamicItemWithCache retrieves a mutated item with cache info
+func (c *ClientImpl) GetDynamicItemWithCache(ctx context.Context, typeID int32, itemID int64) (*DynamicItemResult, error) {
+	endpoint := fmt.Sprintf("/dogma/dynamic/items/%d/%d/", typeID, itemID)
+
+	var item DynamicItem
+	cached, err := c.get(ctx, endpoint, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DynamicItemResult{
+		Data:  &item,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)},
+	}, nil
+}
+
+// get fetches an endpoint into v, serving it from cache while fresh and revalidating it with its
+// ETag once expired
+func (c *ClientImpl) get(ctx context.Context, endpoint string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs an unauthenticated GET. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/dogma")
+		ctx, span = tracer.Start(ctx, "dogma.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI dogma endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI dogma endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
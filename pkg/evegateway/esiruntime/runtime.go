@@ -0,0 +1,315 @@
+// Package esiruntime is the request plumbing shared by the ESI clients generated with cmd/esigen:
+// caching with ETag revalidation, pagination, retries and tracing. Generated packages only
+// describe endpoints and types; everything that talks to ESI lives here.
+package esiruntime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/evegateway/paging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Result is a response together with its cache information
+type Result[T any] struct {
+	Data  T         `json:"data"`
+	Cache CacheInfo `json:"cache"`
+}
+
+// TokenProvider supplies the access token of an authenticated call
+type TokenProvider interface {
+	AccessToken(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenProvider for an access token the caller already holds
+type StaticToken string
+
+// AccessToken implements TokenProvider
+func (t StaticToken) AccessToken(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// Request describes one ESI call made by a generated client
+type Request struct {
+	Method   string
+	Endpoint string
+	Query    url.Values
+	// Token authenticates the request when set
+	Token TokenProvider
+	// Body is sent as JSON when set
+	Body any
+	// CompatibilityDate is the X-Compatibility-Date the endpoint was generated against
+	CompatibilityDate string
+}
+
+// Runtime holds the shared infrastructure a generated client sends its requests through
+type Runtime struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// New creates a runtime on top of the gateway's HTTP client, cache and retry client
+func New(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) *Runtime {
+	return &Runtime{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// url returns the absolute URL of a request, which is also its cache key
+func (rt *Runtime) url(req Request) string {
+	u := rt.baseURL + req.Endpoint
+	if len(req.Query) > 0 {
+		u += "?" + req.Query.Encode()
+	}
+	return u
+}
+
+// Get fetches a cacheable endpoint into T, serving it from cache while fresh and revalidating it
+// with its ETag once expired
+func Get[T any](ctx context.Context, rt *Runtime, req Request) (*Result[T], error) {
+	cacheKey := rt.url(req)
+	var data T
+
+	if cachedData, found, expiresAt, err := rt.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, &data); err == nil {
+			return &Result[T]{Data: data, Cache: CacheInfo{Cached: true, ExpiresAt: expiresAt}}, nil
+		}
+	}
+
+	body, headers, err := rt.do(ctx, req, cacheKey, cacheKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if body == nil {
+		if cachedData, found, err := rt.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if err := json.Unmarshal(cachedData, &data); err == nil {
+				rt.cacheManager.RefreshExpiry(cacheKey, headers)
+				return &Result[T]{Data: data, Cache: CacheInfo{Cached: true, ExpiresAt: rt.cacheExpiry(cacheKey)}}, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch unconditionally
+		if body, headers, err = rt.do(ctx, req, cacheKey, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	rt.cacheManager.Set(cacheKey, body, headers)
+	return &Result[T]{Data: data, Cache: CacheInfo{Cached: false, ExpiresAt: rt.cacheExpiry(cacheKey)}}, nil
+}
+
+// GetAllPages fetches every page of a paginated list endpoint and caches the complete list under
+// the URL of the first page, with the first page's expiry
+func GetAllPages[T any](ctx context.Context, rt *Runtime, req Request) (*Result[[]T], error) {
+	cacheKey := rt.url(req)
+
+	if cachedData, found, expiresAt, err := rt.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		var items []T
+		if err := json.Unmarshal(cachedData, &items); err == nil {
+			return &Result[[]T]{Data: items, Cache: CacheInfo{Cached: true, ExpiresAt: expiresAt}}, nil
+		}
+	}
+
+	items, headers, err := paging.FetchAllPages[T](ctx, func(ctx context.Context, page int) ([]byte, http.Header, error) {
+		return rt.do(ctx, req, paging.PageURL(cacheKey, page), "")
+	}, paging.DefaultConcurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(items); err == nil {
+		rt.cacheManager.Set(cacheKey, data, headers)
+	}
+	return &Result[[]T]{Data: items, Cache: CacheInfo{Cached: false, ExpiresAt: rt.cacheExpiry(cacheKey)}}, nil
+}
+
+// Send performs an uncached request (POST, PUT, DELETE or a GET that must not be cached) and
+// decodes the response into T. Endpoints answering 204 No Content leave T at its zero value.
+func Send[T any](ctx context.Context, rt *Runtime, req Request) (T, error) {
+	var data T
+	body, _, err := rt.do(ctx, req, rt.url(req), "")
+	if err != nil {
+		return data, err
+	}
+	if len(body) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return data, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return data, nil
+}
+
+// do performs a request to the given URL. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (rt *Runtime) do(ctx context.Context, req Request, url, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/esiruntime")
+		ctx, span = tracer.Start(ctx, "esi."+strings.ToLower(req.Method))
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	var payload io.Reader
+	if req.Body != nil {
+		data, err := json.Marshal(req.Body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, url, payload)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("User-Agent", rt.userAgent)
+	httpReq.Header.Set("Accept", "application/json")
+	if req.Body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if req.CompatibilityDate != "" {
+		httpReq.Header.Set("X-Compatibility-Date", req.CompatibilityDate)
+	}
+	if req.Token != nil {
+		token, err := req.Token.AccessToken(ctx)
+		if err != nil {
+			if span != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "failed to get access token")
+			}
+			return nil, nil, fmt.Errorf("failed to get access token: %w", err)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	if conditionalKey != "" {
+		rt.cacheManager.SetConditionalHeaders(httpReq, conditionalKey)
+	}
+
+	resp, err := rt.retryClient.DoWithRetry(ctx, httpReq, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI", "method", req.Method, "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI returned error", "method", req.Method, "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (rt *Runtime) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := rt.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
+
+// PathParam formats a path parameter, escaping strings
+func PathParam(v any) string {
+	if s, ok := v.(string); ok {
+		return url.PathEscape(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// QueryParam formats a query parameter; ESI takes lists as comma-separated values
+func QueryParam(v any) string {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		return fmt.Sprint(v)
+	}
+	parts := make([]string, value.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(value.Index(i).Interface())
+	}
+	return strings.Join(parts, ",")
+}
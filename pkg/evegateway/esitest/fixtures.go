@@ -0,0 +1,160 @@
+package esitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// IDs of the default fixtures: one character in a corporation in an alliance, and Tritanium on
+// The Forge market
+const (
+	CharacterID   = 90000001
+	CorporationID = 98000001
+	AllianceID    = 99000001
+	RegionID      = 10000002
+	TypeID        = 34
+	StationID     = 60003760
+)
+
+// fixtureEpoch keeps fixture dates stable across runs
+var fixtureEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// loadDefaultFixtures registers the canned character, corporation, alliance, market and status data
+func loadDefaultFixtures(s *Server) {
+	s.Set("/status", map[string]any{
+		"players":        23456,
+		"server_version": "2345678",
+		"start_time":     fixtureEpoch,
+	})
+
+	// Character
+	s.Set(fmt.Sprintf("/characters/%d", CharacterID), map[string]any{
+		"name":            "Falcon Tester",
+		"description":     "Fixture character",
+		"corporation_id":  CorporationID,
+		"alliance_id":     AllianceID,
+		"birthday":        fixtureEpoch,
+		"gender":          "female",
+		"race_id":         1,
+		"bloodline_id":    1,
+		"ancestry_id":     1,
+		"security_status": 1.5,
+	})
+	s.Set(fmt.Sprintf("/characters/%d/portrait", CharacterID), map[string]any{
+		"px64x64":   fmt.Sprintf("https://images.evetech.net/characters/%d/portrait?size=64", CharacterID),
+		"px128x128": fmt.Sprintf("https://images.evetech.net/characters/%d/portrait?size=128", CharacterID),
+		"px256x256": fmt.Sprintf("https://images.evetech.net/characters/%d/portrait?size=256", CharacterID),
+		"px512x512": fmt.Sprintf("https://images.evetech.net/characters/%d/portrait?size=512", CharacterID),
+	})
+	s.Set(fmt.Sprintf("/characters/%d/corporationhistory", CharacterID), []map[string]any{
+		{"corporation_id": CorporationID, "record_id": 2, "start_date": fixtureEpoch.AddDate(1, 0, 0)},
+		{"corporation_id": 1000166, "record_id": 1, "start_date": fixtureEpoch},
+	})
+	s.Set(fmt.Sprintf("/characters/%d/online", CharacterID), map[string]any{
+		"online":      false,
+		"last_login":  fixtureEpoch.AddDate(5, 0, 0),
+		"last_logout": fixtureEpoch.AddDate(5, 0, 0).Add(2 * time.Hour),
+		"logins":      1234,
+	})
+	s.Set(fmt.Sprintf("/characters/%d/skills", CharacterID), map[string]any{
+		"skills": []map[string]any{
+			{"skill_id": 3300, "active_skill_level": 5, "trained_skill_level": 5, "skillpoints_in_skill": 256000},
+		},
+		"total_sp":       256000,
+		"unallocated_sp": 0,
+	})
+	s.Set(fmt.Sprintf("/characters/%d/wallet", CharacterID), 1234567.89)
+	s.Handle(http.MethodPost, "/characters/affiliation", affiliation)
+
+	// Corporation
+	s.Set(fmt.Sprintf("/corporations/%d", CorporationID), map[string]any{
+		"name":            "Falcon Test Corporation",
+		"ticker":          "FALC",
+		"description":     "Fixture corporation",
+		"alliance_id":     AllianceID,
+		"ceo_id":          CharacterID,
+		"creator_id":      CharacterID,
+		"date_founded":    fixtureEpoch,
+		"home_station_id": StationID,
+		"member_count":    1,
+		"tax_rate":        0.1,
+		"war_eligible":    true,
+	})
+	s.Set(fmt.Sprintf("/corporations/%d/alliancehistory", CorporationID), []map[string]any{
+		{"alliance_id": AllianceID, "record_id": 1, "start_date": fixtureEpoch},
+	})
+	s.Set(fmt.Sprintf("/corporations/%d/members", CorporationID), []int{CharacterID})
+	s.Set(fmt.Sprintf("/corporations/%d/icons", CorporationID), map[string]any{
+		"px64x64":   fmt.Sprintf("https://images.evetech.net/corporations/%d/logo?size=64", CorporationID),
+		"px128x128": fmt.Sprintf("https://images.evetech.net/corporations/%d/logo?size=128", CorporationID),
+		"px256x256": fmt.Sprintf("https://images.evetech.net/corporations/%d/logo?size=256", CorporationID),
+	})
+
+	// Alliance
+	s.Set("/alliances", []int{AllianceID})
+	s.Set(fmt.Sprintf("/alliances/%d", AllianceID), map[string]any{
+		"name":                    "Falcon Test Alliance",
+		"ticker":                  "FALCN",
+		"creator_id":              CharacterID,
+		"creator_corporation_id":  CorporationID,
+		"executor_corporation_id": CorporationID,
+		"date_founded":            fixtureEpoch,
+	})
+	s.Set(fmt.Sprintf("/alliances/%d/corporations", AllianceID), []int{CorporationID})
+	s.Set(fmt.Sprintf("/alliances/%d/icons", AllianceID), map[string]any{
+		"px64x64":   fmt.Sprintf("https://images.evetech.net/alliances/%d/logo?size=64", AllianceID),
+		"px128x128": fmt.Sprintf("https://images.evetech.net/alliances/%d/logo?size=128", AllianceID),
+	})
+
+	// Market
+	s.Set("/markets/prices", []map[string]any{
+		{"type_id": TypeID, "average_price": 4.25, "adjusted_price": 4.1},
+	})
+	s.SetPages(fmt.Sprintf("/markets/%d/orders", RegionID),
+		[]map[string]any{marketOrder(1, 4.3, false), marketOrder(2, 4.1, true)},
+		[]map[string]any{marketOrder(3, 4.5, false)},
+	)
+	s.Set(fmt.Sprintf("/markets/%d/history", RegionID), []map[string]any{
+		{"date": "2020-01-01", "order_count": 1500, "volume": 250000000, "highest": 4.4, "average": 4.25, "lowest": 4.0},
+	})
+	s.Set(fmt.Sprintf("/markets/%d/types", RegionID), []int{TypeID})
+}
+
+// marketOrder returns a Tritanium order at the fixture station
+func marketOrder(id int64, price float64, buy bool) map[string]any {
+	return map[string]any{
+		"order_id":      id,
+		"type_id":       TypeID,
+		"location_id":   StationID,
+		"system_id":     30000142,
+		"volume_total":  1000000,
+		"volume_remain": 500000,
+		"min_volume":    1,
+		"price":         price,
+		"is_buy_order":  buy,
+		"duration":      90,
+		"issued":        fixtureEpoch,
+		"range":         "region",
+	}
+}
+
+// affiliation answers POST /characters/affiliation: the fixture character keeps its corporation
+// and alliance, any other ID belongs to an NPC corporation
+func affiliation(r Request) (int, any) {
+	var ids []int
+	if err := json.Unmarshal(r.Body, &ids); err != nil || len(ids) == 0 {
+		return http.StatusBadRequest, "Invalid character IDs"
+	}
+
+	result := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		if id == CharacterID {
+			result = append(result, map[string]any{"character_id": id, "corporation_id": CorporationID, "alliance_id": AllianceID})
+		} else {
+			result = append(result, map[string]any{"character_id": id, "corporation_id": 1000166})
+		}
+	}
+	return http.StatusOK, result
+}
@@ -0,0 +1,306 @@
+// Package esitest runs an in-process mock of ESI for tests. The server answers from canned
+// fixtures with ESI's caching behaviour (ETag, Expires, 304 Not Modified), pagination (X-Pages) and
+// error-limit headers, so code using evegateway can be exercised without calling live ESI:
+//
+//	server := esitest.NewServer()
+//	defer server.Close()
+//	client := server.Client()
+//	info, err := client.Character.GetCharacterInfo(ctx, esitest.CharacterID)
+package esitest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-falcon/pkg/evegateway"
+)
+
+// DefaultErrorLimit is the error budget of a fresh server, as on live ESI
+const DefaultErrorLimit = 100
+
+// DefaultMaxAge is how long fixtures may be cached unless set otherwise
+const DefaultMaxAge = 5 * time.Minute
+
+// Request is a request received by the server
+type Request struct {
+	Method        string
+	Path          string
+	Query         string
+	Authorization string
+	IfNoneMatch   string
+	Body          []byte
+}
+
+// HandlerFunc answers requests that cannot be served from a static fixture, such as POST
+// endpoints whose response depends on the body. It returns the status and the JSON value to send.
+type HandlerFunc func(r Request) (int, any)
+
+// fixture is the canned response of one endpoint
+type fixture struct {
+	status  int
+	pages   [][]byte
+	maxAge  time.Duration
+	handler HandlerFunc
+}
+
+// Server is a mock ESI server
+type Server struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	fixtures       map[string]*fixture
+	requests       []Request
+	errorRemain    int
+	errorWindow    time.Duration
+	errorWindowEnd time.Time
+}
+
+// NewServer starts a mock ESI server loaded with the default fixtures
+func NewServer() *Server {
+	s := &Server{
+		fixtures:    make(map[string]*fixture),
+		errorRemain: DefaultErrorLimit,
+		errorWindow: time.Minute,
+	}
+	s.errorWindowEnd = time.Now().Add(s.errorWindow)
+	loadDefaultFixtures(s)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns an ESI client with an empty in-memory cache that talks to this server
+func (s *Server) Client() *evegateway.Client {
+	return evegateway.NewClientWithBaseURL(s.URL)
+}
+
+// Set makes GET path answer with body. Paths are matched without their version prefix and
+// trailing slash, so "/characters/1/" also serves "/latest/characters/1".
+func (s *Server) Set(path string, body any) {
+	s.SetPages(path, body)
+}
+
+// SetPages makes GET path a paginated list; each page is a JSON array and X-Pages is their count
+func (s *Server) SetPages(path string, pages ...any) {
+	encoded := make([][]byte, len(pages))
+	for i, page := range pages {
+		encoded[i] = mustJSON(page)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures[routeKey(http.MethodGet, path)] = &fixture{status: http.StatusOK, pages: encoded, maxAge: DefaultMaxAge}
+}
+
+// SetMaxAge changes how long the response of GET path may be cached
+func (s *Server) SetMaxAge(path string, maxAge time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f, ok := s.fixtures[routeKey(http.MethodGet, path)]; ok {
+		f.maxAge = maxAge
+	}
+}
+
+// SetStatus makes GET path fail with the given status, which counts against the error limit
+func (s *Server) SetStatus(path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures[routeKey(http.MethodGet, path)] = &fixture{status: status}
+}
+
+// Handle answers method and path with a handler
+func (s *Server) Handle(method, path string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures[routeKey(method, path)] = &fixture{handler: handler}
+}
+
+// SetErrorLimit sets the remaining error budget and the length of the error-limit window
+func (s *Server) SetErrorLimit(remain int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errorRemain = remain
+	s.errorWindow = window
+	s.errorWindowEnd = time.Now().Add(window)
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestCount returns how many requests were made to method and path
+func (s *Server) RequestCount(method, path string) int {
+	key := routeKey(method, path)
+	count := 0
+	for _, r := range s.Requests() {
+		if routeKey(r.Method, r.Path) == key {
+			count++
+		}
+	}
+	return count
+}
+
+// Reset forgets the recorded requests and restores the error budget
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.errorRemain = DefaultErrorLimit
+	s.errorWindowEnd = time.Now().Add(s.errorWindow)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := Request{
+		Method:        r.Method,
+		Path:          r.URL.Path,
+		Query:         r.URL.RawQuery,
+		Authorization: r.Header.Get("Authorization"),
+		IfNoneMatch:   r.Header.Get("If-None-Match"),
+		Body:          body,
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	f, found := s.fixtures[routeKey(r.Method, r.URL.Path)]
+	var fx fixture
+	if found {
+		fx = *f
+	}
+	s.mu.Unlock()
+
+	switch {
+	case !found:
+		s.writeError(w, http.StatusNotFound, "Not found")
+	case fx.handler != nil:
+		status, value := fx.handler(req)
+		if status >= 400 {
+			s.writeError(w, status, fmt.Sprint(value))
+			return
+		}
+		s.writeErrorLimit(w, false)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if value != nil {
+			w.Write(mustJSON(value))
+		}
+	case fx.status >= 400:
+		s.writeError(w, fx.status, http.StatusText(fx.status))
+	default:
+		s.writePage(w, r, fx)
+	}
+}
+
+// writePage serves a fixture page with ESI's caching headers, or 304 when the client's ETag matches
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, fx fixture) {
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil {
+		page = p
+	}
+	if page < 1 || page > len(fx.pages) {
+		s.writeError(w, http.StatusNotFound, "Requested page does not exist")
+		return
+	}
+	body := fx.pages[page-1]
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	s.writeErrorLimit(w, false)
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(fx.maxAge.Seconds())))
+	header.Set("Expires", time.Now().Add(fx.maxAge).UTC().Format(http.TimeFormat))
+	header.Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	if len(fx.pages) > 1 {
+		header.Set("X-Pages", strconv.Itoa(len(fx.pages)))
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// writeError answers with an ESI error body and spends one error from the budget; once the budget
+// is exhausted every request is answered with 420 until the window resets
+func (s *Server) writeError(w http.ResponseWriter, status int, message string) {
+	if s.writeErrorLimit(w, true) && status != http.StatusTooManyRequests {
+		status = 420
+		message = "This software has exceeded the error limit for ESI."
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(mustJSON(map[string]string{"error": message}))
+}
+
+// writeErrorLimit sets the X-ESI-Error-Limit headers, spending an error if asked, and reports
+// whether the budget is exhausted
+func (s *Server) writeErrorLimit(w http.ResponseWriter, spend bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.errorWindowEnd) {
+		s.errorRemain = DefaultErrorLimit
+		s.errorWindowEnd = now.Add(s.errorWindow)
+	}
+	exhausted := s.errorRemain <= 0
+	if spend && !exhausted {
+		s.errorRemain--
+	}
+
+	w.Header().Set("X-ESI-Error-Limit-Remain", strconv.Itoa(s.errorRemain))
+	w.Header().Set("X-ESI-Error-Limit-Reset", strconv.Itoa(int(s.errorWindowEnd.Sub(now).Seconds())))
+	return exhausted
+}
+
+// routeKey normalizes a request to "METHOD /path": version prefixes (/latest, /v1, ...) and the
+// trailing slash are dropped because the sub-clients are not consistent about them
+func routeKey(method, path string) string {
+	path = strings.Trim(path, "/")
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	if len(segments) > 1 && isVersion(segments[0]) {
+		segments = segments[1:]
+	}
+	return strings.ToUpper(method) + " /" + strings.Join(segments, "/")
+}
+
+// isVersion reports whether a path segment is an ESI version prefix
+func isVersion(segment string) bool {
+	switch segment {
+	case "latest", "legacy", "dev":
+		return true
+	}
+	if len(segment) > 1 && segment[0] == 'v' {
+		_, err := strconv.Atoi(segment[1:])
+		return err == nil
+	}
+	return false
+}
+
+func mustJSON(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("esitest: fixture is not JSON-encodable: %v", err))
+	}
+	return data
+}
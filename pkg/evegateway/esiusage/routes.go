@@ -0,0 +1,51 @@
+// Package esiusage serves the ESI usage report of the evegateway client
+package esiusage
+
+import (
+	"context"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/apidocs"
+	"go-falcon/pkg/evegateway"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// SuperAdminChecker authorizes access to the ESI usage report
+type SuperAdminChecker interface {
+	RequireSuperAdmin(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error)
+}
+
+// ReportInput is the input for the ESI usage report endpoint
+type ReportInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// ReportOutput wraps the ESI usage report
+type ReportOutput struct {
+	Body evegateway.UsageReport
+}
+
+// RegisterRoutes registers the ESI usage report endpoint (super admin only)
+func RegisterRoutes(api huma.API, path string, client *evegateway.Client, auth SuperAdminChecker) {
+	huma.Register(api, huma.Operation{
+		OperationID: "admin-get-esi-usage",
+		Method:      "GET",
+		Path:        path,
+		Summary:     "Get ESI usage report",
+		Description: "Returns the ESI requests made since startup broken down by originating module and endpoint, with error rates, and the response cache hit rate per endpoint. Requires super admin access.",
+		Tags:        []string{"Health"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
+		Security: []map[string][]string{
+			{"bearerAuth": {}},
+			{"cookieAuth": {}},
+		},
+	}, func(ctx context.Context, input *ReportInput) (*ReportOutput, error) {
+		if _, err := auth.RequireSuperAdmin(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+
+		return &ReportOutput{Body: client.UsageReport()}, nil
+	})
+}
@@ -0,0 +1,324 @@
+package factionwarfare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// SystemsResult contains faction warfare systems and cache information
+type SystemsResult struct {
+	Data  []System  `json:"data"`
+	Cache CacheInfo `json:"cache"`
+}
+
+// StatsResult contains per-faction statistics and cache information
+type StatsResult struct {
+	Data  []FactionStats `json:"data"`
+	Cache CacheInfo      `json:"cache"`
+}
+
+// Client interface for faction warfare ESI operations
+type Client interface {
+	GetFWSystems(ctx context.Context) ([]System, error)
+	GetFWSystemsWithCache(ctx context.Context) (*SystemsResult, error)
+	GetFWWars(ctx context.Context) ([]War, error)
+	GetFWStats(ctx context.Context) ([]FactionStats, error)
+	GetFWStatsWithCache(ctx context.Context) (*StatsResult, error)
+	GetCharacterFWStats(ctx context.Context, characterID int, token string) (*CharacterStats, error)
+	GetCorporationFWStats(ctx context.Context, corporationID int, token string) (*CorporationStats, error)
+}
+
+// System is the state of a contested solar system. Contested is one of captured, contested,
+// uncontested or vulnerable; the occupier captures it once VictoryPoints reaches the threshold.
+type System struct {
+	SolarSystemID          int32  `json:"solar_system_id"`
+	OwnerFactionID         int32  `json:"owner_faction_id"`
+	OccupierFactionID      int32  `json:"occupier_faction_id"`
+	Contested              string `json:"contested"`
+	VictoryPoints          int32  `json:"victory_points"`
+	VictoryPointsThreshold int32  `json:"victory_points_threshold"`
+}
+
+// War is a faction at war with another faction
+type War struct {
+	FactionID int32 `json:"faction_id"`
+	AgainstID int32 `json:"against_id"`
+}
+
+// Totals counts kills or victory points yesterday, over the last week and overall
+type Totals struct {
+	Yesterday int32 `json:"yesterday"`
+	LastWeek  int32 `json:"last_week"`
+	Total     int32 `json:"total"`
+}
+
+// FactionStats is a faction's warfare statistics
+type FactionStats struct {
+	FactionID         int32  `json:"faction_id"`
+	Pilots            int32  `json:"pilots"`
+	SystemsControlled int32  `json:"systems_controlled"`
+	Kills             Totals `json:"kills"`
+	VictoryPoints     Totals `json:"victory_points"`
+}
+
+// CharacterStats is a character's warfare statistics; enlistment fields are empty outside militia
+type CharacterStats struct {
+	FactionID     *int32     `json:"faction_id,omitempty"`
+	EnlistedOn    *time.Time `json:"enlisted_on,omitempty"`
+	CurrentRank   *int32     `json:"current_rank,omitempty"`
+	HighestRank   *int32     `json:"highest_rank,omitempty"`
+	Kills         Totals     `json:"kills"`
+	VictoryPoints Totals     `json:"victory_points"`
+}
+
+// CorporationStats is a corporation's warfare statistics; enlistment fields are empty outside militia
+type CorporationStats struct {
+	FactionID     *int32     `json:"faction_id,omitempty"`
+	EnlistedOn    *time.Time `json:"enlisted_on,omitempty"`
+	Pilots        *int32     `json:"pilots,omitempty"`
+	Kills         Totals     `json:"kills"`
+	VictoryPoints Totals     `json:"victory_points"`
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewFactionWarfareClient creates a new faction warfare client
+func NewFactionWarfareClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetFWSystems retrieves the state of every contested solar system (public)
+func (c *ClientImpl) GetFWSystems(ctx context.Context) ([]System, error) {
+	result, err := c.GetFWSystemsWithCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetFWSystemsWithCache retrieves faction warfare systems with cache info
+func (c *ClientImpl) GetFWSystemsWithCache(ctx context.Context) (*SystemsResult, error) {
+	var systems []System
+	cached, err := c.get(ctx, "/fw/systems/", "", &systems)
+	if err != nil {
+		return nil, err
+	}
+	return &SystemsResult{Data: systems, Cache: c.cacheInfo("/fw/systems/", cached)}, nil
+}
+
+// GetFWWars retrieves which factions are at war with each other (public)
+func (c *ClientImpl) GetFWWars(ctx context.Context) ([]War, error) {
+	var wars []War
+	_, err := c.get(ctx, "/fw/wars/", "", &wars)
+	return wars, err
+}
+
+// GetFWStats retrieves the statistics of every faction (public)
+func (c *ClientImpl) GetFWStats(ctx context.Context) ([]FactionStats, error) {
+	result, err := c.GetFWStatsWithCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetFWStatsWithCache retrieves faction statistics with cache info
+func (c *ClientImpl) GetFWStatsWithCache(ctx context.Context) (*StatsResult, error) {
+	var stats []FactionStats
+	cached, err := c.get(ctx, "/fw/stats/", "", &stats)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsResult{Data: stats, Cache: c.cacheInfo("/fw/stats/", cached)}, nil
+}
+
+// GetCharacterFWStats retrieves a character's warfare statistics (requires esi-characters.read_fw_stats.v1)
+func (c *ClientImpl) GetCharacterFWStats(ctx context.Context, characterID int, token string) (*CharacterStats, error) {
+	var stats CharacterStats
+	if _, err := c.get(ctx, fmt.Sprintf("/characters/%d/fw/stats/", characterID), token, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetCorporationFWStats retrieves a corporation's warfare statistics (requires esi-corporations.read_fw_stats.v1)
+func (c *ClientImpl) GetCorporationFWStats(ctx context.Context, corporationID int, token string) (*CorporationStats, error) {
+	var stats CorporationStats
+	if _, err := c.get(ctx, fmt.Sprintf("/corporations/%d/fw/stats/", corporationID), token, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// cacheInfo builds the cache information of an endpoint's response
+func (c *ClientImpl) cacheInfo(endpoint string, cached bool) CacheInfo {
+	return CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)}
+}
+
+// get fetches an endpoint into v, serving it from cache while fresh and revalidating it with its
+// ETag once expired
+func (c *ClientImpl) get(ctx context.Context, endpoint, token string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, token, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, token, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs a GET, authenticated when a token is given. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, token, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/factionwarfare")
+		ctx, span = tracer.Start(ctx, "factionwarfare.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI faction warfare endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI faction warfare endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
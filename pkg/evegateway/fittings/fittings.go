@@ -0,0 +1,225 @@
+package fittings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// FittingsResult contains character fittings and cache information
+type FittingsResult struct {
+	Data  []FittingResponse `json:"data"`
+	Cache CacheInfo         `json:"cache"`
+}
+
+// Client interface for fittings-related ESI operations
+type Client interface {
+	GetCharacterFittings(ctx context.Context, characterID int, token string) ([]FittingResponse, error)
+	GetCharacterFittingsWithCache(ctx context.Context, characterID int, token string) (*FittingsResult, error)
+}
+
+// FittingResponse represents a saved in-game fitting from ESI
+type FittingResponse struct {
+	FittingID   int64         `json:"fitting_id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	ShipTypeID  int32         `json:"ship_type_id"`
+	Items       []FittingItem `json:"items"`
+}
+
+// FittingItem represents a single module, charge, drone or cargo item in a fitting
+type FittingItem struct {
+	TypeID   int32  `json:"type_id"`
+	Flag     string `json:"flag"`
+	Quantity int32  `json:"quantity"`
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewFittingsClient creates a new fittings client
+func NewFittingsClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetCharacterFittings retrieves a character's saved fittings from ESI (requires esi-fittings.read_fittings.v1)
+func (c *ClientImpl) GetCharacterFittings(ctx context.Context, characterID int, token string) ([]FittingResponse, error) {
+	endpoint := fmt.Sprintf("/characters/%d/fittings/", characterID)
+	cacheKey := fmt.Sprintf("%s%s", c.baseURL, endpoint)
+
+	body, err := c.makeAuthenticatedRequest(ctx, endpoint, token, cacheKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var fittings []FittingResponse
+	if err := json.Unmarshal(body, &fittings); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return fittings, nil
+}
+
+// GetCharacterFittingsWithCache retrieves a character's saved fittings from ESI with cache info
+func (c *ClientImpl) GetCharacterFittingsWithCache(ctx context.Context, characterID int, token string) (*FittingsResult, error) {
+	endpoint := fmt.Sprintf("/characters/%d/fittings/", characterID)
+	cacheKey := fmt.Sprintf("%s%s", c.baseURL, endpoint)
+
+	if cachedData, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		var fittings []FittingResponse
+		if err := json.Unmarshal(cachedData, &fittings); err == nil {
+			return &FittingsResult{
+				Data:  fittings,
+				Cache: CacheInfo{Cached: true, ExpiresAt: expiry},
+			}, nil
+		}
+	}
+
+	data, err := c.GetCharacterFittings(ctx, characterID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var cacheExpiry *time.Time
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		cacheExpiry = expiry
+	}
+
+	return &FittingsResult{
+		Data:  data,
+		Cache: CacheInfo{Cached: false, ExpiresAt: cacheExpiry},
+	}, nil
+}
+
+// Helper method to make authenticated requests
+func (c *ClientImpl) makeAuthenticatedRequest(ctx context.Context, endpoint, token string, cacheKey string) ([]byte, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/fittings")
+		ctx, span = tracer.Start(ctx, "fittings.makeAuthenticatedRequest")
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("esi.endpoint", endpoint),
+			attribute.String("cache.key", cacheKey),
+		)
+	}
+
+	// Check cache first
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if span != nil {
+			span.SetAttributes(attribute.Bool("cache.hit", true))
+			span.SetStatus(codes.Ok, "cache hit")
+		}
+		return cachedData, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+endpoint, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// Add conditional headers if we have cached data
+	c.cacheManager.SetConditionalHeaders(req, cacheKey)
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		return nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	// Handle 304 Not Modified
+	if resp.StatusCode == http.StatusNotModified {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			c.cacheManager.RefreshExpiry(cacheKey, resp.Header)
+			return cachedData, nil
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		return nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	// Update cache
+	c.cacheManager.Set(cacheKey, body, resp.Header)
+
+	return body, nil
+}
@@ -0,0 +1,521 @@
+package fleets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// MembersResult contains fleet members and cache information
+type MembersResult struct {
+	Data  []Member  `json:"data"`
+	Cache CacheInfo `json:"cache"`
+}
+
+// Client interface for fleet-related ESI operations. Reads require esi-fleets.read_fleet.v1 and
+// writes esi-fleets.write_fleet.v1; everything except GetCharacterFleet must be called with the
+// fleet boss's token.
+type Client interface {
+	GetCharacterFleet(ctx context.Context, characterID int, token string) (*CharacterFleet, error)
+	GetFleet(ctx context.Context, fleetID int64, token string) (*Fleet, error)
+	UpdateFleet(ctx context.Context, fleetID int64, update FleetUpdate, token string) error
+	GetFleetMembers(ctx context.Context, fleetID int64, token string) ([]Member, error)
+	GetFleetMembersWithCache(ctx context.Context, fleetID int64, token string) (*MembersResult, error)
+	InviteFleetMember(ctx context.Context, fleetID int64, invitation Invitation, token string) error
+	KickFleetMember(ctx context.Context, fleetID int64, memberID int, token string) error
+	MoveFleetMember(ctx context.Context, fleetID int64, memberID int, movement Movement, token string) error
+	GetFleetWings(ctx context.Context, fleetID int64, token string) ([]Wing, error)
+	CreateFleetWing(ctx context.Context, fleetID int64, token string) (int64, error)
+	RenameFleetWing(ctx context.Context, fleetID, wingID int64, name, token string) error
+	DeleteFleetWing(ctx context.Context, fleetID, wingID int64, token string) error
+	CreateFleetSquad(ctx context.Context, fleetID, wingID int64, token string) (int64, error)
+	RenameFleetSquad(ctx context.Context, fleetID, squadID int64, name, token string) error
+	DeleteFleetSquad(ctx context.Context, fleetID, squadID int64, token string) error
+}
+
+// Fleet roles used in members, invitations and movements
+const (
+	RoleFleetCommander = "fleet_commander"
+	RoleWingCommander  = "wing_commander"
+	RoleSquadCommander = "squad_commander"
+	RoleSquadMember    = "squad_member"
+)
+
+// CharacterFleet is the fleet a character is currently in
+type CharacterFleet struct {
+	FleetID     int64  `json:"fleet_id"`
+	FleetBossID int32  `json:"fleet_boss_id"`
+	Role        string `json:"role"`
+	WingID      int64  `json:"wing_id"`
+	SquadID     int64  `json:"squad_id"`
+}
+
+// Fleet holds the fleet settings
+type Fleet struct {
+	IsFreeMove     bool   `json:"is_free_move"`
+	IsRegistered   bool   `json:"is_registered"`
+	IsVoiceEnabled bool   `json:"is_voice_enabled"`
+	Motd           string `json:"motd"`
+}
+
+// FleetUpdate changes fleet settings; nil fields are left unchanged
+type FleetUpdate struct {
+	IsFreeMove *bool   `json:"is_free_move,omitempty"`
+	Motd       *string `json:"motd,omitempty"`
+}
+
+// Member is a fleet member. WingID and SquadID are -1 for the fleet commander, SquadID is -1 for
+// wing commanders.
+type Member struct {
+	CharacterID    int32     `json:"character_id"`
+	JoinTime       time.Time `json:"join_time"`
+	Role           string    `json:"role"`
+	RoleName       string    `json:"role_name"`
+	ShipTypeID     int32     `json:"ship_type_id"`
+	SolarSystemID  int32     `json:"solar_system_id"`
+	StationID      *int64    `json:"station_id,omitempty"`
+	TakesFleetWarp bool      `json:"takes_fleet_warp"`
+	WingID         int64     `json:"wing_id"`
+	SquadID        int64     `json:"squad_id"`
+}
+
+// Invitation invites a character into the fleet. Squad members need both WingID and SquadID,
+// squad commanders as well, wing commanders only WingID and fleet commanders neither.
+type Invitation struct {
+	CharacterID int    `json:"character_id"`
+	Role        string `json:"role"`
+	WingID      *int64 `json:"wing_id,omitempty"`
+	SquadID     *int64 `json:"squad_id,omitempty"`
+}
+
+// Movement moves a member to another position; WingID and SquadID follow the Invitation rules
+type Movement struct {
+	Role    string `json:"role"`
+	WingID  *int64 `json:"wing_id,omitempty"`
+	SquadID *int64 `json:"squad_id,omitempty"`
+}
+
+// Wing is a fleet wing with its squads
+type Wing struct {
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Squads []Squad `json:"squads"`
+}
+
+// Squad is a squad within a wing
+type Squad struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Error is an error response from a fleet endpoint. ESI answers 404 when the fleet does not exist
+// or the token's character is not its boss, and 422 with a reason when an invite or move is refused.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("ESI returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// NotFound reports whether the fleet does not exist or cannot be accessed with the token
+func (e *Error) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsError returns the fleet Error wrapped in err, if any
+func IsError(err error) (*Error, bool) {
+	var fleetErr *Error
+	if errors.As(err, &fleetErr) {
+		return fleetErr, true
+	}
+	return nil, false
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewFleetsClient creates a new fleets client
+func NewFleetsClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetCharacterFleet returns the fleet the character is in; a character outside any fleet gets a
+// 404 *Error
+func (c *ClientImpl) GetCharacterFleet(ctx context.Context, characterID int, token string) (*CharacterFleet, error) {
+	var fleet CharacterFleet
+	if _, err := c.get(ctx, fmt.Sprintf("/characters/%d/fleet/", characterID), token, &fleet); err != nil {
+		return nil, err
+	}
+	return &fleet, nil
+}
+
+// GetFleet retrieves the fleet settings
+func (c *ClientImpl) GetFleet(ctx context.Context, fleetID int64, token string) (*Fleet, error) {
+	var fleet Fleet
+	if _, err := c.get(ctx, fmt.Sprintf("/fleets/%d/", fleetID), token, &fleet); err != nil {
+		return nil, err
+	}
+	return &fleet, nil
+}
+
+// UpdateFleet changes the free-move setting and MOTD
+func (c *ClientImpl) UpdateFleet(ctx context.Context, fleetID int64, update FleetUpdate, token string) error {
+	return c.send(ctx, http.MethodPut, fmt.Sprintf("/fleets/%d/", fleetID), token, update, nil)
+}
+
+// GetFleetMembers retrieves the fleet members with their ship, location and position
+func (c *ClientImpl) GetFleetMembers(ctx context.Context, fleetID int64, token string) ([]Member, error) {
+	var members []Member
+	_, err := c.get(ctx, fmt.Sprintf("/fleets/%d/members/", fleetID), token, &members)
+	return members, err
+}
+
+// GetFleetMembersWithCache retrieves the fleet members with cache info
+func (c *ClientImpl) GetFleetMembersWithCache(ctx context.Context, fleetID int64, token string) (*MembersResult, error) {
+	endpoint := fmt.Sprintf("/fleets/%d/members/", fleetID)
+
+	var members []Member
+	cached, err := c.get(ctx, endpoint, token, &members)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MembersResult{
+		Data:  members,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)},
+	}, nil
+}
+
+// InviteFleetMember sends a fleet invitation; the character still has to accept it in game
+func (c *ClientImpl) InviteFleetMember(ctx context.Context, fleetID int64, invitation Invitation, token string) error {
+	return c.send(ctx, http.MethodPost, fmt.Sprintf("/fleets/%d/members/", fleetID), token, invitation, nil)
+}
+
+// KickFleetMember removes a member from the fleet
+func (c *ClientImpl) KickFleetMember(ctx context.Context, fleetID int64, memberID int, token string) error {
+	return c.send(ctx, http.MethodDelete, fmt.Sprintf("/fleets/%d/members/%d/", fleetID, memberID), token, nil, nil)
+}
+
+// MoveFleetMember moves a member to another role, wing or squad
+func (c *ClientImpl) MoveFleetMember(ctx context.Context, fleetID int64, memberID int, movement Movement, token string) error {
+	return c.send(ctx, http.MethodPut, fmt.Sprintf("/fleets/%d/members/%d/", fleetID, memberID), token, movement, nil)
+}
+
+// GetFleetWings retrieves the wings and squads of the fleet
+func (c *ClientImpl) GetFleetWings(ctx context.Context, fleetID int64, token string) ([]Wing, error) {
+	var wings []Wing
+	_, err := c.get(ctx, fmt.Sprintf("/fleets/%d/wings/", fleetID), token, &wings)
+	return wings, err
+}
+
+// CreateFleetWing creates a wing and returns its ID
+func (c *ClientImpl) CreateFleetWing(ctx context.Context, fleetID int64, token string) (int64, error) {
+	var created struct {
+		WingID int64 `json:"wing_id"`
+	}
+	err := c.send(ctx, http.MethodPost, fmt.Sprintf("/fleets/%d/wings/", fleetID), token, nil, &created)
+	return created.WingID, err
+}
+
+// RenameFleetWing renames a wing
+func (c *ClientImpl) RenameFleetWing(ctx context.Context, fleetID, wingID int64, name, token string) error {
+	return c.send(ctx, http.MethodPut, fmt.Sprintf("/fleets/%d/wings/%d/", fleetID, wingID), token, naming{Name: name}, nil)
+}
+
+// DeleteFleetWing deletes an empty wing
+func (c *ClientImpl) DeleteFleetWing(ctx context.Context, fleetID, wingID int64, token string) error {
+	return c.send(ctx, http.MethodDelete, fmt.Sprintf("/fleets/%d/wings/%d/", fleetID, wingID), token, nil, nil)
+}
+
+// CreateFleetSquad creates a squad in a wing and returns its ID
+func (c *ClientImpl) CreateFleetSquad(ctx context.Context, fleetID, wingID int64, token string) (int64, error) {
+	var created struct {
+		SquadID int64 `json:"squad_id"`
+	}
+	err := c.send(ctx, http.MethodPost, fmt.Sprintf("/fleets/%d/wings/%d/squads/", fleetID, wingID), token, nil, &created)
+	return created.SquadID, err
+}
+
+// RenameFleetSquad renames a squad
+func (c *ClientImpl) RenameFleetSquad(ctx context.Context, fleetID, squadID int64, name, token string) error {
+	return c.send(ctx, http.MethodPut, fmt.Sprintf("/fleets/%d/squads/%d/", fleetID, squadID), token, naming{Name: name}, nil)
+}
+
+// DeleteFleetSquad deletes an empty squad
+func (c *ClientImpl) DeleteFleetSquad(ctx context.Context, fleetID, squadID int64, token string) error {
+	return c.send(ctx, http.MethodDelete, fmt.Sprintf("/fleets/%d/squads/%d/", fleetID, squadID), token, nil, nil)
+}
+
+// naming is the request body for renaming wings and squads
+type naming struct {
+	Name string `json:"name"`
+}
+
+// get fetches an endpoint into v, serving it from cache while fresh and revalidating it with its
+// ETag once expired
+func (c *ClientImpl) get(ctx context.Context, endpoint, token string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, token, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, token, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs an authenticated GET. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, token, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/fleets")
+		ctx, span = tracer.Start(ctx, "fleets.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI fleet endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		fleetErr := &Error{StatusCode: resp.StatusCode, Message: esiErrorMessage(body)}
+		if !fleetErr.NotFound() {
+			slog.ErrorContext(ctx, "ESI fleet endpoint returned error", "url", url, "status_code", resp.StatusCode, "error", fleetErr.Message)
+		}
+		return nil, nil, fleetErr
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// send performs a write request with an optional JSON payload and decodes the response into v
+// when set. Writes go out once through the plain HTTP client, as the retry client cannot replay a
+// request body and an invite or kick should not be repeated blindly.
+func (c *ClientImpl) send(ctx context.Context, method, endpoint, token string, payload, v any) error {
+	requestURL := c.baseURL + endpoint
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/fleets")
+		ctx, span = tracer.Start(ctx, "fleets.send")
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("esi.url", requestURL),
+			attribute.String("http.method", method),
+		)
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI fleet endpoint", "url", requestURL, "method", method, "error", err)
+		return fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		fleetErr := &Error{StatusCode: resp.StatusCode, Message: esiErrorMessage(respBody)}
+		slog.ErrorContext(ctx, "ESI fleet endpoint returned error",
+			"url", requestURL, "method", method, "status_code", resp.StatusCode, "error", fleetErr.Message)
+		return fleetErr
+	}
+
+	if span != nil {
+		span.SetStatus(codes.Ok, "request completed")
+	}
+
+	if v == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
+
+// esiErrorMessage extracts the error text from an ESI error body
+func esiErrorMessage(body []byte) string {
+	var esiErr struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &esiErr); err == nil && esiErr.Error != "" {
+		return esiErr.Error
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,5 @@
+package evegateway
+
+// Generated clients for the endpoint families without a hand-written client. Add a family to the
+// list to generate it; see cmd/esigen and the "Generated Clients" section of CLAUDE.md.
+//go:generate go run ../../cmd/esigen -source openapi.json -output generated -tags "Contacts,Insurance,Planetary Interaction"
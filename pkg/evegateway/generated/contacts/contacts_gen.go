@@ -0,0 +1,290 @@
+// Code generated by esigen from the ESI OpenAPI spec. DO NOT EDIT.
+
+// Package contacts is the generated client for the Contacts endpoints of ESI.
+package contacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"go-falcon/pkg/evegateway/esiruntime"
+)
+
+// Client interface for Contacts ESI operations
+type Client interface {
+	GetAlliancesAllianceIDContacts(ctx context.Context, allianceID int64, token esiruntime.TokenProvider) ([]AlliancesAllianceIDContactsGetItem, error)
+	GetAlliancesAllianceIDContactsWithCache(ctx context.Context, allianceID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]AlliancesAllianceIDContactsGetItem], error)
+	GetAlliancesAllianceIDContactsLabels(ctx context.Context, allianceID int64, token esiruntime.TokenProvider) ([]AlliancesAllianceIDContactsLabelsGetItem, error)
+	GetAlliancesAllianceIDContactsLabelsWithCache(ctx context.Context, allianceID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]AlliancesAllianceIDContactsLabelsGetItem], error)
+	DeleteCharactersCharacterIDContacts(ctx context.Context, characterID int64, params DeleteCharactersCharacterIDContactsParams, token esiruntime.TokenProvider) error
+	GetCharactersCharacterIDContacts(ctx context.Context, characterID int64, token esiruntime.TokenProvider) ([]CharactersCharacterIDContactsGetItem, error)
+	GetCharactersCharacterIDContactsWithCache(ctx context.Context, characterID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CharactersCharacterIDContactsGetItem], error)
+	PostCharactersCharacterIDContacts(ctx context.Context, characterID int64, params PostCharactersCharacterIDContactsParams, body []int64, token esiruntime.TokenProvider) ([]int64, error)
+	PutCharactersCharacterIDContacts(ctx context.Context, characterID int64, params PutCharactersCharacterIDContactsParams, body []int64, token esiruntime.TokenProvider) error
+	GetCharactersCharacterIDContactsLabels(ctx context.Context, characterID int64, token esiruntime.TokenProvider) ([]CharactersCharacterIDContactsLabelsGetItem, error)
+	GetCharactersCharacterIDContactsLabelsWithCache(ctx context.Context, characterID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CharactersCharacterIDContactsLabelsGetItem], error)
+	GetCorporationsCorporationIDContacts(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) ([]CorporationsCorporationIDContactsGetItem, error)
+	GetCorporationsCorporationIDContactsWithCache(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CorporationsCorporationIDContactsGetItem], error)
+	GetCorporationsCorporationIDContactsLabels(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) ([]CorporationsCorporationIDContactsLabelsGetItem, error)
+	GetCorporationsCorporationIDContactsLabelsWithCache(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CorporationsCorporationIDContactsLabelsGetItem], error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	runtime *esiruntime.Runtime
+}
+
+// NewClient creates a Contacts client on top of the shared ESI runtime
+func NewClient(runtime *esiruntime.Runtime) Client {
+	return &ClientImpl{runtime: runtime}
+}
+
+// GetAlliancesAllianceIDContacts calls GET /alliances/{alliance_id}/contacts (Get alliance contacts)
+// Fetches all pages; cached for 300s; requires esi-alliances.read_contacts.v1.
+func (c *ClientImpl) GetAlliancesAllianceIDContacts(ctx context.Context, allianceID int64, token esiruntime.TokenProvider) ([]AlliancesAllianceIDContactsGetItem, error) {
+	result, err := c.GetAlliancesAllianceIDContactsWithCache(ctx, allianceID, token)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetAlliancesAllianceIDContactsWithCache is GetAlliancesAllianceIDContacts with cache info
+func (c *ClientImpl) GetAlliancesAllianceIDContactsWithCache(ctx context.Context, allianceID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]AlliancesAllianceIDContactsGetItem], error) {
+	return esiruntime.GetAllPages[AlliancesAllianceIDContactsGetItem](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/alliances/%s/contacts", esiruntime.PathParam(allianceID)),
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// GetAlliancesAllianceIDContactsLabels calls GET /alliances/{alliance_id}/contacts/labels (Get alliance contact labels)
+// Cached for 300s; requires esi-alliances.read_contacts.v1.
+func (c *ClientImpl) GetAlliancesAllianceIDContactsLabels(ctx context.Context, allianceID int64, token esiruntime.TokenProvider) ([]AlliancesAllianceIDContactsLabelsGetItem, error) {
+	result, err := c.GetAlliancesAllianceIDContactsLabelsWithCache(ctx, allianceID, token)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetAlliancesAllianceIDContactsLabelsWithCache is GetAlliancesAllianceIDContactsLabels with cache info
+func (c *ClientImpl) GetAlliancesAllianceIDContactsLabelsWithCache(ctx context.Context, allianceID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]AlliancesAllianceIDContactsLabelsGetItem], error) {
+	return esiruntime.Get[[]AlliancesAllianceIDContactsLabelsGetItem](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/alliances/%s/contacts/labels", esiruntime.PathParam(allianceID)),
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// DeleteCharactersCharacterIDContacts calls DELETE /characters/{character_id}/contacts (Delete contacts)
+// Requires esi-characters.write_contacts.v1.
+func (c *ClientImpl) DeleteCharactersCharacterIDContacts(ctx context.Context, characterID int64, params DeleteCharactersCharacterIDContactsParams, token esiruntime.TokenProvider) error {
+	query := url.Values{}
+	query.Set("contact_ids", esiruntime.QueryParam(params.ContactIDs))
+	_, err := esiruntime.Send[json.RawMessage](ctx, c.runtime, esiruntime.Request{
+		Method:            "DELETE",
+		Endpoint:          fmt.Sprintf("/characters/%s/contacts", esiruntime.PathParam(characterID)),
+		Query:             query,
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+	return err
+}
+
+// GetCharactersCharacterIDContacts calls GET /characters/{character_id}/contacts (Get contacts)
+// Fetches all pages; cached for 300s; requires esi-characters.read_contacts.v1.
+func (c *ClientImpl) GetCharactersCharacterIDContacts(ctx context.Context, characterID int64, token esiruntime.TokenProvider) ([]CharactersCharacterIDContactsGetItem, error) {
+	result, err := c.GetCharactersCharacterIDContactsWithCache(ctx, characterID, token)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetCharactersCharacterIDContactsWithCache is GetCharactersCharacterIDContacts with cache info
+func (c *ClientImpl) GetCharactersCharacterIDContactsWithCache(ctx context.Context, characterID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CharactersCharacterIDContactsGetItem], error) {
+	return esiruntime.GetAllPages[CharactersCharacterIDContactsGetItem](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/characters/%s/contacts", esiruntime.PathParam(characterID)),
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// PostCharactersCharacterIDContacts calls POST /characters/{character_id}/contacts (Add contacts)
+// Requires esi-characters.write_contacts.v1.
+func (c *ClientImpl) PostCharactersCharacterIDContacts(ctx context.Context, characterID int64, params PostCharactersCharacterIDContactsParams, body []int64, token esiruntime.TokenProvider) ([]int64, error) {
+	query := url.Values{}
+	if len(params.LabelIDs) > 0 {
+		query.Set("label_ids", esiruntime.QueryParam(params.LabelIDs))
+	}
+	query.Set("standing", esiruntime.QueryParam(params.Standing))
+	if params.Watched != nil {
+		query.Set("watched", esiruntime.QueryParam(*params.Watched))
+	}
+	return esiruntime.Send[[]int64](ctx, c.runtime, esiruntime.Request{
+		Method:            "POST",
+		Endpoint:          fmt.Sprintf("/characters/%s/contacts", esiruntime.PathParam(characterID)),
+		Query:             query,
+		Token:             token,
+		Body:              body,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// PutCharactersCharacterIDContacts calls PUT /characters/{character_id}/contacts (Edit contacts)
+// Requires esi-characters.write_contacts.v1.
+func (c *ClientImpl) PutCharactersCharacterIDContacts(ctx context.Context, characterID int64, params PutCharactersCharacterIDContactsParams, body []int64, token esiruntime.TokenProvider) error {
+	query := url.Values{}
+	if len(params.LabelIDs) > 0 {
+		query.Set("label_ids", esiruntime.QueryParam(params.LabelIDs))
+	}
+	query.Set("standing", esiruntime.QueryParam(params.Standing))
+	if params.Watched != nil {
+		query.Set("watched", esiruntime.QueryParam(*params.Watched))
+	}
+	_, err := esiruntime.Send[json.RawMessage](ctx, c.runtime, esiruntime.Request{
+		Method:            "PUT",
+		Endpoint:          fmt.Sprintf("/characters/%s/contacts", esiruntime.PathParam(characterID)),
+		Query:             query,
+		Token:             token,
+		Body:              body,
+		CompatibilityDate: "2020-01-01",
+	})
+	return err
+}
+
+// GetCharactersCharacterIDContactsLabels calls GET /characters/{character_id}/contacts/labels (Get contact labels)
+// Cached for 300s; requires esi-characters.read_contacts.v1.
+func (c *ClientImpl) GetCharactersCharacterIDContactsLabels(ctx context.Context, characterID int64, token esiruntime.TokenProvider) ([]CharactersCharacterIDContactsLabelsGetItem, error) {
+	result, err := c.GetCharactersCharacterIDContactsLabelsWithCache(ctx, characterID, token)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetCharactersCharacterIDContactsLabelsWithCache is GetCharactersCharacterIDContactsLabels with cache info
+func (c *ClientImpl) GetCharactersCharacterIDContactsLabelsWithCache(ctx context.Context, characterID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CharactersCharacterIDContactsLabelsGetItem], error) {
+	return esiruntime.Get[[]CharactersCharacterIDContactsLabelsGetItem](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/characters/%s/contacts/labels", esiruntime.PathParam(characterID)),
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// GetCorporationsCorporationIDContacts calls GET /corporations/{corporation_id}/contacts (Get corporation contacts)
+// Fetches all pages; cached for 300s; requires esi-corporations.read_contacts.v1.
+func (c *ClientImpl) GetCorporationsCorporationIDContacts(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) ([]CorporationsCorporationIDContactsGetItem, error) {
+	result, err := c.GetCorporationsCorporationIDContactsWithCache(ctx, corporationID, token)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetCorporationsCorporationIDContactsWithCache is GetCorporationsCorporationIDContacts with cache info
+func (c *ClientImpl) GetCorporationsCorporationIDContactsWithCache(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CorporationsCorporationIDContactsGetItem], error) {
+	return esiruntime.GetAllPages[CorporationsCorporationIDContactsGetItem](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/corporations/%s/contacts", esiruntime.PathParam(corporationID)),
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// GetCorporationsCorporationIDContactsLabels calls GET /corporations/{corporation_id}/contacts/labels (Get corporation contact labels)
+// Cached for 300s; requires esi-corporations.read_contacts.v1.
+func (c *ClientImpl) GetCorporationsCorporationIDContactsLabels(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) ([]CorporationsCorporationIDContactsLabelsGetItem, error) {
+	result, err := c.GetCorporationsCorporationIDContactsLabelsWithCache(ctx, corporationID, token)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetCorporationsCorporationIDContactsLabelsWithCache is GetCorporationsCorporationIDContactsLabels with cache info
+func (c *ClientImpl) GetCorporationsCorporationIDContactsLabelsWithCache(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CorporationsCorporationIDContactsLabelsGetItem], error) {
+	return esiruntime.Get[[]CorporationsCorporationIDContactsLabelsGetItem](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/corporations/%s/contacts/labels", esiruntime.PathParam(corporationID)),
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// AlliancesAllianceIDContactsGetItem is an ESI model
+type AlliancesAllianceIDContactsGetItem struct {
+	ContactID   int64   `json:"contact_id"`
+	ContactType string  `json:"contact_type"`
+	LabelIDs    []int64 `json:"label_ids,omitempty"`
+	// Standing of the contact
+	Standing float64 `json:"standing"`
+}
+
+// AlliancesAllianceIDContactsLabelsGetItem is an ESI model
+type AlliancesAllianceIDContactsLabelsGetItem struct {
+	LabelID   int64  `json:"label_id"`
+	LabelName string `json:"label_name"`
+}
+
+// CharactersCharacterIDContactsGetItem is an ESI model
+type CharactersCharacterIDContactsGetItem struct {
+	ContactID   int64  `json:"contact_id"`
+	ContactType string `json:"contact_type"`
+	// Whether this contact is in the blocked list. Note a missing value denotes unknown, not true or false
+	IsBlocked *bool `json:"is_blocked,omitempty"`
+	// Whether this contact is being watched
+	IsWatched *bool   `json:"is_watched,omitempty"`
+	LabelIDs  []int64 `json:"label_ids,omitempty"`
+	// Standing of the contact
+	Standing float64 `json:"standing"`
+}
+
+// CharactersCharacterIDContactsLabelsGetItem is an ESI model
+type CharactersCharacterIDContactsLabelsGetItem struct {
+	LabelID   int64  `json:"label_id"`
+	LabelName string `json:"label_name"`
+}
+
+// CorporationsCorporationIDContactsGetItem is an ESI model
+type CorporationsCorporationIDContactsGetItem struct {
+	ContactID   int64  `json:"contact_id"`
+	ContactType string `json:"contact_type"`
+	// Whether this contact is being watched
+	IsWatched *bool   `json:"is_watched,omitempty"`
+	LabelIDs  []int64 `json:"label_ids,omitempty"`
+	// Standing of the contact
+	Standing float64 `json:"standing"`
+}
+
+// CorporationsCorporationIDContactsLabelsGetItem is an ESI model
+type CorporationsCorporationIDContactsLabelsGetItem struct {
+	LabelID   int64  `json:"label_id"`
+	LabelName string `json:"label_name"`
+}
+
+// DeleteCharactersCharacterIDContactsParams holds the query parameters of DeleteCharactersCharacterIDContacts
+type DeleteCharactersCharacterIDContactsParams struct {
+	ContactIDs []int64
+}
+
+// PostCharactersCharacterIDContactsParams holds the query parameters of PostCharactersCharacterIDContacts
+type PostCharactersCharacterIDContactsParams struct {
+	LabelIDs []int64
+	Standing float64
+	Watched  *bool
+}
+
+// PutCharactersCharacterIDContactsParams holds the query parameters of PutCharactersCharacterIDContacts
+type PutCharactersCharacterIDContactsParams struct {
+	LabelIDs []int64
+	Standing float64
+	Watched  *bool
+}
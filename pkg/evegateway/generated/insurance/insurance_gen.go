@@ -0,0 +1,60 @@
+// Code generated by esigen from the ESI OpenAPI spec. DO NOT EDIT.
+
+// Package insurance is the generated client for the Insurance endpoints of ESI.
+package insurance
+
+import (
+	"context"
+
+	"go-falcon/pkg/evegateway/esiruntime"
+)
+
+// Client interface for Insurance ESI operations
+type Client interface {
+	GetInsurancePrices(ctx context.Context) ([]InsurancePricesGetItem, error)
+	GetInsurancePricesWithCache(ctx context.Context) (*esiruntime.Result[[]InsurancePricesGetItem], error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	runtime *esiruntime.Runtime
+}
+
+// NewClient creates a Insurance client on top of the shared ESI runtime
+func NewClient(runtime *esiruntime.Runtime) Client {
+	return &ClientImpl{runtime: runtime}
+}
+
+// GetInsurancePrices calls GET /insurance/prices (List insurance levels)
+// Cached for 3600s.
+func (c *ClientImpl) GetInsurancePrices(ctx context.Context) ([]InsurancePricesGetItem, error) {
+	result, err := c.GetInsurancePricesWithCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetInsurancePricesWithCache is GetInsurancePrices with cache info
+func (c *ClientImpl) GetInsurancePricesWithCache(ctx context.Context) (*esiruntime.Result[[]InsurancePricesGetItem], error) {
+	return esiruntime.Get[[]InsurancePricesGetItem](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          "/insurance/prices",
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// InsurancePricesGetItem is an ESI model
+type InsurancePricesGetItem struct {
+	// A list of a available insurance levels for this ship type
+	Levels []InsurancePricesGetItemLevelsItem `json:"levels"`
+	TypeID int64                              `json:"type_id"`
+}
+
+// InsurancePricesGetItemLevelsItem: level object
+type InsurancePricesGetItemLevelsItem struct {
+	Cost float64 `json:"cost"`
+	// Localized insurance level
+	Name   string  `json:"name"`
+	Payout float64 `json:"payout"`
+}
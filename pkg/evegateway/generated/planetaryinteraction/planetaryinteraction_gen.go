@@ -0,0 +1,224 @@
+// Code generated by esigen from the ESI OpenAPI spec. DO NOT EDIT.
+
+// Package planetaryinteraction is the generated client for the Planetary Interaction endpoints of ESI.
+package planetaryinteraction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-falcon/pkg/evegateway/esiruntime"
+)
+
+// Client interface for Planetary Interaction ESI operations
+type Client interface {
+	GetCharactersCharacterIDPlanets(ctx context.Context, characterID int64, token esiruntime.TokenProvider) ([]CharactersCharacterIDPlanetsGetItem, error)
+	GetCharactersCharacterIDPlanetsWithCache(ctx context.Context, characterID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CharactersCharacterIDPlanetsGetItem], error)
+	GetCharactersCharacterIDPlanetsPlanetID(ctx context.Context, characterID int64, planetID int64, token esiruntime.TokenProvider) (CharactersCharacterIDPlanetsPlanetIDGet, error)
+	GetCharactersCharacterIDPlanetsPlanetIDWithCache(ctx context.Context, characterID int64, planetID int64, token esiruntime.TokenProvider) (*esiruntime.Result[CharactersCharacterIDPlanetsPlanetIDGet], error)
+	GetCorporationsCorporationIDCustomsOffices(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) ([]CorporationsCorporationIDCustomsOfficesGetItem, error)
+	GetCorporationsCorporationIDCustomsOfficesWithCache(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CorporationsCorporationIDCustomsOfficesGetItem], error)
+	GetUniverseSchematicsSchematicID(ctx context.Context, schematicID int64) (UniverseSchematicsSchematicIDGet, error)
+	GetUniverseSchematicsSchematicIDWithCache(ctx context.Context, schematicID int64) (*esiruntime.Result[UniverseSchematicsSchematicIDGet], error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	runtime *esiruntime.Runtime
+}
+
+// NewClient creates a Planetary Interaction client on top of the shared ESI runtime
+func NewClient(runtime *esiruntime.Runtime) Client {
+	return &ClientImpl{runtime: runtime}
+}
+
+// GetCharactersCharacterIDPlanets calls GET /characters/{character_id}/planets (Get colonies)
+// Cached for 600s; requires esi-planets.manage_planets.v1.
+func (c *ClientImpl) GetCharactersCharacterIDPlanets(ctx context.Context, characterID int64, token esiruntime.TokenProvider) ([]CharactersCharacterIDPlanetsGetItem, error) {
+	result, err := c.GetCharactersCharacterIDPlanetsWithCache(ctx, characterID, token)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetCharactersCharacterIDPlanetsWithCache is GetCharactersCharacterIDPlanets with cache info
+func (c *ClientImpl) GetCharactersCharacterIDPlanetsWithCache(ctx context.Context, characterID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CharactersCharacterIDPlanetsGetItem], error) {
+	return esiruntime.Get[[]CharactersCharacterIDPlanetsGetItem](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/characters/%s/planets", esiruntime.PathParam(characterID)),
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// GetCharactersCharacterIDPlanetsPlanetID calls GET /characters/{character_id}/planets/{planet_id} (Get colony layout)
+// Requires esi-planets.manage_planets.v1.
+func (c *ClientImpl) GetCharactersCharacterIDPlanetsPlanetID(ctx context.Context, characterID int64, planetID int64, token esiruntime.TokenProvider) (CharactersCharacterIDPlanetsPlanetIDGet, error) {
+	result, err := c.GetCharactersCharacterIDPlanetsPlanetIDWithCache(ctx, characterID, planetID, token)
+	if err != nil {
+		return CharactersCharacterIDPlanetsPlanetIDGet{}, err
+	}
+	return result.Data, nil
+}
+
+// GetCharactersCharacterIDPlanetsPlanetIDWithCache is GetCharactersCharacterIDPlanetsPlanetID with cache info
+func (c *ClientImpl) GetCharactersCharacterIDPlanetsPlanetIDWithCache(ctx context.Context, characterID int64, planetID int64, token esiruntime.TokenProvider) (*esiruntime.Result[CharactersCharacterIDPlanetsPlanetIDGet], error) {
+	return esiruntime.Get[CharactersCharacterIDPlanetsPlanetIDGet](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/characters/%s/planets/%s", esiruntime.PathParam(characterID), esiruntime.PathParam(planetID)),
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// GetCorporationsCorporationIDCustomsOffices calls GET /corporations/{corporation_id}/customs_offices (List corporation customs offices)
+// Fetches all pages; cached for 3600s; requires esi-planets.read_customs_offices.v1.
+func (c *ClientImpl) GetCorporationsCorporationIDCustomsOffices(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) ([]CorporationsCorporationIDCustomsOfficesGetItem, error) {
+	result, err := c.GetCorporationsCorporationIDCustomsOfficesWithCache(ctx, corporationID, token)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetCorporationsCorporationIDCustomsOfficesWithCache is GetCorporationsCorporationIDCustomsOffices with cache info
+func (c *ClientImpl) GetCorporationsCorporationIDCustomsOfficesWithCache(ctx context.Context, corporationID int64, token esiruntime.TokenProvider) (*esiruntime.Result[[]CorporationsCorporationIDCustomsOfficesGetItem], error) {
+	return esiruntime.GetAllPages[CorporationsCorporationIDCustomsOfficesGetItem](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/corporations/%s/customs_offices", esiruntime.PathParam(corporationID)),
+		Token:             token,
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// GetUniverseSchematicsSchematicID calls GET /universe/schematics/{schematic_id} (Get schematic information)
+// Cached for 3600s.
+func (c *ClientImpl) GetUniverseSchematicsSchematicID(ctx context.Context, schematicID int64) (UniverseSchematicsSchematicIDGet, error) {
+	result, err := c.GetUniverseSchematicsSchematicIDWithCache(ctx, schematicID)
+	if err != nil {
+		return UniverseSchematicsSchematicIDGet{}, err
+	}
+	return result.Data, nil
+}
+
+// GetUniverseSchematicsSchematicIDWithCache is GetUniverseSchematicsSchematicID with cache info
+func (c *ClientImpl) GetUniverseSchematicsSchematicIDWithCache(ctx context.Context, schematicID int64) (*esiruntime.Result[UniverseSchematicsSchematicIDGet], error) {
+	return esiruntime.Get[UniverseSchematicsSchematicIDGet](ctx, c.runtime, esiruntime.Request{
+		Method:            "GET",
+		Endpoint:          fmt.Sprintf("/universe/schematics/%s", esiruntime.PathParam(schematicID)),
+		CompatibilityDate: "2020-01-01",
+	})
+}
+
+// CharactersCharacterIDPlanetsGetItem is an ESI model
+type CharactersCharacterIDPlanetsGetItem struct {
+	LastUpdate    time.Time `json:"last_update"`
+	NumPins       int64     `json:"num_pins"`
+	OwnerID       int64     `json:"owner_id"`
+	PlanetID      int64     `json:"planet_id"`
+	PlanetType    string    `json:"planet_type"`
+	SolarSystemID int64     `json:"solar_system_id"`
+	UpgradeLevel  int64     `json:"upgrade_level"`
+}
+
+// CharactersCharacterIDPlanetsPlanetIDGet is an ESI model
+type CharactersCharacterIDPlanetsPlanetIDGet struct {
+	Links  []CharactersCharacterIDPlanetsPlanetIDGetLinksItem  `json:"links"`
+	Pins   []CharactersCharacterIDPlanetsPlanetIDGetPinsItem   `json:"pins"`
+	Routes []CharactersCharacterIDPlanetsPlanetIDGetRoutesItem `json:"routes"`
+}
+
+// CharactersCharacterIDPlanetsPlanetIDGetLinksItem: link object
+type CharactersCharacterIDPlanetsPlanetIDGetLinksItem struct {
+	DestinationPinID int64 `json:"destination_pin_id"`
+	LinkLevel        int64 `json:"link_level"`
+	SourcePinID      int64 `json:"source_pin_id"`
+}
+
+// CharactersCharacterIDPlanetsPlanetIDGetPinsItem: pin object
+type CharactersCharacterIDPlanetsPlanetIDGetPinsItem struct {
+	Contents         []CharactersCharacterIDPlanetsPlanetIDGetPinsItemContentsItem    `json:"contents,omitempty"`
+	ExpiryTime       *time.Time                                                       `json:"expiry_time,omitempty"`
+	ExtractorDetails *CharactersCharacterIDPlanetsPlanetIDGetPinsItemExtractorDetails `json:"extractor_details,omitempty"`
+	FactoryDetails   *CharactersCharacterIDPlanetsPlanetIDGetPinsItemFactoryDetails   `json:"factory_details,omitempty"`
+	InstallTime      *time.Time                                                       `json:"install_time,omitempty"`
+	LastCycleStart   *time.Time                                                       `json:"last_cycle_start,omitempty"`
+	Latitude         float64                                                          `json:"latitude"`
+	Longitude        float64                                                          `json:"longitude"`
+	PinID            int64                                                            `json:"pin_id"`
+	SchematicID      *int64                                                           `json:"schematic_id,omitempty"`
+	TypeID           int64                                                            `json:"type_id"`
+}
+
+// CharactersCharacterIDPlanetsPlanetIDGetPinsItemContentsItem: content object
+type CharactersCharacterIDPlanetsPlanetIDGetPinsItemContentsItem struct {
+	Amount int64 `json:"amount"`
+	TypeID int64 `json:"type_id"`
+}
+
+// CharactersCharacterIDPlanetsPlanetIDGetPinsItemExtractorDetails is an ESI model
+type CharactersCharacterIDPlanetsPlanetIDGetPinsItemExtractorDetails struct {
+	// in seconds
+	CycleTime     *int64                                                                     `json:"cycle_time,omitempty"`
+	HeadRadius    *float64                                                                   `json:"head_radius,omitempty"`
+	Heads         []CharactersCharacterIDPlanetsPlanetIDGetPinsItemExtractorDetailsHeadsItem `json:"heads"`
+	ProductTypeID *int64                                                                     `json:"product_type_id,omitempty"`
+	QtyPerCycle   *int64                                                                     `json:"qty_per_cycle,omitempty"`
+}
+
+// CharactersCharacterIDPlanetsPlanetIDGetPinsItemExtractorDetailsHeadsItem: head object
+type CharactersCharacterIDPlanetsPlanetIDGetPinsItemExtractorDetailsHeadsItem struct {
+	HeadID    int64   `json:"head_id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// CharactersCharacterIDPlanetsPlanetIDGetPinsItemFactoryDetails is an ESI model
+type CharactersCharacterIDPlanetsPlanetIDGetPinsItemFactoryDetails struct {
+	SchematicID int64 `json:"schematic_id"`
+}
+
+// CharactersCharacterIDPlanetsPlanetIDGetRoutesItem: route object
+type CharactersCharacterIDPlanetsPlanetIDGetRoutesItem struct {
+	ContentTypeID    int64   `json:"content_type_id"`
+	DestinationPinID int64   `json:"destination_pin_id"`
+	Quantity         float64 `json:"quantity"`
+	RouteID          int64   `json:"route_id"`
+	SourcePinID      int64   `json:"source_pin_id"`
+	// list of pin ID waypoints
+	Waypoints []int64 `json:"waypoints,omitempty"`
+}
+
+// CorporationsCorporationIDCustomsOfficesGetItem is an ESI model
+type CorporationsCorporationIDCustomsOfficesGetItem struct {
+	// Only present if alliance access is allowed
+	AllianceTaxRate *float64 `json:"alliance_tax_rate,omitempty"`
+	// standing_level and any standing related tax rate only present when this is true
+	AllowAccessWithStandings bool     `json:"allow_access_with_standings"`
+	AllowAllianceAccess      bool     `json:"allow_alliance_access"`
+	BadStandingTaxRate       *float64 `json:"bad_standing_tax_rate,omitempty"`
+	CorporationTaxRate       *float64 `json:"corporation_tax_rate,omitempty"`
+	// Tax rate for entities with excellent level of standing, only present if this level is allowed, same for all other standing related tax rates
+	ExcellentStandingTaxRate *float64 `json:"excellent_standing_tax_rate,omitempty"`
+	GoodStandingTaxRate      *float64 `json:"good_standing_tax_rate,omitempty"`
+	NeutralStandingTaxRate   *float64 `json:"neutral_standing_tax_rate,omitempty"`
+	// unique ID of this customs office
+	OfficeID         int64 `json:"office_id"`
+	ReinforceExitEnd int64 `json:"reinforce_exit_end"`
+	// Together with reinforce_exit_end, marks a 2-hour period where this customs office could exit reinforcement mode during the day after initial attack
+	ReinforceExitStart int64 `json:"reinforce_exit_start"`
+	// Access is allowed only for entities with this level of standing or better
+	StandingLevel *string `json:"standing_level,omitempty"`
+	// ID of the solar system this customs office is located in
+	SystemID                int64    `json:"system_id"`
+	TerribleStandingTaxRate *float64 `json:"terrible_standing_tax_rate,omitempty"`
+}
+
+// UniverseSchematicsSchematicIDGet is an ESI model
+type UniverseSchematicsSchematicIDGet struct {
+	// Time in seconds to process a run
+	CycleTime     int64  `json:"cycle_time"`
+	SchematicName string `json:"schematic_name"`
+}
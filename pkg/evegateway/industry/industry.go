@@ -0,0 +1,368 @@
+package industry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/evegateway/paging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// JobsResult contains industry jobs and cache information
+type JobsResult struct {
+	Data  []Job     `json:"data"`
+	Cache CacheInfo `json:"cache"`
+}
+
+// SystemsResult contains solar system cost indices and cache information
+type SystemsResult struct {
+	Data  []SystemCostIndices `json:"data"`
+	Cache CacheInfo           `json:"cache"`
+}
+
+// Client interface for industry-related ESI operations
+type Client interface {
+	GetCharacterIndustryJobs(ctx context.Context, characterID int, includeCompleted bool, token string) ([]Job, error)
+	GetCharacterIndustryJobsWithCache(ctx context.Context, characterID int, includeCompleted bool, token string) (*JobsResult, error)
+	GetCorporationIndustryJobs(ctx context.Context, corporationID int, includeCompleted bool, token string) ([]Job, error)
+	GetCorporationIndustryJobsWithCache(ctx context.Context, corporationID int, includeCompleted bool, token string) (*JobsResult, error)
+	GetIndustryFacilities(ctx context.Context) ([]Facility, error)
+	GetIndustrySystems(ctx context.Context) ([]SystemCostIndices, error)
+	GetIndustrySystemsWithCache(ctx context.Context) (*SystemsResult, error)
+}
+
+// Job represents an industry job from ESI. Character jobs report where they run in StationID,
+// corporation jobs in LocationID.
+type Job struct {
+	JobID                int32      `json:"job_id"`
+	ActivityID           int32      `json:"activity_id"`
+	Status               string     `json:"status"`
+	InstallerID          int32      `json:"installer_id"`
+	FacilityID           int64      `json:"facility_id"`
+	StationID            int64      `json:"station_id,omitempty"`
+	LocationID           int64      `json:"location_id,omitempty"`
+	BlueprintID          int64      `json:"blueprint_id"`
+	BlueprintTypeID      int32      `json:"blueprint_type_id"`
+	BlueprintLocationID  int64      `json:"blueprint_location_id"`
+	OutputLocationID     int64      `json:"output_location_id"`
+	ProductTypeID        *int32     `json:"product_type_id,omitempty"`
+	Runs                 int32      `json:"runs"`
+	LicensedRuns         *int32     `json:"licensed_runs,omitempty"`
+	SuccessfulRuns       *int32     `json:"successful_runs,omitempty"`
+	Probability          *float64   `json:"probability,omitempty"`
+	Cost                 *float64   `json:"cost,omitempty"`
+	Duration             int32      `json:"duration"`
+	StartDate            time.Time  `json:"start_date"`
+	EndDate              time.Time  `json:"end_date"`
+	PauseDate            *time.Time `json:"pause_date,omitempty"`
+	CompletedDate        *time.Time `json:"completed_date,omitempty"`
+	CompletedCharacterID *int32     `json:"completed_character_id,omitempty"`
+}
+
+// Facility represents a public industry facility (NPC station or player structure)
+type Facility struct {
+	FacilityID    int64    `json:"facility_id"`
+	OwnerID       int32    `json:"owner_id"`
+	RegionID      int32    `json:"region_id"`
+	SolarSystemID int32    `json:"solar_system_id"`
+	TypeID        int32    `json:"type_id"`
+	Tax           *float64 `json:"tax,omitempty"`
+}
+
+// SystemCostIndices holds the job cost index of every activity in a solar system
+type SystemCostIndices struct {
+	SolarSystemID int32       `json:"solar_system_id"`
+	CostIndices   []CostIndex `json:"cost_indices"`
+}
+
+// CostIndex is the job cost index of one activity, e.g. "manufacturing" or "researching_time_efficiency"
+type CostIndex struct {
+	Activity  string  `json:"activity"`
+	CostIndex float64 `json:"cost_index"`
+}
+
+// Industry activity IDs used in Job.ActivityID
+const (
+	ActivityManufacturing              = 1
+	ActivityResearchTimeEfficiency     = 3
+	ActivityResearchMaterialEfficiency = 4
+	ActivityCopying                    = 5
+	ActivityInvention                  = 8
+	ActivityReactions                  = 9
+)
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewIndustryClient creates a new industry client
+func NewIndustryClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetCharacterIndustryJobs retrieves a character's active and paused jobs, plus jobs finished in the
+// last 90 days when includeCompleted is set (requires esi-industry.read_character_jobs.v1)
+func (c *ClientImpl) GetCharacterIndustryJobs(ctx context.Context, characterID int, includeCompleted bool, token string) ([]Job, error) {
+	var jobs []Job
+	_, err := c.getList(ctx, jobsEndpoint(fmt.Sprintf("/characters/%d/industry/jobs/", characterID), includeCompleted), token, &jobs)
+	return jobs, err
+}
+
+// GetCharacterIndustryJobsWithCache retrieves a character's industry jobs with cache info
+func (c *ClientImpl) GetCharacterIndustryJobsWithCache(ctx context.Context, characterID int, includeCompleted bool, token string) (*JobsResult, error) {
+	return c.getJobsWithCache(ctx, jobsEndpoint(fmt.Sprintf("/characters/%d/industry/jobs/", characterID), includeCompleted), token)
+}
+
+// GetCorporationIndustryJobs retrieves all pages of a corporation's industry jobs
+// (requires esi-industry.read_corporation_jobs.v1 and the Factory_Manager role)
+func (c *ClientImpl) GetCorporationIndustryJobs(ctx context.Context, corporationID int, includeCompleted bool, token string) ([]Job, error) {
+	var jobs []Job
+	_, err := c.getList(ctx, jobsEndpoint(fmt.Sprintf("/corporations/%d/industry/jobs/", corporationID), includeCompleted), token, &jobs)
+	return jobs, err
+}
+
+// GetCorporationIndustryJobsWithCache retrieves a corporation's industry jobs with cache info
+func (c *ClientImpl) GetCorporationIndustryJobsWithCache(ctx context.Context, corporationID int, includeCompleted bool, token string) (*JobsResult, error) {
+	return c.getJobsWithCache(ctx, jobsEndpoint(fmt.Sprintf("/corporations/%d/industry/jobs/", corporationID), includeCompleted), token)
+}
+
+// GetIndustryFacilities retrieves the public industry facilities
+func (c *ClientImpl) GetIndustryFacilities(ctx context.Context) ([]Facility, error) {
+	var facilities []Facility
+	_, err := c.getList(ctx, "/industry/facilities/", "", &facilities)
+	return facilities, err
+}
+
+// GetIndustrySystems retrieves the job cost indices of every solar system (refreshed hourly by ESI)
+func (c *ClientImpl) GetIndustrySystems(ctx context.Context) ([]SystemCostIndices, error) {
+	var systems []SystemCostIndices
+	_, err := c.getList(ctx, "/industry/systems/", "", &systems)
+	return systems, err
+}
+
+// GetIndustrySystemsWithCache retrieves solar system cost indices with cache info
+func (c *ClientImpl) GetIndustrySystemsWithCache(ctx context.Context) (*SystemsResult, error) {
+	var systems []SystemCostIndices
+	cached, err := c.getList(ctx, "/industry/systems/", "", &systems)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SystemsResult{
+		Data:  systems,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + "/industry/systems/")},
+	}, nil
+}
+
+// getJobsWithCache wraps getList for job lists, adding the cache expiry
+func (c *ClientImpl) getJobsWithCache(ctx context.Context, endpoint, token string) (*JobsResult, error) {
+	var jobs []Job
+	cached, err := c.getList(ctx, endpoint, token, &jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JobsResult{
+		Data:  jobs,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)},
+	}, nil
+}
+
+// getList fetches a list endpoint into v, following X-Pages. The complete list is cached under
+// the endpoint with the first page's headers. Single-page responses are revalidated with their
+// ETag once expired; multi-page lists are refetched, since one page's ETag says nothing about the others.
+// An empty token makes an unauthenticated request.
+func (c *ClientImpl) getList(ctx context.Context, endpoint, token string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, token, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	totalPages := paging.TotalPages(headers)
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found && totalPages <= 1 {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch the first page unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, token, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if totalPages > 1 {
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			return false, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		rest, err := paging.FetchRemainingPages[json.RawMessage](ctx, totalPages, func(ctx context.Context, page int) ([]byte, http.Header, error) {
+			return c.fetch(ctx, paging.PageURL(cacheKey, page), token, "")
+		}, paging.DefaultConcurrency)
+		if err != nil {
+			return false, err
+		}
+		items = append(items, rest...)
+
+		if body, err = json.Marshal(items); err != nil {
+			return false, fmt.Errorf("failed to combine pages: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs a GET, authenticated when token is set. When conditionalKey is set, the request
+// carries the ETag cached under that key and a 304 response is reported as a nil body with the
+// response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, token, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/industry")
+		ctx, span = tracer.Start(ctx, "industry.fetch")
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("esi.url", url),
+			attribute.Bool("esi.authenticated", token != ""),
+		)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI industry endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI industry endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
+
+// jobsEndpoint adds include_completed to a jobs endpoint; the flag is part of the cache key
+func jobsEndpoint(endpoint string, includeCompleted bool) string {
+	if includeCompleted {
+		return endpoint + "?include_completed=true"
+	}
+	return endpoint
+}
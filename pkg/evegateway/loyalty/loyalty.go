@@ -0,0 +1,254 @@
+package loyalty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// OffersResult contains LP store offers and cache information
+type OffersResult struct {
+	Data  []Offer   `json:"data"`
+	Cache CacheInfo `json:"cache"`
+}
+
+// Client interface for loyalty point ESI operations
+type Client interface {
+	GetCharacterLoyaltyPoints(ctx context.Context, characterID int, token string) ([]LoyaltyPoints, error)
+	GetLoyaltyStoreOffers(ctx context.Context, corporationID int) ([]Offer, error)
+	GetLoyaltyStoreOffersWithCache(ctx context.Context, corporationID int) (*OffersResult, error)
+}
+
+// LoyaltyPoints is a character's LP balance with one corporation
+type LoyaltyPoints struct {
+	CorporationID int32 `json:"corporation_id"`
+	LoyaltyPoints int32 `json:"loyalty_points"`
+}
+
+// Offer is an item sold in a corporation's LP store. Buying it costs LPCost loyalty points,
+// ISKCost ISK, AKCost analysis kredits (Concord only) and the RequiredItems.
+type Offer struct {
+	OfferID       int32          `json:"offer_id"`
+	TypeID        int32          `json:"type_id"`
+	Quantity      int32          `json:"quantity"`
+	LPCost        int32          `json:"lp_cost"`
+	ISKCost       int64          `json:"isk_cost"`
+	AKCost        int32          `json:"ak_cost,omitempty"`
+	RequiredItems []RequiredItem `json:"required_items"`
+}
+
+// RequiredItem is an item that has to be handed in with an offer
+type RequiredItem struct {
+	TypeID   int32 `json:"type_id"`
+	Quantity int32 `json:"quantity"`
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewLoyaltyClient creates a new loyalty client
+func NewLoyaltyClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetCharacterLoyaltyPoints retrieves a character's LP balances per corporation
+// (requires esi-characters.read_loyalty.v1)
+func (c *ClientImpl) GetCharacterLoyaltyPoints(ctx context.Context, characterID int, token string) ([]LoyaltyPoints, error) {
+	var points []LoyaltyPoints
+	_, err := c.get(ctx, fmt.Sprintf("/characters/%d/loyalty/points/", characterID), token, &points)
+	return points, err
+}
+
+// GetLoyaltyStoreOffers retrieves the offers of a corporation's LP store (public, cached for an hour by ESI)
+func (c *ClientImpl) GetLoyaltyStoreOffers(ctx context.Context, corporationID int) ([]Offer, error) {
+	var offers []Offer
+	_, err := c.get(ctx, offersEndpoint(corporationID), "", &offers)
+	return offers, err
+}
+
+// GetLoyaltyStoreOffersWithCache retrieves LP store offers with cache info
+func (c *ClientImpl) GetLoyaltyStoreOffersWithCache(ctx context.Context, corporationID int) (*OffersResult, error) {
+	endpoint := offersEndpoint(corporationID)
+
+	var offers []Offer
+	cached, err := c.get(ctx, endpoint, "", &offers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OffersResult{
+		Data:  offers,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)},
+	}, nil
+}
+
+// get fetches an endpoint into v, serving it from cache while fresh and revalidating it with its
+// ETag once expired
+func (c *ClientImpl) get(ctx context.Context, endpoint, token string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, token, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, token, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs a GET, authenticated when a token is given. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, token, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/loyalty")
+		ctx, span = tracer.Start(ctx, "loyalty.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI loyalty endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI loyalty endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
+
+// offersEndpoint returns the LP store endpoint of a corporation
+func offersEndpoint(corporationID int) string {
+	return fmt.Sprintf("/loyalty/stores/%d/offers/", corporationID)
+}
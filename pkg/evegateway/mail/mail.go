@@ -0,0 +1,521 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// HeadersResult contains mail headers and cache information
+type HeadersResult struct {
+	Data  []Header  `json:"data"`
+	Cache CacheInfo `json:"cache"`
+}
+
+// Client interface for mail-related ESI operations
+type Client interface {
+	GetMailHeaders(ctx context.Context, characterID int, labels []int32, lastMailID int32, token string) ([]Header, error)
+	GetMailHeadersWithCache(ctx context.Context, characterID int, labels []int32, lastMailID int32, token string) (*HeadersResult, error)
+	GetMail(ctx context.Context, characterID int, mailID int32, token string) (*Mail, error)
+	SendMail(ctx context.Context, characterID int, mail NewMail, token string) (int32, error)
+	UpdateMail(ctx context.Context, characterID int, mailID int32, update MailUpdate, token string) error
+	DeleteMail(ctx context.Context, characterID int, mailID int32, token string) error
+	GetMailLabels(ctx context.Context, characterID int, token string) (*Labels, error)
+	CreateMailLabel(ctx context.Context, characterID int, label NewLabel, token string) (int32, error)
+	DeleteMailLabel(ctx context.Context, characterID int, labelID int32, token string) error
+	GetMailingLists(ctx context.Context, characterID int, token string) ([]MailingList, error)
+}
+
+// Recipient types used in Recipient.RecipientType
+const (
+	RecipientAlliance    = "alliance"
+	RecipientCharacter   = "character"
+	RecipientCorporation = "corporation"
+	RecipientMailingList = "mailing_list"
+)
+
+// Recipient is a character, corporation, alliance or mailing list a mail is addressed to
+type Recipient struct {
+	RecipientID   int32  `json:"recipient_id"`
+	RecipientType string `json:"recipient_type"`
+}
+
+// Header is a mail as listed in the inbox, without its body
+type Header struct {
+	MailID     int32       `json:"mail_id"`
+	From       int32       `json:"from"`
+	Subject    string      `json:"subject"`
+	Timestamp  time.Time   `json:"timestamp"`
+	IsRead     bool        `json:"is_read"`
+	Labels     []int32     `json:"labels"`
+	Recipients []Recipient `json:"recipients"`
+}
+
+// Mail is a single mail including its body. The body is EVE client markup, not plain text.
+type Mail struct {
+	From       int32       `json:"from"`
+	Subject    string      `json:"subject"`
+	Body       string      `json:"body"`
+	Timestamp  time.Time   `json:"timestamp"`
+	Read       bool        `json:"read"`
+	Labels     []int32     `json:"labels"`
+	Recipients []Recipient `json:"recipients"`
+}
+
+// NewMail is a mail to send. ApprovedCost is the ISK the sender accepts to pay for recipients
+// that charge for mail (CSPA); leave it zero to refuse charges.
+type NewMail struct {
+	Recipients   []Recipient `json:"recipients"`
+	Subject      string      `json:"subject"`
+	Body         string      `json:"body"`
+	ApprovedCost int64       `json:"approved_cost,omitempty"`
+}
+
+// MailUpdate changes the read flag and labels of a mail; nil fields are left unchanged
+type MailUpdate struct {
+	Read   *bool   `json:"read,omitempty"`
+	Labels []int32 `json:"labels,omitempty"`
+}
+
+// Labels is a character's mail labels with unread counts
+type Labels struct {
+	Labels           []Label `json:"labels"`
+	TotalUnreadCount int32   `json:"total_unread_count"`
+}
+
+// Label is a mail label. IDs 1-8 are the built-in Inbox, Sent, Corp, Alliance and similar labels.
+type Label struct {
+	LabelID     int32  `json:"label_id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	UnreadCount int32  `json:"unread_count"`
+}
+
+// NewLabel is a label to create; Color is one of the hex colors the EVE client offers, e.g. "#ffffff"
+type NewLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+// MailingList is a mailing list the character is subscribed to. ESI can only list subscriptions;
+// joining, leaving and creating lists is done in the EVE client.
+type MailingList struct {
+	MailingListID int32  `json:"mailing_list_id"`
+	Name          string `json:"name"`
+}
+
+// ESI error messages returned with status 520 when the EVE server refuses to send a mail
+const (
+	ErrorContactCostNotApproved = "ContactCostNotApproved"
+	ErrorMailStopSpamming       = "MailStopSpamming"
+)
+
+// SendError is returned when ESI refuses to send a mail. ESI reports these failures as status 520
+// with the reason from the EVE server, e.g. a recipient charging for mail (CSPA) or the sender
+// hitting the mail rate limit. A 520 means the mail was not sent, so the request is never retried.
+type SendError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *SendError) Error() string {
+	return fmt.Sprintf("ESI refused to send mail (status %d): %s", e.StatusCode, e.Message)
+}
+
+// CostNotApproved reports whether a recipient charges for mail and the approved cost was too low
+func (e *SendError) CostNotApproved() bool {
+	return strings.Contains(e.Message, ErrorContactCostNotApproved)
+}
+
+// RateLimited reports whether the sender has sent too many mails recently
+func (e *SendError) RateLimited() bool {
+	return strings.Contains(e.Message, ErrorMailStopSpamming)
+}
+
+// IsSendError returns the SendError wrapped in err, if any
+func IsSendError(err error) (*SendError, bool) {
+	var sendErr *SendError
+	if errors.As(err, &sendErr) {
+		return sendErr, true
+	}
+	return nil, false
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewMailClient creates a new mail client
+func NewMailClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetMailHeaders retrieves up to 50 mail headers, newest first (requires esi-mail.read_mail.v1).
+// Pass the lowest mail ID of the previous call as lastMailID to page back; labels filters by label.
+func (c *ClientImpl) GetMailHeaders(ctx context.Context, characterID int, labels []int32, lastMailID int32, token string) ([]Header, error) {
+	var headers []Header
+	_, err := c.get(ctx, headersEndpoint(characterID, labels, lastMailID), token, &headers)
+	return headers, err
+}
+
+// GetMailHeadersWithCache retrieves mail headers with cache info
+func (c *ClientImpl) GetMailHeadersWithCache(ctx context.Context, characterID int, labels []int32, lastMailID int32, token string) (*HeadersResult, error) {
+	endpoint := headersEndpoint(characterID, labels, lastMailID)
+
+	var headers []Header
+	cached, err := c.get(ctx, endpoint, token, &headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HeadersResult{
+		Data:  headers,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)},
+	}, nil
+}
+
+// GetMail retrieves a mail with its body (requires esi-mail.read_mail.v1)
+func (c *ClientImpl) GetMail(ctx context.Context, characterID int, mailID int32, token string) (*Mail, error) {
+	var mail Mail
+	if _, err := c.get(ctx, fmt.Sprintf("/characters/%d/mail/%d/", characterID, mailID), token, &mail); err != nil {
+		return nil, err
+	}
+	return &mail, nil
+}
+
+// SendMail sends a mail and returns its ID (requires esi-mail.send_mail.v1). A refusal from the
+// EVE server is returned as a *SendError.
+func (c *ClientImpl) SendMail(ctx context.Context, characterID int, mail NewMail, token string) (int32, error) {
+	var mailID int32
+	err := c.send(ctx, http.MethodPost, fmt.Sprintf("/characters/%d/mail/", characterID), token, mail, &mailID)
+	return mailID, err
+}
+
+// UpdateMail marks a mail read or unread and replaces its labels (requires esi-mail.organize_mail.v1)
+func (c *ClientImpl) UpdateMail(ctx context.Context, characterID int, mailID int32, update MailUpdate, token string) error {
+	return c.send(ctx, http.MethodPut, fmt.Sprintf("/characters/%d/mail/%d/", characterID, mailID), token, update, nil)
+}
+
+// DeleteMail moves a mail to the trash (requires esi-mail.organize_mail.v1)
+func (c *ClientImpl) DeleteMail(ctx context.Context, characterID int, mailID int32, token string) error {
+	return c.send(ctx, http.MethodDelete, fmt.Sprintf("/characters/%d/mail/%d/", characterID, mailID), token, nil, nil)
+}
+
+// GetMailLabels retrieves a character's mail labels and unread counts (requires esi-mail.read_mail.v1)
+func (c *ClientImpl) GetMailLabels(ctx context.Context, characterID int, token string) (*Labels, error) {
+	var labels Labels
+	if _, err := c.get(ctx, fmt.Sprintf("/characters/%d/mail/labels/", characterID), token, &labels); err != nil {
+		return nil, err
+	}
+	return &labels, nil
+}
+
+// CreateMailLabel creates a mail label and returns its ID (requires esi-mail.organize_mail.v1)
+func (c *ClientImpl) CreateMailLabel(ctx context.Context, characterID int, label NewLabel, token string) (int32, error) {
+	var labelID int32
+	err := c.send(ctx, http.MethodPost, fmt.Sprintf("/characters/%d/mail/labels/", characterID), token, label, &labelID)
+	return labelID, err
+}
+
+// DeleteMailLabel deletes a custom mail label; built-in labels cannot be deleted
+// (requires esi-mail.organize_mail.v1)
+func (c *ClientImpl) DeleteMailLabel(ctx context.Context, characterID int, labelID int32, token string) error {
+	return c.send(ctx, http.MethodDelete, fmt.Sprintf("/characters/%d/mail/labels/%d/", characterID, labelID), token, nil, nil)
+}
+
+// GetMailingLists retrieves the mailing lists a character is subscribed to (requires esi-mail.read_mail.v1)
+func (c *ClientImpl) GetMailingLists(ctx context.Context, characterID int, token string) ([]MailingList, error) {
+	var lists []MailingList
+	_, err := c.get(ctx, fmt.Sprintf("/characters/%d/mail/lists/", characterID), token, &lists)
+	return lists, err
+}
+
+// get fetches an endpoint into v, serving it from cache while fresh and revalidating it with its
+// ETag once expired
+func (c *ClientImpl) get(ctx context.Context, endpoint, token string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, token, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, token, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs an authenticated GET. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, token, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/mail")
+		ctx, span = tracer.Start(ctx, "mail.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI mail endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI mail endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// send performs a write request with an optional JSON payload and decodes the response into v
+// when set. Writes go out once through the plain HTTP client: the retry client would resend a mail
+// on 5xx and cannot replay a request body.
+func (c *ClientImpl) send(ctx context.Context, method, endpoint, token string, payload, v any) error {
+	requestURL := c.baseURL + endpoint
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/mail")
+		ctx, span = tracer.Start(ctx, "mail.send")
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("esi.url", requestURL),
+			attribute.String("http.method", method),
+		)
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI mail endpoint", "url", requestURL, "method", method, "error", err)
+		return fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		message := esiErrorMessage(respBody)
+		slog.ErrorContext(ctx, "ESI mail endpoint returned error",
+			"url", requestURL, "method", method, "status_code", resp.StatusCode, "error", message)
+
+		if resp.StatusCode == 520 {
+			return &SendError{StatusCode: resp.StatusCode, Message: message}
+		}
+		return fmt.Errorf("ESI returned status %d: %s", resp.StatusCode, message)
+	}
+
+	if span != nil {
+		span.SetStatus(codes.Ok, "request completed")
+	}
+
+	if v == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
+
+// headersEndpoint builds the mail headers endpoint; the query is part of the cache key
+func headersEndpoint(characterID int, labels []int32, lastMailID int32) string {
+	query := url.Values{}
+	if len(labels) > 0 {
+		ids := make([]string, len(labels))
+		for i, label := range labels {
+			ids[i] = strconv.Itoa(int(label))
+		}
+		query.Set("labels", strings.Join(ids, ","))
+	}
+	if lastMailID > 0 {
+		query.Set("last_mail_id", strconv.Itoa(int(lastMailID)))
+	}
+
+	endpoint := fmt.Sprintf("/characters/%d/mail/", characterID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint
+}
+
+// esiErrorMessage extracts the error text from an ESI error body
+func esiErrorMessage(body []byte) string {
+	var esiErr struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &esiErr); err == nil && esiErr.Error != "" {
+		return esiErr.Error
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -11,8 +11,6 @@ import (
 	"strings"
 	"time"
 
-	"go-falcon/pkg/config"
-
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -44,6 +42,12 @@ type MarketStatsResult struct {
 	Cache CacheInfo             `json:"cache"`
 }
 
+// MarketPricesResult contains market prices and cache information
+type MarketPricesResult struct {
+	Data  []MarketPriceResponse `json:"data"`
+	Cache CacheInfo             `json:"cache"`
+}
+
 // MarketTypesResult contains market types and cache information
 type MarketTypesResult struct {
 	Data  []int     `json:"data"`
@@ -57,10 +61,16 @@ type Client interface {
 	GetMarketOrdersWithCache(ctx context.Context, regionID int, orderType string, page int) (*MarketOrdersResult, error)
 	GetMarketOrdersWithPagination(ctx context.Context, regionID int, orderType string, params PaginationParams) (*MarketOrdersResult, error)
 
+	GetMarketOrdersForType(ctx context.Context, regionID int, typeID int, orderType string) ([]MarketOrderResponse, error)
+
 	// Market History
 	GetMarketHistory(ctx context.Context, regionID int, typeID int) ([]MarketHistoryResponse, error)
 	GetMarketHistoryWithCache(ctx context.Context, regionID int, typeID int) (*MarketHistoryResult, error)
 
+	// Market Prices (universe-wide average and adjusted prices)
+	GetMarketPrices(ctx context.Context) ([]MarketPriceResponse, error)
+	GetMarketPricesWithCache(ctx context.Context) (*MarketPricesResult, error)
+
 	// Market Statistics
 	GetMarketStats(ctx context.Context, regionID int) ([]MarketStatsResponse, error)
 	GetMarketStatsWithCache(ctx context.Context, regionID int) (*MarketStatsResult, error)
@@ -99,6 +109,13 @@ type MarketHistoryResponse struct {
 	Lowest     float64 `json:"lowest"`
 }
 
+// MarketPriceResponse represents a type's average and adjusted price from ESI
+type MarketPriceResponse struct {
+	TypeID        int     `json:"type_id"`
+	AveragePrice  float64 `json:"average_price,omitempty"`
+	AdjustedPrice float64 `json:"adjusted_price,omitempty"`
+}
+
 // MarketStatsResponse represents market statistics from ESI
 type MarketStatsResponse struct {
 	TypeID           int     `json:"type_id"`
@@ -152,24 +169,34 @@ type MarketClient struct {
 	baseURL      string
 	userAgent    string
 	cacheManager CacheManager
+	retryClient  RetryClient
 }
 
-// CacheManager interface - kept for interface compatibility but not used for market data
+// CacheManager interface for caching operations. Order books are never cached (too large and
+// short-lived); prices and history are small and cached per ESI's expiry headers.
 type CacheManager interface {
 	Get(key string) ([]byte, bool, error)
 	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
 	GetForNotModified(key string) ([]byte, bool, error)
+	GetMetadata(key string) (map[string]interface{}, error)
 	Set(key string, data []byte, headers http.Header) error
 	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
 }
 
 // NewMarketClient creates a new market ESI client
-func NewMarketClient(httpClient *http.Client, cacheManager CacheManager) Client {
+func NewMarketClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
 	return &MarketClient{
 		httpClient:   httpClient,
-		baseURL:      "https://esi.evetech.net",
-		userAgent:    config.GetEnv("ESI_USER_AGENT", "go-falcon/1.0.0 contact@example.com"),
+		baseURL:      baseURL,
+		userAgent:    userAgent,
 		cacheManager: cacheManager,
+		retryClient:  retryClient,
 	}
 }
 
@@ -242,7 +269,7 @@ func (c *MarketClient) GetMarketOrdersWithPagination(ctx context.Context, region
 	req.Header.Set("Accept", "application/json")
 
 	// Make request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -326,7 +353,130 @@ func (c *MarketClient) GetMarketHistoryWithCache(ctx context.Context, regionID i
 	// Build request URL
 	url := fmt.Sprintf("%s/v1/markets/%d/history/?type_id=%d", c.baseURL, regionID, typeID)
 
-	return c.fetchMarketHistory(ctx, url, span)
+	body, cached, expiresAt, err := c.getCached(ctx, url, span)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []MarketHistoryResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("data_count", len(data)),
+		attribute.Bool("cache.hit", cached),
+	)
+
+	return &MarketHistoryResult{
+		Data: data,
+		Cache: CacheInfo{
+			Cached:    cached,
+			ExpiresAt: expiresAt,
+		},
+	}, nil
+}
+
+// GetMarketOrdersForType fetches every page of a region's orders for a single type
+func (c *MarketClient) GetMarketOrdersForType(ctx context.Context, regionID int, typeID int, orderType string) ([]MarketOrderResponse, error) {
+	tracer := otel.Tracer("evegateway.market")
+	ctx, span := tracer.Start(ctx, "GetMarketOrdersForType",
+		trace.WithAttributes(
+			attribute.Int("region_id", regionID),
+			attribute.Int("type_id", typeID),
+			attribute.String("order_type", orderType),
+		),
+	)
+	defer span.End()
+
+	if orderType == "" {
+		orderType = "all"
+	}
+
+	var orders []MarketOrderResponse
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/v1/markets/%d/orders/?order_type=%s&type_id=%d&page=%d", c.baseURL, regionID, orderType, typeID, page)
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("User-Agent", c.userAgent)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, fmt.Errorf("HTTP request failed: %w", err)
+		}
+
+		var pageOrders []MarketOrderResponse
+		err = c.decodeResponse(resp, &pageOrders)
+		pages := c.parsePaginationHeaders(resp.Header).TotalPages
+		resp.Body.Close()
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		orders = append(orders, pageOrders...)
+		if page >= pages {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int("orders_count", len(orders)))
+
+	return orders, nil
+}
+
+// GetMarketPrices fetches average and adjusted prices for all types
+func (c *MarketClient) GetMarketPrices(ctx context.Context) ([]MarketPriceResponse, error) {
+	result, err := c.GetMarketPricesWithCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetMarketPricesWithCache fetches market prices with cache information
+func (c *MarketClient) GetMarketPricesWithCache(ctx context.Context) (*MarketPricesResult, error) {
+	tracer := otel.Tracer("evegateway.market")
+	ctx, span := tracer.Start(ctx, "GetMarketPricesWithCache")
+	defer span.End()
+
+	url := fmt.Sprintf("%s/v1/markets/prices/", c.baseURL)
+
+	body, cached, expiresAt, err := c.getCached(ctx, url, span)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []MarketPriceResponse
+	if err := json.Unmarshal(body, &prices); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("prices_count", len(prices)),
+		attribute.Bool("cache.hit", cached),
+	)
+
+	return &MarketPricesResult{
+		Data: prices,
+		Cache: CacheInfo{
+			Cached:    cached,
+			ExpiresAt: expiresAt,
+		},
+	}, nil
 }
 
 // GetMarketStats fetches market statistics for a region
@@ -389,7 +539,7 @@ func (c *MarketClient) GetMarketTypesWithCache(ctx context.Context, regionID int
 	req.Header.Set("Accept", "application/json")
 
 	// Make request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -471,7 +621,7 @@ func (c *MarketClient) GetStructureOrdersWithCache(ctx context.Context, structur
 	// Skip caching for market data - it's too large and changes frequently
 
 	// Make request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -520,61 +670,85 @@ func (c *MarketClient) GetStructureOrdersWithCache(ctx context.Context, structur
 	}, nil
 }
 
-// fetchMarketHistory is a helper for fetching market history data
-func (c *MarketClient) fetchMarketHistory(ctx context.Context, url string, span trace.Span) (*MarketHistoryResult, error) {
-	// Skip caching for market data - it's too large and changes frequently
+// getCached fetches a public endpoint through the cache manager, revalidating with ETag and
+// falling back to the cached body on 304 Not Modified
+func (c *MarketClient) getCached(ctx context.Context, url string, span trace.Span) ([]byte, bool, *time.Time, error) {
+	cacheKey := url
+
+	if cachedData, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return cachedData, true, expiry, nil
+	}
 
-	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, false, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Accept", "application/json")
 
-	// Make request
-	resp, err := c.httpClient.Do(req)
+	// Add conditional headers if we have cached data
+	c.cacheManager.SetConditionalHeaders(req, cacheKey)
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		return nil, false, nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey)
+		if err != nil || !found {
+			err = fmt.Errorf("ESI returned 304 Not Modified but no cached data is available for %s", url)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, false, nil, err
+		}
+
+		// ESI confirmed the data is still valid
+		c.cacheManager.RefreshExpiry(cacheKey, resp.Header)
+		expiresAt := c.parseExpiresHeader(resp.Header)
+		return cachedData, true, &expiresAt, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		err = fmt.Errorf("ESI API returned status %d", resp.StatusCode)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return nil, err
+		return nil, false, nil, err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, false, nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var data []MarketHistoryResponse
-	if err := json.Unmarshal(body, &data); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	c.cacheManager.Set(cacheKey, body, resp.Header)
+	expiresAt := c.parseExpiresHeader(resp.Header)
+
+	return body, false, &expiresAt, nil
+}
+
+// decodeResponse checks the status of an uncached response and decodes its JSON body into v
+func (c *MarketClient) decodeResponse(resp *http.Response, v any) error {
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ESI API returned status %d", resp.StatusCode)
 	}
 
-	span.SetAttributes(
-		attribute.Int("data_count", len(data)),
-	)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
 
-	return &MarketHistoryResult{
-		Data: data,
-		Cache: CacheInfo{
-			Cached: false,
-		},
-	}, nil
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	return nil
 }
 
 // fetchMarketStats is a helper for fetching market statistics data
@@ -593,7 +767,7 @@ func (c *MarketClient) fetchMarketStats(ctx context.Context, url string, span tr
 	req.Header.Set("Accept", "application/json")
 
 	// Make request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -0,0 +1,209 @@
+package evegateway
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Cache lookup results
+const (
+	cacheHit         = "hit"
+	cacheMiss        = "miss"
+	cacheRevalidated = "revalidated"
+)
+
+// Metrics holds the Prometheus collectors of the ESI client. Endpoints are labelled with their
+// route template (IDs replaced by {id}, version prefix dropped), e.g. /characters/{id}/assets/.
+type Metrics struct {
+	requests   *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	retries    *prometheus.CounterVec
+	cache      *prometheus.CounterVec
+	cacheTiers *prometheus.CounterVec
+	evictions  prometheus.Counter
+	errorLimit prometheus.Gauge
+	usage      *usageTracker
+}
+
+// NewMetrics creates the ESI client collectors; they are exposed once registered
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "esi_requests_total",
+			Help: "ESI requests sent, by endpoint, method and response status (\"error\" for network errors).",
+		}, []string{"endpoint", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "esi_request_duration_seconds",
+			Help:    "Latency of individual ESI request attempts.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"endpoint", "method"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "esi_retries_total",
+			Help: "ESI request attempts that were retried, by endpoint and the status (or \"error\") that caused the retry.",
+		}, []string{"endpoint", "reason"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "esi_cache_lookups_total",
+			Help: "ESI response cache lookups by endpoint and result (hit, miss, revalidated by a 304).",
+		}, []string{"endpoint", "result"}),
+		cacheTiers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "esi_cache_tier_lookups_total",
+			Help: "ESI response cache lookups by tier (memory, redis) and result (hit, miss). Redis is only asked on a memory miss.",
+		}, []string{"tier", "result"}),
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "esi_cache_memory_evictions_total",
+			Help: "Entries evicted from the in-memory ESI cache tier to stay within ESI_MEMORY_CACHE_SIZE.",
+		}),
+		errorLimit: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "esi_error_limit_remaining",
+			Help: "Errors left in the current ESI error limit window (X-ESI-Error-Limit-Remain).",
+		}),
+		usage: newUsageTracker(),
+	}
+}
+
+// Register adds the collectors to a Prometheus registry
+func (m *Metrics) Register(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{m.requests, m.duration, m.retries, m.cache, m.cacheTiers, m.evictions, m.errorLimit} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Metrics) observeRequest(req *http.Request, status string, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	endpoint := endpointLabel(req.URL.Path)
+	m.requests.WithLabelValues(endpoint, req.Method, status).Inc()
+	m.duration.WithLabelValues(endpoint, req.Method).Observe(elapsed.Seconds())
+	m.usage.observeRequest(req.Context(), endpoint, status)
+}
+
+func (m *Metrics) observeRetry(req *http.Request, reason string) {
+	if m == nil {
+		return
+	}
+	m.retries.WithLabelValues(endpointLabel(req.URL.Path), reason).Inc()
+}
+
+func (m *Metrics) observeCache(key, result string) {
+	if m == nil {
+		return
+	}
+	endpoint := cacheKeyEndpoint(key)
+	m.cache.WithLabelValues(endpoint, result).Inc()
+	if result != cacheRevalidated {
+		m.usage.observeCache(endpoint, result == cacheHit)
+	}
+}
+
+func (m *Metrics) observeCacheTier(tier string, hit bool) {
+	if m == nil {
+		return
+	}
+	result := cacheMiss
+	if hit {
+		result = cacheHit
+	}
+	m.cacheTiers.WithLabelValues(tier, result).Inc()
+}
+
+func (m *Metrics) observeMemoryEviction() {
+	if m == nil {
+		return
+	}
+	m.evictions.Inc()
+}
+
+func (m *Metrics) setErrorLimitRemaining(remain int) {
+	if m == nil {
+		return
+	}
+	m.errorLimit.Set(float64(remain))
+}
+
+// statusLabel returns the status label of a response, or "error" when the request failed
+func statusLabel(resp *http.Response, err error) string {
+	if err != nil || resp == nil {
+		return "error"
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
+
+// endpointLabel turns a request path into its route template, keeping label cardinality bounded.
+// Anything after the last slash (sub-clients append tokens to some cache keys) is dropped.
+func endpointLabel(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[:i+1]
+	}
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return "/"
+	}
+
+	segments := strings.Split(trimmed, "/")
+	if len(segments) > 1 && isVersionSegment(segments[0]) {
+		segments = segments[1:]
+	}
+	for i, segment := range segments {
+		if segment != "" && strings.Trim(segment, "0123456789") == "" {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/") + "/"
+}
+
+// cacheKeyEndpoint returns the endpoint of a cache key; sub-clients key their cache by request URL
+func cacheKeyEndpoint(key string) string {
+	parsed, err := url.Parse(key)
+	if err != nil || parsed.Host == "" {
+		return "other"
+	}
+	return endpointLabel(parsed.Path)
+}
+
+// instrumentedCacheManager counts cache hits and misses of the sub-clients' lookups
+type instrumentedCacheManager struct {
+	CacheManager
+	metrics *Metrics
+}
+
+func (c *instrumentedCacheManager) Get(key string) ([]byte, bool, error) {
+	data, found, err := c.CacheManager.Get(key)
+	if err == nil {
+		c.observe(key, found)
+	}
+	return data, found, err
+}
+
+func (c *instrumentedCacheManager) GetWithExpiry(key string) ([]byte, bool, *time.Time, error) {
+	data, found, expiry, err := c.CacheManager.GetWithExpiry(key)
+	if err == nil {
+		c.observe(key, found)
+	}
+	return data, found, expiry, err
+}
+
+// GetForNotModified is only called after ESI answered 304, so a found entry was revalidated
+func (c *instrumentedCacheManager) GetForNotModified(key string) ([]byte, bool, error) {
+	data, found, err := c.CacheManager.GetForNotModified(key)
+	if err == nil && found {
+		c.metrics.observeCache(key, cacheRevalidated)
+	}
+	return data, found, err
+}
+
+func (c *instrumentedCacheManager) observe(key string, found bool) {
+	if found {
+		c.metrics.observeCache(key, cacheHit)
+	} else {
+		c.metrics.observeCache(key, cacheMiss)
+	}
+}
@@ -0,0 +1,138 @@
+// Package paging fetches the complete contents of paginated ESI list endpoints, which report
+// their page count in the X-Pages header and serve further pages through the page query parameter.
+package paging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultConcurrency is how many pages are requested at once. It keeps large lists such as
+// corporation assets fast without bursting enough requests to trip ESI's error limit when
+// something goes wrong.
+const DefaultConcurrency = 4
+
+// PageFetcher fetches one page (numbered from 1) of a list endpoint and returns the raw JSON
+// array and the response headers
+type PageFetcher func(ctx context.Context, page int) ([]byte, http.Header, error)
+
+// TotalPages returns the page count announced in the X-Pages header, or 1 when it is absent
+func TotalPages(headers http.Header) int {
+	if pagesHeader := headers.Get("X-Pages"); pagesHeader != "" {
+		if pages, err := strconv.Atoi(pagesHeader); err == nil && pages > 0 {
+			return pages
+		}
+	}
+	return 1
+}
+
+// PageURL adds the page parameter to a URL. The first page is requested without it, as ESI
+// serves it by default.
+func PageURL(url string, page int) string {
+	if page <= 1 {
+		return url
+	}
+	if strings.Contains(url, "?") {
+		return fmt.Sprintf("%s&page=%d", url, page)
+	}
+	return fmt.Sprintf("%s?page=%d", url, page)
+}
+
+// FetchAllPages fetches the first page, then the remaining pages announced by its X-Pages header,
+// and returns all items in page order together with the first page's headers, which carry the
+// list's cache expiry.
+func FetchAllPages[T any](ctx context.Context, fetch PageFetcher, concurrency int) ([]T, http.Header, error) {
+	body, headers, err := fetch(ctx, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var items []T
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse page 1: %w", err)
+	}
+
+	rest, err := FetchRemainingPages[T](ctx, TotalPages(headers), fetch, concurrency)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(items, rest...), headers, nil
+}
+
+// FetchRemainingPages fetches pages 2 through totalPages for callers that fetched the first page
+// themselves, e.g. to revalidate it against the cache. At most concurrency pages are requested at
+// once; the first failure cancels the outstanding requests and is returned. Items are returned in
+// page order.
+func FetchRemainingPages[T any](ctx context.Context, totalPages int, fetch PageFetcher, concurrency int) ([]T, error) {
+	if totalPages <= 1 {
+		return nil, nil
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([][]T, totalPages+1)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	slots := make(chan struct{}, concurrency)
+	for page := 2; page <= totalPages; page++ {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(page int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			body, _, err := fetch(ctx, page)
+			if err != nil {
+				fail(err)
+				return
+			}
+
+			var items []T
+			if err := json.Unmarshal(body, &items); err != nil {
+				fail(fmt.Errorf("failed to parse page %d: %w", page, err))
+				return
+			}
+			pages[page] = items
+		}(page)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var items []T
+	for _, pageItems := range pages[2:] {
+		items = append(items, pageItems...)
+	}
+	return items, nil
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/deadline"
 )
 
 // AuthContextKey key for storing user info in context (matches pkg/middleware)
@@ -32,14 +33,29 @@ type DefaultRetryClient struct {
 	httpClient  *http.Client
 	errorLimits *ESIErrorLimits
 	limitsMutex *sync.RWMutex
+	breakers    *CircuitBreakers
+	metrics     *Metrics
+	// callBudget caps the time one call spends on attempts and backoff; 0 leaves only the
+	// context's deadline
+	callBudget time.Duration
 }
 
-// NewDefaultRetryClient creates a new default retry client
-func NewDefaultRetryClient(httpClient *http.Client, errorLimits *ESIErrorLimits, limitsMutex *sync.RWMutex) *DefaultRetryClient {
+// minAttemptBudget is the least time worth starting another attempt with
+const minAttemptBudget = time.Second
+
+// ErrBudgetExhausted is returned when a retry would not finish within the call budget or the
+// request's deadline; it wraps context.DeadlineExceeded
+var ErrBudgetExhausted = fmt.Errorf("ESI call budget exhausted: %w", context.DeadlineExceeded)
+
+// NewDefaultRetryClient creates a new default retry client. Requests are guarded by the given
+// circuit breakers and recorded in metrics; nil disables either.
+func NewDefaultRetryClient(httpClient *http.Client, errorLimits *ESIErrorLimits, limitsMutex *sync.RWMutex, breakers *CircuitBreakers, metrics *Metrics) *DefaultRetryClient {
 	return &DefaultRetryClient{
 		httpClient:  httpClient,
 		errorLimits: errorLimits,
 		limitsMutex: limitsMutex,
+		breakers:    breakers,
+		metrics:     metrics,
 	}
 }
 
@@ -48,21 +64,62 @@ func (r *DefaultRetryClient) DoWithRetry(ctx context.Context, req *http.Request,
 	var resp *http.Response
 	var err error
 
+	family := endpointFamily(req)
+	started := time.Now()
+
+	// A request body can only be resent if it can be rewound; without GetBody send it once
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	if hasBody && req.GetBody == nil {
+		maxRetries = 0
+	}
+
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		// Clone request for retry attempts
+		// While the family's breaker is open, answer from cache where the caller has a copy
+		if !r.breakers.Allow(family) {
+			retryAfter := r.breakers.RetryAfter(family)
+			if stale := staleResponse(req, retryAfter); stale != nil {
+				return stale, nil
+			}
+			return nil, fmt.Errorf("%w: %s endpoints are failing, retry in %s", ErrESIUnavailable, family, retryAfter.Round(time.Second))
+		}
+
+		// Clone request for retry attempts. The clone shares the body, which the previous attempt
+		// consumed, so retries send a fresh copy.
 		reqClone := req.Clone(ctx)
+		if attempt > 0 && hasBody {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				r.breakers.Release(family)
+				return nil, fmt.Errorf("failed to rewind request body: %w", bodyErr)
+			}
+			reqClone.Body = body
+		}
 
+		sent := time.Now()
 		resp, err = r.httpClient.Do(reqClone)
+		r.metrics.observeRequest(reqClone, statusLabel(resp, err), time.Since(sent))
 		if err != nil {
+			if ctx.Err() != nil {
+				r.breakers.Release(family)
+				deadline.Observe(ctx, deadline.LayerESI, ctx.Err())
+				return nil, ctx.Err()
+			}
+			r.breakers.RecordFailure(family)
+
 			if attempt == maxRetries {
 				return nil, fmt.Errorf("request failed after %d attempts: %w", maxRetries+1, err)
 			}
+			r.metrics.observeRetry(req, "error")
 
 			// Wait before retry for network errors
 			backoffDuration := time.Duration(1<<uint(attempt)) * time.Second
 			if backoffDuration > 10*time.Second {
 				backoffDuration = 10 * time.Second
 			}
+			if !r.fitsBudget(ctx, started, backoffDuration) {
+				deadline.ObserveExceeded(ctx, deadline.LayerESI)
+				return nil, fmt.Errorf("%w after %d attempts: %v", ErrBudgetExhausted, attempt+1, err)
+			}
 
 			select {
 			case <-ctx.Done():
@@ -79,6 +136,13 @@ func (r *DefaultRetryClient) DoWithRetry(ctx context.Context, req *http.Request,
 			r.updateErrorLimitsWithContext(resp.Header, ctx, req)
 		}
 
+		// Only server errors count towards the breaker; rate limiting means ESI is answering
+		if resp.StatusCode >= 500 {
+			r.breakers.RecordFailure(family)
+		} else {
+			r.breakers.RecordSuccess(family)
+		}
+
 		// Check if we need to retry based on status code
 		if resp.StatusCode >= 500 || resp.StatusCode == 420 || resp.StatusCode == 429 {
 			resp.Body.Close() // Close body before retry
@@ -86,9 +150,10 @@ func (r *DefaultRetryClient) DoWithRetry(ctx context.Context, req *http.Request,
 			if attempt == maxRetries {
 				return nil, fmt.Errorf("request failed with status %d after %d attempts", resp.StatusCode, maxRetries+1)
 			}
+			r.metrics.observeRetry(req, strconv.Itoa(resp.StatusCode))
 
 			// Apply backoff for error status codes
-			if err := r.backoffForError(ctx, resp.StatusCode, attempt); err != nil {
+			if err := r.backoffForError(ctx, started, resp.StatusCode, attempt); err != nil {
 				return nil, err
 			}
 			continue
@@ -167,8 +232,18 @@ func (r *DefaultRetryClient) updateErrorLimitsWithContext(headers http.Header, c
 	}
 }
 
-// backoffForError implements exponential backoff based on HTTP status codes
-func (r *DefaultRetryClient) backoffForError(ctx context.Context, statusCode int, attempt int) error {
+// fitsBudget reports whether another attempt after waiting d still fits in the call budget and
+// the request's deadline
+func (r *DefaultRetryClient) fitsBudget(ctx context.Context, started time.Time, d time.Duration) bool {
+	if r.callBudget > 0 && time.Since(started)+d+minAttemptBudget > r.callBudget {
+		return false
+	}
+	return deadline.Allows(ctx, d+minAttemptBudget)
+}
+
+// backoffForError implements exponential backoff based on HTTP status codes. It gives up at once
+// when the retry would not fit in the call's budget.
+func (r *DefaultRetryClient) backoffForError(ctx context.Context, started time.Time, statusCode int, attempt int) error {
 	var backoffDuration time.Duration
 
 	switch {
@@ -194,6 +269,11 @@ func (r *DefaultRetryClient) backoffForError(ctx context.Context, statusCode int
 		return nil // No backoff needed
 	}
 
+	if !r.fitsBudget(ctx, started, backoffDuration) {
+		deadline.ObserveExceeded(ctx, deadline.LayerESI)
+		return fmt.Errorf("%w: status %d needs a %s backoff", ErrBudgetExhausted, statusCode, backoffDuration)
+	}
+
 	slog.WarnContext(ctx, "ESI error requires backoff",
 		"status_code", statusCode,
 		"attempt", attempt,
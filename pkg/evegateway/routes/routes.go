@@ -0,0 +1,325 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Route preference flags accepted by ESI
+const (
+	FlagShortest = "shortest"
+	FlagSecure   = "secure"
+	FlagInsecure = "insecure"
+)
+
+// ESI limits on the avoidance and connection lists
+const (
+	maxAvoid       = 100
+	maxConnections = 100
+)
+
+// ErrNoRoute is returned when ESI finds no route between the two systems, e.g. because a
+// wormhole system is involved or every path crosses an avoided system
+var ErrNoRoute = errors.New("no route found")
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// RouteResult contains a route and cache information
+type RouteResult struct {
+	Data  []int32   `json:"data"`
+	Cache CacheInfo `json:"cache"`
+}
+
+// Connection is an extra one-way jump from one system to another, such as a known wormhole
+// or jump bridge, that the planner may use
+type Connection struct {
+	From int32 `json:"from"`
+	To   int32 `json:"to"`
+}
+
+// RouteOptions tunes route planning
+type RouteOptions struct {
+	// Avoid lists systems the route must not pass through (at most 100)
+	Avoid []int32
+	// Connections lists extra jumps the route may take (at most 100)
+	Connections []Connection
+}
+
+// Client interface for route planning ESI operations
+type Client interface {
+	GetRoute(ctx context.Context, origin, destination int32, flag string, options *RouteOptions) ([]int32, error)
+	GetRouteWithCache(ctx context.Context, origin, destination int32, flag string, options *RouteOptions) (*RouteResult, error)
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewRoutesClient creates a new route planning client
+func NewRoutesClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetRoute retrieves the solar system IDs of a route from origin to destination, both included.
+// An empty flag plans the shortest route.
+func (c *ClientImpl) GetRoute(ctx context.Context, origin, destination int32, flag string, options *RouteOptions) ([]int32, error) {
+	result, err := c.GetRouteWithCache(ctx, origin, destination, flag, options)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetRouteWithCache retrieves a route with cache info
+func (c *ClientImpl) GetRouteWithCache(ctx context.Context, origin, destination int32, flag string, options *RouteOptions) (*RouteResult, error) {
+	endpoint, err := routeEndpoint(origin, destination, flag, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var route []int32
+	cached, err := c.get(ctx, endpoint, &route)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RouteResult{
+		Data:  route,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)},
+	}, nil
+}
+
+// routeEndpoint builds the route URL path. Avoided systems and connections are sorted and
+// deduplicated so that equivalent requests share a cache entry.
+func routeEndpoint(origin, destination int32, flag string, options *RouteOptions) (string, error) {
+	if flag == "" {
+		flag = FlagShortest
+	}
+	if flag != FlagShortest && flag != FlagSecure && flag != FlagInsecure {
+		return "", fmt.Errorf("invalid route flag %q", flag)
+	}
+
+	query := url.Values{}
+	query.Set("flag", flag)
+
+	if options != nil {
+		if len(options.Avoid) > 0 {
+			avoid := make([]int32, 0, len(options.Avoid))
+			seen := make(map[int32]bool, len(options.Avoid))
+			for _, systemID := range options.Avoid {
+				if !seen[systemID] {
+					seen[systemID] = true
+					avoid = append(avoid, systemID)
+				}
+			}
+			if len(avoid) > maxAvoid {
+				return "", fmt.Errorf("at most %d avoided systems are allowed", maxAvoid)
+			}
+			sort.Slice(avoid, func(i, j int) bool { return avoid[i] < avoid[j] })
+
+			ids := make([]string, len(avoid))
+			for i, systemID := range avoid {
+				ids[i] = strconv.FormatInt(int64(systemID), 10)
+			}
+			query.Set("avoid", strings.Join(ids, ","))
+		}
+
+		if len(options.Connections) > 0 {
+			connections := make([]Connection, 0, len(options.Connections))
+			seen := make(map[Connection]bool, len(options.Connections))
+			for _, connection := range options.Connections {
+				if !seen[connection] {
+					seen[connection] = true
+					connections = append(connections, connection)
+				}
+			}
+			if len(connections) > maxConnections {
+				return "", fmt.Errorf("at most %d connections are allowed", maxConnections)
+			}
+			sort.Slice(connections, func(i, j int) bool {
+				if connections[i].From != connections[j].From {
+					return connections[i].From < connections[j].From
+				}
+				return connections[i].To < connections[j].To
+			})
+
+			pairs := make([]string, len(connections))
+			for i, connection := range connections {
+				pairs[i] = fmt.Sprintf("%d|%d", connection.From, connection.To)
+			}
+			query.Set("connections", strings.Join(pairs, ","))
+		}
+	}
+
+	return fmt.Sprintf("/route/%d/%d/?%s", origin, destination, query.Encode()), nil
+}
+
+// get fetches an endpoint into v, serving it from cache while fresh and revalidating it with its
+// ETag once expired
+func (c *ClientImpl) get(ctx context.Context, endpoint string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs an unauthenticated GET. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/routes")
+		ctx, span = tracer.Start(ctx, "routes.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI route endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, ErrNoRoute
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI route endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package sovereignty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	mapEndpoint        = "/sovereignty/map/"
+	structuresEndpoint = "/sovereignty/structures/"
+	campaignsEndpoint  = "/sovereignty/campaigns/"
+
+	// Campaign scores and start times move quickly during an entosis fight, and vulnerability
+	// windows shift after every ADM recalculation, so neither is kept longer than this even if
+	// ESI reports a later expiry
+	campaignsMaxTTL  = 30 * time.Second
+	structuresMaxTTL = 2 * time.Minute
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// MapResult contains the sovereignty map and cache information
+type MapResult struct {
+	Data  []SystemSovereignty `json:"data"`
+	Cache CacheInfo           `json:"cache"`
+}
+
+// StructuresResult contains sovereignty structures and cache information
+type StructuresResult struct {
+	Data  []Structure `json:"data"`
+	Cache CacheInfo   `json:"cache"`
+}
+
+// CampaignsResult contains active sovereignty campaigns and cache information
+type CampaignsResult struct {
+	Data  []Campaign `json:"data"`
+	Cache CacheInfo  `json:"cache"`
+}
+
+// Client interface for sovereignty ESI operations
+type Client interface {
+	GetSovereigntyMap(ctx context.Context) ([]SystemSovereignty, error)
+	GetSovereigntyMapWithCache(ctx context.Context) (*MapResult, error)
+	GetSovereigntyStructures(ctx context.Context) ([]Structure, error)
+	GetSovereigntyStructuresWithCache(ctx context.Context) (*StructuresResult, error)
+	GetSovereigntyCampaigns(ctx context.Context) ([]Campaign, error)
+	GetSovereigntyCampaignsWithCache(ctx context.Context) (*CampaignsResult, error)
+}
+
+// SystemSovereignty is the holder of a solar system; unclaimed systems carry no IDs
+type SystemSovereignty struct {
+	SystemID      int32  `json:"system_id"`
+	AllianceID    *int32 `json:"alliance_id,omitempty"`
+	CorporationID *int32 `json:"corporation_id,omitempty"`
+	FactionID     *int32 `json:"faction_id,omitempty"`
+}
+
+// Structure is a sovereignty hub or TCU with its vulnerability window
+type Structure struct {
+	AllianceID                  int32      `json:"alliance_id"`
+	SolarSystemID               int32      `json:"solar_system_id"`
+	StructureID                 int64      `json:"structure_id"`
+	StructureTypeID             int32      `json:"structure_type_id"`
+	VulnerabilityOccupancyLevel *float64   `json:"vulnerability_occupancy_level,omitempty"`
+	VulnerableStartTime         *time.Time `json:"vulnerable_start_time,omitempty"`
+	VulnerableEndTime           *time.Time `json:"vulnerable_end_time,omitempty"`
+}
+
+// Campaign is an active sovereignty fight over a structure. EventType is one of tcu_defense,
+// ihub_defense, station_defense or station_freeport. Scores are 0-1; freeport events list
+// Participants instead of a defender and attacker score.
+type Campaign struct {
+	CampaignID      int32         `json:"campaign_id"`
+	ConstellationID int32         `json:"constellation_id"`
+	SolarSystemID   int32         `json:"solar_system_id"`
+	StructureID     int64         `json:"structure_id"`
+	EventType       string        `json:"event_type"`
+	StartTime       time.Time     `json:"start_time"`
+	DefenderID      *int32        `json:"defender_id,omitempty"`
+	DefenderScore   *float64      `json:"defender_score,omitempty"`
+	AttackersScore  *float64      `json:"attackers_score,omitempty"`
+	Participants    []Participant `json:"participants,omitempty"`
+}
+
+// Participant is an alliance's score in a freeport campaign
+type Participant struct {
+	AllianceID int32   `json:"alliance_id"`
+	Score      float64 `json:"score"`
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewSovereigntyClient creates a new sovereignty client
+func NewSovereigntyClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetSovereigntyMap retrieves the holder of every solar system (public)
+func (c *ClientImpl) GetSovereigntyMap(ctx context.Context) ([]SystemSovereignty, error) {
+	result, err := c.GetSovereigntyMapWithCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetSovereigntyMapWithCache retrieves the sovereignty map with cache info
+func (c *ClientImpl) GetSovereigntyMapWithCache(ctx context.Context) (*MapResult, error) {
+	var systems []SystemSovereignty
+	cached, err := c.get(ctx, mapEndpoint, 0, &systems)
+	if err != nil {
+		return nil, err
+	}
+	return &MapResult{Data: systems, Cache: c.cacheInfo(mapEndpoint, cached)}, nil
+}
+
+// GetSovereigntyStructures retrieves sovereignty structures and their vulnerability windows (public)
+func (c *ClientImpl) GetSovereigntyStructures(ctx context.Context) ([]Structure, error) {
+	result, err := c.GetSovereigntyStructuresWithCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetSovereigntyStructuresWithCache retrieves sovereignty structures with cache info
+func (c *ClientImpl) GetSovereigntyStructuresWithCache(ctx context.Context) (*StructuresResult, error) {
+	var structures []Structure
+	cached, err := c.get(ctx, structuresEndpoint, structuresMaxTTL, &structures)
+	if err != nil {
+		return nil, err
+	}
+	return &StructuresResult{Data: structures, Cache: c.cacheInfo(structuresEndpoint, cached)}, nil
+}
+
+// GetSovereigntyCampaigns retrieves active sovereignty campaigns (public)
+func (c *ClientImpl) GetSovereigntyCampaigns(ctx context.Context) ([]Campaign, error) {
+	result, err := c.GetSovereigntyCampaignsWithCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetSovereigntyCampaignsWithCache retrieves active sovereignty campaigns with cache info
+func (c *ClientImpl) GetSovereigntyCampaignsWithCache(ctx context.Context) (*CampaignsResult, error) {
+	var campaigns []Campaign
+	cached, err := c.get(ctx, campaignsEndpoint, campaignsMaxTTL, &campaigns)
+	if err != nil {
+		return nil, err
+	}
+	return &CampaignsResult{Data: campaigns, Cache: c.cacheInfo(campaignsEndpoint, cached)}, nil
+}
+
+// get fetches an endpoint into v, serving it from cache while fresh and revalidating it with its
+// ETag once expired. A positive maxTTL caps how long the response is cached.
+func (c *ClientImpl) get(ctx context.Context, endpoint string, maxTTL time.Duration, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, capExpiry(headers, maxTTL))
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, capExpiry(headers, maxTTL))
+	return false, nil
+}
+
+// fetch performs an unauthenticated GET. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/sovereignty")
+		ctx, span = tracer.Start(ctx, "sovereignty.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI sovereignty endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI sovereignty endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
+
+// cacheInfo builds the cache information of an endpoint's response
+func (c *ClientImpl) cacheInfo(endpoint string, cached bool) CacheInfo {
+	return CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)}
+}
+
+// capExpiry returns headers whose expiry is at most maxTTL away, leaving them untouched when
+// maxTTL is not positive or ESI already expires the response sooner
+func capExpiry(headers http.Header, maxTTL time.Duration) http.Header {
+	if maxTTL <= 0 {
+		return headers
+	}
+
+	limit := time.Now().Add(maxTTL)
+	if expires, err := time.Parse(time.RFC1123, headers.Get("Expires")); err == nil && expires.Before(limit) {
+		return headers
+	}
+
+	capped := headers.Clone()
+	if capped == nil {
+		capped = http.Header{}
+	}
+	capped.Set("Expires", limit.UTC().Format(http.TimeFormat))
+	return capped
+}
@@ -0,0 +1,184 @@
+package evegateway
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-falcon/pkg/config"
+)
+
+// Cache tiers reported by esi_cache_tier_lookups_total
+const (
+	cacheTierMemory = "memory"
+	cacheTierRedis  = "redis"
+)
+
+// MemoryCacheConfig configures the in-process cache tier in front of Redis
+type MemoryCacheConfig struct {
+	// MaxEntries bounds the tier; the least recently used entry is evicted first. 0 disables the tier.
+	MaxEntries int
+	// TTL caps how long an entry is served from memory, so replicas do not keep serving a response
+	// another replica has already replaced in Redis
+	TTL time.Duration
+	// MaxEntryBytes keeps large responses (market order pages, assets) in Redis only
+	MaxEntryBytes int
+}
+
+// LoadMemoryCacheConfig reads the in-memory ESI cache settings from the environment
+func LoadMemoryCacheConfig() MemoryCacheConfig {
+	return MemoryCacheConfig{
+		MaxEntries:    config.GetIntEnv("ESI_MEMORY_CACHE_SIZE", 10000),
+		TTL:           config.GetDurationEnv("ESI_MEMORY_CACHE_TTL", 30*time.Second),
+		MaxEntryBytes: config.GetIntEnv("ESI_MEMORY_CACHE_MAX_ENTRY_BYTES", 256*1024),
+	}
+}
+
+// memoryEntry is a response held in the memory tier
+type memoryEntry struct {
+	key     string
+	data    []byte
+	expires time.Time // ESI expiry of the response
+	evictAt time.Time // when the memory copy is dropped: the ESI expiry or the tier TTL, whichever is first
+	element *list.Element
+}
+
+// TieredCacheManager serves hot ESI responses from a bounded in-process LRU and falls back to
+// another CacheManager (Redis) on a miss. Writes go to the backing cache and drop the memory copy,
+// so the next read picks up the stored entry with its expiry.
+type TieredCacheManager struct {
+	CacheManager
+	config  MemoryCacheConfig
+	metrics *Metrics
+
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	order   *list.List // front is the most recently used
+}
+
+// NewTieredCacheManager puts a memory tier in front of a cache manager
+func NewTieredCacheManager(backing CacheManager, cfg MemoryCacheConfig, metrics *Metrics) *TieredCacheManager {
+	return &TieredCacheManager{
+		CacheManager: backing,
+		config:       cfg,
+		metrics:      metrics,
+		entries:      make(map[string]*memoryEntry),
+		order:        list.New(),
+	}
+}
+
+// Get retrieves data from memory, then from the backing cache
+func (t *TieredCacheManager) Get(key string) ([]byte, bool, error) {
+	data, found, _, err := t.GetWithExpiry(key)
+	return data, found, err
+}
+
+// GetWithExpiry retrieves data and its expiry from memory, then from the backing cache
+func (t *TieredCacheManager) GetWithExpiry(key string) ([]byte, bool, *time.Time, error) {
+	now := time.Now()
+	if entry, ok := t.lookup(key, now); ok {
+		t.metrics.observeCacheTier(cacheTierMemory, true)
+		expires := entry.expires
+		return entry.data, true, &expires, nil
+	}
+	t.metrics.observeCacheTier(cacheTierMemory, false)
+
+	data, found, expires, err := t.CacheManager.GetWithExpiry(key)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	t.metrics.observeCacheTier(cacheTierRedis, found)
+	if found && expires != nil {
+		t.store(key, data, *expires, now)
+	}
+	return data, found, expires, nil
+}
+
+// GetForNotModified serves the memory copy when it is still held, otherwise the backing entry
+func (t *TieredCacheManager) GetForNotModified(key string) ([]byte, bool, error) {
+	if entry, ok := t.lookup(key, time.Now()); ok {
+		return entry.data, true, nil
+	}
+	return t.CacheManager.GetForNotModified(key)
+}
+
+// Set stores data in the backing cache and drops the memory copy
+func (t *TieredCacheManager) Set(key string, data []byte, headers http.Header) error {
+	err := t.CacheManager.Set(key, data, headers)
+	t.remove(key)
+	return err
+}
+
+// RefreshExpiry extends the entry in the backing cache and drops the memory copy
+func (t *TieredCacheManager) RefreshExpiry(key string, headers http.Header) error {
+	err := t.CacheManager.RefreshExpiry(key, headers)
+	t.remove(key)
+	return err
+}
+
+// lookup returns the memory copy of key, evicting it once past its ESI expiry or the tier TTL
+func (t *TieredCacheManager) lookup(key string, now time.Time) (*memoryEntry, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if now.After(entry.evictAt) {
+		t.removeLocked(entry)
+		return nil, false
+	}
+	t.order.MoveToFront(entry.element)
+	return entry, true
+}
+
+// store keeps a copy of a backing cache entry, evicting the least recently used entries over the limit
+func (t *TieredCacheManager) store(key string, data []byte, expires, now time.Time) {
+	if t.config.MaxEntryBytes > 0 && len(data) > t.config.MaxEntryBytes {
+		return
+	}
+	evictAt := now.Add(t.config.TTL)
+	if expires.Before(evictAt) {
+		evictAt = expires
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if entry, ok := t.entries[key]; ok {
+		entry.data, entry.expires, entry.evictAt = data, expires, evictAt
+		t.order.MoveToFront(entry.element)
+		return
+	}
+
+	entry := &memoryEntry{key: key, data: data, expires: expires, evictAt: evictAt}
+	entry.element = t.order.PushFront(entry)
+	t.entries[key] = entry
+
+	for len(t.entries) > t.config.MaxEntries {
+		t.removeLocked(t.order.Back().Value.(*memoryEntry))
+		t.metrics.observeMemoryEviction()
+	}
+}
+
+func (t *TieredCacheManager) remove(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if entry, ok := t.entries[key]; ok {
+		t.removeLocked(entry)
+	}
+}
+
+func (t *TieredCacheManager) removeLocked(entry *memoryEntry) {
+	t.order.Remove(entry.element)
+	delete(t.entries, entry.key)
+}
+
+// Len returns the number of entries held in memory
+func (t *TieredCacheManager) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.entries)
+}
@@ -0,0 +1,155 @@
+package evegateway
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-falcon/pkg/evegateway/esiruntime"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// TokenProvider supplies the access token of an authenticated ESI call. Providers backed by a
+// stored character token return a fresh token, refreshing it first when it has expired.
+type TokenProvider = esiruntime.TokenProvider
+
+// StaticToken is a TokenProvider for an access token the caller already holds
+type StaticToken = esiruntime.StaticToken
+
+// ErrNoToken is returned by token providers that have no token for their character
+var ErrNoToken = errors.New("no ESI access token available")
+
+// TokenRefresher exchanges a character's refresh token for a new access token; the auth module
+// implements it. staleToken is the token ESI rejected or that expired, so a refresher can return
+// a newer token another request already obtained instead of refreshing again.
+type TokenRefresher interface {
+	RefreshCharacterToken(ctx context.Context, characterID int, staleToken string) (string, error)
+}
+
+// tokenExpirySkew refreshes tokens this long before they expire, so they do not lapse in flight
+const tokenExpirySkew = 30 * time.Second
+
+// tokenRefreshTransport refreshes EVE SSO access tokens for every authenticated ESI request:
+// expired tokens are refreshed before sending, and a request answered 401 is sent once more
+// with a refreshed token. Callers then only see auth failures the refresh could not fix, such
+// as a revoked refresh token.
+type tokenRefreshTransport struct {
+	base http.RoundTripper
+
+	mu        sync.RWMutex
+	refresher TokenRefresher
+	group     singleflight.Group
+}
+
+func (t *tokenRefreshTransport) setRefresher(refresher TokenRefresher) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refresher = refresher
+}
+
+func (t *tokenRefreshTransport) getRefresher() TokenRefresher {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.refresher
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *tokenRefreshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	refresher := t.getRefresher()
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if refresher == nil || !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	characterID, expiresAt, ok := parseAccessToken(token)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	if time.Until(expiresAt) < tokenExpirySkew {
+		if fresh, err := t.refresh(req.Context(), refresher, characterID, token); err == nil {
+			token = fresh
+			req = withToken(req, token)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Retry once with a refreshed token; a body that cannot be replayed keeps the 401
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	fresh, refreshErr := t.refresh(req.Context(), refresher, characterID, token)
+	if refreshErr != nil || fresh == token {
+		return resp, nil
+	}
+	retry := withToken(req, fresh)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// refresh refreshes a character's token once for all requests that hit the stale token together
+func (t *tokenRefreshTransport) refresh(ctx context.Context, refresher TokenRefresher, characterID int, staleToken string) (string, error) {
+	fresh, err, _ := t.group.Do(strconv.Itoa(characterID), func() (interface{}, error) {
+		return refresher.RefreshCharacterToken(context.WithoutCancel(ctx), characterID, staleToken)
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to refresh EVE access token", "character_id", characterID, "error", err)
+		return "", err
+	}
+	return fresh.(string), nil
+}
+
+// withToken returns a copy of the request authenticated with another token
+func withToken(req *http.Request, token string) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", "Bearer "+token)
+	return clone
+}
+
+// parseAccessToken reads the character ID and expiry from an EVE SSO access token. The token is
+// not verified: ESI does that, and only its claims are needed to pick the token to refresh.
+func parseAccessToken(token string) (int, time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+
+	var claims struct {
+		Subject string `json:"sub"`
+		Expiry  int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return 0, time.Time{}, false
+	}
+
+	// sub has the form CHARACTER:EVE:<character_id>
+	idPart := claims.Subject[strings.LastIndex(claims.Subject, ":")+1:]
+	characterID, err := strconv.Atoi(idPart)
+	if err != nil || characterID <= 0 {
+		return 0, time.Time{}, false
+	}
+	return characterID, time.Unix(claims.Expiry, 0), true
+}
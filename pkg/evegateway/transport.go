@@ -0,0 +1,144 @@
+package evegateway
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+
+	"go-falcon/pkg/config"
+)
+
+// TransportConfig controls the HTTP connection pool shared by all ESI sub-clients
+type TransportConfig struct {
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	MaxConnsPerHost       int
+	IdleConnTimeout       time.Duration
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	RequestTimeout        time.Duration
+	TLSSessionCacheSize   int
+	// CallBudget caps one ESI call including its retries and backoff
+	CallBudget time.Duration
+}
+
+// LoadTransportConfig reads the ESI HTTP client settings from the environment.
+// The defaults keep enough idle connections to ESI that bulk imports reuse them
+// instead of opening a new TLS connection per request. They are retry-aware: one attempt
+// (ESI_HTTP_TIMEOUT) takes at most a third of the call budget, so a hung request still leaves
+// room for two retries and their backoff instead of using up the whole call.
+func LoadTransportConfig() TransportConfig {
+	cfg := TransportConfig{
+		MaxIdleConns:          config.GetIntEnv("ESI_HTTP_MAX_IDLE_CONNS", 100),
+		MaxIdleConnsPerHost:   config.GetIntEnv("ESI_HTTP_MAX_IDLE_CONNS_PER_HOST", 50),
+		MaxConnsPerHost:       config.GetIntEnv("ESI_HTTP_MAX_CONNS_PER_HOST", 100),
+		IdleConnTimeout:       config.GetDurationEnv("ESI_HTTP_IDLE_CONN_TIMEOUT", 90*time.Second),
+		DialTimeout:           config.GetDurationEnv("ESI_HTTP_DIAL_TIMEOUT", 5*time.Second),
+		KeepAlive:             config.GetDurationEnv("ESI_HTTP_KEEPALIVE", 30*time.Second),
+		TLSHandshakeTimeout:   config.GetDurationEnv("ESI_HTTP_TLS_HANDSHAKE_TIMEOUT", 5*time.Second),
+		ResponseHeaderTimeout: config.GetDurationEnv("ESI_HTTP_RESPONSE_HEADER_TIMEOUT", 10*time.Second),
+		RequestTimeout:        config.GetDurationEnv("ESI_HTTP_TIMEOUT", 15*time.Second),
+		TLSSessionCacheSize:   config.GetIntEnv("ESI_HTTP_TLS_SESSION_CACHE_SIZE", 64),
+		CallBudget:            config.GetDurationEnv("ESI_CALL_BUDGET", 45*time.Second),
+	}
+
+	if cfg.CallBudget > 0 && cfg.RequestTimeout > cfg.CallBudget/2 {
+		slog.Warn("ESI_HTTP_TIMEOUT leaves no room for a retry within ESI_CALL_BUDGET",
+			"request_timeout", cfg.RequestTimeout, "call_budget", cfg.CallBudget)
+	}
+	return cfg
+}
+
+// NewTransport builds an *http.Transport from the config
+func NewTransport(cfg TransportConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.TLSSessionCacheSize > 0 {
+		// Session resumption skips the full handshake when a pooled connection is replaced
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(cfg.TLSSessionCacheSize)
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+}
+
+// ConnectionStats reports how often requests reused pooled connections
+type ConnectionStats struct {
+	Requests          int64   `json:"requests"`
+	ReusedConnections int64   `json:"reused_connections"`
+	NewConnections    int64   `json:"new_connections"`
+	TLSResumed        int64   `json:"tls_resumed"`
+	ReuseRatio        float64 `json:"reuse_ratio"`
+}
+
+// connectionTracker is a RoundTripper that counts connection reuse via httptrace
+type connectionTracker struct {
+	next http.RoundTripper
+
+	requests   atomic.Int64
+	reused     atomic.Int64
+	created    atomic.Int64
+	tlsResumed atomic.Int64
+}
+
+func newConnectionTracker(next http.RoundTripper) *connectionTracker {
+	return &connectionTracker{next: next}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *connectionTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				t.reused.Add(1)
+			} else {
+				t.created.Add(1)
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err == nil && state.DidResume {
+				t.tlsResumed.Add(1)
+			}
+		},
+	}
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	return t.next.RoundTrip(req)
+}
+
+// Stats returns a snapshot of the connection counters
+func (t *connectionTracker) Stats() ConnectionStats {
+	stats := ConnectionStats{
+		Requests:          t.requests.Load(),
+		ReusedConnections: t.reused.Load(),
+		NewConnections:    t.created.Load(),
+		TLSResumed:        t.tlsResumed.Load(),
+	}
+	if total := stats.ReusedConnections + stats.NewConnections; total > 0 {
+		stats.ReuseRatio = float64(stats.ReusedConnections) / float64(total)
+	}
+	return stats
+}
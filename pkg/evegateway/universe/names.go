@@ -0,0 +1,249 @@
+package universe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ESI limits on the bulk resolution endpoints
+const (
+	maxIDsPerNamesRequest = 1000
+	maxNamesPerIDsRequest = 500
+)
+
+// resolutionCacheTTL is how long a resolved ID or name is kept. Names only change through rare
+// character renames, so resolutions are cached far longer than ESI's own cache headers allow.
+const resolutionCacheTTL = 24 * time.Hour
+
+// Name is an ID resolved to its name, or a name resolved to its ID. Category is one of agent,
+// alliance, character, constellation, corporation, faction, inventory_type, region,
+// solar_system or station.
+type Name struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// idsResponse is the response of POST /universe/ids/, grouped by category
+type idsResponse struct {
+	Agents         []Name `json:"agents"`
+	Alliances      []Name `json:"alliances"`
+	Characters     []Name `json:"characters"`
+	Constellations []Name `json:"constellations"`
+	Corporations   []Name `json:"corporations"`
+	Factions       []Name `json:"factions"`
+	InventoryTypes []Name `json:"inventory_types"`
+	Regions        []Name `json:"regions"`
+	Stations       []Name `json:"stations"`
+	Systems        []Name `json:"systems"`
+}
+
+// flatten returns the matches with their category set, using the category names of /universe/names/
+func (r *idsResponse) flatten() []Name {
+	groups := []struct {
+		category string
+		names    []Name
+	}{
+		{"agent", r.Agents},
+		{"alliance", r.Alliances},
+		{"character", r.Characters},
+		{"constellation", r.Constellations},
+		{"corporation", r.Corporations},
+		{"faction", r.Factions},
+		{"inventory_type", r.InventoryTypes},
+		{"region", r.Regions},
+		{"station", r.Stations},
+		{"solar_system", r.Systems},
+	}
+
+	var names []Name
+	for _, group := range groups {
+		for _, name := range group.names {
+			name.Category = group.category
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// resolutionCacheHeaders makes the cache manager keep resolution entries for resolutionCacheTTL
+func resolutionCacheHeaders() http.Header {
+	return http.Header{"Cache-Control": []string{fmt.Sprintf("max-age=%d", int(resolutionCacheTTL.Seconds()))}}
+}
+
+func nameCacheKey(id int) string {
+	return "universe:name:" + strconv.Itoa(id)
+}
+
+func idCacheKey(name string) string {
+	return "universe:id:" + strings.ToLower(name)
+}
+
+// GetNames resolves IDs of characters, corporations, alliances, types, systems and other
+// universe entities to names and categories. Any number of IDs may be passed: they are
+// deduplicated, served from the resolution cache where possible and the rest requested in chunks
+// of 1000. IDs ESI cannot resolve are left out of the result.
+func (c *UniverseClient) GetNames(ctx context.Context, ids []int) ([]Name, error) {
+	names := make([]Name, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	var missing []int
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if cachedData, found, err := c.cacheManager.Get(nameCacheKey(id)); err == nil && found {
+			var name Name
+			if err := json.Unmarshal(cachedData, &name); err == nil {
+				names = append(names, name)
+				continue
+			}
+		}
+		missing = append(missing, id)
+	}
+
+	for start := 0; start < len(missing); start += maxIDsPerNamesRequest {
+		end := min(start+maxIDsPerNamesRequest, len(missing))
+
+		resolved, err := c.postNames(ctx, missing[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range resolved {
+			if data, err := json.Marshal(name); err == nil {
+				c.cacheManager.Set(nameCacheKey(name.ID), data, resolutionCacheHeaders())
+			}
+		}
+		names = append(names, resolved...)
+	}
+
+	return names, nil
+}
+
+// postNames resolves one chunk of IDs. ESI rejects the whole chunk with 404 when any ID in it is
+// invalid, so a rejected chunk is split in halves until the invalid IDs are isolated and dropped.
+func (c *UniverseClient) postNames(ctx context.Context, ids []int) ([]Name, error) {
+	var names []Name
+	status, err := c.post(ctx, "/universe/names/", ids, &names)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusNotFound {
+		return names, nil
+	}
+
+	if len(ids) == 1 {
+		slog.DebugContext(ctx, "ESI could not resolve ID", "id", ids[0])
+		return nil, nil
+	}
+
+	half := len(ids) / 2
+	first, err := c.postNames(ctx, ids[:half])
+	if err != nil {
+		return nil, err
+	}
+	second, err := c.postNames(ctx, ids[half:])
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// GetIDs resolves exact names (case-insensitive) to IDs and categories. A name shared by entities
+// of several categories, such as a character and a corporation, yields one entry per category.
+// Names are served from the resolution cache where possible and the rest requested in chunks of
+// 500; names that match nothing are left out of the result.
+func (c *UniverseClient) GetIDs(ctx context.Context, names []string) ([]Name, error) {
+	resolved := make([]Name, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	var missing []string
+
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if cachedData, found, err := c.cacheManager.Get(idCacheKey(name)); err == nil && found {
+			var matches []Name
+			if err := json.Unmarshal(cachedData, &matches); err == nil {
+				resolved = append(resolved, matches...)
+				continue
+			}
+		}
+		missing = append(missing, name)
+	}
+
+	for start := 0; start < len(missing); start += maxNamesPerIDsRequest {
+		end := min(start+maxNamesPerIDsRequest, len(missing))
+
+		var response idsResponse
+		if _, err := c.post(ctx, "/universe/ids/", missing[start:end], &response); err != nil {
+			return nil, err
+		}
+		matches := response.flatten()
+
+		byName := make(map[string][]Name)
+		for _, match := range matches {
+			key := strings.ToLower(match.Name)
+			byName[key] = append(byName[key], match)
+		}
+		for key, nameMatches := range byName {
+			if data, err := json.Marshal(nameMatches); err == nil {
+				c.cacheManager.Set(idCacheKey(key), data, resolutionCacheHeaders())
+			}
+		}
+		resolved = append(resolved, matches...)
+	}
+
+	return resolved, nil
+}
+
+// post sends a JSON body to a public bulk endpoint and decodes a 200 response into v. A 404 is
+// returned as a status without an error so callers can handle unresolvable input.
+func (c *UniverseClient) post(ctx context.Context, endpoint string, payload, v any) (int, error) {
+	requestBody, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to call ESI universe endpoint", "endpoint", endpoint, "error", err)
+		return 0, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return resp.StatusCode, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		slog.ErrorContext(ctx, "ESI universe endpoint returned error", "endpoint", endpoint, "status_code", resp.StatusCode)
+		return resp.StatusCode, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
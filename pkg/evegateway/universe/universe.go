@@ -41,6 +41,8 @@ type Client interface {
 	GetSystemInfoWithCache(ctx context.Context, systemID int) (*SystemInfoResult, error)
 	GetStationInfo(ctx context.Context, stationID int) (*StationInfoResponse, error)
 	GetStationInfoWithCache(ctx context.Context, stationID int) (*StationInfoResult, error)
+	GetNames(ctx context.Context, ids []int) ([]Name, error)
+	GetIDs(ctx context.Context, names []string) ([]Name, error)
 }
 
 // SystemInfoResponse represents solar system information
@@ -0,0 +1,213 @@
+package evegateway
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UnattributedModule is reported for ESI calls whose context names no module, e.g. background
+// goroutines that were started without one
+const UnattributedModule = "unattributed"
+
+type moduleContextKey struct{}
+
+// WithModule records in the context which module ESI calls made with it originate from
+func WithModule(ctx context.Context, module string) context.Context {
+	return context.WithValue(ctx, moduleContextKey{}, module)
+}
+
+// ModuleFromContext returns the module set with WithModule, or UnattributedModule
+func ModuleFromContext(ctx context.Context) string {
+	if module, ok := ctx.Value(moduleContextKey{}).(string); ok && module != "" {
+		return module
+	}
+	return UnattributedModule
+}
+
+// ModuleMiddleware attributes the ESI calls of an API request to the module owning its route,
+// which is the first path segment after the API prefix (e.g. /api/character/... -> character)
+func ModuleMiddleware(apiPrefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+			module, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+			if module != "" {
+				r = r.WithContext(WithModule(r.Context(), module))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UsageReport is the ESI usage of this process since it started, by module and endpoint
+type UsageReport struct {
+	Since   time.Time      `json:"since" doc:"When counting started (process start)"`
+	Modules []ModuleUsage  `json:"modules" doc:"Requests sent to ESI by module, busiest first"`
+	Cache   []CacheUsage   `json:"cache" doc:"Response cache lookups by endpoint, busiest first"`
+	Totals  EndpointTotals `json:"totals" doc:"Requests sent to ESI across all modules"`
+}
+
+// ModuleUsage is the ESI traffic of one module
+type ModuleUsage struct {
+	Module string `json:"module" doc:"Module the calls originated from"`
+	EndpointTotals
+	Endpoints []EndpointUsage `json:"endpoints" doc:"Requests by endpoint, busiest first"`
+}
+
+// EndpointUsage is the ESI traffic of one module to one endpoint
+type EndpointUsage struct {
+	Endpoint string `json:"endpoint" doc:"Endpoint route template, e.g. /characters/{id}/assets/"`
+	EndpointTotals
+}
+
+// EndpointTotals counts requests that reached ESI. Every retry attempt is a request, and errors
+// (network failures and 4xx/5xx responses) are what ESI's error limit counts.
+type EndpointTotals struct {
+	Requests    int64   `json:"requests" doc:"Requests sent to ESI, retries included"`
+	Errors      int64   `json:"errors" doc:"Requests that failed or returned 4xx/5xx"`
+	ErrorRate   float64 `json:"error_rate" doc:"Errors per request (0-1)"`
+	NotModified int64   `json:"not_modified" doc:"Requests answered 304, i.e. cache revalidations"`
+}
+
+// CacheUsage is the response cache hit rate of one endpoint. Cache lookups carry no context, so
+// they are counted per endpoint across all modules.
+type CacheUsage struct {
+	Endpoint string  `json:"endpoint" doc:"Endpoint route template"`
+	Hits     int64   `json:"hits" doc:"Lookups served from cache"`
+	Misses   int64   `json:"misses" doc:"Lookups that had to ask ESI"`
+	HitRate  float64 `json:"hit_rate" doc:"Hits per lookup (0-1)"`
+}
+
+type usageKey struct {
+	module   string
+	endpoint string
+}
+
+type cacheCounters struct {
+	hits   int64
+	misses int64
+}
+
+// usageTracker counts ESI requests by originating module and endpoint, and cache lookups by endpoint
+type usageTracker struct {
+	mu       sync.Mutex
+	since    time.Time
+	requests map[usageKey]*EndpointTotals
+	cache    map[string]*cacheCounters
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{
+		since:    time.Now(),
+		requests: make(map[usageKey]*EndpointTotals),
+		cache:    make(map[string]*cacheCounters),
+	}
+}
+
+// observeRequest counts a request attempt; status is a statusLabel
+func (u *usageTracker) observeRequest(ctx context.Context, endpoint, status string) {
+	key := usageKey{module: ModuleFromContext(ctx), endpoint: endpoint}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	totals, ok := u.requests[key]
+	if !ok {
+		totals = &EndpointTotals{}
+		u.requests[key] = totals
+	}
+	totals.Requests++
+	code, err := strconv.Atoi(status)
+	switch {
+	case err != nil || code >= 400:
+		totals.Errors++
+	case code == http.StatusNotModified:
+		totals.NotModified++
+	}
+}
+
+func (u *usageTracker) observeCache(endpoint string, hit bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	counters, ok := u.cache[endpoint]
+	if !ok {
+		counters = &cacheCounters{}
+		u.cache[endpoint] = counters
+	}
+	if hit {
+		counters.hits++
+	} else {
+		counters.misses++
+	}
+}
+
+// report builds the usage report, busiest modules and endpoints first
+func (u *usageTracker) report() UsageReport {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	report := UsageReport{Since: u.since, Modules: []ModuleUsage{}, Cache: []CacheUsage{}}
+	modules := make(map[string]*ModuleUsage)
+	for key, totals := range u.requests {
+		module, ok := modules[key.module]
+		if !ok {
+			module = &ModuleUsage{Module: key.module}
+			modules[key.module] = module
+		}
+		module.Endpoints = append(module.Endpoints, EndpointUsage{Endpoint: key.endpoint, EndpointTotals: totals.withRate()})
+		module.add(*totals)
+		report.Totals.add(*totals)
+	}
+	report.Totals = report.Totals.withRate()
+
+	for _, module := range modules {
+		module.EndpointTotals = module.EndpointTotals.withRate()
+		sort.Slice(module.Endpoints, func(i, j int) bool {
+			if module.Endpoints[i].Requests != module.Endpoints[j].Requests {
+				return module.Endpoints[i].Requests > module.Endpoints[j].Requests
+			}
+			return module.Endpoints[i].Endpoint < module.Endpoints[j].Endpoint
+		})
+		report.Modules = append(report.Modules, *module)
+	}
+	sort.Slice(report.Modules, func(i, j int) bool {
+		if report.Modules[i].Requests != report.Modules[j].Requests {
+			return report.Modules[i].Requests > report.Modules[j].Requests
+		}
+		return report.Modules[i].Module < report.Modules[j].Module
+	})
+
+	for endpoint, counters := range u.cache {
+		usage := CacheUsage{Endpoint: endpoint, Hits: counters.hits, Misses: counters.misses}
+		if lookups := counters.hits + counters.misses; lookups > 0 {
+			usage.HitRate = float64(counters.hits) / float64(lookups)
+		}
+		report.Cache = append(report.Cache, usage)
+	}
+	sort.Slice(report.Cache, func(i, j int) bool {
+		a, b := report.Cache[i], report.Cache[j]
+		if a.Hits+a.Misses != b.Hits+b.Misses {
+			return a.Hits+a.Misses > b.Hits+b.Misses
+		}
+		return a.Endpoint < b.Endpoint
+	})
+
+	return report
+}
+
+func (t *EndpointTotals) add(other EndpointTotals) {
+	t.Requests += other.Requests
+	t.Errors += other.Errors
+	t.NotModified += other.NotModified
+}
+
+func (t EndpointTotals) withRate() EndpointTotals {
+	if t.Requests > 0 {
+		t.ErrorRate = float64(t.Errors) / float64(t.Requests)
+	}
+	return t
+}
@@ -0,0 +1,418 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/evegateway/paging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// JournalResult contains wallet journal entries and cache information
+type JournalResult struct {
+	Data  []JournalEntry `json:"data"`
+	Cache CacheInfo      `json:"cache"`
+}
+
+// TransactionsResult contains wallet transactions and cache information
+type TransactionsResult struct {
+	Data  []Transaction `json:"data"`
+	Cache CacheInfo     `json:"cache"`
+}
+
+// Client interface for wallet-related ESI operations. Corporation division balances are
+// served by the corporation client (GetCorporationWallets).
+type Client interface {
+	GetCharacterWalletBalance(ctx context.Context, characterID int, token string) (float64, error)
+	GetCharacterWalletJournal(ctx context.Context, characterID int, token string) ([]JournalEntry, error)
+	GetCharacterWalletJournalWithCache(ctx context.Context, characterID int, token string) (*JournalResult, error)
+	GetCharacterWalletTransactions(ctx context.Context, characterID int, fromID int64, token string) ([]Transaction, error)
+	GetCharacterWalletTransactionsWithCache(ctx context.Context, characterID int, fromID int64, token string) (*TransactionsResult, error)
+	GetCorporationWalletJournal(ctx context.Context, corporationID, division int, token string) ([]JournalEntry, error)
+	GetCorporationWalletJournalWithCache(ctx context.Context, corporationID, division int, token string) (*JournalResult, error)
+	GetCorporationWalletTransactions(ctx context.Context, corporationID, division int, fromID int64, token string) ([]Transaction, error)
+	GetCorporationWalletTransactionsWithCache(ctx context.Context, corporationID, division int, fromID int64, token string) (*TransactionsResult, error)
+}
+
+// JournalEntry represents a single wallet journal entry from ESI
+type JournalEntry struct {
+	ID            int64     `json:"id"`
+	Date          time.Time `json:"date"`
+	RefType       string    `json:"ref_type"`
+	Description   string    `json:"description"`
+	Amount        *float64  `json:"amount,omitempty"`
+	Balance       *float64  `json:"balance,omitempty"`
+	FirstPartyID  *int32    `json:"first_party_id,omitempty"`
+	SecondPartyID *int32    `json:"second_party_id,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	ContextID     *int64    `json:"context_id,omitempty"`
+	ContextIDType string    `json:"context_id_type,omitempty"`
+	Tax           *float64  `json:"tax,omitempty"`
+	TaxReceiverID *int32    `json:"tax_receiver_id,omitempty"`
+}
+
+// Transaction represents a market transaction from ESI. IsPersonal is only set for character wallets.
+type Transaction struct {
+	TransactionID int64     `json:"transaction_id"`
+	Date          time.Time `json:"date"`
+	TypeID        int32     `json:"type_id"`
+	LocationID    int64     `json:"location_id"`
+	Quantity      int32     `json:"quantity"`
+	UnitPrice     float64   `json:"unit_price"`
+	ClientID      int32     `json:"client_id"`
+	IsBuy         bool      `json:"is_buy"`
+	IsPersonal    bool      `json:"is_personal,omitempty"`
+	JournalRefID  int64     `json:"journal_ref_id"`
+}
+
+// Corporations have seven wallet divisions, numbered from 1
+const (
+	MinDivision = 1
+	MaxDivision = 7
+)
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewWalletClient creates a new wallet client
+func NewWalletClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetCharacterWalletBalance retrieves a character's ISK balance (requires esi-wallet.read_character_wallet.v1)
+func (c *ClientImpl) GetCharacterWalletBalance(ctx context.Context, characterID int, token string) (float64, error) {
+	url := fmt.Sprintf("%s/characters/%d/wallet/", c.baseURL, characterID)
+
+	body, _, err := c.getCached(ctx, url, token)
+	if err != nil {
+		return 0, err
+	}
+
+	var balance float64
+	if err := json.Unmarshal(body, &balance); err != nil {
+		return 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return balance, nil
+}
+
+// GetCharacterWalletJournal retrieves every page of a character's wallet journal, newest first
+// (requires esi-wallet.read_character_wallet.v1)
+func (c *ClientImpl) GetCharacterWalletJournal(ctx context.Context, characterID int, token string) ([]JournalEntry, error) {
+	entries, _, err := c.getJournal(ctx, fmt.Sprintf("/characters/%d/wallet/journal/", characterID), token)
+	return entries, err
+}
+
+// GetCharacterWalletJournalWithCache retrieves a character's wallet journal with cache info
+func (c *ClientImpl) GetCharacterWalletJournalWithCache(ctx context.Context, characterID int, token string) (*JournalResult, error) {
+	endpoint := fmt.Sprintf("/characters/%d/wallet/journal/", characterID)
+	entries, cached, err := c.getJournal(ctx, endpoint, token)
+	if err != nil {
+		return nil, err
+	}
+	return &JournalResult{
+		Data:  entries,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)},
+	}, nil
+}
+
+// GetCharacterWalletTransactions retrieves up to 2500 character market transactions older than
+// fromID, or the most recent ones when fromID is 0 (requires esi-wallet.read_character_wallet.v1)
+func (c *ClientImpl) GetCharacterWalletTransactions(ctx context.Context, characterID int, fromID int64, token string) ([]Transaction, error) {
+	transactions, _, err := c.getTransactions(ctx, transactionsURL(c.baseURL, fmt.Sprintf("/characters/%d/wallet/transactions/", characterID), fromID), token)
+	return transactions, err
+}
+
+// GetCharacterWalletTransactionsWithCache retrieves character market transactions with cache info
+func (c *ClientImpl) GetCharacterWalletTransactionsWithCache(ctx context.Context, characterID int, fromID int64, token string) (*TransactionsResult, error) {
+	url := transactionsURL(c.baseURL, fmt.Sprintf("/characters/%d/wallet/transactions/", characterID), fromID)
+	transactions, cached, err := c.getTransactions(ctx, url, token)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsResult{
+		Data:  transactions,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(url)},
+	}, nil
+}
+
+// GetCorporationWalletJournal retrieves every page of a corporation wallet division's journal
+// (requires esi-wallet.read_corporation_wallets.v1 and an accountant or junior accountant role)
+func (c *ClientImpl) GetCorporationWalletJournal(ctx context.Context, corporationID, division int, token string) ([]JournalEntry, error) {
+	if err := validateDivision(division); err != nil {
+		return nil, err
+	}
+	entries, _, err := c.getJournal(ctx, fmt.Sprintf("/corporations/%d/wallets/%d/journal/", corporationID, division), token)
+	return entries, err
+}
+
+// GetCorporationWalletJournalWithCache retrieves a corporation wallet division's journal with cache info
+func (c *ClientImpl) GetCorporationWalletJournalWithCache(ctx context.Context, corporationID, division int, token string) (*JournalResult, error) {
+	if err := validateDivision(division); err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/corporations/%d/wallets/%d/journal/", corporationID, division)
+	entries, cached, err := c.getJournal(ctx, endpoint, token)
+	if err != nil {
+		return nil, err
+	}
+	return &JournalResult{
+		Data:  entries,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)},
+	}, nil
+}
+
+// GetCorporationWalletTransactions retrieves up to 2500 market transactions of a corporation wallet
+// division older than fromID, or the most recent ones when fromID is 0
+func (c *ClientImpl) GetCorporationWalletTransactions(ctx context.Context, corporationID, division int, fromID int64, token string) ([]Transaction, error) {
+	if err := validateDivision(division); err != nil {
+		return nil, err
+	}
+	transactions, _, err := c.getTransactions(ctx, transactionsURL(c.baseURL, fmt.Sprintf("/corporations/%d/wallets/%d/transactions/", corporationID, division), fromID), token)
+	return transactions, err
+}
+
+// GetCorporationWalletTransactionsWithCache retrieves corporation wallet division transactions with cache info
+func (c *ClientImpl) GetCorporationWalletTransactionsWithCache(ctx context.Context, corporationID, division int, fromID int64, token string) (*TransactionsResult, error) {
+	if err := validateDivision(division); err != nil {
+		return nil, err
+	}
+	url := transactionsURL(c.baseURL, fmt.Sprintf("/corporations/%d/wallets/%d/transactions/", corporationID, division), fromID)
+	transactions, cached, err := c.getTransactions(ctx, url, token)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsResult{
+		Data:  transactions,
+		Cache: CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(url)},
+	}, nil
+}
+
+// getTransactions fetches a (non-paginated) transactions URL through the cache
+func (c *ClientImpl) getTransactions(ctx context.Context, url, token string) ([]Transaction, bool, error) {
+	body, cached, err := c.getCached(ctx, url, token)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var transactions []Transaction
+	if err := json.Unmarshal(body, &transactions); err != nil {
+		return nil, false, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return transactions, cached, nil
+}
+
+// getJournal returns all journal pages for endpoint. The combined list is cached under the
+// endpoint with the first page's headers; once it expires, page 1 is revalidated with its ETag.
+// The journal is newest first, so an unchanged first page means no new entries and the cached
+// list is reused without fetching the remaining pages.
+func (c *ClientImpl) getJournal(ctx context.Context, endpoint, token string) ([]JournalEntry, bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		var entries []JournalEntry
+		if err := json.Unmarshal(cachedData, &entries); err == nil {
+			return entries, true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, token, cacheKey)
+	if err != nil {
+		return nil, false, err
+	}
+	if body == nil {
+		// 304 on the first page: the cached list is still current
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			var entries []JournalEntry
+			if err := json.Unmarshal(cachedData, &entries); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return entries, true, nil
+			}
+		}
+		return nil, false, fmt.Errorf("ESI returned 304 Not Modified but no cached data is available for %s", endpoint)
+	}
+
+	var entries []JournalEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	totalPages := paging.TotalPages(headers)
+	rest, err := paging.FetchRemainingPages[JournalEntry](ctx, totalPages, func(ctx context.Context, page int) ([]byte, http.Header, error) {
+		return c.fetch(ctx, paging.PageURL(cacheKey, page), token, "")
+	}, paging.DefaultConcurrency)
+	if err != nil {
+		return nil, false, err
+	}
+	entries = append(entries, rest...)
+
+	if data, err := json.Marshal(entries); err == nil {
+		c.cacheManager.Set(cacheKey, data, headers)
+	}
+
+	slog.InfoContext(ctx, "Retrieved wallet journal from ESI", "endpoint", endpoint, "pages", totalPages, "count", len(entries))
+	return entries, false, nil
+}
+
+// getCached returns the cached body for url, or fetches it revalidating with the cached ETag
+func (c *ClientImpl) getCached(ctx context.Context, url, token string) ([]byte, bool, error) {
+	cacheKey := url
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		return cachedData, true, nil
+	}
+
+	body, headers, err := c.fetch(ctx, url, token, cacheKey)
+	if err != nil {
+		return nil, false, err
+	}
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found {
+			c.cacheManager.RefreshExpiry(cacheKey, headers)
+			return cachedData, true, nil
+		}
+		return nil, false, fmt.Errorf("ESI returned 304 Not Modified but no cached data is available for %s", url)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return body, false, nil
+}
+
+// fetch performs an authenticated GET. When conditionalKey is set, the request carries the ETag
+// cached under that key and a 304 response is reported as a nil body with the response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, token, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/wallet")
+		ctx, span = tracer.Start(ctx, "wallet.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI wallet endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI wallet endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
+
+// transactionsURL builds a transactions URL, adding from_id to walk back through older transactions
+func transactionsURL(baseURL, endpoint string, fromID int64) string {
+	if fromID > 0 {
+		return fmt.Sprintf("%s%s?from_id=%d", baseURL, endpoint, fromID)
+	}
+	return baseURL + endpoint
+}
+
+func validateDivision(division int) error {
+	if division < MinDivision || division > MaxDivision {
+		return fmt.Errorf("invalid wallet division %d: must be between %d and %d", division, MinDivision, MaxDivision)
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+package wars
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-falcon/pkg/config"
+	"go-falcon/pkg/evegateway/paging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheInfo represents cache information for responses
+type CacheInfo struct {
+	Cached    bool       `json:"cached"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// WarIDsResult contains war IDs and cache information
+type WarIDsResult struct {
+	Data  []int32   `json:"data"`
+	Cache CacheInfo `json:"cache"`
+}
+
+// WarResult contains a war and cache information
+type WarResult struct {
+	Data  *War      `json:"data"`
+	Cache CacheInfo `json:"cache"`
+}
+
+// KillmailsResult contains a war's killmail references and cache information
+type KillmailsResult struct {
+	Data  []KillmailRef `json:"data"`
+	Cache CacheInfo     `json:"cache"`
+}
+
+// Client interface for war ESI operations
+type Client interface {
+	GetWars(ctx context.Context, maxWarID int32) ([]int32, error)
+	GetWarsWithCache(ctx context.Context, maxWarID int32) (*WarIDsResult, error)
+	GetWar(ctx context.Context, warID int) (*War, error)
+	GetWarWithCache(ctx context.Context, warID int) (*WarResult, error)
+	GetWarKillmails(ctx context.Context, warID int) ([]KillmailRef, error)
+	GetWarKillmailsWithCache(ctx context.Context, warID int) (*KillmailsResult, error)
+}
+
+// War is a war declaration. Finished is set once the war ended or is scheduled to end, Retracted
+// when the aggressor withdrew it.
+type War struct {
+	ID            int32      `json:"id"`
+	Declared      time.Time  `json:"declared"`
+	Started       *time.Time `json:"started,omitempty"`
+	Finished      *time.Time `json:"finished,omitempty"`
+	Retracted     *time.Time `json:"retracted,omitempty"`
+	Mutual        bool       `json:"mutual"`
+	OpenForAllies bool       `json:"open_for_allies"`
+	Aggressor     WarParty   `json:"aggressor"`
+	Defender      WarParty   `json:"defender"`
+	Allies        []Ally     `json:"allies,omitempty"`
+}
+
+// WarParty is the aggressor or defender of a war, either an alliance or a corporation
+type WarParty struct {
+	AllianceID    *int32  `json:"alliance_id,omitempty"`
+	CorporationID *int32  `json:"corporation_id,omitempty"`
+	ISKDestroyed  float64 `json:"isk_destroyed"`
+	ShipsKilled   int32   `json:"ships_killed"`
+}
+
+// Ally is an alliance or corporation fighting on the defender's side
+type Ally struct {
+	AllianceID    *int32 `json:"alliance_id,omitempty"`
+	CorporationID *int32 `json:"corporation_id,omitempty"`
+}
+
+// KillmailRef identifies a killmail of a war; fetch it with the killmails client
+type KillmailRef struct {
+	KillmailID   int64  `json:"killmail_id"`
+	KillmailHash string `json:"killmail_hash"`
+}
+
+// CacheManager interface for caching operations
+type CacheManager interface {
+	Get(key string) ([]byte, bool, error)
+	GetWithExpiry(key string) ([]byte, bool, *time.Time, error)
+	GetForNotModified(key string) ([]byte, bool, error)
+	Set(key string, data []byte, headers http.Header) error
+	RefreshExpiry(key string, headers http.Header) error
+	SetConditionalHeaders(req *http.Request, key string) error
+}
+
+// RetryClient interface for retry operations
+type RetryClient interface {
+	DoWithRetry(ctx context.Context, req *http.Request, maxRetries int) (*http.Response, error)
+}
+
+// ClientImpl implements the Client interface
+type ClientImpl struct {
+	httpClient   *http.Client
+	baseURL      string
+	userAgent    string
+	cacheManager CacheManager
+	retryClient  RetryClient
+}
+
+// NewWarsClient creates a new wars client
+func NewWarsClient(httpClient *http.Client, baseURL, userAgent string, cacheManager CacheManager, retryClient RetryClient) Client {
+	return &ClientImpl{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		userAgent:    userAgent,
+		cacheManager: cacheManager,
+		retryClient:  retryClient,
+	}
+}
+
+// GetWars retrieves up to 2000 war IDs, newest first. A positive maxWarID returns wars with
+// lower IDs, so older wars are walked by passing the smallest ID of the previous call.
+func (c *ClientImpl) GetWars(ctx context.Context, maxWarID int32) ([]int32, error) {
+	result, err := c.GetWarsWithCache(ctx, maxWarID)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetWarsWithCache retrieves war IDs with cache info
+func (c *ClientImpl) GetWarsWithCache(ctx context.Context, maxWarID int32) (*WarIDsResult, error) {
+	endpoint := "/wars/"
+	if maxWarID > 0 {
+		endpoint = fmt.Sprintf("/wars/?max_war_id=%d", maxWarID)
+	}
+
+	var warIDs []int32
+	cached, err := c.get(ctx, endpoint, &warIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &WarIDsResult{Data: warIDs, Cache: c.cacheInfo(endpoint, cached)}, nil
+}
+
+// GetWar retrieves a war's parties, allies and timeline
+func (c *ClientImpl) GetWar(ctx context.Context, warID int) (*War, error) {
+	result, err := c.GetWarWithCache(ctx, warID)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetWarWithCache retrieves a war with cache info
+func (c *ClientImpl) GetWarWithCache(ctx context.Context, warID int) (*WarResult, error) {
+	endpoint := fmt.Sprintf("/wars/%d/", warID)
+
+	var war War
+	cached, err := c.get(ctx, endpoint, &war)
+	if err != nil {
+		return nil, err
+	}
+	return &WarResult{Data: &war, Cache: c.cacheInfo(endpoint, cached)}, nil
+}
+
+// GetWarKillmails retrieves the references of all killmails of a war (all pages)
+func (c *ClientImpl) GetWarKillmails(ctx context.Context, warID int) ([]KillmailRef, error) {
+	result, err := c.GetWarKillmailsWithCache(ctx, warID)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetWarKillmailsWithCache retrieves a war's killmail references with cache info
+func (c *ClientImpl) GetWarKillmailsWithCache(ctx context.Context, warID int) (*KillmailsResult, error) {
+	endpoint := fmt.Sprintf("/wars/%d/killmails/", warID)
+
+	var killmails []KillmailRef
+	cached, err := c.get(ctx, endpoint, &killmails)
+	if err != nil {
+		return nil, err
+	}
+	return &KillmailsResult{Data: killmails, Cache: c.cacheInfo(endpoint, cached)}, nil
+}
+
+// cacheInfo builds the cache information of an endpoint's response
+func (c *ClientImpl) cacheInfo(endpoint string, cached bool) CacheInfo {
+	return CacheInfo{Cached: cached, ExpiresAt: c.cacheExpiry(c.baseURL + endpoint)}
+}
+
+// get fetches an endpoint into v, following X-Pages for paginated lists. The complete list is
+// cached under the endpoint with the first page's headers. Single-page responses are revalidated
+// with their ETag once expired; multi-page lists are refetched, since one page's ETag says nothing
+// about the others.
+func (c *ClientImpl) get(ctx context.Context, endpoint string, v any) (bool, error) {
+	cacheKey := c.baseURL + endpoint
+
+	if cachedData, found, err := c.cacheManager.Get(cacheKey); err == nil && found {
+		if err := json.Unmarshal(cachedData, v); err == nil {
+			return true, nil
+		}
+	}
+
+	body, headers, err := c.fetch(ctx, cacheKey, cacheKey)
+	if err != nil {
+		return false, err
+	}
+
+	totalPages := paging.TotalPages(headers)
+
+	if body == nil {
+		if cachedData, found, err := c.cacheManager.GetForNotModified(cacheKey); err == nil && found && totalPages <= 1 {
+			if err := json.Unmarshal(cachedData, v); err == nil {
+				c.cacheManager.RefreshExpiry(cacheKey, headers)
+				return true, nil
+			}
+		}
+		// Nothing usable to revalidate against; fetch the first page unconditionally
+		if body, headers, err = c.fetch(ctx, cacheKey, ""); err != nil {
+			return false, err
+		}
+	}
+
+	if totalPages > 1 {
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			return false, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		rest, err := paging.FetchRemainingPages[json.RawMessage](ctx, totalPages, func(ctx context.Context, page int) ([]byte, http.Header, error) {
+			return c.fetch(ctx, paging.PageURL(cacheKey, page), "")
+		}, paging.DefaultConcurrency)
+		if err != nil {
+			return false, err
+		}
+		items = append(items, rest...)
+
+		if body, err = json.Marshal(items); err != nil {
+			return false, fmt.Errorf("failed to combine pages: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.cacheManager.Set(cacheKey, body, headers)
+	return false, nil
+}
+
+// fetch performs an unauthenticated GET. When conditionalKey is set, the request
+// carries the ETag cached under that key and a 304 response is reported as a nil body with the
+// response headers.
+func (c *ClientImpl) fetch(ctx context.Context, url, conditionalKey string) ([]byte, http.Header, error) {
+	var span trace.Span
+
+	if config.GetBoolEnv("ENABLE_TELEMETRY", false) {
+		tracer := otel.Tracer("go-falcon/evegateway/wars")
+		ctx, span = tracer.Start(ctx, "wars.fetch")
+		defer span.End()
+
+		span.SetAttributes(attribute.String("esi.url", url))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create request")
+		}
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	if conditionalKey != "" {
+		c.cacheManager.SetConditionalHeaders(req, conditionalKey)
+	}
+
+	resp, err := c.retryClient.DoWithRetry(ctx, req, 3)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to call ESI")
+		}
+		slog.ErrorContext(ctx, "Failed to call ESI wars endpoint", "url", url, "error", err)
+		return nil, nil, fmt.Errorf("failed to call ESI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+
+	if resp.StatusCode == http.StatusNotModified && conditionalKey != "" {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if span != nil {
+			span.SetStatus(codes.Error, "ESI returned error status")
+		}
+		slog.ErrorContext(ctx, "ESI wars endpoint returned error", "url", url, "status_code", resp.StatusCode)
+		return nil, nil, fmt.Errorf("ESI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read response")
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.response_size", len(body)))
+		span.SetStatus(codes.Ok, "successfully retrieved data")
+	}
+
+	return body, resp.Header, nil
+}
+
+// cacheExpiry returns when the entry under cacheKey expires, if it is cached
+func (c *ClientImpl) cacheExpiry(cacheKey string) *time.Time {
+	if _, found, expiry, err := c.cacheManager.GetWithExpiry(cacheKey); err == nil && found {
+		return expiry
+	}
+	return nil
+}
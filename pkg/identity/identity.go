@@ -0,0 +1,179 @@
+package identity
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AuthMethod identifies how the actor authenticated
+type AuthMethod string
+
+const (
+	AuthMethodBearer AuthMethod = "bearer"
+	AuthMethodCookie AuthMethod = "cookie"
+	AuthMethodAPIKey AuthMethod = "api_key"
+	AuthMethodSystem AuthMethod = "system"
+)
+
+// Actor is a user acting through one of their characters, or a staff account acting without
+// one; Provider names the identity provider of staff accounts
+type Actor struct {
+	UserID        string
+	CharacterID   int
+	CharacterName string
+	Provider      string
+}
+
+// Identity describes who is performing an operation. Impersonator is set when an
+// administrator acts as Actor; Process names the background job when the system acts on its own.
+// SessionID and AuthenticatedAt identify the login session of token-authenticated requests;
+// SudoUntil is set while the token carries a second-factor (sudo) elevation.
+type Identity struct {
+	Actor           Actor
+	Impersonator    *Actor
+	AuthMethod      AuthMethod
+	APIKeyID        string
+	Process         string
+	SessionID       string
+	AuthenticatedAt time.Time
+	SudoUntil       time.Time
+}
+
+type contextKey struct{}
+
+// With returns a context carrying the identity
+func With(ctx context.Context, id *Identity) context.Context {
+	if id == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the identity carried by ctx, or nil
+func FromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(contextKey{}).(*Identity)
+	return id
+}
+
+// System returns the identity of a background process acting without a user
+func System(process string) *Identity {
+	return &Identity{AuthMethod: AuthMethodSystem, Process: process}
+}
+
+// Detach returns a background context that keeps the identity and trace of ctx but not its
+// cancellation, for work that outlives the request that started it
+func Detach(ctx context.Context) context.Context {
+	detached := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	return With(detached, FromContext(ctx))
+}
+
+// IsSystem reports whether the identity is a background process
+func (id *Identity) IsSystem() bool {
+	return id.AuthMethod == AuthMethodSystem
+}
+
+// Label is a short human-readable name for created_by style fields
+func (id *Identity) Label() string {
+	if id.IsSystem() {
+		return "system:" + id.Process
+	}
+	if id.Actor.Provider != "" {
+		return "staff:" + id.Actor.UserID
+	}
+	return "character:" + strconv.Itoa(id.Actor.CharacterID)
+}
+
+// LogAttrs returns the identity as structured log attributes
+func (id *Identity) LogAttrs() []slog.Attr {
+	attrs := []slog.Attr{slog.String("auth_method", string(id.AuthMethod))}
+	if id.IsSystem() {
+		return append(attrs, slog.String("process", id.Process))
+	}
+
+	attrs = append(attrs,
+		slog.String("actor_user_id", id.Actor.UserID),
+		slog.Int("actor_character_id", id.Actor.CharacterID),
+	)
+	if id.Impersonator != nil {
+		attrs = append(attrs,
+			slog.String("impersonator_user_id", id.Impersonator.UserID),
+			slog.Int("impersonator_character_id", id.Impersonator.CharacterID),
+		)
+	}
+	if id.APIKeyID != "" {
+		attrs = append(attrs, slog.String("api_key_id", id.APIKeyID))
+	}
+	return attrs
+}
+
+// Attribution is the persisted form of an identity, embedded in audit records,
+// domain events and anything else that must remember who caused it
+type Attribution struct {
+	ActorUserID             string     `bson:"actor_user_id,omitempty" json:"actor_user_id,omitempty"`
+	ActorCharacterID        int        `bson:"actor_character_id,omitempty" json:"actor_character_id,omitempty"`
+	ActorCharacterName      string     `bson:"actor_character_name,omitempty" json:"actor_character_name,omitempty"`
+	ActorProvider           string     `bson:"actor_provider,omitempty" json:"actor_provider,omitempty"`
+	ImpersonatorUserID      string     `bson:"impersonator_user_id,omitempty" json:"impersonator_user_id,omitempty"`
+	ImpersonatorCharacterID int        `bson:"impersonator_character_id,omitempty" json:"impersonator_character_id,omitempty"`
+	AuthMethod              AuthMethod `bson:"auth_method" json:"auth_method"`
+	APIKeyID                string     `bson:"api_key_id,omitempty" json:"api_key_id,omitempty"`
+	Process                 string     `bson:"process,omitempty" json:"process,omitempty"`
+}
+
+// Attribution converts the identity into its persisted form
+func (id *Identity) Attribution() Attribution {
+	attribution := Attribution{
+		ActorUserID:        id.Actor.UserID,
+		ActorCharacterID:   id.Actor.CharacterID,
+		ActorCharacterName: id.Actor.CharacterName,
+		ActorProvider:      id.Actor.Provider,
+		AuthMethod:         id.AuthMethod,
+		APIKeyID:           id.APIKeyID,
+		Process:            id.Process,
+	}
+	if id.Impersonator != nil {
+		attribution.ImpersonatorUserID = id.Impersonator.UserID
+		attribution.ImpersonatorCharacterID = id.Impersonator.CharacterID
+	}
+	return attribution
+}
+
+// AttributionFromContext returns the attribution of the identity carried by ctx, or nil
+func AttributionFromContext(ctx context.Context) *Attribution {
+	id := FromContext(ctx)
+	if id == nil {
+		return nil
+	}
+	attribution := id.Attribution()
+	return &attribution
+}
+
+// FromAttribution restores an identity from its persisted form, e.g. when a queued job runs
+func FromAttribution(attribution *Attribution) *Identity {
+	if attribution == nil {
+		return nil
+	}
+
+	id := &Identity{
+		Actor: Actor{
+			UserID:        attribution.ActorUserID,
+			CharacterID:   attribution.ActorCharacterID,
+			CharacterName: attribution.ActorCharacterName,
+			Provider:      attribution.ActorProvider,
+		},
+		AuthMethod: attribution.AuthMethod,
+		APIKeyID:   attribution.APIKeyID,
+		Process:    attribution.Process,
+	}
+	if attribution.ImpersonatorUserID != "" {
+		id.Impersonator = &Actor{
+			UserID:      attribution.ImpersonatorUserID,
+			CharacterID: attribution.ImpersonatorCharacterID,
+		}
+	}
+	return id
+}
@@ -0,0 +1,180 @@
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisChannel is the pub/sub channel invalidation messages are published on
+const RedisChannel = "cache:invalidate"
+
+// Scopes of the per-instance caches kept by modules
+const (
+	ScopeSiteSettings = "site_settings"
+	ScopeSitemap      = "sitemap"
+)
+
+// Message announces that the data behind a cache scope changed
+type Message struct {
+	Scope     string    `json:"scope"`
+	ServerID  string    `json:"server_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Handler drops or reloads the local copy of a cache scope
+type Handler func(ctx context.Context)
+
+// Bus fans cache invalidations out to every instance. The instance that changes the data
+// clears its own cache and publishes; the others run their handlers for the scope when the
+// message arrives.
+type Bus struct {
+	redis    *redis.Client
+	serverID string
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+
+	pubsub *redis.PubSub
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewBus creates an invalidation bus; without a Redis client invalidations stay local
+func NewBus(redisClient *redis.Client, serverID string) *Bus {
+	return &Bus{
+		redis:    redisClient,
+		serverID: serverID,
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers a handler run when another instance invalidates the scope
+func (b *Bus) Subscribe(scope string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[scope] = append(b.handlers[scope], handler)
+}
+
+// Scopes returns the scopes that have handlers, ordered by name
+func (b *Bus) Scopes() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	scopes := make([]string, 0, len(b.handlers))
+	for scope := range b.handlers {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Publish tells the other instances that a scope changed. Failures are logged rather than
+// returned: the write that triggered them has already succeeded.
+func (b *Bus) Publish(ctx context.Context, scope string) {
+	if b.redis == nil {
+		return
+	}
+
+	data, err := json.Marshal(Message{Scope: scope, ServerID: b.serverID, Timestamp: time.Now()})
+	if err != nil {
+		slog.Error("Failed to marshal cache invalidation", "scope", scope, "error", err)
+		return
+	}
+	if err := b.redis.Publish(ctx, RedisChannel, data).Err(); err != nil {
+		slog.Warn("Failed to publish cache invalidation", "scope", scope, "error", err)
+	}
+}
+
+// Refresh runs the local handlers of the given scopes, or of every scope when none are
+// given, and publishes them so the other instances do the same. It returns the scopes
+// refreshed.
+func (b *Bus) Refresh(ctx context.Context, scopes ...string) ([]string, error) {
+	known := b.Scopes()
+	if len(scopes) == 0 {
+		scopes = known
+	}
+
+	for _, scope := range scopes {
+		if !contains(known, scope) {
+			return nil, fmt.Errorf("unknown cache scope %q", scope)
+		}
+	}
+
+	for _, scope := range scopes {
+		b.dispatch(ctx, scope)
+		b.Publish(ctx, scope)
+	}
+
+	slog.Info("Refreshed caches", "scopes", scopes)
+	return scopes, nil
+}
+
+// Start subscribes to the invalidation channel
+func (b *Bus) Start(ctx context.Context) {
+	if b.redis == nil {
+		return
+	}
+
+	ctx, b.cancel = context.WithCancel(ctx)
+	b.pubsub = b.redis.Subscribe(ctx, RedisChannel)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.listen(ctx)
+	}()
+
+	slog.Info("Cache invalidation bus started", "server_id", b.serverID, "scopes", b.Scopes())
+}
+
+// Stop unsubscribes and waits for the listener to exit
+func (b *Bus) Stop() {
+	if b.cancel != nil {
+		b.cancel()
+	}
+	if b.pubsub != nil {
+		b.pubsub.Close()
+	}
+	b.wg.Wait()
+}
+
+func (b *Bus) listen(ctx context.Context) {
+	for msg := range b.pubsub.Channel() {
+		var message Message
+		if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+			slog.Warn("Failed to decode cache invalidation", "error", err)
+			continue
+		}
+		// The publishing instance already cleared its own cache
+		if message.ServerID == b.serverID {
+			continue
+		}
+		b.dispatch(ctx, message.Scope)
+	}
+}
+
+func (b *Bus) dispatch(ctx context.Context, scope string) {
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.handlers[scope]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
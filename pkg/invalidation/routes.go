@@ -0,0 +1,68 @@
+package invalidation
+
+import (
+	"context"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/apidocs"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// SuperAdminChecker authorizes manual cache refreshes
+type SuperAdminChecker interface {
+	RequireSuperAdmin(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error)
+}
+
+// RefreshInput is the input for the cache refresh endpoint
+type RefreshInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Body          struct {
+		Scopes []string `json:"scopes,omitempty" doc:"Cache scopes to refresh; all scopes when empty" example:"[\"site_settings\"]"`
+	} `required:"false"`
+}
+
+// RefreshResponse lists the refreshed cache scopes
+type RefreshResponse struct {
+	Scopes  []string `json:"scopes" doc:"Cache scopes refreshed on every instance"`
+	Message string   `json:"message"`
+}
+
+// RefreshOutput wraps the cache refresh response
+type RefreshOutput struct {
+	Body RefreshResponse
+}
+
+// RegisterRoutes registers the manual cache refresh endpoint (super admin only)
+func RegisterRoutes(api huma.API, path string, bus *Bus, auth SuperAdminChecker) {
+	huma.Register(api, huma.Operation{
+		OperationID: "admin-refresh-caches",
+		Method:      "POST",
+		Path:        path + "/refresh",
+		Summary:     "Refresh caches",
+		Description: "Drops the per-instance caches of the given scopes on every instance, for when data was changed outside the API. Requires super admin access.",
+		Tags:        []string{"Health"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
+		Security: []map[string][]string{
+			{"bearerAuth": {}},
+			{"cookieAuth": {}},
+		},
+	}, func(ctx context.Context, input *RefreshInput) (*RefreshOutput, error) {
+		if _, err := auth.RequireSuperAdmin(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+
+		scopes, err := bus.Refresh(ctx, input.Body.Scopes...)
+		if err != nil {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+
+		return &RefreshOutput{
+			Body: RefreshResponse{
+				Scopes:  scopes,
+				Message: "Caches refreshed",
+			},
+		}, nil
+	})
+}
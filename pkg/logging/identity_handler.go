@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"go-falcon/pkg/identity"
+)
+
+// IdentityHandler adds the request identity (actor, impersonator, auth method, API key or
+// background process) to every record logged with a context that carries one
+type IdentityHandler struct {
+	handler slog.Handler
+}
+
+func NewIdentityHandler(handler slog.Handler) *IdentityHandler {
+	return &IdentityHandler{handler: handler}
+}
+
+func (h *IdentityHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *IdentityHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := identity.FromContext(ctx); id != nil {
+		record = record.Clone()
+		record.AddAttrs(id.LogAttrs()...)
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *IdentityHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &IdentityHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *IdentityHandler) WithGroup(name string) slog.Handler {
+	return &IdentityHandler{handler: h.handler.WithGroup(name)}
+}
@@ -172,6 +172,9 @@ func (tm *TelemetryManager) setupLogger() {
 		handler = NewOTelHandler(handler)
 	}
 
+	// Outermost so both console and OpenTelemetry output carry the caller identity
+	handler = NewIdentityHandler(handler)
+
 	logger := slog.New(handler)
 
 	// Set as default logger
@@ -108,18 +108,19 @@ func (sa *SchedulerAdapter) RequireSchedulerManagement(ctx context.Context, auth
 	return sa.permissionMiddleware.RequireAnyPermission(ctx, authHeader, cookieHeader, requiredPermissions)
 }
 
+// SchedulerTaskManagementPermissions grant task management; any one of them is sufficient
+var SchedulerTaskManagementPermissions = []string{
+	"scheduler:tasks:create",
+	"scheduler:tasks:update",
+	"scheduler:tasks:delete",
+	"scheduler:tasks:execute",
+	"scheduler:tasks:control",
+	"scheduler:system:manage",
+}
+
 // RequireTaskManagement checks for task management permissions (create, update, delete, execute, control)
 func (sa *SchedulerAdapter) RequireTaskManagement(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error) {
-	taskManagementPermissions := []string{
-		"scheduler:tasks:create",
-		"scheduler:tasks:update",
-		"scheduler:tasks:delete",
-		"scheduler:tasks:execute",
-		"scheduler:tasks:control",
-		"scheduler:system:manage",
-	}
-
-	return sa.permissionMiddleware.RequireAnyPermission(ctx, authHeader, cookieHeader, taskManagementPermissions)
+	return sa.permissionMiddleware.RequireAnyPermission(ctx, authHeader, cookieHeader, SchedulerTaskManagementPermissions)
 }
 
 // RequireSpecificPermission checks for a specific scheduler permission
@@ -180,6 +181,11 @@ func (ua *UsersAdapter) RequireProfileAccess(ctx context.Context, authHeader, co
 	return ua.permissionMiddleware.RequirePermission(ctx, authHeader, cookieHeader, "users:profiles:view")
 }
 
+// RequireAuthenticated ensures the request carries a valid authentication token
+func (ua *UsersAdapter) RequireAuthenticated(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error) {
+	return ua.permissionMiddleware.RequireAuth(ctx, authHeader, cookieHeader)
+}
+
 // RequireUserAccess ensures the user can access user information (self or admin)
 func (ua *UsersAdapter) RequireUserAccess(ctx context.Context, authHeader, cookieHeader, targetUserID string) (*models.AuthenticatedUser, error) {
 	user, err := ua.permissionMiddleware.RequireAuth(ctx, authHeader, cookieHeader)
@@ -341,6 +347,21 @@ func (ca *CorporationAdapter) RequireMemberTrackingAccess(ctx context.Context, a
 	return ca.permissionMiddleware.RequirePermission(ctx, authHeader, cookieHeader, "corporation:membertracking:view")
 }
 
+// RequireContainerLogAccess checks for corporation container audit log permissions
+func (ca *CorporationAdapter) RequireContainerLogAccess(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error) {
+	return ca.permissionMiddleware.RequirePermission(ctx, authHeader, cookieHeader, "corporation:containerlogs:view")
+}
+
+// RequireWalletAccess checks for corporation wallet permissions; access to individual divisions is checked by the service
+func (ca *CorporationAdapter) RequireWalletAccess(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error) {
+	return ca.permissionMiddleware.RequirePermission(ctx, authHeader, cookieHeader, "corporation:wallets:view")
+}
+
+// RequireWalletManagement checks for permission to configure wallet divisions and budget alerts
+func (ca *CorporationAdapter) RequireWalletManagement(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error) {
+	return ca.permissionMiddleware.RequirePermission(ctx, authHeader, cookieHeader, "corporation:wallets:manage")
+}
+
 // SiteSettingsAdapter provides site settings-specific permission methods
 type SiteSettingsAdapter struct {
 	*ModuleAdapter
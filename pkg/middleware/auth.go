@@ -2,11 +2,9 @@ package middleware
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"go-falcon/internal/auth/models"
-	"go-falcon/pkg/config"
 
 	"github.com/danielgtaylor/huma/v2"
 )
@@ -18,6 +16,9 @@ const (
 	AuthContextKeyUser = AuthContextKey("authenticated_user")
 )
 
+// RefreshCookieName is the cookie holding the session refresh token
+const RefreshCookieName = "falcon_refresh_token"
+
 // JWTValidator interface for JWT validation
 type JWTValidator interface {
 	ValidateJWT(token string) (*models.AuthenticatedUser, error)
@@ -99,6 +100,17 @@ func (m *AuthMiddleware) ExtractTokenFromCookie(cookieHeader string) string {
 	return ""
 }
 
+// ExtractRefreshTokenFromCookie extracts the session refresh token from cookie header string
+func (m *AuthMiddleware) ExtractRefreshTokenFromCookie(cookieHeader string) string {
+	for _, cookie := range strings.Split(cookieHeader, ";") {
+		cookie = strings.TrimSpace(cookie)
+		if strings.HasPrefix(cookie, RefreshCookieName+"=") {
+			return strings.TrimPrefix(cookie, RefreshCookieName+"=")
+		}
+	}
+	return ""
+}
+
 // ValidateToken validates a JWT token string and returns the authenticated user
 func (m *AuthMiddleware) ValidateToken(token string) (*models.AuthenticatedUser, error) {
 	if token == "" {
@@ -156,35 +168,3 @@ func (e *AuthError) Error() string {
 func NewAuthError(message string) *AuthError {
 	return &AuthError{message: message}
 }
-
-// CreateAuthCookieHeader creates a Set-Cookie header string for authentication
-func CreateAuthCookieHeader(token string) string {
-	// Get cookie domain from config
-	cookieDomain := config.GetCookieDomain()
-
-	// Get cookie duration from config and convert to seconds for Max-Age
-	cookieDuration := config.GetCookieDuration()
-	maxAge := int(cookieDuration.Seconds())
-
-	// Create cookie similar to the traditional handler
-	cookie := "falcon_auth_token=" + token + "; Path=/"
-	if cookieDomain != "" {
-		cookie += "; Domain=" + cookieDomain
-	}
-	cookie += fmt.Sprintf("; Max-Age=%d; HttpOnly; Secure; SameSite=Lax", maxAge)
-	return cookie
-}
-
-// CreateClearCookieHeader creates a Set-Cookie header string to clear the auth cookie
-func CreateClearCookieHeader() string {
-	// Get cookie domain from config
-	cookieDomain := config.GetCookieDomain()
-
-	// Clear cookie by setting it to empty with past expiration
-	cookie := "falcon_auth_token=; Path=/"
-	if cookieDomain != "" {
-		cookie += "; Domain=" + cookieDomain
-	}
-	cookie += "; Max-Age=0; HttpOnly; Secure; SameSite=Lax"
-	return cookie
-}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go-falcon/pkg/config"
+)
+
+// CacheClass groups routes that share the same caching behaviour
+type CacheClass string
+
+const (
+	// CacheClassStatic is SDE-derived data that only changes with an SDE update; purge the CDN after one
+	CacheClassStatic CacheClass = "static"
+	// CacheClassShort is public data such as stats that a CDN may share for a short time
+	CacheClassShort CacheClass = "short"
+	// CacheClassPrivate is per-user data the browser may keep but a shared cache must not
+	CacheClassPrivate CacheClass = "private"
+	// CacheClassNoStore is never stored anywhere (login flows, tokens)
+	CacheClassNoStore CacheClass = "no-store"
+)
+
+// CacheRule assigns a class to every path under a prefix. Prefixes match whole path
+// segments and exclude the API prefix: "/map/region" matches "/map/region/10000002".
+type CacheRule struct {
+	Prefix string
+	Class  CacheClass
+}
+
+// DefaultCacheRules returns the built-in rule set; routes without a rule are private
+func DefaultCacheRules() []CacheRule {
+	return []CacheRule{
+		{Prefix: "/auth", Class: CacheClassNoStore},
+		{Prefix: "/discord/auth", Class: CacheClassNoStore},
+		{Prefix: "/health", Class: CacheClassNoStore},
+		{Prefix: "/map/region", Class: CacheClassStatic},
+		{Prefix: "/map/search", Class: CacheClassStatic},
+		{Prefix: "/map/route", Class: CacheClassStatic},
+		{Prefix: "/map/status", Class: CacheClassShort},
+		{Prefix: "/site-settings/public", Class: CacheClassShort},
+		{Prefix: "/zkillboard/stats", Class: CacheClassShort},
+		{Prefix: "/zkillboard/recent", Class: CacheClassShort},
+		{Prefix: "/zkillboard/status", Class: CacheClassShort},
+	}
+}
+
+// CachePolicy maps request paths to cache classes and the headers each class sends
+type CachePolicy struct {
+	rules   []CacheRule
+	headers map[CacheClass]cacheHeaders
+}
+
+type cacheHeaders struct {
+	cacheControl     string
+	surrogateControl string
+}
+
+// NewCachePolicy creates a policy from rules and the browser and CDN max-ages (seconds) of
+// the static and short classes. Later rules override earlier ones with the same prefix.
+func NewCachePolicy(rules []CacheRule, staticMaxAge, staticCDNMaxAge, shortMaxAge, shortCDNMaxAge int) *CachePolicy {
+	byPrefix := make(map[string]CacheClass, len(rules))
+	for _, rule := range rules {
+		byPrefix[strings.TrimSuffix(rule.Prefix, "/")] = rule.Class
+	}
+
+	policy := &CachePolicy{
+		rules: make([]CacheRule, 0, len(byPrefix)),
+		headers: map[CacheClass]cacheHeaders{
+			CacheClassStatic: {
+				cacheControl:     fmt.Sprintf("public, max-age=%d", staticMaxAge),
+				surrogateControl: fmt.Sprintf("max-age=%d", staticCDNMaxAge),
+			},
+			CacheClassShort: {
+				cacheControl:     fmt.Sprintf("public, max-age=%d", shortMaxAge),
+				surrogateControl: fmt.Sprintf("max-age=%d", shortCDNMaxAge),
+			},
+			CacheClassPrivate: {cacheControl: "private, no-cache", surrogateControl: "no-store"},
+			CacheClassNoStore: {cacheControl: "no-store", surrogateControl: "no-store"},
+		},
+	}
+	for prefix, class := range byPrefix {
+		policy.rules = append(policy.rules, CacheRule{Prefix: prefix, Class: class})
+	}
+	// Longest prefix wins
+	sort.Slice(policy.rules, func(i, j int) bool { return len(policy.rules[i].Prefix) > len(policy.rules[j].Prefix) })
+
+	return policy
+}
+
+// NewCachePolicyFromConfig creates the policy from the default rules, the CACHE_CONTROL_RULES
+// overrides and the configured max-ages
+func NewCachePolicyFromConfig() *CachePolicy {
+	rules := DefaultCacheRules()
+	for _, entry := range config.GetCacheControlRules() {
+		prefix, class, ok := strings.Cut(entry, "=")
+		rule := CacheRule{Prefix: strings.TrimSpace(prefix), Class: CacheClass(strings.TrimSpace(class))}
+		if !ok || !strings.HasPrefix(rule.Prefix, "/") || !rule.Class.valid() {
+			slog.Warn("Ignoring invalid CACHE_CONTROL_RULES entry", "entry", entry)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return NewCachePolicy(rules,
+		config.GetCacheStaticMaxAge(), config.GetCacheStaticCDNMaxAge(),
+		config.GetCacheShortMaxAge(), config.GetCacheShortCDNMaxAge())
+}
+
+// Classify returns the class of a path (without the API prefix)
+func (p *CachePolicy) Classify(path string) CacheClass {
+	for _, rule := range p.rules {
+		if path == rule.Prefix || strings.HasPrefix(path, rule.Prefix+"/") {
+			return rule.Class
+		}
+	}
+	return CacheClassPrivate
+}
+
+func (c CacheClass) valid() bool {
+	switch c {
+	case CacheClassStatic, CacheClassShort, CacheClassPrivate, CacheClassNoStore:
+		return true
+	}
+	return false
+}
+
+// CacheControl sets Cache-Control and Surrogate-Control on GET and HEAD responses according
+// to the policy. Handlers that set Cache-Control themselves keep their value, error responses
+// are never stored, and shared classes fall back to private when the request carries
+// credentials so a CDN never keeps a response rendered for a signed-in user.
+func CacheControl(policy *CachePolicy, apiPrefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// WebSocket upgrades need the raw writer for hijacking
+			if !(r.Method == http.MethodGet || r.Method == http.MethodHead) || r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			class := policy.Classify(strings.TrimPrefix(r.URL.Path, apiPrefix))
+			if (class == CacheClassStatic || class == CacheClassShort) && hasCredentials(r) {
+				class = CacheClassPrivate
+			}
+
+			next.ServeHTTP(&cacheControlWriter{ResponseWriter: w, headers: policy.headers[class], noStore: policy.headers[CacheClassNoStore]}, r)
+		})
+	}
+}
+
+// hasCredentials reports whether the request is authenticated by header or cookie
+func hasCredentials(r *http.Request) bool {
+	if r.Header.Get("Authorization") != "" {
+		return true
+	}
+	_, err := r.Cookie("falcon_auth_token")
+	return err == nil
+}
+
+// cacheControlWriter applies the cache headers when the status is known
+type cacheControlWriter struct {
+	http.ResponseWriter
+	headers     cacheHeaders
+	noStore     cacheHeaders
+	wroteHeader bool
+}
+
+func (w *cacheControlWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		header := w.ResponseWriter.Header()
+		if header.Get("Cache-Control") == "" {
+			headers := w.headers
+			if status >= http.StatusBadRequest {
+				headers = w.noStore
+			}
+			header.Set("Cache-Control", headers.cacheControl)
+			header.Set("Surrogate-Control", headers.surrogateControl)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush passes through to streaming handlers
+func (w *cacheControlWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
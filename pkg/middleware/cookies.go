@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"go-falcon/pkg/config"
+)
+
+// AuthCookieName is the cookie holding the session JWT
+const AuthCookieName = "falcon_auth_token"
+
+// CookieAttributes are the attributes the auth cookies are set with
+type CookieAttributes struct {
+	Domain   string // Empty for host-only cookies
+	SameSite string // Lax, Strict or None
+	Secure   bool
+	MaxAge   time.Duration
+}
+
+// CookieSettingsSource reads the auth cookie attributes an administrator set in site settings
+type CookieSettingsSource interface {
+	AuthCookieSettings(ctx context.Context) (map[string]interface{}, error)
+}
+
+var (
+	cookieSettingsMu sync.RWMutex
+	cookieSettings   CookieSettingsSource
+)
+
+// SetCookieSettingsSource lets site settings override the COOKIE_* configuration at runtime
+func SetCookieSettingsSource(source CookieSettingsSource) {
+	cookieSettingsMu.Lock()
+	defer cookieSettingsMu.Unlock()
+	cookieSettings = source
+}
+
+// AuthCookieAttributes returns the attributes of the auth cookies: the COOKIE_* configuration,
+// overridden field by field by the auth_cookie site setting. The max age cannot exceed
+// COOKIE_DURATION, which is also the JWT lifetime, and SameSite=None always sets Secure since
+// browsers reject it otherwise.
+func AuthCookieAttributes(ctx context.Context) CookieAttributes {
+	attributes := CookieAttributes{
+		Domain:   config.GetCookieDomain(),
+		SameSite: normalizeSameSite(config.GetCookieSameSite()),
+		Secure:   config.GetCookieSecure(),
+		MaxAge:   config.GetCookieDuration(),
+	}
+	if attributes.SameSite == "" {
+		attributes.SameSite = "Lax"
+	}
+
+	cookieSettingsMu.RLock()
+	source := cookieSettings
+	cookieSettingsMu.RUnlock()
+	if source != nil {
+		fields, err := source.AuthCookieSettings(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to read auth cookie settings", "error", err)
+		} else {
+			applyCookieSettings(ctx, &attributes, fields)
+		}
+	}
+
+	if attributes.SameSite == "None" {
+		attributes.Secure = true
+	}
+	return attributes
+}
+
+// applyCookieSettings overrides the attributes with the fields of the auth_cookie site setting;
+// missing, empty and invalid fields keep the configured value
+func applyCookieSettings(ctx context.Context, attributes *CookieAttributes, fields map[string]interface{}) {
+	if domain, ok := fields["domain"].(string); ok && domain != "" {
+		attributes.Domain = config.CookieDomain(domain)
+	}
+	if value, ok := fields["same_site"].(string); ok && value != "" {
+		if sameSite := normalizeSameSite(value); sameSite != "" {
+			attributes.SameSite = sameSite
+		} else {
+			slog.WarnContext(ctx, "Ignoring invalid auth cookie same_site setting", "value", value)
+		}
+	}
+	if secure, ok := fields["secure"].(bool); ok {
+		attributes.Secure = secure
+	}
+
+	var maxAge time.Duration
+	switch value := fields["max_age"].(type) {
+	case string:
+		parsed, err := config.ParseDuration(value)
+		if err != nil {
+			slog.WarnContext(ctx, "Ignoring invalid auth cookie max_age setting", "value", value)
+		}
+		maxAge = parsed
+	case float64:
+		maxAge = time.Duration(value * float64(time.Second))
+	case int32:
+		maxAge = time.Duration(value) * time.Second
+	case int64:
+		maxAge = time.Duration(value) * time.Second
+	}
+	if maxAge > 0 && maxAge < attributes.MaxAge {
+		attributes.MaxAge = maxAge
+	}
+}
+
+// normalizeSameSite returns the SameSite attribute value for a setting, or "" when it is invalid
+func normalizeSameSite(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "lax":
+		return "Lax"
+	case "strict":
+		return "Strict"
+	case "none":
+		return "None"
+	}
+	return ""
+}
+
+// cookieHeader formats a Set-Cookie header with the domain and Secure flag of the attributes
+func cookieHeader(attributes CookieAttributes, name, value, path string, maxAge int, sameSite string) string {
+	cookie := name + "=" + value + "; Path=" + path
+	if attributes.Domain != "" {
+		cookie += "; Domain=" + attributes.Domain
+	}
+	cookie += fmt.Sprintf("; Max-Age=%d; HttpOnly", maxAge)
+	if attributes.Secure {
+		cookie += "; Secure"
+	}
+	return cookie + "; SameSite=" + sameSite
+}
+
+// CreateAuthCookieHeader creates a Set-Cookie header string for authentication
+func CreateAuthCookieHeader(ctx context.Context, token string) string {
+	attributes := AuthCookieAttributes(ctx)
+	return cookieHeader(attributes, AuthCookieName, token, "/", int(attributes.MaxAge.Seconds()), attributes.SameSite)
+}
+
+// CreateClearCookieHeader creates a Set-Cookie header string to clear the auth cookie
+func CreateClearCookieHeader(ctx context.Context) string {
+	attributes := AuthCookieAttributes(ctx)
+	return cookieHeader(attributes, AuthCookieName, "", "/", 0, attributes.SameSite)
+}
+
+// refreshCookiePath limits the refresh token cookie to the auth endpoints, which refresh and end sessions
+func refreshCookiePath() string {
+	return config.GetAPIPrefix() + "/auth"
+}
+
+// CreateRefreshCookieHeader creates a Set-Cookie header string for the session refresh token. It
+// shares the domain and Secure flag of the auth cookie but is always SameSite=Strict.
+func CreateRefreshCookieHeader(ctx context.Context, token string) string {
+	attributes := AuthCookieAttributes(ctx)
+	return cookieHeader(attributes, RefreshCookieName, token, refreshCookiePath(), int(config.GetRefreshTokenDuration().Seconds()), "Strict")
+}
+
+// CreateClearRefreshCookieHeader creates a Set-Cookie header string to clear the refresh token cookie
+func CreateClearRefreshCookieHeader(ctx context.Context) string {
+	return cookieHeader(AuthCookieAttributes(ctx), RefreshCookieName, "", refreshCookiePath(), 0, "Strict")
+}
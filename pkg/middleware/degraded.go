@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AvailabilityProbe reports whether the primary database is reachable
+type AvailabilityProbe interface {
+	Available() bool
+	RetryAfter() time.Duration
+}
+
+// DegradedMode rejects writes with 503 while the database is unavailable. Reads still pass
+// through so endpoints answered from Redis or the in-memory SDE keep working; reads that
+// need MongoDB fail on their own once the driver's server selection times out.
+func DegradedMode(probe AvailabilityProbe) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if probe.Available() || isReadOnlyMethod(r.Method) || strings.HasSuffix(r.URL.Path, "/health") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			retryAfter := int(probe.RetryAfter().Seconds())
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]any{
+				"title":  http.StatusText(http.StatusServiceUnavailable),
+				"status": http.StatusServiceUnavailable,
+				"detail": "The database is temporarily unavailable; changes are disabled until it recovers. Read-only requests are still served.",
+			})
+		})
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
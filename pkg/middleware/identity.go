@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"go-falcon/pkg/identity"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// IdentityMiddleware resolves the caller from the Authorization header or auth cookie and stores
+// both the identity and the authenticated user in the request context. Anonymous requests pass
+// through unchanged; handlers still enforce authentication with RequireAuth/RequirePermission.
+func IdentityMiddleware(validator JWTValidator) func(ctx huma.Context, next func(huma.Context)) {
+	auth := NewAuthMiddleware(validator)
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		method := identity.AuthMethodBearer
+		token := auth.ExtractTokenFromHeaders(ctx.Header("Authorization"))
+		if token == "" {
+			method = identity.AuthMethodCookie
+			token = auth.ExtractTokenFromCookie(ctx.Header("Cookie"))
+		}
+		if token == "" {
+			next(ctx)
+			return
+		}
+
+		user, err := auth.ValidateToken(token)
+		if err != nil {
+			next(ctx)
+			return
+		}
+
+		id := &identity.Identity{
+			Actor: identity.Actor{
+				UserID:        user.UserID,
+				CharacterID:   user.CharacterID,
+				CharacterName: user.CharacterName,
+				Provider:      user.Provider,
+			},
+			AuthMethod:      method,
+			SessionID:       SessionID(token),
+			AuthenticatedAt: user.AuthTime,
+			SudoUntil:       user.SudoUntil,
+		}
+		if user.Impersonator != nil {
+			id.Impersonator = &identity.Actor{
+				UserID:        user.Impersonator.UserID,
+				CharacterID:   user.Impersonator.CharacterID,
+				CharacterName: user.Impersonator.CharacterName,
+			}
+		}
+
+		requestCtx := context.WithValue(ctx.Context(), AuthContextKeyUser, user)
+		next(huma.WithContext(ctx, identity.With(requestCtx, id)))
+	}
+}
+
+// SessionID derives a stable, non-reversible session identifier from an auth token so
+// per-session state can be keyed without storing the token itself
+func SessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:16])
+}
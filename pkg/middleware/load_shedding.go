@@ -0,0 +1,257 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"runtime"
+	"runtime/metrics"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go-falcon/pkg/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Priority decides how early a route is shed when the gateway runs short of resources
+type Priority string
+
+const (
+	// PriorityCritical is never shed (health checks, login, administration)
+	PriorityCritical Priority = "critical"
+	// PriorityNormal is shed only once the gateway is at full load
+	PriorityNormal Priority = "normal"
+	// PriorityLow is shed first, as soon as load passes the low-priority threshold
+	PriorityLow Priority = "low"
+)
+
+// PriorityRule assigns a priority to every path under a prefix. Prefixes match whole path
+// segments and exclude the API prefix, like CacheRule.
+type PriorityRule struct {
+	Prefix   string
+	Priority Priority
+}
+
+// DefaultPriorityRules returns the built-in rule set; routes without a rule are normal
+func DefaultPriorityRules() []PriorityRule {
+	return []PriorityRule{
+		{Prefix: "/health", Priority: PriorityCritical},
+		{Prefix: "/auth", Priority: PriorityCritical},
+		{Prefix: "/discord/auth", Priority: PriorityCritical},
+		{Prefix: "/admin", Priority: PriorityCritical},
+		{Prefix: "/zkillboard/stats", Priority: PriorityLow},
+		{Prefix: "/zkillboard/recent", Priority: PriorityLow},
+		{Prefix: "/killmails", Priority: PriorityLow},
+	}
+}
+
+// LoadSheddingConfig sets what counts as full load. Load is the highest of the in-flight
+// requests, goroutines and memory in use relative to their limit.
+type LoadSheddingConfig struct {
+	MaxInFlight   int
+	MaxGoroutines int
+	// MemoryLimit is the container memory limit in bytes; 0 leaves memory out of the load
+	MemoryLimit int64
+	// MemoryPercent is the share of MemoryLimit that counts as full load
+	MemoryPercent int
+	// LowPriorityPercent is the load (percent of full) from which low-priority requests are shed
+	LowPriorityPercent int
+	Rules              []PriorityRule
+}
+
+// LoadSheddingConfigFromEnv reads the load shedding settings; memoryLimit comes from the
+// container cgroup detection
+func LoadSheddingConfigFromEnv(memoryLimit int64) LoadSheddingConfig {
+	rules := DefaultPriorityRules()
+	for _, entry := range config.GetLoadSheddingRules() {
+		prefix, priority, ok := strings.Cut(entry, "=")
+		rule := PriorityRule{Prefix: strings.TrimSpace(prefix), Priority: Priority(strings.TrimSpace(priority))}
+		if !ok || !strings.HasPrefix(rule.Prefix, "/") || !rule.Priority.valid() {
+			slog.Warn("Ignoring invalid LOAD_SHEDDING_RULES entry", "entry", entry)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return LoadSheddingConfig{
+		MaxInFlight:        config.GetLoadSheddingMaxInFlight(),
+		MaxGoroutines:      config.GetLoadSheddingMaxGoroutines(),
+		MemoryLimit:        memoryLimit,
+		MemoryPercent:      config.GetLoadSheddingMemoryPercent(),
+		LowPriorityPercent: config.GetLoadSheddingLowPriorityPercent(),
+		Rules:              rules,
+	}
+}
+
+func (p Priority) valid() bool {
+	switch p {
+	case PriorityCritical, PriorityNormal, PriorityLow:
+		return true
+	}
+	return false
+}
+
+// sampleInterval is how often goroutines and memory are sampled; in-flight requests are exact
+const sampleInterval = time.Second
+
+// LoadShedder rejects requests with 503 before the gateway becomes unresponsive. Goroutines and
+// memory are sampled in the background, so the per-request check is a few atomic loads.
+type LoadShedder struct {
+	config LoadSheddingConfig
+	rules  []PriorityRule
+
+	inFlight   atomic.Int64
+	goroutines atomic.Int64
+	memory     atomic.Int64
+
+	shed *prometheus.CounterVec
+	load prometheus.GaugeFunc
+}
+
+// NewLoadShedder creates a load shedder; call Start to begin sampling
+func NewLoadShedder(cfg LoadSheddingConfig) *LoadShedder {
+	byPrefix := make(map[string]Priority, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		byPrefix[strings.TrimSuffix(rule.Prefix, "/")] = rule.Priority
+	}
+
+	s := &LoadShedder{
+		config: cfg,
+		rules:  make([]PriorityRule, 0, len(byPrefix)),
+		shed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_shed_total",
+			Help: "Requests rejected with 503 by load shedding, by route priority.",
+		}, []string{"priority"}),
+	}
+	for prefix, priority := range byPrefix {
+		s.rules = append(s.rules, PriorityRule{Prefix: prefix, Priority: priority})
+	}
+	// Longest prefix wins
+	sort.Slice(s.rules, func(i, j int) bool { return len(s.rules[i].Prefix) > len(s.rules[j].Prefix) })
+
+	s.load = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "http_load_ratio",
+		Help: "Gateway load used for shedding: the highest of in-flight requests, goroutines and memory relative to their limit (1 = full).",
+	}, s.Load)
+	return s
+}
+
+// Register adds the shedding collectors to a Prometheus registry
+func (s *LoadShedder) Register(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{s.shed, s.load} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start samples goroutines and memory until the context is cancelled
+func (s *LoadShedder) Start(ctx context.Context) {
+	s.sample()
+	go func() {
+		ticker := time.NewTicker(sampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+}
+
+// memorySamples reads the memory mapped by the Go runtime minus what it returned to the OS,
+// which tracks the container's view of the process without stopping the world
+var memorySamples = []metrics.Sample{
+	{Name: "/memory/classes/total:bytes"},
+	{Name: "/memory/classes/heap/released:bytes"},
+}
+
+func (s *LoadShedder) sample() {
+	s.goroutines.Store(int64(runtime.NumGoroutine()))
+
+	samples := make([]metrics.Sample, len(memorySamples))
+	copy(samples, memorySamples)
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 && samples[1].Value.Kind() == metrics.KindUint64 {
+		s.memory.Store(int64(samples[0].Value.Uint64() - samples[1].Value.Uint64()))
+	}
+}
+
+// Load returns the current load, where 1 is full
+func (s *LoadShedder) Load() float64 {
+	load := ratio(s.inFlight.Load(), int64(s.config.MaxInFlight))
+	load = math.Max(load, ratio(s.goroutines.Load(), int64(s.config.MaxGoroutines)))
+	if s.config.MemoryLimit > 0 && s.config.MemoryPercent > 0 {
+		load = math.Max(load, ratio(s.memory.Load(), s.config.MemoryLimit*int64(s.config.MemoryPercent)/100))
+	}
+	return load
+}
+
+func ratio(value, limit int64) float64 {
+	if limit <= 0 {
+		return 0
+	}
+	return float64(value) / float64(limit)
+}
+
+// Classify returns the priority of a path (without the API prefix)
+func (s *LoadShedder) Classify(path string) Priority {
+	for _, rule := range s.rules {
+		if path == rule.Prefix || strings.HasPrefix(path, rule.Prefix+"/") {
+			return rule.Priority
+		}
+	}
+	return PriorityNormal
+}
+
+// admits reports whether a request of the given priority is served at the current load
+func (s *LoadShedder) admits(priority Priority) bool {
+	switch priority {
+	case PriorityCritical:
+		return true
+	case PriorityLow:
+		return s.Load()*100 < float64(s.config.LowPriorityPercent)
+	default:
+		return s.Load() < 1
+	}
+}
+
+// Middleware counts in-flight requests and sheds those whose priority the current load no longer
+// admits. WebSocket upgrades are long-lived and neither counted nor shed.
+func (s *LoadShedder) Middleware(apiPrefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			priority := s.Classify(strings.TrimPrefix(r.URL.Path, apiPrefix))
+			if !s.admits(priority) {
+				s.shed.WithLabelValues(string(priority)).Inc()
+				w.Header().Set("Content-Type", "application/problem+json")
+				w.Header().Set("Retry-After", "5")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				json.NewEncoder(w).Encode(map[string]any{
+					"title":  http.StatusText(http.StatusServiceUnavailable),
+					"status": http.StatusServiceUnavailable,
+					"detail": "The server is under heavy load and is temporarily not serving this request. Please retry shortly.",
+				})
+				return
+			}
+
+			s.inFlight.Add(1)
+			defer s.inFlight.Add(-1)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
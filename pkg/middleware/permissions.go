@@ -17,6 +17,12 @@ type PermissionChecker interface {
 	CheckPermission(ctx context.Context, characterID int64, permissionID string) (*permissions.PermissionCheck, error)
 }
 
+// StaffPermissionResolver resolves the permissions of staff accounts. Staff sign in through an
+// external identity provider and have no characters, so group memberships don't apply to them.
+type StaffPermissionResolver interface {
+	GetStaffPermissions(ctx context.Context, userID string) ([]string, error)
+}
+
 // PermissionMode defines how permissions are evaluated
 type PermissionMode int
 
@@ -38,6 +44,7 @@ type MiddlewareOptions struct {
 type PermissionMiddleware struct {
 	authMiddleware    *AuthMiddleware
 	permissionChecker PermissionChecker
+	staffResolver     StaffPermissionResolver
 	options           MiddlewareOptions
 }
 
@@ -58,9 +65,13 @@ func NewPermissionMiddleware(
 		opt(&options)
 	}
 
+	// The auth service validates tokens and also knows the staff accounts
+	staffResolver, _ := jwtValidator.(StaffPermissionResolver)
+
 	return &PermissionMiddleware{
 		authMiddleware:    NewAuthMiddleware(jwtValidator),
 		permissionChecker: permissionChecker,
+		staffResolver:     staffResolver,
 		options:           options,
 	}
 }
@@ -89,8 +100,28 @@ func WithoutFallback() MiddlewareOption {
 	}
 }
 
-// RequireAuth ensures the user is authenticated (permission-aware version)
+// RequireAuth ensures the user is authenticated with a character. Staff accounts are refused: they
+// only reach endpoints through the permissions listed on them (RequirePermission and friends) or
+// endpoints that opt in with RequireAuthAllowStaff.
 func (pm *PermissionMiddleware) RequireAuth(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error) {
+	user, err := pm.authenticate(ctx, authHeader, cookieHeader)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsStaff() {
+		return nil, huma.Error403Forbidden("Not available to staff accounts")
+	}
+	return user, nil
+}
+
+// RequireAuthAllowStaff ensures the user is authenticated, admitting staff accounts. Only for
+// endpoints that are safe for any signed-in identity without a character.
+func (pm *PermissionMiddleware) RequireAuthAllowStaff(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error) {
+	return pm.authenticate(ctx, authHeader, cookieHeader)
+}
+
+// authenticate validates the token of a request, whoever it belongs to
+func (pm *PermissionMiddleware) authenticate(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error) {
 	if pm.options.EnableDebugLogging {
 		slog.Debug("[Permission Middleware] Checking authentication",
 			"has_auth_header", authHeader != "",
@@ -118,11 +149,16 @@ func (pm *PermissionMiddleware) RequireAuth(ctx context.Context, authHeader, coo
 // RequireSuperAdmin ensures the user is authenticated and is a super admin
 func (pm *PermissionMiddleware) RequireSuperAdmin(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error) {
 	// First, authenticate the user
-	user, err := pm.RequireAuth(ctx, authHeader, cookieHeader)
+	user, err := pm.authenticate(ctx, authHeader, cookieHeader)
 	if err != nil {
 		return nil, err
 	}
 
+	// Staff accounts never get admin access
+	if user.IsStaff() {
+		return nil, huma.Error403Forbidden("Super admin access required")
+	}
+
 	// Check if user is admin (super admin or administrator) using the permission manager
 	if pm.permissionChecker != nil {
 		// Use the permission manager's isAdminUser method via interface
@@ -149,12 +185,16 @@ func (pm *PermissionMiddleware) RequireSuperAdmin(ctx context.Context, authHeade
 
 // RequirePermission checks if the authenticated user has a specific permission
 func (pm *PermissionMiddleware) RequirePermission(ctx context.Context, authHeader, cookieHeader, permissionID string) (*models.AuthenticatedUser, error) {
-	// First, authenticate the user
-	user, err := pm.RequireAuth(ctx, authHeader, cookieHeader)
+	// First, authenticate the user; staff are checked against their own permissions
+	user, err := pm.authenticate(ctx, authHeader, cookieHeader)
 	if err != nil {
 		return nil, err
 	}
 
+	if user.IsStaff() {
+		return pm.checkStaffPermissions(ctx, user, []string{permissionID}, PermissionModeAND)
+	}
+
 	// Check permission if permission checker is available
 	if pm.permissionChecker != nil {
 		return pm.checkUserPermission(ctx, user, permissionID)
@@ -173,8 +213,8 @@ func (pm *PermissionMiddleware) RequirePermission(ctx context.Context, authHeade
 
 // RequireAnyPermission checks if user has any of the specified permissions (OR logic)
 func (pm *PermissionMiddleware) RequireAnyPermission(ctx context.Context, authHeader, cookieHeader string, permissionIDs []string) (*models.AuthenticatedUser, error) {
-	// First, authenticate the user
-	user, err := pm.RequireAuth(ctx, authHeader, cookieHeader)
+	// First, authenticate the user; staff are checked against their own permissions
+	user, err := pm.authenticate(ctx, authHeader, cookieHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -185,6 +225,10 @@ func (pm *PermissionMiddleware) RequireAnyPermission(ctx context.Context, authHe
 			"permissions", permissionIDs)
 	}
 
+	if user.IsStaff() {
+		return pm.checkStaffPermissions(ctx, user, permissionIDs, PermissionModeOR)
+	}
+
 	// Check permissions if permission checker is available
 	if pm.permissionChecker != nil {
 		return pm.checkUserAnyPermission(ctx, user, permissionIDs)
@@ -203,8 +247,8 @@ func (pm *PermissionMiddleware) RequireAnyPermission(ctx context.Context, authHe
 
 // RequireAllPermissions checks if user has all specified permissions (AND logic)
 func (pm *PermissionMiddleware) RequireAllPermissions(ctx context.Context, authHeader, cookieHeader string, permissionIDs []string) (*models.AuthenticatedUser, error) {
-	// First, authenticate the user
-	user, err := pm.RequireAuth(ctx, authHeader, cookieHeader)
+	// First, authenticate the user; staff are checked against their own permissions
+	user, err := pm.authenticate(ctx, authHeader, cookieHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -215,6 +259,10 @@ func (pm *PermissionMiddleware) RequireAllPermissions(ctx context.Context, authH
 			"permissions", permissionIDs)
 	}
 
+	if user.IsStaff() {
+		return pm.checkStaffPermissions(ctx, user, permissionIDs, PermissionModeAND)
+	}
+
 	// Check permissions if permission checker is available
 	if pm.permissionChecker != nil {
 		return pm.checkUserAllPermissions(ctx, user, permissionIDs)
@@ -352,6 +400,47 @@ func (pm *PermissionMiddleware) checkUserAllPermissions(ctx context.Context, use
 	return user, nil
 }
 
+// checkStaffPermissions checks permissions against the list held by a staff account. Staff have
+// no admin bypass and are denied when their account can't be resolved.
+func (pm *PermissionMiddleware) checkStaffPermissions(ctx context.Context, user *models.AuthenticatedUser, permissionIDs []string, mode PermissionMode) (*models.AuthenticatedUser, error) {
+	if pm.staffResolver == nil {
+		return nil, huma.Error403Forbidden("Staff accounts are not supported")
+	}
+
+	held, err := pm.staffResolver.GetStaffPermissions(ctx, user.UserID)
+	if err != nil {
+		slog.Error("[Permission Middleware] Staff permission lookup failed",
+			"error", err,
+			"user_id", user.UserID)
+		return nil, huma.Error500InternalServerError("Permission check failed")
+	}
+
+	granted := make(map[string]bool, len(held))
+	for _, permissionID := range held {
+		granted[permissionID] = true
+	}
+
+	for _, permissionID := range permissionIDs {
+		if granted[permissionID] && mode == PermissionModeOR {
+			return user, nil
+		}
+		if !granted[permissionID] && mode == PermissionModeAND {
+			if pm.options.EnableDebugLogging {
+				slog.Debug("[Permission Middleware] Staff permission denied",
+					"user_id", user.UserID,
+					"provider", user.Provider,
+					"permission", permissionID)
+			}
+			return nil, huma.Error403Forbidden(fmt.Sprintf("Permission denied: %s required", permissionID))
+		}
+	}
+
+	if mode == PermissionModeOR && len(permissionIDs) > 0 {
+		return nil, huma.Error403Forbidden(fmt.Sprintf("Permission denied: one of %v required", permissionIDs))
+	}
+	return user, nil
+}
+
 // GetAuthMiddleware returns the underlying auth middleware for advanced use cases
 func (pm *PermissionMiddleware) GetAuthMiddleware() *AuthMiddleware {
 	return pm.authMiddleware
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-falcon/pkg/identity"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitSource returns the API requests per minute a user may make, 0 for no limit; implemented
+// by the groups service from the rate limit policies of the user's groups
+type RateLimitSource interface {
+	UserRateLimit(ctx context.Context, userID string) (int, error)
+}
+
+const (
+	// Redis key prefix of the per-user request counters, one key per minute
+	rateLimitKeyPrefix = "rate_limit:"
+
+	// rateLimitPolicyTTL is how long a user's limit is reused before it is looked up again, so a
+	// changed policy or membership applies within a minute
+	rateLimitPolicyTTL = time.Minute
+)
+
+type cachedRateLimit struct {
+	limit     int
+	expiresAt time.Time
+}
+
+// RateLimiter throttles authenticated users whose groups carry a rate limit policy. Requests are
+// counted per user in fixed one-minute windows in Redis, so every gateway instance shares the
+// count. Users without a policy, anonymous requests and requests Redis cannot count pass.
+type RateLimiter struct {
+	redis  *redis.Client
+	source RateLimitSource
+
+	mu     sync.Mutex
+	limits map[string]cachedRateLimit
+}
+
+// NewRateLimiter creates a rate limiter reading policies from source
+func NewRateLimiter(client *redis.Client, source RateLimitSource) *RateLimiter {
+	return &RateLimiter{
+		redis:  client,
+		source: source,
+		limits: make(map[string]cachedRateLimit),
+	}
+}
+
+// Middleware rejects requests over the caller's limit with 429 and a Retry-After header. It must
+// run after IdentityMiddleware, which resolves the caller.
+func (l *RateLimiter) Middleware(api huma.API) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		id := identity.FromContext(ctx.Context())
+		if id == nil || id.IsSystem() || id.Actor.UserID == "" {
+			next(ctx)
+			return
+		}
+
+		limit := l.userLimit(ctx.Context(), id.Actor.UserID)
+		if limit <= 0 {
+			next(ctx)
+			return
+		}
+
+		now := time.Now()
+		window := now.Truncate(time.Minute)
+		count, err := l.count(ctx.Context(), id.Actor.UserID, window)
+		if err != nil {
+			slog.Debug("Rate limit counter unavailable", "user_id", id.Actor.UserID, "error", err)
+			next(ctx)
+			return
+		}
+
+		ctx.SetHeader("X-RateLimit-Limit", strconv.Itoa(limit))
+		ctx.SetHeader("X-RateLimit-Remaining", strconv.FormatInt(max(int64(limit)-count, 0), 10))
+		if count > int64(limit) {
+			retryAfter := int(window.Add(time.Minute).Sub(now).Seconds()) + 1
+			ctx.SetHeader("Retry-After", strconv.Itoa(retryAfter))
+			huma.WriteErr(api, ctx, http.StatusTooManyRequests,
+				fmt.Sprintf("Rate limit of %d requests per minute exceeded", limit))
+			return
+		}
+		next(ctx)
+	}
+}
+
+// count increments the user's counter of the window and returns it
+func (l *RateLimiter) count(ctx context.Context, userID string, window time.Time) (int64, error) {
+	key := fmt.Sprintf("%s%s:%d", rateLimitKeyPrefix, userID, window.Unix())
+	pipe := l.redis.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 2*time.Minute)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// userLimit returns the cached limit of a user, looking it up when missing or stale. A failed
+// lookup leaves the user unlimited until the next one.
+func (l *RateLimiter) userLimit(ctx context.Context, userID string) int {
+	now := time.Now()
+	l.mu.Lock()
+	cached, ok := l.limits[userID]
+	l.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.limit
+	}
+
+	limit, err := l.source.UserRateLimit(ctx, userID)
+	if err != nil {
+		slog.Warn("Failed to look up rate limit", "user_id", userID, "error", err)
+		limit = 0
+	}
+
+	l.mu.Lock()
+	if len(l.limits) > 10000 {
+		for key, entry := range l.limits {
+			if now.After(entry.expiresAt) {
+				delete(l.limits, key)
+			}
+		}
+	}
+	l.limits[userID] = cachedRateLimit{limit: limit, expiresAt: now.Add(rateLimitPolicyTTL)}
+	l.mu.Unlock()
+	return limit
+}
@@ -2,7 +2,11 @@ package migrations
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/user"
 	"strings"
 	"time"
 
@@ -13,12 +17,17 @@ import (
 
 // Migration represents a database migration
 type Migration struct {
-	Version     string    `bson:"version"`     // e.g., "001_create_groups_indexes"
-	Description string    `bson:"description"` // Human-readable description
-	AppliedAt   time.Time `bson:"applied_at"`  // When the migration was applied
-	Checksum    string    `bson:"checksum"`    // SHA256 of migration content for integrity
+	Version     string    `bson:"version"`              // e.g., "001_create_groups_indexes"
+	Description string    `bson:"description"`          // Human-readable description
+	AppliedAt   time.Time `bson:"applied_at"`           // When the migration was applied
+	Checksum    string    `bson:"checksum"`             // SHA256 of migration content for integrity
+	AppliedBy   string    `bson:"applied_by,omitempty"` // Operator or CI job that applied it
+	Host        string    `bson:"host,omitempty"`       // Host the runner ran on
 }
 
+// ErrIrreversible is returned when a rollback would run an irreversible migration without force
+var ErrIrreversible = errors.New("refusing to roll back irreversible migrations")
+
 // MigrationFunc defines a migration function signature
 type MigrationFunc func(ctx context.Context, db *mongo.Database) error
 
@@ -28,13 +37,73 @@ type RegisteredMigration struct {
 	Description string
 	Up          MigrationFunc // Apply migration
 	Down        MigrationFunc // Rollback migration (optional)
+	// Irreversible marks a migration whose Down loses data written since it was applied (e.g. it
+	// deletes seeded documents admins may have edited); rolling it back requires force
+	Irreversible bool
+	Impact       Impact
+}
+
+// Impact estimates what applying a migration costs, so the runner can warn before it runs
+// against large collections
+type Impact struct {
+	Collections    []string `json:"collections,omitempty"`     // Collections the migration reads or writes
+	IndexBuilds    int      `json:"index_builds,omitempty"`    // Indexes created; each build reads its whole collection
+	CollectionScan bool     `json:"collection_scan,omitempty"` // Reads or rewrites every document outside of index builds
+}
+
+// Reversible reports whether the migration can be rolled back without force
+func (m RegisteredMigration) Reversible() bool {
+	return m.Down != nil && !m.Irreversible
 }
 
+// DefaultLargeCollectionThreshold is the estimated document count from which impact warnings are printed
+const DefaultLargeCollectionThreshold = 100000
+
 // Runner manages database migrations
 type Runner struct {
 	db         *mongo.Database
 	collection *mongo.Collection
 	migrations []RegisteredMigration
+	out        io.Writer
+	appliedBy  string
+	host       string
+	// Collections with at least this many estimated documents trigger impact warnings
+	largeCollectionThreshold int64
+}
+
+// MigrationStatus is the state of one registered migration
+type MigrationStatus struct {
+	Version     string     `json:"version"`
+	Description string     `json:"description"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+	AppliedBy   string     `json:"applied_by,omitempty"`
+	Host        string     `json:"host,omitempty"`
+	Reversible  bool       `json:"reversible"`
+	Impact      Impact     `json:"impact"`
+	// Warnings lists the large collections a pending migration will scan or index
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// StatusReport lists registered migrations in order with their applied state
+type StatusReport struct {
+	Migrations []MigrationStatus `json:"migrations"`
+	Total      int               `json:"total"`
+	Applied    int               `json:"applied"`
+	Pending    int               `json:"pending"`
+	// Unknown lists versions recorded as applied that no registered migration matches
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+// PendingVersions returns the versions of migrations not applied yet
+func (s *StatusReport) PendingVersions() []string {
+	pending := make([]string, 0, s.Pending)
+	for _, m := range s.Migrations {
+		if !m.Applied {
+			pending = append(pending, m.Version)
+		}
+	}
+	return pending
 }
 
 // NewRunner creates a new migration runner
@@ -43,9 +112,31 @@ func NewRunner(db *mongo.Database) *Runner {
 		db:         db,
 		collection: db.Collection("_migrations"),
 		migrations: make([]RegisteredMigration, 0),
+		out:        os.Stdout,
+		appliedBy:  defaultAppliedBy(),
+		host:       defaultHost(),
+
+		largeCollectionThreshold: DefaultLargeCollectionThreshold,
+	}
+}
+
+// SetAppliedBy overrides who is recorded as applying migrations (defaults to the OS user)
+func (r *Runner) SetAppliedBy(appliedBy string) {
+	if appliedBy != "" {
+		r.appliedBy = appliedBy
 	}
 }
 
+// SetLargeCollectionThreshold sets the estimated document count from which impact warnings are printed
+func (r *Runner) SetLargeCollectionThreshold(documents int64) {
+	r.largeCollectionThreshold = documents
+}
+
+// SetOutput redirects the runner's progress messages, e.g. to stderr when stdout carries JSON
+func (r *Runner) SetOutput(w io.Writer) {
+	r.out = w
+}
+
 // Register adds a migration to the runner
 func (r *Runner) Register(migration RegisteredMigration) {
 	r.migrations = append(r.migrations, migration)
@@ -76,7 +167,10 @@ func (r *Runner) Run(ctx context.Context) error {
 			continue // Skip already applied
 		}
 
-		fmt.Printf("🔄 Running migration: %s - %s\n", migration.Version, migration.Description)
+		fmt.Fprintf(r.out, "🔄 Running migration: %s - %s\n", migration.Version, migration.Description)
+		for _, warning := range r.impactWarnings(ctx, migration) {
+			fmt.Fprintf(r.out, "⚠️  %s\n", warning)
+		}
 
 		// Start transaction for atomicity
 		session, err := r.db.Client().StartSession()
@@ -97,6 +191,8 @@ func (r *Runner) Run(ctx context.Context) error {
 				Description: migration.Description,
 				AppliedAt:   time.Now(),
 				Checksum:    calculateChecksum(migration),
+				AppliedBy:   r.appliedBy,
+				Host:        r.host,
 			}
 
 			if _, err := r.collection.InsertOne(sc, migrationRecord); err != nil {
@@ -110,14 +206,16 @@ func (r *Runner) Run(ctx context.Context) error {
 			return err
 		}
 
-		fmt.Printf("✅ Migration %s completed successfully\n", migration.Version)
+		fmt.Fprintf(r.out, "✅ Migration %s completed successfully\n", migration.Version)
 	}
 
 	return nil
 }
 
-// Rollback rolls back the last n migrations
-func (r *Runner) Rollback(ctx context.Context, steps int) error {
+// Rollback rolls back the last n migrations. Unless force is set, it refuses before touching
+// anything when one of them is irreversible or has no rollback function; with force,
+// irreversible migrations are rolled back and ones without a rollback function are skipped.
+func (r *Runner) Rollback(ctx context.Context, steps int, force bool) error {
 	// Get applied migrations in reverse order
 	applied, err := r.getAppliedMigrations(ctx)
 	if err != nil {
@@ -135,20 +233,35 @@ func (r *Runner) Rollback(ctx context.Context, steps int) error {
 		migrationMap[m.Version] = m
 	}
 
-	// Rollback migrations
+	var blocked []string
 	for i := len(applied) - 1; i >= len(applied)-steps; i-- {
 		version := applied[i].Version
 		migration, exists := migrationMap[version]
 		if !exists {
 			return fmt.Errorf("migration %s not found in registered migrations", version)
 		}
+		if !migration.Reversible() {
+			blocked = append(blocked, version)
+		}
+	}
+	if len(blocked) > 0 && !force {
+		return fmt.Errorf("%w: %s (use force to roll back anyway)", ErrIrreversible, strings.Join(blocked, ", "))
+	}
+
+	// Rollback migrations
+	for i := len(applied) - 1; i >= len(applied)-steps; i-- {
+		version := applied[i].Version
+		migration := migrationMap[version]
 
 		if migration.Down == nil {
-			fmt.Printf("⚠️  Migration %s has no rollback function, skipping\n", version)
+			fmt.Fprintf(r.out, "⚠️  Migration %s has no rollback function, skipping\n", version)
 			continue
 		}
+		if migration.Irreversible {
+			fmt.Fprintf(r.out, "⚠️  Forcing rollback of irreversible migration %s; data written since it was applied may be lost\n", version)
+		}
 
-		fmt.Printf("🔄 Rolling back migration: %s\n", version)
+		fmt.Fprintf(r.out, "🔄 Rolling back migration: %s\n", version)
 
 		// Start transaction
 		session, err := r.db.Client().StartSession()
@@ -175,17 +288,17 @@ func (r *Runner) Rollback(ctx context.Context, steps int) error {
 			return err
 		}
 
-		fmt.Printf("✅ Rollback %s completed successfully\n", version)
+		fmt.Fprintf(r.out, "✅ Rollback %s completed successfully\n", version)
 	}
 
 	return nil
 }
 
-// Status shows the current migration status
-func (r *Runner) Status(ctx context.Context) error {
+// GetStatus returns the applied state of every registered migration
+func (r *Runner) GetStatus(ctx context.Context) (*StatusReport, error) {
 	applied, err := r.getAppliedMigrations(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get applied migrations: %w", err)
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
 	}
 
 	appliedMap := make(map[string]Migration)
@@ -193,27 +306,120 @@ func (r *Runner) Status(ctx context.Context) error {
 		appliedMap[m.Version] = m
 	}
 
-	fmt.Println("\n📊 Migration Status:")
-	fmt.Println(strings.Repeat("=", 80))
-
+	report := &StatusReport{
+		Migrations: make([]MigrationStatus, 0, len(r.migrations)),
+		Total:      len(r.migrations),
+	}
+	registered := make(map[string]bool, len(r.migrations))
 	for _, migration := range r.migrations {
+		registered[migration.Version] = true
+		status := MigrationStatus{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Reversible:  migration.Reversible(),
+			Impact:      migration.Impact,
+		}
+		if record, exists := appliedMap[migration.Version]; exists {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.AppliedBy = record.AppliedBy
+			status.Host = record.Host
+			report.Applied++
+		} else {
+			status.Warnings = r.impactWarnings(ctx, migration)
+			report.Pending++
+		}
+		report.Migrations = append(report.Migrations, status)
+	}
+	for _, m := range applied {
+		if !registered[m.Version] {
+			report.Unknown = append(report.Unknown, m.Version)
+		}
+	}
+
+	return report, nil
+}
+
+// Status shows the current migration status
+func (r *Runner) Status(ctx context.Context) error {
+	report, err := r.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(r.out, "\n📊 Migration Status:")
+	fmt.Fprintln(r.out, strings.Repeat("=", 80))
+
+	for _, migration := range report.Migrations {
 		status := "⏳ Pending"
 		appliedAt := ""
 
-		if applied, exists := appliedMap[migration.Version]; exists {
+		if migration.Applied {
 			status = "✅ Applied"
-			appliedAt = fmt.Sprintf(" (at %s)", applied.AppliedAt.Format("2006-01-02 15:04:05"))
+			appliedAt = fmt.Sprintf(" (at %s)", migration.AppliedAt.Format("2006-01-02 15:04:05"))
 		}
 
-		fmt.Printf("%s %s - %s%s\n", status, migration.Version, migration.Description, appliedAt)
+		if migration.Applied && migration.AppliedBy != "" {
+			appliedAt = fmt.Sprintf(" (at %s by %s@%s)", migration.AppliedAt.Format("2006-01-02 15:04:05"), migration.AppliedBy, migration.Host)
+		}
+		if !migration.Reversible {
+			appliedAt += " [irreversible]"
+		}
+
+		fmt.Fprintf(r.out, "%s %s - %s%s\n", status, migration.Version, migration.Description, appliedAt)
+		for _, warning := range migration.Warnings {
+			fmt.Fprintf(r.out, "   ⚠️  %s\n", warning)
+		}
+	}
+	for _, version := range report.Unknown {
+		fmt.Fprintf(r.out, "❓ Unknown %s - applied but not registered\n", version)
 	}
 
-	fmt.Printf("\nTotal: %d migrations (%d applied, %d pending)\n",
-		len(r.migrations), len(applied), len(r.migrations)-len(applied))
+	fmt.Fprintf(r.out, "\nTotal: %d migrations (%d applied, %d pending)\n",
+		report.Total, report.Applied, report.Pending)
 
 	return nil
 }
 
+// impactWarnings describes the collections at or above the large-collection threshold that a
+// migration will index or scan. Counts are estimates from collection metadata, so this is cheap.
+func (r *Runner) impactWarnings(ctx context.Context, migration RegisteredMigration) []string {
+	impact := migration.Impact
+	if impact.IndexBuilds == 0 && !impact.CollectionScan {
+		return nil
+	}
+
+	var warnings []string
+	for _, name := range impact.Collections {
+		count, err := r.db.Collection(name).EstimatedDocumentCount(ctx)
+		if err != nil || count < r.largeCollectionThreshold {
+			continue
+		}
+		if impact.IndexBuilds > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s builds %d index(es) on %s (~%d documents)", migration.Version, impact.IndexBuilds, name, count))
+		}
+		if impact.CollectionScan {
+			warnings = append(warnings, fmt.Sprintf("%s scans every document of %s (~%d documents)", migration.Version, name, count))
+		}
+	}
+	return warnings
+}
+
+// defaultAppliedBy returns the OS user running the migrations
+func defaultAppliedBy() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
+
+// defaultHost returns the host name of the machine running the migrations
+func defaultHost() string {
+	host, _ := os.Hostname()
+	return host
+}
+
 // ensureMigrationsIndex creates an index on the migrations collection
 func (r *Runner) ensureMigrationsIndex(ctx context.Context) error {
 	indexModel := mongo.IndexModel{
@@ -0,0 +1,143 @@
+package permissions
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// decisionKeyPrefix prefixes cached HasPermission results; keys also carry the generation
+	decisionKeyPrefix = "permissions:decision:"
+	// decisionGenerationKey is bumped on every change that can alter a decision, which orphans
+	// all cached results at once on every instance
+	decisionGenerationKey = "permissions:decision:generation"
+	// decisionCacheTimeout bounds a cache lookup; a slow Redis falls back to MongoDB
+	decisionCacheTimeout = 100 * time.Millisecond
+)
+
+// decisionCache keeps HasPermission results in Redis. Counters are per instance.
+type decisionCache struct {
+	client *redis.Client
+	ttl    time.Duration
+	since  time.Time
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+	errors        atomic.Int64
+}
+
+// CacheStats reports how well the permission decision cache of this instance performs
+type CacheStats struct {
+	Enabled       bool      `json:"enabled" doc:"Whether decisions are cached"`
+	TTLSeconds    int       `json:"ttl_seconds" doc:"How long a decision is kept at most"`
+	Generation    int64     `json:"generation" doc:"Current cache generation, bumped by every invalidation on any instance"`
+	Hits          int64     `json:"hits" doc:"Checks answered from the cache"`
+	Misses        int64     `json:"misses" doc:"Checks that went to MongoDB"`
+	HitRate       float64   `json:"hit_rate" doc:"Hits divided by all cached checks"`
+	Invalidations int64     `json:"invalidations" doc:"Invalidations made by this instance"`
+	Errors        int64     `json:"errors" doc:"Failed Redis operations; the check fell back to MongoDB"`
+	Since         time.Time `json:"since" doc:"When the counters started"`
+}
+
+// EnableDecisionCache caches HasPermission results in Redis for up to ttl. Grants, revocations
+// and membership changes invalidate them through InvalidateDecisions; the ttl bounds anything
+// else, such as a character moving to another account.
+func (pm *PermissionManager) EnableDecisionCache(client *redis.Client, ttl time.Duration) {
+	if client == nil || ttl <= 0 {
+		return
+	}
+	pm.cache = &decisionCache{client: client, ttl: ttl, since: time.Now()}
+}
+
+// InvalidateDecisions drops every cached decision, on all instances
+func (pm *PermissionManager) InvalidateDecisions(ctx context.Context) {
+	if pm.cache == nil {
+		return
+	}
+	if err := pm.cache.client.Incr(ctx, decisionGenerationKey).Err(); err != nil {
+		pm.cache.errors.Add(1)
+		slog.Error("[Permissions] Failed to invalidate decision cache", "error", err)
+		return
+	}
+	pm.cache.invalidations.Add(1)
+}
+
+// CacheStats returns the decision cache counters of this instance
+func (pm *PermissionManager) CacheStats(ctx context.Context) CacheStats {
+	if pm.cache == nil {
+		return CacheStats{}
+	}
+
+	stats := CacheStats{
+		Enabled:       true,
+		TTLSeconds:    int(pm.cache.ttl.Seconds()),
+		Hits:          pm.cache.hits.Load(),
+		Misses:        pm.cache.misses.Load(),
+		Invalidations: pm.cache.invalidations.Load(),
+		Errors:        pm.cache.errors.Load(),
+		Since:         pm.cache.since,
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	stats.Generation, _ = pm.cache.generation(ctx)
+	return stats
+}
+
+// generation returns the current cache generation; a missing key is generation 0
+func (c *decisionCache) generation(ctx context.Context) (int64, error) {
+	generation, err := c.client.Get(ctx, decisionGenerationKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return generation, err
+}
+
+// lookup returns the cached decision and the key to store a fresh one under. The key is empty
+// when Redis is unavailable, so nothing gets stored.
+func (c *decisionCache) lookup(ctx context.Context, characterID int64, permissionID string) (granted, found bool, key string) {
+	ctx, cancel := context.WithTimeout(ctx, decisionCacheTimeout)
+	defer cancel()
+
+	generation, err := c.generation(ctx)
+	if err != nil {
+		c.errors.Add(1)
+		return false, false, ""
+	}
+	key = decisionKeyPrefix + strconv.FormatInt(generation, 10) + ":" + strconv.FormatInt(characterID, 10) + ":" + permissionID
+
+	value, err := c.client.Get(ctx, key).Result()
+	switch {
+	case err == redis.Nil:
+		c.misses.Add(1)
+		return false, false, key
+	case err != nil:
+		c.errors.Add(1)
+		return false, false, ""
+	}
+	c.hits.Add(1)
+	return value == "1", true, key
+}
+
+// store caches a decision under a key returned by lookup
+func (c *decisionCache) store(ctx context.Context, key string, granted bool) {
+	if key == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, decisionCacheTimeout)
+	defer cancel()
+
+	value := "0"
+	if granted {
+		value = "1"
+	}
+	if err := c.client.Set(ctx, key, value, c.ttl).Err(); err != nil {
+		c.errors.Add(1)
+	}
+}
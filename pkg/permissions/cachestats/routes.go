@@ -0,0 +1,51 @@
+// Package cachestats serves the statistics of the permission decision cache
+package cachestats
+
+import (
+	"context"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/apidocs"
+	"go-falcon/pkg/permissions"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// SuperAdminChecker authorizes access to the decision cache statistics
+type SuperAdminChecker interface {
+	RequireSuperAdmin(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error)
+}
+
+// CacheStatsInput is the input for the decision cache statistics endpoint
+type CacheStatsInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// CacheStatsOutput wraps the decision cache statistics
+type CacheStatsOutput struct {
+	Body permissions.CacheStats
+}
+
+// RegisterRoutes registers the decision cache statistics endpoint (super admin only)
+func RegisterRoutes(api huma.API, path string, pm *permissions.PermissionManager, auth SuperAdminChecker) {
+	huma.Register(api, huma.Operation{
+		OperationID: "admin-get-permission-cache-stats",
+		Method:      "GET",
+		Path:        path,
+		Summary:     "Get permission cache stats",
+		Description: "Returns the hit rate of the permission decision cache on the answering instance since startup, with invalidations and Redis errors. Requires super admin access.",
+		Tags:        []string{"Health"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
+		Security: []map[string][]string{
+			{"bearerAuth": {}},
+			{"cookieAuth": {}},
+		},
+	}, func(ctx context.Context, input *CacheStatsInput) (*CacheStatsOutput, error) {
+		if _, err := auth.RequireSuperAdmin(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+
+		return &CacheStatsOutput{Body: pm.CacheStats(ctx)}, nil
+	})
+}
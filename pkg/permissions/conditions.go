@@ -0,0 +1,182 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Attributes a permission condition can test. Affiliation and security status come from the
+// character's profile; the time attributes are read at check time.
+const (
+	ConditionCorporationID  = "corporation_id"
+	ConditionAllianceID     = "alliance_id"
+	ConditionSecurityStatus = "security_status"
+	ConditionHourUTC        = "hour_utc"    // 0-23
+	ConditionWeekdayUTC     = "weekday_utc" // 0 (Sunday) to 6
+)
+
+// Operators of permission conditions
+const (
+	ConditionEq    = "eq"
+	ConditionNe    = "ne"
+	ConditionGt    = "gt"
+	ConditionGte   = "gte"
+	ConditionLt    = "lt"
+	ConditionLte   = "lte"
+	ConditionIn    = "in"
+	ConditionNotIn = "not_in"
+)
+
+// maxConditionValues bounds the values of in and not_in conditions
+const maxConditionValues = 100
+
+// PermissionCondition restricts a grant to characters whose attribute compares to the values,
+// e.g. {security_status gt [0]} or {hour_utc in [18 19 20 21]}. Comparison operators take one
+// value; in and not_in take a list.
+type PermissionCondition struct {
+	Attribute string    `json:"attribute" bson:"attribute"`
+	Operator  string    `json:"operator" bson:"operator"`
+	Values    []float64 `json:"values" bson:"values"`
+}
+
+// ValidateConditions rejects conditions on unknown attributes, with unknown operators or with the
+// wrong number of values
+func ValidateConditions(conditions []PermissionCondition) error {
+	for _, condition := range conditions {
+		switch condition.Attribute {
+		case ConditionCorporationID, ConditionAllianceID, ConditionSecurityStatus, ConditionHourUTC, ConditionWeekdayUTC:
+		default:
+			return fmt.Errorf("unknown condition attribute: %s", condition.Attribute)
+		}
+		switch condition.Operator {
+		case ConditionEq, ConditionNe, ConditionGt, ConditionGte, ConditionLt, ConditionLte:
+			if len(condition.Values) != 1 {
+				return fmt.Errorf("condition %s %s takes exactly one value", condition.Attribute, condition.Operator)
+			}
+		case ConditionIn, ConditionNotIn:
+			if len(condition.Values) == 0 || len(condition.Values) > maxConditionValues {
+				return fmt.Errorf("condition %s %s takes 1 to %d values", condition.Attribute, condition.Operator, maxConditionValues)
+			}
+		default:
+			return fmt.Errorf("unknown condition operator: %s", condition.Operator)
+		}
+	}
+	return nil
+}
+
+// String renders a condition for check results and logs, e.g. "security_status gt 0"
+func (c PermissionCondition) String() string {
+	values := make([]string, len(c.Values))
+	for i, value := range c.Values {
+		values[i] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	if c.Operator == ConditionIn || c.Operator == ConditionNotIn {
+		return fmt.Sprintf("%s %s [%s]", c.Attribute, c.Operator, strings.Join(values, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", c.Attribute, c.Operator, strings.Join(values, ", "))
+}
+
+// holds evaluates the condition against an attribute value
+func (c PermissionCondition) holds(value float64) bool {
+	switch c.Operator {
+	case ConditionIn, ConditionNotIn:
+		found := false
+		for _, candidate := range c.Values {
+			if candidate == value {
+				found = true
+				break
+			}
+		}
+		return found == (c.Operator == ConditionIn)
+	}
+	if len(c.Values) != 1 {
+		return false
+	}
+
+	switch c.Operator {
+	case ConditionEq:
+		return value == c.Values[0]
+	case ConditionNe:
+		return value != c.Values[0]
+	case ConditionGt:
+		return value > c.Values[0]
+	case ConditionGte:
+		return value >= c.Values[0]
+	case ConditionLt:
+		return value < c.Values[0]
+	case ConditionLte:
+		return value <= c.Values[0]
+	}
+	return false
+}
+
+// characterAttributes are the values conditions are evaluated against, loaded at most once per check
+type characterAttributes struct {
+	pm          *PermissionManager
+	characterID int64
+	now         time.Time
+	profile     map[string]float64
+}
+
+func (pm *PermissionManager) newCharacterAttributes(characterID int64) *characterAttributes {
+	return &characterAttributes{pm: pm, characterID: characterID, now: time.Now().UTC()}
+}
+
+// unmet returns the conditions that do not hold for the character, none when the grant applies
+func (a *characterAttributes) unmet(ctx context.Context, conditions []PermissionCondition) ([]PermissionCondition, error) {
+	var unmet []PermissionCondition
+	for _, condition := range conditions {
+		value, err := a.value(ctx, condition.Attribute)
+		if err != nil {
+			return nil, err
+		}
+		if !condition.holds(value) {
+			unmet = append(unmet, condition)
+		}
+	}
+	return unmet, nil
+}
+
+func (a *characterAttributes) value(ctx context.Context, attribute string) (float64, error) {
+	switch attribute {
+	case ConditionHourUTC:
+		return float64(a.now.Hour()), nil
+	case ConditionWeekdayUTC:
+		return float64(a.now.Weekday()), nil
+	}
+
+	if a.profile == nil {
+		var profile struct {
+			CorporationID  int64   `bson:"corporation_id"`
+			AllianceID     int64   `bson:"alliance_id"`
+			SecurityStatus float64 `bson:"security_status"`
+		}
+		err := a.pm.db.Collection("user_profiles").FindOne(ctx, bson.M{"character_id": a.characterID},
+			options.FindOne().SetProjection(bson.M{"corporation_id": 1, "alliance_id": 1, "security_status": 1})).Decode(&profile)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return 0, fmt.Errorf("failed to get character attributes: %w", err)
+		}
+		a.profile = map[string]float64{
+			ConditionCorporationID:  float64(profile.CorporationID),
+			ConditionAllianceID:     float64(profile.AllianceID),
+			ConditionSecurityStatus: profile.SecurityStatus,
+		}
+	}
+	return a.profile[attribute], nil
+}
+
+// ConditionsHold reports whether every condition holds for a character now
+func (pm *PermissionManager) ConditionsHold(ctx context.Context, characterID int64, conditions []PermissionCondition) (bool, error) {
+	if len(conditions) == 0 {
+		return true, nil
+	}
+	unmet, err := pm.newCharacterAttributes(characterID).unmet(ctx, conditions)
+	return len(unmet) == 0, err
+}
@@ -0,0 +1,163 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Subjects a permission can be denied to
+const (
+	DenySubjectCharacter   = "character"
+	DenySubjectCorporation = "corporation"
+	DenySubjectAlliance    = "alliance"
+)
+
+// ErrDenialExists is returned when the permission is already denied to the subject
+var ErrDenialExists = errors.New("permission already denied to this subject")
+
+// PermissionDenial withholds a permission from a character, or from every character of a
+// corporation or alliance, whatever their groups grant. Deny wins over allow; only the admin
+// bypass of Super Administrator and Administrator members ignores denials.
+type PermissionDenial struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	PermissionID string             `json:"permission_id" bson:"permission_id"`
+	SubjectType  string             `json:"subject_type" bson:"subject_type"` // character, corporation or alliance
+	SubjectID    int64              `json:"subject_id" bson:"subject_id"`
+	Reason       string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	CreatedBy    int64              `json:"created_by" bson:"created_by"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// CreateDenial stores a denial; cached decisions are dropped
+func (pm *PermissionManager) CreateDenial(ctx context.Context, denial *PermissionDenial) error {
+	if !pm.permissionExists(denial.PermissionID) {
+		return fmt.Errorf("permission not found: %s", denial.PermissionID)
+	}
+	switch denial.SubjectType {
+	case DenySubjectCharacter, DenySubjectCorporation, DenySubjectAlliance:
+	default:
+		return fmt.Errorf("invalid subject type: %s", denial.SubjectType)
+	}
+
+	denial.CreatedAt = time.Now()
+	result, err := pm.denialsCollection.InsertOne(ctx, denial)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDenialExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create permission denial: %w", err)
+	}
+	denial.ID = result.InsertedID.(primitive.ObjectID)
+
+	pm.InvalidateDecisions(ctx)
+
+	slog.Info("[Permissions] Denied permission",
+		"permission_id", denial.PermissionID,
+		"subject_type", denial.SubjectType,
+		"subject_id", denial.SubjectID,
+		"created_by", denial.CreatedBy)
+	return nil
+}
+
+// ListDenials returns the denials, optionally of one permission, newest first
+func (pm *PermissionManager) ListDenials(ctx context.Context, permissionID string) ([]PermissionDenial, error) {
+	filter := bson.M{}
+	if permissionID != "" {
+		filter["permission_id"] = permissionID
+	}
+
+	cursor, err := pm.denialsCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permission denials: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	denials := []PermissionDenial{}
+	if err := cursor.All(ctx, &denials); err != nil {
+		return nil, fmt.Errorf("failed to decode permission denials: %w", err)
+	}
+	return denials, nil
+}
+
+// DeleteDenial removes a denial and returns it, or nil when it does not exist
+func (pm *PermissionManager) DeleteDenial(ctx context.Context, id primitive.ObjectID) (*PermissionDenial, error) {
+	var denial PermissionDenial
+	err := pm.denialsCollection.FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&denial)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete permission denial: %w", err)
+	}
+
+	pm.InvalidateDecisions(ctx)
+
+	slog.Info("[Permissions] Removed permission denial",
+		"permission_id", denial.PermissionID,
+		"subject_type", denial.SubjectType,
+		"subject_id", denial.SubjectID)
+	return &denial, nil
+}
+
+// DenialsForCharacter returns the denials that apply to a character directly or through its
+// corporation or alliance
+func (pm *PermissionManager) DenialsForCharacter(ctx context.Context, characterID int64) ([]PermissionDenial, error) {
+	return pm.findDenials(ctx, characterID, bson.M{})
+}
+
+// findDenial returns a denial of the permission that applies to the character, or nil
+func (pm *PermissionManager) findDenial(ctx context.Context, characterID int64, permissionID string) (*PermissionDenial, error) {
+	denials, err := pm.findDenials(ctx, characterID, bson.M{"permission_id": permissionID})
+	if err != nil || len(denials) == 0 {
+		return nil, err
+	}
+	return &denials[0], nil
+}
+
+func (pm *PermissionManager) findDenials(ctx context.Context, characterID int64, filter bson.M) ([]PermissionDenial, error) {
+	subjects := []bson.M{
+		{"subject_type": DenySubjectCharacter, "subject_id": characterID},
+	}
+
+	var profile struct {
+		CorporationID int64 `bson:"corporation_id"`
+		AllianceID    int64 `bson:"alliance_id"`
+	}
+	err := pm.db.Collection("user_profiles").FindOne(ctx, bson.M{"character_id": characterID},
+		options.FindOne().SetProjection(bson.M{"corporation_id": 1, "alliance_id": 1})).Decode(&profile)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to get character affiliation: %w", err)
+	}
+	if profile.CorporationID != 0 {
+		subjects = append(subjects, bson.M{"subject_type": DenySubjectCorporation, "subject_id": profile.CorporationID})
+	}
+	if profile.AllianceID != 0 {
+		subjects = append(subjects, bson.M{"subject_type": DenySubjectAlliance, "subject_id": profile.AllianceID})
+	}
+	filter["$or"] = subjects
+
+	cursor, err := pm.denialsCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check permission denials: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var denials []PermissionDenial
+	if err := cursor.All(ctx, &denials); err != nil {
+		return nil, fmt.Errorf("failed to decode permission denials: %w", err)
+	}
+	return denials, nil
+}
+
+// describe names the subject of a denial for permission check results
+func (d *PermissionDenial) describe() string {
+	return fmt.Sprintf("%s %d", d.SubjectType, d.SubjectID)
+}
@@ -0,0 +1,206 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Decisions of a permission explanation
+const (
+	DecisionAdminBypass     = "admin_bypass"     // A character of the user is in an admin group
+	DecisionDenied          = "denied"           // A denial overrides every grant
+	DecisionGranted         = "granted"          // An assignment applies
+	DecisionConditionsUnmet = "conditions_unmet" // Assignments exist but none of their conditions hold
+	DecisionNoAssignment    = "no_assignment"    // No group of the character is granted the permission
+)
+
+// AssignmentTrace is one grant of a permission that reaches a character: the group the character
+// is a member of, the group the permission is granted to (the same group or an ancestor) and
+// whether its conditions hold
+type AssignmentTrace struct {
+	GroupID           primitive.ObjectID    `json:"group_id"`
+	GroupName         string                `json:"group_name"`
+	GroupType         string                `json:"group_type"`
+	EVEEntityID       *int64                `json:"eve_entity_id,omitempty"` // Corporation or alliance of an entity group
+	MembershipSource  string                `json:"membership_source,omitempty"`
+	MembershipAddedBy *int64                `json:"membership_added_by,omitempty"`
+	MembershipAddedAt time.Time             `json:"membership_added_at"`
+	GrantGroupID      primitive.ObjectID    `json:"grant_group_id"`
+	GrantGroupName    string                `json:"grant_group_name"`
+	Depth             int64                 `json:"depth"` // 0 when granted to the member's own group
+	GrantedBy         *int64                `json:"granted_by,omitempty"`
+	GrantedAt         time.Time             `json:"granted_at"`
+	Conditions        []PermissionCondition `json:"conditions,omitempty"`
+	UnmetConditions   []PermissionCondition `json:"unmet_conditions,omitempty"`
+	Applied           bool                  `json:"applied"`
+}
+
+// PermissionExplanation is the full decision trace of a permission check
+type PermissionExplanation struct {
+	CharacterID  int64              `json:"character_id"`
+	PermissionID string             `json:"permission_id"`
+	Granted      bool               `json:"granted"`
+	Decision     string             `json:"decision"`
+	AdminGroup   string             `json:"admin_group,omitempty"`
+	Denials      []PermissionDenial `json:"denials"`
+	Assignments  []AssignmentTrace  `json:"assignments"`
+}
+
+// ExplainPermission traces how CheckPermission decides: the admin bypass, every denial of the
+// permission reaching the character and every active assignment through its groups and their
+// ancestors, most direct first. Unlike a check, the trace is complete even when an earlier step
+// already decides; Decision names the step that did.
+func (pm *PermissionManager) ExplainPermission(ctx context.Context, characterID int64, permissionID string) (*PermissionExplanation, error) {
+	explanation := &PermissionExplanation{
+		CharacterID:  characterID,
+		PermissionID: permissionID,
+		Denials:      []PermissionDenial{},
+		Assignments:  []AssignmentTrace{},
+	}
+
+	explanation.AdminGroup = pm.getAdminGroup(ctx, characterID)
+	if explanation.AdminGroup == "" && !pm.permissionExists(permissionID) {
+		return nil, fmt.Errorf("permission not found: %s", permissionID)
+	}
+
+	denials, err := pm.findDenials(ctx, characterID, bson.M{"permission_id": permissionID})
+	if err != nil {
+		return nil, err
+	}
+	explanation.Denials = append(explanation.Denials, denials...)
+
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{
+				"character_id": characterID,
+				"is_active":    true,
+			},
+		},
+		{
+			"$lookup": bson.M{
+				"from":         "groups",
+				"localField":   "group_id",
+				"foreignField": "_id",
+				"as":           "group",
+			},
+		},
+		{
+			"$unwind": "$group",
+		},
+	}
+	pipeline = append(pipeline, lineageStages()...)
+	pipeline = append(pipeline, []bson.M{
+		{
+			"$lookup": bson.M{
+				"from":         "group_permissions",
+				"localField":   "lineage._id",
+				"foreignField": "group_id",
+				"as":           "permissions",
+			},
+		},
+		{
+			"$unwind": "$permissions",
+		},
+		{
+			"$match": bson.M{
+				"permissions.permission_id": permissionID,
+				"permissions.is_active":     true,
+			},
+		},
+		{
+			"$sort": bson.D{{Key: "lineage.depth", Value: 1}, {Key: "permissions.granted_at", Value: 1}},
+		},
+		{
+			"$project": bson.M{
+				"group_id":         "$group._id",
+				"group_name":       "$group.name",
+				"group_type":       "$group.type",
+				"eve_entity_id":    "$group.eve_entity_id",
+				"source":           "$source",
+				"added_by":         "$added_by",
+				"added_at":         "$added_at",
+				"grant_group_id":   "$lineage._id",
+				"grant_group_name": "$lineage.name",
+				"depth":            "$lineage.depth",
+				"granted_by":       "$permissions.granted_by",
+				"granted_at":       "$permissions.granted_at",
+				"conditions":       "$permissions.conditions",
+			},
+		},
+	}...)
+
+	cursor, err := pm.db.Collection("group_memberships").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain permission: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	attributes := pm.newCharacterAttributes(characterID)
+	applied := false
+	for cursor.Next(ctx) {
+		var doc struct {
+			GroupID        primitive.ObjectID    `bson:"group_id"`
+			GroupName      string                `bson:"group_name"`
+			GroupType      string                `bson:"group_type"`
+			EVEEntityID    *int64                `bson:"eve_entity_id"`
+			Source         string                `bson:"source"`
+			AddedBy        *int64                `bson:"added_by"`
+			AddedAt        time.Time             `bson:"added_at"`
+			GrantGroupID   primitive.ObjectID    `bson:"grant_group_id"`
+			GrantGroupName string                `bson:"grant_group_name"`
+			Depth          int64                 `bson:"depth"`
+			GrantedBy      *int64                `bson:"granted_by"`
+			GrantedAt      time.Time             `bson:"granted_at"`
+			Conditions     []PermissionCondition `bson:"conditions"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode permission grant: %w", err)
+		}
+
+		unmet, err := attributes.unmet(ctx, doc.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		trace := AssignmentTrace{
+			GroupID:           doc.GroupID,
+			GroupName:         doc.GroupName,
+			GroupType:         doc.GroupType,
+			EVEEntityID:       doc.EVEEntityID,
+			MembershipSource:  doc.Source,
+			MembershipAddedBy: doc.AddedBy,
+			MembershipAddedAt: doc.AddedAt,
+			GrantGroupID:      doc.GrantGroupID,
+			GrantGroupName:    doc.GrantGroupName,
+			Depth:             doc.Depth,
+			GrantedBy:         doc.GrantedBy,
+			GrantedAt:         doc.GrantedAt,
+			Conditions:        doc.Conditions,
+			UnmetConditions:   unmet,
+			Applied:           !applied && len(unmet) == 0,
+		}
+		applied = applied || trace.Applied
+		explanation.Assignments = append(explanation.Assignments, trace)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to explain permission: %w", err)
+	}
+
+	switch {
+	case explanation.AdminGroup != "":
+		explanation.Decision = DecisionAdminBypass
+	case len(explanation.Denials) > 0:
+		explanation.Decision = DecisionDenied
+	case applied:
+		explanation.Decision = DecisionGranted
+	case len(explanation.Assignments) > 0:
+		explanation.Decision = DecisionConditionsUnmet
+	default:
+		explanation.Decision = DecisionNoAssignment
+	}
+	explanation.Granted = explanation.Decision == DecisionAdminBypass || explanation.Decision == DecisionGranted
+	return explanation, nil
+}
@@ -0,0 +1,77 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxGroupDepth bounds how many parent groups above a group are followed. Members of a group
+// inherit the permissions granted to its parent, the parent's parent and so on.
+const MaxGroupDepth = 8
+
+// lineageStages expand the group_id of each membership into the group itself (depth 0) and its
+// active ancestors, as "lineage". An inactive parent passes nothing on; the group a character is a
+// direct member of counts regardless of its status, as it always has.
+func lineageStages() []bson.M {
+	return []bson.M{
+		{
+			"$graphLookup": bson.M{
+				"from":             "groups",
+				"startWith":        "$group_id",
+				"connectFromField": "parent_id",
+				"connectToField":   "_id",
+				"as":               "lineage",
+				"maxDepth":         MaxGroupDepth,
+				"depthField":       "depth",
+			},
+		},
+		{
+			"$unwind": "$lineage",
+		},
+		{
+			"$match": bson.M{
+				"$or": []bson.M{
+					{"lineage.depth": 0},
+					{"lineage.is_active": true},
+				},
+			},
+		},
+	}
+}
+
+// EffectiveGroupIDs returns the given groups together with every active ancestor they inherit
+// permissions from
+func (pm *PermissionManager) EffectiveGroupIDs(ctx context.Context, groupIDs []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"_id": bson.M{"$in": groupIDs}}},
+		{"$project": bson.M{"group_id": "$_id"}},
+	}
+	pipeline = append(pipeline, lineageStages()...)
+	pipeline = append(pipeline, bson.M{"$group": bson.M{"_id": "$lineage._id"}})
+
+	cursor, err := pm.db.Collection("groups").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve group hierarchy: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode group hierarchy: %w", err)
+	}
+
+	effective := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		effective[i] = doc.ID
+	}
+	return effective, nil
+}
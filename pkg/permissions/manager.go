@@ -23,6 +23,10 @@ type PermissionManager struct {
 	// Collections
 	permissionsCollection      *mongo.Collection
 	groupPermissionsCollection *mongo.Collection
+	denialsCollection          *mongo.Collection
+
+	// Optional Redis cache of HasPermission results
+	cache *decisionCache
 }
 
 // NewPermissionManager creates a new permission manager instance
@@ -33,6 +37,7 @@ func NewPermissionManager(db *mongo.Database) *PermissionManager {
 		dynamicPermissions:         make(map[string]Permission),
 		permissionsCollection:      db.Collection("permissions"),
 		groupPermissionsCollection: db.Collection("group_permissions"),
+		denialsCollection:          db.Collection("permission_denials"),
 	}
 
 	// Load static permissions
@@ -140,6 +145,7 @@ func (pm *PermissionManager) InitializeSystemGroupPermissions(ctx context.Contex
 			"total", len(permissionIDs))
 	}
 
+	pm.InvalidateDecisions(ctx)
 	slog.Info("[Permissions] System group permission initialization completed")
 	return nil
 }
@@ -224,17 +230,50 @@ func (pm *PermissionManager) RegisterServicePermissions(ctx context.Context, ser
 	return nil
 }
 
-// HasPermission checks if a character has a specific permission
+// HasPermission checks if a character has a specific permission, granted to one of its groups
+// or inherited from a parent group
 // Super Administrator and Administrator groups bypass all permission checks
 func (pm *PermissionManager) HasPermission(ctx context.Context, characterID int64, permissionID string) (bool, error) {
+	if pm.cache == nil {
+		granted, _, err := pm.hasPermission(ctx, characterID, permissionID)
+		return granted, err
+	}
+
+	granted, found, key := pm.cache.lookup(ctx, characterID, permissionID)
+	if found {
+		return granted, nil
+	}
+	granted, cacheable, err := pm.hasPermission(ctx, characterID, permissionID)
+	if err != nil {
+		return false, err
+	}
+	if cacheable {
+		pm.cache.store(ctx, key, granted)
+	}
+	return granted, nil
+}
+
+// hasPermission answers HasPermission from MongoDB. Decisions that evaluated grant conditions are
+// not cacheable: the time of day or the character's affiliation can change them without an
+// invalidation.
+func (pm *PermissionManager) hasPermission(ctx context.Context, characterID int64, permissionID string) (granted, cacheable bool, err error) {
 	// Super admin and admin have all permissions (bypass all checks including existence check)
 	if pm.isAdminUser(ctx, characterID) {
-		return true, nil
+		return true, true, nil
 	}
 
 	// Check if permission exists (only for non-admin users)
 	if !pm.permissionExists(permissionID) {
-		return false, fmt.Errorf("permission not found: %s", permissionID)
+		return false, false, fmt.Errorf("permission not found: %s", permissionID)
+	}
+
+	// A denial overrides every grant
+	denial, err := pm.findDenial(ctx, characterID, permissionID)
+	if err != nil {
+		return false, false, err
+	}
+	if denial != nil {
+		return false, true, nil
 	}
 
 	// Check group permissions via aggregation pipeline
@@ -246,11 +285,15 @@ func (pm *PermissionManager) HasPermission(ctx context.Context, characterID int6
 				"is_active":    true,
 			},
 		},
+	}
+	// Expand each group into itself and the parents it inherits from
+	pipeline = append(pipeline, lineageStages()...)
+	pipeline = append(pipeline, []bson.M{
 		// Lookup group permissions
 		{
 			"$lookup": bson.M{
 				"from":         "group_permissions",
-				"localField":   "group_id",
+				"localField":   "lineage._id",
 				"foreignField": "group_id",
 				"as":           "permissions",
 			},
@@ -269,23 +312,50 @@ func (pm *PermissionManager) HasPermission(ctx context.Context, characterID int6
 				"permissions.is_active":     true,
 			},
 		},
-		// Limit to one result (we just need to know if it exists)
 		{
-			"$limit": 1,
+			"$project": bson.M{"conditions": "$permissions.conditions"},
 		},
-	}
+	}...)
 
 	cursor, err := pm.db.Collection("group_memberships").Aggregate(ctx, pipeline)
 	if err != nil {
-		return false, fmt.Errorf("failed to check permission: %w", err)
+		return false, false, fmt.Errorf("failed to check permission: %w", err)
 	}
 	defer cursor.Close(ctx)
 
-	return cursor.Next(ctx), nil
+	// An unconditional grant decides at once; conditional ones apply when all conditions hold
+	var attributes *characterAttributes
+	for cursor.Next(ctx) {
+		var grant struct {
+			Conditions []PermissionCondition `bson:"conditions"`
+		}
+		if err := cursor.Decode(&grant); err != nil {
+			return false, false, fmt.Errorf("failed to decode permission grant: %w", err)
+		}
+		if len(grant.Conditions) == 0 {
+			return true, true, nil
+		}
+		if granted {
+			continue
+		}
+		if attributes == nil {
+			attributes = pm.newCharacterAttributes(characterID)
+		}
+		unmet, err := attributes.unmet(ctx, grant.Conditions)
+		if err != nil {
+			return false, false, err
+		}
+		granted = len(unmet) == 0
+	}
+	if err := cursor.Err(); err != nil {
+		return false, false, fmt.Errorf("failed to check permission: %w", err)
+	}
+
+	return granted, attributes == nil, nil
 }
 
 // CheckPermission returns detailed permission check result
-// Super Administrator and Administrator groups bypass all permission checks
+// Super Administrator and Administrator groups bypass all permission checks, denials included
 func (pm *PermissionManager) CheckPermission(ctx context.Context, characterID int64, permissionID string) (*PermissionCheck, error) {
 	result := &PermissionCheck{
 		CharacterID:  characterID,
@@ -305,7 +375,17 @@ func (pm *PermissionManager) CheckPermission(ctx context.Context, characterID in
 		return result, fmt.Errorf("permission not found: %s", permissionID)
 	}
 
-	// Check group permissions with group name resolution
+	denial, err := pm.findDenial(ctx, characterID, permissionID)
+	if err != nil {
+		return result, err
+	}
+	if denial != nil {
+		result.DeniedBy = denial.describe()
+		return result, nil
+	}
+
+	// Check group permissions with group name resolution; a grant on a parent group is
+	// inherited by the members of its child groups
 	pipeline := []bson.M{
 		{
 			"$match": bson.M{
@@ -324,10 +404,13 @@ func (pm *PermissionManager) CheckPermission(ctx context.Context, characterID in
 		{
 			"$unwind": "$group",
 		},
+	}
+	pipeline = append(pipeline, lineageStages()...)
+	pipeline = append(pipeline, []bson.M{
 		{
 			"$lookup": bson.M{
 				"from":         "group_permissions",
-				"localField":   "group_id",
+				"localField":   "lineage._id",
 				"foreignField": "group_id",
 				"as":           "permissions",
 			},
@@ -344,15 +427,19 @@ func (pm *PermissionManager) CheckPermission(ctx context.Context, characterID in
 				"permissions.is_active":     true,
 			},
 		},
+		// Prefer a direct grant over an inherited one
 		{
-			"$limit": 1,
+			"$sort": bson.M{"lineage.depth": 1},
 		},
 		{
 			"$project": bson.M{
-				"group_name": "$group.name",
+				"group_name":     "$group.name",
+				"inherited_from": "$lineage.name",
+				"depth":          "$lineage.depth",
+				"conditions":     "$permissions.conditions",
 			},
 		},
-	}
+	}...)
 
 	cursor, err := pm.db.Collection("group_memberships").Aggregate(ctx, pipeline)
 	if err != nil {
@@ -360,17 +447,37 @@ func (pm *PermissionManager) CheckPermission(ctx context.Context, characterID in
 	}
 	defer cursor.Close(ctx)
 
-	if cursor.Next(ctx) {
+	// The first grant whose conditions hold applies; the unmet conditions of the others explain a refusal
+	attributes := pm.newCharacterAttributes(characterID)
+	for cursor.Next(ctx) {
 		var doc struct {
-			GroupName string `bson:"group_name"`
+			GroupName     string                `bson:"group_name"`
+			InheritedFrom string                `bson:"inherited_from"`
+			Depth         int64                 `bson:"depth"`
+			Conditions    []PermissionCondition `bson:"conditions"`
 		}
-		if err := cursor.Decode(&doc); err == nil {
-			result.Granted = true
-			result.GrantedVia = doc.GroupName
-		} else {
-			result.Granted = true
-			result.GrantedVia = "Unknown Group"
+		if err := cursor.Decode(&doc); err != nil {
+			return result, fmt.Errorf("failed to decode permission grant: %w", err)
 		}
+
+		unmet, err := attributes.unmet(ctx, doc.Conditions)
+		if err != nil {
+			return result, err
+		}
+		if len(unmet) > 0 {
+			for _, condition := range unmet {
+				result.UnmetConditions = append(result.UnmetConditions, fmt.Sprintf("%s: %s", doc.GroupName, condition))
+			}
+			continue
+		}
+
+		result.Granted = true
+		result.GrantedVia = doc.GroupName
+		if doc.Depth > 0 {
+			result.GrantedVia = fmt.Sprintf("%s (inherited from %s)", doc.GroupName, doc.InheritedFrom)
+		}
+		result.UnmetConditions = nil
+		break
 	}
 
 	return result, nil
@@ -414,12 +521,21 @@ func (pm *PermissionManager) GetAllPermissions() map[string]Permission {
 	return all
 }
 
-// GrantPermissionToGroup grants a permission to a group
+// GrantPermissionToGroup grants a permission to a group unconditionally
 func (pm *PermissionManager) GrantPermissionToGroup(ctx context.Context, groupID primitive.ObjectID, permissionID string, grantedBy int64) error {
+	return pm.GrantConditionalPermissionToGroup(ctx, groupID, permissionID, grantedBy, nil)
+}
+
+// GrantConditionalPermissionToGroup grants a permission to a group that only applies while all
+// conditions hold. Granting again replaces the conditions.
+func (pm *PermissionManager) GrantConditionalPermissionToGroup(ctx context.Context, groupID primitive.ObjectID, permissionID string, grantedBy int64, conditions []PermissionCondition) error {
 	// Verify permission exists
 	if !pm.permissionExists(permissionID) {
 		return fmt.Errorf("permission not found: %s", permissionID)
 	}
+	if err := ValidateConditions(conditions); err != nil {
+		return err
+	}
 
 	// Check if permission is static and restricted
 	if pm.isStaticPermission(permissionID) && pm.isRestrictedStaticPermission(permissionID) {
@@ -442,6 +558,11 @@ func (pm *PermissionManager) GrantPermissionToGroup(ctx context.Context, groupID
 			"updated_at":    time.Now(),
 		},
 	}
+	if len(conditions) > 0 {
+		update["$set"].(bson.M)["conditions"] = conditions
+	} else {
+		update["$unset"] = bson.M{"conditions": ""}
+	}
 
 	opts := options.Update().SetUpsert(true)
 	_, err := pm.groupPermissionsCollection.UpdateOne(ctx, filter, update, opts)
@@ -449,9 +570,12 @@ func (pm *PermissionManager) GrantPermissionToGroup(ctx context.Context, groupID
 		return fmt.Errorf("failed to grant permission: %w", err)
 	}
 
+	pm.InvalidateDecisions(ctx)
+
 	slog.Info("[Permissions] Granted permission to group",
 		"group_id", groupID.Hex(),
 		"permission_id", permissionID,
+		"conditions", len(conditions),
 		"granted_by", grantedBy)
 
 	return nil
@@ -479,6 +603,8 @@ func (pm *PermissionManager) DeletePermissionFromGroup(ctx context.Context, grou
 		return fmt.Errorf("permission assignment not found")
 	}
 
+	pm.InvalidateDecisions(ctx)
+
 	slog.Info("[Permissions] Deleted permission from group",
 		"group_id", groupID.Hex(),
 		"permission_id", permissionID)
@@ -514,6 +640,8 @@ func (pm *PermissionManager) UpdateGroupPermissionStatus(ctx context.Context, gr
 		return fmt.Errorf("permission assignment not found")
 	}
 
+	pm.InvalidateDecisions(ctx)
+
 	status := "deactivated"
 	if isActive {
 		status = "activated"
@@ -752,5 +880,18 @@ func (pm *PermissionManager) ensureIndexes(ctx context.Context) error {
 		return fmt.Errorf("failed to create group permissions indexes: %w", err)
 	}
 
+	// A permission is denied to a subject once; checks look denials up by permission and subject
+	_, err = pm.denialsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "permission_id", Value: 1},
+			{Key: "subject_type", Value: 1},
+			{Key: "subject_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create permission denial indexes: %w", err)
+	}
+
 	return nil
 }
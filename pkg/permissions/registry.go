@@ -109,6 +109,17 @@ var StaticPermissions = map[string]Permission{
 		Category:    "Group Management",
 		CreatedAt:   time.Now(),
 	},
+	"groups:audit:read": {
+		ID:          "groups:audit:read",
+		Service:     "groups",
+		Resource:    "audit",
+		Action:      "read",
+		IsStatic:    true,
+		Name:        "Groups Audit Export",
+		Description: "Export the audit log of group, membership, grant and denial changes",
+		Category:    "Group Management",
+		CreatedAt:   time.Now(),
+	},
 
 	// Authentication System
 	"auth:tokens:manage": {
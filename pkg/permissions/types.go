@@ -28,14 +28,19 @@ type GroupPermission struct {
 	GrantedAt    time.Time          `json:"granted_at" bson:"granted_at"`
 	IsActive     bool               `json:"is_active" bson:"is_active"`
 	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+
+	// Conditions must all hold at check time for the grant to apply; none means always
+	Conditions []PermissionCondition `json:"conditions,omitempty" bson:"conditions,omitempty"`
 }
 
 // PermissionCheck represents the result of a permission check
 type PermissionCheck struct {
-	CharacterID  int64  `json:"character_id"`
-	PermissionID string `json:"permission_id"`
-	Granted      bool   `json:"granted"`
-	GrantedVia   string `json:"granted_via,omitempty"` // Which group granted the permission
+	CharacterID     int64    `json:"character_id"`
+	PermissionID    string   `json:"permission_id"`
+	Granted         bool     `json:"granted"`
+	GrantedVia      string   `json:"granted_via,omitempty"`      // Which group granted the permission
+	DeniedBy        string   `json:"denied_by,omitempty"`        // Denial that overrides any grant, e.g. "corporation 98000001"
+	UnmetConditions []string `json:"unmet_conditions,omitempty"` // Conditions of grants that did not hold
 }
 
 // PermissionCategory defines UI groupings for permissions
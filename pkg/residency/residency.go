@@ -0,0 +1,116 @@
+package residency
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go-falcon/pkg/config"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Category is a kind of sensitive ESI data whose storage a deployment can restrict
+type Category string
+
+const (
+	// CategoryWallets covers character and corporation wallet balances and journals
+	CategoryWallets Category = "wallets"
+	// CategoryAssets covers character and corporation assets
+	CategoryAssets Category = "assets"
+)
+
+// Categories lists every category with a storage toggle
+var Categories = []Category{CategoryWallets, CategoryAssets}
+
+// Mode is how much of a category is kept
+type Mode string
+
+const (
+	// ModeFull fetches the data and persists it in MongoDB
+	ModeFull Mode = "full"
+	// ModeCache fetches the data live; it is only kept in the short-lived ESI response cache
+	ModeCache Mode = "cache"
+	// ModeDisabled neither fetches nor stores the data; its endpoints answer 404
+	ModeDisabled Mode = "disabled"
+)
+
+// strictness orders the modes; the stricter of the configured and site setting modes applies
+var strictness = map[Mode]int{ModeFull: 0, ModeCache: 1, ModeDisabled: 2}
+
+// SettingsSource reads the per-category modes an administrator set in site settings
+type SettingsSource interface {
+	DataResidencyModes(ctx context.Context) (map[string]string, error)
+}
+
+var (
+	mu       sync.RWMutex
+	settings SettingsSource
+)
+
+// SetSettingsSource lets site settings restrict categories at runtime
+func SetSettingsSource(source SettingsSource) {
+	mu.Lock()
+	defer mu.Unlock()
+	settings = source
+}
+
+// Configured returns the mode set by DATA_RESIDENCY_<CATEGORY>. Routes of a category configured
+// as disabled are not registered, so they are also missing from the OpenAPI spec.
+func Configured(category Category) Mode {
+	return parseMode(config.GetDataResidencyMode(string(category)), ModeFull)
+}
+
+// ModeOf returns the mode in effect for a category: the configured mode, or the site setting when
+// that is stricter. Site settings can only restrict a category, since routes of a category
+// disabled in configuration do not exist.
+func ModeOf(ctx context.Context, category Category) Mode {
+	mode := Configured(category)
+
+	mu.RLock()
+	source := settings
+	mu.RUnlock()
+	if source == nil {
+		return mode
+	}
+
+	modes, err := source.DataResidencyModes(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read data residency settings", "error", err)
+		return mode
+	}
+	if override := parseMode(modes[string(category)], mode); strictness[override] > strictness[mode] {
+		return override
+	}
+	return mode
+}
+
+// Stores reports whether a category may be persisted in MongoDB
+func Stores(ctx context.Context, category Category) bool {
+	return ModeOf(ctx, category) == ModeFull
+}
+
+// Enabled reports whether a category may be fetched at all
+func Enabled(ctx context.Context, category Category) bool {
+	return ModeOf(ctx, category) != ModeDisabled
+}
+
+// Require answers 404 when a category is disabled, for the endpoints serving it
+func Require(ctx context.Context, category Category) error {
+	if Enabled(ctx, category) {
+		return nil
+	}
+	return huma.Error404NotFound(string(category) + " data is disabled on this deployment")
+}
+
+// parseMode returns the named mode, or fallback for unknown names
+func parseMode(name string, fallback Mode) Mode {
+	mode := Mode(name)
+	if _, ok := strictness[mode]; !ok {
+		if name != "" {
+			slog.Warn("Unknown data residency mode", "mode", name, "using", fallback)
+		}
+		return fallback
+	}
+	return mode
+}
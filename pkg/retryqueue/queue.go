@@ -0,0 +1,306 @@
+package retryqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go-falcon/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection holds one update status document per entity
+const Collection = "esi_update_status"
+
+// Kinds of ESI-backed updates that are recorded
+const (
+	KindCorporation = "corporation"
+)
+
+const (
+	// pollInterval is how often the worker looks for due retries
+	pollInterval = time.Minute
+	// baseBackoff is the delay before the first retry; it doubles with every failed attempt
+	baseBackoff = 2 * time.Minute
+	// maxBackoff caps the delay between retries
+	maxBackoff = 6 * time.Hour
+	// MaxAttempts is how many retries are made before an entity is left for the next scheduled update
+	MaxAttempts = 8
+	// claimLease keeps other replicas off an entity while one is retrying it
+	claimLease = 5 * time.Minute
+	// batchSize is the number of due retries handled per poll
+	batchSize = 50
+)
+
+// UpdateFunc refreshes one entity from ESI
+type UpdateFunc func(ctx context.Context, entityID int64) error
+
+// Status is the update state of one entity
+type Status struct {
+	Kind          string     `bson:"kind" json:"kind"`
+	EntityID      int64      `bson:"entity_id" json:"entity_id"`
+	LastSuccessAt *time.Time `bson:"last_success_at,omitempty" json:"last_success_at,omitempty"`
+	LastFailureAt *time.Time `bson:"last_failure_at,omitempty" json:"last_failure_at,omitempty"`
+	LastError     string     `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	Attempts      int        `bson:"attempts" json:"attempts"`
+	NextRetryAt   *time.Time `bson:"next_retry_at,omitempty" json:"next_retry_at,omitempty"`
+}
+
+// target is a registered update kind
+type target struct {
+	staleAfter time.Duration
+	update     UpdateFunc
+}
+
+// Queue records the outcome of ESI-backed updates and retries failed ones with exponential
+// backoff. Modules report every update with RecordSuccess or RecordFailure; a failure schedules
+// a retry that the worker runs through the kind's UpdateFunc until it succeeds or MaxAttempts
+// is reached. The recorded success times drive the stale-data report.
+type Queue struct {
+	collection *mongo.Collection
+
+	mu      sync.RWMutex
+	targets map[string]target
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewQueue creates a retry queue backed by MongoDB
+func NewQueue(mongodb *database.MongoDB) *Queue {
+	return &Queue{
+		collection: mongodb.Database.Collection(Collection),
+		targets:    make(map[string]target),
+	}
+}
+
+// Register sets how entities of a kind are refreshed and after how long without a successful
+// update they are reported as stale
+func (q *Queue) Register(kind string, staleAfter time.Duration, update UpdateFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.targets[kind] = target{staleAfter: staleAfter, update: update}
+}
+
+// Kinds returns the registered kinds, ordered by name
+func (q *Queue) Kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	kinds := make([]string, 0, len(q.targets))
+	for kind := range q.targets {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func (q *Queue) target(kind string) (target, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	t, ok := q.targets[kind]
+	return t, ok
+}
+
+// CreateIndexes creates the lookup and due-retry indexes
+func (q *Queue) CreateIndexes(ctx context.Context) error {
+	_, err := q.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "kind", Value: 1}, {Key: "entity_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "next_retry_at", Value: 1}}},
+		{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "last_success_at", Value: 1}}},
+	})
+	return err
+}
+
+// RecordSuccess marks an entity as freshly updated and cancels any pending retry. Recording is
+// best effort: failures are logged because the update itself has already happened.
+func (q *Queue) RecordSuccess(ctx context.Context, kind string, entityID int64) {
+	now := time.Now().UTC()
+	_, err := q.collection.UpdateOne(ctx,
+		bson.M{"kind": kind, "entity_id": entityID},
+		bson.M{
+			"$set":   bson.M{"last_success_at": now, "attempts": 0},
+			"$unset": bson.M{"next_retry_at": "", "last_error": ""},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to record ESI update success", "kind", kind, "entity_id", entityID, "error", err)
+	}
+}
+
+// RecordFailure records a failed update and schedules a retry with backoff
+func (q *Queue) RecordFailure(ctx context.Context, kind string, entityID int64, updateErr error) {
+	filter := bson.M{"kind": kind, "entity_id": entityID}
+
+	var current Status
+	err := q.collection.FindOne(ctx, filter).Decode(&current)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		slog.WarnContext(ctx, "Failed to load ESI update status", "kind", kind, "entity_id", entityID, "error", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	attempts := current.Attempts + 1
+	set := bson.M{"last_failure_at": now, "last_error": updateErr.Error(), "attempts": attempts}
+	update := bson.M{"$set": set}
+	if attempts <= MaxAttempts {
+		set["next_retry_at"] = now.Add(backoff(attempts))
+	} else {
+		// Out of retries: the next scheduled update gets another go
+		update["$unset"] = bson.M{"next_retry_at": ""}
+	}
+
+	if _, err := q.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		slog.WarnContext(ctx, "Failed to record ESI update failure", "kind", kind, "entity_id", entityID, "error", err)
+	}
+}
+
+// backoff returns the delay before the given attempt
+func backoff(attempt int) time.Duration {
+	delay := time.Duration(float64(baseBackoff) * math.Pow(2, float64(attempt-1)))
+	if delay <= 0 || delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
+
+// Start runs the retry worker until Stop is called
+func (q *Queue) Start(ctx context.Context) {
+	if err := q.CreateIndexes(ctx); err != nil {
+		slog.Warn("Failed to create ESI update status indexes", "error", err)
+	}
+
+	ctx, q.cancel = context.WithCancel(ctx)
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				q.RunDue(ctx)
+			}
+		}
+	}()
+
+	slog.Info("ESI retry queue started", "kinds", q.Kinds())
+}
+
+// Stop stops the worker and waits for the retry in progress
+func (q *Queue) Stop() {
+	if q.cancel != nil {
+		q.cancel()
+	}
+	q.wg.Wait()
+}
+
+// RunDue retries the entities whose retry is due and returns how many succeeded
+func (q *Queue) RunDue(ctx context.Context) int {
+	succeeded := 0
+	for i := 0; i < batchSize && ctx.Err() == nil; i++ {
+		status, err := q.claim(ctx)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				slog.WarnContext(ctx, "Failed to claim ESI retry", "error", err)
+			}
+			break
+		}
+
+		t, ok := q.target(status.Kind)
+		if !ok {
+			continue
+		}
+		if err := t.update(ctx, status.EntityID); err != nil {
+			slog.WarnContext(ctx, "ESI update retry failed", "kind", status.Kind, "entity_id", status.EntityID, "attempt", status.Attempts, "error", err)
+			q.RecordFailure(ctx, status.Kind, status.EntityID, err)
+			continue
+		}
+		q.RecordSuccess(ctx, status.Kind, status.EntityID)
+		succeeded++
+	}
+	return succeeded
+}
+
+// claim takes the next due retry of a registered kind, pushing its retry time out by the lease so
+// other replicas skip it while it runs
+func (q *Queue) claim(ctx context.Context) (*Status, error) {
+	now := time.Now().UTC()
+	var status Status
+	err := q.collection.FindOneAndUpdate(ctx,
+		bson.M{"kind": bson.M{"$in": q.Kinds()}, "next_retry_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_retry_at": now.Add(claimLease)}},
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_retry_at", Value: 1}}),
+	).Decode(&status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// StaleKind lists the entities of one kind without a successful update within its threshold
+type StaleKind struct {
+	Kind       string   `json:"kind" doc:"Update kind"`
+	StaleAfter string   `json:"stale_after" doc:"Threshold after which an entity is stale"`
+	Count      int64    `json:"count" doc:"Number of stale entities"`
+	Pending    int64    `json:"pending" doc:"Entities with a retry scheduled"`
+	Entities   []Status `json:"entities" doc:"Stale entities, longest without a successful update first"`
+}
+
+// StaleReport returns the stale entities of every registered kind, at most limit per kind
+func (q *Queue) StaleReport(ctx context.Context, limit int64) ([]StaleKind, error) {
+	report := []StaleKind{}
+	for _, kind := range q.Kinds() {
+		t, _ := q.target(kind)
+		filter := bson.M{
+			"kind": kind,
+			"$or": bson.A{
+				bson.M{"last_success_at": bson.M{"$lt": time.Now().UTC().Add(-t.staleAfter)}},
+				bson.M{"last_success_at": bson.M{"$exists": false}},
+			},
+		}
+
+		count, err := q.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count stale %s entities: %w", kind, err)
+		}
+		pending, err := q.collection.CountDocuments(ctx, bson.M{"kind": kind, "next_retry_at": bson.M{"$exists": true}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count pending %s retries: %w", kind, err)
+		}
+
+		cursor, err := q.collection.Find(ctx, filter, options.Find().
+			SetSort(bson.D{{Key: "last_success_at", Value: 1}, {Key: "entity_id", Value: 1}}).
+			SetLimit(limit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stale %s entities: %w", kind, err)
+		}
+		entities := []Status{}
+		if err := cursor.All(ctx, &entities); err != nil {
+			return nil, fmt.Errorf("failed to decode stale %s entities: %w", kind, err)
+		}
+
+		report = append(report, StaleKind{
+			Kind:       kind,
+			StaleAfter: t.staleAfter.String(),
+			Count:      count,
+			Pending:    pending,
+			Entities:   entities,
+		})
+	}
+	return report, nil
+}
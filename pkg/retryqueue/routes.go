@@ -0,0 +1,59 @@
+package retryqueue
+
+import (
+	"context"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/apidocs"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// SuperAdminChecker authorizes access to the stale-data report
+type SuperAdminChecker interface {
+	RequireSuperAdmin(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error)
+}
+
+// StaleReportInput is the input for the stale-data report endpoint
+type StaleReportInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+	Limit         int64  `query:"limit" minimum:"1" maximum:"1000" default:"100" doc:"Maximum entities listed per kind"`
+}
+
+// StaleReportResponse lists the stale entities by update kind
+type StaleReportResponse struct {
+	Kinds []StaleKind `json:"kinds" doc:"Stale entities of every recorded update kind"`
+}
+
+// StaleReportOutput wraps the stale-data report
+type StaleReportOutput struct {
+	Body StaleReportResponse
+}
+
+// RegisterRoutes registers the stale-data report endpoint (super admin only)
+func RegisterRoutes(api huma.API, path string, queue *Queue, auth SuperAdminChecker) {
+	huma.Register(api, huma.Operation{
+		OperationID: "admin-get-stale-data",
+		Method:      "GET",
+		Path:        path,
+		Summary:     "Get stale data report",
+		Description: "Lists entities whose last successful ESI update is older than the threshold of their kind, with the failure and retry state of each. Requires super admin access.",
+		Tags:        []string{"Health"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
+		Security: []map[string][]string{
+			{"bearerAuth": {}},
+			{"cookieAuth": {}},
+		},
+	}, func(ctx context.Context, input *StaleReportInput) (*StaleReportOutput, error) {
+		if _, err := auth.RequireSuperAdmin(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+
+		kinds, err := queue.StaleReport(ctx, input.Limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to build stale data report", err)
+		}
+		return &StaleReportOutput{Body: StaleReportResponse{Kinds: kinds}}, nil
+	})
+}
@@ -0,0 +1,123 @@
+package startup
+
+import (
+	"sync"
+	"time"
+)
+
+// Phase identifies when a startup step runs
+type Phase string
+
+const (
+	// PhaseInit steps run synchronously before the server starts listening
+	PhaseInit Phase = "init"
+	// PhaseBackground steps run in goroutines after the main initialization
+	PhaseBackground Phase = "background"
+)
+
+// Status is the outcome of a startup step
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusSuccess Status = "success"
+	StatusFailed  Status = "failed"
+)
+
+// Step records the outcome of a single initialization or registration task
+type Step struct {
+	Name       string    `json:"name" doc:"Step name, usually the module and action"`
+	Phase      Phase     `json:"phase" doc:"When the step runs (init or background)"`
+	Status     Status    `json:"status" doc:"Step outcome (pending, success or failed)"`
+	StartedAt  time.Time `json:"started_at" doc:"When the step started"`
+	DurationMS int64     `json:"duration_ms" doc:"How long the step took in milliseconds"`
+	Error      string    `json:"error,omitempty" doc:"Error message if the step failed"`
+}
+
+// Snapshot is a point-in-time copy of the startup report
+type Snapshot struct {
+	StartedAt time.Time  `json:"started_at" doc:"When the process began initializing"`
+	ReadyAt   *time.Time `json:"ready_at,omitempty" doc:"When the HTTP server started listening"`
+	Healthy   bool       `json:"healthy" doc:"True when no step failed and none are still pending"`
+	Succeeded int        `json:"succeeded" doc:"Number of successful steps"`
+	Failed    int        `json:"failed" doc:"Number of failed steps"`
+	Pending   int        `json:"pending" doc:"Number of steps still running"`
+	Steps     []Step     `json:"steps" doc:"Steps in the order they started"`
+}
+
+// Report collects startup step outcomes; it is safe for concurrent use
+type Report struct {
+	mu        sync.RWMutex
+	startedAt time.Time
+	readyAt   *time.Time
+	steps     []*Step
+}
+
+// NewReport creates an empty startup report starting now
+func NewReport() *Report {
+	return &Report{startedAt: time.Now().UTC()}
+}
+
+// Run records a pending step, runs fn and marks the step finished with its error (nil for
+// success). The error is returned unchanged.
+func (r *Report) Run(name string, phase Phase, fn func() error) error {
+	step := &Step{
+		Name:      name,
+		Phase:     phase,
+		Status:    StatusPending,
+		StartedAt: time.Now().UTC(),
+	}
+
+	r.mu.Lock()
+	r.steps = append(r.steps, step)
+	r.mu.Unlock()
+
+	err := fn()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	step.DurationMS = time.Since(step.StartedAt).Milliseconds()
+	if err != nil {
+		step.Status = StatusFailed
+		step.Error = err.Error()
+	} else {
+		step.Status = StatusSuccess
+	}
+	return err
+}
+
+// MarkReady records when the server started accepting requests
+func (r *Report) MarkReady() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	r.readyAt = &now
+}
+
+// Snapshot returns a copy of the report with summary counts
+func (r *Report) Snapshot() Snapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := Snapshot{
+		StartedAt: r.startedAt,
+		ReadyAt:   r.readyAt,
+		Steps:     make([]Step, len(r.steps)),
+	}
+	for i, step := range r.steps {
+		snapshot.Steps[i] = *step
+		switch step.Status {
+		case StatusSuccess:
+			snapshot.Succeeded++
+		case StatusFailed:
+			snapshot.Failed++
+		default:
+			snapshot.Pending++
+		}
+	}
+	snapshot.Healthy = snapshot.Failed == 0 && snapshot.Pending == 0 && r.readyAt != nil
+
+	return snapshot
+}
@@ -0,0 +1,61 @@
+package startup
+
+import (
+	"context"
+
+	"go-falcon/internal/auth/models"
+	"go-falcon/pkg/apidocs"
+	"go-falcon/pkg/version"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// SuperAdminChecker authorizes access to the startup report
+type SuperAdminChecker interface {
+	RequireSuperAdmin(ctx context.Context, authHeader, cookieHeader string) (*models.AuthenticatedUser, error)
+}
+
+// ReportInput is the input for the startup report endpoint
+type ReportInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// ReportResponse is the startup report with build information
+type ReportResponse struct {
+	Version version.Info `json:"version" doc:"Build information of the running binary"`
+	Snapshot
+}
+
+// ReportOutput wraps the startup report response
+type ReportOutput struct {
+	Body ReportResponse
+}
+
+// RegisterRoutes registers the startup report endpoint (super admin only)
+func RegisterRoutes(api huma.API, path string, report *Report, auth SuperAdminChecker) {
+	huma.Register(api, huma.Operation{
+		OperationID: "admin-get-startup-report",
+		Method:      "GET",
+		Path:        path,
+		Summary:     "Get startup report",
+		Description: "Returns the outcome, duration and error of every module initialization and background registration step from the current boot. Requires super admin access.",
+		Tags:        []string{"Health"},
+		Extensions:  apidocs.RequiresPermission(apidocs.SuperAdminPermission),
+		Security: []map[string][]string{
+			{"bearerAuth": {}},
+			{"cookieAuth": {}},
+		},
+	}, func(ctx context.Context, input *ReportInput) (*ReportOutput, error) {
+		if _, err := auth.RequireSuperAdmin(ctx, input.Authorization, input.Cookie); err != nil {
+			return nil, err
+		}
+
+		return &ReportOutput{
+			Body: ReportResponse{
+				Version:  version.Get(),
+				Snapshot: report.Snapshot(),
+			},
+		}, nil
+	})
+}
@@ -0,0 +1,96 @@
+package stepup
+
+import (
+	"context"
+	"time"
+
+	"go-falcon/pkg/identity"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// AuthInput carries the credentials of the session being confirmed
+type AuthInput struct {
+	Authorization string `header:"Authorization" doc:"Bearer token for authentication"`
+	Cookie        string `header:"Cookie" doc:"Authentication cookie"`
+}
+
+// StatusResponse reports whether the current session can run destructive actions
+type StatusResponse struct {
+	Confirmed      bool       `json:"confirmed" doc:"Whether destructive actions are currently allowed"`
+	ConfirmedUntil *time.Time `json:"confirmed_until,omitempty" doc:"When the confirmation expires"`
+	Methods        []string   `json:"methods" doc:"Ways to confirm the session"`
+	WindowMinutes  int        `json:"window_minutes" doc:"How long a confirmation stays valid"`
+}
+
+// StatusOutput wraps the step-up status response
+type StatusOutput struct {
+	Body StatusResponse
+}
+
+// ConfirmInput confirms the session with a second factor
+type ConfirmInput struct {
+	AuthInput
+	Body struct {
+		Method string `json:"method" minLength:"1" doc:"Second-factor method, e.g. totp"`
+		Code   string `json:"code" minLength:"1" doc:"Second-factor code"`
+	}
+}
+
+// RegisterRoutes registers the step-up status and confirmation endpoints
+func RegisterRoutes(api huma.API, path string, guard *Guard) {
+	security := []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-get-step-up-status",
+		Method:      "GET",
+		Path:        path,
+		Summary:     "Get step-up status",
+		Description: "Reports whether the current session recently signed in or confirmed a second factor, and can therefore run destructive admin actions.",
+		Tags:        []string{"Auth"},
+		Security:    security,
+	}, func(ctx context.Context, input *AuthInput) (*StatusOutput, error) {
+		id := identity.FromContext(ctx)
+		if id == nil || id.SessionID == "" {
+			return nil, huma.Error401Unauthorized("Authentication required")
+		}
+
+		until, err := guard.ConfirmedUntil(ctx, id)
+		if err != nil {
+			return nil, huma.Error503ServiceUnavailable("Step-up confirmation unavailable", err)
+		}
+
+		response := StatusResponse{
+			Confirmed:     !until.IsZero(),
+			Methods:       guard.Methods(),
+			WindowMinutes: int(guard.Window() / time.Minute),
+		}
+		if response.Confirmed {
+			response.ConfirmedUntil = &until
+		}
+		return &StatusOutput{Body: response}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "auth-confirm-step-up",
+		Method:      "POST",
+		Path:        path,
+		Summary:     "Confirm session with a second factor",
+		Description: "Confirms the current session with a second-factor code so destructive admin actions are allowed for the step-up window. Signing in through SSO again confirms the session as well.",
+		Tags:        []string{"Auth"},
+		Security:    security,
+	}, func(ctx context.Context, input *ConfirmInput) (*StatusOutput, error) {
+		id := identity.FromContext(ctx)
+		until, err := guard.Confirm(ctx, id, input.Body.Method, input.Body.Code)
+		if err != nil {
+			return nil, err
+		}
+
+		return &StatusOutput{Body: StatusResponse{
+			Confirmed:      true,
+			ConfirmedUntil: &until,
+			Methods:        guard.Methods(),
+			WindowMinutes:  int(guard.Window() / time.Minute),
+		}}, nil
+	})
+}
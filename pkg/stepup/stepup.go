@@ -0,0 +1,293 @@
+package stepup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-falcon/pkg/identity"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Redis key prefix for sessions that confirmed a step-up with a second factor
+	confirmationKeyPrefix = "auth:step_up:"
+
+	// MethodReauth is satisfied by signing in through EVE SSO again; it is always available
+	MethodReauth = "reauth"
+
+	// MethodSudo is satisfied by exchanging an authenticator code for a sudo token (POST /auth/sudo);
+	// it is the only way to run actions protected by RequireSecondFactor once a second factor exists
+	MethodSudo = "sudo"
+)
+
+// Verifier checks a second-factor code (for example a TOTP code) for a user
+type Verifier interface {
+	Verify(ctx context.Context, userID, code string) (bool, error)
+}
+
+// ThrottledError is returned by a Verifier while the user is locked out of code checks
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many step-up codes; retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// SecondFactorEnrollment tells whether a user has enrolled a second factor
+type SecondFactorEnrollment interface {
+	HasSecondFactor(ctx context.Context, userID string) (bool, error)
+}
+
+// Challenge is returned with the 403 response when an action needs a step-up confirmation
+type Challenge struct {
+	Action        string   `json:"action" doc:"Action that requires confirmation"`
+	Methods       []string `json:"methods" doc:"Ways to confirm: reauth (sign in through SSO again) or a registered second factor"`
+	WindowMinutes int      `json:"window_minutes" doc:"How long a confirmation stays valid"`
+}
+
+// Guard decides whether the current session recently proved who it is. A session passes when its
+// token was issued by an SSO login within the window, or when it confirmed a second factor within
+// the window; confirmations are tracked per session in Redis.
+type Guard struct {
+	redis  *redis.Client
+	window time.Duration
+
+	mu        sync.RWMutex
+	verifiers map[string]Verifier
+
+	secondFactor         SecondFactorEnrollment
+	secondFactorRequired bool
+}
+
+// NewGuard creates a step-up guard with the given confirmation window
+func NewGuard(redisClient *redis.Client, window time.Duration) *Guard {
+	return &Guard{
+		redis:     redisClient,
+		window:    window,
+		verifiers: make(map[string]Verifier),
+	}
+}
+
+// RegisterVerifier makes a second-factor method available for confirming sessions
+func (g *Guard) RegisterVerifier(method string, verifier Verifier) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.verifiers[method] = verifier
+}
+
+// SetSecondFactor enables RequireSecondFactor. Callers with an enrolled second factor then need a
+// sudo token for the protected actions; with required set, callers without one are refused too.
+func (g *Guard) SetSecondFactor(enrollment SecondFactorEnrollment, required bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.secondFactor = enrollment
+	g.secondFactorRequired = required
+}
+
+// Methods lists the ways a session can be confirmed
+func (g *Guard) Methods() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	methods := make([]string, 0, len(g.verifiers))
+	for method := range g.verifiers {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return append([]string{MethodReauth}, methods...)
+}
+
+// Window returns how long a confirmation stays valid
+func (g *Guard) Window() time.Duration {
+	return g.window
+}
+
+// ConfirmedUntil returns when the session's step-up confirmation expires, or the zero time
+// when the session has to confirm before running a destructive action
+func (g *Guard) ConfirmedUntil(ctx context.Context, id *identity.Identity) (time.Time, error) {
+	if id == nil || id.SessionID == "" {
+		return time.Time{}, nil
+	}
+
+	until := id.AuthenticatedAt.Add(g.window)
+
+	ttl, err := g.redis.TTL(ctx, confirmationKeyPrefix+id.SessionID).Result()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read step-up confirmation: %w", err)
+	}
+	if ttl > 0 {
+		if confirmed := time.Now().Add(ttl); confirmed.After(until) {
+			until = confirmed
+		}
+	}
+
+	if !until.After(time.Now()) {
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+// Check returns nil when the session may run the action, or a 403 carrying a Challenge
+func (g *Guard) Check(ctx context.Context, id *identity.Identity, action string) error {
+	if id == nil || id.SessionID == "" {
+		return huma.Error401Unauthorized("Authentication required")
+	}
+	if id.Impersonator != nil {
+		return errImpersonated(ctx, id, action)
+	}
+
+	until, err := g.ConfirmedUntil(ctx, id)
+	if err != nil {
+		return huma.Error503ServiceUnavailable("Step-up confirmation unavailable", err)
+	}
+	if !until.IsZero() {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "Step-up confirmation required", "action", action, "user_id", id.Actor.UserID)
+	return huma.Error403Forbidden(
+		"This action requires a recent sign-in. Sign in again or confirm with a second factor, then retry.",
+		&huma.ErrorDetail{
+			Location: "step_up",
+			Message:  "step-up confirmation required",
+			Value: Challenge{
+				Action:        action,
+				Methods:       g.Methods(),
+				WindowMinutes: int(g.window / time.Minute),
+			},
+		},
+	)
+}
+
+// errImpersonated refuses a protected action to an impersonation token. The administrator cannot
+// confirm as the target, and a fresh impersonation token must not pass for a recent sign-in.
+func errImpersonated(ctx context.Context, id *identity.Identity, action string) error {
+	slog.WarnContext(ctx, "Protected action refused during impersonation",
+		"action", action, "user_id", id.Actor.UserID, "impersonator_id", id.Impersonator.UserID)
+	return huma.Error403Forbidden("This action is not available while impersonating a user")
+}
+
+// CheckSecondFactor returns nil when the session may run an action that needs a second factor:
+// its token carries an unexpired sudo claim, or the caller has no second factor and it is not
+// required, in which case the regular step-up check applies
+func (g *Guard) CheckSecondFactor(ctx context.Context, id *identity.Identity, action string) error {
+	if id == nil || id.SessionID == "" {
+		return huma.Error401Unauthorized("Authentication required")
+	}
+	if id.Impersonator != nil {
+		return errImpersonated(ctx, id, action)
+	}
+	if id.SudoUntil.After(time.Now()) {
+		return nil
+	}
+
+	g.mu.RLock()
+	enrollment, required := g.secondFactor, g.secondFactorRequired
+	g.mu.RUnlock()
+	if enrollment == nil {
+		return g.Check(ctx, id, action)
+	}
+
+	enrolled, err := enrollment.HasSecondFactor(ctx, id.Actor.UserID)
+	if err != nil {
+		return huma.Error503ServiceUnavailable("Second factor unavailable", err)
+	}
+	if !enrolled && !required {
+		return g.Check(ctx, id, action)
+	}
+
+	slog.InfoContext(ctx, "Second factor required", "action", action, "user_id", id.Actor.UserID, "enrolled", enrolled)
+	message := "This action requires a second factor. Confirm with your authenticator at /auth/sudo, then retry with the returned token."
+	if !enrolled {
+		message = "This action requires a second factor. Enroll an authenticator at /auth/mfa/totp first."
+	}
+	return huma.Error403Forbidden(message, &huma.ErrorDetail{
+		Location: "step_up",
+		Message:  "second factor required",
+		Value: Challenge{
+			Action:        action,
+			Methods:       []string{MethodSudo},
+			WindowMinutes: int(g.window / time.Minute),
+		},
+	})
+}
+
+// Confirm verifies a second-factor code and marks the session confirmed for the window
+func (g *Guard) Confirm(ctx context.Context, id *identity.Identity, method, code string) (time.Time, error) {
+	if id == nil || id.SessionID == "" {
+		return time.Time{}, huma.Error401Unauthorized("Authentication required")
+	}
+	if method == MethodReauth {
+		return time.Time{}, huma.Error400BadRequest("Re-authentication is confirmed by signing in through SSO again")
+	}
+
+	g.mu.RLock()
+	verifier, ok := g.verifiers[method]
+	g.mu.RUnlock()
+	if !ok {
+		return time.Time{}, huma.Error400BadRequest(fmt.Sprintf("Unsupported step-up method %q", method))
+	}
+
+	valid, err := verifier.Verify(ctx, id.Actor.UserID, code)
+	var throttled *ThrottledError
+	if errors.As(err, &throttled) {
+		retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		return time.Time{}, huma.ErrorWithHeaders(huma.Error429TooManyRequests("Too many step-up codes"),
+			http.Header{"Retry-After": {strconv.Itoa(retryAfter)}})
+	}
+	if err != nil {
+		return time.Time{}, huma.Error500InternalServerError("Failed to verify step-up code", err)
+	}
+	if !valid {
+		return time.Time{}, huma.Error403Forbidden("Invalid step-up code")
+	}
+
+	if err := g.redis.Set(ctx, confirmationKeyPrefix+id.SessionID, method, g.window).Err(); err != nil {
+		return time.Time{}, huma.Error503ServiceUnavailable("Failed to record step-up confirmation", err)
+	}
+
+	slog.InfoContext(ctx, "Step-up confirmed", "method", method, "user_id", id.Actor.UserID)
+	return time.Now().Add(g.window), nil
+}
+
+type contextKey struct{}
+
+// Middleware makes the guard available to handlers through Require. It must run after
+// middleware.IdentityMiddleware, which resolves the session.
+func (g *Guard) Middleware() func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		next(huma.WithValue(ctx, contextKey{}, g))
+	}
+}
+
+// Require returns nil when the caller's session may run a destructive action, or an error to
+// return from the handler. When step-up is disabled (no guard installed) every action passes.
+func Require(ctx context.Context, action string) error {
+	guard, ok := ctx.Value(contextKey{}).(*Guard)
+	if !ok {
+		return nil
+	}
+	return guard.Check(ctx, identity.FromContext(ctx), action)
+}
+
+// RequireSecondFactor is Require for the most sensitive actions: once second factors are enabled
+// on the guard, callers with an authenticator must present a sudo token. When step-up is disabled
+// every action passes.
+func RequireSecondFactor(ctx context.Context, action string) error {
+	guard, ok := ctx.Value(contextKey{}).(*Guard)
+	if !ok {
+		return nil
+	}
+	return guard.CheckSecondFactor(ctx, identity.FromContext(ctx), action)
+}
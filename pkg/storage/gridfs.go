@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go-falcon/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFSStore keeps objects in a MongoDB GridFS bucket, so every instance sees the same files
+// without shared disk or an external object store. The key is stored as the GridFS file name.
+type GridFSStore struct {
+	bucket *gridfs.Bucket
+}
+
+// NewGridFSStore creates a store backed by the named GridFS bucket
+func NewGridFSStore(db *database.MongoDB, bucketName string) (*GridFSStore, error) {
+	bucket, err := gridfs.NewBucket(db.Database, options.GridFSBucket().SetName(bucketName))
+	if err != nil {
+		return nil, err
+	}
+	return &GridFSStore{bucket: bucket}, nil
+}
+
+// Put implements Store
+func (s *GridFSStore) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	stream, err := s.bucket.OpenUploadStream(key, options.GridFSUpload().SetMetadata(bson.M{"content_type": contentType}))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		stream.SetWriteDeadline(deadline)
+	}
+
+	if _, err := io.Copy(stream, r); err != nil {
+		stream.Abort()
+		return err
+	}
+	return stream.Close()
+}
+
+// Open implements Store
+func (s *GridFSStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	stream, err := s.bucket.OpenDownloadStreamByName(key)
+	if err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		stream.SetReadDeadline(deadline)
+	}
+	return stream, nil
+}
+
+// Delete implements Store
+func (s *GridFSStore) Delete(ctx context.Context, key string) error {
+	cursor, err := s.bucket.FindContext(ctx, bson.M{"filename": key})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var file struct {
+			ID any `bson:"_id"`
+		}
+		if err := cursor.Decode(&file); err != nil {
+			return err
+		}
+		if err := s.bucket.DeleteContext(ctx, file.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return err
+		}
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,22 @@
+// Package storage stores opaque binary objects, such as uploaded attachments, under string keys.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("object not found")
+
+// Store is a binary object store. Keys are chosen by the caller and must be unique; storing
+// under an existing key is an error for some backends.
+type Store interface {
+	// Put stores the contents of r under key
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Open returns a reader for the object under key, or ErrNotFound
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}